@@ -176,6 +176,13 @@ const (
 	LogSinkDBLoggerFlushInterval = "LOGSINK_DBLOGGER_FLUSH_INTERVAL"
 	LogSinkRateLimitBurst        = "LOGSINK_RATELIMIT_BURST"
 	LogSinkRateLimitRefill       = "LOGSINK_RATELIMIT_REFILL"
+
+	// DependencyEngineErrorDelay, DependencyEngineBounceDelay and
+	// DependencyEngineMaxRestartBurst override the restart delays of
+	// the agent's dependency engine; see dependency.EngineConfig.
+	DependencyEngineErrorDelay      = "DEPENDENCY_ENGINE_ERROR_DELAY"
+	DependencyEngineBounceDelay     = "DEPENDENCY_ENGINE_BOUNCE_DELAY"
+	DependencyEngineMaxRestartBurst = "DEPENDENCY_ENGINE_MAX_RESTART_BURST"
 )
 
 // The Config interface is the sole way that the agent gets access to the
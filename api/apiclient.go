@@ -150,6 +150,10 @@ type state struct {
 	// bakeryClient holds the client that will be used to
 	// authorize macaroon based login requests.
 	bakeryClient *httpbakery.Client
+
+	// tokenSource holds the source of ID tokens used
+	// for OpenID Connect based login requests.
+	tokenSource TokenSource
 }
 
 // RedirectError is returned from Open when the controller
@@ -243,6 +247,7 @@ func Open(info *Info, opts DialOpts) (Connection, error) {
 		nonce:        info.Nonce,
 		tlsConfig:    dialResult.tlsConfig,
 		bakeryClient: bakeryClient,
+		tokenSource:  opts.TokenSource,
 		modelTag:     info.ModelTag,
 	}
 	if !info.SkipLogin {
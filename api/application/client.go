@@ -482,6 +482,11 @@ type DestroyApplicationsParams struct {
 	// DestroyStorage controls whether or not storage attached
 	// to units of the applications will be destroyed.
 	DestroyStorage bool
+
+	// Force controls whether or not the applications are removed
+	// without waiting for their units' agents to run their
+	// remaining hooks.
+	Force bool
 }
 
 // DestroyApplications destroys the given applications.
@@ -502,11 +507,15 @@ func (c *Client) DestroyApplications(in DestroyApplicationsParams) ([]params.Des
 		argsV5.Applications = append(argsV5.Applications, params.DestroyApplicationParams{
 			ApplicationTag: names.NewApplicationTag(name).String(),
 			DestroyStorage: in.DestroyStorage,
+			Force:          in.Force,
 		})
 	}
 	if len(argsV5.Applications) == 0 {
 		return allResults, nil
 	}
+	if in.Force && c.BestAPIVersion() < 7 {
+		return nil, errors.New("this controller does not support --force")
+	}
 
 	args := interface{}(argsV5)
 	if c.BestAPIVersion() < 5 {
@@ -772,3 +781,44 @@ func (c *Client) UnsetApplicationConfig(application string, options []string) er
 	}
 	return results.OneError()
 }
+
+// SetApplicationTrust grants or revokes the trust of an application.
+func (c *Client) SetApplicationTrust(application string, trusted bool) error {
+	if c.BestAPIVersion() < 8 {
+		return errors.NotSupportedf("SetApplicationTrust not supported by this version of Juju")
+	}
+	args := params.ApplicationTrustArgs{
+		Args: []params.ApplicationTrust{{
+			ApplicationName: application,
+			Trusted:         trusted,
+		}},
+	}
+	var results params.ErrorResults
+	err := c.facade.FacadeCall("SetApplicationsTrust", args, &results)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return results.OneError()
+}
+
+// UnitsInfo returns the detail of the specified units: their
+// machines, opened ports, leadership, storage, relation data and
+// recent status history.
+func (c *Client) UnitsInfo(units []names.UnitTag) ([]params.UnitInfoResult, error) {
+	if c.BestAPIVersion() < 8 {
+		return nil, errors.NotSupportedf("UnitsInfo not supported by this version of Juju")
+	}
+	args := params.Entities{Entities: make([]params.Entity, len(units))}
+	for i, unit := range units {
+		args.Entities[i].Tag = unit.String()
+	}
+	var results params.UnitInfoResults
+	err := c.facade.FacadeCall("UnitsInfo", args, &results)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(results.Results) != len(units) {
+		return nil, errors.Errorf("expected %d results, got %d", len(units), len(results.Results))
+	}
+	return results.Results, nil
+}
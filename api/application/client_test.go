@@ -9,6 +9,7 @@ import (
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 	"gopkg.in/juju/charm.v6"
+	"gopkg.in/juju/names.v2"
 	"gopkg.in/macaroon.v1"
 
 	"github.com/juju/juju/api/application"
@@ -386,6 +387,50 @@ func (s *applicationSuite) TestDestroyApplicationsV4(c *gc.C) {
 	c.Assert(results, jc.DeepEquals, expectedResults)
 }
 
+func (s *applicationSuite) TestDestroyApplicationsForce(c *gc.C) {
+	expectedResults := []params.DestroyApplicationResult{{
+		Info: &params.DestroyApplicationInfo{
+			DestroyedUnits:  []params.Entity{{Tag: "unit-foo-0"}},
+			ForcedRelations: []params.Entity{{Tag: "relation-foo.db#bar.db"}},
+		},
+	}}
+	client := application.NewClient(basetesting.BestVersionCaller{
+		APICallerFunc: func(objType string, version int, id, request string, a, response interface{}) error {
+			c.Assert(request, gc.Equals, "DestroyApplication")
+			c.Assert(a, jc.DeepEquals, params.DestroyApplicationsParams{
+				Applications: []params.DestroyApplicationParams{
+					{ApplicationTag: "application-foo", Force: true},
+				},
+			})
+			out := response.(*params.DestroyApplicationResults)
+			*out = params.DestroyApplicationResults{expectedResults}
+			return nil
+		},
+		BestVersion: 7,
+	})
+	results, err := client.DestroyApplications(application.DestroyApplicationsParams{
+		Applications: []string{"foo"},
+		Force:        true,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, expectedResults)
+}
+
+func (s *applicationSuite) TestDestroyApplicationsForceNotSupported(c *gc.C) {
+	client := application.NewClient(basetesting.BestVersionCaller{
+		APICallerFunc: func(objType string, version int, id, request string, a, response interface{}) error {
+			c.Fatalf("unexpected API call")
+			return nil
+		},
+		BestVersion: 6,
+	})
+	_, err := client.DestroyApplications(application.DestroyApplicationsParams{
+		Applications: []string{"foo"},
+		Force:        true,
+	})
+	c.Assert(err, gc.ErrorMatches, "this controller does not support --force")
+}
+
 func (s *applicationSuite) TestDestroyApplicationsArity(c *gc.C) {
 	client := newClient(func(objType string, version int, id, request string, a, response interface{}) error {
 		return nil
@@ -987,6 +1032,48 @@ func (s *applicationSuite) TestSetApplicationConfig(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, "FAIL")
 }
 
+func (s *applicationSuite) TestSetApplicationTrust(c *gc.C) {
+	client := application.NewClient(basetesting.BestVersionCaller{
+		APICallerFunc: basetesting.APICallerFunc(
+			func(objType string, version int, id, request string, a, response interface{}) error {
+				c.Assert(request, gc.Equals, "SetApplicationsTrust")
+				args, ok := a.(params.ApplicationTrustArgs)
+				c.Assert(ok, jc.IsTrue)
+				c.Assert(args, jc.DeepEquals, params.ApplicationTrustArgs{
+					Args: []params.ApplicationTrust{{
+						ApplicationName: "foo",
+						Trusted:         true,
+					}}})
+				result, ok := response.(*params.ErrorResults)
+				c.Assert(ok, jc.IsTrue)
+				result.Results = []params.ErrorResult{
+					{Error: &params.Error{Message: "FAIL"}},
+				}
+				return nil
+			},
+		),
+		BestVersion: 8,
+	})
+
+	err := client.SetApplicationTrust("foo", true)
+	c.Assert(err, gc.ErrorMatches, "FAIL")
+}
+
+func (s *applicationSuite) TestSetApplicationTrustAPIv7(c *gc.C) {
+	client := application.NewClient(basetesting.BestVersionCaller{
+		APICallerFunc: basetesting.APICallerFunc(
+			func(objType string, version int, id, request string, a, response interface{}) error {
+				c.Fail()
+				return nil
+			},
+		),
+		BestVersion: 7,
+	})
+
+	err := client.SetApplicationTrust("foo", true)
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
+
 func (s *applicationSuite) TestUnsetApplicationConfig(c *gc.C) {
 	client := application.NewClient(basetesting.BestVersionCaller{
 		APICallerFunc: basetesting.APICallerFunc(
@@ -1041,3 +1128,49 @@ func (s *applicationSuite) TestUnsetApplicationConfigAPIv5(c *gc.C) {
 	err := client.UnsetApplicationConfig("foo", []string{})
 	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
 }
+
+func (s *applicationSuite) TestUnitsInfo(c *gc.C) {
+	client := application.NewClient(basetesting.BestVersionCaller{
+		APICallerFunc: basetesting.APICallerFunc(
+			func(objType string, version int, id, request string, a, response interface{}) error {
+				c.Assert(request, gc.Equals, "UnitsInfo")
+				args, ok := a.(params.Entities)
+				c.Assert(ok, jc.IsTrue)
+				c.Assert(args, jc.DeepEquals, params.Entities{
+					Entities: []params.Entity{{Tag: "unit-foo-0"}, {Tag: "unit-foo-1"}},
+				})
+				result, ok := response.(*params.UnitInfoResults)
+				c.Assert(ok, jc.IsTrue)
+				result.Results = []params.UnitInfoResult{
+					{Result: &params.UnitResult{Tag: "unit-foo-0", Machine: "0"}},
+					{Error: &params.Error{Message: "FAIL"}},
+				}
+				return nil
+			},
+		),
+		BestVersion: 8,
+	})
+
+	results, err := client.UnitsInfo([]names.UnitTag{
+		names.NewUnitTag("foo/0"), names.NewUnitTag("foo/1"),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, []params.UnitInfoResult{
+		{Result: &params.UnitResult{Tag: "unit-foo-0", Machine: "0"}},
+		{Error: &params.Error{Message: "FAIL"}},
+	})
+}
+
+func (s *applicationSuite) TestUnitsInfoAPIv7(c *gc.C) {
+	client := application.NewClient(basetesting.BestVersionCaller{
+		APICallerFunc: basetesting.APICallerFunc(
+			func(objType string, version int, id, request string, a, response interface{}) error {
+				c.Fail()
+				return errors.NotSupportedf("")
+			}),
+		BestVersion: 7,
+	})
+
+	_, err := client.UnitsInfo([]names.UnitTag{names.NewUnitTag("foo/0")})
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
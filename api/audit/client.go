@@ -0,0 +1,47 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package audit provides access to the Audit facade, which reports
+// the activity in a model recorded in the controller's audit log.
+package audit
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/apiserver/params"
+)
+
+// Client allows access to the audit API end point.
+type Client struct {
+	base.ClientFacade
+	facade base.FacadeCaller
+}
+
+// NewClient creates a new client for accessing the audit API.
+func NewClient(st base.APICallCloser) *Client {
+	frontend, backend := base.NewClientFacade(st, "Audit")
+	return &Client{ClientFacade: frontend, facade: backend}
+}
+
+// ModelActivity returns the commands that changed the model, most
+// recent first. If user is not empty, only the commands run by that
+// user are returned; if since is not zero, only those run at or
+// after that time. No more than limit commands are returned, unless
+// limit is zero.
+func (c *Client) ModelActivity(user string, since time.Time, limit int) ([]params.ModelActivity, error) {
+	args := params.ModelActivityArgs{
+		User:  user,
+		Limit: limit,
+	}
+	if !since.IsZero() {
+		args.Since = &since
+	}
+	var result params.ModelActivityResults
+	if err := c.facade.FacadeCall("ModelActivity", args, &result); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return result.Results, nil
+}
@@ -0,0 +1,74 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package audit_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/api/audit"
+	basetesting "github.com/juju/juju/api/base/testing"
+	"github.com/juju/juju/apiserver/params"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type auditSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&auditSuite{})
+
+func (s *auditSuite) TestModelActivity(c *gc.C) {
+	since := time.Date(2018, 6, 1, 10, 0, 0, 0, time.UTC)
+	activity := []params.ModelActivity{{
+		User:    "fred",
+		Command: "juju deploy mysql",
+		When:    since.Add(time.Minute),
+	}}
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string, version int, id, request string, a, result interface{}) error {
+			c.Check(objType, gc.Equals, "Audit")
+			c.Check(id, gc.Equals, "")
+			c.Check(request, gc.Equals, "ModelActivity")
+			c.Check(a, jc.DeepEquals, params.ModelActivityArgs{
+				User:  "fred",
+				Since: &since,
+				Limit: 5,
+			})
+			c.Assert(result, gc.FitsTypeOf, &params.ModelActivityResults{})
+			*(result.(*params.ModelActivityResults)) = params.ModelActivityResults{
+				Results: activity,
+			}
+			return nil
+		})
+	client := audit.NewClient(apiCaller)
+	result, err := client.ModelActivity("fred", since, 5)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, activity)
+}
+
+func (s *auditSuite) TestModelActivityNoFilters(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string, version int, id, request string, a, result interface{}) error {
+			c.Check(a, jc.DeepEquals, params.ModelActivityArgs{})
+			return nil
+		})
+	client := audit.NewClient(apiCaller)
+	result, err := client.ModelActivity("", time.Time{}, 0)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.HasLen, 0)
+}
+
+func (s *auditSuite) TestModelActivityError(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string, version int, id, request string, a, result interface{}) error {
+			return errors.New("auditing is not enabled on this controller")
+		})
+	client := audit.NewClient(apiCaller)
+	_, err := client.ModelActivity("", time.Time{}, 0)
+	c.Assert(err, gc.ErrorMatches, "auditing is not enabled on this controller")
+}
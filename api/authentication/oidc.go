@@ -0,0 +1,380 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package authentication
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils/clock"
+	"gopkg.in/juju/names.v2"
+)
+
+const (
+	// deviceCodeGrantType is the grant type used to poll for the
+	// tokens of a device authorization (RFC 8628).
+	deviceCodeGrantType = "urn:ietf:params:oauth:grant-type:device_code"
+
+	// defaultPollInterval is how often the token endpoint is polled
+	// during a device authorization, if the provider does not say.
+	defaultPollInterval = 5 * time.Second
+
+	// defaultDeviceCodeExpiry is how long a device authorization is
+	// waited for, if the provider does not say.
+	defaultDeviceCodeExpiry = 10 * time.Minute
+)
+
+// OIDCProvider holds the metadata of an OpenID Connect provider
+// that is used by Juju.
+type OIDCProvider struct {
+	Issuer                      string `json:"issuer"`
+	TokenEndpoint               string `json:"token_endpoint"`
+	DeviceAuthorizationEndpoint string `json:"device_authorization_endpoint,omitempty"`
+	JWKSURI                     string `json:"jwks_uri"`
+}
+
+// DiscoverOIDCProvider fetches the metadata of the OpenID Connect
+// provider with the given issuer URL.
+func DiscoverOIDCProvider(client *http.Client, issuerURL string) (*OIDCProvider, error) {
+	issuerURL = strings.TrimSuffix(issuerURL, "/")
+	var provider OIDCProvider
+	if err := getJSON(client, issuerURL+"/.well-known/openid-configuration", &provider); err != nil {
+		return nil, errors.Annotatef(err, "cannot discover OIDC provider %q", issuerURL)
+	}
+	if strings.TrimSuffix(provider.Issuer, "/") != issuerURL {
+		return nil, errors.Errorf("OIDC provider %q claims to be issuer %q", issuerURL, provider.Issuer)
+	}
+	return &provider, nil
+}
+
+// IDToken holds an OpenID Connect ID token, a JSON Web Token
+// with the claims about the user that it identifies.
+type IDToken struct {
+	Header IDTokenHeader
+	Claims IDTokenClaims
+
+	// SignedContent holds the part of the token covered by
+	// Signature: its encoded header and claims.
+	SignedContent []byte
+	Signature     []byte
+}
+
+// IDTokenHeader holds the header of an ID token.
+type IDTokenHeader struct {
+	Algorithm string `json:"alg"`
+	KeyID     string `json:"kid,omitempty"`
+}
+
+// IDTokenClaims holds the claims of an ID token used by Juju.
+type IDTokenClaims struct {
+	Issuer            string   `json:"iss"`
+	Subject           string   `json:"sub"`
+	Audience          Audience `json:"aud"`
+	Expiry            int64    `json:"exp"`
+	PreferredUsername string   `json:"preferred_username,omitempty"`
+}
+
+// Expired reports whether the token has expired at the given time.
+func (c IDTokenClaims) Expired(now time.Time) bool {
+	return !now.Before(time.Unix(c.Expiry, 0))
+}
+
+// UserTag returns the tag of the external user that the claims
+// identify. This is the user's preferred user name, or the subject
+// if there is none, in the "external" domain.
+func (c IDTokenClaims) UserTag() (names.UserTag, error) {
+	name := c.PreferredUsername
+	if name == "" {
+		name = c.Subject
+	}
+	if !names.IsValidUserName(name) {
+		return names.UserTag{}, errors.NotValidf("user name %q", name)
+	}
+	return names.NewLocalUserTag(name).WithDomain("external"), nil
+}
+
+// Audience holds the audience of an ID token, which
+// may be encoded as either a string or a list.
+type Audience []string
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (a *Audience) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*a = Audience{s}
+		return nil
+	}
+	var l []string
+	if err := json.Unmarshal(data, &l); err != nil {
+		return errors.Trace(err)
+	}
+	*a = Audience(l)
+	return nil
+}
+
+// Contains reports whether the audience includes the given client.
+func (a Audience) Contains(clientID string) bool {
+	for _, aud := range a {
+		if aud == clientID {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseIDToken parses an ID token. The signature of the token is
+// not verified.
+func ParseIDToken(token string) (*IDToken, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, errors.NotValidf("ID token")
+	}
+	var idToken IDToken
+	if err := decodeTokenPart(parts[0], &idToken.Header); err != nil {
+		return nil, errors.Annotate(err, "cannot decode ID token header")
+	}
+	if err := decodeTokenPart(parts[1], &idToken.Claims); err != nil {
+		return nil, errors.Annotate(err, "cannot decode ID token claims")
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot decode ID token signature")
+	}
+	idToken.SignedContent = []byte(parts[0] + "." + parts[1])
+	idToken.Signature = signature
+	return &idToken, nil
+}
+
+func decodeTokenPart(part string, v interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(part)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(json.Unmarshal(data, v))
+}
+
+// OIDCTokens holds the tokens issued to a client by an
+// OpenID Connect provider.
+type OIDCTokens struct {
+	// IDToken holds the ID token identifying the user.
+	IDToken string
+
+	// RefreshToken holds the token used to obtain new tokens
+	// when the ID token expires, if the provider issued one.
+	RefreshToken string
+}
+
+// DeviceAuthorization holds the details that a user needs
+// to authorize a device login.
+type DeviceAuthorization struct {
+	// UserCode holds the code that the user must enter.
+	UserCode string
+
+	// VerificationURI holds the URL of the page at which the
+	// user enters the code.
+	VerificationURI string
+
+	// VerificationURIComplete optionally holds the URL of a page
+	// at which the user need not enter the code.
+	VerificationURIComplete string
+}
+
+// OIDCClient obtains ID tokens from an OpenID Connect provider. Users
+// log in with the device authorization grant, so that they may do so
+// with a web browser on any device, and tokens are renewed with the
+// refresh tokens issued along with them.
+type OIDCClient struct {
+	// IssuerURL holds the issuer URL of the provider.
+	IssuerURL string
+
+	// ClientID holds the client ID registered with the provider.
+	ClientID string
+
+	// HTTPClient holds the client used to make requests to the
+	// provider. If it is nil, http.DefaultClient is used.
+	HTTPClient *http.Client
+
+	// Clock is used to wait between polls for the tokens of a device
+	// authorization. If it is nil, the wall clock is used.
+	Clock clock.Clock
+}
+
+// DeviceLogin logs in with the device authorization grant, calling
+// prompt with the details that the user needs to complete the login
+// and then waiting until they have done so.
+func (c *OIDCClient) DeviceLogin(prompt func(DeviceAuthorization) error) (*OIDCTokens, error) {
+	provider, err := DiscoverOIDCProvider(c.httpClient(), c.IssuerURL)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if provider.DeviceAuthorizationEndpoint == "" {
+		return nil, errors.NotSupportedf("device login with OIDC provider %q", c.IssuerURL)
+	}
+	var auth struct {
+		DeviceCode              string `json:"device_code"`
+		UserCode                string `json:"user_code"`
+		VerificationURI         string `json:"verification_uri"`
+		VerificationURIComplete string `json:"verification_uri_complete,omitempty"`
+		ExpiresIn               int    `json:"expires_in"`
+		Interval                int    `json:"interval,omitempty"`
+	}
+	err = c.postForm(provider.DeviceAuthorizationEndpoint, url.Values{
+		"client_id": {c.ClientID},
+		"scope":     {"openid profile offline_access"},
+	}, &auth)
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot start device login")
+	}
+	if err := prompt(DeviceAuthorization{
+		UserCode:                auth.UserCode,
+		VerificationURI:         auth.VerificationURI,
+		VerificationURIComplete: auth.VerificationURIComplete,
+	}); err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	interval := defaultPollInterval
+	if auth.Interval > 0 {
+		interval = time.Duration(auth.Interval) * time.Second
+	}
+	expiry := defaultDeviceCodeExpiry
+	if auth.ExpiresIn > 0 {
+		expiry = time.Duration(auth.ExpiresIn) * time.Second
+	}
+	deadline := c.clock().Now().Add(expiry)
+	for {
+		<-c.clock().After(interval)
+		tokens, err := c.requestTokens(provider.TokenEndpoint, url.Values{
+			"grant_type":  {deviceCodeGrantType},
+			"device_code": {auth.DeviceCode},
+			"client_id":   {c.ClientID},
+		})
+		if err, ok := errors.Cause(err).(*oauthError); ok {
+			switch err.Code {
+			case "authorization_pending":
+			case "slow_down":
+				interval += defaultPollInterval
+			default:
+				return nil, errors.Annotate(err, "device login failed")
+			}
+			if c.clock().Now().After(deadline) {
+				return nil, errors.New("device login timed out")
+			}
+			continue
+		}
+		if err != nil {
+			return nil, errors.Annotate(err, "device login failed")
+		}
+		return tokens, nil
+	}
+}
+
+// Refresh obtains new tokens using the given refresh token. If the
+// provider does not issue a new refresh token, the given one is
+// returned with the new ID token.
+func (c *OIDCClient) Refresh(refreshToken string) (*OIDCTokens, error) {
+	provider, err := DiscoverOIDCProvider(c.httpClient(), c.IssuerURL)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	tokens, err := c.requestTokens(provider.TokenEndpoint, url.Values{
+		"grant_type":    {"refresh_token"},
+		"refresh_token": {refreshToken},
+		"client_id":     {c.ClientID},
+		"scope":         {"openid profile offline_access"},
+	})
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot refresh ID token")
+	}
+	if tokens.RefreshToken == "" {
+		tokens.RefreshToken = refreshToken
+	}
+	return tokens, nil
+}
+
+func (c *OIDCClient) requestTokens(tokenEndpoint string, form url.Values) (*OIDCTokens, error) {
+	var resp struct {
+		IDToken      string `json:"id_token"`
+		RefreshToken string `json:"refresh_token,omitempty"`
+	}
+	if err := c.postForm(tokenEndpoint, form, &resp); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if resp.IDToken == "" {
+		return nil, errors.New("no ID token issued")
+	}
+	return &OIDCTokens{
+		IDToken:      resp.IDToken,
+		RefreshToken: resp.RefreshToken,
+	}, nil
+}
+
+func (c *OIDCClient) postForm(url string, form url.Values, v interface{}) error {
+	resp, err := c.httpClient().PostForm(url, form)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer resp.Body.Close()
+	return errors.Trace(decodeResponse(resp, v))
+}
+
+func (c *OIDCClient) httpClient() *http.Client {
+	if c.HTTPClient != nil {
+		return c.HTTPClient
+	}
+	return http.DefaultClient
+}
+
+func (c *OIDCClient) clock() clock.Clock {
+	if c.Clock != nil {
+		return c.Clock
+	}
+	return clock.WallClock
+}
+
+// oauthError holds an error response from an OAuth 2.0 endpoint.
+type oauthError struct {
+	Code        string `json:"error"`
+	Description string `json:"error_description,omitempty"`
+}
+
+// Error implements the error interface.
+func (e *oauthError) Error() string {
+	if e.Description == "" {
+		return e.Code
+	}
+	return fmt.Sprintf("%s: %s", e.Code, e.Description)
+}
+
+func getJSON(client *http.Client, url string, v interface{}) error {
+	resp, err := client.Get(url)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer resp.Body.Close()
+	return errors.Trace(decodeResponse(resp, v))
+}
+
+func decodeResponse(resp *http.Response, v interface{}) error {
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		var oauthErr oauthError
+		if err := json.Unmarshal(body, &oauthErr); err == nil && oauthErr.Code != "" {
+			return &oauthErr
+		}
+		return errors.Errorf("%s: %s", resp.Status, bytes.TrimSpace(body))
+	}
+	return errors.Trace(json.Unmarshal(body, v))
+}
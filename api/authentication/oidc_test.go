@@ -0,0 +1,282 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package authentication_test
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/api/authentication"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type OIDCSuite struct {
+	testing.IsolationSuite
+
+	server *httptest.Server
+
+	// tokenResponses holds the responses to successive
+	// requests to the token endpoint.
+	tokenResponses []tokenResponse
+
+	// tokenRequests records the forms posted to the token endpoint.
+	tokenRequests []map[string]string
+}
+
+type tokenResponse struct {
+	code int
+	body interface{}
+}
+
+var _ = gc.Suite(&OIDCSuite{})
+
+func (s *OIDCSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.tokenResponses = nil
+	s.tokenRequests = nil
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, req *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]string{
+			"issuer":                        s.server.URL,
+			"token_endpoint":                s.server.URL + "/token",
+			"device_authorization_endpoint": s.server.URL + "/device",
+			"jwks_uri":                      s.server.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/device", func(w http.ResponseWriter, req *http.Request) {
+		c.Check(req.FormValue("client_id"), gc.Equals, "juju")
+		c.Check(req.FormValue("scope"), gc.Equals, "openid profile offline_access")
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"device_code":      "device-code",
+			"user_code":        "ABCD-EFGH",
+			"verification_uri": s.server.URL + "/activate",
+			"expires_in":       600,
+			"interval":         1,
+		})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, req *http.Request) {
+		req.ParseForm()
+		form := make(map[string]string)
+		for k := range req.PostForm {
+			form[k] = req.PostForm.Get(k)
+		}
+		s.tokenRequests = append(s.tokenRequests, form)
+		if len(s.tokenResponses) == 0 {
+			c.Errorf("unexpected token request")
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_request"})
+			return
+		}
+		resp := s.tokenResponses[0]
+		s.tokenResponses = s.tokenResponses[1:]
+		writeJSON(w, resp.code, resp.body)
+	})
+	s.server = httptest.NewServer(mux)
+	s.AddCleanup(func(*gc.C) { s.server.Close() })
+}
+
+func (s *OIDCSuite) TestDiscoverOIDCProvider(c *gc.C) {
+	provider, err := authentication.DiscoverOIDCProvider(http.DefaultClient, s.server.URL+"/")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(provider, jc.DeepEquals, &authentication.OIDCProvider{
+		Issuer:                      s.server.URL,
+		TokenEndpoint:               s.server.URL + "/token",
+		DeviceAuthorizationEndpoint: s.server.URL + "/device",
+		JWKSURI:                     s.server.URL + "/jwks",
+	})
+}
+
+func (s *OIDCSuite) TestDiscoverOIDCProviderIssuerMismatch(c *gc.C) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]string{"issuer": "https://elsewhere.example.com"})
+	}))
+	defer server.Close()
+	_, err := authentication.DiscoverOIDCProvider(http.DefaultClient, server.URL)
+	c.Assert(err, gc.ErrorMatches, `OIDC provider ".*" claims to be issuer "https://elsewhere.example.com"`)
+}
+
+func (s *OIDCSuite) TestDeviceLogin(c *gc.C) {
+	s.tokenResponses = []tokenResponse{
+		{http.StatusBadRequest, map[string]string{"error": "authorization_pending"}},
+		{http.StatusBadRequest, map[string]string{"error": "slow_down"}},
+		{http.StatusOK, map[string]string{"id_token": "id-token", "refresh_token": "refresh-token"}},
+	}
+	clock := testing.NewClock(time.Now())
+	client := &authentication.OIDCClient{
+		IssuerURL: s.server.URL,
+		ClientID:  "juju",
+		Clock:     clock,
+	}
+
+	var prompted authentication.DeviceAuthorization
+	result := make(chan *authentication.OIDCTokens, 1)
+	go func() {
+		tokens, err := client.DeviceLogin(func(auth authentication.DeviceAuthorization) error {
+			prompted = auth
+			return nil
+		})
+		c.Check(err, jc.ErrorIsNil)
+		result <- tokens
+	}()
+
+	c.Assert(clock.WaitAdvance(time.Second, coretesting.LongWait, 1), jc.ErrorIsNil)
+	c.Assert(clock.WaitAdvance(time.Second, coretesting.LongWait, 1), jc.ErrorIsNil)
+	// The provider asked us to slow down, so the
+	// interval grows by five seconds.
+	c.Assert(clock.WaitAdvance(6*time.Second, coretesting.LongWait, 1), jc.ErrorIsNil)
+
+	select {
+	case tokens := <-result:
+		c.Assert(tokens, jc.DeepEquals, &authentication.OIDCTokens{
+			IDToken:      "id-token",
+			RefreshToken: "refresh-token",
+		})
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for device login")
+	}
+	c.Assert(prompted, jc.DeepEquals, authentication.DeviceAuthorization{
+		UserCode:        "ABCD-EFGH",
+		VerificationURI: s.server.URL + "/activate",
+	})
+	c.Assert(s.tokenRequests, gc.HasLen, 3)
+	c.Assert(s.tokenRequests[0], jc.DeepEquals, map[string]string{
+		"grant_type":  "urn:ietf:params:oauth:grant-type:device_code",
+		"device_code": "device-code",
+		"client_id":   "juju",
+	})
+}
+
+func (s *OIDCSuite) TestDeviceLoginDenied(c *gc.C) {
+	s.tokenResponses = []tokenResponse{
+		{http.StatusBadRequest, map[string]string{
+			"error":             "access_denied",
+			"error_description": "the user denied the request",
+		}},
+	}
+	clock := testing.NewClock(time.Now())
+	client := &authentication.OIDCClient{
+		IssuerURL: s.server.URL,
+		ClientID:  "juju",
+		Clock:     clock,
+	}
+	result := make(chan error, 1)
+	go func() {
+		_, err := client.DeviceLogin(func(authentication.DeviceAuthorization) error {
+			return nil
+		})
+		result <- err
+	}()
+	c.Assert(clock.WaitAdvance(time.Second, coretesting.LongWait, 1), jc.ErrorIsNil)
+	select {
+	case err := <-result:
+		c.Assert(err, gc.ErrorMatches, "device login failed: access_denied: the user denied the request")
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for device login")
+	}
+}
+
+func (s *OIDCSuite) TestRefresh(c *gc.C) {
+	s.tokenResponses = []tokenResponse{
+		{http.StatusOK, map[string]string{"id_token": "new-id-token"}},
+	}
+	client := &authentication.OIDCClient{
+		IssuerURL: s.server.URL,
+		ClientID:  "juju",
+	}
+	tokens, err := client.Refresh("refresh-token")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(tokens, jc.DeepEquals, &authentication.OIDCTokens{
+		IDToken:      "new-id-token",
+		RefreshToken: "refresh-token",
+	})
+	c.Assert(s.tokenRequests, gc.HasLen, 1)
+	c.Assert(s.tokenRequests[0]["grant_type"], gc.Equals, "refresh_token")
+	c.Assert(s.tokenRequests[0]["refresh_token"], gc.Equals, "refresh-token")
+}
+
+func (s *OIDCSuite) TestRefreshError(c *gc.C) {
+	s.tokenResponses = []tokenResponse{
+		{http.StatusBadRequest, map[string]string{"error": "invalid_grant"}},
+	}
+	client := &authentication.OIDCClient{
+		IssuerURL: s.server.URL,
+		ClientID:  "juju",
+	}
+	_, err := client.Refresh("refresh-token")
+	c.Assert(err, gc.ErrorMatches, "cannot refresh ID token: invalid_grant")
+}
+
+func (s *OIDCSuite) TestParseIDToken(c *gc.C) {
+	token := makeToken(`{"alg":"RS256","kid":"key-1"}`, `{
+		"iss": "https://issuer.example.com",
+		"sub": "1234",
+		"aud": "juju",
+		"exp": 1500000000,
+		"preferred_username": "bob"
+	}`)
+	idToken, err := authentication.ParseIDToken(token)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(idToken.Header, jc.DeepEquals, authentication.IDTokenHeader{
+		Algorithm: "RS256",
+		KeyID:     "key-1",
+	})
+	c.Assert(idToken.Claims, jc.DeepEquals, authentication.IDTokenClaims{
+		Issuer:            "https://issuer.example.com",
+		Subject:           "1234",
+		Audience:          authentication.Audience{"juju"},
+		Expiry:            1500000000,
+		PreferredUsername: "bob",
+	})
+	c.Assert(idToken.Signature, jc.DeepEquals, []byte("signature"))
+	c.Assert(idToken.Claims.Expired(time.Unix(1499999999, 0)), jc.IsFalse)
+	c.Assert(idToken.Claims.Expired(time.Unix(1500000000, 0)), jc.IsTrue)
+
+	tag, err := idToken.Claims.UserTag()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(tag, gc.Equals, names.NewUserTag("bob@external"))
+}
+
+func (s *OIDCSuite) TestParseIDTokenAudienceList(c *gc.C) {
+	token := makeToken(`{"alg":"RS256"}`, `{"sub": "bob", "aud": ["other", "juju"]}`)
+	idToken, err := authentication.ParseIDToken(token)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(idToken.Claims.Audience.Contains("juju"), jc.IsTrue)
+	c.Assert(idToken.Claims.Audience.Contains("nope"), jc.IsFalse)
+
+	// Without a preferred user name, the subject is used.
+	tag, err := idToken.Claims.UserTag()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(tag, gc.Equals, names.NewUserTag("bob@external"))
+}
+
+func (s *OIDCSuite) TestParseIDTokenInvalid(c *gc.C) {
+	_, err := authentication.ParseIDToken("not-a-token")
+	c.Assert(err, gc.ErrorMatches, "ID token not valid")
+	_, err = authentication.ParseIDToken("!!.e30.e30")
+	c.Assert(err, gc.ErrorMatches, "cannot decode ID token header: .*")
+}
+
+func makeToken(header, claims string) string {
+	enc := base64.RawURLEncoding
+	return fmt.Sprintf("%s.%s.%s",
+		enc.EncodeToString([]byte(header)),
+		enc.EncodeToString([]byte(claims)),
+		enc.EncodeToString([]byte("signature")),
+	)
+}
+
+func writeJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(v)
+}
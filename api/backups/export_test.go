@@ -4,6 +4,9 @@
 package backups
 
 import (
+	"io"
+	"net/http"
+
 	"github.com/juju/juju/api/base"
 )
 
@@ -43,3 +46,9 @@ func (f *resultCaller) BestAPIVersion() int {
 func (f *resultCaller) RawAPICaller() base.APICaller {
 	return nil
 }
+
+// NewStreamedArchive returns the archive that Stream returns for the
+// given response.
+func NewStreamedArchive(resp *http.Response) io.ReadCloser {
+	return newStreamedArchive(resp)
+}
@@ -0,0 +1,86 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backups
+
+import (
+	"crypto/sha1"
+	"encoding/base64"
+	"hash"
+	"io"
+	"net/http"
+
+	"github.com/juju/errors"
+	"github.com/juju/httprequest"
+
+	"github.com/juju/juju/apiserver/params"
+)
+
+type streamParams struct {
+	httprequest.Route `httprequest:"POST /backups"`
+	Body              params.BackupsCreateArgs `httprequest:",body"`
+}
+
+// Stream requests a new backup of juju's state and returns its archive,
+// which the controller sends as it is built rather than staging it on
+// disk or storing it. Reading the archive fails at its end if it was not
+// received in full.
+func (c *Client) Stream(notes string) (io.ReadCloser, error) {
+	var resp *http.Response
+	err := c.client.Call(
+		&streamParams{
+			Body: params.BackupsCreateArgs{
+				Notes: notes,
+			},
+		},
+		&resp,
+	)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return newStreamedArchive(resp), nil
+}
+
+// streamedArchive reads a streamed backup archive, checking it against
+// the digest that the controller sends after the archive.
+type streamedArchive struct {
+	resp   *http.Response
+	hasher hash.Hash
+}
+
+func newStreamedArchive(resp *http.Response) *streamedArchive {
+	return &streamedArchive{
+		resp:   resp,
+		hasher: sha1.New(),
+	}
+}
+
+// Read implements io.Reader.
+func (a *streamedArchive) Read(p []byte) (int, error) {
+	n, err := a.resp.Body.Read(p)
+	a.hasher.Write(p[:n])
+	if err == io.EOF {
+		// The trailer is only available once the body has been read.
+		if verr := a.verify(); verr != nil {
+			return n, verr
+		}
+	}
+	return n, err
+}
+
+func (a *streamedArchive) verify() error {
+	digest := a.resp.Trailer.Get("Digest")
+	if digest == "" {
+		return errors.New("backup archive incomplete: controller failed to create backup")
+	}
+	checksum := base64.StdEncoding.EncodeToString(a.hasher.Sum(nil))
+	if digest != params.EncodeChecksum(checksum) {
+		return errors.New("backup archive corrupt: checksum mismatch")
+	}
+	return nil
+}
+
+// Close implements io.Closer.
+func (a *streamedArchive) Close() error {
+	return a.resp.Body.Close()
+}
@@ -0,0 +1,65 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backups_test
+
+import (
+	"crypto/sha1"
+	"encoding/base64"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/api/backups"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/testing"
+)
+
+type streamSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&streamSuite{})
+
+const streamedData = "<compressed archive data>"
+
+func (s *streamSuite) response(digest string) *http.Response {
+	resp := &http.Response{
+		Body:    ioutil.NopCloser(strings.NewReader(streamedData)),
+		Trailer: make(http.Header),
+	}
+	if digest != "" {
+		resp.Trailer.Set("Digest", digest)
+	}
+	return resp
+}
+
+func (s *streamSuite) TestStreamedArchive(c *gc.C) {
+	sum := sha1.Sum([]byte(streamedData))
+	digest := params.EncodeChecksum(base64.StdEncoding.EncodeToString(sum[:]))
+	archive := backups.NewStreamedArchive(s.response(digest))
+	defer archive.Close()
+
+	data, err := ioutil.ReadAll(archive)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(string(data), gc.Equals, streamedData)
+}
+
+func (s *streamSuite) TestStreamedArchiveIncomplete(c *gc.C) {
+	archive := backups.NewStreamedArchive(s.response(""))
+	defer archive.Close()
+
+	_, err := ioutil.ReadAll(archive)
+	c.Assert(err, gc.ErrorMatches, "backup archive incomplete: controller failed to create backup")
+}
+
+func (s *streamSuite) TestStreamedArchiveChecksumMismatch(c *gc.C) {
+	archive := backups.NewStreamedArchive(s.response(params.EncodeChecksum("bogus")))
+	defer archive.Close()
+
+	_, err := ioutil.ReadAll(archive)
+	c.Assert(err, gc.ErrorMatches, "backup archive corrupt: checksum mismatch")
+}
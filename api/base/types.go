@@ -45,6 +45,12 @@ type Machine struct {
 	WantsVote  bool
 	Status     string
 	Hardware   *instance.HardwareCharacteristics
+
+	// The remaining fields are only set for controller machines.
+	AgentVersion      string
+	ReplicaSetState   string
+	ReplicaSetHealthy bool
+	APIAddresses      []string
 }
 
 // ModelInfo holds information about a model.
@@ -112,6 +118,7 @@ type UserModelSummary struct {
 	Status             Status
 	ModelUserAccess    string
 	UserLastConnection *time.Time
+	LastConnection     *time.Time
 	Counts             []EntityCount
 	AgentVersion       *version.Number
 	Error              error
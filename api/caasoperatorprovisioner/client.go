@@ -8,6 +8,7 @@ import (
 	"gopkg.in/juju/names.v2"
 
 	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/api/common"
 	apiwatcher "github.com/juju/juju/api/watcher"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/watcher"
@@ -15,6 +16,7 @@ import (
 
 // Client allows access to the CAAS operator provisioner API endpoint.
 type Client struct {
+	*common.ModelWatcher
 	facade base.FacadeCaller
 }
 
@@ -22,7 +24,8 @@ type Client struct {
 func NewClient(caller base.APICaller) *Client {
 	facadeCaller := base.NewFacadeCaller(caller, "CAASOperatorProvisioner")
 	return &Client{
-		facade: facadeCaller,
+		ModelWatcher: common.NewModelWatcher(facadeCaller),
+		facade:       facadeCaller,
 	}
 }
 
@@ -10,8 +10,10 @@ import (
 	"github.com/juju/juju/api/base"
 	apiwatcher "github.com/juju/juju/api/watcher"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/core/application"
 	"github.com/juju/juju/core/life"
+	"github.com/juju/juju/storage"
 	"github.com/juju/juju/watcher"
 )
 
@@ -82,6 +84,73 @@ func (c *Client) ApplicationConfig(applicationName string) (application.ConfigAt
 	return application.ConfigAttributes(results.Results[0].Config), nil
 }
 
+// ApplicationConstraints returns the constraints for the specified application.
+func (c *Client) ApplicationConstraints(applicationName string) (constraints.Value, error) {
+	if c.facade.BestAPIVersion() < 2 {
+		return constraints.Value{}, errors.NotImplementedf("ApplicationConstraints() (need V2+)")
+	}
+	var results params.ConstraintsResults
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: names.NewApplicationTag(applicationName).String()}},
+	}
+	err := c.facade.FacadeCall("ApplicationsConstraints", args, &results)
+	if err != nil {
+		return constraints.Value{}, errors.Trace(err)
+	}
+	if len(results.Results) != len(args.Entities) {
+		return constraints.Value{}, errors.Errorf("expected %d result(s), got %d", len(args.Entities), len(results.Results))
+	}
+	if err := results.Results[0].Error; err != nil {
+		return constraints.Value{}, maybeNotFound(err)
+	}
+	return results.Results[0].Constraints, nil
+}
+
+// ApplicationPlacement returns the placement directive
+// for the specified application.
+func (c *Client) ApplicationPlacement(applicationName string) (string, error) {
+	var results params.StringResults
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: names.NewApplicationTag(applicationName).String()}},
+	}
+	err := c.facade.FacadeCall("ApplicationsPlacement", args, &results)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	if len(results.Results) != len(args.Entities) {
+		return "", errors.Errorf("expected %d result(s), got %d", len(args.Entities), len(results.Results))
+	}
+	if err := results.Results[0].Error; err != nil {
+		return "", maybeNotFound(err)
+	}
+	return results.Results[0].Result, nil
+}
+
+// StoragePool returns the config of the named storage pool.
+func (c *Client) StoragePool(name string) (*storage.Config, error) {
+	var results params.StoragePoolsResults
+	args := params.StoragePoolFilters{
+		Filters: []params.StoragePoolFilter{{Names: []string{name}}},
+	}
+	err := c.facade.FacadeCall("StoragePools", args, &results)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(results.Results) != len(args.Filters) {
+		return nil, errors.Errorf("expected %d result(s), got %d", len(args.Filters), len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return nil, maybeNotFound(result.Error)
+	}
+	if len(result.Result) == 0 {
+		return nil, errors.NotFoundf("storage pool %q", name)
+	}
+	pool := result.Result[0]
+	cfg, err := storage.NewConfig(pool.Name, storage.ProviderType(pool.Provider), pool.Attrs)
+	return cfg, errors.Trace(err)
+}
+
 // WatchUnits returns a StringsWatcher that notifies of
 // changes to the lifecycles of units of the specified
 // CAAS application in the current model.
@@ -130,6 +199,30 @@ func (c *Client) WatchContainerSpec(unit string) (watcher.NotifyWatcher, error)
 	return w, nil
 }
 
+// WatchApplicationConfig returns a NotifyWatcher that notifies of
+// changes to the config or constraints of the specified CAAS
+// application in the current model.
+func (c *Client) WatchApplicationConfig(application string) (watcher.NotifyWatcher, error) {
+	applicationTag, err := applicationTag(application)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	args := entities(applicationTag)
+
+	var results params.NotifyWatchResults
+	if err := c.facade.FacadeCall("WatchApplicationsConfig", args, &results); err != nil {
+		return nil, err
+	}
+	if n := len(results.Results); n != 1 {
+		return nil, errors.Errorf("expected 1 result, got %d", n)
+	}
+	if err := results.Results[0].Error; err != nil {
+		return nil, errors.Trace(err)
+	}
+	w := apiwatcher.NewNotifyWatcher(c.facade.RawAPICaller(), results.Results[0])
+	return w, nil
+}
+
 // ContainerSpec returns the container spec for the specified CAAS
 // unit in the current model.
 func (c *Client) ContainerSpec(unit string) (string, error) {
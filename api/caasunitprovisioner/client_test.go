@@ -13,8 +13,10 @@ import (
 	basetesting "github.com/juju/juju/api/base/testing"
 	"github.com/juju/juju/api/caasunitprovisioner"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/core/application"
 	"github.com/juju/juju/core/life"
+	"github.com/juju/juju/storage"
 )
 
 type unitprovisionerSuite struct {
@@ -205,6 +207,32 @@ func (s *unitprovisionerSuite) TestWatchContainerSpec(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, "FAIL")
 }
 
+func (s *unitprovisionerSuite) TestWatchApplicationConfig(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(objType, gc.Equals, "CAASUnitProvisioner")
+		c.Check(version, gc.Equals, 0)
+		c.Check(id, gc.Equals, "")
+		c.Check(request, gc.Equals, "WatchApplicationsConfig")
+		c.Assert(arg, jc.DeepEquals, params.Entities{
+			Entities: []params.Entity{{
+				Tag: "application-gitlab",
+			}},
+		})
+		c.Assert(result, gc.FitsTypeOf, &params.NotifyWatchResults{})
+		*(result.(*params.NotifyWatchResults)) = params.NotifyWatchResults{
+			Results: []params.NotifyWatchResult{{
+				Error: &params.Error{Message: "FAIL"},
+			}},
+		}
+		return nil
+	})
+
+	client := caasunitprovisioner.NewClient(apiCaller)
+	watcher, err := client.WatchApplicationConfig("gitlab")
+	c.Assert(watcher, gc.IsNil)
+	c.Assert(err, gc.ErrorMatches, "FAIL")
+}
+
 func (s *unitprovisionerSuite) TestApplicationConfig(c *gc.C) {
 	apiCaller := basetesting.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
 		c.Check(objType, gc.Equals, "CAASUnitProvisioner")
@@ -231,6 +259,132 @@ func (s *unitprovisionerSuite) TestApplicationConfig(c *gc.C) {
 	c.Assert(cfg, jc.DeepEquals, application.ConfigAttributes{"foo": "bar"})
 }
 
+func (s *unitprovisionerSuite) TestApplicationConstraints(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(objType, gc.Equals, "CAASUnitProvisioner")
+		c.Check(version, gc.Equals, 2)
+		c.Check(id, gc.Equals, "")
+		c.Check(request, gc.Equals, "ApplicationsConstraints")
+		c.Assert(arg, jc.DeepEquals, params.Entities{
+			Entities: []params.Entity{{
+				Tag: "application-gitlab",
+			}},
+		})
+		c.Assert(result, gc.FitsTypeOf, &params.ConstraintsResults{})
+		*(result.(*params.ConstraintsResults)) = params.ConstraintsResults{
+			Results: []params.ConstraintsResult{{
+				Constraints: constraints.MustParse("mem=4G"),
+			}},
+		}
+		return nil
+	})
+
+	client := caasunitprovisioner.NewClient(basetesting.BestVersionCaller{apiCaller, 2})
+	cons, err := client.ApplicationConstraints("gitlab")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cons, jc.DeepEquals, constraints.MustParse("mem=4G"))
+}
+
+func (s *unitprovisionerSuite) TestApplicationConstraintsError(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		*(result.(*params.ConstraintsResults)) = params.ConstraintsResults{
+			Results: []params.ConstraintsResult{{Error: &params.Error{
+				Code:    params.CodeNotFound,
+				Message: "application not found",
+			}}},
+		}
+		return nil
+	})
+
+	client := caasunitprovisioner.NewClient(basetesting.BestVersionCaller{apiCaller, 2})
+	_, err := client.ApplicationConstraints("gitlab")
+	c.Assert(err, gc.ErrorMatches, "application not found")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *unitprovisionerSuite) TestApplicationConstraintsNotImplemented(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Fatalf("unexpected API call %q", request)
+		return nil
+	})
+
+	client := caasunitprovisioner.NewClient(basetesting.BestVersionCaller{apiCaller, 1})
+	_, err := client.ApplicationConstraints("gitlab")
+	c.Assert(err, gc.ErrorMatches, `ApplicationConstraints\(\) \(need V2\+\) not implemented`)
+	c.Assert(err, jc.Satisfies, errors.IsNotImplemented)
+}
+
+func (s *unitprovisionerSuite) TestApplicationPlacement(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(objType, gc.Equals, "CAASUnitProvisioner")
+		c.Check(version, gc.Equals, 0)
+		c.Check(id, gc.Equals, "")
+		c.Check(request, gc.Equals, "ApplicationsPlacement")
+		c.Assert(arg, jc.DeepEquals, params.Entities{
+			Entities: []params.Entity{{
+				Tag: "application-gitlab",
+			}},
+		})
+		c.Assert(result, gc.FitsTypeOf, &params.StringResults{})
+		*(result.(*params.StringResults)) = params.StringResults{
+			Results: []params.StringResult{{
+				Result: "disktype=ssd",
+			}},
+		}
+		return nil
+	})
+
+	client := caasunitprovisioner.NewClient(apiCaller)
+	placement, err := client.ApplicationPlacement("gitlab")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(placement, gc.Equals, "disktype=ssd")
+}
+
+func (s *unitprovisionerSuite) TestStoragePool(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(objType, gc.Equals, "CAASUnitProvisioner")
+		c.Check(version, gc.Equals, 0)
+		c.Check(id, gc.Equals, "")
+		c.Check(request, gc.Equals, "StoragePools")
+		c.Assert(arg, jc.DeepEquals, params.StoragePoolFilters{
+			Filters: []params.StoragePoolFilter{{
+				Names: []string{"fast"},
+			}},
+		})
+		c.Assert(result, gc.FitsTypeOf, &params.StoragePoolsResults{})
+		*(result.(*params.StoragePoolsResults)) = params.StoragePoolsResults{
+			Results: []params.StoragePoolsResult{{
+				Result: []params.StoragePool{{
+					Name:     "fast",
+					Provider: "kubernetes",
+					Attrs:    map[string]interface{}{"storage-class": "ssd"},
+				}},
+			}},
+		}
+		return nil
+	})
+
+	client := caasunitprovisioner.NewClient(apiCaller)
+	cfg, err := client.StoragePool("fast")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cfg.Name(), gc.Equals, "fast")
+	c.Assert(cfg.Provider(), gc.Equals, storage.ProviderType("kubernetes"))
+	c.Assert(cfg.Attrs(), jc.DeepEquals, map[string]interface{}{"storage-class": "ssd"})
+}
+
+func (s *unitprovisionerSuite) TestStoragePoolNotFound(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		*(result.(*params.StoragePoolsResults)) = params.StoragePoolsResults{
+			Results: []params.StoragePoolsResult{{}},
+		}
+		return nil
+	})
+
+	client := caasunitprovisioner.NewClient(apiCaller)
+	_, err := client.StoragePool("fast")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
 func (s *unitprovisionerSuite) TestUpdateUnits(c *gc.C) {
 	var called bool
 	client := newClient(func(objType string, version int, id, request string, a, result interface{}) error {
@@ -11,6 +11,7 @@ import (
 	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/params"
 	jujucloud "github.com/juju/juju/cloud"
+	"github.com/juju/juju/permission"
 )
 
 // Client provides methods that the Juju client command uses to interact
@@ -189,3 +190,85 @@ func (c *Client) AddCloud(cloud jujucloud.Cloud) error {
 	}
 	return nil
 }
+
+// UpdateCloud replaces the controller's definition of an existing cloud.
+func (c *Client) UpdateCloud(cloud jujucloud.Cloud) error {
+	if bestVer := c.BestAPIVersion(); bestVer < 3 {
+		return errors.NotImplementedf("UpdateCloud() (need v3+, have v%d)", bestVer)
+	}
+	args := params.UpdateCloudArgs{Clouds: []params.AddCloudArgs{{
+		Name:  cloud.Name,
+		Cloud: common.CloudToParams(cloud),
+	}}}
+	var results params.ErrorResults
+	if err := c.facade.FacadeCall("UpdateCloud", args, &results); err != nil {
+		return errors.Trace(err)
+	}
+	return results.OneError()
+}
+
+// RotateCredential switches all models using the old cloud credential
+// over to the new one, which must already have been uploaded, and then
+// removes the old credential.
+func (c *Client) RotateCredential(oldTag, newTag names.CloudCredentialTag) error {
+	if bestVer := c.BestAPIVersion(); bestVer < 3 {
+		return errors.NotImplementedf("RotateCredential() (need v3+, have v%d)", bestVer)
+	}
+	args := params.RotateCredentialArgs{Credentials: []params.RotateCredentialArg{{
+		OldTag: oldTag.String(),
+		NewTag: newTag.String(),
+	}}}
+	var results params.ErrorResults
+	if err := c.facade.FacadeCall("RotateCredentials", args, &results); err != nil {
+		return errors.Trace(err)
+	}
+	return results.OneError()
+}
+
+// GrantCloud grants a user access to the specified clouds.
+func (c *Client) GrantCloud(user, access string, clouds ...string) error {
+	return c.modifyCloudUser(params.GrantCloudAccess, user, access, clouds)
+}
+
+// RevokeCloud revokes a user's access to the specified clouds.
+func (c *Client) RevokeCloud(user, access string, clouds ...string) error {
+	return c.modifyCloudUser(params.RevokeCloudAccess, user, access, clouds)
+}
+
+func (c *Client) modifyCloudUser(action params.CloudAction, user, access string, clouds []string) error {
+	if bestVer := c.BestAPIVersion(); bestVer < 3 {
+		return errors.NotImplementedf("ModifyCloudAccess() (need v3+, have v%d)", bestVer)
+	}
+	var args params.ModifyCloudAccessRequest
+
+	if !names.IsValidUser(user) {
+		return errors.Errorf("invalid username: %q", user)
+	}
+	userTag := names.NewUserTag(user)
+
+	cloudAccess := permission.Access(access)
+	if err := permission.ValidateCloudAccess(cloudAccess); err != nil {
+		return errors.Trace(err)
+	}
+	for _, cloud := range clouds {
+		if !names.IsValidCloud(cloud) {
+			return errors.NotValidf("cloud %q", cloud)
+		}
+		args.Changes = append(args.Changes, params.ModifyCloudAccess{
+			UserTag:  userTag.String(),
+			CloudTag: names.NewCloudTag(cloud).String(),
+			Action:   action,
+			Access:   access,
+		})
+	}
+
+	var result params.ErrorResults
+	err := c.facade.FacadeCall("ModifyCloudAccess", args, &result)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(result.Results) != len(args.Changes) {
+		return errors.Errorf("expected %d results, got %d", len(args.Changes), len(result.Results))
+	}
+	return result.Combine()
+}
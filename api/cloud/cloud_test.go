@@ -346,6 +346,73 @@ func (s *cloudSuite) TestAddCloudV2API(c *gc.C) {
 	c.Assert(called, jc.IsTrue)
 }
 
+func (s *cloudSuite) TestUpdateCloudNotInV2API(c *gc.C) {
+	apiCaller := basetesting.BestVersionCaller{
+		APICallerFunc: basetesting.APICallerFunc(
+			func(objType string,
+				version int,
+				id, request string,
+				a, result interface{},
+			) error {
+				c.Fatalf("unexpected API call %q", request)
+				return nil
+			},
+		),
+		BestVersion: 2,
+	}
+	client := cloudapi.NewClient(apiCaller)
+	err := client.UpdateCloud(cloud.Cloud{
+		Name:      "foo",
+		Type:      "dummy",
+		AuthTypes: []cloud.AuthType{cloud.EmptyAuthType},
+	})
+	c.Assert(err, gc.ErrorMatches, "UpdateCloud\\(\\).* not implemented")
+}
+
+func (s *cloudSuite) TestUpdateCloudV3API(c *gc.C) {
+	var called bool
+	apiCaller := basetesting.BestVersionCaller{
+		APICallerFunc: basetesting.APICallerFunc(
+			func(objType string,
+				version int,
+				id, request string,
+				a, result interface{},
+			) error {
+				called = true
+				c.Check(objType, gc.Equals, "Cloud")
+				c.Check(id, gc.Equals, "")
+				c.Check(request, gc.Equals, "UpdateCloud")
+				c.Check(a, jc.DeepEquals, params.UpdateCloudArgs{Clouds: []params.AddCloudArgs{{
+					Name: "foo",
+					Cloud: params.Cloud{
+						Type:      "dummy",
+						AuthTypes: []string{"empty", "userpass"},
+						Regions:   []params.CloudRegion{{Name: "nether", Endpoint: "endpoint"}},
+					},
+				}}})
+				c.Assert(result, gc.FitsTypeOf, &params.ErrorResults{})
+				*result.(*params.ErrorResults) = params.ErrorResults{
+					Results: []params.ErrorResult{{
+						Error: &params.Error{Message: "boom"},
+					}},
+				}
+				return nil
+			},
+		),
+		BestVersion: 3,
+	}
+
+	client := cloudapi.NewClient(apiCaller)
+	err := client.UpdateCloud(cloud.Cloud{
+		Name:      "foo",
+		Type:      "dummy",
+		AuthTypes: []cloud.AuthType{cloud.EmptyAuthType, cloud.UserPassAuthType},
+		Regions:   []cloud.Region{{Name: "nether", Endpoint: "endpoint"}},
+	})
+	c.Assert(err, gc.ErrorMatches, "boom")
+	c.Assert(called, jc.IsTrue)
+}
+
 func (s *cloudSuite) TestAddCredentialNotInV1API(c *gc.C) {
 	apiCaller := basetesting.BestVersionCaller{
 		APICallerFunc: basetesting.APICallerFunc(
@@ -398,3 +465,179 @@ func (s *cloudSuite) TestAddCredentialV2API(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(called, jc.IsTrue)
 }
+
+func (s *cloudSuite) TestRotateCredentialNotInV2API(c *gc.C) {
+	apiCaller := basetesting.BestVersionCaller{
+		APICallerFunc: basetesting.APICallerFunc(
+			func(objType string,
+				version int,
+				id, request string,
+				a, result interface{},
+			) error {
+				c.Fatalf("unexpected API call %q", request)
+				return nil
+			},
+		),
+		BestVersion: 2,
+	}
+	client := cloudapi.NewClient(apiCaller)
+	err := client.RotateCredential(
+		names.NewCloudCredentialTag("foo/bob/old"),
+		names.NewCloudCredentialTag("foo/bob/new"),
+	)
+	c.Assert(err, gc.ErrorMatches, "RotateCredential\\(\\).* not implemented")
+}
+
+func (s *cloudSuite) TestRotateCredentialV3API(c *gc.C) {
+	var called bool
+	apiCaller := basetesting.BestVersionCaller{
+		APICallerFunc: basetesting.APICallerFunc(
+			func(objType string,
+				version int,
+				id, request string,
+				a, result interface{},
+			) error {
+				called = true
+				c.Check(objType, gc.Equals, "Cloud")
+				c.Check(id, gc.Equals, "")
+				c.Check(request, gc.Equals, "RotateCredentials")
+				c.Check(a, jc.DeepEquals, params.RotateCredentialArgs{
+					Credentials: []params.RotateCredentialArg{{
+						OldTag: "cloudcred-foo_bob_old",
+						NewTag: "cloudcred-foo_bob_new",
+					}},
+				})
+				c.Assert(result, gc.FitsTypeOf, &params.ErrorResults{})
+				*result.(*params.ErrorResults) = params.ErrorResults{
+					Results: []params.ErrorResult{{
+						Error: &params.Error{Message: "boom"},
+					}},
+				}
+				return nil
+			},
+		),
+		BestVersion: 3,
+	}
+
+	client := cloudapi.NewClient(apiCaller)
+	err := client.RotateCredential(
+		names.NewCloudCredentialTag("foo/bob/old"),
+		names.NewCloudCredentialTag("foo/bob/new"),
+	)
+	c.Assert(err, gc.ErrorMatches, "boom")
+	c.Assert(called, jc.IsTrue)
+}
+
+func (s *cloudSuite) TestGrantCloudNotInV2API(c *gc.C) {
+	apiCaller := basetesting.BestVersionCaller{
+		APICallerFunc: basetesting.APICallerFunc(
+			func(objType string,
+				version int,
+				id, request string,
+				a, result interface{},
+			) error {
+				c.Fatalf("unexpected API call %q", request)
+				return nil
+			},
+		),
+		BestVersion: 2,
+	}
+	client := cloudapi.NewClient(apiCaller)
+	err := client.GrantCloud("fred", "add-model", "aws")
+	c.Assert(err, gc.ErrorMatches, "ModifyCloudAccess\\(\\).* not implemented")
+}
+
+func (s *cloudSuite) TestGrantCloudV3API(c *gc.C) {
+	var called bool
+	apiCaller := basetesting.BestVersionCaller{
+		APICallerFunc: basetesting.APICallerFunc(
+			func(objType string,
+				version int,
+				id, request string,
+				a, result interface{},
+			) error {
+				called = true
+				c.Check(objType, gc.Equals, "Cloud")
+				c.Check(id, gc.Equals, "")
+				c.Check(request, gc.Equals, "ModifyCloudAccess")
+				c.Check(a, jc.DeepEquals, params.ModifyCloudAccessRequest{
+					Changes: []params.ModifyCloudAccess{{
+						UserTag:  "user-fred",
+						CloudTag: "cloud-aws",
+						Action:   params.GrantCloudAccess,
+						Access:   "add-model",
+					}, {
+						UserTag:  "user-fred",
+						CloudTag: "cloud-gce",
+						Action:   params.GrantCloudAccess,
+						Access:   "add-model",
+					}},
+				})
+				c.Assert(result, gc.FitsTypeOf, &params.ErrorResults{})
+				*result.(*params.ErrorResults) = params.ErrorResults{
+					Results: []params.ErrorResult{{}, {
+						Error: &params.Error{Message: "boom"},
+					}},
+				}
+				return nil
+			},
+		),
+		BestVersion: 3,
+	}
+	client := cloudapi.NewClient(apiCaller)
+	err := client.GrantCloud("fred", "add-model", "aws", "gce")
+	c.Assert(err, gc.ErrorMatches, "boom")
+	c.Assert(called, jc.IsTrue)
+}
+
+func (s *cloudSuite) TestRevokeCloudV3API(c *gc.C) {
+	var called bool
+	apiCaller := basetesting.BestVersionCaller{
+		APICallerFunc: basetesting.APICallerFunc(
+			func(objType string,
+				version int,
+				id, request string,
+				a, result interface{},
+			) error {
+				called = true
+				c.Check(request, gc.Equals, "ModifyCloudAccess")
+				c.Check(a, jc.DeepEquals, params.ModifyCloudAccessRequest{
+					Changes: []params.ModifyCloudAccess{{
+						UserTag:  "user-fred",
+						CloudTag: "cloud-aws",
+						Action:   params.RevokeCloudAccess,
+						Access:   "admin",
+					}},
+				})
+				*result.(*params.ErrorResults) = params.ErrorResults{
+					Results: []params.ErrorResult{{}},
+				}
+				return nil
+			},
+		),
+		BestVersion: 3,
+	}
+	client := cloudapi.NewClient(apiCaller)
+	err := client.RevokeCloud("fred", "admin", "aws")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(called, jc.IsTrue)
+}
+
+func (s *cloudSuite) TestGrantCloudInvalidAccess(c *gc.C) {
+	apiCaller := basetesting.BestVersionCaller{
+		APICallerFunc: basetesting.APICallerFunc(
+			func(objType string,
+				version int,
+				id, request string,
+				a, result interface{},
+			) error {
+				c.Fatalf("unexpected API call %q", request)
+				return nil
+			},
+		),
+		BestVersion: 3,
+	}
+	client := cloudapi.NewClient(apiCaller)
+	err := client.GrantCloud("fred", "write", "aws")
+	c.Assert(err, gc.ErrorMatches, `"write" cloud access not valid`)
+}
@@ -100,11 +100,15 @@ func constructModelStatus(model names.ModelTag, owner names.UserTag, r params.Mo
 			result.CoreCount += int(*mm.Hardware.Cores)
 		}
 		result.Machines[j] = base.Machine{
-			Id:         mm.Id,
-			InstanceId: mm.InstanceId,
-			HasVote:    mm.HasVote,
-			WantsVote:  mm.WantsVote,
-			Status:     mm.Status,
+			Id:                mm.Id,
+			InstanceId:        mm.InstanceId,
+			HasVote:           mm.HasVote,
+			WantsVote:         mm.WantsVote,
+			Status:            mm.Status,
+			AgentVersion:      mm.AgentVersion,
+			ReplicaSetState:   mm.ReplicaSetState,
+			ReplicaSetHealthy: mm.ReplicaSetHealthy,
+			APIAddresses:      mm.APIAddresses,
 		}
 	}
 	return result
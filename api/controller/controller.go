@@ -315,6 +315,39 @@ func (c *Client) InitiateMigration(spec MigrationSpec) (string, error) {
 	return result.MigrationId, nil
 }
 
+// PauseMigration pauses the active migration of the model with the
+// given UUID. The migration stops before moving on to its next phase
+// until it is resumed or aborted.
+func (c *Client) PauseMigration(modelUUID string) error {
+	return c.changeMigration("PauseMigration", modelUUID)
+}
+
+// ResumeMigration resumes the paused migration of the model with the
+// given UUID.
+func (c *Client) ResumeMigration(modelUUID string) error {
+	return c.changeMigration("ResumeMigration", modelUUID)
+}
+
+// AbortMigration aborts the active migration of the model with the
+// given UUID, rolling the model back to this controller.
+func (c *Client) AbortMigration(modelUUID string) error {
+	return c.changeMigration("AbortMigration", modelUUID)
+}
+
+func (c *Client) changeMigration(request, modelUUID string) error {
+	if c.BestAPIVersion() < 5 {
+		return errors.NotSupportedf("pausing, resuming and aborting migrations by this controller")
+	}
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: names.NewModelTag(modelUUID).String()}},
+	}
+	var results params.ErrorResults
+	if err := c.facade.FacadeCall(request, args, &results); err != nil {
+		return errors.Trace(err)
+	}
+	return results.OneError()
+}
+
 func macaroonsToJSON(macs []macaroon.Slice) (string, error) {
 	if len(macs) == 0 {
 		return "", nil
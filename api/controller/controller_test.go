@@ -170,6 +170,60 @@ func (s *Suite) TestInitiateMigrationValidationError(c *gc.C) {
 	c.Check(stub.Calls(), gc.HasLen, 0) // API call shouldn't have happened
 }
 
+func (s *Suite) TestChangeMigration(c *gc.C) {
+	for _, test := range []struct {
+		request string
+		call    func(*controller.Client, string) error
+	}{
+		{"PauseMigration", (*controller.Client).PauseMigration},
+		{"ResumeMigration", (*controller.Client).ResumeMigration},
+		{"AbortMigration", (*controller.Client).AbortMigration},
+	} {
+		c.Logf("request %s", test.request)
+		var stub jujutesting.Stub
+		apiCaller := apitesting.BestVersionCaller{
+			BestVersion: 5,
+			APICallerFunc: func(objType string, version int, id, request string, arg, result interface{}) error {
+				stub.AddCall(objType+"."+request, arg)
+				*(result.(*params.ErrorResults)) = params.ErrorResults{
+					Results: []params.ErrorResult{{}},
+				}
+				return nil
+			},
+		}
+		client := controller.NewClient(apiCaller)
+		err := test.call(client, "bad-dead-beef")
+		c.Assert(err, jc.ErrorIsNil)
+		stub.CheckCalls(c, []jujutesting.StubCall{
+			{"Controller." + test.request, []interface{}{params.Entities{
+				Entities: []params.Entity{{Tag: "model-bad-dead-beef"}},
+			}}},
+		})
+	}
+}
+
+func (s *Suite) TestChangeMigrationError(c *gc.C) {
+	apiCaller := apitesting.BestVersionCaller{
+		BestVersion: 5,
+		APICallerFunc: func(objType string, version int, id, request string, arg, result interface{}) error {
+			*(result.(*params.ErrorResults)) = params.ErrorResults{
+				Results: []params.ErrorResult{{Error: &params.Error{Message: "boom"}}},
+			}
+			return nil
+		},
+	}
+	client := controller.NewClient(apiCaller)
+	err := client.AbortMigration("bad-dead-beef")
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
+
+func (s *Suite) TestChangeMigrationAPIVersion(c *gc.C) {
+	apiCaller := apitesting.BestVersionCaller{BestVersion: 4}
+	client := controller.NewClient(apiCaller)
+	err := client.PauseMigration("bad-dead-beef")
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
+
 func (s *Suite) TestHostedModelConfigs_CallError(c *gc.C) {
 	apiCaller := apitesting.APICallerFunc(func(string, int, string, string, interface{}, interface{}) error {
 		return errors.New("boom")
@@ -0,0 +1,122 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package credentialvalidator implements the client-side API facade
+// used by the credentialvalidator worker.
+package credentialvalidator
+
+import (
+	"github.com/juju/errors"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/api/base"
+	apiwatcher "github.com/juju/juju/api/watcher"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/status"
+	"github.com/juju/juju/watcher"
+)
+
+// ModelCredential describes the cloud credential used by a model.
+type ModelCredential struct {
+	// Exists is true if the model uses a cloud credential.
+	Exists bool
+
+	// Credential is the tag of the credential, if one is used.
+	Credential names.CloudCredentialTag
+
+	// Valid is true if the credential exists and has not been
+	// revoked.
+	Valid bool
+}
+
+// Facade provides access to the CredentialValidator API facade, on
+// behalf of a single model.
+type Facade struct {
+	caller base.FacadeCaller
+	tag    names.ModelTag
+}
+
+// NewFacade creates a new client-side CredentialValidator facade for
+// the specified model.
+func NewFacade(caller base.APICaller, tag names.ModelTag) *Facade {
+	return &Facade{
+		caller: base.NewFacadeCaller(caller, "CredentialValidator"),
+		tag:    tag,
+	}
+}
+
+// ModelCredential returns the cloud credential used by the model.
+func (f *Facade) ModelCredential() (ModelCredential, error) {
+	var result params.ModelCredential
+	err := f.caller.FacadeCall("ModelCredential", nil, &result)
+	if err != nil {
+		return ModelCredential{}, errors.Trace(err)
+	}
+	if result.Model != f.tag.String() {
+		return ModelCredential{}, errors.Errorf("expected credential for %s, got %s", f.tag, result.Model)
+	}
+	if !result.Exists {
+		return ModelCredential{}, nil
+	}
+	tag, err := names.ParseCloudCredentialTag(result.CloudCredential)
+	if err != nil {
+		return ModelCredential{}, errors.Trace(err)
+	}
+	return ModelCredential{
+		Exists:     true,
+		Credential: tag,
+		Valid:      result.Valid,
+	}, nil
+}
+
+// WatchCredential returns a watcher that notifies of changes to the
+// specified credential, which must be the one used by the model.
+func (f *Facade) WatchCredential(tag names.CloudCredentialTag) (watcher.NotifyWatcher, error) {
+	var result params.NotifyWatchResult
+	err := f.caller.FacadeCall("WatchCredential", params.Entity{Tag: tag.String()}, &result)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return apiwatcher.NewNotifyWatcher(f.caller.RawAPICaller(), result), nil
+}
+
+// ModelStatus returns the status of the model.
+func (f *Facade) ModelStatus() (status.Status, error) {
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: f.tag.String()}},
+	}
+	var results params.StatusResults
+	err := f.caller.FacadeCall("Status", args, &results)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	if len(results.Results) != 1 {
+		return "", errors.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return "", result.Error
+	}
+	return status.Status(result.Status), nil
+}
+
+// SetModelStatus sets the status of the model.
+func (f *Facade) SetModelStatus(modelStatus status.Status, info string, data map[string]interface{}) error {
+	args := params.SetStatus{
+		Entities: []params.EntityStatusArgs{{
+			Tag:    f.tag.String(),
+			Status: modelStatus.String(),
+			Info:   info,
+			Data:   data,
+		}},
+	}
+	var results params.ErrorResults
+	err := f.caller.FacadeCall("SetStatus", args, &results)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return results.OneError()
+}
@@ -0,0 +1,136 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package credentialvalidator_test
+
+import (
+	"errors"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v2"
+
+	basetesting "github.com/juju/juju/api/base/testing"
+	"github.com/juju/juju/api/credentialvalidator"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/status"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type facadeSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&facadeSuite{})
+
+var credentialTag = names.NewCloudCredentialTag("dummy/fred/default")
+
+func (s *facadeSuite) newFacade(c *gc.C, stub *testing.Stub, result interface{}) *credentialvalidator.Facade {
+	apiCaller := basetesting.APICallerFunc(func(
+		objType string, version int,
+		id, request string,
+		args, response interface{},
+	) error {
+		c.Check(objType, gc.Equals, "CredentialValidator")
+		c.Check(version, gc.Equals, 0)
+		c.Check(id, gc.Equals, "")
+		stub.AddCall(request, args)
+		switch response := response.(type) {
+		case *params.ModelCredential:
+			*response = result.(params.ModelCredential)
+		case *params.StatusResults:
+			*response = result.(params.StatusResults)
+		case *params.ErrorResults:
+			*response = result.(params.ErrorResults)
+		default:
+			c.Fatalf("unexpected response type %T", response)
+		}
+		return stub.NextErr()
+	})
+	return credentialvalidator.NewFacade(apiCaller, coretesting.ModelTag)
+}
+
+func (s *facadeSuite) TestModelCredential(c *gc.C) {
+	stub := new(testing.Stub)
+	facade := s.newFacade(c, stub, params.ModelCredential{
+		Model:           coretesting.ModelTag.String(),
+		Exists:          true,
+		CloudCredential: credentialTag.String(),
+		Valid:           true,
+	})
+	credential, err := facade.ModelCredential()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(credential, jc.DeepEquals, credentialvalidator.ModelCredential{
+		Exists:     true,
+		Credential: credentialTag,
+		Valid:      true,
+	})
+	stub.CheckCalls(c, []testing.StubCall{{"ModelCredential", []interface{}{nil}}})
+}
+
+func (s *facadeSuite) TestModelCredentialNotSet(c *gc.C) {
+	stub := new(testing.Stub)
+	facade := s.newFacade(c, stub, params.ModelCredential{
+		Model: coretesting.ModelTag.String(),
+	})
+	credential, err := facade.ModelCredential()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(credential, jc.DeepEquals, credentialvalidator.ModelCredential{})
+}
+
+func (s *facadeSuite) TestModelCredentialWrongModel(c *gc.C) {
+	stub := new(testing.Stub)
+	facade := s.newFacade(c, stub, params.ModelCredential{
+		Model: names.NewModelTag("deadbeef-0bad-400d-8000-4b1d0d06f00d").String(),
+	})
+	_, err := facade.ModelCredential()
+	c.Check(err, gc.ErrorMatches, "expected credential for model-.*, got model-deadbeef-.*")
+}
+
+func (s *facadeSuite) TestModelCredentialError(c *gc.C) {
+	stub := new(testing.Stub)
+	stub.SetErrors(errors.New("boom"))
+	facade := s.newFacade(c, stub, params.ModelCredential{})
+	_, err := facade.ModelCredential()
+	c.Check(err, gc.ErrorMatches, "boom")
+}
+
+func (s *facadeSuite) TestModelStatus(c *gc.C) {
+	stub := new(testing.Stub)
+	facade := s.newFacade(c, stub, params.StatusResults{
+		Results: []params.StatusResult{{Status: "suspended"}},
+	})
+	modelStatus, err := facade.ModelStatus()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(modelStatus, gc.Equals, status.Suspended)
+	stub.CheckCalls(c, []testing.StubCall{{"Status", []interface{}{params.Entities{
+		Entities: []params.Entity{{Tag: coretesting.ModelTag.String()}},
+	}}}})
+}
+
+func (s *facadeSuite) TestModelStatusError(c *gc.C) {
+	stub := new(testing.Stub)
+	facade := s.newFacade(c, stub, params.StatusResults{
+		Results: []params.StatusResult{{Error: &params.Error{Message: "nope"}}},
+	})
+	_, err := facade.ModelStatus()
+	c.Check(err, gc.ErrorMatches, "nope")
+}
+
+func (s *facadeSuite) TestSetModelStatus(c *gc.C) {
+	stub := new(testing.Stub)
+	facade := s.newFacade(c, stub, params.ErrorResults{
+		Results: []params.ErrorResult{{}},
+	})
+	err := facade.SetModelStatus(status.Suspended, "revoked", map[string]interface{}{"a": "b"})
+	c.Assert(err, jc.ErrorIsNil)
+	stub.CheckCalls(c, []testing.StubCall{{"SetStatus", []interface{}{params.SetStatus{
+		Entities: []params.EntityStatusArgs{{
+			Tag:    coretesting.ModelTag.String(),
+			Status: "suspended",
+			Info:   "revoked",
+			Data:   map[string]interface{}{"a": "b"},
+		}},
+	}}}})
+}
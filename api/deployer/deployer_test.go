@@ -13,6 +13,7 @@ import (
 	"github.com/juju/juju/api"
 	"github.com/juju/juju/api/deployer"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/juju/testing"
 	"github.com/juju/juju/network"
 	"github.com/juju/juju/state"
@@ -239,3 +240,38 @@ func (s *deployerSuite) TestUnitSetStatus(c *gc.C) {
 		Data:    map[string]interface{}{"foo": "bar"},
 	})
 }
+
+func (s *deployerSuite) TestUnitSetAgentStatus(c *gc.C) {
+	unit, err := s.st.Unit(s.principal.Tag().(names.UnitTag))
+	c.Assert(err, jc.ErrorIsNil)
+	err = unit.SetAgentStatus(status.Failed, "agent not responding", nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	stateUnit, err := s.BackingState.Unit(unit.Name())
+	c.Assert(err, jc.ErrorIsNil)
+	sInfo, err := stateUnit.AgentStatus()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(sInfo.Status, gc.Equals, status.Failed)
+	c.Assert(sInfo.Message, gc.Equals, "agent not responding")
+}
+
+func (s *deployerSuite) TestUnitConstraints(c *gc.C) {
+	err := s.app0.SetConstraints(constraints.MustParse("mem=1G"))
+	c.Assert(err, jc.ErrorIsNil)
+	principal, err := s.app0.AddUnit(state.AddUnitParams{})
+	c.Assert(err, jc.ErrorIsNil)
+	err = principal.AssignToMachine(s.machine)
+	c.Assert(err, jc.ErrorIsNil)
+
+	unit, err := s.st.Unit(principal.Tag().(names.UnitTag))
+	c.Assert(err, jc.ErrorIsNil)
+	cons, err := unit.Constraints()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cons, jc.DeepEquals, constraints.MustParse("mem=1G"))
+
+	unit, err = s.st.Unit(s.subordinate.Tag().(names.UnitTag))
+	c.Assert(err, jc.ErrorIsNil)
+	cons, err = unit.Constraints()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cons, jc.DeepEquals, constraints.Value{})
+}
@@ -4,10 +4,12 @@
 package deployer
 
 import (
+	"github.com/juju/errors"
 	"gopkg.in/juju/names.v2"
 
 	"github.com/juju/juju/api/common"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/status"
 )
 
@@ -86,3 +88,44 @@ func (u *Unit) SetStatus(unitStatus status.Status, info string, data map[string]
 	}
 	return result.OneError()
 }
+
+// SetAgentStatus sets the status of the unit's agent.
+func (u *Unit) SetAgentStatus(agentStatus status.Status, info string, data map[string]interface{}) error {
+	if u.st.facade.BestAPIVersion() < 3 {
+		return errors.NotImplementedf("SetAgentStatus() (need V3+)")
+	}
+	var result params.ErrorResults
+	args := params.SetStatus{
+		Entities: []params.EntityStatusArgs{
+			{Tag: u.tag.String(), Status: agentStatus.String(), Info: info, Data: data},
+		},
+	}
+	err := u.st.facade.FacadeCall("SetAgentStatus", args, &result)
+	if err != nil {
+		return err
+	}
+	return result.OneError()
+}
+
+// Constraints returns the unit's deployment constraints.
+func (u *Unit) Constraints() (constraints.Value, error) {
+	if u.st.facade.BestAPIVersion() < 2 {
+		return constraints.Value{}, errors.NotImplementedf("Constraints() (need V2+)")
+	}
+	var results params.ConstraintsResults
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: u.tag.String()}},
+	}
+	err := u.st.facade.FacadeCall("Constraints", args, &results)
+	if err != nil {
+		return constraints.Value{}, err
+	}
+	if len(results.Results) != 1 {
+		return constraints.Value{}, errors.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return constraints.Value{}, result.Error
+	}
+	return result.Constraints, nil
+}
@@ -18,31 +18,33 @@ var facadeVersions = map[string]int{
 	"AllModelWatcher":              2,
 	"AllWatcher":                   1,
 	"Annotations":                  2,
-	"Application":                  6,
+	"Application":                  8,
 	"ApplicationOffers":            1,
 	"ApplicationScaler":            1,
+	"Audit":                        1,
 	"Backups":                      1,
 	"Block":                        2,
 	"Bundle":                       1,
 	"CAASFirewaller":               1,
 	"CAASOperator":                 1,
 	"CAASOperatorProvisioner":      1,
-	"CAASUnitProvisioner":          1,
+	"CAASUnitProvisioner":          2,
 	"CharmRevisionUpdater":         2,
 	"Charms":                       2,
 	"Cleaner":                      2,
 	"Client":                       1,
-	"Cloud":                        2,
-	"Controller":                   4,
+	"Cloud":                        3,
+	"Controller":                   5,
+	"CredentialValidator":          1,
 	"CrossController":              1,
 	"CrossModelRelations":          1,
-	"Deployer":                     1,
+	"Deployer":                     3,
 	"DiskManager":                  2,
 	"EntityWatcher":                2,
 	"ExternalControllerUpdater":    1,
 	"FanConfigurer":                1,
 	"FilesystemAttachmentsWatcher": 2,
-	"Firewaller":                   5,
+	"Firewaller":                   6,
 	"FirewallRules":                1,
 	"HighAvailability":             2,
 	"HostKeyReporter":              1,
@@ -62,7 +64,7 @@ var facadeVersions = map[string]int{
 	"Machiner":                     1,
 	"MeterStatus":                  1,
 	"MetricsAdder":                 2,
-	"MetricsDebug":                 2,
+	"MetricsDebug":                 3,
 	"MetricsManager":               1,
 	"MigrationFlag":                1,
 	"MigrationMaster":              1,
@@ -70,7 +72,8 @@ var facadeVersions = map[string]int{
 	"MigrationStatusWatcher":       1,
 	"MigrationTarget":              1,
 	"ModelConfig":                  1,
-	"ModelManager":                 4,
+	"ModelManager":                 5,
+	"ModelPower":                   1,
 	"ModelUpgrader":                1,
 	"NotifyWatcher":                1,
 	"OfferStatusWatcher":           1,
@@ -83,13 +86,13 @@ var facadeVersions = map[string]int{
 	"RelationStatusWatcher":        1,
 	"RelationUnitsWatcher":         1,
 	"RemoteRelations":              1,
-	"Resources":                    1,
+	"Resources":                    2,
 	"ResourcesHookContext":         1,
 	"Resumer":                      2,
 	"RetryStrategy":                1,
 	"Singular":                     2,
 	"Spaces":                       3,
-	"SSHClient":                    2,
+	"SSHClient":                    3,
 	"StatusHistory":                2,
 	"Storage":                      4,
 	"StorageProvisioner":           4,
@@ -99,6 +102,7 @@ var facadeVersions = map[string]int{
 	"UnitAssigner":                 1,
 	"Uniter":                       7,
 	"Upgrader":                     1,
+	"UpgradeSeries":                1,
 	"UserManager":                  2,
 	"VolumeAttachmentsWatcher":     2,
 }
@@ -4,6 +4,7 @@
 package firewaller_test
 
 import (
+	"github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
 	"github.com/juju/utils"
 	gc "gopkg.in/check.v1"
@@ -246,3 +247,42 @@ func (s *firewallerSuite) TestFirewallRules(c *gc.C) {
 	c.Assert(result, gc.HasLen, 1)
 	c.Check(callCount, gc.Equals, 1)
 }
+
+func (s *firewallerSuite) TestWatchFirewallRules(c *gc.C) {
+	var callCount int
+	apiCaller := testing.BestVersionCaller{
+		APICallerFunc: testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+			c.Check(objType, gc.Equals, "Firewaller")
+			c.Check(version, gc.Equals, 6)
+			c.Check(id, gc.Equals, "")
+			c.Check(request, gc.Equals, "WatchFirewallRules")
+			c.Assert(arg, gc.IsNil)
+			c.Assert(result, gc.FitsTypeOf, &params.NotifyWatchResult{})
+			*(result.(*params.NotifyWatchResult)) = params.NotifyWatchResult{
+				Error: &params.Error{Message: "FAIL"},
+			}
+			callCount++
+			return nil
+		}),
+		BestVersion: 6,
+	}
+	client, err := firewaller.NewClient(apiCaller)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = client.WatchFirewallRules()
+	c.Check(err, gc.ErrorMatches, "FAIL")
+	c.Check(callCount, gc.Equals, 1)
+}
+
+func (s *firewallerSuite) TestWatchFirewallRulesNotSupported(c *gc.C) {
+	apiCaller := testing.BestVersionCaller{
+		APICallerFunc: testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+			c.Fatalf("unexpected call to %s", request)
+			return nil
+		}),
+		BestVersion: 5,
+	}
+	client, err := firewaller.NewClient(apiCaller)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = client.WatchFirewallRules()
+	c.Check(err, jc.Satisfies, errors.IsNotSupported)
+}
@@ -148,6 +148,11 @@ type DialOpts struct {
 	// the HTTP client is ignored.
 	BakeryClient *httpbakery.Client
 
+	// TokenSource is used to obtain ID tokens when the controller
+	// requires external users to log in with an OpenID Connect
+	// provider. If it is nil, such logins will fail.
+	TokenSource TokenSource
+
 	// InsecureSkipVerify skips TLS certificate verification
 	// when connecting to the controller. This should only
 	// be used in tests, or when verification cannot be
@@ -184,6 +189,13 @@ type IPAddrResolver interface {
 	LookupIPAddr(ctx context.Context, host string) ([]net.IPAddr, error)
 }
 
+// TokenSource provides ID tokens issued by an OpenID Connect provider.
+type TokenSource interface {
+	// Token returns an ID token issued by the provider with the
+	// given issuer URL to the client with the given ID.
+	Token(issuerURL, clientID string) (string, error)
+}
+
 // DNSCache implements a cache of DNS lookup results.
 type DNSCache interface {
 	// Lookup returns the IP addresses associated
@@ -27,6 +27,10 @@ type MetricsDebugClient interface {
 	// supplied GetMetrics will return all the metrics recorded in the
 	// current model.
 	GetMetrics(tags ...string) ([]params.MetricResult, error)
+
+	// GetMetricsHistory behaves like GetMetrics, but returns every
+	// value recorded for each metric instead of only the latest one.
+	GetMetricsHistory(tags ...string) ([]params.MetricResult, error)
 }
 
 // MeterStatusClient defines methods on the metricsdebug API end point.
@@ -46,13 +50,26 @@ func NewClient(st base.APICallCloser) *Client {
 
 // GetMetrics will receive metrics collected by the given entity
 func (c *Client) GetMetrics(tags ...string) ([]params.MetricResult, error) {
+	return c.getMetrics("GetMetrics", tags)
+}
+
+// GetMetricsHistory will receive all values of the metrics collected
+// by the given entity.
+func (c *Client) GetMetricsHistory(tags ...string) ([]params.MetricResult, error) {
+	if c.BestAPIVersion() < 3 {
+		return nil, errors.NotSupportedf("metrics history")
+	}
+	return c.getMetrics("GetMetricsHistory", tags)
+}
+
+func (c *Client) getMetrics(method string, tags []string) ([]params.MetricResult, error) {
 	entities := make([]params.Entity, len(tags))
 	for i, tag := range tags {
 		entities[i] = params.Entity{Tag: tag}
 	}
 	p := params.Entities{Entities: entities}
 	results := new(params.MetricResults)
-	if err := c.facade.FacadeCall("GetMetrics", p, results); err != nil {
+	if err := c.facade.FacadeCall(method, p, results); err != nil {
 		return nil, errors.Trace(err)
 	}
 	if err := results.OneError(); err != nil {
@@ -152,6 +152,65 @@ func (s *metricsdebugSuiteMock) TestGetMetricsForModelFails(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, "an error")
 }
 
+func (s *metricsdebugSuiteMock) TestGetMetricsHistory(c *gc.C) {
+	var called bool
+	now := time.Now()
+	apiCaller := basetesting.BestVersionCaller{
+		APICallerFunc: basetesting.APICallerFunc(
+			func(objType string,
+				version int,
+				id, request string,
+				requestParam, response interface{},
+			) error {
+				c.Assert(request, gc.Equals, "GetMetricsHistory")
+				c.Assert(version, gc.Equals, 3)
+				entities := requestParam.(params.Entities)
+				c.Assert(entities, gc.DeepEquals, params.Entities{Entities: []params.Entity{{"unit-wordpress/0"}}})
+				result := response.(*params.MetricResults)
+				result.Results = []params.EntityMetrics{{
+					Metrics: []params.MetricResult{{
+						Key:   "pings",
+						Value: "5",
+						Time:  now,
+					}, {
+						Key:   "pings",
+						Value: "6",
+						Time:  now.Add(time.Minute),
+					}},
+				}}
+				called = true
+				return nil
+			}),
+		BestVersion: 3,
+	}
+	client := metricsdebug.NewClient(apiCaller)
+	metrics, err := client.GetMetricsHistory("unit-wordpress/0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(called, jc.IsTrue)
+	c.Assert(metrics, gc.HasLen, 2)
+	c.Assert(metrics[0].Value, gc.Equals, "5")
+	c.Assert(metrics[1].Value, gc.Equals, "6")
+}
+
+func (s *metricsdebugSuiteMock) TestGetMetricsHistoryNotSupported(c *gc.C) {
+	apiCaller := basetesting.BestVersionCaller{
+		APICallerFunc: basetesting.APICallerFunc(
+			func(objType string,
+				version int,
+				id, request string,
+				requestParam, response interface{},
+			) error {
+				c.Fatalf("unexpected API call %q", request)
+				return nil
+			}),
+		BestVersion: 2,
+	}
+	client := metricsdebug.NewClient(apiCaller)
+	metrics, err := client.GetMetricsHistory("unit-wordpress/0")
+	c.Assert(err, gc.ErrorMatches, "metrics history not supported")
+	c.Assert(metrics, gc.IsNil)
+}
+
 func (s *metricsdebugSuiteMock) TestSetMeterStatus(c *gc.C) {
 	var called bool
 	apiCaller := basetesting.APICallerFunc(
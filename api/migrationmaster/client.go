@@ -102,6 +102,7 @@ func (c *Client) MigrationStatus() (migration.MigrationStatus, error) {
 		ModelUUID:        modelTag.Id(),
 		Phase:            phase,
 		PhaseChangedTime: status.PhaseChangedTime,
+		Paused:           status.Paused,
 		TargetInfo: migration.TargetInfo{
 			ControllerTag: controllerTag,
 			Addrs:         target.Addrs,
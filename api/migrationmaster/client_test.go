@@ -97,6 +97,7 @@ func (s *ClientSuite) TestMigrationStatus(c *gc.C) {
 			MigrationId:      "id",
 			Phase:            "IMPORT",
 			PhaseChangedTime: timestamp,
+			Paused:           true,
 		}
 		return nil
 	})
@@ -108,6 +109,7 @@ func (s *ClientSuite) TestMigrationStatus(c *gc.C) {
 		ModelUUID:        modelUUID,
 		Phase:            migration.IMPORT,
 		PhaseChangedTime: timestamp,
+		Paused:           true,
 		TargetInfo: migration.TargetInfo{
 			ControllerTag: controllerTag,
 			Addrs:         []string{"2.2.2.2:2"},
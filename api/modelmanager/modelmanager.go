@@ -127,11 +127,15 @@ func convertParamsModelInfo(modelInfo params.ModelInfo) (base.ModelInfo, error)
 	result.Machines = make([]base.Machine, len(modelInfo.Machines))
 	for i, m := range modelInfo.Machines {
 		machine := base.Machine{
-			Id:         m.Id,
-			InstanceId: m.InstanceId,
-			HasVote:    m.HasVote,
-			WantsVote:  m.WantsVote,
-			Status:     m.Status,
+			Id:                m.Id,
+			InstanceId:        m.InstanceId,
+			HasVote:           m.HasVote,
+			WantsVote:         m.WantsVote,
+			Status:            m.Status,
+			AgentVersion:      m.AgentVersion,
+			ReplicaSetState:   m.ReplicaSetState,
+			ReplicaSetHealthy: m.ReplicaSetHealthy,
+			APIAddresses:      m.APIAddresses,
 		}
 		if m.Hardware != nil {
 			machine.Hardware = &instance.HardwareCharacteristics{
@@ -207,6 +211,7 @@ func (c *Client) ListModelSummaries(user string, all bool) ([]base.UserModelSumm
 			Life:               string(summary.Life),
 			ModelUserAccess:    string(summary.UserAccess),
 			UserLastConnection: summary.UserLastConnection,
+			LastConnection:     summary.LastConnection,
 			Counts:             make([]base.EntityCount, len(summary.Counts)),
 			AgentVersion:       summary.AgentVersion,
 		}
@@ -454,6 +459,7 @@ func (c *Client) ModelDefaults() (config.ModelDefaultAttributes, error) {
 		setting := config.AttributeDefaultValues{
 			Default:    val.Default,
 			Controller: val.Controller,
+			Cloud:      val.Cloud,
 		}
 		for _, region := range val.Regions {
 			setting.Regions = append(setting.Regions, config.RegionDefaultValue{
@@ -469,6 +475,9 @@ func (c *Client) ModelDefaults() (config.ModelDefaultAttributes, error) {
 func (c *Client) SetModelDefaults(cloud, region string, config map[string]interface{}) error {
 	var cloudTag string
 	if cloud != "" {
+		if region == "" && c.BestAPIVersion() < 5 {
+			return errors.NotSupportedf("set cloud model defaults on this controller")
+		}
 		cloudTag = names.NewCloudTag(cloud).String()
 	}
 	args := params.SetModelDefaults{
@@ -490,6 +499,9 @@ func (c *Client) SetModelDefaults(cloud, region string, config map[string]interf
 func (c *Client) UnsetModelDefaults(cloud, region string, keys ...string) error {
 	var cloudTag string
 	if cloud != "" {
+		if region == "" && c.BestAPIVersion() < 5 {
+			return errors.NotSupportedf("unset cloud model defaults on this controller")
+		}
 		cloudTag = names.NewCloudTag(cloud).String()
 	}
 	args := params.UnsetModelDefaults{
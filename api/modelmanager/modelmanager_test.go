@@ -242,9 +242,12 @@ func (s *modelmanagerSuite) TestModelDefaults(c *gc.C) {
 			c.Assert(result, gc.FitsTypeOf, &params.ModelDefaultsResult{})
 			results := result.(*params.ModelDefaultsResult)
 			results.Config = map[string]params.ModelDefaults{
-				"foo": {"bar", "model", []params.RegionDefaults{{
-					"dummy-region",
-					"dummy-value"}}},
+				"foo": {
+					Default:    "bar",
+					Controller: "model",
+					Regions: []params.RegionDefaults{{
+						"dummy-region",
+						"dummy-value"}}},
 			}
 			return nil
 		},
@@ -254,9 +257,12 @@ func (s *modelmanagerSuite) TestModelDefaults(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 
 	c.Assert(result, jc.DeepEquals, config.ModelDefaultAttributes{
-		"foo": {"bar", "model", []config.RegionDefaultValue{{
-			"dummy-region",
-			"dummy-value"}}},
+		"foo": {
+			Default:    "bar",
+			Controller: "model",
+			Regions: []config.RegionDefaultValue{{
+				"dummy-region",
+				"dummy-value"}}},
 	})
 }
 
@@ -328,6 +334,46 @@ func (s *modelmanagerSuite) TestUnsetModelDefaults(c *gc.C) {
 	c.Assert(called, jc.IsTrue)
 }
 
+func (s *modelmanagerSuite) TestSetModelDefaultsCloud(c *gc.C) {
+	var called bool
+	apiCaller := basetesting.BestVersionCaller{
+		BestVersion: 5,
+		APICallerFunc: func(objType string, version int, id, request string, a, result interface{}) error {
+			c.Check(objType, gc.Equals, "ModelManager")
+			c.Check(request, gc.Equals, "SetModelDefaults")
+			c.Check(a, jc.DeepEquals, params.SetModelDefaults{
+				Config: []params.ModelDefaultValues{{
+					CloudTag: "cloud-mycloud",
+					Config:   map[string]interface{}{"some-name": "value"},
+				}},
+			})
+			*(result.(*params.ErrorResults)) = params.ErrorResults{
+				Results: []params.ErrorResult{{}},
+			}
+			called = true
+			return nil
+		},
+	}
+	client := modelmanager.NewClient(apiCaller)
+	err := client.SetModelDefaults("mycloud", "", map[string]interface{}{"some-name": "value"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(called, jc.IsTrue)
+}
+
+func (s *modelmanagerSuite) TestSetModelDefaultsCloudV4(c *gc.C) {
+	client := modelmanager.NewClient(basetesting.BestVersionCaller{BestVersion: 4})
+	err := client.SetModelDefaults("mycloud", "", map[string]interface{}{"some-name": "value"})
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+	c.Assert(err, gc.ErrorMatches, "set cloud model defaults on this controller not supported")
+}
+
+func (s *modelmanagerSuite) TestUnsetModelDefaultsCloudV4(c *gc.C) {
+	client := modelmanager.NewClient(basetesting.BestVersionCaller{BestVersion: 4})
+	err := client.UnsetModelDefaults("mycloud", "", "foo")
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+	c.Assert(err, gc.ErrorMatches, "unset cloud model defaults on this controller not supported")
+}
+
 func (s *modelmanagerSuite) TestModelStatus(c *gc.C) {
 	apiCaller := basetesting.BestVersionCaller{
 		BestVersion: 4,
@@ -0,0 +1,44 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package modelpower provides access to the ModelPower facade, which
+// powers the instances of a model off and back on.
+package modelpower
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/apiserver/params"
+)
+
+// Client allows access to the model power API end point.
+type Client struct {
+	base.ClientFacade
+	facade base.FacadeCaller
+}
+
+// NewClient creates a new client for accessing the model power API.
+func NewClient(st base.APICallCloser) *Client {
+	frontend, backend := base.NewClientFacade(st, "ModelPower")
+	return &Client{ClientFacade: frontend, facade: backend}
+}
+
+// SuspendModel powers off the instances of the model's machines,
+// keeping their disks and storage.
+func (c *Client) SuspendModel() (params.ModelPowerResult, error) {
+	var result params.ModelPowerResult
+	if err := c.facade.FacadeCall("SuspendModel", nil, &result); err != nil {
+		return params.ModelPowerResult{}, errors.Trace(err)
+	}
+	return result, nil
+}
+
+// ResumeModel powers on the instances of a suspended model's machines.
+func (c *Client) ResumeModel() (params.ModelPowerResult, error) {
+	var result params.ModelPowerResult
+	if err := c.facade.FacadeCall("ResumeModel", nil, &result); err != nil {
+		return params.ModelPowerResult{}, errors.Trace(err)
+	}
+	return result, nil
+}
@@ -0,0 +1,64 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package modelpower_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	basetesting "github.com/juju/juju/api/base/testing"
+	"github.com/juju/juju/api/modelpower"
+	"github.com/juju/juju/apiserver/params"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type modelPowerSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&modelPowerSuite{})
+
+func (s *modelPowerSuite) apiCaller(c *gc.C, method string) basetesting.APICallerFunc {
+	return basetesting.APICallerFunc(
+		func(objType string, version int, id, request string, a, result interface{}) error {
+			c.Check(objType, gc.Equals, "ModelPower")
+			c.Check(id, gc.Equals, "")
+			c.Check(request, gc.Equals, method)
+			c.Check(a, gc.IsNil)
+			c.Assert(result, gc.FitsTypeOf, &params.ModelPowerResult{})
+			*(result.(*params.ModelPowerResult)) = params.ModelPowerResult{
+				Machines: []string{"0", "1"},
+				Skipped:  []string{"2"},
+			}
+			return nil
+		})
+}
+
+func (s *modelPowerSuite) TestSuspendModel(c *gc.C) {
+	client := modelpower.NewClient(s.apiCaller(c, "SuspendModel"))
+	result, err := client.SuspendModel()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.ModelPowerResult{
+		Machines: []string{"0", "1"},
+		Skipped:  []string{"2"},
+	})
+}
+
+func (s *modelPowerSuite) TestResumeModel(c *gc.C) {
+	client := modelpower.NewClient(s.apiCaller(c, "ResumeModel"))
+	result, err := client.ResumeModel()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Machines, jc.DeepEquals, []string{"0", "1"})
+}
+
+func (s *modelPowerSuite) TestSuspendModelError(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string, version int, id, request string, a, result interface{}) error {
+			return errors.New("the controller model cannot be suspended")
+		})
+	client := modelpower.NewClient(apiCaller)
+	_, err := client.SuspendModel()
+	c.Assert(err, gc.ErrorMatches, "the controller model cannot be suspended")
+}
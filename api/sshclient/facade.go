@@ -8,7 +8,9 @@ import (
 	"gopkg.in/juju/names.v2"
 
 	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/api/common/cloudspec"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/environs"
 )
 
 // NewFacade returns a new Facade based on an existing API connection.
@@ -17,12 +19,14 @@ func NewFacade(callCloser base.APICallCloser) *Facade {
 	return &Facade{
 		ClientFacade: clientFacade,
 		caller:       caller,
+		cloudSpec:    cloudspec.NewCloudSpecAPI(caller, names.ModelTag{}),
 	}
 }
 
 type Facade struct {
 	base.ClientFacade
-	caller base.FacadeCaller
+	caller    base.FacadeCaller
+	cloudSpec *cloudspec.CloudSpecAPI
 }
 
 // PublicAddress returns the public address for the SSH target
@@ -111,6 +115,28 @@ func (facade *Facade) Proxy() (bool, error) {
 	return out.UseProxy, nil
 }
 
+// ModelCredentialForSSH returns the cloud spec of the associated
+// CAAS model, used to run commands in the pods of its units. An
+// error satisfying errors.IsNotSupported is returned for models
+// which are not CAAS models.
+func (facade *Facade) ModelCredentialForSSH() (environs.CloudSpec, error) {
+	if facade.BestAPIVersion() < 3 {
+		return environs.CloudSpec{}, errors.NotSupportedf("ModelCredentialForSSH on this controller")
+	}
+	var out params.CloudSpecResult
+	err := facade.caller.FacadeCall("ModelCredentialForSSH", nil, &out)
+	if err != nil {
+		return environs.CloudSpec{}, errors.Trace(err)
+	}
+	if out.Error != nil {
+		if params.IsCodeNotSupported(out.Error) {
+			return environs.CloudSpec{}, errors.NewNotSupported(out.Error, "")
+		}
+		return environs.CloudSpec{}, errors.Trace(out.Error)
+	}
+	return facade.cloudSpec.MakeCloudSpec(out.Result)
+}
+
 func targetToEntities(target string) (params.Entities, error) {
 	tag, err := targetToTag(target)
 	if err != nil {
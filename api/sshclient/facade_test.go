@@ -280,3 +280,60 @@ func (s *FacadeSuite) TestProxyError(c *gc.C) {
 	_, err := facade.Proxy()
 	c.Check(err, gc.ErrorMatches, "boom")
 }
+
+func (s *FacadeSuite) TestModelCredentialForSSH(c *gc.C) {
+	var stub jujutesting.Stub
+	apiCaller := apitesting.BestVersionCaller{
+		APICallerFunc: func(objType string, version int, id, request string, arg, result interface{}) error {
+			stub.AddCall(objType+"."+request, arg)
+			*result.(*params.CloudSpecResult) = params.CloudSpecResult{
+				Result: &params.CloudSpec{
+					Type:     "kubernetes",
+					Name:     "k8s",
+					Endpoint: "https://10.0.0.1:6443",
+					Credential: &params.CloudCredential{
+						AuthType:   "userpass",
+						Attributes: map[string]string{"username": "fred", "password": "secret"},
+					},
+				},
+			}
+			return nil
+		},
+		BestVersion: 3,
+	}
+	facade := sshclient.NewFacade(apiCaller)
+	spec, err := facade.ModelCredentialForSSH()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(spec.Type, gc.Equals, "kubernetes")
+	c.Check(spec.Endpoint, gc.Equals, "https://10.0.0.1:6443")
+	c.Check(spec.Credential.Attributes(), jc.DeepEquals, map[string]string{"username": "fred", "password": "secret"})
+	stub.CheckCalls(c, []jujutesting.StubCall{{"SSHClient.ModelCredentialForSSH", []interface{}{nil}}})
+}
+
+func (s *FacadeSuite) TestModelCredentialForSSHIAASModel(c *gc.C) {
+	apiCaller := apitesting.BestVersionCaller{
+		APICallerFunc: func(objType string, version int, id, request string, arg, result interface{}) error {
+			*result.(*params.CloudSpecResult) = params.CloudSpecResult{
+				Error: &params.Error{Code: params.CodeNotSupported, Message: "not a CAAS model"},
+			}
+			return nil
+		},
+		BestVersion: 3,
+	}
+	facade := sshclient.NewFacade(apiCaller)
+	_, err := facade.ModelCredentialForSSH()
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
+
+func (s *FacadeSuite) TestModelCredentialForSSHOldController(c *gc.C) {
+	apiCaller := apitesting.BestVersionCaller{
+		APICallerFunc: func(objType string, version int, id, request string, arg, result interface{}) error {
+			c.Fatalf("unexpected call %s.%s", objType, request)
+			return nil
+		},
+		BestVersion: 2,
+	}
+	facade := sshclient.NewFacade(apiCaller)
+	_, err := facade.ModelCredentialForSSH()
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
@@ -114,6 +114,28 @@ func (st *state) Login(tag names.Tag, password, nonce string, macaroons []macaro
 			return errors.Errorf("login with discharged macaroons failed: %s", result.DischargeRequiredReason)
 		}
 	}
+	if result.TokenRequired != nil {
+		// The controller requires an ID token from its
+		// OpenID Connect provider. We obtain one and
+		// retry the login request with it.
+		required := result.TokenRequired
+		if st.tokenSource == nil {
+			return errors.Errorf("cannot log in: an ID token from %q is required", required.IssuerURL)
+		}
+		token, err := st.tokenSource.Token(required.IssuerURL, required.ClientID)
+		if err != nil {
+			return errors.Annotate(err, "cannot obtain ID token")
+		}
+		request.Token = token
+		result = params.LoginResult{} // zero result
+		err = st.APICall("Admin", 3, "", "Login", request, &result)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if result.TokenRequired != nil {
+			return errors.Errorf("login with token failed: %s", result.TokenRequired.Reason)
+		}
+	}
 
 	var controllerAccess string
 	var modelAccess string
@@ -0,0 +1,147 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package upgradeseries implements the client-side API facade used
+// by the upgradeseries worker.
+package upgradeseries
+
+import (
+	"github.com/juju/errors"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/api/base"
+	apiwatcher "github.com/juju/juju/api/watcher"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/core/model"
+	"github.com/juju/juju/watcher"
+)
+
+// Facade provides access to the UpgradeSeries API facade, on behalf
+// of a single machine.
+type Facade struct {
+	caller base.FacadeCaller
+	tag    names.MachineTag
+}
+
+// NewFacade creates a new client-side UpgradeSeries facade for the
+// specified machine.
+func NewFacade(caller base.APICaller, tag names.MachineTag) *Facade {
+	return &Facade{
+		caller: base.NewFacadeCaller(caller, "UpgradeSeries"),
+		tag:    tag,
+	}
+}
+
+// WatchUpgradeSeriesNotifications returns a watcher that notifies of
+// changes to the machine's series upgrade.
+func (f *Facade) WatchUpgradeSeriesNotifications() (watcher.NotifyWatcher, error) {
+	var results params.NotifyWatchResults
+	err := f.caller.FacadeCall("WatchUpgradeSeriesNotifications", f.entities(), &results)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(results.Results) != 1 {
+		return nil, errors.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return apiwatcher.NewNotifyWatcher(f.caller.RawAPICaller(), result), nil
+}
+
+// MachineStatus returns the status of the machine's series upgrade.
+func (f *Facade) MachineStatus() (model.UpgradeSeriesStatus, error) {
+	var results params.UpgradeSeriesStatusResults
+	err := f.caller.FacadeCall("MachineStatus", f.entities(), &results)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	if len(results.Results) != 1 {
+		return "", errors.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return "", result.Error
+	}
+	return model.UpgradeSeriesStatus(result.Status), nil
+}
+
+// SetMachineStatus records the status of the machine's series upgrade.
+func (f *Facade) SetMachineStatus(status model.UpgradeSeriesStatus) error {
+	args := params.UpgradeSeriesStatusParams{
+		Params: []params.UpgradeSeriesStatusParam{{
+			Entity: params.Entity{Tag: f.tag.String()},
+			Status: string(status),
+		}},
+	}
+	var results params.ErrorResults
+	err := f.caller.FacadeCall("SetMachineStatus", args, &results)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return results.OneError()
+}
+
+// TargetSeries returns the series to which the machine is being
+// upgraded.
+func (f *Facade) TargetSeries() (string, error) {
+	var results params.StringResults
+	err := f.caller.FacadeCall("TargetSeries", f.entities(), &results)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	if len(results.Results) != 1 {
+		return "", errors.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return "", result.Error
+	}
+	return result.Result, nil
+}
+
+// UnitStatuses returns the series upgrade status of each unit taking
+// part in the machine's series upgrade.
+func (f *Facade) UnitStatuses() (map[string]model.UpgradeSeriesStatus, error) {
+	var results params.UpgradeSeriesUnitStatusesResults
+	err := f.caller.FacadeCall("UnitStatuses", f.entities(), &results)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(results.Results) != 1 {
+		return nil, errors.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	statuses := make(map[string]model.UpgradeSeriesStatus)
+	for unitName, status := range result.Statuses {
+		statuses[unitName] = model.UpgradeSeriesStatus(status)
+	}
+	return statuses, nil
+}
+
+// FinishUpgradeSeries records the series now running on the machine,
+// and ends the machine's series upgrade.
+func (f *Facade) FinishUpgradeSeries(hostSeries string) error {
+	args := params.UpdateSeriesArgs{
+		Args: []params.UpdateSeriesArg{{
+			Entity: params.Entity{Tag: f.tag.String()},
+			Series: hostSeries,
+		}},
+	}
+	var results params.ErrorResults
+	err := f.caller.FacadeCall("FinishUpgradeSeries", args, &results)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return results.OneError()
+}
+
+func (f *Facade) entities() params.Entities {
+	return params.Entities{
+		Entities: []params.Entity{{Tag: f.tag.String()}},
+	}
+}
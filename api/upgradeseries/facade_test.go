@@ -0,0 +1,149 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package upgradeseries_test
+
+import (
+	"errors"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v2"
+
+	basetesting "github.com/juju/juju/api/base/testing"
+	"github.com/juju/juju/api/upgradeseries"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/core/model"
+)
+
+type facadeSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&facadeSuite{})
+
+var machineEntities = params.Entities{
+	Entities: []params.Entity{{Tag: "machine-42"}},
+}
+
+func (s *facadeSuite) newFacade(c *gc.C, stub *testing.Stub, result interface{}) *upgradeseries.Facade {
+	apiCaller := basetesting.APICallerFunc(func(
+		objType string, version int,
+		id, request string,
+		args, response interface{},
+	) error {
+		c.Check(objType, gc.Equals, "UpgradeSeries")
+		c.Check(version, gc.Equals, 0)
+		c.Check(id, gc.Equals, "")
+		stub.AddCall(request, args)
+		switch response := response.(type) {
+		case *params.UpgradeSeriesStatusResults:
+			*response = result.(params.UpgradeSeriesStatusResults)
+		case *params.UpgradeSeriesUnitStatusesResults:
+			*response = result.(params.UpgradeSeriesUnitStatusesResults)
+		case *params.StringResults:
+			*response = result.(params.StringResults)
+		case *params.ErrorResults:
+			*response = result.(params.ErrorResults)
+		default:
+			c.Fatalf("unexpected response type %T", response)
+		}
+		return stub.NextErr()
+	})
+	return upgradeseries.NewFacade(apiCaller, names.NewMachineTag("42"))
+}
+
+func (s *facadeSuite) TestMachineStatus(c *gc.C) {
+	stub := new(testing.Stub)
+	facade := s.newFacade(c, stub, params.UpgradeSeriesStatusResults{
+		Results: []params.UpgradeSeriesStatusResult{{Status: "prepare started"}},
+	})
+
+	status, err := facade.MachineStatus()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(status, gc.Equals, model.UpgradeSeriesPrepareStarted)
+	stub.CheckCalls(c, []testing.StubCall{{"MachineStatus", []interface{}{machineEntities}}})
+}
+
+func (s *facadeSuite) TestSetMachineStatus(c *gc.C) {
+	stub := new(testing.Stub)
+	facade := s.newFacade(c, stub, params.ErrorResults{
+		Results: []params.ErrorResult{{}},
+	})
+
+	err := facade.SetMachineStatus(model.UpgradeSeriesPrepareCompleted)
+	c.Assert(err, jc.ErrorIsNil)
+	stub.CheckCalls(c, []testing.StubCall{{
+		"SetMachineStatus", []interface{}{params.UpgradeSeriesStatusParams{
+			Params: []params.UpgradeSeriesStatusParam{{
+				Entity: params.Entity{Tag: "machine-42"},
+				Status: "prepare completed",
+			}},
+		}},
+	}})
+}
+
+func (s *facadeSuite) TestTargetSeries(c *gc.C) {
+	stub := new(testing.Stub)
+	facade := s.newFacade(c, stub, params.StringResults{
+		Results: []params.StringResult{{Result: "xenial"}},
+	})
+
+	target, err := facade.TargetSeries()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(target, gc.Equals, "xenial")
+	stub.CheckCalls(c, []testing.StubCall{{"TargetSeries", []interface{}{machineEntities}}})
+}
+
+func (s *facadeSuite) TestUnitStatuses(c *gc.C) {
+	stub := new(testing.Stub)
+	facade := s.newFacade(c, stub, params.UpgradeSeriesUnitStatusesResults{
+		Results: []params.UpgradeSeriesUnitStatusesResult{{
+			Statuses: map[string]string{"mysql/0": "prepare completed"},
+		}},
+	})
+
+	statuses, err := facade.UnitStatuses()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(statuses, jc.DeepEquals, map[string]model.UpgradeSeriesStatus{
+		"mysql/0": model.UpgradeSeriesPrepareCompleted,
+	})
+}
+
+func (s *facadeSuite) TestFinishUpgradeSeries(c *gc.C) {
+	stub := new(testing.Stub)
+	facade := s.newFacade(c, stub, params.ErrorResults{
+		Results: []params.ErrorResult{{}},
+	})
+
+	err := facade.FinishUpgradeSeries("xenial")
+	c.Assert(err, jc.ErrorIsNil)
+	stub.CheckCalls(c, []testing.StubCall{{
+		"FinishUpgradeSeries", []interface{}{params.UpdateSeriesArgs{
+			Args: []params.UpdateSeriesArg{{
+				Entity: params.Entity{Tag: "machine-42"},
+				Series: "xenial",
+			}},
+		}},
+	}})
+}
+
+func (s *facadeSuite) TestCallError(c *gc.C) {
+	stub := new(testing.Stub)
+	stub.SetErrors(errors.New("blam"))
+	facade := s.newFacade(c, stub, params.UpgradeSeriesStatusResults{})
+
+	_, err := facade.MachineStatus()
+	c.Assert(err, gc.ErrorMatches, "blam")
+}
+
+func (s *facadeSuite) TestInnerError(c *gc.C) {
+	stub := new(testing.Stub)
+	facade := s.newFacade(c, stub, params.StringResults{
+		Results: []params.StringResult{{Error: &params.Error{Message: "blam"}}},
+	})
+
+	_, err := facade.TargetSeries()
+	c.Assert(err, gc.ErrorMatches, "blam")
+}
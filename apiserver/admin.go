@@ -96,6 +96,17 @@ func (a *admin) login(req params.LoginRequest, loginVersion int) (params.LoginRe
 		logger.Infof("login failed with discharge-required error: %v", err)
 		return loginResult, nil
 	}
+	if err, ok := errors.Cause(err).(*common.TokenRequiredError); ok {
+		loginResult := params.LoginResult{
+			TokenRequired: &params.TokenRequiredInfo{
+				IssuerURL: err.IssuerURL,
+				ClientID:  err.ClientID,
+				Reason:    err.Error(),
+			},
+		}
+		logger.Infof("login failed with token-required error: %v", err)
+		return loginResult, nil
+	}
 	if err != nil {
 		return fail, errors.Trace(err)
 	}
@@ -291,7 +302,8 @@ func (a *admin) handleAuthError(
 	authTag names.Tag,
 	err error,
 ) (state.Entity, error) {
-	if err, ok := errors.Cause(err).(*common.DischargeRequiredError); ok {
+	switch err := errors.Cause(err).(type) {
+	case *common.DischargeRequiredError, *common.TokenRequiredError:
 		return nil, err
 	}
 	if a.maintenanceInProgress() {
@@ -14,6 +14,7 @@ import (
 	"github.com/juju/juju/apiserver/facade"
 	"github.com/juju/juju/apiserver/facades/agent/agent" // ModelUser Write
 	"github.com/juju/juju/apiserver/facades/agent/caasoperator"
+	"github.com/juju/juju/apiserver/facades/agent/credentialvalidator"
 	"github.com/juju/juju/apiserver/facades/agent/deployer"
 	"github.com/juju/juju/apiserver/facades/agent/diskmanager"
 	"github.com/juju/juju/apiserver/facades/agent/fanconfigurer"
@@ -37,10 +38,12 @@ import (
 	"github.com/juju/juju/apiserver/facades/agent/unitassigner"
 	"github.com/juju/juju/apiserver/facades/agent/uniter"
 	"github.com/juju/juju/apiserver/facades/agent/upgrader"
+	"github.com/juju/juju/apiserver/facades/agent/upgradeseries"
 	"github.com/juju/juju/apiserver/facades/client/action"
 	"github.com/juju/juju/apiserver/facades/client/annotations" // ModelUser Write
 	"github.com/juju/juju/apiserver/facades/client/application" // ModelUser Write
 	"github.com/juju/juju/apiserver/facades/client/applicationoffers"
+	"github.com/juju/juju/apiserver/facades/client/audit"
 	"github.com/juju/juju/apiserver/facades/client/backups" // ModelUser Write
 	"github.com/juju/juju/apiserver/facades/client/block"   // ModelUser Write
 	"github.com/juju/juju/apiserver/facades/client/bundle"
@@ -57,6 +60,7 @@ import (
 	"github.com/juju/juju/apiserver/facades/client/metricsdebug"   // ModelUser Write
 	"github.com/juju/juju/apiserver/facades/client/modelconfig"    // ModelUser Write
 	"github.com/juju/juju/apiserver/facades/client/modelmanager"   // ModelUser Write
+	"github.com/juju/juju/apiserver/facades/client/modelpower"
 	"github.com/juju/juju/apiserver/facades/client/payloads"
 	"github.com/juju/juju/apiserver/facades/client/resources"
 	"github.com/juju/juju/apiserver/facades/client/spaces"    // ModelUser Write
@@ -136,9 +140,13 @@ func AllFacades() *facade.Registry {
 	reg("Application", 3, application.NewFacadeV4)
 	reg("Application", 4, application.NewFacadeV4)
 	reg("Application", 5, application.NewFacadeV5) // adds AttachStorage & UpdateApplicationSeries & SetRelationStatus
+	reg("Application", 6, application.NewFacadeV6) // adds CharmConfig & Set/UnsetApplicationsConfig
+	reg("Application", 7, application.NewFacadeV7) // adds Force to DestroyApplication
+	reg("Application", 8, application.NewFacadeV8) // adds UnitsInfo & SetApplicationsTrust
 
 	reg("ApplicationOffers", 1, applicationoffers.NewOffersAPI)
 	reg("ApplicationScaler", 1, applicationscaler.NewAPI)
+	reg("Audit", 1, audit.NewFacade)
 	reg("Backups", 1, backups.NewFacade)
 	reg("Block", 2, block.NewAPI)
 	reg("Bundle", 1, bundle.NewFacade)
@@ -150,26 +158,36 @@ func AllFacades() *facade.Registry {
 	if featureflag.Enabled(feature.CAAS) {
 		// CAAS related facades.
 		// Move these to the correct place above once the feature flag disappears.
-		reg("Application", 6, application.NewFacadeV6)
 		reg("Cloud", 2, cloud.NewFacadeV2)
+		reg("Cloud", 3, cloud.NewFacadeV3)
 		reg("CAASFirewaller", 1, caasfirewaller.NewStateFacade)
 		reg("CAASOperator", 1, caasoperator.NewStateFacade)
 		reg("CAASOperatorProvisioner", 1, caasoperatorprovisioner.NewStateCAASOperatorProvisionerAPI)
-		reg("CAASUnitProvisioner", 1, caasunitprovisioner.NewStateFacade)
+		reg("CAASUnitProvisioner", 1, caasunitprovisioner.NewStateFacadeV1)
+		reg("CAASUnitProvisioner", 2, caasunitprovisioner.NewStateFacade) // adds ApplicationsConstraints
+	} else {
+		// Version 3 of the Cloud facade builds on the CAAS-only
+		// version 2, so AddCloud is hidden without the flag.
+		reg("Cloud", 3, cloud.NewFacadeV3NoCAAS)
 	}
 
 	reg("Controller", 3, controller.NewControllerAPIv3)
 	reg("Controller", 4, controller.NewControllerAPIv4)
+	reg("Controller", 5, controller.NewControllerAPIv5)
 	reg("CrossModelRelations", 1, crossmodelrelations.NewStateCrossModelRelationsAPI)
 	reg("CrossController", 1, crosscontroller.NewStateCrossControllerAPI)
+	reg("CredentialValidator", 1, credentialvalidator.NewFacade)
 	reg("ExternalControllerUpdater", 1, externalcontrollerupdater.NewStateAPI)
 
-	reg("Deployer", 1, deployer.NewDeployerAPI)
+	reg("Deployer", 1, deployer.NewDeployerAPIV1)
+	reg("Deployer", 2, deployer.NewDeployerAPIV2)
+	reg("Deployer", 3, deployer.NewDeployerAPI) // adds SetAgentStatus
 	reg("DiskManager", 2, diskmanager.NewDiskManagerAPI)
 	reg("FanConfigurer", 1, fanconfigurer.NewFanConfigurerAPI)
 	reg("Firewaller", 3, firewaller.NewStateFirewallerAPIV3)
 	reg("Firewaller", 4, firewaller.NewStateFirewallerAPIV4)
 	reg("Firewaller", 5, firewaller.NewStateFirewallerAPIV5)
+	reg("Firewaller", 6, firewaller.NewStateFirewallerAPIV6) // adds WatchFirewallRules
 	reg("FirewallRules", 1, firewallrules.NewFacade)
 	reg("HighAvailability", 2, highavailability.NewHighAvailabilityAPI)
 	reg("HostKeyReporter", 1, hostkeyreporter.NewFacade)
@@ -198,7 +216,8 @@ func AllFacades() *facade.Registry {
 
 	reg("MeterStatus", 1, meterstatus.NewMeterStatusAPI)
 	reg("MetricsAdder", 2, metricsadder.NewMetricsAdderAPI)
-	reg("MetricsDebug", 2, metricsdebug.NewMetricsDebugAPI)
+	reg("MetricsDebug", 2, metricsdebug.NewMetricsDebugAPIV2)
+	reg("MetricsDebug", 3, metricsdebug.NewMetricsDebugAPI)
 	reg("MetricsManager", 1, metricsmanager.NewFacade)
 
 	reg("MigrationFlag", 1, migrationflag.NewFacade)
@@ -210,6 +229,8 @@ func AllFacades() *facade.Registry {
 	reg("ModelManager", 2, modelmanager.NewFacadeV2)
 	reg("ModelManager", 3, modelmanager.NewFacadeV3)
 	reg("ModelManager", 4, modelmanager.NewFacadeV4)
+	reg("ModelManager", 5, modelmanager.NewFacadeV5) // Adds cloud-wide model defaults.
+	reg("ModelPower", 1, modelpower.NewFacade)
 	reg("ModelUpgrader", 1, modelupgrader.NewStateFacade)
 
 	reg("Payloads", 1, payloads.NewFacade)
@@ -228,6 +249,7 @@ func AllFacades() *facade.Registry {
 	reg("RemoteRelations", 1, remoterelations.NewStateRemoteRelationsAPI)
 
 	reg("Resources", 1, resources.NewPublicFacade)
+	reg("Resources", 2, resources.NewPublicFacade) // v2 adds uploads in chunks to the HTTP endpoint
 	regHookContext(
 		"ResourcesHookContext", 1,
 		resourceshookcontext.NewHookContextFacade,
@@ -240,6 +262,7 @@ func AllFacades() *facade.Registry {
 
 	reg("SSHClient", 1, sshclient.NewFacade)
 	reg("SSHClient", 2, sshclient.NewFacade) // v2 adds AllAddresses() method.
+	reg("SSHClient", 3, sshclient.NewFacade) // v3 adds ModelCredentialForSSH() method.
 
 	reg("Spaces", 2, spaces.NewAPIV2)
 	reg("Spaces", 3, spaces.NewAPI)
@@ -261,6 +284,7 @@ func AllFacades() *facade.Registry {
 	reg("Uniter", 7, uniter.NewUniterAPI)
 
 	reg("Upgrader", 1, upgrader.NewUpgraderFacade)
+	reg("UpgradeSeries", 1, upgradeseries.NewFacade)
 	reg("UserManager", 1, usermanager.NewUserManagerAPI)
 	reg("UserManager", 2, usermanager.NewUserManagerAPI) // Adds ResetPassword
 
@@ -4,14 +4,17 @@
 package apiserver_test
 
 import (
-	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 
 	"github.com/juju/juju/apiserver"
+	"github.com/juju/juju/feature"
+	"github.com/juju/juju/rpc/rpcreflect"
+	coretesting "github.com/juju/juju/testing"
 )
 
 type AllFacadesSuite struct {
-	testing.IsolationSuite
+	coretesting.BaseSuite
 }
 
 var _ = gc.Suite(&AllFacadesSuite{})
@@ -21,3 +24,50 @@ func (s *AllFacadesSuite) TestNoPanic(c *gc.C) {
 	r := apiserver.AllFacades()
 	c.Assert(r, gc.NotNil)
 }
+
+func (s *AllFacadesSuite) TestApplicationWithoutCAAS(c *gc.C) {
+	// Application config is not specific to CAAS models, so the
+	// versions of the Application facade which manage it are
+	// available without the CAAS feature flag.
+	r := apiserver.AllFacades()
+	for _, version := range []int{6, 7, 8} {
+		_, err := r.GetType("Application", version)
+		c.Check(err, jc.ErrorIsNil, gc.Commentf("version %d", version))
+	}
+}
+
+func (s *AllFacadesSuite) TestCAASUnitProvisionerVersions(c *gc.C) {
+	s.SetFeatureFlags(feature.CAAS)
+	r := apiserver.AllFacades()
+	for version, expectErr := range map[int]error{
+		1: rpcreflect.ErrMethodNotFound,
+		2: nil,
+	} {
+		facadeType, err := r.GetType("CAASUnitProvisioner", version)
+		c.Assert(err, jc.ErrorIsNil)
+		objType := rpcreflect.ObjTypeOf(facadeType)
+		_, err = objType.Method("ApplicationsConstraints")
+		c.Check(err, gc.Equals, expectErr, gc.Commentf("version %d", version))
+	}
+}
+
+func (s *AllFacadesSuite) TestCloudV3WithoutCAAS(c *gc.C) {
+	facadeType, err := apiserver.AllFacades().GetType("Cloud", 3)
+	c.Assert(err, jc.ErrorIsNil)
+	objType := rpcreflect.ObjTypeOf(facadeType)
+	_, err = objType.Method("AddCloud")
+	c.Assert(err, gc.Equals, rpcreflect.ErrMethodNotFound)
+	_, err = objType.Method("UpdateCloud")
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *AllFacadesSuite) TestCloudV3WithCAAS(c *gc.C) {
+	s.SetFeatureFlags(feature.CAAS)
+	facadeType, err := apiserver.AllFacades().GetType("Cloud", 3)
+	c.Assert(err, jc.ErrorIsNil)
+	objType := rpcreflect.ObjTypeOf(facadeType)
+	_, err = objType.Method("AddCloud")
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = objType.Method("UpdateCloud")
+	c.Assert(err, jc.ErrorIsNil)
+}
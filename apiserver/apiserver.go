@@ -575,6 +575,7 @@ func (srv *Server) endpoints() []apihttp.Endpoint {
 			}
 			return rst, closer, entity.Tag(), nil
 		},
+		UploadDir: filepath.Join(srv.dataDir, "resource-uploads"),
 	})
 	add("/model/:modeluuid/units/:unit/resources/:resource", &UnitResourcesHandler{
 		NewOpener: func(req *http.Request, tagKinds ...string) (resource.Opener, state.StatePoolReleaser, error) {
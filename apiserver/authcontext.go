@@ -54,6 +54,11 @@ type authContext struct {
 	macaroonAuthOnce   sync.Once
 	_macaroonAuth      *authentication.ExternalMacaroonAuthenticator
 	_macaroonAuthError error
+
+	// oidcAuthOnce guards the fields below it.
+	oidcAuthOnce   sync.Once
+	_oidcAuth      *authentication.OIDCAuthenticator
+	_oidcAuthError error
 }
 
 // newAuthContext creates a new authentication context for st.
@@ -148,7 +153,16 @@ func (a authenticator) Authenticate(
 	tag names.Tag,
 	req params.LoginRequest,
 ) (state.Entity, error) {
-	auth, err := a.authenticatorForTag(tag)
+	var auth authentication.EntityAuthenticator
+	var err error
+	if tag == nil && req.Token != "" {
+		auth, err = a.ctxt.oidcAuth()
+		if errors.Cause(err) == errOIDCAuthNotConfigured {
+			err = errors.Trace(common.ErrNoCreds)
+		}
+	} else {
+		auth, err = a.authenticatorForTag(tag)
+	}
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
@@ -161,6 +175,11 @@ func (a authenticator) authenticatorForTag(tag names.Tag) (authentication.Entity
 	if tag == nil {
 		auth, err := a.ctxt.externalMacaroonAuth()
 		if errors.Cause(err) == errMacaroonAuthNotConfigured {
+			// Without an identity manager, external users
+			// may still log in with an OpenID Connect provider.
+			auth, err = a.ctxt.oidcAuth()
+		}
+		if errors.Cause(err) == errOIDCAuthNotConfigured {
 			err = errors.Trace(common.ErrNoCreds)
 		}
 		if err != nil {
@@ -253,6 +272,40 @@ func newExternalMacaroonAuth(st *state.State) (*authentication.ExternalMacaroonA
 	return &auth, nil
 }
 
+// oidcAuth returns an authenticator that can authenticate logins for
+// external users with ID tokens issued by an OpenID Connect provider.
+// If it fails once, it will always fail.
+func (ctxt *authContext) oidcAuth() (authentication.EntityAuthenticator, error) {
+	ctxt.oidcAuthOnce.Do(func() {
+		ctxt._oidcAuth, ctxt._oidcAuthError = newOIDCAuth(ctxt.st, ctxt.clock)
+	})
+	if ctxt._oidcAuth == nil {
+		return nil, errors.Trace(ctxt._oidcAuthError)
+	}
+	return ctxt._oidcAuth, nil
+}
+
+var errOIDCAuthNotConfigured = errors.New("OIDC authentication is not configured")
+
+// newOIDCAuth returns an authenticator that can authenticate logins
+// for external users with ID tokens. This is just a helper function
+// for authCtxt.oidcAuth.
+func newOIDCAuth(st *state.State, clock clock.Clock) (*authentication.OIDCAuthenticator, error) {
+	controllerCfg, err := st.ControllerConfig()
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot get controller config")
+	}
+	issuerURL := controllerCfg.OIDCIssuerURL()
+	if issuerURL == "" {
+		return nil, errOIDCAuthNotConfigured
+	}
+	return &authentication.OIDCAuthenticator{
+		IssuerURL: issuerURL,
+		ClientID:  controllerCfg.OIDCClientID(),
+		Clock:     clock,
+	}, nil
+}
+
 // newBakeryService creates a new bakery.Service.
 func newBakeryService(
 	st *state.State,
@@ -0,0 +1,201 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package authentication
+
+import (
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"sync"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils/clock"
+	"gopkg.in/juju/names.v2"
+
+	apiauthentication "github.com/juju/juju/api/authentication"
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+)
+
+// OIDCAuthenticator performs authentication for external users using
+// ID tokens issued by an OpenID Connect provider. If no token is
+// provided, or the token is not valid, it will return a
+// *common.TokenRequiredError identifying the provider from which the
+// client should obtain one.
+type OIDCAuthenticator struct {
+	// IssuerURL holds the issuer URL of the provider.
+	IssuerURL string
+
+	// ClientID holds the client ID that tokens must be issued to.
+	ClientID string
+
+	// HTTPClient holds the client used to fetch the provider's
+	// signing keys. If it is nil, http.DefaultClient is used.
+	HTTPClient *http.Client
+
+	// Clock is used to check the expiry of tokens.
+	Clock clock.Clock
+
+	// mu guards the fields below it.
+	mu sync.Mutex
+
+	// keys holds the provider's signing keys, by key ID. They are
+	// fetched on demand, and refetched when a token is signed with
+	// a key that we have not seen, since providers rotate their keys.
+	keys map[string]*rsa.PublicKey
+}
+
+var _ EntityAuthenticator = (*OIDCAuthenticator)(nil)
+
+// Authenticate authenticates the user identified by the ID token in
+// the login request. The tag is ignored.
+func (a *OIDCAuthenticator) Authenticate(entityFinder EntityFinder, _ names.Tag, req params.LoginRequest) (state.Entity, error) {
+	if req.Token == "" {
+		return nil, a.newTokenRequiredError(errors.New("no ID token provided"))
+	}
+	claims, err := a.verify(req.Token)
+	if err != nil {
+		logger.Debugf("invalid ID token: %v", err)
+		return nil, a.newTokenRequiredError(err)
+	}
+	tag, err := claims.UserTag()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	entity, err := entityFinder.FindEntity(tag)
+	if errors.IsNotFound(err) {
+		return nil, errors.Trace(common.ErrBadCreds)
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return entity, nil
+}
+
+func (a *OIDCAuthenticator) newTokenRequiredError(cause error) error {
+	return &common.TokenRequiredError{
+		Cause:     cause,
+		IssuerURL: a.IssuerURL,
+		ClientID:  a.ClientID,
+	}
+}
+
+// verify checks the signature, issuer, audience and expiry
+// of the given ID token, returning its claims.
+func (a *OIDCAuthenticator) verify(token string) (*apiauthentication.IDTokenClaims, error) {
+	idToken, err := apiauthentication.ParseIDToken(token)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if idToken.Header.Algorithm != "RS256" {
+		return nil, errors.NotSupportedf("ID token signing algorithm %q", idToken.Header.Algorithm)
+	}
+	key, err := a.signingKey(idToken.Header.KeyID)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	digest := sha256.Sum256(idToken.SignedContent)
+	if err := rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], idToken.Signature); err != nil {
+		return nil, errors.New("ID token signature not valid")
+	}
+	claims := idToken.Claims
+	if claims.Issuer != a.IssuerURL {
+		return nil, errors.Errorf("ID token issued by %q, expected %q", claims.Issuer, a.IssuerURL)
+	}
+	if !claims.Audience.Contains(a.ClientID) {
+		return nil, errors.Errorf("ID token not issued to client %q", a.ClientID)
+	}
+	if claims.Expired(a.Clock.Now()) {
+		return nil, errors.New("ID token has expired")
+	}
+	return &claims, nil
+}
+
+// signingKey returns the provider's signing key with the given ID.
+func (a *OIDCAuthenticator) signingKey(keyID string) (*rsa.PublicKey, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if key, ok := a.keys[keyID]; ok {
+		return key, nil
+	}
+	keys, err := a.fetchKeys()
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot fetch OIDC signing keys")
+	}
+	a.keys = keys
+	if key, ok := a.keys[keyID]; ok {
+		return key, nil
+	}
+	return nil, errors.NotFoundf("OIDC signing key %q", keyID)
+}
+
+// jsonWebKey holds the fields of an RSA JSON Web Key used by Juju.
+type jsonWebKey struct {
+	KeyType string `json:"kty"`
+	KeyID   string `json:"kid"`
+	Use     string `json:"use,omitempty"`
+	N       string `json:"n"`
+	E       string `json:"e"`
+}
+
+func (a *OIDCAuthenticator) fetchKeys() (map[string]*rsa.PublicKey, error) {
+	client := a.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	provider, err := apiauthentication.DiscoverOIDCProvider(client, a.IssuerURL)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	resp, err := client.Get(provider.JWKSURI)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("cannot get %q: %s", provider.JWKSURI, resp.Status)
+	}
+	var keySet struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&keySet); err != nil {
+		return nil, errors.Trace(err)
+	}
+	keys := make(map[string]*rsa.PublicKey)
+	for _, jwk := range keySet.Keys {
+		if jwk.KeyType != "RSA" || (jwk.Use != "" && jwk.Use != "sig") {
+			continue
+		}
+		key, err := jwk.rsaPublicKey()
+		if err != nil {
+			logger.Warningf("ignoring OIDC signing key %q: %v", jwk.KeyID, err)
+			continue
+		}
+		keys[jwk.KeyID] = key
+	}
+	return keys, nil
+}
+
+func (k jsonWebKey) rsaPublicKey() (*rsa.PublicKey, error) {
+	n, err := base64.RawURLEncoding.DecodeString(k.N)
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot decode modulus")
+	}
+	e, err := base64.RawURLEncoding.DecodeString(k.E)
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot decode exponent")
+	}
+	exponent := new(big.Int).SetBytes(e)
+	if !exponent.IsInt64() || exponent.Int64() > 1<<31-1 {
+		return nil, errors.NotValidf("exponent")
+	}
+	return &rsa.PublicKey{
+		N: new(big.Int).SetBytes(n),
+		E: int(exponent.Int64()),
+	}, nil
+}
@@ -0,0 +1,180 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package authentication_test
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/authentication"
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+)
+
+type oidcAuthenticatorSuite struct {
+	testing.IsolationSuite
+
+	server *httptest.Server
+	key    *rsa.PrivateKey
+	clock  *testing.Clock
+
+	// jwksRequests counts the requests for the signing keys.
+	jwksRequests int
+
+	authenticator *authentication.OIDCAuthenticator
+}
+
+var _ = gc.Suite(&oidcAuthenticatorSuite{})
+
+func (s *oidcAuthenticatorSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	c.Assert(err, jc.ErrorIsNil)
+	s.key = key
+	s.jwksRequests = 0
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, req *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{
+			"issuer":         s.server.URL,
+			"token_endpoint": s.server.URL + "/token",
+			"jwks_uri":       s.server.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, req *http.Request) {
+		s.jwksRequests++
+		enc := base64.RawURLEncoding
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"keys": []map[string]string{{
+				"kty": "RSA",
+				"kid": "key-1",
+				"use": "sig",
+				"n":   enc.EncodeToString(s.key.N.Bytes()),
+				"e":   enc.EncodeToString(big.NewInt(int64(s.key.E)).Bytes()),
+			}},
+		})
+	})
+	s.server = httptest.NewServer(mux)
+	s.AddCleanup(func(*gc.C) { s.server.Close() })
+
+	s.clock = testing.NewClock(time.Unix(1500000000, 0))
+	s.authenticator = &authentication.OIDCAuthenticator{
+		IssuerURL: s.server.URL,
+		ClientID:  "juju",
+		Clock:     s.clock,
+	}
+}
+
+func (s *oidcAuthenticatorSuite) makeToken(c *gc.C, keyID string, claims map[string]interface{}) string {
+	header, err := json.Marshal(map[string]string{"alg": "RS256", "kid": keyID})
+	c.Assert(err, jc.ErrorIsNil)
+	body, err := json.Marshal(claims)
+	c.Assert(err, jc.ErrorIsNil)
+	enc := base64.RawURLEncoding
+	content := enc.EncodeToString(header) + "." + enc.EncodeToString(body)
+	digest := sha256.Sum256([]byte(content))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, s.key, crypto.SHA256, digest[:])
+	c.Assert(err, jc.ErrorIsNil)
+	return content + "." + enc.EncodeToString(signature)
+}
+
+func (s *oidcAuthenticatorSuite) claims(username string) map[string]interface{} {
+	return map[string]interface{}{
+		"iss":                s.server.URL,
+		"sub":                "1234",
+		"aud":                "juju",
+		"exp":                s.clock.Now().Add(time.Hour).Unix(),
+		"preferred_username": username,
+	}
+}
+
+func (s *oidcAuthenticatorSuite) TestAuthenticate(c *gc.C) {
+	finder := simpleEntityFinder{"user-bob@external": true}
+	token := s.makeToken(c, "key-1", s.claims("bob"))
+	entity, err := s.authenticator.Authenticate(finder, nil, params.LoginRequest{Token: token})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(entity.Tag().String(), gc.Equals, "user-bob@external")
+
+	// The signing keys are cached.
+	_, err = s.authenticator.Authenticate(finder, nil, params.LoginRequest{Token: token})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.jwksRequests, gc.Equals, 1)
+}
+
+func (s *oidcAuthenticatorSuite) TestAuthenticateUserNotFound(c *gc.C) {
+	token := s.makeToken(c, "key-1", s.claims("bob"))
+	_, err := s.authenticator.Authenticate(simpleEntityFinder{}, nil, params.LoginRequest{Token: token})
+	c.Assert(errors.Cause(err), gc.Equals, common.ErrBadCreds)
+}
+
+func (s *oidcAuthenticatorSuite) TestAuthenticateNoToken(c *gc.C) {
+	_, err := s.authenticator.Authenticate(simpleEntityFinder{}, nil, params.LoginRequest{})
+	s.assertTokenRequired(c, err, "no ID token provided")
+}
+
+func (s *oidcAuthenticatorSuite) TestAuthenticateInvalidTokens(c *gc.C) {
+	wrongIssuer := s.claims("bob")
+	wrongIssuer["iss"] = "https://elsewhere.example.com"
+	wrongAudience := s.claims("bob")
+	wrongAudience["aud"] = []string{"other"}
+	expired := s.claims("bob")
+	expired["exp"] = s.clock.Now().Unix()
+
+	tests := []struct {
+		about       string
+		token       string
+		expectError string
+	}{{
+		about:       "malformed token",
+		token:       "not-a-token",
+		expectError: "ID token not valid",
+	}, {
+		about:       "unknown signing key",
+		token:       s.makeToken(c, "key-2", s.claims("bob")),
+		expectError: `OIDC signing key "key-2" not found`,
+	}, {
+		about:       "bad signature",
+		token:       s.makeToken(c, "key-1", s.claims("bob")) + "AA",
+		expectError: "ID token signature not valid",
+	}, {
+		about:       "wrong issuer",
+		token:       s.makeToken(c, "key-1", wrongIssuer),
+		expectError: `ID token issued by "https://elsewhere.example.com", expected ".*"`,
+	}, {
+		about:       "wrong audience",
+		token:       s.makeToken(c, "key-1", wrongAudience),
+		expectError: `ID token not issued to client "juju"`,
+	}, {
+		about:       "expired",
+		token:       s.makeToken(c, "key-1", expired),
+		expectError: "ID token has expired",
+	}}
+	finder := simpleEntityFinder{"user-bob@external": true}
+	for i, test := range tests {
+		c.Logf("test %d: %s", i, test.about)
+		_, err := s.authenticator.Authenticate(finder, nil, params.LoginRequest{Token: test.token})
+		s.assertTokenRequired(c, err, test.expectError)
+	}
+}
+
+func (s *oidcAuthenticatorSuite) assertTokenRequired(c *gc.C, err error, expectError string) {
+	c.Assert(err, gc.ErrorMatches, expectError)
+	tokenErr, ok := errors.Cause(err).(*common.TokenRequiredError)
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(tokenErr.IssuerURL, gc.Equals, s.server.URL)
+	c.Assert(tokenErr.ClientID, gc.Equals, "juju")
+}
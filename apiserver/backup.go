@@ -10,6 +10,7 @@ import (
 	"net/http"
 
 	"github.com/juju/errors"
+	"gopkg.in/juju/names.v2"
 
 	"github.com/juju/juju/apiserver/common"
 	apiserverbackups "github.com/juju/juju/apiserver/facades/client/backups"
@@ -28,6 +29,8 @@ var newBackups = func(st *state.State, m *state.Model) (backups.Backups, io.Clos
 	return backups.NewBackups(stor), stor
 }
 
+var prepareCreateBackup = apiserverbackups.PrepareCreate
+
 // backupHandler handles backup requests.
 type backupHandler struct {
 	ctxt httpContext
@@ -36,7 +39,7 @@ type backupHandler struct {
 func (h *backupHandler) ServeHTTP(resp http.ResponseWriter, req *http.Request) {
 	// Validate before authenticate because the authentication is dependent
 	// on the state connection that is determined during the validation.
-	st, releaser, err := h.ctxt.stateForRequestAuthenticatedUser(req)
+	st, releaser, user, err := h.ctxt.stateAndEntityForRequestAuthenticatedUser(req)
 	if err != nil {
 		h.sendError(resp, err)
 		return
@@ -69,6 +72,9 @@ func (h *backupHandler) ServeHTTP(resp http.ResponseWriter, req *http.Request) {
 			return
 		}
 		logger.Infof("backups upload request successful for %q", id)
+	case "POST":
+		logger.Infof("handling backups stream request")
+		h.stream(st, m, user, backups, resp, req)
 	default:
 		h.sendError(resp, errors.MethodNotAllowedf("unsupported method: %q", req.Method))
 	}
@@ -118,6 +124,82 @@ func (h *backupHandler) upload(backups backups.Backups, resp http.ResponseWriter
 	return id, nil
 }
 
+// stream creates a new backup, sending the archive in the response as
+// it is built, so that nothing is staged or stored on the controller.
+// The archive's checksum is only known once it has been sent, so it
+// is sent in the Digest trailer; a response without the trailer holds
+// an incomplete archive.
+func (h *backupHandler) stream(
+	st *state.State,
+	m *state.Model,
+	user state.Entity,
+	backups backups.Backups,
+	resp http.ResponseWriter,
+	req *http.Request,
+) {
+	paths, meta, dbInfo, err := h.prepareStream(st, m, user, req)
+	if err != nil {
+		h.sendError(resp, err)
+		return
+	}
+
+	resp.Header().Set("Content-Type", params.ContentTypeRaw)
+	resp.Header().Set("Trailer", "Digest")
+	resp.WriteHeader(http.StatusOK)
+	if err := backups.Stream(meta, paths, dbInfo, resp); err != nil {
+		// The response is already under way, so the error can't be
+		// sent; the client notices the missing digest instead.
+		logger.Errorf("backups stream request failed: %v", err)
+		return
+	}
+	resp.Header().Set("Digest", params.EncodeChecksum(meta.Checksum()))
+	logger.Infof("backups stream request successful")
+}
+
+func (h *backupHandler) prepareStream(
+	st *state.State,
+	m *state.Model,
+	user state.Entity,
+	req *http.Request,
+) (*backups.Paths, *backups.Metadata, *backups.DBInfo, error) {
+	admin, err := st.IsControllerAdmin(user.Tag().(names.UserTag))
+	if err != nil {
+		return nil, nil, nil, errors.Trace(err)
+	}
+	if !admin {
+		return nil, nil, nil, common.ErrPerm
+	}
+	if !st.IsController() {
+		return nil, nil, nil, errors.BadRequestf("backups are only supported from the controller model")
+	}
+
+	body, err := h.read(req, params.ContentTypeJSON)
+	if err != nil {
+		return nil, nil, nil, errors.Trace(err)
+	}
+	var args params.BackupsCreateArgs
+	if err := json.Unmarshal(body, &args); err != nil {
+		return nil, nil, nil, errors.Annotate(err, "while de-serializing args")
+	}
+
+	srv := h.ctxt.srv
+	paths := &backups.Paths{
+		DataDir: srv.dataDir,
+		LogsDir: srv.logDir,
+	}
+	backend := apiserverbackups.NewBackend(st, m)
+	meta, dbInfo, err := prepareCreateBackup(backend, paths, srv.tag.Id(), args)
+	if err != nil {
+		return nil, nil, nil, errors.Trace(err)
+	}
+	// Check this before the response is under way, so the client
+	// gets the error instead of a truncated archive.
+	if err := backups.CheckStreamSupported(dbInfo); err != nil {
+		return nil, nil, nil, errors.Trace(err)
+	}
+	return paths, meta, dbInfo, nil
+}
+
 func validateBackupMetadataResult(metaResult params.BackupsMetadataResult) error {
 	if metaResult.ID != "" {
 		return errors.New("got unexpected metadata ID")
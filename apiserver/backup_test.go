@@ -23,6 +23,8 @@ import (
 	"github.com/juju/juju/apiserver"
 	apiserverbackups "github.com/juju/juju/apiserver/facades/client/backups"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/mongo"
+	"github.com/juju/juju/permission"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/state/backups"
 	backupstesting "github.com/juju/juju/state/backups/testing"
@@ -84,7 +86,7 @@ func (s *backupsSuite) checkInvalidMethod(c *gc.C, method, url string) {
 
 func (s *backupsSuite) TestInvalidHTTPMethods(c *gc.C) {
 	url := s.backupURL(c)
-	for _, method := range []string{"POST", "DELETE", "OPTIONS"} {
+	for _, method := range []string{"DELETE", "OPTIONS"} {
 		c.Log("testing HTTP method: " + method)
 		s.checkInvalidMethod(c, method, url)
 	}
@@ -111,8 +113,8 @@ func (s *backupsSuite) TestAuthRequiresClientNotMachine(c *gc.C) {
 	s.assertErrorResponse(c, resp, http.StatusInternalServerError, "tag kind machine not valid")
 
 	// Now try a user login.
-	resp = s.authRequest(c, httpRequestParams{method: "POST", url: s.backupURL(c)})
-	s.assertErrorResponse(c, resp, http.StatusMethodNotAllowed, `unsupported method: "POST"`)
+	resp = s.authRequest(c, httpRequestParams{method: "DELETE", url: s.backupURL(c)})
+	s.assertErrorResponse(c, resp, http.StatusMethodNotAllowed, `unsupported method: "DELETE"`)
 }
 
 type backupsWithMacaroonsSuite struct {
@@ -258,6 +260,100 @@ func (s *backupsDownloadSuite) TestErrorWhenGetFails(c *gc.C) {
 	s.assertErrorResponse(c, resp, http.StatusInternalServerError, "failed!")
 }
 
+type backupsStreamSuite struct {
+	backupsCommonSuite
+	mongoVersion mongo.Version
+}
+
+var _ = gc.Suite(&backupsStreamSuite{})
+
+func (s *backupsStreamSuite) SetUpTest(c *gc.C) {
+	s.backupsCommonSuite.SetUpTest(c)
+	s.PatchValue(apiserver.PrepareCreateBackup, func(
+		backend apiserverbackups.Backend,
+		paths *backups.Paths,
+		machineID string,
+		args params.BackupsCreateArgs,
+	) (*backups.Metadata, *backups.DBInfo, error) {
+		meta := backupstesting.NewMetadataStarted()
+		meta.Notes = args.Notes
+		return meta, &backups.DBInfo{MongoVersion: s.mongoVersion}, nil
+	})
+	s.mongoVersion = mongo.Version{Major: 3, Minor: 6, StorageEngine: mongo.WiredTiger}
+}
+
+func (s *backupsStreamSuite) setControllerAdmin(c *gc.C) {
+	_, err := s.State.SetUserAccess(s.userTag, s.State.ControllerTag(), permission.SuperuserAccess)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+// sendValidPost sends a valid POST request to the backups endpoint
+// and returns the response and the expected contents of the archive
+// if the request succeeds.
+func (s *backupsStreamSuite) sendValidPost(c *gc.C) (resp *http.Response, archiveBytes []byte) {
+	meta := backupstesting.NewMetadata()
+	archive, err := backupstesting.NewArchiveBasic(meta)
+	c.Assert(err, jc.ErrorIsNil)
+	archiveBytes = archive.Bytes()
+	s.fake.Meta = meta
+	s.fake.Archive = ioutil.NopCloser(archive)
+
+	return s.authRequest(c, httpRequestParams{
+		method:      "POST",
+		url:         s.backupURL(c),
+		contentType: params.ContentTypeJSON,
+		jsonBody:    params.BackupsCreateArgs{Notes: "spam"},
+	}), archiveBytes
+}
+
+func (s *backupsStreamSuite) TestStream(c *gc.C) {
+	s.setControllerAdmin(c)
+	resp, archiveBytes := s.sendValidPost(c)
+	defer resp.Body.Close()
+
+	c.Check(resp.StatusCode, gc.Equals, http.StatusOK)
+	c.Check(resp.Header.Get("Content-Type"), gc.Equals, params.ContentTypeRaw)
+	body, err := ioutil.ReadAll(resp.Body)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(body, jc.DeepEquals, archiveBytes)
+
+	// The digest is only available once the body has been read.
+	expectedChecksum := base64.StdEncoding.EncodeToString([]byte(s.fake.Meta.Checksum()))
+	c.Check(resp.Trailer.Get("Digest"), gc.Equals, string(params.DigestSHA256)+"="+expectedChecksum)
+
+	c.Check(s.fake.Calls, gc.DeepEquals, []string{"Stream"})
+	c.Check(s.fake.MetaArg.Notes, gc.Equals, s.fake.Meta.Notes)
+}
+
+func (s *backupsStreamSuite) TestStreamFailsWithoutDigest(c *gc.C) {
+	s.setControllerAdmin(c)
+	s.fake.Error = errors.New("failed!")
+	resp, _ := s.sendValidPost(c)
+	defer resp.Body.Close()
+
+	_, err := ioutil.ReadAll(resp.Body)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(resp.Trailer.Get("Digest"), gc.Equals, "")
+}
+
+func (s *backupsStreamSuite) TestStreamUnsupportedMongo(c *gc.C) {
+	s.setControllerAdmin(c)
+	s.mongoVersion = mongo.Mongo32wt
+	resp, _ := s.sendValidPost(c)
+	defer resp.Body.Close()
+
+	s.assertErrorResponse(c, resp, http.StatusInternalServerError, "streaming backups of mongo 3.2/wiredTiger not supported")
+	c.Check(s.fake.Calls, gc.HasLen, 0)
+}
+
+func (s *backupsStreamSuite) TestStreamRequiresControllerAdmin(c *gc.C) {
+	resp, _ := s.sendValidPost(c)
+	defer resp.Body.Close()
+
+	s.assertErrorResponse(c, resp, http.StatusUnauthorized, "permission denied")
+	c.Check(s.fake.Calls, gc.HasLen, 0)
+}
+
 type backupsUploadSuite struct {
 	backupsCommonSuite
 	meta *backups.Metadata
@@ -77,6 +77,19 @@ func IsDischargeRequiredError(err error) bool {
 	return ok
 }
 
+// TokenRequiredError is the error returned when an ID token from
+// an OpenID Connect provider is required to complete authentication.
+type TokenRequiredError struct {
+	Cause     error
+	IssuerURL string
+	ClientID  string
+}
+
+// Error implements the error interface.
+func (e *TokenRequiredError) Error() string {
+	return e.Cause.Error()
+}
+
 // IsUpgradeInProgress returns true if this error is caused
 // by an upgrade in progress.
 func IsUpgradeInProgressError(err error) bool {
@@ -8,9 +8,11 @@ import (
 
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/instance"
+	"github.com/juju/juju/network"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/state/multiwatcher"
 	"github.com/juju/juju/status"
+	"github.com/juju/juju/tools"
 )
 
 // StateJobs translates a slice of multiwatcher jobs to their equivalents in state.
@@ -67,6 +69,8 @@ type Machine interface {
 	Destroy() error
 	AgentPresence() (bool, error)
 	IsManager() bool
+	AgentTools() (*tools.Tools, error)
+	Addresses() []network.Address
 }
 
 func DestroyMachines(st origStateInterface, force bool, ids ...string) error {
@@ -96,12 +100,15 @@ func destroyMachines(st stateInterface, force bool, ids ...string) error {
 }
 
 // ModelMachineInfo returns information about machine hardware for
-// alive top level machines (not containers).
+// alive top level machines (not containers). Controller machines
+// also report their agent version, mongo replicaset membership and
+// API addresses.
 func ModelMachineInfo(st ModelManagerBackend) (machineInfo []params.ModelMachineInfo, _ error) {
 	machines, err := st.AllMachines()
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
+	var controllerInfo *controllerMachineInfo
 	for _, m := range machines {
 		if m.Life() != state.Alive {
 			continue
@@ -119,6 +126,12 @@ func ModelMachineInfo(st ModelManagerBackend) (machineInfo []params.ModelMachine
 			WantsVote: m.WantsVote(),
 			Status:    status,
 		}
+		if mInfo.WantsVote {
+			if controllerInfo == nil {
+				controllerInfo = newControllerMachineInfo(st)
+			}
+			controllerInfo.fill(m, &mInfo)
+		}
 		instId, err := m.InstanceId()
 		switch {
 		case err == nil:
@@ -153,3 +166,49 @@ func ModelMachineInfo(st ModelManagerBackend) (machineInfo []params.ModelMachine
 	}
 	return machineInfo, nil
 }
+
+// controllerMachineInfo holds the controller wide information
+// needed to describe controller machines.
+type controllerMachineInfo struct {
+	apiPort    int
+	replicaSet map[string]state.ReplicaSetMemberStatus
+}
+
+// newControllerMachineInfo gathers the information needed to describe
+// controller machines. Failing to do so is not fatal: the affected
+// fields are left empty and a warning logged instead.
+func newControllerMachineInfo(st ModelManagerBackend) *controllerMachineInfo {
+	info := &controllerMachineInfo{}
+	if cfg, err := st.ControllerConfig(); err != nil {
+		logger.Warningf("cannot get controller config: %v", err)
+	} else {
+		info.apiPort = cfg.APIPort()
+	}
+	replicaSet, err := st.ReplicaSetStatus()
+	if err != nil {
+		logger.Warningf("cannot get replicaset status: %v", err)
+	}
+	info.replicaSet = replicaSet
+	return info
+}
+
+// fill records the agent version, replicaset membership and API
+// addresses of the controller machine m in mInfo.
+func (info *controllerMachineInfo) fill(m Machine, mInfo *params.ModelMachineInfo) {
+	agentTools, err := m.AgentTools()
+	if err == nil {
+		mInfo.AgentVersion = agentTools.Version.Number.String()
+	} else if !errors.IsNotFound(err) {
+		logger.Warningf("cannot get agent version of machine %s: %v", m.Id(), err)
+	}
+	if member, ok := info.replicaSet[m.Id()]; ok {
+		mInfo.ReplicaSetState = member.Role
+		mInfo.ReplicaSetHealthy = member.Healthy
+	}
+	if info.apiPort != 0 {
+		hostPorts := network.FilterUnusableHostPorts(network.AddressesWithPort(m.Addresses(), info.apiPort))
+		if len(hostPorts) > 0 {
+			mInfo.APIAddresses = network.HostPortsToStrings(hostPorts)
+		}
+	}
+}
@@ -11,10 +11,14 @@ import (
 
 	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/controller"
 	"github.com/juju/juju/instance"
+	"github.com/juju/juju/network"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/state/multiwatcher"
 	"github.com/juju/juju/status"
+	"github.com/juju/juju/tools"
+	"github.com/juju/juju/version"
 )
 
 type machineSuite struct{}
@@ -123,6 +127,7 @@ func (s *machineSuite) TestMachineInstanceInfo(c *gc.C) {
 		machines: map[string]*mockMachine{
 			"1": {id: "1", instId: instance.Id("123"), status: status.Down, hasVote: true, wantsVote: true},
 		},
+		controllerConfig: controller.Config{controller.APIPort: 17070},
 	}
 	info, err := common.ModelMachineInfo(&st)
 	c.Assert(err, jc.ErrorIsNil)
@@ -137,9 +142,89 @@ func (s *machineSuite) TestMachineInstanceInfo(c *gc.C) {
 	})
 }
 
+func (s *machineSuite) TestControllerMachineInfo(c *gc.C) {
+	st := mockState{
+		machines: map[string]*mockMachine{
+			"0": {
+				id: "0", instId: instance.Id("inst-0"), status: status.Started, hasVote: true, wantsVote: true,
+				agentTools: &tools.Tools{Version: version.MustParseBinary("2.4.1-bionic-amd64")},
+				addresses:  network.NewAddresses("10.0.0.1", "127.0.0.1"),
+			},
+			"1": {
+				id: "1", instId: instance.Id("inst-1"), status: status.Down, wantsVote: true,
+				agentTools: &tools.Tools{Version: version.MustParseBinary("2.4.0-bionic-amd64")},
+				addresses:  network.NewAddresses("10.0.0.2"),
+			},
+			"2": {id: "2", instId: instance.Id("inst-2"), status: status.Started},
+		},
+		controllerConfig: controller.Config{controller.APIPort: 17070},
+		replicaSet: map[string]state.ReplicaSetMemberStatus{
+			"0": {Role: "PRIMARY", Healthy: true},
+			"1": {Role: "(not reachable/healthy)"},
+		},
+	}
+	info, err := common.ModelMachineInfo(&st)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(info, jc.DeepEquals, []params.ModelMachineInfo{
+		{
+			Id:                "0",
+			InstanceId:        "inst-0",
+			Status:            "started",
+			HasVote:           true,
+			WantsVote:         true,
+			AgentVersion:      "2.4.1",
+			ReplicaSetState:   "PRIMARY",
+			ReplicaSetHealthy: true,
+			APIAddresses:      []string{"10.0.0.1:17070"},
+		}, {
+			Id:              "1",
+			InstanceId:      "inst-1",
+			Status:          "down",
+			WantsVote:       true,
+			AgentVersion:    "2.4.0",
+			ReplicaSetState: "(not reachable/healthy)",
+			APIAddresses:    []string{"10.0.0.2:17070"},
+		}, {
+			Id:         "2",
+			InstanceId: "inst-2",
+			Status:     "started",
+		},
+	})
+}
+
+func (s *machineSuite) TestControllerMachineInfoReplicaSetError(c *gc.C) {
+	st := mockState{
+		machines: map[string]*mockMachine{
+			"0": {id: "0", wantsVote: true, addresses: network.NewAddresses("10.0.0.1")},
+		},
+		controllerConfig: controller.Config{controller.APIPort: 17070},
+		replicaSetErr:    errors.New("not running with --replSet"),
+	}
+	info, err := common.ModelMachineInfo(&st)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(info, jc.DeepEquals, []params.ModelMachineInfo{
+		{
+			Id:           "0",
+			WantsVote:    true,
+			APIAddresses: []string{"10.0.0.1:17070"},
+		},
+	})
+}
+
 type mockState struct {
 	common.ModelManagerBackend
-	machines map[string]*mockMachine
+	machines         map[string]*mockMachine
+	controllerConfig controller.Config
+	replicaSet       map[string]state.ReplicaSetMemberStatus
+	replicaSetErr    error
+}
+
+func (st *mockState) ControllerConfig() (controller.Config, error) {
+	return st.controllerConfig, nil
+}
+
+func (st *mockState) ReplicaSetStatus() (map[string]state.ReplicaSetMemberStatus, error) {
+	return st.replicaSet, st.replicaSetErr
 }
 
 func (st *mockState) Machine(id string) (common.Machine, error) {
@@ -178,6 +263,8 @@ type mockMachine struct {
 	destroyCalled      bool
 	agentDead          bool
 	presenceErr        error
+	agentTools         *tools.Tools
+	addresses          []network.Address
 }
 
 func (m *mockMachine) Id() string {
@@ -210,6 +297,17 @@ func (m *mockMachine) HardwareCharacteristics() (*instance.HardwareCharacteristi
 	return m.hw, nil
 }
 
+func (m *mockMachine) AgentTools() (*tools.Tools, error) {
+	if m.agentTools == nil {
+		return nil, errors.NotFoundf("agent binaries for machine %v", m.id)
+	}
+	return m.agentTools, nil
+}
+
+func (m *mockMachine) Addresses() []network.Address {
+	return m.addresses
+}
+
 func (m *mockMachine) AgentPresence() (bool, error) {
 	return !m.agentDead, m.presenceErr
 }
@@ -64,6 +64,7 @@ type ModelManagerBackend interface {
 	ReloadSpaces(environ environs.Environ) error
 	LatestMigration() (state.ModelMigration, error)
 	DumpAll() (map[string]interface{}, error)
+	ReplicaSetStatus() (map[string]state.ReplicaSetMemberStatus, error)
 	Close() error
 
 	// Methods required by the metricsender package.
@@ -130,6 +130,38 @@ func (r *PermissionSuite) TestHasPermission(c *gc.C) {
 			access:           permission.AddModelAccess,
 			expected:         false,
 		},
+		{
+			title:            "user has equal cloud permission than required",
+			userGetterAccess: permission.AddModelAccess,
+			user:             names.NewUserTag("validuser"),
+			target:           names.NewCloudTag("aws"),
+			access:           permission.AddModelAccess,
+			expected:         true,
+		},
+		{
+			title:            "user has greater cloud permission than required",
+			userGetterAccess: permission.AdminAccess,
+			user:             names.NewUserTag("validuser"),
+			target:           names.NewCloudTag("aws"),
+			access:           permission.AddModelAccess,
+			expected:         true,
+		},
+		{
+			title:            "user has lesser cloud permission than required",
+			userGetterAccess: permission.AddModelAccess,
+			user:             names.NewUserTag("validuser"),
+			target:           names.NewCloudTag("aws"),
+			access:           permission.AdminAccess,
+			expected:         false,
+		},
+		{
+			title:            "user requests controller permission on cloud",
+			userGetterAccess: permission.AdminAccess,
+			user:             names.NewUserTag("validuser"),
+			target:           names.NewCloudTag("aws"),
+			access:           permission.SuperuserAccess,
+			expected:         false,
+		},
 	}
 	for i, t := range testCases {
 		userGetter := &fakeUserAccess{
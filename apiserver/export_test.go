@@ -25,6 +25,7 @@ var (
 	NewPingTimeout        = newPingTimeout
 	MaxClientPingInterval = maxClientPingInterval
 	NewBackups            = &newBackups
+	PrepareCreateBackup   = &prepareCreateBackup
 	BZMimeType            = bzMimeType
 	JSMimeType            = jsMimeType
 	GUIURLPathPrefix      = guiURLPathPrefix
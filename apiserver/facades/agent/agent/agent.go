@@ -14,6 +14,7 @@ import (
 	"github.com/juju/juju/apiserver/common/cloudspec"
 	"github.com/juju/juju/apiserver/facade"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/core/application"
 	"github.com/juju/juju/mongo"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/state/multiwatcher"
@@ -55,7 +56,7 @@ func NewAgentAPIV2(st *state.State, resources facade.Resources, auth facade.Auth
 		ControllerConfigAPI: common.NewStateControllerConfig(st),
 		CloudSpecAPI: cloudspec.NewCloudSpec(
 			cloudspec.MakeCloudSpecGetterForModel(st),
-			common.AuthFuncForTag(model.ModelTag()),
+			getCanAccessCloudSpec(st, model.ModelTag(), auth),
 		),
 		st:        st,
 		auth:      auth,
@@ -63,6 +64,35 @@ func NewAgentAPIV2(st *state.State, resources facade.Resources, auth facade.Auth
 	}, nil
 }
 
+// getCanAccessCloudSpec returns a function which allows agents to read
+// the cloud spec, and so the cloud credentials, of the specified model.
+// Unit agents are only allowed to if their application is trusted.
+func getCanAccessCloudSpec(st *state.State, modelTag names.ModelTag, auth facade.Authorizer) common.GetAuthFunc {
+	return func() (common.AuthFunc, error) {
+		if !auth.AuthUnitAgent() {
+			return common.AuthFuncForTag(modelTag)()
+		}
+		unit, err := st.Unit(auth.GetAuthTag().Id())
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		app, err := unit.Application()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		appConfig, err := app.ApplicationConfig()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if !appConfig.GetBool(application.TrustConfigOptionName, false) {
+			return func(names.Tag) bool {
+				return false
+			}, nil
+		}
+		return common.AuthFuncForTag(modelTag)()
+	}
+}
+
 func (api *AgentAPIV2) GetEntities(args params.Entities) params.AgentGetEntitiesResults {
 	results := params.AgentGetEntitiesResults{
 		Entities: make([]params.AgentGetEntitiesResult, len(args.Entities)),
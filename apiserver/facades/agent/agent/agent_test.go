@@ -6,8 +6,10 @@ package agent_test
 import (
 	stdtesting "testing"
 
+	"github.com/juju/schema"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/environschema.v1"
 	"gopkg.in/juju/names.v2"
 
 	"github.com/juju/juju/apiserver/common"
@@ -15,6 +17,7 @@ import (
 	"github.com/juju/juju/apiserver/params"
 	apiservertesting "github.com/juju/juju/apiserver/testing"
 	"github.com/juju/juju/cloud"
+	coreapplication "github.com/juju/juju/core/application"
 	"github.com/juju/juju/instance"
 	jujutesting "github.com/juju/juju/juju/testing"
 	"github.com/juju/juju/state"
@@ -276,3 +279,48 @@ func (s *agentSuite) TestWatchAuthError(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, "permission denied")
 	c.Assert(s.resources.Count(), gc.Equals, 0)
 }
+
+func (s *agentSuite) TestCloudSpecMachineAgent(c *gc.C) {
+	api, err := agent.NewAgentAPIV2(s.State, s.resources, s.authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+	result, err := api.CloudSpec(params.Entities{Entities: []params.Entity{{Tag: s.Model.ModelTag().String()}}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results, gc.HasLen, 1)
+	c.Assert(result.Results[0].Error, gc.IsNil)
+	c.Assert(result.Results[0].Result, gc.NotNil)
+}
+
+func (s *agentSuite) TestCloudSpecUntrustedUnitAgent(c *gc.C) {
+	unit := s.Factory.MakeUnit(c, nil)
+	auth := s.authorizer
+	auth.Tag = unit.Tag()
+	api, err := agent.NewAgentAPIV2(s.State, s.resources, auth)
+	c.Assert(err, jc.ErrorIsNil)
+	result, err := api.CloudSpec(params.Entities{Entities: []params.Entity{{Tag: s.Model.ModelTag().String()}}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results, gc.HasLen, 1)
+	c.Assert(result.Results[0].Error, gc.ErrorMatches, "permission denied")
+	c.Assert(result.Results[0].Result, gc.IsNil)
+}
+
+func (s *agentSuite) TestCloudSpecTrustedUnitAgent(c *gc.C) {
+	unit := s.Factory.MakeUnit(c, nil)
+	app, err := unit.Application()
+	c.Assert(err, jc.ErrorIsNil)
+	fields, defaults, err := coreapplication.AddTrustSchemaAndDefaults(environschema.Fields{}, schema.Defaults{})
+	c.Assert(err, jc.ErrorIsNil)
+	err = app.UpdateApplicationConfig(coreapplication.ConfigAttributes{
+		coreapplication.TrustConfigOptionName: true,
+	}, nil, fields, defaults)
+	c.Assert(err, jc.ErrorIsNil)
+
+	auth := s.authorizer
+	auth.Tag = unit.Tag()
+	api, err := agent.NewAgentAPIV2(s.State, s.resources, auth)
+	c.Assert(err, jc.ErrorIsNil)
+	result, err := api.CloudSpec(params.Entities{Entities: []params.Entity{{Tag: s.Model.ModelTag().String()}}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results, gc.HasLen, 1)
+	c.Assert(result.Results[0].Error, gc.IsNil)
+	c.Assert(result.Results[0].Result, gc.NotNil)
+}
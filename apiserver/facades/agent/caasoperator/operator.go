@@ -278,7 +278,7 @@ func (f *Facade) SetContainerSpec(args params.SetContainerSpecParams) (params.Er
 			results.Results[i].Error = common.ServerError(common.ErrPerm)
 			continue
 		}
-		if _, err := caas.ParseContainerSpec(arg.Value); err != nil {
+		if _, err := caas.ParsePodSpec(arg.Value); err != nil {
 			results.Results[i].Error = common.ServerError(errors.New("invalid container spec"))
 			continue
 		}
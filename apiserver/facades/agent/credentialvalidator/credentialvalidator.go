@@ -0,0 +1,121 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package credentialvalidator implements the API facade used by the
+// credentialvalidator worker.
+package credentialvalidator
+
+import (
+	"github.com/juju/errors"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/facade"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/watcher"
+)
+
+// ModelCredential describes the cloud credential used by a model.
+type ModelCredential struct {
+	// Model is the tag of the model.
+	Model names.ModelTag
+
+	// Exists is true if the model uses a cloud credential.
+	Exists bool
+
+	// Credential is the tag of the credential, if one is used.
+	Credential names.CloudCredentialTag
+
+	// Valid is true if the credential exists and has not been
+	// revoked.
+	Valid bool
+}
+
+// Backend defines the State API used by the credentialvalidator facade.
+type Backend interface {
+	state.EntityFinder
+
+	// ModelCredential returns the cloud credential used by the model.
+	ModelCredential() (ModelCredential, error)
+
+	// WatchCredential returns a watcher that notifies of changes to
+	// the specified cloud credential.
+	WatchCredential(names.CloudCredentialTag) state.NotifyWatcher
+}
+
+// API implements the API required by the credentialvalidator worker.
+type API struct {
+	*common.StatusGetter
+	*common.StatusSetter
+
+	backend   Backend
+	resources facade.Resources
+}
+
+// New returns a new API facade for the credentialvalidator worker.
+// The worker runs on behalf of a model, so only controllers may use
+// it.
+func New(backend Backend, resources facade.Resources, authorizer facade.Authorizer) (*API, error) {
+	if !authorizer.AuthController() {
+		return nil, common.ErrPerm
+	}
+	getCanAccess := func() (common.AuthFunc, error) {
+		mc, err := backend.ModelCredential()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return func(tag names.Tag) bool {
+			return tag == mc.Model
+		}, nil
+	}
+	return &API{
+		StatusGetter: common.NewStatusGetter(backend, getCanAccess),
+		StatusSetter: common.NewStatusSetter(backend, getCanAccess),
+		backend:      backend,
+		resources:    resources,
+	}, nil
+}
+
+// ModelCredential returns the cloud credential used by the model,
+// and whether it is valid.
+func (api *API) ModelCredential() (params.ModelCredential, error) {
+	mc, err := api.backend.ModelCredential()
+	if err != nil {
+		return params.ModelCredential{}, errors.Trace(err)
+	}
+	result := params.ModelCredential{
+		Model:  mc.Model.String(),
+		Exists: mc.Exists,
+		Valid:  mc.Valid,
+	}
+	if mc.Exists {
+		result.CloudCredential = mc.Credential.String()
+	}
+	return result, nil
+}
+
+// WatchCredential returns a NotifyWatcher for observing changes to
+// the specified credential, which must be the one used by the model.
+func (api *API) WatchCredential(arg params.Entity) (params.NotifyWatchResult, error) {
+	tag, err := names.ParseCloudCredentialTag(arg.Tag)
+	if err != nil {
+		return params.NotifyWatchResult{}, errors.Trace(err)
+	}
+	mc, err := api.backend.ModelCredential()
+	if err != nil {
+		return params.NotifyWatchResult{}, errors.Trace(err)
+	}
+	if !mc.Exists || tag != mc.Credential {
+		return params.NotifyWatchResult{}, common.ErrPerm
+	}
+
+	var result params.NotifyWatchResult
+	watch := api.backend.WatchCredential(tag)
+	if _, ok := <-watch.Changes(); ok {
+		result.NotifyWatcherId = api.resources.Register(watch)
+	} else {
+		result.Error = common.ServerError(watcher.EnsureErr(watch))
+	}
+	return result, nil
+}
@@ -0,0 +1,115 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package credentialvalidator_test
+
+import (
+	jujutesting "github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/facades/agent/credentialvalidator"
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/testing"
+)
+
+var credentialTag = names.NewCloudCredentialTag("dummy/fred/default")
+
+type credentialValidatorSuite struct {
+	testing.BaseSuite
+	backend    *mockBackend
+	resources  *common.Resources
+	authorizer *apiservertesting.FakeAuthorizer
+	facade     *credentialvalidator.API
+}
+
+var _ = gc.Suite(&credentialValidatorSuite{})
+
+func (s *credentialValidatorSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.backend = &mockBackend{
+		credential: credentialvalidator.ModelCredential{
+			Model:      testing.ModelTag,
+			Exists:     true,
+			Credential: credentialTag,
+			Valid:      true,
+		},
+	}
+	s.resources = common.NewResources()
+	s.AddCleanup(func(*gc.C) { s.resources.StopAll() })
+	s.authorizer = &apiservertesting.FakeAuthorizer{
+		Tag:        names.NewMachineTag("0"),
+		Controller: true,
+	}
+	facade, err := credentialvalidator.New(s.backend, s.resources, s.authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+	s.facade = facade
+}
+
+func (s *credentialValidatorSuite) TestNewRequiresController(c *gc.C) {
+	s.authorizer.Controller = false
+	_, err := credentialvalidator.New(s.backend, s.resources, s.authorizer)
+	c.Assert(err, gc.Equals, common.ErrPerm)
+}
+
+func (s *credentialValidatorSuite) TestModelCredential(c *gc.C) {
+	result, err := s.facade.ModelCredential()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.DeepEquals, params.ModelCredential{
+		Model:           testing.ModelTag.String(),
+		Exists:          true,
+		CloudCredential: credentialTag.String(),
+		Valid:           true,
+	})
+}
+
+func (s *credentialValidatorSuite) TestModelCredentialNotSet(c *gc.C) {
+	s.backend.credential = credentialvalidator.ModelCredential{
+		Model: testing.ModelTag,
+	}
+	result, err := s.facade.ModelCredential()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.DeepEquals, params.ModelCredential{
+		Model: testing.ModelTag.String(),
+	})
+}
+
+func (s *credentialValidatorSuite) TestWatchCredential(c *gc.C) {
+	result, err := s.facade.WatchCredential(params.Entity{Tag: credentialTag.String()})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.DeepEquals, params.NotifyWatchResult{NotifyWatcherId: "1"})
+	c.Assert(s.resources.Count(), gc.Equals, 1)
+	s.backend.stub.CheckCall(c, 1, "WatchCredential", credentialTag)
+}
+
+func (s *credentialValidatorSuite) TestWatchOtherCredential(c *gc.C) {
+	other := names.NewCloudCredentialTag("dummy/mary/default")
+	_, err := s.facade.WatchCredential(params.Entity{Tag: other.String()})
+	c.Assert(err, gc.Equals, common.ErrPerm)
+	c.Assert(s.resources.Count(), gc.Equals, 0)
+}
+
+func (s *credentialValidatorSuite) TestWatchCredentialInvalidTag(c *gc.C) {
+	_, err := s.facade.WatchCredential(params.Entity{Tag: "machine-0"})
+	c.Assert(err, gc.ErrorMatches, `"machine-0" is not a valid cloudcred tag`)
+}
+
+type mockBackend struct {
+	state.EntityFinder
+	stub       jujutesting.Stub
+	credential credentialvalidator.ModelCredential
+}
+
+func (b *mockBackend) ModelCredential() (credentialvalidator.ModelCredential, error) {
+	b.stub.AddCall("ModelCredential")
+	return b.credential, b.stub.NextErr()
+}
+
+func (b *mockBackend) WatchCredential(tag names.CloudCredentialTag) state.NotifyWatcher {
+	b.stub.AddCall("WatchCredential", tag)
+	return apiservertesting.NewFakeNotifyWatcher()
+}
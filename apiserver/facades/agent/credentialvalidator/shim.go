@@ -0,0 +1,48 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package credentialvalidator
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/apiserver/facade"
+	"github.com/juju/juju/state"
+)
+
+// NewFacade wraps New to express the supplied *state.State as a Backend.
+func NewFacade(st *state.State, res facade.Resources, auth facade.Authorizer) (*API, error) {
+	facade, err := New(backendShim{st}, res, auth)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return facade, nil
+}
+
+// backendShim implements Backend by wrapping a *state.State.
+type backendShim struct {
+	*state.State
+}
+
+// ModelCredential is part of the Backend interface.
+func (shim backendShim) ModelCredential() (ModelCredential, error) {
+	model, err := shim.Model()
+	if err != nil {
+		return ModelCredential{}, errors.Trace(err)
+	}
+	result := ModelCredential{Model: model.ModelTag()}
+	tag, exists := model.CloudCredential()
+	if !exists {
+		return result, nil
+	}
+	result.Exists = true
+	result.Credential = tag
+	credential, err := shim.CloudCredential(tag)
+	if errors.IsNotFound(err) {
+		return result, nil
+	} else if err != nil {
+		return ModelCredential{}, errors.Trace(err)
+	}
+	result.Valid = !credential.Revoked
+	return result, nil
+}
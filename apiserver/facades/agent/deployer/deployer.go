@@ -11,9 +11,22 @@ import (
 	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/facade"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/state"
 )
 
+// DeployerAPIV1 provides access to the version 1 Deployer API facade,
+// which does not provide unit constraints.
+type DeployerAPIV1 struct {
+	*DeployerAPIV2
+}
+
+// DeployerAPIV2 provides access to the version 2 Deployer API facade,
+// which cannot set the status of unit agents.
+type DeployerAPIV2 struct {
+	*DeployerAPI
+}
+
 // DeployerAPI provides access to the Deployer API facade.
 type DeployerAPI struct {
 	*common.Remover
@@ -23,9 +36,38 @@ type DeployerAPI struct {
 	*common.UnitsWatcher
 	*common.StatusSetter
 
-	st         *state.State
-	resources  facade.Resources
-	authorizer facade.Authorizer
+	agentStatusSetter *common.StatusSetter
+
+	st          *state.State
+	resources   facade.Resources
+	authorizer  facade.Authorizer
+	getAuthFunc common.GetAuthFunc
+}
+
+// NewDeployerAPIV1 creates a new server-side version 1 DeployerAPI facade.
+func NewDeployerAPIV1(
+	st *state.State,
+	resources facade.Resources,
+	authorizer facade.Authorizer,
+) (*DeployerAPIV1, error) {
+	api, err := NewDeployerAPIV2(st, resources, authorizer)
+	if err != nil {
+		return nil, err
+	}
+	return &DeployerAPIV1{api}, nil
+}
+
+// NewDeployerAPIV2 creates a new server-side version 2 DeployerAPI facade.
+func NewDeployerAPIV2(
+	st *state.State,
+	resources facade.Resources,
+	authorizer facade.Authorizer,
+) (*DeployerAPIV2, error) {
+	api, err := NewDeployerAPI(st, resources, authorizer)
+	if err != nil {
+		return nil, err
+	}
+	return &DeployerAPIV2{api}, nil
 }
 
 // NewDeployerAPI creates a new server-side DeployerAPI facade.
@@ -66,9 +108,13 @@ func NewDeployerAPI(
 		APIAddresser:    common.NewAPIAddresser(st, resources),
 		UnitsWatcher:    common.NewUnitsWatcher(st, resources, getCanWatch),
 		StatusSetter:    common.NewStatusSetter(st, getAuthFunc),
-		st:              st,
-		resources:       resources,
-		authorizer:      authorizer,
+		agentStatusSetter: common.NewStatusSetter(
+			&common.UnitAgentFinder{st}, getAuthFunc,
+		),
+		st:          st,
+		resources:   resources,
+		authorizer:  authorizer,
+		getAuthFunc: getAuthFunc,
 	}, nil
 }
 
@@ -90,6 +136,62 @@ func (d *DeployerAPI) SetStatus(args params.SetStatus) (params.ErrorResults, err
 	return d.StatusSetter.SetStatus(args)
 }
 
+// SetAgentStatus sets the status of the agents of the specified units.
+func (d *DeployerAPI) SetAgentStatus(args params.SetStatus) (params.ErrorResults, error) {
+	return d.agentStatusSetter.SetStatus(args)
+}
+
+// Constraints returns the deployment constraints of the specified
+// units, from which the deployer derives the resource limits of their
+// agents. Subordinate units have no constraints of their own.
+func (d *DeployerAPI) Constraints(args params.Entities) (params.ConstraintsResults, error) {
+	result := params.ConstraintsResults{
+		Results: make([]params.ConstraintsResult, len(args.Entities)),
+	}
+	canAccess, err := d.getAuthFunc()
+	if err != nil {
+		return result, err
+	}
+	for i, entity := range args.Entities {
+		tag, err := names.ParseUnitTag(entity.Tag)
+		if err != nil || !canAccess(tag) {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		cons, err := d.unitConstraints(tag)
+		if err == nil {
+			result.Results[i].Constraints = cons
+		}
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+func (d *DeployerAPI) unitConstraints(tag names.UnitTag) (constraints.Value, error) {
+	unit, err := d.st.Unit(tag.Id())
+	if err != nil {
+		return constraints.Value{}, err
+	}
+	if !unit.IsPrincipal() {
+		return constraints.Value{}, nil
+	}
+	cons, err := unit.Constraints()
+	if err != nil {
+		return constraints.Value{}, err
+	}
+	return *cons, nil
+}
+
+// Mask the new methods from the V1 API. The API reflection code in
+// rpc/rpcreflect/type.go:newMethod skips 2-argument methods, so this
+// removes the method as far as the RPC machinery is concerned.
+
+// Constraints isn't on the V1 API.
+func (*DeployerAPIV1) Constraints(_, _ struct{}) {}
+
+// SetAgentStatus isn't on the V1 or V2 APIs.
+func (*DeployerAPIV2) SetAgentStatus(_, _ struct{}) {}
+
 // getAllUnits returns a list of all principal and subordinate units
 // assigned to the given machine.
 func getAllUnits(st *state.State, tag names.Tag) ([]string, error) {
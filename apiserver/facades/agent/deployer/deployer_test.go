@@ -15,6 +15,7 @@ import (
 	"github.com/juju/juju/apiserver/facades/agent/deployer"
 	"github.com/juju/juju/apiserver/params"
 	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/juju/testing"
 	"github.com/juju/juju/network"
 	"github.com/juju/juju/state"
@@ -320,6 +321,34 @@ func (s *deployerSuite) TestConnectionInfo(c *gc.C) {
 	c.Assert(result, gc.DeepEquals, expected)
 }
 
+func (s *deployerSuite) TestConstraints(c *gc.C) {
+	err := s.service0.SetConstraints(constraints.MustParse("mem=1G cpu-power=200"))
+	c.Assert(err, jc.ErrorIsNil)
+	principal2, err := s.service0.AddUnit(state.AddUnitParams{})
+	c.Assert(err, jc.ErrorIsNil)
+	err = principal2.AssignToMachine(s.machine1)
+	c.Assert(err, jc.ErrorIsNil)
+
+	args := params.Entities{Entities: []params.Entity{
+		{Tag: "unit-mysql-2"},
+		{Tag: "unit-mysql-1"},
+		{Tag: "unit-logging-0"},
+		{Tag: "unit-fake-42"},
+		{Tag: "machine-1"},
+	}}
+	result, err := s.deployer.Constraints(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.ConstraintsResults{
+		Results: []params.ConstraintsResult{
+			{Constraints: constraints.MustParse("mem=1G cpu-power=200")},
+			{Error: apiservertesting.ErrUnauthorized},
+			{Constraints: constraints.Value{}},
+			{Error: apiservertesting.ErrUnauthorized},
+			{Error: apiservertesting.ErrUnauthorized},
+		},
+	})
+}
+
 func (s *deployerSuite) TestSetStatus(c *gc.C) {
 	args := params.SetStatus{
 		Entities: []params.EntityStatusArgs{
@@ -346,3 +375,31 @@ func (s *deployerSuite) TestSetStatus(c *gc.C) {
 		Data:    map[string]interface{}{"foo": "bar"},
 	})
 }
+
+func (s *deployerSuite) TestSetAgentStatus(c *gc.C) {
+	args := params.SetStatus{
+		Entities: []params.EntityStatusArgs{
+			{Tag: "unit-mysql-0", Status: "failed", Info: "agent not responding"},
+			{Tag: "unit-mysql-1", Status: "failed", Info: "agent not responding"},
+			{Tag: "unit-fake-42", Status: "failed", Info: "agent not responding"},
+		},
+	}
+	results, err := s.deployer.SetAgentStatus(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{nil},
+			{apiservertesting.ErrUnauthorized},
+			{apiservertesting.ErrUnauthorized},
+		},
+	})
+	sInfo, err := s.principal0.AgentStatus()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(sInfo.Status, gc.Equals, status.Failed)
+	c.Assert(sInfo.Message, gc.Equals, "agent not responding")
+
+	// The workload status is left alone.
+	sInfo, err = s.principal0.Status()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(sInfo.Status, gc.Not(gc.Equals), status.Failed)
+}
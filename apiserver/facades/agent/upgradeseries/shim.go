@@ -0,0 +1,34 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package upgradeseries
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/apiserver/facade"
+	"github.com/juju/juju/state"
+)
+
+// NewFacade wraps New to express the supplied *state.State as a Backend.
+func NewFacade(st *state.State, res facade.Resources, auth facade.Authorizer) (*API, error) {
+	facade, err := New(backendShim{st}, res, auth)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return facade, nil
+}
+
+// backendShim implements Backend by wrapping a *state.State.
+type backendShim struct {
+	st *state.State
+}
+
+// Machine is part of the Backend interface.
+func (shim backendShim) Machine(id string) (Machine, error) {
+	machine, err := shim.st.Machine(id)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return machine, nil
+}
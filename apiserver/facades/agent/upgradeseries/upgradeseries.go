@@ -0,0 +1,180 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package upgradeseries implements the API facade used by the
+// upgradeseries worker.
+package upgradeseries
+
+import (
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/facade"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/core/model"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/watcher"
+)
+
+// Backend defines the State API used by the upgradeseries facade.
+type Backend interface {
+	Machine(id string) (Machine, error)
+}
+
+// Machine defines the machine methods used by the upgradeseries facade.
+type Machine interface {
+	WatchUpgradeSeriesNotifications() state.NotifyWatcher
+	UpgradeSeriesStatus() (model.UpgradeSeriesStatus, error)
+	SetUpgradeSeriesStatus(model.UpgradeSeriesStatus) error
+	UpgradeSeriesTarget() (string, error)
+	UpgradeSeriesUnitStatuses() (map[string]model.UpgradeSeriesStatus, error)
+	UpdateMachineSeries(series string, force bool) error
+	RemoveUpgradeSeriesLock() error
+}
+
+// API implements the API required by the upgradeseries worker.
+type API struct {
+	backend    Backend
+	resources  facade.Resources
+	authorizer facade.Authorizer
+}
+
+// New returns a new API facade for the upgradeseries worker.
+func New(backend Backend, resources facade.Resources, authorizer facade.Authorizer) (*API, error) {
+	if !authorizer.AuthMachineAgent() {
+		return nil, common.ErrPerm
+	}
+	return &API{
+		backend:    backend,
+		resources:  resources,
+		authorizer: authorizer,
+	}, nil
+}
+
+// WatchUpgradeSeriesNotifications returns a NotifyWatcher for
+// observing changes to each machine's series upgrade.
+func (api *API) WatchUpgradeSeriesNotifications(args params.Entities) (params.NotifyWatchResults, error) {
+	result := params.NotifyWatchResults{
+		Results: make([]params.NotifyWatchResult, len(args.Entities)),
+	}
+	for i, entity := range args.Entities {
+		machine, err := api.machine(entity.Tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		watch := machine.WatchUpgradeSeriesNotifications()
+		if _, ok := <-watch.Changes(); ok {
+			result.Results[i].NotifyWatcherId = api.resources.Register(watch)
+		} else {
+			result.Results[i].Error = common.ServerError(watcher.EnsureErr(watch))
+		}
+	}
+	return result, nil
+}
+
+// MachineStatus returns the status of each machine's series upgrade.
+func (api *API) MachineStatus(args params.Entities) (params.UpgradeSeriesStatusResults, error) {
+	result := params.UpgradeSeriesStatusResults{
+		Results: make([]params.UpgradeSeriesStatusResult, len(args.Entities)),
+	}
+	for i, entity := range args.Entities {
+		machine, err := api.machine(entity.Tag)
+		if err == nil {
+			var status model.UpgradeSeriesStatus
+			status, err = machine.UpgradeSeriesStatus()
+			result.Results[i].Status = string(status)
+		}
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+// SetMachineStatus records the status of each machine's series upgrade.
+func (api *API) SetMachineStatus(args params.UpgradeSeriesStatusParams) (params.ErrorResults, error) {
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Params)),
+	}
+	for i, arg := range args.Params {
+		machine, err := api.machine(arg.Entity.Tag)
+		if err == nil {
+			err = machine.SetUpgradeSeriesStatus(model.UpgradeSeriesStatus(arg.Status))
+		}
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+// TargetSeries returns the series to which each machine is being
+// upgraded.
+func (api *API) TargetSeries(args params.Entities) (params.StringResults, error) {
+	result := params.StringResults{
+		Results: make([]params.StringResult, len(args.Entities)),
+	}
+	for i, entity := range args.Entities {
+		machine, err := api.machine(entity.Tag)
+		if err == nil {
+			result.Results[i].Result, err = machine.UpgradeSeriesTarget()
+		}
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+// UnitStatuses returns the series upgrade status of each unit taking
+// part in each machine's series upgrade.
+func (api *API) UnitStatuses(args params.Entities) (params.UpgradeSeriesUnitStatusesResults, error) {
+	result := params.UpgradeSeriesUnitStatusesResults{
+		Results: make([]params.UpgradeSeriesUnitStatusesResult, len(args.Entities)),
+	}
+	for i, entity := range args.Entities {
+		machine, err := api.machine(entity.Tag)
+		if err == nil {
+			var statuses map[string]model.UpgradeSeriesStatus
+			statuses, err = machine.UpgradeSeriesUnitStatuses()
+			if err == nil {
+				result.Results[i].Statuses = make(map[string]string)
+				for unitName, status := range statuses {
+					result.Results[i].Statuses[unitName] = string(status)
+				}
+			}
+		}
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+// FinishUpgradeSeries records the new series of each machine, and
+// removes the lock on the machine taken for its series upgrade. The
+// operating system has already been upgraded by this point, so the
+// series is updated even if it is not supported by the charms of
+// the machine's units.
+func (api *API) FinishUpgradeSeries(args params.UpdateSeriesArgs) (params.ErrorResults, error) {
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Args)),
+	}
+	for i, arg := range args.Args {
+		machine, err := api.machine(arg.Entity.Tag)
+		if err == nil {
+			err = machine.UpdateMachineSeries(arg.Series, true)
+		}
+		if err == nil {
+			err = machine.RemoveUpgradeSeriesLock()
+		}
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+// machine returns the machine with the supplied tag, if the
+// authenticated agent is allowed to access it.
+func (api *API) machine(tag string) (Machine, error) {
+	machineTag, err := names.ParseMachineTag(tag)
+	if err != nil {
+		return nil, common.ErrPerm
+	}
+	if !api.authorizer.AuthOwner(machineTag) {
+		return nil, common.ErrPerm
+	}
+	return api.backend.Machine(machineTag.Id())
+}
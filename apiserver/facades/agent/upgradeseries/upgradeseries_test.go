@@ -0,0 +1,173 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package upgradeseries_test
+
+import (
+	jujutesting "github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/facades/agent/upgradeseries"
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/core/model"
+	"github.com/juju/juju/testing"
+)
+
+type upgradeSeriesSuite struct {
+	testing.BaseSuite
+	backend    *mockBackend
+	authorizer *apiservertesting.FakeAuthorizer
+	facade     *upgradeseries.API
+}
+
+var _ = gc.Suite(&upgradeSeriesSuite{})
+
+func (s *upgradeSeriesSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.backend = &mockBackend{machine: &mockMachine{}}
+	s.authorizer = &apiservertesting.FakeAuthorizer{
+		Tag: names.NewMachineTag("1"),
+	}
+	facade, err := upgradeseries.New(s.backend, nil, s.authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+	s.facade = facade
+}
+
+func (s *upgradeSeriesSuite) TestNewRequiresMachineAgent(c *gc.C) {
+	s.authorizer.Tag = names.NewUnitTag("mysql/0")
+	_, err := upgradeseries.New(s.backend, nil, s.authorizer)
+	c.Assert(err, gc.Equals, common.ErrPerm)
+}
+
+func (s *upgradeSeriesSuite) TestMachineStatus(c *gc.C) {
+	result, err := s.facade.MachineStatus(entities("0", "1"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.DeepEquals, params.UpgradeSeriesStatusResults{
+		Results: []params.UpgradeSeriesStatusResult{
+			{Error: apiservertesting.ErrUnauthorized},
+			{Status: "prepare started"},
+		},
+	})
+	s.backend.stub.CheckCalls(c, []jujutesting.StubCall{
+		{"Machine", []interface{}{"1"}},
+	})
+	s.backend.machine.stub.CheckCallNames(c, "UpgradeSeriesStatus")
+}
+
+func (s *upgradeSeriesSuite) TestSetMachineStatus(c *gc.C) {
+	result, err := s.facade.SetMachineStatus(params.UpgradeSeriesStatusParams{
+		Params: []params.UpgradeSeriesStatusParam{{
+			Entity: params.Entity{Tag: "machine-0"},
+			Status: "prepare completed",
+		}, {
+			Entity: params.Entity{Tag: "machine-1"},
+			Status: "prepare completed",
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{Error: apiservertesting.ErrUnauthorized},
+			{nil},
+		},
+	})
+	s.backend.machine.stub.CheckCalls(c, []jujutesting.StubCall{
+		{"SetUpgradeSeriesStatus", []interface{}{model.UpgradeSeriesPrepareCompleted}},
+	})
+}
+
+func (s *upgradeSeriesSuite) TestTargetSeries(c *gc.C) {
+	result, err := s.facade.TargetSeries(entities("1"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.DeepEquals, params.StringResults{
+		Results: []params.StringResult{{Result: "xenial"}},
+	})
+}
+
+func (s *upgradeSeriesSuite) TestUnitStatuses(c *gc.C) {
+	result, err := s.facade.UnitStatuses(entities("1"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.DeepEquals, params.UpgradeSeriesUnitStatusesResults{
+		Results: []params.UpgradeSeriesUnitStatusesResult{{
+			Statuses: map[string]string{"mysql/0": "prepare completed"},
+		}},
+	})
+}
+
+func (s *upgradeSeriesSuite) TestFinishUpgradeSeries(c *gc.C) {
+	result, err := s.facade.FinishUpgradeSeries(params.UpdateSeriesArgs{
+		Args: []params.UpdateSeriesArg{{
+			Entity: params.Entity{Tag: "machine-1"},
+			Series: "xenial",
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{{nil}},
+	})
+	s.backend.machine.stub.CheckCalls(c, []jujutesting.StubCall{
+		{"UpdateMachineSeries", []interface{}{"xenial", true}},
+		{"RemoveUpgradeSeriesLock", nil},
+	})
+}
+
+func entities(ids ...string) params.Entities {
+	var args params.Entities
+	for _, id := range ids {
+		args.Entities = append(args.Entities, params.Entity{
+			Tag: names.NewMachineTag(id).String(),
+		})
+	}
+	return args
+}
+
+type mockBackend struct {
+	stub    jujutesting.Stub
+	machine *mockMachine
+}
+
+func (backend *mockBackend) Machine(id string) (upgradeseries.Machine, error) {
+	backend.stub.AddCall("Machine", id)
+	return backend.machine, backend.stub.NextErr()
+}
+
+type mockMachine struct {
+	upgradeseries.Machine
+	stub jujutesting.Stub
+}
+
+func (m *mockMachine) UpgradeSeriesStatus() (model.UpgradeSeriesStatus, error) {
+	m.stub.AddCall("UpgradeSeriesStatus")
+	return model.UpgradeSeriesPrepareStarted, m.stub.NextErr()
+}
+
+func (m *mockMachine) SetUpgradeSeriesStatus(status model.UpgradeSeriesStatus) error {
+	m.stub.AddCall("SetUpgradeSeriesStatus", status)
+	return m.stub.NextErr()
+}
+
+func (m *mockMachine) UpgradeSeriesTarget() (string, error) {
+	m.stub.AddCall("UpgradeSeriesTarget")
+	return "xenial", m.stub.NextErr()
+}
+
+func (m *mockMachine) UpgradeSeriesUnitStatuses() (map[string]model.UpgradeSeriesStatus, error) {
+	m.stub.AddCall("UpgradeSeriesUnitStatuses")
+	return map[string]model.UpgradeSeriesStatus{
+		"mysql/0": model.UpgradeSeriesPrepareCompleted,
+	}, m.stub.NextErr()
+}
+
+func (m *mockMachine) UpdateMachineSeries(series string, force bool) error {
+	m.stub.AddCall("UpdateMachineSeries", series, force)
+	return m.stub.NextErr()
+}
+
+func (m *mockMachine) RemoveUpgradeSeriesLock() error {
+	m.stub.AddCall("RemoveUpgradeSeriesLock")
+	return m.stub.NextErr()
+}
@@ -9,6 +9,7 @@ package application
 import (
 	"fmt"
 	"net"
+	"strings"
 
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
@@ -51,6 +52,16 @@ type APIv6 struct {
 	*APIv5
 }
 
+// APIv7 provides the Application API facade for version 7.
+type APIv7 struct {
+	*APIv6
+}
+
+// APIv8 provides the Application API facade for version 8.
+type APIv8 struct {
+	*APIv7
+}
+
 // API implements the application interface and is the concrete
 // implementation of the api end point.
 //
@@ -90,6 +101,26 @@ func NewFacadeV6(ctx facade.Context) (*APIv6, error) {
 	return &APIv6{apiV5}, nil
 }
 
+// NewFacadeV7 provides the signature required for facade registration
+// for version 7.
+func NewFacadeV7(ctx facade.Context) (*APIv7, error) {
+	apiV6, err := NewFacadeV6(ctx)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &APIv7{apiV6}, nil
+}
+
+// NewFacadeV8 provides the signature required for facade registration
+// for version 8.
+func NewFacadeV8(ctx facade.Context) (*APIv8, error) {
+	apiV7, err := NewFacadeV7(ctx)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &APIv8{apiV7}, nil
+}
+
 // NewFacade provides the signature required for facade registration.
 func NewFacadeV5(ctx facade.Context) (*APIv5, error) {
 	backend, err := NewStateBackend(ctx.State())
@@ -211,15 +242,21 @@ func (api *APIv5) Deploy(args params.ApplicationsDeploy) (params.ErrorResults, e
 
 func applicationConfigSchema(modelType state.ModelType) (environschema.Fields, schema.Defaults, error) {
 	if modelType != state.ModelTypeCAAS {
-		return environschema.Fields{}, schema.Defaults{}, nil
+		return application.AddTrustSchemaAndDefaults(environschema.Fields{}, schema.Defaults{})
 	}
 	// TODO(caas) - get the schema from the provider
 	defaults := caas.ConfigDefaults(k8s.ConfigDefaults())
-	schema, err := caas.ConfigSchema(k8s.ConfigSchema())
-	return schema, defaults, err
+	fields, err := caas.ConfigSchema(k8s.ConfigSchema())
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	return application.AddTrustSchemaAndDefaults(fields, defaults)
 }
 
-func splitApplicationAndCharmConfig(modelType state.ModelType, inConfig map[string]string) (
+// splitApplicationAndCharmConfig splits the given config into application
+// config and charm config. Options defined by the charm are always charm
+// config, even if they share a name with an application config option.
+func splitApplicationAndCharmConfig(modelType state.ModelType, charmOptions *charm.Config, inConfig map[string]string) (
 	appCfg map[string]interface{},
 	charmCfg map[string]string,
 	_ error,
@@ -234,7 +271,7 @@ func splitApplicationAndCharmConfig(modelType state.ModelType, inConfig map[stri
 	appConfigAttrs := make(map[string]interface{})
 	charmConfig := make(map[string]string)
 	for k, v := range inConfig {
-		if appConfigKeys.Contains(k) {
+		if isApplicationConfigKey(appConfigKeys, charmOptions, k) {
 			appConfigAttrs[k] = v
 		} else {
 			charmConfig[k] = v
@@ -243,6 +280,17 @@ func splitApplicationAndCharmConfig(modelType state.ModelType, inConfig map[stri
 	return appConfigAttrs, charmConfig, nil
 }
 
+// isApplicationConfigKey reports whether the named option is
+// application config rather than charm config.
+func isApplicationConfigKey(appConfigKeys set.Strings, charmOptions *charm.Config, name string) bool {
+	if charmOptions != nil {
+		if _, ok := charmOptions.Options[name]; ok {
+			return false
+		}
+	}
+	return appConfigKeys.Contains(name)
+}
+
 // deployApplication fetches the charm from the charm store and deploys it.
 // The logic has been factored out into a common function which is called by
 // both the legacy API on the client facade, as well as the new application facade.
@@ -267,12 +315,21 @@ func deployApplication(
 				backend.ModelType(),
 			)
 		}
-		if len(args.Placement) > 0 {
+		if len(args.Placement) > 1 {
 			return errors.Errorf(
-				"Placement may not be specified for %s models",
+				"only 1 placement directive is supported for %s models, got %d",
 				backend.ModelType(),
+				len(args.Placement),
 			)
 		}
+		for _, p := range args.Placement {
+			if p.Scope == instance.MachineScope || p.Directive == "" {
+				return errors.Errorf(
+					"placement directive %q is not supported for %s models",
+					p, backend.ModelType(),
+				)
+			}
+		}
 	}
 
 	// Do a quick but not complete validation check before going any further.
@@ -296,7 +353,7 @@ func deployApplication(
 		return errors.Trace(err)
 	}
 
-	appConfigAttrs, charmConfig, err := splitApplicationAndCharmConfig(backend.ModelType(), args.Config)
+	appConfigAttrs, charmConfig, err := splitApplicationAndCharmConfig(backend.ModelType(), ch.Config(), args.Config)
 	if err != nil {
 		return errors.Trace(err)
 	}
@@ -853,6 +910,8 @@ func addApplicationUnits(backend Backend, args params.AddApplicationUnits) ([]Un
 	}
 
 	assignUnits := true
+	placement := args.Placement
+	var podPlacement string
 	if backend.ModelType() != state.ModelTypeIAAS {
 		// In a CAAS model, there are no machines for
 		// units to be assigned to.
@@ -863,12 +922,14 @@ func addApplicationUnits(backend Backend, args params.AddApplicationUnits) ([]Un
 				backend.ModelType(),
 			)
 		}
-		if len(args.Placement) > 0 {
-			return nil, errors.Errorf(
-				"Placement may not be specified for %s models",
-				backend.ModelType(),
-			)
+		// The pods of a CAAS application are all scheduled
+		// alike, so the placement directives are combined
+		// and applied to the application as a whole.
+		var err error
+		if podPlacement, err = combinePodPlacement(backend.ModelType(), placement); err != nil {
+			return nil, errors.Trace(err)
 		}
+		placement = nil
 	}
 
 	// Parse storage tags in AttachStorage.
@@ -887,16 +948,40 @@ func addApplicationUnits(backend Backend, args params.AddApplicationUnits) ([]Un
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
+	if podPlacement != "" {
+		if err := application.SetPlacement(podPlacement); err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
 	return addUnits(
 		application,
 		args.ApplicationName,
 		args.NumUnits,
-		args.Placement,
+		placement,
 		attachStorage,
 		assignUnits,
 	)
 }
 
+// combinePodPlacement returns the single placement directive
+// used to schedule the pods of an application in a model of the
+// given type, made by joining the given placement directives.
+// Machine and container placement is not supported.
+func combinePodPlacement(modelType state.ModelType, placement []*instance.Placement) (string, error) {
+	directives := make([]string, len(placement))
+	for i, p := range placement {
+		_, err := instance.ParseContainerType(p.Scope)
+		if p.Scope == instance.MachineScope || err == nil || p.Directive == "" {
+			return "", errors.Errorf(
+				"placement directive %q is not supported for %s models",
+				p, modelType,
+			)
+		}
+		directives[i] = p.Directive
+	}
+	return strings.Join(directives, ","), nil
+}
+
 // DestroyUnits removes a given set of application units.
 //
 // NOTE(axw) this exists only for backwards compatibility,
@@ -1053,7 +1138,27 @@ func (api *APIv4) DestroyApplication(args params.Entities) (params.DestroyApplic
 }
 
 // DestroyApplication removes a given set of applications.
+//
+// NOTE forcing the removal of applications is only supported by
+// facade version 7 and later, so Force is ignored here.
 func (api *APIv5) DestroyApplication(args params.DestroyApplicationsParams) (params.DestroyApplicationResults, error) {
+	v5args := params.DestroyApplicationsParams{
+		Applications: make([]params.DestroyApplicationParams, len(args.Applications)),
+	}
+	for i, arg := range args.Applications {
+		arg.Force = false
+		v5args.Applications[i] = arg
+	}
+	return api.destroyApplication(v5args)
+}
+
+// DestroyApplication removes a given set of applications, forcibly
+// removing those for which Force is set.
+func (api *APIv7) DestroyApplication(args params.DestroyApplicationsParams) (params.DestroyApplicationResults, error) {
+	return api.destroyApplication(args)
+}
+
+func (api *APIv5) destroyApplication(args params.DestroyApplicationsParams) (params.DestroyApplicationResults, error) {
 	if err := api.checkCanWrite(); err != nil {
 		return params.DestroyApplicationResults{}, err
 	}
@@ -1065,6 +1170,9 @@ func (api *APIv5) DestroyApplication(args params.DestroyApplicationsParams) (par
 		if err != nil {
 			return nil, err
 		}
+		if arg.Force && api.backend.ModelType() != state.ModelTypeIAAS {
+			return nil, errors.NotSupportedf("forcing the removal of applications in a %s model", api.backend.ModelType())
+		}
 		var info params.DestroyApplicationInfo
 		app, err := api.backend.Application(tag.Id())
 		if err != nil {
@@ -1120,8 +1228,21 @@ func (api *APIv5) DestroyApplication(args params.DestroyApplicationsParams) (par
 				info.DetachedStorage = append(info.DetachedStorage, detached...)
 			}
 		}
+		if arg.Force {
+			relations, err := app.Relations()
+			if err != nil {
+				return nil, err
+			}
+			for _, rel := range relations {
+				info.ForcedRelations = append(
+					info.ForcedRelations,
+					params.Entity{rel.Tag().String()},
+				)
+			}
+		}
 		op := app.DestroyOperation()
 		op.DestroyStorage = arg.DestroyStorage
+		op.Force = arg.Force
 		if err := api.backend.ApplyOperation(op); err != nil {
 			return nil, err
 		}
@@ -1581,8 +1702,12 @@ func (api *APIv6) setApplicationConfig(arg params.ApplicationConfigSet) error {
 	if err != nil {
 		return errors.Trace(err)
 	}
+	ch, _, err := app.Charm()
+	if err != nil {
+		return errors.Trace(err)
+	}
 
-	appConfigAttrs, charmConfig, err := splitApplicationAndCharmConfig(api.backend.ModelType(), arg.Config)
+	appConfigAttrs, charmConfig, err := splitApplicationAndCharmConfig(api.backend.ModelType(), ch.Config(), arg.Config)
 	if err != nil {
 		return errors.Trace(err)
 	}
@@ -1597,10 +1722,6 @@ func (api *APIv6) setApplicationConfig(arg params.ApplicationConfigSet) error {
 		}
 	}
 	if len(charmConfig) > 0 {
-		ch, _, err := app.Charm()
-		if err != nil {
-			return err
-		}
 		// Validate the charm and application config.
 		charmConfigChanges, err := ch.Config().ParseSettingsStrings(charmConfig)
 		if err != nil {
@@ -1635,6 +1756,10 @@ func (api *APIv6) unsetApplicationConfig(arg params.ApplicationUnset) error {
 	if err != nil {
 		return errors.Trace(err)
 	}
+	ch, _, err := app.Charm()
+	if err != nil {
+		return errors.Trace(err)
+	}
 
 	schema, defaults, err := applicationConfigSchema(api.backend.ModelType())
 	if err != nil {
@@ -1645,7 +1770,7 @@ func (api *APIv6) unsetApplicationConfig(arg params.ApplicationUnset) error {
 	var appConfigKeys []string
 	charmSettings := make(charm.Settings)
 	for _, name := range arg.Options {
-		if appConfigFields.Contains(name) {
+		if isApplicationConfigKey(appConfigFields, ch.Config(), name) {
 			appConfigKeys = append(appConfigKeys, name)
 		} else {
 			charmSettings[name] = nil
@@ -1664,3 +1789,41 @@ func (api *APIv6) unsetApplicationConfig(arg params.ApplicationUnset) error {
 	}
 	return nil
 }
+
+// SetApplicationsTrust grants or revokes the trust of the specified
+// applications. Trust is recorded in the application config, separately
+// from any charm config option of the same name.
+func (api *APIv8) SetApplicationsTrust(args params.ApplicationTrustArgs) (params.ErrorResults, error) {
+	var result params.ErrorResults
+	if err := api.checkCanWrite(); err != nil {
+		return result, errors.Trace(err)
+	}
+	if err := api.check.ChangeAllowed(); err != nil {
+		return result, errors.Trace(err)
+	}
+	result.Results = make([]params.ErrorResult, len(args.Args))
+	for i, arg := range args.Args {
+		err := api.setApplicationTrust(arg)
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+func (api *APIv8) setApplicationTrust(arg params.ApplicationTrust) error {
+	app, err := api.backend.Application(arg.ApplicationName)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	schema, defaults, err := applicationConfigSchema(api.backend.ModelType())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if arg.Trusted {
+		err = app.UpdateApplicationConfig(application.ConfigAttributes{
+			application.TrustConfigOptionName: true,
+		}, nil, schema, defaults)
+	} else {
+		err = app.UpdateApplicationConfig(nil, []string{application.TrustConfigOptionName}, schema, defaults)
+	}
+	return errors.Annotate(err, "updating application trust")
+}
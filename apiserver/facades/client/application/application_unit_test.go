@@ -4,12 +4,16 @@
 package application_test
 
 import (
+	"fmt"
+
 	"github.com/juju/errors"
+	"github.com/juju/schema"
 	"github.com/juju/testing"
 	jc "github.com/juju/testing/checkers"
 	"github.com/juju/utils"
 	gc "gopkg.in/check.v1"
 	"gopkg.in/juju/charm.v6"
+	"gopkg.in/juju/environschema.v1"
 	"gopkg.in/juju/names.v2"
 	"gopkg.in/macaroon.v1"
 
@@ -39,7 +43,7 @@ type ApplicationSuite struct {
 	env          environs.Environ
 	blockChecker mockBlockChecker
 	authorizer   apiservertesting.FakeAuthorizer
-	api          *application.APIv6
+	api          *application.APIv8
 }
 
 var _ = gc.Suite(&ApplicationSuite{})
@@ -58,7 +62,7 @@ func (s *ApplicationSuite) setAPIUser(c *gc.C, user names.UserTag) {
 		},
 	)
 	c.Assert(err, jc.ErrorIsNil)
-	s.api = &application.APIv6{api}
+	s.api = &application.APIv8{&application.APIv7{&application.APIv6{api}}}
 }
 
 func (s *ApplicationSuite) SetUpTest(c *gc.C) {
@@ -173,7 +177,7 @@ func (s *ApplicationSuite) SetUpTest(c *gc.C) {
 		},
 	)
 	c.Assert(err, jc.ErrorIsNil)
-	s.api = &application.APIv6{api}
+	s.api = &application.APIv8{&application.APIv7{&application.APIv6{api}}}
 }
 
 func (s *ApplicationSuite) TearDownTest(c *gc.C) {
@@ -363,6 +367,74 @@ func (s *ApplicationSuite) TestDestroyApplicationDestroyStorage(c *gc.C) {
 	})
 }
 
+func (s *ApplicationSuite) TestDestroyApplicationForce(c *gc.C) {
+	app := s.backend.applications["postgresql"]
+	app.relations = []application.Relation{&s.relation}
+	results, err := s.api.DestroyApplication(params.DestroyApplicationsParams{
+		Applications: []params.DestroyApplicationParams{{
+			ApplicationTag: "application-postgresql",
+			Force:          true,
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	c.Assert(results.Results[0], jc.DeepEquals, params.DestroyApplicationResult{
+		Info: &params.DestroyApplicationInfo{
+			DestroyedUnits: []params.Entity{
+				{Tag: "unit-postgresql-0"},
+				{Tag: "unit-postgresql-1"},
+			},
+			DetachedStorage: []params.Entity{
+				{Tag: "storage-pgdata-0"},
+			},
+			DestroyedStorage: []params.Entity{
+				{Tag: "storage-pgdata-1"},
+			},
+			ForcedRelations: []params.Entity{
+				{Tag: "relation-wordpress.db#mysql.db"},
+			},
+		},
+	})
+
+	app.CheckCallNames(c, "AllUnits", "Relations", "DestroyOperation")
+	s.backend.CheckCall(c, 7, "ApplyOperation", &state.DestroyApplicationOperation{
+		Force: true,
+	})
+}
+
+func (s *ApplicationSuite) TestDestroyApplicationForceV6(c *gc.C) {
+	app := s.backend.applications["postgresql"]
+	app.relations = []application.Relation{&s.relation}
+	results, err := s.api.APIv6.DestroyApplication(params.DestroyApplicationsParams{
+		Applications: []params.DestroyApplicationParams{{
+			ApplicationTag: "application-postgresql",
+			Force:          true,
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(results.Results[0].Info.ForcedRelations, gc.HasLen, 0)
+
+	// Versions before 7 ignore Force.
+	app.CheckCallNames(c, "AllUnits", "DestroyOperation")
+	s.backend.CheckCall(c, 7, "ApplyOperation", &state.DestroyApplicationOperation{})
+}
+
+func (s *ApplicationSuite) TestDestroyApplicationForceCAAS(c *gc.C) {
+	s.backend.modelType = state.ModelTypeCAAS
+	results, err := s.api.DestroyApplication(params.DestroyApplicationsParams{
+		Applications: []params.DestroyApplicationParams{{
+			ApplicationTag: "application-postgresql",
+			Force:          true,
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	c.Assert(results.Results[0].Error, gc.ErrorMatches, "forcing the removal of applications in a caas model not supported")
+	s.backend.CheckNoCalls(c)
+}
+
 func (s *ApplicationSuite) TestDestroyApplicationNotFound(c *gc.C) {
 	delete(s.backend.applications, "postgresql")
 	results, err := s.api.DestroyApplication(params.DestroyApplicationsParams{
@@ -496,14 +568,42 @@ func (s *ApplicationSuite) TestDeployCAASModel(c *gc.C) {
 			CharmURL:        "local:baz-0",
 			NumUnits:        1,
 			Placement:       []*instance.Placement{{}},
+		}, {
+			ApplicationName: "qux",
+			CharmURL:        "local:qux-0",
+			NumUnits:        1,
+			Placement:       []*instance.Placement{{Scope: "#", Directive: "0"}},
+		}, {
+			ApplicationName: "quux",
+			CharmURL:        "local:quux-0",
+			NumUnits:        1,
+			Placement:       []*instance.Placement{{Scope: "uuid", Directive: "a=b"}, {Scope: "uuid", Directive: "c=d"}},
 		}},
 	}
 	results, err := s.api.Deploy(args)
 	c.Assert(err, jc.ErrorIsNil)
-	c.Assert(results.Results, gc.HasLen, 3)
+	c.Assert(results.Results, gc.HasLen, 5)
 	c.Assert(results.Results[0].Error, gc.IsNil)
 	c.Assert(results.Results[1].Error, gc.ErrorMatches, "AttachStorage may not be specified for caas models")
-	c.Assert(results.Results[2].Error, gc.ErrorMatches, "Placement may not be specified for caas models")
+	c.Assert(results.Results[2].Error, gc.ErrorMatches, `placement directive ":" is not supported for caas models`)
+	c.Assert(results.Results[3].Error, gc.ErrorMatches, `placement directive "#:0" is not supported for caas models`)
+	c.Assert(results.Results[4].Error, gc.ErrorMatches, "only 1 placement directive is supported for caas models, got 2")
+}
+
+func (s *ApplicationSuite) TestDeployCAASModelPlacement(c *gc.C) {
+	s.backend.modelType = state.ModelTypeCAAS
+	args := params.ApplicationsDeploy{
+		Applications: []params.ApplicationDeploy{{
+			ApplicationName: "foo",
+			CharmURL:        "local:foo-0",
+			NumUnits:        1,
+			Placement:       []*instance.Placement{{Scope: "uuid", Directive: "disktype=ssd"}},
+		}},
+	}
+	results, err := s.api.Deploy(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	c.Assert(results.Results[0].Error, gc.IsNil)
 }
 
 func (s *ApplicationSuite) TestAddUnits(c *gc.C) {
@@ -581,12 +681,41 @@ func (s *ApplicationSuite) TestAddUnitsAttachStorageCAASModel(c *gc.C) {
 
 func (s *ApplicationSuite) TestAddUnitsPlacementCAASModel(c *gc.C) {
 	s.backend.modelType = state.ModelTypeCAAS
-	_, err := s.api.AddUnits(params.AddApplicationUnits{
+	results, err := s.api.AddUnits(params.AddApplicationUnits{
 		ApplicationName: "postgresql",
 		NumUnits:        1,
-		Placement:       []*instance.Placement{{}},
+		Placement: []*instance.Placement{
+			{Scope: "uuid", Directive: "disktype=ssd"},
+			{Scope: "uuid", Directive: "spread=zone"},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.AddApplicationUnitsResults{
+		Units: []string{"postgresql/99"},
 	})
-	c.Assert(err, gc.ErrorMatches, "Placement may not be specified for caas models")
+	app := s.backend.applications["postgresql"]
+	app.CheckCalls(c, []testing.StubCall{
+		{"SetPlacement", []interface{}{"disktype=ssd,spread=zone"}},
+		{"AddUnit", []interface{}{state.AddUnitParams{}}},
+	})
+	app.addedUnit.CheckNoCalls(c) // no assignment
+}
+
+func (s *ApplicationSuite) TestAddUnitsInvalidPlacementCAASModel(c *gc.C) {
+	s.backend.modelType = state.ModelTypeCAAS
+	for _, placement := range []*instance.Placement{
+		{},
+		{Scope: instance.MachineScope, Directive: "0"},
+		{Scope: "lxd", Directive: "0"},
+	} {
+		_, err := s.api.AddUnits(params.AddApplicationUnits{
+			ApplicationName: "postgresql",
+			NumUnits:        1,
+			Placement:       []*instance.Placement{placement},
+		})
+		c.Assert(err, gc.ErrorMatches, fmt.Sprintf("placement directive %q is not supported for caas models", placement))
+	}
+	s.backend.applications["postgresql"].CheckNoCalls(c)
 }
 
 func (s *ApplicationSuite) TestSetRelationSuspended(c *gc.C) {
@@ -1081,12 +1210,36 @@ func (s *ApplicationSuite) TestSetApplicationConfig(c *gc.C) {
 	schema, err := caas.ConfigSchema(k8s.ConfigSchema())
 	c.Assert(err, jc.ErrorIsNil)
 	defaults := caas.ConfigDefaults(k8s.ConfigDefaults())
+	schema, defaults, err = coreapplication.AddTrustSchemaAndDefaults(schema, defaults)
+	c.Assert(err, jc.ErrorIsNil)
 	app.CheckCall(c, 0, "UpdateApplicationConfig", coreapplication.ConfigAttributes{
 		"juju-external-hostname": "value",
 	}, []string(nil), schema, defaults)
 	app.CheckCall(c, 1, "UpdateCharmConfig", charm.Settings{"stringOption": "stringVal"})
 }
 
+func (s *ApplicationSuite) TestSetApplicationConfigCharmOption(c *gc.C) {
+	// Options defined by the charm are charm config,
+	// even if they clash with application config.
+	app := s.backend.applications["postgresql"]
+	app.charm = &mockCharm{
+		config: &charm.Config{
+			Options: map[string]charm.Option{
+				"trust": {Type: "string"},
+			},
+		},
+	}
+	result, err := s.api.SetApplicationsConfig(params.ApplicationConfigSetArgs{
+		Args: []params.ApplicationConfigSet{{
+			ApplicationName: "postgresql",
+			Config:          map[string]string{"trust": "me"},
+		}}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.OneError(), jc.ErrorIsNil)
+	app.CheckCallNames(c, "UpdateCharmConfig")
+	app.CheckCall(c, 0, "UpdateCharmConfig", charm.Settings{"trust": "me"})
+}
+
 func (s *ApplicationSuite) TestBlockSetApplicationConfig(c *gc.C) {
 	s.blockChecker.SetErrors(errors.New("blocked"))
 	_, err := s.api.SetApplicationsConfig(params.ApplicationConfigSetArgs{})
@@ -1135,11 +1288,64 @@ func (s *ApplicationSuite) TestUnsetApplicationConfig(c *gc.C) {
 	schema, err := caas.ConfigSchema(k8s.ConfigSchema())
 	c.Assert(err, jc.ErrorIsNil)
 	defaults := caas.ConfigDefaults(k8s.ConfigDefaults())
+	schema, defaults, err = coreapplication.AddTrustSchemaAndDefaults(schema, defaults)
+	c.Assert(err, jc.ErrorIsNil)
 	app.CheckCall(c, 0, "UpdateApplicationConfig", coreapplication.ConfigAttributes(nil),
 		[]string{"juju-external-hostname"}, schema, defaults)
 	app.CheckCall(c, 1, "UpdateCharmConfig", charm.Settings{"stringVal": nil})
 }
 
+func (s *ApplicationSuite) TestUnsetApplicationConfigCharmOption(c *gc.C) {
+	app := s.backend.applications["postgresql"]
+	app.charm = &mockCharm{
+		config: &charm.Config{
+			Options: map[string]charm.Option{
+				"trust": {Type: "string"},
+			},
+		},
+	}
+	result, err := s.api.UnsetApplicationsConfig(params.ApplicationConfigUnsetArgs{
+		Args: []params.ApplicationUnset{{
+			ApplicationName: "postgresql",
+			Options:         []string{"trust"},
+		}}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.OneError(), jc.ErrorIsNil)
+	app.CheckCallNames(c, "UpdateCharmConfig")
+	app.CheckCall(c, 0, "UpdateCharmConfig", charm.Settings{"trust": nil})
+}
+
+func (s *ApplicationSuite) TestSetApplicationsTrust(c *gc.C) {
+	result, err := s.api.SetApplicationsTrust(params.ApplicationTrustArgs{
+		Args: []params.ApplicationTrust{{
+			ApplicationName: "postgresql",
+			Trusted:         true,
+		}, {
+			ApplicationName: "postgresql",
+			Trusted:         false,
+		}}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Combine(), jc.ErrorIsNil)
+	s.backend.CheckCallNames(c, "Application", "Application")
+	app := s.backend.applications["postgresql"]
+	app.CheckCallNames(c, "UpdateApplicationConfig", "UpdateApplicationConfig")
+
+	fields, defaults, err := coreapplication.AddTrustSchemaAndDefaults(environschema.Fields{}, schema.Defaults{})
+	c.Assert(err, jc.ErrorIsNil)
+	app.CheckCall(c, 0, "UpdateApplicationConfig", coreapplication.ConfigAttributes{
+		"trust": true,
+	}, []string(nil), fields, defaults)
+	app.CheckCall(c, 1, "UpdateApplicationConfig", coreapplication.ConfigAttributes(nil),
+		[]string{"trust"}, fields, defaults)
+}
+
+func (s *ApplicationSuite) TestBlockSetApplicationsTrust(c *gc.C) {
+	s.blockChecker.SetErrors(errors.New("blocked"))
+	_, err := s.api.SetApplicationsTrust(params.ApplicationTrustArgs{})
+	c.Assert(err, gc.ErrorMatches, "blocked")
+	s.blockChecker.CheckCallNames(c, "ChangeAllowed")
+}
+
 func (s *ApplicationSuite) TestBlockUnsetApplicationConfig(c *gc.C) {
 	s.blockChecker.SetErrors(errors.New("blocked"))
 	_, err := s.api.UnsetApplicationsConfig(params.ApplicationConfigUnsetArgs{})
@@ -1192,3 +1398,73 @@ func (s *ApplicationSuite) TestCAASExposeWithHostname(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 	app.CheckCallNames(c, "ApplicationConfig", "SetExposed")
 }
+
+func (s *ApplicationSuite) TestUnitsInfo(c *gc.C) {
+	results, err := s.api.UnitsInfo(params.Entities{Entities: []params.Entity{
+		{Tag: "unit-postgresql-0"},
+		{Tag: "unit-postgresql-1"},
+		{Tag: "unit-mysql-0"},
+		{Tag: "application-postgresql"},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 4)
+	relationData := []params.EndpointRelationData{{
+		RelationId:      123,
+		Endpoint:        "db",
+		RelatedEndpoint: "wordpress:db",
+		Data:            map[string]interface{}{"user": "wordpress"},
+	}}
+	workloadHistory := []params.DetailedStatus{{Status: "active", Info: "ready", Kind: "workload"}}
+	agentHistory := []params.DetailedStatus{{Status: "idle", Kind: "juju-unit"}}
+	c.Assert(results.Results[0], jc.DeepEquals, params.UnitInfoResult{Result: &params.UnitResult{
+		Tag:                   "unit-postgresql-0",
+		Life:                  "alive",
+		WorkloadVersion:       "9.6",
+		Machine:               "0",
+		PublicAddress:         "10.0.0.1",
+		OpenedPorts:           []string{"5432/tcp"},
+		Leader:                true,
+		Storage:               []string{"pgdata/0", "pgdata/1"},
+		RelationData:          relationData,
+		WorkloadStatusHistory: workloadHistory,
+		AgentStatusHistory:    agentHistory,
+	}})
+	c.Assert(results.Results[1], jc.DeepEquals, params.UnitInfoResult{Result: &params.UnitResult{
+		Tag:                   "unit-postgresql-1",
+		Life:                  "alive",
+		WorkloadVersion:       "9.6",
+		Machine:               "0",
+		PublicAddress:         "10.0.0.1",
+		OpenedPorts:           []string{"5432/tcp"},
+		RelationData:          relationData,
+		WorkloadStatusHistory: workloadHistory,
+		AgentStatusHistory:    agentHistory,
+	}})
+	c.Assert(results.Results[2].Error, gc.ErrorMatches, `unit "mysql/0" not found`)
+	c.Assert(results.Results[3].Error, gc.ErrorMatches, `"application-postgresql" is not a valid unit tag`)
+	s.backend.CheckCallNames(c, "ApplicationLeaders",
+		"Unit", "UnitStorageAttachments",
+		"Unit", "UnitStorageAttachments",
+		"Unit",
+	)
+}
+
+func (s *ApplicationSuite) TestUnitsInfoPermissionDenied(c *gc.C) {
+	s.authorizer.Tag = names.NewUserTag("fred")
+	apiv5, err := application.NewAPIV5(
+		&s.backend,
+		s.authorizer,
+		&s.blockChecker,
+		func(application.Charm) *state.Charm {
+			return &state.Charm{}
+		},
+		func(application.ApplicationDeployer, application.DeployApplicationParams) (application.Application, error) {
+			return nil, nil
+		},
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	api := &application.APIv8{&application.APIv7{&application.APIv6{apiv5}}}
+	_, err = api.UnitsInfo(params.Entities{Entities: []params.Entity{{Tag: "unit-postgresql-0"}}})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+	s.backend.CheckNoCalls(c)
+}
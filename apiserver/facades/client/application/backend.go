@@ -42,6 +42,7 @@ type Backend interface {
 	ModelTag() names.ModelTag
 	ModelType() state.ModelType
 	Unit(string) (Unit, error)
+	ApplicationLeaders() (map[string]string, error)
 	SaveController(info crossmodel.ControllerInfo, modelUUID string) (ExternalController, error)
 	ControllerTag() names.ControllerTag
 	Resources() (Resources, error)
@@ -74,12 +75,14 @@ type Application interface {
 	DestroyOperation() *state.DestroyApplicationOperation
 	Endpoints() ([]state.Endpoint, error)
 	IsPrincipal() bool
+	Relations() ([]Relation, error)
 	Series() string
 	SetCharm(state.SetCharmConfig) error
 	SetConstraints(constraints.Value) error
 	SetExposed() error
 	SetMetricCredentials([]byte) error
 	SetMinUnits(int) error
+	SetPlacement(string) error
 	UpdateApplicationSeries(string, bool) error
 	UpdateCharmConfig(charm.Settings) error
 	ApplicationConfig() (application.ConfigAttributes, error)
@@ -128,6 +131,23 @@ type Unit interface {
 
 	AssignWithPolicy(state.AssignmentPolicy) error
 	AssignWithPlacement(*instance.Placement) error
+
+	WorkloadVersion() (string, error)
+	AssignedMachineId() (string, error)
+	PublicAddress() (network.Address, error)
+	OpenedPorts() ([]network.PortRange, error)
+	StatusHistory(status.StatusHistoryFilter) ([]status.StatusInfo, error)
+	AgentHistory() status.StatusHistoryGetter
+	RelationsData() ([]UnitRelationData, error)
+}
+
+// UnitRelationData holds the settings a unit has set in
+// one of the relations it has joined.
+type UnitRelationData struct {
+	RelationId      int
+	Endpoint        string
+	RelatedEndpoint string
+	Settings        map[string]interface{}
 }
 
 // Model defines a subset of the functionality provided by the
@@ -348,6 +368,18 @@ func (a stateApplicationShim) AllUnits() ([]Unit, error) {
 	return out, nil
 }
 
+func (a stateApplicationShim) Relations() ([]Relation, error) {
+	relations, err := a.Application.Relations()
+	if err != nil {
+		return nil, err
+	}
+	out := make([]Relation, len(relations))
+	for i, r := range relations {
+		out[i] = stateRelationShim{r}
+	}
+	return out, nil
+}
+
 type stateCharmShim struct {
 	*state.Charm
 }
@@ -373,6 +405,39 @@ func (u stateUnitShim) AssignWithPlacement(placement *instance.Placement) error
 	return u.st.AssignUnitWithPlacement(u.Unit, placement)
 }
 
+func (u stateUnitShim) RelationsData() ([]UnitRelationData, error) {
+	relations, err := u.Unit.RelationsJoined()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	result := make([]UnitRelationData, len(relations))
+	for i, rel := range relations {
+		ep, err := rel.Endpoint(u.ApplicationName())
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		related, err := rel.RelatedEndpoints(u.ApplicationName())
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		ru, err := rel.Unit(u.Unit)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		settings, err := ru.Settings()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		result[i] = UnitRelationData{
+			RelationId:      rel.Id(),
+			Endpoint:        ep.Name,
+			RelatedEndpoint: related[0].String(),
+			Settings:        settings.Map(),
+		}
+	}
+	return result, nil
+}
+
 type Subnet interface {
 	CIDR() string
 	VLANTag() int
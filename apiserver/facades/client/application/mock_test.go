@@ -94,6 +94,7 @@ type mockApplication struct {
 	series      string
 	units       []mockUnit
 	addedUnit   mockUnit
+	relations   []application.Relation
 	config      coreapplication.ConfigAttributes
 }
 
@@ -189,6 +190,16 @@ func (a *mockApplication) SetExposed() error {
 	return a.NextErr()
 }
 
+func (a *mockApplication) Relations() ([]application.Relation, error) {
+	a.MethodCall(a, "Relations")
+	return a.relations, a.NextErr()
+}
+
+func (a *mockApplication) SetPlacement(placement string) error {
+	a.MethodCall(a, "SetPlacement", placement)
+	return a.NextErr()
+}
+
 type mockRemoteApplication struct {
 	jtesting.Stub
 	name           string
@@ -388,6 +399,11 @@ func (m *mockBackend) OfferConnectionForRelation(key string) (application.OfferC
 	return nil, errors.NotFoundf("offer connection for relation")
 }
 
+func (m *mockBackend) ApplicationLeaders() (map[string]string, error) {
+	m.MethodCall(m, "ApplicationLeaders")
+	return map[string]string{"postgresql": "postgresql/0"}, m.NextErr()
+}
+
 func (m *mockBackend) UnitStorageAttachments(tag names.UnitTag) ([]state.StorageAttachment, error) {
 	m.MethodCall(m, "UnitStorageAttachments", tag)
 	if err := m.NextErr(); err != nil {
@@ -621,6 +637,55 @@ func (u *mockUnit) AssignWithPlacement(placement *instance.Placement) error {
 	return u.NextErr()
 }
 
+func (u *mockUnit) Life() state.Life {
+	return state.Alive
+}
+
+func (u *mockUnit) WorkloadVersion() (string, error) {
+	u.MethodCall(u, "WorkloadVersion")
+	return "9.6", u.NextErr()
+}
+
+func (u *mockUnit) AssignedMachineId() (string, error) {
+	u.MethodCall(u, "AssignedMachineId")
+	return "0", u.NextErr()
+}
+
+func (u *mockUnit) PublicAddress() (network.Address, error) {
+	u.MethodCall(u, "PublicAddress")
+	return network.NewAddress("10.0.0.1"), u.NextErr()
+}
+
+func (u *mockUnit) OpenedPorts() ([]network.PortRange, error) {
+	u.MethodCall(u, "OpenedPorts")
+	return []network.PortRange{{FromPort: 5432, ToPort: 5432, Protocol: "tcp"}}, u.NextErr()
+}
+
+func (u *mockUnit) RelationsData() ([]application.UnitRelationData, error) {
+	u.MethodCall(u, "RelationsData")
+	return []application.UnitRelationData{{
+		RelationId:      123,
+		Endpoint:        "db",
+		RelatedEndpoint: "wordpress:db",
+		Settings:        map[string]interface{}{"user": "wordpress"},
+	}}, u.NextErr()
+}
+
+func (u *mockUnit) StatusHistory(filter status.StatusHistoryFilter) ([]status.StatusInfo, error) {
+	u.MethodCall(u, "StatusHistory", filter)
+	return []status.StatusInfo{{Status: status.Active, Message: "ready"}}, u.NextErr()
+}
+
+func (u *mockUnit) AgentHistory() status.StatusHistoryGetter {
+	return mockAgentHistory{}
+}
+
+type mockAgentHistory struct{}
+
+func (mockAgentHistory) StatusHistory(filter status.StatusHistoryFilter) ([]status.StatusInfo, error) {
+	return []status.StatusInfo{{Status: status.Idle}}, nil
+}
+
 type mockStorageAttachment struct {
 	state.StorageAttachment
 	jtesting.Stub
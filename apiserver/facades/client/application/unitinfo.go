@@ -0,0 +1,135 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package application
+
+import (
+	"github.com/juju/errors"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/status"
+)
+
+// unitStatusHistorySize is the number of workload and agent
+// status history entries reported for each unit.
+const unitStatusHistorySize = 10
+
+// UnitsInfo returns the detail of the specified units: the machine
+// each is on, its opened ports, leadership and storage, the relation
+// data it has set, and its recent status history.
+func (api *APIv8) UnitsInfo(args params.Entities) (params.UnitInfoResults, error) {
+	if err := api.checkCanRead(); err != nil {
+		return params.UnitInfoResults{}, errors.Trace(err)
+	}
+	leaders, err := api.backend.ApplicationLeaders()
+	if err != nil {
+		return params.UnitInfoResults{}, errors.Trace(err)
+	}
+	results := make([]params.UnitInfoResult, len(args.Entities))
+	for i, entity := range args.Entities {
+		info, err := api.unitInfo(entity.Tag, leaders)
+		if err != nil {
+			results[i].Error = common.ServerError(err)
+			continue
+		}
+		results[i].Result = info
+	}
+	return params.UnitInfoResults{Results: results}, nil
+}
+
+func (api *APIv8) unitInfo(tagString string, leaders map[string]string) (*params.UnitResult, error) {
+	tag, err := names.ParseUnitTag(tagString)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	unit, err := api.backend.Unit(tag.Id())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	applicationName, err := names.UnitApplication(tag.Id())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	result := &params.UnitResult{
+		Tag:    tag.String(),
+		Life:   string(unit.Life()),
+		Leader: leaders[applicationName] == tag.Id(),
+	}
+	if result.WorkloadVersion, err = unit.WorkloadVersion(); err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	// Units which are not yet assigned, or which are not deployed
+	// to machines at all, have no machine, address or ports.
+	machineId, err := unit.AssignedMachineId()
+	switch {
+	case errors.IsNotAssigned(err):
+	case err != nil:
+		return nil, errors.Trace(err)
+	default:
+		result.Machine = machineId
+		address, err := unit.PublicAddress()
+		if err != nil && !network.IsNoAddressError(err) {
+			return nil, errors.Trace(err)
+		}
+		result.PublicAddress = address.Value
+		ports, err := unit.OpenedPorts()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		for _, port := range ports {
+			result.OpenedPorts = append(result.OpenedPorts, port.String())
+		}
+	}
+
+	attachments, err := api.backend.UnitStorageAttachments(tag)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	for _, attachment := range attachments {
+		result.Storage = append(result.Storage, attachment.StorageInstance().Id())
+	}
+
+	relations, err := unit.RelationsData()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	for _, rel := range relations {
+		result.RelationData = append(result.RelationData, params.EndpointRelationData{
+			RelationId:      rel.RelationId,
+			Endpoint:        rel.Endpoint,
+			RelatedEndpoint: rel.RelatedEndpoint,
+			Data:            rel.Settings,
+		})
+	}
+
+	filter := status.StatusHistoryFilter{Size: unitStatusHistorySize}
+	workloadHistory, err := unit.StatusHistory(filter)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	result.WorkloadStatusHistory = detailedStatuses(workloadHistory, status.KindWorkload)
+	agentHistory, err := unit.AgentHistory().StatusHistory(filter)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	result.AgentStatusHistory = detailedStatuses(agentHistory, status.KindUnitAgent)
+	return result, nil
+}
+
+func detailedStatuses(history []status.StatusInfo, kind status.HistoryKind) []params.DetailedStatus {
+	var result []params.DetailedStatus
+	for _, info := range history {
+		result = append(result, params.DetailedStatus{
+			Status: string(info.Status),
+			Info:   info.Message,
+			Data:   info.Data,
+			Since:  info.Since,
+			Kind:   string(kind),
+		})
+	}
+	return result
+}
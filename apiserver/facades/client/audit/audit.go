@@ -0,0 +1,219 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package audit provides the Audit facade, which reports the
+// activity in a model recorded in the controller's audit log.
+package audit
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils/set"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/facade"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/core/auditlog"
+	"github.com/juju/juju/permission"
+	"github.com/juju/juju/state"
+)
+
+// API implements the Audit facade.
+type API struct {
+	backend Backend
+	auth    facade.Authorizer
+
+	// logDir is the directory holding the audit log
+	// of the controller running the API server.
+	logDir string
+}
+
+// NewFacade is used for API registration.
+func NewFacade(st *state.State, resources facade.Resources, auth facade.Authorizer) (*API, error) {
+	logDir, ok := resources.Get("logDir").(common.StringResource)
+	if !ok {
+		return nil, errors.New("log directory not available")
+	}
+	return NewAPI(NewStateBackend(st), auth, logDir.String())
+}
+
+// NewAPI creates a new instance of the Audit facade.
+func NewAPI(backend Backend, authorizer facade.Authorizer, logDir string) (*API, error) {
+	if !authorizer.AuthClient() {
+		return nil, common.ErrPerm
+	}
+	return &API{
+		backend: backend,
+		auth:    authorizer,
+		logDir:  logDir,
+	}, nil
+}
+
+// checkCanAudit checks that the user is an admin of the model or a
+// controller superuser.
+func (a *API) checkCanAudit() error {
+	isSuperuser, err := a.auth.HasPermission(permission.SuperuserAccess, a.backend.ControllerTag())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	isAdmin, err := a.auth.HasPermission(permission.AdminAccess, a.backend.ModelTag())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if !isSuperuser && !isAdmin {
+		return common.ErrPerm
+	}
+	return nil
+}
+
+// ModelActivity returns the commands that changed the model, as
+// recorded in the audit log of the controller running the API server,
+// most recent first. Calls to the methods excluded from the audit log
+// by the controller's audit-log-exclude-methods setting are omitted.
+func (a *API) ModelActivity(args params.ModelActivityArgs) (params.ModelActivityResults, error) {
+	var result params.ModelActivityResults
+	if err := a.checkCanAudit(); err != nil {
+		return result, errors.Trace(err)
+	}
+	var who string
+	if args.User != "" {
+		if !names.IsValidUser(args.User) {
+			return result, errors.NotValidf("user name %q", args.User)
+		}
+		who = names.NewUserTag(args.User).String()
+	}
+	cfg, err := a.backend.ControllerConfig()
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	if !cfg.AuditingEnabled() {
+		return result, errors.New("auditing is not enabled on this controller")
+	}
+
+	f, err := os.Open(filepath.Join(a.logDir, auditlog.LogFileName))
+	if os.IsNotExist(err) {
+		return result, nil
+	} else if err != nil {
+		return result, errors.Trace(err)
+	}
+	defer f.Close()
+
+	modelUUID := a.backend.ModelTag().Id()
+	activity, err := auditlog.ReadActivity(f, func(conv auditlog.Conversation) bool {
+		if conv.ModelUUID != modelUUID || (who != "" && conv.Who != who) {
+			return false
+		}
+		if args.Since != nil {
+			when, err := time.Parse(time.RFC3339, conv.When)
+			if err != nil || when.Before(*args.Since) {
+				return false
+			}
+		}
+		return true
+	})
+	if err != nil {
+		return result, errors.Annotate(err, "reading audit log")
+	}
+
+	exclude := cfg.AuditLogExcludeMethods()
+	for i := len(activity) - 1; i >= 0; i-- {
+		if args.Limit > 0 && len(result.Results) == args.Limit {
+			break
+		}
+		if entry, ok := modelActivity(activity[i], exclude); ok {
+			result.Results = append(result.Results, entry)
+		}
+	}
+	return result, nil
+}
+
+// modelActivity converts a conversation from the audit log, returning
+// false if all of the calls made in it are excluded.
+func modelActivity(activity *auditlog.Activity, exclude set.Strings) (params.ModelActivity, bool) {
+	conv := activity.Conversation
+	user := conv.Who
+	if tag, err := names.ParseUserTag(conv.Who); err == nil {
+		user = tag.Id()
+	}
+	entry := params.ModelActivity{
+		User:    user,
+		Command: conv.What,
+		When:    parseTime(conv.When),
+	}
+
+	errs := make(map[uint64][]string)
+	for _, response := range activity.Errors {
+		for _, err := range response.Errors {
+			errs[response.RequestID] = append(errs[response.RequestID], err.Message)
+		}
+	}
+	for _, req := range activity.Requests {
+		if exclude.Contains(req.Facade + "." + req.Method) {
+			continue
+		}
+		entry.Operations = append(entry.Operations, params.ModelActivityOperation{
+			Facade:   req.Facade,
+			Method:   req.Method,
+			Version:  req.Version,
+			When:     parseTime(req.When),
+			Entities: requestEntities(req.Args),
+			Errors:   errs[req.RequestID],
+		})
+	}
+	return entry, len(entry.Operations) > 0
+}
+
+// requestEntities returns the tags of the entities in the arguments
+// of an API call, as captured in the audit log.
+func requestEntities(args string) []string {
+	if args == "" {
+		return nil
+	}
+	var value interface{}
+	if err := json.Unmarshal([]byte(args), &value); err != nil {
+		return nil
+	}
+	var entities []string
+	seen := set.NewStrings()
+	var walk func(interface{})
+	walk = func(value interface{}) {
+		switch value := value.(type) {
+		case string:
+			if _, err := names.ParseTag(value); err == nil && !seen.Contains(value) {
+				seen.Add(value)
+				entities = append(entities, value)
+			}
+		case []interface{}:
+			for _, v := range value {
+				walk(v)
+			}
+		case map[string]interface{}:
+			// Walk the fields in order so that the
+			// entities are reported consistently.
+			keys := make([]string, 0, len(value))
+			for k := range value {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			for _, k := range keys {
+				walk(value[k])
+			}
+		}
+	}
+	walk(value)
+	return entities
+}
+
+func parseTime(value string) time.Time {
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}
+	}
+	return t.UTC()
+}
@@ -0,0 +1,184 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package audit_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"time"
+
+	gitjujutesting "github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/facades/client/audit"
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/controller"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type auditSuite struct {
+	gitjujutesting.IsolationSuite
+	backend    *mockBackend
+	authorizer apiservertesting.FakeAuthorizer
+	logDir     string
+}
+
+var _ = gc.Suite(&auditSuite{})
+
+const auditLog = `
+{"conversation":{"who":"user-fred","what":"juju deploy mysql","when":"2018-06-01T10:00:00Z","model-name":"admin/default","model-uuid":"` + coretesting.ModelTag.Id() + `","conversation-id":"0001","connection-id":"A1"}}
+{"request":{"conversation-id":"0001","connection-id":"A1","request-id":1,"when":"2018-06-01T10:00:01Z","facade":"Client","method":"FullStatus","version":1}}
+{"request":{"conversation-id":"0001","connection-id":"A1","request-id":2,"when":"2018-06-01T10:00:02Z","facade":"Application","method":"Deploy","version":6,"args":"{\"applications\":[{\"application\":\"mysql\",\"charm-url\":\"cs:mysql-1\"}]}"}}
+{"conversation":{"who":"user-mary","what":"juju add-unit mysql","when":"2018-06-01T11:00:00Z","model-name":"admin/other","model-uuid":"cafef00d","conversation-id":"0002","connection-id":"A2"}}
+{"request":{"conversation-id":"0002","connection-id":"A2","request-id":1,"when":"2018-06-01T11:00:01Z","facade":"Application","method":"AddUnits","version":6}}
+{"conversation":{"who":"user-mary","what":"juju remove-unit mysql/0","when":"2018-06-01T12:00:00Z","model-name":"admin/default","model-uuid":"` + coretesting.ModelTag.Id() + `","conversation-id":"0003","connection-id":"A3"}}
+{"request":{"conversation-id":"0003","connection-id":"A3","request-id":1,"when":"2018-06-01T12:00:01Z","facade":"Application","method":"DestroyUnit","version":6,"args":"{\"units\":[{\"unit-tag\":\"unit-mysql-0\"}]}"}}
+{"errors":{"conversation-id":"0003","connection-id":"A3","request-id":1,"when":"2018-06-01T12:00:02Z","errors":[{"message":"unit not found","code":"not found"}]}}
+{"conversation":{"who":"user-fred","what":"juju status","when":"2018-06-01T13:00:00Z","model-name":"admin/default","model-uuid":"` + coretesting.ModelTag.Id() + `","conversation-id":"0004","connection-id":"A4"}}
+{"request":{"conversation-id":"0004","connection-id":"A4","request-id":1,"when":"2018-06-01T13:00:01Z","facade":"Client","method":"FullStatus","version":1}}
+`
+
+var (
+	deployActivity = params.ModelActivity{
+		User:    "fred",
+		Command: "juju deploy mysql",
+		When:    time.Date(2018, 6, 1, 10, 0, 0, 0, time.UTC),
+		Operations: []params.ModelActivityOperation{{
+			Facade:  "Application",
+			Method:  "Deploy",
+			Version: 6,
+			When:    time.Date(2018, 6, 1, 10, 0, 2, 0, time.UTC),
+		}},
+	}
+	removeUnitActivity = params.ModelActivity{
+		User:    "mary",
+		Command: "juju remove-unit mysql/0",
+		When:    time.Date(2018, 6, 1, 12, 0, 0, 0, time.UTC),
+		Operations: []params.ModelActivityOperation{{
+			Facade:   "Application",
+			Method:   "DestroyUnit",
+			Version:  6,
+			When:     time.Date(2018, 6, 1, 12, 0, 1, 0, time.UTC),
+			Entities: []string{"unit-mysql-0"},
+			Errors:   []string{"unit not found"},
+		}},
+	}
+)
+
+func (s *auditSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.authorizer = apiservertesting.FakeAuthorizer{
+		Tag: names.NewUserTag("admin"),
+	}
+	s.backend = &mockBackend{
+		config: controller.Config{
+			controller.AuditingEnabled:        true,
+			controller.AuditLogExcludeMethods: []interface{}{"Client.FullStatus"},
+		},
+	}
+	s.logDir = c.MkDir()
+	err := ioutil.WriteFile(filepath.Join(s.logDir, "audit.log"), []byte(auditLog), 0600)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *auditSuite) newAPI(c *gc.C) *audit.API {
+	api, err := audit.NewAPI(s.backend, s.authorizer, s.logDir)
+	c.Assert(err, jc.ErrorIsNil)
+	return api
+}
+
+func (s *auditSuite) TestNewAPIRequiresClient(c *gc.C) {
+	s.authorizer.Tag = names.NewMachineTag("0")
+	_, err := audit.NewAPI(s.backend, s.authorizer, s.logDir)
+	c.Assert(err, gc.Equals, common.ErrPerm)
+}
+
+func (s *auditSuite) TestModelActivity(c *gc.C) {
+	result, err := s.newAPI(c).ModelActivity(params.ModelActivityArgs{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results, jc.DeepEquals, []params.ModelActivity{
+		removeUnitActivity,
+		deployActivity,
+	})
+}
+
+func (s *auditSuite) TestModelActivityUser(c *gc.C) {
+	result, err := s.newAPI(c).ModelActivity(params.ModelActivityArgs{User: "fred"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results, jc.DeepEquals, []params.ModelActivity{deployActivity})
+}
+
+func (s *auditSuite) TestModelActivityInvalidUser(c *gc.C) {
+	_, err := s.newAPI(c).ModelActivity(params.ModelActivityArgs{User: "not/valid"})
+	c.Assert(err, gc.ErrorMatches, `user name "not/valid" not valid`)
+}
+
+func (s *auditSuite) TestModelActivitySince(c *gc.C) {
+	since := time.Date(2018, 6, 1, 11, 0, 0, 0, time.UTC)
+	result, err := s.newAPI(c).ModelActivity(params.ModelActivityArgs{Since: &since})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results, jc.DeepEquals, []params.ModelActivity{removeUnitActivity})
+}
+
+func (s *auditSuite) TestModelActivityLimit(c *gc.C) {
+	result, err := s.newAPI(c).ModelActivity(params.ModelActivityArgs{Limit: 1})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results, jc.DeepEquals, []params.ModelActivity{removeUnitActivity})
+}
+
+func (s *auditSuite) TestModelActivityNoExcludedMethods(c *gc.C) {
+	s.backend.config[controller.AuditLogExcludeMethods] = []interface{}{}
+	result, err := s.newAPI(c).ModelActivity(params.ModelActivityArgs{User: "fred", Limit: 1})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results, gc.HasLen, 1)
+	c.Assert(result.Results[0].Command, gc.Equals, "juju status")
+	c.Assert(result.Results[0].Operations, gc.HasLen, 1)
+	c.Assert(result.Results[0].Operations[0].Method, gc.Equals, "FullStatus")
+}
+
+func (s *auditSuite) TestModelActivityNoAuditLog(c *gc.C) {
+	s.logDir = c.MkDir()
+	result, err := s.newAPI(c).ModelActivity(params.ModelActivityArgs{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results, gc.HasLen, 0)
+}
+
+func (s *auditSuite) TestModelActivityAuditingDisabled(c *gc.C) {
+	s.backend.config[controller.AuditingEnabled] = false
+	_, err := s.newAPI(c).ModelActivity(params.ModelActivityArgs{})
+	c.Assert(err, gc.ErrorMatches, "auditing is not enabled on this controller")
+}
+
+func (s *auditSuite) TestModelActivityRequiresAdmin(c *gc.C) {
+	s.authorizer.Tag = names.NewUserTag("write")
+	_, err := s.newAPI(c).ModelActivity(params.ModelActivityArgs{})
+	c.Assert(err, gc.Equals, common.ErrPerm)
+}
+
+func (s *auditSuite) TestModelActivitySuperuser(c *gc.C) {
+	s.authorizer.Tag = names.NewUserTag("superuser")
+	result, err := s.newAPI(c).ModelActivity(params.ModelActivityArgs{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results, gc.HasLen, 2)
+}
+
+type mockBackend struct {
+	config controller.Config
+}
+
+func (b *mockBackend) ControllerTag() names.ControllerTag {
+	return coretesting.ControllerTag
+}
+
+func (b *mockBackend) ModelTag() names.ModelTag {
+	return coretesting.ModelTag
+}
+
+func (b *mockBackend) ControllerConfig() (controller.Config, error) {
+	return b.config, nil
+}
@@ -0,0 +1,32 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package audit
+
+import (
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/controller"
+	"github.com/juju/juju/state"
+)
+
+// Backend contains the state.State methods used in this package,
+// allowing stubs to be created for testing.
+type Backend interface {
+	ControllerTag() names.ControllerTag
+	ModelTag() names.ModelTag
+	ControllerConfig() (controller.Config, error)
+}
+
+type stateShim struct {
+	*state.State
+}
+
+func (st stateShim) ModelTag() names.ModelTag {
+	return names.NewModelTag(st.State.ModelUUID())
+}
+
+// NewStateBackend creates a backend for the facade to use.
+func NewStateBackend(st *state.State) Backend {
+	return stateShim{st}
+}
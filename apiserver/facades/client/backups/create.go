@@ -20,46 +20,62 @@ func (a *API) Create(args params.BackupsCreateArgs) (p params.BackupsMetadataRes
 	backupsMethods, closer := newBackups(a.backend)
 	defer closer.Close()
 
-	session := a.backend.MongoSession().Copy()
+	meta, dbInfo, err := PrepareCreate(a.backend, a.paths, a.machineID, args)
+	if err != nil {
+		return p, errors.Trace(err)
+	}
+
+	err = backupsMethods.Create(meta, a.paths, dbInfo)
+	if err != nil {
+		return p, errors.Trace(err)
+	}
+
+	return ResultFromMetadata(meta), nil
+}
+
+// PrepareCreate checks that juju's state is ready to be backed up on
+// the controller machine with the given ID, and returns the metadata
+// and database details to create the backup with.
+func PrepareCreate(
+	backend Backend,
+	paths *backups.Paths,
+	machineID string,
+	args params.BackupsCreateArgs,
+) (*backups.Metadata, *backups.DBInfo, error) {
+	session := backend.MongoSession().Copy()
 	defer session.Close()
 
 	// Don't go if HA isn't ready.
-	err = waitUntilReady(session, 60)
+	err := waitUntilReady(session, 60)
 	if err != nil {
-		return p, errors.Annotatef(err, "HA not ready; try again later")
+		return nil, nil, errors.Annotatef(err, "HA not ready; try again later")
 	}
 
-	mgoInfo, err := mongoInfo(a.paths.DataDir, a.machineID)
+	mgoInfo, err := mongoInfo(paths.DataDir, machineID)
 	if err != nil {
-		return p, errors.Annotatef(err, "getting mongo info")
+		return nil, nil, errors.Annotatef(err, "getting mongo info")
 	}
-	v, err := a.backend.MongoVersion()
+	v, err := backend.MongoVersion()
 	if err != nil {
-		return p, errors.Annotatef(err, "discovering mongo version")
+		return nil, nil, errors.Annotatef(err, "discovering mongo version")
 	}
 	mongoVersion, err := mongo.NewVersion(v)
 	if err != nil {
-		return p, errors.Trace(err)
+		return nil, nil, errors.Trace(err)
 	}
 	dbInfo, err := backups.NewDBInfo(mgoInfo, session, mongoVersion)
 	if err != nil {
-		return p, errors.Trace(err)
+		return nil, nil, errors.Trace(err)
 	}
-	mSeries, err := a.backend.MachineSeries(a.machineID)
+	mSeries, err := backend.MachineSeries(machineID)
 	if err != nil {
-		return p, errors.Trace(err)
+		return nil, nil, errors.Trace(err)
 	}
 
-	meta, err := backups.NewMetadataState(a.backend, a.machineID, mSeries)
+	meta, err := backups.NewMetadataState(backend, machineID, mSeries)
 	if err != nil {
-		return p, errors.Trace(err)
+		return nil, nil, errors.Trace(err)
 	}
 	meta.Notes = args.Notes
-
-	err = backupsMethods.Create(meta, a.paths, dbInfo)
-	if err != nil {
-		return p, errors.Trace(err)
-	}
-
-	return ResultFromMetadata(meta), nil
+	return meta, dbInfo, nil
 }
@@ -41,6 +41,11 @@ func NewFacade(st *state.State, resources facade.Resources, authorizer facade.Au
 	return NewAPI(&stateShim{st, model}, resources, authorizer)
 }
 
+// NewBackend returns a Backend for the given controller model.
+func NewBackend(st *state.State, m *state.Model) Backend {
+	return &stateShim{st, m}
+}
+
 // ControllerTag disambiguates the ControllerTag method pending further
 // refactoring to separate model functionality from state functionality.
 func (s *stateShim) ControllerTag() names.ControllerTag {
@@ -959,11 +959,12 @@ func (context *statusContext) processApplication(application *state.Application)
 			return params.ApplicationStatus{Err: common.ServerError(err)}
 		}
 		if specStr != "" {
-			spec, err := caas.ParseContainerSpec(specStr)
+			spec, err := caas.ParsePodSpec(specStr)
 			if err != nil {
 				return params.ApplicationStatus{Err: common.ServerError(err)}
 			}
-			processedStatus.WorkloadVersion = fmt.Sprintf("%v", spec.ImageName)
+			// The first container runs the workload; any others are sidecars.
+			processedStatus.WorkloadVersion = fmt.Sprintf("%v", spec.Containers[0].ImageName)
 		}
 	}
 
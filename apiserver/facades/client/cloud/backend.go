@@ -8,6 +8,7 @@ import (
 
 	"github.com/juju/juju/cloud"
 	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/permission"
 	"github.com/juju/juju/state"
 )
 
@@ -20,7 +21,15 @@ type Backend interface {
 	CloudCredentials(user names.UserTag, cloudName string) (map[string]cloud.Credential, error)
 	UpdateCloudCredential(names.CloudCredentialTag, cloud.Credential) error
 	RemoveCloudCredential(names.CloudCredentialTag) error
+	CloudCredentialModels(names.CloudCredentialTag) ([]string, error)
+	RotateCloudCredential(oldTag, newTag names.CloudCredentialTag) error
 	AddCloud(cloud.Cloud) error
+	UpdateCloud(cloud.Cloud) error
+
+	GetCloudAccess(cloudName string, user names.UserTag) (permission.Access, error)
+	CreateCloudAccess(cloudName string, user names.UserTag, access permission.Access) error
+	UpdateCloudAccess(cloudName string, user names.UserTag, access permission.Access) error
+	RemoveCloudAccess(cloudName string, user names.UserTag) error
 }
 
 type stateShim struct {
@@ -7,6 +7,7 @@ package cloud
 
 import (
 	"github.com/juju/errors"
+	"github.com/juju/txn"
 	"gopkg.in/juju/names.v2"
 
 	"github.com/juju/juju/apiserver/common"
@@ -15,6 +16,8 @@ import (
 	"github.com/juju/juju/cloud"
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/permission"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/stateenvirons"
 )
 
 type CloudV1 interface {
@@ -32,6 +35,12 @@ type CloudV2 interface {
 	AddCredentials(args params.TaggedCredentials) (params.ErrorResults, error)
 }
 
+type CloudV3 interface {
+	UpdateCloud(args params.UpdateCloudArgs) (params.ErrorResults, error)
+	RotateCredentials(args params.RotateCredentialArgs) (params.ErrorResults, error)
+	ModifyCloudAccess(args params.ModifyCloudAccessRequest) (params.ErrorResults, error)
+}
+
 type CloudAPI struct {
 	backend                Backend
 	ctlrBackend            Backend
@@ -44,9 +53,30 @@ type CloudAPIV2 struct {
 	CloudAPI
 }
 
+type CloudAPIV3 struct {
+	CloudAPIV2
+	validateCredential CredentialValidator
+}
+
+// CloudAPIV3NoCAAS provides version 3 of the Cloud facade to
+// controllers without the CAAS feature flag. It hides AddCloud,
+// which version 3 inherits from the CAAS-only version 2.
+type CloudAPIV3NoCAAS struct {
+	CloudAPIV3
+}
+
+// AddCloud is not available without the CAAS feature flag.
+func (*CloudAPIV3NoCAAS) AddCloud(_, _ struct{}) {}
+
+// CredentialValidator checks that the cloud credential with the given
+// tag can be used to access the provider of the model with the given
+// UUID.
+type CredentialValidator func(modelUUID string, tag names.CloudCredentialTag) error
+
 var (
 	_ CloudV1 = (*CloudAPI)(nil)
 	_ CloudV2 = (*CloudAPIV2)(nil)
+	_ CloudV3 = (*CloudAPIV3)(nil)
 )
 
 // NewFacade provides the required signature for facade registration.
@@ -62,6 +92,59 @@ func NewFacadeV2(context facade.Context) (*CloudAPIV2, error) {
 	return NewCloudAPIV2(st, ctlrSt, context.Auth())
 }
 
+func NewFacadeV3(context facade.Context) (*CloudAPIV3, error) {
+	st := NewStateBackend(context.State())
+	ctlrSt := NewStateBackend(context.StatePool().SystemState())
+	validator := NewStateCredentialValidator(context.StatePool())
+	return NewCloudAPIV3(st, ctlrSt, context.Auth(), validator)
+}
+
+// NewFacadeV3NoCAAS provides the required signature for registering
+// version 3 of the facade without the CAAS feature flag.
+func NewFacadeV3NoCAAS(context facade.Context) (*CloudAPIV3NoCAAS, error) {
+	api, err := NewFacadeV3(context)
+	if err != nil {
+		return nil, err
+	}
+	return &CloudAPIV3NoCAAS{*api}, nil
+}
+
+// NewStateCredentialValidator returns a CredentialValidator that opens
+// each model's environ with the credential, and checks that the
+// provider's API can be reached with it. CAAS models are not checked.
+func NewStateCredentialValidator(pool *state.StatePool) CredentialValidator {
+	return func(modelUUID string, tag names.CloudCredentialTag) error {
+		st, release, err := pool.Get(modelUUID)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		defer release()
+		m, err := st.Model()
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if m.Type() != state.ModelTypeIAAS {
+			return nil
+		}
+		cfg, err := m.ModelConfig()
+		if err != nil {
+			return errors.Trace(err)
+		}
+		cloudSpec, err := stateenvirons.CloudSpec(st, m.Cloud(), m.CloudRegion(), tag)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		env, err := environs.New(environs.OpenParams{
+			Cloud:  cloudSpec,
+			Config: cfg,
+		})
+		if err != nil {
+			return errors.Trace(err)
+		}
+		return environs.CheckProviderAPI(env)
+	}
+}
+
 // NewCloudAPI creates a new API server endpoint for managing the controller's
 // cloud definition and cloud credentials.
 func NewCloudAPI(backend, ctlrBackend Backend, authorizer facade.Authorizer) (*CloudAPI, error) {
@@ -101,6 +184,21 @@ func NewCloudAPIV2(backend, ctlrBackend Backend, authorizer facade.Authorizer) (
 	}, nil
 }
 
+func NewCloudAPIV3(
+	backend, ctlrBackend Backend,
+	authorizer facade.Authorizer,
+	validateCredential CredentialValidator,
+) (*CloudAPIV3, error) {
+	cloudAPIV2, err := NewCloudAPIV2(backend, ctlrBackend, authorizer)
+	if err != nil {
+		return nil, err
+	}
+	return &CloudAPIV3{
+		CloudAPIV2:         *cloudAPIV2,
+		validateCredential: validateCredential,
+	}, nil
+}
+
 // Clouds returns the definitions of all clouds supported by the controller.
 func (api *CloudAPI) Clouds() (params.CloudsResult, error) {
 	var result params.CloudsResult
@@ -385,3 +483,161 @@ func (api *CloudAPIV2) AddCloud(cloudArgs params.AddCloudArgs) error {
 	}
 	return nil
 }
+
+// UpdateCloud replaces the definitions of existing clouds, such as to
+// make new regions available. Only controller superusers may do so.
+func (api *CloudAPIV3) UpdateCloud(args params.UpdateCloudArgs) (params.ErrorResults, error) {
+	isAdmin, err := api.authorizer.HasPermission(permission.SuperuserAccess, api.ctlrBackend.ControllerTag())
+	if err != nil && !errors.IsNotFound(err) {
+		return params.ErrorResults{}, errors.Trace(err)
+	}
+	if !isAdmin {
+		return params.ErrorResults{}, common.ErrPerm
+	}
+	results := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Clouds)),
+	}
+	for i, arg := range args.Clouds {
+		err := api.ctlrBackend.UpdateCloud(common.CloudFromParams(arg.Name, arg.Cloud))
+		results.Results[i].Error = common.ServerError(err)
+	}
+	return results, nil
+}
+
+// RotateCredentials switches all models using each old credential over
+// to the corresponding new one, and then removes the old credential.
+// The new credential must already have been uploaded, and must be
+// usable by every model that is switched to it.
+func (api *CloudAPIV3) RotateCredentials(args params.RotateCredentialArgs) (params.ErrorResults, error) {
+	results := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Credentials)),
+	}
+	authFunc, err := api.getCredentialsAuthFunc()
+	if err != nil {
+		return results, err
+	}
+	for i, arg := range args.Credentials {
+		err := api.rotateCredential(authFunc, arg)
+		results.Results[i].Error = common.ServerError(err)
+	}
+	return results, nil
+}
+
+func (api *CloudAPIV3) rotateCredential(authFunc common.AuthFunc, arg params.RotateCredentialArg) error {
+	oldTag, err := names.ParseCloudCredentialTag(arg.OldTag)
+	if err != nil {
+		return err
+	}
+	newTag, err := names.ParseCloudCredentialTag(arg.NewTag)
+	if err != nil {
+		return err
+	}
+	// NOTE(axw) if we add ACLs for cloud credentials, we'll need
+	// to change this auth check.
+	if !authFunc(oldTag.Owner()) || !authFunc(newTag.Owner()) {
+		return common.ErrPerm
+	}
+	modelUUIDs, err := api.backend.CloudCredentialModels(oldTag)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for _, modelUUID := range modelUUIDs {
+		if err := api.validateCredential(modelUUID, newTag); err != nil {
+			return errors.Annotatef(err, "validating credential %q for model %q", newTag.Id(), modelUUID)
+		}
+	}
+	return api.backend.RotateCloudCredential(oldTag, newTag)
+}
+
+// ModifyCloudAccess grants or revokes users' access to clouds. Only
+// controller superusers and admins of a cloud may change its access.
+func (api *CloudAPIV3) ModifyCloudAccess(args params.ModifyCloudAccessRequest) (params.ErrorResults, error) {
+	results := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Changes)),
+	}
+	if len(args.Changes) == 0 {
+		return results, nil
+	}
+	isControllerAdmin, err := api.authorizer.HasPermission(permission.SuperuserAccess, api.ctlrBackend.ControllerTag())
+	if err != nil && !errors.IsNotFound(err) {
+		return results, errors.Trace(err)
+	}
+	for i, arg := range args.Changes {
+		err := api.modifyOneCloudAccess(isControllerAdmin, arg)
+		results.Results[i].Error = common.ServerError(err)
+	}
+	return results, nil
+}
+
+func (api *CloudAPIV3) modifyOneCloudAccess(isControllerAdmin bool, arg params.ModifyCloudAccess) error {
+	cloudTag, err := names.ParseCloudTag(arg.CloudTag)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	canModifyCloud := isControllerAdmin
+	if !canModifyCloud {
+		canModifyCloud, err = api.authorizer.HasPermission(permission.AdminAccess, cloudTag)
+		if err != nil && !errors.IsNotFound(err) {
+			return errors.Trace(err)
+		}
+	}
+	if !canModifyCloud {
+		return common.ErrPerm
+	}
+
+	cloudAccess := permission.Access(arg.Access)
+	if err := permission.ValidateCloudAccess(cloudAccess); err != nil {
+		return errors.Annotate(err, "could not modify cloud access")
+	}
+	targetUserTag, err := names.ParseUserTag(arg.UserTag)
+	if err != nil {
+		return errors.Annotate(err, "could not modify cloud access")
+	}
+	switch arg.Action {
+	case params.GrantCloudAccess:
+		return api.grantCloudAccess(cloudTag.Id(), targetUserTag, cloudAccess)
+	case params.RevokeCloudAccess:
+		return api.revokeCloudAccess(cloudTag.Id(), targetUserTag, cloudAccess)
+	default:
+		return errors.Errorf("unknown action %q", arg.Action)
+	}
+}
+
+func (api *CloudAPIV3) grantCloudAccess(cloudName string, targetUserTag names.UserTag, access permission.Access) error {
+	err := api.ctlrBackend.CreateCloudAccess(cloudName, targetUserTag, access)
+	if errors.IsAlreadyExists(err) {
+		cloudAccess, err := api.ctlrBackend.GetCloudAccess(cloudName, targetUserTag)
+		if errors.IsNotFound(err) {
+			// Conflicts with prior check, must be inconsistent state.
+			err = txn.ErrExcessiveContention
+		}
+		if err != nil {
+			return errors.Annotate(err, "could not look up cloud access for user")
+		}
+
+		// Only set access if greater access is being granted.
+		if cloudAccess.EqualOrGreaterCloudAccessThan(access) {
+			return errors.Errorf("user already has %q access or greater", access)
+		}
+		if err = api.ctlrBackend.UpdateCloudAccess(cloudName, targetUserTag, access); err != nil {
+			return errors.Annotate(err, "could not set cloud access for user")
+		}
+		return nil
+	}
+	return errors.Annotate(err, "could not grant cloud access")
+}
+
+func (api *CloudAPIV3) revokeCloudAccess(cloudName string, targetUserTag names.UserTag, access permission.Access) error {
+	switch access {
+	case permission.AddModelAccess:
+		// Revoking add-model access removes all access.
+		err := api.ctlrBackend.RemoveCloudAccess(cloudName, targetUserTag)
+		return errors.Annotate(err, "could not revoke cloud access")
+	case permission.AdminAccess:
+		// Revoking admin access sets add-model.
+		err := api.ctlrBackend.UpdateCloudAccess(cloudName, targetUserTag, permission.AddModelAccess)
+		return errors.Annotate(err, "could not set cloud access to add-model")
+	default:
+		return errors.Errorf("don't know how to revoke %q access", access)
+	}
+}
@@ -10,10 +10,12 @@ import (
 	gc "gopkg.in/check.v1"
 	"gopkg.in/juju/names.v2"
 
+	"github.com/juju/juju/apiserver/common"
 	cloudfacade "github.com/juju/juju/apiserver/facades/client/cloud"
 	"github.com/juju/juju/apiserver/params"
 	apiservertesting "github.com/juju/juju/apiserver/testing"
 	"github.com/juju/juju/cloud"
+	"github.com/juju/juju/permission"
 	_ "github.com/juju/juju/provider/dummy"
 )
 
@@ -24,6 +26,8 @@ type cloudSuite struct {
 	authorizer  *apiservertesting.FakeAuthorizer
 	api         *cloudfacade.CloudAPI
 	apiv2       *cloudfacade.CloudAPIV2
+	apiv3       *cloudfacade.CloudAPIV3
+	validator   gitjujutesting.Stub
 }
 
 var _ = gc.Suite(&cloudSuite{})
@@ -62,6 +66,13 @@ func (s *cloudSuite) SetUpTest(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 	s.apiv2, err = cloudfacade.NewCloudAPIV2(s.backend, s.ctlrBackend, s.authorizer)
 	c.Assert(err, jc.ErrorIsNil)
+	s.validator = gitjujutesting.Stub{}
+	validateCredential := func(modelUUID string, tag names.CloudCredentialTag) error {
+		s.validator.MethodCall(s, "ValidateCredential", modelUUID, tag)
+		return s.validator.NextErr()
+	}
+	s.apiv3, err = cloudfacade.NewCloudAPIV3(s.backend, s.ctlrBackend, s.authorizer, validateCredential)
+	c.Assert(err, jc.ErrorIsNil)
 }
 
 func (s *cloudSuite) TestCloud(c *gc.C) {
@@ -328,10 +339,265 @@ func (s *cloudSuite) TestAddCredentialInV2(c *gc.C) {
 	c.Assert(results.Results[0].Error, gc.IsNil)
 }
 
+func (s *cloudSuite) TestUpdateCloudInV3(c *gc.C) {
+	s.ctlrBackend.SetErrors(nil, errors.NotFoundf("cloud %q", "missing"))
+	results, err := s.apiv3.UpdateCloud(params.UpdateCloudArgs{Clouds: []params.AddCloudArgs{{
+		Name: "dummy",
+		Cloud: params.Cloud{
+			Type:      "dummy",
+			AuthTypes: []string{"empty", "userpass"},
+			Regions: []params.CloudRegion{
+				{Name: "nether", Endpoint: "endpoint"},
+				{Name: "aether", Endpoint: "aether-endpoint"},
+			},
+		},
+	}, {
+		Name: "missing",
+		Cloud: params.Cloud{
+			Type:      "dummy",
+			AuthTypes: []string{"empty"},
+		},
+	}}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 2)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(results.Results[1].Error, jc.Satisfies, params.IsCodeNotFound)
+	s.ctlrBackend.CheckCallNames(c, "ControllerTag", "UpdateCloud", "UpdateCloud")
+	s.ctlrBackend.CheckCall(c, 1, "UpdateCloud", cloud.Cloud{
+		Name:      "dummy",
+		Type:      "dummy",
+		AuthTypes: []cloud.AuthType{cloud.EmptyAuthType, cloud.UserPassAuthType},
+		Regions: []cloud.Region{
+			{Name: "nether", Endpoint: "endpoint"},
+			{Name: "aether", Endpoint: "aether-endpoint"},
+		},
+	})
+	s.backend.CheckNoCalls(c)
+}
+
+func (s *cloudSuite) TestUpdateCloudNonAdmin(c *gc.C) {
+	s.authorizer.Tag = names.NewUserTag("bruce")
+	_, err := s.apiv3.UpdateCloud(params.UpdateCloudArgs{Clouds: []params.AddCloudArgs{{
+		Name: "dummy",
+		Cloud: params.Cloud{
+			Type:      "dummy",
+			AuthTypes: []string{"empty"},
+		},
+	}}})
+	c.Assert(err, gc.Equals, common.ErrPerm)
+	for _, call := range s.ctlrBackend.Calls() {
+		c.Assert(call.FuncName, gc.Not(gc.Equals), "UpdateCloud")
+	}
+}
+
+func (s *cloudSuite) TestRotateCredentials(c *gc.C) {
+	s.backend.credentialModels = []string{"model-1", "model-2"}
+	oldTag := names.NewCloudCredentialTag("meep/bruce/one")
+	newTag := names.NewCloudCredentialTag("meep/bruce/two")
+	results, err := s.apiv3.RotateCredentials(params.RotateCredentialArgs{
+		Credentials: []params.RotateCredentialArg{{
+			OldTag: oldTag.String(),
+			NewTag: newTag.String(),
+		}, {
+			OldTag: "machine-0",
+			NewTag: newTag.String(),
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 2)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(results.Results[1].Error, jc.DeepEquals, &params.Error{
+		Message: `"machine-0" is not a valid cloudcred tag`,
+	})
+	s.validator.CheckCalls(c, []gitjujutesting.StubCall{
+		{"ValidateCredential", []interface{}{"model-1", newTag}},
+		{"ValidateCredential", []interface{}{"model-2", newTag}},
+	})
+	s.backend.CheckCallNames(c, "ControllerTag", "CloudCredentialModels", "RotateCloudCredential")
+	s.backend.CheckCall(c, 1, "CloudCredentialModels", oldTag)
+	s.backend.CheckCall(c, 2, "RotateCloudCredential", oldTag, newTag)
+}
+
+func (s *cloudSuite) TestRotateCredentialsValidationFails(c *gc.C) {
+	s.backend.credentialModels = []string{"model-1"}
+	s.validator.SetErrors(errors.New("unauthorized"))
+	results, err := s.apiv3.RotateCredentials(params.RotateCredentialArgs{
+		Credentials: []params.RotateCredentialArg{{
+			OldTag: "cloudcred-meep_bruce_one",
+			NewTag: "cloudcred-meep_bruce_two",
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	c.Assert(results.Results[0].Error, gc.ErrorMatches,
+		`validating credential "meep/bruce/two" for model "model-1": unauthorized`)
+	s.backend.CheckCallNames(c, "ControllerTag", "CloudCredentialModels")
+}
+
+func (s *cloudSuite) TestRotateCredentialsPermissionDenied(c *gc.C) {
+	s.authorizer.Tag = names.NewUserTag("bruce")
+	results, err := s.apiv3.RotateCredentials(params.RotateCredentialArgs{
+		Credentials: []params.RotateCredentialArg{{
+			OldTag: "cloudcred-meep_bruce_one",
+			NewTag: "cloudcred-meep_julia_two",
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	c.Assert(results.Results[0].Error, jc.DeepEquals, &params.Error{
+		Message: "permission denied", Code: params.CodeUnauthorized,
+	})
+	s.validator.CheckNoCalls(c)
+	s.backend.CheckCallNames(c, "ControllerTag")
+}
+
+func (s *cloudSuite) TestModifyCloudAccessGrant(c *gc.C) {
+	results, err := s.apiv3.ModifyCloudAccess(params.ModifyCloudAccessRequest{
+		Changes: []params.ModifyCloudAccess{{
+			UserTag:  "user-bruce",
+			CloudTag: "cloud-dummy",
+			Action:   params.GrantCloudAccess,
+			Access:   "add-model",
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	s.ctlrBackend.CheckCallNames(c, "ControllerTag", "CreateCloudAccess")
+	s.ctlrBackend.CheckCall(c, 1, "CreateCloudAccess", "dummy", names.NewUserTag("bruce"), permission.AddModelAccess)
+}
+
+func (s *cloudSuite) TestModifyCloudAccessGrantExisting(c *gc.C) {
+	s.ctlrBackend.SetErrors(errors.AlreadyExistsf("permission"))
+	s.ctlrBackend.cloudAccess = permission.AddModelAccess
+	results, err := s.apiv3.ModifyCloudAccess(params.ModifyCloudAccessRequest{
+		Changes: []params.ModifyCloudAccess{{
+			UserTag:  "user-bruce",
+			CloudTag: "cloud-dummy",
+			Action:   params.GrantCloudAccess,
+			Access:   "admin",
+		}, {
+			UserTag:  "user-bruce",
+			CloudTag: "cloud-dummy",
+			Action:   params.GrantCloudAccess,
+			Access:   "add-model",
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 2)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(results.Results[1].Error, gc.IsNil)
+	s.ctlrBackend.CheckCallNames(c, "ControllerTag", "CreateCloudAccess", "GetCloudAccess", "UpdateCloudAccess", "CreateCloudAccess")
+	s.ctlrBackend.CheckCall(c, 3, "UpdateCloudAccess", "dummy", names.NewUserTag("bruce"), permission.AdminAccess)
+}
+
+func (s *cloudSuite) TestModifyCloudAccessGrantAlreadyGranted(c *gc.C) {
+	s.ctlrBackend.SetErrors(errors.AlreadyExistsf("permission"))
+	s.ctlrBackend.cloudAccess = permission.AdminAccess
+	results, err := s.apiv3.ModifyCloudAccess(params.ModifyCloudAccessRequest{
+		Changes: []params.ModifyCloudAccess{{
+			UserTag:  "user-bruce",
+			CloudTag: "cloud-dummy",
+			Action:   params.GrantCloudAccess,
+			Access:   "add-model",
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	c.Assert(results.Results[0].Error, gc.ErrorMatches, `user already has "add-model" access or greater`)
+}
+
+func (s *cloudSuite) TestModifyCloudAccessRevoke(c *gc.C) {
+	results, err := s.apiv3.ModifyCloudAccess(params.ModifyCloudAccessRequest{
+		Changes: []params.ModifyCloudAccess{{
+			UserTag:  "user-bruce",
+			CloudTag: "cloud-dummy",
+			Action:   params.RevokeCloudAccess,
+			Access:   "admin",
+		}, {
+			UserTag:  "user-julia",
+			CloudTag: "cloud-dummy",
+			Action:   params.RevokeCloudAccess,
+			Access:   "add-model",
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 2)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(results.Results[1].Error, gc.IsNil)
+	s.ctlrBackend.CheckCallNames(c, "ControllerTag", "UpdateCloudAccess", "RemoveCloudAccess")
+	s.ctlrBackend.CheckCall(c, 1, "UpdateCloudAccess", "dummy", names.NewUserTag("bruce"), permission.AddModelAccess)
+	s.ctlrBackend.CheckCall(c, 2, "RemoveCloudAccess", "dummy", names.NewUserTag("julia"))
+}
+
+func (s *cloudSuite) TestModifyCloudAccessInvalid(c *gc.C) {
+	results, err := s.apiv3.ModifyCloudAccess(params.ModifyCloudAccessRequest{
+		Changes: []params.ModifyCloudAccess{{
+			UserTag:  "user-bruce",
+			CloudTag: "cloud-dummy",
+			Action:   params.GrantCloudAccess,
+			Access:   "write",
+		}, {
+			UserTag:  "user-bruce",
+			CloudTag: "machine-0",
+			Action:   params.GrantCloudAccess,
+			Access:   "add-model",
+		}, {
+			UserTag:  "user-bruce",
+			CloudTag: "cloud-dummy",
+			Action:   "dance",
+			Access:   "add-model",
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 3)
+	c.Assert(results.Results[0].Error, gc.ErrorMatches, `could not modify cloud access: "write" cloud access not valid`)
+	c.Assert(results.Results[1].Error, gc.ErrorMatches, `"machine-0" is not a valid cloud tag`)
+	c.Assert(results.Results[2].Error, gc.ErrorMatches, `unknown action "dance"`)
+	s.ctlrBackend.CheckCallNames(c, "ControllerTag")
+}
+
+func (s *cloudSuite) TestModifyCloudAccessPermissionDenied(c *gc.C) {
+	s.authorizer.Tag = names.NewUserTag("bruce")
+	results, err := s.apiv3.ModifyCloudAccess(params.ModifyCloudAccessRequest{
+		Changes: []params.ModifyCloudAccess{{
+			UserTag:  "user-julia",
+			CloudTag: "cloud-dummy",
+			Action:   params.GrantCloudAccess,
+			Access:   "add-model",
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	c.Assert(results.Results[0].Error, jc.DeepEquals, &params.Error{
+		Message: "permission denied", Code: params.CodeUnauthorized,
+	})
+	s.ctlrBackend.CheckCallNames(c, "ControllerTag")
+}
+
+func (s *cloudSuite) TestModifyCloudAccessCloudAdmin(c *gc.C) {
+	s.authorizer.Tag = names.NewUserTag("admin-cloud-dummy")
+	results, err := s.apiv3.ModifyCloudAccess(params.ModifyCloudAccessRequest{
+		Changes: []params.ModifyCloudAccess{{
+			UserTag:  "user-julia",
+			CloudTag: "cloud-dummy",
+			Action:   params.GrantCloudAccess,
+			Access:   "add-model",
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	s.ctlrBackend.CheckCallNames(c, "ControllerTag", "CreateCloudAccess")
+}
+
 type mockBackend struct {
 	gitjujutesting.Stub
 	cloud cloud.Cloud
 	creds map[string]cloud.Credential
+
+	credentialModels []string
+	cloudAccess      permission.Access
 }
 
 func (st *mockBackend) ControllerTag() names.ControllerTag {
@@ -373,11 +639,46 @@ func (st *mockBackend) RemoveCloudCredential(tag names.CloudCredentialTag) error
 	return st.NextErr()
 }
 
+func (st *mockBackend) CloudCredentialModels(tag names.CloudCredentialTag) ([]string, error) {
+	st.MethodCall(st, "CloudCredentialModels", tag)
+	return st.credentialModels, st.NextErr()
+}
+
+func (st *mockBackend) RotateCloudCredential(oldTag, newTag names.CloudCredentialTag) error {
+	st.MethodCall(st, "RotateCloudCredential", oldTag, newTag)
+	return st.NextErr()
+}
+
 func (st *mockBackend) AddCloud(cloud cloud.Cloud) error {
 	st.MethodCall(st, "AddCloud", cloud)
 	return st.NextErr()
 }
 
+func (st *mockBackend) UpdateCloud(cloud cloud.Cloud) error {
+	st.MethodCall(st, "UpdateCloud", cloud)
+	return st.NextErr()
+}
+
+func (st *mockBackend) GetCloudAccess(cloudName string, user names.UserTag) (permission.Access, error) {
+	st.MethodCall(st, "GetCloudAccess", cloudName, user)
+	return st.cloudAccess, st.NextErr()
+}
+
+func (st *mockBackend) CreateCloudAccess(cloudName string, user names.UserTag, access permission.Access) error {
+	st.MethodCall(st, "CreateCloudAccess", cloudName, user, access)
+	return st.NextErr()
+}
+
+func (st *mockBackend) UpdateCloudAccess(cloudName string, user names.UserTag, access permission.Access) error {
+	st.MethodCall(st, "UpdateCloudAccess", cloudName, user, access)
+	return st.NextErr()
+}
+
+func (st *mockBackend) RemoveCloudAccess(cloudName string, user names.UserTag) error {
+	st.MethodCall(st, "RemoveCloudAccess", cloudName, user)
+	return st.NextErr()
+}
+
 type mockModel struct {
 	cloud              string
 	cloudRegion        string
@@ -42,13 +42,19 @@ type ControllerAPI struct {
 	resources  facade.Resources
 }
 
+// ControllerAPIv4 provides the v4 Controller API. It lacks the
+// PauseMigration, ResumeMigration and AbortMigration methods.
+type ControllerAPIv4 struct {
+	*ControllerAPI
+}
+
 // ControllerAPIv3 provides the v3 Controller API.
 type ControllerAPIv3 struct {
-	*ControllerAPI
+	*ControllerAPIv4
 }
 
-// NewControllerAPIv4 creates a new ControllerAPIv4.
-func NewControllerAPIv4(ctx facade.Context) (*ControllerAPI, error) {
+// NewControllerAPIv5 creates a new ControllerAPIv5.
+func NewControllerAPIv5(ctx facade.Context) (*ControllerAPI, error) {
 	st := ctx.State()
 	authorizer := ctx.Auth()
 	pool := ctx.StatePool()
@@ -62,6 +68,15 @@ func NewControllerAPIv4(ctx facade.Context) (*ControllerAPI, error) {
 	)
 }
 
+// NewControllerAPIv4 creates a new ControllerAPIv4.
+func NewControllerAPIv4(ctx facade.Context) (*ControllerAPIv4, error) {
+	v5, err := NewControllerAPIv5(ctx)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &ControllerAPIv4{v5}, nil
+}
+
 // NewControllerAPIv3 creates a new ControllerAPIv3.
 func NewControllerAPIv3(ctx facade.Context) (*ControllerAPIv3, error) {
 	v4, err := NewControllerAPIv4(ctx)
@@ -460,6 +475,90 @@ func (c *ControllerAPI) initiateOneMigration(spec params.MigrationSpec) (string,
 	return mig.Id(), nil
 }
 
+// PauseMigration pauses the active migrations of the given models.
+// A paused migration does not move on to its next phase until it is
+// resumed or aborted.
+func (c *ControllerAPI) PauseMigration(args params.Entities) (params.ErrorResults, error) {
+	return c.changeMigrations(args, func(mig state.ModelMigration) error {
+		return mig.Pause()
+	})
+}
+
+// ResumeMigration resumes the paused migrations of the given models.
+func (c *ControllerAPI) ResumeMigration(args params.Entities) (params.ErrorResults, error) {
+	return c.changeMigrations(args, func(mig state.ModelMigration) error {
+		return mig.Resume()
+	})
+}
+
+// AbortMigration aborts the active migrations of the given models,
+// rolling them back to this controller. A migration can only be
+// aborted before its model is activated in the target controller.
+func (c *ControllerAPI) AbortMigration(args params.Entities) (params.ErrorResults, error) {
+	return c.changeMigrations(args, func(mig state.ModelMigration) error {
+		phase, err := mig.Phase()
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if !phase.CanTransitionTo(coremigration.ABORT) {
+			return errors.Errorf("migration cannot be aborted in phase %s", phase)
+		}
+		return mig.SetPhase(coremigration.ABORT)
+	})
+}
+
+func (c *ControllerAPI) changeMigrations(
+	args params.Entities,
+	change func(state.ModelMigration) error,
+) (params.ErrorResults, error) {
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Entities)),
+	}
+	if err := c.checkHasAdmin(); err != nil {
+		return result, errors.Trace(err)
+	}
+	for i, entity := range args.Entities {
+		err := c.changeOneMigration(entity.Tag, change)
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+func (c *ControllerAPI) changeOneMigration(tag string, change func(state.ModelMigration) error) error {
+	modelTag, err := names.ParseModelTag(tag)
+	if err != nil {
+		return errors.Annotate(err, "model tag")
+	}
+	if modelExists, err := c.state.ModelExists(modelTag.Id()); err != nil {
+		return errors.Annotate(err, "reading model")
+	} else if !modelExists {
+		return errors.NotFoundf("model")
+	}
+
+	hostedState, release, err := c.statePool.Get(modelTag.Id())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer release()
+
+	mig, err := hostedState.LatestMigration()
+	if errors.IsNotFound(err) {
+		return errors.NotFoundf("migration for model %q", modelTag.Id())
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(change(mig))
+}
+
+// PauseMigration isn't on the v4 API.
+func (c *ControllerAPIv4) PauseMigration(_, _ struct{}) {}
+
+// ResumeMigration isn't on the v4 API.
+func (c *ControllerAPIv4) ResumeMigration(_, _ struct{}) {}
+
+// AbortMigration isn't on the v4 API.
+func (c *ControllerAPIv4) AbortMigration(_, _ struct{}) {}
+
 // ModifyControllerAccess changes the model access granted to users.
 func (c *ControllerAPI) ModifyControllerAccess(args params.ModifyControllerAccessRequest) (params.ErrorResults, error) {
 	result := params.ErrorResults{
@@ -23,6 +23,7 @@ import (
 	"github.com/juju/juju/apiserver/params"
 	apiservertesting "github.com/juju/juju/apiserver/testing"
 	"github.com/juju/juju/cloud"
+	coremigration "github.com/juju/juju/core/migration"
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/permission"
@@ -59,7 +60,7 @@ func (s *controllerSuite) SetUpTest(c *gc.C) {
 		AdminTag: s.Owner,
 	}
 
-	controller, err := controller.NewControllerAPIv4(
+	controller, err := controller.NewControllerAPIv5(
 		facadetest.Context{
 			State_:     s.State,
 			StatePool_: s.StatePool,
@@ -76,7 +77,7 @@ func (s *controllerSuite) TestNewAPIRefusesNonClient(c *gc.C) {
 	anAuthoriser := apiservertesting.FakeAuthorizer{
 		Tag: names.NewUnitTag("mysql/0"),
 	}
-	endPoint, err := controller.NewControllerAPIv4(
+	endPoint, err := controller.NewControllerAPIv5(
 		facadetest.Context{
 			State_:     s.State,
 			Resources_: s.resources,
@@ -256,7 +257,7 @@ func (s *controllerSuite) TestModelConfigFromNonController(c *gc.C) {
 		Tag:      s.Owner,
 		AdminTag: s.Owner,
 	}
-	controller, err := controller.NewControllerAPIv4(
+	controller, err := controller.NewControllerAPIv5(
 		facadetest.Context{
 			State_:     st,
 			StatePool_: s.StatePool,
@@ -286,7 +287,7 @@ func (s *controllerSuite) TestControllerConfigFromNonController(c *gc.C) {
 	defer st.Close()
 
 	authorizer := &apiservertesting.FakeAuthorizer{Tag: s.Owner}
-	controller, err := controller.NewControllerAPIv4(
+	controller, err := controller.NewControllerAPIv5(
 		facadetest.Context{
 			State_:     st,
 			Resources_: common.NewResources(),
@@ -563,6 +564,98 @@ func (s *controllerSuite) TestInitiateMigrationPrecheckFail(c *gc.C) {
 	c.Check(active, jc.IsFalse)
 }
 
+func (s *controllerSuite) startMigration(c *gc.C, st *state.State) state.ModelMigration {
+	mig, err := st.CreateMigration(state.MigrationSpec{
+		InitiatedBy: s.Owner,
+		TargetInfo: coremigration.TargetInfo{
+			ControllerTag: names.NewControllerTag(utils.MustNewUUID().String()),
+			Addrs:         []string{"1.1.1.1:1111"},
+			CACert:        "cert",
+			AuthTag:       names.NewUserTag("admin"),
+			Password:      "secret",
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	return mig
+}
+
+func (s *controllerSuite) TestPauseAndResumeMigration(c *gc.C) {
+	st := s.Factory.MakeModel(c, nil)
+	defer st.Close()
+	mig := s.startMigration(c, st)
+	args := params.Entities{Entities: []params.Entity{
+		{Tag: names.NewModelTag(st.ModelUUID()).String()},
+	}}
+
+	out, err := s.controller.PauseMigration(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(out.Results, gc.HasLen, 1)
+	c.Assert(out.Results[0].Error, gc.IsNil)
+	c.Assert(mig.Refresh(), jc.ErrorIsNil)
+	c.Check(mig.Paused(), jc.IsTrue)
+
+	out, err = s.controller.ResumeMigration(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(out.Results, gc.HasLen, 1)
+	c.Assert(out.Results[0].Error, gc.IsNil)
+	c.Assert(mig.Refresh(), jc.ErrorIsNil)
+	c.Check(mig.Paused(), jc.IsFalse)
+}
+
+func (s *controllerSuite) TestAbortMigration(c *gc.C) {
+	st := s.Factory.MakeModel(c, nil)
+	defer st.Close()
+	mig := s.startMigration(c, st)
+	c.Assert(mig.Pause(), jc.ErrorIsNil)
+
+	out, err := s.controller.AbortMigration(params.Entities{Entities: []params.Entity{
+		{Tag: names.NewModelTag(st.ModelUUID()).String()},
+		{Tag: randomModelTag()},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(out.Results, gc.HasLen, 2)
+	c.Check(out.Results[0].Error, gc.IsNil)
+	c.Check(out.Results[1].Error, gc.ErrorMatches, "model not found")
+
+	c.Assert(mig.Refresh(), jc.ErrorIsNil)
+	phase, err := mig.Phase()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(phase, gc.Equals, coremigration.ABORT)
+	c.Check(mig.Paused(), jc.IsFalse)
+}
+
+func (s *controllerSuite) TestAbortMigrationTooLate(c *gc.C) {
+	st := s.Factory.MakeModel(c, nil)
+	defer st.Close()
+	mig := s.startMigration(c, st)
+	for _, phase := range []coremigration.Phase{
+		coremigration.IMPORT,
+		coremigration.VALIDATION,
+		coremigration.SUCCESS,
+	} {
+		c.Assert(mig.SetPhase(phase), jc.ErrorIsNil)
+	}
+
+	out, err := s.controller.AbortMigration(params.Entities{Entities: []params.Entity{
+		{Tag: names.NewModelTag(st.ModelUUID()).String()},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(out.Results, gc.HasLen, 1)
+	c.Check(out.Results[0].Error, gc.ErrorMatches, "migration cannot be aborted in phase SUCCESS")
+}
+
+func (s *controllerSuite) TestPauseMigrationNoMigration(c *gc.C) {
+	st := s.Factory.MakeModel(c, nil)
+	defer st.Close()
+
+	out, err := s.controller.PauseMigration(params.Entities{Entities: []params.Entity{
+		{Tag: names.NewModelTag(st.ModelUUID()).String()},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(out.Results, gc.HasLen, 1)
+	c.Check(out.Results[0].Error, gc.ErrorMatches, `migration for model ".+" not found`)
+}
+
 func randomControllerTag() string {
 	uuid := utils.MustNewUUID().String()
 	return names.NewControllerTag(uuid).String()
@@ -802,7 +895,7 @@ func (s *controllerSuite) TestGetControllerAccessPermissions(c *gc.C) {
 	anAuthoriser := apiservertesting.FakeAuthorizer{
 		Tag: user.Tag(),
 	}
-	endpoint, err := controller.NewControllerAPIv4(
+	endpoint, err := controller.NewControllerAPIv5(
 		facadetest.Context{
 			State_:     s.State,
 			Resources_: s.resources,
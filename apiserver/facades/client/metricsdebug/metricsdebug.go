@@ -40,6 +40,10 @@ type MetricsDebug interface {
 	// GetMetrics returns all metrics stored by the state server.
 	GetMetrics(arg params.Entities) (params.MetricResults, error)
 
+	// GetMetricsHistory returns every value of every metric stored by
+	// the state server, rather than only the latest ones.
+	GetMetricsHistory(arg params.Entities) (params.MetricResults, error)
+
 	// SetMeterStatus will set the meter status on the given entity tag.
 	SetMeterStatus(params.MeterStatusParams) (params.ErrorResults, error)
 }
@@ -50,6 +54,12 @@ type MetricsDebugAPI struct {
 	state metricsDebug
 }
 
+// MetricsDebugAPIV2 implements version 2 of the metricsdebug
+// API end point, which lacks GetMetricsHistory.
+type MetricsDebugAPIV2 struct {
+	*MetricsDebugAPI
+}
+
 var _ MetricsDebug = (*MetricsDebugAPI)(nil)
 
 // NewMetricsDebugAPI creates a new API endpoint for calling metrics debug functions.
@@ -67,8 +77,38 @@ func NewMetricsDebugAPI(
 	}, nil
 }
 
+// NewMetricsDebugAPIV2 creates a new API endpoint for calling
+// version 2 metrics debug functions.
+func NewMetricsDebugAPIV2(
+	st *state.State,
+	resources facade.Resources,
+	authorizer facade.Authorizer,
+) (*MetricsDebugAPIV2, error) {
+	api, err := NewMetricsDebugAPI(st, resources, authorizer)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &MetricsDebugAPIV2{api}, nil
+}
+
+// GetMetricsHistory is not available on version 2 of the API.
+func (*MetricsDebugAPIV2) GetMetricsHistory(_, _ struct{}) {}
+
 // GetMetrics returns all metrics stored by the state server.
 func (api *MetricsDebugAPI) GetMetrics(args params.Entities) (params.MetricResults, error) {
+	return api.getMetrics(args, filterLastValuePerKeyPerUnit)
+}
+
+// GetMetricsHistory returns all values of the metrics stored by the
+// state server, ordered by unit, metric key and time.
+func (api *MetricsDebugAPI) GetMetricsHistory(args params.Entities) (params.MetricResults, error) {
+	return api.getMetrics(args, allValues)
+}
+
+func (api *MetricsDebugAPI) getMetrics(
+	args params.Entities,
+	filter func([]state.MetricBatch) []params.MetricResult,
+) (params.MetricResults, error) {
 	results := params.MetricResults{
 		Results: make([]params.EntityMetrics, len(args.Entities)),
 	}
@@ -79,7 +119,7 @@ func (api *MetricsDebugAPI) GetMetrics(args params.Entities) (params.MetricResul
 		}
 		return params.MetricResults{
 			Results: []params.EntityMetrics{{
-				Metrics: api.filterLastValuePerKeyPerUnit(batches),
+				Metrics: filter(batches),
 			}},
 		}, nil
 	}
@@ -109,7 +149,7 @@ func (api *MetricsDebugAPI) GetMetrics(args params.Entities) (params.MetricResul
 			err := errors.Errorf("invalid tag %v", arg.Tag)
 			results.Results[i].Error = common.ServerError(err)
 		}
-		results.Results[i].Metrics = api.filterLastValuePerKeyPerUnit(batches)
+		results.Results[i].Metrics = filter(batches)
 	}
 	return results, nil
 }
@@ -122,7 +162,37 @@ func (t byUnit) Less(i, j int) bool {
 	return t[i].Unit < t[j].Unit
 }
 
-func (api *MetricsDebugAPI) filterLastValuePerKeyPerUnit(batches []state.MetricBatch) []params.MetricResult {
+type byUnitKeyTime []params.MetricResult
+
+func (t byUnitKeyTime) Len() int      { return len(t) }
+func (t byUnitKeyTime) Swap(i, j int) { t[i], t[j] = t[j], t[i] }
+func (t byUnitKeyTime) Less(i, j int) bool {
+	if t[i].Unit != t[j].Unit {
+		return t[i].Unit < t[j].Unit
+	}
+	if t[i].Key != t[j].Key {
+		return t[i].Key < t[j].Key
+	}
+	return t[i].Time.Before(t[j].Time)
+}
+
+func allValues(batches []state.MetricBatch) []params.MetricResult {
+	results := []params.MetricResult{}
+	for _, mb := range batches {
+		for _, m := range mb.Metrics() {
+			results = append(results, params.MetricResult{
+				Key:   m.Key,
+				Value: m.Value,
+				Time:  m.Time,
+				Unit:  mb.Unit(),
+			})
+		}
+	}
+	sort.Stable(byUnitKeyTime(results))
+	return results
+}
+
+func filterLastValuePerKeyPerUnit(batches []state.MetricBatch) []params.MetricResult {
 	metrics := []params.MetricResult{}
 	for _, mb := range batches {
 		for _, m := range mb.UniqueMetrics() {
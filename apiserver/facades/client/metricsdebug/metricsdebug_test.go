@@ -414,3 +414,57 @@ func (s *metricsDebugSuite) TestGetModelNoMocks(c *gc.C) {
 	c.Assert(metric1.Time, jc.TimeBetween(expected1.Time, expected1.Time))
 	c.Assert(metric1.Unit, gc.Equals, metricUnit1.Unit())
 }
+
+func (s *metricsDebugSuite) TestGetMetricsHistory(c *gc.C) {
+	meteredCharm := s.Factory.MakeCharm(c, &factory.CharmParams{Name: "metered", URL: "local:quantal/metered-1"})
+	meteredService := s.Factory.MakeApplication(c, &factory.ApplicationParams{Charm: meteredCharm})
+	unit0 := s.Factory.MakeUnit(c, &factory.UnitParams{Application: meteredService, SetCharmURL: true})
+	unit1 := s.Factory.MakeUnit(c, &factory.UnitParams{Application: meteredService, SetCharmURL: true})
+	t0 := time.Now().Round(time.Second)
+	t1 := t0.Add(time.Second)
+	metricA := state.Metric{"pings", "5", t1}
+	metricB := state.Metric{"pings", "10.5", t0}
+	metricC := state.Metric{"juju-units", "8", t1}
+	s.Factory.MakeMetric(c, &factory.MetricParams{Unit: unit0, Metrics: []state.Metric{metricA, metricC}})
+	s.Factory.MakeMetric(c, &factory.MetricParams{Unit: unit0, Metrics: []state.Metric{metricB}})
+	s.Factory.MakeMetric(c, &factory.MetricParams{Unit: unit1, Metrics: []state.Metric{metricA}})
+	args := params.Entities{Entities: []params.Entity{
+		{"application-metered"},
+	}}
+	result, err := s.metricsdebug.GetMetricsHistory(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results, gc.HasLen, 1)
+	c.Assert(result.Results[0], jc.DeepEquals, params.EntityMetrics{
+		Metrics: []params.MetricResult{{
+			Key:   "juju-units",
+			Value: "8",
+			Time:  t1,
+			Unit:  "metered/0",
+		}, {
+			Key:   "pings",
+			Value: "10.5",
+			Time:  t0,
+			Unit:  "metered/0",
+		}, {
+			Key:   "pings",
+			Value: "5",
+			Time:  t1,
+			Unit:  "metered/0",
+		}, {
+			Key:   "pings",
+			Value: "5",
+			Time:  t1,
+			Unit:  "metered/1",
+		}},
+	})
+}
+
+func (s *metricsDebugSuite) TestGetMetricsHistoryInvalidTag(c *gc.C) {
+	args := params.Entities{Entities: []params.Entity{
+		{"machine-0"},
+	}}
+	result, err := s.metricsdebug.GetMetricsHistory(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results, gc.HasLen, 1)
+	c.Assert(result.Results[0].Error, gc.ErrorMatches, "invalid tag machine-0")
+}
@@ -8,6 +8,8 @@ import (
 	"gopkg.in/juju/names.v2"
 )
 
+var NewCAASBroker = &newCAASBroker
+
 func AuthCheck(c *gc.C, mm *ModelManagerAPI, user names.UserTag) bool {
 	mm.authCheck(user)
 	return mm.isAdmin
@@ -149,6 +149,24 @@ func (s *ListModelsWithInfoSuite) TestListModelSummariesWithCoreCount(c *gc.C) {
 	c.Assert(result.Results[0].Result.Counts[0], jc.DeepEquals, params.ModelEntityCount{params.Cores, 43})
 }
 
+func (s *ListModelsWithInfoSuite) TestListModelSummariesWithUsage(c *gc.C) {
+	lastConnection := time.Now()
+	s.st.modelDetailsForUser = func() ([]state.ModelSummary, error) {
+		summary := s.st.model.getModelDetails()
+		summary.MemoryMB = int64(4096)
+		summary.UnitCount = int64(3)
+		summary.LastConnection = &lastConnection
+		return []state.ModelSummary{summary}, nil
+	}
+	result, err := s.api.ListModelSummaries(params.ModelSummariesRequest{UserTag: s.adminUser.String()})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results[0].Result.Counts, jc.DeepEquals, []params.ModelEntityCount{
+		{params.Memory, 4096},
+		{params.Units, 3},
+	})
+	c.Assert(result.Results[0].Result.LastConnection, gc.DeepEquals, &lastConnection)
+}
+
 func (s *ListModelsWithInfoSuite) TestListModelSummariesWithMachineAndUserDetails(c *gc.C) {
 	now := time.Now()
 	s.st.modelDetailsForUser = func() ([]state.ModelSummary, error) {
@@ -19,6 +19,7 @@ import (
 	"github.com/juju/juju/apiserver/facades/client/modelmanager"
 	"github.com/juju/juju/apiserver/params"
 	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/caas"
 	"github.com/juju/juju/cloud"
 	"github.com/juju/juju/controller"
 	"github.com/juju/juju/environs"
@@ -821,6 +822,11 @@ func (st *mockState) DumpAll() (map[string]interface{}, error) {
 	}, st.NextErr()
 }
 
+func (st *mockState) ReplicaSetStatus() (map[string]state.ReplicaSetMemberStatus, error) {
+	st.MethodCall(st, "ReplicaSetStatus")
+	return nil, st.NextErr()
+}
+
 func (st *mockState) LatestMigration() (state.ModelMigration, error) {
 	st.MethodCall(st, "LatestMigration")
 	if st.migration == nil {
@@ -1080,3 +1086,19 @@ func (m *mockMigration) StartTime() time.Time {
 func (m *mockMigration) EndTime() time.Time {
 	return m.end
 }
+
+type mockCAASBroker struct {
+	caas.Broker
+	gitjujutesting.Stub
+	clusterMetadata caas.ClusterMetadata
+}
+
+func (m *mockCAASBroker) ClusterMetadata() (*caas.ClusterMetadata, error) {
+	m.MethodCall(m, "ClusterMetadata")
+	return &m.clusterMetadata, m.NextErr()
+}
+
+func (m *mockCAASBroker) Create(args environs.CreateParams) error {
+	m.MethodCall(m, "Create", args)
+	return m.NextErr()
+}
@@ -24,6 +24,8 @@ import (
 	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/facade"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/caas"
+	caasall "github.com/juju/juju/caas/all"
 	jujucloud "github.com/juju/juju/cloud"
 	"github.com/juju/juju/controller/modelmanager"
 	"github.com/juju/juju/environs"
@@ -37,7 +39,24 @@ import (
 
 var logger = loggo.GetLogger("juju.apiserver.modelmanager")
 
-// ModelManagerV4 defines the methods on the version 2 facade for the
+var newCAASBroker caas.NewContainerBrokerFunc = caasall.NewContainerBroker
+
+// ModelManagerV5 defines the methods on the version 5 facade for the
+// modelmanager API endpoint.
+type ModelManagerV5 interface {
+	CreateModel(args params.ModelCreateArgs) (params.ModelInfo, error)
+	DumpModels(args params.DumpModelRequest) params.StringResults
+	DumpModelsDB(args params.Entities) params.MapResults
+	ListModelSummaries(request params.ModelSummariesRequest) (params.ModelSummaryResults, error)
+	ListModels(user params.Entity) (params.UserModelList, error)
+	DestroyModels(args params.DestroyModelsParams) (params.ErrorResults, error)
+	ModelInfo(args params.Entities) (params.ModelInfoResults, error)
+	ModelStatus(req params.Entities) (params.ModelStatusResults, error)
+	SetModelDefaults(args params.SetModelDefaults) (params.ErrorResults, error)
+	UnsetModelDefaults(args params.UnsetModelDefaults) (params.ErrorResults, error)
+}
+
+// ModelManagerV4 defines the methods on the version 4 facade for the
 // modelmanager API endpoint.
 type ModelManagerV4 interface {
 	CreateModel(args params.ModelCreateArgs) (params.ModelInfo, error)
@@ -87,10 +106,16 @@ type ModelManagerAPI struct {
 	model       common.Model
 }
 
-// ModelManagerAPIV2 provides a way to wrap the different calls between
+// ModelManagerAPIV4 provides a way to wrap the different calls between
+// version 4 and version 5 of the model manager API
+type ModelManagerAPIV4 struct {
+	*ModelManagerAPI
+}
+
+// ModelManagerAPIV3 provides a way to wrap the different calls between
 // version 3 and version 4 of the model manager API
 type ModelManagerAPIV3 struct {
-	*ModelManagerAPI
+	*ModelManagerAPIV4
 }
 
 // ModelManagerAPIV2 provides a way to wrap the different calls between
@@ -100,13 +125,14 @@ type ModelManagerAPIV2 struct {
 }
 
 var (
-	_ ModelManagerV4 = (*ModelManagerAPI)(nil)
+	_ ModelManagerV5 = (*ModelManagerAPI)(nil)
+	_ ModelManagerV4 = (*ModelManagerAPIV4)(nil)
 	_ ModelManagerV3 = (*ModelManagerAPIV3)(nil)
 	_ ModelManagerV2 = (*ModelManagerAPIV2)(nil)
 )
 
-// NewFacadeV4 is used for API registration.
-func NewFacadeV4(ctx facade.Context) (*ModelManagerAPI, error) {
+// NewFacadeV5 is used for API registration.
+func NewFacadeV5(ctx facade.Context) (*ModelManagerAPI, error) {
 	st := ctx.State()
 	pool := ctx.StatePool()
 	ctlrSt := pool.SystemState()
@@ -134,6 +160,15 @@ func NewFacadeV4(ctx facade.Context) (*ModelManagerAPI, error) {
 	)
 }
 
+// NewFacadeV4 is used for API registration.
+func NewFacadeV4(ctx facade.Context) (*ModelManagerAPIV4, error) {
+	v5, err := NewFacadeV5(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &ModelManagerAPIV4{v5}, nil
+}
+
 // NewFacadeV3 is used for API registration.
 func NewFacadeV3(ctx facade.Context) (*ModelManagerAPIV3, error) {
 	v4, err := NewFacadeV4(ctx)
@@ -278,12 +313,14 @@ func (m *ModelManagerAPI) newCAASModelConfig(
 		return nil, errors.NewNotValid(nil, "Name must be specified")
 	}
 
-	attrs := map[string]interface{}{
-		config.NameKey:         args.Name,
-		config.TypeKey:         "CAAS",
-		config.UUIDKey:         uuid.String(),
-		config.AgentVersionKey: jujuversion.Current.String(),
+	attrs := make(map[string]interface{})
+	for key, value := range args.Config {
+		attrs[key] = value
 	}
+	attrs[config.NameKey] = args.Name
+	attrs[config.TypeKey] = "CAAS"
+	attrs[config.UUIDKey] = uuid.String()
+	attrs[config.AgentVersionKey] = jujuversion.Current.String()
 
 	cfg, err := config.New(config.UseDefaults, attrs)
 	if err != nil {
@@ -297,13 +334,13 @@ func (m *ModelManagerAPI) newCAASModelConfig(
 // model config specified in the args.
 func (m *ModelManagerAPI) CreateModel(args params.ModelCreateArgs) (params.ModelInfo, error) {
 	result := params.ModelInfo{}
+	// Users without add-model access to the controller may still
+	// have been granted it for the cloud hosting the model, which
+	// is checked once the cloud is known.
 	canAddModel, err := m.authorizer.HasPermission(permission.AddModelAccess, m.state.ControllerTag())
 	if err != nil {
 		return result, errors.Trace(err)
 	}
-	if !canAddModel {
-		return result, common.ErrPerm
-	}
 
 	ownerTag, err := names.ParseUserTag(args.OwnerTag)
 	if err != nil {
@@ -313,6 +350,9 @@ func (m *ModelManagerAPI) CreateModel(args params.ModelCreateArgs) (params.Model
 	// a special case of ErrPerm will happen if the user has add-model permission but is trying to
 	// create a model for another person, which is not yet supported.
 	if !m.isAdmin && ownerTag != m.apiUser {
+		if !canAddModel {
+			return result, common.ErrPerm
+		}
 		return result, errors.Annotatef(common.ErrPerm, "%q permission does not permit creation of models for different owners", permission.AddModelAccess)
 	}
 
@@ -334,6 +374,15 @@ func (m *ModelManagerAPI) CreateModel(args params.ModelCreateArgs) (params.Model
 	} else {
 		cloudTag = names.NewCloudTag(controllerModel.Cloud())
 	}
+	if !canAddModel {
+		canAddModel, err = m.authorizer.HasPermission(permission.AddModelAccess, cloudTag)
+		if err != nil {
+			return result, errors.Trace(err)
+		}
+		if !canAddModel {
+			return result, common.ErrPerm
+		}
+	}
 	if cloudRegionName == "" && cloudTag.Id() == controllerModel.Cloud() {
 		cloudRegionName = controllerModel.CloudRegion()
 	}
@@ -439,6 +488,34 @@ func (m *ModelManagerAPI) newCAASModel(cloudSpec environs.CloudSpec,
 		return nil, errors.Annotate(err, "failed to create config")
 	}
 
+	// Create the model's resources in the cloud, such
+	// as the namespace in which its applications run.
+	broker, err := newCAASBroker(environs.OpenParams{
+		Cloud:  cloudSpec,
+		Config: newConfig,
+	})
+	if err != nil {
+		return nil, errors.Annotate(err, "failed to open CAAS broker")
+	}
+	// Check the cluster up front, rather than having the
+	// model's applications fail when they are deployed.
+	clusterMetadata, err := broker.ClusterMetadata()
+	if err != nil {
+		return nil, errors.Annotate(err, "failed to query CAAS cluster")
+	}
+	if err := clusterMetadata.Validate(); err != nil {
+		return nil, errors.Annotate(err, "cannot use CAAS cluster")
+	}
+	controllerCfg, err := m.state.ControllerConfig()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if err := broker.Create(environs.CreateParams{
+		ControllerUUID: controllerCfg.ControllerUUID(),
+	}); err != nil {
+		return nil, errors.Annotate(err, "failed to create CAAS model")
+	}
+
 	model, st, err := m.state.NewModel(state.ModelArgs{
 		Type:            state.ModelTypeCAAS,
 		CloudName:       cloudTag.Id(),
@@ -722,6 +799,7 @@ func (m *ModelManagerAPI) ListModelSummaries(req params.ModelSummariesRequest) (
 			Status:             common.EntityStatusFromState(mi.Status),
 			Counts:             []params.ModelEntityCount{},
 			UserLastConnection: mi.UserLastConnection,
+			LastConnection:     mi.LastConnection,
 		}
 
 		if mi.MachineCount > 0 {
@@ -732,6 +810,14 @@ func (m *ModelManagerAPI) ListModelSummaries(req params.ModelSummariesRequest) (
 			summary.Counts = append(summary.Counts, params.ModelEntityCount{params.Cores, mi.CoreCount})
 		}
 
+		if mi.MemoryMB > 0 {
+			summary.Counts = append(summary.Counts, params.ModelEntityCount{params.Memory, mi.MemoryMB})
+		}
+
+		if mi.UnitCount > 0 {
+			summary.Counts = append(summary.Counts, params.ModelEntityCount{params.Units, mi.UnitCount})
+		}
+
 		access, err := common.StateToParamsUserAccessPermission(mi.Access)
 		if err == nil {
 			summary.UserAccess = access
@@ -1183,6 +1269,7 @@ func (m *ModelManagerAPI) ModelDefaults() (params.ModelDefaultsResult, error) {
 	for attr, val := range values {
 		settings := params.ModelDefaults{
 			Controller: val.Controller,
+			Cloud:      val.Cloud,
 			Default:    val.Default,
 		}
 		for _, v := range val.Regions {
@@ -1224,7 +1311,7 @@ func (m *ModelManagerAPI) setModelDefaults(args params.ModelDefaultValues) error
 	}
 
 	var rspec *environs.RegionSpec
-	if args.CloudRegion != "" {
+	if args.CloudTag != "" {
 		spec, err := m.makeRegionSpec(args.CloudTag, args.CloudRegion)
 		if err != nil {
 			return errors.Trace(err)
@@ -1247,7 +1334,7 @@ func (m *ModelManagerAPI) UnsetModelDefaults(args params.UnsetModelDefaults) (pa
 
 	for i, arg := range args.Keys {
 		var rspec *environs.RegionSpec
-		if arg.CloudRegion != "" {
+		if arg.CloudTag != "" {
 			spec, err := m.makeRegionSpec(arg.CloudTag, arg.CloudRegion)
 			if err != nil {
 				results.Results[i].Error = common.ServerError(
@@ -1263,13 +1350,41 @@ func (m *ModelManagerAPI) UnsetModelDefaults(args params.UnsetModelDefaults) (pa
 	return results, nil
 }
 
+// SetModelDefaults writes new values for the specified default model
+// settings. Version 4 of the facade has no cloud-wide defaults; a cloud
+// without a region is ignored, and the controller defaults are set.
+func (m *ModelManagerAPIV4) SetModelDefaults(args params.SetModelDefaults) (params.ErrorResults, error) {
+	for i, arg := range args.Config {
+		if arg.CloudRegion == "" {
+			args.Config[i].CloudTag = ""
+		}
+	}
+	return m.ModelManagerAPI.SetModelDefaults(args)
+}
+
+// UnsetModelDefaults removes the specified default model settings.
+// Version 4 of the facade has no cloud-wide defaults; a cloud without
+// a region is ignored, and the controller defaults are removed.
+func (m *ModelManagerAPIV4) UnsetModelDefaults(args params.UnsetModelDefaults) (params.ErrorResults, error) {
+	for i, arg := range args.Keys {
+		if arg.CloudRegion == "" {
+			args.Keys[i].CloudTag = ""
+		}
+	}
+	return m.ModelManagerAPI.UnsetModelDefaults(args)
+}
+
 // makeRegionSpec is a helper method for methods that call
-// state.UpdateModelConfigDefaultValues.
+// state.UpdateModelConfigDefaultValues. An empty region
+// results in a spec for the cloud as a whole.
 func (m *ModelManagerAPI) makeRegionSpec(cloudTag, r string) (*environs.RegionSpec, error) {
 	cTag, err := names.ParseCloudTag(cloudTag)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
+	if r == "" {
+		return &environs.RegionSpec{Cloud: cTag.Id()}, nil
+	}
 	rspec, err := environs.NewRegionSpec(cTag.Id(), r)
 	if err != nil {
 		return nil, errors.Trace(err)
@@ -12,6 +12,7 @@ import (
 	"github.com/juju/loggo"
 	gitjujutesting "github.com/juju/testing"
 	jc "github.com/juju/testing/checkers"
+	"github.com/juju/version"
 	gc "gopkg.in/check.v1"
 	"gopkg.in/juju/names.v2"
 
@@ -20,6 +21,7 @@ import (
 	"github.com/juju/juju/apiserver/facades/client/modelmanager"
 	"github.com/juju/juju/apiserver/params"
 	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/caas"
 	"github.com/juju/juju/cloud"
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/environs/config"
@@ -59,6 +61,7 @@ type modelManagerSuite struct {
 	authoriser apiservertesting.FakeAuthorizer
 	api        *modelmanager.ModelManagerAPI
 	caasApi    *modelmanager.ModelManagerAPI
+	caasBroker *mockCAASBroker
 }
 
 var _ = gc.Suite(&modelManagerSuite{})
@@ -66,6 +69,18 @@ var _ = gc.Suite(&modelManagerSuite{})
 func (s *modelManagerSuite) SetUpTest(c *gc.C) {
 	s.IsolationSuite.SetUpTest(c)
 
+	s.caasBroker = &mockCAASBroker{
+		clusterMetadata: caas.ClusterMetadata{
+			Version:    version.MustParse("1.9.3"),
+			MinVersion: version.MustParse("1.6.0"),
+			Nodes:      []caas.NodeCapacity{{Name: "node-1", CpuPower: 200, Mem: 4096}},
+		},
+	}
+	s.PatchValue(modelmanager.NewCAASBroker, func(args environs.OpenParams) (caas.Broker, error) {
+		s.caasBroker.MethodCall(s.caasBroker, "Open", args)
+		return s.caasBroker, s.caasBroker.NextErr()
+	})
+
 	attrs := dummy.SampleConfig()
 	attrs["agent-version"] = jujuversion.Current.String()
 	cfg, err := config.New(config.UseDefaults, attrs)
@@ -424,6 +439,7 @@ func (s *modelManagerSuite) TestCreateCAASModelArgs(c *gc.C) {
 		"ControllerTag",
 		"Cloud",
 		"CloudCredential",
+		"ControllerConfig",
 		"NewModel",
 		"Close",
 		"GetBackend",
@@ -431,6 +447,10 @@ func (s *modelManagerSuite) TestCreateCAASModelArgs(c *gc.C) {
 		"AllMachines",
 		"LatestMigration",
 	)
+	s.caasBroker.CheckCallNames(c, "Open", "ClusterMetadata", "Create")
+	s.caasBroker.CheckCall(c, 2, "Create", environs.CreateParams{
+		ControllerUUID: "deadbeef-1bad-500d-9000-4b1d0d06f00d",
+	})
 
 	// Check that Model.LastModelConnection is called just twice
 	// without making the test depend on other calls to Model
@@ -470,6 +490,58 @@ func (s *modelManagerSuite) TestCreateCAASModelArgs(c *gc.C) {
 	})
 }
 
+func (s *modelManagerSuite) TestCreateCAASModelNamespaceConfig(c *gc.C) {
+	args := params.ModelCreateArgs{
+		Name:     "foo",
+		OwnerTag: "user-admin",
+		Config: map[string]interface{}{
+			"namespace-memory-quota": "8Gi",
+		},
+		CloudTag:           "cloud-k8s-cloud",
+		CloudCredentialTag: "cloudcred-k8s-cloud_admin_some-credential",
+	}
+	_, err := s.caasApi.CreateModel(args)
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.caasBroker.CheckCallNames(c, "Open", "ClusterMetadata", "Create")
+	openArgs := s.caasBroker.Calls()[0].Args[0].(environs.OpenParams)
+	c.Assert(openArgs.Config.Name(), gc.Equals, "foo")
+	c.Assert(openArgs.Config.UnknownAttrs()["namespace-memory-quota"], gc.Equals, "8Gi")
+}
+
+func (s *modelManagerSuite) TestCreateCAASModelBrokerCreateFails(c *gc.C) {
+	s.caasBroker.SetErrors(nil, nil, errors.New("namespace exists"))
+	args := params.ModelCreateArgs{
+		Name:               "foo",
+		OwnerTag:           "user-admin",
+		Config:             map[string]interface{}{},
+		CloudTag:           "cloud-k8s-cloud",
+		CloudCredentialTag: "cloudcred-k8s-cloud_admin_some-credential",
+	}
+	_, err := s.caasApi.CreateModel(args)
+	c.Assert(err, gc.ErrorMatches, "failed to create CAAS model: namespace exists")
+	for _, call := range s.caasSt.Calls() {
+		c.Assert(call.FuncName, gc.Not(gc.Equals), "NewModel")
+	}
+}
+
+func (s *modelManagerSuite) TestCreateCAASModelUnsupportedCluster(c *gc.C) {
+	s.caasBroker.clusterMetadata.Version = version.MustParse("1.5.2")
+	args := params.ModelCreateArgs{
+		Name:               "foo",
+		OwnerTag:           "user-admin",
+		Config:             map[string]interface{}{},
+		CloudTag:           "cloud-k8s-cloud",
+		CloudCredentialTag: "cloudcred-k8s-cloud_admin_some-credential",
+	}
+	_, err := s.caasApi.CreateModel(args)
+	c.Assert(err, gc.ErrorMatches, "cannot use CAAS cluster: cluster version 1.5.2 is not supported, version 1.6.0 or later is required")
+	s.caasBroker.CheckCallNames(c, "Open", "ClusterMetadata")
+	for _, call := range s.caasSt.Calls() {
+		c.Assert(call.FuncName, gc.Not(gc.Equals), "NewModel")
+	}
+}
+
 func (s *modelManagerSuite) TestModelDefaults(c *gc.C) {
 	result, err := s.api.ModelDefaults()
 	c.Assert(err, jc.ErrorIsNil)
@@ -518,6 +590,39 @@ func (s *modelManagerSuite) TestSetModelDefaults(c *gc.C) {
 	})
 }
 
+func (s *modelManagerSuite) TestSetModelDefaultsCloud(c *gc.C) {
+	params := params.SetModelDefaults{
+		Config: []params.ModelDefaultValues{{
+			CloudTag: "cloud-dummy",
+			Config:   map[string]interface{}{"attr3": "val3"},
+		}}}
+	result, err := s.api.SetModelDefaults(params)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.OneError(), jc.ErrorIsNil)
+	s.st.CheckCall(c, len(s.st.Calls())-1, "UpdateModelConfigDefaultValues",
+		map[string]interface{}{"attr3": "val3"},
+		[]string(nil),
+		&environs.RegionSpec{Cloud: "dummy"},
+	)
+}
+
+func (s *modelManagerSuite) TestSetModelDefaultsCloudV4(c *gc.C) {
+	api := &modelmanager.ModelManagerAPIV4{s.api}
+	params := params.SetModelDefaults{
+		Config: []params.ModelDefaultValues{{
+			CloudTag: "cloud-dummy",
+			Config:   map[string]interface{}{"attr3": "val3"},
+		}}}
+	result, err := api.SetModelDefaults(params)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.OneError(), jc.ErrorIsNil)
+	s.st.CheckCall(c, len(s.st.Calls())-1, "UpdateModelConfigDefaultValues",
+		map[string]interface{}{"attr3": "val3"},
+		[]string(nil),
+		(*environs.RegionSpec)(nil),
+	)
+}
+
 func (s *modelManagerSuite) blockAllChanges(c *gc.C, msg string) {
 	s.st.blockMsg = msg
 	s.st.block = state.ChangeBlock
@@ -632,7 +737,7 @@ func (s *modelManagerSuite) TestUnsetModelDefaultsAsNormalUser(c *gc.C) {
 
 func (s *modelManagerSuite) TestDumpModelV2(c *gc.C) {
 	api := &modelmanager.ModelManagerAPIV2{
-		&modelmanager.ModelManagerAPIV3{s.api},
+		&modelmanager.ModelManagerAPIV3{&modelmanager.ModelManagerAPIV4{s.api}},
 	}
 
 	results := api.DumpModels(params.Entities{[]params.Entity{{
@@ -811,8 +916,48 @@ func (s *modelManagerSuite) TestAddModelCantCreateModelForSomeoneElse(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, "\"add-model\" permission does not permit creation of models for different owners: permission denied")
 }
 
+// cloudAddModelAuthorizer grants add-model access on a single cloud,
+// deferring to FakeAuthorizer for everything else.
+type cloudAddModelAuthorizer struct {
+	apiservertesting.FakeAuthorizer
+	cloud names.CloudTag
+}
+
+func (a cloudAddModelAuthorizer) HasPermission(operation permission.Access, target names.Tag) (bool, error) {
+	if operation == permission.AddModelAccess && target == a.cloud {
+		return true, nil
+	}
+	return a.FakeAuthorizer.HasPermission(operation, target)
+}
+
+func (s *modelManagerSuite) TestCloudAddModelCanCreateModel(c *gc.C) {
+	user := names.NewUserTag("cloud-user")
+	authorizer := cloudAddModelAuthorizer{
+		FakeAuthorizer: apiservertesting.FakeAuthorizer{Tag: user},
+		cloud:          names.NewCloudTag("some-cloud"),
+	}
+	api, err := modelmanager.NewModelManagerAPI(s.st, s.ctlrSt, nil, authorizer, s.st.model)
+	c.Assert(err, jc.ErrorIsNil)
+	args := createArgs(user)
+	args.CloudCredentialTag = "cloudcred-some-cloud_cloud-user_some-credential"
+	_, err = api.CreateModel(args)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *modelManagerSuite) TestCloudAddModelCantCreateModelOnOtherCloud(c *gc.C) {
+	user := names.NewUserTag("cloud-user")
+	authorizer := cloudAddModelAuthorizer{
+		FakeAuthorizer: apiservertesting.FakeAuthorizer{Tag: user},
+		cloud:          names.NewCloudTag("other-cloud"),
+	}
+	api, err := modelmanager.NewModelManagerAPI(s.st, s.ctlrSt, nil, authorizer, s.st.model)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = api.CreateModel(createArgs(user))
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
 func (s *modelManagerSuite) TestDestroyModelsV3(c *gc.C) {
-	api := &modelmanager.ModelManagerAPIV3{s.api}
+	api := &modelmanager.ModelManagerAPIV3{&modelmanager.ModelManagerAPIV4{s.api}}
 	results, err := api.DestroyModels(params.Entities{
 		Entities: []params.Entity{{coretesting.ModelTag.String()}},
 	})
@@ -1569,7 +1714,7 @@ func (s *modelManagerStateSuite) TestModifyModelAccessInvalidAction(c *gc.C) {
 
 func (s *modelManagerSuite) TestModelStatusV2(c *gc.C) {
 	api := &modelmanager.ModelManagerAPIV2{
-		&modelmanager.ModelManagerAPIV3{s.api},
+		&modelmanager.ModelManagerAPIV3{&modelmanager.ModelManagerAPIV4{s.api}},
 	}
 	// Check that we err out immediately if a model errs.
 	results, err := api.ModelStatus(params.Entities{[]params.Entity{{
@@ -1598,7 +1743,7 @@ func (s *modelManagerSuite) TestModelStatusV2(c *gc.C) {
 }
 
 func (s *modelManagerSuite) TestModelStatusV3(c *gc.C) {
-	api := &modelmanager.ModelManagerAPIV3{s.api}
+	api := &modelmanager.ModelManagerAPIV3{&modelmanager.ModelManagerAPIV4{s.api}}
 
 	// Check that we err out immediately if a model errs.
 	results, err := api.ModelStatus(params.Entities{[]params.Entity{{
@@ -0,0 +1,70 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package modelpower
+
+import (
+	"github.com/juju/errors"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/state"
+)
+
+// Backend contains the state.State methods used in this package,
+// allowing stubs to be created for testing.
+type Backend interface {
+	ControllerTag() names.ControllerTag
+	ModelTag() names.ModelTag
+	IsController() bool
+	Model() (Model, error)
+	AllMachines() ([]Machine, error)
+	GetBlockForType(t state.BlockType) (state.Block, bool, error)
+}
+
+// Model contains the state.Model methods used in this package.
+type Model interface {
+	Suspended() bool
+	SetSuspended(bool) error
+}
+
+// Machine contains the state.Machine methods used in this package.
+type Machine interface {
+	Id() string
+	ContainerType() instance.ContainerType
+	IsManual() (bool, error)
+	InstanceId() (instance.Id, error)
+}
+
+type stateShim struct {
+	*state.State
+}
+
+func (st stateShim) ModelTag() names.ModelTag {
+	return names.NewModelTag(st.State.ModelUUID())
+}
+
+func (st stateShim) Model() (Model, error) {
+	m, err := st.State.Model()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return m, nil
+}
+
+func (st stateShim) AllMachines() ([]Machine, error) {
+	all, err := st.State.AllMachines()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	machines := make([]Machine, len(all))
+	for i, m := range all {
+		machines[i] = m
+	}
+	return machines, nil
+}
+
+// NewStateBackend creates a backend for the facade to use.
+func NewStateBackend(st *state.State) Backend {
+	return stateShim{st}
+}
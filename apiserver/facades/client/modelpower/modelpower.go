@@ -0,0 +1,184 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package modelpower provides the ModelPower facade, which powers
+// the instances of a model off and back on so that an idle model
+// costs less to keep.
+package modelpower
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/facade"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/permission"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/stateenvirons"
+)
+
+// NewEnvironFunc returns the environ of the model the
+// facade is serving.
+type NewEnvironFunc func() (environs.Environ, error)
+
+// API implements the ModelPower facade.
+type API struct {
+	backend    Backend
+	auth       facade.Authorizer
+	check      *common.BlockChecker
+	newEnviron NewEnvironFunc
+}
+
+// NewFacade is used for API registration.
+func NewFacade(st *state.State, _ facade.Resources, auth facade.Authorizer) (*API, error) {
+	newEnviron := func() (environs.Environ, error) {
+		return stateenvirons.GetNewEnvironFunc(environs.New)(st)
+	}
+	return NewAPI(NewStateBackend(st), auth, newEnviron)
+}
+
+// NewAPI creates a new instance of the ModelPower facade.
+func NewAPI(backend Backend, authorizer facade.Authorizer, newEnviron NewEnvironFunc) (*API, error) {
+	if !authorizer.AuthClient() {
+		return nil, common.ErrPerm
+	}
+	return &API{
+		backend:    backend,
+		auth:       authorizer,
+		check:      common.NewBlockChecker(backend),
+		newEnviron: newEnviron,
+	}, nil
+}
+
+// checkCanChange checks that the user is an admin of the model or
+// a controller superuser, and that the model may be changed.
+func (a *API) checkCanChange() error {
+	isSuperuser, err := a.auth.HasPermission(permission.SuperuserAccess, a.backend.ControllerTag())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	isAdmin, err := a.auth.HasPermission(permission.AdminAccess, a.backend.ModelTag())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if !isSuperuser && !isAdmin {
+		return common.ErrPerm
+	}
+	if a.backend.IsController() {
+		return errors.New("the controller model cannot be suspended")
+	}
+	return a.check.ChangeAllowed()
+}
+
+// powerManager returns the model's environ as an InstancePowerManager.
+func (a *API) powerManager() (environs.InstancePowerManager, error) {
+	env, err := a.newEnviron()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	powerManager, ok := env.(environs.InstancePowerManager)
+	if !ok {
+		return nil, errors.NotSupportedf("powering off instances in this cloud")
+	}
+	return powerManager, nil
+}
+
+// modelInstances returns the instances of the model's top level
+// machines. Containers are left out, as they are powered off and on
+// along with their hosts; manual machines and machines that have not
+// been provisioned are reported as skipped.
+func (a *API) modelInstances() ([]instance.Id, params.ModelPowerResult, error) {
+	var result params.ModelPowerResult
+	machines, err := a.backend.AllMachines()
+	if err != nil {
+		return nil, result, errors.Trace(err)
+	}
+	var ids []instance.Id
+	for _, m := range machines {
+		if m.ContainerType() != "" && m.ContainerType() != instance.NONE {
+			continue
+		}
+		manual, err := m.IsManual()
+		if err != nil {
+			return nil, result, errors.Trace(err)
+		}
+		if manual {
+			result.Skipped = append(result.Skipped, m.Id())
+			continue
+		}
+		id, err := m.InstanceId()
+		if errors.IsNotProvisioned(err) {
+			result.Skipped = append(result.Skipped, m.Id())
+			continue
+		} else if err != nil {
+			return nil, result, errors.Trace(err)
+		}
+		ids = append(ids, id)
+		result.Machines = append(result.Machines, m.Id())
+	}
+	return ids, result, nil
+}
+
+// SuspendModel powers off the instances of the model's machines,
+// keeping their disks and storage, and records that the model is
+// suspended.
+func (a *API) SuspendModel() (params.ModelPowerResult, error) {
+	if err := a.checkCanChange(); err != nil {
+		return params.ModelPowerResult{}, errors.Trace(err)
+	}
+	powerManager, err := a.powerManager()
+	if err != nil {
+		return params.ModelPowerResult{}, errors.Trace(err)
+	}
+	ids, result, err := a.modelInstances()
+	if err != nil {
+		return params.ModelPowerResult{}, errors.Trace(err)
+	}
+	model, err := a.backend.Model()
+	if err != nil {
+		return params.ModelPowerResult{}, errors.Trace(err)
+	}
+	// The model is marked as suspended first, so that it may be
+	// resumed if only some of the instances are powered off.
+	if err := model.SetSuspended(true); err != nil {
+		return params.ModelPowerResult{}, errors.Trace(err)
+	}
+	if len(ids) > 0 {
+		if err := powerManager.PowerOffInstances(ids...); err != nil {
+			return params.ModelPowerResult{}, errors.Annotate(err, "powering off instances")
+		}
+	}
+	return result, nil
+}
+
+// ResumeModel powers on the instances of the model's machines, whose
+// agents reconnect to the controller as they start, and records that
+// the model is no longer suspended.
+func (a *API) ResumeModel() (params.ModelPowerResult, error) {
+	if err := a.checkCanChange(); err != nil {
+		return params.ModelPowerResult{}, errors.Trace(err)
+	}
+	powerManager, err := a.powerManager()
+	if err != nil {
+		return params.ModelPowerResult{}, errors.Trace(err)
+	}
+	ids, result, err := a.modelInstances()
+	if err != nil {
+		return params.ModelPowerResult{}, errors.Trace(err)
+	}
+	if len(ids) > 0 {
+		if err := powerManager.PowerOnInstances(ids...); err != nil {
+			return params.ModelPowerResult{}, errors.Annotate(err, "powering on instances")
+		}
+	}
+	model, err := a.backend.Model()
+	if err != nil {
+		return params.ModelPowerResult{}, errors.Trace(err)
+	}
+	if err := model.SetSuspended(false); err != nil {
+		return params.ModelPowerResult{}, errors.Trace(err)
+	}
+	return result, nil
+}
@@ -0,0 +1,220 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package modelpower_test
+
+import (
+	"github.com/juju/errors"
+	gitjujutesting "github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/facades/client/modelpower"
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/state"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type modelPowerSuite struct {
+	gitjujutesting.IsolationSuite
+	backend    *mockBackend
+	environ    environs.Environ
+	authorizer apiservertesting.FakeAuthorizer
+}
+
+var _ = gc.Suite(&modelPowerSuite{})
+
+func (s *modelPowerSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.authorizer = apiservertesting.FakeAuthorizer{
+		Tag: names.NewUserTag("admin"),
+	}
+	s.backend = &mockBackend{
+		model: &mockModel{},
+		machines: []modelpower.Machine{
+			&mockMachine{id: "0", instanceId: "inst-0"},
+			&mockMachine{id: "0/lxd/0", containerType: instance.LXD, instanceId: "inst-0-lxd-0"},
+			&mockMachine{id: "1", instanceId: "inst-1"},
+			&mockMachine{id: "2", manual: true, instanceId: "manual:10.0.0.2"},
+			&mockMachine{id: "3"},
+		},
+	}
+	s.environ = &mockPowerEnviron{}
+}
+
+func (s *modelPowerSuite) newAPI(c *gc.C) *modelpower.API {
+	api, err := modelpower.NewAPI(s.backend, s.authorizer, func() (environs.Environ, error) {
+		return s.environ, nil
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	return api
+}
+
+func (s *modelPowerSuite) TestNewAPIRequiresClient(c *gc.C) {
+	s.authorizer.Tag = names.NewMachineTag("0")
+	_, err := modelpower.NewAPI(s.backend, s.authorizer, nil)
+	c.Assert(err, gc.Equals, common.ErrPerm)
+}
+
+var expectedResult = params.ModelPowerResult{
+	Machines: []string{"0", "1"},
+	Skipped:  []string{"2", "3"},
+}
+
+func (s *modelPowerSuite) TestSuspendModel(c *gc.C) {
+	result, err := s.newAPI(c).SuspendModel()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, expectedResult)
+	c.Assert(s.backend.model.suspended, jc.IsTrue)
+	s.environ.(*mockPowerEnviron).CheckCalls(c, []gitjujutesting.StubCall{
+		{"PowerOffInstances", []interface{}{[]instance.Id{"inst-0", "inst-1"}}},
+	})
+}
+
+func (s *modelPowerSuite) TestSuspendModelPowerOffFails(c *gc.C) {
+	s.environ.(*mockPowerEnviron).SetErrors(errors.New("boom"))
+	_, err := s.newAPI(c).SuspendModel()
+	c.Assert(err, gc.ErrorMatches, "powering off instances: boom")
+	// The model stays suspended, so that it can be resumed.
+	c.Assert(s.backend.model.suspended, jc.IsTrue)
+}
+
+func (s *modelPowerSuite) TestResumeModel(c *gc.C) {
+	s.backend.model.suspended = true
+	result, err := s.newAPI(c).ResumeModel()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, expectedResult)
+	c.Assert(s.backend.model.suspended, jc.IsFalse)
+	s.environ.(*mockPowerEnviron).CheckCalls(c, []gitjujutesting.StubCall{
+		{"PowerOnInstances", []interface{}{[]instance.Id{"inst-0", "inst-1"}}},
+	})
+}
+
+func (s *modelPowerSuite) TestResumeModelPowerOnFails(c *gc.C) {
+	s.backend.model.suspended = true
+	s.environ.(*mockPowerEnviron).SetErrors(errors.New("boom"))
+	_, err := s.newAPI(c).ResumeModel()
+	c.Assert(err, gc.ErrorMatches, "powering on instances: boom")
+	c.Assert(s.backend.model.suspended, jc.IsTrue)
+}
+
+func (s *modelPowerSuite) TestSuspendModelNotSupported(c *gc.C) {
+	s.environ = &mockEnviron{}
+	_, err := s.newAPI(c).SuspendModel()
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+	c.Assert(err, gc.ErrorMatches, "powering off instances in this cloud not supported")
+	c.Assert(s.backend.model.suspended, jc.IsFalse)
+}
+
+func (s *modelPowerSuite) TestSuspendControllerModel(c *gc.C) {
+	s.backend.controller = true
+	_, err := s.newAPI(c).SuspendModel()
+	c.Assert(err, gc.ErrorMatches, "the controller model cannot be suspended")
+}
+
+func (s *modelPowerSuite) TestSuspendModelRequiresAdmin(c *gc.C) {
+	s.authorizer.Tag = names.NewUserTag("write")
+	_, err := s.newAPI(c).SuspendModel()
+	c.Assert(err, gc.Equals, common.ErrPerm)
+	_, err = s.newAPI(c).ResumeModel()
+	c.Assert(err, gc.Equals, common.ErrPerm)
+}
+
+func (s *modelPowerSuite) TestSuspendModelSuperuser(c *gc.C) {
+	s.authorizer.Tag = names.NewUserTag("superuser")
+	_, err := s.newAPI(c).SuspendModel()
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+type mockBackend struct {
+	model      *mockModel
+	machines   []modelpower.Machine
+	controller bool
+}
+
+func (b *mockBackend) ControllerTag() names.ControllerTag {
+	return coretesting.ControllerTag
+}
+
+func (b *mockBackend) ModelTag() names.ModelTag {
+	return coretesting.ModelTag
+}
+
+func (b *mockBackend) IsController() bool {
+	return b.controller
+}
+
+func (b *mockBackend) Model() (modelpower.Model, error) {
+	return b.model, nil
+}
+
+func (b *mockBackend) AllMachines() ([]modelpower.Machine, error) {
+	return b.machines, nil
+}
+
+func (b *mockBackend) GetBlockForType(t state.BlockType) (state.Block, bool, error) {
+	return nil, false, nil
+}
+
+type mockModel struct {
+	suspended bool
+}
+
+func (m *mockModel) Suspended() bool {
+	return m.suspended
+}
+
+func (m *mockModel) SetSuspended(suspended bool) error {
+	m.suspended = suspended
+	return nil
+}
+
+type mockMachine struct {
+	id            string
+	containerType instance.ContainerType
+	manual        bool
+	instanceId    instance.Id
+}
+
+func (m *mockMachine) Id() string {
+	return m.id
+}
+
+func (m *mockMachine) ContainerType() instance.ContainerType {
+	return m.containerType
+}
+
+func (m *mockMachine) IsManual() (bool, error) {
+	return m.manual, nil
+}
+
+func (m *mockMachine) InstanceId() (instance.Id, error) {
+	if m.instanceId == "" {
+		return "", errors.NotProvisionedf("machine %v", m.id)
+	}
+	return m.instanceId, nil
+}
+
+type mockEnviron struct {
+	environs.Environ
+}
+
+type mockPowerEnviron struct {
+	environs.Environ
+	gitjujutesting.Stub
+}
+
+func (e *mockPowerEnviron) PowerOffInstances(ids ...instance.Id) error {
+	e.MethodCall(e, "PowerOffInstances", ids)
+	return e.NextErr()
+}
+
+func (e *mockPowerEnviron) PowerOnInstances(ids ...instance.Id) error {
+	e.MethodCall(e, "PowerOnInstances", ids)
+	return e.NextErr()
+}
@@ -8,13 +8,16 @@ package sshclient
 import (
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
+	"gopkg.in/juju/names.v2"
 
 	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/common/cloudspec"
 	"github.com/juju/juju/apiserver/facade"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/network"
 	"github.com/juju/juju/permission"
+	"github.com/juju/juju/state"
 )
 
 var logger = loggo.GetLogger("juju.apiserver.sshclient")
@@ -190,6 +193,26 @@ func (facade *Facade) PublicKeys(args params.Entities) (params.SSHPublicKeysResu
 	return out, nil
 }
 
+// ModelCredentialForSSH returns the cloud spec of a CAAS model.
+// Clients use it to run commands in the pods of the model's units,
+// which do not run SSH servers.
+func (facade *Facade) ModelCredentialForSSH() (params.CloudSpecResult, error) {
+	if err := facade.checkIsModelAdmin(); err != nil {
+		return params.CloudSpecResult{}, errors.Trace(err)
+	}
+	modelTag := facade.backend.ModelTag()
+	if facade.backend.ModelType() != state.ModelTypeCAAS {
+		return params.CloudSpecResult{
+			Error: common.ServerError(errors.NotSupportedf("model credential for non CAAS model %q", modelTag.Id())),
+		}, nil
+	}
+	getCloudSpec := func(names.ModelTag) (environs.CloudSpec, error) {
+		return facade.backend.CloudSpec()
+	}
+	api := cloudspec.NewCloudSpec(getCloudSpec, common.AuthFuncForTag(modelTag))
+	return api.GetCloudSpec(modelTag), nil
+}
+
 // Proxy returns whether SSH connections should be proxied through the
 // controller hosts for the model associated with the API connection.
 func (facade *Facade) Proxy() (params.SSHProxyResult, error) {
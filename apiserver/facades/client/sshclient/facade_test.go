@@ -177,15 +177,61 @@ func (s *facadeSuite) TestProxyFalse(c *gc.C) {
 	})
 }
 
+func (s *facadeSuite) TestModelCredentialForSSH(c *gc.C) {
+	s.backend.modelType = state.ModelTypeCAAS
+	result, err := s.facade.ModelCredentialForSSH()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Error, gc.IsNil)
+	c.Check(result.Result, jc.DeepEquals, &params.CloudSpec{
+		Type:             "dummy",
+		Name:             "dummy",
+		Region:           "dummy-region",
+		Endpoint:         "dummy-endpoint",
+		IdentityEndpoint: "dummy-identity-endpoint",
+		StorageEndpoint:  "dummy-storage-endpoint",
+		Credential: &params.CloudCredential{
+			AuthType:   "userpass",
+			Attributes: map[string]string{"username": "dummy", "password": "secret"},
+		},
+	})
+	s.backend.stub.CheckCalls(c, []jujutesting.StubCall{
+		{"CloudSpec", nil},
+	})
+}
+
+func (s *facadeSuite) TestModelCredentialForSSHIAASModel(c *gc.C) {
+	result, err := s.facade.ModelCredentialForSSH()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Error, gc.ErrorMatches, `model credential for non CAAS model "deadbeef-2f18-4fd2-967d-db9663db7bea" not supported`)
+	c.Assert(result.Error.Code, gc.Equals, params.CodeNotSupported)
+	c.Assert(result.Result, gc.IsNil)
+	s.backend.stub.CheckNoCalls(c)
+}
+
+func (s *facadeSuite) TestModelCredentialForSSHNotAdmin(c *gc.C) {
+	s.backend.modelType = state.ModelTypeCAAS
+	s.authorizer.AdminTag = names.NewUserTag("other")
+	_, err := s.facade.ModelCredentialForSSH()
+	c.Assert(errors.Cause(err), gc.Equals, common.ErrPerm)
+}
+
 type mockBackend struct {
-	stub     jujutesting.Stub
-	proxySSH bool
+	stub      jujutesting.Stub
+	proxySSH  bool
+	modelType state.ModelType
 }
 
 func (backend *mockBackend) ModelTag() names.ModelTag {
 	return names.NewModelTag("deadbeef-2f18-4fd2-967d-db9663db7bea")
 }
 
+func (backend *mockBackend) ModelType() state.ModelType {
+	if backend.modelType == "" {
+		return state.ModelTypeIAAS
+	}
+	return backend.modelType
+}
+
 func (backend *mockBackend) ModelConfig() (*config.Config, error) {
 	backend.stub.AddCall("ModelConfig")
 	attrs := testing.FakeConfig()
@@ -22,6 +22,7 @@ type Backend interface {
 	GetMachineForEntity(tag string) (SSHMachine, error)
 	GetSSHHostKeys(names.MachineTag) (state.SSHHostKeys, error)
 	ModelTag() names.ModelTag
+	ModelType() state.ModelType
 }
 
 // SSHMachine specifies the methods on State.Machine of interest to
@@ -47,6 +48,11 @@ type backend struct {
 	stateenvirons.EnvironConfigGetter
 }
 
+// ModelType returns the type of the model.
+func (b *backend) ModelType() state.ModelType {
+	return b.Model.Type()
+}
+
 // GetMachineForEntity takes a machine or unit tag (as a string) and
 // returns the associated SSHMachine.
 func (b *backend) GetMachineForEntity(tagString string) (SSHMachine, error) {
@@ -8,9 +8,11 @@ import (
 	"gopkg.in/juju/names.v2"
 
 	"github.com/juju/juju/apiserver/facade"
+	caasall "github.com/juju/juju/caas/all"
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/state/stateenvirons"
+	"github.com/juju/juju/storage"
 	"github.com/juju/juju/storage/poolmanager"
 )
 
@@ -25,11 +27,10 @@ func NewFacadeV4(
 	resources facade.Resources,
 	authorizer facade.Authorizer,
 ) (*APIv4, error) {
-	env, err := stateenvirons.GetNewEnvironFunc(environs.New)(st)
+	registry, err := storageProviderRegistry(st)
 	if err != nil {
-		return nil, errors.Annotate(err, "getting environ")
+		return nil, errors.Trace(err)
 	}
-	registry := stateenvirons.NewStorageProviderRegistry(env)
 	pm := poolmanager.New(state.NewStateSettings(st), registry)
 
 	backend, err := getState(st)
@@ -45,11 +46,10 @@ func NewFacadeV3(
 	resources facade.Resources,
 	authorizer facade.Authorizer,
 ) (*APIv3, error) {
-	env, err := stateenvirons.GetNewEnvironFunc(environs.New)(st)
+	registry, err := storageProviderRegistry(st)
 	if err != nil {
-		return nil, errors.Annotate(err, "getting environ")
+		return nil, errors.Trace(err)
 	}
-	registry := stateenvirons.NewStorageProviderRegistry(env)
 	pm := poolmanager.New(state.NewStateSettings(st), registry)
 
 	backend, err := getState(st)
@@ -59,6 +59,28 @@ func NewFacadeV3(
 	return NewAPIv3(backend, registry, pm, resources, authorizer)
 }
 
+// storageProviderRegistry returns the registry of the storage providers
+// usable in the model: those of the CAAS substrate for a CAAS model, or
+// those of the environ along with the common providers otherwise.
+func storageProviderRegistry(st *state.State) (storage.ProviderRegistry, error) {
+	model, err := st.Model()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if model.Type() == state.ModelTypeCAAS {
+		broker, err := stateenvirons.GetNewCAASBrokerFunc(caasall.NewContainerBroker)(st)
+		if err != nil {
+			return nil, errors.Annotate(err, "getting CAAS broker")
+		}
+		return broker, nil
+	}
+	env, err := stateenvirons.GetNewEnvironFunc(environs.New)(st)
+	if err != nil {
+		return nil, errors.Annotate(err, "getting environ")
+	}
+	return stateenvirons.NewStorageProviderRegistry(env), nil
+}
+
 type storageAccess interface {
 	// StorageInstance is required for storage functionality.
 	StorageInstance(names.StorageTag) (state.StorageInstance, error)
@@ -161,7 +183,14 @@ type storageAccess interface {
 }
 
 var getState = func(st *state.State) (storageAccess, error) {
-	im, err := st.IAASModel()
+	m, err := st.Model()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if m.Type() == state.ModelTypeCAAS {
+		return caasStateShim{State: st, model: m}, nil
+	}
+	im, err := m.IAASModel()
 	if err != nil {
 		return nil, err
 	}
@@ -199,3 +228,166 @@ func (s stateShim) ModelName() (string, error) {
 	}
 	return cfg.Name(), nil
 }
+
+// caasStateShim is the storage backend of a CAAS model. The storage of
+// CAAS units is provisioned by the substrate rather than being tracked
+// as model storage, so only storage pools may be managed; there is no
+// model storage to list, and other operations are not supported.
+type caasStateShim struct {
+	*state.State
+	model *state.Model
+}
+
+var errCAASStorage = errors.NotSupportedf("storage operations on a CAAS model")
+
+func (s caasStateShim) ControllerTag() names.ControllerTag {
+	return s.model.ControllerTag()
+}
+
+func (s caasStateShim) ModelTag() names.ModelTag {
+	return s.model.ModelTag()
+}
+
+func (s caasStateShim) ModelName() (string, error) {
+	return s.model.Name(), nil
+}
+
+func (caasStateShim) StorageInstance(names.StorageTag) (state.StorageInstance, error) {
+	return nil, errCAASStorage
+}
+
+func (caasStateShim) AllStorageInstances() ([]state.StorageInstance, error) {
+	return nil, nil
+}
+
+func (caasStateShim) StorageAttachments(names.StorageTag) ([]state.StorageAttachment, error) {
+	return nil, errCAASStorage
+}
+
+func (caasStateShim) UnitAssignedMachine(names.UnitTag) (names.MachineTag, error) {
+	return names.MachineTag{}, errCAASStorage
+}
+
+func (caasStateShim) FilesystemAttachment(names.MachineTag, names.FilesystemTag) (state.FilesystemAttachment, error) {
+	return nil, errCAASStorage
+}
+
+func (caasStateShim) StorageInstanceFilesystem(names.StorageTag) (state.Filesystem, error) {
+	return nil, errCAASStorage
+}
+
+func (caasStateShim) StorageInstanceVolume(names.StorageTag) (state.Volume, error) {
+	return nil, errCAASStorage
+}
+
+func (caasStateShim) VolumeAttachment(names.MachineTag, names.VolumeTag) (state.VolumeAttachment, error) {
+	return nil, errCAASStorage
+}
+
+func (caasStateShim) WatchStorageAttachment(names.StorageTag, names.UnitTag) state.NotifyWatcher {
+	return notSupportedWatcher{}
+}
+
+func (caasStateShim) WatchFilesystemAttachment(names.MachineTag, names.FilesystemTag) state.NotifyWatcher {
+	return notSupportedWatcher{}
+}
+
+func (caasStateShim) WatchVolumeAttachment(names.MachineTag, names.VolumeTag) state.NotifyWatcher {
+	return notSupportedWatcher{}
+}
+
+func (caasStateShim) WatchBlockDevices(names.MachineTag) state.NotifyWatcher {
+	return notSupportedWatcher{}
+}
+
+func (caasStateShim) BlockDevices(names.MachineTag) ([]state.BlockDeviceInfo, error) {
+	return nil, errCAASStorage
+}
+
+func (caasStateShim) AllVolumes() ([]state.Volume, error) {
+	return nil, nil
+}
+
+func (caasStateShim) VolumeAttachments(names.VolumeTag) ([]state.VolumeAttachment, error) {
+	return nil, errCAASStorage
+}
+
+func (caasStateShim) MachineVolumeAttachments(names.MachineTag) ([]state.VolumeAttachment, error) {
+	return nil, errCAASStorage
+}
+
+func (caasStateShim) Volume(names.VolumeTag) (state.Volume, error) {
+	return nil, errCAASStorage
+}
+
+func (caasStateShim) AllFilesystems() ([]state.Filesystem, error) {
+	return nil, nil
+}
+
+func (caasStateShim) FilesystemAttachments(names.FilesystemTag) ([]state.FilesystemAttachment, error) {
+	return nil, errCAASStorage
+}
+
+func (caasStateShim) MachineFilesystemAttachments(names.MachineTag) ([]state.FilesystemAttachment, error) {
+	return nil, errCAASStorage
+}
+
+func (caasStateShim) Filesystem(names.FilesystemTag) (state.Filesystem, error) {
+	return nil, errCAASStorage
+}
+
+func (caasStateShim) AddStorageForUnit(names.UnitTag, string, state.StorageConstraints) ([]names.StorageTag, error) {
+	return nil, errCAASStorage
+}
+
+func (caasStateShim) AttachStorage(names.StorageTag, names.UnitTag) error {
+	return errCAASStorage
+}
+
+func (caasStateShim) DetachStorage(names.StorageTag, names.UnitTag) error {
+	return errCAASStorage
+}
+
+func (caasStateShim) DestroyStorageInstance(names.StorageTag, bool) error {
+	return errCAASStorage
+}
+
+func (caasStateShim) ReleaseStorageInstance(names.StorageTag, bool) error {
+	return errCAASStorage
+}
+
+func (caasStateShim) UnitStorageAttachments(names.UnitTag) ([]state.StorageAttachment, error) {
+	return nil, errCAASStorage
+}
+
+func (caasStateShim) AddExistingFilesystem(state.FilesystemInfo, *state.VolumeInfo, string) (names.StorageTag, error) {
+	return names.StorageTag{}, errCAASStorage
+}
+
+// notSupportedWatcher is the watcher returned for the storage
+// of a CAAS model. It is born dead, failing with errCAASStorage.
+type notSupportedWatcher struct{}
+
+var closedChanges = func() chan struct{} {
+	ch := make(chan struct{})
+	close(ch)
+	return ch
+}()
+
+func (notSupportedWatcher) Kill() {}
+
+func (notSupportedWatcher) Wait() error {
+	return errCAASStorage
+}
+
+func (notSupportedWatcher) Stop() error {
+	return errCAASStorage
+}
+
+func (notSupportedWatcher) Err() error {
+	return errCAASStorage
+}
+
+func (notSupportedWatcher) Changes() <-chan struct{} {
+	return closedChanges
+}
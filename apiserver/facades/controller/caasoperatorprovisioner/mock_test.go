@@ -9,21 +9,38 @@ import (
 	"gopkg.in/juju/names.v2"
 	"gopkg.in/tomb.v1"
 
+	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/state"
+	coretesting "github.com/juju/juju/testing"
 )
 
 type mockState struct {
 	testing.Stub
 	applicationWatcher *mockStringsWatcher
+	modelConfigWatcher *mockNotifyWatcher
 	app                *mockApplication
 }
 
 func newMockState() *mockState {
 	return &mockState{
 		applicationWatcher: newMockStringsWatcher(),
+		modelConfigWatcher: newMockNotifyWatcher(),
 	}
 }
 
+func (st *mockState) WatchForModelConfigChanges() state.NotifyWatcher {
+	st.MethodCall(st, "WatchForModelConfigChanges")
+	return st.modelConfigWatcher
+}
+
+func (st *mockState) ModelConfig() (*config.Config, error) {
+	st.MethodCall(st, "ModelConfig")
+	if err := st.NextErr(); err != nil {
+		return nil, err
+	}
+	return config.New(config.UseDefaults, coretesting.FakeConfig())
+}
+
 func (st *mockState) WatchApplications() state.StringsWatcher {
 	st.MethodCall(st, "WatchApplications")
 	return st.applicationWatcher
@@ -90,3 +107,19 @@ func (w *mockStringsWatcher) Changes() <-chan []string {
 	w.MethodCall(w, "Changes")
 	return w.changes
 }
+
+type mockNotifyWatcher struct {
+	mockWatcher
+	changes chan struct{}
+}
+
+func newMockNotifyWatcher() *mockNotifyWatcher {
+	w := &mockNotifyWatcher{changes: make(chan struct{}, 1)}
+	go w.doneWhenDying()
+	return w
+}
+
+func (w *mockNotifyWatcher) Changes() <-chan struct{} {
+	w.MethodCall(w, "Changes")
+	return w.changes
+}
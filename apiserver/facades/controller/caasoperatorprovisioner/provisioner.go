@@ -4,6 +4,8 @@
 package caasoperatorprovisioner
 
 import (
+	"github.com/juju/errors"
+
 	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/facade"
 	"github.com/juju/juju/apiserver/params"
@@ -12,6 +14,7 @@ import (
 
 type API struct {
 	*common.PasswordChanger
+	*common.ModelWatcher
 
 	auth      facade.Authorizer
 	resources facade.Resources
@@ -24,7 +27,11 @@ func NewStateCAASOperatorProvisionerAPI(ctx facade.Context) (*API, error) {
 
 	authorizer := ctx.Auth()
 	resources := ctx.Resources()
-	return NewCAASOperatorProvisionerAPI(resources, authorizer, ctx.State())
+	model, err := ctx.State().Model()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return NewCAASOperatorProvisionerAPI(resources, authorizer, stateShim{ctx.State(), model})
 }
 
 // NewCAASOperatorProvisionerAPI returns a new CAAS operator provisioner API facade.
@@ -38,6 +45,7 @@ func NewCAASOperatorProvisionerAPI(
 	}
 	return &API{
 		PasswordChanger: common.NewPasswordChanger(st, common.AuthAlways()),
+		ModelWatcher:    common.NewModelWatcher(st, resources, authorizer),
 		auth:            authorizer,
 		resources:       resources,
 		state:           st,
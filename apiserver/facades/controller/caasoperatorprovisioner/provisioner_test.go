@@ -66,6 +66,25 @@ func (s *CAASProvisionerSuite) TestWatchApplications(c *gc.C) {
 	c.Assert(resource, gc.Implements, new(state.StringsWatcher))
 }
 
+func (s *CAASProvisionerSuite) TestWatchForModelConfigChanges(c *gc.C) {
+	s.st.modelConfigWatcher.changes <- struct{}{}
+	result, err := s.api.WatchForModelConfigChanges()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Error, gc.IsNil)
+	c.Assert(result.NotifyWatcherId, gc.Equals, "1")
+
+	resource := s.resources.Get("1")
+	c.Assert(resource, gc.NotNil)
+	c.Assert(resource, gc.Implements, new(state.NotifyWatcher))
+}
+
+func (s *CAASProvisionerSuite) TestModelConfig(c *gc.C) {
+	result, err := s.api.ModelConfig()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Config["name"], gc.Equals, "testenv")
+	s.st.CheckCallNames(c, "ModelConfig")
+}
+
 func (s *CAASProvisionerSuite) TestSetPasswords(c *gc.C) {
 	s.st.app = &mockApplication{
 		tag: names.NewApplicationTag("app"),
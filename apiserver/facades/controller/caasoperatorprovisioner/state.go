@@ -6,12 +6,27 @@ package caasoperatorprovisioner
 import (
 	"gopkg.in/juju/names.v2"
 
+	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/state"
 )
 
 // CAASOperatorProvisionerState provides the subset of global state
 // required by the CAAS operator provisioner facade.
 type CAASOperatorProvisionerState interface {
+	state.ModelAccessor
 	WatchApplications() state.StringsWatcher
 	FindEntity(tag names.Tag) (state.Entity, error)
 }
+
+type stateShim struct {
+	*state.State
+	model *state.Model
+}
+
+func (s stateShim) ModelConfig() (*config.Config, error) {
+	return s.model.ModelConfig()
+}
+
+func (s stateShim) WatchForModelConfigChanges() state.NotifyWatcher {
+	return s.model.WatchForModelConfigChanges()
+}
@@ -9,10 +9,13 @@ import (
 	"gopkg.in/juju/names.v2"
 
 	"github.com/juju/juju/apiserver/facades/controller/caasunitprovisioner"
+	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/core/application"
 	"github.com/juju/juju/state"
 	statetesting "github.com/juju/juju/state/testing"
 	"github.com/juju/juju/status"
+	"github.com/juju/juju/storage"
+	"github.com/juju/juju/storage/poolmanager"
 )
 
 type mockState struct {
@@ -59,6 +62,17 @@ func (st *mockState) Model() (caasunitprovisioner.Model, error) {
 	return &st.model, nil
 }
 
+type mockStoragePoolManager struct {
+	testing.Stub
+	poolmanager.PoolManager
+	pools []*storage.Config
+}
+
+func (m *mockStoragePoolManager) List() ([]*storage.Config, error) {
+	m.MethodCall(m, "List")
+	return m.pools, m.NextErr()
+}
+
 type mockModel struct {
 	testing.Stub
 	containerSpecWatcher *statetesting.MockNotifyWatcher
@@ -84,6 +98,10 @@ type mockApplication struct {
 	testing.Stub
 	life         state.Life
 	unitsWatcher *statetesting.MockStringsWatcher
+	// configWatcher is returned by WatchApplicationConfig.
+	configWatcher *statetesting.MockNotifyWatcher
+	// constraintsWatcher is returned by WatchConstraints.
+	constraintsWatcher *statetesting.MockNotifyWatcher
 
 	tag   names.Tag
 	units []caasunitprovisioner.Unit
@@ -104,11 +122,31 @@ func (a *mockApplication) WatchUnits() state.StringsWatcher {
 	return a.unitsWatcher
 }
 
+func (a *mockApplication) WatchApplicationConfig() state.NotifyWatcher {
+	a.MethodCall(a, "WatchApplicationConfig")
+	return a.configWatcher
+}
+
+func (a *mockApplication) WatchConstraints() state.NotifyWatcher {
+	a.MethodCall(a, "WatchConstraints")
+	return a.constraintsWatcher
+}
+
 func (a *mockApplication) ApplicationConfig() (application.ConfigAttributes, error) {
 	a.MethodCall(a, "ApplicationConfig")
 	return application.ConfigAttributes{"foo": "bar"}, a.NextErr()
 }
 
+func (a *mockApplication) Constraints() (constraints.Value, error) {
+	a.MethodCall(a, "Constraints")
+	return constraints.MustParse("mem=4G cpu-power=200"), a.NextErr()
+}
+
+func (a *mockApplication) Placement() string {
+	a.MethodCall(a, "Placement")
+	return "disktype=ssd"
+}
+
 func (m *mockApplication) AllUnits() (units []caasunitprovisioner.Unit, err error) {
 	return m.units, nil
 }
@@ -127,9 +165,10 @@ func (m *mockApplication) AddOperation(props state.UnitUpdateProperties) *state.
 
 type mockUnit struct {
 	testing.Stub
-	name       string
-	life       state.Life
-	providerId string
+	name          string
+	life          state.Life
+	providerId    string
+	containerInfo state.ContainerInfo
 }
 
 func (*mockUnit) Tag() names.Tag {
@@ -153,6 +192,10 @@ func (m *mockUnit) AgentStatus() (status.StatusInfo, error) {
 	return status.StatusInfo{Status: status.Allocating}, nil
 }
 
+func (m *mockUnit) ContainerInfo() state.ContainerInfo {
+	return m.containerInfo
+}
+
 var updateOp = &state.UpdateUnitOperation{}
 
 func (m *mockUnit) UpdateOperation(props state.UnitUpdateProperties) *state.UpdateUnitOperation {
@@ -8,32 +8,61 @@ import (
 
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
+	"github.com/juju/utils/set"
 	"gopkg.in/juju/names.v2"
 
 	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/facade"
 	"github.com/juju/juju/apiserver/params"
+	caasall "github.com/juju/juju/caas/all"
+	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/stateenvirons"
 	"github.com/juju/juju/state/watcher"
 	"github.com/juju/juju/status"
+	"github.com/juju/juju/storage/poolmanager"
 )
 
 var logger = loggo.GetLogger("juju.apiserver.controller.caasunitprovisioner")
 
+// FacadeV1 provides access to the version 1 CAAS unit provisioner
+// facade, which does not provide application constraints.
+type FacadeV1 struct {
+	*Facade
+}
+
+// Facade provides access to the CAAS unit provisioner facade.
 type Facade struct {
 	*common.LifeGetter
-	resources facade.Resources
-	state     CAASUnitProvisionerState
+	resources          facade.Resources
+	state              CAASUnitProvisionerState
+	storagePoolManager poolmanager.PoolManager
+}
+
+// NewStateFacadeV1 provides the signature required for version 1
+// facade registration.
+func NewStateFacadeV1(ctx facade.Context) (*FacadeV1, error) {
+	f, err := NewStateFacade(ctx)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &FacadeV1{f}, nil
 }
 
 // NewStateFacade provides the signature required for facade registration.
 func NewStateFacade(ctx facade.Context) (*Facade, error) {
 	authorizer := ctx.Auth()
 	resources := ctx.Resources()
+	broker, err := stateenvirons.GetNewCAASBrokerFunc(caasall.NewContainerBroker)(ctx.State())
+	if err != nil {
+		return nil, errors.Annotate(err, "getting caas client")
+	}
+	pm := poolmanager.New(state.NewStateSettings(ctx.State()), broker)
 	return NewFacade(
 		resources,
 		authorizer,
 		stateShim{ctx.State()},
+		pm,
 	)
 }
 
@@ -42,6 +71,7 @@ func NewFacade(
 	resources facade.Resources,
 	authorizer facade.Authorizer,
 	st CAASUnitProvisionerState,
+	storagePoolManager poolmanager.PoolManager,
 ) (*Facade, error) {
 	if !authorizer.AuthController() {
 		return nil, common.ErrPerm
@@ -53,8 +83,9 @@ func NewFacade(
 				common.AuthFuncForTagKind(names.UnitTagKind),
 			),
 		),
-		resources: resources,
-		state:     st,
+		resources:          resources,
+		state:              st,
+		storagePoolManager: storagePoolManager,
 	}, nil
 }
 
@@ -170,6 +201,43 @@ func (f *Facade) containerSpec(model Model, tagString string) (string, error) {
 	return model.ContainerSpec(tag)
 }
 
+// WatchApplicationsConfig starts a NotifyWatcher to watch changes
+// to the config and constraints of the specified applications in
+// this model.
+func (f *Facade) WatchApplicationsConfig(args params.Entities) (params.NotifyWatchResults, error) {
+	results := params.NotifyWatchResults{
+		Results: make([]params.NotifyWatchResult, len(args.Entities)),
+	}
+	for i, arg := range args.Entities {
+		id, err := f.watchApplicationConfig(arg.Tag)
+		if err != nil {
+			results.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		results.Results[i].NotifyWatcherId = id
+	}
+	return results, nil
+}
+
+func (f *Facade) watchApplicationConfig(tagString string) (string, error) {
+	tag, err := names.ParseApplicationTag(tagString)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	app, err := f.state.Application(tag.Id())
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	w := common.NewMultiNotifyWatcher(
+		app.WatchApplicationConfig(),
+		app.WatchConstraints(),
+	)
+	if _, ok := <-w.Changes(); ok {
+		return f.resources.Register(w), nil
+	}
+	return "", watcher.EnsureErr(w)
+}
+
 // ApplicationsConfig returns the config for the specified applications.
 func (f *Facade) ApplicationsConfig(args params.Entities) (params.ApplicationGetConfigResults, error) {
 	results := params.ApplicationGetConfigResults{
@@ -195,6 +263,96 @@ func (f *Facade) getApplicationConfig(tagString string) (map[string]interface{},
 	return app.ApplicationConfig()
 }
 
+// ApplicationsConstraints returns the constraints for the specified applications.
+func (f *Facade) ApplicationsConstraints(args params.Entities) (params.ConstraintsResults, error) {
+	results := params.ConstraintsResults{
+		Results: make([]params.ConstraintsResult, len(args.Entities)),
+	}
+	for i, arg := range args.Entities {
+		cons, err := f.getApplicationConstraints(arg.Tag)
+		if err != nil {
+			results.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		results.Results[i].Constraints = cons
+	}
+	return results, nil
+}
+
+func (f *Facade) getApplicationConstraints(tagString string) (constraints.Value, error) {
+	tag, err := names.ParseApplicationTag(tagString)
+	if err != nil {
+		return constraints.Value{}, errors.Trace(err)
+	}
+	app, err := f.state.Application(tag.Id())
+	if err != nil {
+		return constraints.Value{}, errors.Trace(err)
+	}
+	return app.Constraints()
+}
+
+// Mask the new methods from the V1 API. The API reflection code in
+// rpc/rpcreflect/type.go:newMethod skips 2-argument methods, so this
+// removes the method as far as the RPC machinery is concerned.
+
+// ApplicationsConstraints isn't on the V1 API.
+func (*FacadeV1) ApplicationsConstraints(_, _ struct{}) {}
+
+// ApplicationsPlacement returns the placement directives
+// for the specified applications.
+func (f *Facade) ApplicationsPlacement(args params.Entities) (params.StringResults, error) {
+	results := params.StringResults{
+		Results: make([]params.StringResult, len(args.Entities)),
+	}
+	for i, arg := range args.Entities {
+		tag, err := names.ParseApplicationTag(arg.Tag)
+		if err != nil {
+			results.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		app, err := f.state.Application(tag.Id())
+		if err != nil {
+			results.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		results.Results[i].Result = app.Placement()
+	}
+	return results, nil
+}
+
+// StoragePools returns the storage pools in the model matching
+// the specified filters. An empty filter matches all pools.
+func (f *Facade) StoragePools(args params.StoragePoolFilters) (params.StoragePoolsResults, error) {
+	results := params.StoragePoolsResults{
+		Results: make([]params.StoragePoolsResult, len(args.Filters)),
+	}
+	if len(args.Filters) == 0 {
+		return results, nil
+	}
+	all, err := f.storagePoolManager.List()
+	if err != nil {
+		return params.StoragePoolsResults{}, errors.Trace(err)
+	}
+	for i, filter := range args.Filters {
+		poolNames := set.NewStrings(filter.Names...)
+		providers := set.NewStrings(filter.Providers...)
+		for _, cfg := range all {
+			if !poolNames.IsEmpty() && !poolNames.Contains(cfg.Name()) {
+				continue
+			}
+			if !providers.IsEmpty() && !providers.Contains(string(cfg.Provider())) {
+				continue
+			}
+			results.Results[i].Result = append(results.Results[i].Result, params.StoragePool{
+				Name:     cfg.Name(),
+				Provider: string(cfg.Provider()),
+				Attrs:    cfg.Attrs(),
+			})
+		}
+	}
+	return results, nil
+}
+
 // UpdateApplicationsUnits updates the Juju data model to reflect the given
 // units of the specified application.
 func (a *Facade) UpdateApplicationsUnits(args params.UpdateApplicationUnitArgs) (params.ErrorResults, error) {
@@ -215,7 +373,7 @@ func (a *Facade) UpdateApplicationsUnits(args params.UpdateApplicationUnitArgs)
 			result.Results[i].Error = common.ServerError(err)
 			continue
 		}
-		if err := a.updateUnitsFromCloud(app, appUpdate.Units); err != nil {
+		if err := a.updateUnitsFromCloud(app, appUpdate.Units, appUpdate.Autoscaled); err != nil {
 			result.Results[i].Error = common.ServerError(err)
 		}
 	}
@@ -226,7 +384,9 @@ func (a *Facade) UpdateApplicationsUnits(args params.UpdateApplicationUnitArgs)
 // source (typically a cloud update event) and merges that with the existing unit
 // data model in state. The passed in units are the complete set for the cloud, so
 // any existing units in state with provider ids which aren't in the set will be removed.
-func (a *Facade) updateUnitsFromCloud(app Application, units []params.ApplicationUnitParams) error {
+// If the units are autoscaled, the cloud decides how many there are, so any units in
+// state left without a provider id will also be removed.
+func (a *Facade) updateUnitsFromCloud(app Application, units []params.ApplicationUnitParams, autoscaled bool) error {
 	// Set up the initial data structures.
 	existingStateUnits, err := app.AllUnits()
 	if err != nil {
@@ -295,9 +455,10 @@ func (a *Facade) updateUnitsFromCloud(app Application, units []params.Applicatio
 
 	unitUpdateProperties := func(unitParams params.ApplicationUnitParams) state.UnitUpdateProperties {
 		return state.UnitUpdateProperties{
-			ProviderId: unitParams.Id,
-			Address:    unitParams.Address,
-			Ports:      unitParams.Ports,
+			ProviderId:  unitParams.Id,
+			Address:     unitParams.Address,
+			Ports:       unitParams.Ports,
+			Filesystems: containerFilesystems(unitParams.FilesystemInfo),
 			Status: &status.StatusInfo{
 				Status:  status.Status(unitParams.Status),
 				Message: unitParams.Info,
@@ -317,6 +478,10 @@ func (a *Facade) updateUnitsFromCloud(app Application, units []params.Applicatio
 			reflect.DeepEqual(existingStatus.Data, params.Data) {
 			return true, nil
 		}
+		filesystems := containerFilesystems(params.FilesystemInfo)
+		if !reflect.DeepEqual(u.ContainerInfo().Filesystems, filesystems) {
+			return true, nil
+		}
 		return false, nil
 	}
 
@@ -351,5 +516,32 @@ func (a *Facade) updateUnitsFromCloud(app Application, units []params.Applicatio
 		unitUpdate.Adds = append(unitUpdate.Adds,
 			app.AddOperation(unitUpdateProperties(unitParams)))
 	}
+	if autoscaled {
+		for _, u := range unassociatedUnits[idx:] {
+			logger.Debugf("unit %q has been scaled down in the cloud", u.Name())
+			unitUpdate.Deletes = append(unitUpdate.Deletes, u.DestroyOperation())
+		}
+	}
 	return app.UpdateUnits(&unitUpdate)
 }
+
+// containerFilesystems returns the state representation
+// of the filesystems mounted into a unit's container.
+func containerFilesystems(filesystems []params.UnitFilesystemInfo) []state.ContainerFilesystem {
+	var result []state.ContainerFilesystem
+	for _, fs := range filesystems {
+		result = append(result, state.ContainerFilesystem{
+			StorageName:  fs.StorageName,
+			FilesystemId: fs.FilesystemId,
+			Size:         fs.Size,
+			MountPoint:   fs.MountPoint,
+			ReadOnly:     fs.ReadOnly,
+			Status:       status.Status(fs.Status),
+			StatusInfo:   fs.Info,
+			VolumeId:     fs.Volume.VolumeId,
+			VolumeSize:   fs.Volume.Size,
+			Persistent:   fs.Volume.Persistent,
+		})
+	}
+	return result
+}
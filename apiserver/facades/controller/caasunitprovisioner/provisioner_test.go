@@ -4,6 +4,8 @@
 package caasunitprovisioner_test
 
 import (
+	"time"
+
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 	"gopkg.in/juju/names.v2"
@@ -12,9 +14,11 @@ import (
 	"github.com/juju/juju/apiserver/facades/controller/caasunitprovisioner"
 	"github.com/juju/juju/apiserver/params"
 	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/state"
 	statetesting "github.com/juju/juju/state/testing"
 	"github.com/juju/juju/status"
+	"github.com/juju/juju/storage"
 	coretesting "github.com/juju/juju/testing"
 	"github.com/juju/juju/worker/workertest"
 )
@@ -27,11 +31,14 @@ type CAASProvisionerSuite struct {
 	st                   *mockState
 	applicationsChanges  chan []string
 	containerSpecChanges chan struct{}
+	configChanges        chan struct{}
+	constraintsChanges   chan struct{}
 	unitsChanges         chan []string
 
-	resources  *common.Resources
-	authorizer *apiservertesting.FakeAuthorizer
-	facade     *caasunitprovisioner.Facade
+	resources          *common.Resources
+	authorizer         *apiservertesting.FakeAuthorizer
+	storagePoolManager *mockStoragePoolManager
+	facade             *caasunitprovisioner.Facade
 }
 
 func (s *CAASProvisionerSuite) SetUpTest(c *gc.C) {
@@ -39,12 +46,16 @@ func (s *CAASProvisionerSuite) SetUpTest(c *gc.C) {
 
 	s.applicationsChanges = make(chan []string, 1)
 	s.containerSpecChanges = make(chan struct{}, 1)
+	s.configChanges = make(chan struct{}, 1)
+	s.constraintsChanges = make(chan struct{}, 1)
 	s.unitsChanges = make(chan []string, 1)
 	s.st = &mockState{
 		application: mockApplication{
-			tag:          names.NewApplicationTag("gitlab"),
-			life:         state.Alive,
-			unitsWatcher: statetesting.NewMockStringsWatcher(s.unitsChanges),
+			tag:                names.NewApplicationTag("gitlab"),
+			life:               state.Alive,
+			unitsWatcher:       statetesting.NewMockStringsWatcher(s.unitsChanges),
+			configWatcher:      statetesting.NewMockNotifyWatcher(s.configChanges),
+			constraintsWatcher: statetesting.NewMockNotifyWatcher(s.constraintsChanges),
 		},
 		applicationsWatcher: statetesting.NewMockStringsWatcher(s.applicationsChanges),
 		model: mockModel{
@@ -57,6 +68,8 @@ func (s *CAASProvisionerSuite) SetUpTest(c *gc.C) {
 	s.AddCleanup(func(c *gc.C) { workertest.DirtyKill(c, s.st.applicationsWatcher) })
 	s.AddCleanup(func(c *gc.C) { workertest.DirtyKill(c, s.st.application.unitsWatcher) })
 	s.AddCleanup(func(c *gc.C) { workertest.DirtyKill(c, s.st.model.containerSpecWatcher) })
+	s.AddCleanup(func(c *gc.C) { workertest.DirtyKill(c, s.st.application.configWatcher) })
+	s.AddCleanup(func(c *gc.C) { workertest.DirtyKill(c, s.st.application.constraintsWatcher) })
 
 	s.resources = common.NewResources()
 	s.authorizer = &apiservertesting.FakeAuthorizer{
@@ -64,7 +77,13 @@ func (s *CAASProvisionerSuite) SetUpTest(c *gc.C) {
 		Controller: true,
 	}
 
-	facade, err := caasunitprovisioner.NewFacade(s.resources, s.authorizer, s.st)
+	ebs, err := storage.NewConfig("ebs", "kubernetes", map[string]interface{}{"storage-class": "gp2"})
+	c.Assert(err, jc.ErrorIsNil)
+	fast, err := storage.NewConfig("fast", "kubernetes", map[string]interface{}{"storage-provisioner": "kubernetes.io/gce-pd"})
+	c.Assert(err, jc.ErrorIsNil)
+	s.storagePoolManager = &mockStoragePoolManager{pools: []*storage.Config{ebs, fast}}
+
+	facade, err := caasunitprovisioner.NewFacade(s.resources, s.authorizer, s.st, s.storagePoolManager)
 	c.Assert(err, jc.ErrorIsNil)
 	s.facade = facade
 }
@@ -73,7 +92,7 @@ func (s *CAASProvisionerSuite) TestPermission(c *gc.C) {
 	s.authorizer = &apiservertesting.FakeAuthorizer{
 		Tag: names.NewMachineTag("0"),
 	}
-	_, err := caasunitprovisioner.NewFacade(s.resources, s.authorizer, s.st)
+	_, err := caasunitprovisioner.NewFacade(s.resources, s.authorizer, s.st, s.storagePoolManager)
 	c.Assert(err, gc.ErrorMatches, "permission denied")
 }
 
@@ -175,6 +194,40 @@ func (s *CAASProvisionerSuite) TestLife(c *gc.C) {
 	})
 }
 
+func (s *CAASProvisionerSuite) TestWatchApplicationsConfig(c *gc.C) {
+	s.configChanges <- struct{}{}
+	s.constraintsChanges <- struct{}{}
+
+	results, err := s.facade.WatchApplicationsConfig(params.Entities{
+		Entities: []params.Entity{
+			{Tag: "application-gitlab"},
+			{Tag: "unit-gitlab-0"},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 2)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(results.Results[1].Error, jc.DeepEquals, &params.Error{
+		Message: `"unit-gitlab-0" is not a valid application tag`,
+	})
+
+	c.Assert(results.Results[0].NotifyWatcherId, gc.Equals, "1")
+	w, ok := s.resources.Get("1").(state.NotifyWatcher)
+	c.Assert(ok, jc.IsTrue)
+	defer workertest.CleanKill(c, w)
+
+	// Changes to both config and constraints are notified.
+	for _, changes := range []chan struct{}{s.configChanges, s.constraintsChanges} {
+		changes <- struct{}{}
+		select {
+		case _, ok := <-w.Changes():
+			c.Assert(ok, jc.IsTrue)
+		case <-time.After(coretesting.LongWait):
+			c.Fatal("timed out waiting for change")
+		}
+	}
+}
+
 func (s *CAASProvisionerSuite) TestApplicationConfig(c *gc.C) {
 	results, err := s.facade.ApplicationsConfig(params.Entities{
 		Entities: []params.Entity{
@@ -191,6 +244,65 @@ func (s *CAASProvisionerSuite) TestApplicationConfig(c *gc.C) {
 	c.Assert(results.Results[0].Config, jc.DeepEquals, map[string]interface{}{"foo": "bar"})
 }
 
+func (s *CAASProvisionerSuite) TestApplicationConstraints(c *gc.C) {
+	results, err := s.facade.ApplicationsConstraints(params.Entities{
+		Entities: []params.Entity{
+			{Tag: "application-gitlab"},
+			{Tag: "unit-gitlab-0"},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 2)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(results.Results[1].Error, jc.DeepEquals, &params.Error{
+		Message: `"unit-gitlab-0" is not a valid application tag`,
+	})
+	c.Assert(results.Results[0].Constraints, jc.DeepEquals, constraints.MustParse("mem=4G cpu-power=200"))
+}
+
+func (s *CAASProvisionerSuite) TestApplicationPlacement(c *gc.C) {
+	results, err := s.facade.ApplicationsPlacement(params.Entities{
+		Entities: []params.Entity{
+			{Tag: "application-gitlab"},
+			{Tag: "unit-gitlab-0"},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.StringResults{
+		Results: []params.StringResult{{
+			Result: "disktype=ssd",
+		}, {
+			Error: &params.Error{
+				Message: `"unit-gitlab-0" is not a valid application tag`,
+			},
+		}},
+	})
+}
+
+func (s *CAASProvisionerSuite) TestStoragePools(c *gc.C) {
+	results, err := s.facade.StoragePools(params.StoragePoolFilters{
+		Filters: []params.StoragePoolFilter{
+			{Names: []string{"fast"}},
+			{Providers: []string{"kubernetes"}},
+			{Names: []string{"missing"}},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.StoragePoolsResults{
+		Results: []params.StoragePoolsResult{{
+			Result: []params.StoragePool{
+				{Name: "fast", Provider: "kubernetes", Attrs: map[string]interface{}{"storage-provisioner": "kubernetes.io/gce-pd"}},
+			},
+		}, {
+			Result: []params.StoragePool{
+				{Name: "ebs", Provider: "kubernetes", Attrs: map[string]interface{}{"storage-class": "gp2"}},
+				{Name: "fast", Provider: "kubernetes", Attrs: map[string]interface{}{"storage-provisioner": "kubernetes.io/gce-pd"}},
+			},
+		}, {}},
+	})
+	s.storagePoolManager.CheckCallNames(c, "List")
+}
+
 func (s *CAASProvisionerSuite) TestUpdateApplicationsUnits(c *gc.C) {
 	s.st.application.units = []caasunitprovisioner.Unit{
 		&mockUnit{name: "gitlab/0", providerId: "uuid", life: state.Alive},
@@ -240,3 +352,84 @@ func (s *CAASProvisionerSuite) TestUpdateApplicationsUnits(c *gc.C) {
 	})
 	s.st.application.units[2].(*mockUnit).CheckCallNames(c, "Life", "DestroyOperation")
 }
+
+func (s *CAASProvisionerSuite) TestUpdateApplicationsUnitsFilesystems(c *gc.C) {
+	s.st.application.units = []caasunitprovisioner.Unit{
+		&mockUnit{name: "gitlab/0", providerId: "uuid", life: state.Alive},
+	}
+
+	units := []params.ApplicationUnitParams{{
+		Id: "uuid", Address: "address", Ports: []string{"port"},
+		Status: "running", Info: "message",
+		FilesystemInfo: []params.UnitFilesystemInfo{{
+			StorageName:  "data",
+			FilesystemId: "claim-uuid",
+			Size:         1024,
+			MountPoint:   "/var/opt/gitlab",
+			Status:       "attached",
+			Volume: params.UnitVolumeInfo{
+				VolumeId:   "pv-1",
+				Size:       2048,
+				Persistent: true,
+			},
+		}},
+	}}
+	args := params.UpdateApplicationUnitArgs{
+		Args: []params.UpdateApplicationUnits{
+			{ApplicationTag: "application-gitlab", Units: units},
+		},
+	}
+	results, err := s.facade.UpdateApplicationsUnits(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.OneError(), jc.ErrorIsNil)
+	s.st.application.units[0].(*mockUnit).CheckCallNames(c, "Life", "UpdateOperation")
+	s.st.application.units[0].(*mockUnit).CheckCall(c, 1, "UpdateOperation", state.UnitUpdateProperties{
+		ProviderId: "uuid",
+		Address:    "address", Ports: []string{"port"},
+		Filesystems: []state.ContainerFilesystem{{
+			StorageName:  "data",
+			FilesystemId: "claim-uuid",
+			Size:         1024,
+			MountPoint:   "/var/opt/gitlab",
+			Status:       status.Attached,
+			VolumeId:     "pv-1",
+			VolumeSize:   2048,
+			Persistent:   true,
+		}},
+		Status: &status.StatusInfo{Status: status.Running, Message: "message"},
+	})
+}
+
+func (s *CAASProvisionerSuite) TestUpdateApplicationsUnitsAutoscaled(c *gc.C) {
+	s.st.application.units = []caasunitprovisioner.Unit{
+		&mockUnit{name: "gitlab/0", providerId: "uuid", life: state.Alive},
+		&mockUnit{name: "gitlab/1", life: state.Alive},
+		&mockUnit{name: "gitlab/2", life: state.Alive},
+	}
+
+	units := []params.ApplicationUnitParams{
+		{Id: "uuid", Address: "address", Ports: []string{"port"},
+			Status: "running", Info: "message"},
+		{Id: "another-uuid", Address: "another-address", Ports: []string{"another-port"},
+			Status: "running", Info: "another message"},
+	}
+	args := params.UpdateApplicationUnitArgs{
+		Args: []params.UpdateApplicationUnits{
+			{ApplicationTag: "application-gitlab", Units: units, Autoscaled: true},
+		},
+	}
+	results, err := s.facade.UpdateApplicationsUnits(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.OneError(), jc.ErrorIsNil)
+	s.st.application.CheckNoCalls(c)
+	s.st.application.units[0].(*mockUnit).CheckCallNames(c, "Life", "UpdateOperation")
+	s.st.application.units[1].(*mockUnit).CheckCallNames(c, "Life", "UpdateOperation")
+	s.st.application.units[1].(*mockUnit).CheckCall(c, 1, "UpdateOperation", state.UnitUpdateProperties{
+		ProviderId: "another-uuid",
+		Address:    "another-address", Ports: []string{"another-port"},
+		Status: &status.StatusInfo{Status: status.Running, Message: "another message"},
+	})
+	// The autoscaler has scaled the application down,
+	// so the unit with no pod is removed.
+	s.st.application.units[2].(*mockUnit).CheckCallNames(c, "Life", "DestroyOperation")
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/juju/errors"
 	"gopkg.in/juju/names.v2"
 
+	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/core/application"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/status"
@@ -32,7 +33,11 @@ type Model interface {
 // required by the CAAS operator facade.
 type Application interface {
 	WatchUnits() state.StringsWatcher
+	WatchApplicationConfig() state.NotifyWatcher
+	WatchConstraints() state.NotifyWatcher
 	ApplicationConfig() (application.ConfigAttributes, error)
+	Constraints() (constraints.Value, error)
+	Placement() string
 	AllUnits() (units []Unit, err error)
 	AddOperation(state.UnitUpdateProperties) *state.AddUnitOperation
 	UpdateUnits(*state.UpdateUnitsOperation) error
@@ -79,6 +84,7 @@ type Unit interface {
 	Life() state.Life
 	ProviderId() string
 	AgentStatus() (status.StatusInfo, error)
+	ContainerInfo() state.ContainerInfo
 	UpdateOperation(props state.UnitUpdateProperties) *state.UpdateUnitOperation
 	DestroyOperation() *state.DestroyUnitOperation
 }
@@ -51,6 +51,11 @@ type FirewallerAPIV5 struct {
 	*FirewallerAPIV4
 }
 
+// FirewallerAPIV6 provides access to the Firewaller v6 API facade.
+type FirewallerAPIV6 struct {
+	*FirewallerAPIV5
+}
+
 // NewStateFirewallerAPIV3 creates a new server-side FirewallerAPIV3 facade.
 func NewStateFirewallerAPIV3(context facade.Context) (*FirewallerAPIV3, error) {
 	st := context.State()
@@ -90,6 +95,17 @@ func NewStateFirewallerAPIV5(context facade.Context) (*FirewallerAPIV5, error) {
 	}, nil
 }
 
+// NewStateFirewallerAPIV6 creates a new server-side FirewallerAPIV6 facade.
+func NewStateFirewallerAPIV6(context facade.Context) (*FirewallerAPIV6, error) {
+	facadev5, err := NewStateFirewallerAPIV5(context)
+	if err != nil {
+		return nil, err
+	}
+	return &FirewallerAPIV6{
+		FirewallerAPIV5: facadev5,
+	}, nil
+}
+
 // NewFirewallerAPI creates a new server-side FirewallerAPIV3 facade.
 func NewFirewallerAPI(
 	st State,
@@ -532,3 +548,16 @@ func (f *FirewallerAPIV5) AreManuallyProvisioned(args params.Entities) (params.B
 	}
 	return result, nil
 }
+
+// WatchFirewallRules returns a NotifyWatcher which triggers
+// whenever the model's firewall rules change.
+func (f *FirewallerAPIV6) WatchFirewallRules() (params.NotifyWatchResult, error) {
+	var result params.NotifyWatchResult
+	w := f.st.WatchFirewallRules()
+	// Consume the initial event.
+	if _, ok := <-w.Changes(); !ok {
+		return result, common.ServerError(watcher.EnsureErr(w))
+	}
+	result.NotifyWatcherId = f.resources.Register(w)
+	return result, nil
+}
@@ -134,3 +134,15 @@ func (s *RemoteFirewallerSuite) TestFirewallRules(c *gc.C) {
 	c.Assert(result.Rules[0].KnownService, gc.Equals, params.KnownServiceValue("juju-application-offer"))
 	c.Assert(result.Rules[0].WhitelistCIDRS, jc.SameContents, []string{"192.168.0.0/16"})
 }
+
+func (s *RemoteFirewallerSuite) TestWatchFirewallRules(c *gc.C) {
+	api := &firewaller.FirewallerAPIV6{&firewaller.FirewallerAPIV5{s.api}}
+	result, err := api.WatchFirewallRules()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Error, gc.IsNil)
+	c.Assert(result.NotifyWatcherId, gc.Equals, "1")
+
+	resource := s.resources.Get("1")
+	c.Assert(resource, gc.Equals, s.st.rulesWatcher)
+	s.st.CheckCallNames(c, "WatchFirewallRules")
+}
@@ -42,6 +42,7 @@ type mockState struct {
 	firewallRules  map[state.WellKnownServiceType]*state.FirewallRule
 	subnetsWatcher *mockStringsWatcher
 	modelWatcher   *mockNotifyWatcher
+	rulesWatcher   *mockNotifyWatcher
 	configAttrs    map[string]interface{}
 }
 
@@ -55,6 +56,7 @@ func newMockState(modelUUID string) *mockState {
 		firewallRules:  make(map[state.WellKnownServiceType]*state.FirewallRule),
 		subnetsWatcher: newMockStringsWatcher(),
 		modelWatcher:   newMockNotifyWatcher(),
+		rulesWatcher:   newMockNotifyWatcher(),
 		configAttrs:    coretesting.FakeConfig(),
 	}
 }
@@ -120,6 +122,11 @@ func (st *mockState) FirewallRule(service state.WellKnownServiceType) (*state.Fi
 	return r, nil
 }
 
+func (st *mockState) WatchFirewallRules() state.NotifyWatcher {
+	st.MethodCall(st, "WatchFirewallRules")
+	return st.rulesWatcher
+}
+
 type mockWatcher struct {
 	testing.Stub
 	tomb.Tomb
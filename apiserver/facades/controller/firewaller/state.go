@@ -25,6 +25,8 @@ type State interface {
 	FindEntity(tag names.Tag) (state.Entity, error)
 
 	FirewallRule(service state.WellKnownServiceType) (*state.FirewallRule, error)
+
+	WatchFirewallRules() state.NotifyWatcher
 }
 
 // TODO(wallyworld) - for tests, remove when remaining firewaller tests become unit tests.
@@ -58,3 +60,7 @@ func (s stateShim) FirewallRule(service state.WellKnownServiceType) (*state.Fire
 	api := state.NewFirewallRules(s.st)
 	return api.Rule(service)
 }
+
+func (s stateShim) WatchFirewallRules() state.NotifyWatcher {
+	return s.st.WatchFirewallRules()
+}
@@ -103,6 +103,7 @@ func (api *API) MigrationStatus() (params.MasterMigrationStatus, error) {
 		MigrationId:      mig.Id(),
 		Phase:            phase.String(),
 		PhaseChangedTime: mig.PhaseChangedTime(),
+		Paused:           mig.Paused(),
 	}, nil
 }
 
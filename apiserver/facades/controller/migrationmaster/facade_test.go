@@ -114,6 +114,14 @@ func (s *Suite) TestMigrationStatus(c *gc.C) {
 	})
 }
 
+func (s *Suite) TestMigrationStatusPaused(c *gc.C) {
+	s.backend.migration.paused = true
+	api := s.mustMakeAPI(c)
+	status, err := api.MigrationStatus()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(status.Paused, jc.IsTrue)
+}
+
 func (s *Suite) TestModelInfo(c *gc.C) {
 	api := s.mustMakeAPI(c)
 	model, err := api.ModelInfo()
@@ -470,6 +478,7 @@ type stubMigration struct {
 	messageSet      string
 	minionReports   *state.MinionReports
 	externalControl bool
+	paused          bool
 }
 
 func (m *stubMigration) Id() string {
@@ -480,6 +489,10 @@ func (m *stubMigration) Phase() (coremigration.Phase, error) {
 	return coremigration.IMPORT, nil
 }
 
+func (m *stubMigration) Paused() bool {
+	return m.paused
+}
+
 func (m *stubMigration) PhaseChangedTime() time.Time {
 	return time.Date(2016, 6, 22, 16, 38, 0, 0, time.UTC)
 }
@@ -36,6 +36,11 @@ type agentLoggingStrategy struct {
 	version    version.Number
 	entity     names.Tag
 	filePrefix string
+
+	// workloadLogger is true if the agent may record logs on
+	// behalf of the model's units and applications, as the
+	// controller does when it ingests CAAS workload logs.
+	workloadLogger bool
 }
 
 type recordLogger interface {
@@ -143,6 +148,9 @@ func (s *agentLoggingStrategy) init(ctxt httpContext, req *http.Request) error {
 	}
 	s.version = ver
 	s.entity = entity.Tag()
+	if m, ok := entity.(*state.Machine); ok && m.IsManager() {
+		s.workloadLogger = true
+	}
 	s.filePrefix = st.ModelUUID() + ":"
 	s.dblogger = s.dbloggers.get(st)
 	s.releaser = func() {
@@ -166,9 +174,10 @@ func (s *agentLoggingStrategy) Close() error {
 // WriteLog is part of the logsink.LogWriteCloser interface.
 func (s *agentLoggingStrategy) WriteLog(m params.LogRecord) error {
 	level, _ := loggo.ParseLevel(m.Level)
+	entity := s.recordEntity(m)
 	dbErr := errors.Annotate(s.dblogger.Log([]state.LogRecord{{
 		Time:     m.Time,
-		Entity:   s.entity,
+		Entity:   entity,
 		Version:  s.version,
 		Module:   m.Module,
 		Location: m.Location,
@@ -176,7 +185,7 @@ func (s *agentLoggingStrategy) WriteLog(m params.LogRecord) error {
 		Message:  m.Message,
 	}}), "logging to DB failed")
 
-	m.Entity = s.entity.String()
+	m.Entity = entity.String()
 	fileErr := errors.Annotate(
 		logToFile(s.fileLogger, s.filePrefix, m),
 		"logging to logsink.log failed",
@@ -190,6 +199,25 @@ func (s *agentLoggingStrategy) WriteLog(m params.LogRecord) error {
 	return err
 }
 
+// recordEntity returns the entity to which the log record is
+// attributed. Only the controller may record logs on behalf of
+// units and applications; any other entity specified in the
+// record is ignored.
+func (s *agentLoggingStrategy) recordEntity(m params.LogRecord) names.Tag {
+	if !s.workloadLogger || m.Entity == "" {
+		return s.entity
+	}
+	tag, err := names.ParseTag(m.Entity)
+	if err != nil {
+		return s.entity
+	}
+	switch tag.Kind() {
+	case names.UnitTagKind, names.ApplicationTagKind:
+		return tag
+	}
+	return s.entity
+}
+
 // logToFile writes a single log message to the logsink log file.
 func logToFile(writer io.Writer, prefix string, m params.LogRecord) error {
 	_, err := writer.Write([]byte(strings.Join([]string{
@@ -24,6 +24,7 @@ import (
 	"github.com/juju/juju/apiserver"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/apiserver/websocket/websockettest"
+	"github.com/juju/juju/state"
 	coretesting "github.com/juju/juju/testing"
 	"github.com/juju/juju/testing/factory"
 	"github.com/juju/juju/version"
@@ -194,6 +195,66 @@ func (s *logsinkSuite) TestLogging(c *gc.C) {
 	}
 }
 
+func (s *logsinkSuite) TestLoggingOnBehalfOfUnitIgnored(c *gc.C) {
+	doc := s.logOnBehalfOf(c, "unit-mysql-0")
+	c.Assert(doc["n"], gc.Equals, s.machineTag.String())
+}
+
+func (s *logsinkSuite) TestControllerLoggingOnBehalfOfUnit(c *gc.C) {
+	s.makeController(c)
+	doc := s.logOnBehalfOf(c, "unit-mysql-0")
+	c.Assert(doc["n"], gc.Equals, "unit-mysql-0")
+}
+
+func (s *logsinkSuite) TestControllerLoggingOnBehalfOfMachineIgnored(c *gc.C) {
+	s.makeController(c)
+	doc := s.logOnBehalfOf(c, "machine-42")
+	c.Assert(doc["n"], gc.Equals, s.machineTag.String())
+}
+
+// makeController has the test connect as a controller machine.
+func (s *logsinkSuite) makeController(c *gc.C) {
+	m, password := s.Factory.MakeMachineReturningPassword(c, &factory.MachineParams{
+		Nonce: s.nonce,
+		Jobs:  []state.MachineJob{state.JobManageModel},
+	})
+	s.machineTag = m.Tag()
+	s.password = password
+}
+
+// logOnBehalfOf sends a log record for the specified entity,
+// and returns the log document recorded.
+func (s *logsinkSuite) logOnBehalfOf(c *gc.C, entity string) bson.M {
+	conn := s.dialWebsocket(c)
+	defer conn.Close()
+	websockettest.AssertJSONInitialErrorNil(c, conn)
+
+	err := conn.WriteJSON(&params.LogRecord{
+		Time:     time.Date(2015, time.June, 1, 23, 2, 1, 0, time.UTC),
+		Module:   "workload",
+		Location: "mysql-0/mysql",
+		Level:    loggo.INFO.String(),
+		Message:  "ready for connections",
+		Entity:   entity,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	logsColl := s.State.MongoSession().DB("logs").C("logs." + s.State.ModelUUID())
+	var docs []bson.M
+	for a := coretesting.LongAttempt.Start(); a.Next(); {
+		err := logsColl.Find(nil).All(&docs)
+		c.Assert(err, jc.ErrorIsNil)
+		if len(docs) > 0 {
+			break
+		}
+		if !a.HasNext() {
+			c.Fatalf("timed out waiting for log writes")
+		}
+	}
+	c.Assert(docs, gc.HasLen, 1)
+	return docs[0]
+}
+
 func (s *logsinkSuite) TestReceiveErrorBreaksConn(c *gc.C) {
 	conn := s.dialWebsocket(c)
 	defer conn.Close()
@@ -0,0 +1,56 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package params
+
+import (
+	"time"
+)
+
+// ModelActivityArgs holds the arguments for querying the recent
+// activity in a model.
+type ModelActivityArgs struct {
+	// User, if set, restricts the activity to commands run
+	// by the named user.
+	User string `json:"user,omitempty"`
+
+	// Since, if set, restricts the activity to commands run
+	// at or after this time.
+	Since *time.Time `json:"since,omitempty"`
+
+	// Limit is the maximum number of commands to return,
+	// most recent first. Zero means no limit.
+	Limit int `json:"limit,omitempty"`
+}
+
+// ModelActivityResults holds the recent activity in a model, most
+// recent first.
+type ModelActivityResults struct {
+	Results []ModelActivity `json:"results"`
+}
+
+// ModelActivity describes a command that changed a model, as
+// recorded in the controller's audit log.
+type ModelActivity struct {
+	User       string                   `json:"user"`
+	Command    string                   `json:"command"`
+	When       time.Time                `json:"when"`
+	Operations []ModelActivityOperation `json:"operations"`
+}
+
+// ModelActivityOperation describes an API call made as part of a
+// command that changed a model.
+type ModelActivityOperation struct {
+	Facade  string    `json:"facade"`
+	Method  string    `json:"method"`
+	Version int       `json:"version"`
+	When    time.Time `json:"when"`
+
+	// Entities holds the tags of the entities in the arguments of
+	// the call. They are only known when the controller's
+	// audit-log-capture-args setting is enabled.
+	Entities []string `json:"entities,omitempty"`
+
+	// Errors holds the errors returned by the call, if any.
+	Errors []string `json:"errors,omitempty"`
+}
@@ -28,6 +28,23 @@ type AddCloudArgs struct {
 	Name  string `json:"name"`
 }
 
+// UpdateCloudArgs holds the new definitions of existing clouds.
+type UpdateCloudArgs struct {
+	Clouds []AddCloudArgs `json:"clouds"`
+}
+
+// RotateCredentialArg identifies a cloud credential to be replaced,
+// and the credential that models using it should be switched to.
+type RotateCredentialArg struct {
+	OldTag string `json:"old-tag"`
+	NewTag string `json:"new-tag"`
+}
+
+// RotateCredentialArgs holds the cloud credentials to be rotated.
+type RotateCredentialArgs struct {
+	Credentials []RotateCredentialArg `json:"credentials"`
+}
+
 // CloudResult contains a cloud definition or an error.
 type CloudResult struct {
 	Cloud *Cloud `json:"cloud,omitempty"`
@@ -114,3 +131,43 @@ type CloudSpecResult struct {
 type CloudSpecResults struct {
 	Results []CloudSpecResult `json:"results,omitempty"`
 }
+
+// ModelCredential holds the cloud credential used by a model, and
+// whether it is still valid.
+type ModelCredential struct {
+	// Model is the model tag.
+	Model string `json:"model-tag"`
+
+	// Exists is true if the model uses a cloud credential.
+	Exists bool `json:"exists,omitempty"`
+
+	// CloudCredential is the tag of the cloud credential used by the
+	// model, if any.
+	CloudCredential string `json:"credential-tag"`
+
+	// Valid is true if the credential exists and has not been
+	// revoked.
+	Valid bool `json:"valid,omitempty"`
+}
+
+// ModifyCloudAccessRequest holds the parameters for making grant and revoke cloud calls.
+type ModifyCloudAccessRequest struct {
+	Changes []ModifyCloudAccess `json:"changes"`
+}
+
+// ModifyCloudAccess contains parameters to grant and revoke access to a cloud.
+type ModifyCloudAccess struct {
+	UserTag  string      `json:"user-tag"`
+	CloudTag string      `json:"cloud-tag"`
+	Action   CloudAction `json:"action"`
+	Access   string      `json:"access"`
+}
+
+// CloudAction is an action that can be performed on a cloud.
+type CloudAction string
+
+// Actions that can be preformed on a cloud.
+const (
+	GrantCloudAccess  CloudAction = "grant"
+	RevokeCloudAccess CloudAction = "revoke"
+)
@@ -122,6 +122,7 @@ type MasterMigrationStatus struct {
 	MigrationId      string        `json:"migration-id"`
 	Phase            string        `json:"phase"`
 	PhaseChangedTime time.Time     `json:"phase-changed-time"`
+	Paused           bool          `json:"paused,omitempty"`
 }
 
 // MigrationModelInfo is used to report basic model information to the
@@ -51,6 +51,7 @@ type ModelDefaultsResult struct {
 type ModelDefaults struct {
 	Default    interface{}      `json:"default,omitempty"`
 	Controller interface{}      `json:"controller,omitempty"`
+	Cloud      interface{}      `json:"cloud,omitempty"`
 	Regions    []RegionDefaults `json:"regions,omitempty"`
 }
 
@@ -190,6 +191,10 @@ type ModelSummary struct {
 	// into the model last.
 	UserLastConnection *time.Time `json:"last-connection"`
 
+	// LastConnection contains the time when any user logged in
+	// into the model last.
+	LastConnection *time.Time `json:"model-last-connection,omitempty"`
+
 	// Counts contains counts of interesting entities
 	// in the model, for example machines, cores, containers, units, etc.
 	Counts []ModelEntityCount `json:"counts"`
@@ -218,6 +223,8 @@ type CountedEntity string
 const (
 	Machines CountedEntity = "machines"
 	Cores    CountedEntity = "cores"
+	Memory   CountedEntity = "memory"
+	Units    CountedEntity = "units"
 )
 
 // ModelSLAInfo describes the SLA info for a model.
@@ -280,6 +287,12 @@ type ModelMachineInfo struct {
 	Status     string           `json:"status,omitempty"`
 	HasVote    bool             `json:"has-vote,omitempty"`
 	WantsVote  bool             `json:"wants-vote,omitempty"`
+
+	// The remaining fields are only set for controller machines.
+	AgentVersion      string   `json:"agent-version,omitempty"`
+	ReplicaSetState   string   `json:"replicaset-state,omitempty"`
+	ReplicaSetHealthy bool     `json:"replicaset-healthy,omitempty"`
+	APIAddresses      []string `json:"api-addresses,omitempty"`
 }
 
 // MachineHardware holds information about a machine's hardware characteristics.
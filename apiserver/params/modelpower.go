@@ -0,0 +1,15 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package params
+
+// ModelPowerResult holds the result of suspending or resuming a model.
+type ModelPowerResult struct {
+	// Machines holds the ids of the machines whose instances
+	// were powered off or on.
+	Machines []string `json:"machines"`
+
+	// Skipped holds the ids of the machines that were left alone
+	// because they are manually provisioned or have no instance.
+	Skipped []string `json:"skipped,omitempty"`
+}
@@ -371,6 +371,42 @@ type ApplicationGetResults struct {
 	Series            string                 `json:"series"`
 }
 
+// UnitInfoResults holds the results of the application UnitsInfo call.
+type UnitInfoResults struct {
+	Results []UnitInfoResult `json:"results"`
+}
+
+// UnitInfoResult holds the detail of a single unit, or an error.
+type UnitInfoResult struct {
+	Result *UnitResult `json:"result,omitempty"`
+	Error  *Error      `json:"error,omitempty"`
+}
+
+// UnitResult holds the detail of a unit reported by the
+// application UnitsInfo call.
+type UnitResult struct {
+	Tag                   string                 `json:"tag"`
+	Life                  string                 `json:"life"`
+	WorkloadVersion       string                 `json:"workload-version"`
+	Machine               string                 `json:"machine,omitempty"`
+	PublicAddress         string                 `json:"public-address,omitempty"`
+	OpenedPorts           []string               `json:"opened-ports"`
+	Leader                bool                   `json:"leader"`
+	Storage               []string               `json:"storage,omitempty"`
+	RelationData          []EndpointRelationData `json:"relation-data,omitempty"`
+	WorkloadStatusHistory []DetailedStatus       `json:"workload-status-history,omitempty"`
+	AgentStatusHistory    []DetailedStatus       `json:"agent-status-history,omitempty"`
+}
+
+// EndpointRelationData holds the settings a unit has set
+// in one of the relations it has joined.
+type EndpointRelationData struct {
+	RelationId      int                    `json:"relation-id"`
+	Endpoint        string                 `json:"endpoint"`
+	RelatedEndpoint string                 `json:"related-endpoint"`
+	Data            map[string]interface{} `json:"data"`
+}
+
 // ApplicationConfigSetArgs holds the parameters for
 // setting application config values for specified applications.
 type ApplicationConfigSetArgs struct {
@@ -390,6 +426,19 @@ type ApplicationConfigUnsetArgs struct {
 	Args []ApplicationUnset
 }
 
+// ApplicationTrustArgs holds the parameters for
+// granting or revoking the trust of specified applications.
+type ApplicationTrustArgs struct {
+	Args []ApplicationTrust `json:"args"`
+}
+
+// ApplicationTrust holds the parameters for granting
+// or revoking the trust of an application.
+type ApplicationTrust struct {
+	ApplicationName string `json:"application"`
+	Trusted         bool   `json:"trusted"`
+}
+
 // ApplicationCharmRelations holds parameters for making the application CharmRelations call.
 type ApplicationCharmRelations struct {
 	ApplicationName string `json:"application"`
@@ -484,6 +533,12 @@ type UpdateApplicationUnitArgs struct {
 type UpdateApplicationUnits struct {
 	ApplicationTag string                  `json:"application-tag"`
 	Units          []ApplicationUnitParams `json:"units"`
+
+	// Autoscaled is true if the number of units is managed
+	// by the cloud, by its autoscaler or by running a unit
+	// on each node, in which case units with no cloud
+	// counterpart are removed.
+	Autoscaled bool `json:"autoscaled,omitempty"`
 }
 
 // ApplicationUnitParams holds unit parameters used to update a unit.
@@ -494,6 +549,29 @@ type ApplicationUnitParams struct {
 	Status  string                 `json:"status"`
 	Info    string                 `json:"info"`
 	Data    map[string]interface{} `json:"data"`
+
+	FilesystemInfo []UnitFilesystemInfo `json:"filesystem-info,omitempty"`
+}
+
+// UnitFilesystemInfo holds information about a filesystem
+// mounted by a unit in a CAAS model.
+type UnitFilesystemInfo struct {
+	StorageName  string         `json:"storage-name"`
+	FilesystemId string         `json:"filesystem-id"`
+	Size         uint64         `json:"size"`
+	MountPoint   string         `json:"mount-point"`
+	ReadOnly     bool           `json:"read-only"`
+	Status       string         `json:"status"`
+	Info         string         `json:"info"`
+	Volume       UnitVolumeInfo `json:"volume"`
+}
+
+// UnitVolumeInfo holds information about a volume
+// backing a filesystem mounted by a unit in a CAAS model.
+type UnitVolumeInfo struct {
+	VolumeId   string `json:"volume-id"`
+	Size       uint64 `json:"size"`
+	Persistent bool   `json:"persistent"`
 }
 
 // DestroyApplicationUnits holds parameters for the deprecated
@@ -538,6 +616,11 @@ type DestroyApplicationParams struct {
 	// DestroyStorage controls whether or not storage attached to
 	// units of the application should be destroyed.
 	DestroyStorage bool `json:"destroy-storage,omitempty"`
+
+	// Force controls whether or not the application and its units
+	// are removed without waiting for the units' agents to run
+	// their remaining hooks.
+	Force bool `json:"force,omitempty"`
 }
 
 // DestroyConsumedApplicationsParams holds bulk parameters for the
@@ -565,12 +648,16 @@ type Creds struct {
 // any one is valid, the authentication succeeds). If there are no
 // valid macaroons and macaroon authentication is configured,
 // the LoginResult will contain a macaroon that when
-// discharged, may allow access.
+// discharged, may allow access. If instead OpenID Connect
+// authentication is configured, the provided ID token will be
+// used, and if it is missing or invalid the LoginResult will
+// identify the provider from which to obtain one.
 type LoginRequest struct {
 	AuthTag     string           `json:"auth-tag"`
 	Credentials string           `json:"credentials"`
 	Nonce       string           `json:"nonce"`
 	Macaroons   []macaroon.Slice `json:"macaroons"`
+	Token       string           `json:"token,omitempty"`
 	CLIArgs     string           `json:"cli-args,omitempty"`
 	UserData    string           `json:"user-data"`
 }
@@ -833,6 +920,12 @@ type LoginResult struct {
 	// required.
 	DischargeRequiredReason string `json:"discharge-required-error,omitempty"`
 
+	// TokenRequired implies that the login request has failed, and none
+	// of the other fields are populated. It identifies the OpenID Connect
+	// provider from which an ID token must be obtained to grant access on
+	// a subsequent call to Login.
+	TokenRequired *TokenRequiredInfo `json:"token-required,omitempty"`
+
 	// Servers is the list of API server addresses.
 	Servers [][]HostPort `json:"servers,omitempty"`
 
@@ -860,6 +953,19 @@ type LoginResult struct {
 	ServerVersion string `json:"server-version,omitempty"`
 }
 
+// TokenRequiredInfo identifies the OpenID Connect provider that
+// issues the ID tokens accepted by a controller.
+type TokenRequiredInfo struct {
+	// IssuerURL holds the issuer URL of the provider.
+	IssuerURL string `json:"issuer-url"`
+
+	// ClientID holds the client ID that tokens must be issued to.
+	ClientID string `json:"client-id"`
+
+	// Reason holds the reason that a token was required.
+	Reason string `json:"reason,omitempty"`
+}
+
 // ControllersServersSpec contains arguments for
 // the EnableHA client API call.
 type ControllersSpec struct {
@@ -1133,6 +1239,11 @@ type DestroyApplicationInfo struct {
 	// DestroyedUnits is the tags of units that will be destroyed
 	// as a result of destroying the application.
 	DestroyedUnits []Entity `json:"destroyed-units,omitempty"`
+
+	// ForcedRelations is the tags of relations that will be removed
+	// without waiting for the units in them to leave, as a result
+	// of forcibly destroying the application.
+	ForcedRelations []Entity `json:"forced-relations,omitempty"`
 }
 
 // DestroyUnitResults contains the results of a DestroyUnit API request.
@@ -71,6 +71,10 @@ type UploadResult struct {
 
 	// Resource describes the resource that was stored in the model.
 	Resource Resource `json:"resource"`
+
+	// Offset is the number of bytes of a resource uploaded in
+	// chunks that the controller has received.
+	Offset int64 `json:"offset,omitempty"`
 }
 
 // Resource contains info about a Resource.
@@ -0,0 +1,43 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package params
+
+// UpgradeSeriesStatusResult holds the status of a machine's series
+// upgrade, or an error.
+type UpgradeSeriesStatusResult struct {
+	Error  *Error `json:"error,omitempty"`
+	Status string `json:"status,omitempty"`
+}
+
+// UpgradeSeriesStatusResults holds the bulk operation result of an
+// API call that returns series upgrade statuses.
+type UpgradeSeriesStatusResults struct {
+	Results []UpgradeSeriesStatusResult `json:"results"`
+}
+
+// UpgradeSeriesStatusParam holds the new series upgrade status of
+// an entity.
+type UpgradeSeriesStatusParam struct {
+	Entity Entity `json:"entity"`
+	Status string `json:"status"`
+}
+
+// UpgradeSeriesStatusParams holds the new series upgrade statuses of
+// one or more entities.
+type UpgradeSeriesStatusParams struct {
+	Params []UpgradeSeriesStatusParam `json:"params"`
+}
+
+// UpgradeSeriesUnitStatusesResult holds the series upgrade status of
+// each unit taking part in a machine's series upgrade, or an error.
+type UpgradeSeriesUnitStatusesResult struct {
+	Error    *Error            `json:"error,omitempty"`
+	Statuses map[string]string `json:"statuses,omitempty"`
+}
+
+// UpgradeSeriesUnitStatusesResults holds the bulk operation result of
+// an API call that returns unit series upgrade statuses.
+type UpgradeSeriesUnitStatusesResults struct {
+	Results []UpgradeSeriesUnitStatusesResult `json:"results"`
+}
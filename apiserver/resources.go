@@ -4,12 +4,19 @@
 package apiserver
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"mime"
 	"net/http"
+	"os"
 	"path"
+	"path/filepath"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/juju/errors"
 	charmresource "gopkg.in/juju/charm.v6/resource"
@@ -43,8 +50,17 @@ type ResourcesBackend interface {
 // uploads of resources.
 type ResourcesHandler struct {
 	StateAuthFunc func(*http.Request, ...string) (ResourcesBackend, state.StatePoolReleaser, names.Tag, error)
+
+	// UploadDir is the directory in which resources uploaded in
+	// chunks are staged until all of their chunks are received.
+	UploadDir string
 }
 
+// staleUploadAge is how long a resource upload sent in chunks may
+// go without receiving a chunk before the data staged for it is
+// removed.
+const staleUploadAge = 24 * time.Hour
+
 // ServeHTTP implements http.Handler.
 func (h *ResourcesHandler) ServeHTTP(resp http.ResponseWriter, req *http.Request) {
 	backend, closer, tag, err := h.StateAuthFunc(req, names.UserTagKind, names.MachineTagKind)
@@ -100,6 +116,19 @@ func (h *ResourcesHandler) upload(backend ResourcesBackend, req *http.Request, u
 		return nil, errors.Trace(err)
 	}
 
+	if uploaded.Range != nil {
+		data, offset, err := h.stageChunk(req, uploaded)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if data == nil {
+			// There are more chunks of the resource to come.
+			return &params.UploadResult{Offset: offset}, nil
+		}
+		defer data.Close()
+		uploaded.Data = data
+	}
+
 	var stored resource.Resource
 	if uploaded.PendingID != "" {
 		stored, err = backend.UpdatePendingResource(uploaded.Service, uploaded.PendingID, username, uploaded.Resource, uploaded.Data)
@@ -116,9 +145,138 @@ func (h *ResourcesHandler) upload(backend ResourcesBackend, req *http.Request, u
 	result := &params.UploadResult{
 		Resource: api.Resource2API(stored),
 	}
+	if uploaded.Range != nil {
+		result.Offset = uploaded.Range.Size
+	}
 	return result, nil
 }
 
+// stageChunk writes a chunk of a resource uploaded in chunks to the
+// file in which the upload is staged, returning the number of bytes
+// of the resource received so far. Once all of the resource has been
+// received, and its fingerprint checked, the staged data is returned
+// too; closing it removes the file.
+func (h *ResourcesHandler) stageChunk(req *http.Request, uploaded *uploadedResource) (io.ReadCloser, int64, error) {
+	if h.UploadDir == "" {
+		return nil, 0, errors.NotSupportedf("resource uploads in chunks")
+	}
+	if err := os.MkdirAll(h.UploadDir, 0700); err != nil {
+		return nil, 0, errors.Trace(err)
+	}
+	rng := *uploaded.Range
+	if rng.Length == 0 {
+		// A new upload asks how much of the resource has been
+		// received, which is a good time to tidy up after any
+		// uploads that were abandoned.
+		removeStaleUploads(h.UploadDir, staleUploadAge)
+	}
+
+	path := filepath.Join(h.UploadDir, stagedUploadName(req.URL.Query().Get(":modeluuid"), uploaded))
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, 0, errors.Trace(err)
+	}
+	staged := &stagedUpload{f}
+	ok := false
+	defer func() {
+		if !ok {
+			f.Close()
+		}
+	}()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, 0, errors.Trace(err)
+	}
+	offset := info.Size()
+	if rng.Length > 0 {
+		if rng.Start > offset {
+			return nil, offset, errors.BadRequestf(
+				"chunk starts at byte %d, but only %d bytes have been received", rng.Start, offset)
+		}
+		// Any of the chunk that was received before, when the
+		// connection failed part way through, is replaced.
+		if err := f.Truncate(rng.Start); err != nil {
+			return nil, 0, errors.Trace(err)
+		}
+		if _, err := f.Seek(rng.Start, os.SEEK_SET); err != nil {
+			return nil, 0, errors.Trace(err)
+		}
+		n, err := io.Copy(f, io.LimitReader(req.Body, rng.Length))
+		offset = rng.Start + n
+		if err != nil {
+			return nil, offset, errors.Annotate(err, "reading chunk")
+		}
+	}
+	if offset < rng.Size {
+		return nil, offset, nil
+	}
+
+	if _, err := f.Seek(0, os.SEEK_SET); err != nil {
+		return nil, 0, errors.Trace(err)
+	}
+	fp, err := charmresource.GenerateFingerprint(f)
+	if err != nil {
+		return nil, 0, errors.Trace(err)
+	}
+	if fp.String() != uploaded.Resource.Fingerprint.String() {
+		staged.Close()
+		ok = true
+		return nil, 0, errors.Errorf("checksum mismatch: uploaded resource has fingerprint %s, expected %s",
+			fp, uploaded.Resource.Fingerprint)
+	}
+	if _, err := f.Seek(0, os.SEEK_SET); err != nil {
+		return nil, 0, errors.Trace(err)
+	}
+	ok = true
+	return staged, offset, nil
+}
+
+// stagedUpload is the staged data of a resource uploaded in chunks.
+type stagedUpload struct {
+	*os.File
+}
+
+// Close closes and removes the file the data is staged in.
+func (s *stagedUpload) Close() error {
+	s.File.Close()
+	return errors.Trace(os.Remove(s.Name()))
+}
+
+// stagedUploadName returns the name of the file in which the
+// upload of a resource in chunks is staged. Uploads of the same
+// content for the same resource share the file, so an upload that
+// was interrupted can be resumed.
+func stagedUploadName(modelUUID string, uploaded *uploadedResource) string {
+	key := strings.Join([]string{
+		modelUUID,
+		uploaded.Service,
+		uploaded.Resource.Name,
+		uploaded.PendingID,
+		uploaded.Resource.Fingerprint.String(),
+	}, "/")
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// removeStaleUploads removes the data staged for uploads in
+// chunks that have not received a chunk in the given time.
+func removeStaleUploads(dir string, age time.Duration) {
+	infos, err := ioutil.ReadDir(dir)
+	if err != nil {
+		logger.Warningf("cannot read resource uploads: %v", err)
+		return
+	}
+	for _, info := range infos {
+		if time.Since(info.ModTime()) < age {
+			continue
+		}
+		if err := os.Remove(filepath.Join(dir, info.Name())); err != nil {
+			logger.Warningf("cannot remove stale resource upload: %v", err)
+		}
+	}
+}
+
 // uploadedResource holds both the information about an uploaded
 // resource and the reader containing its data.
 type uploadedResource struct {
@@ -133,6 +291,10 @@ type uploadedResource struct {
 
 	// Data holds the resource blob.
 	Data io.ReadCloser
+
+	// Range is the range of the bytes of the resource sent
+	// in the request, if the resource is uploaded in chunks.
+	Range *api.ContentRange
 }
 
 // readResource extracts the relevant info from the request.
@@ -141,6 +303,17 @@ func (h *ResourcesHandler) readResource(backend ResourcesBackend, req *http.Requ
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
+	var rng *api.ContentRange
+	if value := req.Header.Get(api.HeaderContentRange); value != "" {
+		parsed, err := api.ParseContentRange(value)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		// The size of a resource uploaded in chunks is that
+		// of the whole resource, not of the chunk.
+		uReq.Size = parsed.Size
+		rng = &parsed
+	}
 	var res resource.Resource
 	if uReq.PendingID != "" {
 		res, err = backend.GetPendingResource(uReq.Service, uReq.Name, uReq.PendingID)
@@ -168,6 +341,7 @@ func (h *ResourcesHandler) readResource(backend ResourcesBackend, req *http.Requ
 		PendingID: uReq.PendingID,
 		Resource:  chRes,
 		Data:      req.Body,
+		Range:     rng,
 	}, nil
 }
 
@@ -59,6 +59,7 @@ func (s *ResourcesHandlerSuite) SetUpTest(c *gc.C) {
 	s.recorder = httptest.NewRecorder()
 	s.handler = &apiserver.ResourcesHandler{
 		StateAuthFunc: s.authState,
+		UploadDir:     c.MkDir(),
 	}
 }
 
@@ -160,6 +161,112 @@ func (s *ResourcesHandlerSuite) TestPutSetResourceFailure(c *gc.C) {
 	s.checkResp(c, http.StatusInternalServerError, "application/json", string(expected))
 }
 
+func (s *ResourcesHandlerSuite) TestPutChunks(c *gc.C) {
+	uploadContent := "<some data>"
+	res, _ := newResource(c, "spam", "a-user", uploadContent)
+	stored, _ := newResource(c, "spam", "", "")
+	s.backend.ReturnGetResource = stored
+	s.backend.ReturnSetResource = res
+
+	for _, test := range []struct {
+		rng    api.ContentRange
+		offset int64
+	}{
+		{api.ContentRange{Size: 11}, 0},
+		{api.ContentRange{Start: 0, Length: 5, Size: 11}, 5},
+		// A chunk resent after the connection failed replaces
+		// the part of it already received.
+		{api.ContentRange{Start: 3, Length: 4, Size: 11}, 7},
+		{api.ContentRange{Size: 11}, 7},
+	} {
+		recorder := httptest.NewRecorder()
+		req := newChunkRequest(c, "spam", "a-application", uploadContent, test.rng)
+		s.handler.ServeHTTP(recorder, req)
+
+		expected := mustMarshalJSON(&params.UploadResult{Offset: test.offset})
+		checkHTTPResp(c, recorder, http.StatusOK, "application/json", string(expected))
+	}
+	c.Check(s.backend.SetResourceData, gc.Equals, "")
+
+	req := newChunkRequest(c, "spam", "a-application", uploadContent, api.ContentRange{Start: 7, Length: 4, Size: 11})
+	s.handler.ServeHTTP(s.recorder, req)
+
+	expected := mustMarshalJSON(&params.UploadResult{
+		Resource: api.Resource2API(res),
+		Offset:   11,
+	})
+	s.checkResp(c, http.StatusOK, "application/json", string(expected))
+	c.Check(s.backend.SetResourceData, gc.Equals, uploadContent)
+	s.checkNoStagedUploads(c)
+}
+
+func (s *ResourcesHandlerSuite) TestPutChunkWithPending(c *gc.C) {
+	uploadContent := "<some data>"
+	res, _ := newResource(c, "spam", "a-user", uploadContent)
+	res.PendingID = "some-unique-id"
+	stored, _ := newResource(c, "spam", "", "")
+	stored.PendingID = "some-unique-id"
+	s.backend.ReturnGetPendingResource = stored
+	s.backend.ReturnUpdatePendingResource = res
+
+	req := newChunkRequest(c, "spam", "a-application", uploadContent, api.ContentRange{Start: 0, Length: 11, Size: 11})
+	req.URL.RawQuery += "&pendingid=some-unique-id"
+	s.handler.ServeHTTP(s.recorder, req)
+
+	expected := mustMarshalJSON(&params.UploadResult{
+		Resource: api.Resource2API(res),
+		Offset:   11,
+	})
+	s.checkResp(c, http.StatusOK, "application/json", string(expected))
+	c.Check(s.backend.UpdatePendingResourceData, gc.Equals, uploadContent)
+	s.checkNoStagedUploads(c)
+}
+
+func (s *ResourcesHandlerSuite) TestPutChunkMissingBytes(c *gc.C) {
+	stored, _ := newResource(c, "spam", "", "")
+	s.backend.ReturnGetResource = stored
+
+	req := newChunkRequest(c, "spam", "a-application", "<some data>", api.ContentRange{Start: 5, Length: 6, Size: 11})
+	s.handler.ServeHTTP(s.recorder, req)
+
+	_, expected := apiFailure("chunk starts at byte 5, but only 0 bytes have been received", params.CodeBadRequest)
+	s.checkResp(c, http.StatusBadRequest, "application/json", expected)
+}
+
+func (s *ResourcesHandlerSuite) TestPutChunksChecksumMismatch(c *gc.C) {
+	stored, _ := newResource(c, "spam", "", "")
+	s.backend.ReturnGetResource = stored
+
+	req := newChunkRequest(c, "spam", "a-application", "<some data>", api.ContentRange{Start: 0, Length: 11, Size: 11})
+	fp, err := charmresource.GenerateFingerprint(strings.NewReader("<other data>"))
+	c.Assert(err, jc.ErrorIsNil)
+	req.Header.Set("Content-SHA384", fp.String())
+	s.handler.ServeHTTP(s.recorder, req)
+
+	c.Check(s.recorder.Code, gc.Equals, http.StatusInternalServerError)
+	c.Check(s.recorder.Body.String(), gc.Matches, `.*checksum mismatch: uploaded resource has fingerprint .*, expected .*`)
+	c.Check(s.backend.SetResourceData, gc.Equals, "")
+	s.checkNoStagedUploads(c)
+}
+
+func (s *ResourcesHandlerSuite) TestPutChunksNotSupported(c *gc.C) {
+	stored, _ := newResource(c, "spam", "", "")
+	s.backend.ReturnGetResource = stored
+	s.handler.UploadDir = ""
+
+	req := newChunkRequest(c, "spam", "a-application", "<some data>", api.ContentRange{Size: 11})
+	s.handler.ServeHTTP(s.recorder, req)
+
+	_, expected := apiFailure("resource uploads in chunks not supported", params.CodeNotSupported)
+	s.checkResp(c, http.StatusInternalServerError, "application/json", expected)
+}
+
+func (s *ResourcesHandlerSuite) checkNoStagedUploads(c *gc.C) {
+	infos, err := ioutil.ReadDir(s.handler.UploadDir)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(infos, gc.HasLen, 0)
+}
+
 func (s *ResourcesHandlerSuite) checkResp(c *gc.C, status int, ctype, body string) {
 	checkHTTPResp(c, s.recorder, status, ctype, body)
 }
@@ -180,7 +287,9 @@ type fakeBackend struct {
 	ReturnGetPendingResource    resource.Resource
 	ReturnSetResource           resource.Resource
 	SetResourceErr              error
+	SetResourceData             string
 	ReturnUpdatePendingResource resource.Resource
+	UpdatePendingResourceData   string
 }
 
 const resourceBody = "body"
@@ -204,10 +313,20 @@ func (s *fakeBackend) SetResource(applicationID, userID string, res charmresourc
 	if s.SetResourceErr != nil {
 		return resource.Resource{}, s.SetResourceErr
 	}
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return resource.Resource{}, err
+	}
+	s.SetResourceData = string(data)
 	return s.ReturnSetResource, nil
 }
 
 func (s *fakeBackend) UpdatePendingResource(applicationID, pendingID, userID string, res charmresource.Resource, r io.Reader) (resource.Resource, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return resource.Resource{}, err
+	}
+	s.UpdatePendingResourceData = string(data)
 	return s.ReturnUpdatePendingResource, nil
 }
 
@@ -259,6 +378,16 @@ func newUploadRequest(c *gc.C, name, service, content string) (*http.Request, io
 	return req, body
 }
 
+func newChunkRequest(c *gc.C, name, service, content string, rng api.ContentRange) *http.Request {
+	req, _ := newUploadRequest(c, name, service, content)
+	chunk := content[rng.Start : rng.Start+rng.Length]
+	req.Body = ioutil.NopCloser(strings.NewReader(chunk))
+	req.ContentLength = rng.Length
+	req.Header.Set("Content-Length", fmt.Sprint(rng.Length))
+	req.Header.Set("Content-Range", rng.String())
+	return req
+}
+
 func apiFailure(msg, code string) (error, string) {
 	failure := errors.New(msg)
 	data := mustMarshalJSON(params.ErrorResult{
@@ -16,6 +16,7 @@ var commonModelFacadeNames = set.NewStrings(
 	"ActionPruner",
 	"Agent",
 	"Application",
+	"Audit",
 	"CharmRevisionUpdater",
 	"Charms",
 	"Cleaner",
@@ -33,8 +34,10 @@ var commonModelFacadeNames = set.NewStrings(
 	"Pinger",
 	"RelationUnitsWatcher",
 	"RemoteRelations",
+	"SSHClient",
 	"Singular",
 	"StatusHistory",
+	"Storage",
 	"StringsWatcher",
 )
 
@@ -44,6 +44,7 @@ var allowedMethodsDuringUpgrades = map[string]set.Strings{
 		"AllAddresses",
 		"PublicKeys",
 		"Proxy",
+		"ModelCredentialForSSH",
 	),
 	"Pinger": set.NewStrings(
 		"Ping",
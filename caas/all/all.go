@@ -0,0 +1,39 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package all provides access to all of the CAAS brokers.
+package all
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/caas"
+	k8s "github.com/juju/juju/caas/kubernetes/provider"
+	swarm "github.com/juju/juju/caas/swarm/provider"
+	"github.com/juju/juju/environs"
+)
+
+// NewContainerBroker returns a broker for the CAAS
+// substrate of the cloud specified in args.
+func NewContainerBroker(args environs.OpenParams) (caas.Broker, error) {
+	switch args.Cloud.Type {
+	case k8s.ProviderType:
+		return k8s.NewK8sProvider(args)
+	case swarm.ProviderType:
+		return swarm.NewSwarmProvider(args)
+	}
+	return nil, errors.NotSupportedf("CAAS cloud type %q", args.Cloud.Type)
+}
+
+// ClusterMetadata returns details of the CAAS substrate of
+// the cloud specified by the cloud spec, without requiring
+// a model.
+func ClusterMetadata(cloud environs.CloudSpec) (*caas.ClusterMetadata, error) {
+	switch cloud.Type {
+	case k8s.ProviderType:
+		return k8s.ClusterMetadata(cloud)
+	case swarm.ProviderType:
+		return swarm.ClusterMetadata(cloud)
+	}
+	return nil, errors.NotSupportedf("CAAS cloud type %q", cloud.Type)
+}
@@ -4,23 +4,50 @@
 package caas
 
 import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/version"
+
+	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/core/application"
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/status"
-	"github.com/juju/juju/watcher"
+	"github.com/juju/juju/storage"
 )
 
 // NewContainerBrokerFunc returns a Container Broker.
-type NewContainerBrokerFunc func(environs.CloudSpec) (Broker, error)
+type NewContainerBrokerFunc func(args environs.OpenParams) (Broker, error)
 
 // Broker instances interact with the CAAS substrate.
 type Broker interface {
+	// Broker instances provide the storage providers used
+	// to validate the storage pools of a CAAS model.
+	storage.ProviderRegistry
+
+	// Create creates the resources in which the model's
+	// applications will run, and which are removed when
+	// the model is destroyed.
+	Create(args environs.CreateParams) error
+
+	// Destroy removes the model's resources, and any
+	// applications running in the model.
+	Destroy() error
+
+	// ClusterMetadata returns details of the CAAS substrate,
+	// used to check that it can run the model's applications.
+	ClusterMetadata() (*ClusterMetadata, error)
+
 	// EnsureOperator creates or updates an operator pod for running
 	// a charm for the specified application.
 	EnsureOperator(appName, agentPath string, config *OperatorConfig) error
 
-	// EnsureService creates or updates a service for pods with the given spec.
-	EnsureService(appName string, spec *ContainerSpec, numUnits int, config application.ConfigAttributes) error
+	// UpgradeOperator updates the operator pod for the specified
+	// application so that it runs the specified Juju agent version.
+	UpgradeOperator(appName string, vers version.Number) error
+
+	// EnsureService creates or updates a service for pods with the given params.
+	EnsureService(appName string, params *ServiceParams, numUnits int, config application.ConfigAttributes) error
 
 	// DeleteService deletes the specified service.
 	DeleteService(appName string) error
@@ -31,15 +58,148 @@ type Broker interface {
 	// UnexposeService removes external access to the specified service.
 	UnexposeService(appName string) error
 
-	// EnsureUnit creates or updates a pod with the given spec.
-	EnsureUnit(appName, unitName string, spec *ContainerSpec) error
+	// EnsureUnit creates or updates a pod with the given params.
+	EnsureUnit(appName, unitName string, params *ServiceParams) error
 
-	// WatchUnits returns a watcher which notifies when there
-	// are changes to units of the specified application.
-	WatchUnits(appName string) (watcher.NotifyWatcher, error)
+	// EnsureUnits creates or updates the pods of the specified
+	// units of an application, in one operation.
+	EnsureUnits(appName string, units []UnitSpec) error
+
+	// DeleteUnit deletes the pod of the specified unit.
+	DeleteUnit(unitName string) error
+
+	// WatchUnits returns a watcher which reports the units of
+	// the specified application which are added, updated or
+	// removed.
+	WatchUnits(appName string) (UnitsWatcher, error)
 
 	// Units returns all units of the specified application.
 	Units(appName string) ([]Unit, error)
+
+	// WatchLogs returns a watcher which reports the output of the
+	// containers of the specified application's pods, written since
+	// the specified time.
+	WatchLogs(appName string, since time.Time) (LogsWatcher, error)
+
+	// Exec runs a command in a container of a unit's pod, returning
+	// when the command exits or the abort channel is closed. If the
+	// command exits with a non-zero status, an *ExitError is returned.
+	Exec(params ExecParams, abort <-chan struct{}) error
+}
+
+// ServiceParams defines parameters used to create a service
+// or unit pod on the CAAS substrate.
+type ServiceParams struct {
+	// PodSpec is the spec used to configure a pod.
+	PodSpec *PodSpec
+
+	// Constraints is a set of constraints on
+	// the pod to create.
+	Constraints constraints.Value
+
+	// Placement is the placement directive used to
+	// restrict the nodes on which pods are scheduled.
+	Placement string
+
+	// DeploymentMode determines how the application's
+	// pods are managed.
+	DeploymentMode DeploymentMode
+
+	// Autoscale, if set, is the policy by which the
+	// number of the application's pods is scaled.
+	Autoscale *AutoscalePolicy
+
+	// Trusted reports whether the application has been
+	// trusted, and so may be granted the permissions its
+	// charm requests beyond those of every application.
+	Trusted bool
+
+	// ImagePullSecret, if set, is the name of an existing
+	// secret holding the credentials used to pull the pods'
+	// images, in addition to any credentials in the pod spec.
+	ImagePullSecret string
+
+	// PodLabels and PodAnnotations are extra labels and
+	// annotations applied to the pods. They do not replace
+	// those used by Juju to manage the pods.
+	PodLabels      map[string]string
+	PodAnnotations map[string]string
+
+	// StoragePools holds the config of the storage pools
+	// named by the pod spec's volumes, keyed on pool name.
+	StoragePools map[string]*storage.Config
+}
+
+// UnitSpec defines the pod of a unit to create or update.
+type UnitSpec struct {
+	// UnitName is the name of the unit.
+	UnitName string
+
+	// Params defines the unit's pod.
+	Params *ServiceParams
+}
+
+// AutoscalePolicy defines how the CAAS substrate scales
+// the number of pods of an application. When a policy is
+// in effect, the substrate decides the number of units,
+// within the policy's bounds.
+type AutoscalePolicy struct {
+	// MinUnits is the minimum number of units.
+	// If zero, the minimum is one unit.
+	MinUnits int
+
+	// MaxUnits is the maximum number of units.
+	MaxUnits int
+
+	// TargetCPUPercent is the average CPU utilisation,
+	// as a percentage of the CPU requested by each pod,
+	// which the autoscaler aims to maintain. If zero,
+	// the substrate's default is used.
+	TargetCPUPercent int
+}
+
+// Validate returns an error if the policy is not valid.
+func (p *AutoscalePolicy) Validate() error {
+	if p.MinUnits < 0 {
+		return errors.NotValidf("autoscale min units %d", p.MinUnits)
+	}
+	if p.MaxUnits < 1 || p.MaxUnits < p.MinUnits {
+		return errors.NotValidf("autoscale max units %d with min units %d", p.MaxUnits, p.MinUnits)
+	}
+	if p.TargetCPUPercent < 0 {
+		return errors.NotValidf("autoscale target cpu %d%%", p.TargetCPUPercent)
+	}
+	return nil
+}
+
+// DeploymentMode describes how the pods of an application
+// are managed by the CAAS substrate.
+type DeploymentMode string
+
+const (
+	// DeploymentStateless pods are interchangeable. Pods
+	// cannot share storage, so substrates may manage the
+	// pods of specs declaring storage as stateful ones.
+	DeploymentStateless DeploymentMode = "stateless"
+
+	// DeploymentStateful pods have stable identities
+	// and DNS names, are started in order, and each
+	// has its own storage which outlives the pod.
+	DeploymentStateful DeploymentMode = "stateful"
+
+	// DeploymentOnePerNode pods run one on each node of
+	// the cluster, so the number of units follows the
+	// number of nodes rather than being set by Juju.
+	DeploymentOnePerNode DeploymentMode = "one-per-node"
+)
+
+// Validate returns an error if the deployment mode is not valid.
+func (mode DeploymentMode) Validate() error {
+	switch mode {
+	case DeploymentStateless, DeploymentStateful, DeploymentOnePerNode:
+		return nil
+	}
+	return errors.NotValidf("deployment mode %q", mode)
 }
 
 // Unit represents information about the status of a "pod".
@@ -48,6 +208,53 @@ type Unit struct {
 	Address string
 	Ports   []string
 	Status  status.StatusInfo
+
+	// FilesystemInfo holds information about the
+	// filesystems mounted into the unit's containers.
+	FilesystemInfo []FilesystemInfo
+}
+
+// FilesystemInfo represents information about a filesystem
+// mounted by a unit, declared as a volume in the pod spec.
+type FilesystemInfo struct {
+	// StorageName is the name of the volume in the pod spec.
+	StorageName string
+
+	// FilesystemId is the provider-allocated unique
+	// ID of the filesystem.
+	FilesystemId string
+
+	// Size is the size of the filesystem, in MiB.
+	Size uint64
+
+	// MountPoint is the path at which the filesystem
+	// is mounted in the container.
+	MountPoint string
+
+	// ReadOnly is true if the filesystem is mounted read-only.
+	ReadOnly bool
+
+	// Status is the status of the filesystem.
+	Status status.StatusInfo
+
+	// Volume holds information about the volume
+	// backing the filesystem, if there is one.
+	Volume VolumeInfo
+}
+
+// VolumeInfo represents information about a volume
+// backing a unit's filesystem.
+type VolumeInfo struct {
+	// VolumeId is the provider-allocated unique
+	// ID of the volume.
+	VolumeId string
+
+	// Size is the size of the volume, in MiB.
+	Size uint64
+
+	// Persistent is true if the volume outlives
+	// the unit to which it is attached.
+	Persistent bool
 }
 
 // OperatorConfig is the config to use when creating an operator.
@@ -0,0 +1,74 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package caas
+
+import (
+	"fmt"
+
+	"github.com/juju/errors"
+	"github.com/juju/version"
+)
+
+// ClusterMetadata describes the CAAS substrate on which
+// a broker manages applications.
+type ClusterMetadata struct {
+	// Version is the version of the substrate's API server.
+	Version version.Number
+
+	// MinVersion is the oldest version of the
+	// substrate with which the broker works.
+	MinVersion version.Number
+
+	// StorageClasses holds the classes of storage
+	// which may be requested by pod volumes.
+	StorageClasses []StorageClass
+
+	// Nodes holds the capacity of the nodes
+	// on which pods may be scheduled.
+	Nodes []NodeCapacity
+}
+
+// StorageClass describes a class of storage
+// provided by the CAAS substrate.
+type StorageClass struct {
+	// Name is the name of the storage class.
+	Name string
+
+	// Provisioner is the name of the
+	// volume provisioner for the class.
+	Provisioner string
+
+	// Default is true if volumes not
+	// requesting a class use this one.
+	Default bool
+}
+
+// NodeCapacity describes the resources of a node
+// on which the substrate schedules pods.
+type NodeCapacity struct {
+	// Name is the name of the node.
+	Name string
+
+	// CpuPower is the node's CPU capacity, in the
+	// same units as the cpu-power constraint.
+	CpuPower uint64
+
+	// Mem is the node's memory, in MiB.
+	Mem uint64
+}
+
+// Validate returns an error if Juju cannot deploy
+// applications on the described substrate.
+func (m *ClusterMetadata) Validate() error {
+	if m.Version.Compare(m.MinVersion) < 0 {
+		return errors.NewNotSupported(nil, fmt.Sprintf(
+			"cluster version %v is not supported, version %v or later is required",
+			m.Version, m.MinVersion,
+		))
+	}
+	if len(m.Nodes) == 0 {
+		return errors.New("cluster has no schedulable nodes")
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package caas_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/version"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/caas"
+	"github.com/juju/juju/testing"
+)
+
+type ClusterSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&ClusterSuite{})
+
+func (s *ClusterSuite) TestValidate(c *gc.C) {
+	meta := caas.ClusterMetadata{
+		Version:    version.MustParse("1.9.3"),
+		MinVersion: version.MustParse("1.6.0"),
+		Nodes:      []caas.NodeCapacity{{Name: "node-1", CpuPower: 200, Mem: 4096}},
+	}
+	c.Assert(meta.Validate(), jc.ErrorIsNil)
+}
+
+func (s *ClusterSuite) TestValidateOldVersion(c *gc.C) {
+	meta := caas.ClusterMetadata{
+		Version:    version.MustParse("1.5.2"),
+		MinVersion: version.MustParse("1.6.0"),
+		Nodes:      []caas.NodeCapacity{{Name: "node-1"}},
+	}
+	c.Assert(meta.Validate(), gc.ErrorMatches, "cluster version 1.5.2 is not supported, version 1.6.0 or later is required")
+	c.Assert(meta.Validate(), jc.Satisfies, errors.IsNotSupported)
+}
+
+func (s *ClusterSuite) TestValidateNoNodes(c *gc.C) {
+	meta := caas.ClusterMetadata{
+		Version:    version.MustParse("1.9.3"),
+		MinVersion: version.MustParse("1.6.0"),
+	}
+	c.Assert(meta.Validate(), gc.ErrorMatches, "cluster has no schedulable nodes")
+}
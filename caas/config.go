@@ -4,9 +4,14 @@
 package caas
 
 import (
+	"strings"
+
 	"github.com/juju/errors"
 	"github.com/juju/schema"
+	"github.com/juju/utils/keyvalues"
 	"gopkg.in/juju/environschema.v1"
+
+	"github.com/juju/juju/core/application"
 )
 
 const (
@@ -18,6 +23,45 @@ const (
 
 	// JujuDefaultApplicationPath is the default value for juju-application-path.
 	JujuDefaultApplicationPath = "/"
+
+	// JujuDeploymentModeKey specifies how the pods of a CAAS application
+	// are managed, overriding any mode requested in the charm's pod spec.
+	JujuDeploymentModeKey = "juju-deployment-mode"
+
+	// JujuAutoscaleMinUnitsKey specifies the minimum number of units
+	// of an autoscaled CAAS application.
+	JujuAutoscaleMinUnitsKey = "juju-autoscale-min-units"
+
+	// JujuAutoscaleMaxUnitsKey specifies the maximum number of units
+	// of an autoscaled CAAS application. Autoscaling is enabled when
+	// this is set.
+	JujuAutoscaleMaxUnitsKey = "juju-autoscale-max-units"
+
+	// JujuAutoscaleTargetCPUKey specifies the average CPU utilisation,
+	// as a percentage of the requested CPU, which the autoscaler of
+	// a CAAS application aims to maintain.
+	JujuAutoscaleTargetCPUKey = "juju-autoscale-target-cpu"
+
+	// JujuImagePullSecretKey specifies the name of an existing secret
+	// holding the credentials used to pull a CAAS application's images
+	// from a private registry.
+	JujuImagePullSecretKey = "juju-image-pull-secret"
+
+	// JujuPodLabelsKey specifies extra labels applied to the pods
+	// of a CAAS application, as a comma separated list of key=value.
+	JujuPodLabelsKey = "juju-pod-labels"
+
+	// JujuPodAnnotationsKey specifies extra annotations applied to the
+	// pods of a CAAS application, as a comma separated list of key=value.
+	JujuPodAnnotationsKey = "juju-pod-annotations"
+
+	// JujuServiceLabelsKey specifies extra labels applied to the service
+	// of a CAAS application, as a comma separated list of key=value.
+	JujuServiceLabelsKey = "juju-service-labels"
+
+	// JujuServiceAnnotationsKey specifies extra annotations applied to the
+	// service of a CAAS application, as a comma separated list of key=value.
+	JujuServiceAnnotationsKey = "juju-service-annotations"
 )
 
 var configFields = environschema.Fields{
@@ -31,6 +75,52 @@ var configFields = environschema.Fields{
 		Type:        environschema.Tstring,
 		Group:       environschema.EnvironGroup,
 	},
+	JujuDeploymentModeKey: {
+		Description: "whether application pods are stateless, stateful with stable identities and storage, or run one per node",
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+		Values:      []interface{}{string(DeploymentStateless), string(DeploymentStateful), string(DeploymentOnePerNode)},
+	},
+	JujuAutoscaleMinUnitsKey: {
+		Description: "the minimum number of units of an autoscaled application",
+		Type:        environschema.Tint,
+		Group:       environschema.EnvironGroup,
+	},
+	JujuAutoscaleMaxUnitsKey: {
+		Description: "the maximum number of units of an autoscaled application; setting this enables autoscaling",
+		Type:        environschema.Tint,
+		Group:       environschema.EnvironGroup,
+	},
+	JujuAutoscaleTargetCPUKey: {
+		Description: "the target average CPU utilisation of an autoscaled application, as a percentage of requested CPU",
+		Type:        environschema.Tint,
+		Group:       environschema.EnvironGroup,
+	},
+	JujuImagePullSecretKey: {
+		Description: "the name of an existing secret holding the registry credentials used to pull application images",
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+	},
+	JujuPodLabelsKey: {
+		Description: "comma separated list of key=value labels applied to application pods",
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+	},
+	JujuPodAnnotationsKey: {
+		Description: "comma separated list of key=value annotations applied to application pods",
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+	},
+	JujuServiceLabelsKey: {
+		Description: "comma separated list of key=value labels applied to the application service",
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+	},
+	JujuServiceAnnotationsKey: {
+		Description: "comma separated list of key=value annotations applied to the application service",
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+	},
 }
 
 // ConfigSchema returns the valid fields for a CAAS application config.
@@ -64,3 +154,68 @@ func ConfigDefaults(providerDefaults schema.Defaults) schema.Defaults {
 	}
 	return defaults
 }
+
+// AutoscalePolicyFromConfig returns the autoscale policy set in the application
+// config, or nil if the application is not autoscaled.
+func AutoscalePolicyFromConfig(config application.ConfigAttributes) (*AutoscalePolicy, error) {
+	maxUnits, err := intAttr(config, JujuAutoscaleMaxUnitsKey)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if maxUnits == 0 {
+		return nil, nil
+	}
+	minUnits, err := intAttr(config, JujuAutoscaleMinUnitsKey)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	targetCPU, err := intAttr(config, JujuAutoscaleTargetCPUKey)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	policy := &AutoscalePolicy{
+		MinUnits:         minUnits,
+		MaxUnits:         maxUnits,
+		TargetCPUPercent: targetCPU,
+	}
+	if err := policy.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return policy, nil
+}
+
+// KeyValues returns the key/value pairs held by the specified
+// attribute as a comma separated list of key=value, or nil if
+// the attribute is not set.
+func KeyValues(config application.ConfigAttributes, key string) (map[string]string, error) {
+	value := strings.TrimSpace(config.GetString(key, ""))
+	if value == "" {
+		return nil, nil
+	}
+	var pairs []string
+	for _, pair := range strings.Split(value, ",") {
+		pairs = append(pairs, strings.TrimSpace(pair))
+	}
+	result, err := keyvalues.Parse(pairs, true)
+	if err != nil {
+		return nil, errors.Annotatef(err, "invalid %s", key)
+	}
+	return result, nil
+}
+
+// intAttr returns the value of the specified
+// integer attribute, or zero if it is not set.
+func intAttr(config application.ConfigAttributes, key string) (int, error) {
+	switch val := config.Get(key, nil).(type) {
+	case nil:
+		return 0, nil
+	case int:
+		return val, nil
+	case int64:
+		return int(val), nil
+	case float64:
+		return int(val), nil
+	default:
+		return 0, errors.NotValidf("%s value %v", key, val)
+	}
+}
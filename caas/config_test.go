@@ -10,6 +10,7 @@ import (
 	"gopkg.in/juju/environschema.v1"
 
 	"github.com/juju/juju/caas"
+	"github.com/juju/juju/core/application"
 	"github.com/juju/juju/testing"
 )
 
@@ -24,6 +25,52 @@ var baseFields = environschema.Fields{
 		Type:        environschema.Tstring,
 		Group:       environschema.EnvironGroup,
 	},
+	caas.JujuDeploymentModeKey: {
+		Description: "whether application pods are stateless, stateful with stable identities and storage, or run one per node",
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+		Values:      []interface{}{"stateless", "stateful", "one-per-node"},
+	},
+	caas.JujuAutoscaleMinUnitsKey: {
+		Description: "the minimum number of units of an autoscaled application",
+		Type:        environschema.Tint,
+		Group:       environschema.EnvironGroup,
+	},
+	caas.JujuAutoscaleMaxUnitsKey: {
+		Description: "the maximum number of units of an autoscaled application; setting this enables autoscaling",
+		Type:        environschema.Tint,
+		Group:       environschema.EnvironGroup,
+	},
+	caas.JujuAutoscaleTargetCPUKey: {
+		Description: "the target average CPU utilisation of an autoscaled application, as a percentage of requested CPU",
+		Type:        environschema.Tint,
+		Group:       environschema.EnvironGroup,
+	},
+	caas.JujuImagePullSecretKey: {
+		Description: "the name of an existing secret holding the registry credentials used to pull application images",
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+	},
+	caas.JujuPodLabelsKey: {
+		Description: "comma separated list of key=value labels applied to application pods",
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+	},
+	caas.JujuPodAnnotationsKey: {
+		Description: "comma separated list of key=value annotations applied to application pods",
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+	},
+	caas.JujuServiceLabelsKey: {
+		Description: "comma separated list of key=value labels applied to the application service",
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+	},
+	caas.JujuServiceAnnotationsKey: {
+		Description: "comma separated list of key=value annotations applied to the application service",
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+	},
 }
 
 var baseDefaults = schema.Defaults{
@@ -93,3 +140,58 @@ func (s *ConfigSuite) TestConfigSchemaProviderDefaults(c *gc.C) {
 	}
 	c.Assert(defaults, jc.DeepEquals, expectedDefaults)
 }
+
+func (s *ConfigSuite) TestAutoscalePolicyFromConfigNotSet(c *gc.C) {
+	policy, err := caas.AutoscalePolicyFromConfig(application.ConfigAttributes{
+		caas.JujuAutoscaleMinUnitsKey: 2,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(policy, gc.IsNil)
+}
+
+func (s *ConfigSuite) TestAutoscalePolicyFromConfig(c *gc.C) {
+	policy, err := caas.AutoscalePolicyFromConfig(application.ConfigAttributes{
+		caas.JujuAutoscaleMinUnitsKey:  int64(2),
+		caas.JujuAutoscaleMaxUnitsKey:  int64(5),
+		caas.JujuAutoscaleTargetCPUKey: float64(80),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(policy, jc.DeepEquals, &caas.AutoscalePolicy{
+		MinUnits:         2,
+		MaxUnits:         5,
+		TargetCPUPercent: 80,
+	})
+}
+
+func (s *ConfigSuite) TestAutoscalePolicyFromConfigInvalid(c *gc.C) {
+	_, err := caas.AutoscalePolicyFromConfig(application.ConfigAttributes{
+		caas.JujuAutoscaleMinUnitsKey: 5,
+		caas.JujuAutoscaleMaxUnitsKey: 2,
+	})
+	c.Assert(err, gc.ErrorMatches, "autoscale max units 2 with min units 5 not valid")
+}
+
+func (s *ConfigSuite) TestKeyValues(c *gc.C) {
+	values, err := caas.KeyValues(application.ConfigAttributes{
+		caas.JujuPodLabelsKey: "team=data, cost-centre=42,empty=",
+	}, caas.JujuPodLabelsKey)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(values, jc.DeepEquals, map[string]string{
+		"team":        "data",
+		"cost-centre": "42",
+		"empty":       "",
+	})
+}
+
+func (s *ConfigSuite) TestKeyValuesNotSet(c *gc.C) {
+	values, err := caas.KeyValues(application.ConfigAttributes{}, caas.JujuPodLabelsKey)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(values, gc.IsNil)
+}
+
+func (s *ConfigSuite) TestKeyValuesInvalid(c *gc.C) {
+	_, err := caas.KeyValues(application.ConfigAttributes{
+		caas.JujuPodLabelsKey: "team",
+	}, caas.JujuPodLabelsKey)
+	c.Assert(err, gc.ErrorMatches, `invalid juju-pod-labels: .*"team"`)
+}
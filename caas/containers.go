@@ -4,6 +4,8 @@
 package caas
 
 import (
+	"strings"
+
 	"github.com/juju/errors"
 	"gopkg.in/yaml.v2"
 )
@@ -15,26 +17,484 @@ type ContainerPort struct {
 	Protocol      string `yaml:"protocol"`
 }
 
+// ContainerVolume defines the attributes used to configure
+// persistent storage mounted into the container. The storage
+// is provisioned from either the named storage class on the
+// CAAS substrate, or the named Juju storage pool.
+type ContainerVolume struct {
+	Name         string `yaml:"name"`
+	MountPath    string `yaml:"mount-path"`
+	Size         string `yaml:"size"`
+	StorageClass string `yaml:"storage-class,omitempty"`
+	StoragePool  string `yaml:"storage-pool,omitempty"`
+	ReadOnly     bool   `yaml:"read-only,omitempty"`
+}
+
+// FileSet defines a set of files to be projected into the
+// container at the specified mount path. The file contents
+// are either specified inline, keyed on file name, or taken
+// from an existing secret on the CAAS substrate.
+type FileSet struct {
+	Name      string            `yaml:"name"`
+	MountPath string            `yaml:"mount-path"`
+	Files     map[string]string `yaml:"files,omitempty"`
+	Secret    string            `yaml:"secret,omitempty"`
+}
+
+func (f *FileSet) validate() error {
+	if f.Name == "" {
+		return errors.New("spec file set name is missing")
+	}
+	if f.MountPath == "" {
+		return errors.Errorf("spec file set %q mount path is missing", f.Name)
+	}
+	if (len(f.Files) == 0) == (f.Secret == "") {
+		return errors.Errorf("spec file set %q must specify exactly one of files or secret", f.Name)
+	}
+	return nil
+}
+
+// ContainerProbe defines a health check performed against
+// the container. Exactly one of HTTPGet, TCPSocket and Exec
+// must be specified.
+type ContainerProbe struct {
+	HTTPGet   *HTTPGetAction   `yaml:"http-get,omitempty"`
+	TCPSocket *TCPSocketAction `yaml:"tcp-socket,omitempty"`
+	Exec      *ExecAction      `yaml:"exec,omitempty"`
+
+	InitialDelaySeconds int32 `yaml:"initial-delay-seconds,omitempty"`
+	TimeoutSeconds      int32 `yaml:"timeout-seconds,omitempty"`
+	PeriodSeconds       int32 `yaml:"period-seconds,omitempty"`
+	SuccessThreshold    int32 `yaml:"success-threshold,omitempty"`
+	FailureThreshold    int32 `yaml:"failure-threshold,omitempty"`
+}
+
+// HTTPGetAction defines a probe which performs an HTTP GET request.
+type HTTPGetAction struct {
+	Path   string `yaml:"path,omitempty"`
+	Port   int    `yaml:"port"`
+	Scheme string `yaml:"scheme,omitempty"`
+}
+
+// TCPSocketAction defines a probe which opens a TCP connection.
+type TCPSocketAction struct {
+	Port int `yaml:"port"`
+}
+
+// ExecAction defines a probe which runs a command in the container.
+type ExecAction struct {
+	Command []string `yaml:"command"`
+}
+
+func (p *ContainerProbe) validate() error {
+	handlers := 0
+	if p.HTTPGet != nil {
+		if p.HTTPGet.Port <= 0 {
+			return errors.New("http-get port must be specified")
+		}
+		handlers++
+	}
+	if p.TCPSocket != nil {
+		if p.TCPSocket.Port <= 0 {
+			return errors.New("tcp-socket port must be specified")
+		}
+		handlers++
+	}
+	if p.Exec != nil {
+		if len(p.Exec.Command) == 0 {
+			return errors.New("exec command must be specified")
+		}
+		handlers++
+	}
+	if handlers != 1 {
+		return errors.New("exactly one of http-get, tcp-socket or exec must be specified")
+	}
+	return nil
+}
+
+// ImageCredentials defines the credentials used to pull
+// a container image from a private registry. If the registry
+// is not specified, it is taken from the image name.
+type ImageCredentials struct {
+	Registry string `yaml:"registry,omitempty"`
+	Username string `yaml:"username"`
+	Password string `yaml:"password"`
+}
+
+func (c *ImageCredentials) validate() error {
+	if c.Username == "" {
+		return errors.New("image credentials username is missing")
+	}
+	if c.Password == "" {
+		return errors.New("image credentials password is missing")
+	}
+	return nil
+}
+
+// DefaultGPUType is the type of GPU requested by
+// containers which do not specify one.
+const DefaultGPUType = "nvidia.com/gpu"
+
+// GPURequest defines the GPUs required by a container. The
+// type is the name of the resource advertised by the nodes
+// having GPUs, such as "nvidia.com/gpu" or "amd.com/gpu".
+type GPURequest struct {
+	Type  string `yaml:"type,omitempty"`
+	Count int    `yaml:"count"`
+}
+
+func (r *GPURequest) validate() error {
+	if r.Count <= 0 {
+		return errors.New("gpu count must be positive")
+	}
+	if r.Type != "" && !strings.Contains(r.Type, "/") {
+		return errors.Errorf("gpu type %q must be qualified by a domain, e.g. %q", r.Type, DefaultGPUType)
+	}
+	return nil
+}
+
 // ContainerSpec defines the data values used to configure
 // a container on the CAAS substrate.
 type ContainerSpec struct {
-	Name      string            `yaml:"name"`
-	ImageName string            `yaml:"image-name"`
-	Ports     []ContainerPort   `yaml:"ports,omitempty"`
-	Config    map[string]string `yaml:"config,omitempty"`
+	Name             string            `yaml:"name"`
+	ImageName        string            `yaml:"image-name"`
+	ImageCredentials *ImageCredentials `yaml:"image-credentials,omitempty"`
+	Ports            []ContainerPort   `yaml:"ports,omitempty"`
+	Config           map[string]string `yaml:"config,omitempty"`
+	Volumes          []ContainerVolume `yaml:"volumes,omitempty"`
+	Files            []FileSet         `yaml:"files,omitempty"`
+	GPU              *GPURequest       `yaml:"gpu,omitempty"`
+
+	LivenessProbe  *ContainerProbe `yaml:"liveness-probe,omitempty"`
+	ReadinessProbe *ContainerProbe `yaml:"readiness-probe,omitempty"`
 }
 
-// ParseContainerSpec parses a YAML string into a ContainerSpec struct.
-func ParseContainerSpec(in string) (*ContainerSpec, error) {
-	var spec ContainerSpec
-	if err := yaml.Unmarshal([]byte(in), &spec); err != nil {
+// RBACRule defines a permission on the CAAS substrate's
+// API, granting the verbs on the resources of the API groups.
+// The core API group is denoted by the empty string.
+type RBACRule struct {
+	APIGroups     []string `yaml:"api-groups,omitempty"`
+	Resources     []string `yaml:"resources"`
+	ResourceNames []string `yaml:"resource-names,omitempty"`
+	Verbs         []string `yaml:"verbs"`
+}
+
+func (r *RBACRule) validate() error {
+	if len(r.Resources) == 0 {
+		return errors.New("rule resources are missing")
+	}
+	if len(r.Verbs) == 0 {
+		return errors.New("rule verbs are missing")
+	}
+	return nil
+}
+
+// CustomResourceDefinition defines a custom resource type to be
+// installed on the CAAS substrate along with an application, for
+// use by charms which operate resources of that type. The plural
+// name defaults to the lower case kind followed by "s", and the
+// scope, one of "Namespaced" or "Cluster", to "Namespaced".
+type CustomResourceDefinition struct {
+	Group      string   `yaml:"group"`
+	Version    string   `yaml:"version"`
+	Kind       string   `yaml:"kind"`
+	Plural     string   `yaml:"plural,omitempty"`
+	Singular   string   `yaml:"singular,omitempty"`
+	ShortNames []string `yaml:"short-names,omitempty"`
+	Scope      string   `yaml:"scope,omitempty"`
+}
+
+const (
+	// CustomResourceNamespaced denotes custom resources
+	// which are created in a model's namespace.
+	CustomResourceNamespaced = "Namespaced"
+
+	// CustomResourceCluster denotes custom resources
+	// which are not scoped to a namespace.
+	CustomResourceCluster = "Cluster"
+)
+
+// PluralName returns the plural name of the custom resource.
+func (crd *CustomResourceDefinition) PluralName() string {
+	if crd.Plural != "" {
+		return crd.Plural
+	}
+	return strings.ToLower(crd.Kind) + "s"
+}
+
+// Name returns the name of the custom resource definition,
+// which is the plural name qualified by the API group.
+func (crd *CustomResourceDefinition) Name() string {
+	return crd.PluralName() + "." + crd.Group
+}
+
+func (crd *CustomResourceDefinition) validate() error {
+	if crd.Kind == "" {
+		return errors.New("kind is missing")
+	}
+	if crd.Group == "" {
+		return errors.Errorf("custom resource %q group is missing", crd.Kind)
+	}
+	// Only resources in groups qualified by a domain
+	// may be defined, as the others are reserved.
+	if !strings.Contains(crd.Group, ".") {
+		return errors.Errorf("custom resource %q group %q must be qualified by a domain", crd.Kind, crd.Group)
+	}
+	if crd.Version == "" {
+		return errors.Errorf("custom resource %q version is missing", crd.Kind)
+	}
+	switch crd.Scope {
+	case "", CustomResourceNamespaced, CustomResourceCluster:
+	default:
+		return errors.Errorf("custom resource %q scope %q not valid", crd.Kind, crd.Scope)
+	}
+	return nil
+}
+
+// PodSpec defines the data values used to configure
+// a pod on the CAAS substrate. The first container is
+// the application workload; any others run alongside
+// it as sidecars. Init containers are run to completion,
+// in order, before any of the other containers are started.
+// The charm may request a deployment mode for its pods;
+// if not specified, pods are stateless. The charm may also
+// request permissions, beyond the minimal ones it is given
+// by default, for its operator to use the substrate's API,
+// and custom resource types to be installed while the
+// application exists.
+type PodSpec struct {
+	Version        int             `yaml:"version,omitempty"`
+	Containers     []ContainerSpec `yaml:"containers"`
+	InitContainers []ContainerSpec `yaml:"init-containers,omitempty"`
+	DeploymentMode DeploymentMode  `yaml:"deployment-mode,omitempty"`
+	OperatorRules  []RBACRule      `yaml:"operator-rules,omitempty"`
+
+	CustomResourceDefinitions []CustomResourceDefinition `yaml:"custom-resource-definitions,omitempty"`
+}
+
+const (
+	// PodSpecV1 is the version of pod specs which do not declare
+	// one. Such specs are parsed leniently: unknown attributes are
+	// ignored, and a single container may be defined at the top
+	// level rather than in a list of containers.
+	PodSpecV1 = 1
+
+	// PodSpecV2 is the version of pod specs which are parsed
+	// strictly: unknown attributes are rejected, with the line
+	// on which they appear, and containers must be listed.
+	PodSpecV2 = 2
+
+	// CurrentPodSpecVersion is the latest version of the pod spec
+	// schema. Pod specs of older versions are still accepted.
+	CurrentPodSpecVersion = PodSpecV2
+)
+
+// ParsePodSpec parses a YAML string into a PodSpec struct.
+// The schema used is chosen by the version declared in the
+// YAML; specs without a version are parsed as version 1.
+func ParsePodSpec(in string) (*PodSpec, error) {
+	var header struct {
+		Version int `yaml:"version"`
+	}
+	if err := yaml.Unmarshal([]byte(in), &header); err != nil {
+		return nil, errors.Annotate(err, "parsing pod spec")
+	}
+	var (
+		spec *PodSpec
+		err  error
+	)
+	switch header.Version {
+	case 0, PodSpecV1:
+		spec, err = parsePodSpecV1(in)
+	case PodSpecV2:
+		spec, err = parsePodSpecV2(in)
+	default:
+		return nil, errors.NotSupportedf("pod spec version %d", header.Version)
+	}
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if err := spec.validate(); err != nil {
 		return nil, errors.Trace(err)
 	}
+	return spec, nil
+}
+
+// parsePodSpecV1 parses a version 1 pod spec, and converts
+// it to the current version. The YAML may either define a
+// list of containers, or the attributes of a single container
+// at the top level.
+func parsePodSpecV1(in string) (*PodSpec, error) {
+	var spec PodSpec
+	if err := yaml.Unmarshal([]byte(in), &spec); err != nil {
+		return nil, errors.Annotate(err, "parsing pod spec v1")
+	}
+	if len(spec.Containers) == 0 {
+		var container ContainerSpec
+		if err := yaml.Unmarshal([]byte(in), &container); err != nil {
+			return nil, errors.Annotate(err, "parsing pod spec v1")
+		}
+		spec.Containers = []ContainerSpec{container}
+	}
+	return &spec, nil
+}
+
+// parsePodSpecV2 parses a version 2 pod spec. Unlike version 1,
+// attributes not defined by the schema are errors, reported
+// along with the line on which they appear, as are values of
+// the wrong type.
+func parsePodSpecV2(in string) (*PodSpec, error) {
+	var spec PodSpec
+	if err := yaml.UnmarshalStrict([]byte(in), &spec); err != nil {
+		return nil, errors.Annotate(err, "parsing pod spec v2")
+	}
+	if len(spec.Containers) == 0 {
+		return nil, errors.New("parsing pod spec v2: containers are missing")
+	}
+	return &spec, nil
+}
+
+func (spec *PodSpec) validate() error {
+	if spec.DeploymentMode != "" {
+		if err := spec.DeploymentMode.Validate(); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	for _, rule := range spec.OperatorRules {
+		if err := rule.validate(); err != nil {
+			return errors.Annotate(err, "invalid operator rule")
+		}
+	}
+	crdNames := make(map[string]bool)
+	for _, crd := range spec.CustomResourceDefinitions {
+		if err := crd.validate(); err != nil {
+			return errors.Annotate(err, "invalid custom resource definition")
+		}
+		if crdNames[crd.Name()] {
+			return errors.Errorf("duplicate custom resource definition %q", crd.Name())
+		}
+		crdNames[crd.Name()] = true
+	}
+	containerNames := make(map[string]bool)
+	volumeNames := make(map[string]bool)
+	for _, container := range spec.Containers {
+		if err := container.validate(); err != nil {
+			return errors.Trace(err)
+		}
+		if containerNames[container.Name] {
+			return errors.Errorf("duplicate container name %q", container.Name)
+		}
+		containerNames[container.Name] = true
+		// Volumes are backed by storage belonging to the pod,
+		// so their names must be unique across all containers.
+		for _, vol := range container.Volumes {
+			if volumeNames[vol.Name] {
+				return errors.Errorf("duplicate spec volume name %q", vol.Name)
+			}
+			volumeNames[vol.Name] = true
+		}
+		// File sets are mounted as volumes in the same namespace.
+		for _, fileSet := range container.Files {
+			if volumeNames[fileSet.Name] {
+				return errors.Errorf("duplicate spec file set name %q", fileSet.Name)
+			}
+			volumeNames[fileSet.Name] = true
+		}
+	}
+	for _, container := range spec.InitContainers {
+		if err := container.validateInit(volumeNames); err != nil {
+			return errors.Annotatef(err, "invalid init container %q", container.Name)
+		}
+		if containerNames[container.Name] {
+			return errors.Errorf("duplicate container name %q", container.Name)
+		}
+		containerNames[container.Name] = true
+	}
+	return nil
+}
+
+// validateInit validates an init container. Init containers
+// run to completion so cannot have probes, and they may only
+// mount volumes declared by the workload containers.
+func (spec *ContainerSpec) validateInit(volumeNames map[string]bool) error {
 	if spec.Name == "" {
-		return nil, errors.New("spec name is missing")
+		return errors.New("spec name is missing")
 	}
 	if spec.ImageName == "" {
-		return nil, errors.New("spec image name is missing")
+		return errors.New("spec image name is missing")
 	}
-	return &spec, nil
+	if spec.ImageCredentials != nil {
+		if err := spec.ImageCredentials.validate(); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	if spec.LivenessProbe != nil || spec.ReadinessProbe != nil {
+		return errors.New("probes are not supported")
+	}
+	if spec.GPU != nil {
+		if err := spec.GPU.validate(); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	if len(spec.Files) > 0 {
+		return errors.New("file sets are not supported")
+	}
+	for _, vol := range spec.Volumes {
+		if !volumeNames[vol.Name] {
+			return errors.Errorf("volume %q not declared by any container", vol.Name)
+		}
+		if vol.MountPath == "" {
+			return errors.Errorf("spec volume %q mount path is missing", vol.Name)
+		}
+	}
+	return nil
+}
+
+func (spec *ContainerSpec) validate() error {
+	if spec.Name == "" {
+		return errors.New("spec name is missing")
+	}
+	if spec.ImageName == "" {
+		return errors.New("spec image name is missing")
+	}
+	if spec.ImageCredentials != nil {
+		if err := spec.ImageCredentials.validate(); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	for _, vol := range spec.Volumes {
+		if vol.Name == "" {
+			return errors.New("spec volume name is missing")
+		}
+		if vol.MountPath == "" {
+			return errors.Errorf("spec volume %q mount path is missing", vol.Name)
+		}
+		if vol.Size == "" {
+			return errors.Errorf("spec volume %q size is missing", vol.Name)
+		}
+		if vol.StorageClass != "" && vol.StoragePool != "" {
+			return errors.Errorf("spec volume %q cannot specify both storage class and storage pool", vol.Name)
+		}
+	}
+	for _, fileSet := range spec.Files {
+		if err := fileSet.validate(); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	if spec.LivenessProbe != nil {
+		if err := spec.LivenessProbe.validate(); err != nil {
+			return errors.Annotate(err, "invalid liveness probe")
+		}
+	}
+	if spec.ReadinessProbe != nil {
+		if err := spec.ReadinessProbe.validate(); err != nil {
+			return errors.Annotate(err, "invalid readiness probe")
+		}
+	}
+	if spec.GPU != nil {
+		if err := spec.GPU.validate(); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
 }
@@ -31,19 +31,21 @@ config:
   foo: bar
 `[1:]
 
-	spec, err := caas.ParseContainerSpec(specStr)
+	spec, err := caas.ParsePodSpec(specStr)
 	c.Assert(err, jc.ErrorIsNil)
-	c.Assert(spec, jc.DeepEquals, &caas.ContainerSpec{
-		Name:      "gitlab",
-		ImageName: "gitlab/latest",
-		Ports: []caas.ContainerPort{
-			{ContainerPort: 80, Protocol: "TCP"},
-			{ContainerPort: 443},
-		},
-		Config: map[string]string{
-			"attr": "foo=bar; fred=blogs",
-			"foo":  "bar",
-		},
+	c.Assert(spec, jc.DeepEquals, &caas.PodSpec{
+		Containers: []caas.ContainerSpec{{
+			Name:      "gitlab",
+			ImageName: "gitlab/latest",
+			Ports: []caas.ContainerPort{
+				{ContainerPort: 80, Protocol: "TCP"},
+				{ContainerPort: 443},
+			},
+			Config: map[string]string{
+				"attr": "foo=bar; fred=blogs",
+				"foo":  "bar",
+			},
+		}},
 	})
 }
 
@@ -53,7 +55,7 @@ func (s *ContainersSuite) TestParseMissingName(c *gc.C) {
 image-name: gitlab/latest
 `[1:]
 
-	_, err := caas.ParseContainerSpec(specStr)
+	_, err := caas.ParsePodSpec(specStr)
 	c.Assert(err, gc.ErrorMatches, "spec name is missing")
 }
 
@@ -63,6 +65,673 @@ func (s *ContainersSuite) TestParseMissingImage(c *gc.C) {
 name: gitlab
 `[1:]
 
-	_, err := caas.ParseContainerSpec(specStr)
+	_, err := caas.ParsePodSpec(specStr)
 	c.Assert(err, gc.ErrorMatches, "spec image name is missing")
 }
+
+func (s *ContainersSuite) TestParseVolumes(c *gc.C) {
+
+	specStr := `
+name: gitlab
+image-name: gitlab/latest
+volumes:
+- name: data
+  mount-path: /var/opt/gitlab
+  size: 10Gi
+  storage-class: fast
+- name: config
+  mount-path: /etc/gitlab
+  size: 100Mi
+  read-only: true
+- name: logs
+  mount-path: /var/log/gitlab
+  size: 1Gi
+  storage-pool: k8s-ebs
+`[1:]
+
+	spec, err := caas.ParsePodSpec(specStr)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(spec, jc.DeepEquals, &caas.PodSpec{
+		Containers: []caas.ContainerSpec{{
+			Name:      "gitlab",
+			ImageName: "gitlab/latest",
+			Volumes: []caas.ContainerVolume{
+				{Name: "data", MountPath: "/var/opt/gitlab", Size: "10Gi", StorageClass: "fast"},
+				{Name: "config", MountPath: "/etc/gitlab", Size: "100Mi", ReadOnly: true},
+				{Name: "logs", MountPath: "/var/log/gitlab", Size: "1Gi", StoragePool: "k8s-ebs"},
+			},
+		}},
+	})
+}
+
+func (s *ContainersSuite) TestParseInvalidVolumes(c *gc.C) {
+	for i, test := range []struct {
+		volumes string
+		err     string
+	}{{
+		volumes: "- mount-path: /data\n  size: 1Gi",
+		err:     "spec volume name is missing",
+	}, {
+		volumes: "- name: data\n  size: 1Gi",
+		err:     `spec volume "data" mount path is missing`,
+	}, {
+		volumes: "- name: data\n  mount-path: /data",
+		err:     `spec volume "data" size is missing`,
+	}, {
+		volumes: "- name: data\n  mount-path: /data\n  size: 1Gi\n- name: data\n  mount-path: /other\n  size: 1Gi",
+		err:     `duplicate spec volume name "data"`,
+	}, {
+		volumes: "- name: data\n  mount-path: /data\n  size: 1Gi\n  storage-class: fast\n  storage-pool: k8s-ebs",
+		err:     `spec volume "data" cannot specify both storage class and storage pool`,
+	}} {
+		c.Logf("test %d", i)
+		specStr := "name: gitlab\nimage-name: gitlab/latest\nvolumes:\n" + test.volumes + "\n"
+		_, err := caas.ParsePodSpec(specStr)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
+
+func (s *ContainersSuite) TestParseProbes(c *gc.C) {
+
+	specStr := `
+name: gitlab
+image-name: gitlab/latest
+liveness-probe:
+  http-get:
+    path: /health
+    port: 8080
+  initial-delay-seconds: 10
+  period-seconds: 5
+readiness-probe:
+  tcp-socket:
+    port: 80
+  failure-threshold: 3
+`[1:]
+
+	spec, err := caas.ParsePodSpec(specStr)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(spec, jc.DeepEquals, &caas.PodSpec{
+		Containers: []caas.ContainerSpec{{
+			Name:      "gitlab",
+			ImageName: "gitlab/latest",
+			LivenessProbe: &caas.ContainerProbe{
+				HTTPGet:             &caas.HTTPGetAction{Path: "/health", Port: 8080},
+				InitialDelaySeconds: 10,
+				PeriodSeconds:       5,
+			},
+			ReadinessProbe: &caas.ContainerProbe{
+				TCPSocket:        &caas.TCPSocketAction{Port: 80},
+				FailureThreshold: 3,
+			},
+		}},
+	})
+}
+
+func (s *ContainersSuite) TestParseInvalidProbes(c *gc.C) {
+	for i, test := range []struct {
+		probe string
+		err   string
+	}{{
+		probe: "liveness-probe:\n  initial-delay-seconds: 10",
+		err:   "invalid liveness probe: exactly one of http-get, tcp-socket or exec must be specified",
+	}, {
+		probe: "readiness-probe:\n  tcp-socket:\n    port: 80\n  exec:\n    command: [ls]",
+		err:   "invalid readiness probe: exactly one of http-get, tcp-socket or exec must be specified",
+	}, {
+		probe: "liveness-probe:\n  http-get:\n    path: /health",
+		err:   "invalid liveness probe: http-get port must be specified",
+	}, {
+		probe: "readiness-probe:\n  exec:\n    command: []",
+		err:   "invalid readiness probe: exec command must be specified",
+	}} {
+		c.Logf("test %d", i)
+		specStr := "name: gitlab\nimage-name: gitlab/latest\n" + test.probe + "\n"
+		_, err := caas.ParsePodSpec(specStr)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
+
+func (s *ContainersSuite) TestParseMultipleContainers(c *gc.C) {
+
+	specStr := `
+containers:
+- name: gitlab
+  image-name: gitlab/latest
+  ports:
+  - container-port: 80
+    protocol: TCP
+- name: exporter
+  image-name: prom/exporter
+  ports:
+  - container-port: 9100
+`[1:]
+
+	spec, err := caas.ParsePodSpec(specStr)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(spec, jc.DeepEquals, &caas.PodSpec{
+		Containers: []caas.ContainerSpec{{
+			Name:      "gitlab",
+			ImageName: "gitlab/latest",
+			Ports:     []caas.ContainerPort{{ContainerPort: 80, Protocol: "TCP"}},
+		}, {
+			Name:      "exporter",
+			ImageName: "prom/exporter",
+			Ports:     []caas.ContainerPort{{ContainerPort: 9100}},
+		}},
+	})
+}
+
+func (s *ContainersSuite) TestParseV2(c *gc.C) {
+
+	specStr := `
+version: 2
+containers:
+- name: gitlab
+  image-name: gitlab/latest
+  ports:
+  - container-port: 80
+    protocol: TCP
+  volumes:
+  - name: data
+    mount-path: /var/opt/gitlab
+    size: 1Gi
+deployment-mode: stateful
+`[1:]
+
+	spec, err := caas.ParsePodSpec(specStr)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(spec, jc.DeepEquals, &caas.PodSpec{
+		Version: caas.PodSpecV2,
+		Containers: []caas.ContainerSpec{{
+			Name:      "gitlab",
+			ImageName: "gitlab/latest",
+			Ports:     []caas.ContainerPort{{ContainerPort: 80, Protocol: "TCP"}},
+			Volumes: []caas.ContainerVolume{
+				{Name: "data", MountPath: "/var/opt/gitlab", Size: "1Gi"},
+			},
+		}},
+		DeploymentMode: caas.DeploymentStateful,
+	})
+}
+
+func (s *ContainersSuite) TestParseV1IgnoresUnknownAttributes(c *gc.C) {
+
+	specStr := `
+name: gitlab
+image-name: gitlab/latest
+restart-policy: always
+`[1:]
+
+	spec, err := caas.ParsePodSpec(specStr)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(spec, jc.DeepEquals, &caas.PodSpec{
+		Containers: []caas.ContainerSpec{{
+			Name:      "gitlab",
+			ImageName: "gitlab/latest",
+		}},
+	})
+}
+
+func (s *ContainersSuite) TestParseInvalidV2(c *gc.C) {
+	for i, test := range []struct {
+		spec string
+		err  string
+	}{{
+		spec: `
+version: 2
+containers:
+- name: gitlab
+  image-name: gitlab/latest
+  restart-policy: always
+`[1:],
+		err: `(?s)parsing pod spec v2: .*line 5: field restart-policy not found.*`,
+	}, {
+		spec: `
+version: 2
+containers:
+- name: gitlab
+  image-name: gitlab/latest
+  ports:
+  - container-port: http
+`[1:],
+		err: `(?s)parsing pod spec v2: .*line 6: cannot unmarshal !!str ` + "`http`" + ` into int.*`,
+	}, {
+		spec: `
+version: 2
+name: gitlab
+image-name: gitlab/latest
+`[1:],
+		err: `(?s)parsing pod spec v2: .*line 2: field name not found.*`,
+	}, {
+		spec: `
+version: 2
+deployment-mode: stateful
+`[1:],
+		err: `parsing pod spec v2: containers are missing`,
+	}, {
+		spec: `
+version: 3
+containers:
+- name: gitlab
+  image-name: gitlab/latest
+`[1:],
+		err: `pod spec version 3 not supported`,
+	}} {
+		c.Logf("test %d", i)
+		_, err := caas.ParsePodSpec(test.spec)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
+
+func (s *ContainersSuite) TestParseDuplicateContainers(c *gc.C) {
+
+	specStr := `
+containers:
+- name: gitlab
+  image-name: gitlab/latest
+- name: gitlab
+  image-name: gitlab/other
+`[1:]
+
+	_, err := caas.ParsePodSpec(specStr)
+	c.Assert(err, gc.ErrorMatches, `duplicate container name "gitlab"`)
+}
+
+func (s *ContainersSuite) TestParseDuplicateVolumesAcrossContainers(c *gc.C) {
+
+	specStr := `
+containers:
+- name: gitlab
+  image-name: gitlab/latest
+  volumes:
+  - name: data
+    mount-path: /data
+    size: 1Gi
+- name: exporter
+  image-name: prom/exporter
+  volumes:
+  - name: data
+    mount-path: /data
+    size: 1Gi
+`[1:]
+
+	_, err := caas.ParsePodSpec(specStr)
+	c.Assert(err, gc.ErrorMatches, `duplicate spec volume name "data"`)
+}
+
+func (s *ContainersSuite) TestParseInitContainers(c *gc.C) {
+
+	specStr := `
+name: gitlab
+image-name: gitlab/latest
+volumes:
+- name: data
+  mount-path: /var/opt/gitlab
+  size: 1Gi
+init-containers:
+- name: migrate
+  image-name: gitlab/migrate
+  config:
+    mode: upgrade
+- name: chown
+  image-name: busybox
+  volumes:
+  - name: data
+    mount-path: /data
+`[1:]
+
+	spec, err := caas.ParsePodSpec(specStr)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(spec, jc.DeepEquals, &caas.PodSpec{
+		Containers: []caas.ContainerSpec{{
+			Name:      "gitlab",
+			ImageName: "gitlab/latest",
+			Volumes: []caas.ContainerVolume{
+				{Name: "data", MountPath: "/var/opt/gitlab", Size: "1Gi"},
+			},
+		}},
+		InitContainers: []caas.ContainerSpec{{
+			Name:      "migrate",
+			ImageName: "gitlab/migrate",
+			Config:    map[string]string{"mode": "upgrade"},
+		}, {
+			Name:      "chown",
+			ImageName: "busybox",
+			Volumes: []caas.ContainerVolume{
+				{Name: "data", MountPath: "/data"},
+			},
+		}},
+	})
+}
+
+func (s *ContainersSuite) TestParseInvalidInitContainers(c *gc.C) {
+	for i, test := range []struct {
+		init string
+		err  string
+	}{{
+		init: "- name: migrate",
+		err:  `invalid init container "migrate": spec image name is missing`,
+	}, {
+		init: "- name: migrate\n  image-name: gitlab/migrate\n  liveness-probe:\n    tcp-socket:\n      port: 80",
+		err:  `invalid init container "migrate": probes are not supported`,
+	}, {
+		init: "- name: chown\n  image-name: busybox\n  volumes:\n  - name: other\n    mount-path: /data",
+		err:  `invalid init container "chown": volume "other" not declared by any container`,
+	}, {
+		init: "- name: gitlab\n  image-name: busybox",
+		err:  `duplicate container name "gitlab"`,
+	}} {
+		c.Logf("test %d", i)
+		specStr := "name: gitlab\nimage-name: gitlab/latest\ninit-containers:\n" + test.init + "\n"
+		_, err := caas.ParsePodSpec(specStr)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
+
+func (s *ContainersSuite) TestParseFiles(c *gc.C) {
+
+	specStr := `
+name: gitlab
+image-name: gitlab/latest
+files:
+- name: configuration
+  mount-path: /etc/gitlab
+  files:
+    gitlab.rb: |
+      external_url 'https://gitlab.example.com'
+- name: credentials
+  mount-path: /etc/gitlab/ssl
+  secret: gitlab-tls
+`[1:]
+
+	spec, err := caas.ParsePodSpec(specStr)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(spec, jc.DeepEquals, &caas.PodSpec{
+		Containers: []caas.ContainerSpec{{
+			Name:      "gitlab",
+			ImageName: "gitlab/latest",
+			Files: []caas.FileSet{{
+				Name:      "configuration",
+				MountPath: "/etc/gitlab",
+				Files: map[string]string{
+					"gitlab.rb": "external_url 'https://gitlab.example.com'\n",
+				},
+			}, {
+				Name:      "credentials",
+				MountPath: "/etc/gitlab/ssl",
+				Secret:    "gitlab-tls",
+			}},
+		}},
+	})
+}
+
+func (s *ContainersSuite) TestParseInvalidFiles(c *gc.C) {
+	for i, test := range []struct {
+		files string
+		err   string
+	}{{
+		files: "- mount-path: /etc/gitlab\n  secret: foo",
+		err:   "spec file set name is missing",
+	}, {
+		files: "- name: config\n  secret: foo",
+		err:   `spec file set "config" mount path is missing`,
+	}, {
+		files: "- name: config\n  mount-path: /etc/gitlab",
+		err:   `spec file set "config" must specify exactly one of files or secret`,
+	}, {
+		files: "- name: config\n  mount-path: /etc/gitlab\n  secret: foo\n  files:\n    a: b",
+		err:   `spec file set "config" must specify exactly one of files or secret`,
+	}, {
+		files: "- name: data\n  mount-path: /etc/gitlab\n  secret: foo",
+		err:   `duplicate spec file set name "data"`,
+	}} {
+		c.Logf("test %d", i)
+		specStr := "name: gitlab\nimage-name: gitlab/latest\n" +
+			"volumes:\n- name: data\n  mount-path: /data\n  size: 1Gi\n" +
+			"files:\n" + test.files + "\n"
+		_, err := caas.ParsePodSpec(specStr)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
+
+func (s *ContainersSuite) TestParseDeploymentMode(c *gc.C) {
+
+	specStr := `
+deployment-mode: stateful
+containers:
+- name: mariadb
+  image-name: mariadb/latest
+`[1:]
+
+	spec, err := caas.ParsePodSpec(specStr)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(spec, jc.DeepEquals, &caas.PodSpec{
+		DeploymentMode: caas.DeploymentStateful,
+		Containers: []caas.ContainerSpec{{
+			Name:      "mariadb",
+			ImageName: "mariadb/latest",
+		}},
+	})
+}
+
+func (s *ContainersSuite) TestParseInvalidDeploymentMode(c *gc.C) {
+
+	specStr := `
+deployment-mode: daemon
+name: mariadb
+image-name: mariadb/latest
+`[1:]
+
+	_, err := caas.ParsePodSpec(specStr)
+	c.Assert(err, gc.ErrorMatches, `deployment mode "daemon" not valid`)
+}
+
+func (s *ContainersSuite) TestParseOperatorRules(c *gc.C) {
+
+	specStr := `
+operator-rules:
+- resources: [pods]
+  verbs: [get, list, delete]
+- api-groups: [extensions]
+  resources: [deployments]
+  resource-names: [juju-unit-storage]
+  verbs: [get]
+containers:
+- name: gitlab
+  image-name: gitlab/latest
+`[1:]
+
+	spec, err := caas.ParsePodSpec(specStr)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(spec, jc.DeepEquals, &caas.PodSpec{
+		Containers: []caas.ContainerSpec{{
+			Name:      "gitlab",
+			ImageName: "gitlab/latest",
+		}},
+		OperatorRules: []caas.RBACRule{{
+			Resources: []string{"pods"},
+			Verbs:     []string{"get", "list", "delete"},
+		}, {
+			APIGroups:     []string{"extensions"},
+			Resources:     []string{"deployments"},
+			ResourceNames: []string{"juju-unit-storage"},
+			Verbs:         []string{"get"},
+		}},
+	})
+}
+
+func (s *ContainersSuite) TestParseInvalidOperatorRules(c *gc.C) {
+	for i, test := range []struct {
+		rules string
+		err   string
+	}{{
+		rules: "- verbs: [get]",
+		err:   "invalid operator rule: rule resources are missing",
+	}, {
+		rules: "- resources: [pods]",
+		err:   "invalid operator rule: rule verbs are missing",
+	}} {
+		c.Logf("test %d", i)
+		specStr := "operator-rules:\n" + test.rules + "\n" +
+			"name: gitlab\nimage-name: gitlab/latest\n"
+		_, err := caas.ParsePodSpec(specStr)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
+
+func (s *ContainersSuite) TestParseCustomResourceDefinitions(c *gc.C) {
+
+	specStr := `
+custom-resource-definitions:
+- group: etcd.database.coreos.com
+  version: v1beta2
+  kind: EtcdCluster
+  short-names: [etcd]
+- group: etcd.database.coreos.com
+  version: v1beta2
+  kind: EtcdBackup
+  plural: etcdbackups
+  scope: Cluster
+containers:
+- name: etcd-operator
+  image-name: coreos/etcd-operator
+`[1:]
+
+	spec, err := caas.ParsePodSpec(specStr)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(spec, jc.DeepEquals, &caas.PodSpec{
+		Containers: []caas.ContainerSpec{{
+			Name:      "etcd-operator",
+			ImageName: "coreos/etcd-operator",
+		}},
+		CustomResourceDefinitions: []caas.CustomResourceDefinition{{
+			Group:      "etcd.database.coreos.com",
+			Version:    "v1beta2",
+			Kind:       "EtcdCluster",
+			ShortNames: []string{"etcd"},
+		}, {
+			Group:   "etcd.database.coreos.com",
+			Version: "v1beta2",
+			Kind:    "EtcdBackup",
+			Plural:  "etcdbackups",
+			Scope:   caas.CustomResourceCluster,
+		}},
+	})
+	crd := spec.CustomResourceDefinitions[0]
+	c.Assert(crd.PluralName(), gc.Equals, "etcdclusters")
+	c.Assert(crd.Name(), gc.Equals, "etcdclusters.etcd.database.coreos.com")
+}
+
+func (s *ContainersSuite) TestParseInvalidCustomResourceDefinitions(c *gc.C) {
+	for i, test := range []struct {
+		crds string
+		err  string
+	}{{
+		crds: "- group: example.com\n  version: v1",
+		err:  "invalid custom resource definition: kind is missing",
+	}, {
+		crds: "- kind: Widget\n  version: v1",
+		err:  `invalid custom resource definition: custom resource "Widget" group is missing`,
+	}, {
+		crds: "- kind: Widget\n  group: apps\n  version: v1",
+		err:  `invalid custom resource definition: custom resource "Widget" group "apps" must be qualified by a domain`,
+	}, {
+		crds: "- kind: Widget\n  group: example.com",
+		err:  `invalid custom resource definition: custom resource "Widget" version is missing`,
+	}, {
+		crds: "- kind: Widget\n  group: example.com\n  version: v1\n  scope: Global",
+		err:  `invalid custom resource definition: custom resource "Widget" scope "Global" not valid`,
+	}, {
+		crds: "- kind: Widget\n  group: example.com\n  version: v1\n" +
+			"- kind: Gadget\n  plural: widgets\n  group: example.com\n  version: v1",
+		err: `duplicate custom resource definition "widgets.example.com"`,
+	}} {
+		c.Logf("test %d", i)
+		specStr := "custom-resource-definitions:\n" + test.crds + "\n" +
+			"name: gitlab\nimage-name: gitlab/latest\n"
+		_, err := caas.ParsePodSpec(specStr)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
+
+func (s *ContainersSuite) TestParseImageCredentials(c *gc.C) {
+
+	specStr := `
+name: gitlab
+image-name: registry.example.com/gitlab/latest
+image-credentials:
+  username: fred
+  password: secret
+`[1:]
+
+	spec, err := caas.ParsePodSpec(specStr)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(spec, jc.DeepEquals, &caas.PodSpec{
+		Containers: []caas.ContainerSpec{{
+			Name:      "gitlab",
+			ImageName: "registry.example.com/gitlab/latest",
+			ImageCredentials: &caas.ImageCredentials{
+				Username: "fred",
+				Password: "secret",
+			},
+		}},
+	})
+}
+
+func (s *ContainersSuite) TestParseInvalidImageCredentials(c *gc.C) {
+	for i, test := range []struct {
+		credentials string
+		err         string
+	}{{
+		credentials: "  password: secret",
+		err:         "image credentials username is missing",
+	}, {
+		credentials: "  username: fred",
+		err:         "image credentials password is missing",
+	}} {
+		c.Logf("test %d", i)
+		specStr := "name: gitlab\nimage-name: gitlab/latest\n" +
+			"image-credentials:\n" + test.credentials + "\n"
+		_, err := caas.ParsePodSpec(specStr)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
+
+func (s *ContainersSuite) TestParseGPU(c *gc.C) {
+
+	specStr := `
+name: tensorflow
+image-name: tensorflow/tensorflow:latest-gpu
+gpu:
+  count: 2
+`[1:]
+
+	spec, err := caas.ParsePodSpec(specStr)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(spec, jc.DeepEquals, &caas.PodSpec{
+		Containers: []caas.ContainerSpec{{
+			Name:      "tensorflow",
+			ImageName: "tensorflow/tensorflow:latest-gpu",
+			GPU:       &caas.GPURequest{Count: 2},
+		}},
+	})
+}
+
+func (s *ContainersSuite) TestParseInvalidGPU(c *gc.C) {
+	for i, test := range []struct {
+		gpu string
+		err string
+	}{{
+		gpu: "  type: nvidia.com/gpu",
+		err: "gpu count must be positive",
+	}, {
+		gpu: "  type: gpu\n  count: 1",
+		err: `gpu type "gpu" must be qualified by a domain, e.g. "nvidia.com/gpu"`,
+	}} {
+		c.Logf("test %d", i)
+		specStr := "name: tensorflow\nimage-name: tensorflow/tensorflow\n" +
+			"gpu:\n" + test.gpu + "\n"
+		_, err := caas.ParsePodSpec(specStr)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
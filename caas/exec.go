@@ -0,0 +1,73 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package caas
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/juju/errors"
+	"gopkg.in/juju/names.v2"
+)
+
+// ExecParams holds the parameters for running a command
+// in a container of a unit's pod.
+type ExecParams struct {
+	// UnitName is the name of the unit in whose
+	// pod the command is run.
+	UnitName string
+
+	// Operator, if true, runs the command in the operator
+	// pod of the unit's application, where the unit's
+	// hooks are run, rather than in the unit's pod.
+	Operator bool
+
+	// ContainerName is the name of the container in which the
+	// command is run. If empty, the pod's first container is used.
+	ContainerName string
+
+	// Commands holds the command to run and its arguments.
+	Commands []string
+
+	// TTY, if true, allocates a terminal for the command.
+	TTY bool
+
+	// Stdin, if not nil, is read as the command's input.
+	Stdin io.Reader
+
+	// Stdout and Stderr receive the command's output.
+	// When a terminal is allocated, all output is
+	// written to Stdout.
+	Stdout io.Writer
+	Stderr io.Writer
+}
+
+// Validate returns an error if the parameters are not valid.
+func (p *ExecParams) Validate() error {
+	if !names.IsValidUnit(p.UnitName) {
+		return errors.NotValidf("unit name %q", p.UnitName)
+	}
+	if len(p.Commands) == 0 {
+		return errors.NotValidf("empty command")
+	}
+	if p.Stdout == nil {
+		return errors.NotValidf("missing stdout")
+	}
+	if p.Stderr == nil && !p.TTY {
+		return errors.NotValidf("missing stderr")
+	}
+	return nil
+}
+
+// ExitError is returned by Broker.Exec when the
+// command runs but exits with a non-zero status.
+type ExitError struct {
+	// Code is the command's exit status.
+	Code int
+}
+
+// Error is part of the error interface.
+func (e *ExitError) Error() string {
+	return fmt.Sprintf("command terminated with exit code %d", e.Code)
+}
@@ -0,0 +1,70 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package caas_test
+
+import (
+	"bytes"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/caas"
+	"github.com/juju/juju/testing"
+)
+
+type ExecSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&ExecSuite{})
+
+func (s *ExecSuite) validParams() caas.ExecParams {
+	return caas.ExecParams{
+		UnitName: "gitlab/0",
+		Commands: []string{"ls", "-l"},
+		Stdout:   &bytes.Buffer{},
+		Stderr:   &bytes.Buffer{},
+	}
+}
+
+func (s *ExecSuite) TestValidate(c *gc.C) {
+	p := s.validParams()
+	c.Assert(p.Validate(), jc.ErrorIsNil)
+}
+
+func (s *ExecSuite) TestValidateTTYWithoutStderr(c *gc.C) {
+	p := s.validParams()
+	p.TTY = true
+	p.Stderr = nil
+	c.Assert(p.Validate(), jc.ErrorIsNil)
+}
+
+func (s *ExecSuite) TestValidateErrors(c *gc.C) {
+	for i, test := range []struct {
+		modify func(*caas.ExecParams)
+		err    string
+	}{{
+		modify: func(p *caas.ExecParams) { p.UnitName = "gitlab" },
+		err:    `unit name "gitlab" not valid`,
+	}, {
+		modify: func(p *caas.ExecParams) { p.Commands = nil },
+		err:    `empty command not valid`,
+	}, {
+		modify: func(p *caas.ExecParams) { p.Stdout = nil },
+		err:    `missing stdout not valid`,
+	}, {
+		modify: func(p *caas.ExecParams) { p.Stderr = nil },
+		err:    `missing stderr not valid`,
+	}} {
+		c.Logf("test %d", i)
+		p := s.validParams()
+		test.modify(&p)
+		c.Check(p.Validate(), gc.ErrorMatches, test.err)
+	}
+}
+
+func (s *ExecSuite) TestExitError(c *gc.C) {
+	err := &caas.ExitError{Code: 3}
+	c.Assert(err, gc.ErrorMatches, "command terminated with exit code 3")
+}
@@ -0,0 +1,6 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package clientconfig
+
+var InClusterConfig = &inClusterConfig
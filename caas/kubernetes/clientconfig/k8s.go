@@ -6,6 +6,7 @@ import (
 
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
+	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
 
@@ -14,12 +15,31 @@ import (
 
 var logger = loggo.GetLogger("juju.caas.kubernetes.clientconfig")
 
+// inClusterContextName is the name of the context, cloud and
+// credential of the configuration of the cluster in which the
+// process is running.
+const inClusterContextName = "in-cluster"
+
+// inClusterConfig is patched out in tests.
+var inClusterConfig = rest.InClusterConfig
+
 // K8SClientConfig parses Kubernetes client configuration from the default location or $KUBECONFIG.
+// If there is no such file and the process is running in a Kubernetes pod, the configuration
+// of the cluster in which it is running is returned.
 func K8SClientConfig() (*ClientConfig, error) {
 
 	configPath := getKubeConfigPath()
 
 	config, err := clientcmd.LoadFromFile(configPath)
+	if os.IsNotExist(err) {
+		inCluster, inClusterErr := k8sInClusterConfig()
+		if inClusterErr == nil {
+			return inCluster, nil
+		}
+		if inClusterErr != rest.ErrNotInCluster {
+			return nil, errors.Annotate(inClusterErr, "failed to read in-cluster kubernetes config")
+		}
+	}
 	if err != nil {
 		return nil, errors.Annotatef(err, "failed to read kubernetes config from '%s'", configPath)
 	}
@@ -47,6 +67,45 @@ func K8SClientConfig() (*ClientConfig, error) {
 	}, nil
 }
 
+// k8sInClusterConfig returns a ClientConfig with a single context
+// holding the API server and service account token of the cluster
+// in which the process is running.
+func k8sInClusterConfig() (*ClientConfig, error) {
+	restConfig, err := inClusterConfig()
+	if err != nil {
+		return nil, err
+	}
+	caData := restConfig.TLSClientConfig.CAData
+	if len(caData) == 0 && restConfig.TLSClientConfig.CAFile != "" {
+		caData, err = ioutil.ReadFile(restConfig.TLSClientConfig.CAFile)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	return &ClientConfig{
+		Type: "kubernetes",
+		Contexts: map[string]Context{
+			inClusterContextName: {
+				CloudName:      inClusterContextName,
+				CredentialName: inClusterContextName,
+			},
+		},
+		CurrentContext: inClusterContextName,
+		Clouds: map[string]CloudConfig{
+			inClusterContextName: {
+				Endpoint:   restConfig.Host,
+				Attributes: map[string]interface{}{"CAData": string(caData)},
+			},
+		},
+		Credentials: map[string]cloud.Credential{
+			inClusterContextName: cloud.NewCredential(
+				cloud.OAuth2AuthType,
+				map[string]string{"Token": restConfig.BearerToken},
+			),
+		},
+	}, nil
+}
+
 func contextsFromConfig(config *clientcmdapi.Config) (map[string]Context, error) {
 	rv := map[string]Context{}
 	for name, ctx := range config.Contexts {
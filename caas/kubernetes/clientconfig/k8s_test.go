@@ -10,6 +10,7 @@ import (
 
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
+	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 
 	"github.com/juju/juju/caas/kubernetes/clientconfig"
@@ -256,3 +257,55 @@ func (s *k8sConfigSuite) TestGetSingleConfigReadsFilePaths(c *gc.C) {
 	s.writeTempKubeConfig(c, "singleConfigWithPaths", string(singleConfigWithPathsYAML))
 	s.assertSingleConfig(c)
 }
+
+func (s *k8sConfigSuite) TestGetInClusterConfig(c *gc.C) {
+	s.PatchEnvironment("KUBECONFIG", filepath.Join(s.dir, "missing"))
+	caFile := filepath.Join(s.dir, "ca.crt")
+	err := ioutil.WriteFile(caFile, []byte("A"), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+	s.PatchValue(clientconfig.InClusterConfig, func() (*rest.Config, error) {
+		return &rest.Config{
+			Host:            "https://10.0.0.1:443",
+			BearerToken:     "sa-token",
+			TLSClientConfig: rest.TLSClientConfig{CAFile: caFile},
+		}, nil
+	})
+
+	cfg, err := clientconfig.K8SClientConfig()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cfg, jc.DeepEquals,
+		&clientconfig.ClientConfig{
+			Type: "kubernetes",
+			Contexts: map[string]clientconfig.Context{
+				"in-cluster": clientconfig.Context{
+					CloudName:      "in-cluster",
+					CredentialName: "in-cluster"}},
+			CurrentContext: "in-cluster",
+			Clouds: map[string]clientconfig.CloudConfig{
+				"in-cluster": clientconfig.CloudConfig{
+					Endpoint:   "https://10.0.0.1:443",
+					Attributes: map[string]interface{}{"CAData": "A"}}},
+			Credentials: map[string]cloud.Credential{
+				"in-cluster": cloud.NewCredential(
+					cloud.OAuth2AuthType,
+					map[string]string{"Token": "sa-token"})},
+		})
+}
+
+func (s *k8sConfigSuite) TestGetConfigPrefersKubeConfig(c *gc.C) {
+	s.writeTempKubeConfig(c, "singleConfig", singleConfigYAML)
+	s.PatchValue(clientconfig.InClusterConfig, func() (*rest.Config, error) {
+		c.Fatalf("unexpected in-cluster config lookup")
+		return nil, nil
+	})
+	s.assertSingleConfig(c)
+}
+
+func (s *k8sConfigSuite) TestGetConfigNotInCluster(c *gc.C) {
+	s.PatchEnvironment("KUBECONFIG", filepath.Join(s.dir, "missing"))
+	s.PatchValue(clientconfig.InClusterConfig, func() (*rest.Config, error) {
+		return nil, rest.ErrNotInCluster
+	})
+	_, err := clientconfig.K8SClientConfig()
+	c.Assert(err, gc.ErrorMatches, "failed to read kubernetes config from '.*missing': .*")
+}
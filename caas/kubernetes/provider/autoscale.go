@@ -0,0 +1,94 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package provider
+
+import (
+	"github.com/juju/errors"
+	k8serrors "k8s.io/client-go/pkg/api/errors"
+	"k8s.io/client-go/pkg/api/v1"
+	autoscaling "k8s.io/client-go/pkg/apis/autoscaling/v1"
+
+	"github.com/juju/juju/caas"
+)
+
+// autoscaledReplicas returns the number of replicas with which to
+// create or update the deployment of an autoscaled application.
+// Once the deployment exists, the autoscaler owns its replica
+// count, so the existing count is preserved.
+func (k *kubernetesClient) autoscaledReplicas(appName string, numUnits int, policy *caas.AutoscalePolicy) (int32, error) {
+	deployments := k.ExtensionsV1beta1().Deployments(k.namespace)
+	existing, err := deployments.Get(deploymentName(appName))
+	if err == nil && existing.Spec.Replicas != nil {
+		return *existing.Spec.Replicas, nil
+	}
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return 0, errors.Trace(err)
+	}
+	minUnits := policy.MinUnits
+	if minUnits < 1 {
+		minUnits = 1
+	}
+	switch {
+	case numUnits < minUnits:
+		numUnits = minUnits
+	case numUnits > policy.MaxUnits:
+		numUnits = policy.MaxUnits
+	}
+	return int32(numUnits), nil
+}
+
+// configureAutoscaler creates, updates or removes the horizontal pod
+// autoscaler for the application's deployment, according to the policy.
+func (k *kubernetesClient) configureAutoscaler(appName string, policy *caas.AutoscalePolicy) error {
+	if policy == nil {
+		return k.deleteAutoscaler(appName)
+	}
+	logger.Debugf("creating/updating autoscaler for %s", appName)
+
+	spec := &autoscaling.HorizontalPodAutoscaler{
+		ObjectMeta: v1.ObjectMeta{
+			Name:   deploymentName(appName),
+			Labels: map[string]string{labelApplication: appName},
+		},
+		Spec: autoscaling.HorizontalPodAutoscalerSpec{
+			ScaleTargetRef: autoscaling.CrossVersionObjectReference{
+				APIVersion: "extensions/v1beta1",
+				Kind:       "Deployment",
+				Name:       deploymentName(appName),
+			},
+			MaxReplicas: int32(policy.MaxUnits),
+		},
+	}
+	if policy.MinUnits > 0 {
+		minReplicas := int32(policy.MinUnits)
+		spec.Spec.MinReplicas = &minReplicas
+	}
+	if policy.TargetCPUPercent > 0 {
+		targetCPU := int32(policy.TargetCPUPercent)
+		spec.Spec.TargetCPUUtilizationPercentage = &targetCPU
+	}
+
+	autoscalers := k.AutoscalingV1().HorizontalPodAutoscalers(k.namespace)
+	existing, err := autoscalers.Get(spec.Name)
+	if k8serrors.IsNotFound(err) {
+		_, err = autoscalers.Create(spec)
+		return errors.Trace(err)
+	}
+	if err != nil {
+		return errors.Trace(err)
+	}
+	spec.ObjectMeta.ResourceVersion = existing.ObjectMeta.ResourceVersion
+	_, err = autoscalers.Update(spec)
+	return errors.Trace(err)
+}
+
+func (k *kubernetesClient) deleteAutoscaler(appName string) error {
+	orphanDependents := false
+	autoscalers := k.AutoscalingV1().HorizontalPodAutoscalers(k.namespace)
+	err := autoscalers.Delete(deploymentName(appName), &v1.DeleteOptions{OrphanDependents: &orphanDependents})
+	if k8serrors.IsNotFound(err) {
+		return nil
+	}
+	return errors.Trace(err)
+}
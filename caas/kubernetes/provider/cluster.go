@@ -0,0 +1,125 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package provider
+
+import (
+	"regexp"
+	"strconv"
+
+	"github.com/juju/errors"
+	"github.com/juju/version"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/pkg/api/v1"
+	storage "k8s.io/client-go/pkg/apis/storage/v1beta1"
+
+	"github.com/juju/juju/caas"
+	"github.com/juju/juju/environs"
+)
+
+// minClusterVersion is the oldest kubernetes version on
+// which Juju deploys applications. Version 1.6 is the
+// first to support the RBAC API used for operators.
+var minClusterVersion = version.Number{Major: 1, Minor: 6}
+
+const defaultStorageClassAnnotation = "storageclass.beta.kubernetes.io/is-default-class"
+
+var gitVersionRegexp = regexp.MustCompile(`^v?(\d+)\.(\d+)(?:\.(\d+))?`)
+
+// ClusterMetadata returns details of the kubernetes cluster
+// specified by the cloud spec, without requiring a model.
+func ClusterMetadata(cloudSpec environs.CloudSpec) (*caas.ClusterMetadata, error) {
+	k8sConfig, err := newK8sConfig(cloudSpec)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	client, err := kubernetes.NewForConfig(k8sConfig)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return clusterMetadata(client)
+}
+
+// ClusterMetadata is part of the caas.Broker interface.
+func (k *kubernetesClient) ClusterMetadata() (*caas.ClusterMetadata, error) {
+	return clusterMetadata(k.Interface)
+}
+
+func clusterMetadata(client kubernetes.Interface) (*caas.ClusterMetadata, error) {
+	info, err := client.Discovery().ServerVersion()
+	if err != nil {
+		return nil, errors.Annotate(err, "querying cluster version")
+	}
+	vers, err := parseGitVersion(info.GitVersion)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	result := &caas.ClusterMetadata{
+		Version:    vers,
+		MinVersion: minClusterVersion,
+	}
+
+	classes, err := client.StorageV1beta1().StorageClasses().List(v1.ListOptions{})
+	if err != nil {
+		return nil, errors.Annotate(err, "listing storage classes")
+	}
+	for _, sc := range classes.Items {
+		result.StorageClasses = append(result.StorageClasses, storageClass(sc))
+	}
+
+	nodes, err := client.CoreV1().Nodes().List(v1.ListOptions{})
+	if err != nil {
+		return nil, errors.Annotate(err, "listing nodes")
+	}
+	for _, n := range nodes.Items {
+		if n.Spec.Unschedulable {
+			continue
+		}
+		result.Nodes = append(result.Nodes, nodeCapacity(n))
+	}
+	return result, nil
+}
+
+// parseGitVersion parses the version reported by a kubernetes
+// API server, such as "v1.9.3-gke.0", ignoring any suffix.
+func parseGitVersion(gitVersion string) (version.Number, error) {
+	parts := gitVersionRegexp.FindStringSubmatch(gitVersion)
+	if parts == nil {
+		return version.Number{}, errors.NotValidf("cluster version %q", gitVersion)
+	}
+	var numbers [3]int
+	for i, part := range parts[1:] {
+		if part == "" {
+			continue
+		}
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			return version.Number{}, errors.NotValidf("cluster version %q", gitVersion)
+		}
+		numbers[i] = n
+	}
+	return version.Number{Major: numbers[0], Minor: numbers[1], Patch: numbers[2]}, nil
+}
+
+func storageClass(sc storage.StorageClass) caas.StorageClass {
+	return caas.StorageClass{
+		Name:        sc.Name,
+		Provisioner: sc.Provisioner,
+		Default:     sc.Annotations[defaultStorageClassAnnotation] == "true",
+	}
+}
+
+// nodeCapacity returns the resources of the node which
+// are available to pods, in the units used by constraints.
+func nodeCapacity(n v1.Node) caas.NodeCapacity {
+	resources := n.Status.Allocatable
+	if len(resources) == 0 {
+		resources = n.Status.Capacity
+	}
+	// A cpu-power of 100 is one core, or 1000 millicores.
+	return caas.NodeCapacity{
+		Name:     n.Name,
+		CpuPower: uint64(resources.Cpu().MilliValue() / 10),
+		Mem:      quantityMiB(*resources.Memory()),
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/juju/schema"
 	"gopkg.in/juju/environschema.v1"
 	"k8s.io/client-go/pkg/api/v1"
+	"k8s.io/client-go/pkg/apis/extensions/v1beta1"
 )
 
 const (
@@ -15,6 +16,8 @@ const (
 	defaultIngressSSLRedirect    = false
 	defaultIngressSSLPassthrough = false
 	defaultIngressAllowHTTPKey   = false
+	defaultNetworkPolicy         = false
+	defaultUpdateStrategy        = string(v1beta1.RollingUpdateDeploymentStrategyType)
 
 	serviceTypeConfigKey               = "kubernetes-service-type"
 	serviceExternalIPsConfigKey        = "kubernetes-service-external-ips"
@@ -27,6 +30,17 @@ const (
 	ingressSSLRedirectKey    = "kubernetes-ingress-ssl-redirect"
 	ingressSSLPassthroughKey = "kubernetes-ingress-ssl-passthrough"
 	ingressAllowHTTPKey      = "kubernetes-ingress-allow-http"
+	ingressTLSSecretKey      = "kubernetes-ingress-tls-secret"
+	ingressTLSCertificateKey = "kubernetes-ingress-tls-certificate"
+	ingressTLSKeyKey         = "kubernetes-ingress-tls-key"
+	ingressPathsKey          = "kubernetes-ingress-paths"
+
+	networkPolicyKey      = "kubernetes-network-policy"
+	networkPolicyCIDRsKey = "kubernetes-network-policy-cidrs"
+
+	updateStrategyKey       = "kubernetes-update-strategy"
+	updateMaxSurgeKey       = "kubernetes-update-max-surge"
+	updateMaxUnavailableKey = "kubernetes-update-max-unavailable"
 )
 
 var configFields = environschema.Fields{
@@ -80,6 +94,55 @@ var configFields = environschema.Fields{
 		Type:        environschema.Tbool,
 		Group:       environschema.ProviderGroup,
 	},
+	ingressTLSSecretKey: {
+		Description: "the name of an existing TLS secret used to terminate HTTPS traffic at the ingress",
+		Type:        environschema.Tstring,
+		Group:       environschema.ProviderGroup,
+	},
+	ingressTLSCertificateKey: {
+		Description: "the PEM encoded TLS certificate used to terminate HTTPS traffic at the ingress",
+		Type:        environschema.Tstring,
+		Group:       environschema.ProviderGroup,
+	},
+	ingressTLSKeyKey: {
+		Description: "the PEM encoded private key for the ingress TLS certificate",
+		Type:        environschema.Tstring,
+		Group:       environschema.ProviderGroup,
+	},
+	ingressPathsKey: {
+		Description: "comma separated list of additional http paths routed to the application",
+		Type:        environschema.Tstring,
+		Group:       environschema.ProviderGroup,
+	},
+	networkPolicyKey: {
+		Description: "whether exposing the application restricts ingress to its pods with a network policy",
+		Type:        environschema.Tbool,
+		Group:       environschema.ProviderGroup,
+	},
+	networkPolicyCIDRsKey: {
+		Description: "comma separated list of CIDRs from which the ports of an exposed application may be reached, defaulting to all addresses",
+		Type:        environschema.Tstring,
+		Group:       environschema.ProviderGroup,
+	},
+	updateStrategyKey: {
+		Description: "how pods are replaced when the application changes, either RollingUpdate or Recreate",
+		Type:        environschema.Tstring,
+		Group:       environschema.ProviderGroup,
+		Values: []interface{}{
+			string(v1beta1.RollingUpdateDeploymentStrategyType),
+			string(v1beta1.RecreateDeploymentStrategyType),
+		},
+	},
+	updateMaxSurgeKey: {
+		Description: "the number or percentage of pods which may be created above the desired number during a rolling update",
+		Type:        environschema.Tstring,
+		Group:       environschema.ProviderGroup,
+	},
+	updateMaxUnavailableKey: {
+		Description: "the number or percentage of pods which may be unavailable during a rolling update",
+		Type:        environschema.Tstring,
+		Group:       environschema.ProviderGroup,
+	},
 }
 
 var schemaDefaults = schema.Defaults{
@@ -88,6 +151,8 @@ var schemaDefaults = schema.Defaults{
 	ingressSSLRedirectKey:    defaultIngressSSLRedirect,
 	ingressSSLPassthroughKey: defaultIngressSSLPassthrough,
 	ingressAllowHTTPKey:      defaultIngressAllowHTTPKey,
+	networkPolicyKey:         defaultNetworkPolicy,
+	updateStrategyKey:        defaultUpdateStrategy,
 }
 
 // ConfigSchema returns the configuration schema for
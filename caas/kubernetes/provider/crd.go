@@ -0,0 +1,175 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/juju/errors"
+	k8serrors "k8s.io/client-go/pkg/api/errors"
+	"k8s.io/client-go/pkg/api/v1"
+
+	"github.com/juju/juju/caas"
+)
+
+// The client library we use predates custom resource definitions,
+// so they are managed with plain REST requests using the types below,
+// which mirror those of the apiextensions.k8s.io/v1beta1 API.
+const (
+	crdAPIVersion = "apiextensions.k8s.io/v1beta1"
+	crdKind       = "CustomResourceDefinition"
+	crdPath       = "/apis/" + crdAPIVersion + "/customresourcedefinitions"
+)
+
+type customResourceDefinition struct {
+	APIVersion string                       `json:"apiVersion"`
+	Kind       string                       `json:"kind"`
+	Metadata   v1.ObjectMeta                `json:"metadata"`
+	Spec       customResourceDefinitionSpec `json:"spec"`
+}
+
+type customResourceDefinitionSpec struct {
+	Group   string                        `json:"group"`
+	Version string                        `json:"version"`
+	Scope   string                        `json:"scope"`
+	Names   customResourceDefinitionNames `json:"names"`
+}
+
+type customResourceDefinitionNames struct {
+	Plural     string   `json:"plural"`
+	Singular   string   `json:"singular,omitempty"`
+	ShortNames []string `json:"shortNames,omitempty"`
+	Kind       string   `json:"kind"`
+}
+
+type customResourceDefinitionList struct {
+	Items []customResourceDefinition `json:"items"`
+}
+
+// ensureCustomResourceDefinitions installs or upgrades the custom
+// resource definitions requested by the specified application's charm,
+// and deletes any previously installed for the application which are
+// no longer requested. Custom resource definitions are not namespaced,
+// so they are labelled with the model as well as the application, and
+// a definition installed by any other application is not adopted.
+func (k *kubernetesClient) ensureCustomResourceDefinitions(appName string, crds []caas.CustomResourceDefinition) error {
+	labels := k.customResourceLabels(appName)
+	keep := make(map[string]bool)
+	for _, crd := range crds {
+		spec := customResourceDefinitionFor(crd, labels)
+		if err := k.ensureCustomResourceDefinition(spec); err != nil {
+			return errors.Annotatef(err, "installing custom resource definition %q", spec.Metadata.Name)
+		}
+		keep[spec.Metadata.Name] = true
+	}
+	return errors.Trace(k.deleteCustomResourceDefinitions(labels, keep))
+}
+
+func (k *kubernetesClient) customResourceLabels(appName string) map[string]string {
+	return map[string]string{
+		labelModel:       k.modelConfig.UUID(),
+		labelApplication: appName,
+	}
+}
+
+func customResourceDefinitionFor(crd caas.CustomResourceDefinition, labels map[string]string) *customResourceDefinition {
+	scope := crd.Scope
+	if scope == "" {
+		scope = caas.CustomResourceNamespaced
+	}
+	return &customResourceDefinition{
+		APIVersion: crdAPIVersion,
+		Kind:       crdKind,
+		Metadata: v1.ObjectMeta{
+			Name:   crd.Name(),
+			Labels: labels,
+		},
+		Spec: customResourceDefinitionSpec{
+			Group:   crd.Group,
+			Version: crd.Version,
+			Scope:   scope,
+			Names: customResourceDefinitionNames{
+				Plural:     crd.PluralName(),
+				Singular:   crd.Singular,
+				ShortNames: crd.ShortNames,
+				Kind:       crd.Kind,
+			},
+		},
+	}
+}
+
+func (k *kubernetesClient) ensureCustomResourceDefinition(spec *customResourceDefinition) error {
+	existing, err := k.getCustomResourceDefinition(spec.Metadata.Name)
+	if k8serrors.IsNotFound(err) {
+		return errors.Trace(k.sendCustomResourceDefinition("POST", crdPath, spec))
+	}
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for _, key := range []string{labelModel, labelApplication} {
+		if existing.Metadata.Labels[key] != spec.Metadata.Labels[key] {
+			return errors.AlreadyExistsf("custom resource definition not managed by this application")
+		}
+	}
+	spec.Metadata.ResourceVersion = existing.Metadata.ResourceVersion
+	return errors.Trace(k.sendCustomResourceDefinition("PUT", crdPath+"/"+spec.Metadata.Name, spec))
+}
+
+func (k *kubernetesClient) getCustomResourceDefinition(name string) (*customResourceDefinition, error) {
+	data, err := k.CoreV1().RESTClient().Get().AbsPath(crdPath, name).Do().Raw()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var crd customResourceDefinition
+	if err := json.Unmarshal(data, &crd); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &crd, nil
+}
+
+func (k *kubernetesClient) sendCustomResourceDefinition(verb, path string, spec *customResourceDefinition) error {
+	body, err := json.Marshal(spec)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return k.CoreV1().RESTClient().Verb(verb).AbsPath(path).Body(body).Do().Error()
+}
+
+// deleteCustomResourceDefinitions deletes the custom resource definitions
+// having the specified labels, other than those to keep. Deleting a
+// definition also deletes all of the custom resources of its type.
+func (k *kubernetesClient) deleteCustomResourceDefinitions(labels map[string]string, keep map[string]bool) error {
+	var selector []string
+	for key, value := range labels {
+		selector = append(selector, fmt.Sprintf("%v==%v", key, value))
+	}
+	data, err := k.CoreV1().RESTClient().Get().AbsPath(crdPath).
+		Param("labelSelector", strings.Join(selector, ",")).
+		Do().Raw()
+	if k8serrors.IsNotFound(err) {
+		// The cluster does not support custom resource definitions,
+		// so there are none to delete.
+		return nil
+	}
+	if err != nil {
+		return errors.Trace(err)
+	}
+	var existing customResourceDefinitionList
+	if err := json.Unmarshal(data, &existing); err != nil {
+		return errors.Trace(err)
+	}
+	for _, crd := range existing.Items {
+		if keep[crd.Metadata.Name] {
+			continue
+		}
+		logger.Debugf("deleting custom resource definition %s", crd.Metadata.Name)
+		err := k.CoreV1().RESTClient().Delete().AbsPath(crdPath, crd.Metadata.Name).Do().Error()
+		if err != nil && !k8serrors.IsNotFound(err) {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
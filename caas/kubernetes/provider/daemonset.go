@@ -0,0 +1,73 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package provider
+
+import (
+	"github.com/juju/errors"
+	k8serrors "k8s.io/client-go/pkg/api/errors"
+	"k8s.io/client-go/pkg/api/unversioned"
+	"k8s.io/client-go/pkg/api/v1"
+	"k8s.io/client-go/pkg/apis/extensions/v1beta1"
+)
+
+// configureDaemonSet creates or updates the daemon set controller
+// for an application whose pods run one on each node of the cluster.
+// Any placement directive or constraint tags restrict the nodes used.
+func (k *kubernetesClient) configureDaemonSet(appName string, unitSpec *unitSpec) error {
+	logger.Debugf("creating/updating daemon set for %s", appName)
+
+	annotations, err := podAnnotations(unitSpec)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	appLabels := map[string]string{labelApplication: appName}
+	daemonSet := &v1beta1.DaemonSet{
+		ObjectMeta: v1.ObjectMeta{
+			Name:   deploymentName(appName),
+			Labels: appLabels,
+		},
+		Spec: v1beta1.DaemonSetSpec{
+			Selector: &unversioned.LabelSelector{
+				MatchLabels: appLabels,
+			},
+			Template: v1.PodTemplateSpec{
+				ObjectMeta: v1.ObjectMeta{
+					GenerateName: resourceNamePrefix(appName),
+					Labels:       podLabels(unitSpec, appLabels),
+					Annotations:  annotations,
+				},
+				Spec: unitSpec.Pod,
+			},
+		},
+	}
+	return k.ensureDaemonSet(daemonSet)
+}
+
+func (k *kubernetesClient) ensureDaemonSet(spec *v1beta1.DaemonSet) error {
+	daemonSets := k.ExtensionsV1beta1().DaemonSets(k.namespace)
+	existing, err := daemonSets.Get(spec.Name)
+	if k8serrors.IsNotFound(err) {
+		_, err = daemonSets.Create(spec)
+		return errors.Trace(err)
+	}
+	if err != nil {
+		return errors.Trace(err)
+	}
+	// The kubernetes API version we support does not replace
+	// the pods of a daemon set when its template changes; the
+	// new template is used as pods are deleted and recreated.
+	spec.ObjectMeta.ResourceVersion = existing.ObjectMeta.ResourceVersion
+	_, err = daemonSets.Update(spec)
+	return errors.Trace(err)
+}
+
+func (k *kubernetesClient) deleteDaemonSet(appName string) error {
+	orphanDependents := false
+	daemonSets := k.ExtensionsV1beta1().DaemonSets(k.namespace)
+	err := daemonSets.Delete(deploymentName(appName), &v1.DeleteOptions{OrphanDependents: &orphanDependents})
+	if k8serrors.IsNotFound(err) {
+		return nil
+	}
+	return errors.Trace(err)
+}
@@ -0,0 +1,88 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package provider
+
+import (
+	"encoding/json"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	k8serrors "k8s.io/client-go/pkg/api/errors"
+	"k8s.io/client-go/pkg/api/v1"
+	"k8s.io/client-go/pkg/apis/extensions/v1beta1"
+)
+
+type daemonSetSuite struct {
+	baseSuite
+}
+
+var _ = gc.Suite(&daemonSetSuite{})
+
+func (s *daemonSetSuite) getDaemonSet(c *gc.C) *v1beta1.DaemonSet {
+	ds, err := s.clientset.ExtensionsV1beta1().DaemonSets(s.client.namespace).Get("juju-gitlab")
+	c.Assert(err, jc.ErrorIsNil)
+	return ds
+}
+
+func (s *daemonSetSuite) TestConfigureDaemonSet(c *gc.C) {
+	unitSpec := &unitSpec{
+		Pod: v1.PodSpec{
+			Containers:   []v1.Container{{Name: "gitlab", Image: "gitlab/latest"}},
+			NodeSelector: map[string]string{"disktype": "ssd"},
+		},
+		Tolerations: []v1.Toleration{{
+			Key:      "disktype",
+			Operator: v1.TolerationOpEqual,
+			Value:    "ssd",
+		}},
+	}
+	err := s.client.configureDaemonSet("gitlab", unitSpec)
+	c.Assert(err, jc.ErrorIsNil)
+
+	ds := s.getDaemonSet(c)
+	c.Assert(ds.Labels, jc.DeepEquals, map[string]string{labelApplication: "gitlab"})
+	c.Assert(ds.Spec.Selector.MatchLabels, jc.DeepEquals, map[string]string{labelApplication: "gitlab"})
+	c.Assert(ds.Spec.Template.GenerateName, gc.Equals, "juju-application-gitlab-")
+	c.Assert(ds.Spec.Template.Labels, jc.DeepEquals, map[string]string{labelApplication: "gitlab"})
+	c.Assert(ds.Spec.Template.Spec, jc.DeepEquals, unitSpec.Pod)
+
+	// The tolerations are applied as an annotation.
+	var tolerations []v1.Toleration
+	err = json.Unmarshal([]byte(ds.Spec.Template.Annotations[tolerationsAnnotation]), &tolerations)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(tolerations, jc.DeepEquals, unitSpec.Tolerations)
+}
+
+func (s *daemonSetSuite) TestConfigureDaemonSetUpdates(c *gc.C) {
+	err := s.client.configureDaemonSet("gitlab", &unitSpec{
+		Pod: v1.PodSpec{
+			Containers: []v1.Container{{Name: "gitlab", Image: "gitlab/gitlab:1"}},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.client.configureDaemonSet("gitlab", &unitSpec{
+		Pod: v1.PodSpec{
+			Containers: []v1.Container{{Name: "gitlab", Image: "gitlab/gitlab:2"}},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	ds := s.getDaemonSet(c)
+	c.Assert(ds.Spec.Template.Spec.Containers[0].Image, gc.Equals, "gitlab/gitlab:2")
+}
+
+func (s *daemonSetSuite) TestDeleteDaemonSet(c *gc.C) {
+	err := s.client.configureDaemonSet("gitlab", &unitSpec{})
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.client.deleteDaemonSet("gitlab")
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.clientset.ExtensionsV1beta1().DaemonSets(s.client.namespace).Get("juju-gitlab")
+	c.Assert(k8serrors.IsNotFound(err), jc.IsTrue)
+}
+
+func (s *daemonSetSuite) TestDeleteDaemonSetNotFound(c *gc.C) {
+	err := s.client.deleteDaemonSet("gitlab")
+	c.Assert(err, jc.ErrorIsNil)
+}
@@ -0,0 +1,222 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package provider
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/websocket"
+	"github.com/juju/errors"
+	"gopkg.in/juju/names.v2"
+	k8serrors "k8s.io/client-go/pkg/api/errors"
+	"k8s.io/client-go/pkg/api/unversioned"
+	"k8s.io/client-go/pkg/api/v1"
+	"k8s.io/client-go/rest"
+
+	"github.com/juju/juju/caas"
+)
+
+// execProtocol is the streaming protocol used for exec requests.
+// Each message is prefixed by a byte identifying its stream, and
+// the result of the command is sent as a status on the error stream.
+const execProtocol = "v4.channel.k8s.io"
+
+const (
+	execStdin byte = iota
+	execStdout
+	execStderr
+	execError
+)
+
+// Exec is part of the caas.Broker interface.
+func (k *kubernetesClient) Exec(params caas.ExecParams, abort <-chan struct{}) error {
+	if err := params.Validate(); err != nil {
+		return errors.Trace(err)
+	}
+	podName := unitPodName(params.UnitName)
+	if params.Operator {
+		appName, err := names.UnitApplication(params.UnitName)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		podName = operatorPodName(appName)
+	}
+	pod, err := k.CoreV1().Pods(k.namespace).Get(podName)
+	if k8serrors.IsNotFound(err) {
+		return errors.NotFoundf("pod for unit %q", params.UnitName)
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	if pod.Status.Phase != v1.PodRunning {
+		return errors.Errorf("pod %q is %s, not running", podName, strings.ToLower(string(pod.Status.Phase)))
+	}
+	container := params.ContainerName
+	if container == "" {
+		if len(pod.Spec.Containers) == 0 {
+			return errors.Errorf("pod %q has no containers", podName)
+		}
+		container = pod.Spec.Containers[0].Name
+	}
+
+	conn, err := k.dialExec(podName, container, params)
+	if err != nil {
+		return errors.Annotatef(err, "running command in pod %q", podName)
+	}
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-abort:
+		case <-done:
+		}
+		conn.Close()
+	}()
+	return streamExec(conn, params)
+}
+
+// dialExec opens a connection to the API server streaming
+// the input and output of the command run in the container.
+func (k *kubernetesClient) dialExec(podName, container string, params caas.ExecParams) (*websocket.Conn, error) {
+	u, err := execURL(k.restConfig.Host, k.namespace, podName)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	query := url.Values{}
+	query.Set("container", container)
+	for _, arg := range params.Commands {
+		query.Add("command", arg)
+	}
+	query.Set("stdin", strconv.FormatBool(params.Stdin != nil))
+	query.Set("stdout", "true")
+	query.Set("stderr", strconv.FormatBool(!params.TTY))
+	query.Set("tty", strconv.FormatBool(params.TTY))
+	u.RawQuery = query.Encode()
+
+	tlsConfig, err := rest.TLSConfigFor(k.restConfig)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	header := make(http.Header)
+	switch {
+	case k.restConfig.BearerToken != "":
+		header.Set("Authorization", "Bearer "+k.restConfig.BearerToken)
+	case k.restConfig.Username != "":
+		auth := k.restConfig.Username + ":" + k.restConfig.Password
+		header.Set("Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte(auth)))
+	}
+	dialer := websocket.Dialer{
+		TLSClientConfig: tlsConfig,
+		Subprotocols:    []string{execProtocol},
+	}
+	conn, _, err := dialer.Dial(u.String(), header)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return conn, nil
+}
+
+// execURL returns the websocket URL of the exec
+// subresource of the pod, on the specified host.
+func execURL(host, namespace, podName string) (*url.URL, error) {
+	if !strings.Contains(host, "://") {
+		host = "https://" + host
+	}
+	u, err := url.Parse(host)
+	if err != nil {
+		return nil, errors.Annotate(err, "parsing cluster endpoint")
+	}
+	switch u.Scheme {
+	case "https":
+		u.Scheme = "wss"
+	case "http":
+		u.Scheme = "ws"
+	default:
+		return nil, errors.NotValidf("cluster endpoint scheme %q", u.Scheme)
+	}
+	u.Path = path.Join(u.Path, "api", "v1", "namespaces", namespace, "pods", podName, "exec")
+	return u, nil
+}
+
+// streamExec copies the command's input and output over the
+// connection until the command exits, returning its result.
+func streamExec(conn *websocket.Conn, params caas.ExecParams) error {
+	stderr := params.Stderr
+	if stderr == nil {
+		stderr = params.Stdout
+	}
+	if params.Stdin != nil {
+		// Only this goroutine writes to the connection.
+		go func() {
+			buf := make([]byte, 32*1024)
+			for {
+				n, err := params.Stdin.Read(buf)
+				if n > 0 {
+					msg := append([]byte{execStdin}, buf[:n]...)
+					if err := conn.WriteMessage(websocket.BinaryMessage, msg); err != nil {
+						return
+					}
+				}
+				if err != nil {
+					return
+				}
+			}
+		}()
+	}
+
+	var result error
+	for {
+		_, data, err := conn.ReadMessage()
+		if websocket.IsCloseError(err, websocket.CloseNormalClosure) {
+			return result
+		} else if err != nil {
+			if result != nil {
+				return result
+			}
+			return errors.Annotate(err, "reading command output")
+		}
+		if len(data) == 0 {
+			continue
+		}
+		switch data[0] {
+		case execStdout:
+			_, err = params.Stdout.Write(data[1:])
+		case execStderr:
+			_, err = stderr.Write(data[1:])
+		case execError:
+			result = execResult(data[1:])
+		}
+		if err != nil {
+			return errors.Trace(err)
+		}
+	}
+}
+
+// execResult returns the error, if any, described
+// by the status sent on the error stream.
+func execResult(data []byte) error {
+	var status unversioned.Status
+	if err := json.Unmarshal(data, &status); err != nil {
+		return errors.Errorf("command failed: %s", data)
+	}
+	if status.Status == unversioned.StatusSuccess {
+		return nil
+	}
+	if status.Reason == "NonZeroExitCode" && status.Details != nil {
+		for _, cause := range status.Details.Causes {
+			if cause.Type != "ExitCode" {
+				continue
+			}
+			if code, err := strconv.Atoi(cause.Message); err == nil {
+				return &caas.ExitError{Code: code}
+			}
+		}
+	}
+	return errors.New(status.Message)
+}
@@ -5,6 +5,7 @@ package provider
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"strconv"
 	"strings"
@@ -15,9 +16,11 @@ import (
 	"github.com/juju/loggo"
 	"github.com/juju/retry"
 	"github.com/juju/utils/clock"
+	"github.com/juju/version"
 	"gopkg.in/juju/names.v2"
 	"k8s.io/client-go/kubernetes"
 	k8serrors "k8s.io/client-go/pkg/api/errors"
+	"k8s.io/client-go/pkg/api/resource"
 	"k8s.io/client-go/pkg/api/unversioned"
 	"k8s.io/client-go/pkg/api/v1"
 	"k8s.io/client-go/pkg/apis/extensions/v1beta1"
@@ -27,39 +30,71 @@ import (
 
 	"github.com/juju/juju/agent"
 	"github.com/juju/juju/caas"
+	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/core/application"
 	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/status"
-	"github.com/juju/juju/watcher"
+	"github.com/juju/juju/storage"
+	jujuversion "github.com/juju/juju/version"
 )
 
 var logger = loggo.GetLogger("juju.kubernetes.provider")
 
-const (
-	// TODO(caas) should be using a juju specific namespace
-	namespace = "default"
+// ProviderType is the cloud type of kubernetes clouds.
+const ProviderType = "kubernetes"
 
+const (
+	labelModel       = "juju-model"
 	labelApplication = "juju-application"
 	labelUnit        = "juju-unit"
-)
+	labelOperator    = "juju-operator"
 
-// TODO(caas) - add unit tests
+	storageClassAnnotation   = "volume.beta.kubernetes.io/storage-class"
+	initContainersAnnotation = "pod.beta.kubernetes.io/init-containers"
+
+	podReasonUnschedulable = "Unschedulable"
+
+	operatorContainerName = "juju-operator"
+	operatorImageRepo     = "jujusolutions/caas-jujud-operator"
+)
 
 type kubernetesClient struct {
-	*kubernetes.Clientset
+	kubernetes.Interface
+
+	// namespace is the kubernetes namespace in
+	// which all of the model's resources live.
+	namespace string
+
+	// modelConfig is the config of the model
+	// for which the client manages resources.
+	modelConfig *config.Config
+
+	// restConfig is the config used to connect to
+	// the cluster, for requests which are not made
+	// with the clientset, such as exec.
+	restConfig *rest.Config
 }
 
 // NewK8sProvider returns a kubernetes client for the specified cloud.
-func NewK8sProvider(cloudSpec environs.CloudSpec) (caas.Broker, error) {
-	config, err := newK8sConfig(cloudSpec)
+func NewK8sProvider(args environs.OpenParams) (caas.Broker, error) {
+	if args.Config == nil {
+		return nil, errors.NotValidf("nil model config")
+	}
+	k8sConfig, err := newK8sConfig(args.Cloud)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
-	client, err := kubernetes.NewForConfig(config)
+	client, err := kubernetes.NewForConfig(k8sConfig)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
-	return &kubernetesClient{client}, nil
+	return &kubernetesClient{
+		Interface:   client,
+		namespace:   args.Config.Name(),
+		modelConfig: args.Config,
+		restConfig:  k8sConfig,
+	}, nil
 }
 
 func newK8sConfig(cloudSpec environs.CloudSpec) (*rest.Config, error) {
@@ -94,13 +129,47 @@ func (k *kubernetesClient) EnsureOperator(appName, agentPath string, config *caa
 	if err := k.ensureConfigMap(operatorConfigMap(appName, config)); err != nil {
 		return errors.Annotate(err, "creating or updating ConfigMap")
 	}
-	pod := operatorPod(appName, agentPath)
+	if err := k.ensureOperatorAccount(appName); err != nil {
+		return errors.Annotate(err, "creating or updating operator service account")
+	}
+	pod := operatorPod(appName, agentPath, operatorImagePath(jujuversion.Current))
 	if err := k.deletePod(pod.Name); err != nil {
 		return errors.Trace(err)
 	}
 	return k.createPod(pod)
 }
 
+// UpgradeOperator updates the operator pod for the specified application
+// to run the specified Juju agent version.
+func (k *kubernetesClient) UpgradeOperator(appName string, vers version.Number) error {
+	logger.Debugf("upgrading %s operator to %v", appName, vers)
+
+	pods := k.CoreV1().Pods(k.namespace)
+	pod, err := pods.Get(operatorPodName(appName))
+	if k8serrors.IsNotFound(err) {
+		return errors.NotFoundf("operator pod for %q", appName)
+	}
+	if err != nil {
+		return errors.Trace(err)
+	}
+	image := operatorImagePath(vers)
+	changed := false
+	for i, c := range pod.Spec.Containers {
+		if c.Name != operatorContainerName || c.Image == image {
+			continue
+		}
+		pod.Spec.Containers[i].Image = image
+		changed = true
+	}
+	if !changed {
+		return nil
+	}
+	// The container image is one of the few mutable parts of a
+	// pod spec; updating it causes the container to be restarted.
+	_, err = pods.Update(pod)
+	return errors.Trace(err)
+}
+
 // DeleteService deletes the specified service.
 func (k *kubernetesClient) DeleteService(appName string) (err error) {
 	logger.Debugf("deleting application %s", appName)
@@ -108,20 +177,42 @@ func (k *kubernetesClient) DeleteService(appName string) (err error) {
 	if err := k.deleteService(appName); err != nil {
 		return errors.Trace(err)
 	}
-	return errors.Trace(k.deleteDeployment(appName))
+	if err := k.deleteDeployment(appName); err != nil {
+		return errors.Trace(err)
+	}
+	if err := k.deleteStatefulSet(appName); err != nil {
+		return errors.Trace(err)
+	}
+	if err := k.deleteDaemonSet(appName); err != nil {
+		return errors.Trace(err)
+	}
+	if err := k.deleteAutoscaler(appName); err != nil {
+		return errors.Trace(err)
+	}
+	if err := k.deleteNetworkPolicy(appName); err != nil {
+		return errors.Trace(err)
+	}
+	if err := k.deleteCustomResourceDefinitions(k.customResourceLabels(appName), nil); err != nil {
+		return errors.Trace(err)
+	}
+	appLabels := map[string]string{labelApplication: appName}
+	if err := k.deleteSecrets(appLabels, imagePullSecretName(deploymentName(appName), ""), nil); err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(k.deleteConfigMaps(appLabels, fileSetConfigMapName(deploymentName(appName), ""), nil))
 }
 
 // EnsureService creates or updates a service for pods with the given spec.
 func (k *kubernetesClient) EnsureService(
-	appName string, spec *caas.ContainerSpec, numUnits int, config application.ConfigAttributes,
+	appName string, params *caas.ServiceParams, numUnits int, config application.ConfigAttributes,
 ) (err error) {
 	logger.Debugf("creating/updating application %s", appName)
 
 	if numUnits <= 0 {
 		return errors.Errorf("number of units must be > 0")
 	}
-	if spec == nil {
-		return errors.Errorf("missing container spec")
+	if params == nil || params.PodSpec == nil {
+		return errors.Errorf("missing pod spec")
 	}
 
 	var cleanups []func()
@@ -134,15 +225,80 @@ func (k *kubernetesClient) EnsureService(
 		}
 	}()
 
-	unitSpec, err := makeUnitSpec(spec)
+	stateful := params.DeploymentMode == caas.DeploymentStateful
+	onePerNode := params.DeploymentMode == caas.DeploymentOnePerNode
+	if hasVolumes(params.PodSpec) {
+		// A volume claim can only be mounted read-write on one
+		// node, so each pod needs a claim of its own. Only
+		// stateful sets create claims per pod.
+		if onePerNode {
+			return errors.NotSupportedf("storage for %s applications", params.DeploymentMode)
+		}
+		if params.Autoscale != nil {
+			return errors.NotSupportedf("autoscaling applications with storage")
+		}
+		stateful = true
+	}
+	if (stateful || onePerNode) && params.Autoscale != nil {
+		// The kubernetes API version we support
+		// can only autoscale deployments.
+		return errors.NotSupportedf("autoscaling %s applications", params.DeploymentMode)
+	}
+	if err := k.ensureOperatorRules(appName, params.PodSpec.OperatorRules, params.Trusted); err != nil {
+		return errors.Annotate(err, "updating operator rules")
+	}
+	if err := k.ensureCustomResourceDefinitions(appName, params.PodSpec.CustomResourceDefinitions); err != nil {
+		return errors.Trace(err)
+	}
+	appLabels := map[string]string{labelApplication: appName}
+	unitSpec, err := k.prepareUnitSpec(params, deploymentName(appName), appLabels, stateful)
 	if err != nil {
-		return errors.Annotatef(err, "parsing unit spec for %s", appName)
+		return errors.Annotatef(err, "preparing unit spec for %s", appName)
 	}
 	numPods := int32(numUnits)
-	if err := k.configureDeployment(appName, unitSpec, &numPods); err != nil {
-		return errors.Annotate(err, "creating or updating deployment controller")
+	switch {
+	case stateful:
+		if err := k.configureStatefulSet(appName, unitSpec, &numPods); err != nil {
+			return errors.Annotate(err, "creating or updating stateful set controller")
+		}
+		cleanups = append(cleanups, func() { k.deleteStatefulSet(appName) })
+	case onePerNode:
+		// The number of pods is determined by the
+		// number of nodes, so numUnits is ignored.
+		if err := k.configureDaemonSet(appName, unitSpec); err != nil {
+			return errors.Annotate(err, "creating or updating daemon set controller")
+		}
+		cleanups = append(cleanups, func() { k.deleteDaemonSet(appName) })
+	default:
+		if params.Autoscale != nil {
+			if numPods, err = k.autoscaledReplicas(appName, numUnits, params.Autoscale); err != nil {
+				return errors.Trace(err)
+			}
+		}
+		if err := k.configureDeployment(appName, unitSpec, &numPods, config); err != nil {
+			return errors.Annotate(err, "creating or updating deployment controller")
+		}
+		cleanups = append(cleanups, func() { k.deleteDeployment(appName) })
+	}
+	// The application may previously have used another deployment mode.
+	if !stateful {
+		if err := k.deleteStatefulSet(appName); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	if !onePerNode {
+		if err := k.deleteDaemonSet(appName); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	if stateful || onePerNode {
+		if err := k.deleteDeployment(appName); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	if err := k.configureAutoscaler(appName, params.Autoscale); err != nil {
+		return errors.Annotate(err, "configuring autoscaler")
 	}
-	cleanups = append(cleanups, func() { k.deleteDeployment(appName) })
 
 	var ports []v1.ContainerPort
 	for _, c := range unitSpec.Pod.Containers {
@@ -159,9 +315,19 @@ func (k *kubernetesClient) EnsureService(
 	return nil
 }
 
-func (k *kubernetesClient) configureDeployment(appName string, unitSpec *unitSpec, replicas *int32) error {
+func (k *kubernetesClient) configureDeployment(
+	appName string, unitSpec *unitSpec, replicas *int32, config application.ConfigAttributes,
+) error {
 	logger.Debugf("creating/updating deployment for %s", appName)
 
+	annotations, err := podAnnotations(unitSpec)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	strategy, err := deploymentStrategy(config)
+	if err != nil {
+		return errors.Trace(err)
+	}
 	namePrefix := resourceNamePrefix(appName)
 	deployment := &v1beta1.Deployment{
 		ObjectMeta: v1.ObjectMeta{
@@ -172,10 +338,12 @@ func (k *kubernetesClient) configureDeployment(appName string, unitSpec *unitSpe
 			Selector: &unversioned.LabelSelector{
 				MatchLabels: map[string]string{labelApplication: appName},
 			},
+			Strategy: strategy,
 			Template: v1.PodTemplateSpec{
 				ObjectMeta: v1.ObjectMeta{
 					GenerateName: namePrefix,
-					Labels:       map[string]string{labelApplication: appName},
+					Labels:       podLabels(unitSpec, map[string]string{labelApplication: appName}),
+					Annotations:  annotations,
 				},
 				Spec: unitSpec.Pod,
 			},
@@ -184,8 +352,65 @@ func (k *kubernetesClient) configureDeployment(appName string, unitSpec *unitSpe
 	return k.ensureDeployment(deployment)
 }
 
+// deploymentStrategy returns the strategy by which the pods of
+// a deployment are replaced, as specified in the application config.
+func deploymentStrategy(config application.ConfigAttributes) (v1beta1.DeploymentStrategy, error) {
+	strategy := v1beta1.DeploymentStrategy{
+		Type: v1beta1.DeploymentStrategyType(config.GetString(updateStrategyKey, defaultUpdateStrategy)),
+	}
+	maxSurge := config.GetString(updateMaxSurgeKey, "")
+	maxUnavailable := config.GetString(updateMaxUnavailableKey, "")
+	switch strategy.Type {
+	case v1beta1.RecreateDeploymentStrategyType:
+		if maxSurge != "" || maxUnavailable != "" {
+			return strategy, errors.Errorf(
+				"%s and %s cannot be specified with the %s update strategy",
+				updateMaxSurgeKey, updateMaxUnavailableKey, strategy.Type,
+			)
+		}
+		return strategy, nil
+	case v1beta1.RollingUpdateDeploymentStrategyType:
+	default:
+		return strategy, errors.NotValidf("update strategy %q", strategy.Type)
+	}
+	if maxSurge == "" && maxUnavailable == "" {
+		// Use the kubernetes defaults.
+		return strategy, nil
+	}
+	strategy.RollingUpdate = &v1beta1.RollingUpdateDeployment{}
+	if maxSurge != "" {
+		value, err := intOrPercent(maxSurge)
+		if err != nil {
+			return strategy, errors.Annotatef(err, "invalid %s", updateMaxSurgeKey)
+		}
+		strategy.RollingUpdate.MaxSurge = &value
+	}
+	if maxUnavailable != "" {
+		value, err := intOrPercent(maxUnavailable)
+		if err != nil {
+			return strategy, errors.Annotatef(err, "invalid %s", updateMaxUnavailableKey)
+		}
+		strategy.RollingUpdate.MaxUnavailable = &value
+	}
+	return strategy, nil
+}
+
+// intOrPercent parses a non-negative number of pods,
+// or a percentage of pods such as "25%".
+func intOrPercent(value string) (intstr.IntOrString, error) {
+	number := strings.TrimSuffix(value, "%")
+	n, err := strconv.Atoi(number)
+	if err != nil || n < 0 {
+		return intstr.IntOrString{}, errors.NotValidf("number or percentage %q", value)
+	}
+	if number != value {
+		return intstr.FromString(value), nil
+	}
+	return intstr.FromInt(n), nil
+}
+
 func (k *kubernetesClient) ensureDeployment(spec *v1beta1.Deployment) error {
-	deployments := k.ExtensionsV1beta1().Deployments(namespace)
+	deployments := k.ExtensionsV1beta1().Deployments(k.namespace)
 	_, err := deployments.Update(spec)
 	if k8serrors.IsNotFound(err) {
 		_, err = deployments.Create(spec)
@@ -195,7 +420,7 @@ func (k *kubernetesClient) ensureDeployment(spec *v1beta1.Deployment) error {
 
 func (k *kubernetesClient) deleteDeployment(appName string) error {
 	orphanDependents := false
-	deployments := k.ExtensionsV1beta1().Deployments(namespace)
+	deployments := k.ExtensionsV1beta1().Deployments(k.namespace)
 	err := deployments.Delete(deploymentName(appName), &v1.DeleteOptions{OrphanDependents: &orphanDependents})
 	if k8serrors.IsNotFound(err) {
 		return nil
@@ -223,11 +448,25 @@ func (k *kubernetesClient) configureService(appName string, containerPorts []v1.
 		})
 	}
 
+	labels, err := caas.KeyValues(config, caas.JujuServiceLabelsKey)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if labels == nil {
+		labels = make(map[string]string)
+	}
+	labels[labelApplication] = appName
+	annotations, err := caas.KeyValues(config, caas.JujuServiceAnnotationsKey)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
 	serviceType := v1.ServiceType(config.GetString(serviceTypeConfigKey, defaultServiceType))
 	service := &v1.Service{
 		ObjectMeta: v1.ObjectMeta{
-			Name:   deploymentName(appName),
-			Labels: map[string]string{labelApplication: appName}},
+			Name:        deploymentName(appName),
+			Labels:      labels,
+			Annotations: annotations},
 		Spec: v1.ServiceSpec{
 			Selector:                 map[string]string{labelApplication: appName},
 			Type:                     serviceType,
@@ -242,7 +481,7 @@ func (k *kubernetesClient) configureService(appName string, containerPorts []v1.
 }
 
 func (k *kubernetesClient) ensureService(spec *v1.Service) error {
-	services := k.CoreV1().Services(namespace)
+	services := k.CoreV1().Services(k.namespace)
 	// Set any immutable fields if the service already exists.
 	existing, err := services.Get(spec.Name)
 	if err == nil {
@@ -258,7 +497,7 @@ func (k *kubernetesClient) ensureService(spec *v1.Service) error {
 
 func (k *kubernetesClient) deleteService(appName string) error {
 	orphanDependents := false
-	services := k.CoreV1().Services(namespace)
+	services := k.CoreV1().Services(k.namespace)
 	err := services.Delete(deploymentName(appName), &v1.DeleteOptions{OrphanDependents: &orphanDependents})
 	if k8serrors.IsNotFound(err) {
 		return nil
@@ -266,7 +505,8 @@ func (k *kubernetesClient) deleteService(appName string) error {
 	return errors.Trace(err)
 }
 
-// ExposeService sets up external access to the specified application.
+// ExposeService sets up external access to the specified application,
+// optionally restricting the traffic admitted to its pods.
 func (k *kubernetesClient) ExposeService(appName string, config application.ConfigAttributes) error {
 	logger.Debugf("creating/updating ingress resource for %s", appName)
 
@@ -278,21 +518,39 @@ func (k *kubernetesClient) ExposeService(appName string, config application.Conf
 	ingressSSLRedirect := config.GetBool(ingressSSLRedirectKey, defaultIngressSSLRedirect)
 	ingressSSLPassthrough := config.GetBool(ingressSSLPassthroughKey, defaultIngressSSLPassthrough)
 	ingressAllowHTTP := config.GetBool(ingressAllowHTTPKey, defaultIngressAllowHTTPKey)
-	httpPath := config.GetString(caas.JujuApplicationPath, caas.JujuDefaultApplicationPath)
-	if httpPath == "$appname" {
-		httpPath = appName
-	}
-	if !strings.HasPrefix(httpPath, "/") {
-		httpPath = "/" + httpPath
+	httpPaths := []string{config.GetString(caas.JujuApplicationPath, caas.JujuDefaultApplicationPath)}
+	for _, p := range strings.Split(config.GetString(ingressPathsKey, ""), ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			httpPaths = append(httpPaths, p)
+		}
 	}
 
-	svc, err := k.CoreV1().Services(namespace).Get(deploymentName(appName))
+	svc, err := k.CoreV1().Services(k.namespace).Get(deploymentName(appName))
 	if err != nil {
 		return errors.Trace(err)
 	}
 	if len(svc.Spec.Ports) == 0 {
 		return errors.Errorf("cannot create ingress rule for service %q without a port", svc.Name)
 	}
+	var ingressPaths []v1beta1.HTTPIngressPath
+	for _, httpPath := range httpPaths {
+		if httpPath == "$appname" {
+			httpPath = appName
+		}
+		if !strings.HasPrefix(httpPath, "/") {
+			httpPath = "/" + httpPath
+		}
+		ingressPaths = append(ingressPaths, v1beta1.HTTPIngressPath{
+			Path: httpPath,
+			Backend: v1beta1.IngressBackend{
+				ServiceName: svc.Name, ServicePort: svc.Spec.Ports[0].TargetPort},
+		})
+	}
+
+	tlsSecretName, err := k.configureIngressTLS(appName, config)
+	if err != nil {
+		return errors.Annotatef(err, "configuring TLS for %s", appName)
+	}
 	spec := &v1beta1.Ingress{
 		ObjectMeta: v1.ObjectMeta{
 			Name:   deploymentName(appName),
@@ -310,25 +568,95 @@ func (k *kubernetesClient) ExposeService(appName string, config application.Conf
 				Host: host,
 				IngressRuleValue: v1beta1.IngressRuleValue{
 					HTTP: &v1beta1.HTTPIngressRuleValue{
-						Paths: []v1beta1.HTTPIngressPath{{
-							Path: httpPath,
-							Backend: v1beta1.IngressBackend{
-								ServiceName: svc.Name, ServicePort: svc.Spec.Ports[0].TargetPort},
-						}}},
-				}}},
+						Paths: ingressPaths,
+					}},
+			}},
+		},
+	}
+	if tlsSecretName != "" {
+		spec.Spec.TLS = []v1beta1.IngressTLS{{
+			Hosts:      []string{host},
+			SecretName: tlsSecretName,
+		}}
+	}
+	if err := k.ensureIngress(spec); err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Annotatef(k.configureNetworkPolicy(appName, svc, config), "configuring network policy for %s", appName)
+}
+
+// configureIngressTLS returns the name of the secret holding the TLS
+// certificate for the application's ingress, or "" if TLS is not
+// configured. If the certificate and key are supplied in the application
+// config, a Juju managed secret is created to hold them; otherwise an
+// existing secret may be named.
+func (k *kubernetesClient) configureIngressTLS(appName string, config application.ConfigAttributes) (string, error) {
+	cert := config.GetString(ingressTLSCertificateKey, "")
+	key := config.GetString(ingressTLSKeyKey, "")
+	secretName := config.GetString(ingressTLSSecretKey, "")
+	if cert == "" && key == "" {
+		// Any previously managed secret is no longer needed.
+		if err := k.deleteSecret(ingressTLSSecretName(appName)); err != nil {
+			return "", errors.Trace(err)
+		}
+		return secretName, nil
+	}
+	if cert == "" || key == "" {
+		return "", errors.Errorf("both %s and %s must be specified", ingressTLSCertificateKey, ingressTLSKeyKey)
+	}
+	if secretName != "" {
+		return "", errors.Errorf("cannot specify both %s and a TLS certificate", ingressTLSSecretKey)
+	}
+	secret := &v1.Secret{
+		ObjectMeta: v1.ObjectMeta{
+			Name:   ingressTLSSecretName(appName),
+			Labels: map[string]string{labelApplication: appName},
+		},
+		Type: v1.SecretTypeTLS,
+		Data: map[string][]byte{
+			v1.TLSCertKey:       []byte(cert),
+			v1.TLSPrivateKeyKey: []byte(key),
 		},
 	}
-	return k.ensureIngress(spec)
+	if err := k.ensureSecret(secret); err != nil {
+		return "", errors.Trace(err)
+	}
+	return secret.Name, nil
+}
+
+func (k *kubernetesClient) ensureSecret(spec *v1.Secret) error {
+	secrets := k.CoreV1().Secrets(k.namespace)
+	_, err := secrets.Update(spec)
+	if k8serrors.IsNotFound(err) {
+		_, err = secrets.Create(spec)
+	}
+	return errors.Trace(err)
+}
+
+func (k *kubernetesClient) deleteSecret(secretName string) error {
+	orphanDependents := false
+	secrets := k.CoreV1().Secrets(k.namespace)
+	err := secrets.Delete(secretName, &v1.DeleteOptions{OrphanDependents: &orphanDependents})
+	if k8serrors.IsNotFound(err) {
+		return nil
+	}
+	return errors.Trace(err)
 }
 
 // UnexposeService removes external access to the specified service.
 func (k *kubernetesClient) UnexposeService(appName string) error {
 	logger.Debugf("deleting ingress resource for %s", appName)
-	return k.deleteIngress(appName)
+	if err := k.deleteIngress(appName); err != nil {
+		return errors.Trace(err)
+	}
+	if err := k.restrictNetworkPolicy(appName); err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(k.deleteSecret(ingressTLSSecretName(appName)))
 }
 
 func (k *kubernetesClient) ensureIngress(spec *v1beta1.Ingress) error {
-	ingress := k.ExtensionsV1beta1().Ingresses(namespace)
+	ingress := k.ExtensionsV1beta1().Ingresses(k.namespace)
 	_, err := ingress.Update(spec)
 	if k8serrors.IsNotFound(err) {
 		_, err = ingress.Create(spec)
@@ -338,7 +666,7 @@ func (k *kubernetesClient) ensureIngress(spec *v1beta1.Ingress) error {
 
 func (k *kubernetesClient) deleteIngress(appName string) error {
 	orphanDependents := false
-	ingress := k.ExtensionsV1beta1().Ingresses(namespace)
+	ingress := k.ExtensionsV1beta1().Ingresses(k.namespace)
 	err := ingress.Delete(deploymentName(appName), &v1.DeleteOptions{OrphanDependents: &orphanDependents})
 	if k8serrors.IsNotFound(err) {
 		return nil
@@ -350,10 +678,10 @@ func applicationSelector(appName string) string {
 	return fmt.Sprintf("%v==%v", labelApplication, appName)
 }
 
-// WatchUnits returns a watcher which notifies when there
-// are changes to units of the specified application.
-func (k *kubernetesClient) WatchUnits(appName string) (watcher.NotifyWatcher, error) {
-	pods := k.CoreV1().Pods(namespace)
+// WatchUnits returns a watcher which reports the units of
+// the specified application which are added, updated or removed.
+func (k *kubernetesClient) WatchUnits(appName string) (caas.UnitsWatcher, error) {
+	pods := k.CoreV1().Pods(k.namespace)
 	w, err := pods.Watch(v1.ListOptions{
 		LabelSelector: applicationSelector(appName),
 		Watch:         true,
@@ -361,12 +689,12 @@ func (k *kubernetesClient) WatchUnits(appName string) (watcher.NotifyWatcher, er
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
-	return newKubernetesWatcher(w, appName)
+	return newKubernetesWatcher(w, appName, k.unitFromPod)
 }
 
 // Units returns all units of the specified application.
 func (k *kubernetesClient) Units(appName string) ([]caas.Unit, error) {
-	pods := k.CoreV1().Pods(namespace)
+	pods := k.CoreV1().Pods(k.namespace)
 	podsList, err := pods.List(v1.ListOptions{
 		LabelSelector: applicationSelector(appName),
 	})
@@ -374,67 +702,519 @@ func (k *kubernetesClient) Units(appName string) ([]caas.Unit, error) {
 		return nil, errors.Trace(err)
 	}
 	var result []caas.Unit
-	now := time.Now()
 	for _, p := range podsList.Items {
-		var ports []string
-		for _, c := range p.Spec.Containers {
-			for _, p := range c.Ports {
-				ports = append(ports, fmt.Sprintf("%v/%v", p.ContainerPort, p.Protocol))
-			}
-		}
 		dying := p.DeletionTimestamp != nil
 		if dying {
 			continue
 		}
-		result = append(result, caas.Unit{
-			Id:      string(p.UID),
-			Address: p.Status.PodIP,
-			Ports:   ports,
+		unit, err := k.unitFromPod(&p)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		result = append(result, unit)
+	}
+	return result, nil
+}
+
+// unitFromPod returns the unit corresponding to the pod.
+func (k *kubernetesClient) unitFromPod(p *v1.Pod) (caas.Unit, error) {
+	var ports []string
+	for _, c := range p.Spec.Containers {
+		for _, p := range c.Ports {
+			ports = append(ports, fmt.Sprintf("%v/%v", p.ContainerPort, p.Protocol))
+		}
+	}
+	now := time.Now()
+	unitStatus, message := k.jujuStatus(p.Status)
+	unit := caas.Unit{
+		Id:      string(p.UID),
+		Address: p.Status.PodIP,
+		Ports:   ports,
+		Status: status.StatusInfo{
+			Status:  unitStatus,
+			Message: message,
+			Since:   &now,
+		},
+	}
+	// Pods which are being removed may no
+	// longer have their claims, so skip them.
+	if p.DeletionTimestamp != nil {
+		return unit, nil
+	}
+	filesystems, err := k.filesystemInfo(p)
+	if err != nil {
+		return caas.Unit{}, errors.Annotatef(err, "getting filesystems of pod %q", p.Name)
+	}
+	unit.FilesystemInfo = filesystems
+	return unit, nil
+}
+
+// filesystemInfo returns information about the persistent
+// volume claims mounted into the pod's containers.
+func (k *kubernetesClient) filesystemInfo(p *v1.Pod) ([]caas.FilesystemInfo, error) {
+	mounts := make(map[string]v1.VolumeMount)
+	for _, c := range p.Spec.Containers {
+		for _, m := range c.VolumeMounts {
+			if _, ok := mounts[m.Name]; !ok {
+				mounts[m.Name] = m
+			}
+		}
+	}
+	var result []caas.FilesystemInfo
+	for _, vol := range p.Spec.Volumes {
+		mount, ok := mounts[vol.Name]
+		if vol.PersistentVolumeClaim == nil || !ok {
+			continue
+		}
+		claims := k.CoreV1().PersistentVolumeClaims(k.namespace)
+		pvc, err := claims.Get(vol.PersistentVolumeClaim.ClaimName)
+		if k8serrors.IsNotFound(err) {
+			logger.Debugf("persistent volume claim %q for pod %q not found", vol.PersistentVolumeClaim.ClaimName, p.Name)
+			continue
+		}
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		fsStatus, message := k.claimStatus(pvc.Status)
+		info := caas.FilesystemInfo{
+			StorageName:  vol.Name,
+			FilesystemId: string(pvc.UID),
+			Size:         quantityMiB(pvc.Status.Capacity[v1.ResourceStorage]),
+			MountPoint:   mount.MountPath,
+			ReadOnly:     mount.ReadOnly,
 			Status: status.StatusInfo{
-				Status:  k.jujuStatus(p.Status.Phase),
-				Message: p.Status.Message,
-				Since:   &now,
+				Status:  fsStatus,
+				Message: message,
 			},
-		})
+		}
+		if pvc.Spec.VolumeName != "" {
+			pv, err := k.CoreV1().PersistentVolumes().Get(pvc.Spec.VolumeName)
+			if err != nil && !k8serrors.IsNotFound(err) {
+				return nil, errors.Trace(err)
+			}
+			if err == nil {
+				info.Volume = caas.VolumeInfo{
+					VolumeId:   pv.Name,
+					Size:       quantityMiB(pv.Spec.Capacity[v1.ResourceStorage]),
+					Persistent: pv.Spec.PersistentVolumeReclaimPolicy == v1.PersistentVolumeReclaimRetain,
+				}
+			}
+		}
+		result = append(result, info)
 	}
 	return result, nil
 }
 
-func (k *kubernetesClient) jujuStatus(podPhase v1.PodPhase) status.Status {
-	switch podPhase {
+func (k *kubernetesClient) claimStatus(claimStatus v1.PersistentVolumeClaimStatus) (status.Status, string) {
+	switch claimStatus.Phase {
+	case v1.ClaimBound:
+		return status.Attached, ""
+	case v1.ClaimLost:
+		return status.Error, "persistent volume lost"
+	}
+	return status.Pending, ""
+}
+
+// quantityMiB returns the quantity in MiB,
+// rounded up to the nearest MiB.
+func quantityMiB(q resource.Quantity) uint64 {
+	const mib = 1024 * 1024
+	value := q.Value()
+	if value <= 0 {
+		return 0
+	}
+	return uint64((value + mib - 1) / mib)
+}
+
+func (k *kubernetesClient) jujuStatus(podStatus v1.PodStatus) (status.Status, string) {
+	switch podStatus.Phase {
 	case v1.PodRunning:
-		return status.Running
+		return status.Running, podStatus.Message
 	case v1.PodFailed:
-		return status.Error
-	default:
-		return status.Allocating
+		return status.Error, podStatus.Message
+	case v1.PodPending:
+		// A pod which cannot be scheduled, typically because there
+		// is insufficient capacity for its resource requests, will
+		// stay pending indefinitely so is reported as an error.
+		for _, cond := range podStatus.Conditions {
+			if cond.Type == v1.PodScheduled && cond.Status == v1.ConditionFalse && cond.Reason == podReasonUnschedulable {
+				return status.Error, cond.Message
+			}
+		}
 	}
+	return status.Allocating, podStatus.Message
 }
 
 // EnsureUnit creates or updates a unit pod with the given unit name and spec.
-func (k *kubernetesClient) EnsureUnit(appName, unitName string, spec *caas.ContainerSpec) error {
-	logger.Debugf("creating/updating unit %s", unitName)
-	unitSpec, err := makeUnitSpec(spec)
+func (k *kubernetesClient) EnsureUnit(appName, unitName string, params *caas.ServiceParams) error {
+	return k.EnsureUnits(appName, []caas.UnitSpec{{UnitName: unitName, Params: params}})
+}
+
+// EnsureUnits creates or updates the pods of the specified units.
+// Any existing pods are deleted together, so that the units are
+// recreated in one pass rather than one at a time.
+func (k *kubernetesClient) EnsureUnits(appName string, units []caas.UnitSpec) error {
+	var (
+		pods     []*v1.Pod
+		podNames []string
+	)
+	for _, u := range units {
+		logger.Debugf("creating/updating unit %s", u.UnitName)
+		pod, err := k.unitPod(appName, u.UnitName, u.Params)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		pods = append(pods, pod)
+		podNames = append(podNames, pod.Name)
+	}
+	if err := k.deletePods(podNames); err != nil {
+		return errors.Trace(err)
+	}
+	for _, pod := range pods {
+		if err := k.createPod(pod); err != nil {
+			return errors.Annotatef(err, "creating pod %s", pod.Name)
+		}
+	}
+	return nil
+}
+
+// unitPod returns the pod for the specified unit, creating
+// any resources on which the pod depends.
+func (k *kubernetesClient) unitPod(appName, unitName string, params *caas.ServiceParams) (*v1.Pod, error) {
+	if params == nil || params.PodSpec == nil {
+		return nil, errors.Errorf("missing pod spec for %s", unitName)
+	}
+	podName := unitPodName(unitName)
+	unitLabels := map[string]string{
+		labelApplication: appName,
+		labelUnit:        unitName,
+	}
+	if err := k.ensureOperatorRules(appName, params.PodSpec.OperatorRules, params.Trusted); err != nil {
+		return nil, errors.Annotate(err, "updating operator rules")
+	}
+	unitSpec, err := k.prepareUnitSpec(params, podName, unitLabels, false)
 	if err != nil {
-		return errors.Annotatef(err, "parsing spec for %s", unitName)
+		return nil, errors.Annotatef(err, "preparing unit spec for %s", unitName)
+	}
+	annotations, err := podAnnotations(unitSpec)
+	if err != nil {
+		return nil, errors.Trace(err)
 	}
+	return &v1.Pod{
+		ObjectMeta: v1.ObjectMeta{
+			Name:        podName,
+			Labels:      podLabels(unitSpec, unitLabels),
+			Annotations: annotations},
+		Spec: unitSpec.Pod,
+	}, nil
+}
+
+// DeleteUnit deletes the pod of the specified unit, along with
+// the config maps and secrets created for it. The unit's volume
+// claims are deliberately left behind, so that storage outlives
+// the unit.
+func (k *kubernetesClient) DeleteUnit(unitName string) error {
+	logger.Debugf("deleting unit %s", unitName)
+
 	podName := unitPodName(unitName)
 	if err := k.deletePod(podName); err != nil {
 		return errors.Trace(err)
 	}
-	pod := &v1.Pod{
-		ObjectMeta: v1.ObjectMeta{
-			Name: podName,
-			Labels: map[string]string{
-				labelApplication: appName,
-				labelUnit:        unitName}},
-		Spec: unitSpec.Pod,
+	unitLabels := map[string]string{labelUnit: unitName}
+	if err := k.deleteSecrets(unitLabels, imagePullSecretName(podName, ""), nil); err != nil {
+		return errors.Trace(err)
 	}
-	return k.createPod(pod)
+	return errors.Trace(k.deleteConfigMaps(unitLabels, fileSetConfigMapName(podName, ""), nil))
+}
+
+// prepareUnitSpec returns the unit spec for pods created from the
+// specified params, creating any resources on which the pods depend.
+// Those resources are named after, and labelled as belonging to, the
+// specified owner.
+func (k *kubernetesClient) prepareUnitSpec(
+	params *caas.ServiceParams, ownerName string, labels map[string]string, stateful bool,
+) (*unitSpec, error) {
+	unitSpec, err := makeUnitSpec(params.PodSpec)
+	if err != nil {
+		return nil, errors.Annotate(err, "parsing unit spec")
+	}
+	unitSpec.Labels = params.PodLabels
+	unitSpec.Annotations = params.PodAnnotations
+	if err := applyConstraints(&unitSpec.Pod, params.Constraints); err != nil {
+		return nil, errors.Annotate(err, "applying constraints")
+	}
+	applyGPURequests(unitSpec, params.PodSpec)
+	if err := applyPlacement(unitSpec, labels[labelApplication], params.Placement, params.Constraints); err != nil {
+		return nil, errors.Annotate(err, "applying placement")
+	}
+	if err := k.configureStorage(unitSpec, ownerName, labels, params.PodSpec, params.StoragePools, stateful); err != nil {
+		return nil, errors.Annotate(err, "configuring storage")
+	}
+	if err := k.configureFiles(unitSpec, ownerName, labels, params.PodSpec); err != nil {
+		return nil, errors.Annotate(err, "configuring files")
+	}
+	if err := k.configureImagePullSecrets(unitSpec, ownerName, labels, params); err != nil {
+		return nil, errors.Annotate(err, "configuring image pull secrets")
+	}
+	return unitSpec, nil
+}
+
+// applyConstraints sets the resource requests and limits of the
+// workload container from the specified constraints. Juju measures
+// cpu-power such that 100 is one core, and mem in MiB. The gpus
+// constraint requests GPUs of the default type.
+func applyConstraints(pod *v1.PodSpec, cons constraints.Value) error {
+	if len(pod.Containers) == 0 {
+		return nil
+	}
+	resources := v1.ResourceList{}
+	if cons.HasCpuPower() {
+		cpu, err := resource.ParseQuantity(fmt.Sprintf("%dm", *cons.CpuPower*10))
+		if err != nil {
+			return errors.Trace(err)
+		}
+		resources[v1.ResourceCPU] = cpu
+	}
+	if cons.HasMem() {
+		mem, err := resource.ParseQuantity(fmt.Sprintf("%dMi", *cons.Mem))
+		if err != nil {
+			return errors.Trace(err)
+		}
+		resources[v1.ResourceMemory] = mem
+	}
+	if cons.HasGpus() {
+		resources[v1.ResourceName(caas.DefaultGPUType)] = *resource.NewQuantity(int64(*cons.Gpus), resource.DecimalSI)
+	}
+	if len(resources) == 0 {
+		return nil
+	}
+	// The workload must not be scheduled unless the requested
+	// resources are available, and must not exceed them.
+	pod.Containers[0].Resources = v1.ResourceRequirements{
+		Requests: resources,
+		Limits:   resources,
+	}
+	return nil
+}
+
+// applyGPURequests requests the GPUs declared by the containers in
+// the pod spec. GPUs cannot be overcommitted, so the requests and
+// limits are the same, and take precedence over any constraint.
+func applyGPURequests(unitSpec *unitSpec, podSpec *caas.PodSpec) {
+	apply := func(container *v1.Container, gpu *caas.GPURequest) {
+		if gpu == nil {
+			return
+		}
+		gpuType := gpu.Type
+		if gpuType == "" {
+			gpuType = caas.DefaultGPUType
+		}
+		quantity := *resource.NewQuantity(int64(gpu.Count), resource.DecimalSI)
+		resources := &container.Resources
+		if resources.Requests == nil {
+			resources.Requests = v1.ResourceList{}
+		}
+		if resources.Limits == nil {
+			resources.Limits = v1.ResourceList{}
+		}
+		resources.Requests[v1.ResourceName(gpuType)] = quantity
+		resources.Limits[v1.ResourceName(gpuType)] = quantity
+	}
+	for i, c := range podSpec.Containers {
+		apply(&unitSpec.Pod.Containers[i], c.GPU)
+	}
+	for i, c := range podSpec.InitContainers {
+		apply(&unitSpec.Pod.InitContainers[i], c.GPU)
+	}
+}
+
+// configureStorage ensures there is a persistent volume claim for each
+// of the volumes in the spec, and mounts the claims into the containers
+// which declare them. Claims are named after the owning resource so that
+// they survive the pods being rescheduled; since a claim is mounted by
+// a single pod, the owner must not have more than one. For stateful sets
+// claim templates are added to the unit spec instead, from which a claim
+// is created for each pod. Volumes provisioned from a Juju storage pool
+// use the storage class configured by the pool.
+func (k *kubernetesClient) configureStorage(
+	unitSpec *unitSpec, ownerName string, labels map[string]string,
+	spec *caas.PodSpec, storagePools map[string]*storage.Config, stateful bool,
+) error {
+	podSpec := &unitSpec.Pod
+	for i, c := range spec.Containers {
+		for _, vol := range c.Volumes {
+			size, err := resource.ParseQuantity(vol.Size)
+			if err != nil {
+				return errors.Annotatef(err, "invalid size %q for volume %q", vol.Size, vol.Name)
+			}
+			claimName := persistentVolumeClaimName(ownerName, vol.Name)
+			claim := &v1.PersistentVolumeClaim{
+				ObjectMeta: v1.ObjectMeta{
+					Name:   claimName,
+					Labels: labels,
+				},
+				Spec: v1.PersistentVolumeClaimSpec{
+					AccessModes: []v1.PersistentVolumeAccessMode{v1.ReadWriteOnce},
+					Resources: v1.ResourceRequirements{
+						Requests: v1.ResourceList{v1.ResourceStorage: size},
+					},
+				},
+			}
+			className := vol.StorageClass
+			if vol.StoragePool != "" {
+				pool, ok := storagePools[vol.StoragePool]
+				if !ok {
+					return errors.NotFoundf("storage pool %q for volume %q", vol.StoragePool, vol.Name)
+				}
+				if className, err = k.ensureStorageClass(pool); err != nil {
+					return errors.Annotatef(err, "configuring storage class for volume %q", vol.Name)
+				}
+			}
+			if className != "" {
+				claim.ObjectMeta.Annotations = map[string]string{
+					storageClassAnnotation: className,
+				}
+			}
+			podSpec.Containers[i].VolumeMounts = append(podSpec.Containers[i].VolumeMounts, v1.VolumeMount{
+				Name:      vol.Name,
+				MountPath: vol.MountPath,
+				ReadOnly:  vol.ReadOnly,
+			})
+			if stateful {
+				// Each pod of a stateful set gets its own claim,
+				// created from the template by kubernetes, and
+				// named after the template and the pod.
+				claim.ObjectMeta.Name = vol.Name
+				unitSpec.VolumeClaimTemplates = append(unitSpec.VolumeClaimTemplates, *claim)
+				continue
+			}
+			if err := k.ensurePersistentVolumeClaim(claim); err != nil {
+				return errors.Annotatef(err, "creating persistent volume claim for %q", vol.Name)
+			}
+
+			podSpec.Volumes = append(podSpec.Volumes, v1.Volume{
+				Name: vol.Name,
+				VolumeSource: v1.VolumeSource{
+					PersistentVolumeClaim: &v1.PersistentVolumeClaimVolumeSource{
+						ClaimName: claimName,
+						ReadOnly:  vol.ReadOnly,
+					},
+				},
+			})
+		}
+	}
+	// Init containers may only mount volumes declared above.
+	for i, c := range spec.InitContainers {
+		for _, vol := range c.Volumes {
+			unitSpec.InitContainers[i].VolumeMounts = append(unitSpec.InitContainers[i].VolumeMounts, v1.VolumeMount{
+				Name:      vol.Name,
+				MountPath: vol.MountPath,
+				ReadOnly:  vol.ReadOnly,
+			})
+		}
+	}
+	podSpec.InitContainers = unitSpec.InitContainers
+	return nil
+}
+
+// hasVolumes reports whether any container in the spec declares volumes.
+func hasVolumes(spec *caas.PodSpec) bool {
+	for _, c := range spec.Containers {
+		if len(c.Volumes) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// configureFiles ensures there is a config map holding the contents of
+// each inline file set in the spec, and mounts the config maps, or the
+// referenced secrets, into the containers which declare them. Config
+// maps for file sets no longer in the spec are removed.
+func (k *kubernetesClient) configureFiles(
+	unitSpec *unitSpec, ownerName string, labels map[string]string, spec *caas.PodSpec,
+) error {
+	podSpec := &unitSpec.Pod
+	wanted := make(map[string]bool)
+	for i, c := range spec.Containers {
+		for _, fileSet := range c.Files {
+			var source v1.VolumeSource
+			if fileSet.Secret != "" {
+				source.Secret = &v1.SecretVolumeSource{SecretName: fileSet.Secret}
+			} else {
+				configMapName := fileSetConfigMapName(ownerName, fileSet.Name)
+				configMap := &v1.ConfigMap{
+					ObjectMeta: v1.ObjectMeta{
+						Name:   configMapName,
+						Labels: labels,
+					},
+					Data: fileSet.Files,
+				}
+				if err := k.ensureConfigMap(configMap); err != nil {
+					return errors.Annotatef(err, "creating config map for file set %q", fileSet.Name)
+				}
+				wanted[configMapName] = true
+				source.ConfigMap = &v1.ConfigMapVolumeSource{
+					LocalObjectReference: v1.LocalObjectReference{Name: configMapName},
+				}
+			}
+			podSpec.Volumes = append(podSpec.Volumes, v1.Volume{
+				Name:         fileSet.Name,
+				VolumeSource: source,
+			})
+			podSpec.Containers[i].VolumeMounts = append(podSpec.Containers[i].VolumeMounts, v1.VolumeMount{
+				Name:      fileSet.Name,
+				MountPath: fileSet.MountPath,
+				ReadOnly:  true,
+			})
+		}
+	}
+	return errors.Trace(k.deleteConfigMaps(labels, fileSetConfigMapName(ownerName, ""), wanted))
+}
+
+// deleteConfigMaps deletes the config maps with the specified labels
+// and name prefix, other than those in the keep set.
+func (k *kubernetesClient) deleteConfigMaps(labels map[string]string, namePrefix string, keep map[string]bool) error {
+	var selector []string
+	for key, value := range labels {
+		selector = append(selector, fmt.Sprintf("%v==%v", key, value))
+	}
+	configMaps := k.CoreV1().ConfigMaps(k.namespace)
+	existing, err := configMaps.List(v1.ListOptions{
+		LabelSelector: strings.Join(selector, ","),
+	})
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for _, cm := range existing.Items {
+		if keep[cm.Name] || !strings.HasPrefix(cm.Name, namePrefix) {
+			continue
+		}
+		err := configMaps.Delete(cm.Name, &v1.DeleteOptions{})
+		if err != nil && !k8serrors.IsNotFound(err) {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+func (k *kubernetesClient) ensurePersistentVolumeClaim(spec *v1.PersistentVolumeClaim) error {
+	claims := k.CoreV1().PersistentVolumeClaims(k.namespace)
+	_, err := claims.Get(spec.Name)
+	if err == nil {
+		// The spec of a bound claim is immutable,
+		// so any existing claim is used as is.
+		return nil
+	}
+	if !k8serrors.IsNotFound(err) {
+		return errors.Trace(err)
+	}
+	_, err = claims.Create(spec)
+	return errors.Trace(err)
 }
 
 func (k *kubernetesClient) ensureConfigMap(configMap *v1.ConfigMap) error {
-	configMaps := k.CoreV1().ConfigMaps(namespace)
+	configMaps := k.CoreV1().ConfigMaps(k.namespace)
 	_, err := configMaps.Update(configMap)
 	if k8serrors.IsNotFound(err) {
 		_, err = configMaps.Create(configMap)
@@ -443,25 +1223,38 @@ func (k *kubernetesClient) ensureConfigMap(configMap *v1.ConfigMap) error {
 }
 
 func (k *kubernetesClient) createPod(spec *v1.Pod) error {
-	pods := k.CoreV1().Pods(namespace)
+	pods := k.CoreV1().Pods(k.namespace)
 	_, err := pods.Create(spec)
 	return errors.Trace(err)
 }
 
 func (k *kubernetesClient) deletePod(podName string) error {
+	return k.deletePods([]string{podName})
+}
+
+// deletePods deletes the specified pods, and waits for
+// them all to go away.
+func (k *kubernetesClient) deletePods(podNames []string) error {
 	orphanDependents := false
-	pods := k.CoreV1().Pods(namespace)
-	err := pods.Delete(podName, &v1.DeleteOptions{
-		OrphanDependents: &orphanDependents,
-	})
-	if k8serrors.IsNotFound(err) {
-		return nil
+	pods := k.CoreV1().Pods(k.namespace)
+	var deleted []string
+	for _, podName := range podNames {
+		err := pods.Delete(podName, &v1.DeleteOptions{
+			OrphanDependents: &orphanDependents,
+		})
+		if k8serrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return errors.Trace(err)
+		}
+		deleted = append(deleted, podName)
 	}
-	if err != nil {
-		return errors.Trace(err)
+	if len(deleted) == 0 {
+		return nil
 	}
 
-	// Wait for pod to be deleted.
+	// Wait for pods to be deleted.
 	//
 	// TODO(caas) if we even need to wait,
 	// consider using pods.Watch.
@@ -472,14 +1265,17 @@ func (k *kubernetesClient) deletePod(podName string) error {
 			return errors.Cause(err) != errExists
 		},
 		Func: func() error {
-			_, err := pods.Get(podName)
-			if err == nil {
-				return errExists
+			for len(deleted) > 0 {
+				_, err := pods.Get(deleted[0])
+				if err == nil {
+					return errExists
+				}
+				if !k8serrors.IsNotFound(err) {
+					return errors.Trace(err)
+				}
+				deleted = deleted[1:]
 			}
-			if k8serrors.IsNotFound(err) {
-				return nil
-			}
-			return errors.Trace(err)
+			return nil
 		},
 		Delay:       5 * time.Second,
 		MaxDuration: time.Minute,
@@ -489,7 +1285,7 @@ func (k *kubernetesClient) deletePod(podName string) error {
 
 // operatorPod returns a *v1.Pod for the operator pod
 // of the specified application.
-func operatorPod(appName, agentPath string) *v1.Pod {
+func operatorPod(appName, agentPath, image string) *v1.Pod {
 	podName := operatorPodName(appName)
 	configMapName := operatorConfigMapName(appName)
 	configVolName := configMapName + "-volume"
@@ -498,10 +1294,11 @@ func operatorPod(appName, agentPath string) *v1.Pod {
 	return &v1.Pod{
 		ObjectMeta: v1.ObjectMeta{Name: podName},
 		Spec: v1.PodSpec{
+			ServiceAccountName: operatorAccountName(appName),
 			Containers: []v1.Container{{
-				Name:            "juju-operator",
+				Name:            operatorContainerName,
 				ImagePullPolicy: v1.PullIfNotPresent,
-				Image:           "jujusolutions/caas-jujud-operator:latest",
+				Image:           image,
 				Env: []v1.EnvVar{
 					{Name: "JUJU_APPLICATION", Value: appName},
 				},
@@ -544,12 +1341,27 @@ func operatorConfigMap(appName string, config *caas.OperatorConfig) *v1.ConfigMa
 }
 
 type unitSpec struct {
-	Pod v1.PodSpec `json:"pod"`
+	Pod            v1.PodSpec     `json:"pod"`
+	InitContainers []v1.Container `json:"initContainers,omitempty"`
+
+	// Affinity and Tolerations are not fields of
+	// the pod spec in the kubernetes API version
+	// we support, so are applied as annotations.
+	Affinity    *v1.Affinity    `json:"-"`
+	Tolerations []v1.Toleration `json:"-"`
+
+	// VolumeClaimTemplates are the templates of the
+	// per-pod volume claims of a stateful set.
+	VolumeClaimTemplates []v1.PersistentVolumeClaim `json:"-"`
+
+	// Labels and Annotations are the extra labels
+	// and annotations applied to the pods.
+	Labels      map[string]string `json:"-"`
+	Annotations map[string]string `json:"-"`
 }
 
 var defaultPodTemplate = `
-pod:
-  containers:
+{{- define "container" }}
   - name: {{.Name}}
     image: {{.ImageName}}
     {{if .Ports}}
@@ -566,12 +1378,20 @@ pod:
           value: {{$v}}
     {{- end}}
     {{end}}
+{{- end }}
+pod:
+  containers:
+  {{- range .Containers }}{{ template "container" . }}{{- end}}
+{{- if .InitContainers }}
+initContainers:
+  {{- range .InitContainers }}{{ template "container" . }}{{- end}}
+{{- end }}
 `[1:]
 
-func makeUnitSpec(containerSpec *caas.ContainerSpec) (*unitSpec, error) {
+func makeUnitSpec(podSpec *caas.PodSpec) (*unitSpec, error) {
 	tmpl := template.Must(template.New("").Parse(defaultPodTemplate))
 	var buf bytes.Buffer
-	if err := tmpl.Execute(&buf, containerSpec); err != nil {
+	if err := tmpl.Execute(&buf, podSpec); err != nil {
 		return nil, errors.Trace(err)
 	}
 	unitSpecString := buf.String()
@@ -581,9 +1401,96 @@ func makeUnitSpec(containerSpec *caas.ContainerSpec) (*unitSpec, error) {
 	if err := decoder.Decode(&unitSpec); err != nil {
 		return nil, errors.Trace(err)
 	}
+	for i, c := range podSpec.Containers {
+		unitSpec.Pod.Containers[i].LivenessProbe = containerProbe(c.LivenessProbe)
+		unitSpec.Pod.Containers[i].ReadinessProbe = containerProbe(c.ReadinessProbe)
+	}
+	unitSpec.Pod.InitContainers = unitSpec.InitContainers
 	return &unitSpec, nil
 }
 
+// podLabels returns the labels to apply to pods created from
+// the unit spec. The specified labels, used by Juju to manage
+// the pods, take precedence over any extra labels.
+func podLabels(unitSpec *unitSpec, labels map[string]string) map[string]string {
+	result := make(map[string]string)
+	for key, value := range unitSpec.Labels {
+		result[key] = value
+	}
+	for key, value := range labels {
+		result[key] = value
+	}
+	return result
+}
+
+// podAnnotations returns the annotations to apply to pods
+// created from the unit spec.
+func podAnnotations(unitSpec *unitSpec) (map[string]string, error) {
+	// The kubernetes API version we support only recognises
+	// init containers, affinity and tolerations via annotations.
+	values := map[string]interface{}{}
+	if len(unitSpec.InitContainers) > 0 {
+		values[initContainersAnnotation] = unitSpec.InitContainers
+	}
+	if unitSpec.Affinity != nil {
+		values[affinityAnnotation] = unitSpec.Affinity
+	}
+	if len(unitSpec.Tolerations) > 0 {
+		values[tolerationsAnnotation] = unitSpec.Tolerations
+	}
+	if len(values) == 0 && len(unitSpec.Annotations) == 0 {
+		return nil, nil
+	}
+	annotations := make(map[string]string)
+	for key, value := range unitSpec.Annotations {
+		annotations[key] = value
+	}
+	for key, value := range values {
+		data, err := json.Marshal(value)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		annotations[key] = string(data)
+	}
+	return annotations, nil
+}
+
+// containerProbe returns the kubernetes probe
+// corresponding to the specified Juju probe.
+func containerProbe(probe *caas.ContainerProbe) *v1.Probe {
+	if probe == nil {
+		return nil
+	}
+	result := &v1.Probe{
+		InitialDelaySeconds: probe.InitialDelaySeconds,
+		TimeoutSeconds:      probe.TimeoutSeconds,
+		PeriodSeconds:       probe.PeriodSeconds,
+		SuccessThreshold:    probe.SuccessThreshold,
+		FailureThreshold:    probe.FailureThreshold,
+	}
+	switch {
+	case probe.HTTPGet != nil:
+		result.HTTPGet = &v1.HTTPGetAction{
+			Path:   probe.HTTPGet.Path,
+			Port:   intstr.FromInt(probe.HTTPGet.Port),
+			Scheme: v1.URIScheme(probe.HTTPGet.Scheme),
+		}
+	case probe.TCPSocket != nil:
+		result.TCPSocket = &v1.TCPSocketAction{
+			Port: intstr.FromInt(probe.TCPSocket.Port),
+		}
+	case probe.Exec != nil:
+		result.Exec = &v1.ExecAction{
+			Command: probe.Exec.Command,
+		}
+	}
+	return result
+}
+
+func operatorImagePath(vers version.Number) string {
+	return operatorImageRepo + ":" + vers.String()
+}
+
 func operatorPodName(appName string) string {
 	return "juju-operator-" + appName
 }
@@ -596,6 +1503,18 @@ func unitPodName(unitName string) string {
 	return "juju-" + names.NewUnitTag(unitName).String()
 }
 
+func fileSetConfigMapName(ownerName, fileSetName string) string {
+	return ownerName + "-files-" + fileSetName
+}
+
+func persistentVolumeClaimName(ownerName, volumeName string) string {
+	return ownerName + "-" + volumeName
+}
+
+func ingressTLSSecretName(appName string) string {
+	return deploymentName(appName) + "-tls"
+}
+
 func deploymentName(appName string) string {
 	return "juju-" + appName
 }
@@ -9,30 +9,38 @@ import (
 	"github.com/juju/errors"
 	"gopkg.in/tomb.v1"
 	apierrs "k8s.io/client-go/pkg/api/errors"
+	"k8s.io/client-go/pkg/api/v1"
 	"k8s.io/client-go/pkg/watch"
 
-	"github.com/juju/juju/watcher"
+	"github.com/juju/juju/caas"
 	"github.com/juju/juju/worker/catacomb"
 )
 
-// kubernetesWatcher reports changes to kubernetes
-// resources. A native kubernetes watcher is passed
-// in to generate change events from the kubernetes
-// model. These events are consolidated into a Juju
-// notification watcher event.
+const (
+	unitReasonPodDeleted     = "pod deleted"
+	unitReasonPodTerminating = "pod terminating"
+)
+
+// kubernetesWatcher reports changes to the pods of
+// an application. A native kubernetes watcher is
+// passed in to generate change events from the
+// kubernetes model. These events are consolidated
+// into unit change events.
 type kubernetesWatcher struct {
 	catacomb catacomb.Catacomb
 
-	out       chan struct{}
+	out       chan []caas.UnitChange
 	name      string
 	k8watcher watch.Interface
+	toUnit    func(*v1.Pod) (caas.Unit, error)
 }
 
-func newKubernetesWatcher(wi watch.Interface, name string) (*kubernetesWatcher, error) {
+func newKubernetesWatcher(wi watch.Interface, name string, toUnit func(*v1.Pod) (caas.Unit, error)) (*kubernetesWatcher, error) {
 	w := &kubernetesWatcher{
-		out:       make(chan struct{}),
+		out:       make(chan []caas.UnitChange),
 		k8watcher: wi,
 		name:      name,
+		toUnit:    toUnit,
 	}
 	err := catacomb.Invoke(catacomb.Plan{
 		Site: &w.catacomb,
@@ -47,8 +55,10 @@ func (w *kubernetesWatcher) loop() error {
 	defer close(w.out)
 	defer w.k8watcher.Stop()
 
-	var out chan struct{}
-	// Set delayCh now so that initial event is sent.
+	var out chan []caas.UnitChange
+	pending := make(map[string]caas.UnitChange)
+	// Set delayCh now so that initial event is sent,
+	// even if there are no pods.
 	delayCh := time.After(sendDelay)
 	for {
 		select {
@@ -63,21 +73,54 @@ func (w *kubernetesWatcher) loop() error {
 			if evt.Type == watch.Error {
 				return errors.Errorf("kubernetes watcher error: %v", apierrs.FromObject(evt.Object))
 			}
-			if delayCh == nil {
+			pod, ok := evt.Object.(*v1.Pod)
+			if !ok {
+				logger.Debugf("ignoring unexpected %T in k8s event for %v", evt.Object, w.name)
+				continue
+			}
+			change, err := w.unitChange(evt.Type, pod)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			caas.MergeUnitChange(pending, change)
+			if delayCh == nil && out == nil {
 				delayCh = time.After(sendDelay)
 			}
 		case <-delayCh:
 			out = w.out
-		case out <- struct{}{}:
-			logger.Debugf("fire notify watcher for %v", w.name)
-			out = nil
 			delayCh = nil
+		case out <- caas.SortedUnitChanges(pending):
+			logger.Debugf("fire units watcher for %v", w.name)
+			pending = make(map[string]caas.UnitChange)
+			out = nil
 		}
 	}
 }
 
+// unitChange returns the unit change for the pod event.
+func (w *kubernetesWatcher) unitChange(eventType watch.EventType, pod *v1.Pod) (caas.UnitChange, error) {
+	unit, err := w.toUnit(pod)
+	if err != nil {
+		return caas.UnitChange{}, errors.Trace(err)
+	}
+	change := caas.UnitChange{Unit: unit}
+	switch {
+	case eventType == watch.Deleted:
+		change.Kind = caas.UnitRemoved
+		change.Reason = unitReasonPodDeleted
+	case pod.DeletionTimestamp != nil:
+		change.Kind = caas.UnitRemoved
+		change.Reason = unitReasonPodTerminating
+	case eventType == watch.Added:
+		change.Kind = caas.UnitAdded
+	default:
+		change.Kind = caas.UnitUpdated
+	}
+	return change, nil
+}
+
 // Changes returns the event channel for this watcher.
-func (w *kubernetesWatcher) Changes() watcher.NotifyChannel {
+func (w *kubernetesWatcher) Changes() caas.UnitsChannel {
 	return w.out
 }
 
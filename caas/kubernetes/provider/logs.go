@@ -0,0 +1,261 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package provider
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+	"gopkg.in/tomb.v1"
+	apierrs "k8s.io/client-go/pkg/api/errors"
+	"k8s.io/client-go/pkg/api/unversioned"
+	"k8s.io/client-go/pkg/api/v1"
+	"k8s.io/client-go/pkg/watch"
+
+	"github.com/juju/juju/caas"
+	"github.com/juju/juju/worker/catacomb"
+)
+
+// maxPendingLogRecords is the maximum number of log records held
+// while waiting for them to be consumed. Older records are dropped
+// so that a slow consumer cannot exhaust memory.
+const maxPendingLogRecords = 1000
+
+// WatchLogs returns a watcher which reports the output of the
+// containers of the specified application's pods.
+func (k *kubernetesClient) WatchLogs(appName string, since time.Time) (caas.LogsWatcher, error) {
+	pods := k.CoreV1().Pods(k.namespace)
+	w, err := pods.Watch(v1.ListOptions{
+		LabelSelector: applicationSelector(appName),
+		Watch:         true,
+	})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	openStream := func(podName string, opts *v1.PodLogOptions) (io.ReadCloser, error) {
+		return pods.GetLogs(podName, opts).Stream()
+	}
+	return newLogsWatcher(w, appName, since, openStream)
+}
+
+// logStreamKey identifies a container of a pod.
+type logStreamKey struct {
+	podUID    string
+	container string
+}
+
+// logsWatcher reports the output of the containers of an
+// application's pods. The pods are watched so that the
+// output of each running container is streamed, including
+// after the container restarts, until the pod is deleted.
+type logsWatcher struct {
+	catacomb catacomb.Catacomb
+
+	out        chan []caas.LogRecord
+	name       string
+	since      time.Time
+	k8watcher  watch.Interface
+	openStream func(podName string, opts *v1.PodLogOptions) (io.ReadCloser, error)
+
+	records chan caas.LogRecord
+	ended   chan logStreamKey
+}
+
+func newLogsWatcher(
+	wi watch.Interface,
+	name string,
+	since time.Time,
+	openStream func(string, *v1.PodLogOptions) (io.ReadCloser, error),
+) (*logsWatcher, error) {
+	w := &logsWatcher{
+		out:        make(chan []caas.LogRecord),
+		name:       name,
+		since:      since,
+		k8watcher:  wi,
+		openStream: openStream,
+		records:    make(chan caas.LogRecord),
+		ended:      make(chan logStreamKey),
+	}
+	err := catacomb.Invoke(catacomb.Plan{
+		Site: &w.catacomb,
+		Work: w.loop,
+	})
+	return w, err
+}
+
+func (w *logsWatcher) loop() error {
+	defer close(w.out)
+	defer w.k8watcher.Stop()
+
+	// streams holds, for each container whose output is being
+	// streamed, a channel which is closed to stop the stream.
+	streams := make(map[logStreamKey]chan struct{})
+	defer func() {
+		for _, stop := range streams {
+			close(stop)
+		}
+	}()
+	// lastSeen holds the time of the last line read from each
+	// container, so that a restarted stream resumes after it.
+	lastSeen := make(map[logStreamKey]time.Time)
+
+	var out chan []caas.LogRecord
+	var pending []caas.LogRecord
+	for {
+		select {
+		case <-w.catacomb.Dying():
+			return tomb.ErrDying
+		case evt, ok := <-w.k8watcher.ResultChan():
+			// This can happen if the k8s API connection drops.
+			if !ok {
+				return errors.Errorf("k8s event watcher closed, restarting")
+			}
+			if evt.Type == watch.Error {
+				return errors.Errorf("kubernetes watcher error: %v", apierrs.FromObject(evt.Object))
+			}
+			pod, ok := evt.Object.(*v1.Pod)
+			if !ok {
+				logger.Debugf("ignoring unexpected %T in k8s event for %v", evt.Object, w.name)
+				continue
+			}
+			if evt.Type == watch.Deleted {
+				for key, stop := range streams {
+					if key.podUID == string(pod.UID) {
+						close(stop)
+						delete(streams, key)
+					}
+				}
+				for key := range lastSeen {
+					if key.podUID == string(pod.UID) {
+						delete(lastSeen, key)
+					}
+				}
+				continue
+			}
+			for _, cs := range pod.Status.ContainerStatuses {
+				key := logStreamKey{podUID: string(pod.UID), container: cs.Name}
+				if _, ok := streams[key]; ok || cs.State.Running == nil {
+					continue
+				}
+				since, resumed := lastSeen[key]
+				if !resumed {
+					since = w.since
+				}
+				stop := make(chan struct{})
+				streams[key] = stop
+				go w.streamLogs(*pod, key, since, resumed, stop)
+			}
+		case key := <-w.ended:
+			if stop, ok := streams[key]; ok {
+				close(stop)
+				delete(streams, key)
+			}
+		case record := <-w.records:
+			lastSeen[logStreamKey{podUID: record.UnitId, container: record.ContainerName}] = record.Time
+			pending = append(pending, record)
+			if dropped := len(pending) - maxPendingLogRecords; dropped > 0 {
+				logger.Warningf("dropping %d log records of %v", dropped, w.name)
+				pending = pending[dropped:]
+			}
+			out = w.out
+		case out <- pending:
+			pending = nil
+			out = nil
+		}
+	}
+}
+
+// streamLogs reads the output of the container of the pod, written
+// since the specified time, until the container exits or the stop
+// channel is closed. If the stream resumes an earlier one, lines
+// written at the time of the last line already read are skipped.
+func (w *logsWatcher) streamLogs(pod v1.Pod, key logStreamKey, since time.Time, resumed bool, stop <-chan struct{}) {
+	defer func() {
+		select {
+		case w.ended <- key:
+		case <-w.catacomb.Dying():
+		}
+	}()
+	opts := &v1.PodLogOptions{
+		Container:  key.container,
+		Follow:     true,
+		Timestamps: true,
+	}
+	if !since.IsZero() {
+		sinceTime := unversioned.NewTime(since)
+		opts.SinceTime = &sinceTime
+	}
+	stream, err := w.openStream(pod.Name, opts)
+	if err != nil {
+		logger.Warningf("cannot stream logs of container %q of pod %q: %v", key.container, pod.Name, err)
+		return
+	}
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-stop:
+		case <-w.catacomb.Dying():
+		case <-done:
+		}
+		stream.Close()
+	}()
+
+	scanner := bufio.NewScanner(stream)
+	for scanner.Scan() {
+		t, message := parseLogLine(scanner.Text())
+		if t.Before(since) || resumed && !t.After(since) {
+			continue
+		}
+		record := caas.LogRecord{
+			UnitId:        key.podUID,
+			UnitName:      pod.Labels[labelUnit],
+			PodName:       pod.Name,
+			ContainerName: key.container,
+			Time:          t,
+			Message:       message,
+		}
+		select {
+		case w.records <- record:
+		case <-stop:
+			return
+		case <-w.catacomb.Dying():
+			return
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		logger.Debugf("logs of container %q of pod %q: %v", key.container, pod.Name, err)
+	}
+}
+
+// parseLogLine splits a line of container output into the
+// timestamp prefixed by kubernetes and the line written.
+func parseLogLine(line string) (time.Time, string) {
+	parts := strings.SplitN(line, " ", 2)
+	if len(parts) == 2 {
+		if t, err := time.Parse(time.RFC3339Nano, parts[0]); err == nil {
+			return t, parts[1]
+		}
+	}
+	return time.Now(), line
+}
+
+// Changes returns the event channel for this watcher.
+func (w *logsWatcher) Changes() caas.LogsChannel {
+	return w.out
+}
+
+// Kill asks the watcher to stop without waiting for it do so.
+func (w *logsWatcher) Kill() {
+	w.catacomb.Kill(nil)
+}
+
+// Wait waits for the watcher to die and returns any
+// error encountered when it was running.
+func (w *logsWatcher) Wait() error {
+	return w.catacomb.Wait()
+}
@@ -0,0 +1,206 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package provider
+
+import (
+	"io"
+	"io/ioutil"
+	"strings"
+	"time"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/worker.v1/workertest"
+	"k8s.io/client-go/pkg/api/unversioned"
+	"k8s.io/client-go/pkg/api/v1"
+	"k8s.io/client-go/pkg/types"
+	"k8s.io/client-go/pkg/watch"
+
+	"github.com/juju/juju/caas"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type logsSuite struct {
+	testing.IsolationSuite
+
+	podWatcher *watch.FakeWatcher
+	logs       map[string]string
+	opened     chan *v1.PodLogOptions
+}
+
+var _ = gc.Suite(&logsSuite{})
+
+func (s *logsSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.podWatcher = watch.NewFake()
+	s.logs = make(map[string]string)
+	s.opened = make(chan *v1.PodLogOptions, 10)
+}
+
+func (s *logsSuite) openStream(podName string, opts *v1.PodLogOptions) (io.ReadCloser, error) {
+	s.opened <- opts
+	return ioutil.NopCloser(strings.NewReader(s.logs[podName+"/"+opts.Container])), nil
+}
+
+func (s *logsSuite) newWatcher(c *gc.C, since time.Time) *logsWatcher {
+	w, err := newLogsWatcher(s.podWatcher, "gitlab", since, s.openStream)
+	c.Assert(err, jc.ErrorIsNil)
+	return w
+}
+
+func runningPod(name, uid string, containers ...string) *v1.Pod {
+	pod := &v1.Pod{
+		ObjectMeta: v1.ObjectMeta{
+			Name:   name,
+			UID:    types.UID(uid),
+			Labels: map[string]string{labelUnit: "gitlab/0"},
+		},
+	}
+	for _, container := range containers {
+		pod.Status.ContainerStatuses = append(pod.Status.ContainerStatuses, v1.ContainerStatus{
+			Name:  container,
+			State: v1.ContainerState{Running: &v1.ContainerStateRunning{}},
+		})
+	}
+	return pod
+}
+
+func (s *logsSuite) nextRecords(c *gc.C, w *logsWatcher, n int) []caas.LogRecord {
+	var records []caas.LogRecord
+	for len(records) < n {
+		select {
+		case batch, ok := <-w.Changes():
+			c.Assert(ok, jc.IsTrue)
+			records = append(records, batch...)
+		case <-time.After(coretesting.LongWait):
+			c.Fatalf("timed out waiting for log records, got %d of %d", len(records), n)
+		}
+	}
+	c.Assert(records, gc.HasLen, n)
+	return records
+}
+
+func (s *logsSuite) TestParseLogLine(c *gc.C) {
+	t, message := parseLogLine("2018-07-01T10:00:00.000000001Z hello world")
+	c.Assert(t, gc.Equals, time.Date(2018, 7, 1, 10, 0, 0, 1, time.UTC))
+	c.Assert(message, gc.Equals, "hello world")
+}
+
+func (s *logsSuite) TestParseLogLineWithoutTimestamp(c *gc.C) {
+	before := time.Now()
+	t, message := parseLogLine("hello world")
+	c.Assert(t.Before(before), jc.IsFalse)
+	c.Assert(message, gc.Equals, "hello world")
+}
+
+func (s *logsSuite) TestStreamsRunningContainers(c *gc.C) {
+	s.logs["gitlab-0/gitlab"] = "" +
+		"2018-07-01T10:00:00Z started\n" +
+		"2018-07-01T10:00:01Z listening\n"
+	w := s.newWatcher(c, time.Time{})
+	defer workertest.CleanKill(c, w)
+
+	pod := runningPod("gitlab-0", "uid-0", "gitlab")
+	// Containers which are not running are not streamed.
+	pod.Status.ContainerStatuses = append(pod.Status.ContainerStatuses, v1.ContainerStatus{
+		Name:  "init",
+		State: v1.ContainerState{Terminated: &v1.ContainerStateTerminated{}},
+	})
+	s.podWatcher.Add(pod)
+
+	records := s.nextRecords(c, w, 2)
+	c.Assert(records, jc.DeepEquals, []caas.LogRecord{{
+		UnitId:        "uid-0",
+		UnitName:      "gitlab/0",
+		PodName:       "gitlab-0",
+		ContainerName: "gitlab",
+		Time:          time.Date(2018, 7, 1, 10, 0, 0, 0, time.UTC),
+		Message:       "started",
+	}, {
+		UnitId:        "uid-0",
+		UnitName:      "gitlab/0",
+		PodName:       "gitlab-0",
+		ContainerName: "gitlab",
+		Time:          time.Date(2018, 7, 1, 10, 0, 1, 0, time.UTC),
+		Message:       "listening",
+	}})
+
+	opts := <-s.opened
+	c.Assert(opts, jc.DeepEquals, &v1.PodLogOptions{
+		Container:  "gitlab",
+		Follow:     true,
+		Timestamps: true,
+	})
+	select {
+	case opts := <-s.opened:
+		c.Fatalf("unexpected stream opened for container %q", opts.Container)
+	default:
+	}
+}
+
+func (s *logsSuite) TestStreamsSince(c *gc.C) {
+	s.logs["gitlab-0/gitlab"] = "" +
+		"2018-07-01T09:59:59Z old\n" +
+		"2018-07-01T10:00:00Z new\n"
+	since := time.Date(2018, 7, 1, 10, 0, 0, 0, time.UTC)
+	w := s.newWatcher(c, since)
+	defer workertest.CleanKill(c, w)
+
+	s.podWatcher.Add(runningPod("gitlab-0", "uid-0", "gitlab"))
+	records := s.nextRecords(c, w, 1)
+	c.Assert(records[0].Message, gc.Equals, "new")
+
+	opts := <-s.opened
+	sinceTime := unversioned.NewTime(since)
+	c.Assert(opts.SinceTime, jc.DeepEquals, &sinceTime)
+}
+
+func (s *logsSuite) TestResumesAfterRestart(c *gc.C) {
+	s.logs["gitlab-0/gitlab"] = "2018-07-01T10:00:00Z first\n"
+	w := s.newWatcher(c, time.Time{})
+	defer workertest.CleanKill(c, w)
+
+	pod := runningPod("gitlab-0", "uid-0", "gitlab")
+	s.podWatcher.Add(pod)
+	records := s.nextRecords(c, w, 1)
+	c.Assert(records[0].Message, gc.Equals, "first")
+	<-s.opened
+
+	// The stream ends when the container exits. Once it is
+	// running again, the stream resumes after the last line read.
+	s.logs["gitlab-0/gitlab"] = "" +
+		"2018-07-01T10:00:00Z first\n" +
+		"2018-07-01T10:00:02Z second\n"
+	for a := coretesting.LongAttempt.Start(); a.Next(); {
+		s.podWatcher.Modify(pod)
+		select {
+		case opts := <-s.opened:
+			c.Assert(opts.SinceTime.Time, gc.Equals, time.Date(2018, 7, 1, 10, 0, 0, 0, time.UTC))
+			records := s.nextRecords(c, w, 1)
+			c.Assert(records[0].Message, gc.Equals, "second")
+			return
+		default:
+		}
+	}
+	c.Fatal("timed out waiting for stream to resume")
+}
+
+func (s *logsSuite) TestPodWatcherError(c *gc.C) {
+	w := s.newWatcher(c, time.Time{})
+	defer workertest.DirtyKill(c, w)
+
+	s.podWatcher.Error(&unversioned.Status{Message: "boom"})
+	err := workertest.CheckKilled(c, w)
+	c.Assert(err, gc.ErrorMatches, "kubernetes watcher error: .*boom")
+}
+
+func (s *logsSuite) TestPodWatcherClosed(c *gc.C) {
+	w := s.newWatcher(c, time.Time{})
+	defer workertest.DirtyKill(c, w)
+
+	s.podWatcher.Stop()
+	err := workertest.CheckKilled(c, w)
+	c.Assert(err, gc.ErrorMatches, "k8s event watcher closed, restarting")
+}
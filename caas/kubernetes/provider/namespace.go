@@ -0,0 +1,159 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package provider
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/schema"
+	k8serrors "k8s.io/client-go/pkg/api/errors"
+	"k8s.io/client-go/pkg/api/resource"
+	"k8s.io/client-go/pkg/api/v1"
+
+	"github.com/juju/juju/environs"
+)
+
+const (
+	// resourceQuotaName is the name of the resource
+	// quota Juju manages in each model's namespace.
+	resourceQuotaName = "juju-quota"
+
+	namespaceCPUQuotaKey    = "namespace-cpu-quota"
+	namespaceMemoryQuotaKey = "namespace-memory-quota"
+	namespacePodsQuotaKey   = "namespace-pods-quota"
+)
+
+var quotaConfigFields = schema.Fields{
+	namespaceCPUQuotaKey:    schema.String(),
+	namespaceMemoryQuotaKey: schema.String(),
+	namespacePodsQuotaKey:   schema.String(),
+}
+
+var quotaConfigDefaults = schema.Defaults{
+	namespaceCPUQuotaKey:    schema.Omit,
+	namespaceMemoryQuotaKey: schema.Omit,
+	namespacePodsQuotaKey:   schema.Omit,
+}
+
+// Create is part of the caas.Broker interface. It creates the model's
+// namespace, and applies any resource quota defined in model config.
+func (k *kubernetesClient) Create(args environs.CreateParams) error {
+	logger.Debugf("creating namespace %s", k.namespace)
+	hard, err := k.resourceQuota()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err := k.ensureNamespace(); err != nil {
+		return errors.Annotatef(err, "creating namespace %q", k.namespace)
+	}
+	if err := k.ensureResourceQuota(hard); err != nil {
+		return errors.Annotatef(err, "configuring resource quota for namespace %q", k.namespace)
+	}
+	return nil
+}
+
+// Destroy is part of the caas.Broker interface. It deletes the model's
+// namespace, which causes kubernetes to delete everything in it, and
+// the custom resource definitions and storage classes installed for
+// the model, which are not namespaced.
+func (k *kubernetesClient) Destroy() error {
+	logger.Debugf("deleting namespace %s", k.namespace)
+	namespaces := k.CoreV1().Namespaces()
+	ns, err := namespaces.Get(k.namespace)
+	if k8serrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if uuid := ns.Labels[labelModel]; uuid != k.modelConfig.UUID() {
+		logger.Warningf("not deleting namespace %q which is not managed by this model", k.namespace)
+		return nil
+	}
+	modelLabels := map[string]string{labelModel: k.modelConfig.UUID()}
+	if err := k.deleteCustomResourceDefinitions(modelLabels, nil); err != nil {
+		return errors.Annotate(err, "deleting custom resource definitions")
+	}
+	if err := k.deleteStorageClasses(); err != nil {
+		return errors.Annotate(err, "deleting storage classes")
+	}
+	orphanDependents := false
+	err = namespaces.Delete(k.namespace, &v1.DeleteOptions{OrphanDependents: &orphanDependents})
+	if k8serrors.IsNotFound(err) {
+		return nil
+	}
+	return errors.Trace(err)
+}
+
+func (k *kubernetesClient) ensureNamespace() error {
+	namespaces := k.CoreV1().Namespaces()
+	ns := &v1.Namespace{
+		ObjectMeta: v1.ObjectMeta{
+			Name:   k.namespace,
+			Labels: map[string]string{labelModel: k.modelConfig.UUID()},
+		},
+	}
+	existing, err := namespaces.Get(k.namespace)
+	if k8serrors.IsNotFound(err) {
+		_, err = namespaces.Create(ns)
+		return errors.Trace(err)
+	}
+	if err != nil {
+		return errors.Trace(err)
+	}
+	// Refuse to adopt a namespace belonging to another model
+	// since the namespace is deleted along with the model.
+	if uuid := existing.Labels[labelModel]; uuid != k.modelConfig.UUID() {
+		return errors.AlreadyExistsf("namespace %q", k.namespace)
+	}
+	return nil
+}
+
+func (k *kubernetesClient) ensureResourceQuota(hard v1.ResourceList) error {
+	quotas := k.CoreV1().ResourceQuotas(k.namespace)
+	if len(hard) == 0 {
+		err := quotas.Delete(resourceQuotaName, &v1.DeleteOptions{})
+		if k8serrors.IsNotFound(err) {
+			return nil
+		}
+		return errors.Trace(err)
+	}
+	quota := &v1.ResourceQuota{
+		ObjectMeta: v1.ObjectMeta{Name: resourceQuotaName},
+		Spec:       v1.ResourceQuotaSpec{Hard: hard},
+	}
+	_, err := quotas.Update(quota)
+	if k8serrors.IsNotFound(err) {
+		_, err = quotas.Create(quota)
+	}
+	return errors.Trace(err)
+}
+
+// resourceQuota returns the hard resource limits for the
+// model's namespace, as defined in model config.
+func (k *kubernetesClient) resourceQuota() (v1.ResourceList, error) {
+	coerced, err := schema.FieldMap(quotaConfigFields, quotaConfigDefaults).Coerce(
+		k.modelConfig.UnknownAttrs(), nil,
+	)
+	if err != nil {
+		return nil, errors.Annotate(err, "validating namespace quota config")
+	}
+	attrs := coerced.(map[string]interface{})
+	hard := v1.ResourceList{}
+	for key, name := range map[string]v1.ResourceName{
+		namespaceCPUQuotaKey:    v1.ResourceLimitsCPU,
+		namespaceMemoryQuotaKey: v1.ResourceLimitsMemory,
+		namespacePodsQuotaKey:   v1.ResourcePods,
+	} {
+		value, ok := attrs[key].(string)
+		if !ok || value == "" {
+			continue
+		}
+		quantity, err := resource.ParseQuantity(value)
+		if err != nil {
+			return nil, errors.Annotatef(err, "invalid %s %q", key, value)
+		}
+		hard[name] = quantity
+	}
+	return hard, nil
+}
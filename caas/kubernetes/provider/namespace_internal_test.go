@@ -0,0 +1,115 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package provider
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	k8serrors "k8s.io/client-go/pkg/api/errors"
+	"k8s.io/client-go/pkg/api/v1"
+
+	"github.com/juju/juju/environs"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type namespaceSuite struct {
+	baseSuite
+}
+
+var _ = gc.Suite(&namespaceSuite{})
+
+func (s *namespaceSuite) createNamespace(c *gc.C, modelUUID string) {
+	_, err := s.clientset.CoreV1().Namespaces().Create(&v1.Namespace{
+		ObjectMeta: v1.ObjectMeta{
+			Name:   s.client.namespace,
+			Labels: map[string]string{labelModel: modelUUID},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *namespaceSuite) TestCreate(c *gc.C) {
+	err := s.client.Create(environs.CreateParams{})
+	c.Assert(err, jc.ErrorIsNil)
+
+	ns, err := s.clientset.CoreV1().Namespaces().Get(s.client.namespace)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(ns.Labels, jc.DeepEquals, map[string]string{labelModel: s.client.modelConfig.UUID()})
+	_, err = s.clientset.CoreV1().ResourceQuotas(s.client.namespace).Get(resourceQuotaName)
+	c.Assert(k8serrors.IsNotFound(err), jc.IsTrue)
+}
+
+func (s *namespaceSuite) TestCreateExistingNamespace(c *gc.C) {
+	s.createNamespace(c, s.client.modelConfig.UUID())
+	err := s.client.Create(environs.CreateParams{})
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *namespaceSuite) TestCreateNamespaceOfAnotherModel(c *gc.C) {
+	s.createNamespace(c, "another-model")
+	err := s.client.Create(environs.CreateParams{})
+	c.Assert(err, gc.ErrorMatches, `creating namespace "testenv": namespace "testenv" already exists`)
+	c.Assert(errors.Cause(err), jc.Satisfies, errors.IsAlreadyExists)
+}
+
+func (s *namespaceSuite) TestCreateWithResourceQuota(c *gc.C) {
+	s.newClient(coretesting.CustomModelConfig(c, coretesting.Attrs{
+		"namespace-cpu-quota":    "2",
+		"namespace-memory-quota": "4Gi",
+		"namespace-pods-quota":   "10",
+	}))
+	err := s.client.Create(environs.CreateParams{})
+	c.Assert(err, jc.ErrorIsNil)
+
+	quota, err := s.clientset.CoreV1().ResourceQuotas(s.client.namespace).Get(resourceQuotaName)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(quota.Spec.Hard, gc.HasLen, 3)
+	for name, expect := range map[v1.ResourceName]string{
+		v1.ResourceLimitsCPU:    "2",
+		v1.ResourceLimitsMemory: "4Gi",
+		v1.ResourcePods:         "10",
+	} {
+		quantity := quota.Spec.Hard[name]
+		c.Check(quantity.String(), gc.Equals, expect, gc.Commentf("%s", name))
+	}
+}
+
+func (s *namespaceSuite) TestCreateRemovesResourceQuota(c *gc.C) {
+	_, err := s.clientset.CoreV1().ResourceQuotas(s.client.namespace).Create(&v1.ResourceQuota{
+		ObjectMeta: v1.ObjectMeta{Name: resourceQuotaName},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.client.Create(environs.CreateParams{})
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.clientset.CoreV1().ResourceQuotas(s.client.namespace).Get(resourceQuotaName)
+	c.Assert(k8serrors.IsNotFound(err), jc.IsTrue)
+}
+
+func (s *namespaceSuite) TestCreateInvalidResourceQuota(c *gc.C) {
+	s.newClient(coretesting.CustomModelConfig(c, coretesting.Attrs{
+		"namespace-cpu-quota": "lots",
+	}))
+	err := s.client.Create(environs.CreateParams{})
+	c.Assert(err, gc.ErrorMatches, `invalid namespace-cpu-quota "lots": .*`)
+
+	// Nothing is created if the quota is not valid.
+	_, err = s.clientset.CoreV1().Namespaces().Get(s.client.namespace)
+	c.Assert(k8serrors.IsNotFound(err), jc.IsTrue)
+}
+
+func (s *namespaceSuite) TestDestroyNamespaceNotFound(c *gc.C) {
+	err := s.client.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *namespaceSuite) TestDestroyNamespaceOfAnotherModel(c *gc.C) {
+	s.createNamespace(c, "another-model")
+	err := s.client.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = s.clientset.CoreV1().Namespaces().Get(s.client.namespace)
+	c.Assert(err, jc.ErrorIsNil)
+}
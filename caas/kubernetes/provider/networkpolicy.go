@@ -0,0 +1,189 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package provider
+
+import (
+	"encoding/json"
+	"net"
+	"strings"
+
+	"github.com/juju/errors"
+	k8serrors "k8s.io/client-go/pkg/api/errors"
+	"k8s.io/client-go/pkg/api/unversioned"
+	"k8s.io/client-go/pkg/api/v1"
+	"k8s.io/client-go/pkg/util/intstr"
+
+	"github.com/juju/juju/core/application"
+)
+
+// The client library we use predates the networking.k8s.io/v1 API,
+// which is needed to restrict ingress by CIDR, so network policies
+// are managed with plain REST requests using the types below.
+const (
+	networkPolicyAPIVersion = "networking.k8s.io/v1"
+	networkPolicyKind       = "NetworkPolicy"
+	defaultIngressCIDR      = "0.0.0.0/0"
+)
+
+type networkPolicy struct {
+	APIVersion string            `json:"apiVersion"`
+	Kind       string            `json:"kind"`
+	Metadata   v1.ObjectMeta     `json:"metadata"`
+	Spec       networkPolicySpec `json:"spec"`
+}
+
+type networkPolicySpec struct {
+	PodSelector unversioned.LabelSelector  `json:"podSelector"`
+	Ingress     []networkPolicyIngressRule `json:"ingress"`
+}
+
+type networkPolicyIngressRule struct {
+	Ports []networkPolicyPort `json:"ports,omitempty"`
+	From  []networkPolicyPeer `json:"from,omitempty"`
+}
+
+type networkPolicyPort struct {
+	Protocol v1.Protocol         `json:"protocol,omitempty"`
+	Port     *intstr.IntOrString `json:"port,omitempty"`
+}
+
+type networkPolicyPeer struct {
+	PodSelector *unversioned.LabelSelector `json:"podSelector,omitempty"`
+	IPBlock     *networkPolicyIPBlock      `json:"ipBlock,omitempty"`
+}
+
+type networkPolicyIPBlock struct {
+	CIDR string `json:"cidr"`
+}
+
+// configureNetworkPolicy creates or updates the network policy for an
+// exposed application if the application config asks for one, and
+// deletes any existing policy otherwise. The policy admits traffic
+// to the service ports from the configured CIDRs, and any traffic
+// from other pods in the model, mirroring the ports opened by the
+// firewaller for exposed applications on other clouds.
+func (k *kubernetesClient) configureNetworkPolicy(appName string, svc *v1.Service, config application.ConfigAttributes) error {
+	if !config.GetBool(networkPolicyKey, defaultNetworkPolicy) {
+		return errors.Trace(k.deleteNetworkPolicy(appName))
+	}
+	cidrs, err := ingressCIDRs(config)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	var ports []networkPolicyPort
+	for _, p := range svc.Spec.Ports {
+		// The target port defaults to the service port.
+		port := p.TargetPort
+		if port.Type == intstr.Int && port.IntVal == 0 {
+			port = intstr.FromInt(int(p.Port))
+		}
+		ports = append(ports, networkPolicyPort{Protocol: p.Protocol, Port: &port})
+	}
+	var from []networkPolicyPeer
+	for _, cidr := range cidrs {
+		from = append(from, networkPolicyPeer{IPBlock: &networkPolicyIPBlock{CIDR: cidr}})
+	}
+	spec := modelOnlyNetworkPolicy(appName)
+	spec.Spec.Ingress = append(spec.Spec.Ingress, networkPolicyIngressRule{
+		Ports: ports,
+		From:  from,
+	})
+	return errors.Trace(k.ensureNetworkPolicy(spec))
+}
+
+// restrictNetworkPolicy replaces any existing network policy for the
+// specified application with one which only admits traffic from other
+// pods in the model. If there is no policy, the application's config
+// did not ask for one and there is nothing to do.
+func (k *kubernetesClient) restrictNetworkPolicy(appName string) error {
+	_, err := k.getNetworkPolicy(deploymentName(appName))
+	if k8serrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(k.ensureNetworkPolicy(modelOnlyNetworkPolicy(appName)))
+}
+
+func ingressCIDRs(config application.ConfigAttributes) ([]string, error) {
+	var cidrs []string
+	for _, cidr := range strings.Split(config.GetString(networkPolicyCIDRsKey, ""), ",") {
+		if cidr = strings.TrimSpace(cidr); cidr == "" {
+			continue
+		}
+		if _, _, err := net.ParseCIDR(cidr); err != nil {
+			return nil, errors.NotValidf("%s CIDR %q", networkPolicyCIDRsKey, cidr)
+		}
+		cidrs = append(cidrs, cidr)
+	}
+	if len(cidrs) == 0 {
+		cidrs = []string{defaultIngressCIDR}
+	}
+	return cidrs, nil
+}
+
+func modelOnlyNetworkPolicy(appName string) *networkPolicy {
+	return &networkPolicy{
+		APIVersion: networkPolicyAPIVersion,
+		Kind:       networkPolicyKind,
+		Metadata: v1.ObjectMeta{
+			Name:   deploymentName(appName),
+			Labels: map[string]string{labelApplication: appName},
+		},
+		Spec: networkPolicySpec{
+			PodSelector: unversioned.LabelSelector{
+				MatchLabels: map[string]string{labelApplication: appName},
+			},
+			// An empty pod selector matches all pods in the namespace.
+			Ingress: []networkPolicyIngressRule{{
+				From: []networkPolicyPeer{{PodSelector: &unversioned.LabelSelector{}}},
+			}},
+		},
+	}
+}
+
+func (k *kubernetesClient) networkPoliciesPath() string {
+	return "/apis/" + networkPolicyAPIVersion + "/namespaces/" + k.namespace + "/networkpolicies"
+}
+
+func (k *kubernetesClient) ensureNetworkPolicy(spec *networkPolicy) error {
+	existing, err := k.getNetworkPolicy(spec.Metadata.Name)
+	if k8serrors.IsNotFound(err) {
+		return errors.Trace(k.sendNetworkPolicy("POST", k.networkPoliciesPath(), spec))
+	}
+	if err != nil {
+		return errors.Trace(err)
+	}
+	spec.Metadata.ResourceVersion = existing.Metadata.ResourceVersion
+	return errors.Trace(k.sendNetworkPolicy("PUT", k.networkPoliciesPath()+"/"+spec.Metadata.Name, spec))
+}
+
+func (k *kubernetesClient) getNetworkPolicy(name string) (*networkPolicy, error) {
+	data, err := k.CoreV1().RESTClient().Get().AbsPath(k.networkPoliciesPath(), name).Do().Raw()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var policy networkPolicy
+	if err := json.Unmarshal(data, &policy); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &policy, nil
+}
+
+func (k *kubernetesClient) sendNetworkPolicy(verb, path string, spec *networkPolicy) error {
+	body, err := json.Marshal(spec)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return k.CoreV1().RESTClient().Verb(verb).AbsPath(path).Body(body).Do().Error()
+}
+
+func (k *kubernetesClient) deleteNetworkPolicy(appName string) error {
+	err := k.CoreV1().RESTClient().Delete().AbsPath(k.networkPoliciesPath(), deploymentName(appName)).Do().Error()
+	if k8serrors.IsNotFound(err) {
+		return nil
+	}
+	return errors.Trace(err)
+}
@@ -0,0 +1,44 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package provider
+
+import (
+	stdtesting "testing"
+
+	"github.com/juju/testing"
+	gc "gopkg.in/check.v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/juju/juju/environs/config"
+	coretesting "github.com/juju/juju/testing"
+)
+
+func Test(t *stdtesting.T) {
+	gc.TestingT(t)
+}
+
+// baseSuite provides a kubernetesClient backed by a fake
+// clientset, which keeps the resources it is sent in memory.
+type baseSuite struct {
+	testing.IsolationSuite
+
+	clientset *fake.Clientset
+	client    *kubernetesClient
+}
+
+func (s *baseSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.newClient(coretesting.ModelConfig(c))
+}
+
+// newClient replaces the suite's client with one
+// for a model with the specified config.
+func (s *baseSuite) newClient(cfg *config.Config) {
+	s.clientset = fake.NewSimpleClientset()
+	s.client = &kubernetesClient{
+		Interface:   s.clientset,
+		namespace:   cfg.Name(),
+		modelConfig: cfg,
+	}
+}
@@ -0,0 +1,179 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package provider
+
+import (
+	"strings"
+
+	"github.com/juju/errors"
+	"k8s.io/client-go/pkg/api/unversioned"
+	"k8s.io/client-go/pkg/api/v1"
+
+	"github.com/juju/juju/constraints"
+)
+
+const (
+	affinityAnnotation    = "scheduler.alpha.kubernetes.io/affinity"
+	tolerationsAnnotation = "scheduler.alpha.kubernetes.io/tolerations"
+
+	// spreadPlacementKey is the placement directive key used
+	// to spread an application's pods across nodes or zones.
+	spreadPlacementKey = "spread"
+)
+
+// spreadTopologyKeys maps the values of the spread placement
+// directive to the node labels identifying the domains that
+// pods are spread across.
+var spreadTopologyKeys = map[string]string{
+	"node": "kubernetes.io/hostname",
+	"zone": "failure-domain.beta.kubernetes.io/zone",
+}
+
+// applyPlacement restricts the nodes on which the pods of the
+// application may be scheduled according to the specified placement
+// directive and constraint tags.
+//
+// The placement directive is a comma separated list of node
+// label key=value pairs, all of which a node must have. Nodes
+// dedicated to a workload are typically tainted with the same
+// key and value used to label them, so the pod also tolerates
+// taints matching each of the labels. The directive may also
+// contain "spread=node" or "spread=zone", asking that the
+// application's pods be scheduled on different nodes or in
+// different zones where possible.
+//
+// Each constraint tag is a node label key, or key=value pair,
+// which the node must have. A tag prefixed with "^" is a label
+// the node must not have.
+func applyPlacement(unitSpec *unitSpec, appName, placement string, cons constraints.Value) error {
+	nodeSelector, spread, err := parsePlacement(placement)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(spread) > 0 {
+		unitSpec.Affinity = &v1.Affinity{
+			PodAntiAffinity: podAntiAffinity(appName, spread),
+		}
+	}
+	for key, value := range nodeSelector {
+		if unitSpec.Pod.NodeSelector == nil {
+			unitSpec.Pod.NodeSelector = make(map[string]string)
+		}
+		unitSpec.Pod.NodeSelector[key] = value
+		unitSpec.Tolerations = append(unitSpec.Tolerations, v1.Toleration{
+			Key:      key,
+			Operator: v1.TolerationOpEqual,
+			Value:    value,
+		})
+	}
+
+	if !cons.HasTags() {
+		return nil
+	}
+	var requirements []v1.NodeSelectorRequirement
+	for _, tag := range *cons.Tags {
+		requirement, err := nodeSelectorRequirement(tag)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		requirements = append(requirements, requirement)
+	}
+	if unitSpec.Affinity == nil {
+		unitSpec.Affinity = &v1.Affinity{}
+	}
+	unitSpec.Affinity.NodeAffinity = &v1.NodeAffinity{
+		RequiredDuringSchedulingIgnoredDuringExecution: &v1.NodeSelector{
+			NodeSelectorTerms: []v1.NodeSelectorTerm{{
+				MatchExpressions: requirements,
+			}},
+		},
+	}
+	return nil
+}
+
+// parsePlacement parses a placement directive of
+// the form "key=value[,key=value...]" into a node
+// selector and the topology keys of the domains the
+// pods are to be spread across.
+func parsePlacement(placement string) (map[string]string, []string, error) {
+	if placement == "" {
+		return nil, nil, nil
+	}
+	nodeSelector := make(map[string]string)
+	var spread []string
+	for _, item := range strings.Split(placement, ",") {
+		key, value, ok := splitLabel(item)
+		if !ok || value == "" {
+			return nil, nil, errors.NotValidf("placement directive %q", placement)
+		}
+		if key != spreadPlacementKey {
+			nodeSelector[key] = value
+			continue
+		}
+		topologyKey, ok := spreadTopologyKeys[value]
+		if !ok {
+			return nil, nil, errors.NotValidf("spread %q in placement directive %q", value, placement)
+		}
+		spread = append(spread, topologyKey)
+	}
+	return nodeSelector, spread, nil
+}
+
+// podAntiAffinity returns the anti-affinity which prefers
+// that the application's pods are not scheduled in the same
+// domain, for each of the specified topology keys.
+func podAntiAffinity(appName string, topologyKeys []string) *v1.PodAntiAffinity {
+	terms := make([]v1.WeightedPodAffinityTerm, len(topologyKeys))
+	for i, topologyKey := range topologyKeys {
+		terms[i] = v1.WeightedPodAffinityTerm{
+			Weight: 100,
+			PodAffinityTerm: v1.PodAffinityTerm{
+				LabelSelector: &unversioned.LabelSelector{
+					MatchLabels: map[string]string{labelApplication: appName},
+				},
+				TopologyKey: topologyKey,
+			},
+		}
+	}
+	return &v1.PodAntiAffinity{
+		PreferredDuringSchedulingIgnoredDuringExecution: terms,
+	}
+}
+
+// nodeSelectorRequirement returns the node selector
+// requirement corresponding to the specified
+// constraint tag.
+func nodeSelectorRequirement(tag string) (v1.NodeSelectorRequirement, error) {
+	negated := strings.HasPrefix(tag, "^")
+	key, value, ok := splitLabel(strings.TrimPrefix(tag, "^"))
+	if !ok {
+		return v1.NodeSelectorRequirement{}, errors.NotValidf("tag %q", tag)
+	}
+	requirement := v1.NodeSelectorRequirement{Key: key}
+	switch {
+	case value == "" && negated:
+		requirement.Operator = v1.NodeSelectorOpDoesNotExist
+	case value == "":
+		requirement.Operator = v1.NodeSelectorOpExists
+	case negated:
+		requirement.Operator = v1.NodeSelectorOpNotIn
+		requirement.Values = []string{value}
+	default:
+		requirement.Operator = v1.NodeSelectorOpIn
+		requirement.Values = []string{value}
+	}
+	return requirement, nil
+}
+
+// splitLabel splits a node label of the form "key[=value]"
+// into its key and value, reporting whether the key is
+// non-empty.
+func splitLabel(label string) (key, value string, ok bool) {
+	key = label
+	if i := strings.Index(label, "="); i >= 0 {
+		key, value = label[:i], label[i+1:]
+	}
+	key = strings.TrimSpace(key)
+	return key, strings.TrimSpace(value), key != ""
+}
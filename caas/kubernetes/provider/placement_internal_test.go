@@ -0,0 +1,180 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package provider
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"k8s.io/client-go/pkg/api/unversioned"
+	"k8s.io/client-go/pkg/api/v1"
+
+	"github.com/juju/juju/constraints"
+)
+
+type placementSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&placementSuite{})
+
+func (s *placementSuite) TestParsePlacement(c *gc.C) {
+	for i, test := range []struct {
+		placement    string
+		nodeSelector map[string]string
+		spread       []string
+	}{{
+		placement: "",
+	}, {
+		placement:    "disktype=ssd",
+		nodeSelector: map[string]string{"disktype": "ssd"},
+	}, {
+		placement:    " disktype = ssd ,gpu=nvidia",
+		nodeSelector: map[string]string{"disktype": "ssd", "gpu": "nvidia"},
+	}, {
+		placement:    "spread=node",
+		nodeSelector: map[string]string{},
+		spread:       []string{"kubernetes.io/hostname"},
+	}, {
+		placement:    "disktype=ssd,spread=zone",
+		nodeSelector: map[string]string{"disktype": "ssd"},
+		spread:       []string{"failure-domain.beta.kubernetes.io/zone"},
+	}} {
+		c.Logf("test %d: %q", i, test.placement)
+		nodeSelector, spread, err := parsePlacement(test.placement)
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(nodeSelector, jc.DeepEquals, test.nodeSelector)
+		c.Check(spread, jc.DeepEquals, test.spread)
+	}
+}
+
+func (s *placementSuite) TestParsePlacementInvalid(c *gc.C) {
+	for i, test := range []struct {
+		placement string
+		err       string
+	}{{
+		placement: "disktype",
+		err:       `placement directive "disktype" not valid`,
+	}, {
+		placement: "disktype=",
+		err:       `placement directive "disktype=" not valid`,
+	}, {
+		placement: "=ssd",
+		err:       `placement directive "=ssd" not valid`,
+	}, {
+		placement: "disktype=ssd,",
+		err:       `placement directive "disktype=ssd," not valid`,
+	}, {
+		placement: "spread=rack",
+		err:       `spread "rack" in placement directive "spread=rack" not valid`,
+	}} {
+		c.Logf("test %d: %q", i, test.placement)
+		_, _, err := parsePlacement(test.placement)
+		c.Check(err, gc.ErrorMatches, test.err)
+		c.Check(err, jc.Satisfies, errors.IsNotValid)
+	}
+}
+
+func (s *placementSuite) TestNodeSelectorRequirement(c *gc.C) {
+	for i, test := range []struct {
+		tag         string
+		requirement v1.NodeSelectorRequirement
+	}{{
+		tag: "gpu",
+		requirement: v1.NodeSelectorRequirement{
+			Key:      "gpu",
+			Operator: v1.NodeSelectorOpExists,
+		},
+	}, {
+		tag: "^gpu",
+		requirement: v1.NodeSelectorRequirement{
+			Key:      "gpu",
+			Operator: v1.NodeSelectorOpDoesNotExist,
+		},
+	}, {
+		tag: "disktype=ssd",
+		requirement: v1.NodeSelectorRequirement{
+			Key:      "disktype",
+			Operator: v1.NodeSelectorOpIn,
+			Values:   []string{"ssd"},
+		},
+	}, {
+		tag: "^disktype=ssd",
+		requirement: v1.NodeSelectorRequirement{
+			Key:      "disktype",
+			Operator: v1.NodeSelectorOpNotIn,
+			Values:   []string{"ssd"},
+		},
+	}} {
+		c.Logf("test %d: %q", i, test.tag)
+		requirement, err := nodeSelectorRequirement(test.tag)
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(requirement, jc.DeepEquals, test.requirement)
+	}
+}
+
+func (s *placementSuite) TestNodeSelectorRequirementInvalid(c *gc.C) {
+	for _, tag := range []string{"", "^", "=ssd", "^=ssd"} {
+		_, err := nodeSelectorRequirement(tag)
+		c.Check(err, gc.ErrorMatches, `tag ".*" not valid`)
+		c.Check(err, jc.Satisfies, errors.IsNotValid)
+	}
+}
+
+func (s *placementSuite) TestApplyPlacement(c *gc.C) {
+	var spec unitSpec
+	err := applyPlacement(&spec, "gitlab", "disktype=ssd,spread=zone", constraints.MustParse("tags=gpu,^spot=true"))
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Assert(spec.Pod.NodeSelector, jc.DeepEquals, map[string]string{"disktype": "ssd"})
+	c.Assert(spec.Tolerations, jc.DeepEquals, []v1.Toleration{{
+		Key:      "disktype",
+		Operator: v1.TolerationOpEqual,
+		Value:    "ssd",
+	}})
+	c.Assert(spec.Affinity, jc.DeepEquals, &v1.Affinity{
+		PodAntiAffinity: &v1.PodAntiAffinity{
+			PreferredDuringSchedulingIgnoredDuringExecution: []v1.WeightedPodAffinityTerm{{
+				Weight: 100,
+				PodAffinityTerm: v1.PodAffinityTerm{
+					LabelSelector: &unversioned.LabelSelector{
+						MatchLabels: map[string]string{labelApplication: "gitlab"},
+					},
+					TopologyKey: "failure-domain.beta.kubernetes.io/zone",
+				},
+			}},
+		},
+		NodeAffinity: &v1.NodeAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: &v1.NodeSelector{
+				NodeSelectorTerms: []v1.NodeSelectorTerm{{
+					MatchExpressions: []v1.NodeSelectorRequirement{{
+						Key:      "gpu",
+						Operator: v1.NodeSelectorOpExists,
+					}, {
+						Key:      "spot",
+						Operator: v1.NodeSelectorOpNotIn,
+						Values:   []string{"true"},
+					}},
+				}},
+			},
+		},
+	})
+}
+
+func (s *placementSuite) TestApplyPlacementNone(c *gc.C) {
+	var spec unitSpec
+	err := applyPlacement(&spec, "gitlab", "", constraints.Value{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(spec, jc.DeepEquals, unitSpec{})
+}
+
+func (s *placementSuite) TestApplyPlacementInvalid(c *gc.C) {
+	var spec unitSpec
+	err := applyPlacement(&spec, "gitlab", "disktype", constraints.Value{})
+	c.Assert(err, gc.ErrorMatches, `placement directive "disktype" not valid`)
+
+	err = applyPlacement(&spec, "gitlab", "", constraints.MustParse("tags=^"))
+	c.Assert(err, gc.ErrorMatches, `tag "\^" not valid`)
+}
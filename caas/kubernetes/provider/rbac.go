@@ -0,0 +1,150 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package provider
+
+import (
+	"reflect"
+
+	"github.com/juju/errors"
+	k8serrors "k8s.io/client-go/pkg/api/errors"
+	"k8s.io/client-go/pkg/api/v1"
+	rbac "k8s.io/client-go/pkg/apis/rbac/v1beta1"
+
+	"github.com/juju/juju/caas"
+)
+
+// operatorRules are the rules granted to every operator. They
+// only allow the operator to inspect its application's pods
+// and config maps, in the model's namespace.
+var operatorRules = []rbac.PolicyRule{{
+	APIGroups: []string{""},
+	Resources: []string{"pods"},
+	Verbs:     []string{"get", "list", "watch"},
+}, {
+	APIGroups: []string{""},
+	Resources: []string{"configmaps"},
+	Verbs:     []string{"get"},
+}}
+
+// ensureOperatorAccount creates or updates the service account as which
+// the operator of the specified application runs, along with the role
+// and role binding which grant it access to the model's namespace.
+// Any rules already granted to the operator, as requested by its charm,
+// are preserved.
+func (k *kubernetesClient) ensureOperatorAccount(appName string) error {
+	name := operatorAccountName(appName)
+	labels := map[string]string{labelOperator: appName}
+
+	accounts := k.CoreV1().ServiceAccounts(k.namespace)
+	_, err := accounts.Get(name)
+	if k8serrors.IsNotFound(err) {
+		_, err = accounts.Create(&v1.ServiceAccount{
+			ObjectMeta: v1.ObjectMeta{Name: name, Labels: labels},
+		})
+	}
+	if err != nil {
+		return errors.Annotate(err, "creating service account")
+	}
+
+	roles := k.RbacV1beta1().Roles(k.namespace)
+	role, err := roles.Get(name)
+	if k8serrors.IsNotFound(err) {
+		_, err = roles.Create(&rbac.Role{
+			ObjectMeta: v1.ObjectMeta{Name: name, Labels: labels},
+			Rules:      operatorRules,
+		})
+	} else if err == nil {
+		rules := mergeRules(operatorRules, role.Rules)
+		if !reflect.DeepEqual(rules, role.Rules) {
+			role.Rules = rules
+			_, err = roles.Update(role)
+		}
+	}
+	if err != nil {
+		return errors.Annotate(err, "creating or updating role")
+	}
+
+	bindings := k.RbacV1beta1().RoleBindings(k.namespace)
+	_, err = bindings.Get(name)
+	if k8serrors.IsNotFound(err) {
+		_, err = bindings.Create(&rbac.RoleBinding{
+			ObjectMeta: v1.ObjectMeta{Name: name, Labels: labels},
+			Subjects: []rbac.Subject{{
+				Kind:      rbac.ServiceAccountKind,
+				Name:      name,
+				Namespace: k.namespace,
+			}},
+			RoleRef: rbac.RoleRef{
+				APIGroup: rbac.GroupName,
+				Kind:     "Role",
+				Name:     name,
+			},
+		})
+	}
+	return errors.Annotate(err, "creating role binding")
+}
+
+// ensureOperatorRules updates the role of the specified application's
+// operator so that it grants the rules requested by the charm, in
+// addition to those granted to every operator. The requested rules
+// are only granted to trusted applications; the operators of other
+// applications have any rules previously granted to them revoked.
+func (k *kubernetesClient) ensureOperatorRules(appName string, requested []caas.RBACRule, trusted bool) error {
+	if err := k.ensureOperatorAccount(appName); err != nil {
+		return errors.Trace(err)
+	}
+	if !trusted && len(requested) > 0 {
+		logger.Warningf("application %s is not trusted, not granting the operator rules requested by its charm", appName)
+		requested = nil
+	}
+	rules := append([]rbac.PolicyRule(nil), operatorRules...)
+	for _, r := range requested {
+		apiGroups := r.APIGroups
+		if len(apiGroups) == 0 {
+			apiGroups = []string{""}
+		}
+		rules = append(rules, rbac.PolicyRule{
+			APIGroups:     apiGroups,
+			Resources:     r.Resources,
+			ResourceNames: r.ResourceNames,
+			Verbs:         r.Verbs,
+		})
+	}
+
+	roles := k.RbacV1beta1().Roles(k.namespace)
+	role, err := roles.Get(operatorAccountName(appName))
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if reflect.DeepEqual(rules, role.Rules) {
+		return nil
+	}
+	logger.Debugf("updating %s operator rules", appName)
+	role.Rules = rules
+	_, err = roles.Update(role)
+	return errors.Trace(err)
+}
+
+// mergeRules returns the existing rules, preceded
+// by any of the required rules which are missing.
+func mergeRules(required, existing []rbac.PolicyRule) []rbac.PolicyRule {
+	var result []rbac.PolicyRule
+	for _, r := range required {
+		found := false
+		for _, e := range existing {
+			if reflect.DeepEqual(r, e) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			result = append(result, r)
+		}
+	}
+	return append(result, existing...)
+}
+
+func operatorAccountName(appName string) string {
+	return "juju-operator-" + appName
+}
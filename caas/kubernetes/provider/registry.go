@@ -0,0 +1,138 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package provider
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/juju/errors"
+	k8serrors "k8s.io/client-go/pkg/api/errors"
+	"k8s.io/client-go/pkg/api/v1"
+
+	"github.com/juju/juju/caas"
+)
+
+// defaultRegistry is the registry from which images
+// are pulled when the image name doesn't specify one.
+const defaultRegistry = "https://index.docker.io/v1/"
+
+// dockerConfigEntry holds the credentials for
+// a registry, in the form used in a .dockercfg file.
+type dockerConfigEntry struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+	Email    string `json:"email"`
+	Auth     string `json:"auth"`
+}
+
+// configureImagePullSecrets ensures there is a secret holding the
+// registry credentials of each container in the spec which has them,
+// and has the pods use those secrets, along with any existing secret
+// named in the params, to pull their images. Secrets for containers
+// no longer having credentials are removed.
+func (k *kubernetesClient) configureImagePullSecrets(
+	unitSpec *unitSpec, ownerName string, labels map[string]string, params *caas.ServiceParams,
+) error {
+	podSpec := &unitSpec.Pod
+	wanted := make(map[string]bool)
+	var containers []caas.ContainerSpec
+	containers = append(containers, params.PodSpec.Containers...)
+	containers = append(containers, params.PodSpec.InitContainers...)
+	for _, c := range containers {
+		if c.ImageCredentials == nil {
+			continue
+		}
+		secret, err := imagePullSecret(imagePullSecretName(ownerName, c.Name), labels, c.ImageName, c.ImageCredentials)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if err := k.ensureSecret(secret); err != nil {
+			return errors.Annotatef(err, "creating image pull secret for %q", c.Name)
+		}
+		wanted[secret.Name] = true
+		podSpec.ImagePullSecrets = append(podSpec.ImagePullSecrets, v1.LocalObjectReference{Name: secret.Name})
+	}
+	if params.ImagePullSecret != "" {
+		podSpec.ImagePullSecrets = append(podSpec.ImagePullSecrets, v1.LocalObjectReference{Name: params.ImagePullSecret})
+	}
+	return errors.Trace(k.deleteSecrets(labels, imagePullSecretName(ownerName, ""), wanted))
+}
+
+// imagePullSecret returns a secret holding the credentials
+// used to pull the specified image from its registry.
+func imagePullSecret(name string, labels map[string]string, imageName string, creds *caas.ImageCredentials) (*v1.Secret, error) {
+	registry := creds.Registry
+	if registry == "" {
+		registry = imageRegistry(imageName)
+	}
+	auth := base64.StdEncoding.EncodeToString([]byte(creds.Username + ":" + creds.Password))
+	data, err := json.Marshal(map[string]dockerConfigEntry{
+		registry: {
+			Username: creds.Username,
+			Password: creds.Password,
+			Auth:     auth,
+		},
+	})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &v1.Secret{
+		ObjectMeta: v1.ObjectMeta{
+			Name:   name,
+			Labels: labels,
+		},
+		Type: v1.SecretTypeDockercfg,
+		Data: map[string][]byte{
+			v1.DockerConfigKey: data,
+		},
+	}, nil
+}
+
+// imageRegistry returns the registry from which the specified
+// image is pulled. As with docker, the first component of the
+// image name is only a registry if it looks like a host name.
+func imageRegistry(imageName string) string {
+	parts := strings.SplitN(imageName, "/", 2)
+	if len(parts) == 1 {
+		return defaultRegistry
+	}
+	host := parts[0]
+	if host != "localhost" && !strings.ContainsAny(host, ".:") {
+		return defaultRegistry
+	}
+	return host
+}
+
+// deleteSecrets deletes the secrets with the specified labels
+// and name prefix, other than those in the keep set.
+func (k *kubernetesClient) deleteSecrets(labels map[string]string, namePrefix string, keep map[string]bool) error {
+	var selector []string
+	for key, value := range labels {
+		selector = append(selector, fmt.Sprintf("%v==%v", key, value))
+	}
+	secrets := k.CoreV1().Secrets(k.namespace)
+	existing, err := secrets.List(v1.ListOptions{
+		LabelSelector: strings.Join(selector, ","),
+	})
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for _, s := range existing.Items {
+		if keep[s.Name] || !strings.HasPrefix(s.Name, namePrefix) {
+			continue
+		}
+		err := secrets.Delete(s.Name, &v1.DeleteOptions{})
+		if err != nil && !k8serrors.IsNotFound(err) {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+func imagePullSecretName(ownerName, containerName string) string {
+	return ownerName + "-registry-" + containerName
+}
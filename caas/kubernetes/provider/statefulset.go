@@ -0,0 +1,118 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package provider
+
+import (
+	"github.com/juju/errors"
+	k8serrors "k8s.io/client-go/pkg/api/errors"
+	"k8s.io/client-go/pkg/api/unversioned"
+	"k8s.io/client-go/pkg/api/v1"
+	apps "k8s.io/client-go/pkg/apis/apps/v1beta1"
+)
+
+// configureStatefulSet creates or updates the stateful set controller
+// for a stateful application, along with the headless service which
+// gives each of its pods a stable DNS name of the form
+// <pod>.<service>.<namespace>.svc.
+func (k *kubernetesClient) configureStatefulSet(appName string, unitSpec *unitSpec, replicas *int32) error {
+	logger.Debugf("creating/updating stateful set for %s", appName)
+
+	if err := k.ensureService(headlessService(appName)); err != nil {
+		return errors.Annotate(err, "creating or updating headless service")
+	}
+	annotations, err := podAnnotations(unitSpec)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	appLabels := map[string]string{labelApplication: appName}
+	statefulSet := &apps.StatefulSet{
+		ObjectMeta: v1.ObjectMeta{
+			Name:   deploymentName(appName),
+			Labels: appLabels,
+		},
+		Spec: apps.StatefulSetSpec{
+			Replicas: replicas,
+			Selector: &unversioned.LabelSelector{
+				MatchLabels: appLabels,
+			},
+			Template: v1.PodTemplateSpec{
+				ObjectMeta: v1.ObjectMeta{
+					Labels:      podLabels(unitSpec, appLabels),
+					Annotations: annotations,
+				},
+				Spec: unitSpec.Pod,
+			},
+			VolumeClaimTemplates: unitSpec.VolumeClaimTemplates,
+			ServiceName:          headlessServiceName(appName),
+		},
+	}
+	return k.ensureStatefulSet(statefulSet)
+}
+
+func (k *kubernetesClient) ensureStatefulSet(spec *apps.StatefulSet) error {
+	statefulSets := k.AppsV1beta1().StatefulSets(k.namespace)
+	existing, err := statefulSets.Get(spec.Name)
+	if k8serrors.IsNotFound(err) {
+		_, err = statefulSets.Create(spec)
+		return errors.Trace(err)
+	}
+	if err != nil {
+		return errors.Trace(err)
+	}
+	// Clusters which support rolling updates of stateful sets
+	// replace the pods as the template changes. Older clusters
+	// only allow the number of replicas to be changed, so we
+	// fall back to scaling and report the template as unapplied
+	// rather than silently running the old pod spec.
+	existing.Spec.Replicas = spec.Spec.Replicas
+	template := existing.Spec.Template
+	existing.Spec.Template = spec.Spec.Template
+	_, err = statefulSets.Update(existing)
+	if !k8serrors.IsInvalid(err) {
+		return errors.Trace(err)
+	}
+	logger.Debugf("cannot update template of stateful set %q: %v", spec.Name, err)
+	existing.Spec.Template = template
+	if _, err := statefulSets.Update(existing); err != nil {
+		return errors.Trace(err)
+	}
+	return errors.NotSupportedf("updating the pod template of stateful set %q on this cluster", spec.Name)
+}
+
+func (k *kubernetesClient) deleteStatefulSet(appName string) error {
+	orphanDependents := false
+	statefulSets := k.AppsV1beta1().StatefulSets(k.namespace)
+	err := statefulSets.Delete(deploymentName(appName), &v1.DeleteOptions{OrphanDependents: &orphanDependents})
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return errors.Trace(err)
+	}
+	// The per-pod volume claims are deliberately left
+	// behind, so that storage outlives the pods.
+	services := k.CoreV1().Services(k.namespace)
+	err = services.Delete(headlessServiceName(appName), &v1.DeleteOptions{OrphanDependents: &orphanDependents})
+	if k8serrors.IsNotFound(err) {
+		return nil
+	}
+	return errors.Trace(err)
+}
+
+// headlessService returns the service which governs
+// the network identity of a stateful set's pods.
+func headlessService(appName string) *v1.Service {
+	appLabels := map[string]string{labelApplication: appName}
+	return &v1.Service{
+		ObjectMeta: v1.ObjectMeta{
+			Name:   headlessServiceName(appName),
+			Labels: appLabels,
+		},
+		Spec: v1.ServiceSpec{
+			Selector:  appLabels,
+			ClusterIP: v1.ClusterIPNone,
+		},
+	}
+}
+
+func headlessServiceName(appName string) string {
+	return deploymentName(appName) + "-endpoints"
+}
@@ -0,0 +1,134 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package provider
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	k8serrors "k8s.io/client-go/pkg/api/errors"
+	"k8s.io/client-go/pkg/api/unversioned"
+	"k8s.io/client-go/pkg/api/v1"
+	apps "k8s.io/client-go/pkg/apis/apps/v1beta1"
+	"k8s.io/client-go/pkg/runtime"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+type statefulSetSuite struct {
+	baseSuite
+}
+
+var _ = gc.Suite(&statefulSetSuite{})
+
+func newStatefulSet(replicas int32, image string) *apps.StatefulSet {
+	return &apps.StatefulSet{
+		ObjectMeta: v1.ObjectMeta{Name: "juju-gitlab"},
+		Spec: apps.StatefulSetSpec{
+			Replicas: &replicas,
+			Template: v1.PodTemplateSpec{
+				Spec: v1.PodSpec{
+					Containers: []v1.Container{{Name: "gitlab", Image: image}},
+				},
+			},
+		},
+	}
+}
+
+func (s *statefulSetSuite) getStatefulSet(c *gc.C) *apps.StatefulSet {
+	ss, err := s.clientset.AppsV1beta1().StatefulSets(s.client.namespace).Get("juju-gitlab")
+	c.Assert(err, jc.ErrorIsNil)
+	return ss
+}
+
+func (s *statefulSetSuite) TestHeadlessService(c *gc.C) {
+	service := headlessService("gitlab")
+	c.Assert(service.Name, gc.Equals, "juju-gitlab-endpoints")
+	c.Assert(service.Labels, jc.DeepEquals, map[string]string{labelApplication: "gitlab"})
+	c.Assert(service.Spec.Selector, jc.DeepEquals, map[string]string{labelApplication: "gitlab"})
+	c.Assert(service.Spec.ClusterIP, gc.Equals, v1.ClusterIPNone)
+}
+
+func (s *statefulSetSuite) TestConfigureStatefulSet(c *gc.C) {
+	replicas := int32(2)
+	unitSpec := &unitSpec{
+		Pod: v1.PodSpec{
+			Containers: []v1.Container{{Name: "gitlab", Image: "gitlab/latest"}},
+		},
+		Labels: map[string]string{"tier": "web"},
+		VolumeClaimTemplates: []v1.PersistentVolumeClaim{{
+			ObjectMeta: v1.ObjectMeta{Name: "database"},
+		}},
+	}
+	err := s.client.configureStatefulSet("gitlab", unitSpec, &replicas)
+	c.Assert(err, jc.ErrorIsNil)
+
+	service, err := s.clientset.CoreV1().Services(s.client.namespace).Get("juju-gitlab-endpoints")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(service.Spec.ClusterIP, gc.Equals, v1.ClusterIPNone)
+
+	ss := s.getStatefulSet(c)
+	c.Assert(*ss.Spec.Replicas, gc.Equals, int32(2))
+	c.Assert(ss.Spec.ServiceName, gc.Equals, "juju-gitlab-endpoints")
+	c.Assert(ss.Spec.Selector.MatchLabels, jc.DeepEquals, map[string]string{labelApplication: "gitlab"})
+	c.Assert(ss.Spec.Template.Labels, jc.DeepEquals, map[string]string{
+		labelApplication: "gitlab",
+		"tier":           "web",
+	})
+	c.Assert(ss.Spec.Template.Annotations, gc.HasLen, 0)
+	c.Assert(ss.Spec.Template.Spec, jc.DeepEquals, unitSpec.Pod)
+	c.Assert(ss.Spec.VolumeClaimTemplates, jc.DeepEquals, unitSpec.VolumeClaimTemplates)
+}
+
+func (s *statefulSetSuite) TestEnsureStatefulSetUpdates(c *gc.C) {
+	err := s.client.ensureStatefulSet(newStatefulSet(1, "gitlab/gitlab:1"))
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.client.ensureStatefulSet(newStatefulSet(3, "gitlab/gitlab:2"))
+	c.Assert(err, jc.ErrorIsNil)
+	ss := s.getStatefulSet(c)
+	c.Assert(*ss.Spec.Replicas, gc.Equals, int32(3))
+	c.Assert(ss.Spec.Template.Spec.Containers[0].Image, gc.Equals, "gitlab/gitlab:2")
+}
+
+func (s *statefulSetSuite) TestEnsureStatefulSetTemplateNotUpdatable(c *gc.C) {
+	err := s.client.ensureStatefulSet(newStatefulSet(1, "gitlab/gitlab:1"))
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Older clusters only allow the replicas to be updated.
+	s.clientset.PrependReactor("update", "statefulsets", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		ss := action.(k8stesting.UpdateAction).GetObject().(*apps.StatefulSet)
+		if ss.Spec.Template.Spec.Containers[0].Image == "gitlab/gitlab:1" {
+			return false, nil, nil
+		}
+		return true, nil, &k8serrors.StatusError{ErrStatus: unversioned.Status{
+			Reason:  unversioned.StatusReasonInvalid,
+			Message: "updates to statefulset spec for fields other than 'replicas' are forbidden",
+		}}
+	})
+
+	err = s.client.ensureStatefulSet(newStatefulSet(3, "gitlab/gitlab:2"))
+	c.Assert(err, gc.ErrorMatches, `updating the pod template of stateful set "juju-gitlab" on this cluster not supported`)
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+	ss := s.getStatefulSet(c)
+	c.Assert(*ss.Spec.Replicas, gc.Equals, int32(3))
+	c.Assert(ss.Spec.Template.Spec.Containers[0].Image, gc.Equals, "gitlab/gitlab:1")
+}
+
+func (s *statefulSetSuite) TestDeleteStatefulSet(c *gc.C) {
+	replicas := int32(1)
+	err := s.client.configureStatefulSet("gitlab", &unitSpec{}, &replicas)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.client.deleteStatefulSet("gitlab")
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.clientset.AppsV1beta1().StatefulSets(s.client.namespace).Get("juju-gitlab")
+	c.Assert(k8serrors.IsNotFound(err), jc.IsTrue)
+	_, err = s.clientset.CoreV1().Services(s.client.namespace).Get("juju-gitlab-endpoints")
+	c.Assert(k8serrors.IsNotFound(err), jc.IsTrue)
+}
+
+func (s *statefulSetSuite) TestDeleteStatefulSetNotFound(c *gc.C) {
+	err := s.client.deleteStatefulSet("gitlab")
+	c.Assert(err, jc.ErrorIsNil)
+}
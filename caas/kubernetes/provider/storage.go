@@ -0,0 +1,293 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/schema"
+	k8serrors "k8s.io/client-go/pkg/api/errors"
+	"k8s.io/client-go/pkg/api/v1"
+
+	"github.com/juju/juju/storage"
+)
+
+const (
+	// StorageProviderType is the type of the storage provider used
+	// to satisfy storage with kubernetes persistent volume claims.
+	StorageProviderType = storage.ProviderType("kubernetes")
+
+	// storageClassKey is the attribute name for the storage class
+	// from which volumes are provisioned. If a provisioner is also
+	// given, Juju manages the storage class; its name defaults to
+	// the model's namespace followed by the pool name.
+	storageClassKey = "storage-class"
+
+	// storageProvisionerKey is the attribute name for the
+	// provisioner of a Juju managed storage class.
+	storageProvisionerKey = "storage-provisioner"
+
+	// storageReclaimPolicyKey is the attribute name for the
+	// reclaim policy of a Juju managed storage class.
+	storageReclaimPolicyKey = "reclaim-policy"
+
+	// storageParametersPrefix prefixes the attribute names of
+	// the provisioner parameters of a Juju managed storage class.
+	storageParametersPrefix = "parameters."
+)
+
+// The client library we use predates storage class reclaim policies,
+// so storage classes are managed with plain REST requests using the
+// type below, which mirrors that of the storage.k8s.io/v1 API.
+const (
+	storageClassAPIVersion = "storage.k8s.io/v1"
+	storageClassKind       = "StorageClass"
+	storageClassPath       = "/apis/" + storageClassAPIVersion + "/storageclasses"
+)
+
+type storageClassSpec struct {
+	APIVersion    string            `json:"apiVersion"`
+	Kind          string            `json:"kind"`
+	Metadata      v1.ObjectMeta     `json:"metadata"`
+	Provisioner   string            `json:"provisioner"`
+	Parameters    map[string]string `json:"parameters,omitempty"`
+	ReclaimPolicy string            `json:"reclaimPolicy,omitempty"`
+}
+
+type storageClassSpecList struct {
+	Items []storageClassSpec `json:"items"`
+}
+
+// StorageProviderTypes is part of the storage.ProviderRegistry interface.
+func (k *kubernetesClient) StorageProviderTypes() ([]storage.ProviderType, error) {
+	return []storage.ProviderType{StorageProviderType}, nil
+}
+
+// StorageProvider is part of the storage.ProviderRegistry interface.
+func (k *kubernetesClient) StorageProvider(t storage.ProviderType) (storage.Provider, error) {
+	if t == StorageProviderType {
+		return &storageProvider{}, nil
+	}
+	return nil, errors.NotFoundf("storage provider %q", t)
+}
+
+// storageProvider validates the config of storage pools used by
+// CAAS applications. The volumes themselves are provisioned by the
+// cluster, from the claims made by the broker for the application.
+type storageProvider struct{}
+
+var _ storage.Provider = (*storageProvider)(nil)
+
+var storageConfigChecker = schema.FieldMap(
+	schema.Fields{
+		storageClassKey:       schema.String(),
+		storageProvisionerKey: schema.String(),
+		storageReclaimPolicyKey: schema.OneOf(
+			schema.Const(string(v1.PersistentVolumeReclaimRetain)),
+			schema.Const(string(v1.PersistentVolumeReclaimDelete)),
+		),
+	},
+	schema.Defaults{
+		storageClassKey:         "",
+		storageProvisionerKey:   "",
+		storageReclaimPolicyKey: schema.Omit,
+	},
+)
+
+type storageConfig struct {
+	storageClass  string
+	provisioner   string
+	reclaimPolicy string
+	parameters    map[string]string
+}
+
+func newStorageConfig(attrs map[string]interface{}) (*storageConfig, error) {
+	parameters := make(map[string]string)
+	checked := make(map[string]interface{})
+	for k, v := range attrs {
+		if strings.HasPrefix(k, storageParametersPrefix) {
+			parameters[strings.TrimPrefix(k, storageParametersPrefix)] = fmt.Sprint(v)
+			continue
+		}
+		checked[k] = v
+	}
+	coerced, err := storageConfigChecker.Coerce(checked, nil)
+	if err != nil {
+		return nil, errors.Annotate(err, "validating kubernetes storage config")
+	}
+	checked = coerced.(map[string]interface{})
+	config := &storageConfig{
+		storageClass: checked[storageClassKey].(string),
+		provisioner:  checked[storageProvisionerKey].(string),
+	}
+	config.reclaimPolicy, _ = checked[storageReclaimPolicyKey].(string)
+	if len(parameters) > 0 {
+		config.parameters = parameters
+	}
+	if config.provisioner == "" {
+		if config.storageClass == "" {
+			return nil, errors.Errorf("one of %s or %s must be specified", storageClassKey, storageProvisionerKey)
+		}
+		if config.reclaimPolicy != "" || len(config.parameters) > 0 {
+			return nil, errors.Errorf("%s required to configure the storage class", storageProvisionerKey)
+		}
+	}
+	return config, nil
+}
+
+// ValidateConfig is part of the storage.Provider interface.
+func (*storageProvider) ValidateConfig(cfg *storage.Config) error {
+	_, err := newStorageConfig(cfg.Attrs())
+	return errors.Trace(err)
+}
+
+// Supports is part of the storage.Provider interface.
+func (*storageProvider) Supports(k storage.StorageKind) bool {
+	return k == storage.StorageKindFilesystem
+}
+
+// Scope is part of the storage.Provider interface.
+func (*storageProvider) Scope() storage.Scope {
+	return storage.ScopeEnviron
+}
+
+// Dynamic is part of the storage.Provider interface.
+func (*storageProvider) Dynamic() bool {
+	return true
+}
+
+// Releasable is part of the storage.Provider interface.
+func (*storageProvider) Releasable() bool {
+	return false
+}
+
+// DefaultPools is part of the storage.Provider interface.
+func (*storageProvider) DefaultPools() []*storage.Config {
+	return nil
+}
+
+// VolumeSource is part of the storage.Provider interface.
+func (*storageProvider) VolumeSource(*storage.Config) (storage.VolumeSource, error) {
+	return nil, errors.NotSupportedf("volumes")
+}
+
+// FilesystemSource is part of the storage.Provider interface.
+func (*storageProvider) FilesystemSource(*storage.Config) (storage.FilesystemSource, error) {
+	return nil, errors.NotSupportedf("filesystems")
+}
+
+// ensureStorageClass returns the name of the storage class used for
+// volumes provisioned from the specified storage pool, creating or
+// updating the storage class if it is managed by Juju. Juju managed
+// storage classes are labelled with the model, and are deleted along
+// with the model.
+func (k *kubernetesClient) ensureStorageClass(cfg *storage.Config) (string, error) {
+	if cfg.Provider() != StorageProviderType {
+		return "", errors.NotValidf("storage pool %q with provider %q", cfg.Name(), cfg.Provider())
+	}
+	config, err := newStorageConfig(cfg.Attrs())
+	if err != nil {
+		return "", errors.Annotatef(err, "storage pool %q", cfg.Name())
+	}
+	if config.provisioner == "" {
+		// The storage class is managed outside of Juju.
+		return config.storageClass, nil
+	}
+	name := config.storageClass
+	if name == "" {
+		name = k.namespace + "-" + cfg.Name()
+	}
+	spec := &storageClassSpec{
+		APIVersion: storageClassAPIVersion,
+		Kind:       storageClassKind,
+		Metadata: v1.ObjectMeta{
+			Name:   name,
+			Labels: map[string]string{labelModel: k.modelConfig.UUID()},
+		},
+		Provisioner:   config.provisioner,
+		Parameters:    config.parameters,
+		ReclaimPolicy: config.reclaimPolicy,
+	}
+	existing, err := k.getStorageClass(name)
+	if k8serrors.IsNotFound(err) {
+		return name, errors.Trace(k.createStorageClass(spec))
+	}
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	if existing.Metadata.Labels[labelModel] != k.modelConfig.UUID() {
+		return "", errors.AlreadyExistsf("storage class %q not managed by this model", name)
+	}
+	if existing.Provisioner == spec.Provisioner &&
+		existing.ReclaimPolicy == spec.ReclaimPolicy &&
+		reflect.DeepEqual(existing.Parameters, spec.Parameters) {
+		return name, nil
+	}
+	// The provisioner, parameters and reclaim policy of a storage
+	// class cannot be updated, so the storage class is replaced.
+	// Volumes already provisioned from it are not affected.
+	if err := k.deleteStorageClass(name); err != nil {
+		return "", errors.Trace(err)
+	}
+	return name, errors.Trace(k.createStorageClass(spec))
+}
+
+func (k *kubernetesClient) getStorageClass(name string) (*storageClassSpec, error) {
+	data, err := k.CoreV1().RESTClient().Get().AbsPath(storageClassPath, name).Do().Raw()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var sc storageClassSpec
+	if err := json.Unmarshal(data, &sc); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &sc, nil
+}
+
+func (k *kubernetesClient) createStorageClass(spec *storageClassSpec) error {
+	body, err := json.Marshal(spec)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return k.CoreV1().RESTClient().Post().AbsPath(storageClassPath).Body(body).Do().Error()
+}
+
+func (k *kubernetesClient) deleteStorageClass(name string) error {
+	err := k.CoreV1().RESTClient().Delete().AbsPath(storageClassPath, name).Do().Error()
+	if k8serrors.IsNotFound(err) {
+		return nil
+	}
+	return errors.Trace(err)
+}
+
+// deleteStorageClasses deletes the storage classes managed by Juju
+// for the model.
+func (k *kubernetesClient) deleteStorageClasses() error {
+	data, err := k.CoreV1().RESTClient().Get().AbsPath(storageClassPath).
+		Param("labelSelector", fmt.Sprintf("%v==%v", labelModel, k.modelConfig.UUID())).
+		Do().Raw()
+	if k8serrors.IsNotFound(err) {
+		// The cluster does not support the storage.k8s.io/v1
+		// API, so there are no storage classes to delete.
+		return nil
+	}
+	if err != nil {
+		return errors.Trace(err)
+	}
+	var existing storageClassSpecList
+	if err := json.Unmarshal(data, &existing); err != nil {
+		return errors.Trace(err)
+	}
+	for _, sc := range existing.Items {
+		logger.Debugf("deleting storage class %s", sc.Metadata.Name)
+		if err := k.deleteStorageClass(sc.Metadata.Name); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
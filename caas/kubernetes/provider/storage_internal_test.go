@@ -0,0 +1,136 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package provider
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/storage"
+)
+
+type storageSuite struct {
+	baseSuite
+}
+
+var _ = gc.Suite(&storageSuite{})
+
+func (s *storageSuite) TestStorageProviderTypes(c *gc.C) {
+	types, err := s.client.StorageProviderTypes()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(types, jc.DeepEquals, []storage.ProviderType{StorageProviderType})
+}
+
+func (s *storageSuite) TestStorageProvider(c *gc.C) {
+	p, err := s.client.StorageProvider(StorageProviderType)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(p.Supports(storage.StorageKindFilesystem), jc.IsTrue)
+	c.Assert(p.Supports(storage.StorageKindBlock), jc.IsFalse)
+	c.Assert(p.Scope(), gc.Equals, storage.ScopeEnviron)
+	c.Assert(p.Dynamic(), jc.IsTrue)
+	c.Assert(p.Releasable(), jc.IsFalse)
+	c.Assert(p.DefaultPools(), gc.HasLen, 0)
+
+	_, err = p.VolumeSource(nil)
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+	_, err = p.FilesystemSource(nil)
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
+
+func (s *storageSuite) TestStorageProviderNotFound(c *gc.C) {
+	_, err := s.client.StorageProvider("ebs")
+	c.Assert(err, gc.ErrorMatches, `storage provider "ebs" not found`)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *storageSuite) TestNewStorageConfig(c *gc.C) {
+	config, err := newStorageConfig(map[string]interface{}{
+		"storage-provisioner": "kubernetes.io/gce-pd",
+		"reclaim-policy":      "Retain",
+		"parameters.type":     "pd-ssd",
+		"parameters.replicas": 2,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(config, jc.DeepEquals, &storageConfig{
+		provisioner:   "kubernetes.io/gce-pd",
+		reclaimPolicy: "Retain",
+		parameters: map[string]string{
+			"type":     "pd-ssd",
+			"replicas": "2",
+		},
+	})
+}
+
+func (s *storageSuite) TestNewStorageConfigExternalClass(c *gc.C) {
+	config, err := newStorageConfig(map[string]interface{}{
+		"storage-class": "ssd",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(config, jc.DeepEquals, &storageConfig{storageClass: "ssd"})
+}
+
+func (s *storageSuite) TestValidateConfig(c *gc.C) {
+	p := &storageProvider{}
+	for i, test := range []struct {
+		attrs map[string]interface{}
+		err   string
+	}{{
+		attrs: map[string]interface{}{"storage-class": "ssd"},
+	}, {
+		attrs: map[string]interface{}{"storage-provisioner": "kubernetes.io/aws-ebs"},
+	}, {
+		attrs: map[string]interface{}{},
+		err:   "one of storage-class or storage-provisioner must be specified",
+	}, {
+		attrs: map[string]interface{}{"storage-class": "ssd", "reclaim-policy": "Retain"},
+		err:   "storage-provisioner required to configure the storage class",
+	}, {
+		attrs: map[string]interface{}{"storage-class": "ssd", "parameters.type": "gp2"},
+		err:   "storage-provisioner required to configure the storage class",
+	}, {
+		attrs: map[string]interface{}{"storage-provisioner": "kubernetes.io/aws-ebs", "reclaim-policy": "Recycle"},
+		err:   "validating kubernetes storage config: reclaim-policy: .*",
+	}} {
+		c.Logf("test %d: %v", i, test.attrs)
+		cfg, err := storage.NewConfig("pool", StorageProviderType, test.attrs)
+		c.Assert(err, jc.ErrorIsNil)
+		err = p.ValidateConfig(cfg)
+		if test.err == "" {
+			c.Check(err, jc.ErrorIsNil)
+		} else {
+			c.Check(err, gc.ErrorMatches, test.err)
+		}
+	}
+}
+
+func (s *storageSuite) TestEnsureStorageClassExternal(c *gc.C) {
+	// A storage class not managed by Juju is used as is,
+	// without making any requests of the cluster.
+	cfg, err := storage.NewConfig("pool", StorageProviderType, map[string]interface{}{
+		"storage-class": "ssd",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	name, err := s.client.ensureStorageClass(cfg)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(name, gc.Equals, "ssd")
+	c.Assert(s.clientset.Actions(), gc.HasLen, 0)
+}
+
+func (s *storageSuite) TestEnsureStorageClassWrongProvider(c *gc.C) {
+	cfg, err := storage.NewConfig("pool", "ebs", map[string]interface{}{
+		"storage-class": "ssd",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.client.ensureStorageClass(cfg)
+	c.Assert(err, gc.ErrorMatches, `storage pool "pool" with provider "ebs" not valid`)
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+}
+
+func (s *storageSuite) TestEnsureStorageClassInvalidConfig(c *gc.C) {
+	cfg, err := storage.NewConfig("pool", StorageProviderType, map[string]interface{}{})
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.client.ensureStorageClass(cfg)
+	c.Assert(err, gc.ErrorMatches, `storage pool "pool": one of storage-class or storage-provisioner must be specified`)
+}
@@ -0,0 +1,47 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package caas
+
+import (
+	"time"
+
+	"github.com/juju/juju/watcher"
+)
+
+// LogRecord is a line of output written by
+// a container of one of an application's pods.
+type LogRecord struct {
+	// UnitId is the provider id of the unit
+	// whose pod the container belongs to.
+	UnitId string
+
+	// UnitName is the name of the unit, if the pod belongs
+	// to a unit managed by Juju rather than the substrate.
+	UnitName string
+
+	// PodName is the name of the pod.
+	PodName string
+
+	// ContainerName is the name of the container.
+	ContainerName string
+
+	// Time is the time at which the line was written.
+	Time time.Time
+
+	// Message is the line written, without its line ending.
+	Message string
+}
+
+// LogsChannel is a change channel as described in the CoreWatcher docs.
+//
+// Each value sent holds the log records written since the previous
+// value was sent, in the order they were received from the substrate.
+type LogsChannel <-chan []LogRecord
+
+// LogsWatcher conveniently ties a LogsChannel to the
+// worker.Worker that represents its validity.
+type LogsWatcher interface {
+	watcher.CoreWatcher
+	Changes() LogsChannel
+}
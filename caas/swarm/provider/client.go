@@ -0,0 +1,247 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package provider
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/environs"
+)
+
+// apiVersion is the Docker Engine API version used by the
+// client. Version 1.30 is the first to support swarm configs.
+const apiVersion = "v1.30"
+
+// swarmAPI is a minimal client for the Docker Engine API,
+// talking to a swarm manager node.
+type swarmAPI struct {
+	client   *http.Client
+	endpoint string
+}
+
+func newSwarmAPI(cloudSpec environs.CloudSpec) (*swarmAPI, error) {
+	if cloudSpec.Endpoint == "" {
+		return nil, errors.Errorf("cloud %v has no endpoint", cloudSpec.Name)
+	}
+	endpoint, err := url.Parse(cloudSpec.Endpoint)
+	if err != nil {
+		return nil, errors.Annotate(err, "parsing endpoint")
+	}
+	tlsConfig, err := newTLSConfig(cloudSpec)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	// Docker endpoints are conventionally written as tcp://host:port.
+	if endpoint.Scheme == "tcp" || endpoint.Scheme == "" {
+		endpoint.Scheme = "http"
+		if tlsConfig != nil {
+			endpoint.Scheme = "https"
+		}
+	}
+	return &swarmAPI{
+		client: &http.Client{
+			Transport: &http.Transport{TLSClientConfig: tlsConfig},
+		},
+		endpoint: strings.TrimSuffix(endpoint.String(), "/"),
+	}, nil
+}
+
+// newTLSConfig returns the TLS configuration for connecting to the
+// swarm manager, or nil if the cloud spec does not specify any.
+func newTLSConfig(cloudSpec environs.CloudSpec) (*tls.Config, error) {
+	var certData, keyData string
+	if cloudSpec.Credential != nil {
+		credentialAttrs := cloudSpec.Credential.Attributes()
+		certData = credentialAttrs["ClientCertificateData"]
+		keyData = credentialAttrs["ClientKeyData"]
+	}
+	if len(cloudSpec.CACertificates) == 0 && certData == "" {
+		return nil, nil
+	}
+	tlsConfig := &tls.Config{}
+	if len(cloudSpec.CACertificates) > 0 {
+		pool := x509.NewCertPool()
+		for _, cacert := range cloudSpec.CACertificates {
+			if !pool.AppendCertsFromPEM([]byte(cacert)) {
+				return nil, errors.NotValidf("CA certificate")
+			}
+		}
+		tlsConfig.RootCAs = pool
+	}
+	if certData != "" {
+		cert, err := tls.X509KeyPair([]byte(certData), []byte(keyData))
+		if err != nil {
+			return nil, errors.Annotate(err, "parsing client certificate")
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	return tlsConfig, nil
+}
+
+// do performs a request against the Docker Engine API. If in is
+// non-nil it is sent as the JSON request body; if out is non-nil
+// the JSON response body is decoded into it.
+func (api *swarmAPI) do(method, path string, query url.Values, in, out interface{}) error {
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		body = bytes.NewReader(data)
+	}
+	reqURL := api.endpoint + "/" + apiVersion + path
+	if len(query) > 0 {
+		reqURL += "?" + query.Encode()
+	}
+	req, err := http.NewRequest(method, reqURL, body)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := api.client.Do(req)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return responseError(resp)
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return errors.Annotatef(err, "decoding response to %s %s", method, path)
+	}
+	return nil
+}
+
+// responseError returns an error describing the failed request,
+// satisfying errors.IsNotFound if the object does not exist.
+func responseError(resp *http.Response) error {
+	data, _ := ioutil.ReadAll(resp.Body)
+	var errResp errorResponse
+	if err := json.Unmarshal(data, &errResp); err != nil || errResp.Message == "" {
+		errResp.Message = strings.TrimSpace(string(data))
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		return errors.NewNotFound(nil, errResp.Message)
+	}
+	return errors.Errorf("docker API error (%d): %s", resp.StatusCode, errResp.Message)
+}
+
+// labelFilters returns the query parameters for listing
+// objects with all of the specified labels.
+func labelFilters(labels map[string]string) url.Values {
+	var label []string
+	for k, v := range labels {
+		label = append(label, fmt.Sprintf("%s=%s", k, v))
+	}
+	filters, _ := json.Marshal(map[string][]string{"label": label})
+	return url.Values{"filters": {string(filters)}}
+}
+
+func (api *swarmAPI) listServices(labels map[string]string) ([]service, error) {
+	var result []service
+	err := api.do("GET", "/services", labelFilters(labels), nil, &result)
+	return result, errors.Trace(err)
+}
+
+func (api *swarmAPI) inspectService(name string) (*service, error) {
+	var result service
+	if err := api.do("GET", "/services/"+name, nil, nil, &result); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &result, nil
+}
+
+func (api *swarmAPI) createService(spec *serviceSpec) error {
+	return errors.Trace(api.do("POST", "/services/create", nil, spec, nil))
+}
+
+func (api *swarmAPI) updateService(id string, version objectVersion, spec *serviceSpec) error {
+	query := url.Values{"version": {fmt.Sprint(version.Index)}}
+	return errors.Trace(api.do("POST", "/services/"+id+"/update", query, spec, nil))
+}
+
+func (api *swarmAPI) removeService(name string) error {
+	return errors.Trace(api.do("DELETE", "/services/"+name, nil, nil, nil))
+}
+
+func (api *swarmAPI) listTasks(serviceName string) ([]task, error) {
+	filters, _ := json.Marshal(map[string][]string{"service": {serviceName}})
+	var result []task
+	err := api.do("GET", "/tasks", url.Values{"filters": {string(filters)}}, nil, &result)
+	return result, errors.Trace(err)
+}
+
+func (api *swarmAPI) inspectNetwork(name string) (*network, error) {
+	var result network
+	if err := api.do("GET", "/networks/"+name, nil, nil, &result); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &result, nil
+}
+
+func (api *swarmAPI) createNetwork(spec *networkCreate) error {
+	return errors.Trace(api.do("POST", "/networks/create", nil, spec, nil))
+}
+
+func (api *swarmAPI) removeNetwork(name string) error {
+	return errors.Trace(api.do("DELETE", "/networks/"+name, nil, nil, nil))
+}
+
+func (api *swarmAPI) listConfigs(labels map[string]string) ([]swarmConfig, error) {
+	var result []swarmConfig
+	err := api.do("GET", "/configs", labelFilters(labels), nil, &result)
+	return result, errors.Trace(err)
+}
+
+func (api *swarmAPI) createConfig(spec *swarmConfigSpec) (string, error) {
+	var result idResponse
+	if err := api.do("POST", "/configs/create", nil, spec, &result); err != nil {
+		return "", errors.Trace(err)
+	}
+	return result.ID, nil
+}
+
+func (api *swarmAPI) removeConfig(id string) error {
+	return errors.Trace(api.do("DELETE", "/configs/"+id, nil, nil, nil))
+}
+
+func (api *swarmAPI) version() (*versionResponse, error) {
+	var result versionResponse
+	if err := api.do("GET", "/version", nil, nil, &result); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &result, nil
+}
+
+func (api *swarmAPI) info() (*infoResponse, error) {
+	var result infoResponse
+	if err := api.do("GET", "/info", nil, nil, &result); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &result, nil
+}
+
+func (api *swarmAPI) listNodes() ([]node, error) {
+	var result []node
+	err := api.do("GET", "/nodes", nil, nil, &result)
+	return result, errors.Trace(err)
+}
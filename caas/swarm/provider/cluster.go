@@ -0,0 +1,98 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package provider
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/version"
+
+	"github.com/juju/juju/caas"
+	"github.com/juju/juju/environs"
+)
+
+// ClusterMetadata returns details of the swarm specified
+// by the cloud spec, without requiring a model.
+func ClusterMetadata(cloudSpec environs.CloudSpec) (*caas.ClusterMetadata, error) {
+	api, err := newSwarmAPI(cloudSpec)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return clusterMetadata(api)
+}
+
+// ClusterMetadata is part of the caas.Broker interface.
+// The version reported is that of the Docker Engine API,
+// and the storage classes are the volume drivers.
+func (s *swarmBroker) ClusterMetadata() (*caas.ClusterMetadata, error) {
+	return clusterMetadata(s.api)
+}
+
+func clusterMetadata(api *swarmAPI) (*caas.ClusterMetadata, error) {
+	vers, err := api.version()
+	if err != nil {
+		return nil, errors.Annotate(err, "querying engine version")
+	}
+	engineVersion, err := parseAPIVersion(vers.APIVersion)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	// The client requires the API version it uses.
+	minVersion, err := parseAPIVersion(strings.TrimPrefix(apiVersion, "v"))
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	result := &caas.ClusterMetadata{
+		Version:    engineVersion,
+		MinVersion: minVersion,
+	}
+
+	info, err := api.info()
+	if err != nil {
+		return nil, errors.Annotate(err, "querying engine info")
+	}
+	for _, driver := range info.Plugins.Volume {
+		result.StorageClasses = append(result.StorageClasses, caas.StorageClass{
+			Name:        driver,
+			Provisioner: driver,
+			Default:     driver == "local",
+		})
+	}
+
+	nodes, err := api.listNodes()
+	if err != nil {
+		return nil, errors.Annotate(err, "listing nodes")
+	}
+	for _, n := range nodes {
+		if n.Spec.Availability != nodeAvailabilityActive {
+			continue
+		}
+		// One CPU core is 100 cpu-power, and 1e9 nano CPUs.
+		result.Nodes = append(result.Nodes, caas.NodeCapacity{
+			Name:     n.Description.Hostname,
+			CpuPower: uint64(n.Description.Resources.NanoCPUs / 1e7),
+			Mem:      uint64(n.Description.Resources.MemoryBytes / (1024 * 1024)),
+		})
+	}
+	return result, nil
+}
+
+// parseAPIVersion parses a Docker Engine API version, such as "1.30".
+func parseAPIVersion(s string) (version.Number, error) {
+	parts := strings.Split(s, ".")
+	if len(parts) != 2 {
+		return version.Number{}, errors.NotValidf("engine API version %q", s)
+	}
+	major, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return version.Number{}, errors.NotValidf("engine API version %q", s)
+	}
+	minor, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return version.Number{}, errors.NotValidf("engine API version %q", s)
+	}
+	return version.Number{Major: major, Minor: minor}, nil
+}
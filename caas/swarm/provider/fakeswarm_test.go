@@ -0,0 +1,195 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// fakeSwarm is an in-memory implementation of the subset of the
+// Docker Engine API used to manage services and list their tasks.
+type fakeSwarm struct {
+	mu sync.Mutex
+
+	nextID   int
+	services map[string]*service
+	tasks    map[string][]task
+
+	// calls records the method and path of each request.
+	calls []string
+}
+
+func newFakeSwarm() *fakeSwarm {
+	return &fakeSwarm{
+		services: make(map[string]*service),
+		tasks:    make(map[string][]task),
+	}
+}
+
+// addService adds a service with the given spec,
+// returning its ID.
+func (f *fakeSwarm) addService(spec serviceSpec) string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.createService(spec)
+}
+
+func (f *fakeSwarm) createService(spec serviceSpec) string {
+	f.nextID++
+	id := fmt.Sprintf("svc-%d", f.nextID)
+	f.services[id] = &service{
+		ID:      id,
+		Version: objectVersion{Index: 1},
+		Spec:    spec,
+	}
+	return id
+}
+
+// service returns the service with the given name, or nil.
+func (f *fakeSwarm) service(name string) *service {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.findService(name)
+}
+
+func (f *fakeSwarm) findService(nameOrID string) *service {
+	if svc, ok := f.services[nameOrID]; ok {
+		return svc
+	}
+	for _, svc := range f.services {
+		if svc.Spec.Name == nameOrID {
+			return svc
+		}
+	}
+	return nil
+}
+
+// setTasks sets the tasks of the service with the given ID.
+func (f *fakeSwarm) setTasks(serviceID string, tasks ...task) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.tasks[serviceID] = tasks
+}
+
+// Calls returns the requests made so far.
+func (f *fakeSwarm) Calls() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.calls...)
+}
+
+// ServeHTTP implements http.Handler.
+func (f *fakeSwarm) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	path := strings.TrimPrefix(req.URL.Path, "/"+apiVersion)
+	f.calls = append(f.calls, req.Method+" "+path)
+
+	switch {
+	case req.Method == "GET" && path == "/services":
+		var filters struct {
+			Label []string `json:"label"`
+		}
+		if err := json.Unmarshal([]byte(req.URL.Query().Get("filters")), &filters); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		result := []service{}
+		for _, svc := range f.services {
+			if matchLabels(svc.Spec.Labels, filters.Label) {
+				result = append(result, *svc)
+			}
+		}
+		writeJSON(w, result)
+	case req.Method == "POST" && path == "/services/create":
+		var spec serviceSpec
+		if err := json.NewDecoder(req.Body).Decode(&spec); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if f.findService(spec.Name) != nil {
+			writeError(w, http.StatusConflict, fmt.Sprintf("service %s already exists", spec.Name))
+			return
+		}
+		writeJSON(w, idResponse{ID: f.createService(spec)})
+	case req.Method == "POST" && strings.HasPrefix(path, "/services/") && strings.HasSuffix(path, "/update"):
+		id := strings.TrimSuffix(strings.TrimPrefix(path, "/services/"), "/update")
+		svc := f.services[id]
+		if svc == nil {
+			writeError(w, http.StatusNotFound, fmt.Sprintf("service %s not found", id))
+			return
+		}
+		if req.URL.Query().Get("version") != fmt.Sprint(svc.Version.Index) {
+			writeError(w, http.StatusInternalServerError, "update out of sequence")
+			return
+		}
+		var spec serviceSpec
+		if err := json.NewDecoder(req.Body).Decode(&spec); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		svc.Spec = spec
+		svc.Version.Index++
+		writeJSON(w, struct{}{})
+	case strings.HasPrefix(path, "/services/"):
+		name := strings.TrimPrefix(path, "/services/")
+		svc := f.findService(name)
+		if svc == nil {
+			writeError(w, http.StatusNotFound, fmt.Sprintf("service %s not found", name))
+			return
+		}
+		switch req.Method {
+		case "GET":
+			writeJSON(w, svc)
+		case "DELETE":
+			delete(f.services, svc.ID)
+			delete(f.tasks, svc.ID)
+		default:
+			writeError(w, http.StatusMethodNotAllowed, req.Method)
+		}
+	case req.Method == "GET" && path == "/tasks":
+		var filters struct {
+			Service []string `json:"service"`
+		}
+		if err := json.Unmarshal([]byte(req.URL.Query().Get("filters")), &filters); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		result := []task{}
+		for _, id := range filters.Service {
+			result = append(result, f.tasks[id]...)
+		}
+		writeJSON(w, result)
+	default:
+		writeError(w, http.StatusNotFound, "page not found")
+	}
+}
+
+// matchLabels reports whether the labels include
+// all of the "key=value" filters.
+func matchLabels(labels map[string]string, filters []string) bool {
+	for _, filter := range filters {
+		parts := strings.SplitN(filter, "=", 2)
+		if len(parts) != 2 || labels[parts[0]] != parts[1] {
+			return false
+		}
+	}
+	return true
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, code int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(errorResponse{Message: message})
+}
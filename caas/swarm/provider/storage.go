@@ -0,0 +1,21 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package provider
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/storage"
+)
+
+// StorageProviderTypes is part of the storage.ProviderRegistry interface.
+// Task volumes are local to the node, so there are no storage providers.
+func (s *swarmBroker) StorageProviderTypes() ([]storage.ProviderType, error) {
+	return nil, nil
+}
+
+// StorageProvider is part of the storage.ProviderRegistry interface.
+func (s *swarmBroker) StorageProvider(t storage.ProviderType) (storage.Provider, error) {
+	return nil, errors.NotFoundf("storage provider %q", t)
+}
@@ -0,0 +1,762 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package provider
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"github.com/juju/utils/clock"
+	"github.com/juju/version"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/agent"
+	"github.com/juju/juju/caas"
+	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/core/application"
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/status"
+	jujuversion "github.com/juju/juju/version"
+)
+
+var logger = loggo.GetLogger("juju.swarm.provider")
+
+// ProviderType is the cloud type of Docker Swarm clouds.
+const ProviderType = "docker-swarm"
+
+const (
+	labelModel       = "juju-model"
+	labelApplication = "juju-application"
+	labelUnit        = "juju-unit"
+	labelOperator    = "juju-operator"
+	labelPorts       = "juju-ports"
+
+	operatorImageRepo = "jujusolutions/caas-jujud-operator"
+
+	networkDriver = "overlay"
+)
+
+type swarmBroker struct {
+	api   *swarmAPI
+	clock clock.Clock
+
+	// network is the name of the overlay network to
+	// which all of the model's services are attached.
+	network string
+
+	// modelConfig is the config of the model
+	// for which the broker manages resources.
+	modelConfig *config.Config
+}
+
+// NewSwarmProvider returns a Docker Swarm broker for the specified cloud.
+func NewSwarmProvider(args environs.OpenParams) (caas.Broker, error) {
+	if args.Config == nil {
+		return nil, errors.NotValidf("nil model config")
+	}
+	api, err := newSwarmAPI(args.Cloud)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return newSwarmBroker(api, args.Config, clock.WallClock), nil
+}
+
+func newSwarmBroker(api *swarmAPI, cfg *config.Config, clock clock.Clock) *swarmBroker {
+	return &swarmBroker{
+		api:         api,
+		clock:       clock,
+		network:     cfg.Name(),
+		modelConfig: cfg,
+	}
+}
+
+// Create creates the overlay network to which the model's
+// services are attached.
+func (s *swarmBroker) Create(args environs.CreateParams) error {
+	existing, err := s.api.inspectNetwork(s.network)
+	if err == nil {
+		if existing.Labels[labelModel] != s.modelConfig.UUID() {
+			return errors.AlreadyExistsf("network %q for another model", s.network)
+		}
+		return nil
+	}
+	if !errors.IsNotFound(err) {
+		return errors.Trace(err)
+	}
+	logger.Debugf("creating network %s", s.network)
+	return errors.Trace(s.api.createNetwork(&networkCreate{
+		Name:           s.network,
+		Driver:         networkDriver,
+		Attachable:     true,
+		CheckDuplicate: true,
+		Labels:         s.modelLabels(),
+	}))
+}
+
+// Destroy removes all of the model's services and configs,
+// and then the model's network.
+func (s *swarmBroker) Destroy() error {
+	services, err := s.api.listServices(s.modelLabels())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for _, svc := range services {
+		if err := s.api.removeService(svc.ID); err != nil && !errors.IsNotFound(err) {
+			return errors.Trace(err)
+		}
+	}
+	configs, err := s.api.listConfigs(s.modelLabels())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for _, cfg := range configs {
+		if err := s.api.removeConfig(cfg.ID); err != nil && !errors.IsNotFound(err) {
+			return errors.Trace(err)
+		}
+	}
+	existing, err := s.api.inspectNetwork(s.network)
+	if errors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	if existing.Labels[labelModel] != s.modelConfig.UUID() {
+		logger.Debugf("not removing network %s belonging to another model", s.network)
+		return nil
+	}
+	err = s.api.removeNetwork(s.network)
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	return errors.Trace(err)
+}
+
+// EnsureOperator creates or updates an operator service with the given
+// application name, agent path, and operator config.
+func (s *swarmBroker) EnsureOperator(appName, agentPath string, config *caas.OperatorConfig) error {
+	logger.Debugf("creating/updating %s operator", appName)
+
+	// Swarm configs are immutable, so each distinct agent
+	// config is stored under a name derived from its content.
+	configName := operatorConfigName(appName, config.AgentConf)
+	configLabels := s.modelLabels()
+	configLabels[labelOperator] = appName
+	configs, err := s.api.listConfigs(configLabels)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	var configID string
+	for _, cfg := range configs {
+		if cfg.Spec.Name == configName {
+			configID = cfg.ID
+		}
+	}
+	if configID == "" {
+		configID, err = s.api.createConfig(&swarmConfigSpec{
+			Name:   configName,
+			Labels: configLabels,
+			Data:   config.AgentConf,
+		})
+		if err != nil {
+			return errors.Annotate(err, "creating operator config")
+		}
+	}
+
+	spec := s.operatorServiceSpec(appName, agentPath, operatorImagePath(jujuversion.Current), configID, configName)
+	if err := s.ensureService(spec); err != nil {
+		return errors.Trace(err)
+	}
+
+	// Remove any stale configs; those still in use by
+	// tasks which are shutting down will fail to be
+	// removed, and will be cleaned up next time.
+	for _, cfg := range configs {
+		if cfg.ID == configID {
+			continue
+		}
+		if err := s.api.removeConfig(cfg.ID); err != nil {
+			logger.Debugf("cannot remove operator config %s: %v", cfg.Spec.Name, err)
+		}
+	}
+	return nil
+}
+
+// UpgradeOperator updates the operator service for the specified
+// application to run the specified Juju agent version.
+func (s *swarmBroker) UpgradeOperator(appName string, vers version.Number) error {
+	logger.Debugf("upgrading %s operator to %v", appName, vers)
+
+	svc, err := s.api.inspectService(operatorServiceName(appName))
+	if errors.IsNotFound(err) {
+		return errors.NotFoundf("operator service for %q", appName)
+	}
+	if err != nil {
+		return errors.Trace(err)
+	}
+	image := operatorImagePath(vers)
+	if svc.Spec.TaskTemplate.ContainerSpec.Image == image {
+		return nil
+	}
+	svc.Spec.TaskTemplate.ContainerSpec.Image = image
+	return errors.Trace(s.api.updateService(svc.ID, svc.Version, &svc.Spec))
+}
+
+// EnsureService creates or updates a replicated service running
+// numUnits tasks with the given params, or a global service running
+// a task on every node for applications deployed one per node.
+func (s *swarmBroker) EnsureService(
+	appName string, params *caas.ServiceParams, numUnits int, config application.ConfigAttributes,
+) error {
+	logger.Debugf("creating/updating application %s", appName)
+
+	if numUnits < 0 {
+		return errors.Errorf("number of units must be >= 0")
+	}
+	if numUnits == 0 {
+		return s.deleteService(applicationServiceName(appName))
+	}
+	if params == nil || params.PodSpec == nil {
+		return errors.Errorf("missing pod spec")
+	}
+	if params.DeploymentMode == caas.DeploymentStateful {
+		return errors.NotSupportedf("%s deployment mode", params.DeploymentMode)
+	}
+	if params.Autoscale != nil {
+		return errors.NotSupportedf("autoscaling")
+	}
+	if annotations, err := caas.KeyValues(config, caas.JujuServiceAnnotationsKey); err != nil {
+		return errors.Trace(err)
+	} else if len(annotations) > 0 {
+		return errors.NotSupportedf("service annotations")
+	}
+	labels, err := caas.KeyValues(config, caas.JujuServiceLabelsKey)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if labels == nil {
+		labels = make(map[string]string)
+	}
+	for k, v := range s.modelLabels() {
+		labels[k] = v
+	}
+	labels[labelApplication] = appName
+	spec, err := s.makeServiceSpec(applicationServiceName(appName), params, labels)
+	if err != nil {
+		return errors.Annotatef(err, "parsing unit spec for %s", appName)
+	}
+	if params.DeploymentMode == caas.DeploymentOnePerNode {
+		// A global service runs one task on each node.
+		spec.Mode = serviceMode{Global: &globalService{}}
+	} else {
+		replicas := uint64(numUnits)
+		spec.Mode.Replicated.Replicas = &replicas
+	}
+
+	// Preserve any published ports set up by ExposeService.
+	existing, err := s.api.inspectService(spec.Name)
+	if err == nil {
+		spec.EndpointSpec = existing.Spec.EndpointSpec
+		// The mode of a service cannot be updated, so
+		// the service is replaced if the mode changes.
+		if (existing.Spec.Mode.Global == nil) != (spec.Mode.Global == nil) {
+			if err := s.deleteService(spec.Name); err != nil {
+				return errors.Trace(err)
+			}
+		}
+	} else if !errors.IsNotFound(err) {
+		return errors.Trace(err)
+	}
+	return s.ensureService(spec)
+}
+
+// DeleteService deletes the specified service.
+func (s *swarmBroker) DeleteService(appName string) error {
+	logger.Debugf("deleting application %s", appName)
+	return s.deleteService(applicationServiceName(appName))
+}
+
+// ExposeService publishes the ports of the specified service
+// on every node in the swarm, using the ingress routing mesh.
+func (s *swarmBroker) ExposeService(appName string, config application.ConfigAttributes) error {
+	logger.Debugf("exposing application %s", appName)
+
+	svc, err := s.api.inspectService(applicationServiceName(appName))
+	if errors.IsNotFound(err) {
+		return errors.NotFoundf("service for %q", appName)
+	}
+	if err != nil {
+		return errors.Trace(err)
+	}
+	ports, err := servicePorts(svc)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(ports) == 0 {
+		return errors.Errorf("cannot create ingress rule for service %q without a port", appName)
+	}
+	endpoint := &endpointSpec{Mode: "vip"}
+	for _, p := range ports {
+		endpoint.Ports = append(endpoint.Ports, portConfig{
+			Protocol:    p.Protocol,
+			TargetPort:  uint32(p.ContainerPort),
+			PublishMode: "ingress",
+		})
+	}
+	svc.Spec.EndpointSpec = endpoint
+	return errors.Trace(s.api.updateService(svc.ID, svc.Version, &svc.Spec))
+}
+
+// UnexposeService removes the published ports of the specified service.
+func (s *swarmBroker) UnexposeService(appName string) error {
+	logger.Debugf("unexposing application %s", appName)
+
+	svc, err := s.api.inspectService(applicationServiceName(appName))
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if svc.Spec.EndpointSpec == nil {
+		return nil
+	}
+	svc.Spec.EndpointSpec = nil
+	return errors.Trace(s.api.updateService(svc.ID, svc.Version, &svc.Spec))
+}
+
+// EnsureUnit creates or updates a single replica service for
+// the specified unit, with the given params.
+func (s *swarmBroker) EnsureUnit(appName, unitName string, params *caas.ServiceParams) error {
+	logger.Debugf("creating/updating unit %s", unitName)
+
+	if params == nil || params.PodSpec == nil {
+		return errors.Errorf("missing pod spec")
+	}
+	labels := s.modelLabels()
+	labels[labelApplication] = appName
+	labels[labelUnit] = unitName
+	spec, err := s.makeServiceSpec(unitServiceName(unitName), params, labels)
+	if err != nil {
+		return errors.Annotatef(err, "parsing unit spec for %s", unitName)
+	}
+	replicas := uint64(1)
+	spec.Mode.Replicated.Replicas = &replicas
+	return s.ensureService(spec)
+}
+
+// EnsureUnits creates or updates the services of the specified units.
+func (s *swarmBroker) EnsureUnits(appName string, units []caas.UnitSpec) error {
+	// Services are updated in place, so there's
+	// no benefit in handling the units together.
+	for _, u := range units {
+		if err := s.EnsureUnit(appName, u.UnitName, u.Params); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+// DeleteUnit deletes the service of the specified unit.
+func (s *swarmBroker) DeleteUnit(unitName string) error {
+	logger.Debugf("deleting unit %s", unitName)
+	return s.deleteService(unitServiceName(unitName))
+}
+
+// WatchUnits returns a watcher which reports the units of
+// the specified application which are added, updated or removed.
+func (s *swarmBroker) WatchUnits(appName string) (caas.UnitsWatcher, error) {
+	return newSwarmWatcher(func() ([]caas.Unit, error) {
+		return s.Units(appName)
+	}, appName, s.clock)
+}
+
+// Units returns all units of the specified application.
+func (s *swarmBroker) Units(appName string) ([]caas.Unit, error) {
+	services, err := s.applicationServices(appName)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var result []caas.Unit
+	now := time.Now()
+	for _, svc := range services {
+		ports, err := servicePorts(&svc)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		var unitPorts []string
+		for _, p := range ports {
+			unitPorts = append(unitPorts, fmt.Sprintf("%v/%v", p.ContainerPort, p.Protocol))
+		}
+		tasks, err := s.api.listTasks(svc.ID)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		for _, t := range tasks {
+			// Tasks which have been superseded, by a
+			// restart or rolling update, are not units.
+			if t.DesiredState != taskStateRunning {
+				continue
+			}
+			unitStatus, message := jujuStatus(t.Status)
+			result = append(result, caas.Unit{
+				Id:      t.ID,
+				Address: s.taskAddress(t),
+				Ports:   unitPorts,
+				Status: status.StatusInfo{
+					Status:  unitStatus,
+					Message: message,
+					Since:   &now,
+				},
+				FilesystemInfo: filesystemInfo(&svc, t),
+			})
+		}
+	}
+	return result, nil
+}
+
+// WatchLogs is part of the caas.Broker interface.
+func (s *swarmBroker) WatchLogs(appName string, since time.Time) (caas.LogsWatcher, error) {
+	return nil, errors.NotSupportedf("streaming logs of docker swarm services")
+}
+
+// Exec is part of the caas.Broker interface.
+func (s *swarmBroker) Exec(params caas.ExecParams, abort <-chan struct{}) error {
+	return errors.NotSupportedf("running commands in docker swarm services")
+}
+
+// applicationServices returns the services running units
+// of the specified application.
+func (s *swarmBroker) applicationServices(appName string) ([]service, error) {
+	labels := s.modelLabels()
+	labels[labelApplication] = appName
+	services, err := s.api.listServices(labels)
+	return services, errors.Trace(err)
+}
+
+// filesystemInfo returns information about the volumes
+// mounted into the task's container. The volumes are local
+// to the node running the task, and their size is unknown.
+func filesystemInfo(svc *service, t task) []caas.FilesystemInfo {
+	fsStatus := status.Pending
+	if t.Status.State == taskStateRunning {
+		fsStatus = status.Attached
+	}
+	var result []caas.FilesystemInfo
+	for _, m := range svc.Spec.TaskTemplate.ContainerSpec.Mounts {
+		if m.Type != "volume" {
+			continue
+		}
+		result = append(result, caas.FilesystemInfo{
+			StorageName:  strings.TrimPrefix(m.Source, svc.Spec.Name+"-"),
+			FilesystemId: m.Source,
+			MountPoint:   m.Target,
+			ReadOnly:     m.ReadOnly,
+			Status:       status.StatusInfo{Status: fsStatus},
+		})
+	}
+	return result
+}
+
+// taskAddress returns the address of the task on the model's network.
+func (s *swarmBroker) taskAddress(t task) string {
+	for _, attachment := range t.NetworksAttachments {
+		if attachment.Network.Name != s.network || len(attachment.Addresses) == 0 {
+			continue
+		}
+		// Addresses are in CIDR notation.
+		return strings.SplitN(attachment.Addresses[0], "/", 2)[0]
+	}
+	return ""
+}
+
+func jujuStatus(taskStatus taskStatus) (status.Status, string) {
+	message := taskStatus.Message
+	if taskStatus.Err != "" {
+		message = taskStatus.Err
+	}
+	switch taskStatus.State {
+	case taskStateRunning:
+		return status.Running, message
+	case taskStateFailed, taskStateRejected:
+		return status.Error, message
+	default:
+		return status.Allocating, message
+	}
+}
+
+func (s *swarmBroker) ensureService(spec *serviceSpec) error {
+	existing, err := s.api.inspectService(spec.Name)
+	if errors.IsNotFound(err) {
+		return errors.Trace(s.api.createService(spec))
+	}
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(s.api.updateService(existing.ID, existing.Version, spec))
+}
+
+func (s *swarmBroker) deleteService(name string) error {
+	err := s.api.removeService(name)
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	return errors.Trace(err)
+}
+
+func (s *swarmBroker) modelLabels() map[string]string {
+	return map[string]string{labelModel: s.modelConfig.UUID()}
+}
+
+// makeServiceSpec returns the spec of a service running
+// tasks defined by the given params.
+func (s *swarmBroker) makeServiceSpec(name string, params *caas.ServiceParams, labels map[string]string) (*serviceSpec, error) {
+	podSpec := params.PodSpec
+	if len(podSpec.Containers) != 1 {
+		return nil, errors.NotSupportedf("%d containers", len(podSpec.Containers))
+	}
+	if len(podSpec.InitContainers) > 0 {
+		return nil, errors.NotSupportedf("init containers")
+	}
+	if len(podSpec.OperatorRules) > 0 {
+		return nil, errors.NotSupportedf("operator rules")
+	}
+	if len(podSpec.CustomResourceDefinitions) > 0 {
+		return nil, errors.NotSupportedf("custom resource definitions")
+	}
+	container := podSpec.Containers[0]
+	if len(container.Files) > 0 {
+		return nil, errors.NotSupportedf("file sets")
+	}
+	if container.ReadinessProbe != nil {
+		return nil, errors.NotSupportedf("readiness probe")
+	}
+	if container.ImageCredentials != nil || params.ImagePullSecret != "" {
+		return nil, errors.NotSupportedf("image pull credentials")
+	}
+	if len(params.PodAnnotations) > 0 {
+		return nil, errors.NotSupportedf("pod annotations")
+	}
+	if container.GPU != nil || params.Constraints.HasGpus() {
+		return nil, errors.NotSupportedf("gpu requests")
+	}
+
+	// The container ports are recorded as a label, since
+	// they are only published when the service is exposed.
+	var ports []string
+	for _, p := range container.Ports {
+		protocol := strings.ToLower(p.Protocol)
+		if protocol == "" {
+			protocol = "tcp"
+		}
+		ports = append(ports, fmt.Sprintf("%d/%s", p.ContainerPort, protocol))
+	}
+	containerLabels := make(map[string]string)
+	for k, v := range params.PodLabels {
+		containerLabels[k] = v
+	}
+	containerLabels[labelPorts] = strings.Join(ports, ",")
+	containerSpec := containerSpec{
+		Image:  container.ImageName,
+		Labels: containerLabels,
+	}
+	for k, v := range container.Config {
+		containerSpec.Env = append(containerSpec.Env, fmt.Sprintf("%s=%s", k, v))
+	}
+	for _, vol := range container.Volumes {
+		if vol.StoragePool != "" {
+			return nil, errors.NotSupportedf("storage pools")
+		}
+		// The volume is a named docker volume local to the node
+		// on which the task runs; the size and storage class are
+		// not configurable without a volume plugin.
+		containerSpec.Mounts = append(containerSpec.Mounts, mount{
+			Type:     "volume",
+			Source:   volumeName(name, vol.Name),
+			Target:   vol.MountPath,
+			ReadOnly: vol.ReadOnly,
+		})
+	}
+	healthcheck, err := containerHealthcheck(container.LivenessProbe)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	containerSpec.Healthcheck = healthcheck
+
+	constraints, err := placementConstraints(params.Placement, params.Constraints)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &serviceSpec{
+		Name:   name,
+		Labels: labels,
+		TaskTemplate: taskSpec{
+			ContainerSpec: containerSpec,
+			Resources:     containerResources(params.Constraints),
+			Placement:     &placement{Constraints: constraints},
+			Networks:      []networkAttachmentRef{{Target: s.network}},
+		},
+		Mode: serviceMode{Replicated: &replicatedService{}},
+	}, nil
+}
+
+// operatorServiceSpec returns the spec of the operator
+// service for the specified application.
+func (s *swarmBroker) operatorServiceSpec(appName, agentPath, image, configID, configName string) *serviceSpec {
+	appTag := names.NewApplicationTag(appName)
+	labels := s.modelLabels()
+	labels[labelOperator] = appName
+	replicas := uint64(1)
+	return &serviceSpec{
+		Name:   operatorServiceName(appName),
+		Labels: labels,
+		TaskTemplate: taskSpec{
+			ContainerSpec: containerSpec{
+				Image: image,
+				Env:   []string{"JUJU_APPLICATION=" + appName},
+				Configs: []configReference{{
+					File: configReferenceFile{
+						Name: agent.Dir(agentPath, appTag) + "/agent.conf",
+						UID:  "0",
+						GID:  "0",
+						Mode: 0600,
+					},
+					ConfigID:   configID,
+					ConfigName: configName,
+				}},
+			},
+			Networks: []networkAttachmentRef{{Target: s.network}},
+		},
+		Mode: serviceMode{Replicated: &replicatedService{Replicas: &replicas}},
+	}
+}
+
+// containerResources returns the resources reserved for, and
+// limiting, tasks started with the specified constraints.
+func containerResources(cons constraints.Value) *resourceRequirements {
+	var res resources
+	if cons.HasCpuPower() {
+		// One CPU core is 100 cpu-power, and 1e9 nano CPUs.
+		res.NanoCPUs = int64(*cons.CpuPower) * 1e7
+	}
+	if cons.HasMem() {
+		res.MemoryBytes = int64(*cons.Mem) * 1024 * 1024
+	}
+	if res == (resources{}) {
+		return nil
+	}
+	reservations := res
+	return &resourceRequirements{
+		Limits:       &res,
+		Reservations: &reservations,
+	}
+}
+
+// placementConstraints returns the swarm placement constraints
+// restricting tasks to nodes with the node labels specified by
+// the placement directive and the tags constraint.
+func placementConstraints(placement string, cons constraints.Value) ([]string, error) {
+	var result []string
+	if placement != "" {
+		for _, label := range strings.Split(placement, ",") {
+			key, value, ok := splitLabel(label)
+			if !ok {
+				return nil, errors.NotValidf("placement directive %q", placement)
+			}
+			result = append(result, fmt.Sprintf("node.labels.%s==%s", key, value))
+		}
+	}
+	if cons.Tags == nil {
+		return result, nil
+	}
+	for _, tag := range *cons.Tags {
+		negate := strings.HasPrefix(tag, "^")
+		key, value, ok := splitLabel(strings.TrimPrefix(tag, "^"))
+		if !ok {
+			return nil, errors.NotSupportedf("tag %q without a value", tag)
+		}
+		op := "=="
+		if negate {
+			op = "!="
+		}
+		result = append(result, fmt.Sprintf("node.labels.%s%s%s", key, op, value))
+	}
+	return result, nil
+}
+
+// splitLabel splits a "key=value" label into its key and value.
+func splitLabel(label string) (key, value string, ok bool) {
+	parts := strings.SplitN(strings.TrimSpace(label), "=", 2)
+	if len(parts) != 2 || parts[0] == "" {
+		return "", "", false
+	}
+	return parts[0], parts[1], true
+}
+
+// containerHealthcheck returns the docker healthcheck for the
+// liveness probe. Only exec probes can be supported, since
+// docker health checks are commands run inside the container.
+func containerHealthcheck(probe *caas.ContainerProbe) (*healthConfig, error) {
+	if probe == nil {
+		return nil, nil
+	}
+	if probe.Exec == nil {
+		return nil, errors.NotSupportedf("liveness probe without exec command")
+	}
+	return &healthConfig{
+		Test:        append([]string{"CMD"}, probe.Exec.Command...),
+		Interval:    int64(time.Duration(probe.PeriodSeconds) * time.Second),
+		Timeout:     int64(time.Duration(probe.TimeoutSeconds) * time.Second),
+		StartPeriod: int64(time.Duration(probe.InitialDelaySeconds) * time.Second),
+		Retries:     int(probe.FailureThreshold),
+	}, nil
+}
+
+// servicePorts returns the ports declared by the
+// service's container, which are recorded in the
+// container labels when the service is created.
+func servicePorts(svc *service) ([]caas.ContainerPort, error) {
+	var ports []caas.ContainerPort
+	portsLabel := svc.Spec.TaskTemplate.ContainerSpec.Labels[labelPorts]
+	if portsLabel == "" {
+		return nil, nil
+	}
+	for _, p := range strings.Split(portsLabel, ",") {
+		var port caas.ContainerPort
+		if _, err := fmt.Sscanf(strings.Replace(p, "/", " ", 1), "%d %s", &port.ContainerPort, &port.Protocol); err != nil {
+			return nil, errors.NotValidf("port %q", p)
+		}
+		ports = append(ports, port)
+	}
+	return ports, nil
+}
+
+func operatorImagePath(vers version.Number) string {
+	return fmt.Sprintf("%s:%s", operatorImageRepo, vers.String())
+}
+
+func operatorServiceName(appName string) string {
+	return "juju-operator-" + appName
+}
+
+func operatorConfigName(appName string, agentConf []byte) string {
+	sum := sha256.Sum256(agentConf)
+	return fmt.Sprintf("juju-operator-%s-%x", appName, sum[:8])
+}
+
+func applicationServiceName(appName string) string {
+	return "juju-" + appName
+}
+
+func unitServiceName(unitName string) string {
+	return "juju-" + names.NewUnitTag(unitName).String()
+}
+
+func volumeName(serviceName, volName string) string {
+	return serviceName + "-" + volName
+}
@@ -0,0 +1,327 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package provider
+
+import (
+	"net/http/httptest"
+	"time"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/caas"
+	"github.com/juju/juju/core/application"
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/status"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/workertest"
+)
+
+type SwarmSuite struct {
+	testing.IsolationSuite
+
+	fake   *fakeSwarm
+	clock  *testing.Clock
+	broker *swarmBroker
+}
+
+var _ = gc.Suite(&SwarmSuite{})
+
+func (s *SwarmSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.fake = newFakeSwarm()
+	server := httptest.NewServer(s.fake)
+	s.AddCleanup(func(*gc.C) { server.Close() })
+
+	api, err := newSwarmAPI(environs.CloudSpec{
+		Name:     "swarm",
+		Endpoint: server.URL,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.clock = testing.NewClock(time.Time{})
+	s.broker = newSwarmBroker(api, coretesting.ModelConfig(c), s.clock)
+}
+
+func (s *SwarmSuite) modelUUID() string {
+	return s.broker.modelConfig.UUID()
+}
+
+func basicServiceParams() *caas.ServiceParams {
+	return &caas.ServiceParams{
+		PodSpec: &caas.PodSpec{
+			Containers: []caas.ContainerSpec{{
+				Name:      "test",
+				ImageName: "juju/image",
+				Ports:     []caas.ContainerPort{{ContainerPort: 80, Protocol: "TCP"}},
+				Config:    map[string]string{"restricted": "yes"},
+			}},
+		},
+	}
+}
+
+// basicServiceSpec returns the spec of the service created
+// for basicServiceParams, without the mode.
+func (s *SwarmSuite) basicServiceSpec(name string, labels map[string]string) serviceSpec {
+	return serviceSpec{
+		Name:   name,
+		Labels: labels,
+		TaskTemplate: taskSpec{
+			ContainerSpec: containerSpec{
+				Image:  "juju/image",
+				Labels: map[string]string{labelPorts: "80/tcp"},
+				Env:    []string{"restricted=yes"},
+			},
+			Placement: &placement{},
+			Networks:  []networkAttachmentRef{{Target: "testenv"}},
+		},
+	}
+}
+
+func replicated(n uint64) serviceMode {
+	return serviceMode{Replicated: &replicatedService{Replicas: &n}}
+}
+
+func (s *SwarmSuite) TestEnsureServiceCreates(c *gc.C) {
+	err := s.broker.EnsureService("app-name", basicServiceParams(), 2, application.ConfigAttributes{})
+	c.Assert(err, jc.ErrorIsNil)
+
+	svc := s.fake.service("juju-app-name")
+	c.Assert(svc, gc.NotNil)
+	expected := s.basicServiceSpec("juju-app-name", map[string]string{
+		labelModel:       s.modelUUID(),
+		labelApplication: "app-name",
+	})
+	expected.Mode = replicated(2)
+	c.Assert(svc.Spec, jc.DeepEquals, expected)
+	c.Assert(s.fake.Calls(), jc.DeepEquals, []string{
+		"GET /services/juju-app-name",
+		"GET /services/juju-app-name",
+		"POST /services/create",
+	})
+}
+
+func (s *SwarmSuite) TestEnsureServiceUpdates(c *gc.C) {
+	existing := s.basicServiceSpec("juju-app-name", nil)
+	existing.Mode = replicated(1)
+	existing.EndpointSpec = &endpointSpec{
+		Mode:  "vip",
+		Ports: []portConfig{{Protocol: "tcp", TargetPort: 80, PublishMode: "ingress"}},
+	}
+	id := s.fake.addService(existing)
+
+	err := s.broker.EnsureService("app-name", basicServiceParams(), 3, application.ConfigAttributes{
+		caas.JujuServiceLabelsKey: "tier=web",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	svc := s.fake.service("juju-app-name")
+	c.Assert(svc.ID, gc.Equals, id)
+	c.Assert(svc.Version.Index, gc.Equals, uint64(2))
+	expected := s.basicServiceSpec("juju-app-name", map[string]string{
+		"tier":           "web",
+		labelModel:       s.modelUUID(),
+		labelApplication: "app-name",
+	})
+	expected.Mode = replicated(3)
+	// The published ports are preserved.
+	expected.EndpointSpec = existing.EndpointSpec
+	c.Assert(svc.Spec, jc.DeepEquals, expected)
+	c.Assert(s.fake.Calls(), jc.DeepEquals, []string{
+		"GET /services/juju-app-name",
+		"GET /services/juju-app-name",
+		"POST /services/" + id + "/update",
+	})
+}
+
+func (s *SwarmSuite) TestEnsureServiceOnePerNodeReplacesService(c *gc.C) {
+	existing := s.basicServiceSpec("juju-app-name", nil)
+	existing.Mode = replicated(2)
+	id := s.fake.addService(existing)
+
+	params := basicServiceParams()
+	params.DeploymentMode = caas.DeploymentOnePerNode
+	err := s.broker.EnsureService("app-name", params, 2, application.ConfigAttributes{})
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The mode of a service cannot be changed, so
+	// the service is replaced.
+	svc := s.fake.service("juju-app-name")
+	c.Assert(svc.ID, gc.Not(gc.Equals), id)
+	c.Assert(svc.Spec.Mode, jc.DeepEquals, serviceMode{Global: &globalService{}})
+	c.Assert(s.fake.Calls(), jc.DeepEquals, []string{
+		"GET /services/juju-app-name",
+		"DELETE /services/juju-app-name",
+		"GET /services/juju-app-name",
+		"POST /services/create",
+	})
+}
+
+func (s *SwarmSuite) TestEnsureServiceNoUnitsDeletesService(c *gc.C) {
+	existing := s.basicServiceSpec("juju-app-name", nil)
+	existing.Mode = replicated(2)
+	s.fake.addService(existing)
+
+	err := s.broker.EnsureService("app-name", basicServiceParams(), 0, application.ConfigAttributes{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.fake.service("juju-app-name"), gc.IsNil)
+}
+
+func (s *SwarmSuite) TestEnsureServiceNotSupported(c *gc.C) {
+	params := basicServiceParams()
+	params.DeploymentMode = caas.DeploymentStateful
+	err := s.broker.EnsureService("app-name", params, 1, application.ConfigAttributes{})
+	c.Assert(err, gc.ErrorMatches, "stateful deployment mode not supported")
+
+	params = basicServiceParams()
+	params.PodSpec.Containers = append(params.PodSpec.Containers, caas.ContainerSpec{Name: "sidecar"})
+	err = s.broker.EnsureService("app-name", params, 1, application.ConfigAttributes{})
+	c.Assert(err, gc.ErrorMatches, "parsing unit spec for app-name: 2 containers not supported")
+
+	c.Assert(s.fake.Calls(), gc.HasLen, 0)
+}
+
+func (s *SwarmSuite) TestEnsureServiceMissingPodSpec(c *gc.C) {
+	err := s.broker.EnsureService("app-name", &caas.ServiceParams{}, 1, application.ConfigAttributes{})
+	c.Assert(err, gc.ErrorMatches, "missing pod spec")
+}
+
+func (s *SwarmSuite) TestEnsureUnit(c *gc.C) {
+	err := s.broker.EnsureUnit("app-name", "app-name/0", basicServiceParams())
+	c.Assert(err, jc.ErrorIsNil)
+
+	svc := s.fake.service("juju-unit-app-name-0")
+	c.Assert(svc, gc.NotNil)
+	expected := s.basicServiceSpec("juju-unit-app-name-0", map[string]string{
+		labelModel:       s.modelUUID(),
+		labelApplication: "app-name",
+		labelUnit:        "app-name/0",
+	})
+	expected.Mode = replicated(1)
+	c.Assert(svc.Spec, jc.DeepEquals, expected)
+
+	// Ensuring the unit again updates the service.
+	params := basicServiceParams()
+	params.PodSpec.Containers[0].ImageName = "juju/image:2"
+	err = s.broker.EnsureUnit("app-name", "app-name/0", params)
+	c.Assert(err, jc.ErrorIsNil)
+	svc = s.fake.service("juju-unit-app-name-0")
+	c.Assert(svc.Spec.TaskTemplate.ContainerSpec.Image, gc.Equals, "juju/image:2")
+	c.Assert(s.fake.Calls(), jc.DeepEquals, []string{
+		"GET /services/juju-unit-app-name-0",
+		"POST /services/create",
+		"GET /services/juju-unit-app-name-0",
+		"POST /services/" + svc.ID + "/update",
+	})
+}
+
+func (s *SwarmSuite) TestEnsureUnitMissingPodSpec(c *gc.C) {
+	err := s.broker.EnsureUnit("app-name", "app-name/0", nil)
+	c.Assert(err, gc.ErrorMatches, "missing pod spec")
+}
+
+func (s *SwarmSuite) TestDeleteService(c *gc.C) {
+	existing := s.basicServiceSpec("juju-app-name", nil)
+	existing.Mode = replicated(1)
+	s.fake.addService(existing)
+
+	err := s.broker.DeleteService("app-name")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.fake.service("juju-app-name"), gc.IsNil)
+	c.Assert(s.fake.Calls(), jc.DeepEquals, []string{
+		"DELETE /services/juju-app-name",
+	})
+}
+
+func (s *SwarmSuite) TestDeleteServiceNotFound(c *gc.C) {
+	err := s.broker.DeleteService("app-name")
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func runningTask(id, address string) task {
+	return task{
+		ID:           id,
+		DesiredState: taskStateRunning,
+		Status:       taskStatus{State: taskStateRunning},
+		NetworksAttachments: []networkAttachment{{
+			Network:   network{Name: "testenv"},
+			Addresses: []string{address + "/24"},
+		}},
+	}
+}
+
+type unitChange struct {
+	kind    caas.UnitChangeKind
+	id      string
+	address string
+	status  status.Status
+}
+
+func (s *SwarmSuite) nextChanges(c *gc.C, w caas.UnitsWatcher) []unitChange {
+	select {
+	case changes, ok := <-w.Changes():
+		c.Assert(ok, jc.IsTrue)
+		var result []unitChange
+		for _, change := range changes {
+			result = append(result, unitChange{
+				kind:    change.Kind,
+				id:      change.Unit.Id,
+				address: change.Unit.Address,
+				status:  change.Unit.Status.Status,
+			})
+		}
+		return result
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for unit changes")
+	}
+	return nil
+}
+
+func (s *SwarmSuite) assertNoChanges(c *gc.C, w caas.UnitsWatcher) {
+	select {
+	case changes := <-w.Changes():
+		c.Fatalf("unexpected unit changes: %v", changes)
+	case <-time.After(coretesting.ShortWait):
+	}
+}
+
+func (s *SwarmSuite) TestWatchUnits(c *gc.C) {
+	spec := s.basicServiceSpec("juju-app-name", map[string]string{
+		labelModel:       s.modelUUID(),
+		labelApplication: "app-name",
+	})
+	spec.Mode = replicated(2)
+	id := s.fake.addService(spec)
+	superseded := runningTask("task-0", "10.0.0.9")
+	superseded.DesiredState = taskStateShutdown
+	s.fake.setTasks(id, superseded, runningTask("task-1", "10.0.0.1"))
+
+	w, err := s.broker.WatchUnits("app-name")
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.CleanKill(c, w)
+
+	// Tasks which are not meant to be running are not units.
+	c.Assert(s.nextChanges(c, w), jc.DeepEquals, []unitChange{
+		{caas.UnitAdded, "task-1", "10.0.0.1", status.Running},
+	})
+
+	// Unchanged units are not reported.
+	s.clock.WaitAdvance(pollInterval, coretesting.LongWait, 1)
+	s.assertNoChanges(c, w)
+
+	failed := runningTask("task-1", "10.0.0.1")
+	failed.Status = taskStatus{State: taskStateFailed, Err: "exit status 1"}
+	s.fake.setTasks(id, failed, runningTask("task-2", "10.0.0.2"))
+	s.clock.WaitAdvance(pollInterval, coretesting.LongWait, 1)
+	c.Assert(s.nextChanges(c, w), jc.DeepEquals, []unitChange{
+		{caas.UnitUpdated, "task-1", "10.0.0.1", status.Error},
+		{caas.UnitAdded, "task-2", "10.0.0.2", status.Running},
+	})
+
+	s.fake.setTasks(id, runningTask("task-2", "10.0.0.2"))
+	s.clock.WaitAdvance(pollInterval, coretesting.LongWait, 1)
+	c.Assert(s.nextChanges(c, w), jc.DeepEquals, []unitChange{
+		{caas.UnitRemoved, "task-1", "10.0.0.1", status.Error},
+	})
+}
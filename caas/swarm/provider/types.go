@@ -0,0 +1,210 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package provider
+
+// The types in this file are the subset of the Docker Engine API
+// (version 1.30) swarm mode objects used by the broker. Only the
+// fields Juju sets or reads are defined.
+
+type objectVersion struct {
+	Index uint64 `json:"Index"`
+}
+
+type service struct {
+	ID      string        `json:"ID"`
+	Version objectVersion `json:"Version"`
+	Spec    serviceSpec   `json:"Spec"`
+}
+
+type serviceSpec struct {
+	Name         string            `json:"Name"`
+	Labels       map[string]string `json:"Labels,omitempty"`
+	TaskTemplate taskSpec          `json:"TaskTemplate"`
+	Mode         serviceMode       `json:"Mode"`
+	EndpointSpec *endpointSpec     `json:"EndpointSpec,omitempty"`
+}
+
+type taskSpec struct {
+	ContainerSpec containerSpec          `json:"ContainerSpec"`
+	Resources     *resourceRequirements  `json:"Resources,omitempty"`
+	Placement     *placement             `json:"Placement,omitempty"`
+	Networks      []networkAttachmentRef `json:"Networks,omitempty"`
+	ForceUpdate   uint64                 `json:"ForceUpdate,omitempty"`
+}
+
+type containerSpec struct {
+	Image       string            `json:"Image"`
+	Labels      map[string]string `json:"Labels,omitempty"`
+	Env         []string          `json:"Env,omitempty"`
+	Mounts      []mount           `json:"Mounts,omitempty"`
+	Configs     []configReference `json:"Configs,omitempty"`
+	Healthcheck *healthConfig     `json:"Healthcheck,omitempty"`
+}
+
+type mount struct {
+	Type     string `json:"Type"`
+	Source   string `json:"Source,omitempty"`
+	Target   string `json:"Target"`
+	ReadOnly bool   `json:"ReadOnly,omitempty"`
+}
+
+type configReference struct {
+	File       configReferenceFile `json:"File"`
+	ConfigID   string              `json:"ConfigID"`
+	ConfigName string              `json:"ConfigName"`
+}
+
+type configReferenceFile struct {
+	Name string `json:"Name"`
+	UID  string `json:"UID"`
+	GID  string `json:"GID"`
+	Mode uint32 `json:"Mode"`
+}
+
+type healthConfig struct {
+	Test        []string `json:"Test,omitempty"`
+	Interval    int64    `json:"Interval,omitempty"`
+	Timeout     int64    `json:"Timeout,omitempty"`
+	StartPeriod int64    `json:"StartPeriod,omitempty"`
+	Retries     int      `json:"Retries,omitempty"`
+}
+
+type resourceRequirements struct {
+	Limits       *resources `json:"Limits,omitempty"`
+	Reservations *resources `json:"Reservations,omitempty"`
+}
+
+type resources struct {
+	NanoCPUs    int64 `json:"NanoCPUs,omitempty"`
+	MemoryBytes int64 `json:"MemoryBytes,omitempty"`
+}
+
+type placement struct {
+	Constraints []string `json:"Constraints,omitempty"`
+}
+
+type networkAttachmentRef struct {
+	Target string `json:"Target"`
+}
+
+type serviceMode struct {
+	Replicated *replicatedService `json:"Replicated,omitempty"`
+	Global     *globalService     `json:"Global,omitempty"`
+}
+
+type replicatedService struct {
+	Replicas *uint64 `json:"Replicas,omitempty"`
+}
+
+type globalService struct{}
+
+type endpointSpec struct {
+	Mode  string       `json:"Mode,omitempty"`
+	Ports []portConfig `json:"Ports,omitempty"`
+}
+
+type portConfig struct {
+	Protocol      string `json:"Protocol,omitempty"`
+	TargetPort    uint32 `json:"TargetPort"`
+	PublishedPort uint32 `json:"PublishedPort,omitempty"`
+	PublishMode   string `json:"PublishMode,omitempty"`
+}
+
+type task struct {
+	ID                  string              `json:"ID"`
+	ServiceID           string              `json:"ServiceID"`
+	Status              taskStatus          `json:"Status"`
+	DesiredState        string              `json:"DesiredState"`
+	NetworksAttachments []networkAttachment `json:"NetworksAttachments,omitempty"`
+}
+
+type taskStatus struct {
+	State   string `json:"State"`
+	Message string `json:"Message"`
+	Err     string `json:"Err,omitempty"`
+}
+
+type networkAttachment struct {
+	Network   network  `json:"Network"`
+	Addresses []string `json:"Addresses"`
+}
+
+type network struct {
+	ID     string            `json:"Id"`
+	Name   string            `json:"Name"`
+	Labels map[string]string `json:"Labels,omitempty"`
+}
+
+type networkCreate struct {
+	Name           string            `json:"Name"`
+	Driver         string            `json:"Driver"`
+	Attachable     bool              `json:"Attachable"`
+	CheckDuplicate bool              `json:"CheckDuplicate"`
+	Labels         map[string]string `json:"Labels,omitempty"`
+}
+
+type swarmConfig struct {
+	ID   string          `json:"ID"`
+	Spec swarmConfigSpec `json:"Spec"`
+}
+
+type swarmConfigSpec struct {
+	Name   string            `json:"Name"`
+	Labels map[string]string `json:"Labels,omitempty"`
+	// Data is base64 encoded by encoding/json.
+	Data []byte `json:"Data"`
+}
+
+type versionResponse struct {
+	Version    string `json:"Version"`
+	APIVersion string `json:"ApiVersion"`
+}
+
+type infoResponse struct {
+	Plugins pluginsInfo `json:"Plugins"`
+}
+
+type pluginsInfo struct {
+	Volume []string `json:"Volume"`
+}
+
+type node struct {
+	ID          string          `json:"ID"`
+	Spec        nodeSpec        `json:"Spec"`
+	Description nodeDescription `json:"Description"`
+}
+
+type nodeSpec struct {
+	Availability string `json:"Availability"`
+}
+
+type nodeDescription struct {
+	Hostname  string    `json:"Hostname"`
+	Resources resources `json:"Resources"`
+}
+
+type idResponse struct {
+	ID string `json:"ID"`
+}
+
+type errorResponse struct {
+	Message string `json:"message"`
+}
+
+const (
+	taskStateNew       = "new"
+	taskStatePending   = "pending"
+	taskStateAssigned  = "assigned"
+	taskStateAccepted  = "accepted"
+	taskStatePreparing = "preparing"
+	taskStateStarting  = "starting"
+	taskStateRunning   = "running"
+	taskStateComplete  = "complete"
+	taskStateShutdown  = "shutdown"
+	taskStateFailed    = "failed"
+	taskStateRejected  = "rejected"
+	taskStateOrphaned  = "orphaned"
+)
+
+const nodeAvailabilityActive = "active"
@@ -0,0 +1,137 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package provider
+
+import (
+	"reflect"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils/clock"
+	"gopkg.in/tomb.v1"
+
+	"github.com/juju/juju/caas"
+	"github.com/juju/juju/worker/catacomb"
+)
+
+// swarmWatcher reports changes to the units of an
+// application. The Docker Engine API has no watch
+// support for services or tasks, so the watcher
+// periodically polls the units, and reports those
+// which have been added, updated or removed since
+// the previous poll.
+type swarmWatcher struct {
+	catacomb catacomb.Catacomb
+
+	out   chan []caas.UnitChange
+	name  string
+	units func() ([]caas.Unit, error)
+	clock clock.Clock
+}
+
+func newSwarmWatcher(units func() ([]caas.Unit, error), name string, clock clock.Clock) (*swarmWatcher, error) {
+	w := &swarmWatcher{
+		out:   make(chan []caas.UnitChange),
+		units: units,
+		name:  name,
+		clock: clock,
+	}
+	err := catacomb.Invoke(catacomb.Plan{
+		Site: &w.catacomb,
+		Work: w.loop,
+	})
+	return w, err
+}
+
+const (
+	pollInterval = 5 * time.Second
+
+	unitReasonTaskStopped = "task no longer running"
+)
+
+func (w *swarmWatcher) loop() error {
+	defer close(w.out)
+
+	known := make(map[string]caas.Unit)
+	pending := make(map[string]caas.UnitChange)
+	if err := w.poll(known, pending); err != nil {
+		return errors.Trace(err)
+	}
+	// Set out now so that initial event is sent.
+	out := w.out
+	poll := w.clock.After(pollInterval)
+	for {
+		select {
+		case <-w.catacomb.Dying():
+			return tomb.ErrDying
+		case <-poll:
+			if err := w.poll(known, pending); err != nil {
+				return errors.Trace(err)
+			}
+			if len(pending) > 0 {
+				out = w.out
+			}
+			poll = w.clock.After(pollInterval)
+		case out <- caas.SortedUnitChanges(pending):
+			logger.Debugf("fire units watcher for %v", w.name)
+			pending = make(map[string]caas.UnitChange)
+			out = nil
+		}
+	}
+}
+
+// poll records in pending the changes between the known
+// units and the current ones, and updates known.
+func (w *swarmWatcher) poll(known map[string]caas.Unit, pending map[string]caas.UnitChange) error {
+	units, err := w.units()
+	if err != nil {
+		return errors.Annotatef(err, "polling units of %s", w.name)
+	}
+	current := make(map[string]bool)
+	for _, u := range units {
+		current[u.Id] = true
+		existing, ok := known[u.Id]
+		known[u.Id] = u
+		if !ok {
+			caas.MergeUnitChange(pending, caas.UnitChange{Kind: caas.UnitAdded, Unit: u})
+		} else if !sameUnit(existing, u) {
+			caas.MergeUnitChange(pending, caas.UnitChange{Kind: caas.UnitUpdated, Unit: u})
+		}
+	}
+	for id, u := range known {
+		if current[id] {
+			continue
+		}
+		delete(known, id)
+		caas.MergeUnitChange(pending, caas.UnitChange{
+			Kind:   caas.UnitRemoved,
+			Unit:   u,
+			Reason: unitReasonTaskStopped,
+		})
+	}
+	return nil
+}
+
+// sameUnit reports whether the units have the same
+// address, ports and status, ignoring the status time.
+func sameUnit(a, b caas.Unit) bool {
+	a.Status.Since, b.Status.Since = nil, nil
+	return reflect.DeepEqual(a, b)
+}
+
+// Changes returns the event channel for this watcher.
+func (w *swarmWatcher) Changes() caas.UnitsChannel {
+	return w.out
+}
+
+// Kill asks the watcher to stop without waiting for it do so.
+func (w *swarmWatcher) Kill() {
+	w.catacomb.Kill(nil)
+}
+
+// Wait waits for the watcher to die and returns any
+// error encountered when it was running.
+func (w *swarmWatcher) Wait() error {
+	return w.catacomb.Wait()
+}
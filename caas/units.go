@@ -0,0 +1,93 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package caas
+
+import (
+	"sort"
+
+	"github.com/juju/juju/watcher"
+)
+
+// UnitChangeKind describes how a unit has changed.
+type UnitChangeKind string
+
+const (
+	// UnitAdded indicates a unit which was not
+	// previously reported now exists.
+	UnitAdded UnitChangeKind = "added"
+
+	// UnitUpdated indicates a change to the address,
+	// ports or status of an existing unit.
+	UnitUpdated UnitChangeKind = "updated"
+
+	// UnitRemoved indicates a unit no longer exists,
+	// or is being removed.
+	UnitRemoved UnitChangeKind = "removed"
+)
+
+// UnitChange describes a change to a unit of an application.
+type UnitChange struct {
+	// Kind is the kind of change.
+	Kind UnitChangeKind
+
+	// Unit holds the latest known details of the unit.
+	// For removed units, only the Id is guaranteed
+	// to be set.
+	Unit Unit
+
+	// Reason optionally describes the cause
+	// of the change, e.g. why a unit was removed.
+	Reason string
+}
+
+// UnitsChannel is a change channel as described in the CoreWatcher docs.
+//
+// The first value sent reports all existing units as added; subsequent
+// values report units which have been added, updated or removed since
+// the previous value was sent.
+type UnitsChannel <-chan []UnitChange
+
+// UnitsWatcher conveniently ties a UnitsChannel to the
+// worker.Worker that represents its validity.
+type UnitsWatcher interface {
+	watcher.CoreWatcher
+	Changes() UnitsChannel
+}
+
+// MergeUnitChange merges the change into the pending changes,
+// keyed on unit id, which have not yet been reported. A unit
+// which is reported as added and then updated is still added;
+// a unit which is added and then removed is not reported at all.
+func MergeUnitChange(pending map[string]UnitChange, change UnitChange) {
+	id := change.Unit.Id
+	existing, ok := pending[id]
+	if !ok {
+		pending[id] = change
+		return
+	}
+	switch {
+	case existing.Kind == UnitAdded && change.Kind == UnitUpdated:
+		change.Kind = UnitAdded
+	case existing.Kind == UnitAdded && change.Kind == UnitRemoved:
+		delete(pending, id)
+		return
+	case existing.Kind == UnitRemoved && change.Kind == UnitAdded:
+		// The unit was removed and re-added with
+		// the same id, so report it as updated.
+		change.Kind = UnitUpdated
+	}
+	pending[id] = change
+}
+
+// SortedUnitChanges returns the pending changes ordered by unit id.
+func SortedUnitChanges(pending map[string]UnitChange) []UnitChange {
+	changes := make([]UnitChange, 0, len(pending))
+	for _, change := range pending {
+		changes = append(changes, change)
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Unit.Id < changes[j].Unit.Id
+	})
+	return changes
+}
@@ -0,0 +1,80 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package caas_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/caas"
+	"github.com/juju/juju/testing"
+)
+
+type UnitsSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&UnitsSuite{})
+
+func change(kind caas.UnitChangeKind, id, address string) caas.UnitChange {
+	return caas.UnitChange{
+		Kind: kind,
+		Unit: caas.Unit{Id: id, Address: address},
+	}
+}
+
+func (s *UnitsSuite) TestMergeUnitChangeNew(c *gc.C) {
+	pending := make(map[string]caas.UnitChange)
+	caas.MergeUnitChange(pending, change(caas.UnitUpdated, "a", "10.0.0.1"))
+	caas.MergeUnitChange(pending, change(caas.UnitRemoved, "b", ""))
+	c.Assert(pending, jc.DeepEquals, map[string]caas.UnitChange{
+		"a": change(caas.UnitUpdated, "a", "10.0.0.1"),
+		"b": change(caas.UnitRemoved, "b", ""),
+	})
+}
+
+func (s *UnitsSuite) TestMergeUnitChangeAddedThenUpdated(c *gc.C) {
+	pending := make(map[string]caas.UnitChange)
+	caas.MergeUnitChange(pending, change(caas.UnitAdded, "a", ""))
+	caas.MergeUnitChange(pending, change(caas.UnitUpdated, "a", "10.0.0.1"))
+	c.Assert(pending, jc.DeepEquals, map[string]caas.UnitChange{
+		"a": change(caas.UnitAdded, "a", "10.0.0.1"),
+	})
+}
+
+func (s *UnitsSuite) TestMergeUnitChangeAddedThenRemoved(c *gc.C) {
+	pending := make(map[string]caas.UnitChange)
+	caas.MergeUnitChange(pending, change(caas.UnitAdded, "a", ""))
+	caas.MergeUnitChange(pending, change(caas.UnitRemoved, "a", ""))
+	c.Assert(pending, gc.HasLen, 0)
+}
+
+func (s *UnitsSuite) TestMergeUnitChangeRemovedThenAdded(c *gc.C) {
+	pending := make(map[string]caas.UnitChange)
+	caas.MergeUnitChange(pending, change(caas.UnitRemoved, "a", ""))
+	caas.MergeUnitChange(pending, change(caas.UnitAdded, "a", "10.0.0.1"))
+	c.Assert(pending, jc.DeepEquals, map[string]caas.UnitChange{
+		"a": change(caas.UnitUpdated, "a", "10.0.0.1"),
+	})
+}
+
+func (s *UnitsSuite) TestMergeUnitChangeUpdatedThenRemoved(c *gc.C) {
+	pending := make(map[string]caas.UnitChange)
+	caas.MergeUnitChange(pending, change(caas.UnitUpdated, "a", "10.0.0.1"))
+	caas.MergeUnitChange(pending, change(caas.UnitRemoved, "a", ""))
+	c.Assert(pending, jc.DeepEquals, map[string]caas.UnitChange{
+		"a": change(caas.UnitRemoved, "a", ""),
+	})
+}
+
+func (s *UnitsSuite) TestSortedUnitChanges(c *gc.C) {
+	pending := map[string]caas.UnitChange{
+		"b": change(caas.UnitRemoved, "b", ""),
+		"a": change(caas.UnitAdded, "a", "10.0.0.1"),
+	}
+	c.Assert(caas.SortedUnitChanges(pending), jc.DeepEquals, []caas.UnitChange{
+		change(caas.UnitAdded, "a", "10.0.0.1"),
+		change(caas.UnitRemoved, "b", ""),
+	})
+}
@@ -203,7 +203,8 @@ type region struct {
 }
 
 var caasCloudTypes = map[string]bool{
-	"kubernetes": true,
+	"kubernetes":   true,
+	"docker-swarm": true,
 }
 
 func CloudIsCAAS(cloud Cloud) bool {
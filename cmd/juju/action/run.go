@@ -56,16 +56,30 @@ is a boolean true in YAML.
 If --params is passed, along with key.key...=value explicit arguments, the
 explicit arguments will override the parameter file.
 
+With --wait, the command blocks until the action completes on every unit,
+or until the optional timeout passes, and then prints each unit's results in
+the chosen format. The command fails if the action fails on any unit, or if
+the timeout passes before it completes, so scripts need not poll
+'juju show-action-output'.
+
 Examples:
 
 $ juju run-action mysql/3 backup --wait
-action-id: <ID>
-result:
-  status: success
-  file:
-    size: 873.2
-    units: GB
-    name: foo.sql
+unit-mysql-3:
+  id: <ID>
+  results:
+    status: success
+    file:
+      size: 873.2
+      units: GB
+      name: foo.sql
+  status: completed
+  timing:
+    ...
+  unit: mysql/3
+
+$ juju run-action mysql/3 mysql/4 backup --wait=10m --format=json
+...
 
 
 $ juju run-action mysql/3 backup 
@@ -310,23 +324,46 @@ func (c *runCommand) Run(ctx *cmd.Context) error {
 		wait = time.NewTimer(c.wait.d)
 	}
 
+	var timedOut, failed []string
 	for _, result := range results.Results {
 		tag, err := names.ParseActionTag(result.Action.Tag)
 		if err != nil {
 			return err
 		}
-		result, err = GetActionResult(api, tag.Id(), wait)
-		if err != nil {
-			return errors.Trace(err)
-		}
 		unitTag, err := names.ParseUnitTag(result.Action.Receiver)
 		if err != nil {
 			return err
 		}
-		d := FormatActionResult(result)
+		actionResult, err := GetActionResult(api, tag.Id(), wait)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		d := FormatActionResult(actionResult)
 		d["id"] = tag.Id()       // Action ID is required in case we timed out.
 		d["unit"] = unitTag.Id() // Formatted unit is nice to have.
 		output[result.Action.Receiver] = d
+
+		switch actionResult.Status {
+		case params.ActionRunning, params.ActionPending:
+			timedOut = append(timedOut, unitTag.Id())
+			// The wait timer has fired, so the remaining
+			// actions are only checked once.
+			wait = time.NewTimer(0)
+		case params.ActionFailed:
+			failed = append(failed, unitTag.Id())
+		}
 	}
-	return c.out.Write(ctx, output)
+	if err := c.out.Write(ctx, output); err != nil {
+		return errors.Trace(err)
+	}
+
+	// Report incomplete and failed actions in the exit
+	// status, so that scripts need not parse the output.
+	if len(timedOut) > 0 {
+		return errors.Errorf("timed out waiting for %q on %s", c.actionName, strings.Join(timedOut, ", "))
+	}
+	if len(failed) > 0 {
+		return errors.Errorf("%q failed on %s", c.actionName, strings.Join(failed, ", "))
+	}
+	return nil
 }
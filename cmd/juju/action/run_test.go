@@ -7,6 +7,7 @@ import (
 	"bytes"
 	"errors"
 	"strings"
+	"time"
 	"unicode/utf8"
 
 	"github.com/juju/cmd/cmdtesting"
@@ -413,3 +414,78 @@ func (s *RunSuite) TestRun(c *gc.C) {
 		}
 	}
 }
+
+func (s *RunSuite) runWait(c *gc.C, client *fakeAPIClient, wait string) (string, error) {
+	restore := s.patchAPIClient(client)
+	defer restore()
+	wrappedCommand, _ := action.NewRunCommandForTest(s.store)
+	ctx, err := cmdtesting.RunCommand(c, wrappedCommand, "-m", "admin", wait, validUnitId, "some-action")
+	return cmdtesting.Stdout(ctx), err
+}
+
+func waitResult(status string) []params.ActionResult {
+	return []params.ActionResult{{
+		Action: &params.Action{
+			Tag:      validActionTagString,
+			Receiver: names.NewUnitTag(validUnitId).String(),
+		},
+		Status: status,
+		Output: map[string]interface{}{
+			"foo": map[string]interface{}{
+				"bar": "baz",
+			},
+		},
+		Enqueued:  time.Date(2015, time.February, 14, 8, 13, 0, 0, time.UTC),
+		Completed: time.Date(2015, time.February, 14, 8, 15, 30, 0, time.UTC),
+	}}
+}
+
+func (s *RunSuite) TestRunWait(c *gc.C) {
+	client := makeFakeClient(
+		0, 10*time.Second,
+		tagsForIdPrefix(validActionId, validActionTagString),
+		waitResult(params.ActionCompleted),
+		params.ActionsByNames{}, "",
+	)
+	out, err := s.runWait(c, client, "--wait")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(out, gc.Equals, `
+unit-mysql-0:
+  id: f47ac10b-58cc-4372-a567-0e02b2c3d479
+  results:
+    foo:
+      bar: baz
+  status: completed
+  timing:
+    completed: 2015-02-14 08:15:30 +0000 UTC
+    enqueued: 2015-02-14 08:13:00 +0000 UTC
+  unit: mysql/0
+`[1:])
+}
+
+func (s *RunSuite) TestRunWaitFailed(c *gc.C) {
+	client := makeFakeClient(
+		0, 10*time.Second,
+		tagsForIdPrefix(validActionId, validActionTagString),
+		waitResult(params.ActionFailed),
+		params.ActionsByNames{}, "",
+	)
+	out, err := s.runWait(c, client, "--wait=1m")
+	c.Assert(err, gc.ErrorMatches, `"some-action" failed on mysql/0`)
+	c.Check(out, jc.Contains, "status: failed")
+}
+
+func (s *RunSuite) TestRunWaitTimeout(c *gc.C) {
+	// The API reports the action as pending until long
+	// after the command has stopped waiting.
+	client := makeFakeClient(
+		10*time.Second, 10*time.Second,
+		tagsForIdPrefix(validActionId, validActionTagString),
+		waitResult(params.ActionCompleted),
+		params.ActionsByNames{}, "",
+	)
+	out, err := s.runWait(c, client, "--wait=10ms")
+	c.Assert(err, gc.ErrorMatches, `timed out waiting for "some-action" on mysql/0`)
+	c.Check(out, jc.Contains, "id: f47ac10b-58cc-4372-a567-0e02b2c3d479")
+	c.Check(out, jc.Contains, "status: pending")
+}
@@ -38,6 +38,15 @@ also supports the placement directive ("--to") for targeting specific
 machines or containers, which will bypass application and model
 constraints.
 
+In Kubernetes models, units are pods and are not placed on machines.
+There the placement directives are node labels, given as key=value,
+which the nodes running the application's pods must have. The
+directive "spread=node" or "spread=zone" asks that the pods be
+scheduled on different nodes, or in different zones, where possible.
+The pods of an application are all scheduled alike, so the directives
+apply to all of the application's units, replacing any given when the
+application was deployed.
+
 Examples:
 
 Add five units of wordpress on five new machines:
@@ -68,6 +77,10 @@ Add a unit of mariadb to LXD container number 3 on machine 24:
 Add a unit of mariadb to LXD container on a new machine:
     juju add-unit mariadb --to lxd
 
+Add two units of gitlab, in a Kubernetes model, to nodes labelled
+with disktype=ssd, spreading the pods across zones:
+    juju add-unit gitlab -n 2 --to disktype=ssd,spread=zone
+
 See also: 
     remove-unit`[1:]
 
@@ -23,6 +23,7 @@ import (
 	"gopkg.in/juju/charmrepo.v2"
 	csparams "gopkg.in/juju/charmrepo.v2/csclient/params"
 	"gopkg.in/juju/names.v2"
+	"gopkg.in/macaroon-bakery.v1/httpbakery"
 	"gopkg.in/macaroon.v1"
 	"gopkg.in/yaml.v2"
 
@@ -55,6 +56,16 @@ type deploymentLogger interface {
 	Infof(string, ...interface{})
 }
 
+// DeployBundle deploys the given bundle data to the model of the given
+// API connection, fetching charms from the charm store through the given
+// bakery client. It allows commands which generate bundles, such as
+// clone-model, to deploy them as the deploy command does.
+func DeployBundle(ctx *cmd.Context, apiRoot api.Connection, bakeryClient *httpbakery.Client, data *charm.BundleData) error {
+	deployAPI := newDeployAPIAdapter(apiRoot, bakeryClient, "")
+	_, err := deployBundle("", data, nil, "", deployAPI, ctx, nil, false, false, nil)
+	return errors.Trace(err)
+}
+
 // deployBundle deploys the given bundle data using the given API client and
 // charm store client. The deployment is not transactional, and its progress is
 // notified using the given deployment logger.
@@ -7,6 +7,8 @@ import (
 	"fmt"
 	"io/ioutil"
 	"os"
+	"sort"
+	"strconv"
 	"strings"
 	"unicode/utf8"
 
@@ -14,6 +16,7 @@ import (
 	"github.com/juju/errors"
 	"github.com/juju/gnuflag"
 	"github.com/juju/utils/keyvalues"
+	"gopkg.in/yaml.v2"
 
 	"github.com/juju/juju/api/application"
 	"github.com/juju/juju/apiserver/params"
@@ -41,6 +44,14 @@ Examples:
     juju config apache2 --file path/to/config.yaml
     juju config mysql dataset-size=80% backup_dir=/vol1/mysql/backups
     juju config apache2 --model mymodel --file /home/ubuntu/mysql.yaml
+    juju config mysql --diff dataset-size=80%
+    juju config apache2 --dry-run --file path/to/config.yaml
+
+When setting or resetting values, --diff shows each setting that will
+change, with its current and proposed values, before making the changes.
+Values that are the charm's defaults are marked as such, and a reset
+setting is shown taking its default value. --dry-run shows the changes
+without making them.
 
 See also:
     deploy
@@ -76,7 +87,10 @@ type configCommand struct {
 	reset           []string // Holds the keys to be reset until parsed.
 	resetKeys       []string // Holds the keys to be reset once parsed.
 	useFile         bool
+	configYAML      []byte // Holds the contents of the config file once read.
 	values          attributes
+	diff            bool
+	dryRun          bool
 }
 
 // applicationAPI is an interface to allow passing in a fake implementation under test.
@@ -110,6 +124,8 @@ func (c *configCommand) SetFlags(f *gnuflag.FlagSet) {
 	c.out.AddFlags(f, "yaml", output.DefaultFormatters)
 	f.Var(&c.configFile, "file", "path to yaml-formatted application config")
 	f.Var(cmd.NewAppendStringsValue(&c.reset), "reset", "Reset the provided comma delimited keys")
+	f.BoolVar(&c.diff, "diff", false, "Show the changes to the configuration before making them")
+	f.BoolVar(&c.dryRun, "dry-run", false, "Show the changes to the configuration without making them")
 }
 
 // getAPI either uses the fake API set at test time or that is nil, gets a real
@@ -143,14 +159,24 @@ func (c *configCommand) Init(args []string) error {
 	c.applicationName = args[0]
 	args = args[1:]
 
+	var err error
 	switch len(args) {
 	case 0:
-		return c.handleZeroArgs()
+		err = c.handleZeroArgs()
 	case 1:
-		return c.handleOneArg(args)
+		err = c.handleOneArg(args)
 	default:
-		return c.handleArgs(args)
+		err = c.handleArgs(args)
+	}
+	if err != nil {
+		return err
 	}
+
+	changing := c.useFile || len(c.values) > 0 || len(c.resetKeys) > 0
+	if (c.diff || c.dryRun) && !changing {
+		return errors.New("--diff and --dry-run can only be used when setting or resetting values")
+	}
+	return nil
 }
 
 // handleZeroArgs handles the case where there are no positional args.
@@ -252,6 +278,14 @@ func (c *configCommand) Run(ctx *cmd.Context) error {
 		return errors.Trace(err)
 	}
 	defer client.Close()
+	if c.diff || c.dryRun {
+		if err := c.showChanges(client, ctx); err != nil {
+			return errors.Trace(err)
+		}
+		if c.dryRun {
+			return nil
+		}
+	}
 	if len(c.resetKeys) > 0 {
 		if err := c.resetConfig(client, ctx); err != nil {
 			// We return this error naked as it is almost certainly going to be
@@ -319,25 +353,155 @@ func (c *configCommand) setConfig(client applicationAPI, ctx *cmd.Context) error
 // setConfigFromFile sets the application configuration from settings passed
 // in a YAML file.
 func (c *configCommand) setConfigFromFile(client applicationAPI, ctx *cmd.Context) error {
-	var (
-		b   []byte
-		err error
-	)
+	b, err := c.readConfigFile(ctx)
+	if err != nil {
+		return err
+	}
+	return block.ProcessBlockedError(
+		client.Update(
+			params.ApplicationUpdate{
+				ApplicationName: c.applicationName,
+				SettingsYAML:    string(b)}), block.BlockChange)
+}
+
+// readConfigFile returns the contents of the config file passed with
+// --file, reading it only once so that it may be read from stdin.
+func (c *configCommand) readConfigFile(ctx *cmd.Context) ([]byte, error) {
+	if c.configYAML != nil {
+		return c.configYAML, nil
+	}
 	if c.configFile.Path == "-" {
 		buf := bytes.Buffer{}
 		buf.ReadFrom(ctx.Stdin)
-		b = buf.Bytes()
+		c.configYAML = buf.Bytes()
 	} else {
-		b, err = c.configFile.Read(ctx)
+		b, err := c.configFile.Read(ctx)
 		if err != nil {
-			return err
+			return nil, err
 		}
+		c.configYAML = b
 	}
-	return block.ProcessBlockedError(
-		client.Update(
-			params.ApplicationUpdate{
-				ApplicationName: c.applicationName,
-				SettingsYAML:    string(b)}), block.BlockChange)
+	return c.configYAML, nil
+}
+
+// showChanges writes the settings that setting or resetting the
+// configuration would change, with their current and proposed values.
+func (c *configCommand) showChanges(client applicationAPI, ctx *cmd.Context) error {
+	proposed, err := c.proposedValues(ctx)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	results, err := client.Get(c.applicationName)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	keys := make([]string, 0, len(proposed))
+	for key := range proposed {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var changes []string
+	for _, key := range keys {
+		info, found := results.CharmConfig[key].(map[string]interface{})
+		if !found {
+			info, found = results.ApplicationConfig[key].(map[string]interface{})
+		}
+		if !found {
+			changes = append(changes, fmt.Sprintf("%s: (unknown setting) -> %s",
+				key, formatConfigValue(proposed[key], "")))
+			continue
+		}
+		current := describeConfigValue(info, info["value"], info["source"] == "default")
+		var next string
+		if value := proposed[key]; value == nil {
+			// The setting is being reset.
+			next = describeConfigValue(info, info["default"], true)
+		} else {
+			next = describeConfigValue(info, value, isDefaultValue(info, value))
+		}
+		if current != next {
+			changes = append(changes, fmt.Sprintf("%s: %s -> %s", key, current, next))
+		}
+	}
+
+	if len(changes) == 0 {
+		fmt.Fprintf(ctx.Stdout, "No configuration changes for %s.\n", c.applicationName)
+		return nil
+	}
+	fmt.Fprintf(ctx.Stdout, "Configuration changes for %s:\n", c.applicationName)
+	for _, change := range changes {
+		fmt.Fprintf(ctx.Stdout, "  %s\n", change)
+	}
+	return nil
+}
+
+// proposedValues returns the values being set, keyed by setting name.
+// Settings being reset have a nil value.
+func (c *configCommand) proposedValues(ctx *cmd.Context) (map[string]interface{}, error) {
+	proposed := make(map[string]interface{})
+	for _, key := range c.resetKeys {
+		proposed[key] = nil
+	}
+	if c.useFile {
+		b, err := c.readConfigFile(ctx)
+		if err != nil {
+			return nil, err
+		}
+		var all map[string]map[string]interface{}
+		if err := yaml.Unmarshal(b, &all); err != nil {
+			return nil, errors.Annotate(err, "parsing config file")
+		}
+		settings, ok := all[c.applicationName]
+		if !ok {
+			return nil, errors.Errorf("no settings found for %q in config file", c.applicationName)
+		}
+		for key, value := range settings {
+			proposed[key] = value
+		}
+		return proposed, nil
+	}
+	if len(c.values) > 0 {
+		settings, err := c.validateValues(ctx)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		for key, value := range settings {
+			proposed[key] = value
+		}
+	}
+	return proposed, nil
+}
+
+// describeConfigValue formats a configuration value for display,
+// noting when it is the default value of the setting.
+func describeConfigValue(info map[string]interface{}, value interface{}, isDefault bool) string {
+	if value == nil {
+		return "(unset)"
+	}
+	typ, _ := info["type"].(string)
+	s := formatConfigValue(value, typ)
+	if isDefault {
+		s += " (default)"
+	}
+	return s
+}
+
+// formatConfigValue formats a configuration value for display on a
+// single line, quoting values of string settings.
+func formatConfigValue(value interface{}, typ string) string {
+	if s, ok := value.(string); ok && (typ == "" || typ == "string") {
+		return strconv.Quote(s)
+	}
+	return fmt.Sprint(value)
+}
+
+// isDefaultValue reports whether the value is the default value of
+// the setting described by info.
+func isDefaultValue(info map[string]interface{}, value interface{}) bool {
+	def, ok := info["default"]
+	return ok && def != nil && fmt.Sprint(def) == fmt.Sprint(value)
 }
 
 // getConfig is the run action to return one or all configuration values.
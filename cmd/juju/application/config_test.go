@@ -218,6 +218,14 @@ var setCommandInitErrorTests = []struct {
 	about:       "invalid reset keys",
 	args:        []string{"application", "--reset", "reset,bad=key"},
 	expectError: `--reset accepts a comma delimited set of keys "a,b,c", received: "bad=key"`,
+}, {
+	about:       "--diff without setting or resetting",
+	args:        []string{"application", "--diff"},
+	expectError: "--diff and --dry-run can only be used when setting or resetting values",
+}, {
+	about:       "--dry-run when retrieving a value",
+	args:        []string{"application", "--dry-run", "key"},
+	expectError: "--diff and --dry-run can only be used when setting or resetting values",
 }, {
 	about:       "init too many args fails",
 	args:        []string{"application", "key", "another"},
@@ -357,6 +365,112 @@ func (s *configCommandSuite) TestResetAppConfig(c *gc.C) {
 	}, make(map[string]interface{}), nil)
 }
 
+func (s *configCommandSuite) runConfig(c *gc.C, args []string) (*cmd.Context, error) {
+	command := application.NewConfigCommandForTest(s.fake)
+	command.SetClientStore(application.NewMockStore())
+	return cmdtesting.RunCommandInDir(c, command, args, s.dir)
+}
+
+func (s *configCommandSuite) setCharmDefaults() {
+	s.fake.charmDefaults = map[string]interface{}{
+		"title":       "Nearly There",
+		"skill-level": 100,
+		"username":    "admin001",
+		"outlook":     "true",
+	}
+}
+
+func (s *configCommandSuite) TestSetDiff(c *gc.C) {
+	s.setCharmDefaults()
+	ctx, err := s.runConfig(c, []string{
+		"dummy-application",
+		"--diff",
+		"username=hello",
+		"skill-level=100",
+		"outlook=false",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(cmdtesting.Stdout(ctx), gc.Equals, `
+Configuration changes for dummy-application:
+  outlook: "true" (default) -> "false"
+  username: "admin001" (default) -> "hello"
+`[1:])
+	c.Check(s.fake.charmValues["username"], gc.Equals, "hello")
+	c.Check(s.fake.charmValues["outlook"], gc.Equals, "false")
+}
+
+func (s *configCommandSuite) TestSetDryRunFromYAML(c *gc.C) {
+	s.setCharmDefaults()
+	ctx, err := s.runConfig(c, []string{
+		"dummy-application",
+		"--dry-run",
+		"--file",
+		"testconfig.yaml",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(cmdtesting.Stdout(ctx), gc.Equals, `
+Configuration changes for dummy-application:
+  skill-level: 100 (default) -> 9000
+`[1:])
+	c.Check(s.fake.config, gc.Equals, "")
+}
+
+func (s *configCommandSuite) TestSetDiffFromStdin(c *gc.C) {
+	ctx := cmdtesting.Context(c)
+	ctx.Stdin = strings.NewReader(yamlConfigValue)
+	code := cmd.Main(application.NewConfigCommandForTest(s.fake), ctx, []string{
+		"dummy-application",
+		"--diff",
+		"--file",
+		"-"})
+
+	c.Check(code, gc.Equals, 0)
+	c.Check(cmdtesting.Stdout(ctx), gc.Equals, `
+Configuration changes for dummy-application:
+  skill-level: 100 -> 9000
+`[1:])
+	c.Check(s.fake.config, gc.Equals, yamlConfigValue)
+}
+
+func (s *configCommandSuite) TestSetDryRunFromYAMLNoApplication(c *gc.C) {
+	s.fake.name = "other-application"
+	_, err := s.runConfig(c, []string{
+		"other-application",
+		"--dry-run",
+		"--file",
+		"testconfig.yaml",
+	})
+	c.Assert(err, gc.ErrorMatches, `no settings found for "other-application" in config file`)
+}
+
+func (s *configCommandSuite) TestResetDryRun(c *gc.C) {
+	s.setCharmDefaults()
+	s.fake.charmValues["username"] = "hello"
+	ctx, err := s.runConfig(c, []string{
+		"dummy-application",
+		"--dry-run",
+		"--reset",
+		"username,title",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(cmdtesting.Stdout(ctx), gc.Equals, `
+Configuration changes for dummy-application:
+  username: "hello" -> "admin001" (default)
+`[1:])
+	c.Check(s.fake.charmValues["username"], gc.Equals, "hello")
+}
+
+func (s *configCommandSuite) TestSetDryRunNoChanges(c *gc.C) {
+	s.setCharmDefaults()
+	ctx, err := s.runConfig(c, []string{
+		"dummy-application",
+		"--dry-run",
+		"title=Nearly There",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(cmdtesting.Stdout(ctx), gc.Equals, "No configuration changes for dummy-application.\n")
+}
+
 func (s *configCommandSuite) TestBlockSetConfig(c *gc.C) {
 	// Block operation
 	s.fake.err = common.OperationBlockedError("TestBlockSetConfig")
@@ -188,6 +188,22 @@ func (a *deployAPIAdapter) GetAnnotations(tags []string) ([]apiparams.Annotation
 	return a.annotationsClient.Get(tags)
 }
 
+// newDeployAPIAdapter returns a DeployAPI that uses the given API
+// connection, and the charm store through the given bakery client.
+func newDeployAPIAdapter(apiRoot api.Connection, bakeryClient *httpbakery.Client, channel params.Channel) *deployAPIAdapter {
+	cstoreClient := newCharmStoreClient(bakeryClient).WithChannel(channel)
+	return &deployAPIAdapter{
+		Connection:        apiRoot,
+		apiClient:         &apiClient{Client: apiRoot.Client()},
+		charmsClient:      &charmsClient{Client: apicharms.NewClient(apiRoot)},
+		applicationClient: &applicationClient{Client: application.NewClient(apiRoot)},
+		modelConfigClient: &modelConfigClient{Client: modelconfig.NewClient(apiRoot)},
+		charmstoreClient:  &charmstoreClient{Client: cstoreClient},
+		annotationsClient: &annotationsClient{Client: annotations.NewClient(apiRoot)},
+		charmRepoClient:   &charmRepoClient{CharmStore: charmrepo.NewCharmStoreFromClient(cstoreClient)},
+	}
+}
+
 // NewDeployCommandForTest returns a command to deploy applications inteded to be used only in tests.
 func NewDeployCommandForTest(newAPIRoot func() (DeployAPI, error), steps []DeployStep) modelcmd.ModelCommand {
 	deployCmd := &DeployCommand{
@@ -204,18 +220,7 @@ func NewDeployCommandForTest(newAPIRoot func() (DeployAPI, error), steps []Deplo
 			if err != nil {
 				return nil, errors.Trace(err)
 			}
-			cstoreClient := newCharmStoreClient(bakeryClient).WithChannel(deployCmd.Channel)
-
-			return &deployAPIAdapter{
-				Connection:        apiRoot,
-				apiClient:         &apiClient{Client: apiRoot.Client()},
-				charmsClient:      &charmsClient{Client: apicharms.NewClient(apiRoot)},
-				applicationClient: &applicationClient{Client: application.NewClient(apiRoot)},
-				modelConfigClient: &modelConfigClient{Client: modelconfig.NewClient(apiRoot)},
-				charmstoreClient:  &charmstoreClient{Client: cstoreClient},
-				annotationsClient: &annotationsClient{Client: annotations.NewClient(apiRoot)},
-				charmRepoClient:   &charmRepoClient{CharmStore: charmrepo.NewCharmStoreFromClient(cstoreClient)},
-			}, nil
+			return newDeployAPIAdapter(apiRoot, bakeryClient, deployCmd.Channel), nil
 		}
 	}
 	return modelcmd.Wrap(deployCmd)
@@ -241,18 +246,7 @@ func NewDeployCommand() modelcmd.ModelCommand {
 		if err != nil {
 			return nil, errors.Trace(err)
 		}
-		cstoreClient := newCharmStoreClient(bakeryClient).WithChannel(deployCmd.Channel)
-
-		return &deployAPIAdapter{
-			Connection:        apiRoot,
-			apiClient:         &apiClient{Client: apiRoot.Client()},
-			charmsClient:      &charmsClient{Client: apicharms.NewClient(apiRoot)},
-			applicationClient: &applicationClient{Client: application.NewClient(apiRoot)},
-			modelConfigClient: &modelConfigClient{Client: modelconfig.NewClient(apiRoot)},
-			charmstoreClient:  &charmstoreClient{Client: cstoreClient},
-			annotationsClient: &annotationsClient{Client: annotations.NewClient(apiRoot)},
-			charmRepoClient:   &charmRepoClient{CharmStore: charmrepo.NewCharmStoreFromClient(cstoreClient)},
-		}, nil
+		return newDeployAPIAdapter(apiRoot, bakeryClient, deployCmd.Channel), nil
 	}
 
 	return modelcmd.Wrap(deployCmd)
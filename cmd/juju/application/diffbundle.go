@@ -0,0 +1,493 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package application
+
+import (
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+	"gopkg.in/juju/charm.v6"
+	"gopkg.in/juju/charmrepo.v2"
+
+	"github.com/juju/juju/api"
+	"github.com/juju/juju/api/application"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/constraints"
+)
+
+var usageDiffBundleSummary = `
+Compares a bundle with a model and reports any differences.`[1:]
+
+var usageDiffBundleDetails = `
+Reads a local bundle file, and any overlays, and compares it with the
+current model, so that a model can be checked against the bundle it was
+deployed from before the bundle is deployed again.
+
+The following differences are reported:
+ - applications that are only in the bundle or only in the model;
+ - a different charm, series, number of units or exposure;
+ - config options set in the bundle with a different value in the model;
+ - constraints set in the bundle that differ from those in the model;
+ - relations that are only in the bundle or only in the model.
+
+Config options and constraints not mentioned in the bundle are not
+compared. Relation endpoints omitted from the bundle match any endpoint
+of the application.
+
+No output means the model matches the bundle.
+
+Examples:
+    juju diff-bundle ./bundle.yaml
+    juju diff-bundle -m production ./bundle.yaml --overlay ./production.yaml
+
+See also:
+    deploy`[1:]
+
+// NewDiffBundleCommand returns a command to compare a bundle with
+// the current model.
+func NewDiffBundleCommand() modelcmd.ModelCommand {
+	cmd := &diffBundleCommand{}
+	cmd.newAPIFunc = func() (DiffBundleAPI, error) {
+		return cmd.newAPI()
+	}
+	return modelcmd.Wrap(cmd)
+}
+
+// DiffBundleAPI provides the model status and application config
+// needed by the diff-bundle command.
+type DiffBundleAPI interface {
+	Close() error
+	Status(patterns []string) (*params.FullStatus, error)
+	Get(application string) (*params.ApplicationGetResults, error)
+}
+
+// diffBundleCommand compares a bundle with the current model.
+type diffBundleCommand struct {
+	modelcmd.ModelCommandBase
+	out          cmd.Output
+	bundleFile   string
+	overlayFiles []string
+	newAPIFunc   func() (DiffBundleAPI, error)
+}
+
+// Info implements cmd.Command.
+func (c *diffBundleCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "diff-bundle",
+		Args:    "<bundle file>",
+		Purpose: usageDiffBundleSummary,
+		Doc:     usageDiffBundleDetails,
+	}
+}
+
+// SetFlags implements cmd.Command.
+func (c *diffBundleCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.ModelCommandBase.SetFlags(f)
+	c.out.AddFlags(f, "yaml", map[string]cmd.Formatter{
+		"yaml": cmd.FormatYaml,
+		"json": cmd.FormatJson,
+	})
+	f.Var(cmd.NewAppendStringsValue(&c.overlayFiles), "overlay", "Bundles to overlay on the primary bundle, applied in order")
+}
+
+// Init implements cmd.Command.
+func (c *diffBundleCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no bundle specified")
+	}
+	c.bundleFile = args[0]
+	return cmd.CheckEmpty(args[1:])
+}
+
+// Run implements cmd.Command.
+func (c *diffBundleCommand) Run(ctx *cmd.Context) error {
+	data, err := c.readBundle(ctx)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	client, err := c.newAPIFunc()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer client.Close()
+
+	model, err := newModelRepresentation(client)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	diff, err := diffBundle(data, model, client)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if diff.empty() {
+		return nil
+	}
+	return c.out.Write(ctx, diff)
+}
+
+// readBundle reads the bundle file, resolving any included files
+// and applying any overlays.
+func (c *diffBundleCommand) readBundle(ctx *cmd.Context) (*charm.BundleData, error) {
+	path := ctx.AbsPath(c.bundleFile)
+	data, err := charmrepo.ReadBundleFile(path)
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot read bundle %q", c.bundleFile)
+	}
+	if err := processBundleIncludes(filepath.Dir(path), data); err != nil {
+		return nil, errors.Annotate(err, "cannot process includes")
+	}
+	overlays := make([]string, len(c.overlayFiles))
+	for i, overlay := range c.overlayFiles {
+		overlays[i] = ctx.AbsPath(overlay)
+	}
+	if err := processBundleOverlay(data, overlays...); err != nil {
+		return nil, errors.Annotate(err, "cannot apply overlays")
+	}
+	return data, nil
+}
+
+func (c *diffBundleCommand) newAPI() (DiffBundleAPI, error) {
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &diffBundleAPI{
+		root:        root,
+		client:      root.Client(),
+		application: application.NewClient(root),
+	}, nil
+}
+
+// diffBundleAPI implements DiffBundleAPI using the Client and
+// Application facades of an API connection.
+type diffBundleAPI struct {
+	root        api.Connection
+	client      *api.Client
+	application *application.Client
+}
+
+func (a *diffBundleAPI) Status(patterns []string) (*params.FullStatus, error) {
+	return a.client.Status(patterns)
+}
+
+func (a *diffBundleAPI) Get(application string) (*params.ApplicationGetResults, error) {
+	return a.application.Get(application)
+}
+
+func (a *diffBundleAPI) Close() error {
+	return a.root.Close()
+}
+
+// modelApplication holds the details of an application in the model
+// that can be compared with a bundle.
+type modelApplication struct {
+	Charm    string
+	Series   string
+	NumUnits int
+	Exposed  bool
+
+	// Options and Constraints are only fetched for the
+	// applications that are also in the bundle.
+	Options     map[string]interface{}
+	Constraints constraints.Value
+}
+
+// modelRepresentation holds the details of the model that can be
+// compared with a bundle.
+type modelRepresentation struct {
+	Applications map[string]*modelApplication
+	Relations    []relationEndpoints
+}
+
+// relationEndpoints holds the two endpoints of a relation, each in
+// the form "application:endpoint" or, in bundles, "application".
+type relationEndpoints [2]string
+
+func newModelRepresentation(client DiffBundleAPI) (*modelRepresentation, error) {
+	status, err := client.Status(nil)
+	if err != nil {
+		return nil, errors.Annotate(err, "getting model status")
+	}
+	model := &modelRepresentation{
+		Applications: make(map[string]*modelApplication),
+	}
+	for name, app := range status.Applications {
+		model.Applications[name] = &modelApplication{
+			Charm:    app.Charm,
+			Series:   app.Series,
+			NumUnits: len(app.Units),
+			Exposed:  app.Exposed,
+		}
+	}
+	for _, rel := range status.Relations {
+		// Peer relations have a single endpoint, and are
+		// not listed in bundles.
+		if len(rel.Endpoints) != 2 {
+			continue
+		}
+		model.Relations = append(model.Relations, relationEndpoints{
+			rel.Endpoints[0].ApplicationName + ":" + rel.Endpoints[0].Name,
+			rel.Endpoints[1].ApplicationName + ":" + rel.Endpoints[1].Name,
+		})
+	}
+	return model, nil
+}
+
+// fetchConfig fills in the options and constraints of the named
+// application.
+func (m *modelRepresentation) fetchConfig(client DiffBundleAPI, name string) error {
+	app := m.Applications[name]
+	result, err := client.Get(name)
+	if err != nil {
+		return errors.Annotatef(err, "getting config for %q", name)
+	}
+	app.Options = make(map[string]interface{})
+	for option, info := range result.CharmConfig {
+		if info, ok := info.(map[string]interface{}); ok {
+			app.Options[option] = info["value"]
+		}
+	}
+	app.Constraints = result.Constraints
+	return nil
+}
+
+// bundleDiff describes the differences between a bundle and a model.
+type bundleDiff struct {
+	Applications map[string]*applicationDiff `yaml:"applications,omitempty" json:"applications,omitempty"`
+	Relations    *relationsDiff              `yaml:"relations,omitempty" json:"relations,omitempty"`
+}
+
+func (d *bundleDiff) empty() bool {
+	return len(d.Applications) == 0 && d.Relations == nil
+}
+
+// applicationDiff describes the differences in a single application.
+// Missing is set, and nothing else, if the application is only in one
+// of the bundle or the model.
+type applicationDiff struct {
+	Missing     string                `yaml:"missing,omitempty" json:"missing,omitempty"`
+	Charm       *valueDiff            `yaml:"charm,omitempty" json:"charm,omitempty"`
+	Series      *valueDiff            `yaml:"series,omitempty" json:"series,omitempty"`
+	NumUnits    *valueDiff            `yaml:"num_units,omitempty" json:"num_units,omitempty"`
+	Expose      *valueDiff            `yaml:"expose,omitempty" json:"expose,omitempty"`
+	Options     map[string]*valueDiff `yaml:"options,omitempty" json:"options,omitempty"`
+	Constraints *valueDiff            `yaml:"constraints,omitempty" json:"constraints,omitempty"`
+}
+
+func (d *applicationDiff) empty() bool {
+	return reflect.DeepEqual(*d, applicationDiff{})
+}
+
+// valueDiff holds a value that differs between the bundle and the model.
+type valueDiff struct {
+	Bundle interface{} `yaml:"bundle" json:"bundle"`
+	Model  interface{} `yaml:"model" json:"model"`
+}
+
+// relationsDiff holds the relations that are only in one of the
+// bundle or the model.
+type relationsDiff struct {
+	BundleAdditions [][]string `yaml:"bundle-additions,omitempty" json:"bundle-additions,omitempty"`
+	ModelAdditions  [][]string `yaml:"model-additions,omitempty" json:"model-additions,omitempty"`
+}
+
+const (
+	missingFromBundle = "bundle"
+	missingFromModel  = "model"
+)
+
+// diffBundle returns the differences between the bundle data and the
+// model. The model's config is fetched with client for each
+// application in both, as it is needed.
+func diffBundle(data *charm.BundleData, model *modelRepresentation, client DiffBundleAPI) (*bundleDiff, error) {
+	diff := &bundleDiff{
+		Applications: make(map[string]*applicationDiff),
+	}
+	for name, spec := range data.Applications {
+		app, ok := model.Applications[name]
+		if !ok {
+			diff.Applications[name] = &applicationDiff{Missing: missingFromModel}
+			continue
+		}
+		if err := model.fetchConfig(client, name); err != nil {
+			return nil, errors.Trace(err)
+		}
+		appDiff, err := diffApplication(spec, data.Series, app)
+		if err != nil {
+			return nil, errors.Annotatef(err, "application %q", name)
+		}
+		if !appDiff.empty() {
+			diff.Applications[name] = appDiff
+		}
+	}
+	for name := range model.Applications {
+		if _, ok := data.Applications[name]; !ok {
+			diff.Applications[name] = &applicationDiff{Missing: missingFromBundle}
+		}
+	}
+	diff.Relations = diffRelations(data.Relations, model.Relations)
+	return diff, nil
+}
+
+func diffApplication(spec *charm.ApplicationSpec, defaultSeries string, app *modelApplication) (*applicationDiff, error) {
+	diff := &applicationDiff{}
+	if !charmMatches(spec.Charm, app.Charm) {
+		diff.Charm = &valueDiff{spec.Charm, app.Charm}
+	}
+	series := spec.Series
+	if series == "" {
+		series = defaultSeries
+	}
+	if series != "" && series != app.Series {
+		diff.Series = &valueDiff{series, app.Series}
+	}
+	if spec.NumUnits != app.NumUnits {
+		diff.NumUnits = &valueDiff{spec.NumUnits, app.NumUnits}
+	}
+	if spec.Expose != app.Exposed {
+		diff.Expose = &valueDiff{spec.Expose, app.Exposed}
+	}
+	for option, value := range spec.Options {
+		modelValue := app.Options[option]
+		if !optionValuesEqual(value, modelValue) {
+			if diff.Options == nil {
+				diff.Options = make(map[string]*valueDiff)
+			}
+			diff.Options[option] = &valueDiff{value, modelValue}
+		}
+	}
+	if spec.Constraints != "" {
+		cons, err := constraints.Parse(spec.Constraints)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if cons.String() != app.Constraints.String() {
+			diff.Constraints = &valueDiff{cons.String(), app.Constraints.String()}
+		}
+	}
+	return diff, nil
+}
+
+// charmMatches returns whether the charm in the model satisfies the
+// charm named in the bundle: any parts of the charm URL omitted from
+// the bundle, such as the series or revision, match anything. Local
+// charms are identified by path in the bundle, and cannot be compared.
+func charmMatches(bundleCharm, modelCharm string) bool {
+	if strings.HasPrefix(bundleCharm, ".") || filepath.IsAbs(bundleCharm) {
+		return true
+	}
+	bundleURL, err := charm.ParseURL(bundleCharm)
+	if err != nil {
+		return bundleCharm == modelCharm
+	}
+	modelURL, err := charm.ParseURL(modelCharm)
+	if err != nil {
+		return false
+	}
+	switch {
+	case bundleURL.Schema != modelURL.Schema:
+		return false
+	case bundleURL.User != modelURL.User:
+		return false
+	case bundleURL.Name != modelURL.Name:
+		return false
+	case bundleURL.Series != "" && bundleURL.Series != modelURL.Series:
+		return false
+	case bundleURL.Revision >= 0 && bundleURL.Revision != modelURL.Revision:
+		return false
+	}
+	return true
+}
+
+// optionValuesEqual compares config values read from YAML and from
+// the API, in which all numbers are decoded as float64.
+func optionValuesEqual(bundleValue, modelValue interface{}) bool {
+	if a, ok := toFloat(bundleValue); ok {
+		b, ok := toFloat(modelValue)
+		return ok && a == b
+	}
+	return reflect.DeepEqual(bundleValue, modelValue)
+}
+
+func toFloat(value interface{}) (float64, bool) {
+	switch value := value.(type) {
+	case int:
+		return float64(value), true
+	case int64:
+		return float64(value), true
+	case float64:
+		return value, true
+	}
+	return 0, false
+}
+
+// diffRelations returns the relations only in the bundle and only in
+// the model, or nil if there are none.
+func diffRelations(bundleRelations [][]string, modelRelations []relationEndpoints) *relationsDiff {
+	matched := make([]bool, len(modelRelations))
+	var diff relationsDiff
+	for _, rel := range bundleRelations {
+		if len(rel) != 2 {
+			continue
+		}
+		found := false
+		for i, modelRel := range modelRelations {
+			if !matched[i] && relationMatches(rel, modelRel) {
+				matched[i] = true
+				found = true
+				break
+			}
+		}
+		if !found {
+			diff.BundleAdditions = append(diff.BundleAdditions, sortedRelation(rel[0], rel[1]))
+		}
+	}
+	for i, modelRel := range modelRelations {
+		if !matched[i] {
+			diff.ModelAdditions = append(diff.ModelAdditions, sortedRelation(modelRel[0], modelRel[1]))
+		}
+	}
+	if len(diff.BundleAdditions) == 0 && len(diff.ModelAdditions) == 0 {
+		return nil
+	}
+	sortRelations(diff.BundleAdditions)
+	sortRelations(diff.ModelAdditions)
+	return &diff
+}
+
+// relationMatches returns whether the bundle relation describes the
+// model relation, with its endpoints in either order.
+func relationMatches(rel []string, modelRel relationEndpoints) bool {
+	return endpointMatches(rel[0], modelRel[0]) && endpointMatches(rel[1], modelRel[1]) ||
+		endpointMatches(rel[0], modelRel[1]) && endpointMatches(rel[1], modelRel[0])
+}
+
+// endpointMatches returns whether the bundle endpoint, which may omit
+// the endpoint name, describes the model endpoint.
+func endpointMatches(bundleEndpoint, modelEndpoint string) bool {
+	if strings.Contains(bundleEndpoint, ":") {
+		return bundleEndpoint == modelEndpoint
+	}
+	return strings.SplitN(modelEndpoint, ":", 2)[0] == bundleEndpoint
+}
+
+func sortedRelation(a, b string) []string {
+	if b < a {
+		a, b = b, a
+	}
+	return []string{a, b}
+}
+
+func sortRelations(relations [][]string) {
+	sort.Slice(relations, func(i, j int) bool {
+		return fmt.Sprint(relations[i]) < fmt.Sprint(relations[j])
+	})
+}
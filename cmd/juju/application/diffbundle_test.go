@@ -0,0 +1,255 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package application
+
+import (
+	"io/ioutil"
+	"path/filepath"
+
+	"github.com/juju/cmd"
+	"github.com/juju/cmd/cmdtesting"
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/constraints"
+)
+
+type DiffBundleSuite struct {
+	testing.IsolationSuite
+
+	mockAPI *mockDiffBundleAPI
+	dir     string
+}
+
+var _ = gc.Suite(&DiffBundleSuite{})
+
+func (s *DiffBundleSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.dir = c.MkDir()
+	s.mockAPI = &mockDiffBundleAPI{
+		Stub: &testing.Stub{},
+		status: &params.FullStatus{
+			Applications: map[string]params.ApplicationStatus{
+				"mysql": {
+					Charm:  "cs:xenial/mysql-57",
+					Series: "xenial",
+					Units:  map[string]params.UnitStatus{"mysql/0": {}},
+				},
+				"wordpress": {
+					Charm:   "cs:xenial/wordpress-47",
+					Series:  "xenial",
+					Exposed: true,
+					Units: map[string]params.UnitStatus{
+						"wordpress/0": {},
+						"wordpress/1": {},
+					},
+				},
+			},
+			Relations: []params.RelationStatus{{
+				Endpoints: []params.EndpointStatus{
+					{ApplicationName: "wordpress", Name: "db", Role: "requirer"},
+					{ApplicationName: "mysql", Name: "db", Role: "provider"},
+				},
+			}, {
+				Endpoints: []params.EndpointStatus{
+					{ApplicationName: "mysql", Name: "cluster", Role: "peer"},
+				},
+			}},
+		},
+		config: map[string]*params.ApplicationGetResults{
+			"mysql": {
+				CharmConfig: map[string]interface{}{
+					"dataset-size": map[string]interface{}{"value": "50%"},
+					"max-connections": map[string]interface{}{
+						"value":  float64(-1),
+						"source": "default",
+					},
+				},
+			},
+			"wordpress": {
+				Constraints: constraints.MustParse("mem=2048M"),
+			},
+		},
+	}
+}
+
+func (s *DiffBundleSuite) writeBundle(c *gc.C, content string) string {
+	path := filepath.Join(s.dir, "bundle.yaml")
+	err := ioutil.WriteFile(path, []byte(content), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+	return path
+}
+
+func (s *DiffBundleSuite) runDiffBundle(c *gc.C, args ...string) (*cmd.Context, error) {
+	return cmdtesting.RunCommand(c, NewDiffBundleCommandForTest(s.mockAPI), args...)
+}
+
+func (s *DiffBundleSuite) TestInit(c *gc.C) {
+	_, err := s.runDiffBundle(c)
+	c.Assert(err, gc.ErrorMatches, "no bundle specified")
+	_, err = s.runDiffBundle(c, "bundle.yaml", "extra")
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["extra"\]`)
+}
+
+func (s *DiffBundleSuite) TestMatching(c *gc.C) {
+	path := s.writeBundle(c, `
+series: xenial
+applications:
+  mysql:
+    charm: cs:mysql
+    num_units: 1
+    options:
+      dataset-size: 50%
+      max-connections: -1
+  wordpress:
+    charm: cs:xenial/wordpress-47
+    num_units: 2
+    expose: true
+    constraints: mem=2G
+relations:
+  - [mysql, wordpress:db]
+`)
+	ctx, err := s.runDiffBundle(c, path)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(cmdtesting.Stdout(ctx), gc.Equals, "")
+	s.mockAPI.CheckCallNames(c, "Status", "Get", "Get", "Close")
+}
+
+func (s *DiffBundleSuite) TestDifferences(c *gc.C) {
+	s.mockAPI.status.Applications["haproxy"] = params.ApplicationStatus{
+		Charm:  "cs:xenial/haproxy-40",
+		Series: "xenial",
+	}
+	s.mockAPI.status.Relations = append(s.mockAPI.status.Relations, params.RelationStatus{
+		Endpoints: []params.EndpointStatus{
+			{ApplicationName: "wordpress", Name: "website", Role: "provider"},
+			{ApplicationName: "haproxy", Name: "reverseproxy", Role: "requirer"},
+		},
+	})
+	path := s.writeBundle(c, `
+series: xenial
+applications:
+  mysql:
+    charm: cs:mysql-58
+    num_units: 1
+    options:
+      dataset-size: 80%
+  wordpress:
+    charm: cs:wordpress
+    num_units: 3
+    expose: true
+    constraints: mem=4G
+  memcached:
+    charm: cs:memcached
+    num_units: 1
+relations:
+  - [wordpress:db, mysql:db]
+  - [wordpress, memcached]
+`)
+	ctx, err := s.runDiffBundle(c, path)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(cmdtesting.Stdout(ctx), gc.Equals, `
+applications:
+  haproxy:
+    missing: bundle
+  memcached:
+    missing: model
+  mysql:
+    charm:
+      bundle: cs:mysql-58
+      model: cs:xenial/mysql-57
+    options:
+      dataset-size:
+        bundle: 80%
+        model: 50%
+  wordpress:
+    num_units:
+      bundle: 3
+      model: 2
+    constraints:
+      bundle: mem=4096M
+      model: mem=2048M
+relations:
+  bundle-additions:
+  - - memcached
+    - wordpress
+  model-additions:
+  - - haproxy:reverseproxy
+    - wordpress:website
+`[1:])
+}
+
+func (s *DiffBundleSuite) TestOverlay(c *gc.C) {
+	path := s.writeBundle(c, `
+series: xenial
+applications:
+  mysql:
+    charm: cs:mysql
+    num_units: 1
+  wordpress:
+    charm: cs:wordpress
+    num_units: 2
+    expose: true
+relations:
+  - [mysql, wordpress]
+`)
+	overlay := filepath.Join(s.dir, "overlay.yaml")
+	err := ioutil.WriteFile(overlay, []byte(`
+applications:
+  wordpress:
+    num_units: 4
+`), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+	ctx, err := s.runDiffBundle(c, path, "--overlay", overlay)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(cmdtesting.Stdout(ctx), gc.Equals, `
+applications:
+  wordpress:
+    num_units:
+      bundle: 4
+      model: 2
+`[1:])
+}
+
+func (s *DiffBundleSuite) TestStatusError(c *gc.C) {
+	s.mockAPI.SetErrors(errors.New("boom"))
+	path := s.writeBundle(c, `
+applications:
+  mysql:
+    charm: cs:mysql
+`)
+	_, err := s.runDiffBundle(c, path)
+	c.Assert(err, gc.ErrorMatches, "getting model status: boom")
+	s.mockAPI.CheckCallNames(c, "Status", "Close")
+}
+
+func (s *DiffBundleSuite) TestMissingBundle(c *gc.C) {
+	_, err := s.runDiffBundle(c, filepath.Join(s.dir, "missing.yaml"))
+	c.Assert(err, gc.ErrorMatches, `cannot read bundle ".*missing.yaml": .*`)
+	s.mockAPI.CheckNoCalls(c)
+}
+
+type mockDiffBundleAPI struct {
+	*testing.Stub
+	status *params.FullStatus
+	config map[string]*params.ApplicationGetResults
+}
+
+func (m *mockDiffBundleAPI) Status(patterns []string) (*params.FullStatus, error) {
+	m.MethodCall(m, "Status", patterns)
+	return m.status, m.NextErr()
+}
+
+func (m *mockDiffBundleAPI) Get(application string) (*params.ApplicationGetResults, error) {
+	m.MethodCall(m, "Get", application)
+	return m.config[application], m.NextErr()
+}
+
+func (m *mockDiffBundleAPI) Close() error {
+	m.MethodCall(m, "Close")
+	return m.NextErr()
+}
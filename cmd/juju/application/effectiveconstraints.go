@@ -0,0 +1,325 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package application
+
+import (
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/api"
+	"github.com/juju/juju/api/application"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/block"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/cmd/output"
+	"github.com/juju/juju/constraints"
+)
+
+var usageConstraintsSummary = `
+Displays or sets machine constraints, showing where each value comes from.`[1:]
+
+var usageConstraintsDetails = `
+Constraints may be set for the model and for each application, and are
+combined when a machine is provisioned: where both set the same
+constraint, the application's value takes precedence. This command shows
+the effective constraints along with the level each value comes from.
+
+Without arguments, the model constraints are shown. Given an application,
+the constraints used for new machines provisioned for it are shown, each
+coming from either the "model" or the "application". Given a machine,
+the constraints it was provisioned with are shown; any value that came
+from neither the model nor the applications deployed to it was given
+when the machine was added or the unit placed, and is shown as coming
+from the "machine".
+
+Constraints are set at a level by passing <constraint>=<value> pairs:
+without an application they are set for the model, otherwise for the
+application. A constraint may be unset by giving it an empty value.
+Machine constraints cannot be changed once the machine has been created.
+
+Examples:
+    juju constraints
+    juju constraints mysql
+    juju constraints 3
+    juju constraints mem=4G
+    juju constraints mysql mem=8G cores=4
+    juju constraints mysql cores=
+
+See also:
+    get-constraints
+    set-constraints
+    get-model-constraints
+    set-model-constraints`[1:]
+
+const (
+	constraintSourceModel       = "model"
+	constraintSourceApplication = "application"
+	constraintSourceMachine     = "machine"
+)
+
+// NewConstraintsCommand returns a command which displays and sets
+// constraints at the model and application levels.
+func NewConstraintsCommand() modelcmd.ModelCommand {
+	cmd := &constraintsCommand{}
+	cmd.newAPIFunc = func() (ConstraintsAPI, error) {
+		root, err := cmd.NewAPIRoot()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return &constraintsAPIAdapter{
+			Client:            root.Client(),
+			applicationClient: application.NewClient(root),
+		}, nil
+	}
+	return modelcmd.Wrap(cmd)
+}
+
+// ConstraintsAPI defines the API methods that the constraints command uses.
+type ConstraintsAPI interface {
+	Close() error
+	Status(patterns []string) (*params.FullStatus, error)
+	GetModelConstraints() (constraints.Value, error)
+	SetModelConstraints(constraints.Value) error
+	GetConstraints(...string) ([]constraints.Value, error)
+	SetConstraints(string, constraints.Value) error
+}
+
+type constraintsAPIAdapter struct {
+	*api.Client
+	applicationClient *application.Client
+}
+
+func (a *constraintsAPIAdapter) GetConstraints(applications ...string) ([]constraints.Value, error) {
+	return a.applicationClient.GetConstraints(applications...)
+}
+
+func (a *constraintsAPIAdapter) SetConstraints(application string, cons constraints.Value) error {
+	return a.applicationClient.SetConstraints(application, cons)
+}
+
+// constraintsCommand shows the effective constraints for a model,
+// application or machine, and sets model or application constraints.
+type constraintsCommand struct {
+	modelcmd.ModelCommandBase
+	out        cmd.Output
+	newAPIFunc func() (ConstraintsAPI, error)
+
+	applicationName string
+	machineId       string
+	constraints     *constraints.Value
+}
+
+// ConstraintSource holds a constraint's value and the level it was set at.
+type ConstraintSource struct {
+	Value  string `yaml:"value" json:"value"`
+	Source string `yaml:"source" json:"source"`
+}
+
+func (c *constraintsCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "constraints",
+		Args:    "[<application>|<machine>] [<constraint>=<value> ...]",
+		Purpose: usageConstraintsSummary,
+		Doc:     usageConstraintsDetails,
+	}
+}
+
+func (c *constraintsCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.ModelCommandBase.SetFlags(f)
+	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+		"tabular": formatConstraintSourcesTabular,
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+	})
+}
+
+func (c *constraintsCommand) Init(args []string) error {
+	if len(args) > 0 && !strings.Contains(args[0], "=") {
+		target := args[0]
+		args = args[1:]
+		switch {
+		case names.IsValidMachine(target):
+			if len(args) > 0 {
+				return errors.Errorf("cannot change constraints of machine %q once it has been created", target)
+			}
+			c.machineId = target
+		case names.IsValidApplication(target):
+			c.applicationName = target
+		default:
+			return errors.Errorf("invalid application or machine name %q", target)
+		}
+	}
+	if len(args) == 0 {
+		return nil
+	}
+	cons, err := constraints.Parse(args...)
+	if err != nil {
+		return err
+	}
+	c.constraints = &cons
+	return nil
+}
+
+// Run shows or sets constraints.
+func (c *constraintsCommand) Run(ctx *cmd.Context) error {
+	client, err := c.newAPIFunc()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	if c.constraints != nil {
+		if c.applicationName != "" {
+			err = client.SetConstraints(c.applicationName, *c.constraints)
+		} else {
+			err = client.SetModelConstraints(*c.constraints)
+		}
+		return block.ProcessBlockedError(err, block.BlockChange)
+	}
+
+	modelCons, err := client.GetModelConstraints()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	var sources map[string]ConstraintSource
+	switch {
+	case c.applicationName != "":
+		sources, err = c.applicationSources(client, modelCons)
+	case c.machineId != "":
+		sources, err = c.machineSources(client, modelCons)
+	default:
+		sources = make(map[string]ConstraintSource)
+		addConstraintSources(sources, modelCons.String(), constraintSourceModel)
+	}
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(sources) == 0 && c.out.Name() == "tabular" {
+		ctx.Infof("No constraints set.")
+		return nil
+	}
+	return c.out.Write(ctx, sources)
+}
+
+// applicationSources returns the constraints used for new machines
+// provisioned for the application.
+func (c *constraintsCommand) applicationSources(client ConstraintsAPI, modelCons constraints.Value) (map[string]ConstraintSource, error) {
+	appCons, err := client.GetConstraints(c.applicationName)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	sources := make(map[string]ConstraintSource)
+	addConstraintSources(sources, modelCons.String(), constraintSourceModel)
+	addConstraintSources(sources, appCons[0].String(), constraintSourceApplication)
+	return sources, nil
+}
+
+// machineSources returns the constraints the machine was provisioned
+// with, attributing each to the model or to an application deployed to
+// the machine where their values agree.
+func (c *constraintsCommand) machineSources(client ConstraintsAPI, modelCons constraints.Value) (map[string]ConstraintSource, error) {
+	status, err := client.Status([]string{c.machineId})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	machine, ok := findMachineStatus(status.Machines, c.machineId)
+	if !ok {
+		return nil, errors.NotFoundf("machine %q", c.machineId)
+	}
+	machineCons, err := constraints.Parse(machine.Constraints)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var applicationNames []string
+	for name, app := range status.Applications {
+		for _, unit := range app.Units {
+			if unit.Machine == c.machineId {
+				applicationNames = append(applicationNames, name)
+				break
+			}
+		}
+	}
+	sort.Strings(applicationNames)
+	var appCons []constraints.Value
+	if len(applicationNames) > 0 {
+		appCons, err = client.GetConstraints(applicationNames...)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+
+	modelAttrs := constraintAttributes(modelCons.String())
+	sources := make(map[string]ConstraintSource)
+	for name, value := range constraintAttributes(machineCons.String()) {
+		source := constraintSourceMachine
+		if modelAttrs[name] == value {
+			source = constraintSourceModel
+		}
+		for _, cons := range appCons {
+			if constraintAttributes(cons.String())[name] == value {
+				source = constraintSourceApplication
+				break
+			}
+		}
+		sources[name] = ConstraintSource{Value: value, Source: source}
+	}
+	return sources, nil
+}
+
+func findMachineStatus(machines map[string]params.MachineStatus, id string) (params.MachineStatus, bool) {
+	for machineId, machine := range machines {
+		if machineId == id {
+			return machine, true
+		}
+		if machine, ok := findMachineStatus(machine.Containers, id); ok {
+			return machine, true
+		}
+	}
+	return params.MachineStatus{}, false
+}
+
+// constraintAttributes splits constraints in their string form into
+// a map of each constraint's name to its value.
+func constraintAttributes(cons string) map[string]string {
+	attrs := make(map[string]string)
+	for _, field := range strings.Fields(cons) {
+		parts := strings.SplitN(field, "=", 2)
+		if len(parts) == 2 {
+			attrs[parts[0]] = parts[1]
+		}
+	}
+	return attrs
+}
+
+func addConstraintSources(sources map[string]ConstraintSource, cons, source string) {
+	for name, value := range constraintAttributes(cons) {
+		sources[name] = ConstraintSource{Value: value, Source: source}
+	}
+}
+
+func formatConstraintSourcesTabular(writer io.Writer, value interface{}) error {
+	sources, ok := value.(map[string]ConstraintSource)
+	if !ok {
+		return errors.Errorf("expected value of type %T, got %T", sources, value)
+	}
+	var constraintNames []string
+	for name := range sources {
+		constraintNames = append(constraintNames, name)
+	}
+	sort.Strings(constraintNames)
+
+	tw := output.TabWriter(writer)
+	w := output.Wrapper{tw}
+	w.Println("Constraint", "Value", "Source")
+	for _, name := range constraintNames {
+		w.Println(name, sources[name].Value, sources[name].Source)
+	}
+	return tw.Flush()
+}
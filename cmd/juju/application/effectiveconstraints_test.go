@@ -0,0 +1,208 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package application
+
+import (
+	"github.com/juju/cmd/cmdtesting"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/constraints"
+)
+
+type ConstraintsSuite struct {
+	testing.IsolationSuite
+	mockAPI *mockConstraintsAPI
+}
+
+var _ = gc.Suite(&ConstraintsSuite{})
+
+func (s *ConstraintsSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.mockAPI = &mockConstraintsAPI{
+		Stub:      &testing.Stub{},
+		modelCons: constraints.MustParse("mem=4G arch=amd64"),
+		appCons: map[string]constraints.Value{
+			"mysql":     constraints.MustParse("mem=8G cores=4"),
+			"wordpress": constraints.MustParse("cores=2"),
+		},
+		status: &params.FullStatus{
+			Machines: map[string]params.MachineStatus{
+				"0": {
+					Constraints: "arch=amd64 cores=4 mem=8G root-disk=20G",
+					Containers: map[string]params.MachineStatus{
+						"0/lxd/0": {Constraints: "cores=2"},
+					},
+				},
+			},
+			Applications: map[string]params.ApplicationStatus{
+				"mysql": {
+					Units: map[string]params.UnitStatus{
+						"mysql/0": {Machine: "0"},
+					},
+				},
+				"wordpress": {
+					Units: map[string]params.UnitStatus{
+						"wordpress/0": {Machine: "0/lxd/0"},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (s *ConstraintsSuite) runConstraints(c *gc.C, args ...string) (string, error) {
+	ctx, err := cmdtesting.RunCommand(c, NewConstraintsCommandForTest(s.mockAPI), args...)
+	if err != nil {
+		return "", err
+	}
+	return cmdtesting.Stdout(ctx), nil
+}
+
+func (s *ConstraintsSuite) TestInitErrors(c *gc.C) {
+	_, err := s.runConstraints(c, "foo/0")
+	c.Assert(err, gc.ErrorMatches, `invalid application or machine name "foo/0"`)
+
+	_, err = s.runConstraints(c, "0", "mem=4G")
+	c.Assert(err, gc.ErrorMatches, `cannot change constraints of machine "0" once it has been created`)
+
+	_, err = s.runConstraints(c, "mysql", "=")
+	c.Assert(err, gc.ErrorMatches, `malformed constraint "="`)
+	s.mockAPI.CheckNoCalls(c)
+}
+
+func (s *ConstraintsSuite) TestModel(c *gc.C) {
+	out, err := s.runConstraints(c)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(out, gc.Equals, `
+Constraint  Value  Source
+arch        amd64  model
+mem         4096M  model
+`[1:])
+	s.mockAPI.CheckCallNames(c, "GetModelConstraints", "Close")
+}
+
+func (s *ConstraintsSuite) TestApplication(c *gc.C) {
+	out, err := s.runConstraints(c, "mysql")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(out, gc.Equals, `
+Constraint  Value  Source
+arch        amd64  model
+cores       4      application
+mem         8192M  application
+`[1:])
+	s.mockAPI.CheckCalls(c, []testing.StubCall{
+		{"GetModelConstraints", nil},
+		{"GetConstraints", []interface{}{[]string{"mysql"}}},
+		{"Close", nil},
+	})
+}
+
+func (s *ConstraintsSuite) TestMachine(c *gc.C) {
+	out, err := s.runConstraints(c, "0", "--format", "yaml")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(out, gc.Equals, `
+arch:
+  value: amd64
+  source: model
+cores:
+  value: "4"
+  source: application
+mem:
+  value: 8192M
+  source: application
+root-disk:
+  value: 20480M
+  source: machine
+`[1:])
+	s.mockAPI.CheckCalls(c, []testing.StubCall{
+		{"GetModelConstraints", nil},
+		{"Status", []interface{}{[]string{"0"}}},
+		{"GetConstraints", []interface{}{[]string{"mysql"}}},
+		{"Close", nil},
+	})
+}
+
+func (s *ConstraintsSuite) TestContainer(c *gc.C) {
+	out, err := s.runConstraints(c, "0/lxd/0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(out, gc.Equals, `
+Constraint  Value  Source
+cores       2      application
+`[1:])
+}
+
+func (s *ConstraintsSuite) TestMachineNotFound(c *gc.C) {
+	_, err := s.runConstraints(c, "1")
+	c.Assert(err, gc.ErrorMatches, `machine "1" not found`)
+}
+
+func (s *ConstraintsSuite) TestNoConstraints(c *gc.C) {
+	s.mockAPI.modelCons = constraints.Value{}
+	ctx, err := cmdtesting.RunCommand(c, NewConstraintsCommandForTest(s.mockAPI))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, "")
+	c.Assert(cmdtesting.Stderr(ctx), gc.Equals, "No constraints set.\n")
+}
+
+func (s *ConstraintsSuite) TestSetModel(c *gc.C) {
+	_, err := s.runConstraints(c, "mem=2G")
+	c.Assert(err, jc.ErrorIsNil)
+	s.mockAPI.CheckCalls(c, []testing.StubCall{
+		{"SetModelConstraints", []interface{}{constraints.MustParse("mem=2G")}},
+		{"Close", nil},
+	})
+}
+
+func (s *ConstraintsSuite) TestSetApplication(c *gc.C) {
+	_, err := s.runConstraints(c, "mysql", "mem=16G", "cores=")
+	c.Assert(err, jc.ErrorIsNil)
+	s.mockAPI.CheckCalls(c, []testing.StubCall{
+		{"SetConstraints", []interface{}{"mysql", constraints.MustParse("mem=16G cores=")}},
+		{"Close", nil},
+	})
+}
+
+type mockConstraintsAPI struct {
+	*testing.Stub
+	modelCons constraints.Value
+	appCons   map[string]constraints.Value
+	status    *params.FullStatus
+}
+
+func (m *mockConstraintsAPI) Close() error {
+	m.MethodCall(m, "Close")
+	return m.NextErr()
+}
+
+func (m *mockConstraintsAPI) Status(patterns []string) (*params.FullStatus, error) {
+	m.MethodCall(m, "Status", patterns)
+	return m.status, m.NextErr()
+}
+
+func (m *mockConstraintsAPI) GetModelConstraints() (constraints.Value, error) {
+	m.MethodCall(m, "GetModelConstraints")
+	return m.modelCons, m.NextErr()
+}
+
+func (m *mockConstraintsAPI) SetModelConstraints(cons constraints.Value) error {
+	m.MethodCall(m, "SetModelConstraints", cons)
+	return m.NextErr()
+}
+
+func (m *mockConstraintsAPI) GetConstraints(applications ...string) ([]constraints.Value, error) {
+	m.MethodCall(m, "GetConstraints", applications)
+	result := make([]constraints.Value, len(applications))
+	for i, name := range applications {
+		result[i] = m.appCons[name]
+	}
+	return result, m.NextErr()
+}
+
+func (m *mockConstraintsAPI) SetConstraints(application string, cons constraints.Value) error {
+	m.MethodCall(m, "SetConstraints", application, cons)
+	return m.NextErr()
+}
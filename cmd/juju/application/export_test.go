@@ -118,3 +118,35 @@ func PatchNewCharmStoreClient(s Patcher, url string) {
 		})
 	})
 }
+
+// NewDiffBundleCommandForTest returns a DiffBundleCommand with the api provided as specified.
+func NewDiffBundleCommandForTest(api DiffBundleAPI) modelcmd.ModelCommand {
+	cmd := &diffBundleCommand{newAPIFunc: func() (DiffBundleAPI, error) {
+		return api, nil
+	}}
+	return modelcmd.Wrap(cmd)
+}
+
+// NewTrustCommandForTest returns a TrustCommand with the api provided as specified.
+func NewTrustCommandForTest(api TrustAPI) modelcmd.ModelCommand {
+	cmd := &trustCommand{newAPIFunc: func() (TrustAPI, error) {
+		return api, nil
+	}}
+	return modelcmd.Wrap(cmd)
+}
+
+// NewShowUnitCommandForTest returns a ShowUnitCommand with the api provided as specified.
+func NewShowUnitCommandForTest(api UnitsInfoAPI) modelcmd.ModelCommand {
+	cmd := &showUnitCommand{newAPIFunc: func() (UnitsInfoAPI, error) {
+		return api, nil
+	}}
+	return modelcmd.Wrap(cmd)
+}
+
+// NewConstraintsCommandForTest returns a ConstraintsCommand with the api provided as specified.
+func NewConstraintsCommandForTest(api ConstraintsAPI) modelcmd.ModelCommand {
+	cmd := &constraintsCommand{newAPIFunc: func() (ConstraintsAPI, error) {
+		return api, nil
+	}}
+	return modelcmd.Wrap(cmd)
+}
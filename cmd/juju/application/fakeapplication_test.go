@@ -18,10 +18,12 @@ type fakeApplicationAPI struct {
 	name        string
 	charmName   string
 	charmValues map[string]interface{}
-	appValues   map[string]interface{}
-	config      string
-	err         error
-	version     int
+	// charmDefaults holds the default values of charm settings.
+	charmDefaults map[string]interface{}
+	appValues     map[string]interface{}
+	config        string
+	err           error
+	version       int
 }
 
 func (f *fakeApplicationAPI) Update(args params.ApplicationUpdate) error {
@@ -52,11 +54,19 @@ func (f *fakeApplicationAPI) Get(application string) (*params.ApplicationGetResu
 
 	charmConfigInfo := make(map[string]interface{})
 	for k, v := range f.charmValues {
-		charmConfigInfo[k] = map[string]interface{}{
+		info := map[string]interface{}{
 			"description": fmt.Sprintf("Specifies %s", k),
 			"type":        fmt.Sprintf("%T", v),
 			"value":       v,
 		}
+		if def, ok := f.charmDefaults[k]; ok {
+			info["default"] = def
+			info["source"] = "user"
+			if def == v {
+				info["source"] = "default"
+			}
+		}
+		charmConfigInfo[k] = info
 	}
 	appConfigInfo := make(map[string]interface{})
 	for k, v := range f.appValues {
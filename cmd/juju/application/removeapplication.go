@@ -25,6 +25,7 @@ func NewRemoveApplicationCommand() cmd.Command {
 type removeApplicationCommand struct {
 	modelcmd.ModelCommandBase
 	DestroyStorage   bool
+	Force            bool
 	ApplicationNames []string
 }
 
@@ -40,9 +41,17 @@ before removing them. Removing units which are co-located with units of
 other charms or a Juju controller will not result in the removal of the
 machine.
 
+An application whose units are in an error state, or whose hooks never
+complete, can get stuck in the dying state. The --force option removes
+such an application along with its units and relations without waiting
+for the units' agents to run their remaining hooks. Because those hooks
+are skipped, the charm does not get the chance to clean up after itself;
+use --force only when a normal removal is not making progress.
+
 Examples:
     juju remove-application hadoop
-    juju remove-application -m test-model mariadb`[1:]
+    juju remove-application -m test-model mariadb
+    juju remove-application --force mysql`[1:]
 
 func (c *removeApplicationCommand) Info() *cmd.Info {
 	return &cmd.Info{
@@ -56,6 +65,7 @@ func (c *removeApplicationCommand) Info() *cmd.Info {
 func (c *removeApplicationCommand) SetFlags(f *gnuflag.FlagSet) {
 	c.ModelCommandBase.SetFlags(f)
 	f.BoolVar(&c.DestroyStorage, "destroy-storage", false, "Destroy storage attached to application units")
+	f.BoolVar(&c.Force, "force", false, "Remove the application even if its units are in error or their hooks hang")
 }
 
 func (c *removeApplicationCommand) Init(args []string) error {
@@ -103,6 +113,9 @@ func (c *removeApplicationCommand) Run(ctx *cmd.Context) error {
 	if c.DestroyStorage && apiVersion < 5 {
 		return errors.New("--destroy-storage is not supported by this controller")
 	}
+	if c.Force && apiVersion < 7 {
+		return errors.New("--force is not supported by this controller")
+	}
 	return c.removeApplications(ctx, client)
 }
 
@@ -128,6 +141,7 @@ func (c *removeApplicationCommand) removeApplications(
 	results, err := client.DestroyApplications(application.DestroyApplicationsParams{
 		Applications:   c.ApplicationNames,
 		DestroyStorage: c.DestroyStorage,
+		Force:          c.Force,
 	})
 	if err := block.ProcessBlockedError(err, block.BlockRemove); err != nil {
 		return errors.Trace(err)
@@ -147,7 +161,19 @@ func (c *removeApplicationCommand) removeApplications(
 				logger.Warningf("%s", err)
 				continue
 			}
-			ctx.Verbosef("- will remove %s", names.ReadableString(unitTag))
+			if c.Force {
+				ctx.Infof("- will remove %s without running its remaining hooks", names.ReadableString(unitTag))
+			} else {
+				ctx.Verbosef("- will remove %s", names.ReadableString(unitTag))
+			}
+		}
+		for _, entity := range result.Info.ForcedRelations {
+			relationTag, err := names.ParseRelationTag(entity.Tag)
+			if err != nil {
+				logger.Warningf("%s", err)
+				continue
+			}
+			ctx.Infof("- will break %s", names.ReadableString(relationTag))
 		}
 		for _, entity := range result.Info.DestroyedStorage {
 			storageTag, err := names.ParseStorageTag(entity.Tag)
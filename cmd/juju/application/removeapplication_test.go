@@ -134,6 +134,20 @@ removing application gargleblaster failed: application "gargleblaster" not found
 `[1:])
 }
 
+func (s *RemoveApplicationSuite) TestForce(c *gc.C) {
+	s.setupTestApplication(c)
+	ctx, err := runRemoveApplication(c, "--force", "multi-series")
+	c.Assert(err, jc.ErrorIsNil)
+	stderr := cmdtesting.Stderr(ctx)
+	c.Assert(stderr, gc.Equals, `
+removing application multi-series
+- will remove unit multi-series/0 without running its remaining hooks
+`[1:])
+	multiSeries, err := s.State.Application("multi-series")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(multiSeries.Life(), gc.Equals, state.Dying)
+}
+
 func (s *RemoveApplicationSuite) TestInvalidArgs(c *gc.C) {
 	_, err := runRemoveApplication(c)
 	c.Assert(err, gc.ErrorMatches, `no application specified`)
@@ -0,0 +1,185 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package application
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/api/application"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/common"
+	"github.com/juju/juju/cmd/modelcmd"
+)
+
+var usageShowUnitSummary = `
+Displays information about one or more units.`[1:]
+
+var usageShowUnitDetails = `
+The machine each unit is deployed to, its address and opened ports,
+whether it is its application's leader, its storage, the data it has
+set in each of its relations, and its recent workload and agent status
+history are shown. The default format is yaml; json is also available.
+
+Examples:
+    juju show-unit mysql/0
+    juju show-unit mysql/0 wordpress/1 --format json
+
+See also:
+    status
+    show-status-log
+    show-machine`[1:]
+
+// NewShowUnitCommand returns a command which displays
+// information about units.
+func NewShowUnitCommand() cmd.Command {
+	c := &showUnitCommand{}
+	c.newAPIFunc = func() (UnitsInfoAPI, error) {
+		root, err := c.NewAPIRoot()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return application.NewClient(root), nil
+	}
+	return modelcmd.Wrap(c)
+}
+
+// UnitsInfoAPI defines the API methods that the show-unit command uses.
+type UnitsInfoAPI interface {
+	Close() error
+	UnitsInfo([]names.UnitTag) ([]params.UnitInfoResult, error)
+}
+
+type showUnitCommand struct {
+	modelcmd.ModelCommandBase
+	out        cmd.Output
+	isoTime    bool
+	units      []names.UnitTag
+	newAPIFunc func() (UnitsInfoAPI, error)
+}
+
+// Info implements Command.Info.
+func (c *showUnitCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "show-unit",
+		Args:    "<unit name> ...",
+		Purpose: usageShowUnitSummary,
+		Doc:     usageShowUnitDetails,
+	}
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *showUnitCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.ModelCommandBase.SetFlags(f)
+	f.BoolVar(&c.isoTime, "utc", false, "Display time as UTC in RFC3339 format")
+	c.out.AddFlags(f, "yaml", map[string]cmd.Formatter{
+		"yaml": cmd.FormatYaml,
+		"json": cmd.FormatJson,
+	})
+}
+
+// Init implements Command.Init.
+func (c *showUnitCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no unit name specified")
+	}
+	for _, arg := range args {
+		if !names.IsValidUnit(arg) {
+			return errors.NotValidf("unit name %q", arg)
+		}
+		c.units = append(c.units, names.NewUnitTag(arg))
+	}
+	return nil
+}
+
+// Run implements Command.Run.
+func (c *showUnitCommand) Run(ctx *cmd.Context) error {
+	client, err := c.newAPIFunc()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	results, err := client.UnitsInfo(c.units)
+	if err != nil {
+		if errors.IsNotSupported(err) {
+			return errors.New("show-unit is not supported by this version of Juju")
+		}
+		return errors.Trace(err)
+	}
+	output := make(map[string]unitInfo)
+	for i, result := range results {
+		if result.Error != nil {
+			return errors.Annotatef(result.Error, "getting %s", c.units[i].Id())
+		}
+		output[c.units[i].Id()] = c.formatUnit(result.Result)
+	}
+	return c.out.Write(ctx, output)
+}
+
+type unitInfo struct {
+	Life                  string             `yaml:"life" json:"life"`
+	WorkloadVersion       string             `yaml:"workload-version,omitempty" json:"workload-version,omitempty"`
+	Machine               string             `yaml:"machine,omitempty" json:"machine,omitempty"`
+	PublicAddress         string             `yaml:"public-address,omitempty" json:"public-address,omitempty"`
+	OpenedPorts           []string           `yaml:"opened-ports,omitempty" json:"opened-ports,omitempty"`
+	Leader                bool               `yaml:"leader,omitempty" json:"leader,omitempty"`
+	Storage               []string           `yaml:"storage,omitempty" json:"storage,omitempty"`
+	RelationData          []unitRelationData `yaml:"relation-info,omitempty" json:"relation-info,omitempty"`
+	WorkloadStatusHistory []unitStatus       `yaml:"workload-status-history,omitempty" json:"workload-status-history,omitempty"`
+	AgentStatusHistory    []unitStatus       `yaml:"agent-status-history,omitempty" json:"agent-status-history,omitempty"`
+}
+
+type unitRelationData struct {
+	RelationId      int                    `yaml:"relation-id" json:"relation-id"`
+	Endpoint        string                 `yaml:"endpoint" json:"endpoint"`
+	RelatedEndpoint string                 `yaml:"related-endpoint" json:"related-endpoint"`
+	Data            map[string]interface{} `yaml:"data,omitempty" json:"data,omitempty"`
+}
+
+type unitStatus struct {
+	Status  string `yaml:"status" json:"status"`
+	Message string `yaml:"message,omitempty" json:"message,omitempty"`
+	Since   string `yaml:"since,omitempty" json:"since,omitempty"`
+}
+
+func (c *showUnitCommand) formatUnit(result *params.UnitResult) unitInfo {
+	info := unitInfo{
+		Life:                  result.Life,
+		WorkloadVersion:       result.WorkloadVersion,
+		Machine:               result.Machine,
+		PublicAddress:         result.PublicAddress,
+		OpenedPorts:           result.OpenedPorts,
+		Leader:                result.Leader,
+		Storage:               result.Storage,
+		WorkloadStatusHistory: c.formatHistory(result.WorkloadStatusHistory),
+		AgentStatusHistory:    c.formatHistory(result.AgentStatusHistory),
+	}
+	for _, rel := range result.RelationData {
+		info.RelationData = append(info.RelationData, unitRelationData{
+			RelationId:      rel.RelationId,
+			Endpoint:        rel.Endpoint,
+			RelatedEndpoint: rel.RelatedEndpoint,
+			Data:            rel.Data,
+		})
+	}
+	return info
+}
+
+func (c *showUnitCommand) formatHistory(history []params.DetailedStatus) []unitStatus {
+	var result []unitStatus
+	for _, s := range history {
+		status := unitStatus{
+			Status:  s.Status,
+			Message: s.Info,
+		}
+		if s.Since != nil {
+			status.Since = common.FormatTime(s.Since, c.isoTime)
+		}
+		result = append(result, status)
+	}
+	return result
+}
@@ -0,0 +1,150 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package application
+
+import (
+	"time"
+
+	"github.com/juju/cmd/cmdtesting"
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/apiserver/params"
+)
+
+type ShowUnitSuite struct {
+	testing.IsolationSuite
+	mockAPI *mockUnitsInfoAPI
+}
+
+var _ = gc.Suite(&ShowUnitSuite{})
+
+func (s *ShowUnitSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	since := time.Date(2018, 3, 14, 9, 26, 53, 0, time.UTC)
+	s.mockAPI = &mockUnitsInfoAPI{
+		Stub: &testing.Stub{},
+		results: []params.UnitInfoResult{{
+			Result: &params.UnitResult{
+				Tag:             "unit-mysql-0",
+				Life:            "alive",
+				WorkloadVersion: "5.7",
+				Machine:         "0",
+				PublicAddress:   "10.0.0.1",
+				OpenedPorts:     []string{"3306/tcp"},
+				Leader:          true,
+				Storage:         []string{"data/0"},
+				RelationData: []params.EndpointRelationData{{
+					RelationId:      1,
+					Endpoint:        "db",
+					RelatedEndpoint: "wordpress:db",
+					Data:            map[string]interface{}{"user": "wordpress"},
+				}},
+				WorkloadStatusHistory: []params.DetailedStatus{
+					{Status: "active", Info: "ready", Since: &since},
+				},
+				AgentStatusHistory: []params.DetailedStatus{
+					{Status: "idle", Since: &since},
+				},
+			},
+		}},
+	}
+}
+
+func (s *ShowUnitSuite) runShowUnit(c *gc.C, args ...string) (string, error) {
+	ctx, err := cmdtesting.RunCommand(c, NewShowUnitCommandForTest(s.mockAPI), args...)
+	if err != nil {
+		return "", err
+	}
+	return cmdtesting.Stdout(ctx), nil
+}
+
+func (s *ShowUnitSuite) TestInitErrors(c *gc.C) {
+	_, err := s.runShowUnit(c)
+	c.Assert(err, gc.ErrorMatches, "no unit name specified")
+
+	_, err = s.runShowUnit(c, "mysql")
+	c.Assert(err, gc.ErrorMatches, `unit name "mysql" not valid`)
+	s.mockAPI.CheckNoCalls(c)
+}
+
+func (s *ShowUnitSuite) TestShowUnitYAML(c *gc.C) {
+	out, err := s.runShowUnit(c, "mysql/0", "--utc")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(out, gc.Equals, `
+mysql/0:
+  life: alive
+  workload-version: "5.7"
+  machine: "0"
+  public-address: 10.0.0.1
+  opened-ports:
+  - 3306/tcp
+  leader: true
+  storage:
+  - data/0
+  relation-info:
+  - relation-id: 1
+    endpoint: db
+    related-endpoint: wordpress:db
+    data:
+      user: wordpress
+  workload-status-history:
+  - status: active
+    message: ready
+    since: 2018-03-14 09:26:53Z
+  agent-status-history:
+  - status: idle
+    since: 2018-03-14 09:26:53Z
+`[1:])
+	s.mockAPI.CheckCalls(c, []testing.StubCall{
+		{"UnitsInfo", []interface{}{[]names.UnitTag{names.NewUnitTag("mysql/0")}}},
+		{"Close", nil},
+	})
+}
+
+func (s *ShowUnitSuite) TestShowUnitJSON(c *gc.C) {
+	s.mockAPI.results = []params.UnitInfoResult{{
+		Result: &params.UnitResult{Tag: "unit-mysql-0", Life: "alive"},
+	}, {
+		Result: &params.UnitResult{Tag: "unit-mysql-1", Life: "dying", Machine: "1"},
+	}}
+	out, err := s.runShowUnit(c, "mysql/0", "mysql/1", "--format", "json")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(out, gc.Equals, `{"mysql/0":{"life":"alive"},"mysql/1":{"life":"dying","machine":"1"}}`+"\n")
+}
+
+func (s *ShowUnitSuite) TestShowUnitError(c *gc.C) {
+	s.mockAPI.results = []params.UnitInfoResult{{
+		Error: &params.Error{Message: `unit "mysql/0" not found`, Code: params.CodeNotFound},
+	}}
+	_, err := s.runShowUnit(c, "mysql/0")
+	c.Assert(err, gc.ErrorMatches, `getting mysql/0: unit "mysql/0" not found`)
+}
+
+func (s *ShowUnitSuite) TestShowUnitNotSupported(c *gc.C) {
+	s.mockAPI.SetErrors(errors.NotSupportedf("UnitsInfo"))
+	_, err := s.runShowUnit(c, "mysql/0")
+	c.Assert(err, gc.ErrorMatches, "show-unit is not supported by this version of Juju")
+}
+
+type mockUnitsInfoAPI struct {
+	*testing.Stub
+	results []params.UnitInfoResult
+}
+
+func (m *mockUnitsInfoAPI) Close() error {
+	m.MethodCall(m, "Close")
+	return nil
+}
+
+func (m *mockUnitsInfoAPI) UnitsInfo(units []names.UnitTag) ([]params.UnitInfoResult, error) {
+	m.MethodCall(m, "UnitsInfo", units)
+	if err := m.NextErr(); err != nil {
+		return nil, err
+	}
+	return m.results, nil
+}
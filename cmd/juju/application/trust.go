@@ -0,0 +1,105 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package application
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/api/application"
+	"github.com/juju/juju/cmd/juju/block"
+	"github.com/juju/juju/cmd/modelcmd"
+)
+
+var usageTrustSummary = `
+Grants an application access to cloud credentials and cluster-scoped permissions.`[1:]
+
+var usageTrustDetails = `
+Applications are not trusted by default: charms which need the model's
+cloud credentials, or permissions which extend beyond the application
+itself (such as cluster-scoped roles on Kubernetes), are only granted
+them when the application is explicitly trusted.
+
+Trust is recorded in the "trust" application config option, so it can
+also be granted at deploy time with --config trust=true, and shown with
+juju config <application> trust, unless the charm has a config option
+of the same name.
+
+Use --remove to revoke trust from an application.
+
+Examples:
+    juju trust aws-integrator
+    juju trust aws-integrator --remove
+
+See also: 
+    config
+    deploy`[1:]
+
+// NewTrustCommand returns a command to grant or revoke
+// an application's trust.
+func NewTrustCommand() modelcmd.ModelCommand {
+	cmd := &trustCommand{}
+	cmd.newAPIFunc = func() (TrustAPI, error) {
+		root, err := cmd.NewAPIRoot()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return application.NewClient(root), nil
+	}
+	return modelcmd.Wrap(cmd)
+}
+
+// trustCommand is responsible for granting and revoking
+// an application's trust.
+type trustCommand struct {
+	modelcmd.ModelCommandBase
+	applicationName string
+	removeTrust     bool
+	newAPIFunc      func() (TrustAPI, error)
+}
+
+// TrustAPI defines the API methods that the trust command uses.
+type TrustAPI interface {
+	Close() error
+	SetApplicationTrust(application string, trusted bool) error
+}
+
+func (c *trustCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "trust",
+		Args:    "<application name>",
+		Purpose: usageTrustSummary,
+		Doc:     usageTrustDetails,
+	}
+}
+
+func (c *trustCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.ModelCommandBase.SetFlags(f)
+	f.BoolVar(&c.removeTrust, "remove", false, "Remove trust from the application")
+}
+
+func (c *trustCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no application name specified")
+	}
+	c.applicationName = args[0]
+	if !names.IsValidApplication(c.applicationName) {
+		return errors.Errorf("invalid application name %q", c.applicationName)
+	}
+	return cmd.CheckEmpty(args[1:])
+}
+
+// Run grants or revokes the application's trust.
+func (c *trustCommand) Run(_ *cmd.Context) error {
+	client, err := c.newAPIFunc()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	err = client.SetApplicationTrust(c.applicationName, !c.removeTrust)
+	return block.ProcessBlockedError(err, block.BlockChange)
+}
@@ -0,0 +1,90 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package application
+
+import (
+	"github.com/juju/cmd/cmdtesting"
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/common"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type TrustSuite struct {
+	testing.IsolationSuite
+	mockAPI *mockTrustAPI
+}
+
+var _ = gc.Suite(&TrustSuite{})
+
+func (s *TrustSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.mockAPI = &mockTrustAPI{Stub: &testing.Stub{}}
+}
+
+func (s *TrustSuite) runTrust(c *gc.C, args ...string) error {
+	_, err := cmdtesting.RunCommand(c, NewTrustCommandForTest(s.mockAPI), args...)
+	return err
+}
+
+func (s *TrustSuite) TestTrustInvalidArguments(c *gc.C) {
+	err := s.runTrust(c)
+	c.Assert(err, gc.ErrorMatches, "no application name specified")
+
+	err = s.runTrust(c, "foo/0")
+	c.Assert(err, gc.ErrorMatches, `invalid application name "foo/0"`)
+
+	err = s.runTrust(c, "foo", "bar")
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["bar"\]`)
+	s.mockAPI.CheckNoCalls(c)
+}
+
+func (s *TrustSuite) TestTrust(c *gc.C) {
+	err := s.runTrust(c, "gitlab")
+	c.Assert(err, jc.ErrorIsNil)
+	s.mockAPI.CheckCalls(c, []testing.StubCall{
+		{"SetApplicationTrust", []interface{}{"gitlab", true}},
+		{"Close", nil},
+	})
+}
+
+func (s *TrustSuite) TestRemoveTrust(c *gc.C) {
+	err := s.runTrust(c, "gitlab", "--remove")
+	c.Assert(err, jc.ErrorIsNil)
+	s.mockAPI.CheckCalls(c, []testing.StubCall{
+		{"SetApplicationTrust", []interface{}{"gitlab", false}},
+		{"Close", nil},
+	})
+}
+
+func (s *TrustSuite) TestTrustFail(c *gc.C) {
+	s.mockAPI.SetErrors(errors.New("boom"))
+	err := s.runTrust(c, "gitlab")
+	c.Assert(err, gc.ErrorMatches, "boom")
+	s.mockAPI.CheckCallNames(c, "SetApplicationTrust", "Close")
+}
+
+func (s *TrustSuite) TestTrustBlocked(c *gc.C) {
+	s.mockAPI.SetErrors(common.OperationBlockedError("TestTrustBlocked"))
+	err := s.runTrust(c, "gitlab")
+	coretesting.AssertOperationWasBlocked(c, err, ".*TestTrustBlocked.*")
+	s.mockAPI.CheckCallNames(c, "SetApplicationTrust", "Close")
+}
+
+type mockTrustAPI struct {
+	*testing.Stub
+}
+
+func (m *mockTrustAPI) Close() error {
+	m.MethodCall(m, "Close")
+	return nil
+}
+
+func (m *mockTrustAPI) SetApplicationTrust(application string, trusted bool) error {
+	m.MethodCall(m, "SetApplicationTrust", application, trusted)
+	return m.NextErr()
+}
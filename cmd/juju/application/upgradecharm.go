@@ -150,6 +150,18 @@ repeated more than once to upload more than one resource.
 
 Where bar and baz are resources named in the metadata for the foo charm.
 
+Instead of a file, a charm store resource may be given a revision number,
+which pins the resource to that revision of the resource in the charm store
+rather than the one published with the charm.
+
+  juju upgrade-charm foo --resource bar=3
+
+The charm store channel from which to take the new revision of the charm
+may be chosen with the --channel flag. The channel is recorded against the
+application along with the new charm.
+
+  juju upgrade-charm foo --channel edge
+
 Storage constraints may be added or updated at upgrade time by specifying
 the --storage flag, with the same format as specified in "juju deploy".
 If new required storage is added by the new charm revision, then you must
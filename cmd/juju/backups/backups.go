@@ -31,6 +31,9 @@ type APIClient interface {
 	List() (*params.BackupsListResult, error)
 	// Download pulls the backup archive file.
 	Download(id string) (io.ReadCloser, error)
+	// Stream creates a new backup and pulls its archive file as it is
+	// built, without it being stored.
+	Stream(notes string) (io.ReadCloser, error)
 	// Upload pushes a backup archive to storage.
 	Upload(ar io.ReadSeeker, meta params.BackupsMetadataResult) (string, error)
 	// Remove removes the stored backup.
@@ -31,6 +31,12 @@ The backup archive and associated metadata are stored remotely by Juju, but
 will also be copied locally unless --no-download is supplied. To access the
 remote backups, see 'juju download-backup'.
 
+With --stream, the archive is sent straight to the client as it is built,
+and is neither staged on the controller's disk nor stored remotely. This
+allows backups of controllers with little free disk space. No backup ID is
+printed in this case, since there is no remote backup to refer to.
+Streaming requires the controller to be running mongo 3.4 or later.
+
 See also:
     backups
     download-backup
@@ -50,6 +56,9 @@ type createCommand struct {
 	Filename string
 	// Notes is the custom message to associated with the new backup.
 	Notes string
+	// Stream means the backup archive should be streamed to the client
+	// rather than stored remotely.
+	Stream bool
 }
 
 // Info implements Command.Info.
@@ -67,6 +76,7 @@ func (c *createCommand) SetFlags(f *gnuflag.FlagSet) {
 	c.CommandBase.SetFlags(f)
 	f.BoolVar(&c.NoDownload, "no-download", false, "Do not download the archive")
 	f.StringVar(&c.Filename, "filename", notset, "Download to this file")
+	f.BoolVar(&c.Stream, "stream", false, "Stream the archive to the client without storing it remotely")
 }
 
 // Init implements Command.Init.
@@ -80,6 +90,9 @@ func (c *createCommand) Init(args []string) error {
 	if c.Filename != notset && c.NoDownload {
 		return errors.Errorf("cannot mix --no-download and --filename")
 	}
+	if c.Stream && c.NoDownload {
+		return errors.Errorf("cannot mix --no-download and --stream")
+	}
 	if c.Filename == "" {
 		return errors.Errorf("missing filename")
 	}
@@ -100,6 +113,11 @@ func (c *createCommand) Run(ctx *cmd.Context) error {
 	}
 	defer client.Close()
 
+	if c.Stream {
+		filename := c.decideFilename(ctx, c.Filename, time.Now().UTC())
+		return errors.Trace(c.stream(ctx, client, filename))
+	}
+
 	result, err := client.Create(c.Notes)
 	if err != nil {
 		return errors.Trace(err)
@@ -156,6 +174,27 @@ func (c *createCommand) download(ctx *cmd.Context, id string, filename string) e
 	}
 	defer archive.Close()
 
+	return errors.Trace(writeArchive(filename, archive))
+}
+
+func (c *createCommand) stream(ctx *cmd.Context, client APIClient, filename string) error {
+	archive, err := client.Stream(c.Notes)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer archive.Close()
+
+	fmt.Fprintln(ctx.Stdout, "downloading to "+filename)
+	if err := writeArchive(filename, archive); err != nil {
+		// Don't leave a partial archive around to be mistaken
+		// for a usable backup.
+		os.Remove(filename)
+		return errors.Trace(err)
+	}
+	return nil
+}
+
+func writeArchive(filename string, archive io.Reader) error {
 	outfile, err := os.Create(filename)
 	if err != nil {
 		return errors.Trace(err)
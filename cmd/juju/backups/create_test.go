@@ -139,3 +139,39 @@ func (s *createSuite) TestError(c *gc.C) {
 
 	c.Check(errors.Cause(err), gc.ErrorMatches, "failed!")
 }
+
+func (s *createSuite) TestStream(c *gc.C) {
+	client := s.setDownload()
+	ctx, err := cmdtesting.RunCommand(c, s.wrappedCommand, "spam", "--stream", "--filename", "backup.tgz")
+	c.Assert(err, jc.ErrorIsNil)
+
+	client.Check(c, "", "spam", "Stream")
+	s.checkStd(c, ctx, "downloading to backup.tgz\n", "")
+	s.filename = "backup.tgz"
+	s.checkArchive(c)
+}
+
+func (s *createSuite) TestStreamDefaultFilename(c *gc.C) {
+	s.setDownload()
+	ctx, err := cmdtesting.RunCommand(c, s.wrappedCommand, "--stream")
+	c.Assert(err, jc.ErrorIsNil)
+
+	out := ctx.Stdout.(*bytes.Buffer).String()
+	c.Assert(out, gc.Matches, `downloading to juju-backup-\d{8}-\d{6}\.tar\.gz\n`)
+	s.filename = strings.TrimSpace(strings.TrimPrefix(out, "downloading to "))
+	s.checkArchive(c)
+}
+
+func (s *createSuite) TestStreamError(c *gc.C) {
+	s.setFailure("failed!")
+	_, err := cmdtesting.RunCommand(c, s.wrappedCommand, "--stream", "--filename", "backup.tgz")
+
+	c.Check(errors.Cause(err), gc.ErrorMatches, "failed!")
+}
+
+func (s *createSuite) TestStreamAndNoDownload(c *gc.C) {
+	s.setSuccess()
+	_, err := cmdtesting.RunCommand(c, s.wrappedCommand, "--no-download", "--stream")
+
+	c.Check(err, gc.ErrorMatches, "cannot mix --no-download and --stream")
+}
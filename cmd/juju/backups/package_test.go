@@ -171,6 +171,16 @@ func (c *fakeAPIClient) Download(id string) (io.ReadCloser, error) {
 	return c.archive, nil
 }
 
+func (c *fakeAPIClient) Stream(notes string) (io.ReadCloser, error) {
+	c.calls = append(c.calls, "Stream")
+	c.args = append(c.args, "notes")
+	c.notes = notes
+	if c.err != nil {
+		return nil, c.err
+	}
+	return c.archive, nil
+}
+
 func (c *fakeAPIClient) Upload(ar io.ReadSeeker, meta params.BackupsMetadataResult) (string, error) {
 	c.args = append(c.args, "ar", "meta")
 	if c.err != nil {
@@ -15,10 +15,13 @@ import (
 	"github.com/juju/juju/api"
 	"github.com/juju/juju/api/base"
 	cloudapi "github.com/juju/juju/api/cloud"
+	jujucaas "github.com/juju/juju/caas"
+	caasall "github.com/juju/juju/caas/all"
 	"github.com/juju/juju/caas/kubernetes/clientconfig"
 	"github.com/juju/juju/cloud"
 	"github.com/juju/juju/cmd/juju/common"
 	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/environs"
 	"github.com/juju/juju/jujuclient"
 )
 
@@ -43,9 +46,12 @@ var usageAddCAASSummary = `
 Adds a CAAS endpoint and credential to Juju from among known types.`[1:]
 
 var usageAddCAASDetails = `
+The cluster and credential are read from the client configuration of the
+CAAS type, using its current context unless --context is given.
 
 Examples:
-    juju add-caas myk8s kubernetes
+    juju add-caas kubernetes myk8s
+    juju add-caas kubernetes myk8s --context staging
 
 See also:
     caas`
@@ -68,11 +74,16 @@ type AddCAASCommand struct {
 	apiRoot               api.Connection
 	newCloudAPI           func(base.APICallCloser) CloudAPI
 	newClientConfigReader func(string) (clientconfig.ClientConfigFunc, error)
+	clusterMetadata       func(environs.CloudSpec) (*jujucaas.ClusterMetadata, error)
 }
 
 // NewAddCAASCommand returns a command to add caas information.
 func NewAddCAASCommand(cloudMetadataStore CloudMetadataStore) cmd.Command {
-	cmd := &AddCAASCommand{
+	return modelcmd.Wrap(newAddCAASCommand(cloudMetadataStore))
+}
+
+func newAddCAASCommand(cloudMetadataStore CloudMetadataStore) *AddCAASCommand {
+	return &AddCAASCommand{
 		cloudMetadataStore:  cloudMetadataStore,
 		fileCredentialStore: jujuclient.NewFileCredentialStore(),
 		newCloudAPI: func(caller base.APICallCloser) CloudAPI {
@@ -81,19 +92,25 @@ func NewAddCAASCommand(cloudMetadataStore CloudMetadataStore) cmd.Command {
 		newClientConfigReader: func(caasType string) (clientconfig.ClientConfigFunc, error) {
 			return clientconfig.NewClientConfigReader(caasType)
 		},
+		clusterMetadata: caasall.ClusterMetadata,
 	}
-	return modelcmd.Wrap(cmd)
 }
-func NewAddCAASCommandForTest(cloudMetadataStore CloudMetadataStore, fileCredentialStore jujuclient.CredentialStore, clientStore jujuclient.ClientStore, apiRoot api.Connection, newCloudAPIFunc func(base.APICallCloser) CloudAPI, newClientConfigReaderFunc func(string) (clientconfig.ClientConfigFunc, error)) cmd.Command {
+
+func NewAddCAASCommandForTest(cloudMetadataStore CloudMetadataStore, fileCredentialStore jujuclient.CredentialStore, clientStore jujuclient.ClientStore, apiRoot api.Connection, newCloudAPIFunc func(base.APICallCloser) CloudAPI, newClientConfigReaderFunc func(string) (clientconfig.ClientConfigFunc, error), clusterMetadataFunc func(environs.CloudSpec) (*jujucaas.ClusterMetadata, error)) cmd.Command {
+	return modelcmd.Wrap(newAddCAASCommandForTest(cloudMetadataStore, fileCredentialStore, clientStore, apiRoot, newCloudAPIFunc, newClientConfigReaderFunc, clusterMetadataFunc))
+}
+
+func newAddCAASCommandForTest(cloudMetadataStore CloudMetadataStore, fileCredentialStore jujuclient.CredentialStore, clientStore jujuclient.ClientStore, apiRoot api.Connection, newCloudAPIFunc func(base.APICallCloser) CloudAPI, newClientConfigReaderFunc func(string) (clientconfig.ClientConfigFunc, error), clusterMetadataFunc func(environs.CloudSpec) (*jujucaas.ClusterMetadata, error)) *AddCAASCommand {
 	cmd := &AddCAASCommand{
 		cloudMetadataStore:    cloudMetadataStore,
 		fileCredentialStore:   fileCredentialStore,
 		apiRoot:               apiRoot,
 		newCloudAPI:           newCloudAPIFunc,
 		newClientConfigReader: newClientConfigReaderFunc,
+		clusterMetadata:       clusterMetadataFunc,
 	}
 	cmd.SetClientStore(clientStore)
-	return modelcmd.Wrap(cmd)
+	return cmd
 }
 
 // Info returns help information about the command.
@@ -109,6 +126,7 @@ func (c *AddCAASCommand) Info() *cmd.Info {
 // SetFlags initializes the flags supported by the command.
 func (c *AddCAASCommand) SetFlags(f *gnuflag.FlagSet) {
 	c.CommandBase.SetFlags(f)
+	f.StringVar(&c.context, "context", "", "The context in the client configuration to add (default: the current context)")
 }
 
 // Init populates the command with the args from the command line.
@@ -155,12 +173,19 @@ func (c *AddCAASCommand) Run(ctxt *cmd.Context) error {
 	if len(caasConfig.Contexts) == 0 {
 		return errors.Errorf("No CAAS cluster definitions found in config")
 	}
-	defaultContext := caasConfig.Contexts[caasConfig.CurrentContext]
+	contextName := caasConfig.CurrentContext
+	if c.context != "" {
+		contextName = c.context
+	}
+	context, ok := caasConfig.Contexts[contextName]
+	if !ok {
+		return errors.NotFoundf("context %q in %s config", contextName, c.caasType)
+	}
 
-	defaultCredential := caasConfig.Credentials[defaultContext.CredentialName]
-	defaultCloud := caasConfig.Clouds[defaultContext.CloudName]
+	credential := caasConfig.Credentials[context.CredentialName]
+	cloudConfig := caasConfig.Clouds[context.CloudName]
 
-	defaultCloudCAData, ok := defaultCloud.Attributes["CAData"].(string)
+	cloudCAData, ok := cloudConfig.Attributes["CAData"].(string)
 	if !ok {
 		return errors.Errorf("CAData attribute should be a string")
 	}
@@ -168,9 +193,13 @@ func (c *AddCAASCommand) Run(ctxt *cmd.Context) error {
 	newCloud := cloud.Cloud{
 		Name:           c.caasName,
 		Type:           c.caasType,
-		Endpoint:       defaultCloud.Endpoint,
-		AuthTypes:      []cloud.AuthType{defaultCredential.AuthType()},
-		CACertificates: []string{defaultCloudCAData},
+		Endpoint:       cloudConfig.Endpoint,
+		AuthTypes:      []cloud.AuthType{credential.AuthType()},
+		CACertificates: []string{cloudCAData},
+	}
+
+	if err := c.checkCluster(newCloud, credential); err != nil {
+		return errors.Trace(err)
 	}
 
 	if err := addCloudToLocal(c.cloudMetadataStore, newCloud); err != nil {
@@ -183,17 +212,36 @@ func (c *AddCAASCommand) Run(ctxt *cmd.Context) error {
 		return errors.Trace(err)
 	}
 
-	if err := c.addCredentialToLocal(c.caasName, defaultCredential, defaultContext.CredentialName); err != nil {
+	if err := c.addCredentialToLocal(c.caasName, credential, context.CredentialName); err != nil {
 		return errors.Trace(err)
 	}
 
-	if err := c.addCredentialToController(cloudClient, defaultCredential, defaultContext.CredentialName); err != nil {
+	if err := c.addCredentialToController(cloudClient, credential, context.CredentialName); err != nil {
 		return errors.Trace(err)
 	}
 
 	return nil
 }
 
+// checkCluster returns an error if Juju cannot deploy
+// applications on the cluster, so that it is not added.
+func (c *AddCAASCommand) checkCluster(newCloud cloud.Cloud, credential cloud.Credential) error {
+	metadata, err := c.clusterMetadata(environs.CloudSpec{
+		Type:           newCloud.Type,
+		Name:           newCloud.Name,
+		Endpoint:       newCloud.Endpoint,
+		Credential:     &credential,
+		CACertificates: newCloud.CACertificates,
+	})
+	if err != nil {
+		return errors.Annotatef(err, "querying %s cluster", c.caasType)
+	}
+	if err := metadata.Validate(); err != nil {
+		return errors.Annotatef(err, "cannot add %s cluster", c.caasType)
+	}
+	return nil
+}
+
 func (c *AddCAASCommand) verifyName(name string) error {
 	public, _, err := c.cloudMetadataStore.PublicCloudMetadata()
 	if err != nil {
@@ -10,14 +10,17 @@ import (
 	"github.com/juju/loggo"
 	jujutesting "github.com/juju/testing"
 	jc "github.com/juju/testing/checkers"
+	"github.com/juju/version"
 	gc "gopkg.in/check.v1"
 	"gopkg.in/juju/names.v2"
 
 	"github.com/juju/juju/api"
 	"github.com/juju/juju/api/base"
+	jujucaas "github.com/juju/juju/caas"
 	"github.com/juju/juju/caas/kubernetes/clientconfig"
 	"github.com/juju/juju/cloud"
 	"github.com/juju/juju/cmd/juju/caas"
+	"github.com/juju/juju/environs"
 	"github.com/juju/juju/jujuclient"
 )
 
@@ -27,6 +30,8 @@ type addCAASSuite struct {
 	store               *fakeCloudMetadataStore
 	fileCredentialStore *fakeCredentialStore
 	fakeK8SConfigFunc   clientconfig.ClientConfigFunc
+	clusterMetadata     jujucaas.ClusterMetadata
+	clusterSpec         environs.CloudSpec
 }
 
 var _ = gc.Suite(&addCAASSuite{})
@@ -88,6 +93,9 @@ func fakeK8SClientConfig() (*clientconfig.ClientConfig, error) {
 		Contexts: map[string]clientconfig.Context{"somekey": clientconfig.Context{
 			CloudName:      "mrcloud",
 			CredentialName: "credname",
+		}, "otherkey": clientconfig.Context{
+			CloudName:      "othercloud",
+			CredentialName: "credname",
 		},
 		},
 		CurrentContext: "somekey",
@@ -96,6 +104,11 @@ func fakeK8SClientConfig() (*clientconfig.ClientConfig, error) {
 			Attributes: map[string]interface{}{
 				"CAData": "fakecadata",
 			},
+		}, "othercloud": clientconfig.CloudConfig{
+			Endpoint: "otherendpoint",
+			Attributes: map[string]interface{}{
+				"CAData": "othercadata",
+			},
 		},
 		},
 	}, nil
@@ -133,6 +146,11 @@ func (s *addCAASSuite) SetUpTest(c *gc.C) {
 			names.NewCloudCredentialTag("aws/other/secrets"),
 		},
 	}
+	s.clusterMetadata = jujucaas.ClusterMetadata{
+		Version:    version.MustParse("1.9.3"),
+		MinVersion: version.MustParse("1.6.0"),
+		Nodes:      []jujucaas.NodeCapacity{{Name: "node-1", CpuPower: 200, Mem: 4096}},
+	}
 	var logger loggo.Logger
 	s.store = &fakeCloudMetadataStore{CallMocker: jujutesting.NewCallMocker(logger)}
 
@@ -187,10 +205,33 @@ func (s *addCAASSuite) makeCommand(c *gc.C, cloudTypeExists bool, emptyClientCon
 				return fakeK8SClientConfig, nil
 			}
 		},
+		func(spec environs.CloudSpec) (*jujucaas.ClusterMetadata, error) {
+			s.clusterSpec = spec
+			return &s.clusterMetadata, nil
+		},
 	)
 	return addcmd
 }
 
+func (s *addCAASSuite) makeK8SCommand(c *gc.C) cmd.Command {
+	return caas.NewAddK8SCommandForTest(s.store,
+		&fakeCredentialStore{},
+		NewMockClientStore(),
+		&fakeAPIConnection{},
+		func(caller base.APICallCloser) caas.CloudAPI {
+			return s.fakeCloudAPI
+		},
+		func(caasType string) (clientconfig.ClientConfigFunc, error) {
+			c.Check(caasType, gc.Equals, "kubernetes")
+			return fakeK8SClientConfig, nil
+		},
+		func(spec environs.CloudSpec) (*jujucaas.ClusterMetadata, error) {
+			s.clusterSpec = spec
+			return &s.clusterMetadata, nil
+		},
+	)
+}
+
 func (s *addCAASSuite) runCommand(c *gc.C, cmd cmd.Command, args ...string) (*cmd.Context, error) {
 	return cmdtesting.RunCommand(c, cmd, args...)
 }
@@ -261,3 +302,67 @@ func (s *addCAASSuite) TestCorrect(c *gc.C) {
 				CACertificates:   []string{"fakecadata"},
 			}})
 }
+
+func (s *addCAASSuite) TestQueriesCluster(c *gc.C) {
+	cmd := s.makeCommand(c, true, false)
+	_, err := s.runCommand(c, cmd, "kubernetes", "myk8s")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.clusterSpec.Type, gc.Equals, "kubernetes")
+	c.Assert(s.clusterSpec.Name, gc.Equals, "myk8s")
+	c.Assert(s.clusterSpec.Endpoint, gc.Equals, "fakeendpoint")
+	c.Assert(s.clusterSpec.Credential, gc.NotNil)
+}
+
+func (s *addCAASSuite) TestUnsupportedCluster(c *gc.C) {
+	s.clusterMetadata.Version = version.MustParse("1.5.2")
+	cmd := s.makeCommand(c, true, false)
+	_, err := s.runCommand(c, cmd, "kubernetes", "myk8s")
+	c.Assert(err, gc.ErrorMatches, `cannot add kubernetes cluster: cluster version 1.5.2 is not supported, version 1.6.0 or later is required`)
+	for _, call := range s.store.Calls() {
+		c.Assert(call.FuncName, gc.Not(gc.Equals), "WritePersonalCloudMetadata")
+	}
+}
+
+func (s *addCAASSuite) TestContext(c *gc.C) {
+	cmd := s.makeCommand(c, true, false)
+	_, err := s.runCommand(c, cmd, "kubernetes", "myk8s", "--context", "otherkey")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.clusterSpec.Endpoint, gc.Equals, "otherendpoint")
+	c.Assert(s.clusterSpec.CACertificates, jc.DeepEquals, []string{"othercadata"})
+}
+
+func (s *addCAASSuite) TestUnknownContext(c *gc.C) {
+	cmd := s.makeCommand(c, true, false)
+	_, err := s.runCommand(c, cmd, "kubernetes", "myk8s", "--context", "nokey")
+	c.Assert(err, gc.ErrorMatches, `context "nokey" in kubernetes config not found`)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *addCAASSuite) TestAddK8S(c *gc.C) {
+	cmd := s.makeK8SCommand(c)
+	_, err := s.runCommand(c, cmd, "myk8s")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.clusterSpec.Type, gc.Equals, "kubernetes")
+	c.Assert(s.clusterSpec.Name, gc.Equals, "myk8s")
+	c.Assert(s.clusterSpec.Endpoint, gc.Equals, "fakeendpoint")
+	s.store.CheckCallNames(c, "PublicCloudMetadata", "PersonalCloudMetadata", "WritePersonalCloudMetadata")
+}
+
+func (s *addCAASSuite) TestAddK8SContext(c *gc.C) {
+	cmd := s.makeK8SCommand(c)
+	_, err := s.runCommand(c, cmd, "myk8s", "--context", "otherkey")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.clusterSpec.Endpoint, gc.Equals, "otherendpoint")
+}
+
+func (s *addCAASSuite) TestAddK8SMissingName(c *gc.C) {
+	cmd := s.makeK8SCommand(c)
+	_, err := s.runCommand(c, cmd)
+	c.Assert(err, gc.ErrorMatches, `missing k8s name.`)
+}
+
+func (s *addCAASSuite) TestAddK8SExtraArg(c *gc.C) {
+	cmd := s.makeK8SCommand(c)
+	_, err := s.runCommand(c, cmd, "myk8s", "extra")
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["extra"\]`)
+}
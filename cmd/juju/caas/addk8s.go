@@ -0,0 +1,77 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package caas
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/api"
+	"github.com/juju/juju/api/base"
+	jujucaas "github.com/juju/juju/caas"
+	"github.com/juju/juju/caas/kubernetes/clientconfig"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/jujuclient"
+)
+
+// k8sType is the CAAS type of Kubernetes clusters.
+const k8sType = "kubernetes"
+
+var usageAddK8SSummary = `
+Adds a Kubernetes cluster to Juju as a cloud, along with its credential.`[1:]
+
+var usageAddK8SDetails = `
+The cluster and credential are read from the Kubernetes client
+configuration at $KUBECONFIG or ~/.kube/config, using its current context
+unless --context is given. When there is no such configuration and the
+command runs inside a Kubernetes pod, the cluster's service account is
+used instead.
+
+The cluster is checked to be one on which Juju can deploy applications
+before it is added, both to the local client and to the current
+controller. Models can then be added to the new cloud.
+
+Examples:
+    juju add-k8s myk8s
+    juju add-k8s myk8s --context staging
+
+See also:
+    add-caas
+    add-model`
+
+// AddK8SCommand is the command that adds a Kubernetes cluster
+// and the credential to access it.
+type AddK8SCommand struct {
+	*AddCAASCommand
+}
+
+// NewAddK8SCommand returns a command to add a Kubernetes cluster.
+func NewAddK8SCommand(cloudMetadataStore CloudMetadataStore) cmd.Command {
+	return modelcmd.Wrap(&AddK8SCommand{newAddCAASCommand(cloudMetadataStore)})
+}
+
+func NewAddK8SCommandForTest(cloudMetadataStore CloudMetadataStore, fileCredentialStore jujuclient.CredentialStore, clientStore jujuclient.ClientStore, apiRoot api.Connection, newCloudAPIFunc func(base.APICallCloser) CloudAPI, newClientConfigReaderFunc func(string) (clientconfig.ClientConfigFunc, error), clusterMetadataFunc func(environs.CloudSpec) (*jujucaas.ClusterMetadata, error)) cmd.Command {
+	return modelcmd.Wrap(&AddK8SCommand{newAddCAASCommandForTest(cloudMetadataStore, fileCredentialStore, clientStore, apiRoot, newCloudAPIFunc, newClientConfigReaderFunc, clusterMetadataFunc)})
+}
+
+// Info returns help information about the command.
+func (c *AddK8SCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "add-k8s",
+		Args:    "<k8s name>",
+		Purpose: usageAddK8SSummary,
+		Doc:     usageAddK8SDetails,
+	}
+}
+
+// Init populates the command with the args from the command line.
+func (c *AddK8SCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.Errorf("missing k8s name.")
+	}
+	c.caasType = k8sType
+	c.caasName = args[0]
+	return cmd.CheckEmpty(args[1:])
+}
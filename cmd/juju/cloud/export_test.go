@@ -20,6 +20,13 @@ func NewUpdateCloudsCommandForTest(publicCloudURL string) *updateCloudsCommand {
 	}
 }
 
+func NewUpdateCloudsCommandWithAPIForTest(publicCloudURL string, testStore jujuclient.ClientStore, api updateCloudsAPI) cmd.Command {
+	c := NewUpdateCloudsCommandForTest(publicCloudURL)
+	c.api = api
+	c.SetClientStore(testStore)
+	return modelcmd.WrapController(c, modelcmd.WrapControllerSkipControllerFlags, modelcmd.WrapControllerSkipDefaultController)
+}
+
 func NewListCredentialsCommandForTest(
 	testStore jujuclient.CredentialGetter,
 	personalCloudsFunc func() (map[string]jujucloud.Cloud, error),
@@ -81,3 +88,11 @@ func NewUpdateCredentialCommandForTest(testStore jujuclient.ClientStore, api cre
 	c.SetClientStore(testStore)
 	return modelcmd.WrapController(c)
 }
+
+func NewRotateCredentialCommandForTest(testStore jujuclient.ClientStore, api rotateCredentialAPI) cmd.Command {
+	c := &rotateCredentialCommand{
+		api: api,
+	}
+	c.SetClientStore(testStore)
+	return modelcmd.WrapController(c)
+}
@@ -0,0 +1,147 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package cloud
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"gopkg.in/juju/names.v2"
+
+	apicloud "github.com/juju/juju/api/cloud"
+	jujucloud "github.com/juju/juju/cloud"
+	"github.com/juju/juju/cmd/juju/common"
+	"github.com/juju/juju/cmd/modelcmd"
+)
+
+var usageRotateCredentialSummary = `
+Replaces a controller credential for a cloud with a new one.`[1:]
+
+var usageRotateCredentialDetails = `
+Cloud credentials often need to be rotated, for example when access
+keys expire or are compromised. This command uploads the new, locally
+stored credential to the controller and has the controller check that
+it can be used to access the cloud of every model using the old
+credential. Only once it is valid for all of them are those models
+switched over to the new credential, all at once, and the old
+credential removed from the controller.
+
+If the new credential cannot be used by any one of the models, nothing
+is switched and the old credential is kept. The new credential remains
+uploaded to the controller, so it can be corrected with
+` + "`update-credential`" + ` before trying again.
+
+Examples:
+    juju rotate-credential aws old-keys new-keys
+
+See also:
+    add-credential
+    update-credential
+    credentials`[1:]
+
+type rotateCredentialCommand struct {
+	modelcmd.ControllerCommandBase
+
+	api rotateCredentialAPI
+
+	cloud         string
+	oldCredential string
+	newCredential string
+}
+
+// NewRotateCredentialCommand returns a command to replace a controller
+// credential with a new one.
+func NewRotateCredentialCommand() cmd.Command {
+	return modelcmd.WrapController(&rotateCredentialCommand{})
+}
+
+// Init implements Command.Init.
+func (c *rotateCredentialCommand) Init(args []string) error {
+	if len(args) < 3 {
+		return errors.New("Usage: juju rotate-credential <cloud-name> <old-credential-name> <new-credential-name>")
+	}
+	c.cloud = args[0]
+	c.oldCredential = args[1]
+	c.newCredential = args[2]
+	if c.oldCredential == c.newCredential {
+		return errors.Errorf("old and new credentials are both %q", c.oldCredential)
+	}
+	return cmd.CheckEmpty(args[3:])
+}
+
+// Info implements Command.Info
+func (c *rotateCredentialCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "rotate-credential",
+		Args:    "<cloud-name> <old-credential-name> <new-credential-name>",
+		Purpose: usageRotateCredentialSummary,
+		Doc:     usageRotateCredentialDetails,
+	}
+}
+
+type rotateCredentialAPI interface {
+	UpdateCredential(tag names.CloudCredentialTag, credential jujucloud.Credential) error
+	RotateCredential(oldTag, newTag names.CloudCredentialTag) error
+	Close() error
+}
+
+func (c *rotateCredentialCommand) getAPI() (rotateCredentialAPI, error) {
+	if c.api != nil {
+		return c.api, nil
+	}
+	api, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, errors.Annotate(err, "opening API connection")
+	}
+	return apicloud.NewClient(api), nil
+}
+
+// Run implements Command.Run
+func (c *rotateCredentialCommand) Run(ctx *cmd.Context) error {
+	cloud, err := common.CloudByName(c.cloud)
+	if errors.IsNotFound(err) {
+		ctx.Infof("Cloud %q not found", c.cloud)
+		return nil
+	} else if err != nil {
+		return err
+	}
+	getCredentialsParams := modelcmd.GetCredentialsParams{
+		Cloud:          *cloud,
+		CredentialName: c.newCredential,
+	}
+	newCredential, _, _, err := modelcmd.GetCredentials(ctx, c.ClientStore(), getCredentialsParams)
+	if errors.IsNotFound(err) {
+		ctx.Infof("No credential called %q exists for cloud %q", c.newCredential, c.cloud)
+		return nil
+	} else if err != nil {
+		return err
+	}
+	accountDetails, err := c.CurrentAccountDetails()
+	if err != nil {
+		return err
+	}
+	user := names.NewUserTag(accountDetails.User)
+	cloudTag := names.NewCloudTag(c.cloud)
+	oldTag, err := common.ResolveCloudCredentialTag(user, cloudTag, c.oldCredential)
+	if err != nil {
+		return err
+	}
+	newTag, err := common.ResolveCloudCredentialTag(user, cloudTag, c.newCredential)
+	if err != nil {
+		return err
+	}
+	client, err := c.getAPI()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	if err := client.UpdateCredential(newTag, *newCredential); err != nil {
+		return errors.Annotatef(err, "uploading credential %q", c.newCredential)
+	}
+	if err := client.RotateCredential(oldTag, newTag); err != nil {
+		return err
+	}
+	ctx.Infof("Rotated credential %q to %q for user %q on cloud %q.", c.oldCredential, c.newCredential, accountDetails.User, c.cloud)
+	return nil
+}
@@ -0,0 +1,120 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package cloud_test
+
+import (
+	"strings"
+
+	"github.com/juju/cmd/cmdtesting"
+	"github.com/juju/errors"
+	jujutesting "github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v2"
+
+	jujucloud "github.com/juju/juju/cloud"
+	"github.com/juju/juju/cmd/juju/cloud"
+	"github.com/juju/juju/jujuclient"
+	"github.com/juju/juju/testing"
+)
+
+type rotateCredentialSuite struct {
+	testing.BaseSuite
+
+	store *jujuclient.MemStore
+	api   *fakeRotateCredentialAPI
+}
+
+var _ = gc.Suite(&rotateCredentialSuite{})
+
+func (s *rotateCredentialSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.store = &jujuclient.MemStore{
+		Controllers: map[string]jujuclient.ControllerDetails{
+			"controller": {},
+		},
+		CurrentControllerName: "controller",
+		Accounts: map[string]jujuclient.AccountDetails{
+			"controller": {
+				User: "admin@local",
+			},
+		},
+		Credentials: map[string]jujucloud.CloudCredential{
+			"aws": {
+				AuthCredentials: map[string]jujucloud.Credential{
+					"new-keys": jujucloud.NewCredential(jujucloud.AccessKeyAuthType, map[string]string{
+						"access-key": "key",
+						"secret-key": "secret",
+					}),
+				},
+			},
+		},
+	}
+	s.api = &fakeRotateCredentialAPI{}
+}
+
+func (s *rotateCredentialSuite) TestBadArgs(c *gc.C) {
+	cmd := cloud.NewRotateCredentialCommandForTest(s.store, nil)
+	_, err := cmdtesting.RunCommand(c, cmd, "aws", "old-keys")
+	c.Assert(err, gc.ErrorMatches, "Usage: juju rotate-credential <cloud-name> <old-credential-name> <new-credential-name>")
+	_, err = cmdtesting.RunCommand(c, cmd, "aws", "keys", "keys")
+	c.Assert(err, gc.ErrorMatches, `old and new credentials are both "keys"`)
+	_, err = cmdtesting.RunCommand(c, cmd, "aws", "old-keys", "new-keys", "extra")
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["extra"\]`)
+}
+
+func (s *rotateCredentialSuite) TestMissingNewCredential(c *gc.C) {
+	cmd := cloud.NewRotateCredentialCommandForTest(s.store, s.api)
+	ctx, err := cmdtesting.RunCommand(c, cmd, "aws", "old-keys", "foo")
+	c.Assert(err, jc.ErrorIsNil)
+	output := strings.Replace(cmdtesting.Stderr(ctx), "\n", "", -1)
+	c.Assert(output, gc.Equals, `No credential called "foo" exists for cloud "aws"`)
+	s.api.CheckNoCalls(c)
+}
+
+func (s *rotateCredentialSuite) TestRotate(c *gc.C) {
+	cmd := cloud.NewRotateCredentialCommandForTest(s.store, s.api)
+	ctx, err := cmdtesting.RunCommand(c, cmd, "aws", "old-keys", "new-keys")
+	c.Assert(err, jc.ErrorIsNil)
+	output := strings.Replace(cmdtesting.Stderr(ctx), "\n", "", -1)
+	c.Assert(output, gc.Equals, `Rotated credential "old-keys" to "new-keys" for user "admin@local" on cloud "aws".`)
+
+	oldTag := names.NewCloudCredentialTag("aws/admin@local/old-keys")
+	newTag := names.NewCloudCredentialTag("aws/admin@local/new-keys")
+	s.api.CheckCalls(c, []jujutesting.StubCall{
+		{"UpdateCredential", []interface{}{newTag, jujucloud.NewCredential(jujucloud.AccessKeyAuthType, map[string]string{
+			"access-key": "key",
+			"secret-key": "secret",
+		})}},
+		{"RotateCredential", []interface{}{oldTag, newTag}},
+		{"Close", nil},
+	})
+}
+
+func (s *rotateCredentialSuite) TestRotateFails(c *gc.C) {
+	s.api.SetErrors(nil, errors.New(`validating credential "aws/admin@local/new-keys" for model "deadbeef": unauthorized`))
+	cmd := cloud.NewRotateCredentialCommandForTest(s.store, s.api)
+	_, err := cmdtesting.RunCommand(c, cmd, "aws", "old-keys", "new-keys")
+	c.Assert(err, gc.ErrorMatches, `validating credential "aws/admin@local/new-keys" for model "deadbeef": unauthorized`)
+	s.api.CheckCallNames(c, "UpdateCredential", "RotateCredential", "Close")
+}
+
+type fakeRotateCredentialAPI struct {
+	jujutesting.Stub
+}
+
+func (f *fakeRotateCredentialAPI) UpdateCredential(tag names.CloudCredentialTag, credential jujucloud.Credential) error {
+	f.MethodCall(f, "UpdateCredential", tag, credential)
+	return f.NextErr()
+}
+
+func (f *fakeRotateCredentialAPI) RotateCredential(oldTag, newTag names.CloudCredentialTag) error {
+	f.MethodCall(f, "RotateCredential", oldTag, newTag)
+	return f.NextErr()
+}
+
+func (f *fakeRotateCredentialAPI) Close() error {
+	f.MethodCall(f, "Close")
+	return f.NextErr()
+}
@@ -14,20 +14,27 @@ import (
 
 	"github.com/juju/cmd"
 	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
 	"github.com/juju/utils"
 	"github.com/juju/utils/set"
 	"golang.org/x/crypto/openpgp"
 	"golang.org/x/crypto/openpgp/clearsign"
+	"gopkg.in/juju/names.v2"
 
+	apicloud "github.com/juju/juju/api/cloud"
 	jujucloud "github.com/juju/juju/cloud"
+	"github.com/juju/juju/cmd/modelcmd"
 	"github.com/juju/juju/juju/keys"
 )
 
 type updateCloudsCommand struct {
-	cmd.CommandBase
+	modelcmd.ControllerCommandBase
+
+	api updateCloudsAPI
 
 	publicSigningKey string
 	publicCloudURL   string
+	controllerName   string
 }
 
 var updateCloudsDoc = `
@@ -35,9 +42,16 @@ If any new information for public clouds (such as regions and connection
 endpoints) are available this command will update Juju accordingly. It is
 suggested to run this command periodically.
 
+The cloud information is only accepted if it is signed by Juju's
+public key. When a controller is specified, the definitions of the
+public clouds known to that controller are updated as well, so that
+new regions can be used by its models without upgrading Juju.
+Regions in which models are deployed cannot be removed.
+
 Examples:
 
     juju update-clouds
+    juju update-clouds --controller mycontroller
 
 See also:
     clouds
@@ -49,10 +63,10 @@ var NewUpdateCloudsCommand = func() cmd.Command {
 }
 
 func newUpdateCloudsCommand() cmd.Command {
-	return &updateCloudsCommand{
+	return modelcmd.WrapController(&updateCloudsCommand{
 		publicSigningKey: keys.JujuPublicKey,
 		publicCloudURL:   "https://streams.canonical.com/juju/public-clouds.syaml",
-	}
+	}, modelcmd.WrapControllerSkipControllerFlags, modelcmd.WrapControllerSkipDefaultController)
 }
 
 func (c *updateCloudsCommand) Info() *cmd.Info {
@@ -63,6 +77,40 @@ func (c *updateCloudsCommand) Info() *cmd.Info {
 	}
 }
 
+// SetFlags implements Command.SetFlags.
+func (c *updateCloudsCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.ControllerCommandBase.SetFlags(f)
+	f.StringVar(&c.controllerName, "c", "", "Controller on which to also update the clouds")
+	f.StringVar(&c.controllerName, "controller", "", "")
+}
+
+// Init implements Command.Init.
+func (c *updateCloudsCommand) Init(args []string) error {
+	if c.controllerName != "" {
+		if err := c.SetControllerName(c.controllerName, false); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return cmd.CheckEmpty(args)
+}
+
+type updateCloudsAPI interface {
+	Clouds() (map[names.CloudTag]jujucloud.Cloud, error)
+	UpdateCloud(jujucloud.Cloud) error
+	Close() error
+}
+
+func (c *updateCloudsCommand) getAPI() (updateCloudsAPI, error) {
+	if c.api != nil {
+		return c.api, nil
+	}
+	api, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, errors.Annotate(err, "opening API connection")
+	}
+	return apicloud.NewClient(api), nil
+}
+
 func (c *updateCloudsCommand) Run(ctxt *cmd.Context) error {
 	fmt.Fprint(ctxt.Stderr, "Fetching latest public cloud list...\n")
 	client := utils.GetHTTPClient(utils.VerifySSLHostnames)
@@ -102,16 +150,80 @@ func (c *updateCloudsCommand) Run(ctxt *cmd.Context) error {
 	}
 	if sameCloudInfo {
 		fmt.Fprintln(ctxt.Stderr, "Your list of public clouds is up to date, see `juju clouds`.")
+	} else {
+		if err := jujucloud.WritePublicCloudMetadata(newPublicClouds); err != nil {
+			return errors.Annotate(err, "error writing new local public cloud data")
+		}
+		updateDetails := diffClouds(newPublicClouds, currentPublicClouds)
+		fmt.Fprintln(ctxt.Stderr, fmt.Sprintf("Updated your list of public clouds with %s", updateDetails))
+	}
+	if c.controllerName == "" {
 		return nil
 	}
-	if err := jujucloud.WritePublicCloudMetadata(newPublicClouds); err != nil {
-		return errors.Annotate(err, "error writing new local public cloud data")
+	return errors.Trace(c.updateController(ctxt, newPublicClouds))
+}
+
+// updateController updates the definitions of the controller's clouds
+// that differ from the given public clouds.
+func (c *updateCloudsCommand) updateController(ctxt *cmd.Context, publicClouds map[string]jujucloud.Cloud) error {
+	client, err := c.getAPI()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer client.Close()
+
+	controllerClouds, err := client.Clouds()
+	if err != nil {
+		return errors.Annotate(err, "cannot get clouds from controller")
+	}
+	var updated []string
+	for tag, controllerCloud := range controllerClouds {
+		cloudName := tag.Id()
+		publicCloud, ok := publicClouds[cloudName]
+		if !ok {
+			continue
+		}
+		publicCloud = storedCloudDetails(cloudName, publicCloud)
+		if !cloudChanged(cloudName, publicCloud, storedCloudDetails(cloudName, controllerCloud)) {
+			continue
+		}
+		if err := client.UpdateCloud(publicCloud); err != nil {
+			return errors.Annotatef(err, "cannot update cloud %q on controller %q", cloudName, c.controllerName)
+		}
+		updated = append(updated, cloudName)
+	}
+	if len(updated) == 0 {
+		fmt.Fprintf(ctxt.Stderr, "Public clouds on controller %q are up to date.\n", c.controllerName)
+		return nil
+	}
+	sort.Strings(updated)
+	fmt.Fprintf(ctxt.Stderr, "Updated %s on controller %q.\n", adjustPlurality("cloud", len(updated)), c.controllerName)
+	for _, cloudName := range updated {
+		fmt.Fprintf(ctxt.Stderr, "    - %s\n", cloudName)
 	}
-	updateDetails := diffClouds(newPublicClouds, currentPublicClouds)
-	fmt.Fprintln(ctxt.Stderr, fmt.Sprintf("Updated your list of public clouds with %s", updateDetails))
 	return nil
 }
 
+// storedCloudDetails returns the parts of a cloud definition that
+// a controller records, with the regions sorted by name.
+func storedCloudDetails(cloudName string, in jujucloud.Cloud) jujucloud.Cloud {
+	regions := make([]jujucloud.Region, len(in.Regions))
+	copy(regions, in.Regions)
+	sort.Slice(regions, func(i, j int) bool {
+		return regions[i].Name < regions[j].Name
+	})
+	return jujucloud.Cloud{
+		Name:             cloudName,
+		Type:             in.Type,
+		AuthTypes:        in.AuthTypes,
+		Endpoint:         in.Endpoint,
+		IdentityEndpoint: in.IdentityEndpoint,
+		StorageEndpoint:  in.StorageEndpoint,
+		Regions:          regions,
+		CACertificates:   in.CACertificates,
+	}
+}
+
 func decodeCheckSignature(r io.Reader, publicKey string) ([]byte, error) {
 	data, err := ioutil.ReadAll(r)
 	if err != nil {
@@ -11,14 +11,18 @@ import (
 	"strings"
 
 	"github.com/juju/cmd/cmdtesting"
+	"github.com/juju/errors"
+	jujutesting "github.com/juju/testing"
 	jc "github.com/juju/testing/checkers"
 	"golang.org/x/crypto/openpgp"
 	"golang.org/x/crypto/openpgp/clearsign"
 	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v2"
 
 	jujucloud "github.com/juju/juju/cloud"
 	"github.com/juju/juju/cmd/juju/cloud"
 	sstesting "github.com/juju/juju/environs/simplestreams/testing"
+	"github.com/juju/juju/jujuclient"
 	"github.com/juju/juju/testing"
 )
 
@@ -186,3 +190,108 @@ Updated your list of public clouds with 1 cloud region added:
         - aws/anotherregion
 `[1:])
 }
+
+type fakeUpdateCloudsAPI struct {
+	jujutesting.Stub
+	clouds map[names.CloudTag]jujucloud.Cloud
+}
+
+func (api *fakeUpdateCloudsAPI) Clouds() (map[names.CloudTag]jujucloud.Cloud, error) {
+	api.MethodCall(api, "Clouds")
+	return api.clouds, api.NextErr()
+}
+
+func (api *fakeUpdateCloudsAPI) UpdateCloud(cloud jujucloud.Cloud) error {
+	api.MethodCall(api, "UpdateCloud", cloud)
+	return api.NextErr()
+}
+
+func (api *fakeUpdateCloudsAPI) Close() error {
+	api.MethodCall(api, "Close")
+	return api.NextErr()
+}
+
+func (s *updateCloudsSuite) runWithController(c *gc.C, url string, api *fakeUpdateCloudsAPI) (string, error) {
+	store := &jujuclient.MemStore{
+		Controllers: map[string]jujuclient.ControllerDetails{
+			"controller": {},
+		},
+	}
+	updateCmd := cloud.NewUpdateCloudsCommandWithAPIForTest(url, store, api)
+	ctx, err := cmdtesting.RunCommand(c, updateCmd, "--controller", "controller")
+	return cmdtesting.Stderr(ctx), err
+}
+
+func (s *updateCloudsSuite) TestUpdateController(c *gc.C) {
+	clouds, err := jujucloud.ParseCloudMetadata([]byte(sampleUpdateCloudData))
+	c.Assert(err, jc.ErrorIsNil)
+	err = jujucloud.WritePublicCloudMetadata(clouds)
+	c.Assert(err, jc.ErrorIsNil)
+
+	newUpdateCloudData := sampleUpdateCloudData + `
+      anotherregion:
+        endpoint: http://anotherregion/1.0
+`[1:]
+	ts := s.setupTestServer(c, newUpdateCloudData)
+	defer ts.Close()
+
+	api := &fakeUpdateCloudsAPI{clouds: map[names.CloudTag]jujucloud.Cloud{
+		names.NewCloudTag("aws"):     clouds["aws"],
+		names.NewCloudTag("private"): {Name: "private", Type: "openstack"},
+	}}
+	msg, err := s.runWithController(c, ts.URL, api)
+	c.Assert(err, jc.ErrorIsNil)
+	api.CheckCallNames(c, "Clouds", "UpdateCloud", "Close")
+	api.CheckCall(c, 1, "UpdateCloud", jujucloud.Cloud{
+		Name:      "aws",
+		Type:      "ec2",
+		AuthTypes: jujucloud.AuthTypes{jujucloud.AccessKeyAuthType},
+		Endpoint:  "http://region",
+		Regions: []jujucloud.Region{
+			{Name: "anotherregion", Endpoint: "http://anotherregion/1.0"},
+			{Name: "region", Endpoint: "http://region/1.0"},
+		},
+	})
+	c.Assert(msg, jc.Contains, `
+Updated 1 cloud on controller "controller".
+    - aws
+`[1:])
+}
+
+func (s *updateCloudsSuite) TestUpdateControllerUpToDate(c *gc.C) {
+	clouds, err := jujucloud.ParseCloudMetadata([]byte(sampleUpdateCloudData))
+	c.Assert(err, jc.ErrorIsNil)
+	err = jujucloud.WritePublicCloudMetadata(clouds)
+	c.Assert(err, jc.ErrorIsNil)
+
+	ts := s.setupTestServer(c, sampleUpdateCloudData)
+	defer ts.Close()
+
+	api := &fakeUpdateCloudsAPI{clouds: map[names.CloudTag]jujucloud.Cloud{
+		names.NewCloudTag("aws"): clouds["aws"],
+	}}
+	msg, err := s.runWithController(c, ts.URL, api)
+	c.Assert(err, jc.ErrorIsNil)
+	api.CheckCallNames(c, "Clouds", "Close")
+	c.Assert(strings.Replace(msg, "\n", "", -1), gc.Equals,
+		"Fetching latest public cloud list..."+
+			"Your list of public clouds is up to date, see `juju clouds`."+
+			`Public clouds on controller "controller" are up to date.`)
+}
+
+func (s *updateCloudsSuite) TestUpdateControllerError(c *gc.C) {
+	ts := s.setupTestServer(c, sampleUpdateCloudData+`
+      anotherregion:
+        endpoint: http://anotherregion/1.0
+`[1:])
+	defer ts.Close()
+
+	clouds, err := jujucloud.ParseCloudMetadata([]byte(sampleUpdateCloudData))
+	c.Assert(err, jc.ErrorIsNil)
+	api := &fakeUpdateCloudsAPI{clouds: map[names.CloudTag]jujucloud.Cloud{
+		names.NewCloudTag("aws"): clouds["aws"],
+	}}
+	api.SetErrors(nil, errors.New("permission denied"))
+	_, err = s.runWithController(c, ts.URL, api)
+	c.Assert(err, gc.ErrorMatches, `cannot update cloud "aws" on controller "controller": permission denied`)
+}
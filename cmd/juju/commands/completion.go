@@ -0,0 +1,451 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+	"github.com/juju/utils"
+	"github.com/juju/utils/clock"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/modelcmd"
+)
+
+var usageCompletionSummary = `
+Prints a shell completion script for juju.`[1:]
+
+var usageCompletionDetails = `
+The script is generated from the commands and flags of this juju client,
+and completes command names, flags, and the names of controllers, models,
+applications, units and machines. It can be loaded into the current
+shell, or saved to be loaded by new shells.
+
+Controller and model names are completed from the client's local store.
+Application, unit and machine names are completed from the status of the
+model, which is fetched from the controller and cached for two minutes so
+that completion stays quick.
+
+The --list option prints the names of the given kinds, one per line, and
+is used by the generated scripts to complete names. The kinds are
+"controllers", "models", "applications", "units" and "machines", and
+several may be given separated by commas.
+
+Examples:
+    source <(juju completion bash)
+    source <(juju completion zsh)
+    juju completion fish | source
+    juju completion --list units,machines -m mymodel`[1:]
+
+// completionCacheTTL is how long the names listed from
+// a model's status are cached before being fetched again.
+const completionCacheTTL = 2 * time.Minute
+
+// builtinCommandNames holds the names of the commands
+// that the juju super-command provides itself.
+var builtinCommandNames = []string{"help", "version"}
+
+// completionKinds holds the kinds of names that can be listed.
+var completionKinds = []string{"controllers", "models", "applications", "units", "machines"}
+
+// flagCompletionKinds maps the long names of flags
+// to the kinds of names their values are.
+var flagCompletionKinds = map[string]string{
+	"controller":  "controllers",
+	"model":       "models",
+	"application": "applications",
+	"unit":        "units",
+	"machine":     "machines",
+}
+
+// newCompletionCommand returns a command that prints shell completion
+// scripts for the commands registered with registerCommands.
+func newCompletionCommand() cmd.Command {
+	c := &completionCommand{
+		clock:    clock.WallClock,
+		cacheDir: filepath.Join(utils.Home(), ".cache", "juju", "completion"),
+	}
+	c.commands = func(ctx *cmd.Context) []cmd.Command {
+		var r commandCollector
+		registerCommands(&r, ctx)
+		return r.commands
+	}
+	c.newAPIFunc = func() (CompletionAPI, error) {
+		return c.NewAPIClient()
+	}
+	return modelcmd.Wrap(c)
+}
+
+// CompletionAPI defines the API methods that the completion command uses.
+type CompletionAPI interface {
+	Close() error
+	Status(patterns []string) (*params.FullStatus, error)
+}
+
+// completionCommand prints shell completion scripts,
+// and lists names for them to complete.
+type completionCommand struct {
+	modelcmd.ModelCommandBase
+
+	commands   func(*cmd.Context) []cmd.Command
+	newAPIFunc func() (CompletionAPI, error)
+	clock      clock.Clock
+	cacheDir   string
+
+	shell string
+	kinds []string
+}
+
+func (c *completionCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "completion",
+		Args:    "bash|zsh|fish",
+		Purpose: usageCompletionSummary,
+		Doc:     usageCompletionDetails,
+	}
+}
+
+func (c *completionCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.ModelCommandBase.SetFlags(f)
+	f.Var(cmd.NewStringsValue(nil, &c.kinds), "list", "List names of the given kinds")
+}
+
+func (c *completionCommand) Init(args []string) error {
+	for _, kind := range c.kinds {
+		if !stringInSlice(kind, completionKinds) {
+			return errors.NotValidf("kind %q", kind)
+		}
+	}
+	if len(c.kinds) > 0 {
+		return cmd.CheckEmpty(args)
+	}
+	if len(args) == 0 {
+		return errors.New("no shell specified")
+	}
+	c.shell = args[0]
+	if _, ok := completionTemplates[c.shell]; !ok {
+		return errors.NotSupportedf("shell %q", c.shell)
+	}
+	return cmd.CheckEmpty(args[1:])
+}
+
+func (c *completionCommand) Run(ctx *cmd.Context) error {
+	if len(c.kinds) > 0 {
+		return c.listNames(ctx)
+	}
+	specs := completionSpecs(c.commands(ctx))
+	return completionTemplates[c.shell].Execute(ctx.Stdout, specs)
+}
+
+// commandCollector is a commandRegistry that records
+// the commands registered with it.
+type commandCollector struct {
+	commands []cmd.Command
+}
+
+func (r *commandCollector) Register(c cmd.Command) {
+	r.commands = append(r.commands, c)
+}
+
+func (r *commandCollector) RegisterSuperAlias(name, super, forName string, check cmd.DeprecationCheck) {
+}
+
+func (r *commandCollector) RegisterDeprecated(c cmd.Command, check cmd.DeprecationCheck) {
+	if check != nil && check.Obsolete() {
+		return
+	}
+	r.commands = append(r.commands, c)
+}
+
+// completionSpec describes how to complete a command's arguments.
+type completionSpec struct {
+	Name    string
+	Purpose string
+	// Flags holds the command's flags, including the leading
+	// dashes, with those taking values marked as such.
+	Flags []completionFlag
+	// Args holds the kinds of names that the command's
+	// positional arguments are, separated by commas.
+	Args string
+}
+
+// completionFlag describes a command's flag.
+type completionFlag struct {
+	Name string
+	// Long reports whether the flag is given with two dashes.
+	Long bool
+	// TakesValue reports whether the flag takes a value.
+	TakesValue bool
+	// Kind holds the kind of names that the flag's value is, if any.
+	Kind string
+}
+
+// Option returns the flag as it is given on the command line.
+func (f completionFlag) Option() string {
+	if f.Long {
+		return "--" + f.Name
+	}
+	return "-" + f.Name
+}
+
+type boolFlag interface {
+	IsBoolFlag() bool
+}
+
+// completionSpecs returns the completion specs for the given
+// commands and their aliases, sorted by name.
+func completionSpecs(commands []cmd.Command) []completionSpec {
+	var specs []completionSpec
+	for _, name := range builtinCommandNames {
+		specs = append(specs, completionSpec{Name: name})
+	}
+	for _, c := range commands {
+		info := c.Info()
+		spec := completionSpec{
+			Name:    info.Name,
+			Purpose: strings.SplitN(strings.TrimSpace(info.Purpose), "\n", 2)[0],
+			Flags:   commandFlags(c),
+			Args:    argsKinds(info.Name, info.Args),
+		}
+		specs = append(specs, spec)
+		for _, alias := range info.Aliases {
+			spec.Name = alias
+			specs = append(specs, spec)
+		}
+	}
+	sort.Slice(specs, func(i, j int) bool {
+		return specs[i].Name < specs[j].Name
+	})
+	return specs
+}
+
+// commandFlags returns the flags defined by the command. Short flags
+// sharing a value with a long flag complete the same kind of names.
+func commandFlags(c cmd.Command) []completionFlag {
+	f := gnuflag.NewFlagSet(c.Info().Name, gnuflag.ContinueOnError)
+	c.SetFlags(f)
+	kinds := make(map[interface{}]string)
+	f.VisitAll(func(flag *gnuflag.Flag) {
+		kind, ok := flagCompletionKinds[flag.Name]
+		if ok && reflect.TypeOf(flag.Value).Comparable() {
+			kinds[flag.Value] = kind
+		}
+	})
+	var flags []completionFlag
+	f.VisitAll(func(flag *gnuflag.Flag) {
+		cf := completionFlag{
+			Name:       flag.Name,
+			Long:       len(flag.Name) > 1,
+			TakesValue: true,
+		}
+		if b, ok := flag.Value.(boolFlag); ok && b.IsBoolFlag() {
+			cf.TakesValue = false
+		}
+		if reflect.TypeOf(flag.Value).Comparable() {
+			cf.Kind = kinds[flag.Value]
+		}
+		flags = append(flags, cf)
+	})
+	return flags
+}
+
+// argsKinds returns the kinds of names that a command's positional
+// arguments are, judging by the command's name and args summary.
+func argsKinds(name, args string) string {
+	switch {
+	case name == "ssh" || name == "scp" || name == "debug-hooks":
+		return "units,machines"
+	case strings.Contains(args, "<unit") && strings.Contains(args, "<machine"):
+		return "units,machines"
+	case strings.Contains(args, "<unit"):
+		return "units"
+	case strings.Contains(args, "<application") && strings.Contains(args, "<machine"):
+		return "applications,machines"
+	case strings.Contains(args, "<application"):
+		return "applications"
+	case strings.Contains(args, "<machine"):
+		return "machines"
+	case strings.Contains(args, "<model"):
+		return "models"
+	case strings.Contains(args, "<controller"):
+		return "controllers"
+	}
+	return ""
+}
+
+// completionNames holds the names in a model that can be completed.
+type completionNames struct {
+	Applications []string `json:"applications"`
+	Units        []string `json:"units"`
+	Machines     []string `json:"machines"`
+}
+
+func (c *completionCommand) listNames(ctx *cmd.Context) error {
+	var names []string
+	var modelNames *completionNames
+	for _, kind := range c.kinds {
+		switch kind {
+		case "controllers":
+			controllers, err := c.ClientStore().AllControllers()
+			if err != nil {
+				return errors.Trace(err)
+			}
+			for name := range controllers {
+				names = append(names, name)
+			}
+		case "models":
+			models, err := c.modelNames()
+			if err != nil {
+				return errors.Trace(err)
+			}
+			names = append(names, models...)
+		default:
+			if modelNames == nil {
+				var err error
+				if modelNames, err = c.cachedModelNames(); err != nil {
+					return errors.Trace(err)
+				}
+			}
+			switch kind {
+			case "applications":
+				names = append(names, modelNames.Applications...)
+			case "units":
+				names = append(names, modelNames.Units...)
+			case "machines":
+				names = append(names, modelNames.Machines...)
+			}
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintln(ctx.Stdout, name)
+	}
+	return nil
+}
+
+// modelNames returns the names of the models known to the client: those
+// of the current controller by themselves, and those of every controller
+// qualified by the controller's name.
+func (c *completionCommand) modelNames() ([]string, error) {
+	store := c.ClientStore()
+	currentController, err := store.CurrentController()
+	if err != nil && !errors.IsNotFound(err) {
+		return nil, errors.Trace(err)
+	}
+	controllers, err := store.AllControllers()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var names []string
+	for controllerName := range controllers {
+		models, err := store.AllModels(controllerName)
+		if errors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		for modelName := range models {
+			names = append(names, controllerName+":"+modelName)
+			if controllerName == currentController {
+				names = append(names, modelName)
+			}
+		}
+	}
+	return names, nil
+}
+
+// cachedModelNames returns the names of the model's applications, units
+// and machines, from the cache if it is fresh enough and otherwise from
+// the model's status, which is then cached.
+func (c *completionCommand) cachedModelNames() (*completionNames, error) {
+	_, details, err := c.ModelDetails()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	cacheFile := filepath.Join(c.cacheDir, details.ModelUUID+".json")
+	if info, err := os.Stat(cacheFile); err == nil && c.clock.Now().Sub(info.ModTime()) < completionCacheTTL {
+		data, err := ioutil.ReadFile(cacheFile)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		var names completionNames
+		if err := json.Unmarshal(data, &names); err == nil {
+			return &names, nil
+		}
+		// A corrupt cache is replaced below.
+	}
+
+	client, err := c.newAPIFunc()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	defer client.Close()
+	status, err := client.Status(nil)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	names := namesFromStatus(status)
+
+	data, err := json.Marshal(names)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if err := os.MkdirAll(c.cacheDir, 0700); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if err := utils.AtomicWriteFile(cacheFile, data, 0600); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return names, nil
+}
+
+func namesFromStatus(status *params.FullStatus) *completionNames {
+	names := &completionNames{}
+	for appName, app := range status.Applications {
+		names.Applications = append(names.Applications, appName)
+		for unitName, unit := range app.Units {
+			names.Units = append(names.Units, unitName)
+			for subName := range unit.Subordinates {
+				names.Units = append(names.Units, subName)
+			}
+		}
+	}
+	var addMachines func(map[string]params.MachineStatus)
+	addMachines = func(machines map[string]params.MachineStatus) {
+		for id, machine := range machines {
+			names.Machines = append(names.Machines, id)
+			addMachines(machine.Containers)
+		}
+	}
+	addMachines(status.Machines)
+	sort.Strings(names.Applications)
+	sort.Strings(names.Units)
+	sort.Strings(names.Machines)
+	return names
+}
+
+func stringInSlice(s string, slice []string) bool {
+	for _, item := range slice {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// shellQuote quotes s for use as a single word in a shell script.
+func shellQuote(s string) string {
+	return "'" + strings.Replace(s, "'", `'\''`, -1) + "'"
+}
@@ -0,0 +1,245 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package commands
+
+import (
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/cmd/cmdtesting"
+	"github.com/juju/gnuflag"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/jujuclient"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type CompletionSuite struct {
+	testing.IsolationSuite
+	store    *jujuclient.MemStore
+	clock    *testing.Clock
+	api      *mockCompletionAPI
+	cacheDir string
+}
+
+var _ = gc.Suite(&CompletionSuite{})
+
+func (s *CompletionSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.store = jujuclient.NewMemStore()
+	s.store.CurrentControllerName = "ctrl"
+	s.store.Controllers["ctrl"] = jujuclient.ControllerDetails{}
+	s.store.Controllers["other"] = jujuclient.ControllerDetails{}
+	s.store.Accounts["ctrl"] = jujuclient.AccountDetails{User: "admin"}
+	s.store.Models["ctrl"] = &jujuclient.ControllerModels{
+		Models: map[string]jujuclient.ModelDetails{
+			"admin/default": {ModelUUID: coretesting.ModelTag.Id()},
+		},
+		CurrentModel: "admin/default",
+	}
+	s.store.Models["other"] = &jujuclient.ControllerModels{
+		Models: map[string]jujuclient.ModelDetails{
+			"admin/prod": {ModelUUID: "prod-uuid"},
+		},
+	}
+	s.clock = testing.NewClock(time.Now())
+	s.cacheDir = c.MkDir()
+	s.api = &mockCompletionAPI{
+		Stub: &testing.Stub{},
+		status: &params.FullStatus{
+			Machines: map[string]params.MachineStatus{
+				"0": {
+					Containers: map[string]params.MachineStatus{
+						"0/lxd/0": {},
+					},
+				},
+			},
+			Applications: map[string]params.ApplicationStatus{
+				"mysql": {
+					Units: map[string]params.UnitStatus{
+						"mysql/0": {
+							Subordinates: map[string]params.UnitStatus{
+								"logging/0": {},
+							},
+						},
+					},
+				},
+				"logging": {},
+			},
+		},
+	}
+}
+
+func (s *CompletionSuite) run(c *gc.C, args ...string) (string, error) {
+	command := &completionCommand{
+		commands: func(*cmd.Context) []cmd.Command {
+			return []cmd.Command{&fakeCompletionCommand{}}
+		},
+		newAPIFunc: func() (CompletionAPI, error) {
+			return s.api, nil
+		},
+		clock:    s.clock,
+		cacheDir: s.cacheDir,
+	}
+	wrapped := modelcmd.Wrap(command)
+	wrapped.SetClientStore(s.store)
+	ctx, err := cmdtesting.RunCommand(c, wrapped, args...)
+	if err != nil {
+		return "", err
+	}
+	return cmdtesting.Stdout(ctx), nil
+}
+
+func (s *CompletionSuite) TestInitErrors(c *gc.C) {
+	_, err := s.run(c)
+	c.Assert(err, gc.ErrorMatches, "no shell specified")
+
+	_, err = s.run(c, "tcsh")
+	c.Assert(err, gc.ErrorMatches, `shell "tcsh" not supported`)
+
+	_, err = s.run(c, "--list", "units,spaces")
+	c.Assert(err, gc.ErrorMatches, `kind "spaces" not valid`)
+
+	_, err = s.run(c, "bash", "zsh")
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["zsh"\]`)
+}
+
+func (s *CompletionSuite) TestCompletionSpecs(c *gc.C) {
+	specs := completionSpecs([]cmd.Command{&fakeCompletionCommand{}})
+	c.Assert(specs, jc.DeepEquals, []completionSpec{{
+		Name:    "fake",
+		Purpose: "Does nothing.",
+		Flags: []completionFlag{
+			{Name: "a", TakesValue: true, Kind: "applications"},
+			{Name: "application", Long: true, TakesValue: true, Kind: "applications"},
+			{Name: "force", Long: true},
+		},
+		Args: "units",
+	}, {
+		Name:    "fakes",
+		Purpose: "Does nothing.",
+		Flags: []completionFlag{
+			{Name: "a", TakesValue: true, Kind: "applications"},
+			{Name: "application", Long: true, TakesValue: true, Kind: "applications"},
+			{Name: "force", Long: true},
+		},
+		Args: "units",
+	}, {
+		Name: "help",
+	}, {
+		Name: "version",
+	}})
+}
+
+func (s *CompletionSuite) TestBash(c *gc.C) {
+	out, err := s.run(c, "bash")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(out, jc.Contains, "local commands='fake fakes help version'\n")
+	c.Assert(out, jc.Contains, "        fake) flags='-a --application --force'; valueflags='-a=applications --application=applications'; args='units';;\n")
+	c.Assert(out, jc.HasSuffix, "complete -F _juju juju\n")
+}
+
+func (s *CompletionSuite) TestZsh(c *gc.C) {
+	out, err := s.run(c, "zsh")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(out, jc.HasPrefix, "#compdef juju\n")
+	c.Assert(out, jc.Contains, "        'fake:Does nothing.'\n")
+}
+
+func (s *CompletionSuite) TestFish(c *gc.C) {
+	out, err := s.run(c, "fish")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(out, jc.Contains, "complete -c juju -n '__juju_using_command fake' -l application -x -a '(__juju_list applications)'\n")
+	c.Assert(out, jc.Contains, "complete -c juju -n '__juju_using_command fake' -a '(__juju_list units)'\n")
+}
+
+func (s *CompletionSuite) TestListControllersAndModels(c *gc.C) {
+	out, err := s.run(c, "--list", "controllers,models")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(out, gc.Equals, `
+admin/default
+ctrl
+ctrl:admin/default
+other
+other:admin/prod
+`[1:])
+	s.api.CheckNoCalls(c)
+}
+
+func (s *CompletionSuite) TestListModelNames(c *gc.C) {
+	out, err := s.run(c, "--list", "applications,units,machines")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(out, gc.Equals, `
+0
+0/lxd/0
+logging
+logging/0
+mysql
+mysql/0
+`[1:])
+	s.api.CheckCallNames(c, "Status", "Close")
+}
+
+func (s *CompletionSuite) TestListCachesStatus(c *gc.C) {
+	out, err := s.run(c, "--list", "units")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(out, gc.Equals, "logging/0\nmysql/0\n")
+	s.api.CheckCallNames(c, "Status", "Close")
+
+	s.api.status = &params.FullStatus{}
+	out, err = s.run(c, "--list", "units")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(out, gc.Equals, "logging/0\nmysql/0\n")
+	s.api.CheckCallNames(c, "Status", "Close")
+
+	s.clock.Advance(completionCacheTTL + time.Second)
+	out, err = s.run(c, "--list", "units")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(out, gc.Equals, "")
+	s.api.CheckCallNames(c, "Status", "Close", "Status", "Close")
+}
+
+type fakeCompletionCommand struct {
+	cmd.CommandBase
+	application string
+	force       bool
+}
+
+func (c *fakeCompletionCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "fake",
+		Args:    "<unit name>",
+		Purpose: "Does nothing.\nReally.",
+		Aliases: []string{"fakes"},
+	}
+}
+
+func (c *fakeCompletionCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.StringVar(&c.application, "a", "", "")
+	f.StringVar(&c.application, "application", "", "")
+	f.BoolVar(&c.force, "force", false, "")
+}
+
+func (c *fakeCompletionCommand) Run(*cmd.Context) error {
+	return nil
+}
+
+type mockCompletionAPI struct {
+	*testing.Stub
+	status *params.FullStatus
+}
+
+func (m *mockCompletionAPI) Close() error {
+	m.MethodCall(m, "Close")
+	return m.NextErr()
+}
+
+func (m *mockCompletionAPI) Status(patterns []string) (*params.FullStatus, error) {
+	m.MethodCall(m, "Status", patterns)
+	return m.status, m.NextErr()
+}
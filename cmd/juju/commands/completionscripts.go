@@ -0,0 +1,199 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package commands
+
+import (
+	"strings"
+	"text/template"
+)
+
+// completionTemplates holds the templates for the completion
+// script of each supported shell, executed with the
+// []completionSpec for all commands.
+var completionTemplates = map[string]*template.Template{
+	"bash": newCompletionTemplate("bash", bashCompletionTemplate),
+	"zsh":  newCompletionTemplate("zsh", zshCompletionTemplate),
+	"fish": newCompletionTemplate("fish", fishCompletionTemplate),
+}
+
+func newCompletionTemplate(name, text string) *template.Template {
+	funcs := template.FuncMap{
+		"quote": shellQuote,
+		"commandNames": func(specs []completionSpec) string {
+			names := make([]string, len(specs))
+			for i, spec := range specs {
+				names[i] = spec.Name
+			}
+			return strings.Join(names, " ")
+		},
+		"options": func(flags []completionFlag) string {
+			options := make([]string, len(flags))
+			for i, flag := range flags {
+				options[i] = flag.Option()
+			}
+			return strings.Join(options, " ")
+		},
+		// valueOptions returns the flags that take values,
+		// each followed by "=" and the kind of names its value
+		// is completed from, if any.
+		"valueOptions": func(flags []completionFlag) string {
+			var options []string
+			for _, flag := range flags {
+				if flag.TakesValue {
+					options = append(options, flag.Option()+"="+flag.Kind)
+				}
+			}
+			return strings.Join(options, " ")
+		},
+		"describe": func(spec completionSpec) string {
+			return spec.Name + ":" + spec.Purpose
+		},
+	}
+	return template.Must(template.New(name).Funcs(funcs).Parse(text[1:]))
+}
+
+const bashCompletionTemplate = `
+# bash completion for juju, generated by "juju completion bash".
+# Load it with: source <(juju completion bash)
+
+_juju_list() {
+    local model= i
+    for ((i = 2; i < cword - 1; i++)); do
+        case "${words[i]}" in
+            -m|--model) model="${words[i+1]}";;
+        esac
+    done
+    juju completion --list "$1" ${model:+-m "${model}"} 2>/dev/null
+}
+
+_juju() {
+    local cur prev words cword
+    _get_comp_words_by_ref -n : cur prev words cword
+    local commands={{quote (commandNames .)}}
+    if [ "${cword}" -eq 1 ] || [ "${words[1]}" = help ]; then
+        COMPREPLY=( $(compgen -W "${commands}" -- "${cur}") )
+        return 0
+    fi
+    local flags= valueflags= args=
+    case "${words[1]}" in
+{{- range .}}
+        {{.Name}}) flags={{quote (options .Flags)}}; valueflags={{quote (valueOptions .Flags)}}; args={{quote .Args}};;
+{{- end}}
+        *) return 0;;
+    esac
+    local kind= found= flag
+    for flag in ${valueflags}; do
+        if [ "${prev}" = "${flag%%=*}" ]; then
+            found=1
+            kind="${flag#*=}"
+            break
+        fi
+    done
+    if [ -z "${found}" ]; then
+        if [[ "${cur}" == -* ]]; then
+            COMPREPLY=( $(compgen -W "${flags}" -- "${cur}") )
+            return 0
+        fi
+        kind="${args}"
+    fi
+    [ -n "${kind}" ] || return 0
+    COMPREPLY=( $(compgen -W "$(_juju_list "${kind}")" -- "${cur}") )
+    __ltrim_colon_completions "${cur}"
+}
+
+complete -F _juju juju
+`
+
+const zshCompletionTemplate = `
+#compdef juju
+# zsh completion for juju, generated by "juju completion zsh".
+# Load it with: source <(juju completion zsh)
+
+_juju_list() {
+    local -a model
+    local i=${words[(I)(-m|--model)]}
+    if (( i > 0 && i < CURRENT - 1 )); then
+        model=(-m ${words[i+1]})
+    fi
+    juju completion --list $1 $model 2>/dev/null
+}
+
+_juju() {
+    local -a commands flags valueflags
+    local args kind found flag
+    commands=(
+{{- range .}}
+        {{quote (describe .)}}
+{{- end}}
+    )
+    if (( CURRENT == 2 )) || [[ ${words[2]} == help ]]; then
+        _describe -t commands 'juju command' commands
+        return
+    fi
+    case ${words[2]} in
+{{- range .}}
+        {{.Name}}) flags=({{options .Flags}}); valueflags=({{valueOptions .Flags}}); args={{quote .Args}};;
+{{- end}}
+        *) return 1;;
+    esac
+    for flag in $valueflags; do
+        if [[ ${words[CURRENT-1]} == ${flag%%=*} ]]; then
+            found=1
+            kind=${flag#*=}
+            break
+        fi
+    done
+    if [[ -z $found ]]; then
+        if [[ ${words[CURRENT]} == -* ]]; then
+            compadd -- $flags
+            return
+        fi
+        kind=$args
+    fi
+    [[ -n $kind ]] || return 1
+    compadd -- ${(f)"$(_juju_list $kind)"}
+}
+
+compdef _juju juju
+`
+
+const fishCompletionTemplate = `
+# fish completion for juju, generated by "juju completion fish".
+# Load it with: juju completion fish | source
+
+function __juju_list
+    set -l tokens (commandline -opc)
+    set -l model
+    for i in (seq 2 (math (count $tokens) - 1))
+        if contains -- $tokens[$i] -m --model
+            set model -m $tokens[(math $i + 1)]
+        end
+    end
+    juju completion --list $argv[1] $model 2>/dev/null
+end
+
+function __juju_needs_command
+    set -l tokens (commandline -opc)
+    test (count $tokens) -eq 1
+end
+
+function __juju_using_command
+    set -l tokens (commandline -opc)
+    test (count $tokens) -gt 1; and test $tokens[2] = $argv[1]
+end
+
+complete -c juju -f
+{{- range .}}
+complete -c juju -n __juju_needs_command -a {{quote .Name}} -d {{quote .Purpose}}
+complete -c juju -n '__juju_using_command help' -a {{quote .Name}} -d {{quote .Purpose}}
+{{- end}}
+{{- range $spec := .}}
+{{- range .Flags}}
+complete -c juju -n {{quote (print "__juju_using_command " $spec.Name)}} {{if .Long}}-l{{else}}-s{{end}} {{.Name}}{{if .Kind}} -x -a '(__juju_list {{.Kind}})'{{else if .TakesValue}} -r{{end}}
+{{- end}}
+{{- if .Args}}
+complete -c juju -n {{quote (print "__juju_using_command " .Name)}} -a '(__juju_list {{.Args}})'
+{{- end}}
+{{- end}}
+`
@@ -0,0 +1,89 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package commands
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/network/ssh"
+)
+
+func newDebugCodeCommand(hostChecker ssh.ReachableChecker) cmd.Command {
+	c := new(debugCodeCommand)
+	c.getActionAPI = c.newActionsAPI
+	c.setHostChecker(hostChecker)
+	return modelcmd.Wrap(c)
+}
+
+// debugCodeCommand is like debugHooksCommand, but runs the matching
+// hooks and actions itself, with JUJU_DEBUG_AT set so that breakpoints
+// in the charm's code are reached.
+type debugCodeCommand struct {
+	debugHooksCommand
+	debugAt string
+}
+
+const debugCodeDoc = `
+Interactively debug the code of a charm's hooks or actions remotely on an
+application unit.
+
+As with debug-hooks, a tmux session is started on the unit, and a window
+is opened in it when a matching hook or action next runs. Rather than
+waiting for the hook to be run by hand, debug-code runs it straight away
+with the environment variable JUJU_DEBUG_AT set to the value of --at.
+Charms which support it use JUJU_DEBUG_AT to decide where to stop at a
+breakpoint, such as a Python debugger prompt, in the live hook
+environment. Charms which do not simply run the hook as usual.
+
+The default value of --at, "all", requests every breakpoint. Which other
+values are understood depends on the charm.
+
+Examples:
+
+Stop at the breakpoints in the next install or config-changed hook of
+mysql/0:
+
+    juju debug-code mysql/0 install config-changed
+
+Stop only at the breakpoint named "db-setup" in any hook or action:
+
+    juju debug-code --at db-setup mysql/0
+
+See the "juju help ssh" for information about SSH related options
+accepted by the debug-code command.
+
+See also:
+    debug-hooks
+`
+
+func (c *debugCodeCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "debug-code",
+		Args:    "<unit name> [hook or action names]",
+		Purpose: "Launch a tmux session to debug hooks and/or actions at breakpoints in the charm's code.",
+		Doc:     debugCodeDoc,
+	}
+}
+
+func (c *debugCodeCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.debugHooksCommand.SetFlags(f)
+	f.StringVar(&c.debugAt, "at", "all", "Value of JUJU_DEBUG_AT, naming the breakpoints to stop at")
+}
+
+func (c *debugCodeCommand) Init(args []string) error {
+	if c.debugAt == "" {
+		return errors.New("--at cannot be empty")
+	}
+	return c.debugHooksCommand.Init(args)
+}
+
+// Run ensures c.Target is a unit, and resolves its address,
+// and connects to it via SSH to execute the debug-hooks
+// script, requesting that matching hooks be run at once.
+func (c *debugCodeCommand) Run(ctx *cmd.Context) error {
+	return c.runDebugSession(ctx, c.debugAt)
+}
@@ -0,0 +1,68 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package commands
+
+import (
+	"encoding/base64"
+	"regexp"
+	"runtime"
+
+	"github.com/juju/cmd/cmdtesting"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+)
+
+var _ = gc.Suite(&DebugCodeSuite{})
+
+type DebugCodeSuite struct {
+	SSHCommonSuite
+}
+
+func (s *DebugCodeSuite) SetUpTest(c *gc.C) {
+	//TODO(bogdanteleaga): Fix once debughooks are supported on windows
+	if runtime.GOOS == "windows" {
+		c.Skip("bug 1403084: Skipping on windows for now")
+	}
+	s.SSHCommonSuite.SetUpTest(c)
+	s.setupModel(c)
+	s.setHostChecker(validAddresses("0.public"))
+}
+
+// clientScript returns the debug-hooks client script
+// passed to ssh by the command.
+func (s *DebugCodeSuite) clientScript(c *gc.C, args ...string) string {
+	ctx, err := cmdtesting.RunCommand(c, newDebugCodeCommand(s.hostChecker), args...)
+	c.Assert(err, jc.ErrorIsNil)
+	match := regexp.MustCompile(`echo (\S+) \| base64 -d > \$F`).FindStringSubmatch(cmdtesting.Stdout(ctx))
+	c.Assert(match, gc.HasLen, 2)
+	script, err := base64.StdEncoding.DecodeString(match[1])
+	c.Assert(err, jc.ErrorIsNil)
+	return string(script)
+}
+
+// hookArgs returns the line of the client script
+// which writes out the given hook args.
+func hookArgs(yaml string) string {
+	return `echo "` + base64.StdEncoding.EncodeToString([]byte(yaml)) + `" | base64 -d`
+}
+
+func (s *DebugCodeSuite) TestDebugCodeAll(c *gc.C) {
+	script := s.clientScript(c, "mysql/0", "install")
+	c.Assert(script, jc.Contains, hookArgs("hooks:\n- install\ndebug-at: all\n"))
+}
+
+func (s *DebugCodeSuite) TestDebugCodeAt(c *gc.C) {
+	script := s.clientScript(c, "--at", "db-setup", "mysql/0")
+	c.Assert(script, jc.Contains, hookArgs("debug-at: db-setup\n"))
+}
+
+func (s *DebugCodeSuite) TestDebugCodeEmptyAt(c *gc.C) {
+	_, err := cmdtesting.RunCommand(c, newDebugCodeCommand(s.hostChecker), "--at", "", "mysql/0")
+	c.Assert(err, gc.ErrorMatches, "--at cannot be empty")
+}
+
+func (s *DebugCodeSuite) TestDebugCodeInvalidHook(c *gc.C) {
+	_, err := cmdtesting.RunCommand(c, newDebugCodeCommand(s.hostChecker), "mysql/0", "no-such-hook")
+	c.Assert(err, gc.ErrorMatches, `unit "mysql/0" contains neither hook nor action "no-such-hook".*`)
+}
@@ -179,6 +179,13 @@ func (c *debugHooksCommand) getValidHooks(appName string) (set.Strings, error) {
 // and connects to it via SSH to execute the debug-hooks
 // script.
 func (c *debugHooksCommand) Run(ctx *cmd.Context) error {
+	return c.runDebugSession(ctx, "")
+}
+
+// runDebugSession starts a debug session for the matching hooks and
+// actions of c.Target. If debugAt is not empty, the hooks and actions
+// are run in the session with JUJU_DEBUG_AT set to it.
+func (c *debugHooksCommand) runDebugSession(ctx *cmd.Context, debugAt string) error {
 	err := c.initRun()
 	if err != nil {
 		return err
@@ -189,8 +196,13 @@ func (c *debugHooksCommand) Run(ctx *cmd.Context) error {
 		return err
 	}
 	debugctx := unitdebug.NewHooksContext(c.Target)
-	script := base64.StdEncoding.EncodeToString([]byte(unitdebug.ClientScript(debugctx, c.hooks)))
+	script := base64.StdEncoding.EncodeToString([]byte(unitdebug.ClientScript(debugctx, c.hooks, debugAt)))
 	innercmd := fmt.Sprintf(`F=$(mktemp); echo %s | base64 -d > $F; . $F`, script)
+	if c.caasExecer != nil {
+		// The hooks of a CAAS model's units are run
+		// in the operator pod of their application.
+		return c.execInUnitPod(ctx, c.Target, true, true, []string{"/bin/bash", "-c", innercmd})
+	}
 	args := []string{fmt.Sprintf("sudo /bin/bash -c '%s'", innercmd)}
 	c.Args = args
 	return c.sshCommand.Run(ctx)
@@ -46,6 +46,7 @@ import (
 	"github.com/juju/juju/cmd/juju/storage"
 	"github.com/juju/juju/cmd/juju/subnet"
 	"github.com/juju/juju/cmd/juju/user"
+	"github.com/juju/juju/cmd/juju/waitfor"
 	"github.com/juju/juju/cmd/modelcmd"
 	"github.com/juju/juju/feature"
 	"github.com/juju/juju/juju"
@@ -298,6 +299,8 @@ func registerCommands(r commandRegistry, ctx *cmd.Context) {
 	r.Register(status.NewStatusCommand())
 	r.Register(newSwitchCommand())
 	r.Register(status.NewStatusHistoryCommand())
+	r.Register(application.NewShowUnitCommand())
+	r.Register(waitfor.NewWaitForCommand())
 
 	// Error resolution and debugging commands.
 	r.Register(newDefaultRunCommand())
@@ -306,6 +309,7 @@ func registerCommands(r commandRegistry, ctx *cmd.Context) {
 	r.Register(newResolvedCommand())
 	r.Register(newDebugLogCommand())
 	r.Register(newDebugHooksCommand(nil))
+	r.Register(newDebugCodeCommand(nil))
 
 	// Configuration commands.
 	r.Register(model.NewModelGetConstraintsCommand())
@@ -315,6 +319,9 @@ func registerCommands(r commandRegistry, ctx *cmd.Context) {
 	r.Register(application.NewUpgradeCharmCommand())
 	r.Register(application.NewUpdateSeriesCommand())
 
+	// Shell completion.
+	r.Register(newCompletionCommand())
+
 	// Charm tool commands.
 	r.Register(newHelpToolCommand())
 	// TODO (anastasiamac 2017-08-1) This needs to be removed in Juju 3.x
@@ -366,6 +373,10 @@ func registerCommands(r commandRegistry, ctx *cmd.Context) {
 	r.Register(model.NewGrantCommand())
 	r.Register(model.NewRevokeCommand())
 	r.Register(model.NewShowCommand())
+	r.Register(model.NewActivityCommand())
+	r.Register(model.NewSuspendCommand())
+	r.Register(model.NewResumeCommand())
+	r.Register(model.NewCloneModelCommand())
 
 	r.Register(newMigrateCommand())
 	if featureflag.Enabled(feature.DeveloperMode) {
@@ -387,10 +398,13 @@ func registerCommands(r commandRegistry, ctx *cmd.Context) {
 	r.Register(application.NewAddUnitCommand())
 	r.Register(application.NewConfigCommand())
 	r.Register(application.NewDeployCommand())
+	r.Register(application.NewDiffBundleCommand())
 	r.Register(application.NewExposeCommand())
 	r.Register(application.NewUnexposeCommand())
+	r.Register(application.NewTrustCommand())
 	r.Register(application.NewServiceGetConstraintsCommand())
 	r.Register(application.NewServiceSetConstraintsCommand())
+	r.Register(application.NewConstraintsCommand())
 
 	// Operation protection commands
 	r.Register(block.NewDisableCommand())
@@ -457,15 +471,18 @@ func registerCommands(r commandRegistry, ctx *cmd.Context) {
 	r.Register(cloud.NewAddCredentialCommand())
 	r.Register(cloud.NewRemoveCredentialCommand())
 	r.Register(cloud.NewUpdateCredentialCommand())
+	r.Register(cloud.NewRotateCredentialCommand())
 
 	// CAAS commands
 	if featureflag.Enabled(feature.CAAS) {
 		r.Register(caas.NewAddCAASCommand(&cloudToCommandAdapter{}))
+		r.Register(caas.NewAddK8SCommand(&cloudToCommandAdapter{}))
 	}
 
 	// Juju GUI commands.
 	r.Register(gui.NewGUICommand())
 	r.Register(gui.NewUpgradeGUICommand())
+	r.Register(gui.NewDashboardCommand())
 
 	// Resource commands
 	r.Register(resource.NewUploadCommand(resource.UploadDeps{
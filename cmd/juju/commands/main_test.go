@@ -412,9 +412,12 @@ var commandNames = []string{
 	"change-user-password",
 	"charm",
 	"charm-resources",
+	"clone-model",
 	"clouds",
 	"collect-metrics",
+	"completion",
 	"config",
+	"constraints",
 	"consume",
 	"controller-config",
 	"controllers",
@@ -422,12 +425,15 @@ var commandNames = []string{
 	"create-storage-pool",
 	"create-wallet",
 	"credentials",
+	"dashboard",
+	"debug-code",
 	"debug-hooks",
 	"debug-log",
 	"deploy",
 	"destroy-controller",
 	"destroy-model",
 	"detach-storage",
+	"diff-bundle",
 	"disable-command",
 	"disable-user",
 	"disabled-commands",
@@ -479,6 +485,7 @@ var commandNames = []string{
 	"machines",
 	"metrics",
 	"migrate",
+	"model-activity",
 	"model-config",
 	"model-default",
 	"model-defaults",
@@ -509,9 +516,11 @@ var commandNames = []string{
 	"resolve",
 	"resources",
 	"restore-backup",
+	"resume-model",
 	"resume-relation",
 	"retry-provisioning",
 	"revoke",
+	"rotate-credential",
 	"run",
 	"run-action",
 	"scp",
@@ -534,6 +543,7 @@ var commandNames = []string{
 	"show-status",
 	"show-status-log",
 	"show-storage",
+	"show-unit",
 	"show-user",
 	"show-wallet",
 	"sla",
@@ -544,10 +554,12 @@ var commandNames = []string{
 	"storage",
 	"storage-pools",
 	"subnets",
+	"suspend-model",
 	"suspend-relation",
 	"switch",
 	"sync-agent-binaries",
 	"sync-tools",
+	"trust",
 	"unexpose",
 	"unregister",
 	"update-clouds",
@@ -559,6 +571,7 @@ var commandNames = []string{
 	"upload-backup",
 	"users",
 	"version",
+	"wait-for",
 	"wallets",
 	"whoami",
 }
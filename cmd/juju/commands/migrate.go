@@ -6,6 +6,7 @@ package commands
 import (
 	"github.com/juju/cmd"
 	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
 	"gopkg.in/macaroon-bakery.v1/httpbakery"
 	"gopkg.in/macaroon.v1"
 
@@ -27,10 +28,16 @@ type migrateCommand struct {
 	newAPIRoot       func(jujuclient.ClientStore, string, string) (api.Connection, error)
 	api              migrateAPI
 	targetController string
+	pause            bool
+	resume           bool
+	abort            bool
 }
 
 type migrateAPI interface {
 	InitiateMigration(spec controller.MigrationSpec) (string, error)
+	PauseMigration(modelUUID string) error
+	ResumeMigration(modelUUID string) error
+	AbortMigration(modelUUID string) error
 }
 
 const migrateDoc = `
@@ -56,6 +63,18 @@ This command only starts a model migration - it does not wait for its
 completion. The progress of a migration can be tracked using the
 "status" command and by consulting the logs.
 
+A migration in progress can be paused before it moves on to its next
+phase, and later resumed:
+
+    juju migrate --pause mymodel
+    juju migrate --resume mymodel
+
+Until the model has been activated in the target controller, a migration
+can also be aborted. The model is then removed from the target
+controller and remains managed by the original controller:
+
+    juju migrate --abort mymodel
+
 See also:
     login
     controllers
@@ -66,26 +85,47 @@ See also:
 func (c *migrateCommand) Info() *cmd.Info {
 	return &cmd.Info{
 		Name:    "migrate",
-		Args:    "<model-name> <target-controller-name>",
+		Args:    "<model-name> [<target-controller-name>]",
 		Purpose: "Migrate a hosted model to another controller.",
 		Doc:     migrateDoc,
 	}
 }
 
+// SetFlags implements cmd.Command.
+func (c *migrateCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.ModelCommandBase.SetFlags(f)
+	f.BoolVar(&c.pause, "pause", false, "Pause the active migration of the model")
+	f.BoolVar(&c.resume, "resume", false, "Resume the paused migration of the model")
+	f.BoolVar(&c.abort, "abort", false, "Abort the active migration of the model")
+}
+
 // Init implements cmd.Command.
 func (c *migrateCommand) Init(args []string) error {
 	if len(args) < 1 {
 		return errors.New("model not specified")
 	}
-	if len(args) < 2 {
-		return errors.New("target controller not specified")
+
+	changes := 0
+	for _, flag := range []bool{c.pause, c.resume, c.abort} {
+		if flag {
+			changes++
+		}
 	}
-	if len(args) > 2 {
+	switch {
+	case changes > 1:
+		return errors.New("only one of --pause, --resume and --abort may be specified")
+	case changes == 1 && len(args) > 1:
+		return errors.New("target controller not expected with --pause, --resume or --abort")
+	case changes == 0 && len(args) < 2:
+		return errors.New("target controller not specified")
+	case len(args) > 2:
 		return errors.New("too many arguments specified")
 	}
 
 	c.SetModelName(args[0], false)
-	c.targetController = args[1]
+	if changes == 0 {
+		c.targetController = args[1]
+	}
 	return nil
 }
 
@@ -123,6 +163,9 @@ func (c *migrateCommand) getMigrationSpec() (*controller.MigrationSpec, error) {
 
 // Run implements cmd.Command.
 func (c *migrateCommand) Run(ctx *cmd.Context) error {
+	if c.pause || c.resume || c.abort {
+		return c.changeMigration(ctx)
+	}
 	spec, err := c.getMigrationSpec()
 	if err != nil {
 		return err
@@ -148,6 +191,41 @@ func (c *migrateCommand) Run(ctx *cmd.Context) error {
 	return nil
 }
 
+// changeMigration pauses, resumes or aborts the active migration of
+// the model.
+func (c *migrateCommand) changeMigration(ctx *cmd.Context) error {
+	modelName, err := c.ModelName()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	uuids, err := c.ModelUUIDs([]string{modelName})
+	if err != nil {
+		return errors.Trace(err)
+	}
+	api, err := c.getAPI()
+	if err != nil {
+		return err
+	}
+	switch {
+	case c.pause:
+		if err := api.PauseMigration(uuids[0]); err != nil {
+			return err
+		}
+		ctx.Infof("Migration of model %q paused", modelName)
+	case c.resume:
+		if err := api.ResumeMigration(uuids[0]); err != nil {
+			return err
+		}
+		ctx.Infof("Migration of model %q resumed", modelName)
+	case c.abort:
+		if err := api.AbortMigration(uuids[0]); err != nil {
+			return err
+		}
+		ctx.Infof("Migration of model %q aborted", modelName)
+	}
+	return nil
+}
+
 func (c *migrateCommand) getAPI() (migrateAPI, error) {
 	if c.api != nil {
 		return c.api, nil
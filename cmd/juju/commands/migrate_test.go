@@ -10,6 +10,7 @@ import (
 
 	"github.com/juju/cmd"
 	"github.com/juju/cmd/cmdtesting"
+	"github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 	"gopkg.in/macaroon-bakery.v1/httpbakery"
@@ -210,6 +211,43 @@ func (s *MigrateSuite) TestControllerDoesntExist(c *gc.C) {
 	c.Check(s.api.specSeen, gc.IsNil) // API shouldn't have been called
 }
 
+func (s *MigrateSuite) TestPause(c *gc.C) {
+	ctx, err := s.makeAndRun(c, "--pause", "model")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(cmdtesting.Stderr(ctx), gc.Equals, "Migration of model \"model\" paused\n")
+	c.Check(s.api.changesSeen, jc.DeepEquals, []string{"pause " + modelUUID})
+}
+
+func (s *MigrateSuite) TestResume(c *gc.C) {
+	ctx, err := s.makeAndRun(c, "--resume", "model")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(cmdtesting.Stderr(ctx), gc.Equals, "Migration of model \"model\" resumed\n")
+	c.Check(s.api.changesSeen, jc.DeepEquals, []string{"resume " + modelUUID})
+}
+
+func (s *MigrateSuite) TestAbort(c *gc.C) {
+	ctx, err := s.makeAndRun(c, "--abort", "model")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(cmdtesting.Stderr(ctx), gc.Equals, "Migration of model \"model\" aborted\n")
+	c.Check(s.api.changesSeen, jc.DeepEquals, []string{"abort " + modelUUID})
+}
+
+func (s *MigrateSuite) TestAbortError(c *gc.C) {
+	s.api.changeErr = errors.New("migration cannot be aborted in phase SUCCESS")
+	_, err := s.makeAndRun(c, "--abort", "model")
+	c.Assert(err, gc.ErrorMatches, "migration cannot be aborted in phase SUCCESS")
+}
+
+func (s *MigrateSuite) TestChangeFlagsExclusive(c *gc.C) {
+	_, err := s.makeAndRun(c, "--pause", "--abort", "model")
+	c.Assert(err, gc.ErrorMatches, "only one of --pause, --resume and --abort may be specified")
+}
+
+func (s *MigrateSuite) TestChangeWithTargetController(c *gc.C) {
+	_, err := s.makeAndRun(c, "--pause", "model", "target")
+	c.Assert(err, gc.ErrorMatches, "target controller not expected with --pause, --resume or --abort")
+}
+
 func (s *MigrateSuite) makeAndRun(c *gc.C, args ...string) (*cmd.Context, error) {
 	return cmdtesting.RunCommand(c, s.makeCommand(), args...)
 }
@@ -227,7 +265,9 @@ func (s *MigrateSuite) makeCommand() modelcmd.ModelCommand {
 }
 
 type fakeMigrateAPI struct {
-	specSeen *controller.MigrationSpec
+	specSeen    *controller.MigrationSpec
+	changesSeen []string
+	changeErr   error
 }
 
 func (a *fakeMigrateAPI) InitiateMigration(spec controller.MigrationSpec) (string, error) {
@@ -235,6 +275,21 @@ func (a *fakeMigrateAPI) InitiateMigration(spec controller.MigrationSpec) (strin
 	return "uuid:0", nil
 }
 
+func (a *fakeMigrateAPI) PauseMigration(modelUUID string) error {
+	a.changesSeen = append(a.changesSeen, "pause "+modelUUID)
+	return a.changeErr
+}
+
+func (a *fakeMigrateAPI) ResumeMigration(modelUUID string) error {
+	a.changesSeen = append(a.changesSeen, "resume "+modelUUID)
+	return a.changeErr
+}
+
+func (a *fakeMigrateAPI) AbortMigration(modelUUID string) error {
+	a.changesSeen = append(a.changesSeen, "abort "+modelUUID)
+	return a.changeErr
+}
+
 type fakeModelAPI struct {
 	models []base.UserModel
 }
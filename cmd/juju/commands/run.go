@@ -14,6 +14,7 @@ import (
 	"github.com/juju/errors"
 	"github.com/juju/gnuflag"
 	"github.com/juju/utils"
+	"github.com/juju/utils/set"
 	"gopkg.in/juju/names.v2"
 
 	actionapi "github.com/juju/juju/api/action"
@@ -42,6 +43,7 @@ type runCommand struct {
 	machines  []string
 	services  []string
 	units     []string
+	parallel  int
 	commands  string
 	timeAfter func(time.Duration) <-chan time.Time
 }
@@ -73,6 +75,26 @@ the unit.
 in the model.  If you specify --all you cannot provide additional
 targets.
 
+In a CAAS model, which has no machines, only applications and units may be
+targeted, and the commands are run by a shell in the first container of each
+unit's pod rather than in a hook context.
+
+When the commands are run on more than one target with the default
+output format, the output of each target is written as it is received,
+each line prefixed with the name of the target; standard output and
+standard error are kept apart. Output from machines and units in a
+non-CAAS model is received when the commands on that target complete,
+while output from a CAAS model's pods is received as it is produced.
+Once the commands have completed on all of the targets, a summary of
+the exit status on each is written, and the command fails if the
+commands failed on any target. The yaml and json formats instead
+write the results of all of the targets together at the end.
+
+--parallel limits the number of targets the commands are run on at
+once; the commands are run on further targets as those running
+complete. The --timeout is applied to each target from the time the
+commands are started on it.
+
 Since juju run creates actions, you can query for the status of commands
 started with juju run by calling "juju show-action-status --name juju-run".
 
@@ -81,6 +103,10 @@ command and its arguments with "--", to tell "juju run" to stop processing
 those arguments. For example:
 
     juju run --all -- hostname -f
+
+To upgrade the packages of the units of mysql two at a time:
+
+    juju run --application mysql --parallel 2 -- sudo apt-get upgrade -y
 `
 
 func (c *runCommand) Info() *cmd.Info {
@@ -105,6 +131,7 @@ func (c *runCommand) SetFlags(f *gnuflag.FlagSet) {
 	f.Var(cmd.NewStringsValue(nil, &c.machines), "machine", "One or more machine ids")
 	f.Var(cmd.NewStringsValue(nil, &c.services), "application", "One or more application names")
 	f.Var(cmd.NewStringsValue(nil, &c.units), "unit", "One or more unit ids")
+	f.IntVar(&c.parallel, "parallel", 0, "The maximum number of targets to run the commands on at once (0 for no limit)")
 }
 
 func (c *runCommand) Init(args []string) error {
@@ -121,6 +148,9 @@ func (c *runCommand) Init(args []string) error {
 	} else {
 		c.commands = utils.CommandString(args...)
 	}
+	if c.parallel < 0 {
+		return errors.Errorf("--parallel must not be negative")
+	}
 
 	if c.all {
 		if len(c.machines) != 0 {
@@ -211,30 +241,193 @@ func ConvertActionResults(result params.ActionResult, query actionQuery) map[str
 }
 
 func (c *runCommand) Run(ctx *cmd.Context) error {
+	caasClient, err := getRunCAASClient(c)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if caasClient != nil {
+		defer caasClient.Close()
+		return c.runInPods(ctx, caasClient)
+	}
+
+	// When the number of targets the commands are run on at once is
+	// limited, the targets are expanded here so they can be enqueued
+	// a few at a time; otherwise they are all enqueued together.
+	var pending []names.Tag
+	if c.parallel > 0 {
+		pending, err = c.runTargets()
+		if err != nil {
+			return errors.Trace(err)
+		}
+	}
+
 	client, err := getRunAPIClient(c)
 	if err != nil {
 		return err
 	}
 	defer client.Close()
 
-	var runResults []params.ActionResult
-	if c.all {
-		runResults, err = client.RunOnAllMachines(c.commands, c.timeout)
-	} else {
-		params := params.RunParams{
-			Commands:     c.commands,
-			Timeout:      c.timeout,
-			Machines:     c.machines,
-			Applications: c.services,
-			Units:        c.units,
+	var (
+		actionsToQuery []actionQuery
+		enqueued       bool
+	)
+	enqueue := func() error {
+		var runResults []params.ActionResult
+		var err error
+		switch {
+		case c.parallel > 0:
+			n := c.parallel - len(actionsToQuery)
+			if n > len(pending) {
+				n = len(pending)
+			}
+			if n <= 0 {
+				return nil
+			}
+			runResults, err = client.Run(c.runParams(pending[:n]))
+			pending = pending[n:]
+		case enqueued:
+			return nil
+		case c.all:
+			runResults, err = client.RunOnAllMachines(c.commands, c.timeout)
+		default:
+			runResults, err = client.Run(params.RunParams{
+				Commands:     c.commands,
+				Timeout:      c.timeout,
+				Machines:     c.machines,
+				Applications: c.services,
+				Units:        c.units,
+			})
 		}
-		runResults, err = client.Run(params)
+		if err != nil {
+			return block.ProcessBlockedError(err, block.BlockChange)
+		}
+		queries := actionQueries(ctx, runResults)
+		if len(queries) == 0 {
+			return nil
+		}
+		// Each group of actions enqueued together is given
+		// the timeout from the time it was enqueued.
+		timeout := c.timeAfter(c.timeout)
+		for i := range queries {
+			queries[i].timeout = timeout
+		}
+		actionsToQuery = append(actionsToQuery, queries...)
+		enqueued = true
+		return nil
 	}
 
-	if err != nil {
-		return block.ProcessBlockedError(err, block.BlockChange)
+	if err := enqueue(); err != nil {
+		return err
+	}
+	if len(actionsToQuery) == 0 && len(pending) == 0 {
+		return errors.New("no actions were successfully enqueued, aborting")
+	}
+
+	// The results of commands run on several targets are written
+	// as each completes when using the default format; otherwise
+	// they are written together once all have completed.
+	var stream *streamingOutput
+	if c.out.Name() == "default" && len(actionsToQuery)+len(pending) > 1 {
+		stream = newStreamingOutput(ctx)
 	}
 
+	values := []interface{}{}
+	timedOut := []actionQuery{}
+	for len(actionsToQuery) > 0 || len(pending) > 0 {
+		if len(actionsToQuery) == 0 {
+			// None of the last group of actions could
+			// be enqueued, so try the next group.
+			if err := enqueue(); err != nil {
+				return err
+			}
+			continue
+		}
+
+		actionResults, err := client.Actions(entities(actionsToQuery))
+		if err != nil {
+			return errors.Trace(err)
+		}
+
+		newActionsToQuery := []actionQuery{}
+		for i, result := range actionResults.Results {
+			if result.Error == nil {
+				switch result.Status {
+				case params.ActionRunning, params.ActionPending:
+					newActionsToQuery = append(newActionsToQuery, actionsToQuery[i])
+					continue
+				}
+			}
+
+			converted := ConvertActionResults(result, actionsToQuery[i])
+			if stream != nil {
+				stream.writeResult(converted)
+			} else {
+				values = append(values, converted)
+			}
+		}
+		actionsToQuery = newActionsToQuery
+
+		if len(actionsToQuery) > 0 {
+			// Actions are queried in the order they were
+			// enqueued, so the first is the next to time out.
+			select {
+			case <-actionsToQuery[0].timeout:
+				expired := actionsToQuery[0].timeout
+				newActionsToQuery = []actionQuery{}
+				for _, query := range actionsToQuery {
+					if query.timeout == expired {
+						timedOut = append(timedOut, query)
+					} else {
+						newActionsToQuery = append(newActionsToQuery, query)
+					}
+				}
+				actionsToQuery = newActionsToQuery
+			case <-c.timeAfter(1 * time.Second):
+				// TODO(axw) 2017-02-07 #1662451
+				// use a watcher instead of polling.
+				// this should be easier once we implement
+				// action grouping
+			}
+		}
+		if err := enqueue(); err != nil {
+			return err
+		}
+	}
+
+	if stream != nil {
+		for _, query := range timedOut {
+			stream.addTimedOut(names.ReadableString(query.receiver.tag))
+		}
+		if err := stream.finish(); len(timedOut) == 0 {
+			return err
+		}
+	} else if len(timedOut) == 0 {
+		return c.writeResults(ctx, values)
+	} else if len(values) > 0 {
+		if err := c.out.Write(ctx, values); err != nil {
+			return err
+		}
+	}
+
+	// There are action results remaining, so return an error.
+	n := len(timedOut)
+	suffix := ""
+	if n > 1 {
+		suffix = "s"
+	}
+	receivers := make([]string, n)
+	for i, actionToQuery := range timedOut {
+		receivers[i] = names.ReadableString(actionToQuery.receiver.tag)
+	}
+	return errors.Errorf(
+		"timed out waiting for result%s from: %s",
+		suffix, strings.Join(receivers, ", "),
+	)
+}
+
+// actionQueries returns the actions to query for the results of
+// the commands enqueued, reporting any that could not be enqueued.
+func actionQueries(ctx *cmd.Context, runResults []params.ActionResult) []actionQuery {
 	actionsToQuery := []actionQuery{}
 	for _, result := range runResults {
 		if result.Error != nil {
@@ -267,53 +460,108 @@ func (c *runCommand) Run(ctx *cmd.Context) error {
 				tag:          receiverTag,
 			}})
 	}
+	return actionsToQuery
+}
 
-	if len(actionsToQuery) == 0 {
-		return errors.New("no actions were successfully enqueued, aborting")
+// runParams returns the parameters for running the
+// commands on the specified machines and units.
+func (c *runCommand) runParams(targets []names.Tag) params.RunParams {
+	runParams := params.RunParams{
+		Commands: c.commands,
+		Timeout:  c.timeout,
+	}
+	for _, tag := range targets {
+		switch tag.Kind() {
+		case names.MachineTagKind:
+			runParams.Machines = append(runParams.Machines, tag.Id())
+		case names.UnitTagKind:
+			runParams.Units = append(runParams.Units, tag.Id())
+		}
 	}
+	return runParams
+}
 
-	timeout := c.timeAfter(c.timeout)
-	values := []interface{}{}
-	for len(actionsToQuery) > 0 {
-		actionResults, err := client.Actions(entities(actionsToQuery))
-		if err != nil {
-			return errors.Trace(err)
+// runTargets returns the machines and units the commands are run on,
+// expanding applications into their units, and --all into all of the
+// machines and containers in the model.
+func (c *runCommand) runTargets() ([]names.Tag, error) {
+	var targets []names.Tag
+	seen := set.NewStrings()
+	add := func(tag names.Tag) {
+		if !seen.Contains(tag.String()) {
+			seen.Add(tag.String())
+			targets = append(targets, tag)
 		}
+	}
+	for _, machine := range c.machines {
+		add(names.NewMachineTag(machine))
+	}
+	for _, unit := range c.units {
+		add(names.NewUnitTag(unit))
+	}
+	if !c.all && len(c.services) == 0 {
+		return targets, nil
+	}
 
-		newActionsToQuery := []actionQuery{}
-		for i, result := range actionResults.Results {
-			if result.Error == nil {
-				switch result.Status {
-				case params.ActionRunning, params.ActionPending:
-					newActionsToQuery = append(newActionsToQuery, actionsToQuery[i])
-					continue
+	client, err := getRunStatusAPI(c)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	defer client.Close()
+	status, err := client.Status(nil)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if c.all {
+		for _, machine := range statusMachines(status.Machines) {
+			add(names.NewMachineTag(machine))
+		}
+	}
+	for _, application := range c.services {
+		appStatus, ok := status.Applications[application]
+		if !ok {
+			return nil, errors.NotFoundf("application %q", application)
+		}
+		units := set.NewStrings()
+		for name := range appStatus.Units {
+			units.Add(name)
+		}
+		if len(appStatus.SubordinateTo) > 0 {
+			// The units of subordinate applications are
+			// reported with the units they are related to.
+			for _, principal := range status.Applications {
+				for _, unitStatus := range principal.Units {
+					for name := range unitStatus.Subordinates {
+						if strings.HasPrefix(name, application+"/") {
+							units.Add(name)
+						}
+					}
 				}
 			}
-
-			values = append(values, ConvertActionResults(result, actionsToQuery[i]))
 		}
-		actionsToQuery = newActionsToQuery
-
-		if len(actionsToQuery) > 0 {
-			var timedOut bool
-			select {
-			case <-timeout:
-				timedOut = true
-			case <-c.timeAfter(1 * time.Second):
-				// TODO(axw) 2017-02-07 #1662451
-				// use a watcher instead of polling.
-				// this should be easier once we implement
-				// action grouping
-			}
-			if timedOut {
-				break
-			}
+		for _, unit := range utils.SortStringsNaturally(units.Values()) {
+			add(names.NewUnitTag(unit))
 		}
 	}
+	return targets, nil
+}
+
+// statusMachines returns the ids of the machines, and
+// the containers hosted on them, in natural order.
+func statusMachines(machines map[string]params.MachineStatus) []string {
+	var ids []string
+	for id, machine := range machines {
+		ids = append(ids, id)
+		ids = append(ids, statusMachines(machine.Containers)...)
+	}
+	return utils.SortStringsNaturally(ids)
+}
 
+// writeResults writes the results of the commands run.
+func (c *runCommand) writeResults(ctx *cmd.Context, values []interface{}) error {
 	// If we are just dealing with one result, AND we are using the default
 	// format, then pretend we were running it locally.
-	if len(actionsToQuery) == 0 && len(values) == 1 && c.out.Name() == "default" {
+	if len(values) == 1 && c.out.Name() == "default" {
 		result, ok := values[0].(map[string]interface{})
 		if !ok {
 			return errors.New("couldn't read action output")
@@ -335,25 +583,7 @@ func (c *runCommand) Run(ctx *cmd.Context) error {
 	}
 
 	if len(values) > 0 {
-		if err := c.out.Write(ctx, values); err != nil {
-			return err
-		}
-	}
-
-	if n := len(actionsToQuery); n > 0 {
-		// There are action results remaining, so return an error.
-		suffix := ""
-		if n > 1 {
-			suffix = "s"
-		}
-		receivers := make([]string, n)
-		for i, actionToQuery := range actionsToQuery {
-			receivers[i] = names.ReadableString(actionToQuery.receiver.tag)
-		}
-		return errors.Errorf(
-			"timed out waiting for result%s from: %s",
-			suffix, strings.Join(receivers, ", "),
-		)
+		return c.out.Write(ctx, values)
 	}
 	return nil
 }
@@ -366,6 +596,7 @@ type actionReceiver struct {
 type actionQuery struct {
 	receiver  actionReceiver
 	actionTag names.ActionTag
+	timeout   <-chan time.Time
 }
 
 // RunClient exposes the capabilities required by the CLI
@@ -385,6 +616,23 @@ var getRunAPIClient = func(c *runCommand) (RunClient, error) {
 	return actionapi.NewClient(root), errors.Trace(err)
 }
 
+// runStatusAPI exposes the model status used to expand the targets
+// of the commands when the number run on at once is limited.
+type runStatusAPI interface {
+	Status(patterns []string) (*params.FullStatus, error)
+	Close() error
+}
+
+// In order to be able to easily mock out the API side for testing,
+// the status API client is retrieved using a function.
+var getRunStatusAPI = func(c *runCommand) (runStatusAPI, error) {
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return root.Client(), nil
+}
+
 // getActionResult abstracts over the action CLI function that we use here to fetch results
 var getActionResult = func(c RunClient, actionId string, wait *time.Timer) (params.ActionResult, error) {
 	return action.GetActionResult(c, actionId, wait)
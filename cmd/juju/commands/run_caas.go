@@ -0,0 +1,195 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package commands
+
+import (
+	"bytes"
+	"io"
+	"sort"
+	"sync"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/utils/set"
+
+	"github.com/juju/juju/api"
+	"github.com/juju/juju/api/modelconfig"
+	"github.com/juju/juju/api/sshclient"
+	"github.com/juju/juju/caas"
+)
+
+// caasRunClient exposes the capabilities required to run
+// commands in the pods of the units of a CAAS model.
+type caasRunClient interface {
+	caasExecer
+
+	// ApplicationUnits returns the names of the
+	// units of the specified application.
+	ApplicationUnits(application string) ([]string, error)
+
+	Close() error
+}
+
+// getRunCAASClient returns a caasRunClient if the model is a CAAS
+// model, or nil otherwise. In order to be able to easily mock out
+// the API side for testing, it is retrieved using a function.
+var getRunCAASClient = func(c *runCommand) (caasRunClient, error) {
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	execer, err := openCAASExecer(sshclient.NewFacade(root), modelconfig.NewClient(root))
+	if err != nil || execer == nil {
+		root.Close()
+		return nil, errors.Trace(err)
+	}
+	return &caasRunAPI{caasExecer: execer, root: root}, nil
+}
+
+type caasRunAPI struct {
+	caasExecer
+	root api.Connection
+}
+
+// ApplicationUnits is part of the caasRunClient interface.
+func (a *caasRunAPI) ApplicationUnits(application string) ([]string, error) {
+	status, err := a.root.Client().Status([]string{application})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	appStatus, ok := status.Applications[application]
+	if !ok {
+		return nil, errors.NotFoundf("application %q", application)
+	}
+	var units []string
+	for name := range appStatus.Units {
+		units = append(units, name)
+	}
+	sort.Strings(units)
+	return units, nil
+}
+
+// Close is part of the caasRunClient interface.
+func (a *caasRunAPI) Close() error {
+	return a.root.Close()
+}
+
+// runInPods runs the commands in the pods of the targeted units of
+// a CAAS model, which have no machines, and writes the results in
+// the same form as the results of commands run by the units' agents.
+func (c *runCommand) runInPods(ctx *cmd.Context, client caasRunClient) error {
+	if c.all || len(c.machines) > 0 {
+		return errors.New("cannot run commands on machines in a CAAS model, specify --unit or --application")
+	}
+	units := set.NewStrings(c.units...)
+	for _, application := range c.services {
+		appUnits, err := client.ApplicationUnits(application)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		units = units.Union(set.NewStrings(appUnits...))
+	}
+
+	targets := units.SortedValues()
+	var stream *streamingOutput
+	if c.out.Name() == "default" && len(targets) > 1 {
+		stream = newStreamingOutput(ctx)
+	}
+
+	// The commands are run in the pods of up to c.parallel units
+	// at once, or all of them if that is not limited, and any still
+	// running when the timeout expires are aborted. Commands that
+	// wait to be run are given the timeout from when they start.
+	done := make(chan struct{})
+	defer close(done)
+	var abort <-chan struct{}
+	limit := c.parallel
+	if limit == 0 {
+		limit = len(targets)
+		abort = c.abortAfter(done)
+	}
+	sem := make(chan struct{}, limit)
+	var wg sync.WaitGroup
+	values := make([]interface{}, len(targets))
+	for i, unit := range targets {
+		sem <- struct{}{}
+		unitAbort := abort
+		if unitAbort == nil {
+			unitAbort = c.abortAfter(done)
+		}
+		wg.Add(1)
+		go func(i int, unit string, abort <-chan struct{}) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			values[i] = c.runInPod(client, unit, abort, stream)
+		}(i, unit, unitAbort)
+	}
+	wg.Wait()
+
+	if stream != nil {
+		for _, result := range values {
+			stream.addStatus(result.(map[string]interface{}))
+		}
+		return stream.finish()
+	}
+	return c.writeResults(ctx, values)
+}
+
+// abortAfter returns a channel that is closed when the
+// timeout expires, unless the done channel is closed first.
+func (c *runCommand) abortAfter(done <-chan struct{}) <-chan struct{} {
+	abort := make(chan struct{})
+	timeout := c.timeAfter(c.timeout)
+	go func() {
+		select {
+		case <-timeout:
+			close(abort)
+		case <-done:
+		}
+	}()
+	return abort
+}
+
+// runInPod runs the commands in the pod of the unit, returning the
+// result in the form written by runCommand. The command is aborted
+// when the abort channel is closed. If stream is non-nil, the output
+// of the commands is also written to it as it is received.
+func (c *runCommand) runInPod(
+	client caasRunClient, unit string, abort <-chan struct{}, stream *streamingOutput,
+) map[string]interface{} {
+	var stdout, stderr bytes.Buffer
+	var stdoutW, stderrW io.Writer = &stdout, &stderr
+	if stream != nil {
+		streamStdout, streamStderr := stream.writers("unit " + unit)
+		defer streamStdout.Flush()
+		defer streamStderr.Flush()
+		stdoutW = io.MultiWriter(stdoutW, streamStdout)
+		stderrW = io.MultiWriter(stderrW, streamStderr)
+	}
+	err := client.Exec(caas.ExecParams{
+		UnitName: unit,
+		Commands: []string{"sh", "-c", c.commands},
+		Stdout:   stdoutW,
+		Stderr:   stderrW,
+	}, abort)
+
+	values := map[string]interface{}{
+		"UnitId": unit,
+		"Stdout": stdout.String(),
+	}
+	if stderr.Len() > 0 {
+		values["Stderr"] = stderr.String()
+	}
+	if exitErr, ok := errors.Cause(err).(*caas.ExitError); ok {
+		values["ReturnCode"] = exitErr.Code
+	} else if err != nil {
+		select {
+		case <-abort:
+			values["Error"] = "timed out"
+		default:
+			values["Error"] = err.Error()
+		}
+	}
+	return values
+}
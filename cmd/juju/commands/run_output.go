@@ -0,0 +1,154 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package commands
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+)
+
+// streamingOutput writes the output of commands run on several
+// targets as the output of each is received, with each line prefixed
+// by the name of the target, followed by a summary of the exit status
+// of the commands on each target.
+type streamingOutput struct {
+	ctx *cmd.Context
+
+	// mu serialises the lines written by concurrently
+	// running commands, and guards the fields below.
+	mu      sync.Mutex
+	summary []string
+	failed  int
+}
+
+func newStreamingOutput(ctx *cmd.Context) *streamingOutput {
+	return &streamingOutput{ctx: ctx}
+}
+
+// writers returns the writers for the standard output and standard
+// error of the commands run on the named target. The writers must be
+// flushed once the commands have completed.
+func (o *streamingOutput) writers(target string) (stdout, stderr *prefixWriter) {
+	stdout = &prefixWriter{mu: &o.mu, out: o.ctx.Stdout, prefix: target + ": "}
+	stderr = &prefixWriter{mu: &o.mu, out: o.ctx.Stderr, prefix: target + ": "}
+	return stdout, stderr
+}
+
+// writeResult writes the output of the commands run on a target, as
+// returned by ConvertActionResults, and records their exit status.
+func (o *streamingOutput) writeResult(result map[string]interface{}) {
+	target := resultTarget(result)
+	stdout, stderr := o.writers(target)
+	stdout.Write(formatOutput(result, "Stdout"))
+	stdout.Flush()
+	stderr.Write(formatOutput(result, "Stderr"))
+	stderr.Flush()
+	o.addStatus(result)
+}
+
+// addStatus records the exit status of the commands run on a target,
+// from a result in the form returned by ConvertActionResults.
+func (o *streamingOutput) addStatus(result map[string]interface{}) {
+	status := "ok"
+	if res, ok := result["Error"].(string); ok {
+		status = res
+	} else if code, ok := result["ReturnCode"].(int); ok && code != 0 {
+		status = fmt.Sprintf("exit code %d", code)
+	} else if res, ok := result["Message"].(string); ok && res != "" {
+		// Message should always contain only errors.
+		status = res
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.summary = append(o.summary, fmt.Sprintf("%s: %s", resultTarget(result), status))
+	if status != "ok" {
+		o.failed++
+	}
+}
+
+// addTimedOut records that the result of the commands
+// run on the named target was not received in time.
+func (o *streamingOutput) addTimedOut(target string) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.summary = append(o.summary, fmt.Sprintf("%s: timed out", target))
+}
+
+// finish writes the summary of the exit status of the commands on
+// each target, returning an error if the commands failed on any.
+func (o *streamingOutput) finish() error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if len(o.summary) == 0 {
+		return nil
+	}
+	fmt.Fprintln(o.ctx.Stderr, "Summary:")
+	for _, line := range o.summary {
+		fmt.Fprintf(o.ctx.Stderr, "  %s\n", line)
+	}
+	if o.failed > 0 {
+		return errors.Errorf("commands failed on %d of %d targets", o.failed, len(o.summary))
+	}
+	return nil
+}
+
+// resultTarget returns the name of the target of a result
+// in the form returned by ConvertActionResults.
+func resultTarget(result map[string]interface{}) string {
+	if id, ok := result["UnitId"].(string); ok {
+		return "unit " + id
+	}
+	if id, ok := result["MachineId"].(string); ok {
+		return "machine " + id
+	}
+	return fmt.Sprint(result["ReceiverId"])
+}
+
+// prefixWriter is an io.Writer that writes whole lines to
+// the underlying writer, each prefixed by the same string.
+type prefixWriter struct {
+	mu     *sync.Mutex
+	out    io.Writer
+	prefix string
+	buf    []byte
+}
+
+// Write is part of the io.Writer interface.
+func (w *prefixWriter) Write(p []byte) (int, error) {
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			break
+		}
+		if err := w.writeLine(w.buf[:i+1]); err != nil {
+			return 0, errors.Trace(err)
+		}
+		w.buf = w.buf[i+1:]
+	}
+	return len(p), nil
+}
+
+// Flush writes any incomplete line remaining, terminating it.
+func (w *prefixWriter) Flush() error {
+	if len(w.buf) == 0 {
+		return nil
+	}
+	line := append(w.buf, '\n')
+	w.buf = nil
+	return w.writeLine(line)
+}
+
+func (w *prefixWriter) writeLine(line []byte) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	_, err := fmt.Fprintf(w.out, "%s%s", w.prefix, line)
+	return err
+}
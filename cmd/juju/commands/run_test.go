@@ -7,10 +7,12 @@ import (
 	"bytes"
 	"fmt"
 	"sort"
+	"sync"
 	"time"
 
 	"github.com/juju/cmd"
 	"github.com/juju/cmd/cmdtesting"
+	"github.com/juju/errors"
 	gitjujutesting "github.com/juju/testing"
 	jc "github.com/juju/testing/checkers"
 	"github.com/juju/utils"
@@ -21,6 +23,7 @@ import (
 
 	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/caas"
 	"github.com/juju/juju/cmd/juju/action"
 	"github.com/juju/juju/cmd/modelcmd"
 	"github.com/juju/juju/testing"
@@ -166,6 +169,39 @@ func (*RunSuite) TestTimeoutArgParsing(c *gc.C) {
 	}
 }
 
+func (*RunSuite) TestParallelArgParsing(c *gc.C) {
+	for i, test := range []struct {
+		message  string
+		args     []string
+		errMatch string
+		parallel int
+	}{{
+		message:  "default parallel",
+		args:     []string{"--all", "sudo reboot"},
+		parallel: 0,
+	}, {
+		message:  "invalid parallel",
+		args:     []string{"--parallel=foo", "--all", "sudo reboot"},
+		errMatch: `invalid value "foo" for flag --parallel: .*`,
+	}, {
+		message:  "negative parallel",
+		args:     []string{"--parallel=-1", "--all", "sudo reboot"},
+		errMatch: `--parallel must not be negative`,
+	}, {
+		message:  "two at once",
+		args:     []string{"--parallel=2", "--all", "sudo reboot"},
+		parallel: 2,
+	}} {
+		c.Log(fmt.Sprintf("%v: %s", i, test.message))
+		cmd := &runCommand{}
+		runCmd := modelcmd.Wrap(cmd)
+		cmdtesting.TestInit(c, runCmd, test.args, test.errMatch)
+		if test.errMatch == "" {
+			c.Check(cmd.parallel, gc.Equals, test.parallel)
+		}
+	}
+}
+
 func (s *RunSuite) TestConvertRunResults(c *gc.C) {
 	for i, test := range []struct {
 		message  string
@@ -274,6 +310,162 @@ func (s *RunSuite) TestRunForMachineAndUnit(c *gc.C) {
 	c.Check(cmdtesting.Stdout(context), gc.Equals, buff.String())
 }
 
+func (s *RunSuite) TestRunStreamsOutput(c *gc.C) {
+	mock := s.setupMockAPI()
+	mock.setResponse("0", mockResponse{
+		stdout:     "megatron\n",
+		stderr:     "decepticon\n",
+		code:       "2",
+		machineTag: "machine-0",
+	})
+	mock.setResponse("unit/0", mockResponse{
+		stdout:  "bumblebee",
+		unitTag: "unit-unit-0",
+	})
+	mock.actionResponses = map[string]params.ActionResult{
+		mock.receiverIdMap["0"]:      mock.runResponses["0"],
+		mock.receiverIdMap["unit/0"]: mock.runResponses["unit/0"],
+	}
+
+	context, err := cmdtesting.RunCommand(c, newTestRunCommand(&mockClock{}),
+		"--machine=0", "--unit=unit/0", "hostname",
+	)
+	c.Assert(err, gc.ErrorMatches, "commands failed on 1 of 2 targets")
+	c.Check(cmdtesting.Stdout(context), gc.Equals, ""+
+		"machine 0: megatron\n"+
+		"unit unit/0: bumblebee\n",
+	)
+	c.Check(cmdtesting.Stderr(context), gc.Equals, ""+
+		"machine 0: decepticon\n"+
+		"Summary:\n"+
+		"  machine 0: exit code 2\n"+
+		"  unit unit/0: ok\n",
+	)
+}
+
+func (s *RunSuite) TestRunStreamsOutputTimeout(c *gc.C) {
+	mock := s.setupMockAPI()
+	mock.setMachinesAlive("0", "1")
+	mock.setResponse("0", mockResponse{
+		stdout:     "megatron\n",
+		machineTag: "machine-0",
+	})
+	mock.setResponse("1", mockResponse{
+		machineTag: "machine-1",
+		status:     params.ActionRunning,
+	})
+	mock.actionResponses = map[string]params.ActionResult{
+		mock.receiverIdMap["0"]: mock.runResponses["0"],
+		mock.receiverIdMap["1"]: mock.runResponses["1"],
+	}
+
+	context, err := cmdtesting.RunCommand(c, newTestRunCommand(&mockClock{}), "--all", "hostname")
+	c.Assert(err, gc.ErrorMatches, "timed out waiting for result from: machine 1")
+	c.Check(cmdtesting.Stdout(context), gc.Equals, "machine 0: megatron\n")
+	c.Check(cmdtesting.Stderr(context), gc.Equals, ""+
+		"Summary:\n"+
+		"  machine 0: ok\n"+
+		"  machine 1: timed out\n",
+	)
+}
+
+func (s *RunSuite) TestRunParallel(c *gc.C) {
+	mock := s.setupMockAPI()
+	mock.setResponse("0", mockResponse{
+		stdout:     "megatron\n",
+		machineTag: "machine-0",
+	})
+	mock.setResponse("1", mockResponse{
+		stdout:     "starscream\n",
+		machineTag: "machine-1",
+	})
+	mock.actionResponses = map[string]params.ActionResult{
+		mock.receiverIdMap["0"]: mock.runResponses["0"],
+		mock.receiverIdMap["1"]: mock.runResponses["1"],
+	}
+
+	var clock pollingClock
+	context, err := cmdtesting.RunCommand(c, newTestRunCommand(&clock),
+		"--machine=0,1", "--parallel=1", "hostname",
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(cmdtesting.Stdout(context), gc.Equals, ""+
+		"machine 0: megatron\n"+
+		"machine 1: starscream\n",
+	)
+	c.Check(cmdtesting.Stderr(context), gc.Equals, ""+
+		"Summary:\n"+
+		"  machine 0: ok\n"+
+		"  machine 1: ok\n",
+	)
+	c.Check(mock.runCalls, jc.DeepEquals, []params.RunParams{{
+		Commands: "hostname",
+		Timeout:  5 * time.Minute,
+		Machines: []string{"0"},
+	}, {
+		Commands: "hostname",
+		Timeout:  5 * time.Minute,
+		Machines: []string{"1"},
+	}})
+	// Each machine is given the timeout from when its commands are enqueued.
+	clock.CheckCalls(c, []gitjujutesting.StubCall{
+		{"After", []interface{}{5 * time.Minute}},
+		{"After", []interface{}{5 * time.Minute}},
+	})
+}
+
+func (s *RunSuite) TestRunParallelApplication(c *gc.C) {
+	mock := s.setupMockAPI()
+	mock.setResponse("mysql/0", mockResponse{
+		stdout:  "mysql/0",
+		unitTag: "unit-mysql-0",
+	})
+	mock.setResponse("mysql/1", mockResponse{
+		stdout:  "mysql/1",
+		unitTag: "unit-mysql-1",
+	})
+	mock.actionResponses = map[string]params.ActionResult{
+		mock.receiverIdMap["mysql/0"]: mock.runResponses["mysql/0"],
+		mock.receiverIdMap["mysql/1"]: mock.runResponses["mysql/1"],
+	}
+	statusAPI := &mockRunStatusAPI{
+		status: &params.FullStatus{
+			Applications: map[string]params.ApplicationStatus{
+				"mysql": {
+					Units: map[string]params.UnitStatus{
+						"mysql/1": {},
+						"mysql/0": {},
+					},
+				},
+			},
+		},
+	}
+	s.PatchValue(&getRunStatusAPI, func(_ *runCommand) (runStatusAPI, error) {
+		return statusAPI, nil
+	})
+
+	context, err := cmdtesting.RunCommand(c, newTestRunCommand(&pollingClock{}),
+		"--format=json", "--application=mysql", "--parallel=1", "hostname",
+	)
+	c.Assert(err, jc.ErrorIsNil)
+
+	var buff bytes.Buffer
+	err = cmd.FormatJson(&buff, []interface{}{
+		ConvertActionResults(mock.runResponses["mysql/0"], makeActionQuery(
+			mock.receiverIdMap["mysql/0"], "UnitId", names.NewUnitTag("mysql/0"),
+		)),
+		ConvertActionResults(mock.runResponses["mysql/1"], makeActionQuery(
+			mock.receiverIdMap["mysql/1"], "UnitId", names.NewUnitTag("mysql/1"),
+		)),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(cmdtesting.Stdout(context), gc.Equals, buff.String())
+	c.Check(mock.runCalls, gc.HasLen, 2)
+	c.Check(mock.runCalls[0].Units, jc.DeepEquals, []string{"mysql/0"})
+	c.Check(mock.runCalls[1].Units, jc.DeepEquals, []string{"mysql/1"})
+	statusAPI.CheckCallNames(c, "Status", "Close")
+}
+
 func (s *RunSuite) TestBlockRunForMachineAndUnit(c *gc.C) {
 	mock := s.setupMockAPI()
 	// Block operation
@@ -417,6 +609,22 @@ func (c *mockClock) After(d time.Duration) <-chan time.Time {
 	return ch
 }
 
+// pollingClock is a clock whose sleeps while polling for
+// results return immediately, and whose timeouts never expire.
+type pollingClock struct {
+	gitjujutesting.Stub
+	clock.Clock
+}
+
+func (c *pollingClock) After(d time.Duration) <-chan time.Time {
+	c.MethodCall(c, "After", d)
+	ch := make(chan time.Time)
+	if d == time.Second {
+		close(ch)
+	}
+	return ch
+}
+
 func (s *RunSuite) TestBlockAllMachines(c *gc.C) {
 	mock := s.setupMockAPI()
 	// Block operation
@@ -496,9 +704,156 @@ func (s *RunSuite) setupMockAPI() *mockRunAPI {
 	s.PatchValue(&getRunAPIClient, func(_ *runCommand) (RunClient, error) {
 		return mock, nil
 	})
+	s.PatchValue(&getRunCAASClient, func(_ *runCommand) (caasRunClient, error) {
+		return nil, nil
+	})
+	return mock
+}
+
+func (s *RunSuite) setupMockCAASAPI() *mockCAASRunClient {
+	mock := &mockCAASRunClient{
+		units: map[string][]string{
+			"mariadb": {"mariadb/0", "mariadb/1"},
+		},
+	}
+	s.PatchValue(&getRunCAASClient, func(_ *runCommand) (caasRunClient, error) {
+		return mock, nil
+	})
 	return mock
 }
 
+func (s *RunSuite) TestCAASSingleUnit(c *gc.C) {
+	mock := s.setupMockCAASAPI()
+	mock.exec = func(params caas.ExecParams) error {
+		fmt.Fprint(params.Stdout, "stdout\n")
+		fmt.Fprint(params.Stderr, "stderr\n")
+		return &caas.ExitError{Code: 42}
+	}
+	context, err := cmdtesting.RunCommand(c, newTestRunCommand(&mockClock{}), "--unit", "mariadb/0", "hostname")
+	c.Assert(err, gc.ErrorMatches, "subprocess encountered error code 42")
+	c.Check(cmdtesting.Stdout(context), gc.Equals, "stdout\n")
+	c.Check(cmdtesting.Stderr(context), gc.Equals, "stderr\n")
+	mock.CheckCall(c, 0, "Exec", "mariadb/0", []string{"sh", "-c", "hostname"})
+}
+
+func (s *RunSuite) TestCAASApplication(c *gc.C) {
+	mock := s.setupMockCAASAPI()
+	mock.exec = func(params caas.ExecParams) error {
+		if params.UnitName == "mariadb/1" {
+			return errors.New("pod not running")
+		}
+		fmt.Fprint(params.Stdout, params.UnitName)
+		return nil
+	}
+	context, err := cmdtesting.RunCommand(c, newTestRunCommand(&mockClock{}), "--format=json", "--application", "mariadb", "hostname")
+	c.Assert(err, jc.ErrorIsNil)
+
+	expected := []interface{}{
+		map[string]interface{}{
+			"UnitId": "mariadb/0",
+			"Stdout": "mariadb/0",
+		},
+		map[string]interface{}{
+			"UnitId": "mariadb/1",
+			"Stdout": "",
+			"Error":  "pod not running",
+		},
+	}
+	var buff bytes.Buffer
+	err = cmd.FormatJson(&buff, expected)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(cmdtesting.Stdout(context), gc.Equals, buff.String())
+	mock.CheckCallNames(c, "ApplicationUnits", "Exec", "Exec", "Close")
+}
+
+func (s *RunSuite) TestCAASApplicationParallel(c *gc.C) {
+	mock := s.setupMockCAASAPI()
+	var (
+		mu      sync.Mutex
+		running int
+	)
+	mock.exec = func(params caas.ExecParams) error {
+		mu.Lock()
+		running++
+		c.Check(running, gc.Equals, 1)
+		mu.Unlock()
+		defer func() {
+			mu.Lock()
+			running--
+			mu.Unlock()
+		}()
+		fmt.Fprintf(params.Stdout, "hello from\n%s", params.UnitName)
+		if params.UnitName == "mariadb/1" {
+			return &caas.ExitError{Code: 1}
+		}
+		return nil
+	}
+	var clock pollingClock
+	context, err := cmdtesting.RunCommand(c, newTestRunCommand(&clock),
+		"--application", "mariadb", "--parallel", "1", "hostname",
+	)
+	c.Assert(err, gc.ErrorMatches, "commands failed on 1 of 2 targets")
+	c.Check(cmdtesting.Stdout(context), gc.Equals, ""+
+		"unit mariadb/0: hello from\n"+
+		"unit mariadb/0: mariadb/0\n"+
+		"unit mariadb/1: hello from\n"+
+		"unit mariadb/1: mariadb/1\n",
+	)
+	c.Check(cmdtesting.Stderr(context), gc.Equals, ""+
+		"Summary:\n"+
+		"  unit mariadb/0: ok\n"+
+		"  unit mariadb/1: exit code 1\n",
+	)
+	mock.CheckCallNames(c, "ApplicationUnits", "Exec", "Exec", "Close")
+	clock.CheckCalls(c, []gitjujutesting.StubCall{
+		{"After", []interface{}{5 * time.Minute}},
+		{"After", []interface{}{5 * time.Minute}},
+	})
+}
+
+func (s *RunSuite) TestCAASMachinesNotSupported(c *gc.C) {
+	mock := s.setupMockCAASAPI()
+	_, err := cmdtesting.RunCommand(c, newTestRunCommand(&mockClock{}), "--all", "hostname")
+	c.Assert(err, gc.ErrorMatches, "cannot run commands on machines in a CAAS model, specify --unit or --application")
+	mock.CheckCallNames(c, "Close")
+}
+
+type mockCAASRunClient struct {
+	gitjujutesting.Stub
+	units map[string][]string
+	exec  func(caas.ExecParams) error
+}
+
+func (m *mockCAASRunClient) Exec(params caas.ExecParams, abort <-chan struct{}) error {
+	m.MethodCall(m, "Exec", params.UnitName, params.Commands)
+	return m.exec(params)
+}
+
+func (m *mockCAASRunClient) ApplicationUnits(application string) ([]string, error) {
+	m.MethodCall(m, "ApplicationUnits", application)
+	return m.units[application], m.NextErr()
+}
+
+func (m *mockCAASRunClient) Close() error {
+	m.MethodCall(m, "Close")
+	return nil
+}
+
+type mockRunStatusAPI struct {
+	gitjujutesting.Stub
+	status *params.FullStatus
+}
+
+func (m *mockRunStatusAPI) Status(patterns []string) (*params.FullStatus, error) {
+	m.MethodCall(m, "Status", patterns)
+	return m.status, m.NextErr()
+}
+
+func (m *mockRunStatusAPI) Close() error {
+	m.MethodCall(m, "Close")
+	return m.NextErr()
+}
+
 type mockRunAPI struct {
 	action.APIClient
 	stdout string
@@ -510,6 +865,7 @@ type mockRunAPI struct {
 	actionResponses map[string]params.ActionResult
 	receiverIdMap   map[string]string
 	block           bool
+	runCalls        []params.RunParams
 }
 
 type mockResponse struct {
@@ -617,6 +973,7 @@ func (m *mockRunAPI) RunOnAllMachines(commands string, timeout time.Duration) ([
 
 func (m *mockRunAPI) Run(runParams params.RunParams) ([]params.ActionResult, error) {
 	var result []params.ActionResult
+	m.runCalls = append(m.runCalls, runParams)
 
 	if m.block {
 		return result, common.OperationBlockedError("the operation has been blocked")
@@ -9,6 +9,7 @@ import (
 
 	"github.com/juju/cmd"
 	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
 	"github.com/juju/utils/ssh"
 
 	"github.com/juju/juju/cmd/modelcmd"
@@ -31,8 +32,17 @@ The <target> may be either a 'unit name' or a 'machine id'. These can be
 obtained from the output of "juju status".
 
 Options specific to scp can be provided after a "--". Refer to the scp(1) man
-page for an explanation of those options. The "-r" option to recursively copy a
-directory is particularly useful.
+page for an explanation of those options.
+
+Directories are copied recursively with -r. When both the source and the
+destination are remote, the transfer is made via the client (scp's -3 option),
+so the machines need not be able to reach each other. Since scp's own progress
+meter is not shown, --progress may be used to copy the sources one at a time,
+reporting each as it is copied.
+
+Targets with no address reachable from the client are reached through the
+controller, as if --proxy had been specified. The --proxy-host option instead
+routes the transfer through another host, such as a bastion.
 
 The SSH host keys of the target are verified. The --no-host-key-checks option
 can be used to disable these checks. Use of this option is not recommended as
@@ -48,7 +58,7 @@ directory:
 Recursively copy the /var/log/mongodb directory from a mongodb unit to the
 client's local remote-logs directory:
 
-    juju scp -- -r mongodb/0:/var/log/mongodb/ remote-logs
+    juju scp -r mongodb/0:/var/log/mongodb/ remote-logs
 
 Copy foo.txt from the client's current working directory to an apache2 unit of
 model "prod". Proxy the SSH connection through the controller and turn on scp
@@ -65,10 +75,13 @@ current working directory:
 
     juju scp bob@3:'file1 file2' .
 
-Copy file.dat from machine 0 to the machine hosting unit foo/0 (-3
-causes the transfer to be made via the client):
+Copy file.dat from machine 0 to the machine hosting unit foo/0:
+
+    juju scp 0:file.dat foo/0:
+
+Copy several directories to a unit, reporting progress:
 
-    juju scp -- -3 0:file.dat foo/0:
+    juju scp -r --progress charms lib mysql/0:/tmp/
 
 See also: 
     ssh`
@@ -82,6 +95,15 @@ func newSCPCommand(hostChecker jujussh.ReachableChecker) cmd.Command {
 // scpCommand is responsible for launching a scp command to copy files to/from remote machine(s)
 type scpCommand struct {
 	SSHCommon
+	recursive bool
+	progress  bool
+}
+
+func (c *scpCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.SSHCommon.SetFlags(f)
+	f.BoolVar(&c.recursive, "r", false, "Recursively copy directories")
+	f.BoolVar(&c.recursive, "recursive", false, "")
+	f.BoolVar(&c.progress, "progress", false, "Copy the sources one at a time, reporting each")
 }
 
 func (c *scpCommand) Info() *cmd.Info {
@@ -109,6 +131,9 @@ func (c *scpCommand) Run(ctx *cmd.Context) error {
 		return errors.Trace(err)
 	}
 	defer c.cleanupRun()
+	if c.caasExecer != nil {
+		return errors.NotSupportedf("scp in a CAAS model")
+	}
 
 	args, targets, err := expandArgs(c.Args, c.resolveTarget)
 	if err != nil {
@@ -120,7 +145,77 @@ func (c *scpCommand) Run(ctx *cmd.Context) error {
 		return err
 	}
 
-	return ssh.Copy(args, options)
+	var extraArgs []string
+	if c.recursive {
+		extraArgs = append(extraArgs, "-r")
+	}
+	paths, scpOptions := splitSCPArgs(c.Args)
+	if len(paths) < 2 {
+		return errors.Errorf("at least two arguments required")
+	}
+	dest := paths[len(paths)-1]
+	if isRemotePath(c.Args[dest]) {
+		for _, source := range paths[:len(paths)-1] {
+			if isRemotePath(c.Args[source]) {
+				// Copy between remote targets via the client, as
+				// they may not be able to reach each other.
+				extraArgs = append(extraArgs, "-3")
+				break
+			}
+		}
+	}
+
+	if !c.progress {
+		return ssh.Copy(append(extraArgs, args...), options)
+	}
+	sources := paths[:len(paths)-1]
+	for i, source := range sources {
+		ctx.Infof("Copying %s to %s (%d of %d)", c.Args[source], c.Args[dest], i+1, len(sources))
+		copyArgs := append(append([]string{}, extraArgs...), selectArgs(args, scpOptions)...)
+		copyArgs = append(copyArgs, args[source], args[dest])
+		if err := ssh.Copy(copyArgs, options); err != nil {
+			return errors.Annotatef(err, "copying %s", c.Args[source])
+		}
+	}
+	return nil
+}
+
+// scpOptionsWithValues holds the scp options which take a value.
+const scpOptionsWithValues = "cFiloPS"
+
+// splitSCPArgs returns the indices of the paths in args, and of the
+// scp options and their values.
+func splitSCPArgs(args []string) (paths, options []int) {
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if !strings.HasPrefix(arg, "-") || arg == "-" {
+			paths = append(paths, i)
+			continue
+		}
+		options = append(options, i)
+		// An option which takes a value may be given it in the same
+		// argument (e.g. -P22), or in the next one (e.g. -P 22).
+		if len(arg) == 2 && strings.Contains(scpOptionsWithValues, arg[1:]) && i+1 < len(args) {
+			i++
+			options = append(options, i)
+		}
+	}
+	return paths, options
+}
+
+// selectArgs returns the args with the given indices.
+func selectArgs(args []string, indices []int) []string {
+	result := make([]string, len(indices))
+	for i, index := range indices {
+		result[i] = args[index]
+	}
+	return result
+}
+
+// isRemotePath reports whether the scp argument refers
+// to a path on a remote target.
+func isRemotePath(arg string) bool {
+	return !strings.HasPrefix(arg, "-") && strings.Contains(arg, ":")
 }
 
 // expandArgs takes a list of arguments and looks for ones in the form of
@@ -133,12 +228,12 @@ func expandArgs(args []string, resolveTarget func(string) (*resolvedTarget, erro
 	outArgs := make([]string, len(args))
 	var targets []*resolvedTarget
 	for i, arg := range args {
-		v := strings.SplitN(arg, ":", 2)
-		if strings.HasPrefix(arg, "-") || len(v) <= 1 {
+		if !isRemotePath(arg) {
 			// Can't be an interesting target, so just pass it along
 			outArgs[i] = arg
 			continue
 		}
+		v := strings.SplitN(arg, ":", 2)
 
 		target, err := resolveTarget(v[0])
 		if err != nil {
@@ -96,7 +96,7 @@ var scpTests = []struct {
 		args:        []string{"0:foo", "mysql/0:/foo"},
 		hostChecker: validAddresses("0.public"),
 		expected: argsSpec{
-			args:            "ubuntu@0.public:foo ubuntu@0.public:/foo",
+			args:            "-3 ubuntu@0.public:foo ubuntu@0.public:/foo",
 			hostKeyChecking: "yes",
 			knownHosts:      "0",
 		},
@@ -105,7 +105,7 @@ var scpTests = []struct {
 		args:        []string{"0:foo", "mysql/0:/foo", "-q"},
 		hostChecker: validAddresses("0.public"),
 		expected: argsSpec{
-			args:            "ubuntu@0.public:foo ubuntu@0.public:/foo -q",
+			args:            "-3 ubuntu@0.public:foo ubuntu@0.public:/foo -q",
 			hostKeyChecking: "yes",
 			knownHosts:      "0",
 		},
@@ -127,7 +127,7 @@ var scpTests = []struct {
 		args:        []string{"0:foo", "mysql/0:", "-r", "-v", "-q", "-l5"},
 		hostChecker: validAddresses("0.public"),
 		expected: argsSpec{
-			args:            "ubuntu@0.public:foo ubuntu@0.public: -r -v -q -l5",
+			args:            "-3 ubuntu@0.public:foo ubuntu@0.public: -r -v -q -l5",
 			hostKeyChecking: "yes",
 			knownHosts:      "0",
 		},
@@ -145,7 +145,7 @@ var scpTests = []struct {
 		args:        []string{"--proxy=true", "0:foo", "mysql/0:/bar"},
 		hostChecker: validAddresses("0.private"),
 		expected: argsSpec{
-			args:            "ubuntu@0.private:foo ubuntu@0.private:/bar",
+			args:            "-3 ubuntu@0.private:foo ubuntu@0.private:/bar",
 			withProxy:       true,
 			hostKeyChecking: "yes",
 			knownHosts:      "0",
@@ -155,7 +155,7 @@ var scpTests = []struct {
 		args:        []string{"--", "-r", "-v", "mysql/0:foo", "2:", "-q", "-l5"},
 		hostChecker: validAddresses("0.public", "2001:db8::1"),
 		expected: argsSpec{
-			args:            "-r -v ubuntu@0.public:foo ubuntu@[2001:db8::1]: -q -l5",
+			args:            "-3 -r -v ubuntu@0.public:foo ubuntu@[2001:db8::1]: -q -l5",
 			hostKeyChecking: "yes",
 			knownHosts:      "0,2",
 		},
@@ -199,11 +199,38 @@ var scpTests = []struct {
 		hostChecker: validAddresses("some.host", "0.private"),
 		forceAPIv1:  true,
 		expected: argsSpec{
-			args:            "some.host:foo ubuntu@0.private:",
+			args:            "-3 some.host:foo ubuntu@0.private:",
 			hostKeyChecking: "no",
 			withProxy:       true,
 			knownHosts:      "null",
 		},
+	}, {
+		about:       "scp a directory from unit mysql/0 to current dir",
+		args:        []string{"-r", "mysql/0:/var/log", "."},
+		hostChecker: validAddresses("0.public"),
+		expected: argsSpec{
+			args:            "-r ubuntu@0.public:/var/log .",
+			hostKeyChecking: "yes",
+			knownHosts:      "0",
+		},
+	}, {
+		about:       "scp a directory to unit mysql/0 with --recursive",
+		args:        []string{"--recursive", "logs", "mysql/0:"},
+		hostChecker: validAddresses("0.public"),
+		expected: argsSpec{
+			args:            "-r logs ubuntu@0.public:",
+			hostKeyChecking: "yes",
+			knownHosts:      "0",
+		},
+	}, {
+		about:       "scp between unit mysql/0 and machine 2",
+		args:        []string{"mysql/0:foo", "2:/foo"},
+		hostChecker: validAddresses("0.public", "2001:db8::1"),
+		expected: argsSpec{
+			args:            "-3 ubuntu@0.public:foo ubuntu@[2001:db8::1]:/foo",
+			hostKeyChecking: "yes",
+			knownHosts:      "0,2",
+		},
 	}, {
 		about: "scp with no arguments",
 		args:  nil,
@@ -211,6 +238,29 @@ var scpTests = []struct {
 	},
 }
 
+func (s *SCPSuite) TestSCPCommandProgress(c *gc.C) {
+	s.setupModel(c)
+	s.setHostChecker(validAddresses("0.public"))
+
+	args := []string{"--progress", "file1", "file2", "mysql/0:/foo/", "-P", "2222"}
+	ctx, err := cmdtesting.RunCommand(c, newSCPCommand(s.hostChecker), args...)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(cmdtesting.Stderr(ctx), gc.Equals, `
+Copying file1 to mysql/0:/foo/ (1 of 2)
+Copying file2 to mysql/0:/foo/ (2 of 2)
+`[1:])
+
+	// The fake scp records the arguments of the last copy.
+	actual, err := ioutil.ReadFile(filepath.Join(s.binDir, "scp.args"))
+	c.Assert(err, jc.ErrorIsNil)
+	expected := argsSpec{
+		args:            "-P 2222 file2 ubuntu@0.public:/foo/",
+		hostKeyChecking: "yes",
+		knownHosts:      "0",
+	}
+	expected.check(c, string(actual))
+}
+
 func (s *SCPSuite) TestSCPCommand(c *gc.C) {
 	s.setupModel(c)
 
@@ -63,10 +63,23 @@ Connect to a jenkins unit as user jenkins:
 
     juju ssh jenkins@jenkins/0
 
+Connect to a mysql unit through a bastion host:
+
+    juju ssh --proxy-host ubuntu@bastion.example.com mysql/0
+
 Connect to a mysql unit with an identity not known to juju (ssh option -i):
 
     juju ssh mysql/0 -i ~/.ssh/my_private_key echo hello
 
+If the target has no address reachable from the client, the connection is
+made through the controller, as if --proxy had been specified. The
+--proxy-host option instead routes the connection through another host, such
+as a bastion; that host is reached using the client's own SSH configuration.
+
+In a CAAS model, where units run in pods without SSH servers, the command
+is instead run in the first container of the unit's pod, and OpenSSH
+options are not accepted. A shell is started if no command is specified.
+
 See also: 
     scp`
 
@@ -118,11 +131,6 @@ func (c *sshCommand) Run(ctx *cmd.Context) error {
 	}
 	defer c.cleanupRun()
 
-	target, err := c.resolveTarget(c.Target)
-	if err != nil {
-		return err
-	}
-
 	var pty bool
 	if c.pty.b != nil {
 		pty = *c.pty.b
@@ -137,6 +145,18 @@ func (c *sshCommand) Run(ctx *cmd.Context) error {
 		pty = isTerminal(ctx.Stdin)
 	}
 
+	if c.caasExecer != nil {
+		// The units of CAAS models run no SSH servers,
+		// so the command is run in the unit's pod.
+		_, entity := splitUserTarget(c.Target)
+		return c.execInUnitPod(ctx, entity, false, pty, c.Args)
+	}
+
+	target, err := c.resolveTarget(c.Target)
+	if err != nil {
+		return err
+	}
+
 	options, err := c.getSSHOptions(pty, target)
 	if err != nil {
 		return err
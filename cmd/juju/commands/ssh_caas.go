@@ -0,0 +1,118 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package commands
+
+import (
+	"os"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/caas"
+	caasall "github.com/juju/juju/caas/all"
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/config"
+)
+
+// caasExecer runs commands in the pods of the units of a CAAS
+// model, which are reached with exec rather than SSH.
+type caasExecer interface {
+	Exec(params caas.ExecParams, abort <-chan struct{}) error
+}
+
+// modelCredentialAPI provides the cloud spec of a CAAS model.
+type modelCredentialAPI interface {
+	ModelCredentialForSSH() (environs.CloudSpec, error)
+}
+
+// caasModelConfigAPI provides the model config, needed
+// to open a broker for a CAAS model's substrate.
+type caasModelConfigAPI interface {
+	ModelGet() (map[string]interface{}, error)
+}
+
+// newCAASExecer returns a caasExecer for the CAAS
+// substrate of the model specified in args.
+var newCAASExecer = func(args environs.OpenParams) (caasExecer, error) {
+	return caasall.NewContainerBroker(args)
+}
+
+// initCAASExecer sets c.caasExecer if the model is a CAAS model,
+// leaving it nil otherwise. It must be called after the API client
+// has been initialised.
+func (c *SSHCommon) initCAASExecer() error {
+	execer, err := openCAASExecer(c.apiClient, c.modelConfigClient)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	c.caasExecer = execer
+	return nil
+}
+
+// openCAASExecer returns a caasExecer for the substrate of
+// the model if it is a CAAS model, or nil otherwise.
+func openCAASExecer(credentialAPI modelCredentialAPI, configAPI caasModelConfigAPI) (caasExecer, error) {
+	cloudSpec, err := credentialAPI.ModelCredentialForSSH()
+	if errors.IsNotSupported(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Annotate(err, "getting model credential")
+	}
+	attrs, err := configAPI.ModelGet()
+	if err != nil {
+		return nil, errors.Annotate(err, "getting model config")
+	}
+	cfg, err := config.New(config.NoDefaults, attrs)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	execer, err := newCAASExecer(environs.OpenParams{
+		Cloud:  cloudSpec,
+		Config: cfg,
+	})
+	return execer, errors.Trace(err)
+}
+
+// execInUnitPod runs the command in the pod of the unit, or in the
+// operator pod of the unit's application, connecting the command's
+// input and output to the context's. If no command is specified,
+// an interactive shell is started.
+func (c *SSHCommon) execInUnitPod(ctx *cmd.Context, target string, operator, tty bool, args []string) error {
+	if !names.IsValidUnit(target) {
+		return errors.Errorf("cannot connect to %q: only units can be reached in a CAAS model", target)
+	}
+	if len(args) == 0 {
+		args = []string{"sh"}
+	}
+	params := caas.ExecParams{
+		UnitName: target,
+		Operator: operator,
+		Commands: args,
+		TTY:      tty,
+		Stdin:    ctx.Stdin,
+		Stdout:   ctx.Stdout,
+		Stderr:   ctx.Stderr,
+	}
+
+	abort := make(chan struct{})
+	done := make(chan struct{})
+	defer close(done)
+	interrupted := make(chan os.Signal, 1)
+	ctx.InterruptNotify(interrupted)
+	defer ctx.StopInterruptNotify(interrupted)
+	go func() {
+		select {
+		case <-interrupted:
+			close(abort)
+		case <-done:
+		}
+	}()
+
+	err := c.caasExecer.Exec(params, abort)
+	if exitErr, ok := errors.Cause(err).(*caas.ExitError); ok {
+		return cmd.NewRcPassthroughError(exitErr.Code)
+	}
+	return errors.Trace(err)
+}
@@ -20,9 +20,11 @@ import (
 	"github.com/juju/utils/ssh"
 	"gopkg.in/juju/names.v2"
 
+	"github.com/juju/juju/api/modelconfig"
 	"github.com/juju/juju/api/sshclient"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/environs"
 	"github.com/juju/juju/network"
 	jujussh "github.com/juju/juju/network/ssh"
 )
@@ -32,6 +34,7 @@ import (
 type SSHCommon struct {
 	modelcmd.ModelCommandBase
 	proxy           bool
+	proxyHost       string
 	noHostKeyChecks bool
 	Target          string
 	Args            []string
@@ -40,6 +43,12 @@ type SSHCommon struct {
 	knownHostsPath  string
 	hostChecker     jujussh.ReachableChecker
 	forceAPIv1      bool
+
+	// modelConfigClient and caasExecer are used to
+	// reach the units of CAAS models, which have no
+	// SSH servers. caasExecer is nil for other models.
+	modelConfigClient caasModelConfigAPI
+	caasExecer        caasExecer
 }
 
 const jujuSSHClientForceAPIv1 = "JUJU_SSHCLIENT_API_V1"
@@ -51,6 +60,7 @@ type sshAPIClient interface {
 	AllAddresses(target string) ([]string, error)
 	PublicKeys(target string) ([]string, error)
 	Proxy() (bool, error)
+	ModelCredentialForSSH() (environs.CloudSpec, error)
 	Close() error
 }
 
@@ -111,6 +121,7 @@ var sshHostFromTargetAttemptStrategy attemptStarter = attemptStrategy{
 func (c *SSHCommon) SetFlags(f *gnuflag.FlagSet) {
 	c.ModelCommandBase.SetFlags(f)
 	f.BoolVar(&c.proxy, "proxy", false, "Proxy through the API server")
+	f.StringVar(&c.proxyHost, "proxy-host", "", "Proxy through the given [user@]host instead of the API server")
 	f.BoolVar(&c.noHostKeyChecks, "no-host-key-checks", false, "Skip host key checking (INSECURE)")
 }
 
@@ -131,11 +142,15 @@ func (c *SSHCommon) setHostChecker(checker jujussh.ReachableChecker) {
 // if SSH proxying is required. It must be called at the top of the
 // command's Run method.
 //
-// The apiClient, apiAddr and proxy fields are initialized after this call.
+// The apiClient, apiAddr, proxy and caasExecer fields are initialized
+// after this call.
 func (c *SSHCommon) initRun() error {
 	if err := c.ensureAPIClient(); err != nil {
 		return errors.Trace(err)
 	}
+	if err := c.initCAASExecer(); err != nil {
+		return errors.Trace(err)
+	}
 
 	if proxy, err := c.proxySSH(); err != nil {
 		return errors.Trace(err)
@@ -161,6 +176,8 @@ func (c *SSHCommon) cleanupRun() {
 		c.apiClient.Close()
 		c.apiClient = nil
 	}
+	c.modelConfigClient = nil
+	c.caasExecer = nil
 }
 
 // getSSHOptions configures SSH options based on command line
@@ -244,10 +261,10 @@ func (c *SSHCommon) generateKnownHosts(targets []*resolvedTarget) (string, error
 	return c.knownHostsPath, nil
 }
 
-// proxySSH returns false if both c.proxy and the proxy-ssh model
-// configuration are false -- otherwise it returns true.
+// proxySSH returns false if c.proxy, c.proxyHost and the proxy-ssh
+// model configuration are all unset -- otherwise it returns true.
 func (c *SSHCommon) proxySSH() (bool, error) {
-	if c.proxy {
+	if c.proxy || c.proxyHost != "" {
 		// No need to check the API if user explictly requested
 		// proxying.
 		return true, nil
@@ -262,6 +279,12 @@ func (c *SSHCommon) proxySSH() (bool, error) {
 
 // setProxyCommand sets the proxy command option.
 func (c *SSHCommon) setProxyCommand(options *ssh.Options) error {
+	if c.proxyHost != "" {
+		// The proxy host is not known to Juju, so the user's own
+		// SSH configuration and known_hosts are used to reach it.
+		options.SetProxyCommand("ssh", "-q", c.proxyHost, "nc %h %p")
+		return nil
+	}
 	apiServerHost, _, err := net.SplitHostPort(c.apiAddr)
 	if err != nil {
 		return errors.Errorf("failed to get proxy address: %v", err)
@@ -308,6 +331,7 @@ func (c *SSHCommon) initAPIClient() error {
 		return errors.Trace(err)
 	}
 	c.apiClient = sshclient.NewFacade(conn)
+	c.modelConfigClient = modelconfig.NewClient(conn)
 	c.apiAddr = conn.Addr()
 	return nil
 }
@@ -339,7 +363,18 @@ func (c *SSHCommon) resolveTarget(target string) (*resolvedTarget, error) {
 		getAddress = c.legacyAddressGetter
 	}
 
-	return c.resolveWithRetry(*out, getAddress)
+	resolved, err := c.resolveWithRetry(*out, getAddress)
+	if err == nil || c.proxy {
+		return resolved, err
+	}
+	// The target has no address reachable from here. If it has a
+	// private address, reach that through the controller instead.
+	if _, privateErr := c.apiClient.PrivateAddress(out.entity); privateErr != nil {
+		return nil, errors.Trace(err)
+	}
+	logger.Infof("no reachable address for %q (%v): proxying through the controller", out.entity, err)
+	c.proxy = true
+	return c.resolveWithRetry(*out, c.legacyAddressGetter)
 }
 
 func (c *SSHCommon) resolveAsAgent(target string) (*resolvedTarget, bool) {
@@ -33,6 +33,10 @@ type argsSpec struct {
 	// expected.
 	withProxy bool
 
+	// proxyHost specifies the host expected in the ProxyCommand
+	// option when proxying through a host other than the controller.
+	proxyHost string
+
 	// enablePty specifies if the forced PTY allocation switches are
 	// expected.
 	enablePty bool
@@ -79,6 +83,9 @@ func (s *argsSpec) check(c *gc.C, output string) {
 			"--no-host-key-checks " +
 			"--pty=false ubuntu@localhost -q \"nc %h %p\"")
 	}
+	if s.proxyHost != "" {
+		expect("-o ProxyCommand ssh -q " + regexp.QuoteMeta(s.proxyHost) + " \"nc %h %p\"")
+	}
 	expect("-o PasswordAuthentication no -o ServerAliveInterval 30")
 	if s.enablePty {
 		expect("-t -t")
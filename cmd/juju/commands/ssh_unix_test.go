@@ -183,6 +183,17 @@ var sshTests = []struct {
 			argsMatch:       `ubuntu@0.private`,
 		},
 	},
+	{
+		about:       "connect to unit mysql/0 through a proxy host",
+		args:        []string{"--proxy-host=jim@bastion", "mysql/0"},
+		hostChecker: nil, // Host checker shouldn't get used with --proxy-host
+		expected: argsSpec{
+			hostKeyChecking: "yes",
+			knownHosts:      "0",
+			proxyHost:       "jim@bastion",
+			args:            "ubuntu@0.private",
+		},
+	},
 }
 
 func (s *SSHSuite) TestSSHCommand(c *gc.C) {
@@ -259,6 +270,23 @@ func (s *SSHSuite) TestSSHWillWorkInUpgrade(c *gc.C) {
 	}
 }
 
+func (s *SSHSuite) TestSSHCommandUnreachableFallsBackToProxy(c *gc.C) {
+	s.setupModel(c)
+	// Make a single attempt to resolve each address.
+	s.PatchValue(&sshHostFromTargetAttemptStrategy, attemptStrategy{})
+
+	// None of the machine's addresses can be reached directly.
+	ctx, err := cmdtesting.RunCommand(c, newSSHCommand(validAddresses(), nil), "0")
+	c.Assert(err, jc.ErrorIsNil)
+	expectedArgs := argsSpec{
+		hostKeyChecking: "yes",
+		knownHosts:      "0",
+		withProxy:       true,
+		args:            "ubuntu@0.private",
+	}
+	expectedArgs.check(c, cmdtesting.Stdout(ctx))
+}
+
 /// XXX(jam): 2017-01-25 do we need these functions anymore? We don't really
 //support ssh'ing to V1 anymore
 func (s *SSHSuite) TestSSHCommandHostAddressRetryAPIv1(c *gc.C) {
@@ -104,3 +104,198 @@ func ResolveCloudCredentialTag(user names.UserTag, cloud names.CloudTag, credent
 	}
 	return names.NewCloudCredentialTag(s), nil
 }
+
+// CredentialAPI provides the credentials stored on a controller.
+type CredentialAPI interface {
+	UserCredentials(names.UserTag, names.CloudTag) ([]names.CloudCredentialTag, error)
+}
+
+// FindCredentialParams holds the parameters for FindCredential.
+type FindCredentialParams struct {
+	// CommandName is the name of the command looking for the
+	// credential, which the user is advised to run again when
+	// the credential to use is ambiguous.
+	CommandName string
+
+	// CredentialName is the name of the credential specified by
+	// the user, if any.
+	CredentialName string
+
+	CloudTag    names.CloudTag
+	Cloud       jujucloud.Cloud
+	CloudRegion string
+	ModelOwner  string
+}
+
+// FindCredential finds a suitable credential to use for a new model.
+// The credential will first be searched for locally and then on the
+// controller. If a credential is found locally then it's value will be
+// returned as the first return value. If it is found on the controller
+// this will be nil as there is no need to upload it in that case.
+func FindCredential(
+	ctx *cmd.Context,
+	store jujuclient.CredentialGetter,
+	providerRegistry environs.ProviderRegistry,
+	cloudClient CredentialAPI,
+	p FindCredentialParams,
+) (_ *jujucloud.Credential, _ names.CloudCredentialTag, cloudRegion string, _ error) {
+	finder := credentialFinder{
+		ctx:                  ctx,
+		store:                store,
+		providerRegistry:     providerRegistry,
+		cloudClient:          cloudClient,
+		FindCredentialParams: p,
+	}
+	if p.CredentialName == "" {
+		return finder.findUnspecifiedCredential()
+	}
+	return finder.findSpecifiedCredential()
+}
+
+type credentialFinder struct {
+	FindCredentialParams
+	ctx              *cmd.Context
+	store            jujuclient.CredentialGetter
+	providerRegistry environs.ProviderRegistry
+	cloudClient      CredentialAPI
+}
+
+func (f *credentialFinder) findUnspecifiedCredential() (_ *jujucloud.Credential, _ names.CloudCredentialTag, cloudRegion string, _ error) {
+	fail := func(err error) (*jujucloud.Credential, names.CloudCredentialTag, string, error) {
+		return nil, names.CloudCredentialTag{}, "", err
+	}
+	// If the user has not specified a credential, and the cloud advertises
+	// itself as supporting the "empty" auth-type, then return immediately.
+	for _, authType := range f.Cloud.AuthTypes {
+		if authType == jujucloud.EmptyAuthType {
+			return nil, names.CloudCredentialTag{}, f.CloudRegion, nil
+		}
+	}
+
+	// No credential has been specified, so see if there is one already on the controller we can use.
+	modelOwnerTag := names.NewUserTag(f.ModelOwner)
+	credentialTags, err := f.cloudClient.UserCredentials(modelOwnerTag, f.CloudTag)
+	if err != nil {
+		return fail(errors.Trace(err))
+	}
+	var credentialTag names.CloudCredentialTag
+	if len(credentialTags) == 1 {
+		credentialTag = credentialTags[0]
+	}
+
+	if (credentialTag != names.CloudCredentialTag{}) {
+		// If the controller already has a credential, see if
+		// there is a local version that has an associated
+		// region.
+		credential, _, cloudRegion, err := f.findLocalCredential(credentialTag.Name())
+		if errors.IsNotFound(err) {
+			// No local credential; use the region
+			// specified by the user, if any.
+			cloudRegion = f.CloudRegion
+		} else if err != nil {
+			return fail(errors.Trace(err))
+		}
+		// If there is a credential in the controller use it even if we don't have a local version.
+		return credential, credentialTag, cloudRegion, nil
+	}
+	// There is not a default credential on the controller (either
+	// there are no credentials, or there is more than one). Look for
+	// a local credential we might use.
+	credential, credentialName, cloudRegion, err := f.findLocalCredential("")
+	if err != nil {
+		return fail(errors.Trace(err))
+	}
+	// We've got a local credential to use.
+	credentialTag, err = ResolveCloudCredentialTag(
+		modelOwnerTag, f.CloudTag, credentialName,
+	)
+	if err != nil {
+		return fail(errors.Trace(err))
+	}
+	return credential, credentialTag, cloudRegion, nil
+}
+
+func (f *credentialFinder) findSpecifiedCredential() (_ *jujucloud.Credential, _ names.CloudCredentialTag, cloudRegion string, _ error) {
+	fail := func(err error) (*jujucloud.Credential, names.CloudCredentialTag, string, error) {
+		return nil, names.CloudCredentialTag{}, "", err
+	}
+	// Look for a local credential with the specified name
+	credential, credentialName, cloudRegion, err := f.findLocalCredential(f.CredentialName)
+	if err != nil && !errors.IsNotFound(err) {
+		return fail(errors.Trace(err))
+	}
+	if credential != nil {
+		// We found a local credential with the specified name.
+		modelOwnerTag := names.NewUserTag(f.ModelOwner)
+		credentialTag, err := ResolveCloudCredentialTag(
+			modelOwnerTag, f.CloudTag, credentialName,
+		)
+		if err != nil {
+			return fail(errors.Trace(err))
+		}
+		return credential, credentialTag, cloudRegion, nil
+	}
+
+	// There was no local credential with that name, check the controller
+	modelOwnerTag := names.NewUserTag(f.ModelOwner)
+	credentialTags, err := f.cloudClient.UserCredentials(modelOwnerTag, f.CloudTag)
+	if err != nil {
+		return fail(errors.Trace(err))
+	}
+	credentialTag, err := ResolveCloudCredentialTag(
+		modelOwnerTag, f.CloudTag, f.CredentialName,
+	)
+	if err != nil {
+		return fail(errors.Trace(err))
+	}
+	credentialId := credentialTag.Id()
+	for _, tag := range credentialTags {
+		if tag.Id() != credentialId {
+			continue
+		}
+		f.ctx.Infof("Using credential '%s' cached in controller", f.CredentialName)
+		return nil, credentialTag, "", nil
+	}
+	// Cannot find a credential with the correct name
+	return fail(errors.NotFoundf("credential '%s'", f.CredentialName))
+}
+
+func (f *credentialFinder) findLocalCredential(name string) (_ *jujucloud.Credential, credentialName, cloudRegion string, _ error) {
+	fail := func(err error) (*jujucloud.Credential, string, string, error) {
+		return nil, "", "", err
+	}
+	provider, err := f.providerRegistry.Provider(f.Cloud.Type)
+	if err != nil {
+		return fail(errors.Trace(err))
+	}
+	credential, credentialName, cloudRegion, _, err := GetOrDetectCredential(
+		f.ctx, f.store, provider, modelcmd.GetCredentialsParams{
+			Cloud:          f.Cloud,
+			CloudRegion:    f.CloudRegion,
+			CredentialName: name,
+		},
+	)
+	if err == nil {
+		return credential, credentialName, cloudRegion, nil
+	}
+	switch errors.Cause(err) {
+	case modelcmd.ErrMultipleCredentials:
+		return fail(errors.Errorf(`
+more than one credential is available. List credentials with:
+
+    juju credentials
+
+and then run the %s command again with the --credential flag.`[1:], f.CommandName,
+		))
+	case ErrMultipleDetectedCredentials:
+		return fail(errors.Errorf(`
+more than one credential detected. Add all detected credentials
+to the client with:
+
+    juju autoload-credentials
+
+and then run the %s command again with the --credential flag.`[1:], f.CommandName,
+		))
+	}
+	return fail(errors.Trace(err))
+}
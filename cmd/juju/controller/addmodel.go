@@ -210,12 +210,16 @@ func (c *addModelCommand) Run(ctx *cmd.Context) error {
 	}
 
 	// Find a credential to use with the new model.
-	credential, credentialTag, cloudRegion, err := c.findCredential(ctx, cloudClient, &findCredentialParams{
-		cloudTag:    cloudTag,
-		cloudRegion: cloudRegion,
-		cloud:       cloud,
-		modelOwner:  modelOwner,
-	})
+	credential, credentialTag, cloudRegion, err := common.FindCredential(
+		ctx, store, c.providerRegistry, cloudClient, common.FindCredentialParams{
+			CommandName:    "add-model",
+			CredentialName: c.CredentialName,
+			CloudTag:       cloudTag,
+			Cloud:          cloud,
+			CloudRegion:    cloudRegion,
+			ModelOwner:     modelOwner,
+		},
+	)
 	if err != nil {
 		return errors.Trace(err)
 	}
@@ -409,169 +413,6 @@ there is no default cloud defined, please specify one using:
 	return cloudTag, cloud, nil
 }
 
-var ambiguousDetectedCredentialError = errors.New(`
-more than one credential detected. Add all detected credentials
-to the client with:
-
-    juju autoload-credentials
-
-and then run the add-model command again with the --credential flag.`[1:],
-)
-
-var ambiguousCredentialError = errors.New(`
-more than one credential is available. List credentials with:
-
-    juju credentials
-
-and then run the add-model command again with the --credential flag.`[1:],
-)
-
-type findCredentialParams struct {
-	cloudTag    names.CloudTag
-	cloud       jujucloud.Cloud
-	cloudRegion string
-	modelOwner  string
-}
-
-// findCredential finds a suitable credential to use for the new model.
-// The credential will first be searched for locally and then on the
-// controller. If a credential is found locally then it's value will be
-// returned as the first return value. If it is found on the controller
-// this will be nil as there is no need to upload it in that case.
-func (c *addModelCommand) findCredential(ctx *cmd.Context, cloudClient CloudAPI, p *findCredentialParams) (_ *jujucloud.Credential, _ names.CloudCredentialTag, cloudRegion string, _ error) {
-	if c.CredentialName == "" {
-		return c.findUnspecifiedCredential(ctx, cloudClient, p)
-	}
-	return c.findSpecifiedCredential(ctx, cloudClient, p)
-}
-
-func (c *addModelCommand) findUnspecifiedCredential(ctx *cmd.Context, cloudClient CloudAPI, p *findCredentialParams) (_ *jujucloud.Credential, _ names.CloudCredentialTag, cloudRegion string, _ error) {
-	fail := func(err error) (*jujucloud.Credential, names.CloudCredentialTag, string, error) {
-		return nil, names.CloudCredentialTag{}, "", err
-	}
-	// If the user has not specified a credential, and the cloud advertises
-	// itself as supporting the "empty" auth-type, then return immediately.
-	for _, authType := range p.cloud.AuthTypes {
-		if authType == jujucloud.EmptyAuthType {
-			return nil, names.CloudCredentialTag{}, p.cloudRegion, nil
-		}
-	}
-
-	// No credential has been specified, so see if there is one already on the controller we can use.
-	modelOwnerTag := names.NewUserTag(p.modelOwner)
-	credentialTags, err := cloudClient.UserCredentials(modelOwnerTag, p.cloudTag)
-	if err != nil {
-		return fail(errors.Trace(err))
-	}
-	var credentialTag names.CloudCredentialTag
-	if len(credentialTags) == 1 {
-		credentialTag = credentialTags[0]
-	}
-
-	if (credentialTag != names.CloudCredentialTag{}) {
-		// If the controller already has a credential, see if
-		// there is a local version that has an associated
-		// region.
-		credential, _, cloudRegion, err := c.findLocalCredential(ctx, p, credentialTag.Name())
-		if errors.IsNotFound(err) {
-			// No local credential; use the region
-			// specified by the user, if any.
-			cloudRegion = p.cloudRegion
-		} else if err != nil {
-			return fail(errors.Trace(err))
-		}
-		// If there is a credential in the controller use it even if we don't have a local version.
-		return credential, credentialTag, cloudRegion, nil
-	}
-	// There is not a default credential on the controller (either
-	// there are no credentials, or there is more than one). Look for
-	// a local credential we might use.
-	credential, credentialName, cloudRegion, err := c.findLocalCredential(ctx, p, "")
-	if err != nil {
-		return fail(errors.Trace(err))
-	}
-	// We've got a local credential to use.
-	credentialTag, err = common.ResolveCloudCredentialTag(
-		modelOwnerTag, p.cloudTag, credentialName,
-	)
-	if err != nil {
-		return fail(errors.Trace(err))
-	}
-	return credential, credentialTag, cloudRegion, nil
-}
-
-func (c *addModelCommand) findSpecifiedCredential(ctx *cmd.Context, cloudClient CloudAPI, p *findCredentialParams) (_ *jujucloud.Credential, _ names.CloudCredentialTag, cloudRegion string, _ error) {
-	fail := func(err error) (*jujucloud.Credential, names.CloudCredentialTag, string, error) {
-		return nil, names.CloudCredentialTag{}, "", err
-	}
-	// Look for a local credential with the specified name
-	credential, credentialName, cloudRegion, err := c.findLocalCredential(ctx, p, c.CredentialName)
-	if err != nil && !errors.IsNotFound(err) {
-		return fail(errors.Trace(err))
-	}
-	if credential != nil {
-		// We found a local credential with the specified name.
-		modelOwnerTag := names.NewUserTag(p.modelOwner)
-		credentialTag, err := common.ResolveCloudCredentialTag(
-			modelOwnerTag, p.cloudTag, credentialName,
-		)
-		if err != nil {
-			return fail(errors.Trace(err))
-		}
-		return credential, credentialTag, cloudRegion, nil
-	}
-
-	// There was no local credential with that name, check the controller
-	modelOwnerTag := names.NewUserTag(p.modelOwner)
-	credentialTags, err := cloudClient.UserCredentials(modelOwnerTag, p.cloudTag)
-	if err != nil {
-		return fail(errors.Trace(err))
-	}
-	credentialTag, err := common.ResolveCloudCredentialTag(
-		modelOwnerTag, p.cloudTag, c.CredentialName,
-	)
-	if err != nil {
-		return fail(errors.Trace(err))
-	}
-	credentialId := credentialTag.Id()
-	for _, tag := range credentialTags {
-		if tag.Id() != credentialId {
-			continue
-		}
-		ctx.Infof("Using credential '%s' cached in controller", c.CredentialName)
-		return nil, credentialTag, "", nil
-	}
-	// Cannot find a credential with the correct name
-	return fail(errors.NotFoundf("credential '%s'", c.CredentialName))
-}
-
-func (c *addModelCommand) findLocalCredential(ctx *cmd.Context, p *findCredentialParams, name string) (_ *jujucloud.Credential, credentialName, cloudRegion string, _ error) {
-	fail := func(err error) (*jujucloud.Credential, string, string, error) {
-		return nil, "", "", err
-	}
-	provider, err := c.providerRegistry.Provider(p.cloud.Type)
-	if err != nil {
-		return fail(errors.Trace(err))
-	}
-	credential, credentialName, cloudRegion, _, err := common.GetOrDetectCredential(
-		ctx, c.ClientStore(), provider, modelcmd.GetCredentialsParams{
-			Cloud:          p.cloud,
-			CloudRegion:    p.cloudRegion,
-			CredentialName: name,
-		},
-	)
-	if err == nil {
-		return credential, credentialName, cloudRegion, nil
-	}
-	switch errors.Cause(err) {
-	case modelcmd.ErrMultipleCredentials:
-		return fail(ambiguousCredentialError)
-	case common.ErrMultipleDetectedCredentials:
-		return fail(ambiguousDetectedCredentialError)
-	}
-	return fail(errors.Trace(err))
-}
-
 func (c *addModelCommand) getConfigValues(ctx *cmd.Context) (map[string]interface{}, error) {
 	configValues, err := c.Config.ReadAttrs(ctx)
 	if err != nil {
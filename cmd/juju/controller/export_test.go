@@ -27,10 +27,15 @@ func NewListControllersCommandForTest(testStore jujuclient.ClientStore, api func
 
 // NewShowControllerCommandForTest returns a showControllerCommand with the clientstore provided
 // as specified.
-func NewShowControllerCommandForTest(testStore jujuclient.ClientStore, api func(string) ControllerAccessAPI) *showControllerCommand {
+func NewShowControllerCommandForTest(
+	testStore jujuclient.ClientStore,
+	api func(string) ControllerAccessAPI,
+	dialAPI func(string) error,
+) *showControllerCommand {
 	return &showControllerCommand{
-		store: testStore,
-		api:   api,
+		store:   testStore,
+		api:     api,
+		dialAPI: dialAPI,
 	}
 }
 
@@ -48,6 +48,7 @@ type modelsCommand struct {
 	user         string
 	listUUID     bool
 	exactTime    bool
+	usage        bool
 	modelAPI     ModelManagerAPI
 	sysAPI       ModelsSysAPI
 
@@ -71,6 +72,7 @@ func (c *modelsCommand) SetFlags(f *gnuflag.FlagSet) {
 	f.BoolVar(&c.all, "all", false, "Lists all models, regardless of user accessibility (administrative users only)")
 	f.BoolVar(&c.listUUID, "uuid", false, "Display UUID for models")
 	f.BoolVar(&c.exactTime, "exact-time", false, "Use full timestamps")
+	f.BoolVar(&c.usage, "usage", false, "Display resource usage and last activity for models")
 	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
 		"yaml":    cmd.FormatYaml,
 		"json":    cmd.FormatJson,
@@ -116,6 +118,11 @@ func (c *modelsCommand) Run(ctx *cmd.Context) error {
 	defer modelmanagerAPI.Close()
 
 	haveModels := false
+	if c.usage && modelmanagerAPI.BestAPIVersion() <= 3 {
+		err := errors.New("--usage is not supported by this controller")
+		ctx.Infof(err.Error())
+		return err
+	}
 	if modelmanagerAPI.BestAPIVersion() > 3 {
 		haveModels, err = c.getModelSummaries(ctx, modelmanagerAPI, now)
 		if err != nil {
@@ -244,6 +251,19 @@ type ModelSummary struct {
 	SLA          string           `json:"sla,omitempty" yaml:"sla,omitempty"`
 	SLAOwner     string           `json:"sla-owner,omitempty" yaml:"sla-owner,omitempty"`
 	AgentVersion string           `json:"agent-version,omitempty" yaml:"agent-version,omitempty"`
+
+	// Usage is only reported when asked for with --usage.
+	Usage *ModelUsage `json:"usage,omitempty" yaml:"usage,omitempty"`
+}
+
+// ModelUsage summarises the resources used by a model and when it was
+// last used, to help find models which have been abandoned.
+type ModelUsage struct {
+	Machines     int64  `json:"machines" yaml:"machines"`
+	Cores        int64  `json:"cores" yaml:"cores"`
+	Memory       string `json:"memory" yaml:"memory"`
+	Units        int64  `json:"units" yaml:"units"`
+	LastActivity string `json:"last-activity" yaml:"last-activity"`
 }
 
 func (c *modelsCommand) modelSummaryFromParams(apiSummary base.UserModelSummary, now time.Time) (ModelSummary, error) {
@@ -294,6 +314,18 @@ func (c *modelsCommand) modelSummaryFromParams(apiSummary base.UserModelSummary,
 	for _, v := range apiSummary.Counts {
 		summary.Counts[string(v.Entity)] = v.Count
 	}
+	if c.usage {
+		summary.Usage = &ModelUsage{
+			Machines:     summary.Counts[string(params.Machines)],
+			Cores:        summary.Counts[string(params.Cores)],
+			Memory:       fmt.Sprintf("%dM", summary.Counts[string(params.Memory)]),
+			Units:        summary.Counts[string(params.Units)],
+			LastActivity: "never connected",
+		}
+		if apiSummary.LastConnection != nil {
+			summary.Usage.LastActivity = common.UserFriendlyDuration(*apiSummary.LastConnection, now)
+		}
+	}
 
 	// If hasMachinesCounts is not yet set, check if we should set it based on this model summary.
 	if !c.runVars.hasMachinesCount {
@@ -349,13 +381,20 @@ func (c *modelsCommand) tabularColumns(tw *ansiterm.TabWriter, w output.Wrapper)
 		tw.SetColumnAlignRight(columnNumber + offset)
 	}
 
-	if c.runVars.hasMachinesCount {
+	if c.runVars.hasMachinesCount || c.usage {
 		printColumnHeader("Machines", 3)
 	}
 
-	if c.runVars.hasCoresCount {
+	if c.runVars.hasCoresCount || c.usage {
 		printColumnHeader("Cores", 4)
 	}
+
+	if c.usage {
+		printColumnHeader("Memory", 5)
+		printColumnHeader("Units", 6)
+		w.Println("Access", "Last connection", "Last activity")
+		return
+	}
 	w.Println("Access", "Last connection")
 }
 
@@ -387,24 +426,36 @@ func (c *modelsCommand) tabularSummaries(writer io.Writer, modelSet ModelSummary
 			status = model.Status.Current.String()
 		}
 		w.Print(cloudRegion, status)
-		if c.runVars.hasMachinesCount {
+		if c.runVars.hasMachinesCount || c.usage {
 			if v, ok := model.Counts[string(params.Machines)]; ok {
 				w.Print(v)
 			} else {
 				w.Print(0)
 			}
 		}
-		if c.runVars.hasCoresCount {
+		if c.runVars.hasCoresCount || c.usage {
 			if v, ok := model.Counts[string(params.Cores)]; ok {
 				w.Print(v)
 			} else {
 				w.Print("-")
 			}
 		}
+		if c.usage {
+			if _, ok := model.Counts[string(params.Memory)]; ok {
+				w.Print(model.Usage.Memory)
+			} else {
+				w.Print("-")
+			}
+			w.Print(model.Usage.Units)
+		}
 		access := model.UserAccess
 		if access == "" {
 			access = "-"
 		}
+		if c.usage {
+			w.Println(access, model.UserLastConnection, model.Usage.LastActivity)
+			continue
+		}
 		w.Println(access, model.UserLastConnection)
 	}
 	tw.Flush()
@@ -417,10 +468,15 @@ models which have been shared with you. Default values for user and
 controller are, respectively, the current user and the current controller.
 The active model is denoted by an asterisk.
 
+The --usage option adds the number of machines, cores, memory and units
+of each model, and when any user last connected to it. This helps to
+find models which are no longer being used.
+
 Examples:
 
     juju models
     juju models --user bob
+    juju models --all --usage
 
 See also:
     add-model
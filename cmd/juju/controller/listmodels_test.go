@@ -48,6 +48,11 @@ type fakeModelMgrAPIClient struct {
 	err   error
 	infos []params.ModelInfoResult
 
+	// counts and lastConnections hold extra summary details,
+	// keyed by model name.
+	counts          map[string][]base.EntityCount
+	lastConnections map[string]*time.Time
+
 	version int
 }
 
@@ -160,6 +165,8 @@ func (f *fakeModelMgrAPIClient) ListModelSummaries(user string, all bool) ([]bas
 				results[i].Counts = append(results[i].Counts, base.EntityCount{string(params.Cores), int64(cores)})
 			}
 		}
+		results[i].Counts = append(results[i].Counts, f.counts[info.Result.Name]...)
+		results[i].LastConnection = f.lastConnections[info.Result.Name]
 	}
 	return results, nil
 }
@@ -739,3 +746,58 @@ func (s *ModelsSuiteV4) TestAllModels(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 	assertAPICallsArgs(false)
 }
+
+func (s *ModelsSuiteV4) TestModelsUsage(c *gc.C) {
+	one := uint64(1)
+	s.api.infos[0].Result.Machines = []params.ModelMachineInfo{
+		{Id: "0", Hardware: &params.MachineHardware{Cores: &one}}, {Id: "1"},
+	}
+	s.api.counts = map[string][]base.EntityCount{
+		"test-model1": {
+			{string(params.Memory), 4096},
+			{string(params.Units), 3},
+		},
+	}
+	last := time.Date(2015, 3, 21, 0, 0, 0, 0, time.UTC)
+	s.api.lastConnections = map[string]*time.Time{"test-model1": &last}
+
+	context, err := cmdtesting.RunCommand(c, s.newCommand(), "--usage")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(context), gc.Equals, ""+
+		"Controller: fake\n"+
+		"\n"+
+		"Model                        Cloud/Region  Status      Machines  Cores  Memory  Units  Access  Last connection  Last activity\n"+
+		"test-model1*                 dummy         active             2      1   4096M      3  read    2015-03-20       2015-03-21\n"+
+		"carlotta/test-model2         dummy         active             0      -       -      0  write   2015-03-01       never connected\n"+
+		"daiwik@external/test-model3  dummy         destroying         0      -       -      0  -       never connected  never connected\n"+
+		"\n")
+	c.Assert(cmdtesting.Stderr(context), gc.Equals, "")
+}
+
+func (s *ModelsSuiteV4) TestModelsUsageYaml(c *gc.C) {
+	s.api.infos = s.api.infos[:1]
+	s.api.counts = map[string][]base.EntityCount{
+		"test-model1": {
+			{string(params.Machines), 2},
+			{string(params.Cores), 4},
+			{string(params.Memory), 4096},
+			{string(params.Units), 3},
+		},
+	}
+
+	context, err := cmdtesting.RunCommand(c, s.newCommand(), "--usage", "--format", "yaml")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(context), jc.Contains, `
+  usage:
+    machines: 2
+    cores: 4
+    memory: 4096M
+    units: 3
+    last-activity: never connected
+`[1:])
+}
+
+func (s *ModelsSuiteV3) TestModelsUsageNotSupported(c *gc.C) {
+	_, err := cmdtesting.RunCommand(c, s.newCommand(), "--usage")
+	c.Assert(err, gc.ErrorMatches, "--usage is not supported by this controller")
+}
@@ -19,6 +19,7 @@ import (
 
 	"github.com/juju/cmd"
 	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
 	"github.com/juju/utils"
 	"github.com/juju/utils/set"
 	"golang.org/x/crypto/nacl/secretbox"
@@ -31,6 +32,7 @@ import (
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/cmd/juju/common"
 	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/juju/osenv"
 	"github.com/juju/juju/jujuclient"
 	"github.com/juju/juju/permission"
 )
@@ -59,6 +61,18 @@ type registerCommand struct {
 	store          jujuclient.ClientStore
 	Arg            string
 
+	// controllerName holds the name to register the controller
+	// under, if specified with --name or $JUJU_CONTROLLER_NAME.
+	controllerName string
+
+	// passwordFile holds the file from which to read the new
+	// password, if specified.
+	passwordFile cmd.FileVar
+
+	// noPrompt causes the command to fail, rather than prompt,
+	// when the controller name or password are not specified.
+	noPrompt bool
+
 	// onRunError is executed if non-nil if there is an error at the end
 	// of the Run method.
 	onRunError func()
@@ -86,12 +100,21 @@ When adding a controller at a public address, authentication via some
 external third party (for example Ubuntu SSO) will be required, usually
 by using a web browser.
 
+To register a controller from a script, the controller name may be given
+with --name (or the JUJU_CONTROLLER_NAME environment variable) and the
+new password read from a file with --password-file ("-" reads it from
+standard input). With --no-prompt, the command fails instead of asking
+for anything that was not specified; the name proposed by the
+registration string is used if no name is given.
+
 Examples:
 
     juju register MFATA3JvZDAnExMxMDQuMTU0LjQyLjQ0OjE3MDcwExAxMC4xMjguMC4yOjE3MDcwBCBEFCaXerhNImkKKabuX5ULWf2Bp4AzPNJEbXVWgraLrAA=
 
     juju register public-controller.example.com
 
+    juju register --name ci --password-file ~/.ci-password --no-prompt MFATA3JvZDAnExMxMDQuMTU0LjQyLjQ0OjE3MDcwExAxMC4xMjguMC4yOjE3MDcwBCBEFCaXerhNImkKKabuX5ULWf2Bp4AzPNJEbXVWgraLrAA=
+
 See also: 
     add-user
     change-user-password
@@ -109,7 +132,15 @@ func (c *registerCommand) Info() *cmd.Info {
 	}
 }
 
-// SetFlags implements Command.Init.
+// SetFlags implements Command.SetFlags.
+func (c *registerCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.CommandBase.SetFlags(f)
+	f.StringVar(&c.controllerName, "name", "", "Name to register the controller under")
+	f.Var(&c.passwordFile, "password-file", "File containing the new password")
+	f.BoolVar(&c.noPrompt, "no-prompt", false, "Fail rather than prompt for missing details")
+}
+
+// Init implements Command.Init.
 func (c *registerCommand) Init(args []string) error {
 	if len(args) < 1 {
 		return errors.New("registration data missing")
@@ -118,6 +149,9 @@ func (c *registerCommand) Init(args []string) error {
 	if err := cmd.CheckEmpty(args); err != nil {
 		return errors.Trace(err)
 	}
+	if c.controllerName == "" {
+		c.controllerName = os.Getenv(osenv.JujuControllerNameEnvKey)
+	}
 	return nil
 }
 
@@ -136,7 +170,7 @@ func (c *registerCommand) run(ctx *cmd.Context) error {
 	if err != nil {
 		return errors.Trace(err)
 	}
-	controllerName, err := c.promptControllerName(registrationParams.defaultControllerName, ctx.Stderr, ctx.Stdin)
+	controllerName, err := c.getControllerName(registrationParams.defaultControllerName, ctx.Stderr, ctx.Stdin)
 	if err != nil {
 		return errors.Trace(err)
 	}
@@ -456,8 +490,8 @@ func (c *registerCommand) getParameters(ctx *cmd.Context) (*registrationParams,
 	copy(params.key[:], info.SecretKey)
 	params.defaultControllerName = info.ControllerName
 
-	// Prompt the user for the new password to set.
-	newPassword, err := c.promptNewPassword(ctx.Stderr, ctx.Stdin)
+	// Get the new password to set, prompting if necessary.
+	newPassword, err := c.getNewPassword(ctx)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
@@ -534,6 +568,28 @@ func (c *registerCommand) secretKeyLogin(addrs []string, request params.SecretKe
 	return &resp, nil
 }
 
+// getNewPassword returns the new password for the user, read from
+// the password file if one was specified.
+func (c *registerCommand) getNewPassword(ctx *cmd.Context) (string, error) {
+	if c.passwordFile.Path == "" {
+		if c.noPrompt {
+			return "", errors.New("no password specified; use --password-file")
+		}
+		return c.promptNewPassword(ctx.Stderr, ctx.Stdin)
+	}
+	data, err := c.passwordFile.Read(ctx)
+	if err != nil {
+		return "", errors.Annotatef(err, "cannot read password")
+	}
+	// Only the trailing newline is removed; any other
+	// whitespace is part of the password.
+	password := strings.TrimSuffix(strings.TrimSuffix(string(data), "\n"), "\r")
+	if password == "" {
+		return "", errors.NewNotValid(nil, "you must specify a non-empty password")
+	}
+	return password, nil
+}
+
 func (c *registerCommand) promptNewPassword(stderr io.Writer, stdin io.Reader) (string, error) {
 	password, err := c.readPassword("Enter a new password: ", stderr, stdin)
 	if err != nil {
@@ -552,6 +608,28 @@ func (c *registerCommand) promptNewPassword(stderr io.Writer, stdin io.Reader) (
 	return password, nil
 }
 
+// getControllerName returns the name to register the controller
+// under. A name specified on the command line is used if given;
+// otherwise the suggested name is used if prompting is disabled.
+func (c *registerCommand) getControllerName(suggestedName string, stderr io.Writer, stdin io.Reader) (string, error) {
+	name := c.controllerName
+	if name == "" {
+		if !c.noPrompt {
+			return c.promptControllerName(suggestedName, stderr, stdin)
+		}
+		if suggestedName == "" {
+			return "", errors.New("no controller name specified; use --name")
+		}
+		name = suggestedName
+	}
+	if _, err := c.store.ControllerByName(name); err == nil {
+		return "", errors.AlreadyExistsf("controller %q", name)
+	} else if !errors.IsNotFound(err) {
+		return "", errors.Trace(err)
+	}
+	return name, nil
+}
+
 func (c *registerCommand) promptControllerName(suggestedName string, stderr io.Writer, stdin io.Reader) (string, error) {
 	if suggestedName != "" {
 		if _, err := c.store.ControllerByName(suggestedName); err == nil {
@@ -12,6 +12,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"path/filepath"
 	"strings"
 
 	"github.com/juju/cmd"
@@ -166,8 +167,9 @@ one of them:
 // stdio instance is used for input and output. If stdio is nil, a
 // default prompter will be used.
 // If controllerName is non-empty, that name will be expected
-// to be the name of the registered controller.
-func (s *RegisterSuite) testRegisterSuccess(c *gc.C, stdio io.ReadWriter, controllerName string) {
+// to be the name of the registered controller. Any extra arguments
+// are passed to the command before the registration data.
+func (s *RegisterSuite) testRegisterSuccess(c *gc.C, stdio io.ReadWriter, controllerName string, args ...string) {
 	srv := s.mockServer(c)
 	s.httpHandler = srv
 
@@ -195,7 +197,7 @@ Welcome, bob. You are now logged into "controller-name".
 		defer prompter.CheckDone()
 		stdio = prompter
 	}
-	err := s.run(c, stdio, registrationData)
+	err := s.run(c, stdio, append(args, registrationData)...)
 	c.Assert(err, jc.ErrorIsNil)
 
 	// There should have been one POST command to "/register".
@@ -233,6 +235,103 @@ Welcome, bob. You are now logged into "controller-name".
 	})
 }
 
+func (s *RegisterSuite) writePasswordFile(c *gc.C, password string) string {
+	path := filepath.Join(c.MkDir(), "password")
+	err := ioutil.WriteFile(path, []byte(password), 0600)
+	c.Assert(err, jc.ErrorIsNil)
+	return path
+}
+
+func (s *RegisterSuite) TestRegisterNoPrompt(c *gc.C) {
+	prompter := cmdtesting.NewSeqPrompter(c, "»", `
+Initial password successfully set for bob.
+
+Welcome, bob. You are now logged into "other-name".
+`[1:]+noModelsText)
+	defer prompter.CheckDone()
+	s.testRegisterSuccess(c, prompter, "other-name",
+		"--name", "other-name",
+		"--password-file", s.writePasswordFile(c, "hunter2\n"),
+		"--no-prompt",
+	)
+}
+
+func (s *RegisterSuite) TestRegisterNoPromptSuggestedName(c *gc.C) {
+	prompter := cmdtesting.NewSeqPrompter(c, "»", `
+Initial password successfully set for bob.
+
+Welcome, bob. You are now logged into "controller-name".
+`[1:]+noModelsText)
+	defer prompter.CheckDone()
+	s.testRegisterSuccess(c, prompter, "",
+		"--password-file", s.writePasswordFile(c, "hunter2"),
+		"--no-prompt",
+	)
+}
+
+func (s *RegisterSuite) TestRegisterControllerNameFromEnvironment(c *gc.C) {
+	s.PatchEnvironment("JUJU_CONTROLLER_NAME", "env-name")
+	prompter := cmdtesting.NewSeqPrompter(c, "»", `
+Enter a new password: »hunter2
+
+Confirm password: »hunter2
+
+Initial password successfully set for bob.
+
+Welcome, bob. You are now logged into "env-name".
+`[1:]+noModelsText)
+	defer prompter.CheckDone()
+	s.testRegisterSuccess(c, prompter, "env-name")
+}
+
+func (s *RegisterSuite) TestRegisterNoPromptNoPassword(c *gc.C) {
+	registrationData := s.encodeRegistrationData(c, jujuclient.RegistrationInfo{
+		User:           "bob",
+		SecretKey:      mockSecretKey,
+		ControllerName: "controller-name",
+	})
+	err := s.run(c, nil, "--no-prompt", registrationData)
+	c.Assert(err, gc.ErrorMatches, "no password specified; use --password-file")
+}
+
+func (s *RegisterSuite) TestRegisterNoPromptNoControllerName(c *gc.C) {
+	srv := s.mockServer(c)
+	s.httpHandler = srv
+	registrationData := s.encodeRegistrationData(c, jujuclient.RegistrationInfo{
+		User:      "bob",
+		SecretKey: mockSecretKey,
+	})
+	passwordFile := s.writePasswordFile(c, "hunter2")
+	err := s.run(c, nil, "--no-prompt", "--password-file", passwordFile, registrationData)
+	c.Assert(err, gc.ErrorMatches, "no controller name specified; use --name")
+	c.Assert(srv.requests, gc.HasLen, 0)
+}
+
+func (s *RegisterSuite) TestRegisterSpecifiedControllerNameExists(c *gc.C) {
+	err := s.store.AddController("controller-name", jujuclient.ControllerDetails{
+		ControllerUUID: "0d75314a-5266-4f4f-8523-415be76f92dc",
+		CACert:         testing.CACert,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	registrationData := s.encodeRegistrationData(c, jujuclient.RegistrationInfo{
+		User:      "bob",
+		SecretKey: mockSecretKey,
+	})
+	passwordFile := s.writePasswordFile(c, "hunter2")
+	err = s.run(c, nil, "--name", "controller-name", "--password-file", passwordFile, registrationData)
+	c.Assert(err, gc.ErrorMatches, `controller "controller-name" already exists`)
+}
+
+func (s *RegisterSuite) TestRegisterEmptyPasswordFile(c *gc.C) {
+	registrationData := s.encodeRegistrationData(c, jujuclient.RegistrationInfo{
+		User:      "bob",
+		SecretKey: mockSecretKey,
+	})
+	passwordFile := s.writePasswordFile(c, "\n")
+	err := s.run(c, nil, "--password-file", passwordFile, registrationData)
+	c.Assert(err, gc.ErrorMatches, "you must specify a non-empty password")
+}
+
 func (s *RegisterSuite) TestRegisterInvalidRegistrationData(c *gc.C) {
 	err := s.run(c, nil, "not base64")
 	c.Assert(err, gc.ErrorMatches, "illegal base64 data at input byte 3")
@@ -5,11 +5,15 @@ package controller
 
 import (
 	"fmt"
+	"net"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/juju/cmd"
 	"github.com/juju/errors"
 	"github.com/juju/gnuflag"
+	"github.com/juju/version"
 	"gopkg.in/juju/names.v2"
 
 	"github.com/juju/juju/api/base"
@@ -29,6 +33,12 @@ var usageShowControllerDetails = `
 Shows extended information about a controller(s) as well as related models
 and user login details.
 
+For each controller machine, the agent version and the role and health of
+its MongoDB replicaset member are shown, along with whether each of its API
+addresses can be reached from this client. Machines whose agents are not yet
+running the controller's agent version are marked as having an upgrade
+pending.
+
 Examples:
     juju show-controller
     juju show-controller aws google
@@ -44,6 +54,9 @@ type showControllerCommand struct {
 	mu    sync.Mutex
 	api   func(controllerName string) ControllerAccessAPI
 
+	// dialAPI checks whether an API address can be reached.
+	dialAPI func(addr string) error
+
 	controllerNames []string
 	showPasswords   bool
 }
@@ -164,6 +177,7 @@ func (c *showControllerCommand) Run(ctx *cmd.Context) error {
 		}
 
 		c.convertControllerForShow(&details, controllerName, one, access, allModels, modelStatusResults)
+		c.checkAPIAddresses(&details)
 		controllers[controllerName] = details
 		machineCount := 0
 		for _, r := range modelStatusResults {
@@ -272,6 +286,25 @@ type MachineDetails struct {
 
 	// HAStatus holds information informing of the HA status of the machine.
 	HAStatus string `yaml:"ha-status,omitempty" json:"ha-status,omitempty"`
+
+	// AgentVersion holds the version of the machine's agent.
+	AgentVersion string `yaml:"agent-version,omitempty" json:"agent-version,omitempty"`
+
+	// UpgradePending is set when the machine's agent is not yet
+	// running the controller's agent version.
+	UpgradePending bool `yaml:"upgrade-pending,omitempty" json:"upgrade-pending,omitempty"`
+
+	// ReplicaSetRole holds the role of the machine's MongoDB
+	// replicaset member, e.g. primary or secondary.
+	ReplicaSetRole string `yaml:"replicaset-role,omitempty" json:"replicaset-role,omitempty"`
+
+	// ReplicaSetHealth holds the health of the machine's MongoDB
+	// replicaset member.
+	ReplicaSetHealth string `yaml:"replicaset-health,omitempty" json:"replicaset-health,omitempty"`
+
+	// APIAddresses maps each of the machine's API addresses to
+	// whether it can be reached from this client.
+	APIAddresses map[string]string `yaml:"api-addresses,omitempty" json:"api-addresses,omitempty"`
 }
 
 // ModelDetails holds details of a model to show.
@@ -340,7 +373,7 @@ func (c *showControllerCommand) convertControllerForShow(
 			}
 		}
 		if found {
-			c.convertMachinesForShow(controllerName, controller, controllerModel)
+			c.convertMachinesForShow(controllerName, controller, controllerModel, details.AgentVersion)
 		}
 	}
 }
@@ -400,7 +433,14 @@ func (c *showControllerCommand) convertMachinesForShow(
 	controllerName string,
 	controller *ShowControllerDetails,
 	controllerModel base.ModelStatus,
+	agentVersion string,
 ) {
+	targetVersion, err := version.Parse(agentVersion)
+	if err != nil {
+		// The agent version could not be determined, so
+		// there is nothing to compare the machines' with.
+		targetVersion = version.Zero
+	}
 	controller.Machines = make(map[string]MachineDetails)
 	numControllers := 0
 	for _, m := range controllerModel.Machines {
@@ -418,10 +458,30 @@ func (c *showControllerCommand) convertMachinesForShow(
 		if instId == "" {
 			instId = "(unprovisioned)"
 		}
-		details := MachineDetails{InstanceID: instId}
+		details := MachineDetails{
+			InstanceID:   instId,
+			AgentVersion: m.AgentVersion,
+		}
 		if numControllers > 1 {
 			details.HAStatus = haStatus(m.HasVote, m.WantsVote, m.Status)
 		}
+		if targetVersion != version.Zero && m.AgentVersion != "" {
+			machineVersion, err := version.Parse(m.AgentVersion)
+			details.UpgradePending = err == nil && machineVersion != targetVersion
+		}
+		if m.ReplicaSetState != "" {
+			details.ReplicaSetRole = strings.ToLower(m.ReplicaSetState)
+			details.ReplicaSetHealth = "unhealthy"
+			if m.ReplicaSetHealthy {
+				details.ReplicaSetHealth = "healthy"
+			}
+		}
+		if len(m.APIAddresses) > 0 {
+			details.APIAddresses = make(map[string]string)
+			for _, addr := range m.APIAddresses {
+				details.APIAddresses[addr] = ""
+			}
+		}
 		controller.Machines[m.Id] = details
 	}
 }
@@ -438,3 +498,49 @@ func haStatus(hasVote bool, wantsVote bool, statusStr string) string {
 	}
 	return "ha-pending"
 }
+
+// apiDialTimeout is how long to wait when checking whether a
+// controller machine's API address can be reached.
+const apiDialTimeout = 5 * time.Second
+
+// dialAPIAddress checks whether a TCP connection can be made
+// to the API address.
+func dialAPIAddress(addr string) error {
+	conn, err := net.DialTimeout("tcp", addr, apiDialTimeout)
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
+
+// checkAPIAddresses records whether each of the controller
+// machines' API addresses can be reached from this client.
+// The addresses are dialled concurrently so that unreachable
+// machines do not hold up the command for long.
+func (c *showControllerCommand) checkAPIAddresses(controller *ShowControllerDetails) {
+	dial := c.dialAPI
+	if dial == nil {
+		dial = dialAPIAddress
+	}
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+	for _, machine := range controller.Machines {
+		for addr := range machine.APIAddresses {
+			wg.Add(1)
+			go func(addresses map[string]string, addr string) {
+				defer wg.Done()
+				reachability := "reachable"
+				if err := dial(addr); err != nil {
+					logger.Debugf("cannot reach API address %s: %v", addr, err)
+					reachability = "unreachable"
+				}
+				mu.Lock()
+				addresses[addr] = reachability
+				mu.Unlock()
+			}(machine.APIAddresses, addr)
+		}
+	}
+	wg.Wait()
+}
@@ -5,6 +5,8 @@ package controller_test
 
 import (
 	"regexp"
+	"strings"
+	"sync"
 
 	"github.com/juju/cmd"
 	"github.com/juju/cmd/cmdtesting"
@@ -24,6 +26,8 @@ type ShowControllerSuite struct {
 	baseControllerSuite
 	fakeController *fakeController
 	api            func(string) controller.ControllerAccessAPI
+	mu             sync.Mutex
+	dialed         []string
 }
 
 var _ = gc.Suite(&ShowControllerSuite{})
@@ -44,6 +48,17 @@ func (s *ShowControllerSuite) SetUpTest(c *gc.C) {
 		s.fakeController.controllerName = controllerName
 		return s.fakeController
 	}
+	s.dialed = nil
+}
+
+func (s *ShowControllerSuite) dialAPI(addr string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.dialed = append(s.dialed, addr)
+	if strings.HasPrefix(addr, "10.0.0.2:") {
+		return errors.New("connection refused")
+	}
+	return nil
 }
 
 func (s *ShowControllerSuite) TestShowOneControllerOneInStore(c *gc.C) {
@@ -252,6 +267,59 @@ mark-test-prodstack:
 	s.assertShowController(c, "aws-test", "mark-test-prodstack")
 }
 
+func (s *ShowControllerSuite) TestShowControllerMachineDetails(c *gc.C) {
+	s.fakeController.machines["ghi"] = []base.Machine{{
+		Id: "0", InstanceId: "id-0", HasVote: true, WantsVote: true, Status: "active",
+		AgentVersion: "999.99.99", ReplicaSetState: "PRIMARY", ReplicaSetHealthy: true,
+		APIAddresses: []string{"10.0.0.1:17070"},
+	}, {
+		Id: "1", InstanceId: "id-1", HasVote: true, WantsVote: true, Status: "down",
+		AgentVersion: "999.99.98", ReplicaSetState: "SECONDARY",
+		APIAddresses: []string{"10.0.0.2:17070"},
+	}}
+	s.createTestClientStore(c)
+
+	s.expectedOutput = `
+aws-test:
+  details:
+    uuid: this-is-the-aws-test-uuid
+    api-endpoints: [this-is-aws-test-of-many-api-endpoints]
+    ca-cert: this-is-aws-test-ca-cert
+    cloud: aws
+    region: us-east-1
+    agent-version: 999.99.99
+  controller-machines:
+    "0":
+      instance-id: id-0
+      ha-status: ha-enabled
+      agent-version: 999.99.99
+      replicaset-role: primary
+      replicaset-health: healthy
+      api-addresses:
+        10.0.0.1:17070: reachable
+    "1":
+      instance-id: id-1
+      ha-status: down, lost connection
+      agent-version: 999.99.98
+      upgrade-pending: true
+      replicaset-role: secondary
+      replicaset-health: unhealthy
+      api-addresses:
+        10.0.0.2:17070: unreachable
+  models:
+    controller:
+      uuid: ghi
+      machine-count: 2
+      core-count: 4
+  current-model: admin/controller
+  account:
+    user: admin
+    access: superuser
+`[1:]
+	s.assertShowController(c, "aws-test")
+	c.Assert(s.dialed, jc.SameContents, []string{"10.0.0.1:17070", "10.0.0.2:17070"})
+}
+
 func (s *ShowControllerSuite) TestShowControllerJsonOne(c *gc.C) {
 	s.createTestClientStore(c)
 
@@ -351,7 +419,7 @@ func (s *ShowControllerSuite) TestShowControllerRefreshesStoreModels(c *gc.C) {
 }
 
 func (s *ShowControllerSuite) runShowController(c *gc.C, args ...string) (*cmd.Context, error) {
-	return cmdtesting.RunCommand(c, controller.NewShowControllerCommandForTest(s.store, s.api), args...)
+	return cmdtesting.RunCommand(c, controller.NewShowControllerCommandForTest(s.store, s.api, s.dialAPI), args...)
 }
 
 func (s *ShowControllerSuite) assertShowControllerFailed(c *gc.C, args ...string) {
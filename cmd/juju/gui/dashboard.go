@@ -0,0 +1,189 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package gui
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net/url"
+	"os"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+	"github.com/juju/loggo"
+	"github.com/juju/utils"
+	"github.com/juju/webbrowser"
+
+	"github.com/juju/juju/api"
+	"github.com/juju/juju/cmd/modelcmd"
+)
+
+var logger = loggo.GetLogger("juju.cmd.juju.gui")
+
+// NewDashboardCommand creates and returns a new dashboard command.
+func NewDashboardCommand() cmd.Command {
+	return modelcmd.Wrap(&dashboardCommand{})
+}
+
+// dashboardCommand opens the Juju GUI through a tunnel
+// to the controller.
+type dashboardCommand struct {
+	modelcmd.ModelCommandBase
+
+	port      int
+	noBrowser bool
+}
+
+const dashboardDoc = `
+Open the Juju GUI for the current model in the default browser:
+
+	juju dashboard
+
+The GUI is reached through a tunnel from a port on this machine to the
+controller, so the controller does not need to be reachable from the
+browser's network. Both the browser's connection to the tunnel and the
+tunnel's connection to the controller are encrypted. The tunnel only
+serves the browser it is opened in: the URL includes a single-use token,
+which the browser exchanges for a cookie on its first request. The
+tunnel stays open until the command is interrupted with Ctrl-C.
+
+Log into the GUI with the current user's password, which this command
+never prints. If you do not know it, set a new one with
+'juju change-user-password'.
+
+Print the tunnelled URL without opening the browser, and listen on a
+fixed port:
+
+	juju dashboard --no-browser --port 31666
+
+An error is returned if the Juju GUI is not available in the controller.
+
+See also:
+    change-user-password
+    gui
+`
+
+// Info implements the cmd.Command interface.
+func (c *dashboardCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "dashboard",
+		Purpose: "Open the Juju GUI in the default browser through a tunnel to the controller.",
+		Doc:     dashboardDoc,
+	}
+}
+
+// SetFlags implements the cmd.Command interface.
+func (c *dashboardCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.ModelCommandBase.SetFlags(f)
+	f.IntVar(&c.port, "port", 0, "Local port for the tunnel (default: any free port)")
+	f.BoolVar(&c.noBrowser, "no-browser", false, "Print the tunnelled URL instead of opening the web browser")
+}
+
+// Init implements the cmd.Command interface.
+func (c *dashboardCommand) Init(args []string) error {
+	if c.port < 0 || c.port > 65535 {
+		return errors.NotValidf("port %d", c.port)
+	}
+	return cmd.CheckEmpty(args)
+}
+
+// Run implements the cmd.Command interface.
+func (c *dashboardCommand) Run(ctx *cmd.Context) error {
+	conn, err := c.NewControllerAPIRoot()
+	if err != nil {
+		return errors.Annotate(err, "cannot establish API connection")
+	}
+	defer conn.Close()
+
+	guiURL, err := findGUIURL(&c.ModelCommandBase, conn)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	u, err := url.Parse(guiURL)
+	if err != nil {
+		return errors.Annotate(err, "cannot parse Juju GUI URL")
+	}
+
+	tlsConfig, err := c.controllerTLSConfig()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	t, err := newTunnel(fmt.Sprintf("127.0.0.1:%d", c.port), u.Host, tlsConfig)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer t.Close()
+
+	if err := c.openBrowser(ctx, t.URL(u)); err != nil {
+		return errors.Trace(err)
+	}
+	if err := c.printUser(ctx); err != nil {
+		return errors.Trace(err)
+	}
+	ctx.Infof("Press Ctrl-C to close the tunnel.")
+	waitForInterrupt(ctx)
+	return nil
+}
+
+// controllerTLSConfig returns the TLS configuration
+// for the tunnel's connections to the controller.
+func (c *dashboardCommand) controllerTLSConfig() (*tls.Config, error) {
+	controllerName, err := c.ControllerName()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	details, err := c.ClientStore().ControllerByName(controllerName)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	certPool, err := api.CreateCertPool(details.CACert)
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot load controller CA certificate")
+	}
+	tlsConfig := utils.SecureTLSConfig()
+	tlsConfig.RootCAs = certPool
+	// The controller's certificate is always valid for this name.
+	tlsConfig.ServerName = "juju-apiserver"
+	return tlsConfig, nil
+}
+
+// openBrowser opens the tunnelled Juju GUI URL,
+// or just prints it if requested.
+func (c *dashboardCommand) openBrowser(ctx *cmd.Context, u *url.URL) error {
+	if c.noBrowser {
+		ctx.Infof("Juju GUI is available through a tunnel at:\n  %s", u)
+		return nil
+	}
+	err := webbrowserOpen(u)
+	if err == nil {
+		ctx.Infof("Opening the Juju GUI in your browser.")
+		ctx.Infof("If it does not open, open this URL:\n%s", u)
+		return nil
+	}
+	if err == webbrowser.ErrNoBrowser {
+		ctx.Infof("Open this URL in your browser:\n%s", u)
+		return nil
+	}
+	return errors.Annotate(err, "cannot open web browser")
+}
+
+// printUser tells the user who to log into the GUI as.
+func (c *dashboardCommand) printUser(ctx *cmd.Context) error {
+	accountDetails, err := c.CurrentAccountDetails()
+	if err != nil {
+		return errors.Annotate(err, "cannot retrieve account details")
+	}
+	ctx.Infof("Log in as %s with your Juju password.", accountDetails.User)
+	return nil
+}
+
+// waitForInterrupt blocks until the command is interrupted.
+// It is defined for testing purposes.
+var waitForInterrupt = func(ctx *cmd.Context) {
+	interrupted := make(chan os.Signal, 1)
+	ctx.InterruptNotify(interrupted)
+	defer ctx.StopInterruptNotify(interrupted)
+	<-interrupted
+}
@@ -0,0 +1,181 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package gui_test
+
+import (
+	"bufio"
+	"crypto/tls"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"strings"
+
+	"github.com/juju/cmd"
+	"github.com/juju/cmd/cmdtesting"
+	"github.com/juju/httprequest"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cmd/juju/gui"
+	jujutesting "github.com/juju/juju/juju/testing"
+)
+
+type dashboardSuite struct {
+	jujutesting.JujuConnSuite
+
+	browserURL *url.URL
+}
+
+var _ = gc.Suite(&dashboardSuite{})
+
+func (s *dashboardSuite) SetUpTest(c *gc.C) {
+	s.JujuConnSuite.SetUpTest(c)
+	s.browserURL = nil
+	s.PatchValue(gui.ClientGet, func(*httprequest.Client, string) error {
+		return nil
+	})
+	s.PatchValue(gui.WebbrowserOpen, func(u *url.URL) error {
+		s.browserURL = u
+		return nil
+	})
+	s.PatchValue(gui.WaitForInterrupt, func(*cmd.Context) {})
+}
+
+// run executes the dashboard command passing the given args.
+func (s *dashboardSuite) run(c *gc.C, args ...string) (string, error) {
+	ctx, err := cmdtesting.RunCommand(c, gui.NewDashboardCommand(), args...)
+	return strings.Trim(cmdtesting.Stderr(ctx), "\n"), err
+}
+
+// newBrowser returns an HTTP client which, like a browser, keeps
+// cookies and follows redirects.
+func newBrowser(c *gc.C) *http.Client {
+	jar, err := cookiejar.New(nil)
+	c.Assert(err, jc.ErrorIsNil)
+	return &http.Client{
+		Jar: jar,
+		Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+		},
+	}
+}
+
+func (s *dashboardSuite) TestDashboardTunnel(c *gc.C) {
+	var tunnelled bool
+	s.PatchValue(gui.WaitForInterrupt, func(*cmd.Context) {
+		// The token is exchanged for a cookie, and the request is
+		// proxied to the controller, which has no GUI to serve.
+		resp, err := newBrowser(c).Get(s.browserURL.String())
+		c.Assert(err, jc.ErrorIsNil)
+		defer resp.Body.Close()
+		body, err := ioutil.ReadAll(resp.Body)
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(resp.StatusCode, gc.Equals, http.StatusNotFound)
+		c.Check(string(body), jc.Contains, "Juju GUI not found")
+		c.Check(resp.Request.URL.Query().Get("juju-dashboard-token"), gc.Equals, "")
+		c.Check(resp.Request.URL.Path, gc.Equals, "/gui/u/admin/controller")
+		tunnelled = true
+	})
+	out, err := s.run(c)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(tunnelled, jc.IsTrue)
+	c.Check(s.browserURL.Scheme, gc.Equals, "https")
+	c.Check(s.browserURL.Hostname(), gc.Equals, "localhost")
+	c.Check(s.browserURL.Path, gc.Equals, "/gui/u/admin/controller")
+	c.Check(s.browserURL.Query().Get("juju-dashboard-token"), gc.Not(gc.Equals), "")
+	c.Check(out, gc.Equals, `
+Opening the Juju GUI in your browser.
+If it does not open, open this URL:
+`[1:]+s.browserURL.String()+`
+Log in as admin with your Juju password.
+Press Ctrl-C to close the tunnel.`)
+	c.Check(out, gc.Not(jc.Contains), "dummy-secret")
+}
+
+func (s *dashboardSuite) TestDashboardTunnelRequiresToken(c *gc.C) {
+	var checked bool
+	s.PatchValue(gui.WaitForInterrupt, func(*cmd.Context) {
+		browser := newBrowser(c)
+		u := *s.browserURL
+		for _, token := range []string{"", "bad-wolf"} {
+			query := u.Query()
+			query.Set("juju-dashboard-token", token)
+			u.RawQuery = query.Encode()
+			resp, err := browser.Get(u.String())
+			c.Assert(err, jc.ErrorIsNil)
+			resp.Body.Close()
+			c.Check(resp.StatusCode, gc.Equals, http.StatusForbidden, gc.Commentf("token %q", token))
+		}
+		checked = true
+	})
+	_, err := s.run(c)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(checked, jc.IsTrue)
+}
+
+func (s *dashboardSuite) TestDashboardTunnelWebsocket(c *gc.C) {
+	var upgraded bool
+	s.PatchValue(gui.WaitForInterrupt, func(*cmd.Context) {
+		conn, err := tls.Dial("tcp", s.browserURL.Host, &tls.Config{InsecureSkipVerify: true})
+		c.Assert(err, jc.ErrorIsNil)
+		defer conn.Close()
+		req, err := http.NewRequest("GET", "https://"+s.browserURL.Host+"/api", nil)
+		c.Assert(err, jc.ErrorIsNil)
+		req.Header.Set("Connection", "Upgrade")
+		req.Header.Set("Upgrade", "websocket")
+		req.Header.Set("Sec-WebSocket-Version", "13")
+		req.Header.Set("Sec-WebSocket-Key", "dGhlIHNhbXBsZSBub25jZQ==")
+		req.AddCookie(&http.Cookie{
+			Name:  "juju-dashboard-token",
+			Value: s.browserURL.Query().Get("juju-dashboard-token"),
+		})
+		c.Assert(req.Write(conn), jc.ErrorIsNil)
+		resp, err := http.ReadResponse(bufio.NewReader(conn), req)
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(resp.StatusCode, gc.Equals, http.StatusSwitchingProtocols)
+		upgraded = true
+	})
+	_, err := s.run(c)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(upgraded, jc.IsTrue)
+}
+
+func (s *dashboardSuite) TestDashboardTunnelClosed(c *gc.C) {
+	_, err := s.run(c)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = tls.Dial("tcp", s.browserURL.Host, &tls.Config{InsecureSkipVerify: true})
+	c.Assert(err, gc.NotNil)
+}
+
+func (s *dashboardSuite) TestDashboardNoBrowser(c *gc.C) {
+	out, err := s.run(c, "--no-browser")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.browserURL, gc.IsNil)
+	c.Assert(out, gc.Matches, `
+Juju GUI is available through a tunnel at:
+  https://localhost:\d+/gui/u/admin/controller\?juju-dashboard-token=\S+
+Log in as admin with your Juju password.
+Press Ctrl-C to close the tunnel.`[1:])
+}
+
+func (s *dashboardSuite) TestDashboardNoShowCredentials(c *gc.C) {
+	_, err := s.run(c, "--show-credentials")
+	c.Assert(err, gc.ErrorMatches, "flag provided but not defined: --show-credentials")
+}
+
+func (s *dashboardSuite) TestDashboardInvalidPort(c *gc.C) {
+	_, err := s.run(c, "--port", "70000")
+	c.Assert(err, gc.ErrorMatches, "port 70000 not valid")
+}
+
+func (s *dashboardSuite) TestDashboardUnavailable(c *gc.C) {
+	s.PatchValue(gui.ClientGet, func(*httprequest.Client, string) error {
+		return errors.New("bad wolf")
+	})
+	_, err := s.run(c)
+	c.Assert(err, gc.ErrorMatches, "Juju GUI is not available: bad wolf")
+	c.Assert(s.browserURL, gc.IsNil)
+}
@@ -12,8 +12,9 @@ import (
 )
 
 var (
-	ClientGet      = &clientGet
-	WebbrowserOpen = &webbrowserOpen
+	ClientGet        = &clientGet
+	WebbrowserOpen   = &webbrowserOpen
+	WaitForInterrupt = &waitForInterrupt
 
 	ClientGUIArchives      = &clientGUIArchives
 	ClientSelectGUIVersion = &clientSelectGUIVersion
@@ -97,34 +97,10 @@ func (c *guiCommand) Run(ctx *cmd.Context) error {
 	}
 	defer conn.Close()
 
-	store, ok := c.ClientStore().(modelcmd.QualifyingClientStore)
-	if !ok {
-		store = modelcmd.QualifyingClientStore{c.ClientStore()}
-	}
-	controllerName, err := c.ControllerName()
+	guiURL, err := findGUIURL(&c.ModelCommandBase, conn)
 	if err != nil {
 		return errors.Trace(err)
 	}
-	modelName, details, err := c.ModelCommandBase.ModelDetails()
-	if err != nil {
-		return errors.Annotate(err, "cannot retrieve model details: please make sure you switched to a valid model")
-	}
-
-	// Make 2 URLs to try - the old and the new.
-	rawURL := fmt.Sprintf("https://%s/gui/%s/", conn.Addr(), details.ModelUUID)
-	qualifiedModelName, err := store.QualifiedModelName(controllerName, modelName)
-	if err != nil {
-		return errors.Annotate(err, "cannot construct model name")
-	}
-	// Do not include any possible "@external" fragment in the path.
-	qualifiedModelName = strings.Replace(qualifiedModelName, "@external/", "/", 1)
-	newRawURL := fmt.Sprintf("https://%s/gui/u/%s", conn.Addr(), qualifiedModelName)
-
-	// Check that the Juju GUI is available.
-	var guiURL string
-	if guiURL, err = c.checkAvailable(rawURL, newRawURL, conn); err != nil {
-		return errors.Trace(err)
-	}
 
 	// Get the GUI version to print.
 	versions, err := c.guiVersions(conn)
@@ -151,9 +127,39 @@ func (c *guiCommand) Run(ctx *cmd.Context) error {
 	return nil
 }
 
+// findGUIURL returns the URL at which the controller serves the Juju GUI
+// for the current model, returning an error if the GUI is not available.
+func findGUIURL(c *modelcmd.ModelCommandBase, conn api.Connection) (string, error) {
+	store, ok := c.ClientStore().(modelcmd.QualifyingClientStore)
+	if !ok {
+		store = modelcmd.QualifyingClientStore{c.ClientStore()}
+	}
+	controllerName, err := c.ControllerName()
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	modelName, details, err := c.ModelDetails()
+	if err != nil {
+		return "", errors.Annotate(err, "cannot retrieve model details: please make sure you switched to a valid model")
+	}
+
+	// Make 2 URLs to try - the old and the new.
+	rawURL := fmt.Sprintf("https://%s/gui/%s/", conn.Addr(), details.ModelUUID)
+	qualifiedModelName, err := store.QualifiedModelName(controllerName, modelName)
+	if err != nil {
+		return "", errors.Annotate(err, "cannot construct model name")
+	}
+	// Do not include any possible "@external" fragment in the path.
+	qualifiedModelName = strings.Replace(qualifiedModelName, "@external/", "/", 1)
+	newRawURL := fmt.Sprintf("https://%s/gui/u/%s", conn.Addr(), qualifiedModelName)
+
+	// Check that the Juju GUI is available.
+	return checkAvailable(rawURL, newRawURL, conn)
+}
+
 // checkAvailable ensures the Juju GUI is available on the controller at
 // one of the given URLs, returning the successful URL.
-func (c *guiCommand) checkAvailable(rawURL, newRawURL string, conn api.Connection) (string, error) {
+func checkAvailable(rawURL, newRawURL string, conn api.Connection) (string, error) {
 	client, err := conn.HTTPClient()
 	if err != nil {
 		return "", errors.Annotate(err, "cannot retrieve HTTP client")
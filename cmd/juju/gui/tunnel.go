@@ -0,0 +1,280 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package gui
+
+import (
+	"crypto/subtle"
+	"crypto/tls"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils"
+
+	"github.com/juju/juju/cert"
+)
+
+const (
+	// tokenParam is the query parameter through which the browser
+	// first presents the tunnel's token.
+	tokenParam = "juju-dashboard-token"
+
+	// tokenCookie is the cookie in which the browser presents the
+	// tunnel's token once it has been exchanged.
+	tokenCookie = "juju-dashboard-token"
+)
+
+// tunnel serves the Juju GUI on a local address by proxying requests
+// to the controller. Only a client which presents the tunnel's token is
+// served, so other users of the local machine cannot use the tunnel to
+// reach the controller. The token is handed to the browser in the URL
+// returned by URL, and exchanged on first use for a cookie, so it does
+// not remain in the browser's address bar or history.
+//
+// Both the browser's connection to the tunnel and the tunnel's
+// connections to the controller use TLS; the latter are verified
+// against the controller's CA certificate.
+type tunnel struct {
+	listener  net.Listener
+	server    *http.Server
+	proxy     *httputil.ReverseProxy
+	target    string
+	tlsConfig *tls.Config
+	token     string
+
+	mu    sync.Mutex
+	conns map[net.Conn]bool
+	wg    sync.WaitGroup
+}
+
+// newTunnel listens on the given local address and proxies requests
+// bearing the tunnel's token to target, using tlsConfig to connect to
+// it, until the tunnel is closed.
+func newTunnel(localAddr, target string, tlsConfig *tls.Config) (*tunnel, error) {
+	token, err := utils.RandomPassword()
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot generate tunnel token")
+	}
+	localCert, err := newTunnelCert()
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot generate tunnel certificate")
+	}
+	listener, err := net.Listen("tcp", localAddr)
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot listen for connections")
+	}
+	t := &tunnel{
+		listener:  listener,
+		target:    target,
+		tlsConfig: tlsConfig,
+		token:     token,
+		conns:     make(map[net.Conn]bool),
+	}
+	t.proxy = &httputil.ReverseProxy{
+		Director: func(req *http.Request) {
+			req.URL.Scheme = "https"
+			req.URL.Host = target
+			removeTokenCookie(req)
+		},
+		Transport: &http.Transport{
+			TLSClientConfig: tlsConfig,
+		},
+	}
+	t.server = &http.Server{Handler: t}
+	tlsListener := tls.NewListener(listener, &tls.Config{
+		Certificates: []tls.Certificate{localCert},
+	})
+	t.wg.Add(1)
+	go func() {
+		defer t.wg.Done()
+		// Serve returns when the tunnel is closed.
+		t.server.Serve(tlsListener)
+	}()
+	return t, nil
+}
+
+// newTunnelCert returns a certificate for the tunnel's local address,
+// signed by a CA generated for the purpose.
+func newTunnelCert() (tls.Certificate, error) {
+	uuid, err := utils.NewUUID()
+	if err != nil {
+		return tls.Certificate{}, errors.Trace(err)
+	}
+	expiry := time.Now().UTC().AddDate(0, 0, 7)
+	caCertPEM, caKeyPEM, err := cert.NewCA("dashboard", uuid.String(), expiry)
+	if err != nil {
+		return tls.Certificate{}, errors.Trace(err)
+	}
+	certPEM, keyPEM, err := cert.NewServer(caCertPEM, caKeyPEM, expiry, []string{"localhost"})
+	if err != nil {
+		return tls.Certificate{}, errors.Trace(err)
+	}
+	localCert, err := tls.X509KeyPair([]byte(certPEM), []byte(keyPEM))
+	return localCert, errors.Trace(err)
+}
+
+// Port returns the local port the tunnel is listening on.
+func (t *tunnel) Port() int {
+	return t.listener.Addr().(*net.TCPAddr).Port
+}
+
+// URL returns the tunnelled equivalent of the given controller URL,
+// including the token which grants access to the tunnel.
+func (t *tunnel) URL(u *url.URL) *url.URL {
+	tunnelled := *u
+	// The tunnel's certificate is valid for localhost.
+	tunnelled.Host = net.JoinHostPort("localhost", strconv.Itoa(t.Port()))
+	query := tunnelled.Query()
+	query.Set(tokenParam, t.token)
+	tunnelled.RawQuery = query.Encode()
+	return &tunnelled
+}
+
+// Close stops accepting connections, closes those
+// already forwarded and waits for them to finish.
+func (t *tunnel) Close() error {
+	err := t.server.Close()
+	t.mu.Lock()
+	for conn := range t.conns {
+		conn.Close()
+	}
+	t.conns = nil
+	t.mu.Unlock()
+	t.wg.Wait()
+	return errors.Trace(err)
+}
+
+// ServeHTTP is part of the http.Handler interface.
+func (t *tunnel) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	query := req.URL.Query()
+	if token := query.Get(tokenParam); token != "" {
+		if !t.validToken(token) {
+			http.Error(w, "permission denied", http.StatusForbidden)
+			return
+		}
+		// Exchange the token for a cookie, and drop it from the URL.
+		http.SetCookie(w, &http.Cookie{
+			Name:     tokenCookie,
+			Value:    token,
+			Path:     "/",
+			Secure:   true,
+			HttpOnly: true,
+		})
+		query.Del(tokenParam)
+		redirect := *req.URL
+		redirect.RawQuery = query.Encode()
+		http.Redirect(w, req, redirect.RequestURI(), http.StatusFound)
+		return
+	}
+	cookie, err := req.Cookie(tokenCookie)
+	if err != nil || !t.validToken(cookie.Value) {
+		http.Error(w, "permission denied", http.StatusForbidden)
+		return
+	}
+	if isWebsocket(req) {
+		t.forwardWebsocket(w, req)
+		return
+	}
+	t.proxy.ServeHTTP(w, req)
+}
+
+func (t *tunnel) validToken(token string) bool {
+	return subtle.ConstantTimeCompare([]byte(token), []byte(t.token)) == 1
+}
+
+// removeTokenCookie removes the tunnel's cookie from a request before
+// it is sent on to the controller.
+func removeTokenCookie(req *http.Request) {
+	cookies := req.Cookies()
+	req.Header.Del("Cookie")
+	for _, cookie := range cookies {
+		if cookie.Name != tokenCookie {
+			req.AddCookie(cookie)
+		}
+	}
+}
+
+func isWebsocket(req *http.Request) bool {
+	return strings.ToLower(req.Header.Get("Upgrade")) == "websocket"
+}
+
+// forwardWebsocket sends a websocket request on to the controller and
+// then forwards the connection in both directions, since the GUI talks
+// to the controller's API over websockets.
+func (t *tunnel) forwardWebsocket(w http.ResponseWriter, req *http.Request) {
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		http.Error(w, "cannot forward connection", http.StatusInternalServerError)
+		return
+	}
+	remote, err := tls.Dial("tcp", t.target, t.tlsConfig)
+	if err != nil {
+		logger.Errorf("cannot connect to %s: %v", t.target, err)
+		http.Error(w, "cannot connect to controller", http.StatusBadGateway)
+		return
+	}
+	if !t.track(remote) {
+		return
+	}
+	defer t.untrack(remote)
+	removeTokenCookie(req)
+	if err := req.Write(remote); err != nil {
+		logger.Errorf("cannot forward request to %s: %v", t.target, err)
+		http.Error(w, "cannot connect to controller", http.StatusBadGateway)
+		return
+	}
+	local, buffered, err := hijacker.Hijack()
+	if err != nil {
+		logger.Errorf("cannot forward connection: %v", err)
+		return
+	}
+	if !t.track(local) {
+		return
+	}
+	defer t.untrack(local)
+
+	done := make(chan struct{}, 2)
+	go func() {
+		// Anything the browser has sent since the request
+		// is buffered, so read through the buffer.
+		io.Copy(remote, buffered)
+		done <- struct{}{}
+	}()
+	go func() {
+		io.Copy(local, remote)
+		done <- struct{}{}
+	}()
+	// When either side finishes, the deferred closes
+	// cause the other copy to finish too.
+	<-done
+}
+
+// track records conn so that it is closed when the tunnel is,
+// returning false (and closing conn) if the tunnel is already closed.
+func (t *tunnel) track(conn net.Conn) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.conns == nil {
+		conn.Close()
+		return false
+	}
+	t.conns[conn] = true
+	t.wg.Add(1)
+	return true
+}
+
+func (t *tunnel) untrack(conn net.Conn) {
+	conn.Close()
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.conns, conn)
+	t.wg.Done()
+}
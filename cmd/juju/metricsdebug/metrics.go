@@ -22,6 +22,17 @@ import (
 
 const metricsDoc = `
 Display recently collected metrics.
+
+By default only the latest value of each metric is shown for every unit.
+Use --history to show all the values that are still held by the
+controller, ordered by unit, metric and time:
+
+    juju metrics --history metered/0
+
+The output can be formatted as json or yaml for consumption by other
+tools:
+
+    juju metrics --all --format json
 `
 
 // MetricsCommand retrieves metrics stored in the juju controller.
@@ -29,8 +40,9 @@ type MetricsCommand struct {
 	modelcmd.ModelCommandBase
 	out cmd.Output
 
-	Tags []string
-	All  bool
+	Tags    []string
+	All     bool
+	History bool
 }
 
 // New creates a new MetricsCommand.
@@ -77,10 +89,12 @@ func (c *MetricsCommand) SetFlags(f *gnuflag.FlagSet) {
 		"yaml":    cmd.FormatYaml,
 	})
 	f.BoolVar(&c.All, "all", false, "retrieve metrics collected by all units in the model")
+	f.BoolVar(&c.History, "history", false, "retrieve all collected values, not only the latest ones")
 }
 
 type GetMetricsClient interface {
 	GetMetrics(tags ...string) ([]params.MetricResult, error)
+	GetMetricsHistory(tags ...string) ([]params.MetricResult, error)
 	Close() error
 }
 
@@ -101,7 +115,13 @@ func (slice metricSlice) Len() int {
 
 // Less implements the sort.Interface.
 func (slice metricSlice) Less(i, j int) bool {
-	return slice[i].Metric < slice[j].Metric
+	if slice[i].Unit != slice[j].Unit {
+		return slice[i].Unit < slice[j].Unit
+	}
+	if slice[i].Metric != slice[j].Metric {
+		return slice[i].Metric < slice[j].Metric
+	}
+	return slice[i].Timestamp.Before(slice[j].Timestamp)
 }
 
 // Swap implements the sort.Interface.
@@ -122,16 +142,20 @@ func (c *MetricsCommand) Run(ctx *cmd.Context) error {
 	if err != nil {
 		return errors.Trace(err)
 	}
+	defer client.Close()
+	getMetrics := client.GetMetrics
+	if c.History {
+		getMetrics = client.GetMetricsHistory
+	}
 	var metrics []params.MetricResult
 	if c.All {
-		metrics, err = client.GetMetrics()
+		metrics, err = getMetrics()
 	} else {
-		metrics, err = client.GetMetrics(c.Tags...)
+		metrics, err = getMetrics(c.Tags...)
 	}
 	if err != nil {
 		return errors.Trace(err)
 	}
-	defer client.Close()
 	if len(metrics) == 0 {
 		return nil
 	}
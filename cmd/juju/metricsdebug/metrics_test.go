@@ -33,6 +33,11 @@ func (m *mockGetMetricsClient) GetMetrics(tags ...string) ([]params.MetricResult
 	return m.metrics, m.NextErr()
 }
 
+func (m *mockGetMetricsClient) GetMetricsHistory(tags ...string) ([]params.MetricResult, error) {
+	m.AddCall("GetMetricsHistory", tags)
+	return m.metrics, m.NextErr()
+}
+
 func (m *mockGetMetricsClient) Close() error {
 	m.AddCall("Close")
 	return m.NextErr()
@@ -173,3 +178,43 @@ func (s *metricsSuite) TestNoArgs(c *gc.C) {
 	_, err := cmdtesting.RunCommand(c, metricsdebug.New())
 	c.Assert(err, gc.ErrorMatches, "you need to specify at least one unit or application")
 }
+
+func (s *metricsSuite) TestHistory(c *gc.C) {
+	s.client.metrics = []params.MetricResult{{
+		Unit:  "unit-metered-1",
+		Key:   "pings",
+		Value: "7.0",
+		Time:  time.Date(2016, 8, 22, 12, 02, 04, 0, time.UTC),
+	}, {
+		Unit:  "unit-metered-0",
+		Key:   "pings",
+		Value: "6.0",
+		Time:  time.Date(2016, 8, 22, 12, 07, 04, 0, time.UTC),
+	}, {
+		Unit:  "unit-metered-0",
+		Key:   "pings",
+		Value: "5.0",
+		Time:  time.Date(2016, 8, 22, 12, 02, 04, 0, time.UTC),
+	}}
+	ctx, err := cmdtesting.RunCommand(c, metricsdebug.New(), "--history", "metered")
+	c.Assert(err, jc.ErrorIsNil)
+	s.client.CheckCallNames(c, "GetMetricsHistory", "Close")
+	s.client.CheckCall(c, 0, "GetMetricsHistory", []string{"application-metered"})
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, `UNIT          	           TIMESTAMP	METRIC	VALUE
+unit-metered-0	2016-08-22T12:02:04Z	 pings	  5.0
+unit-metered-0	2016-08-22T12:07:04Z	 pings	  6.0
+unit-metered-1	2016-08-22T12:02:04Z	 pings	  7.0
+`)
+}
+
+func (s *metricsSuite) TestHistoryAll(c *gc.C) {
+	_, err := cmdtesting.RunCommand(c, metricsdebug.New(), "--history", "--all")
+	c.Assert(err, jc.ErrorIsNil)
+	s.client.CheckCall(c, 0, "GetMetricsHistory", []string(nil))
+}
+
+func (s *metricsSuite) TestHistoryError(c *gc.C) {
+	s.client.SetErrors(errors.New("metrics history not supported"))
+	_, err := cmdtesting.RunCommand(c, metricsdebug.New(), "--history", "metered/0")
+	c.Assert(err, gc.ErrorMatches, "metrics history not supported")
+}
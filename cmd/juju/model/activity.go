@@ -0,0 +1,251 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package model
+
+import (
+	"io"
+	"strings"
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+	"github.com/juju/utils/clock"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/api/audit"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/common"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/cmd/output"
+)
+
+const activityCommandDoc = `
+Lists the commands that recently changed the model, most recent first,
+showing who ran each command, when, and the API calls it made. The
+activity is read from the audit log of the controller the client is
+connected to, so auditing must be enabled on the controller; with
+several controllers, activity recorded by the others is not shown.
+
+Calls to methods that only read the model, as listed in the controller's
+audit-log-exclude-methods setting, are omitted. The entities affected by
+each call are only known when the controller's audit-log-capture-args
+setting is enabled.
+
+Listing the activity requires admin access to the model.
+
+The --since option takes a duration, such as 30m or 24h, or a time in
+RFC3339 format, such as 2018-06-01T10:00:00Z.
+
+Examples:
+
+    juju model-activity
+    juju model-activity -m mymodel --user mary
+    juju model-activity --since 24h --limit 100
+    juju model-activity --format yaml
+
+See also:
+    model-config
+    show-model
+`
+
+// NewActivityCommand returns a command to list the recent
+// activity in a model.
+func NewActivityCommand() cmd.Command {
+	return modelcmd.Wrap(&activityCommand{clock: clock.WallClock})
+}
+
+// activityCommand lists the commands that recently changed a model.
+type activityCommand struct {
+	modelcmd.ModelCommandBase
+	out   cmd.Output
+	api   ActivityAPI
+	clock clock.Clock
+
+	user    string
+	since   string
+	limit   int
+	isoTime bool
+}
+
+// ActivityAPI defines the methods on the audit API that the
+// model-activity command calls.
+type ActivityAPI interface {
+	Close() error
+	BestAPIVersion() int
+	ModelActivity(user string, since time.Time, limit int) ([]params.ModelActivity, error)
+}
+
+func (c *activityCommand) getAPI() (ActivityAPI, error) {
+	if c.api != nil {
+		return c.api, nil
+	}
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return audit.NewClient(root), nil
+}
+
+// Info implements Command.Info.
+func (c *activityCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "model-activity",
+		Purpose: "Lists the recent changes made to a model.",
+		Doc:     activityCommandDoc,
+	}
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *activityCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.ModelCommandBase.SetFlags(f)
+	f.StringVar(&c.user, "user", "", "Only list the commands run by this user")
+	f.StringVar(&c.since, "since", "", "Only list the commands run since this duration ago or time")
+	f.IntVar(&c.limit, "limit", 20, "The maximum number of commands to list, or 0 for all")
+	f.BoolVar(&c.isoTime, "utc", false, "Display time as UTC in RFC3339 format")
+	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": formatActivityTabular,
+	})
+}
+
+// Init implements Command.Init.
+func (c *activityCommand) Init(args []string) error {
+	if c.user != "" && !names.IsValidUser(c.user) {
+		return errors.NotValidf("user name %q", c.user)
+	}
+	if c.limit < 0 {
+		return errors.New("--limit must not be negative")
+	}
+	if c.since != "" {
+		if _, err := parseSince(c.since, time.Time{}); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return cmd.CheckEmpty(args)
+}
+
+// parseSince returns the time described by the value of --since,
+// which may be a duration before now or a time in RFC3339 format.
+func parseSince(value string, now time.Time) (time.Time, error) {
+	if d, err := time.ParseDuration(value); err == nil {
+		if d < 0 {
+			return time.Time{}, errors.Errorf("--since duration %q must not be negative", value)
+		}
+		return now.Add(-d), nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	return time.Time{}, errors.Errorf(
+		"--since %q: expected a duration such as 24h or a time such as 2018-06-01T10:00:00Z", value)
+}
+
+// Run implements Command.Run.
+func (c *activityCommand) Run(ctx *cmd.Context) error {
+	client, err := c.getAPI()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer client.Close()
+	if client.BestAPIVersion() < 1 {
+		return errors.New("listing model activity is not supported by this controller")
+	}
+
+	var since time.Time
+	if c.since != "" {
+		if since, err = parseSince(c.since, c.clock.Now()); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	activity, err := client.ModelActivity(c.user, since, c.limit)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(activity) == 0 && c.out.Name() == "tabular" {
+		ctx.Infof("No activity recorded for the model.")
+		return nil
+	}
+	return c.out.Write(ctx, c.formatActivity(activity))
+}
+
+// ActivityEntry defines the serialization behaviour of a command
+// that changed the model.
+type ActivityEntry struct {
+	Time       string              `yaml:"time" json:"time"`
+	User       string              `yaml:"user" json:"user"`
+	Command    string              `yaml:"command" json:"command"`
+	Operations []ActivityOperation `yaml:"operations" json:"operations"`
+}
+
+// ActivityOperation defines the serialization behaviour of an API
+// call made by a command that changed the model.
+type ActivityOperation struct {
+	Method   string   `yaml:"method" json:"method"`
+	Entities []string `yaml:"entities,omitempty" json:"entities,omitempty"`
+	Errors   []string `yaml:"errors,omitempty" json:"errors,omitempty"`
+}
+
+func (c *activityCommand) formatActivity(activity []params.ModelActivity) []ActivityEntry {
+	entries := make([]ActivityEntry, len(activity))
+	for i, one := range activity {
+		entry := ActivityEntry{
+			Time:    common.FormatTime(&one.When, c.isoTime),
+			User:    one.User,
+			Command: one.Command,
+		}
+		for _, op := range one.Operations {
+			operation := ActivityOperation{
+				Method: op.Facade + "." + op.Method,
+				Errors: op.Errors,
+			}
+			for _, entity := range op.Entities {
+				operation.Entities = append(operation.Entities, formatEntity(entity))
+			}
+			entry.Operations = append(entry.Operations, operation)
+		}
+		entries[i] = entry
+	}
+	return entries
+}
+
+// formatEntity returns an entity tag as the kind of
+// entity followed by its id, such as "unit mysql/0".
+func formatEntity(entity string) string {
+	tag, err := names.ParseTag(entity)
+	if err != nil {
+		return entity
+	}
+	return tag.Kind() + " " + tag.Id()
+}
+
+// formatActivityTabular writes the model activity, with a line for
+// each API call made by each command.
+func formatActivityTabular(writer io.Writer, value interface{}) error {
+	entries, ok := value.([]ActivityEntry)
+	if !ok {
+		return errors.Errorf("expected value of type %T, got %T", entries, value)
+	}
+
+	tw := output.TabWriter(writer)
+	w := output.Wrapper{tw}
+	w.Println("Time", "User", "Command", "Operation", "Entities", "Result")
+	for _, entry := range entries {
+		for i, op := range entry.Operations {
+			result := "ok"
+			if len(op.Errors) > 0 {
+				result = strings.Join(op.Errors, "; ")
+			}
+			if i == 0 {
+				w.Print(entry.Time, entry.User, entry.Command)
+			} else {
+				w.Print("", "", "")
+			}
+			w.Println(op.Method, strings.Join(op.Entities, ", "), result)
+		}
+	}
+	tw.Flush()
+	return nil
+}
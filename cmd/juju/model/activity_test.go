@@ -0,0 +1,190 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package model_test
+
+import (
+	"time"
+
+	"github.com/juju/cmd/cmdtesting"
+	gitjujutesting "github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/model"
+	"github.com/juju/juju/jujuclient"
+	"github.com/juju/juju/testing"
+)
+
+type ActivityCommandSuite struct {
+	testing.FakeJujuXDGDataHomeSuite
+	fake  *fakeActivityClient
+	clock *gitjujutesting.Clock
+	store *jujuclient.MemStore
+}
+
+var _ = gc.Suite(&ActivityCommandSuite{})
+
+var activityNow = time.Date(2018, 6, 1, 13, 0, 0, 0, time.UTC)
+
+func (s *ActivityCommandSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuXDGDataHomeSuite.SetUpTest(c)
+	s.fake = &fakeActivityClient{
+		version: 1,
+		activity: []params.ModelActivity{{
+			User:    "mary",
+			Command: "juju remove-unit mysql/0 mysql/1",
+			When:    time.Date(2018, 6, 1, 12, 0, 0, 0, time.UTC),
+			Operations: []params.ModelActivityOperation{{
+				Facade:   "Application",
+				Method:   "DestroyUnit",
+				Entities: []string{"unit-mysql-0", "unit-mysql-1"},
+				Errors:   []string{"unit mysql/1 not found"},
+			}},
+		}, {
+			User:    "fred",
+			Command: "juju deploy mysql",
+			When:    time.Date(2018, 6, 1, 10, 0, 0, 0, time.UTC),
+			Operations: []params.ModelActivityOperation{{
+				Facade: "Application",
+				Method: "Deploy",
+			}, {
+				Facade:   "Application",
+				Method:   "Expose",
+				Entities: []string{"application-mysql"},
+			}},
+		}},
+	}
+	s.clock = gitjujutesting.NewClock(activityNow)
+	s.store = jujuclient.NewMemStore()
+	s.store.CurrentControllerName = "testing"
+	s.store.Controllers["testing"] = jujuclient.ControllerDetails{}
+	s.store.Accounts["testing"] = jujuclient.AccountDetails{
+		User: "admin",
+	}
+	err := s.store.UpdateModel("testing", "admin/mymodel", jujuclient.ModelDetails{
+		testing.ModelTag.Id(),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.store.Models["testing"].CurrentModel = "admin/mymodel"
+}
+
+func (s *ActivityCommandSuite) run(c *gc.C, args ...string) (string, error) {
+	ctx, err := cmdtesting.RunCommand(c, model.NewActivityCommandForTest(s.fake, s.clock, s.store), args...)
+	if err != nil {
+		return "", err
+	}
+	return cmdtesting.Stdout(ctx), nil
+}
+
+func (s *ActivityCommandSuite) TestInitErrors(c *gc.C) {
+	for i, test := range []struct {
+		args []string
+		err  string
+	}{{
+		args: []string{"--user", "not/valid"},
+		err:  `user name "not/valid" not valid`,
+	}, {
+		args: []string{"--limit", "-1"},
+		err:  "--limit must not be negative",
+	}, {
+		args: []string{"--since", "-1h"},
+		err:  `--since duration "-1h" must not be negative`,
+	}, {
+		args: []string{"--since", "yesterday"},
+		err:  `--since "yesterday": expected a duration such as 24h or a time such as 2018-06-01T10:00:00Z`,
+	}, {
+		args: []string{"extra"},
+		err:  `unrecognized args: \["extra"\]`,
+	}} {
+		c.Logf("test %d: %v", i, test.args)
+		_, err := s.run(c, test.args...)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
+
+func (s *ActivityCommandSuite) TestActivityTabular(c *gc.C) {
+	out, err := s.run(c, "--utc")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(out, gc.Equals, `
+Time                  User  Command                           Operation                Entities                    Result
+2018-06-01 12:00:00Z  mary  juju remove-unit mysql/0 mysql/1  Application.DestroyUnit  unit mysql/0, unit mysql/1  unit mysql/1 not found
+2018-06-01 10:00:00Z  fred  juju deploy mysql                 Application.Deploy                                   ok
+                                                              Application.Expose       application mysql           ok
+`[1:])
+	s.fake.CheckCalls(c, []gitjujutesting.StubCall{
+		{"ModelActivity", []interface{}{"", time.Time{}, 20}},
+		{"Close", nil},
+	})
+}
+
+func (s *ActivityCommandSuite) TestActivityYAML(c *gc.C) {
+	s.fake.activity = s.fake.activity[:1]
+	out, err := s.run(c, "--utc", "--format", "yaml")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(out, gc.Equals, `
+- time: 2018-06-01 12:00:00Z
+  user: mary
+  command: juju remove-unit mysql/0 mysql/1
+  operations:
+  - method: Application.DestroyUnit
+    entities:
+    - unit mysql/0
+    - unit mysql/1
+    errors:
+    - unit mysql/1 not found
+`[1:])
+}
+
+func (s *ActivityCommandSuite) TestActivityFilters(c *gc.C) {
+	_, err := s.run(c, "--user", "mary", "--since", "1h", "--limit", "0")
+	c.Assert(err, jc.ErrorIsNil)
+	s.fake.CheckCalls(c, []gitjujutesting.StubCall{
+		{"ModelActivity", []interface{}{"mary", activityNow.Add(-time.Hour), 0}},
+		{"Close", nil},
+	})
+}
+
+func (s *ActivityCommandSuite) TestActivitySinceTime(c *gc.C) {
+	_, err := s.run(c, "--since", "2018-05-01T10:00:00Z")
+	c.Assert(err, jc.ErrorIsNil)
+	s.fake.CheckCall(c, 0, "ModelActivity", "", time.Date(2018, 5, 1, 10, 0, 0, 0, time.UTC), 20)
+}
+
+func (s *ActivityCommandSuite) TestNoActivity(c *gc.C) {
+	s.fake.activity = nil
+	ctx, err := cmdtesting.RunCommand(c, model.NewActivityCommandForTest(s.fake, s.clock, s.store))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, "")
+	c.Assert(cmdtesting.Stderr(ctx), gc.Equals, "No activity recorded for the model.\n")
+}
+
+func (s *ActivityCommandSuite) TestActivityNotSupported(c *gc.C) {
+	s.fake.version = 0
+	_, err := s.run(c)
+	c.Assert(err, gc.ErrorMatches, "listing model activity is not supported by this controller")
+}
+
+type fakeActivityClient struct {
+	gitjujutesting.Stub
+	version  int
+	activity []params.ModelActivity
+}
+
+func (f *fakeActivityClient) Close() error {
+	f.MethodCall(f, "Close")
+	return f.NextErr()
+}
+
+func (f *fakeActivityClient) BestAPIVersion() int {
+	return f.version
+}
+
+func (f *fakeActivityClient) ModelActivity(user string, since time.Time, limit int) ([]params.ModelActivity, error) {
+	f.MethodCall(f, "ModelActivity", user, since, limit)
+	if err := f.NextErr(); err != nil {
+		return nil, err
+	}
+	return f.activity, nil
+}
@@ -0,0 +1,574 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package model
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+	"github.com/juju/utils"
+	"gopkg.in/juju/charm.v6"
+	"gopkg.in/juju/names.v2"
+	"gopkg.in/yaml.v2"
+
+	"github.com/juju/juju/api"
+	apiapplication "github.com/juju/juju/api/application"
+	"github.com/juju/juju/api/base"
+	cloudapi "github.com/juju/juju/api/cloud"
+	"github.com/juju/juju/api/modelconfig"
+	"github.com/juju/juju/apiserver/params"
+	jujucloud "github.com/juju/juju/cloud"
+	"github.com/juju/juju/cmd/juju/application"
+	"github.com/juju/juju/cmd/juju/common"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/jujuclient"
+)
+
+var usageCloneModelSummary = `
+Creates a new model with the applications and relations of another.`[1:]
+
+var usageCloneModelDetails = `
+Creates a new model in the same cloud, region and credential as the
+current model, and deploys to it the applications of the current
+model, with the same config, constraints, number of units, unit
+placement and relations. Model config set on the current model is also
+set on the new model, with any values given by --config taking
+precedence. Workload data, storage contents and actions are not
+copied, so the new model is suitable for staging copies of a
+production topology.
+
+By default each application is deployed with the latest revision of
+its charm. With --same-revisions the same charm revisions as in the
+current model are deployed instead. Applications using local charms
+cannot be cloned. Relations with applications in other models are not
+cloned.
+
+A different credential may be given with --credential. If the current
+model has no credential, one is chosen as for add-model: a credential
+is used if it is the only one for the cloud on the controller, or
+otherwise found locally and uploaded to the controller.
+
+With --dry-run the bundle that would be deployed is shown, and no
+model is created.
+
+Examples:
+    juju clone-model staging
+    juju clone-model -m production staging --same-revisions
+    juju clone-model staging --config logging-config="<root>=DEBUG"
+    juju clone-model staging --dry-run
+    juju clone-model staging --credential staging-creds
+
+See also:
+    add-model
+    deploy
+    diff-bundle`[1:]
+
+// NewCloneModelCommand returns a command to create a new model
+// with the applications and relations of the current model.
+func NewCloneModelCommand() modelcmd.ModelCommand {
+	cmd := &cloneModelCommand{}
+	cmd.newAPIFunc = cmd.newAPI
+	cmd.newModelManagerAPIFunc = func() (CloneModelManagerAPI, error) {
+		return cmd.NewModelManagerAPIClient()
+	}
+	cmd.newCloudAPIFunc = func() (CloneModelCloudAPI, error) {
+		root, err := cmd.NewControllerAPIRoot()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return cloudapi.NewClient(root), nil
+	}
+	cmd.providerRegistry = environs.GlobalProviderRegistry()
+	cmd.deployFunc = cmd.deploy
+	return modelcmd.Wrap(cmd)
+}
+
+// CloneModelAPI provides the details of the current model
+// needed by the clone-model command.
+type CloneModelAPI interface {
+	Close() error
+	Status(patterns []string) (*params.FullStatus, error)
+	Get(application string) (*params.ApplicationGetResults, error)
+	ModelGetWithMetadata() (config.ConfigValues, error)
+}
+
+// CloneModelManagerAPI provides the model manager methods
+// used by the clone-model command to create the new model.
+type CloneModelManagerAPI interface {
+	Close() error
+	ModelInfo(tags []names.ModelTag) ([]params.ModelInfoResult, error)
+	CreateModel(
+		name, owner, cloudName, cloudRegion string,
+		cloudCredential names.CloudCredentialTag,
+		config map[string]interface{},
+	) (base.ModelInfo, error)
+}
+
+// CloneModelCloudAPI provides the cloud methods used by the
+// clone-model command to find a credential for the new model.
+type CloneModelCloudAPI interface {
+	Close() error
+	Cloud(names.CloudTag) (jujucloud.Cloud, error)
+	UserCredentials(names.UserTag, names.CloudTag) ([]names.CloudCredentialTag, error)
+	UpdateCredential(names.CloudCredentialTag, jujucloud.Credential) error
+}
+
+// cloneModelCommand creates a new model with the applications
+// and relations of the current model.
+type cloneModelCommand struct {
+	modelcmd.ModelCommandBase
+	newModelName   string
+	sameRevisions  bool
+	dryRun         bool
+	noSwitch       bool
+	credentialName string
+	config         common.ConfigFlag
+
+	newAPIFunc             func() (CloneModelAPI, error)
+	newModelManagerAPIFunc func() (CloneModelManagerAPI, error)
+	newCloudAPIFunc        func() (CloneModelCloudAPI, error)
+	providerRegistry       environs.ProviderRegistry
+
+	// deployFunc deploys the bundle to the named model.
+	deployFunc func(ctx *cmd.Context, modelName string, data *charm.BundleData) error
+}
+
+// Info implements cmd.Command.
+func (c *cloneModelCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "clone-model",
+		Args:    "<new model name>",
+		Purpose: usageCloneModelSummary,
+		Doc:     usageCloneModelDetails,
+	}
+}
+
+// SetFlags implements cmd.Command.
+func (c *cloneModelCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.ModelCommandBase.SetFlags(f)
+	f.BoolVar(&c.sameRevisions, "same-revisions", false, "Deploy the same charm revisions as the current model")
+	f.BoolVar(&c.dryRun, "dry-run", false, "Show the bundle that would be deployed without creating the model")
+	f.BoolVar(&c.noSwitch, "no-switch", false, "Do not switch to the new model")
+	f.StringVar(&c.credentialName, "credential", "", "Credential used to add the model")
+	f.Var(&c.config, "config", "Path to YAML model configuration file or individual options (--config config.yaml [--config key=value ...])")
+}
+
+// Init implements cmd.Command.
+func (c *cloneModelCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no new model name specified")
+	}
+	c.newModelName = args[0]
+	if !names.IsValidModelName(c.newModelName) {
+		return errors.NotValidf("model name %q", c.newModelName)
+	}
+	return cmd.CheckEmpty(args[1:])
+}
+
+// Run implements cmd.Command.
+func (c *cloneModelCommand) Run(ctx *cmd.Context) error {
+	sourceName, sourceDetails, err := c.ModelDetails()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	client, err := c.newAPIFunc()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer client.Close()
+
+	data, err := cloneBundle(client, c.sameRevisions)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if c.dryRun {
+		out, err := yaml.Marshal(data)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		_, err = ctx.Stdout.Write(out)
+		return errors.Trace(err)
+	}
+	attrs, err := c.modelConfig(ctx, client)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	modelName, err := c.createModel(ctx, names.NewModelTag(sourceDetails.ModelUUID), attrs)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	ctx.Infof("Added %q model, cloned from %q", modelName, sourceName)
+	if err := c.deployFunc(ctx, modelName, data); err != nil {
+		return errors.Annotatef(err, "cannot deploy applications to %q", modelName)
+	}
+	return nil
+}
+
+// modelConfig returns the config for the new model: the values
+// set on the current model, overridden by those set with --config.
+func (c *cloneModelCommand) modelConfig(ctx *cmd.Context, client CloneModelAPI) (map[string]interface{}, error) {
+	values, err := client.ModelGetWithMetadata()
+	if err != nil {
+		return nil, errors.Annotate(err, "getting model config")
+	}
+	attrs := make(map[string]interface{})
+	for key, value := range values {
+		if value.Source != config.JujuModelConfigSource {
+			continue
+		}
+		switch key {
+		case config.NameKey, config.UUIDKey, config.TypeKey, config.AgentVersionKey:
+			// These are particular to the current model.
+			continue
+		}
+		attrs[key] = value.Value
+	}
+	overrides, err := c.config.ReadAttrs(ctx)
+	if err != nil {
+		return nil, errors.Annotate(err, "unable to parse config")
+	}
+	coerced, err := common.ConformYAML(overrides)
+	if err != nil {
+		return nil, errors.Annotate(err, "unable to parse config")
+	}
+	overrideAttrs, ok := coerced.(map[string]interface{})
+	if !ok {
+		return nil, errors.New("params must contain a YAML map with string keys")
+	}
+	for key, value := range overrideAttrs {
+		attrs[key] = value
+	}
+	return attrs, nil
+}
+
+// createModel creates the new model in the cloud and region of the
+// current model, and returns its qualified name. The new model uses
+// the credential of the current model unless another is specified
+// with --credential, or the current model has none.
+func (c *cloneModelCommand) createModel(ctx *cmd.Context, source names.ModelTag, attrs map[string]interface{}) (string, error) {
+	controllerName, err := c.ControllerName()
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	store := c.ClientStore()
+	accountDetails, err := store.AccountDetails(controllerName)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+
+	client, err := c.newModelManagerAPIFunc()
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	defer client.Close()
+	results, err := client.ModelInfo([]names.ModelTag{source})
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	if len(results) != 1 {
+		return "", errors.Errorf("expected 1 result, got %d", len(results))
+	}
+	if results[0].Error != nil {
+		return "", errors.Trace(results[0].Error)
+	}
+	info := results[0].Result
+	cloudTag, err := names.ParseCloudTag(info.CloudTag)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	owner := accountDetails.User
+	var credentialTag names.CloudCredentialTag
+	if c.credentialName == "" && info.CloudCredentialTag != "" {
+		credentialTag, err = names.ParseCloudCredentialTag(info.CloudCredentialTag)
+		if err != nil {
+			return "", errors.Trace(err)
+		}
+	} else {
+		credentialTag, err = c.findCredential(ctx, owner, cloudTag, info.CloudRegion)
+		if err != nil {
+			return "", errors.Trace(err)
+		}
+	}
+
+	model, err := client.CreateModel(c.newModelName, owner, cloudTag.Id(), info.CloudRegion, credentialTag, attrs)
+	if err != nil {
+		if params.IsCodeUnauthorized(err) {
+			common.PermissionsMessage(ctx.Stderr, "add a model")
+		}
+		return "", errors.Trace(err)
+	}
+	modelName := jujuclient.JoinOwnerModelName(names.NewUserTag(owner), c.newModelName)
+	if err := store.UpdateModel(controllerName, modelName, jujuclient.ModelDetails{
+		model.UUID,
+	}); err != nil {
+		return "", errors.Trace(err)
+	}
+	if !c.noSwitch {
+		if err := store.SetCurrentModel(controllerName, modelName); err != nil {
+			return "", errors.Trace(err)
+		}
+	}
+	return modelName, nil
+}
+
+// findCredential finds a credential for the new model as the
+// add-model command does, uploading it to the controller if it
+// is found locally.
+func (c *cloneModelCommand) findCredential(
+	ctx *cmd.Context, owner string, cloudTag names.CloudTag, cloudRegion string,
+) (names.CloudCredentialTag, error) {
+	client, err := c.newCloudAPIFunc()
+	if err != nil {
+		return names.CloudCredentialTag{}, errors.Trace(err)
+	}
+	defer client.Close()
+	cloud, err := client.Cloud(cloudTag)
+	if err != nil {
+		return names.CloudCredentialTag{}, errors.Trace(err)
+	}
+	credential, credentialTag, _, err := common.FindCredential(
+		ctx, c.ClientStore(), c.providerRegistry, client, common.FindCredentialParams{
+			CommandName:    "clone-model",
+			CredentialName: c.credentialName,
+			CloudTag:       cloudTag,
+			Cloud:          cloud,
+			CloudRegion:    cloudRegion,
+			ModelOwner:     owner,
+		},
+	)
+	if err != nil {
+		return names.CloudCredentialTag{}, errors.Trace(err)
+	}
+	if credential != nil {
+		ctx.Infof("Uploading credential '%s' to controller", credentialTag.Id())
+		if err := client.UpdateCredential(credentialTag, *credential); err != nil {
+			return names.CloudCredentialTag{}, errors.Trace(err)
+		}
+	}
+	return credentialTag, nil
+}
+
+// deploy deploys the bundle to the named model, as the deploy
+// command does.
+func (c *cloneModelCommand) deploy(ctx *cmd.Context, modelName string, data *charm.BundleData) error {
+	controllerName, err := c.ControllerName()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	apiRoot, err := c.CommandBase.NewAPIRoot(c.ClientStore(), controllerName, modelName)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer apiRoot.Close()
+	bakeryClient, err := c.BakeryClient()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(application.DeployBundle(ctx, apiRoot, bakeryClient, data))
+}
+
+func (c *cloneModelCommand) newAPI() (CloneModelAPI, error) {
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &cloneModelAPI{
+		root:        root,
+		client:      root.Client(),
+		application: apiapplication.NewClient(root),
+		modelConfig: modelconfig.NewClient(root),
+	}, nil
+}
+
+// cloneModelAPI implements CloneModelAPI using the Client,
+// Application and ModelConfig facades of an API connection.
+type cloneModelAPI struct {
+	root        api.Connection
+	client      *api.Client
+	application *apiapplication.Client
+	modelConfig *modelconfig.Client
+}
+
+func (a *cloneModelAPI) Status(patterns []string) (*params.FullStatus, error) {
+	return a.client.Status(patterns)
+}
+
+func (a *cloneModelAPI) Get(application string) (*params.ApplicationGetResults, error) {
+	return a.application.Get(application)
+}
+
+func (a *cloneModelAPI) ModelGetWithMetadata() (config.ConfigValues, error) {
+	return a.modelConfig.ModelGetWithMetadata()
+}
+
+func (a *cloneModelAPI) Close() error {
+	return a.root.Close()
+}
+
+// cloneBundle returns a bundle that deploys the applications of the
+// model, with their config, constraints, units and relations. The
+// bundle has a machine for each machine in the model hosting units,
+// so that units placed together in the model are placed together by
+// the bundle. Unless sameRevisions is true, the charm revisions are
+// left out so that the latest revisions are deployed.
+func cloneBundle(client CloneModelAPI, sameRevisions bool) (*charm.BundleData, error) {
+	status, err := client.Status(nil)
+	if err != nil {
+		return nil, errors.Annotate(err, "getting model status")
+	}
+	data := &charm.BundleData{
+		Applications: make(map[string]*charm.ApplicationSpec),
+		Machines:     make(map[string]*charm.MachineSpec),
+	}
+	appNames := make([]string, 0, len(status.Applications))
+	for name := range status.Applications {
+		appNames = append(appNames, name)
+	}
+	sort.Strings(appNames)
+
+	// containerUnits records the first unit placed in each
+	// container, so that units of other applications in the
+	// same container can be placed with it.
+	containerUnits := make(map[string]string)
+	for _, name := range appNames {
+		app := status.Applications[name]
+		curl, err := charm.ParseURL(app.Charm)
+		if err != nil {
+			return nil, errors.Annotatef(err, "application %q", name)
+		}
+		if curl.Schema == "local" {
+			return nil, errors.Errorf("cannot clone application %q: local charm %q not supported", name, app.Charm)
+		}
+		if !sameRevisions {
+			curl = curl.WithRevision(-1)
+		}
+		spec := &charm.ApplicationSpec{
+			Charm:  curl.String(),
+			Series: app.Series,
+			Expose: app.Exposed,
+		}
+
+		result, err := client.Get(name)
+		if err != nil {
+			return nil, errors.Annotatef(err, "getting config for %q", name)
+		}
+		for key, valueMap := range result.CharmConfig {
+			value, err := userConfigValue(key, valueMap)
+			if err != nil {
+				return nil, errors.Annotatef(err, "bad application config for %q", name)
+			}
+			if value == nil {
+				continue
+			}
+			if spec.Options == nil {
+				spec.Options = make(map[string]interface{})
+			}
+			spec.Options[key] = value
+		}
+
+		// Subordinate units are created along with the
+		// units of the applications they are related to.
+		if len(app.SubordinateTo) == 0 {
+			spec.Constraints = result.Constraints.String()
+			spec.NumUnits = len(app.Units)
+			for _, unitName := range sortedUnitNames(app.Units) {
+				machineId := app.Units[unitName].Machine
+				if machineId == "" {
+					continue
+				}
+				hostId := strings.Split(machineId, "/")[0]
+				if _, ok := data.Machines[hostId]; !ok {
+					data.Machines[hostId] = cloneMachine(status.Machines[hostId])
+				}
+				if hostId == machineId {
+					spec.To = append(spec.To, hostId)
+					continue
+				}
+				if unit, ok := containerUnits[machineId]; ok {
+					spec.To = append(spec.To, unit)
+					continue
+				}
+				containerUnits[machineId] = unitName
+				containerType := names.NewMachineTag(machineId).ContainerType()
+				spec.To = append(spec.To, containerType+":"+hostId)
+			}
+		}
+		data.Applications[name] = spec
+	}
+
+	for _, rel := range status.Relations {
+		// Peer relations have a single endpoint, and are
+		// not listed in bundles.
+		if len(rel.Endpoints) != 2 {
+			continue
+		}
+		app1, app2 := rel.Endpoints[0].ApplicationName, rel.Endpoints[1].ApplicationName
+		if data.Applications[app1] == nil || data.Applications[app2] == nil {
+			// Relations with remote applications are not cloned.
+			continue
+		}
+		data.Relations = append(data.Relations, sortedRelation(
+			app1+":"+rel.Endpoints[0].Name,
+			app2+":"+rel.Endpoints[1].Name,
+		))
+	}
+	sortRelations(data.Relations)
+	return data, nil
+}
+
+// cloneMachine returns the bundle machine for a machine in the model.
+func cloneMachine(machine params.MachineStatus) *charm.MachineSpec {
+	return &charm.MachineSpec{
+		Series:      machine.Series,
+		Constraints: machine.Constraints,
+	}
+}
+
+// sortedUnitNames returns the names of the units in unit number order.
+func sortedUnitNames(units map[string]params.UnitStatus) []string {
+	unitNames := make([]string, 0, len(units))
+	for name := range units {
+		unitNames = append(unitNames, name)
+	}
+	return utils.SortStringsNaturally(unitNames)
+}
+
+// userConfigValue returns the value of an application config setting
+// if it has been set by the user, or nil otherwise.
+func userConfigValue(key string, valueMap interface{}) (interface{}, error) {
+	vm, ok := valueMap.(map[string]interface{})
+	if !ok {
+		return nil, errors.Errorf("unexpected application config value type %T for key %q", valueMap, key)
+	}
+	if vm["source"] != "user" {
+		return nil, nil
+	}
+	value, ok := vm["value"]
+	if !ok {
+		return nil, errors.Errorf("missing application config value 'value' for key %q", key)
+	}
+	return value, nil
+}
+
+// sortedRelation returns the endpoints of a relation in the
+// order in which they are listed in bundles.
+func sortedRelation(a, b string) []string {
+	if b < a {
+		a, b = b, a
+	}
+	return []string{a, b}
+}
+
+func sortRelations(relations [][]string) {
+	sort.Slice(relations, func(i, j int) bool {
+		return fmt.Sprint(relations[i]) < fmt.Sprint(relations[j])
+	})
+}
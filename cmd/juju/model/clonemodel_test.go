@@ -0,0 +1,498 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package model_test
+
+import (
+	"strings"
+
+	"github.com/juju/cmd"
+	"github.com/juju/cmd/cmdtesting"
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/charm.v6"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/apiserver/params"
+	jujucloud "github.com/juju/juju/cloud"
+	"github.com/juju/juju/cmd/juju/model"
+	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/jujuclient"
+	_ "github.com/juju/juju/provider/ec2"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type CloneModelSuite struct {
+	testing.IsolationSuite
+
+	mockAPI          *mockCloneModelAPI
+	mockModelManager *mockCloneModelManagerAPI
+	mockCloudAPI     *mockCloneModelCloudAPI
+	providerRegistry *fakeCloneModelProviderRegistry
+	store            *jujuclient.MemStore
+
+	deployedModel string
+	deployed      *charm.BundleData
+}
+
+var _ = gc.Suite(&CloneModelSuite{})
+
+func (s *CloneModelSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.mockAPI = &mockCloneModelAPI{
+		Stub: &testing.Stub{},
+		status: &params.FullStatus{
+			Machines: map[string]params.MachineStatus{
+				"0": {Series: "xenial", Constraints: "mem=4096M"},
+				"1": {Series: "xenial"},
+			},
+			Applications: map[string]params.ApplicationStatus{
+				"haproxy": {
+					Charm:  "cs:xenial/haproxy-40",
+					Series: "xenial",
+					Units: map[string]params.UnitStatus{
+						"haproxy/0": {Machine: "0/lxd/0"},
+					},
+				},
+				"logging": {
+					Charm:         "cs:xenial/logging-2",
+					Series:        "xenial",
+					SubordinateTo: []string{"wordpress"},
+				},
+				"mysql": {
+					Charm:  "cs:xenial/mysql-57",
+					Series: "xenial",
+					Units: map[string]params.UnitStatus{
+						"mysql/0": {Machine: "1"},
+					},
+				},
+				"wordpress": {
+					Charm:   "cs:xenial/wordpress-47",
+					Series:  "xenial",
+					Exposed: true,
+					Units: map[string]params.UnitStatus{
+						"wordpress/0":  {Machine: "0/lxd/0"},
+						"wordpress/1":  {Machine: "1"},
+						"wordpress/10": {Machine: "0"},
+					},
+				},
+			},
+			Relations: []params.RelationStatus{{
+				Endpoints: []params.EndpointStatus{
+					{ApplicationName: "wordpress", Name: "db", Role: "requirer"},
+					{ApplicationName: "mysql", Name: "db", Role: "provider"},
+				},
+			}, {
+				Endpoints: []params.EndpointStatus{
+					{ApplicationName: "mysql", Name: "cluster", Role: "peer"},
+				},
+			}, {
+				Endpoints: []params.EndpointStatus{
+					{ApplicationName: "wordpress", Name: "juju-info", Role: "provider"},
+					{ApplicationName: "logging", Name: "info", Role: "requirer"},
+				},
+			}, {
+				Endpoints: []params.EndpointStatus{
+					{ApplicationName: "haproxy", Name: "reverseproxy", Role: "requirer"},
+					{ApplicationName: "wordpress", Name: "website", Role: "provider"},
+				},
+			}, {
+				Endpoints: []params.EndpointStatus{
+					{ApplicationName: "wordpress", Name: "cache", Role: "requirer"},
+					{ApplicationName: "memcached", Name: "cache", Role: "provider"},
+				},
+			}},
+		},
+		config: map[string]*params.ApplicationGetResults{
+			"mysql": {
+				CharmConfig: map[string]interface{}{
+					"dataset-size": map[string]interface{}{
+						"value":  "50%",
+						"source": "user",
+					},
+					"max-connections": map[string]interface{}{
+						"value":  float64(-1),
+						"source": "default",
+					},
+				},
+			},
+			"wordpress": {
+				Constraints: constraints.MustParse("mem=2048M"),
+			},
+		},
+		modelConfig: config.ConfigValues{
+			"name":                        {Value: "production", Source: config.JujuModelConfigSource},
+			"uuid":                        {Value: coretesting.ModelTag.Id(), Source: config.JujuModelConfigSource},
+			"type":                        {Value: "ec2", Source: config.JujuModelConfigSource},
+			"agent-version":               {Value: "2.4.0", Source: config.JujuModelConfigSource},
+			"logging-config":              {Value: "<root>=INFO", Source: config.JujuModelConfigSource},
+			"update-status-hook-interval": {Value: "10m", Source: config.JujuModelConfigSource},
+			"default-series":              {Value: "xenial", Source: config.JujuDefaultSource},
+		},
+	}
+	s.mockModelManager = &mockCloneModelManagerAPI{
+		Stub: &testing.Stub{},
+		info: params.ModelInfo{
+			CloudTag:           "cloud-aws",
+			CloudRegion:        "us-east-1",
+			CloudCredentialTag: "cloudcred-aws_admin_default",
+		},
+	}
+
+	s.mockCloudAPI = &mockCloneModelCloudAPI{
+		Stub: &testing.Stub{},
+	}
+	s.providerRegistry = &fakeCloneModelProviderRegistry{
+		provider: &fakeCloneModelProvider{},
+	}
+
+	s.store = jujuclient.NewMemStore()
+	s.store.CurrentControllerName = "testing"
+	s.store.Controllers["testing"] = jujuclient.ControllerDetails{}
+	s.store.Accounts["testing"] = jujuclient.AccountDetails{
+		User: "admin",
+	}
+	err := s.store.UpdateModel("testing", "admin/production", jujuclient.ModelDetails{
+		coretesting.ModelTag.Id(),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.store.Models["testing"].CurrentModel = "admin/production"
+	s.store.Credentials["aws"] = jujucloud.CloudCredential{
+		AuthCredentials: map[string]jujucloud.Credential{
+			"secrets": jujucloud.NewCredential(jujucloud.AccessKeyAuthType, map[string]string{
+				"access-key": "key",
+				"secret-key": "sekret",
+			}),
+		},
+	}
+
+	s.deployedModel = ""
+	s.deployed = nil
+}
+
+func (s *CloneModelSuite) deploy(ctx *cmd.Context, modelName string, data *charm.BundleData) error {
+	s.deployedModel = modelName
+	s.deployed = data
+	return nil
+}
+
+func (s *CloneModelSuite) run(c *gc.C, args ...string) (*cmd.Context, error) {
+	command := model.NewCloneModelCommandForTest(
+		s.mockAPI, s.mockModelManager, s.mockCloudAPI, s.providerRegistry, s.deploy, s.store,
+	)
+	return cmdtesting.RunCommand(c, command, args...)
+}
+
+func (s *CloneModelSuite) TestInitErrors(c *gc.C) {
+	for i, test := range []struct {
+		args []string
+		err  string
+	}{{
+		args: nil,
+		err:  "no new model name specified",
+	}, {
+		args: []string{"Staging!"},
+		err:  `model name "Staging!" not valid`,
+	}, {
+		args: []string{"staging", "extra"},
+		err:  `unrecognized args: \["extra"\]`,
+	}} {
+		c.Logf("test %d: %v", i, test.args)
+		_, err := s.run(c, test.args...)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+	s.mockAPI.CheckNoCalls(c)
+	s.mockModelManager.CheckNoCalls(c)
+	s.mockCloudAPI.CheckNoCalls(c)
+}
+
+func (s *CloneModelSuite) TestCloneBundle(c *gc.C) {
+	data, err := model.CloneBundle(s.mockAPI, false)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(data, jc.DeepEquals, &charm.BundleData{
+		Applications: map[string]*charm.ApplicationSpec{
+			"haproxy": {
+				Charm:    "cs:xenial/haproxy",
+				Series:   "xenial",
+				NumUnits: 1,
+				To:       []string{"lxd:0"},
+			},
+			"logging": {
+				Charm:  "cs:xenial/logging",
+				Series: "xenial",
+			},
+			"mysql": {
+				Charm:    "cs:xenial/mysql",
+				Series:   "xenial",
+				Options:  map[string]interface{}{"dataset-size": "50%"},
+				NumUnits: 1,
+				To:       []string{"1"},
+			},
+			"wordpress": {
+				Charm:       "cs:xenial/wordpress",
+				Series:      "xenial",
+				Expose:      true,
+				Constraints: "mem=2048M",
+				NumUnits:    3,
+				To:          []string{"haproxy/0", "1", "0"},
+			},
+		},
+		Machines: map[string]*charm.MachineSpec{
+			"0": {Series: "xenial", Constraints: "mem=4096M"},
+			"1": {Series: "xenial"},
+		},
+		Relations: [][]string{
+			{"haproxy:reverseproxy", "wordpress:website"},
+			{"logging:info", "wordpress:juju-info"},
+			{"mysql:db", "wordpress:db"},
+		},
+	})
+	s.mockAPI.CheckCallNames(c, "Status", "Get", "Get", "Get", "Get")
+}
+
+func (s *CloneModelSuite) TestCloneBundleSameRevisions(c *gc.C) {
+	data, err := model.CloneBundle(s.mockAPI, true)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(data.Applications["mysql"].Charm, gc.Equals, "cs:xenial/mysql-57")
+	c.Assert(data.Applications["wordpress"].Charm, gc.Equals, "cs:xenial/wordpress-47")
+}
+
+func (s *CloneModelSuite) TestCloneBundleLocalCharm(c *gc.C) {
+	app := s.mockAPI.status.Applications["mysql"]
+	app.Charm = "local:xenial/mysql-3"
+	s.mockAPI.status.Applications["mysql"] = app
+
+	_, err := model.CloneBundle(s.mockAPI, false)
+	c.Assert(err, gc.ErrorMatches, `cannot clone application "mysql": local charm "local:xenial/mysql-3" not supported`)
+}
+
+func (s *CloneModelSuite) TestDryRun(c *gc.C) {
+	ctx, err := s.run(c, "staging", "--dry-run")
+	c.Assert(err, jc.ErrorIsNil)
+
+	data, err := charm.ReadBundleData(strings.NewReader(cmdtesting.Stdout(ctx)))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(data.Applications, gc.HasLen, 4)
+	c.Assert(data.Applications["wordpress"].To, jc.DeepEquals, []string{"haproxy/0", "1", "0"})
+	c.Assert(data.Relations, gc.HasLen, 3)
+
+	s.mockAPI.CheckCallNames(c, "Status", "Get", "Get", "Get", "Get", "Close")
+	s.mockModelManager.CheckNoCalls(c)
+	c.Assert(s.deployed, gc.IsNil)
+}
+
+func (s *CloneModelSuite) TestCloneModel(c *gc.C) {
+	ctx, err := s.run(c, "staging")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stderr(ctx), gc.Equals, `Added "admin/staging" model, cloned from "admin/production"`+"\n")
+
+	s.mockModelManager.CheckCalls(c, []testing.StubCall{
+		{"ModelInfo", []interface{}{[]names.ModelTag{coretesting.ModelTag}}},
+		{"CreateModel", []interface{}{
+			"staging", "admin", "aws", "us-east-1",
+			names.NewCloudCredentialTag("aws/admin/default"),
+			map[string]interface{}{
+				"logging-config":              "<root>=INFO",
+				"update-status-hook-interval": "10m",
+			},
+		}},
+		{"Close", nil},
+	})
+
+	details, err := s.store.ModelByName("testing", "admin/staging")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(details.ModelUUID, gc.Equals, "staging-uuid")
+	current, err := s.store.CurrentModel("testing")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(current, gc.Equals, "admin/staging")
+
+	c.Assert(s.deployedModel, gc.Equals, "admin/staging")
+	c.Assert(s.deployed.Applications, gc.HasLen, 4)
+	s.mockCloudAPI.CheckNoCalls(c)
+}
+
+func (s *CloneModelSuite) TestCloneModelConfigOverrides(c *gc.C) {
+	_, err := s.run(c, "staging", "--config", "logging-config=<root>=DEBUG", "--config", "test-mode=true")
+	c.Assert(err, jc.ErrorIsNil)
+	s.mockModelManager.CheckCall(c, 1, "CreateModel",
+		"staging", "admin", "aws", "us-east-1",
+		names.NewCloudCredentialTag("aws/admin/default"),
+		map[string]interface{}{
+			"logging-config":              "<root>=DEBUG",
+			"update-status-hook-interval": "10m",
+			"test-mode":                   true,
+		},
+	)
+}
+
+func (s *CloneModelSuite) TestCloneModelNoSwitch(c *gc.C) {
+	_, err := s.run(c, "staging", "--no-switch")
+	c.Assert(err, jc.ErrorIsNil)
+	current, err := s.store.CurrentModel("testing")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(current, gc.Equals, "admin/production")
+	c.Assert(s.deployedModel, gc.Equals, "admin/staging")
+}
+
+func (s *CloneModelSuite) TestCloneModelNoCredentialUsesControllerCredential(c *gc.C) {
+	// The current model has no credential, and there is
+	// just one for the cloud on the controller, so it is
+	// used without uploading a local credential.
+	s.mockModelManager.info.CloudCredentialTag = ""
+	credentialTag := names.NewCloudCredentialTag("aws/admin/default")
+	s.mockCloudAPI.credentials = []names.CloudCredentialTag{credentialTag}
+	delete(s.store.Credentials, "aws")
+
+	_, err := s.run(c, "staging")
+	c.Assert(err, jc.ErrorIsNil)
+	s.mockCloudAPI.CheckCallNames(c, "Cloud", "UserCredentials", "Close")
+	s.mockModelManager.CheckCall(c, 1, "CreateModel",
+		"staging", "admin", "aws", "us-east-1", credentialTag,
+		map[string]interface{}{
+			"logging-config":              "<root>=INFO",
+			"update-status-hook-interval": "10m",
+		},
+	)
+}
+
+func (s *CloneModelSuite) TestCloneModelNoCredentialUploadsLocalCredential(c *gc.C) {
+	// The current model has no credential, and there are
+	// none on the controller, so the local one is uploaded.
+	s.mockModelManager.info.CloudCredentialTag = ""
+
+	ctx, err := s.run(c, "staging")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stderr(ctx), jc.Contains, "Uploading credential 'aws/admin/secrets' to controller\n")
+
+	credentialTag := names.NewCloudCredentialTag("aws/admin/secrets")
+	s.mockCloudAPI.CheckCallNames(c, "Cloud", "UserCredentials", "UpdateCredential", "Close")
+	c.Assert(s.mockCloudAPI.Calls()[2].Args[0], gc.Equals, credentialTag)
+	c.Assert(s.mockModelManager.Calls()[1].Args[4], gc.Equals, credentialTag)
+}
+
+func (s *CloneModelSuite) TestCloneModelCredentialFlag(c *gc.C) {
+	_, err := s.run(c, "staging", "--credential", "secrets")
+	c.Assert(err, jc.ErrorIsNil)
+
+	credentialTag := names.NewCloudCredentialTag("aws/admin/secrets")
+	s.mockCloudAPI.CheckCallNames(c, "Cloud", "UpdateCredential", "Close")
+	c.Assert(s.mockCloudAPI.Calls()[1].Args[0], gc.Equals, credentialTag)
+	c.Assert(s.mockModelManager.Calls()[1].Args[4], gc.Equals, credentialTag)
+}
+
+func (s *CloneModelSuite) TestCloneModelCredentialFlagNotFound(c *gc.C) {
+	_, err := s.run(c, "staging", "--credential", "other/secrets")
+	c.Assert(err, gc.ErrorMatches, "credential 'other/secrets' not found")
+	s.mockCloudAPI.CheckCallNames(c, "Cloud", "UserCredentials", "Close")
+	s.mockModelManager.CheckCallNames(c, "ModelInfo", "Close")
+	c.Assert(s.deployed, gc.IsNil)
+}
+
+func (s *CloneModelSuite) TestCloneModelCreateFails(c *gc.C) {
+	s.mockModelManager.SetErrors(nil, errors.New("boom"))
+	_, err := s.run(c, "staging")
+	c.Assert(err, gc.ErrorMatches, "boom")
+	c.Assert(s.deployed, gc.IsNil)
+}
+
+type mockCloneModelAPI struct {
+	*testing.Stub
+	status      *params.FullStatus
+	config      map[string]*params.ApplicationGetResults
+	modelConfig config.ConfigValues
+}
+
+func (m *mockCloneModelAPI) Close() error {
+	m.AddCall("Close")
+	return m.NextErr()
+}
+
+func (m *mockCloneModelAPI) Status(patterns []string) (*params.FullStatus, error) {
+	m.AddCall("Status", patterns)
+	return m.status, m.NextErr()
+}
+
+func (m *mockCloneModelAPI) Get(application string) (*params.ApplicationGetResults, error) {
+	m.AddCall("Get", application)
+	if result, ok := m.config[application]; ok {
+		return result, m.NextErr()
+	}
+	return &params.ApplicationGetResults{}, m.NextErr()
+}
+
+func (m *mockCloneModelAPI) ModelGetWithMetadata() (config.ConfigValues, error) {
+	m.AddCall("ModelGetWithMetadata")
+	return m.modelConfig, m.NextErr()
+}
+
+type mockCloneModelManagerAPI struct {
+	*testing.Stub
+	info params.ModelInfo
+}
+
+func (m *mockCloneModelManagerAPI) Close() error {
+	m.AddCall("Close")
+	return m.NextErr()
+}
+
+func (m *mockCloneModelManagerAPI) ModelInfo(tags []names.ModelTag) ([]params.ModelInfoResult, error) {
+	m.AddCall("ModelInfo", tags)
+	return []params.ModelInfoResult{{Result: &m.info}}, m.NextErr()
+}
+
+func (m *mockCloneModelManagerAPI) CreateModel(
+	name, owner, cloudName, cloudRegion string,
+	cloudCredential names.CloudCredentialTag,
+	config map[string]interface{},
+) (base.ModelInfo, error) {
+	m.AddCall("CreateModel", name, owner, cloudName, cloudRegion, cloudCredential, config)
+	return base.ModelInfo{Name: name, UUID: name + "-uuid"}, m.NextErr()
+}
+
+type mockCloneModelCloudAPI struct {
+	*testing.Stub
+	credentials []names.CloudCredentialTag
+}
+
+func (m *mockCloneModelCloudAPI) Close() error {
+	m.AddCall("Close")
+	return m.NextErr()
+}
+
+func (m *mockCloneModelCloudAPI) Cloud(tag names.CloudTag) (jujucloud.Cloud, error) {
+	m.AddCall("Cloud", tag)
+	return jujucloud.Cloud{
+		Name:      tag.Id(),
+		Type:      "ec2",
+		AuthTypes: []jujucloud.AuthType{jujucloud.AccessKeyAuthType},
+		Regions:   []jujucloud.Region{{Name: "us-east-1"}},
+	}, m.NextErr()
+}
+
+func (m *mockCloneModelCloudAPI) UserCredentials(user names.UserTag, cloud names.CloudTag) ([]names.CloudCredentialTag, error) {
+	m.AddCall("UserCredentials", user, cloud)
+	return m.credentials, m.NextErr()
+}
+
+func (m *mockCloneModelCloudAPI) UpdateCredential(tag names.CloudCredentialTag, credential jujucloud.Credential) error {
+	m.AddCall("UpdateCredential", tag, credential)
+	return m.NextErr()
+}
+
+type fakeCloneModelProviderRegistry struct {
+	environs.ProviderRegistry
+	provider environs.EnvironProvider
+}
+
+func (r *fakeCloneModelProviderRegistry) Provider(providerType string) (environs.EnvironProvider, error) {
+	return r.provider, nil
+}
+
+type fakeCloneModelProvider struct {
+	environs.EnvironProvider
+}
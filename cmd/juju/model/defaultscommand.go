@@ -36,21 +36,30 @@ supplied key to the supplied value. This can be repeated for multiple keys.
 You can also specify a yaml file containing key values.
 By default, the model is the current model.
 
+Defaults may be set for the controller as a whole, for a cloud, or for
+a region of a cloud. When a model is added, a value set for its region
+overrides one set for its cloud, which overrides one set for the
+controller, which overrides the Juju default. The tabular output shows
+the levels in that order, from the Juju default through to each region.
+
 
 Examples:
     juju model-defaults
     juju model-defaults http-proxy
     juju model-defaults aws/us-east-1 http-proxy
+    juju model-defaults aws http-proxy
     juju model-defaults us-east-1 http-proxy
     juju model-defaults -m mymodel type
     juju model-defaults ftp-proxy=10.0.0.1:8000
     juju model-defaults aws/us-east-1 ftp-proxy=10.0.0.1:8000
+    juju model-defaults aws vpc-id=vpc-0a1b2c3d
     juju model-defaults us-east-1 ftp-proxy=10.0.0.1:8000
     juju model-defaults us-east-1 ftp-proxy=10.0.0.1:8000 path/to/file.yaml
     juju model-defaults us-east-1 path/to/file.yaml    
     juju model-defaults -m othercontroller:mymodel default-series=yakkety test-mode=false
     juju model-defaults --reset default-series test-mode
     juju model-defaults aws/us-east-1 --reset http-proxy
+    juju model-defaults aws --reset vpc-id
     juju model-defaults us-east-1 --reset http-proxy
 
 See also:
@@ -100,6 +109,7 @@ type cloudAPI interface {
 	Close() error
 	DefaultCloud() (names.CloudTag, error)
 	Cloud(names.CloudTag) (jujucloud.Cloud, error)
+	Clouds() (map[names.CloudTag]jujucloud.Cloud, error)
 }
 
 // defaultsCommandAPI defines an API to be used during testing.
@@ -122,7 +132,7 @@ type defaultsCommandAPI interface {
 // Info implements part of the cmd.Command interface.
 func (c *defaultsCommand) Info() *cmd.Info {
 	return &cmd.Info{
-		Args:    "[[<cloud/>]<region> ]<model-key>[<=value>] ...]",
+		Args:    "[<cloud>|[<cloud/>]<region> ]<model-key>[<=value>] ...]",
 		Doc:     modelDefaultsHelpDoc,
 		Name:    "model-defaults",
 		Purpose: modelDefaultsSummary,
@@ -210,6 +220,10 @@ func (c *defaultsCommand) Run(ctx *cmd.Context) error {
 // be like the following invokation.
 //     juju model-defaults us-east-1 a=b c=d --reset e,f
 //
+// A cloud on its own may be specified too, to set defaults for all of its
+// regions.
+//     juju model-defaults aws a=b c=d --reset e,f
+//
 // Finally one can also ask for the all the defaults or the defaults for one
 // specific setting. In this case specifying a region is not valid as
 // model-defaults shows the settings for a value at all locations that it has a
@@ -246,11 +260,11 @@ func (c *defaultsCommand) parseArgs(args []string) error {
 	}
 
 	// Look at the first positional arg and test to see if it is a valid
-	// optional specification of cloud/region or region. If it is then
-	// cloudName and regionName are set on the object and the positional args
-	// are returned without the first element. If it cannot be validated;
-	// cloudName and regionName are left empty and we get back the same args we
-	// passed in.
+	// optional specification of cloud/region, region or cloud. If it is then
+	// cloudName and regionName are set on the object as appropriate and the
+	// positional args are returned without the first element. If it cannot be
+	// validated; cloudName and regionName are left empty and we get back the
+	// same args we passed in.
 	args, err = c.parseArgsForRegion(args)
 	if err != nil {
 		return errors.Trace(err)
@@ -333,14 +347,16 @@ func (c *defaultsCommand) parseArgsForRegion(args []string) ([]string, error) {
 	return args, nil
 }
 
-// parseCloudRegion examines args to see if the first arg is a cloud/region or
-// region. If not it returns the full args slice. If it is then it sets cloud
-// and/or region on the object and sends the remaining args back to the caller.
+// parseCloudRegion examines args to see if the first arg is a cloud/region,
+// region or cloud. If not it returns the full args slice. If it is then it
+// sets cloud and/or region on the object and sends the remaining args back
+// to the caller.
 func (c *defaultsCommand) parseCloudRegion(args []string) ([]string, error) {
 	var cloud, region string
 	cr := args[0]
 	// Must have no more than one slash and it must not be at the beginning or end.
-	if strings.Count(cr, "/") == 1 && !strings.HasPrefix(cr, "/") && !strings.HasSuffix(cr, "/") {
+	hasCloud := strings.Count(cr, "/") == 1 && !strings.HasPrefix(cr, "/") && !strings.HasSuffix(cr, "/")
+	if hasCloud {
 		elems := strings.Split(cr, "/")
 		cloud, region = elems[0], elems[1]
 	} else {
@@ -357,12 +373,44 @@ func (c *defaultsCommand) parseCloudRegion(args []string) ([]string, error) {
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
+	if !valid && !hasCloud {
+		// Regions take precedence, but a lone name
+		// may also be a cloud.
+		valid, err = c.validCloud(cr)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
 	if !valid {
 		return args, nil
 	}
 	return args[1:], nil
 }
 
+// validCloud checks that name is a cloud known to the controller,
+// setting it as the cloud to operate on if it is.
+func (c *defaultsCommand) validCloud(name string) (bool, error) {
+	if !names.IsValidCloud(name) {
+		return false, nil
+	}
+	root, err := c.newAPIRoot()
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	cc := c.newCloudAPI(root)
+	defer cc.Close()
+
+	clouds, err := cc.Clouds()
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	if _, ok := clouds[names.NewCloudTag(name)]; !ok {
+		return false, nil
+	}
+	c.cloudName = name
+	return true, nil
+}
+
 // validCloudRegion checks that region is a valid region in cloud, or default cloud
 // if cloud is not specified.
 func (c *defaultsCommand) validCloudRegion(cloudName, region string) (bool, error) {
@@ -415,9 +463,9 @@ func (c *defaultsCommand) handleSetArgs(args []string) error {
 	// If an invalid region was specified, the first positional arg won't have
 	// an "=". If we see one here we know it is invalid.
 	switch {
-	case argZeroKeyOnly && c.regionName == "":
+	case argZeroKeyOnly && c.cloudName == "":
 		return errors.Errorf("invalid region specified: %q", args[0])
-	case argZeroKeyOnly && c.regionName != "":
+	case argZeroKeyOnly && c.cloudName != "":
 		return errors.New("cannot set and retrieve default values simultaneously")
 	default:
 		if err := c.parseSetKeys(args); err != nil {
@@ -443,7 +491,8 @@ func (c *defaultsCommand) parseSetKeys(args []string) error {
 // processing for a region and the reset flag.
 func (c *defaultsCommand) handleOneArg(arg string) error {
 	resetSpecified := c.resetKeys != nil
-	regionSpecified := c.regionName != ""
+	// A cloud is always set when a region is.
+	regionSpecified := c.cloudName != ""
 
 	if regionSpecified {
 		if resetSpecified {
@@ -468,7 +517,7 @@ func (c *defaultsCommand) handleOneArg(arg string) error {
 // handleExtraArgs handles the case where too many args were supplied.
 func (c *defaultsCommand) handleExtraArgs(args []string) error {
 	resetSpecified := c.resetKeys != nil
-	regionSpecified := c.regionName != ""
+	regionSpecified := c.cloudName != ""
 	numArgs := len(args)
 
 	// if we have a key=value pair here then something is wrong because the
@@ -517,8 +566,9 @@ func (c *defaultsCommand) getDefaults(client defaultsCommandAPI, ctx *cmd.Contex
 		return config.RegionDefaultValue{}, false
 	}
 
-	// Filter by region if necessary.
-	if c.regionName != "" {
+	// Filter by region or cloud if necessary.
+	switch {
+	case c.regionName != "":
 		for attrName, attr := range attrs {
 			if regionDefault, ok := valueForRegion(c.regionName, attr.Regions); !ok {
 				delete(attrs, attrName)
@@ -528,6 +578,15 @@ func (c *defaultsCommand) getDefaults(client defaultsCommandAPI, ctx *cmd.Contex
 				attrs[attrName] = attrForRegion
 			}
 		}
+	case c.cloudName != "":
+		for attrName, attr := range attrs {
+			if attr.Cloud == nil {
+				delete(attrs, attrName)
+			} else {
+				attr.Regions = nil
+				attrs[attrName] = attr
+			}
+		}
 	}
 
 	if c.key != "" {
@@ -539,6 +598,8 @@ func (c *defaultsCommand) getDefaults(client defaultsCommandAPI, ctx *cmd.Contex
 			msg := fmt.Sprintf("there are no default model values for %q", c.key)
 			if c.regionName != "" {
 				msg += fmt.Sprintf(" in region %q", c.regionName)
+			} else if c.cloudName != "" {
+				msg += fmt.Sprintf(" in cloud %q", c.cloudName)
 			}
 			return errors.New(msg)
 		}
@@ -547,6 +608,10 @@ func (c *defaultsCommand) getDefaults(client defaultsCommandAPI, ctx *cmd.Contex
 		return errors.New(fmt.Sprintf(
 			"there are no default model values in region %q", c.regionName))
 	}
+	if c.cloudName != "" && len(attrs) == 0 {
+		return errors.New(fmt.Sprintf(
+			"there are no default model values in cloud %q", c.cloudName))
+	}
 
 	// If c.keys is empty, write out the whole lot.
 	return c.out.Write(ctx, attrs)
@@ -620,6 +685,7 @@ func (c *defaultsCommand) verifyKnownKeys(client defaultsCommandAPI, keys []stri
 }
 
 // formatConfigTabular writes a tabular summary of default config information.
+// The columns, and then the region rows, are in increasing order of precedence.
 func formatDefaultConfigTabular(writer io.Writer, value interface{}) error {
 	defaultValues, ok := value.(config.ModelDefaultAttributes)
 	if !ok {
@@ -629,27 +695,19 @@ func formatDefaultConfigTabular(writer io.Writer, value interface{}) error {
 	tw := output.TabWriter(writer)
 	w := output.Wrapper{tw}
 
-	p := func(name string, value config.AttributeDefaultValues) {
-		var c, d interface{}
-		switch value.Default {
-		case nil:
-			d = "-"
-		case "":
-			d = `""`
-		default:
-			d = value.Default
-		}
-		switch value.Controller {
+	format := func(value interface{}) interface{} {
+		switch value {
 		case nil:
-			c = "-"
+			return "-"
 		case "":
-			c = `""`
-		default:
-			c = value.Controller
+			return `""`
 		}
-		w.Println(name, d, c)
+		return value
+	}
+	p := func(name string, value config.AttributeDefaultValues) {
+		w.Println(name, format(value.Default), format(value.Controller), format(value.Cloud))
 		for _, region := range value.Regions {
-			w.Println("  "+region.Name, region.Value, "-")
+			w.Println("  "+region.Name, region.Value, "-", "-")
 		}
 	}
 	var valueNames []string
@@ -658,7 +716,7 @@ func formatDefaultConfigTabular(writer io.Writer, value interface{}) error {
 	}
 	sort.Strings(valueNames)
 
-	w.Println("Attribute", "Default", "Controller")
+	w.Println("Attribute", "Default", "Controller", "Cloud")
 
 	for _, name := range valueNames {
 		info := defaultValues[name]
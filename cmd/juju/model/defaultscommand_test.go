@@ -151,6 +151,18 @@ func (s *DefaultsCommandSuite) TestDefaultsInit(c *gc.C) {
 		description: "test valid region and no args",
 		args:        []string{"dummy-region"},
 		nilErr:      true,
+	}, {
+		description: "test valid cloud and set arg",
+		args:        []string{"dummy", "one=two"},
+		nilErr:      true,
+	}, {
+		description: "test valid cloud with reset",
+		args:        []string{"dummy", "--reset", "one"},
+		nilErr:      true,
+	}, {
+		description: "test valid cloud with reset and extra positional arg",
+		args:        []string{"dummy", "--reset", "one", "two"},
+		errorMatch:  "cannot retrieve defaults for a region and reset attributes at the same time",
 	}, {
 		// test cloud/region
 		description: "test invalid cloud fails",
@@ -338,6 +350,7 @@ func (s *DefaultsCommandSuite) TestSetConveysCloudRegion(c *gc.C) {
 		{"dummy-region", "dummy", "dummy-region"},
 		{"dummy/dummy-region", "dummy", "dummy-region"},
 		{"another-region", "dummy", "another-region"},
+		{"dummy", "dummy", ""},
 	}
 	for i, test := range table {
 		c.Logf("test %d", i)
@@ -365,10 +378,10 @@ func (s *DefaultsCommandSuite) TestGetSingleValue(c *gc.C) {
 
 	output := strings.TrimSpace(cmdtesting.Stdout(context))
 	expected := "" +
-		"Attribute         Default        Controller\n" +
-		"attr2             -              bar\n" +
-		"  dummy-region    dummy-value    -\n" +
-		"  another-region  another-value  -"
+		"Attribute         Default        Controller  Cloud\n" +
+		"attr2             -              bar         -\n" +
+		"  dummy-region    dummy-value    -           -\n" +
+		"  another-region  another-value  -           -"
 	c.Assert(output, gc.Equals, expected)
 }
 
@@ -414,11 +427,11 @@ func (s *DefaultsCommandSuite) TestGetAllValuesTabular(c *gc.C) {
 
 	output := strings.TrimSpace(cmdtesting.Stdout(context))
 	expected := "" +
-		"Attribute         Default        Controller\n" +
-		"attr              foo            -\n" +
-		"attr2             -              bar\n" +
-		"  dummy-region    dummy-value    -\n" +
-		"  another-region  another-value  -"
+		"Attribute         Default        Controller  Cloud\n" +
+		"attr              foo            -           -\n" +
+		"attr2             -              bar         -\n" +
+		"  dummy-region    dummy-value    -           -\n" +
+		"  another-region  another-value  -           -"
 	c.Assert(output, gc.Equals, expected)
 }
 
@@ -428,9 +441,9 @@ func (s *DefaultsCommandSuite) TestGetRegionValuesTabular(c *gc.C) {
 
 	output := strings.TrimSpace(cmdtesting.Stdout(context))
 	expected := "" +
-		"Attribute       Default      Controller\n" +
-		"attr2           -            bar\n" +
-		"  dummy-region  dummy-value  -"
+		"Attribute       Default      Controller  Cloud\n" +
+		"attr2           -            bar         -\n" +
+		"  dummy-region  dummy-value  -           -"
 	c.Assert(output, gc.Equals, expected)
 }
 
@@ -447,3 +460,34 @@ func (s *DefaultsCommandSuite) TestGetRegionOneArgNoValuesTabular(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, `there are no default model values for "attr" in region "dummy-region"`)
 	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, "")
 }
+
+func (s *DefaultsCommandSuite) TestGetCloudValuesTabular(c *gc.C) {
+	s.fakeDefaultsAPI.defaults["attr3"] = config.AttributeDefaultValues{
+		Default: "foo",
+		Cloud:   "cloud-value",
+		Regions: []config.RegionDefaultValue{{
+			Name:  "dummy-region",
+			Value: "region-value",
+		}},
+	}
+	context, err := s.run(c, "dummy")
+	c.Assert(err, jc.ErrorIsNil)
+
+	output := strings.TrimSpace(cmdtesting.Stdout(context))
+	expected := "" +
+		"Attribute  Default  Controller  Cloud\n" +
+		"attr3      foo      -           cloud-value"
+	c.Assert(output, gc.Equals, expected)
+}
+
+func (s *DefaultsCommandSuite) TestGetCloudNoValuesTabular(c *gc.C) {
+	ctx, err := s.run(c, "dummy")
+	c.Assert(err, gc.ErrorMatches, `there are no default model values in cloud "dummy"`)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, "")
+}
+
+func (s *DefaultsCommandSuite) TestGetCloudOneArgNoValuesTabular(c *gc.C) {
+	ctx, err := s.run(c, "dummy", "attr2")
+	c.Assert(err, gc.ErrorMatches, `there are no default model values for "attr2" in cloud "dummy"`)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, "")
+}
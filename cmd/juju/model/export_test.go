@@ -7,10 +7,13 @@ import (
 	"time"
 
 	"github.com/juju/cmd"
+	"github.com/juju/utils/clock"
+	"gopkg.in/juju/charm.v6"
 
 	"github.com/juju/juju/api"
 	"github.com/juju/juju/api/base"
 	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/environs"
 	"github.com/juju/juju/jujuclient"
 )
 
@@ -57,6 +60,30 @@ func NewDumpCommandForTest(api DumpModelAPI, store jujuclient.ClientStore) cmd.C
 	return modelcmd.Wrap(cmd)
 }
 
+// NewActivityCommandForTest returns an activityCommand with the api
+// and clock provided as specified.
+func NewActivityCommandForTest(api ActivityAPI, clock clock.Clock, store jujuclient.ClientStore) cmd.Command {
+	cmd := &activityCommand{api: api, clock: clock}
+	cmd.SetClientStore(store)
+	return modelcmd.Wrap(cmd)
+}
+
+// NewSuspendCommandForTest returns a suspendCommand with the api
+// provided as specified.
+func NewSuspendCommandForTest(api ModelPowerAPI, store jujuclient.ClientStore) cmd.Command {
+	cmd := &suspendCommand{modelPowerCommand{api: api}}
+	cmd.SetClientStore(store)
+	return modelcmd.Wrap(cmd)
+}
+
+// NewResumeCommandForTest returns a resumeCommand with the api
+// provided as specified.
+func NewResumeCommandForTest(api ModelPowerAPI, store jujuclient.ClientStore) cmd.Command {
+	cmd := &resumeCommand{modelPowerCommand{api: api}}
+	cmd.SetClientStore(store)
+	return modelcmd.Wrap(cmd)
+}
+
 // NewDumpDBCommandForTest returns a DumpDBCommand with the api provided as specified.
 func NewDumpDBCommandForTest(api DumpDBAPI, store jujuclient.ClientStore) cmd.Command {
 	cmd := &dumpDBCommand{api: api}
@@ -96,23 +123,54 @@ type RevokeCommand struct {
 }
 
 // NewGrantCommandForTest returns a GrantCommand with the api provided as specified.
-func NewGrantCommandForTest(modelsApi GrantModelAPI, offersAPI GrantOfferAPI, store jujuclient.ClientStore) (cmd.Command, *GrantCommand) {
+func NewGrantCommandForTest(modelsApi GrantModelAPI, offersAPI GrantOfferAPI, cloudsAPI GrantCloudAPI, store jujuclient.ClientStore) (cmd.Command, *GrantCommand) {
 	cmd := &grantCommand{
 		modelsApi: modelsApi,
 		offersApi: offersAPI,
+		cloudsApi: cloudsAPI,
 	}
 	cmd.SetClientStore(store)
 	return modelcmd.WrapController(cmd), &GrantCommand{cmd}
 }
 
 // NewRevokeCommandForTest returns an revokeCommand with the api provided as specified.
-func NewRevokeCommandForTest(modelsApi RevokeModelAPI, offersAPI RevokeOfferAPI, store jujuclient.ClientStore) (cmd.Command, *RevokeCommand) {
+func NewRevokeCommandForTest(modelsApi RevokeModelAPI, offersAPI RevokeOfferAPI, cloudsAPI RevokeCloudAPI, store jujuclient.ClientStore) (cmd.Command, *RevokeCommand) {
 	cmd := &revokeCommand{
 		modelsApi: modelsApi,
 		offersApi: offersAPI,
+		cloudsApi: cloudsAPI,
 	}
 	cmd.SetClientStore(store)
 	return modelcmd.WrapController(cmd), &RevokeCommand{cmd}
 }
 
 var GetBudgetAPIClient = &getBudgetAPIClient
+
+// NewCloneModelCommandForTest returns a CloneModelCommand with the apis,
+// provider registry, deploy function and client store provided as specified.
+func NewCloneModelCommandForTest(
+	api CloneModelAPI,
+	modelManagerAPI CloneModelManagerAPI,
+	cloudAPI CloneModelCloudAPI,
+	providerRegistry environs.ProviderRegistry,
+	deploy func(*cmd.Context, string, *charm.BundleData) error,
+	store jujuclient.ClientStore,
+) cmd.Command {
+	c := &cloneModelCommand{
+		newAPIFunc: func() (CloneModelAPI, error) {
+			return api, nil
+		},
+		newModelManagerAPIFunc: func() (CloneModelManagerAPI, error) {
+			return modelManagerAPI, nil
+		},
+		newCloudAPIFunc: func() (CloneModelCloudAPI, error) {
+			return cloudAPI, nil
+		},
+		providerRegistry: providerRegistry,
+		deployFunc:       deploy,
+	}
+	c.SetClientStore(store)
+	return modelcmd.Wrap(c)
+}
+
+var CloneBundle = cloneBundle
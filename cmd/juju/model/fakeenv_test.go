@@ -198,3 +198,14 @@ func (f *fakeCloudAPI) Cloud(name names.CloudTag) (jujucloud.Cloud, error) {
 	}
 	return c, nil
 }
+func (f *fakeCloudAPI) Clouds() (map[names.CloudTag]jujucloud.Cloud, error) {
+	result := make(map[names.CloudTag]jujucloud.Cloud)
+	for tag, c := range f.clouds {
+		cloudTag, err := names.ParseCloudTag(tag)
+		if err != nil {
+			return nil, err
+		}
+		result[cloudTag] = c
+	}
+	return result, nil
+}
@@ -6,9 +6,11 @@ package model
 import (
 	"github.com/juju/cmd"
 	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
 	"gopkg.in/juju/names.v2"
 
 	"github.com/juju/juju/api/applicationoffers"
+	cloudapi "github.com/juju/juju/api/cloud"
 	"github.com/juju/juju/cmd/juju/block"
 	"github.com/juju/juju/cmd/modelcmd"
 	"github.com/juju/juju/core/crossmodel"
@@ -17,7 +19,7 @@ import (
 )
 
 var usageGrantSummary = `
-Grants access level to a Juju user for a model, controller, or application offer.`[1:]
+Grants access level to a Juju user for a model, controller, cloud, or application offer.`[1:]
 
 var usageGrantDetails = `
 By default, the controller is the current controller.
@@ -35,11 +37,19 @@ Valid access levels for controllers are:
     add-model
     superuser
 
+Valid access levels for clouds are:
+    add-model
+    admin
+
 Valid access levels for application offers are:
     read
     consume
     admin
 
+A user with 'add-model' access to a cloud may create models on that
+cloud without having 'add-model' access to the whole controller.
+Use the --cloud option to change a user's access to a cloud.
+
 Examples:
 Grant user 'joe' 'read' access to model 'mymodel':
 
@@ -57,6 +67,10 @@ Grant user 'maria' 'add-model' access to the controller:
 
     juju grant maria add-model
 
+Grant user 'maria' 'add-model' access to the cloud 'aws':
+
+    juju grant maria add-model --cloud aws
+
 Grant user 'joe' 'read' access to application offer 'fred/prod.hosted-mysql':
 
     juju grant joe read fred/prod.hosted-mysql
@@ -74,14 +88,15 @@ See also:
     add-user`[1:]
 
 var usageRevokeSummary = `
-Revokes access from a Juju user for a model, controller, or application offer.`[1:]
+Revokes access from a Juju user for a model, controller, cloud, or application offer.`[1:]
 
 var usageRevokeDetails = `
 By default, the controller is the current controller.
 
 Revoking write access, from a user who has that permission, will leave
 that user with read access. Revoking read access, however, also revokes
-write access.
+write access. Similarly, revoking 'admin' access to a cloud leaves
+'add-model' access, while revoking 'add-model' removes all access.
 
 Examples:
 Revoke 'read' (and 'write') access from user 'joe' for model 'mymodel':
@@ -96,6 +111,10 @@ Revoke 'add-model' access from user 'maria' to the controller:
 
     juju revoke maria add-model
 
+Revoke 'add-model' access from user 'maria' to the cloud 'aws':
+
+    juju revoke maria add-model --cloud aws
+
 Revoke 'read' (and 'write') access from user 'joe' for application offer 'fred/prod.hosted-mysql':
 
     juju revoke joe read fred/prod.hosted-mysql
@@ -113,9 +132,16 @@ type accessCommand struct {
 	User       string
 	ModelNames []string
 	OfferURLs  []*crossmodel.OfferURL
+	Cloud      string
 	Access     string
 }
 
+// SetFlags implements cmd.Command.
+func (c *accessCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.ControllerCommandBase.SetFlags(f)
+	f.StringVar(&c.Cloud, "cloud", "", "The cloud to change access to")
+}
+
 // Init implements cmd.Command.
 func (c *accessCommand) Init(args []string) error {
 	if len(args) < 1 {
@@ -128,6 +154,19 @@ func (c *accessCommand) Init(args []string) error {
 
 	c.User = args[0]
 	c.Access = args[1]
+	// Special case for backwards compatibility.
+	if c.Access == "addmodel" {
+		c.Access = "add-model"
+	}
+	if c.Cloud != "" {
+		if len(args) > 2 {
+			return errors.New("either specify a cloud or model names and offer URLs but not both")
+		}
+		if !names.IsValidCloud(c.Cloud) {
+			return errors.NotValidf("cloud name %q", c.Cloud)
+		}
+		return permission.ValidateCloudAccess(permission.Access(c.Access))
+	}
 	// The remaining args are either model names or offer names.
 	for _, arg := range args[2:] {
 		url, err := crossmodel.ParseOfferURL(arg)
@@ -152,10 +191,6 @@ func (c *accessCommand) Init(args []string) error {
 		return errors.New("either specify model names or offer URLs but not both")
 	}
 
-	// Special case for backwards compatibility.
-	if c.Access == "addmodel" {
-		c.Access = "add-model"
-	}
 	if len(c.ModelNames) > 0 || len(c.OfferURLs) > 0 {
 		if err := permission.ValidateControllerAccess(permission.Access(c.Access)); err == nil {
 			return errors.Errorf("You have specified a controller access permission %q.\n"+
@@ -187,6 +222,7 @@ type grantCommand struct {
 	accessCommand
 	modelsApi GrantModelAPI
 	offersApi GrantOfferAPI
+	cloudsApi GrantCloudAPI
 }
 
 // Info implements Command.Info.
@@ -221,6 +257,17 @@ func (c *grantCommand) getOfferAPI() (GrantOfferAPI, error) {
 	return applicationoffers.NewClient(root), nil
 }
 
+func (c *grantCommand) getCloudAPI() (GrantCloudAPI, error) {
+	if c.cloudsApi != nil {
+		return c.cloudsApi, nil
+	}
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return cloudapi.NewClient(root), nil
+}
+
 // GrantModelAPI defines the API functions used by the grant command.
 type GrantModelAPI interface {
 	Close() error
@@ -239,8 +286,17 @@ type GrantOfferAPI interface {
 	GrantOffer(user, access string, offerURLs ...string) error
 }
 
+// GrantCloudAPI defines the API functions used by the grant command.
+type GrantCloudAPI interface {
+	Close() error
+	GrantCloud(user, access string, clouds ...string) error
+}
+
 // Run implements cmd.Command.
 func (c *grantCommand) Run(ctx *cmd.Context) error {
+	if c.Cloud != "" {
+		return c.runForCloud()
+	}
 	if len(c.ModelNames) > 0 {
 		return c.runForModel()
 	}
@@ -292,6 +348,16 @@ func (c *grantCommand) runForOffers() error {
 	return block.ProcessBlockedError(err, block.BlockChange)
 }
 
+func (c *grantCommand) runForCloud() error {
+	client, err := c.getCloudAPI()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	return block.ProcessBlockedError(client.GrantCloud(c.User, c.Access, c.Cloud), block.BlockChange)
+}
+
 // NewRevokeCommand returns a new revoke command.
 func NewRevokeCommand() cmd.Command {
 	return modelcmd.WrapController(&revokeCommand{})
@@ -302,6 +368,7 @@ type revokeCommand struct {
 	accessCommand
 	modelsApi RevokeModelAPI
 	offersApi RevokeOfferAPI
+	cloudsApi RevokeCloudAPI
 }
 
 // Info implements cmd.Command.
@@ -336,6 +403,17 @@ func (c *revokeCommand) getOfferAPI() (RevokeOfferAPI, error) {
 	return applicationoffers.NewClient(root), nil
 }
 
+func (c *revokeCommand) getCloudAPI() (RevokeCloudAPI, error) {
+	if c.cloudsApi != nil {
+		return c.cloudsApi, nil
+	}
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return cloudapi.NewClient(root), nil
+}
+
 // RevokeModelAPI defines the API functions used by the revoke command.
 type RevokeModelAPI interface {
 	Close() error
@@ -354,8 +432,17 @@ type RevokeOfferAPI interface {
 	RevokeOffer(user, access string, offerURLs ...string) error
 }
 
+// RevokeCloudAPI defines the API functions used by the revoke command.
+type RevokeCloudAPI interface {
+	Close() error
+	RevokeCloud(user, access string, clouds ...string) error
+}
+
 // Run implements cmd.Command.
 func (c *revokeCommand) Run(ctx *cmd.Context) error {
+	if c.Cloud != "" {
+		return c.runForCloud()
+	}
 	if len(c.ModelNames) > 0 {
 		return c.runForModel()
 	}
@@ -392,6 +479,16 @@ func (c *revokeCommand) runForModel() error {
 	return block.ProcessBlockedError(client.RevokeModel(c.User, c.Access, models...), block.BlockChange)
 }
 
+func (c *revokeCommand) runForCloud() error {
+	client, err := c.getCloudAPI()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	return block.ProcessBlockedError(client.RevokeCloud(c.User, c.Access, c.Cloud), block.BlockChange)
+}
+
 type accountDetailsGetter interface {
 	CurrentAccountDetails() (*jujuclient.AccountDetails, error)
 }
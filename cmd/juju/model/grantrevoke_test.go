@@ -22,6 +22,7 @@ type grantRevokeSuite struct {
 	testing.FakeJujuXDGDataHomeSuite
 	fakeModelAPI  *fakeModelGrantRevokeAPI
 	fakeOffersAPI *fakeOffersGrantRevokeAPI
+	fakeCloudAPI  *fakeCloudGrantRevokeAPI
 	cmdFactory    func(*fakeModelGrantRevokeAPI, *fakeOffersGrantRevokeAPI) cmd.Command
 	store         *jujuclient.MemStore
 }
@@ -38,6 +39,7 @@ func (s *grantRevokeSuite) SetUpTest(c *gc.C) {
 	s.FakeJujuXDGDataHomeSuite.SetUpTest(c)
 	s.fakeModelAPI = &fakeModelGrantRevokeAPI{}
 	s.fakeOffersAPI = &fakeOffersGrantRevokeAPI{}
+	s.fakeCloudAPI = &fakeCloudGrantRevokeAPI{}
 
 	// Set up the current controller, and write just enough info
 	// so we don't try to refresh
@@ -104,6 +106,15 @@ func (s *grantRevokeSuite) TestModelAccess(c *gc.C) {
 	c.Assert(s.fakeModelAPI.access, gc.Equals, "write")
 }
 
+func (s *grantRevokeSuite) TestPassesCloudValues(c *gc.C) {
+	_, err := s.run(c, "sam", "add-model", "--cloud", "aws")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.fakeCloudAPI.user, gc.Equals, "sam")
+	c.Assert(s.fakeCloudAPI.clouds, jc.DeepEquals, []string{"aws"})
+	c.Assert(s.fakeCloudAPI.access, gc.Equals, "add-model")
+	c.Assert(s.fakeModelAPI.user, gc.Equals, "")
+}
+
 func (s *grantRevokeSuite) TestModelBlockGrant(c *gc.C) {
 	s.fakeModelAPI.err = common.OperationBlockedError("TestBlockGrant")
 	_, err := s.run(c, "sam", "read", "foo")
@@ -119,13 +130,13 @@ var _ = gc.Suite(&grantSuite{})
 func (s *grantSuite) SetUpTest(c *gc.C) {
 	s.grantRevokeSuite.SetUpTest(c)
 	s.cmdFactory = func(fakeModelAPI *fakeModelGrantRevokeAPI, fakeOfferAPI *fakeOffersGrantRevokeAPI) cmd.Command {
-		c, _ := model.NewGrantCommandForTest(fakeModelAPI, fakeOfferAPI, s.store)
+		c, _ := model.NewGrantCommandForTest(fakeModelAPI, fakeOfferAPI, s.fakeCloudAPI, s.store)
 		return c
 	}
 }
 
 func (s *grantSuite) TestInitModels(c *gc.C) {
-	wrappedCmd, grantCmd := model.NewGrantCommandForTest(nil, nil, s.store)
+	wrappedCmd, grantCmd := model.NewGrantCommandForTest(nil, nil, nil, s.store)
 	err := cmdtesting.InitCommand(wrappedCmd, []string{})
 	c.Assert(err, gc.ErrorMatches, "no user specified")
 
@@ -141,7 +152,7 @@ func (s *grantSuite) TestInitModels(c *gc.C) {
 }
 
 func (s *grantSuite) TestInitOffers(c *gc.C) {
-	wrappedCmd, grantCmd := model.NewGrantCommandForTest(nil, nil, s.store)
+	wrappedCmd, grantCmd := model.NewGrantCommandForTest(nil, nil, nil, s.store)
 
 	err := cmdtesting.InitCommand(wrappedCmd, []string{"bob", "read", "fred/model.offer1", "mary/model.offer2"})
 	c.Assert(err, jc.ErrorIsNil)
@@ -155,10 +166,27 @@ func (s *grantSuite) TestInitOffers(c *gc.C) {
 	c.Assert(grantCmd.ModelNames, gc.HasLen, 0)
 }
 
+func (s *grantSuite) TestInitCloud(c *gc.C) {
+	wrappedCmd, grantCmd := model.NewGrantCommandForTest(nil, nil, nil, s.store)
+	err := cmdtesting.InitCommand(wrappedCmd, []string{"bob", "addmodel", "--cloud", "aws"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(grantCmd.Cloud, gc.Equals, "aws")
+	c.Assert(grantCmd.Access, gc.Equals, "add-model")
+
+	err = cmdtesting.InitCommand(wrappedCmd, []string{"bob", "read", "--cloud", "aws"})
+	c.Assert(err, gc.ErrorMatches, `"read" cloud access not valid`)
+
+	err = cmdtesting.InitCommand(wrappedCmd, []string{"bob", "add-model", "--cloud", "aws", "model1"})
+	c.Assert(err, gc.ErrorMatches, "either specify a cloud or model names and offer URLs but not both")
+
+	err = cmdtesting.InitCommand(wrappedCmd, []string{"bob", "add-model", "--cloud", "#aws"})
+	c.Assert(err, gc.ErrorMatches, `cloud name "#aws" not valid`)
+}
+
 // TestInitGrantAddModel checks that both the documented 'add-model' access and
 // the backwards-compatible 'addmodel' work to grant the AddModel permission.
 func (s *grantSuite) TestInitGrantAddModel(c *gc.C) {
-	wrappedCmd, grantCmd := model.NewGrantCommandForTest(nil, nil, s.store)
+	wrappedCmd, grantCmd := model.NewGrantCommandForTest(nil, nil, nil, s.store)
 	// The documented case, add-model.
 	err := cmdtesting.InitCommand(wrappedCmd, []string{"bob", "add-model"})
 	c.Check(err, jc.ErrorIsNil)
@@ -178,13 +206,13 @@ var _ = gc.Suite(&revokeSuite{})
 func (s *revokeSuite) SetUpTest(c *gc.C) {
 	s.grantRevokeSuite.SetUpTest(c)
 	s.cmdFactory = func(fakeModelAPI *fakeModelGrantRevokeAPI, fakeOffersAPI *fakeOffersGrantRevokeAPI) cmd.Command {
-		c, _ := model.NewRevokeCommandForTest(fakeModelAPI, fakeOffersAPI, s.store)
+		c, _ := model.NewRevokeCommandForTest(fakeModelAPI, fakeOffersAPI, s.fakeCloudAPI, s.store)
 		return c
 	}
 }
 
 func (s *revokeSuite) TestInit(c *gc.C) {
-	wrappedCmd, revokeCmd := model.NewRevokeCommandForTest(nil, nil, s.store)
+	wrappedCmd, revokeCmd := model.NewRevokeCommandForTest(nil, nil, nil, s.store)
 	err := cmdtesting.InitCommand(wrappedCmd, []string{})
 	c.Assert(err, gc.ErrorMatches, "no user specified")
 
@@ -202,7 +230,7 @@ func (s *revokeSuite) TestInit(c *gc.C) {
 // TestInitRevokeAddModel checks that both the documented 'add-model' access and
 // the backwards-compatible 'addmodel' work to revoke the AddModel permission.
 func (s *grantSuite) TestInitRevokeAddModel(c *gc.C) {
-	wrappedCmd, revokeCmd := model.NewRevokeCommandForTest(nil, nil, s.store)
+	wrappedCmd, revokeCmd := model.NewRevokeCommandForTest(nil, nil, nil, s.store)
 	// The documented case, add-model.
 	err := cmdtesting.InitCommand(wrappedCmd, []string{"bob", "add-model"})
 	c.Check(err, jc.ErrorIsNil)
@@ -214,21 +242,21 @@ func (s *grantSuite) TestInitRevokeAddModel(c *gc.C) {
 }
 
 func (s *grantSuite) TestModelAccessForController(c *gc.C) {
-	wrappedCmd, _ := model.NewRevokeCommandForTest(nil, nil, s.store)
+	wrappedCmd, _ := model.NewRevokeCommandForTest(nil, nil, nil, s.store)
 	err := cmdtesting.InitCommand(wrappedCmd, []string{"bob", "write"})
 	msg := strings.Replace(err.Error(), "\n", "", -1)
 	c.Check(msg, gc.Matches, `You have specified a model access permission "write".*`)
 }
 
 func (s *grantSuite) TestControllerAccessForModel(c *gc.C) {
-	wrappedCmd, _ := model.NewRevokeCommandForTest(nil, nil, s.store)
+	wrappedCmd, _ := model.NewRevokeCommandForTest(nil, nil, nil, s.store)
 	err := cmdtesting.InitCommand(wrappedCmd, []string{"bob", "superuser", "default"})
 	msg := strings.Replace(err.Error(), "\n", "", -1)
 	c.Check(msg, gc.Matches, `You have specified a controller access permission "superuser".*`)
 }
 
 func (s *grantSuite) TestControllerAccessForOffer(c *gc.C) {
-	wrappedCmd, _ := model.NewRevokeCommandForTest(nil, nil, s.store)
+	wrappedCmd, _ := model.NewRevokeCommandForTest(nil, nil, nil, s.store)
 	err := cmdtesting.InitCommand(wrappedCmd, []string{"bob", "superuser", "fred/default.mysql"})
 	msg := strings.Replace(err.Error(), "\n", "", -1)
 	c.Check(msg, gc.Matches, `You have specified a controller access permission "superuser".*`)
@@ -281,3 +309,27 @@ func (f *fakeOffersGrantRevokeAPI) fake(user, access string, offerURLs ...string
 	f.offerURLs = append(f.offerURLs, offerURLs...)
 	return f.err
 }
+
+type fakeCloudGrantRevokeAPI struct {
+	err    error
+	user   string
+	access string
+	clouds []string
+}
+
+func (f *fakeCloudGrantRevokeAPI) Close() error { return nil }
+
+func (f *fakeCloudGrantRevokeAPI) GrantCloud(user, access string, clouds ...string) error {
+	return f.fake(user, access, clouds...)
+}
+
+func (f *fakeCloudGrantRevokeAPI) RevokeCloud(user, access string, clouds ...string) error {
+	return f.fake(user, access, clouds...)
+}
+
+func (f *fakeCloudGrantRevokeAPI) fake(user, access string, clouds ...string) error {
+	f.user = user
+	f.access = access
+	f.clouds = clouds
+	return f.err
+}
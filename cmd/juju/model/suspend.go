@@ -0,0 +1,173 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package model
+
+import (
+	"strings"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/api/modelpower"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/block"
+	"github.com/juju/juju/cmd/modelcmd"
+)
+
+const suspendCommandDoc = `
+Powers off the instances of all the machines in a model, keeping their
+disks and any storage attached to them, so that an idle model costs
+less while it is not needed. The applications, units and storage in the
+model are left as they are, and the model may be resumed later with
+resume-model.
+
+Containers are powered off along with the machines hosting them.
+Manually provisioned machines are left running.
+
+Suspending a model requires admin access to the model, and is only
+supported in clouds able to power instances off and back on. The
+controller model cannot be suspended.
+
+Examples:
+
+    juju suspend-model
+    juju suspend-model -m mymodel
+
+See also:
+    resume-model
+    show-model
+`
+
+const resumeCommandDoc = `
+Powers on the instances of the machines in a model suspended with
+suspend-model. The machine and unit agents reconnect to the controller
+as their instances start, after which the model carries on as before.
+
+Resuming a model requires admin access to the model.
+
+Examples:
+
+    juju resume-model
+    juju resume-model -m mymodel
+
+See also:
+    suspend-model
+    show-model
+`
+
+// NewSuspendCommand returns a command to power off the
+// instances of a model.
+func NewSuspendCommand() cmd.Command {
+	return modelcmd.Wrap(&suspendCommand{})
+}
+
+// NewResumeCommand returns a command to power on the
+// instances of a suspended model.
+func NewResumeCommand() cmd.Command {
+	return modelcmd.Wrap(&resumeCommand{})
+}
+
+// ModelPowerAPI defines the methods on the model power API that
+// the suspend-model and resume-model commands call.
+type ModelPowerAPI interface {
+	Close() error
+	BestAPIVersion() int
+	SuspendModel() (params.ModelPowerResult, error)
+	ResumeModel() (params.ModelPowerResult, error)
+}
+
+// modelPowerCommand holds what is common to the
+// suspend-model and resume-model commands.
+type modelPowerCommand struct {
+	modelcmd.ModelCommandBase
+	api ModelPowerAPI
+}
+
+func (c *modelPowerCommand) getAPI() (ModelPowerAPI, error) {
+	if c.api != nil {
+		return c.api, nil
+	}
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return modelpower.NewClient(root), nil
+}
+
+// Init implements Command.Init.
+func (c *modelPowerCommand) Init(args []string) error {
+	return cmd.CheckEmpty(args)
+}
+
+// run calls the API method that suspends or resumes the model,
+// and reports the machines it powered off or on.
+func (c *modelPowerCommand) run(
+	ctx *cmd.Context,
+	call func(ModelPowerAPI) (params.ModelPowerResult, error),
+	action, done string,
+) error {
+	client, err := c.getAPI()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer client.Close()
+	if client.BestAPIVersion() < 1 {
+		return errors.New("suspending models is not supported by this controller")
+	}
+
+	modelName, err := c.ModelName()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	result, err := call(client)
+	if err != nil {
+		return block.ProcessBlockedError(err, block.BlockChange)
+	}
+	if len(result.Machines) > 0 {
+		ctx.Infof("Powered %s machines: %s", action, strings.Join(result.Machines, ", "))
+	}
+	if len(result.Skipped) > 0 {
+		ctx.Infof("Skipped manual or unprovisioned machines: %s", strings.Join(result.Skipped, ", "))
+	}
+	ctx.Infof("Model %q %s.", modelName, done)
+	return nil
+}
+
+// suspendCommand powers off the instances of a model.
+type suspendCommand struct {
+	modelPowerCommand
+}
+
+// Info implements Command.Info.
+func (c *suspendCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "suspend-model",
+		Purpose: "Powers off the machines in a model, keeping their storage.",
+		Doc:     suspendCommandDoc,
+	}
+}
+
+// Run implements Command.Run.
+func (c *suspendCommand) Run(ctx *cmd.Context) error {
+	return c.run(ctx, ModelPowerAPI.SuspendModel, "off", "suspended")
+}
+
+// resumeCommand powers on the instances of a suspended model.
+type resumeCommand struct {
+	modelPowerCommand
+}
+
+// Info implements Command.Info.
+func (c *resumeCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "resume-model",
+		Purpose: "Powers on the machines in a suspended model.",
+		Doc:     resumeCommandDoc,
+	}
+}
+
+// Run implements Command.Run.
+func (c *resumeCommand) Run(ctx *cmd.Context) error {
+	return c.run(ctx, ModelPowerAPI.ResumeModel, "on", "resumed")
+}
@@ -0,0 +1,138 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package model_test
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/cmd/cmdtesting"
+	"github.com/juju/errors"
+	gitjujutesting "github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/model"
+	"github.com/juju/juju/jujuclient"
+	"github.com/juju/juju/testing"
+)
+
+type SuspendCommandSuite struct {
+	testing.FakeJujuXDGDataHomeSuite
+	fake  *fakeModelPowerClient
+	store *jujuclient.MemStore
+}
+
+var _ = gc.Suite(&SuspendCommandSuite{})
+
+func (s *SuspendCommandSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuXDGDataHomeSuite.SetUpTest(c)
+	s.fake = &fakeModelPowerClient{
+		version: 1,
+		result: params.ModelPowerResult{
+			Machines: []string{"0", "1"},
+			Skipped:  []string{"2"},
+		},
+	}
+	s.store = jujuclient.NewMemStore()
+	s.store.CurrentControllerName = "testing"
+	s.store.Controllers["testing"] = jujuclient.ControllerDetails{}
+	s.store.Accounts["testing"] = jujuclient.AccountDetails{
+		User: "admin",
+	}
+	err := s.store.UpdateModel("testing", "admin/mymodel", jujuclient.ModelDetails{
+		testing.ModelTag.Id(),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.store.Models["testing"].CurrentModel = "admin/mymodel"
+}
+
+func (s *SuspendCommandSuite) run(c *gc.C, command cmd.Command, args ...string) (string, error) {
+	ctx, err := cmdtesting.RunCommand(c, command, args...)
+	if err != nil {
+		return "", err
+	}
+	return cmdtesting.Stderr(ctx), nil
+}
+
+func (s *SuspendCommandSuite) TestInit(c *gc.C) {
+	_, err := s.run(c, model.NewSuspendCommandForTest(s.fake, s.store), "extra")
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["extra"\]`)
+}
+
+func (s *SuspendCommandSuite) TestSuspend(c *gc.C) {
+	out, err := s.run(c, model.NewSuspendCommandForTest(s.fake, s.store))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(out, gc.Equals, `
+Powered off machines: 0, 1
+Skipped manual or unprovisioned machines: 2
+Model "admin/mymodel" suspended.
+`[1:])
+	s.fake.CheckCalls(c, []gitjujutesting.StubCall{
+		{"SuspendModel", nil},
+		{"Close", nil},
+	})
+}
+
+func (s *SuspendCommandSuite) TestResume(c *gc.C) {
+	s.fake.result.Skipped = nil
+	out, err := s.run(c, model.NewResumeCommandForTest(s.fake, s.store))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(out, gc.Equals, `
+Powered on machines: 0, 1
+Model "admin/mymodel" resumed.
+`[1:])
+	s.fake.CheckCalls(c, []gitjujutesting.StubCall{
+		{"ResumeModel", nil},
+		{"Close", nil},
+	})
+}
+
+func (s *SuspendCommandSuite) TestSuspendError(c *gc.C) {
+	s.fake.SetErrors(errors.New("powering off instances in this cloud not supported"))
+	_, err := s.run(c, model.NewSuspendCommandForTest(s.fake, s.store))
+	c.Assert(err, gc.ErrorMatches, "powering off instances in this cloud not supported")
+}
+
+func (s *SuspendCommandSuite) TestSuspendBlocked(c *gc.C) {
+	s.fake.SetErrors(&params.Error{Code: params.CodeOperationBlocked, Message: "TestBlockSuspend"})
+	_, err := s.run(c, model.NewSuspendCommandForTest(s.fake, s.store))
+	testing.AssertOperationWasBlocked(c, err, ".*TestBlockSuspend.*")
+}
+
+func (s *SuspendCommandSuite) TestSuspendNotSupported(c *gc.C) {
+	s.fake.version = 0
+	_, err := s.run(c, model.NewSuspendCommandForTest(s.fake, s.store))
+	c.Assert(err, gc.ErrorMatches, "suspending models is not supported by this controller")
+}
+
+type fakeModelPowerClient struct {
+	gitjujutesting.Stub
+	version int
+	result  params.ModelPowerResult
+}
+
+func (f *fakeModelPowerClient) Close() error {
+	f.MethodCall(f, "Close")
+	return f.NextErr()
+}
+
+func (f *fakeModelPowerClient) BestAPIVersion() int {
+	return f.version
+}
+
+func (f *fakeModelPowerClient) SuspendModel() (params.ModelPowerResult, error) {
+	f.MethodCall(f, "SuspendModel")
+	if err := f.NextErr(); err != nil {
+		return params.ModelPowerResult{}, err
+	}
+	return f.result, nil
+}
+
+func (f *fakeModelPowerClient) ResumeModel() (params.ModelPowerResult, error) {
+	f.MethodCall(f, "ResumeModel")
+	if err := f.NextErr(); err != nil {
+		return params.ModelPowerResult{}, err
+	}
+	return f.result, nil
+}
@@ -0,0 +1,52 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package resource
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/dustin/go-humanize"
+)
+
+// progressBarWidth is the number of characters in a progress bar.
+const progressBarWidth = 30
+
+// progressBar writes a bar showing the progress of a resource upload,
+// redrawn on the same line as the upload proceeds.
+type progressBar struct {
+	out     io.Writer
+	name    string
+	percent int
+}
+
+func newProgressBar(out io.Writer, name string) *progressBar {
+	return &progressBar{out: out, name: name, percent: -1}
+}
+
+// update redraws the progress bar, if the upload has progressed
+// by at least one percent since it was last drawn.
+func (p *progressBar) update(uploaded, size int64) {
+	percent := 100
+	if size > 0 {
+		percent = int(uploaded * 100 / size)
+	}
+	if percent == p.percent {
+		return
+	}
+	p.percent = percent
+	filled := progressBarWidth * percent / 100
+	fmt.Fprintf(p.out, "\ruploading %s [%s%s] %3d%% (%s/%s)",
+		p.name,
+		strings.Repeat("=", filled),
+		strings.Repeat(" ", progressBarWidth-filled),
+		percent,
+		humanize.IBytes(uint64(uploaded)),
+		humanize.IBytes(uint64(size)),
+	)
+	if uploaded >= size {
+		fmt.Fprintln(p.out)
+	}
+}
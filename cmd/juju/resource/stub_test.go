@@ -32,12 +32,15 @@ type stubAPIClient struct {
 	stub *testing.Stub
 }
 
-func (s *stubAPIClient) Upload(service, name, filename string, resource io.ReadSeeker) error {
-	s.stub.AddCall("Upload", service, name, filename, resource)
+func (s *stubAPIClient) UploadWithProgress(service, name, filename string, resource io.ReadSeeker, progress func(uploaded, size int64)) error {
+	s.stub.AddCall("UploadWithProgress", service, name, filename, resource)
 	if err := s.stub.NextErr(); err != nil {
 		return errors.Trace(err)
 	}
 
+	progress(0, 4096)
+	progress(1024, 4096)
+	progress(4096, 4096)
 	return nil
 }
 
@@ -14,8 +14,9 @@ import (
 
 // UploadClient has the API client methods needed by UploadCommand.
 type UploadClient interface {
-	// Upload sends the resource to Juju.
-	Upload(service, name, filename string, resource io.ReadSeeker) error
+	// UploadWithProgress sends the resource to Juju, calling
+	// progress with the number of bytes sent as it proceeds.
+	UploadWithProgress(service, name, filename string, resource io.ReadSeeker, progress func(uploaded, size int64)) error
 
 	// Close closes the client.
 	Close() error
@@ -60,6 +61,11 @@ func (c *UploadCommand) Info() *cmd.Info {
 		Doc: `
 This command uploads a file from your local disk to the juju controller to be
 used as a resource for an application.
+
+The progress of the upload is shown as it proceeds. Where the controller
+supports it, large files are sent in chunks, so that an upload interrupted
+by a failing connection is resumed rather than restarted, and the file
+assembled from the chunks is checked against the checksum of the local file.
 `,
 		Aliases: []string{"attach"},
 	}
@@ -108,14 +114,14 @@ func (c *UploadCommand) addResourceFile(arg string) error {
 }
 
 // Run implements cmd.Command.Run.
-func (c *UploadCommand) Run(*cmd.Context) error {
+func (c *UploadCommand) Run(ctx *cmd.Context) error {
 	apiclient, err := c.deps.NewClient(c)
 	if err != nil {
 		return errors.Trace(err)
 	}
 	defer apiclient.Close()
 
-	if err := c.upload(c.resourceFile, apiclient); err != nil {
+	if err := c.upload(ctx, c.resourceFile, apiclient); err != nil {
 		return errors.Annotatef(err, "failed to upload resource %q", c.resourceFile.name)
 	}
 	return nil
@@ -123,12 +129,13 @@ func (c *UploadCommand) Run(*cmd.Context) error {
 
 // upload opens the given file and calls the apiclient to upload it to the given
 // application with the given name.
-func (c *UploadCommand) upload(rf resourceFile, client UploadClient) error {
+func (c *UploadCommand) upload(ctx *cmd.Context, rf resourceFile, client UploadClient) error {
 	f, err := c.deps.OpenResource(rf.filename)
 	if err != nil {
 		return errors.Trace(err)
 	}
 	defer f.Close()
-	err = client.Upload(rf.service, rf.name, rf.filename, f)
+	progress := newProgressBar(ctx.Stderr, rf.name)
+	err = client.UploadWithProgress(rf.service, rf.name, rf.filename, f, progress.update)
 	return errors.Trace(err)
 }
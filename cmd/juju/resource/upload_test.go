@@ -5,6 +5,7 @@ package resource_test
 
 import (
 	jujucmd "github.com/juju/cmd"
+	"github.com/juju/cmd/cmdtesting"
 	"github.com/juju/errors"
 	"github.com/juju/testing"
 	jc "github.com/juju/testing/checkers"
@@ -96,6 +97,11 @@ func (s *UploadSuite) TestInfo(c *gc.C) {
 		Doc: `
 This command uploads a file from your local disk to the juju controller to be
 used as a resource for an application.
+
+The progress of the upload is shown as it proceeds. Where the controller
+supports it, large files are sent in chunks, so that an upload interrupted
+by a failing connection is resumed rather than restarted, and the file
+assembled from the chunks is checked against the checksum of the local file.
 `,
 		Aliases: []string{"attach"},
 	})
@@ -112,18 +118,24 @@ func (s *UploadSuite) TestRun(c *gc.C) {
 	err := u.Init([]string{"svc", "foo=bar"})
 	c.Assert(err, jc.ErrorIsNil)
 
-	err = u.Run(nil)
+	ctx := cmdtesting.Context(c)
+	err = u.Run(ctx)
 	c.Assert(err, jc.ErrorIsNil)
 
 	s.stub.CheckCallNames(c,
 		"NewClient",
 		"OpenResource",
-		"Upload",
+		"UploadWithProgress",
 		"FileClose",
 		"Close",
 	)
 	s.stub.CheckCall(c, 1, "OpenResource", "bar")
-	s.stub.CheckCall(c, 2, "Upload", "svc", "foo", "bar", file)
+	s.stub.CheckCall(c, 2, "UploadWithProgress", "svc", "foo", "bar", file)
+	c.Check(cmdtesting.Stderr(ctx), gc.Equals, ""+
+		"\ruploading foo [                              ]   0% (0 B/4.0 KiB)"+
+		"\ruploading foo [=======                       ]  25% (1.0 KiB/4.0 KiB)"+
+		"\ruploading foo [==============================] 100% (4.0 KiB/4.0 KiB)\n",
+	)
 }
 
 type stubUploadDeps struct {
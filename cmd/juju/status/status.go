@@ -8,15 +8,20 @@ import (
 	"io"
 	"os"
 	"strconv"
+	"time"
 
 	"github.com/juju/cmd"
 	"github.com/juju/errors"
 	"github.com/juju/gnuflag"
 	"github.com/juju/loggo"
+	"github.com/juju/utils/clock"
+	"golang.org/x/crypto/ssh/terminal"
 
+	"github.com/juju/juju/api"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/cmd/modelcmd"
 	"github.com/juju/juju/juju/osenv"
+	"github.com/juju/juju/state/multiwatcher"
 )
 
 var logger = loggo.GetLogger("juju.cmd.juju.status")
@@ -26,10 +31,16 @@ type statusAPI interface {
 	Close() error
 }
 
+// allWatcher reports changes to the model, for --watch.
+type allWatcher interface {
+	Next() ([]multiwatcher.Delta, error)
+	Stop() error
+}
+
 // NewStatusCommand returns a new command, which reports on the
 // runtime state of various system entities.
 func NewStatusCommand() cmd.Command {
-	return modelcmd.Wrap(&statusCommand{})
+	return modelcmd.Wrap(&statusCommand{clock: clock.WallClock})
 }
 
 type statusCommand struct {
@@ -38,8 +49,13 @@ type statusCommand struct {
 	patterns []string
 	isoTime  bool
 	api      statusAPI
+	clock    clock.Clock
 
 	color bool
+
+	// watch, if non-zero, is the minimum interval between
+	// re-displaying the status as the model changes.
+	watch time.Duration
 }
 
 var usageSummary = `
@@ -73,10 +89,18 @@ The available output formats are:
 - json: Displays information about the model, machines, applications, and units
       in structured JSON format.
 
+With --watch, the status is displayed again whenever the model changes,
+but no more often than the given interval, until the command is
+interrupted. The connection to the controller is held open, and the
+status is only fetched when something has changed. Each JSON status is
+written on a line of its own, and YAML statuses are written as separate
+documents.
+
 Examples:
     juju show-status
     juju show-status mysql
     juju show-status nova-*
+    juju show-status --watch 5s
 
 See also:
     machines
@@ -99,6 +123,7 @@ func (c *statusCommand) SetFlags(f *gnuflag.FlagSet) {
 	c.ModelCommandBase.SetFlags(f)
 	f.BoolVar(&c.isoTime, "utc", false, "Display time as UTC in RFC3339 format")
 	f.BoolVar(&c.color, "color", false, "Force use of ANSI color codes")
+	f.DurationVar(&c.watch, "watch", 0, "Display the status again as the model changes, at most this often")
 
 	defaultFormat := "tabular"
 
@@ -114,6 +139,9 @@ func (c *statusCommand) SetFlags(f *gnuflag.FlagSet) {
 }
 
 func (c *statusCommand) Init(args []string) error {
+	if c.watch < 0 {
+		return errors.NotValidf("negative --watch interval")
+	}
 	c.patterns = args
 	// If use of ISO time not specified on command line,
 	// check env var.
@@ -133,6 +161,14 @@ var newAPIClientForStatus = func(c *statusCommand) (statusAPI, error) {
 	return c.NewAPIClient()
 }
 
+var newAllWatcherForStatus = func(apiclient statusAPI) (allWatcher, error) {
+	client, ok := apiclient.(*api.Client)
+	if !ok {
+		return nil, errors.New("watching status is not supported")
+	}
+	return client.WatchAll()
+}
+
 func (c *statusCommand) Run(ctx *cmd.Context) error {
 	apiclient, err := newAPIClientForStatus(c)
 	if err != nil {
@@ -140,6 +176,57 @@ func (c *statusCommand) Run(ctx *cmd.Context) error {
 	}
 	defer apiclient.Close()
 
+	if c.watch > 0 {
+		return c.runWatch(ctx, apiclient)
+	}
+	return c.runStatus(ctx, apiclient)
+}
+
+// runWatch displays the status each time the model changes, waiting
+// at least the watch interval between each display so that a busy
+// model does not cause a stream of full status calls.
+func (c *statusCommand) runWatch(ctx *cmd.Context, apiclient statusAPI) error {
+	watcher, err := newAllWatcherForStatus(apiclient)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer watcher.Stop()
+
+	for first := true; ; first = false {
+		// The first call returns the whole model, so the status
+		// is displayed immediately.
+		if _, err := watcher.Next(); err != nil {
+			return errors.Annotate(err, "watching model")
+		}
+		if !first {
+			c.writeSeparator(ctx)
+		}
+		if err := c.runStatus(ctx, apiclient); err != nil {
+			return errors.Trace(err)
+		}
+		<-c.clock.After(c.watch)
+	}
+}
+
+// writeSeparator marks the start of a new status display: the
+// screen is cleared for the human-readable formats when writing to
+// a terminal, and YAML documents are separated.
+func (c *statusCommand) writeSeparator(ctx *cmd.Context) {
+	switch c.out.Name() {
+	case "json":
+	case "yaml":
+		fmt.Fprintln(ctx.Stdout, "---")
+	default:
+		if f, ok := ctx.Stdout.(*os.File); ok && terminal.IsTerminal(int(f.Fd())) {
+			fmt.Fprint(ctx.Stdout, "\x1b[H\x1b[2J")
+		} else {
+			fmt.Fprintln(ctx.Stdout)
+		}
+	}
+}
+
+// runStatus fetches and displays the status once.
+func (c *statusCommand) runStatus(ctx *cmd.Context, apiclient statusAPI) error {
 	status, err := apiclient.Status(c.patterns)
 	if err != nil {
 		if status == nil {
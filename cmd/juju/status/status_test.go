@@ -14,6 +14,7 @@ import (
 
 	"github.com/juju/cmd"
 	"github.com/juju/cmd/cmdtesting"
+	"github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
 	"github.com/juju/utils"
 	"github.com/juju/version"
@@ -4242,6 +4243,57 @@ func (s *StatusSuite) TestStatusWithNilStatusAPI(c *gc.C) {
 	c.Check(string(stderr), gc.Equals, "ERROR unable to obtain the current status\n")
 }
 
+type fakeAllWatcher struct {
+	changes    int
+	stopCalled bool
+	nextCalled int
+}
+
+func (w *fakeAllWatcher) Next() ([]multiwatcher.Delta, error) {
+	w.nextCalled++
+	if w.nextCalled > w.changes {
+		return nil, errors.New("watcher stopped")
+	}
+	return []multiwatcher.Delta{{}}, nil
+}
+
+func (w *fakeAllWatcher) Stop() error {
+	w.stopCalled = true
+	return nil
+}
+
+func (s *StatusSuite) TestStatusWatch(c *gc.C) {
+	client := &fakeAPIClient{
+		statusReturn: &params.FullStatus{
+			Model: params.ModelStatusInfo{Name: "watched"},
+		},
+	}
+	s.PatchValue(&newAPIClientForStatus, func(_ *statusCommand) (statusAPI, error) {
+		return client, nil
+	})
+	watcher := &fakeAllWatcher{changes: 2}
+	s.PatchValue(&newAllWatcherForStatus, func(apiclient statusAPI) (allWatcher, error) {
+		c.Check(apiclient, gc.Equals, client)
+		return watcher, nil
+	})
+
+	code, stdout, stderr := runStatus(c, "--format", "json", "--watch", "1ms")
+	c.Check(code, gc.Equals, 1)
+	c.Check(string(stderr), gc.Equals, "ERROR watching model: watcher stopped\n")
+	c.Check(watcher.stopCalled, jc.IsTrue)
+
+	// The status is displayed once for each change, one JSON
+	// document per line.
+	lines := strings.Split(strings.TrimSuffix(string(stdout), "\n"), "\n")
+	c.Assert(lines, gc.HasLen, 2)
+	for _, line := range lines {
+		var out M
+		err := json.Unmarshal([]byte(line), &out)
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(out["model"].(map[string]interface{})["name"], gc.Equals, "watched")
+	}
+}
+
 func (s *StatusSuite) TestFormatTabularMetering(c *gc.C) {
 	status := formattedStatus{
 		Applications: map[string]applicationStatus{
@@ -4695,6 +4747,9 @@ var statusInitTests = []struct {
 	}, {
 		envVar: "foo",
 		err:    "invalid JUJU_STATUS_ISO_TIME env var, expected true|false.*",
+	}, {
+		args: []string{"--watch", "-1s"},
+		err:  "negative --watch interval not valid",
 	},
 }
 
@@ -4,16 +4,27 @@
 package user
 
 import (
+	"bytes"
+	"encoding/csv"
 	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
 
 	"github.com/juju/cmd"
 	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+	"github.com/juju/utils/set"
 	"gopkg.in/juju/names.v2"
+	"gopkg.in/yaml.v2"
 
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/cmd/juju/block"
 	"github.com/juju/juju/cmd/juju/common"
 	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/cmd/output"
+	"github.com/juju/juju/permission"
 )
 
 var usageSummary = `
@@ -29,9 +40,36 @@ complete the registration process.
 Some machine providers will require the user to be in possession of certain
 credentials in order to create a model.
 
+Many users can be added at once with --file, which names a YAML file
+listing the users to add, and optionally the access to grant each of
+them to existing models:
+
+    users:
+      - name: bob
+        display-name: Bob Brown
+        models:
+          classroom: write
+          admin/shared: read
+      - name: mary
+
+A file with a .csv extension is read as CSV instead. Its first row
+names the columns, of which only "name" is required, and the models
+column lists the access to grant as space-separated <model>=<access>
+pairs:
+
+    name,display-name,models
+    bob,Bob Brown,classroom=write admin/shared=read
+    mary,,
+
+The file is checked, and the models resolved, before any user is added.
+The registration strings of the added users are written in the format
+given by --format, for distribution by other tools.
+
 Examples:
     juju add-user bob
     juju add-user --controller mycontroller bob
+    juju add-user --file users.yaml --format json
+    juju add-user --file users.csv
 
 See also:
     register
@@ -49,6 +87,13 @@ type AddUserAPI interface {
 	Close() error
 }
 
+// GrantModelAPI defines the modelmanager API methods that the add
+// command uses to grant the users in a file access to models.
+type GrantModelAPI interface {
+	GrantModel(user, access string, modelUUIDs ...string) error
+	Close() error
+}
+
 func NewAddCommand() cmd.Command {
 	return modelcmd.WrapController(&addCommand{})
 }
@@ -57,8 +102,11 @@ func NewAddCommand() cmd.Command {
 type addCommand struct {
 	modelcmd.ControllerCommandBase
 	api         AddUserAPI
+	grantAPI    GrantModelAPI
+	out         cmd.Output
 	User        string
 	DisplayName string
+	File        string
 }
 
 // Info implements Command.Info.
@@ -71,8 +119,21 @@ func (c *addCommand) Info() *cmd.Info {
 	}
 }
 
+// SetFlags implements Command.SetFlags.
+func (c *addCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.ControllerCommandBase.SetFlags(f)
+	f.StringVar(&c.File, "file", "", "Add the users listed in a YAML or CSV file")
+	c.out.AddFlags(f, "yaml", output.DefaultFormatters)
+}
+
 // Init implements Command.Init.
 func (c *addCommand) Init(args []string) error {
+	if c.File != "" {
+		if len(args) > 0 {
+			return errors.New("cannot specify a user name with --file")
+		}
+		return nil
+	}
 	if len(args) == 0 {
 		return errors.Errorf("no username supplied")
 	}
@@ -95,6 +156,9 @@ func (c *addCommand) Run(ctx *cmd.Context) error {
 		}
 		defer api.Close()
 	}
+	if c.File != "" {
+		return c.addUsersFromFile(ctx, api)
+	}
 
 	// Add a user without a password. This will generate a temporary
 	// secret key, which we'll print out for the user to supply to
@@ -130,3 +194,203 @@ func (c *addCommand) Run(ctx *cmd.Context) error {
 
 	return nil
 }
+
+// userSpec describes a user to add, as read from a users file.
+type userSpec struct {
+	Name        string            `yaml:"name"`
+	DisplayName string            `yaml:"display-name,omitempty"`
+	Models      map[string]string `yaml:"models,omitempty"`
+}
+
+// usersFile holds the contents of a file passed with --file.
+type usersFile struct {
+	Users []userSpec `yaml:"users"`
+}
+
+// addedUser describes a user added from a users file, and is
+// written out by the command.
+type addedUser struct {
+	User               string            `yaml:"user" json:"user"`
+	DisplayName        string            `yaml:"display-name,omitempty" json:"display-name,omitempty"`
+	RegistrationString string            `yaml:"registration-string" json:"registration-string"`
+	Models             map[string]string `yaml:"models,omitempty" json:"models,omitempty"`
+}
+
+// readUsersFile reads and validates the users file, and returns the
+// users to add along with the UUIDs of the models they refer to.
+func (c *addCommand) readUsersFile(ctx *cmd.Context) ([]userSpec, map[string]string, error) {
+	data, err := ioutil.ReadFile(ctx.AbsPath(c.File))
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	var users []userSpec
+	if strings.ToLower(filepath.Ext(c.File)) == ".csv" {
+		users, err = parseUsersCSV(data)
+	} else {
+		var file usersFile
+		err = yaml.Unmarshal(data, &file)
+		users = file.Users
+	}
+	if err != nil {
+		return nil, nil, errors.Annotatef(err, "cannot parse %q", c.File)
+	}
+	if len(users) == 0 {
+		return nil, nil, errors.Errorf("no users found in %q", c.File)
+	}
+	seen := set.NewStrings()
+	modelNames := set.NewStrings()
+	for _, user := range users {
+		if !names.IsValidUserName(user.Name) {
+			return nil, nil, errors.NotValidf("user name %q", user.Name)
+		}
+		if seen.Contains(user.Name) {
+			return nil, nil, errors.Errorf("user %q listed more than once", user.Name)
+		}
+		seen.Add(user.Name)
+		for modelName, access := range user.Models {
+			if err := permission.ValidateModelAccess(permission.Access(access)); err != nil {
+				return nil, nil, errors.Annotatef(err, "user %q", user.Name)
+			}
+			modelNames.Add(modelName)
+		}
+	}
+	// Resolve the models before adding any users, so that a mistake
+	// in the file does not leave only some of them added.
+	sortedNames := modelNames.SortedValues()
+	uuids, err := c.ModelUUIDs(sortedNames)
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	modelUUIDs := make(map[string]string)
+	for i, modelName := range sortedNames {
+		modelUUIDs[modelName] = uuids[i]
+	}
+	return users, modelUUIDs, nil
+}
+
+// parseUsersCSV parses the users listed in CSV data. The first
+// record names the columns, which are those of the YAML format; the
+// models column holds space-separated <model>=<access> pairs.
+func parseUsersCSV(data []byte) ([]userSpec, error) {
+	records, err := csv.NewReader(bytes.NewReader(data)).ReadAll()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+	columns := make(map[string]int)
+	for i, column := range records[0] {
+		column = strings.TrimSpace(column)
+		switch column {
+		case "name", "display-name", "models":
+			columns[column] = i
+		default:
+			return nil, errors.Errorf("unknown column %q", column)
+		}
+	}
+	nameColumn, ok := columns["name"]
+	if !ok {
+		return nil, errors.New(`missing "name" column`)
+	}
+	users := make([]userSpec, 0, len(records)-1)
+	for _, record := range records[1:] {
+		user := userSpec{Name: strings.TrimSpace(record[nameColumn])}
+		if i, ok := columns["display-name"]; ok {
+			user.DisplayName = strings.TrimSpace(record[i])
+		}
+		if i, ok := columns["models"]; ok {
+			for _, grant := range strings.Fields(record[i]) {
+				parts := strings.SplitN(grant, "=", 2)
+				if len(parts) != 2 {
+					return nil, errors.Errorf("user %q: expected <model>=<access>, got %q", user.Name, grant)
+				}
+				if user.Models == nil {
+					user.Models = make(map[string]string)
+				}
+				user.Models[parts[0]] = parts[1]
+			}
+		}
+		users = append(users, user)
+	}
+	return users, nil
+}
+
+func (c *addCommand) getGrantAPI() (GrantModelAPI, error) {
+	if c.grantAPI != nil {
+		return c.grantAPI, nil
+	}
+	return c.NewModelManagerAPIClient()
+}
+
+// addUsersFromFile adds each of the users in the users file, grants
+// them their model access, and writes out their registration strings.
+// The users added before any failure are written out too, since their
+// registration strings cannot be retrieved again.
+func (c *addCommand) addUsersFromFile(ctx *cmd.Context, api AddUserAPI) error {
+	users, modelUUIDs, err := c.readUsersFile(ctx)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	var grantAPI GrantModelAPI
+	if len(modelUUIDs) > 0 {
+		grantAPI, err = c.getGrantAPI()
+		if err != nil {
+			return errors.Trace(err)
+		}
+		defer grantAPI.Close()
+	}
+
+	var added []addedUser
+	err = func() error {
+		for _, user := range users {
+			_, secretKey, err := api.AddUser(user.Name, user.DisplayName, "")
+			if err != nil {
+				if params.IsCodeUnauthorized(err) {
+					common.PermissionsMessage(ctx.Stderr, "add a user")
+				}
+				err = errors.Annotatef(err, "adding user %q", user.Name)
+				return block.ProcessBlockedError(err, block.BlockChange)
+			}
+			registrationString, err := generateUserControllerAccessToken(
+				c.ControllerCommandBase,
+				user.Name,
+				secretKey,
+			)
+			if err != nil {
+				return errors.Annotate(err, "generating controller user access token")
+			}
+			added = append(added, addedUser{
+				User:               user.Name,
+				DisplayName:        user.DisplayName,
+				RegistrationString: registrationString,
+			})
+			// Grant access one model at a time, in a stable order,
+			// so that only the grants that succeeded are reported.
+			modelNames := make([]string, 0, len(user.Models))
+			for modelName := range user.Models {
+				modelNames = append(modelNames, modelName)
+			}
+			sort.Strings(modelNames)
+			for _, modelName := range modelNames {
+				access := user.Models[modelName]
+				if err := grantAPI.GrantModel(user.Name, access, modelUUIDs[modelName]); err != nil {
+					err = errors.Annotatef(err, "granting user %q %s access to model %q", user.Name, access, modelName)
+					return block.ProcessBlockedError(err, block.BlockChange)
+				}
+				last := &added[len(added)-1]
+				if last.Models == nil {
+					last.Models = make(map[string]string)
+				}
+				last.Models[modelName] = access
+			}
+		}
+		return nil
+	}()
+	if len(added) > 0 {
+		if writeErr := c.out.Write(ctx, added); writeErr != nil && err == nil {
+			err = writeErr
+		}
+	}
+	return errors.Trace(err)
+}
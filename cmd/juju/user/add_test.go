@@ -4,6 +4,10 @@
 package user_test
 
 import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
 	"strings"
 
 	"github.com/juju/cmd"
@@ -12,11 +16,13 @@ import (
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 	"gopkg.in/juju/names.v2"
+	"gopkg.in/yaml.v2"
 
 	"github.com/juju/juju/api/base"
 	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/cmd/juju/user"
+	"github.com/juju/juju/jujuclient"
 	"github.com/juju/juju/testing"
 )
 
@@ -67,6 +73,11 @@ func (s *UserAddCommandSuite) TestInit(c *gc.C) {
 	}, {
 		args: []string{"foobar"},
 		user: "foobar",
+	}, {
+		args: []string{"--file", "users.yaml"},
+	}, {
+		args:        []string{"--file", "users.yaml", "foobar"},
+		errorString: "cannot specify a user name with --file",
 	}} {
 		c.Logf("test %d (%q)", i, test.args)
 		wrappedCommand, command := user.NewAddCommandForTest(s.mockAPI, s.store, &mockModelAPI{})
@@ -125,6 +136,186 @@ func (s *UserAddCommandSuite) TestUserRegistrationString(c *gc.C) {
 	}
 }
 
+const usersYAML = `
+users:
+  - name: bob
+    display-name: Bob Brown
+    models:
+      classroom: write
+      admin/shared: read
+  - name: mary
+`
+
+const usersCSV = `
+name,display-name,models
+bob,Bob Brown,classroom=write admin/shared=read
+mary,,
+`
+
+func (s *UserAddCommandSuite) writeUsersFile(c *gc.C, content string) string {
+	return s.writeFile(c, "users.yaml", content)
+}
+
+func (s *UserAddCommandSuite) writeFile(c *gc.C, name, content string) string {
+	path := filepath.Join(c.MkDir(), name)
+	err := ioutil.WriteFile(path, []byte(content), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+	return path
+}
+
+func (s *UserAddCommandSuite) runWithGrantAPI(c *gc.C, grantAPI *mockGrantModelAPI, args ...string) (*cmd.Context, error) {
+	s.store.Models["testing"] = &jujuclient.ControllerModels{
+		Models: map[string]jujuclient.ModelDetails{
+			"current-user/classroom": {ModelUUID: "classroom-uuid"},
+			"admin/shared":           {ModelUUID: "shared-uuid"},
+		},
+	}
+	addCommand, _ := user.NewAddCommandWithGrantAPIForTest(s.mockAPI, grantAPI, s.store, &mockModelAPI{})
+	return cmdtesting.RunCommand(c, addCommand, args...)
+}
+
+func (s *UserAddCommandSuite) TestAddUsersFromFile(c *gc.C) {
+	grantAPI := &mockGrantModelAPI{}
+	context, err := s.runWithGrantAPI(c, grantAPI, "--file", s.writeUsersFile(c, usersYAML))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mockAPI.added, jc.DeepEquals, []string{"bob", "mary"})
+	c.Assert(grantAPI.grants, jc.DeepEquals, []string{
+		"bob read shared-uuid",
+		"bob write classroom-uuid",
+	})
+
+	var out []map[string]interface{}
+	err = yaml.Unmarshal([]byte(cmdtesting.Stdout(context)), &out)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(out, gc.HasLen, 2)
+	c.Check(out[0]["user"], gc.Equals, "bob")
+	c.Check(out[0]["display-name"], gc.Equals, "Bob Brown")
+	c.Check(out[0]["registration-string"], gc.Matches, "[A-Za-z0-9]+")
+	c.Check(out[0]["models"], jc.DeepEquals, map[interface{}]interface{}{
+		"admin/shared": "read",
+		"classroom":    "write",
+	})
+	c.Check(out[1]["user"], gc.Equals, "mary")
+	c.Check(out[1]["registration-string"], gc.Matches, "[A-Za-z0-9]+")
+	c.Check(out[1]["models"], gc.IsNil)
+}
+
+func (s *UserAddCommandSuite) TestAddUsersFromFileInvalidAccess(c *gc.C) {
+	path := s.writeUsersFile(c, `
+users:
+  - name: bob
+    models:
+      classroom: superuser
+`)
+	_, err := s.runWithGrantAPI(c, &mockGrantModelAPI{}, "--file", path)
+	c.Assert(err, gc.ErrorMatches, `user "bob": "superuser" model access not valid`)
+	c.Assert(s.mockAPI.added, gc.HasLen, 0)
+}
+
+func (s *UserAddCommandSuite) TestAddUsersFromFileDuplicateUser(c *gc.C) {
+	path := s.writeUsersFile(c, `
+users:
+  - name: bob
+  - name: bob
+`)
+	_, err := s.runWithGrantAPI(c, &mockGrantModelAPI{}, "--file", path)
+	c.Assert(err, gc.ErrorMatches, `user "bob" listed more than once`)
+	c.Assert(s.mockAPI.added, gc.HasLen, 0)
+}
+
+func (s *UserAddCommandSuite) TestAddUsersFromFileNoUsers(c *gc.C) {
+	path := s.writeUsersFile(c, "users: []\n")
+	_, err := s.runWithGrantAPI(c, &mockGrantModelAPI{}, "--file", path)
+	c.Assert(err, gc.ErrorMatches, `no users found in ".*users.yaml"`)
+}
+
+func (s *UserAddCommandSuite) TestAddUsersFromFileReportsAddedOnError(c *gc.C) {
+	grantAPI := &mockGrantModelAPI{err: errors.New("boom")}
+	context, err := s.runWithGrantAPI(c, grantAPI, "--file", s.writeUsersFile(c, usersYAML))
+	c.Assert(err, gc.ErrorMatches, `granting user "bob" read access to model "admin/shared": boom`)
+	c.Assert(s.mockAPI.added, jc.DeepEquals, []string{"bob"})
+
+	// The registration string of the user that was added is not lost.
+	var out []map[string]interface{}
+	err = yaml.Unmarshal([]byte(cmdtesting.Stdout(context)), &out)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(out, gc.HasLen, 1)
+	c.Check(out[0]["user"], gc.Equals, "bob")
+	c.Check(out[0]["registration-string"], gc.Matches, "[A-Za-z0-9]+")
+	c.Check(out[0]["models"], gc.IsNil)
+}
+
+func (s *UserAddCommandSuite) TestAddUsersFromCSVFile(c *gc.C) {
+	grantAPI := &mockGrantModelAPI{}
+	path := s.writeFile(c, "users.csv", usersCSV[1:])
+	context, err := s.runWithGrantAPI(c, grantAPI, "--file", path, "--format", "json")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mockAPI.added, jc.DeepEquals, []string{"bob", "mary"})
+	c.Assert(grantAPI.grants, jc.DeepEquals, []string{
+		"bob read shared-uuid",
+		"bob write classroom-uuid",
+	})
+
+	var out []map[string]interface{}
+	err = json.Unmarshal([]byte(cmdtesting.Stdout(context)), &out)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(out, gc.HasLen, 2)
+	c.Check(out[0]["user"], gc.Equals, "bob")
+	c.Check(out[0]["display-name"], gc.Equals, "Bob Brown")
+	c.Check(out[0]["models"], jc.DeepEquals, map[string]interface{}{
+		"admin/shared": "read",
+		"classroom":    "write",
+	})
+	c.Check(out[1]["user"], gc.Equals, "mary")
+	c.Check(out[1]["display-name"], gc.IsNil)
+	c.Check(out[1]["models"], gc.IsNil)
+}
+
+func (s *UserAddCommandSuite) TestAddUsersFromCSVFileErrors(c *gc.C) {
+	for i, test := range []struct {
+		content string
+		err     string
+	}{{
+		content: "display-name\nBob Brown\n",
+		err:     `cannot parse ".*users.csv": missing "name" column`,
+	}, {
+		content: "name,email\nbob,bob@example.com\n",
+		err:     `cannot parse ".*users.csv": unknown column "email"`,
+	}, {
+		content: "name,models\nbob,classroom\n",
+		err:     `cannot parse ".*users.csv": user "bob": expected <model>=<access>, got "classroom"`,
+	}, {
+		content: "name,display-name\nbob\n",
+		err:     `cannot parse ".*users.csv": .*wrong number of fields.*`,
+	}, {
+		content: "name\n",
+		err:     `no users found in ".*users.csv"`,
+	}} {
+		c.Logf("test %d: %q", i, test.content)
+		path := s.writeFile(c, "users.csv", test.content)
+		_, err := s.runWithGrantAPI(c, &mockGrantModelAPI{}, "--file", path)
+		c.Check(err, gc.ErrorMatches, test.err)
+		c.Check(s.mockAPI.added, gc.HasLen, 0)
+	}
+}
+
+type mockGrantModelAPI struct {
+	err    error
+	grants []string
+}
+
+func (m *mockGrantModelAPI) GrantModel(user, access string, modelUUIDs ...string) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.grants = append(m.grants, fmt.Sprintf("%s %s %s", user, access, strings.Join(modelUUIDs, " ")))
+	return nil
+}
+
+func (*mockGrantModelAPI) Close() error {
+	return nil
+}
+
 type mockModelAPI struct{}
 
 func (m *mockModelAPI) ListModels(user string) ([]base.UserModel, error) {
@@ -167,6 +358,7 @@ type mockAddUserAPI struct {
 	username    string
 	displayname string
 	password    string
+	added       []string
 }
 
 func (m *mockAddUserAPI) AddUser(username, displayname, password string) (names.UserTag, []byte, error) {
@@ -182,6 +374,7 @@ func (m *mockAddUserAPI) AddUser(username, displayname, password string) (names.
 	if m.failMessage != "" {
 		return names.UserTag{}, nil, errors.New(m.failMessage)
 	}
+	m.added = append(m.added, username)
 	return names.NewLocalUserTag(username), m.secretKey, nil
 }
 
@@ -47,7 +47,11 @@ type DisenableUserBase struct {
 }
 
 func NewAddCommandForTest(api AddUserAPI, store jujuclient.ClientStore, modelAPI modelcmd.ModelAPI) (cmd.Command, *AddCommand) {
-	c := &addCommand{api: api}
+	return NewAddCommandWithGrantAPIForTest(api, nil, store, modelAPI)
+}
+
+func NewAddCommandWithGrantAPIForTest(api AddUserAPI, grantAPI GrantModelAPI, store jujuclient.ClientStore, modelAPI modelcmd.ModelAPI) (cmd.Command, *AddCommand) {
+	c := &addCommand{api: api, grantAPI: grantAPI}
 	c.SetClientStore(store)
 	c.SetModelAPI(modelAPI)
 	return modelcmd.WrapController(c), &AddCommand{c}
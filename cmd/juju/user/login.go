@@ -48,6 +48,19 @@ After login, a token ("macaroon") will become active. It has an expiration
 time of 24 hours. Upon expiration, no further Juju commands can be issued
 and the user will be prompted to log in again.
 
+If the controller is configured with an external identity provider
+and no user is given, juju login authenticates with that provider
+instead, opening a web browser to complete the login. No password is
+stored in this case. With a macaroon-based identity manager (see the
+"identity-url" controller setting), the discharged macaroons are kept
+in the client's cookie jar. With an OpenID Connect provider (see the
+"oidc-issuer-url" and "oidc-client-id" controller settings), the user
+is shown a URL and a code to enter there, and the ID and refresh
+tokens issued by the provider are kept with the account; later
+commands refresh the ID token as it expires, and juju logout removes
+them. The --no-browser-login option prevents the web browser from
+being opened.
+
 Aliases
 -------
 
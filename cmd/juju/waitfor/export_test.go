@@ -0,0 +1,32 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package waitfor
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/utils/clock"
+
+	"github.com/juju/juju/cmd/modelcmd"
+)
+
+// NewWaitForCommandForTest returns a wait-for command which uses
+// the api and clock provided.
+func NewWaitForCommandForTest(api WaitForAPI, clock clock.Clock) cmd.Command {
+	c := &waitForCommand{
+		clock: clock,
+		newAPIFunc: func() (WaitForAPI, error) {
+			return api, nil
+		},
+	}
+	return modelcmd.Wrap(c)
+}
+
+// EvalQuery parses the query and evaluates it against the fields.
+func EvalQuery(input string, fields map[string]interface{}) (bool, error) {
+	q, err := parseQuery(input)
+	if err != nil {
+		return false, err
+	}
+	return q.eval(fields)
+}
@@ -0,0 +1,311 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package waitfor
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils/set"
+)
+
+// query is a condition on the fields of an entity.
+type query interface {
+	// eval returns whether the condition holds for the given fields.
+	eval(fields map[string]interface{}) (bool, error)
+
+	// names returns the names of the fields the condition refers to.
+	names() set.Strings
+}
+
+// parseQuery parses a query of the form
+//
+//	field op value [&& field op value]... [|| ...]
+//
+// where op is one of ==, !=, <, <=, > and >=, and value is a quoted
+// string, an integer, true or false. && binds more tightly than ||.
+func parseQuery(input string) (query, error) {
+	tokens, err := tokenize(input)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	p := &parser{tokens: tokens}
+	q, err := p.parseOr()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if !p.done() {
+		return nil, errors.Errorf("unexpected %q", p.peek().text)
+	}
+	return q, nil
+}
+
+type tokenKind int
+
+const (
+	tokenIdent tokenKind = iota
+	tokenString
+	tokenInt
+	tokenOp
+	tokenAnd
+	tokenOr
+)
+
+type token struct {
+	kind tokenKind
+	text string
+}
+
+var operators = []string{"==", "!=", "<=", ">=", "<", ">"}
+
+func tokenize(input string) ([]token, error) {
+	var tokens []token
+	for i := 0; i < len(input); {
+		r := rune(input[i])
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case strings.HasPrefix(input[i:], "&&"):
+			tokens = append(tokens, token{tokenAnd, "&&"})
+			i += 2
+		case strings.HasPrefix(input[i:], "||"):
+			tokens = append(tokens, token{tokenOr, "||"})
+			i += 2
+		case r == '"' || r == '\'':
+			end := strings.IndexRune(input[i+1:], r)
+			if end < 0 {
+				return nil, errors.Errorf("unterminated string at %q", input[i:])
+			}
+			tokens = append(tokens, token{tokenString, input[i+1 : i+1+end]})
+			i += end + 2
+		case r == '-' || unicode.IsDigit(r):
+			j := i + 1
+			for j < len(input) && unicode.IsDigit(rune(input[j])) {
+				j++
+			}
+			tokens = append(tokens, token{tokenInt, input[i:j]})
+			i = j
+		case unicode.IsLetter(r):
+			j := i + 1
+			for j < len(input) && isIdentRune(rune(input[j])) {
+				j++
+			}
+			tokens = append(tokens, token{tokenIdent, input[i:j]})
+			i = j
+		default:
+			op := ""
+			for _, candidate := range operators {
+				if strings.HasPrefix(input[i:], candidate) {
+					op = candidate
+					break
+				}
+			}
+			if op == "" {
+				return nil, errors.Errorf("unexpected character %q", r)
+			}
+			tokens = append(tokens, token{tokenOp, op})
+			i += len(op)
+		}
+	}
+	return tokens, nil
+}
+
+func isIdentRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-' || r == '_'
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) done() bool {
+	return p.pos >= len(p.tokens)
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() (token, error) {
+	if p.done() {
+		return token{}, errors.New("unexpected end of query")
+	}
+	t := p.tokens[p.pos]
+	p.pos++
+	return t, nil
+}
+
+func (p *parser) parseOr() (query, error) {
+	var terms []query
+	for {
+		q, err := p.parseAnd()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		terms = append(terms, q)
+		if p.done() || p.peek().kind != tokenOr {
+			break
+		}
+		p.pos++
+	}
+	if len(terms) == 1 {
+		return terms[0], nil
+	}
+	return orQuery(terms), nil
+}
+
+func (p *parser) parseAnd() (query, error) {
+	var terms []query
+	for {
+		q, err := p.parseComparison()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		terms = append(terms, q)
+		if p.done() || p.peek().kind != tokenAnd {
+			break
+		}
+		p.pos++
+	}
+	if len(terms) == 1 {
+		return terms[0], nil
+	}
+	return andQuery(terms), nil
+}
+
+func (p *parser) parseComparison() (query, error) {
+	field, err := p.next()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if field.kind != tokenIdent {
+		return nil, errors.Errorf("expected field name, got %q", field.text)
+	}
+	op, err := p.next()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if op.kind != tokenOp {
+		return nil, errors.Errorf("expected comparison after %q, got %q", field.text, op.text)
+	}
+	literal, err := p.next()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var value interface{}
+	switch {
+	case literal.kind == tokenString:
+		value = literal.text
+	case literal.kind == tokenInt:
+		n, err := strconv.Atoi(literal.text)
+		if err != nil {
+			return nil, errors.Errorf("invalid number %q", literal.text)
+		}
+		value = n
+	case literal.kind == tokenIdent && (literal.text == "true" || literal.text == "false"):
+		value = literal.text == "true"
+	default:
+		return nil, errors.Errorf("expected value after %q, got %q", field.text+op.text, literal.text)
+	}
+	return &comparison{field: field.text, op: op.text, value: value}, nil
+}
+
+type andQuery []query
+
+func (q andQuery) eval(fields map[string]interface{}) (bool, error) {
+	for _, term := range q {
+		ok, err := term.eval(fields)
+		if err != nil || !ok {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+func (q andQuery) names() set.Strings {
+	return unionNames(q)
+}
+
+type orQuery []query
+
+func (q orQuery) eval(fields map[string]interface{}) (bool, error) {
+	for _, term := range q {
+		ok, err := term.eval(fields)
+		if err != nil || ok {
+			return ok, err
+		}
+	}
+	return false, nil
+}
+
+func (q orQuery) names() set.Strings {
+	return unionNames(q)
+}
+
+func unionNames(terms []query) set.Strings {
+	result := set.NewStrings()
+	for _, term := range terms {
+		result = result.Union(term.names())
+	}
+	return result
+}
+
+// comparison compares a single field with a literal value.
+type comparison struct {
+	field string
+	op    string
+	value interface{}
+}
+
+func (q *comparison) names() set.Strings {
+	return set.NewStrings(q.field)
+}
+
+func (q *comparison) eval(fields map[string]interface{}) (bool, error) {
+	actual, ok := fields[q.field]
+	if !ok {
+		return false, errors.NotFoundf("field %q", q.field)
+	}
+	switch value := q.value.(type) {
+	case int:
+		actual, ok := actual.(int)
+		if !ok {
+			return false, q.typeError()
+		}
+		switch q.op {
+		case "==":
+			return actual == value, nil
+		case "!=":
+			return actual != value, nil
+		case "<":
+			return actual < value, nil
+		case "<=":
+			return actual <= value, nil
+		case ">":
+			return actual > value, nil
+		case ">=":
+			return actual >= value, nil
+		}
+	case string, bool:
+		if fmt.Sprintf("%T", actual) != fmt.Sprintf("%T", value) {
+			return false, q.typeError()
+		}
+		switch q.op {
+		case "==":
+			return actual == value, nil
+		case "!=":
+			return actual != value, nil
+		}
+		return false, errors.Errorf("cannot use %q with %q, which is not a number", q.op, q.field)
+	}
+	return false, errors.Errorf("unknown comparison %q", q.op)
+}
+
+func (q *comparison) typeError() error {
+	return errors.Errorf("cannot compare %q with %#v", q.field, q.value)
+}
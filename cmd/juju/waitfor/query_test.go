@@ -0,0 +1,79 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package waitfor_test
+
+import (
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cmd/juju/waitfor"
+)
+
+type QuerySuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&QuerySuite{})
+
+var queryFields = map[string]interface{}{
+	"status":  "active",
+	"message": "ready",
+	"units":   3,
+	"exposed": false,
+}
+
+func (s *QuerySuite) TestEval(c *gc.C) {
+	for i, test := range []struct {
+		query  string
+		expect bool
+	}{
+		{`status=="active"`, true},
+		{`status == 'active'`, true},
+		{`status!="active"`, false},
+		{`units==3`, true},
+		{`units>3`, false},
+		{`units>=3`, true},
+		{`units<4`, true},
+		{`units<=2`, false},
+		{`units>-1`, true},
+		{`exposed==false`, true},
+		{`status=="active" && units==3`, true},
+		{`status=="active" && units==2`, false},
+		{`status=="blocked" || units==3`, true},
+		{`status=="blocked" || units==2 && exposed==false`, false},
+		{`status=="active" && units==2 || message=="ready"`, true},
+	} {
+		c.Logf("test %d: %s", i, test.query)
+		matched, err := waitfor.EvalQuery(test.query, queryFields)
+		c.Check(err, jc.ErrorIsNil)
+		c.Check(matched, gc.Equals, test.expect)
+	}
+}
+
+func (s *QuerySuite) TestErrors(c *gc.C) {
+	for i, test := range []struct {
+		query string
+		err   string
+	}{
+		{``, `unexpected end of query`},
+		{`status`, `unexpected end of query`},
+		{`status==`, `unexpected end of query`},
+		{`status="active"`, `unexpected character '='`},
+		{`status=="active`, `unterminated string at "\\"active"`},
+		{`status=="active" &&`, `unexpected end of query`},
+		{`status=="active" units==3`, `unexpected "units"`},
+		{`"active"=="active"`, `expected field name, got "active"`},
+		{`status "active"`, `expected comparison after "status", got "active"`},
+		{`status==active`, `expected value after "status==", got "active"`},
+		{`units=="3"`, `cannot compare "units" with "3"`},
+		{`status==3`, `cannot compare "status" with 3`},
+		{`status>"active"`, `cannot use ">" with "status", which is not a number`},
+		{`missing==1`, `field "missing" not found`},
+	} {
+		c.Logf("test %d: %s", i, test.query)
+		_, err := waitfor.EvalQuery(test.query, queryFields)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
@@ -0,0 +1,329 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package waitfor
+
+import (
+	"strings"
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+	"github.com/juju/loggo"
+	"github.com/juju/utils/clock"
+	"github.com/juju/utils/set"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/api"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/state/multiwatcher"
+)
+
+var logger = loggo.GetLogger("juju.cmd.juju.waitfor")
+
+const waitForDoc = `
+Blocks until an entity in the model matches a query, or until the
+timeout expires. The model is watched for changes, so the command
+returns as soon as the condition holds; there is no need to poll
+juju status in scripts and CI pipelines.
+
+The entity is one of "application", "unit" or "machine". The query
+compares the entity's fields with values, and comparisons may be
+combined with && and ||, where && binds more tightly:
+
+    status=="active" && units>=3
+
+Strings are quoted, numbers may be compared with <, <=, > and >=, and
+booleans are written true or false. The fields of each entity are:
+
+    application: name, status, message, life, exposed, charm,
+                 workload-version, units
+    unit:        name, application, status, message, agent-status,
+                 machine
+    machine:     id, status, message, instance-status, instance-id,
+                 life, series
+
+The status of a unit is its workload status, and the status of a
+machine is its agent status. When no query is given, applications and
+units are waited for until they are active, and machines until they
+are started.
+
+Examples:
+    juju wait-for application mysql --query='status=="active" && units==3'
+    juju wait-for unit mysql/0 --query='agent-status=="idle"'
+    juju wait-for machine 0 --timeout=20m
+
+See also:
+    status
+`
+
+// entityFields holds the fields which may be queried
+// for each kind of entity.
+var entityFields = map[string]set.Strings{
+	"application": set.NewStrings(
+		"name", "status", "message", "life", "exposed", "charm",
+		"workload-version", "units",
+	),
+	"unit": set.NewStrings(
+		"name", "application", "status", "message", "agent-status",
+		"machine",
+	),
+	"machine": set.NewStrings(
+		"id", "status", "message", "instance-status", "instance-id",
+		"life", "series",
+	),
+}
+
+// defaultQueries holds the query used for each kind of
+// entity when none is specified.
+var defaultQueries = map[string]string{
+	"application": `status=="active"`,
+	"unit":        `status=="active"`,
+	"machine":     `status=="started"`,
+}
+
+// AllWatcher reports changes to the model.
+type AllWatcher interface {
+	Next() ([]multiwatcher.Delta, error)
+	Stop() error
+}
+
+// WaitForAPI defines the API methods that the wait-for command uses.
+type WaitForAPI interface {
+	Close() error
+	WatchAll() (AllWatcher, error)
+}
+
+// NewWaitForCommand returns a command which waits for an
+// entity in the model to match a query.
+func NewWaitForCommand() cmd.Command {
+	c := &waitForCommand{clock: clock.WallClock}
+	c.newAPIFunc = func() (WaitForAPI, error) {
+		client, err := c.NewAPIClient()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return apiClient{client}, nil
+	}
+	return modelcmd.Wrap(c)
+}
+
+// apiClient adapts an *api.Client to the WaitForAPI interface.
+type apiClient struct {
+	*api.Client
+}
+
+// WatchAll is part of the WaitForAPI interface.
+func (c apiClient) WatchAll() (AllWatcher, error) {
+	return c.Client.WatchAll()
+}
+
+type waitForCommand struct {
+	modelcmd.ModelCommandBase
+
+	kind        string
+	name        string
+	queryString string
+	query       query
+	timeout     time.Duration
+
+	clock      clock.Clock
+	newAPIFunc func() (WaitForAPI, error)
+}
+
+// Info implements Command.
+func (c *waitForCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "wait-for",
+		Args:    "application|unit|machine <name>",
+		Purpose: "Waits for an entity in the model to match a query.",
+		Doc:     waitForDoc,
+	}
+}
+
+// SetFlags implements Command.
+func (c *waitForCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.ModelCommandBase.SetFlags(f)
+	f.StringVar(&c.queryString, "query", "", "Condition to wait for")
+	f.DurationVar(&c.timeout, "timeout", 10*time.Minute, "How long to wait before giving up")
+}
+
+// Init implements Command.
+func (c *waitForCommand) Init(args []string) error {
+	switch len(args) {
+	case 0:
+		return errors.New("no entity kind specified")
+	case 1:
+		return errors.New("no entity name specified")
+	}
+	c.kind, c.name = args[0], args[1]
+	if err := cmd.CheckEmpty(args[2:]); err != nil {
+		return err
+	}
+	fields, ok := entityFields[c.kind]
+	if !ok {
+		return errors.Errorf("unknown entity kind %q; expected application, unit or machine", c.kind)
+	}
+	var valid bool
+	switch c.kind {
+	case "application":
+		valid = names.IsValidApplication(c.name)
+	case "unit":
+		valid = names.IsValidUnit(c.name)
+	case "machine":
+		valid = names.IsValidMachine(c.name)
+	}
+	if !valid {
+		return errors.Errorf("invalid %s name %q", c.kind, c.name)
+	}
+	if c.timeout <= 0 {
+		return errors.New("--timeout must be positive")
+	}
+
+	if c.queryString == "" {
+		c.queryString = defaultQueries[c.kind]
+	}
+	query, err := parseQuery(c.queryString)
+	if err != nil {
+		return errors.Annotate(err, "invalid query")
+	}
+	if unknown := query.names().Difference(fields); !unknown.IsEmpty() {
+		return errors.Errorf(
+			"unknown %s field %q; valid fields are %s",
+			c.kind, unknown.SortedValues()[0], strings.Join(fields.SortedValues(), ", "),
+		)
+	}
+	c.query = query
+	return nil
+}
+
+// Run implements Command.
+func (c *waitForCommand) Run(ctx *cmd.Context) error {
+	client, err := c.newAPIFunc()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer client.Close()
+
+	watcher, err := client.WatchAll()
+	if err != nil {
+		return errors.Annotate(err, "watching model")
+	}
+	defer watcher.Stop()
+
+	type result struct {
+		deltas []multiwatcher.Delta
+		err    error
+	}
+	results := make(chan result)
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		for {
+			deltas, err := watcher.Next()
+			select {
+			case results <- result{deltas, err}:
+			case <-done:
+				return
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+
+	timeout := c.clock.After(c.timeout)
+	entities := make(map[multiwatcher.EntityId]multiwatcher.EntityInfo)
+	for {
+		select {
+		case <-timeout:
+			return errors.Errorf("timed out after %v waiting for %s %q to match %s", c.timeout, c.kind, c.name, c.queryString)
+		case result := <-results:
+			if result.err != nil {
+				return errors.Annotate(result.err, "watching model")
+			}
+			for _, delta := range result.deltas {
+				id := delta.Entity.EntityId()
+				if delta.Removed {
+					delete(entities, id)
+				} else {
+					entities[id] = delta.Entity
+				}
+			}
+			fields := c.fields(entities)
+			if fields == nil {
+				logger.Debugf("%s %q not found", c.kind, c.name)
+				continue
+			}
+			matched, err := c.query.eval(fields)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			if matched {
+				ctx.Infof("%s %q matches %s", c.kind, c.name, c.queryString)
+				return nil
+			}
+		}
+	}
+}
+
+// fields returns the queryable fields of the entity being waited
+// for, or nil if it is not in the model.
+func (c *waitForCommand) fields(entities map[multiwatcher.EntityId]multiwatcher.EntityInfo) map[string]interface{} {
+	for _, entity := range entities {
+		switch info := entity.(type) {
+		case *multiwatcher.ApplicationInfo:
+			if c.kind != "application" || info.Name != c.name {
+				continue
+			}
+			return map[string]interface{}{
+				"name":             info.Name,
+				"status":           string(info.Status.Current),
+				"message":          info.Status.Message,
+				"life":             string(info.Life),
+				"exposed":          info.Exposed,
+				"charm":            info.CharmURL,
+				"workload-version": info.WorkloadVersion,
+				"units":            countUnits(entities, info.Name),
+			}
+		case *multiwatcher.UnitInfo:
+			if c.kind != "unit" || info.Name != c.name {
+				continue
+			}
+			return map[string]interface{}{
+				"name":         info.Name,
+				"application":  info.Application,
+				"status":       string(info.WorkloadStatus.Current),
+				"message":      info.WorkloadStatus.Message,
+				"agent-status": string(info.AgentStatus.Current),
+				"machine":      info.MachineId,
+			}
+		case *multiwatcher.MachineInfo:
+			if c.kind != "machine" || info.Id != c.name {
+				continue
+			}
+			return map[string]interface{}{
+				"id":              info.Id,
+				"status":          string(info.AgentStatus.Current),
+				"message":         info.AgentStatus.Message,
+				"instance-status": string(info.InstanceStatus.Current),
+				"instance-id":     info.InstanceId,
+				"life":            string(info.Life),
+				"series":          info.Series,
+			}
+		}
+	}
+	return nil
+}
+
+// countUnits returns the number of units of the named application.
+func countUnits(entities map[multiwatcher.EntityId]multiwatcher.EntityInfo, application string) int {
+	count := 0
+	for _, entity := range entities {
+		if info, ok := entity.(*multiwatcher.UnitInfo); ok && info.Application == application {
+			count++
+		}
+	}
+	return count
+}
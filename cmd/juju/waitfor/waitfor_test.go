@@ -0,0 +1,241 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package waitfor_test
+
+import (
+	"strings"
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/cmd/cmdtesting"
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cmd/juju/waitfor"
+	"github.com/juju/juju/state/multiwatcher"
+	"github.com/juju/juju/status"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type WaitForSuite struct {
+	testing.IsolationSuite
+	api   *fakeWaitForAPI
+	clock *testing.Clock
+}
+
+var _ = gc.Suite(&WaitForSuite{})
+
+func (s *WaitForSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.api = &fakeWaitForAPI{
+		watcher: &fakeAllWatcher{
+			deltas:  make(chan []multiwatcher.Delta, 10),
+			stopped: make(chan struct{}),
+		},
+	}
+	s.clock = testing.NewClock(time.Now())
+}
+
+func (s *WaitForSuite) runWaitFor(c *gc.C, args ...string) (*cmd.Context, error) {
+	return cmdtesting.RunCommand(c, waitfor.NewWaitForCommandForTest(s.api, s.clock), args...)
+}
+
+func (s *WaitForSuite) send(deltas ...multiwatcher.Delta) {
+	s.api.watcher.deltas <- deltas
+}
+
+func (s *WaitForSuite) TestInitErrors(c *gc.C) {
+	for i, test := range []struct {
+		args []string
+		err  string
+	}{{
+		args: nil,
+		err:  "no entity kind specified",
+	}, {
+		args: []string{"application"},
+		err:  "no entity name specified",
+	}, {
+		args: []string{"application", "mysql", "extra"},
+		err:  `unrecognized args: \["extra"\]`,
+	}, {
+		args: []string{"model", "default"},
+		err:  `unknown entity kind "model"; expected application, unit or machine`,
+	}, {
+		args: []string{"unit", "mysql"},
+		err:  `invalid unit name "mysql"`,
+	}, {
+		args: []string{"machine", "lxd"},
+		err:  `invalid machine name "lxd"`,
+	}, {
+		args: []string{"application", "mysql", "--timeout", "0"},
+		err:  "--timeout must be positive",
+	}, {
+		args: []string{"application", "mysql", "--query", `status=="active" &&`},
+		err:  "invalid query: unexpected end of query",
+	}, {
+		args: []string{"unit", "mysql/0", "--query", `units==3`},
+		err:  `unknown unit field "units"; valid fields are agent-status, application, machine, message, name, status`,
+	}} {
+		c.Logf("test %d: %v", i, test.args)
+		_, err := s.runWaitFor(c, test.args...)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
+
+func (s *WaitForSuite) TestWaitForApplication(c *gc.C) {
+	s.send(
+		applicationDelta("mysql", status.Waiting),
+		unitDelta("mysql/0", status.Waiting),
+	)
+	s.send(
+		applicationDelta("mysql", status.Active),
+		unitDelta("mysql/1", status.Active),
+	)
+	s.send(unitDelta("mysql/2", status.Active))
+
+	ctx, err := s.runWaitFor(c, "application", "mysql", "--query", `status=="active" && units==3`)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(cmdtesting.Stderr(ctx), gc.Equals, `application "mysql" matches status=="active" && units==3`+"\n")
+	c.Check(s.api.watcher.deltas, gc.HasLen, 0)
+	s.api.checkStopped(c)
+}
+
+func (s *WaitForSuite) TestWaitForApplicationUnitRemoved(c *gc.C) {
+	s.send(
+		applicationDelta("mysql", status.Active),
+		unitDelta("mysql/0", status.Active),
+		unitDelta("mysql/1", status.Active),
+	)
+	s.send(multiwatcher.Delta{
+		Removed: true,
+		Entity:  &multiwatcher.UnitInfo{Name: "mysql/1", Application: "mysql"},
+	})
+
+	_, err := s.runWaitFor(c, "application", "mysql", "--query", `units==1`)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(s.api.watcher.deltas, gc.HasLen, 0)
+}
+
+func (s *WaitForSuite) TestWaitForUnitDefaultQuery(c *gc.C) {
+	s.send(unitDelta("mysql/1", status.Active))
+	s.send(unitDelta("mysql/0", status.Maintenance))
+	s.send(unitDelta("mysql/0", status.Active))
+
+	_, err := s.runWaitFor(c, "unit", "mysql/0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(s.api.watcher.deltas, gc.HasLen, 0)
+}
+
+func (s *WaitForSuite) TestWaitForMachine(c *gc.C) {
+	s.send(multiwatcher.Delta{Entity: &multiwatcher.MachineInfo{
+		Id:          "0",
+		AgentStatus: multiwatcher.StatusInfo{Current: status.Started},
+		Life:        multiwatcher.Life("alive"),
+		Series:      "bionic",
+	}})
+
+	_, err := s.runWaitFor(c, "machine", "0", "--query", `status=="started" && series=="bionic"`)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *WaitForSuite) TestQueryError(c *gc.C) {
+	s.send(applicationDelta("mysql", status.Active))
+
+	_, err := s.runWaitFor(c, "application", "mysql", "--query", `units=="3"`)
+	c.Assert(err, gc.ErrorMatches, `cannot compare "units" with "3"`)
+	s.api.checkStopped(c)
+}
+
+func (s *WaitForSuite) TestWatcherError(c *gc.C) {
+	s.api.watcher.err = errors.New("boom")
+
+	_, err := s.runWaitFor(c, "application", "mysql")
+	c.Assert(err, gc.ErrorMatches, "watching model: boom")
+}
+
+func (s *WaitForSuite) TestTimeout(c *gc.C) {
+	s.send(applicationDelta("mysql", status.Waiting))
+
+	errs := make(chan error, 1)
+	go func() {
+		_, err := s.runWaitFor(c, "application", "mysql", "--timeout", "5m")
+		errs <- err
+	}()
+	err := s.clock.WaitAdvance(5*time.Minute, coretesting.LongWait, 1)
+	c.Assert(err, jc.ErrorIsNil)
+
+	select {
+	case err := <-errs:
+		c.Assert(err, gc.ErrorMatches, `timed out after 5m0s waiting for application "mysql" to match status=="active"`)
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for command to finish")
+	}
+	s.api.checkStopped(c)
+}
+
+func applicationDelta(name string, current status.Status) multiwatcher.Delta {
+	return multiwatcher.Delta{Entity: &multiwatcher.ApplicationInfo{
+		Name:   name,
+		Life:   multiwatcher.Life("alive"),
+		Status: multiwatcher.StatusInfo{Current: current},
+	}}
+}
+
+func unitDelta(name string, current status.Status) multiwatcher.Delta {
+	application := name[:strings.Index(name, "/")]
+	return multiwatcher.Delta{Entity: &multiwatcher.UnitInfo{
+		Name:           name,
+		Application:    application,
+		WorkloadStatus: multiwatcher.StatusInfo{Current: current},
+		AgentStatus:    multiwatcher.StatusInfo{Current: status.Idle},
+	}}
+}
+
+type fakeWaitForAPI struct {
+	watcher *fakeAllWatcher
+	closed  bool
+}
+
+func (f *fakeWaitForAPI) Close() error {
+	f.closed = true
+	return nil
+}
+
+func (f *fakeWaitForAPI) WatchAll() (waitfor.AllWatcher, error) {
+	return f.watcher, nil
+}
+
+func (f *fakeWaitForAPI) checkStopped(c *gc.C) {
+	c.Check(f.closed, jc.IsTrue)
+	select {
+	case <-f.watcher.stopped:
+	default:
+		c.Errorf("watcher not stopped")
+	}
+}
+
+type fakeAllWatcher struct {
+	deltas  chan []multiwatcher.Delta
+	stopped chan struct{}
+	err     error
+}
+
+func (w *fakeAllWatcher) Next() ([]multiwatcher.Delta, error) {
+	if w.err != nil {
+		return nil, w.err
+	}
+	select {
+	case deltas := <-w.deltas:
+		return deltas, nil
+	case <-w.stopped:
+		return nil, errors.New("watcher stopped")
+	}
+}
+
+func (w *fakeAllWatcher) Stop() error {
+	close(w.stopped)
+	return nil
+}
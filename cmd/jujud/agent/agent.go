@@ -7,7 +7,9 @@ agent contains jujud's machine agent.
 package agent
 
 import (
+	"strconv"
 	"sync"
+	"time"
 
 	"github.com/juju/cmd"
 	"github.com/juju/errors"
@@ -18,8 +20,62 @@ import (
 
 	"github.com/juju/juju/agent"
 	"github.com/juju/juju/cmd/jujud/util"
+	"github.com/juju/juju/worker/dependency"
 )
 
+// engineShutdownTimeout is how long an agent's dependency engine waits,
+// as the agent stops, for workers such as the uniter and deployer to
+// finish what they are doing before stopping the workers they depend on.
+const engineShutdownTimeout = 30 * time.Second
+
+const (
+	// defaultEngineErrorDelay and defaultEngineBounceDelay are the
+	// restart delays of an agent's dependency engine, unless they are
+	// overridden in the agent config.
+	defaultEngineErrorDelay  = 3 * time.Second
+	defaultEngineBounceDelay = 10 * time.Millisecond
+)
+
+// engineConfig returns the config for an agent's dependency engine,
+// with the restart delays taken from the agent config where they are
+// set there.
+func engineConfig(agentConfig agent.Config) (dependency.EngineConfig, error) {
+	config := dependency.EngineConfig{
+		IsFatal:     util.IsFatal,
+		WorstError:  util.MoreImportantError,
+		ErrorDelay:  defaultEngineErrorDelay,
+		BounceDelay: defaultEngineBounceDelay,
+	}
+	if v := agentConfig.Value(agent.DependencyEngineErrorDelay); v != "" {
+		val, err := time.ParseDuration(v)
+		if err != nil {
+			return dependency.EngineConfig{}, errors.Annotatef(
+				err, "parsing %s", agent.DependencyEngineErrorDelay,
+			)
+		}
+		config.ErrorDelay = val
+	}
+	if v := agentConfig.Value(agent.DependencyEngineBounceDelay); v != "" {
+		val, err := time.ParseDuration(v)
+		if err != nil {
+			return dependency.EngineConfig{}, errors.Annotatef(
+				err, "parsing %s", agent.DependencyEngineBounceDelay,
+			)
+		}
+		config.BounceDelay = val
+	}
+	if v := agentConfig.Value(agent.DependencyEngineMaxRestartBurst); v != "" {
+		val, err := strconv.Atoi(v)
+		if err != nil {
+			return dependency.EngineConfig{}, errors.Annotatef(
+				err, "parsing %s", agent.DependencyEngineMaxRestartBurst,
+			)
+		}
+		config.MaxRestartBurst = val
+	}
+	return config, nil
+}
+
 // AgentConf is a terribly confused interface.
 //
 // Parts of it are a mixin for cmd.Command implementations; others are a mixin
@@ -4,6 +4,8 @@
 package agent
 
 import (
+	"time"
+
 	"github.com/juju/cmd"
 	"github.com/juju/cmd/cmdtesting"
 	"github.com/juju/loggo"
@@ -136,3 +138,50 @@ func (f *fakeLoggingConfig) Value(key string) string {
 	}
 	return ""
 }
+
+type engineConfigSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&engineConfigSuite{})
+
+func (*engineConfigSuite) TestDefaults(c *gc.C) {
+	config, err := engineConfig(&fakeValuesConfig{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(config.ErrorDelay, gc.Equals, 3*time.Second)
+	c.Check(config.BounceDelay, gc.Equals, 10*time.Millisecond)
+	c.Check(config.MaxRestartBurst, gc.Equals, 0)
+}
+
+func (*engineConfigSuite) TestOverrides(c *gc.C) {
+	config, err := engineConfig(&fakeValuesConfig{values: map[string]string{
+		agent.DependencyEngineErrorDelay:      "10s",
+		agent.DependencyEngineBounceDelay:     "1s",
+		agent.DependencyEngineMaxRestartBurst: "5",
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(config.ErrorDelay, gc.Equals, 10*time.Second)
+	c.Check(config.BounceDelay, gc.Equals, time.Second)
+	c.Check(config.MaxRestartBurst, gc.Equals, 5)
+}
+
+func (*engineConfigSuite) TestInvalidValues(c *gc.C) {
+	for key, value := range map[string]string{
+		agent.DependencyEngineErrorDelay:      "soon",
+		agent.DependencyEngineBounceDelay:     "later",
+		agent.DependencyEngineMaxRestartBurst: "lots",
+	} {
+		_, err := engineConfig(&fakeValuesConfig{values: map[string]string{key: value}})
+		c.Check(err, gc.ErrorMatches, "parsing "+key+": .*")
+	}
+}
+
+type fakeValuesConfig struct {
+	agent.Config
+
+	values map[string]string
+}
+
+func (f *fakeValuesConfig) Value(key string) string {
+	return f.values[key]
+}
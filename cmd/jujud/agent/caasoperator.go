@@ -5,7 +5,6 @@ package agent
 
 import (
 	"runtime"
-	"time"
 
 	"github.com/juju/cmd"
 	"github.com/juju/errors"
@@ -133,11 +132,9 @@ func (op *CaasOperatorAgent) Workers() (worker.Worker, error) {
 		PrometheusRegisterer: op.prometheusRegistry,
 	})
 
-	config := dependency.EngineConfig{
-		IsFatal:     cmdutil.IsFatal,
-		WorstError:  cmdutil.MoreImportantError,
-		ErrorDelay:  3 * time.Second,
-		BounceDelay: 10 * time.Millisecond,
+	config, err := engineConfig(op.CurrentConfig())
+	if err != nil {
+		return nil, err
 	}
 	engine, err := dependency.NewEngine(config)
 	if err != nil {
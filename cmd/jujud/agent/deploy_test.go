@@ -16,6 +16,7 @@ import (
 	"github.com/juju/juju/agent"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/deployer"
 )
 
 // fakeManager allows us to test deployments without actually deploying units
@@ -32,13 +33,17 @@ type fakeContext struct {
 	inited      *signal
 }
 
-func (ctx *fakeContext) DeployUnit(unitName, _ string) error {
+func (ctx *fakeContext) DeployUnit(unitName, _ string, _ deployer.ResourceLimits) error {
 	ctx.mu.Lock()
 	ctx.deployed.Add(unitName)
 	ctx.mu.Unlock()
 	return nil
 }
 
+func (ctx *fakeContext) UpdateUnit(_ string, _ deployer.ResourceLimits) error {
+	return nil
+}
+
 func (ctx *fakeContext) RecallUnit(unitName string) error {
 	ctx.mu.Lock()
 	ctx.deployed.Remove(unitName)
@@ -55,6 +60,10 @@ func (ctx *fakeContext) DeployedUnits() ([]string, error) {
 	return ctx.deployed.SortedValues(), nil
 }
 
+func (ctx *fakeContext) CheckUnit(string) error {
+	return nil
+}
+
 func (ctx *fakeContext) waitDeployed(c *gc.C, want ...string) {
 	sort.Strings(want)
 	select {
@@ -83,3 +92,13 @@ func (ctx *fakeContext) waitDeployed(c *gc.C, want ...string) {
 func (ctx *fakeContext) AgentConfig() agent.Config {
 	return ctx.agentConfig
 }
+
+func (ctx *fakeContext) LoggingConfig() string {
+	return ""
+}
+
+func (ctx *fakeContext) SetLoggingConfig(string) {}
+
+func (ctx *fakeContext) SetUnitAgentUsers(bool) {}
+
+func (ctx *fakeContext) SetMaxArchiveAge(time.Duration) {}
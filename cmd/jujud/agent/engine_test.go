@@ -49,6 +49,7 @@ var (
 		"unit-assigner",
 		"remote-relations",
 		"log-forwarder",
+		"valid-credential-flag",
 	}
 	migratingModelWorkers = []string{
 		"environ-tracker",
@@ -58,6 +59,7 @@ var (
 		"model-upgrade-gate",
 		"model-upgraded-flag",
 		"log-forwarder",
+		"valid-credential-flag",
 	}
 	// ReallyLongTimeout should be long enough for the model-tracker
 	// tests that depend on a hosted model; its backing state is not
@@ -68,8 +70,10 @@ var (
 
 	alwaysUnitWorkers = []string{
 		"agent",
+		"agent-conf-watcher",
 		"api-caller",
 		"api-config-watcher",
+		"local-hub",
 		"log-sender",
 		"migration-fortress",
 		"migration-inactive-flag",
@@ -96,9 +100,11 @@ var (
 
 	alwaysMachineWorkers = []string{
 		"agent",
+		"agent-conf-watcher",
 		"api-caller",
 		"api-config-watcher",
 		"clock",
+		"local-hub",
 		"migration-fortress",
 		"migration-inactive-flag",
 		"migration-minion",
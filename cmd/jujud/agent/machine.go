@@ -21,6 +21,7 @@ import (
 	"github.com/juju/utils"
 	utilscert "github.com/juju/utils/cert"
 	"github.com/juju/utils/clock"
+	"github.com/juju/utils/featureflag"
 	"github.com/juju/utils/series"
 	"github.com/juju/utils/set"
 	"github.com/juju/utils/symlink"
@@ -43,7 +44,7 @@ import (
 	apimachiner "github.com/juju/juju/api/machiner"
 	apiprovisioner "github.com/juju/juju/api/provisioner"
 	"github.com/juju/juju/apiserver/params"
-	"github.com/juju/juju/caas/kubernetes/provider"
+	caasall "github.com/juju/juju/caas/all"
 	"github.com/juju/juju/cert"
 	"github.com/juju/juju/cmd/jujud/agent/machine"
 	"github.com/juju/juju/cmd/jujud/agent/model"
@@ -53,6 +54,7 @@ import (
 	"github.com/juju/juju/container/kvm"
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/environs/simplestreams"
+	"github.com/juju/juju/feature"
 	"github.com/juju/juju/instance"
 	jujunames "github.com/juju/juju/juju/names"
 	"github.com/juju/juju/juju/paths"
@@ -359,6 +361,9 @@ type MachineAgent struct {
 	// Only API servers have hubs. This is temporary until the apiserver and
 	// peergrouper have manifolds.
 	centralHub *pubsub.StructuredHub
+
+	// localHub carries events between the workers of this agent.
+	localHub *pubsub.SimpleHub
 }
 
 // Wait waits for the machine agent to finish.
@@ -440,6 +445,7 @@ func (a *MachineAgent) Run(*cmd.Context) error {
 	// When the API server and peergrouper have manifolds, they can
 	// have dependencies on a central hub worker.
 	a.centralHub = centralhub.New(a.Tag().(names.MachineTag))
+	a.localHub = pubsub.NewSimpleHub(nil)
 
 	// Before doing anything else, we need to make sure the certificate generated for
 	// use by mongo to validate controller connections is correct. This needs to be done
@@ -479,12 +485,11 @@ func (a *MachineAgent) Run(*cmd.Context) error {
 
 func (a *MachineAgent) makeEngineCreator(previousAgentVersion version.Number) func() (worker.Worker, error) {
 	return func() (worker.Worker, error) {
-		config := dependency.EngineConfig{
-			IsFatal:     cmdutil.IsFatal,
-			WorstError:  cmdutil.MoreImportantError,
-			ErrorDelay:  3 * time.Second,
-			BounceDelay: 10 * time.Millisecond,
+		config, err := engineConfig(a.CurrentConfig())
+		if err != nil {
+			return nil, err
 		}
+		config.ShutdownTimeout = engineShutdownTimeout
 		engine, err := dependency.NewEngine(config)
 		if err != nil {
 			return nil, err
@@ -532,6 +537,7 @@ func (a *MachineAgent) makeEngineCreator(previousAgentVersion version.Number) fu
 			Agent:                agent.APIHostPortsSetter{Agent: a},
 			RootDir:              a.rootDir,
 			AgentConfigChanged:   a.configChangedVal,
+			ReloadAgentConfig:    a.reloadConfig,
 			UpgradeStepsLock:     a.upgradeComplete,
 			UpgradeCheckLock:     a.initialUpgradeCheckComplete,
 			OpenController:       a.initController,
@@ -545,6 +551,7 @@ func (a *MachineAgent) makeEngineCreator(previousAgentVersion version.Number) fu
 			ValidateMigration:    a.validateMigration,
 			PrometheusRegisterer: a.prometheusRegistry,
 			CentralHub:           a.centralHub,
+			LocalHub:             a.localHub,
 			PubSubReporter:       pubsubReporter,
 			UpdateLoggerConfig:   updateAgentConfLogging,
 			NewAgentStatusSetter: func(apiConn api.Connection) (upgradesteps.StatusSetter, error) {
@@ -617,11 +624,22 @@ func (a *MachineAgent) ChangeConfig(mutate agent.ConfigMutator) error {
 	return errors.Trace(err)
 }
 
+// reloadConfig reads the agent's config from disk, after it has been
+// changed by something other than the agent, and notifies the agent's
+// workers of the change.
+func (a *MachineAgent) reloadConfig() error {
+	if err := a.ReadConfig(a.Tag().String()); err != nil {
+		return errors.Trace(err)
+	}
+	setupAgentLogging(a.CurrentConfig())
+	a.configChangedVal.Set(true)
+	return nil
+}
+
 var (
 	newEnvirons = environs.New
 
-	// TODO(caas) - don't hard code a kubernetes provider
-	newCAASBroker = provider.NewK8sProvider
+	newCAASBroker = caasall.NewContainerBroker
 )
 
 // startAPIWorkers is called to start workers which rely on the
@@ -983,13 +1001,14 @@ func (a *MachineAgent) startModelWorkers(modelUUID string, modelType state.Model
 		return nil, errors.Trace(err)
 	}
 
-	engine, err := dependency.NewEngine(dependency.EngineConfig{
-		IsFatal:     model.IsFatal,
-		WorstError:  model.WorstError,
-		Filter:      model.IgnoreErrRemoved,
-		ErrorDelay:  3 * time.Second,
-		BounceDelay: 10 * time.Millisecond,
-	})
+	config, err := engineConfig(a.CurrentConfig())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	config.IsFatal = model.IsFatal
+	config.WorstError = model.WorstError
+	config.Filter = model.IgnoreErrRemoved
+	engine, err := dependency.NewEngine(config)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
@@ -1276,5 +1295,15 @@ func (a *MachineAgent) uninstallAgent() error {
 // tests can be run without waiting for the 5s watcher refresh time to which we would
 // otherwise be restricted.
 var newDeployContext = func(st *apideployer.State, agentConfig agent.Config) deployer.Context {
-	return deployer.NewSimpleContext(agentConfig, st)
+	if featureflag.Enabled(feature.UnitContainers) {
+		return deployer.NewContainerContext(agentConfig, st)
+	}
+	if featureflag.Enabled(feature.InProcessUnits) {
+		ctx, err := deployer.NewInProcessContext(agentConfig, st, newInProcessUnitAgent(agentConfig.DataDir()))
+		if err == nil {
+			return ctx
+		}
+		logger.Errorf("cannot run unit agents in process, deploying them as services: %v", err)
+	}
+	return deployer.NewSimpleContext(agentConfig, deployer.HostInitSystem(agentConfig.DataDir()), st)
 }
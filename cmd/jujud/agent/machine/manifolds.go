@@ -30,6 +30,7 @@ import (
 	proxyconfig "github.com/juju/juju/utils/proxy"
 	jworker "github.com/juju/juju/worker"
 	"github.com/juju/juju/worker/agent"
+	"github.com/juju/juju/worker/agentconfwatcher"
 	"github.com/juju/juju/worker/apiaddressupdater"
 	"github.com/juju/juju/worker/apicaller"
 	"github.com/juju/juju/worker/apiconfigwatcher"
@@ -42,6 +43,7 @@ import (
 	"github.com/juju/juju/worker/dependency"
 	"github.com/juju/juju/worker/deployer"
 	"github.com/juju/juju/worker/diskmanager"
+	"github.com/juju/juju/worker/diskmonitor"
 	"github.com/juju/juju/worker/externalcontrollerupdater"
 	"github.com/juju/juju/worker/fanconfigurer"
 	"github.com/juju/juju/worker/fortress"
@@ -49,6 +51,7 @@ import (
 	"github.com/juju/juju/worker/globalclockupdater"
 	"github.com/juju/juju/worker/hostkeyreporter"
 	"github.com/juju/juju/worker/identityfilewriter"
+	"github.com/juju/juju/worker/localhub"
 	"github.com/juju/juju/worker/logger"
 	"github.com/juju/juju/worker/logsender"
 	"github.com/juju/juju/worker/machineactions"
@@ -60,6 +63,7 @@ import (
 	"github.com/juju/juju/worker/proxyupdater"
 	psworker "github.com/juju/juju/worker/pubsub"
 	"github.com/juju/juju/worker/reboot"
+	"github.com/juju/juju/worker/rebootmonitor"
 	"github.com/juju/juju/worker/restorewatcher"
 	"github.com/juju/juju/worker/resumer"
 	"github.com/juju/juju/worker/singular"
@@ -70,6 +74,7 @@ import (
 	"github.com/juju/juju/worker/toolsversionchecker"
 	"github.com/juju/juju/worker/txnpruner"
 	"github.com/juju/juju/worker/upgrader"
+	"github.com/juju/juju/worker/upgradeseries"
 	"github.com/juju/juju/worker/upgradesteps"
 )
 
@@ -94,6 +99,10 @@ type ManifoldsConfig struct {
 	// is updated.
 	AgentConfigChanged *voyeur.Value
 
+	// ReloadAgentConfig is called whenever the agent's config file
+	// is changed on disk, to read the file into the running agent.
+	ReloadAgentConfig func() error
+
 	// RootDir is the root directory that any worker that needs to
 	// access local filesystems should use as a base. In actual use it
 	// will be "" but it may be overriden in tests.
@@ -161,6 +170,10 @@ type ManifoldsConfig struct {
 	// CentralHub is the primary hub that exists in the apiserver.
 	CentralHub *pubsub.StructuredHub
 
+	// LocalHub is the agent's own hub, on which workers publish and
+	// subscribe to events within the agent.
+	LocalHub *pubsub.SimpleHub
+
 	// PubSubReporter is the introspection reporter for the pubsub forwarding
 	// worker.
 	PubSubReporter psworker.Reporter
@@ -332,12 +345,32 @@ func Manifolds(config ManifoldsConfig) dependency.Manifolds {
 			SetStatePool:           config.SetStatePool,
 		}),
 
+		// The local-hub manifold provides the agent's own hub to the
+		// workers that want to hear about events in the agent. The
+		// workers that publish those events are mostly upstream of
+		// it, and are given the hub directly.
+		localHubName: localhub.Manifold(localhub.ManifoldConfig{
+			Hub: config.LocalHub,
+		}),
+
+		// The agent-conf-watcher manifold polls the agent's config
+		// file, and reloads the agent's config whenever it is edited,
+		// so that the change takes effect without restarting the agent.
+		agentConfWatcherName: agentconfwatcher.Manifold(agentconfwatcher.ManifoldConfig{
+			AgentName: agentName,
+			Clock:     config.Clock,
+			Interval:  agentconfwatcher.DefaultInterval,
+			Reload:    config.ReloadAgentConfig,
+			NewWorker: agentconfwatcher.NewWorker,
+		}),
+
 		// The api-config-watcher manifold monitors the API server
 		// addresses in the agent config and bounces when they
 		// change. It's required as part of model migrations.
 		apiConfigWatcherName: apiconfigwatcher.Manifold(apiconfigwatcher.ManifoldConfig{
 			AgentName:          agentName,
 			AgentConfigChanged: config.AgentConfigChanged,
+			Hub:                config.LocalHub,
 		}),
 
 		// The certificate-watcher manifold monitors the API server
@@ -360,6 +393,7 @@ func Manifolds(config ManifoldsConfig) dependency.Manifolds {
 			APIOpen:              api.Open,
 			NewConnection:        apicaller.ScaryConnect,
 			Filter:               connectFilter,
+			Hub:                  config.LocalHub,
 		}),
 
 		// The upgrade steps gate is used to coordinate workers which
@@ -398,6 +432,7 @@ func Manifolds(config ManifoldsConfig) dependency.Manifolds {
 			UpgradeStepsGateName: upgradeStepsGateName,
 			UpgradeCheckGateName: upgradeCheckGateName,
 			PreviousAgentVersion: config.PreviousAgentVersion,
+			Hub:                  config.LocalHub,
 		}),
 
 		// The upgradesteps worker runs soon after the machine agent
@@ -564,11 +599,15 @@ func Manifolds(config ManifoldsConfig) dependency.Manifolds {
 		// agents, according to changes in a set of state units; and for the
 		// final removal of its agents' units from state when they are no
 		// longer needed.
-		deployerName: ifNotMigrating(deployer.Manifold(deployer.ManifoldConfig{
-			NewDeployContext: config.NewDeployContext,
-			AgentName:        agentName,
-			APICallerName:    apiCallerName,
-		})),
+		// The deployer is stopped while the disk is nearly full,
+		// so that no new units are deployed, and while the unit
+		// agents are stopped for a series upgrade.
+		deployerName: ifNotMigrating(ifDiskNotFull(ifNotUpgradingSeries(deployer.Manifold(deployer.ManifoldConfig{
+			NewDeployContext:     config.NewDeployContext,
+			AgentName:            agentName,
+			APICallerName:        apiCallerName,
+			PrometheusRegisterer: config.PrometheusRegisterer,
+		})))),
 
 		authenticationWorkerName: ifNotMigrating(authenticationworker.Manifold(authenticationworker.ManifoldConfig{
 			AgentName:     agentName,
@@ -603,12 +642,14 @@ func Manifolds(config ManifoldsConfig) dependency.Manifolds {
 			APICallerName: apiCallerName,
 		})),
 
-		machineActionName: ifNotMigrating(machineactions.Manifold(machineactions.ManifoldConfig{
+		// The machine action runner writes action output to disk,
+		// so is stopped while the disk is nearly full.
+		machineActionName: ifNotMigrating(ifDiskNotFull(machineactions.Manifold(machineactions.ManifoldConfig{
 			AgentName:     agentName,
 			APICallerName: apiCallerName,
 			NewFacade:     machineactions.NewFacade,
 			NewWorker:     machineactions.NewMachineActionsWorker,
-		})),
+		}))),
 
 		hostKeyReporterName: ifNotMigrating(hostkeyreporter.Manifold(hostkeyreporter.ManifoldConfig{
 			AgentName:     agentName,
@@ -618,6 +659,41 @@ func Manifolds(config ManifoldsConfig) dependency.Manifolds {
 			NewWorker:     hostkeyreporter.NewWorker,
 		})),
 
+		// The reboot monitor records unexpected reboots of the
+		// host, detected by changes in its boot id.
+		rebootMonitorName: ifNotMigrating(rebootmonitor.Manifold(rebootmonitor.ManifoldConfig{
+			AgentName:     agentName,
+			APICallerName: apiCallerName,
+			BootIDPath:    rebootmonitor.DefaultBootIDPath,
+			NewFacade:     rebootmonitor.NewFacade,
+			NewWorker:     rebootmonitor.NewWorker,
+		})),
+
+		// The disk monitor reports low disk space in the machine's
+		// status, and its flag stops the workers which need disk
+		// space when the data or log directory's disk is nearly full.
+		diskMonitorName: ifNotMigrating(diskmonitor.Manifold(diskmonitor.ManifoldConfig{
+			AgentName:     agentName,
+			APICallerName: apiCallerName,
+			Clock:         config.Clock,
+			Interval:      diskmonitor.DefaultInterval,
+			LowSpace:      diskmonitor.DefaultLowSpace,
+			CriticalSpace: diskmonitor.DefaultCriticalSpace,
+			FreeSpace:     diskmonitor.FreeSpace,
+			NewFacade:     diskmonitor.NewFacade,
+			NewWorker:     diskmonitor.NewWorker,
+		})),
+
+		// The upgradeseries worker stops and starts the unit agents
+		// around an upgrade of the machine's operating system, and
+		// records the new series once the upgrade is complete.
+		upgradeSeriesName: ifNotMigrating(upgradeseries.Manifold(upgradeseries.ManifoldConfig{
+			AgentName:     agentName,
+			APICallerName: apiCallerName,
+			NewFacade:     upgradeseries.NewFacade,
+			NewWorker:     upgradeseries.NewWorker,
+		})),
+
 		externalControllerUpdaterName: ifNotMigrating(ifPrimaryController(externalcontrollerupdater.Manifold(
 			externalcontrollerupdater.ManifoldConfig{
 				APICallerName:                      apiCallerName,
@@ -720,8 +796,21 @@ var ifController = engine.Housing{
 	},
 }.Decorate
 
+var ifDiskNotFull = engine.Housing{
+	Flags: []string{
+		diskMonitorName,
+	},
+}.Decorate
+
+var ifNotUpgradingSeries = engine.Housing{
+	Flags: []string{
+		upgradeSeriesName,
+	},
+}.Decorate
+
 const (
 	agentName              = "agent"
+	agentConfWatcherName   = "agent-conf-watcher"
 	terminationName        = "termination-signal-handler"
 	stateConfigWatcherName = "state-config-watcher"
 	controllerName         = "controller"
@@ -729,6 +818,7 @@ const (
 	apiCallerName          = "api-caller"
 	apiConfigWatcherName   = "api-config-watcher"
 	centralHubName         = "central-hub"
+	localHubName           = "local-hub"
 	pubSubName             = "pubsub-forwarder"
 	clockName              = "clock"
 
@@ -746,6 +836,9 @@ const (
 	servingInfoSetterName         = "serving-info-setter"
 	apiWorkersName                = "unconverted-api-workers"
 	rebootName                    = "reboot-executor"
+	rebootMonitorName             = "reboot-monitor"
+	diskMonitorName               = "disk-monitor"
+	upgradeSeriesName             = "upgrade-series"
 	loggingConfigUpdaterName      = "logging-config-updater"
 	diskManagerName               = "disk-manager"
 	proxyConfigUpdater            = "proxy-config-updater"
@@ -44,6 +44,7 @@ func (*ManifoldsSuite) TestManifoldNames(c *gc.C) {
 	sort.Strings(keys)
 	expectedKeys := []string{
 		"agent",
+		"agent-conf-watcher",
 		"api-address-updater",
 		"api-caller",
 		"api-config-watcher",
@@ -53,12 +54,14 @@ func (*ManifoldsSuite) TestManifoldNames(c *gc.C) {
 		"certificate-watcher",
 		"clock",
 		"disk-manager",
+		"disk-monitor",
 		"external-controller-updater",
 		"fan-configurer",
 		"global-clock-updater",
 		"host-key-reporter",
 		"is-controller-flag",
 		"is-primary-controller-flag",
+		"local-hub",
 		"log-pruner",
 		"log-sender",
 		"logging-config-updater",
@@ -73,6 +76,7 @@ func (*ManifoldsSuite) TestManifoldNames(c *gc.C) {
 		"proxy-config-updater",
 		"pubsub-forwarder",
 		"reboot-executor",
+		"reboot-monitor",
 		"restore-watcher",
 		"serving-info-setter",
 		"ssh-authkeys-updater",
@@ -87,6 +91,7 @@ func (*ManifoldsSuite) TestManifoldNames(c *gc.C) {
 		"unit-agent-deployer",
 		"upgrade-check-flag",
 		"upgrade-check-gate",
+		"upgrade-series",
 		"upgrade-steps-flag",
 		"upgrade-steps-gate",
 		"upgrade-steps-runner",
@@ -109,6 +114,7 @@ func (*ManifoldsSuite) TestUpgradesBlockMigration(c *gc.C) {
 func (*ManifoldsSuite) TestMigrationGuardsUsed(c *gc.C) {
 	exempt := set.NewStrings(
 		"agent",
+		"agent-conf-watcher",
 		"api-caller",
 		"api-config-watcher",
 		"api-server",
@@ -119,6 +125,7 @@ func (*ManifoldsSuite) TestMigrationGuardsUsed(c *gc.C) {
 		"global-clock-updater",
 		"is-controller-flag",
 		"is-primary-controller-flag",
+		"local-hub",
 		"log-forwarder",
 		"model-worker-manager",
 		"peer-grouper",
@@ -149,6 +156,26 @@ func (*ManifoldsSuite) TestMigrationGuardsUsed(c *gc.C) {
 	}
 }
 
+func (*ManifoldsSuite) TestDiskGuardsUsed(c *gc.C) {
+	manifolds := machine.Manifolds(machine.ManifoldsConfig{
+		Agent: &mockAgent{},
+	})
+	for _, name := range []string{
+		"machine-action-runner",
+		"unit-agent-deployer",
+	} {
+		c.Logf(name)
+		checkContains(c, manifolds[name].Inputs, "disk-monitor")
+	}
+}
+
+func (*ManifoldsSuite) TestUpgradeSeriesGuardsUsed(c *gc.C) {
+	manifolds := machine.Manifolds(machine.ManifoldsConfig{
+		Agent: &mockAgent{},
+	})
+	checkContains(c, manifolds["unit-agent-deployer"].Inputs, "upgrade-series")
+}
+
 func (*ManifoldsSuite) TestSingularGuardsUsed(c *gc.C) {
 	manifolds := machine.Manifolds(machine.ManifoldsConfig{
 		Agent: &mockAgent{},
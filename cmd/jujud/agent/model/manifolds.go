@@ -17,6 +17,7 @@ import (
 	"github.com/juju/juju/api/base"
 	caasfirewallerapi "github.com/juju/juju/api/caasfirewaller"
 	caasunitprovisionerapi "github.com/juju/juju/api/caasunitprovisioner"
+	logsenderapi "github.com/juju/juju/api/logsender"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/caas"
 	"github.com/juju/juju/cmd/jujud/agent/engine"
@@ -30,10 +31,13 @@ import (
 	"github.com/juju/juju/worker/caasbroker"
 	"github.com/juju/juju/worker/caasmodelupgrader"
 	"github.com/juju/juju/worker/caasoperatorprovisioner"
+	"github.com/juju/juju/worker/caasoperatorupgrader"
+	"github.com/juju/juju/worker/caasunitlogger"
 	"github.com/juju/juju/worker/caasunitprovisioner"
 	"github.com/juju/juju/worker/charmrevision"
 	"github.com/juju/juju/worker/charmrevision/charmrevisionmanifold"
 	"github.com/juju/juju/worker/cleaner"
+	"github.com/juju/juju/worker/credentialvalidator"
 	"github.com/juju/juju/worker/dependency"
 	"github.com/juju/juju/worker/environ"
 	"github.com/juju/juju/worker/firewaller"
@@ -225,8 +229,8 @@ func commonManifolds(config ManifoldsConfig) dependency.Manifolds {
 
 		// The undertaker is currently the only ifNotAlive worker.
 		undertakerName: ifNotUpgrading(ifNotAlive(undertaker.Manifold(undertaker.ManifoldConfig{
-			APICallerName: apiCallerName,
-			EnvironName:   environTrackerName,
+			APICallerName:      apiCallerName,
+			CloudDestroyerName: environTrackerName,
 
 			NewFacade: undertaker.NewFacade,
 			NewWorker: undertaker.NewWorker,
@@ -304,6 +308,15 @@ func IAASManifolds(config ManifoldsConfig) dependency.Manifolds {
 			NewEnvironFunc: config.NewEnvironFunc,
 		})),
 
+		// The valid credential flag is set while the model's cloud
+		// credential is usable, and gates the workers that use it to
+		// talk to the cloud. While it is not, the model is suspended.
+		validCredentialFlagName: ifNotDead(credentialvalidator.Manifold(credentialvalidator.ManifoldConfig{
+			APICallerName: apiCallerName,
+			NewFacade:     credentialvalidator.NewFacade,
+			NewWorker:     credentialvalidator.NewWorker,
+		})),
+
 		// Everything else should be wrapped in ifResponsible,
 		// ifNotAlive, ifNotDead, or ifNotMigrating (which also
 		// implies NotDead), to ensure that only a single
@@ -328,19 +341,19 @@ func IAASManifolds(config ManifoldsConfig) dependency.Manifolds {
 		// it.
 
 		// All the rest depend on ifNotMigrating.
-		computeProvisionerName: ifNotMigrating(provisioner.Manifold(provisioner.ManifoldConfig{
+		computeProvisionerName: ifCredentialValid(ifNotMigrating(provisioner.Manifold(provisioner.ManifoldConfig{
 			AgentName:          agentName,
 			APICallerName:      apiCallerName,
 			EnvironName:        environTrackerName,
 			NewProvisionerFunc: provisioner.NewEnvironProvisioner,
-		})),
-		storageProvisionerName: ifNotMigrating(storageprovisioner.ModelManifold(storageprovisioner.ModelManifoldConfig{
+		}))),
+		storageProvisionerName: ifCredentialValid(ifNotMigrating(storageprovisioner.ModelManifold(storageprovisioner.ModelManifoldConfig{
 			APICallerName: apiCallerName,
 			ClockName:     clockName,
 			EnvironName:   environTrackerName,
 			Scope:         modelTag,
-		})),
-		firewallerName: ifNotMigrating(firewaller.Manifold(firewaller.ManifoldConfig{
+		}))),
+		firewallerName: ifCredentialValid(ifNotMigrating(firewaller.Manifold(firewaller.ManifoldConfig{
 			AgentName:               agentName,
 			APICallerName:           apiCallerName,
 			EnvironName:             environTrackerName,
@@ -349,7 +362,7 @@ func IAASManifolds(config ManifoldsConfig) dependency.Manifolds {
 			NewFirewallerWorker:      firewaller.NewWorker,
 			NewFirewallerFacade:      firewaller.NewFirewallerFacade,
 			NewRemoteRelationsFacade: firewaller.NewRemoteRelationsFacade,
-		})),
+		}))),
 		unitAssignerName: ifNotMigrating(unitassigner.Manifold(unitassigner.ManifoldConfig{
 			APICallerName: apiCallerName,
 		})),
@@ -417,6 +430,14 @@ func CAASManifolds(config ManifoldsConfig) dependency.Manifolds {
 				NewWorker:     caasoperatorprovisioner.NewProvisionerWorker,
 			},
 		)),
+		caasOperatorUpgraderName: ifNotMigrating(caasoperatorupgrader.Manifold(
+			caasoperatorupgrader.ManifoldConfig{
+				APICallerName: apiCallerName,
+				BrokerName:    caasBrokerTrackerName,
+				Clock:         config.Clock,
+				NewWorker:     caasoperatorupgrader.NewWorker,
+			},
+		)),
 		caasUnitProvisionerName: ifNotMigrating(caasunitprovisioner.Manifold(
 			caasunitprovisioner.ManifoldConfig{
 				APICallerName: apiCallerName,
@@ -427,6 +448,29 @@ func CAASManifolds(config ManifoldsConfig) dependency.Manifolds {
 				NewWorker: caasunitprovisioner.NewWorker,
 			},
 		)),
+		caasUnitLoggerName: ifNotMigrating(caasunitlogger.Manifold(
+			caasunitlogger.ManifoldConfig{
+				APICallerName: apiCallerName,
+				BrokerName:    caasBrokerTrackerName,
+				Clock:         config.Clock,
+				NewClient: func(caller base.APICaller) caasunitlogger.Client {
+					return caasfirewallerapi.NewClient(caller)
+				},
+				NewLogWriter: func(caller base.APICaller) (logsenderapi.LogWriter, error) {
+					return logsenderapi.NewAPI(caller).LogWriter()
+				},
+				NewWorker: caasunitlogger.NewWorker,
+			},
+		)),
+		// The undertaker destroys the model's namespace
+		// rather than an environ in CAAS models.
+		undertakerName: ifNotUpgrading(ifNotAlive(undertaker.Manifold(undertaker.ManifoldConfig{
+			APICallerName:      apiCallerName,
+			CloudDestroyerName: caasBrokerTrackerName,
+
+			NewFacade: undertaker.NewFacade,
+			NewWorker: undertaker.NewWorker,
+		}))),
 		modelUpgraderName: caasmodelupgrader.Manifold(caasmodelupgrader.ManifoldConfig{
 			APICallerName: apiCallerName,
 			GateName:      modelUpgradeGateName,
@@ -509,6 +553,14 @@ var (
 			modelUpgradedFlagName,
 		},
 	}.Decorate
+
+	// ifCredentialValid wraps a manifold such that it only runs if
+	// the model's cloud credential is valid.
+	ifCredentialValid = engine.Housing{
+		Flags: []string{
+			validCredentialFlagName,
+		},
+	}.Decorate
 )
 
 const (
@@ -521,6 +573,8 @@ const (
 	notDeadFlagName       = "not-dead-flag"
 	notAliveFlagName      = "not-alive-flag"
 
+	validCredentialFlagName = "valid-credential-flag"
+
 	migrationFortressName     = "migration-fortress"
 	migrationInactiveFlagName = "migration-inactive-flag"
 	migrationMasterName       = "migration-master"
@@ -548,6 +602,8 @@ const (
 
 	caasFirewallerName          = "caas-firewaller"
 	caasOperatorProvisionerName = "caas-operator-provisioner"
+	caasOperatorUpgraderName    = "caas-operator-upgrader"
 	caasUnitProvisionerName     = "caas-unit-provisioner"
+	caasUnitLoggerName          = "caas-unit-logger"
 	caasBrokerTrackerName       = "caas-broker-tracker"
 )
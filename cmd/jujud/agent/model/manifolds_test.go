@@ -60,6 +60,7 @@ func (s *ManifoldsSuite) TestIAASNames(c *gc.C) {
 		"storage-provisioner",
 		"undertaker",
 		"unit-assigner",
+		"valid-credential-flag",
 	})
 }
 
@@ -81,6 +82,8 @@ func (s *ManifoldsSuite) TestCAASNames(c *gc.C) {
 		"caas-broker-tracker",
 		"caas-firewaller",
 		"caas-operator-provisioner",
+		"caas-operator-upgrader",
+		"caas-unit-logger",
 		"caas-unit-provisioner",
 		"charm-revision-updater",
 		"clock",
@@ -144,6 +147,21 @@ func (s *ManifoldsSuite) TestStateCleanerIgnoresLifeFlags(c *gc.C) {
 	c.Check(inputs.Contains("not-dead-flag"), jc.IsFalse)
 }
 
+func (s *ManifoldsSuite) TestUndertakerCloudDestroyer(c *gc.C) {
+	manifolds := model.IAASManifolds(model.ManifoldsConfig{
+		Agent: &mockAgent{},
+	})
+	inputs := set.NewStrings(manifolds["undertaker"].Inputs...)
+	c.Check(inputs.Contains("environ-tracker"), jc.IsTrue)
+
+	manifolds = model.CAASManifolds(model.ManifoldsConfig{
+		Agent: &mockAgent{},
+	})
+	inputs = set.NewStrings(manifolds["undertaker"].Inputs...)
+	c.Check(inputs.Contains("caas-broker-tracker"), jc.IsTrue)
+	c.Check(inputs.Contains("environ-tracker"), jc.IsFalse)
+}
+
 func (s *ManifoldsSuite) TestClockWrapper(c *gc.C) {
 	expectClock := &fakeClock{}
 	manifolds := model.IAASManifolds(model.ManifoldsConfig{
@@ -9,6 +9,7 @@ import (
 	"github.com/juju/cmd"
 	"github.com/juju/errors"
 	"github.com/juju/gnuflag"
+	"github.com/juju/pubsub"
 	"github.com/juju/utils/voyeur"
 	"github.com/prometheus/client_golang/prometheus"
 	"gopkg.in/juju/names.v2"
@@ -24,6 +25,7 @@ import (
 	"github.com/juju/juju/upgrades"
 	jworker "github.com/juju/juju/worker"
 	"github.com/juju/juju/worker/dependency"
+	"github.com/juju/juju/worker/deployer"
 	"github.com/juju/juju/worker/gate"
 	"github.com/juju/juju/worker/introspection"
 	"github.com/juju/juju/worker/logsender"
@@ -166,11 +168,19 @@ func (a *UnitAgent) APIWorkers() (worker.Worker, error) {
 	agentConfig := a.AgentConf.CurrentConfig()
 	a.upgradeComplete = upgradesteps.NewLock(agentConfig)
 
+	// Unit agents run in process by the machine agent have no
+	// buffered logger; the machine agent sends their logs.
+	var logSource logsender.LogRecordCh
+	if a.bufferedLogger != nil {
+		logSource = a.bufferedLogger.Logs()
+	}
 	manifolds := unitManifolds(unit.ManifoldsConfig{
 		Agent:                agent.APIHostPortsSetter{a},
-		LogSource:            a.bufferedLogger.Logs(),
+		LogSource:            logSource,
 		LeadershipGuarantee:  30 * time.Second,
 		AgentConfigChanged:   a.configChangedVal,
+		ReloadAgentConfig:    a.reloadConfig,
+		LocalHub:             pubsub.NewSimpleHub(nil),
 		ValidateMigration:    a.validateMigration,
 		PrometheusRegisterer: a.prometheusRegistry,
 		UpdateLoggerConfig:   updateAgentConfLogging,
@@ -180,12 +190,11 @@ func (a *UnitAgent) APIWorkers() (worker.Worker, error) {
 		UpgradeCheckLock:     a.initialUpgradeCheckComplete,
 	})
 
-	config := dependency.EngineConfig{
-		IsFatal:     cmdutil.IsFatal,
-		WorstError:  cmdutil.MoreImportantError,
-		ErrorDelay:  3 * time.Second,
-		BounceDelay: 10 * time.Millisecond,
+	config, err := engineConfig(agentConfig)
+	if err != nil {
+		return nil, err
 	}
+	config.ShutdownTimeout = engineShutdownTimeout
 	engine, err := dependency.NewEngine(config)
 	if err != nil {
 		return nil, err
@@ -212,6 +221,30 @@ func (a *UnitAgent) APIWorkers() (worker.Worker, error) {
 	return engine, nil
 }
 
+// newInProcessUnitAgent returns a deployer.NewUnitAgentFunc which runs
+// the unit agents whose files are in the specified data directory in
+// the current process, each with its own dependency engine.
+func newInProcessUnitAgent(dataDir string) deployer.NewUnitAgentFunc {
+	return func(unitName string) (worker.Worker, error) {
+		prometheusRegistry, err := newPrometheusRegistry()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		a := &UnitAgent{
+			AgentConf:                   NewAgentConf(dataDir),
+			configChangedVal:            voyeur.NewValue(true),
+			UnitName:                    unitName,
+			initialUpgradeCheckComplete: gate.NewLock(),
+			prometheusRegistry:          prometheusRegistry,
+			preUpgradeSteps:             upgrades.PreUpgradeSteps,
+		}
+		if err := a.ReadConfig(a.Tag().String()); err != nil {
+			return nil, errors.Trace(err)
+		}
+		return a.APIWorkers()
+	}
+}
+
 func (a *UnitAgent) Tag() names.Tag {
 	return names.NewUnitTag(a.UnitName)
 }
@@ -222,6 +255,19 @@ func (a *UnitAgent) ChangeConfig(mutate agent.ConfigMutator) error {
 	return errors.Trace(err)
 }
 
+// reloadConfig reads the agent's config from disk, after it has been
+// changed by something other than the agent, and notifies the agent's
+// workers of the change. Logging is not reconfigured, because unit
+// agents may share their process, and its logging config, with the
+// machine agent.
+func (a *UnitAgent) reloadConfig() error {
+	if err := a.ReadConfig(a.Tag().String()); err != nil {
+		return errors.Trace(err)
+	}
+	a.configChangedVal.Set(true)
+	return nil
+}
+
 // validateMigration is called by the migrationminion to help check
 // that the agent will be ok when connected to a new controller.
 func (a *UnitAgent) validateMigration(apiCaller base.APICaller) error {
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/juju/errors"
+	"github.com/juju/pubsub"
 	"github.com/juju/utils/clock"
 	"github.com/juju/utils/voyeur"
 	"github.com/juju/version"
@@ -22,6 +23,7 @@ import (
 	"github.com/juju/juju/utils/proxy"
 	"github.com/juju/juju/worker"
 	"github.com/juju/juju/worker/agent"
+	"github.com/juju/juju/worker/agentconfwatcher"
 	"github.com/juju/juju/worker/apiaddressupdater"
 	"github.com/juju/juju/worker/apicaller"
 	"github.com/juju/juju/worker/apiconfigwatcher"
@@ -29,6 +31,7 @@ import (
 	"github.com/juju/juju/worker/fortress"
 	"github.com/juju/juju/worker/gate"
 	"github.com/juju/juju/worker/leadership"
+	"github.com/juju/juju/worker/localhub"
 	"github.com/juju/juju/worker/logger"
 	"github.com/juju/juju/worker/logsender"
 	"github.com/juju/juju/worker/meterstatus"
@@ -61,6 +64,14 @@ type ManifoldsConfig struct {
 	// is updated.
 	AgentConfigChanged *voyeur.Value
 
+	// ReloadAgentConfig is called whenever the agent's config file
+	// is changed on disk, to read the file into the running agent.
+	ReloadAgentConfig func() error
+
+	// LocalHub is the agent's own hub, on which workers publish and
+	// subscribe to events within the agent.
+	LocalHub *pubsub.SimpleHub
+
 	// ValidateMigration is called by the migrationminion during the
 	// migration process to check that the agent will be ok when
 	// connected to the new target controller.
@@ -122,12 +133,32 @@ func Manifolds(config ManifoldsConfig) dependency.Manifolds {
 		// (Currently, that is "all manifolds", but consider a shared clock.)
 		agentName: agent.Manifold(config.Agent),
 
+		// The local-hub manifold provides the agent's own hub to the
+		// workers that want to hear about events in the agent. The
+		// workers that publish those events are mostly upstream of
+		// it, and are given the hub directly.
+		localHubName: localhub.Manifold(localhub.ManifoldConfig{
+			Hub: config.LocalHub,
+		}),
+
+		// The agent-conf-watcher manifold polls the agent's config
+		// file, and reloads the agent's config whenever it is edited,
+		// so that the change takes effect without restarting the agent.
+		agentConfWatcherName: agentconfwatcher.Manifold(agentconfwatcher.ManifoldConfig{
+			AgentName: agentName,
+			Clock:     clock.WallClock,
+			Interval:  agentconfwatcher.DefaultInterval,
+			Reload:    config.ReloadAgentConfig,
+			NewWorker: agentconfwatcher.NewWorker,
+		}),
+
 		// The api-config-watcher manifold monitors the API server
 		// addresses in the agent config and bounces when they
 		// change. It's required as part of model migrations.
 		apiConfigWatcherName: apiconfigwatcher.Manifold(apiconfigwatcher.ManifoldConfig{
 			AgentName:          agentName,
 			AgentConfigChanged: config.AgentConfigChanged,
+			Hub:                config.LocalHub,
 		}),
 
 		// The api caller is a thin concurrent wrapper around a connection
@@ -141,6 +172,7 @@ func Manifolds(config ManifoldsConfig) dependency.Manifolds {
 			APIOpen:              api.Open,
 			NewConnection:        apicaller.ScaryConnect,
 			Filter:               connectFilter,
+			Hub:                  config.LocalHub,
 		}),
 
 		// The log sender is a leaf worker that sends log messages to some
@@ -186,6 +218,7 @@ func Manifolds(config ManifoldsConfig) dependency.Manifolds {
 			UpgradeStepsGateName: upgradeStepsGateName,
 			UpgradeCheckGateName: upgradeCheckGateName,
 			PreviousAgentVersion: config.PreviousAgentVersion,
+			Hub:                  config.LocalHub,
 		}),
 
 		// The upgradesteps worker runs soon after the unit agent
@@ -355,6 +388,8 @@ var ifNotMigrating = engine.Housing{
 
 const (
 	agentName            = "agent"
+	agentConfWatcherName = "agent-conf-watcher"
+	localHubName         = "local-hub"
 	apiConfigWatcherName = "api-config-watcher"
 	apiCallerName        = "api-caller"
 	logSenderName        = "log-sender"
@@ -35,8 +35,10 @@ func (s *ManifoldsSuite) TestManifoldNames(c *gc.C) {
 	manifolds := unit.Manifolds(config)
 	expectedKeys := []string{
 		"agent",
+		"agent-conf-watcher",
 		"api-config-watcher",
 		"api-caller",
+		"local-hub",
 		"log-sender",
 		"upgrader",
 		"migration-fortress",
@@ -69,9 +71,11 @@ func (s *ManifoldsSuite) TestManifoldNames(c *gc.C) {
 func (*ManifoldsSuite) TestMigrationGuards(c *gc.C) {
 	exempt := set.NewStrings(
 		"agent",
+		"agent-conf-watcher",
 		"machine-lock",
 		"api-config-watcher",
 		"api-caller",
+		"local-hub",
 		"log-sender",
 		"upgrader",
 		"migration-fortress",
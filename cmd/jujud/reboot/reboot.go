@@ -25,6 +25,7 @@ import (
 	"github.com/juju/juju/instance"
 	"github.com/juju/juju/service"
 	"github.com/juju/juju/service/common"
+	"github.com/juju/juju/worker/rebootmonitor"
 )
 
 var logger = loggo.GetLogger("juju.cmd.jujud.reboot")
@@ -85,6 +86,11 @@ func (r *Reboot) ExecuteReboot(action params.RebootAction) error {
 		return errors.Trace(err)
 	}
 
+	// The machine agent will find that the host rebooted, even
+	// after a shutdown, when it next starts.
+	if err := rebootmonitor.RecordExpectedReboot(r.acfg.DataDir()); err != nil {
+		return errors.Trace(err)
+	}
 	if err := scheduleAction(action, rebootAfter); err != nil {
 		return errors.Trace(err)
 	}
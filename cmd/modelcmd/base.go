@@ -13,6 +13,7 @@ import (
 	"github.com/juju/cmd"
 	"github.com/juju/errors"
 	"github.com/juju/gnuflag"
+	"github.com/juju/utils/clock"
 	"golang.org/x/crypto/ssh/terminal"
 	"gopkg.in/juju/names.v2"
 	"gopkg.in/macaroon-bakery.v1/httpbakery"
@@ -200,10 +201,20 @@ func (c *CommandBase) NewAPIConnectionParams(
 		}
 	}
 
+	tokens := &tokenSource{
+		store:          store,
+		controllerName: controllerName,
+		ctx:            c.cmdContext,
+		noBrowser:      c.authOpts.NoBrowser,
+		clock:          clock.WallClock,
+		newClient:      newOIDCClient,
+	}
+
 	return newAPIConnectionParams(
 		store, controllerName, modelName,
 		accountDetails,
 		bakeryClient,
+		tokens,
 		c.apiOpen,
 		getPassword,
 	)
@@ -395,6 +406,7 @@ func newAPIConnectionParams(
 	modelName string,
 	accountDetails *jujuclient.AccountDetails,
 	bakery *httpbakery.Client,
+	tokens api.TokenSource,
 	apiOpen api.OpenFunc,
 	getPassword func(string) (string, error),
 ) (juju.NewAPIConnectionParams, error) {
@@ -411,6 +423,7 @@ func newAPIConnectionParams(
 	}
 	dialOpts := api.DefaultDialOpts()
 	dialOpts.BakeryClient = bakery
+	dialOpts.TokenSource = tokens
 
 	if accountDetails != nil {
 		bakery.WebPageVisitor = httpbakery.NewMultiVisitor(
@@ -1,8 +1,17 @@
 package modelcmd
 
-import "github.com/juju/cmd"
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/utils/clock"
 
-var NewAPIContext = newAPIContext
+	"github.com/juju/juju/api"
+	"github.com/juju/juju/jujuclient"
+)
+
+var (
+	NewAPIContext  = newAPIContext
+	WebbrowserOpen = &webbrowserOpen
+)
 
 func SetRunStarted(b interface {
 	setRunStarted()
@@ -15,3 +24,29 @@ func InitContexts(c *cmd.Context, b interface {
 }) {
 	b.initContexts(c)
 }
+
+// OIDCClient is the client used by the token
+// source returned by NewTokenSource.
+type OIDCClient interface {
+	oidcClient
+}
+
+func NewTokenSource(
+	store jujuclient.AccountStore,
+	controllerName string,
+	ctx *cmd.Context,
+	noBrowser bool,
+	clock clock.Clock,
+	client OIDCClient,
+) api.TokenSource {
+	return &tokenSource{
+		store:          store,
+		controllerName: controllerName,
+		ctx:            ctx,
+		noBrowser:      noBrowser,
+		clock:          clock,
+		newClient: func(string, string) oidcClient {
+			return client
+		},
+	}
+}
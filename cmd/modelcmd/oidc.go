@@ -0,0 +1,138 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package modelcmd
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/utils/clock"
+	"github.com/juju/webbrowser"
+
+	"github.com/juju/juju/api/authentication"
+	"github.com/juju/juju/jujuclient"
+)
+
+// webbrowserOpen is patched out in tests.
+var webbrowserOpen = webbrowser.Open
+
+// tokenSource implements api.TokenSource, obtaining ID tokens from
+// a controller's OpenID Connect provider on behalf of the user and
+// storing them with the user's account for the controller.
+type tokenSource struct {
+	store          jujuclient.AccountStore
+	controllerName string
+
+	// ctx is used to prompt the user to log in
+	// with the provider. It may be nil.
+	ctx *cmd.Context
+
+	// noBrowser specifies that the login page
+	// should not be opened in a web browser.
+	noBrowser bool
+
+	clock clock.Clock
+
+	// newClient returns the client used to obtain tokens.
+	newClient func(issuerURL, clientID string) oidcClient
+}
+
+// oidcClient is the subset of *authentication.OIDCClient
+// used by tokenSource.
+type oidcClient interface {
+	DeviceLogin(prompt func(authentication.DeviceAuthorization) error) (*authentication.OIDCTokens, error)
+	Refresh(refreshToken string) (*authentication.OIDCTokens, error)
+}
+
+func newOIDCClient(issuerURL, clientID string) oidcClient {
+	return &authentication.OIDCClient{
+		IssuerURL: issuerURL,
+		ClientID:  clientID,
+	}
+}
+
+// Token implements api.TokenSource. The stored ID token is returned
+// if it has not expired; otherwise a new one is obtained with the
+// stored refresh token or, failing that, by asking the user to log in.
+func (s *tokenSource) Token(issuerURL, clientID string) (string, error) {
+	details, err := s.store.AccountDetails(s.controllerName)
+	if errors.IsNotFound(err) {
+		details = nil
+	} else if err != nil {
+		return "", errors.Trace(err)
+	}
+	if details != nil && details.IDToken != "" {
+		idToken, err := authentication.ParseIDToken(details.IDToken)
+		if err == nil && !idToken.Claims.Expired(s.clock.Now()) {
+			return details.IDToken, nil
+		}
+	}
+
+	client := s.newClient(issuerURL, clientID)
+	var tokens *authentication.OIDCTokens
+	if details != nil && details.RefreshToken != "" {
+		tokens, err = client.Refresh(details.RefreshToken)
+		if err != nil {
+			logger.Debugf("cannot refresh ID token: %v", err)
+		}
+	}
+	if tokens == nil {
+		if s.ctx == nil {
+			return "", errors.New("no context to log in with OIDC provider")
+		}
+		tokens, err = client.DeviceLogin(s.prompt)
+		if err != nil {
+			return "", errors.Trace(err)
+		}
+	}
+	if err := s.saveTokens(details, tokens); err != nil {
+		return "", errors.Trace(err)
+	}
+	return tokens.IDToken, nil
+}
+
+// prompt asks the user to complete a device login,
+// opening the login page in a web browser if allowed.
+func (s *tokenSource) prompt(auth authentication.DeviceAuthorization) error {
+	fmt.Fprintf(s.ctx.Stderr, "To log in, visit %s and enter the code %s\n", auth.VerificationURI, auth.UserCode)
+	if s.noBrowser {
+		return nil
+	}
+	loginURL := auth.VerificationURIComplete
+	if loginURL == "" {
+		loginURL = auth.VerificationURI
+	}
+	u, err := url.Parse(loginURL)
+	if err != nil {
+		return errors.Annotate(err, "cannot parse login URL")
+	}
+	if err := webbrowserOpen(u); err != nil && err != webbrowser.ErrNoBrowser {
+		logger.Debugf("cannot open web browser: %v", err)
+	}
+	return nil
+}
+
+// saveTokens stores the tokens with the user's account. If there is
+// no account yet, one is created for the user identified by the ID
+// token; the controller will confirm the user when we log in.
+func (s *tokenSource) saveTokens(details *jujuclient.AccountDetails, tokens *authentication.OIDCTokens) error {
+	idToken, err := authentication.ParseIDToken(tokens.IDToken)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	userTag, err := idToken.Claims.UserTag()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	var account jujuclient.AccountDetails
+	if details != nil && details.User == userTag.Id() {
+		account = *details
+	}
+	account.User = userTag.Id()
+	account.IDToken = tokens.IDToken
+	account.RefreshToken = tokens.RefreshToken
+	return errors.Trace(s.store.UpdateAccount(s.controllerName, account))
+}
@@ -0,0 +1,166 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package modelcmd_test
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/juju/cmd/cmdtesting"
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/api/authentication"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/jujuclient"
+)
+
+type TokenSourceSuite struct {
+	testing.IsolationSuite
+	store  *jujuclient.MemStore
+	clock  *testing.Clock
+	client *fakeOIDCClient
+	opened []string
+}
+
+var _ = gc.Suite(&TokenSourceSuite{})
+
+func (s *TokenSourceSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.store = jujuclient.NewMemStore()
+	s.clock = testing.NewClock(time.Unix(1500000000, 0))
+	s.client = &fakeOIDCClient{}
+	s.opened = nil
+	s.PatchValue(modelcmd.WebbrowserOpen, func(u *url.URL) error {
+		s.opened = append(s.opened, u.String())
+		return nil
+	})
+}
+
+func (s *TokenSourceSuite) token(username string, expiry time.Time) string {
+	enc := base64.RawURLEncoding
+	return fmt.Sprintf("%s.%s.%s",
+		enc.EncodeToString([]byte(`{"alg":"RS256"}`)),
+		enc.EncodeToString([]byte(fmt.Sprintf(`{"sub":"1234","exp":%d,"preferred_username":%q}`, expiry.Unix(), username))),
+		enc.EncodeToString([]byte("signature")),
+	)
+}
+
+func (s *TokenSourceSuite) TestStoredToken(c *gc.C) {
+	idToken := s.token("bob", s.clock.Now().Add(time.Hour))
+	s.store.Accounts["ctrl"] = jujuclient.AccountDetails{
+		User:         "bob@external",
+		IDToken:      idToken,
+		RefreshToken: "refresh-token",
+	}
+	source := modelcmd.NewTokenSource(s.store, "ctrl", nil, false, s.clock, s.client)
+	token, err := source.Token("https://issuer.example.com", "juju")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(token, gc.Equals, idToken)
+	s.client.CheckNoCalls(c)
+}
+
+func (s *TokenSourceSuite) TestRefreshExpiredToken(c *gc.C) {
+	s.store.Accounts["ctrl"] = jujuclient.AccountDetails{
+		User:            "bob@external",
+		LastKnownAccess: "login",
+		IDToken:         s.token("bob", s.clock.Now()),
+		RefreshToken:    "refresh-token",
+	}
+	newToken := s.token("bob", s.clock.Now().Add(time.Hour))
+	s.client.tokens = &authentication.OIDCTokens{
+		IDToken:      newToken,
+		RefreshToken: "new-refresh-token",
+	}
+	source := modelcmd.NewTokenSource(s.store, "ctrl", nil, false, s.clock, s.client)
+	token, err := source.Token("https://issuer.example.com", "juju")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(token, gc.Equals, newToken)
+	s.client.CheckCall(c, 0, "Refresh", "refresh-token")
+	c.Assert(s.store.Accounts["ctrl"], jc.DeepEquals, jujuclient.AccountDetails{
+		User:            "bob@external",
+		LastKnownAccess: "login",
+		IDToken:         newToken,
+		RefreshToken:    "new-refresh-token",
+	})
+}
+
+func (s *TokenSourceSuite) TestDeviceLoginWhenRefreshFails(c *gc.C) {
+	s.store.Accounts["ctrl"] = jujuclient.AccountDetails{
+		User:         "bob@external",
+		IDToken:      s.token("bob", s.clock.Now()),
+		RefreshToken: "refresh-token",
+	}
+	newToken := s.token("bob", s.clock.Now().Add(time.Hour))
+	s.client.SetErrors(errors.New("invalid_grant"))
+	s.client.tokens = &authentication.OIDCTokens{IDToken: newToken}
+	ctx := cmdtesting.Context(c)
+	source := modelcmd.NewTokenSource(s.store, "ctrl", ctx, false, s.clock, s.client)
+	token, err := source.Token("https://issuer.example.com", "juju")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(token, gc.Equals, newToken)
+	s.client.CheckCallNames(c, "Refresh", "DeviceLogin")
+	c.Assert(cmdtesting.Stderr(ctx), gc.Equals,
+		"To log in, visit https://issuer.example.com/activate and enter the code ABCD-EFGH\n")
+	c.Assert(s.opened, jc.DeepEquals, []string{"https://issuer.example.com/activate?code=ABCD-EFGH"})
+}
+
+func (s *TokenSourceSuite) TestDeviceLoginNewAccount(c *gc.C) {
+	newToken := s.token("bob", s.clock.Now().Add(time.Hour))
+	s.client.tokens = &authentication.OIDCTokens{
+		IDToken:      newToken,
+		RefreshToken: "refresh-token",
+	}
+	ctx := cmdtesting.Context(c)
+	source := modelcmd.NewTokenSource(s.store, "ctrl", ctx, true, s.clock, s.client)
+	token, err := source.Token("https://issuer.example.com", "juju")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(token, gc.Equals, newToken)
+	s.client.CheckCallNames(c, "DeviceLogin")
+	c.Assert(s.opened, gc.HasLen, 0)
+	c.Assert(s.store.Accounts["ctrl"], jc.DeepEquals, jujuclient.AccountDetails{
+		User:         "bob@external",
+		IDToken:      newToken,
+		RefreshToken: "refresh-token",
+	})
+}
+
+func (s *TokenSourceSuite) TestDeviceLoginNoContext(c *gc.C) {
+	source := modelcmd.NewTokenSource(s.store, "ctrl", nil, false, s.clock, s.client)
+	_, err := source.Token("https://issuer.example.com", "juju")
+	c.Assert(err, gc.ErrorMatches, "no context to log in with OIDC provider")
+	s.client.CheckNoCalls(c)
+}
+
+type fakeOIDCClient struct {
+	testing.Stub
+	tokens *authentication.OIDCTokens
+}
+
+func (f *fakeOIDCClient) DeviceLogin(prompt func(authentication.DeviceAuthorization) error) (*authentication.OIDCTokens, error) {
+	f.MethodCall(f, "DeviceLogin")
+	if err := f.NextErr(); err != nil {
+		return nil, err
+	}
+	if err := prompt(authentication.DeviceAuthorization{
+		UserCode:                "ABCD-EFGH",
+		VerificationURI:         "https://issuer.example.com/activate",
+		VerificationURIComplete: "https://issuer.example.com/activate?code=ABCD-EFGH",
+	}); err != nil {
+		return nil, err
+	}
+	return f.tokens, nil
+}
+
+func (f *fakeOIDCClient) Refresh(refreshToken string) (*authentication.OIDCTokens, error) {
+	f.MethodCall(f, "Refresh", refreshToken)
+	if err := f.NextErr(); err != nil {
+		return nil, err
+	}
+	return f.tokens, nil
+}
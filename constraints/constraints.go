@@ -32,6 +32,7 @@ const (
 	InstanceType = "instance-type"
 	Spaces       = "spaces"
 	VirtType     = "virt-type"
+	Gpus         = "gpus"
 )
 
 // Value describes a user's requirements of the hardware on which units
@@ -85,6 +86,11 @@ type Value struct {
 	// VirtType, if not nil or empty, indicates that a machine must run the named
 	// virtual type. Only valid for clouds with multi-hypervisor support.
 	VirtType *string `json:"virt-type,omitempty" yaml:"virt-type,omitempty"`
+
+	// Gpus, if not nil, indicates that a machine must have at least that
+	// number of GPUs available. Only valid for substrates which schedule
+	// workloads onto nodes with GPUs.
+	Gpus *uint64 `json:"gpus,omitempty" yaml:"gpus,omitempty"`
 }
 
 var rawAliases = map[string]string{
@@ -180,6 +186,17 @@ func (v *Value) HasVirtType() bool {
 	return v.VirtType != nil && *v.VirtType != ""
 }
 
+// HasGpus returns true if the constraints.Value specifies a minimum number
+// of GPUs.
+func (v *Value) HasGpus() bool {
+	return v.Gpus != nil && *v.Gpus > 0
+}
+
+// HasTags returns true if the constraints.Value specifies any tags.
+func (v *Value) HasTags() bool {
+	return v.Tags != nil && len(*v.Tags) > 0
+}
+
 // String expresses a constraints.Value in the language in which it was specified.
 func (v Value) String() string {
 	var strs []string
@@ -195,6 +212,9 @@ func (v Value) String() string {
 	if v.CpuPower != nil {
 		strs = append(strs, "cpu-power="+uintStr(*v.CpuPower))
 	}
+	if v.Gpus != nil {
+		strs = append(strs, "gpus="+uintStr(*v.Gpus))
+	}
 	if v.InstanceType != nil {
 		strs = append(strs, "instance-type="+string(*v.InstanceType))
 	}
@@ -239,6 +259,9 @@ func (v Value) GoString() string {
 	if v.CpuPower != nil {
 		values = append(values, fmt.Sprintf("CpuPower: %v", *v.CpuPower))
 	}
+	if v.Gpus != nil {
+		values = append(values, fmt.Sprintf("Gpus: %v", *v.Gpus))
+	}
 	if v.Mem != nil {
 		values = append(values, fmt.Sprintf("Mem: %v", *v.Mem))
 	}
@@ -408,6 +431,8 @@ func (v *Value) setRaw(name, str string) error {
 		err = v.setCpuCores(str)
 	case CpuPower:
 		err = v.setCpuPower(str)
+	case Gpus:
+		err = v.setGpus(str)
 	case Mem:
 		err = v.setMem(str)
 	case RootDisk:
@@ -465,6 +490,8 @@ func (v *Value) UnmarshalYAML(unmarshal func(interface{}) error) error {
 			v.CpuCores, err = parseUint64(vstr)
 		case CpuPower:
 			v.CpuPower, err = parseUint64(vstr)
+		case Gpus:
+			v.Gpus, err = parseUint64(vstr)
 		case Mem:
 			v.Mem, err = parseUint64(vstr)
 		case RootDisk:
@@ -542,6 +569,14 @@ func (v *Value) setCpuPower(str string) (err error) {
 	return
 }
 
+func (v *Value) setGpus(str string) (err error) {
+	if v.Gpus != nil {
+		return errors.Errorf("already set")
+	}
+	v.Gpus, err = parseUint64(str)
+	return
+}
+
 func (v *Value) setInstanceType(str string) error {
 	if v.InstanceType != nil {
 		return errors.Errorf("already set")
@@ -166,6 +166,26 @@ var parseConstraintsTests = []struct {
 		err:     `bad "cpu-power" constraint: already set`,
 	},
 
+	// "gpus" in detail.
+	{
+		summary: "set gpus empty",
+		args:    []string{"gpus="},
+	}, {
+		summary: "set gpus zero",
+		args:    []string{"gpus=0"},
+	}, {
+		summary: "set gpus",
+		args:    []string{"gpus=2"},
+	}, {
+		summary: "set nonsense gpus",
+		args:    []string{"gpus=lots"},
+		err:     `bad "gpus" constraint: must be a non-negative integer`,
+	}, {
+		summary: "double set gpus together",
+		args:    []string{"gpus=1 gpus=2"},
+		err:     `bad "gpus" constraint: already set`,
+	},
+
 	// "mem" in detail.
 	{
 		summary: "set mem empty",
@@ -496,6 +516,9 @@ var constraintsRoundtripTests = []roundTrip{
 	{"CpuPower1", constraints.Value{CpuPower: nil}},
 	{"CpuPower2", constraints.Value{CpuPower: uint64p(0)}},
 	{"CpuPower3", constraints.Value{CpuPower: uint64p(250)}},
+	{"Gpus1", constraints.Value{Gpus: nil}},
+	{"Gpus2", constraints.Value{Gpus: uint64p(0)}},
+	{"Gpus3", constraints.Value{Gpus: uint64p(4)}},
 	{"Mem1", constraints.Value{Mem: nil}},
 	{"Mem2", constraints.Value{Mem: uint64p(0)}},
 	{"Mem3", constraints.Value{Mem: uint64p(98765)}},
@@ -597,6 +620,15 @@ func (s *ConstraintsSuite) TestHasInstanceType(c *gc.C) {
 	c.Check(cons.HasInstanceType(), jc.IsTrue)
 }
 
+func (s *ConstraintsSuite) TestHasTags(c *gc.C) {
+	cons := constraints.MustParse("arch=amd64")
+	c.Check(cons.HasTags(), jc.IsFalse)
+	cons = constraints.MustParse("arch=amd64 tags=")
+	c.Check(cons.HasTags(), jc.IsFalse)
+	cons = constraints.MustParse("arch=amd64 tags=foo,bar")
+	c.Check(cons.HasTags(), jc.IsTrue)
+}
+
 const initialWithoutCons = "root-disk=8G mem=4G arch=amd64 cpu-power=1000 cores=4 spaces=space1,^space2 tags=foo container=lxd instance-type=bar"
 
 var withoutTests = []struct {
@@ -69,6 +69,15 @@ const (
 	// IdentityPublicKey sets the public key of the identity manager.
 	IdentityPublicKey = "identity-public-key"
 
+	// OIDCIssuerURL sets the issuer URL of an OpenID Connect provider
+	// used to authenticate external users.
+	OIDCIssuerURL = "oidc-issuer-url"
+
+	// OIDCClientID sets the client ID registered with the OpenID
+	// Connect provider, which the ID tokens presented by users must
+	// be issued to.
+	OIDCClientID = "oidc-client-id"
+
 	// SetNUMAControlPolicyKey stores the value for this setting
 	SetNUMAControlPolicyKey = "set-numa-control-policy"
 
@@ -152,6 +161,12 @@ const (
 	// JujuManagementSpace is the network space that agents should use to
 	// communicate with controllers.
 	JujuManagementSpace = "juju-mgmt-space"
+
+	// Features is a list of the names of the feature flags enabled on
+	// the controller. Unlike developer feature flags, which are read
+	// from the environment when an agent starts, changes to these
+	// are observed by the controller agents as they run.
+	Features = "features"
 )
 
 var (
@@ -166,6 +181,8 @@ var (
 		ControllerUUIDKey,
 		IdentityPublicKey,
 		IdentityURL,
+		OIDCIssuerURL,
+		OIDCClientID,
 		SetNUMAControlPolicyKey,
 		StatePort,
 		MongoMemoryProfile,
@@ -179,6 +196,7 @@ var (
 		AuditLogMaxSize,
 		AuditLogMaxBackups,
 		AuditLogExcludeMethods,
+		Features,
 	}
 
 	// DefaultAuditLogExcludeMethods is the default list of methods to
@@ -372,6 +390,18 @@ func (c Config) AuditLogExcludeMethods() set.Strings {
 	return set.NewStrings(DefaultAuditLogExcludeMethods...)
 }
 
+// Features returns the names of the feature flags enabled on the
+// controller.
+func (c Config) Features() set.Strings {
+	features := set.NewStrings()
+	if value, ok := c[Features]; ok {
+		for _, item := range value.([]interface{}) {
+			features.Add(item.(string))
+		}
+	}
+	return features
+}
+
 // ControllerUUID returns the uuid for the model's controller.
 func (c Config) ControllerUUID() string {
 	return c.mustString(ControllerUUIDKey)
@@ -394,6 +424,18 @@ func (c Config) IdentityURL() string {
 	return c.asString(IdentityURL)
 }
 
+// OIDCIssuerURL returns the issuer URL of the OpenID Connect provider
+// used to authenticate external users, or "" if there is none.
+func (c Config) OIDCIssuerURL() string {
+	return c.asString(OIDCIssuerURL)
+}
+
+// OIDCClientID returns the client ID registered with the
+// OpenID Connect provider.
+func (c Config) OIDCClientID() string {
+	return c.asString(OIDCClientID)
+}
+
 // AutocertURL returns the URL used to obtain official TLS certificates
 // when a client connects to the API. See AutocertURLKey
 // for more details.
@@ -504,6 +546,24 @@ func Validate(c Config) error {
 		}
 	}
 
+	if v, ok := c[OIDCIssuerURL].(string); ok {
+		u, err := url.Parse(v)
+		if err != nil {
+			return errors.Annotate(err, "invalid OIDC issuer URL")
+		}
+		// The provider's signing keys are fetched from the
+		// issuer, so they must not be obtained insecurely.
+		if u.Scheme != "https" {
+			return errors.Errorf("%s needs to be https", OIDCIssuerURL)
+		}
+		if _, ok := c[IdentityURL]; ok {
+			return errors.Errorf("cannot specify both %s and %s", IdentityURL, OIDCIssuerURL)
+		}
+		if c.OIDCClientID() == "" {
+			return errors.Errorf("%s requires %s", OIDCIssuerURL, OIDCClientID)
+		}
+	}
+
 	caCert, caCertOK := c.CACert()
 	if !caCertOK {
 		return errors.Errorf("missing CA certificate")
@@ -616,6 +676,8 @@ var configChecker = schema.FieldMap(schema.Fields{
 	StatePort:               schema.ForceInt(),
 	IdentityURL:             schema.String(),
 	IdentityPublicKey:       schema.String(),
+	OIDCIssuerURL:           schema.String(),
+	OIDCClientID:            schema.String(),
 	SetNUMAControlPolicyKey: schema.Bool(),
 	AutocertURLKey:          schema.String(),
 	AutocertDNSNameKey:      schema.String(),
@@ -626,6 +688,7 @@ var configChecker = schema.FieldMap(schema.Fields{
 	MaxTxnLogSize:           schema.String(),
 	JujuHASpace:             schema.String(),
 	JujuManagementSpace:     schema.String(),
+	Features:                schema.List(schema.String()),
 }, schema.Defaults{
 	APIPort:                 DefaultAPIPort,
 	AuditingEnabled:         DefaultAuditingEnabled,
@@ -636,6 +699,8 @@ var configChecker = schema.FieldMap(schema.Fields{
 	StatePort:               DefaultStatePort,
 	IdentityURL:             schema.Omit,
 	IdentityPublicKey:       schema.Omit,
+	OIDCIssuerURL:           schema.Omit,
+	OIDCClientID:            schema.Omit,
 	SetNUMAControlPolicyKey: DefaultNUMAControlPolicy,
 	AutocertURLKey:          schema.Omit,
 	AutocertDNSNameKey:      schema.Omit,
@@ -646,4 +711,5 @@ var configChecker = schema.FieldMap(schema.Fields{
 	MaxTxnLogSize:           fmt.Sprintf("%vM", DefaultMaxTxnLogCollectionMB),
 	JujuHASpace:             schema.Omit,
 	JujuManagementSpace:     schema.Omit,
+	Features:                schema.Omit,
 })
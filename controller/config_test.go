@@ -115,6 +115,37 @@ var validateTests = []struct {
 		controller.IdentityURL:       "http://0.1.2.3/foo",
 		controller.CACertKey:         testing.CACert,
 	},
+}, {
+	about: "HTTPS OIDC issuer URL OK",
+	config: controller.Config{
+		controller.OIDCIssuerURL: "https://accounts.example.com",
+		controller.OIDCClientID:  "juju",
+		controller.CACertKey:     testing.CACert,
+	},
+}, {
+	about: "HTTP OIDC issuer URL not OK",
+	config: controller.Config{
+		controller.OIDCIssuerURL: "http://accounts.example.com",
+		controller.OIDCClientID:  "juju",
+		controller.CACertKey:     testing.CACert,
+	},
+	expectError: `oidc-issuer-url needs to be https`,
+}, {
+	about: "OIDC issuer URL requires client ID",
+	config: controller.Config{
+		controller.OIDCIssuerURL: "https://accounts.example.com",
+		controller.CACertKey:     testing.CACert,
+	},
+	expectError: `oidc-issuer-url requires oidc-client-id`,
+}, {
+	about: "OIDC issuer URL with identity URL",
+	config: controller.Config{
+		controller.OIDCIssuerURL: "https://accounts.example.com",
+		controller.OIDCClientID:  "juju",
+		controller.IdentityURL:   "https://0.1.2.3/foo",
+		controller.CACertKey:     testing.CACert,
+	},
+	expectError: `cannot specify both identity-url and oidc-issuer-url`,
 }, {
 	about: "invalid identity public key",
 	config: controller.Config{
@@ -301,6 +332,22 @@ func (s *ConfigSuite) TestAuditLogValues(c *gc.C) {
 	))
 }
 
+func (s *ConfigSuite) TestFeatures(c *gc.C) {
+	cfg, err := controller.NewConfig(testing.ControllerTag.Id(), testing.CACert, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cfg.Features(), gc.DeepEquals, set.NewStrings())
+
+	cfg, err = controller.NewConfig(
+		testing.ControllerTag.Id(),
+		testing.CACert,
+		map[string]interface{}{
+			"features": []string{"foo", "bar"},
+		},
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cfg.Features(), gc.DeepEquals, set.NewStrings("foo", "bar"))
+}
+
 func (s *ConfigSuite) TestAuditLogExcludeMethodsType(c *gc.C) {
 	_, err := controller.NewConfig(
 		testing.ControllerTag.Id(),
@@ -10,6 +10,47 @@ import (
 	"gopkg.in/juju/environschema.v1"
 )
 
+const (
+	// TrustConfigOptionName is the name of the application config
+	// option which grants an application access to the cloud
+	// credentials and cluster-scoped permissions of its model.
+	TrustConfigOptionName = "trust"
+)
+
+var trustFields = environschema.Fields{
+	TrustConfigOptionName: {
+		Description: "Does this application have access to trusted credentials",
+		Type:        environschema.Tbool,
+		Group:       environschema.JujuGroup,
+	},
+}
+
+var trustDefaults = schema.Defaults{
+	TrustConfigOptionName: false,
+}
+
+// AddTrustSchemaAndDefaults adds the trust config option to the
+// specified application config schema and defaults. Applications
+// are not trusted unless the option is explicitly set.
+func AddTrustSchemaAndDefaults(fields environschema.Fields, defaults schema.Defaults) (environschema.Fields, schema.Defaults, error) {
+	newFields := make(environschema.Fields)
+	for name, field := range fields {
+		newFields[name] = field
+	}
+	newDefaults := make(schema.Defaults)
+	for key, value := range defaults {
+		newDefaults[key] = value
+	}
+	for name, field := range trustFields {
+		if _, ok := newFields[name]; ok {
+			return nil, nil, errors.Errorf("config field %q clashes with trust config", name)
+		}
+		newFields[name] = field
+		newDefaults[name] = trustDefaults[name]
+	}
+	return newFields, newDefaults, nil
+}
+
 // ConfigAttributes is the config for an application.
 type ConfigAttributes map[string]interface{}
 
@@ -104,3 +104,28 @@ func (s *ApplicationSuite) TestGetBool(c *gc.C) {
 	c.Assert(cfg.Attributes().GetBool("field4", false), gc.Equals, true)
 	c.Assert(cfg.Attributes().GetBool("missing", true), gc.Equals, true)
 }
+
+func (s *ApplicationSuite) TestAddTrustSchemaAndDefaults(c *gc.C) {
+	fields, defaults, err := application.AddTrustSchemaAndDefaults(testFields, testDefaults)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(application.KnownConfigKeys(fields), gc.DeepEquals,
+		set.NewStrings("field1", "field2", "field3", "field4", "trust"))
+	c.Assert(defaults, jc.DeepEquals, schema.Defaults{
+		"field1": "field 1 default",
+		"field3": 42,
+		"trust":  false,
+	})
+	// The inputs are not modified.
+	c.Assert(testFields, gc.HasLen, 4)
+	c.Assert(testDefaults, gc.HasLen, 2)
+
+	cfg, err := application.NewConfig(map[string]interface{}{"trust": "true"}, fields, defaults)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cfg.Attributes().GetBool("trust", false), jc.IsTrue)
+}
+
+func (s *ApplicationSuite) TestAddTrustSchemaAndDefaultsClash(c *gc.C) {
+	fields := environschema.Fields{"trust": {Type: environschema.Tstring}}
+	_, _, err := application.AddTrustSchemaAndDefaults(fields, nil)
+	c.Assert(err, gc.ErrorMatches, `config field "trust" clashes with trust config`)
+}
@@ -21,6 +21,10 @@ import (
 
 var logger = loggo.GetLogger("core.auditlog")
 
+// LogFileName is the name of the audit log file written
+// to the log directory of each controller.
+const LogFileName = "audit.log"
+
 // Conversation represents a high-level juju command from the juju
 // client (or other client). There'll be one Conversation per API
 // connection from the client, with zero or more associated
@@ -188,7 +192,7 @@ type auditLogFile struct {
 // the maximum number of old compressed log files to keep (or 0 to
 // keep all of them).
 func NewLogFile(logDir string, maxSize, maxBackups int) AuditLog {
-	logPath := filepath.Join(logDir, "audit.log")
+	logPath := filepath.Join(logDir, LogFileName)
 	if err := primeLogFile(logPath); err != nil {
 		// This isn't a fatal error so log and continue if priming
 		// fails.
@@ -0,0 +1,65 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package auditlog
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+
+	"github.com/juju/errors"
+)
+
+// Activity holds a conversation read from an audit log, along with
+// the requests made and the errors returned as part of it.
+type Activity struct {
+	Conversation Conversation
+	Requests     []Request
+	Errors       []ResponseErrors
+}
+
+// ReadActivity reads the records of an audit log from r, returning
+// the conversations accepted by the filter function in the order
+// they were recorded. Lines that can't be parsed, such as one left
+// partially written, are skipped.
+func ReadActivity(r io.Reader, filter func(Conversation) bool) ([]*Activity, error) {
+	var (
+		all      []*Activity
+		accepted = make(map[string]*Activity)
+	)
+	reader := bufio.NewReader(r)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err != nil && err != io.EOF {
+			return nil, errors.Trace(err)
+		}
+		if line = bytes.TrimSpace(line); len(line) > 0 {
+			var record Record
+			if jsonErr := json.Unmarshal(line, &record); jsonErr != nil {
+				logger.Debugf("skipping audit log line: %v", jsonErr)
+			} else {
+				switch {
+				case record.Conversation != nil:
+					if filter(*record.Conversation) {
+						activity := &Activity{Conversation: *record.Conversation}
+						accepted[activity.Conversation.ConversationID] = activity
+						all = append(all, activity)
+					}
+				case record.Request != nil:
+					if activity, ok := accepted[record.Request.ConversationID]; ok {
+						activity.Requests = append(activity.Requests, *record.Request)
+					}
+				case record.Errors != nil:
+					if activity, ok := accepted[record.Errors.ConversationID]; ok {
+						activity.Errors = append(activity.Errors, *record.Errors)
+					}
+				}
+			}
+		}
+		if err == io.EOF {
+			return all, nil
+		}
+	}
+}
@@ -0,0 +1,90 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package auditlog_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/core/auditlog"
+)
+
+type ReaderSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&ReaderSuite{})
+
+const auditLogContents = `
+{"conversation":{"who":"user-fred","what":"juju deploy mysql","when":"2018-06-01T10:00:00Z","model-name":"admin/default","model-uuid":"deadbeef","conversation-id":"0001","connection-id":"A1"}}
+{"conversation":{"who":"user-mary","what":"juju add-unit mysql","when":"2018-06-01T10:00:01Z","model-name":"admin/other","model-uuid":"cafef00d","conversation-id":"0002","connection-id":"A2"}}
+{"request":{"conversation-id":"0002","connection-id":"A2","request-id":1,"when":"2018-06-01T10:00:02Z","facade":"Application","method":"AddUnits","version":6}}
+{"request":{"conversation-id":"0001","connection-id":"A1","request-id":1,"when":"2018-06-01T10:00:03Z","facade":"Application","method":"Deploy","version":6}}
+{"errors":{"conversation-id":"0001","connection-id":"A1","request-id":1,"when":"2018-06-01T10:00:04Z","errors":[{"message":"oops","code":""}]}}
+{"request":{"conversation-id":"00
+{"request":{"conversation-id":"0003","connection-id":"A3","request-id":1,"when":"2018-06-01T10:00:05Z","facade":"Application","method":"Deploy","version":6}}
+`
+
+func (s *ReaderSuite) TestReadActivity(c *gc.C) {
+	activity, err := auditlog.ReadActivity(strings.NewReader(auditLogContents), func(conv auditlog.Conversation) bool {
+		return conv.ModelUUID == "deadbeef"
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(activity, jc.DeepEquals, []*auditlog.Activity{{
+		Conversation: auditlog.Conversation{
+			Who:            "user-fred",
+			What:           "juju deploy mysql",
+			When:           "2018-06-01T10:00:00Z",
+			ModelName:      "admin/default",
+			ModelUUID:      "deadbeef",
+			ConversationID: "0001",
+			ConnectionID:   "A1",
+		},
+		Requests: []auditlog.Request{{
+			ConversationID: "0001",
+			ConnectionID:   "A1",
+			RequestID:      1,
+			When:           "2018-06-01T10:00:03Z",
+			Facade:         "Application",
+			Method:         "Deploy",
+			Version:        6,
+		}},
+		Errors: []auditlog.ResponseErrors{{
+			ConversationID: "0001",
+			ConnectionID:   "A1",
+			RequestID:      1,
+			When:           "2018-06-01T10:00:04Z",
+			Errors:         []*auditlog.Error{{Message: "oops"}},
+		}},
+	}})
+}
+
+func (s *ReaderSuite) TestReadActivityWrittenLog(c *gc.C) {
+	dir := c.MkDir()
+	logFile := auditlog.NewLogFile(dir, 300, 10)
+	err := logFile.AddConversation(auditlog.Conversation{
+		Who:            "deerhoof",
+		What:           "gojira",
+		When:           "2017-11-27T13:21:24Z",
+		ModelName:      "admin/default",
+		ConversationID: "0123456789abcdef",
+		ConnectionID:   "AC1",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	err = logFile.Close()
+	c.Assert(err, jc.ErrorIsNil)
+
+	f, err := os.Open(filepath.Join(dir, auditlog.LogFileName))
+	c.Assert(err, jc.ErrorIsNil)
+	defer f.Close()
+	activity, err := auditlog.ReadActivity(f, func(auditlog.Conversation) bool { return true })
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(activity, gc.HasLen, 1)
+	c.Assert(activity[0].Conversation.Who, gc.Equals, "deerhoof")
+}
@@ -29,6 +29,11 @@ type MigrationStatus struct {
 	// its current value.
 	PhaseChangedTime time.Time
 
+	// Paused indicates that the migration has been paused by the
+	// user, and should not move on from its current phase until it
+	// is resumed.
+	Paused bool
+
 	// TargetInfo contains the details of how to connect to the target
 	// controller.
 	TargetInfo TargetInfo
@@ -0,0 +1,53 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package model
+
+import (
+	"github.com/juju/errors"
+)
+
+// UpgradeSeriesStatus is the current state of a series upgrade, as
+// recorded for a machine and for each of the units deployed to it.
+type UpgradeSeriesStatus string
+
+const (
+	// UpgradeSeriesNotStarted indicates that no work has yet been done
+	// for the series upgrade.
+	UpgradeSeriesNotStarted UpgradeSeriesStatus = "not started"
+
+	// UpgradeSeriesPrepareStarted indicates that the units are running
+	// their pre-series-upgrade work, after which the unit agents are
+	// stopped.
+	UpgradeSeriesPrepareStarted UpgradeSeriesStatus = "prepare started"
+
+	// UpgradeSeriesPrepareCompleted indicates that the pre-series-upgrade
+	// work is done; the operating system can now be upgraded.
+	UpgradeSeriesPrepareCompleted UpgradeSeriesStatus = "prepare completed"
+
+	// UpgradeSeriesCompleteStarted indicates that the operating system
+	// has been upgraded, and that the unit agents are being restarted
+	// to run their post-series-upgrade work.
+	UpgradeSeriesCompleteStarted UpgradeSeriesStatus = "complete started"
+
+	// UpgradeSeriesCompleted indicates that the series upgrade is done.
+	UpgradeSeriesCompleted UpgradeSeriesStatus = "completed"
+
+	// UpgradeSeriesError indicates that the series upgrade failed, and
+	// needs manual intervention.
+	UpgradeSeriesError UpgradeSeriesStatus = "error"
+)
+
+// Validate returns an error if the status is not known.
+func (s UpgradeSeriesStatus) Validate() error {
+	switch s {
+	case UpgradeSeriesNotStarted,
+		UpgradeSeriesPrepareStarted,
+		UpgradeSeriesPrepareCompleted,
+		UpgradeSeriesCompleteStarted,
+		UpgradeSeriesCompleted,
+		UpgradeSeriesError:
+		return nil
+	}
+	return errors.NotValidf("upgrade series status %q", s)
+}
@@ -170,6 +170,15 @@ const (
 	// UpdateStatusHookInterval is how often to run the update-status hook.
 	UpdateStatusHookInterval = "update-status-hook-interval"
 
+	// UnitAgentUsersKey is the key for whether each unit agent deployed
+	// to a machine runs as its own system user.
+	UnitAgentUsersKey = "unit-agent-users"
+
+	// MaxUnitArchiveAge is how long to keep archives of the agent
+	// files of units recalled from their machines, eg "72h"; the
+	// files are not archived if it is zero.
+	MaxUnitArchiveAge = "max-unit-archive-age"
+
 	// EgressSubnets are the source addresses from which traffic from this model
 	// originates if the model is deployed such that NAT or similar is in use.
 	EgressSubnets = "egress-subnets"
@@ -344,6 +353,10 @@ const (
 	DefaultActionResultsAge = "336h" // 2 weeks
 
 	DefaultActionResultsSize = "5G"
+
+	// DefaultUnitArchiveAge is the default value for MaxUnitArchiveAge,
+	// which disables the archiving of agent files.
+	DefaultUnitArchiveAge = "0"
 )
 
 var defaultConfigValues = map[string]interface{}{
@@ -384,6 +397,7 @@ var defaultConfigValues = map[string]interface{}{
 	"development":              false,
 	"test-mode":                false,
 	TransmitVendorMetricsKey:   true,
+	UnitAgentUsersKey:          false,
 	UpdateStatusHookInterval:   DefaultUpdateStatusHookInterval,
 	EgressSubnets:              "",
 	FanConfig:                  "",
@@ -414,6 +428,7 @@ var defaultConfigValues = map[string]interface{}{
 	MaxStatusHistorySize: DefaultStatusHistorySize,
 	MaxActionResultsAge:  DefaultActionResultsAge,
 	MaxActionResultsSize: DefaultActionResultsSize,
+	MaxUnitArchiveAge:    DefaultUnitArchiveAge,
 }
 
 // ConfigDefaults returns the config default values
@@ -556,6 +571,14 @@ func Validate(cfg, old *Config) error {
 		}
 	}
 
+	if v, ok := cfg.defined[MaxUnitArchiveAge].(string); ok {
+		if age, err := time.ParseDuration(v); err != nil {
+			return errors.Annotate(err, "invalid max unit archive age in model configuration")
+		} else if age < 0 {
+			return errors.Errorf("max unit archive age %v cannot be negative", age)
+		}
+	}
+
 	if v, ok := cfg.defined[UpdateStatusHookInterval].(string); ok {
 		if f, err := time.ParseDuration(v); err != nil {
 			return errors.Annotate(err, "invalid update status hook interval in model configuration")
@@ -969,6 +992,14 @@ func (c *Config) TransmitVendorMetrics() bool {
 	}
 }
 
+// UnitAgentUsers returns whether each unit agent deployed to a machine
+// runs as its own system user, isolating the units on the machine from
+// each other. By default this is false.
+func (c *Config) UnitAgentUsers() bool {
+	val, _ := c.defined[UnitAgentUsersKey].(bool)
+	return val
+}
+
 // ProvisionerHarvestMode reports the harvesting methodology the
 // provisioner should take.
 func (c *Config) ProvisionerHarvestMode() HarvestMode {
@@ -1096,6 +1127,21 @@ func (c *Config) MaxActionResultsSizeMB() uint {
 	return uint(val)
 }
 
+// MaxUnitArchiveAge is how long archives of the agent files of
+// recalled units are kept before being pruned. The files are not
+// archived if it is zero.
+func (c *Config) MaxUnitArchiveAge() time.Duration {
+	// Models created before the key was added don't have it,
+	// and don't archive agent files.
+	raw := c.asString(MaxUnitArchiveAge)
+	if raw == "" {
+		raw = DefaultUnitArchiveAge
+	}
+	// Value has already been validated.
+	val, _ := time.ParseDuration(raw)
+	return val
+}
+
 // UpdateStatusHookInterval is how often to run the charm
 // update-status hook.
 func (c *Config) UpdateStatusHookInterval() time.Duration {
@@ -1251,6 +1297,7 @@ var alwaysOptional = schema.Defaults{
 	AutomaticallyRetryHooks:      schema.Omit,
 	"test-mode":                  schema.Omit,
 	TransmitVendorMetricsKey:     schema.Omit,
+	UnitAgentUsersKey:            schema.Omit,
 	NetBondReconfigureDelayKey:   schema.Omit,
 	ContainerNetworkingMethod:    schema.Omit,
 	MaxStatusHistoryAge:          schema.Omit,
@@ -1258,6 +1305,7 @@ var alwaysOptional = schema.Defaults{
 	MaxActionResultsAge:          schema.Omit,
 	MaxActionResultsSize:         schema.Omit,
 	UpdateStatusHookInterval:     schema.Omit,
+	MaxUnitArchiveAge:            schema.Omit,
 	EgressSubnets:                schema.Omit,
 	FanConfig:                    schema.Omit,
 	CloudInitUserDataKey:         schema.Omit,
@@ -1656,6 +1704,11 @@ data of the store. (default false)`,
 		Type:        environschema.Tbool,
 		Group:       environschema.EnvironGroup,
 	},
+	UnitAgentUsersKey: {
+		Description: "Whether each unit agent deployed to a machine runs as its own system user; applies to units deployed after it is changed",
+		Type:        environschema.Tbool,
+		Group:       environschema.EnvironGroup,
+	},
 	NetBondReconfigureDelayKey: {
 		Description: "The amount of time in seconds to sleep between ifdown and ifup when bridging",
 		Type:        environschema.Tint,
@@ -1691,6 +1744,11 @@ data of the store. (default false)`,
 		Type:        environschema.Tstring,
 		Group:       environschema.EnvironGroup,
 	},
+	MaxUnitArchiveAge: {
+		Description: "How long to keep archives of the agent files of units removed from their machines, in human-readable time format (default 0, which disables archiving)",
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+	},
 	EgressSubnets: {
 		Description: "Source address(es) for traffic originating from this model",
 		Type:        environschema.Tstring,
@@ -1201,6 +1201,36 @@ func (s *ConfigSuite) TestUpdateStatusHookIntervalConfigValue(c *gc.C) {
 	c.Assert(cfg.UpdateStatusHookInterval(), gc.Equals, 30*time.Minute)
 }
 
+func (s *ConfigSuite) TestUnitAgentUsers(c *gc.C) {
+	cfg := newTestConfig(c, testing.Attrs{})
+	c.Assert(cfg.UnitAgentUsers(), jc.IsFalse)
+	cfg = newTestConfig(c, testing.Attrs{
+		"unit-agent-users": true,
+	})
+	c.Assert(cfg.UnitAgentUsers(), jc.IsTrue)
+}
+
+func (s *ConfigSuite) TestMaxUnitArchiveAgeConfigDefault(c *gc.C) {
+	cfg := newTestConfig(c, testing.Attrs{})
+	c.Assert(cfg.MaxUnitArchiveAge(), gc.Equals, time.Duration(0))
+}
+
+func (s *ConfigSuite) TestMaxUnitArchiveAgeConfigValue(c *gc.C) {
+	cfg := newTestConfig(c, testing.Attrs{
+		"max-unit-archive-age": "72h",
+	})
+	c.Assert(cfg.MaxUnitArchiveAge(), gc.Equals, 72*time.Hour)
+}
+
+func (s *ConfigSuite) TestMaxUnitArchiveAgeConfigNegative(c *gc.C) {
+	_, err := config.New(config.UseDefaults, testing.Attrs{
+		"type": "my-type", "name": "my-name",
+		"uuid":                 testing.ModelTag.Id(),
+		"max-unit-archive-age": "-1h",
+	})
+	c.Assert(err, gc.ErrorMatches, `max unit archive age -1h0m0s cannot be negative`)
+}
+
 func (s *ConfigSuite) TestEgressSubnets(c *gc.C) {
 	cfg := newTestConfig(c, testing.Attrs{
 		"egress-subnets": "10.0.0.1/32, 192.168.1.1/16",
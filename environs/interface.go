@@ -380,6 +380,14 @@ type PrecheckInstanceParams struct {
 	VolumeAttachments []storage.VolumeAttachmentParams
 }
 
+// CloudDestroyer provides the API to destroy the cloud resources
+// of a model. It is implemented by both Environs and CAAS Brokers.
+type CloudDestroyer interface {
+	// Destroy destroys all of the cloud resources
+	// belonging to the model.
+	Destroy() error
+}
+
 // CreateParams contains the parameters for Environ.Create.
 type CreateParams struct {
 	// ControllerUUID is the UUID of the controller to be that is creating
@@ -417,6 +425,19 @@ type InstanceTagger interface {
 	TagInstance(id instance.Id, tags map[string]string) error
 }
 
+// InstancePowerManager is an interface that can be used for powering
+// instances off and back on without releasing them.
+type InstancePowerManager interface {
+	// PowerOffInstances shuts down the specified instances, keeping
+	// their disks and any attached storage so that they can later
+	// be powered on again.
+	PowerOffInstances(ids ...instance.Id) error
+
+	// PowerOnInstances starts the specified instances, which must
+	// have previously been powered off with PowerOffInstances.
+	PowerOnInstances(ids ...instance.Id) error
+}
+
 // InstanceTypesFetcher is an interface that allows for instance information from
 // a provider to be obtained.
 type InstanceTypesFetcher interface {
@@ -40,3 +40,13 @@ const StrictMigration = "strict-migration"
 
 // CAAS enables creating models on CAAS infrastructure (k8s, etc)
 const CAAS = "caas"
+
+// UnitContainers causes machine agents to deploy each unit agent
+// into its own LXD container, rather than running it directly on
+// the machine.
+const UnitContainers = "unit-containers"
+
+// InProcessUnits causes machine agents to run unit agents in their
+// own process, rather than installing a service for each, falling
+// back to services if that is not possible.
+const InProcessUnits = "in-process-units"
@@ -135,8 +135,8 @@ func (s *cmdModelSuite) TestModelDefaultsGet(c *gc.C) {
 
 	context := s.run(c, "model-defaults", "special")
 	c.Assert(cmdtesting.Stdout(context), gc.Equals, `
-Attribute  Default  Controller
-special    -        known
+Attribute  Default  Controller  Cloud
+special    -        known       -
 
 `[1:])
 }
@@ -147,9 +147,9 @@ func (s *cmdModelSuite) TestModelDefaultsGetRegion(c *gc.C) {
 
 	context := s.run(c, "model-defaults", "dummy-region", "special")
 	c.Assert(cmdtesting.Stdout(context), gc.Equals, `
-Attribute       Default  Controller
-special         -        -
-  dummy-region  known    -
+Attribute       Default  Controller  Cloud
+special         -        -           -
+  dummy-region  known    -           -
 
 `[1:])
 }
@@ -173,6 +173,17 @@ func (s *cmdModelSuite) TestModelDefaultsSetRegion(c *gc.C) {
 	c.Assert(value.Regions, jc.SameContents, []config.RegionDefaultValue{{"dummy-region", "known"}})
 }
 
+func (s *cmdModelSuite) TestModelDefaultsSetCloud(c *gc.C) {
+	s.run(c, "model-defaults", "dummy", "special=known")
+	defaults, err := s.IAASModel.ModelConfigDefaultValues()
+	c.Assert(err, jc.ErrorIsNil)
+	value, found := defaults["special"]
+	c.Assert(found, jc.IsTrue)
+	c.Assert(value.Controller, gc.IsNil)
+	c.Assert(value.Cloud, gc.Equals, "known")
+	c.Assert(value.Regions, gc.HasLen, 0)
+}
+
 func (s *cmdModelSuite) TestModelDefaultsReset(c *gc.C) {
 	err := s.IAASModel.UpdateModelConfigDefaultValues(map[string]interface{}{"special": "known"}, nil, nil)
 	c.Assert(err, jc.ErrorIsNil)
@@ -111,6 +111,13 @@ func (s *cmdJujuSuite) TestApplicationUnset(c *gc.C) {
 
 func (s *cmdJujuSuite) TestApplicationGetIAASModel(c *gc.C) {
 	expected := `application: dummy-application
+application-config:
+  trust:
+    default: false
+    description: Does this application have access to trusted credentials
+    source: default
+    type: bool
+    value: false
 charm: dummy
 settings:
   outlook:
@@ -151,11 +158,54 @@ application-config:
     source: default
     type: string
     value: /
+  juju-autoscale-max-units:
+    description: the maximum number of units of an autoscaled application; setting
+      this enables autoscaling
+    source: unset
+    type: int
+  juju-autoscale-min-units:
+    description: the minimum number of units of an autoscaled application
+    source: unset
+    type: int
+  juju-autoscale-target-cpu:
+    description: the target average CPU utilisation of an autoscaled application,
+      as a percentage of requested CPU
+    source: unset
+    type: int
+  juju-deployment-mode:
+    description: whether application pods are stateless, stateful with stable identities
+      and storage, or run one per node
+    source: unset
+    type: string
   juju-external-hostname:
     description: the external hostname of an exposed application
     source: user
     type: string
     value: ext-host
+  juju-image-pull-secret:
+    description: the name of an existing secret holding the registry credentials used
+      to pull application images
+    source: unset
+    type: string
+  juju-pod-annotations:
+    description: comma separated list of key=value annotations applied to application
+      pods
+    source: unset
+    type: string
+  juju-pod-labels:
+    description: comma separated list of key=value labels applied to application pods
+    source: unset
+    type: string
+  juju-service-annotations:
+    description: comma separated list of key=value annotations applied to the application
+      service
+    source: unset
+    type: string
+  juju-service-labels:
+    description: comma separated list of key=value labels applied to the application
+      service
+    source: unset
+    type: string
   kubernetes-ingress-allow-http:
     default: false
     description: whether to allow HTTP traffic to the ingress controller
@@ -168,6 +218,10 @@ application-config:
     source: default
     type: string
     value: nginx
+  kubernetes-ingress-paths:
+    description: comma separated list of additional http paths routed to the application
+    source: unset
+    type: string
   kubernetes-ingress-ssl-passthrough:
     default: false
     description: whether to passthrough SSL traffic to the ingress controller
@@ -180,6 +234,32 @@ application-config:
     source: default
     type: bool
     value: false
+  kubernetes-ingress-tls-certificate:
+    description: the PEM encoded TLS certificate used to terminate HTTPS traffic at
+      the ingress
+    source: unset
+    type: string
+  kubernetes-ingress-tls-key:
+    description: the PEM encoded private key for the ingress TLS certificate
+    source: unset
+    type: string
+  kubernetes-ingress-tls-secret:
+    description: the name of an existing TLS secret used to terminate HTTPS traffic
+      at the ingress
+    source: unset
+    type: string
+  kubernetes-network-policy:
+    default: false
+    description: whether exposing the application restricts ingress to its pods with
+      a network policy
+    source: default
+    type: bool
+    value: false
+  kubernetes-network-policy-cidrs:
+    description: comma separated list of CIDRs from which the ports of an exposed
+      application may be reached, defaulting to all addresses
+    source: unset
+    type: string
   kubernetes-service-external-ips:
     description: list of IP addresses for which nodes in the cluster will also accept
       traffic
@@ -210,6 +290,29 @@ application-config:
     source: default
     type: string
     value: ClusterIP
+  kubernetes-update-max-surge:
+    description: the number or percentage of pods which may be created above the desired
+      number during a rolling update
+    source: unset
+    type: string
+  kubernetes-update-max-unavailable:
+    description: the number or percentage of pods which may be unavailable during
+      a rolling update
+    source: unset
+    type: string
+  kubernetes-update-strategy:
+    default: RollingUpdate
+    description: how pods are replaced when the application changes, either RollingUpdate
+      or Recreate
+    source: default
+    type: string
+    value: RollingUpdate
+  trust:
+    default: false
+    description: Does this application have access to trusted credentials
+    source: default
+    type: bool
+    value: false
 charm: dummy
 settings:
   outlook:
@@ -315,6 +418,13 @@ type cmdJujuSuiteNoCAAS struct {
 
 func (s *cmdJujuSuiteNoCAAS) TestApplicationGet(c *gc.C) {
 	expected := `application: dummy-application
+application-config:
+  trust:
+    default: false
+    description: Does this application have access to trusted credentials
+    source: default
+    type: bool
+    value: false
 charm: dummy
 settings:
   outlook:
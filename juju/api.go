@@ -136,8 +136,9 @@ func NewAPIConnection(args NewAPIConnectionParams) (_ api.Connection, err error)
 			}
 		}
 		if ok && !user.IsLocal() && apiInfo.Tag == nil {
-			// We used macaroon auth to login; save the username
-			// that we've logged in as.
+			// We used macaroon or OIDC auth to login; save the
+			// username that we've logged in as. Any tokens saved
+			// for the same user are kept by UpdateAccount.
 			accountDetails = &jujuclient.AccountDetails{
 				User:            user.Id(),
 				LastKnownAccess: st.ControllerAccess(),
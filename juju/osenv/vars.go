@@ -43,6 +43,10 @@ const (
 	// timestamps to be written in RFC3339 format.
 	JujuStatusIsoTimeEnvKey = "JUJU_STATUS_ISO_TIME"
 
+	// JujuControllerNameEnvKey is the env var which, if set, holds
+	// the name under which "juju register" registers a controller.
+	JujuControllerNameEnvKey = "JUJU_CONTROLLER_NAME"
+
 	// XDGDataHome is a path where data for the running user
 	// should be stored according to the xdg standard.
 	XDGDataHome = "XDG_DATA_HOME"
@@ -62,6 +62,38 @@ func (s *AccountsSuite) TestUpdateAccountIgnoresEmptyAccess(c *gc.C) {
 	c.Assert(*details, jc.DeepEquals, testAccountDetails)
 }
 
+func (s *AccountsSuite) TestUpdateAccountKeepsTokens(c *gc.C) {
+	err := s.store.UpdateAccount("ctrl", jujuclient.AccountDetails{
+		User:         "bob@external",
+		IDToken:      "id-token",
+		RefreshToken: "refresh-token",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Updating the same user without tokens keeps them.
+	err = s.store.UpdateAccount("ctrl", jujuclient.AccountDetails{
+		User:            "bob@external",
+		LastKnownAccess: "login",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	details, err := s.store.AccountDetails("ctrl")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(*details, jc.DeepEquals, jujuclient.AccountDetails{
+		User:            "bob@external",
+		LastKnownAccess: "login",
+		IDToken:         "id-token",
+		RefreshToken:    "refresh-token",
+	})
+
+	// Updating a different user discards them.
+	err = s.store.UpdateAccount("ctrl", jujuclient.AccountDetails{User: "alice@external"})
+	c.Assert(err, jc.ErrorIsNil)
+	details, err = s.store.AccountDetails("ctrl")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(details.IDToken, gc.Equals, "")
+	c.Assert(details.RefreshToken, gc.Equals, "")
+}
+
 func (s *AccountsSuite) TestUpdateAccountNewController(c *gc.C) {
 	testAccountDetails := jujuclient.AccountDetails{User: "admin"}
 	err := s.store.UpdateAccount("new-controller", testAccountDetails)
@@ -619,6 +619,12 @@ func (s *store) UpdateAccount(controllerName string, details AccountDetails) err
 		if details.LastKnownAccess == "" {
 			details.LastKnownAccess = oldDetails.LastKnownAccess
 		}
+		// Keep the tokens of the same user, which are not
+		// known to callers that only update the access.
+		if details.IDToken == "" && details.User == oldDetails.User {
+			details.IDToken = oldDetails.IDToken
+			details.RefreshToken = oldDetails.RefreshToken
+		}
 	}
 
 	accounts[controllerName] = details
@@ -80,6 +80,14 @@ type AccountDetails struct {
 
 	// LastKnownAccess is the last known access level for the account.
 	LastKnownAccess string `yaml:"last-known-access,omitempty"`
+
+	// IDToken is the most recent ID token issued to the account's
+	// user by the controller's OpenID Connect provider, if any.
+	IDToken string `yaml:"id-token,omitempty"`
+
+	// RefreshToken is the token used to obtain a new ID token
+	// when IDToken expires.
+	RefreshToken string `yaml:"refresh-token,omitempty"`
 }
 
 // BootstrapConfig holds the configuration used to bootstrap a controller.
@@ -343,6 +343,12 @@ func (c *MemStore) UpdateAccount(controllerName string, details AccountDetails)
 	if details.LastKnownAccess == "" {
 		details.LastKnownAccess = oldDetails.LastKnownAccess
 	}
+	// Keep the tokens of the same user, which are not
+	// known to callers that only update the access.
+	if details.IDToken == "" && details.User == oldDetails.User {
+		details.IDToken = oldDetails.IDToken
+		details.RefreshToken = oldDetails.RefreshToken
+	}
 	c.Accounts[controllerName] = details
 	return nil
 }
@@ -51,6 +51,7 @@ type PrecheckModel interface {
 	Owner() names.UserTag
 	Life() state.Life
 	MigrationMode() state.MigrationMode
+	Suspended() bool
 	CloudCredential() (names.CloudCredentialTag, bool)
 }
 
@@ -153,6 +154,9 @@ func checkModel(backend PrecheckBackend) error {
 	if model.MigrationMode() == state.MigrationModeImporting {
 		return errors.New("model is being imported as part of another migration")
 	}
+	if model.Suspended() {
+		return errors.New("model is suspended")
+	}
 	if credTag, found := model.CloudCredential(); found {
 		creds, err := backend.CloudCredential(credTag)
 		if err != nil {
@@ -93,6 +93,13 @@ func (*SourcePrecheckSuite) TestImportingModel(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, "model is being imported as part of another migration")
 }
 
+func (*SourcePrecheckSuite) TestSuspendedModel(c *gc.C) {
+	backend := newFakeBackend()
+	backend.model.suspended = true
+	err := migration.SourcePrecheck(backend)
+	c.Assert(err, gc.ErrorMatches, "model is suspended")
+}
+
 func (*SourcePrecheckSuite) TestCleanupsError(c *gc.C) {
 	backend := newFakeBackend()
 	backend.cleanupErr = errors.New("boom")
@@ -821,6 +828,7 @@ type fakeModel struct {
 	owner         names.UserTag
 	life          state.Life
 	migrationMode state.MigrationMode
+	suspended     bool
 	credential    string
 }
 
@@ -844,6 +852,10 @@ func (m *fakeModel) MigrationMode() state.MigrationMode {
 	return m.migrationMode
 }
 
+func (m *fakeModel) Suspended() bool {
+	return m.suspended
+}
+
 func (m *fakeModel) CloudCredential() (names.CloudCredentialTag, bool) {
 	if names.IsValidCloudCredential(m.credential) {
 		return names.NewCloudCredentialTag(m.credential), true
@@ -79,6 +79,16 @@ func ValidateControllerAccess(access Access) error {
 	return errors.NotValidf("%q controller access", access)
 }
 
+// ValidateCloudAccess returns error if the passed access is not a valid
+// cloud access level.
+func ValidateCloudAccess(access Access) error {
+	switch access {
+	case AddModelAccess, AdminAccess:
+		return nil
+	}
+	return errors.NotValidf("%q cloud access", access)
+}
+
 func (a Access) controllerValue() int {
 	switch a {
 	case NoAccess:
@@ -183,3 +193,26 @@ func (a Access) GreaterOfferAccessThan(access Access) bool {
 	}
 	return v1 > v2
 }
+
+func (a Access) cloudValue() int {
+	switch a {
+	case NoAccess:
+		return 0
+	case AddModelAccess:
+		return 1
+	case AdminAccess:
+		return 2
+	default:
+		return -1
+	}
+}
+
+// EqualOrGreaterCloudAccessThan returns true if the current access is
+// equal or greater than the passed in access level.
+func (a Access) EqualOrGreaterCloudAccessThan(access Access) bool {
+	v1, v2 := a.cloudValue(), access.cloudValue()
+	if v1 < 0 || v2 < 0 {
+		return false
+	}
+	return v1 >= v2
+}
@@ -199,3 +199,39 @@ func (*accessSuite) TestGreaterControllerAccessThan(c *gc.C) {
 	c.Check(superuser.GreaterControllerAccessThan(addmodel), jc.IsTrue)
 	c.Check(superuser.GreaterControllerAccessThan(superuser), jc.IsFalse)
 }
+
+func (*accessSuite) TestEqualOrGreaterCloudAccessThan(c *gc.C) {
+	var (
+		undefined = permission.NoAccess
+		read      = permission.ReadAccess
+		admin     = permission.AdminAccess
+		addmodel  = permission.AddModelAccess
+		superuser = permission.SuperuserAccess
+	)
+	// No comparison involving a non-cloud permission will return true.
+	for _, value := range []permission.Access{undefined, addmodel, admin} {
+		c.Check(value.EqualOrGreaterCloudAccessThan(read), jc.IsFalse)
+		c.Check(value.EqualOrGreaterCloudAccessThan(superuser), jc.IsFalse)
+		c.Check(read.EqualOrGreaterCloudAccessThan(value), jc.IsFalse)
+		c.Check(superuser.EqualOrGreaterCloudAccessThan(value), jc.IsFalse)
+	}
+
+	c.Check(undefined.EqualOrGreaterCloudAccessThan(undefined), jc.IsTrue)
+	c.Check(undefined.EqualOrGreaterCloudAccessThan(addmodel), jc.IsFalse)
+	c.Check(undefined.EqualOrGreaterCloudAccessThan(admin), jc.IsFalse)
+
+	c.Check(addmodel.EqualOrGreaterCloudAccessThan(undefined), jc.IsTrue)
+	c.Check(addmodel.EqualOrGreaterCloudAccessThan(addmodel), jc.IsTrue)
+	c.Check(addmodel.EqualOrGreaterCloudAccessThan(admin), jc.IsFalse)
+
+	c.Check(admin.EqualOrGreaterCloudAccessThan(undefined), jc.IsTrue)
+	c.Check(admin.EqualOrGreaterCloudAccessThan(addmodel), jc.IsTrue)
+	c.Check(admin.EqualOrGreaterCloudAccessThan(admin), jc.IsTrue)
+}
+
+func (*accessSuite) TestValidateCloudAccess(c *gc.C) {
+	c.Check(permission.ValidateCloudAccess(permission.AddModelAccess), jc.ErrorIsNil)
+	c.Check(permission.ValidateCloudAccess(permission.AdminAccess), jc.ErrorIsNil)
+	err := permission.ValidateCloudAccess(permission.SuperuserAccess)
+	c.Check(err, gc.ErrorMatches, `"superuser" cloud access not valid`)
+}
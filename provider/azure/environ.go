@@ -372,6 +372,7 @@ func (env *azureEnviron) ConstraintsValidator() (constraints.Validator, error) {
 		constraints.CpuPower,
 		constraints.Tags,
 		constraints.VirtType,
+		constraints.Gpus,
 	})
 	validator.RegisterVocabulary(
 		constraints.Arch,
@@ -197,6 +197,16 @@ type OpStopInstances struct {
 	Ids []instance.Id
 }
 
+type OpPowerOffInstances struct {
+	Env string
+	Ids []instance.Id
+}
+
+type OpPowerOnInstances struct {
+	Env string
+	Ids []instance.Id
+}
+
 type OpOpenPorts struct {
 	Env        string
 	MachineId  string
@@ -268,6 +278,7 @@ type environ struct {
 
 var _ environs.Environ = (*environ)(nil)
 var _ environs.Networking = (*environ)(nil)
+var _ environs.InstancePowerManager = (*environ)(nil)
 
 // discardOperations discards all Operations written to it.
 var discardOperations = make(chan Operation)
@@ -1171,6 +1182,67 @@ func (e *environ) StopInstances(ids ...instance.Id) error {
 	return nil
 }
 
+// PowerOffInstances is specified in the environs.InstancePowerManager
+// interface.
+func (e *environ) PowerOffInstances(ids ...instance.Id) error {
+	defer delay()
+	if err := e.checkBroken("PowerOffInstances"); err != nil {
+		return err
+	}
+	if err := e.setInstancesStatus("stopped", ids); err != nil {
+		return err
+	}
+	estate, err := e.state()
+	if err != nil {
+		return err
+	}
+	estate.ops <- OpPowerOffInstances{
+		Env: e.name,
+		Ids: ids,
+	}
+	return nil
+}
+
+// PowerOnInstances is specified in the environs.InstancePowerManager
+// interface.
+func (e *environ) PowerOnInstances(ids ...instance.Id) error {
+	defer delay()
+	if err := e.checkBroken("PowerOnInstances"); err != nil {
+		return err
+	}
+	if err := e.setInstancesStatus(string(status.Running), ids); err != nil {
+		return err
+	}
+	estate, err := e.state()
+	if err != nil {
+		return err
+	}
+	estate.ops <- OpPowerOnInstances{
+		Env: e.name,
+		Ids: ids,
+	}
+	return nil
+}
+
+func (e *environ) setInstancesStatus(instStatus string, ids []instance.Id) error {
+	estate, err := e.state()
+	if err != nil {
+		return err
+	}
+	estate.mu.Lock()
+	defer estate.mu.Unlock()
+	for _, id := range ids {
+		inst, ok := estate.insts[id]
+		if !ok {
+			return errors.NotFoundf("instance %q", id)
+		}
+		inst.mu.Lock()
+		inst.status = instStatus
+		inst.mu.Unlock()
+	}
+	return nil
+}
+
 func (e *environ) Instances(ids []instance.Id) (insts []instance.Instance, err error) {
 	defer delay()
 	if err := e.checkBroken("Instances"); err != nil {
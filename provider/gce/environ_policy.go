@@ -33,6 +33,7 @@ func (env *environ) PrecheckInstance(args environs.PrecheckInstanceParams) error
 var unsupportedConstraints = []string{
 	constraints.Tags,
 	constraints.VirtType,
+	constraints.Gpus,
 }
 
 // instanceTypeConstraints defines the fields defined on each of the
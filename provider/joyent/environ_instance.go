@@ -48,6 +48,7 @@ var unsupportedConstraints = []string{
 	constraints.CpuPower,
 	constraints.Tags,
 	constraints.VirtType,
+	constraints.Gpus,
 }
 
 // ConstraintsValidator is defined on the Environs interface.
@@ -295,3 +295,27 @@ func (env *environ) StopInstances(instances ...instance.Id) error {
 	err := env.raw.RemoveInstances(prefix, ids...)
 	return errors.Trace(err)
 }
+
+var _ environs.InstancePowerManager = (*environ)(nil)
+
+// PowerOffInstances implements environs.InstancePowerManager.
+func (env *environ) PowerOffInstances(instances ...instance.Id) error {
+	var ids []string
+	for _, id := range instances {
+		ids = append(ids, string(id))
+	}
+	prefix := env.namespace.Prefix()
+	err := env.raw.StopInstances(prefix, ids...)
+	return errors.Trace(err)
+}
+
+// PowerOnInstances implements environs.InstancePowerManager.
+func (env *environ) PowerOnInstances(instances ...instance.Id) error {
+	var ids []string
+	for _, id := range instances {
+		ids = append(ids, string(id))
+	}
+	prefix := env.namespace.Prefix()
+	err := env.raw.StartInstances(prefix, ids...)
+	return errors.Trace(err)
+}
@@ -63,6 +63,32 @@ func (s *environBrokerSuite) TestStopInstances(c *gc.C) {
 	}})
 }
 
+func (s *environBrokerSuite) TestPowerOffInstances(c *gc.C) {
+	err := s.Env.PowerOffInstances(s.Instance.Id())
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.Stub.CheckCalls(c, []gitjujutesting.StubCall{{
+		FuncName: "StopInstances",
+		Args: []interface{}{
+			"juju-f75cba-",
+			[]string{"spam"},
+		},
+	}})
+}
+
+func (s *environBrokerSuite) TestPowerOnInstances(c *gc.C) {
+	err := s.Env.PowerOnInstances(s.Instance.Id())
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.Stub.CheckCalls(c, []gitjujutesting.StubCall{{
+		FuncName: "StartInstances",
+		Args: []interface{}{
+			"juju-f75cba-",
+			[]string{"spam"},
+		},
+	}})
+}
+
 func (s *environBrokerSuite) TestImageMetadataURL(c *gc.C) {
 	s.UpdateConfig(c, map[string]interface{}{
 		"image-metadata-url": "https://my-test.com/images/",
@@ -42,6 +42,8 @@ type lxdInstances interface {
 	Instances(string, ...string) ([]lxdclient.Instance, error)
 	AddInstance(lxdclient.InstanceSpec) (*lxdclient.Instance, error)
 	RemoveInstances(string, ...string) error
+	StopInstances(string, ...string) error
+	StartInstances(string, ...string) error
 	Addresses(string) ([]network.Address, error)
 	AttachDisk(string, string, lxdclient.DiskDevice) error
 	RemoveDevice(string, string) error
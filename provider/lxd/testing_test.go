@@ -502,6 +502,24 @@ func (conn *StubClient) RemoveInstances(prefix string, ids ...string) error {
 	return nil
 }
 
+func (conn *StubClient) StopInstances(prefix string, ids ...string) error {
+	conn.AddCall("StopInstances", prefix, ids)
+	if err := conn.NextErr(); err != nil {
+		return errors.Trace(err)
+	}
+
+	return nil
+}
+
+func (conn *StubClient) StartInstances(prefix string, ids ...string) error {
+	conn.AddCall("StartInstances", prefix, ids)
+	if err := conn.NextErr(); err != nil {
+		return errors.Trace(err)
+	}
+
+	return nil
+}
+
 func (conn *StubClient) EnsureImageExists(series, arch string, _ []lxdclient.Remote, _ func(string)) (string, error) {
 	conn.AddCall("EnsureImageExists", series, arch)
 	if err := conn.NextErr(); err != nil {
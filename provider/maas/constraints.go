@@ -20,6 +20,7 @@ var unsupportedConstraints = []string{
 	constraints.CpuPower,
 	constraints.InstanceType,
 	constraints.VirtType,
+	constraints.Gpus,
 }
 
 // ConstraintsValidator is defined on the Environs interface.
@@ -323,6 +323,7 @@ var unsupportedConstraints = []string{
 	constraints.InstanceType,
 	constraints.Tags,
 	constraints.VirtType,
+	constraints.Gpus,
 }
 
 // ConstraintsValidator is defined on the Environs interface.
@@ -0,0 +1,43 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package agent defines the topics and messages published on an
+// agent's local hub, through which the workers running in a single
+// agent tell each other about events in the agent.
+package agent
+
+import (
+	"github.com/juju/version"
+)
+
+const (
+	// APIConnectionLostTopic is published by the api-caller when
+	// the agent's API connection breaks unexpectedly. It carries
+	// no data.
+	APIConnectionLostTopic = "agent.api-connection-lost"
+
+	// APIAddressesChangedTopic is published by the api-config-watcher
+	// when the API addresses in the agent's config change.
+	APIAddressesChangedTopic = "agent.api-addresses-changed"
+
+	// UpgradeStartingTopic is published by the upgrader when the
+	// agent binaries for a new version have been downloaded, and the
+	// agent is about to restart to run them.
+	UpgradeStartingTopic = "agent.upgrade-starting"
+)
+
+// APIAddresses represents the data for the api addresses changed
+// topic.
+type APIAddresses struct {
+	// Addresses holds the API addresses now in the agent's config.
+	Addresses []string
+}
+
+// UpgradeStarting represents the data for the upgrade starting topic.
+type UpgradeStarting struct {
+	// From is the version of the running agent binaries.
+	From version.Binary
+
+	// To is the version the agent is upgrading to.
+	To version.Binary
+}
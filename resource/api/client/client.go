@@ -4,11 +4,15 @@
 package client
 
 import (
+	"bytes"
 	"io"
 	"net/http"
+	"os"
 	"strings"
+	"time"
 
 	"github.com/juju/errors"
+	"github.com/juju/loggo"
 	charmresource "gopkg.in/juju/charm.v6/resource"
 	"gopkg.in/juju/names.v2"
 	"gopkg.in/macaroon.v1"
@@ -20,6 +24,8 @@ import (
 	"github.com/juju/juju/resource/api"
 )
 
+var logger = loggo.GetLogger("juju.resource.api.client")
+
 // TODO(ericsnow) Move FacadeCaller to a component-central package.
 
 // FacadeCaller has the api/base.FacadeCaller methods needed for the component.
@@ -36,7 +42,8 @@ type Doer interface {
 type Client struct {
 	FacadeCaller
 	io.Closer
-	doer Doer
+	doer    Doer
+	chunked ChunkedUploadConfig
 }
 
 // NewClient returns a new Client for the given raw API caller.
@@ -48,6 +55,31 @@ func NewClient(caller FacadeCaller, doer Doer, closer io.Closer) *Client {
 	}
 }
 
+// ChunkedUploadConfig holds the configuration for uploading
+// resources in chunks.
+type ChunkedUploadConfig struct {
+	// ChunkSize is the maximum number of bytes sent in each chunk.
+	// Resources no larger than this are uploaded whole.
+	ChunkSize int64
+
+	// MaxRetries is the number of times in a row sending a chunk
+	// is retried after the connection to the controller fails.
+	MaxRetries int
+
+	// RetryDelay is how long to wait before retrying.
+	RetryDelay time.Duration
+}
+
+// NewClientWithChunkedUploads returns a new Client for the given raw
+// API caller, which uploads large resources in chunks so that uploads
+// interrupted by connection failures are resumed. The controller must
+// support version 2 or later of the resources facade.
+func NewClientWithChunkedUploads(caller FacadeCaller, doer Doer, closer io.Closer, config ChunkedUploadConfig) *Client {
+	client := NewClient(caller, doer, closer)
+	client.chunked = config
+	return client
+}
+
 // ListResources calls the ListResources API server method with
 // the given application names.
 func (c Client) ListResources(services []string) ([]resource.ServiceResources, error) {
@@ -107,10 +139,31 @@ func newListResourcesArgs(services []string) (params.ListResourcesArgs, error) {
 
 // Upload sends the provided resource blob up to Juju.
 func (c Client) Upload(service, name, filename string, reader io.ReadSeeker) error {
+	return c.UploadWithProgress(service, name, filename, reader, nil)
+}
+
+// UploadWithProgress sends the provided resource blob up to Juju,
+// calling progress, if it is not nil, with the number of bytes the
+// controller has received as the upload proceeds.
+func (c Client) UploadWithProgress(service, name, filename string, reader io.ReadSeeker, progress func(uploaded, size int64)) error {
 	uReq, err := api.NewUploadRequest(service, name, filename, reader)
 	if err != nil {
 		return errors.Trace(err)
 	}
+	return errors.Trace(c.upload(uReq, reader, progress))
+}
+
+// upload sends the resource blob described by the upload request up
+// to Juju, in chunks if it is large and the controller supports that.
+func (c Client) upload(uReq api.UploadRequest, reader io.ReadSeeker, progress func(uploaded, size int64)) error {
+	if progress == nil {
+		progress = func(int64, int64) {}
+	}
+	if c.chunked.ChunkSize > 0 && uReq.Size > c.chunked.ChunkSize {
+		return errors.Trace(c.uploadChunks(uReq, reader, progress))
+	}
+
+	progress(0, uReq.Size)
 	req, err := uReq.HTTPRequest()
 	if err != nil {
 		return errors.Trace(err)
@@ -120,10 +173,85 @@ func (c Client) Upload(service, name, filename string, reader io.ReadSeeker) err
 	if err := c.doer.Do(req, reader, &response); err != nil {
 		return errors.Trace(err)
 	}
+	progress(uReq.Size, uReq.Size)
 
 	return nil
 }
 
+// uploadChunks sends the resource blob up to Juju in chunks. Any of
+// the resource the controller has already received, from an earlier
+// upload of the same content that was interrupted, is not resent.
+func (c Client) uploadChunks(uReq api.UploadRequest, reader io.ReadSeeker, progress func(uploaded, size int64)) error {
+	buf := make([]byte, c.chunked.ChunkSize)
+	offset, err := c.uploadChunk(uReq, api.ContentRange{Size: uReq.Size}, nil)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	retries := 0
+	for offset < uReq.Size {
+		progress(offset, uReq.Size)
+		rng := api.ContentRange{
+			Start:  offset,
+			Length: c.chunked.ChunkSize,
+			Size:   uReq.Size,
+		}
+		if rng.Start+rng.Length > rng.Size {
+			rng.Length = rng.Size - rng.Start
+		}
+		chunk := buf[:rng.Length]
+		if _, err := reader.Seek(rng.Start, os.SEEK_SET); err != nil {
+			return errors.Trace(err)
+		}
+		if _, err := io.ReadFull(reader, chunk); err != nil {
+			return errors.Trace(err)
+		}
+
+		received, err := c.uploadChunk(uReq, rng, chunk)
+		if err == nil {
+			offset = received
+			retries = 0
+			continue
+		}
+		if _, ok := errors.Cause(err).(*params.Error); ok || retries >= c.chunked.MaxRetries {
+			// The controller rejected the chunk, or the
+			// connection has failed too many times.
+			return errors.Trace(err)
+		}
+		retries++
+		logger.Debugf("retrying upload of resource %q after error: %v", uReq.Name, err)
+		if c.chunked.RetryDelay > 0 {
+			<-time.After(c.chunked.RetryDelay)
+		}
+		// The connection may have failed part way through
+		// the chunk, so ask how much of it was received.
+		received, err = c.uploadChunk(uReq, api.ContentRange{Size: uReq.Size}, nil)
+		if err == nil {
+			offset = received
+		}
+	}
+	progress(uReq.Size, uReq.Size)
+	return nil
+}
+
+// uploadChunk sends the chunk of the resource in the given range,
+// returning the number of bytes of the resource the controller has
+// received.
+func (c Client) uploadChunk(uReq api.UploadRequest, rng api.ContentRange, chunk []byte) (int64, error) {
+	req, err := uReq.ChunkHTTPRequest(rng)
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	var body io.ReadSeeker
+	if chunk != nil {
+		body = bytes.NewReader(chunk)
+	}
+	var response params.UploadResult
+	if err := c.doer.Do(req, body, &response); err != nil {
+		return 0, errors.Trace(err)
+	}
+	return response.Offset, nil
+}
+
 // AddPendingResourcesArgs holds the arguments to AddPendingResources().
 type AddPendingResourcesArgs struct {
 	// ApplicationID identifies the application being deployed.
@@ -217,13 +345,7 @@ func (c Client) UploadPendingResource(applicationID string, res charmresource.Re
 			return "", errors.Trace(err)
 		}
 		uReq.PendingID = pendingID
-		req, err := uReq.HTTPRequest()
-		if err != nil {
-			return "", errors.Trace(err)
-		}
-
-		var response params.UploadResult // ignored
-		if err := c.doer.Do(req, reader, &response); err != nil {
+		if err := c.upload(uReq, reader, nil); err != nil {
 			return "", errors.Trace(err)
 		}
 	}
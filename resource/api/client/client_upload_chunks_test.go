@@ -0,0 +1,195 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package client_test
+
+import (
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/resource/api"
+	"github.com/juju/juju/resource/api/client"
+)
+
+var _ = gc.Suite(&ChunkedUploadSuite{})
+
+type ChunkedUploadSuite struct {
+	testing.IsolationSuite
+
+	stub   *testing.Stub
+	facade *stubFacade
+	server *chunkServer
+}
+
+const chunkedData = "0123456789abcdefghij"
+
+func (s *ChunkedUploadSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+
+	s.stub = &testing.Stub{}
+	s.facade = newStubFacade(c, s.stub)
+	s.server = &chunkServer{c: c, stub: s.stub}
+}
+
+func (s *ChunkedUploadSuite) newClient(maxRetries int) *client.Client {
+	return client.NewClientWithChunkedUploads(s.facade, s.server, s.facade, client.ChunkedUploadConfig{
+		ChunkSize:  8,
+		MaxRetries: maxRetries,
+	})
+}
+
+func (s *ChunkedUploadSuite) upload(cl *client.Client) ([][2]int64, error) {
+	var progress [][2]int64
+	err := cl.UploadWithProgress("a-application", "spam", "foo.zip", strings.NewReader(chunkedData),
+		func(uploaded, size int64) {
+			progress = append(progress, [2]int64{uploaded, size})
+		},
+	)
+	return progress, err
+}
+
+func (s *ChunkedUploadSuite) TestUploadChunks(c *gc.C) {
+	progress, err := s.upload(s.newClient(0))
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(s.server.received, gc.Equals, chunkedData)
+	c.Check(s.server.ranges, jc.DeepEquals, []string{
+		"bytes */20",
+		"bytes 0-7/20",
+		"bytes 8-15/20",
+		"bytes 16-19/20",
+	})
+	c.Check(progress, jc.DeepEquals, [][2]int64{{0, 20}, {8, 20}, {16, 20}, {20, 20}})
+}
+
+func (s *ChunkedUploadSuite) TestUploadChunksResumes(c *gc.C) {
+	// An earlier upload was interrupted after 8 bytes were received.
+	s.server.received = chunkedData[:8]
+
+	progress, err := s.upload(s.newClient(0))
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(s.server.received, gc.Equals, chunkedData)
+	c.Check(s.server.ranges, jc.DeepEquals, []string{
+		"bytes */20",
+		"bytes 8-15/20",
+		"bytes 16-19/20",
+	})
+	c.Check(progress, jc.DeepEquals, [][2]int64{{8, 20}, {16, 20}, {20, 20}})
+}
+
+func (s *ChunkedUploadSuite) TestUploadChunksRetries(c *gc.C) {
+	// The connection fails after 4 bytes of the second chunk are sent.
+	s.stub.SetErrors(nil, nil, errors.New("connection reset"))
+	s.server.partial = 4
+
+	_, err := s.upload(s.newClient(1))
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(s.server.received, gc.Equals, chunkedData)
+	c.Check(s.server.ranges, jc.DeepEquals, []string{
+		"bytes */20",
+		"bytes 0-7/20",
+		"bytes 8-15/20",
+		"bytes */20",
+		"bytes 12-19/20",
+	})
+}
+
+func (s *ChunkedUploadSuite) TestUploadChunksTooManyRetries(c *gc.C) {
+	failure := errors.New("connection reset")
+	s.stub.SetErrors(nil, failure, nil, failure)
+
+	_, err := s.upload(s.newClient(1))
+	c.Assert(errors.Cause(err), gc.Equals, failure)
+
+	c.Check(s.server.ranges, jc.DeepEquals, []string{
+		"bytes */20",
+		"bytes 0-7/20",
+		"bytes */20",
+		"bytes 0-7/20",
+	})
+}
+
+func (s *ChunkedUploadSuite) TestUploadChunksRejected(c *gc.C) {
+	failure := &params.Error{Message: "checksum mismatch"}
+	s.stub.SetErrors(nil, nil, nil, failure)
+
+	_, err := s.upload(s.newClient(3))
+	c.Assert(errors.Cause(err), gc.Equals, failure)
+
+	// Errors from the controller are not retried.
+	c.Check(s.server.ranges, jc.DeepEquals, []string{
+		"bytes */20",
+		"bytes 0-7/20",
+		"bytes 8-15/20",
+		"bytes 16-19/20",
+	})
+}
+
+func (s *ChunkedUploadSuite) TestUploadSmallResourceWhole(c *gc.C) {
+	cl := s.newClient(0)
+
+	err := cl.Upload("a-application", "spam", "foo.zip", strings.NewReader("<data>"))
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(s.server.received, gc.Equals, "<data>")
+	c.Check(s.server.ranges, jc.DeepEquals, []string{""})
+}
+
+// chunkServer is a Doer that receives the chunks of resources
+// as the controller does.
+type chunkServer struct {
+	c    *gc.C
+	stub *testing.Stub
+
+	// received holds the bytes of the resource received.
+	received string
+
+	// partial is the number of bytes of a chunk received
+	// before a connection failure.
+	partial int
+
+	// ranges holds the Content-Range of each request.
+	ranges []string
+}
+
+func (s *chunkServer) Do(req *http.Request, body io.ReadSeeker, resp interface{}) error {
+	value := req.Header.Get(api.HeaderContentRange)
+	s.stub.AddCall("Do", value)
+	s.ranges = append(s.ranges, value)
+
+	var data []byte
+	if body != nil {
+		var err error
+		data, err = ioutil.ReadAll(body)
+		s.c.Assert(err, jc.ErrorIsNil)
+	}
+	if value == "" {
+		s.received = string(data)
+		return s.stub.NextErr()
+	}
+
+	rng, err := api.ParseContentRange(value)
+	s.c.Assert(err, jc.ErrorIsNil)
+	s.c.Assert(rng.Start <= int64(len(s.received)), jc.IsTrue)
+	if err := s.stub.NextErr(); err != nil {
+		if rng.Length > 0 {
+			s.received = s.received[:rng.Start] + string(data[:s.partial])
+		}
+		return err
+	}
+	if rng.Length > 0 {
+		s.received = s.received[:rng.Start] + string(data)
+	}
+	resp.(*params.UploadResult).Offset = int64(len(s.received))
+	return nil
+}
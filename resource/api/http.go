@@ -45,6 +45,11 @@ const (
 	// The params are formatted according to  RFC 2045 and RFC 2616 (see
 	// mime.ParseMediaType and mime.FormatMediaType).
 	HeaderContentDisposition = "Content-Disposition"
+	// HeaderContentRange is the header name for the range of bytes of
+	// a resource sent in one chunk of an upload (see RFC 7233). A
+	// range with no bytes ("bytes */<size>") asks how many bytes of
+	// the resource have been received.
+	HeaderContentRange = "Content-Range"
 )
 
 const (
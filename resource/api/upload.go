@@ -8,6 +8,8 @@ import (
 	"io"
 	"mime"
 	"net/http"
+	"regexp"
+	"strconv"
 
 	"github.com/juju/errors"
 	charmresource "gopkg.in/juju/charm.v6/resource"
@@ -105,3 +107,70 @@ func (ur UploadRequest) HTTPRequest() (*http.Request, error) {
 
 	return req, nil
 }
+
+// ChunkHTTPRequest generates a new HTTP request for the chunk of the
+// resource in the given range, for uploads sent in chunks. The
+// request for an empty range asks how many bytes of the resource
+// the controller has received.
+func (ur UploadRequest) ChunkHTTPRequest(rng ContentRange) (*http.Request, error) {
+	req, err := ur.HTTPRequest()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	req.Header.Set(HeaderContentRange, rng.String())
+	req.Header.Set(HeaderContentLength, fmt.Sprint(rng.Length))
+	req.ContentLength = rng.Length
+	return req, nil
+}
+
+// ContentRange describes the bytes of a resource sent in one chunk
+// of an upload.
+type ContentRange struct {
+	// Start is the offset of the first byte of the chunk.
+	Start int64
+
+	// Length is the number of bytes in the chunk.
+	Length int64
+
+	// Size is the size of the whole resource, in bytes.
+	Size int64
+}
+
+// String returns the range in the form used for the
+// Content-Range header.
+func (rng ContentRange) String() string {
+	if rng.Length == 0 {
+		return fmt.Sprintf("bytes */%d", rng.Size)
+	}
+	return fmt.Sprintf("bytes %d-%d/%d", rng.Start, rng.Start+rng.Length-1, rng.Size)
+}
+
+var contentRangeRE = regexp.MustCompile(`^bytes (?:(\d+)-(\d+)|\*)/(\d+)$`)
+
+// ParseContentRange parses the value of a Content-Range header.
+func ParseContentRange(value string) (ContentRange, error) {
+	var rng ContentRange
+	match := contentRangeRE.FindStringSubmatch(value)
+	if match == nil {
+		return rng, errors.NotValidf("content range %q", value)
+	}
+	size, err := strconv.ParseInt(match[3], 10, 64)
+	if err != nil {
+		return rng, errors.NotValidf("content range %q", value)
+	}
+	rng.Size = size
+	if match[1] == "" {
+		return rng, nil
+	}
+	start, err := strconv.ParseInt(match[1], 10, 64)
+	if err != nil {
+		return rng, errors.NotValidf("content range %q", value)
+	}
+	end, err := strconv.ParseInt(match[2], 10, 64)
+	if err != nil || end < start || end >= size {
+		return rng, errors.NotValidf("content range %q", value)
+	}
+	rng.Start = start
+	rng.Length = end - start + 1
+	return rng, nil
+}
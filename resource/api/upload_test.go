@@ -0,0 +1,70 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package api_test
+
+import (
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/resource/api"
+)
+
+type UploadSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&UploadSuite{})
+
+func (UploadSuite) TestContentRangeString(c *gc.C) {
+	c.Check(api.ContentRange{Start: 0, Length: 10, Size: 25}.String(), gc.Equals, "bytes 0-9/25")
+	c.Check(api.ContentRange{Start: 20, Length: 5, Size: 25}.String(), gc.Equals, "bytes 20-24/25")
+	c.Check(api.ContentRange{Size: 25}.String(), gc.Equals, "bytes */25")
+}
+
+func (UploadSuite) TestParseContentRange(c *gc.C) {
+	for _, rng := range []api.ContentRange{
+		{Start: 0, Length: 10, Size: 25},
+		{Start: 20, Length: 5, Size: 25},
+		{Size: 25},
+	} {
+		parsed, err := api.ParseContentRange(rng.String())
+		c.Check(err, jc.ErrorIsNil)
+		c.Check(parsed, jc.DeepEquals, rng)
+	}
+}
+
+func (UploadSuite) TestParseContentRangeInvalid(c *gc.C) {
+	for _, value := range []string{
+		"",
+		"bytes 0-9",
+		"bytes 9-0/25",
+		"bytes 20-25/25",
+		"items 0-9/25",
+		"bytes 0-9/*",
+	} {
+		_, err := api.ParseContentRange(value)
+		c.Check(err, jc.Satisfies, errors.IsNotValid, gc.Commentf("%q", value))
+	}
+}
+
+func (UploadSuite) TestChunkHTTPRequest(c *gc.C) {
+	uReq, err := api.NewUploadRequest("a-application", "spam", "spam.tgz", strings.NewReader("<some data>"))
+	c.Assert(err, jc.ErrorIsNil)
+	uReq.PendingID = "some-unique-id"
+
+	req, err := uReq.ChunkHTTPRequest(api.ContentRange{Start: 2, Length: 4, Size: uReq.Size})
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(req.Method, gc.Equals, "PUT")
+	c.Check(req.URL.Path, gc.Equals, "/applications/a-application/resources/spam")
+	c.Check(req.URL.Query().Get("pendingid"), gc.Equals, "some-unique-id")
+	c.Check(req.Header.Get("Content-Range"), gc.Equals, "bytes 2-5/11")
+	c.Check(req.Header.Get("Content-Length"), gc.Equals, "4")
+	c.Check(req.Header.Get("Content-Sha384"), gc.Equals, uReq.Fingerprint.String())
+	c.Check(req.ContentLength, gc.Equals, int64(4))
+}
@@ -4,6 +4,8 @@
 package resourceadapters
 
 import (
+	"time"
+
 	"github.com/juju/errors"
 
 	"github.com/juju/juju/api/base"
@@ -22,6 +24,28 @@ func NewAPIClient(apiCaller base.APICallCloser) (*client.Client, error) {
 		return nil, errors.Trace(err)
 	}
 	// The apiCaller takes care of prepending /environment/<modelUUID>.
-	apiClient := client.NewClient(caller, httpClient, apiCaller)
+	if apiCaller.BestFacadeVersion(resource.FacadeName) < 2 {
+		// Older controllers only accept whole uploads.
+		return client.NewClient(caller, httpClient, apiCaller), nil
+	}
+	apiClient := client.NewClientWithChunkedUploads(caller, httpClient, apiCaller, client.ChunkedUploadConfig{
+		ChunkSize:  uploadChunkSize,
+		MaxRetries: uploadMaxRetries,
+		RetryDelay: uploadRetryDelay,
+	})
 	return apiClient, nil
 }
+
+const (
+	// uploadChunkSize is the size of the chunks in which
+	// resources larger than it are uploaded.
+	uploadChunkSize = 16 * 1024 * 1024
+
+	// uploadMaxRetries is the number of times in a row sending
+	// a chunk of a resource is retried after the connection to
+	// the controller fails.
+	uploadMaxRetries = 10
+
+	// uploadRetryDelay is how long to wait before retrying.
+	uploadRetryDelay = 5 * time.Second
+)
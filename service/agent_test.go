@@ -182,6 +182,32 @@ func (*agentSuite) TestAgentConfUnit(c *gc.C) {
 	})
 }
 
+func (*agentSuite) TestAgentConfUnitLoggingConfig(c *gc.C) {
+	dataDir := c.MkDir()
+	logDir := c.MkDir()
+	info := service.NewUnitAgentInfo("wordpress/0", dataDir, logDir)
+	info.LoggingConfig = "<root>=WARNING;unit=INFO"
+	renderer, err := shell.NewRenderer("")
+	c.Assert(err, jc.ErrorIsNil)
+	conf := service.AgentConf(info, renderer)
+
+	jujud := filepath.Join(dataDir, "tools", "unit-wordpress-0", "jujud"+cmdSuffix)
+	cmd := strings.Join([]string{
+		shquote(jujud),
+		"unit",
+		"--data-dir", shquote(dataDir),
+		"--unit-name", "wordpress/0",
+		"--logging-config", shquote("<root>=WARNING;unit=INFO"),
+	}, " ")
+	c.Check(conf.ExecStart, gc.Equals, cmd)
+	c.Check(conf.ServiceArgs, jc.DeepEquals, []string{
+		"unit",
+		"--data-dir", dataDir,
+		"--unit-name", "wordpress/0",
+		"--logging-config", "<root>=WARNING;unit=INFO",
+	})
+}
+
 func (*agentSuite) TestContainerAgentConf(c *gc.C) {
 	dataDir := c.MkDir()
 	logDir := c.MkDir()
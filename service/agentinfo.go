@@ -44,6 +44,10 @@ type AgentInfo struct {
 
 	// LogDir is the path to the agent's log dir.
 	LogDir string
+
+	// LoggingConfig, if set, is the logging config with which the
+	// agent is started. Otherwise the agent starts with debug logging.
+	LoggingConfig string
 }
 
 // NewAgentInfo composes a new AgentInfo for the given essentials.
@@ -82,16 +86,19 @@ func (ai AgentInfo) jujud(renderer shell.Renderer) string {
 }
 
 func (ai AgentInfo) cmd(renderer shell.Renderer) string {
-	// The agent always starts with debug turned on. The logger worker
-	// will update this to the system logging environment as soon as
-	// it starts.
-	return strings.Join([]string{
+	// Unless given a logging config, the agent starts with debug
+	// turned on. The logger worker will update this to the system
+	// logging environment as soon as it starts.
+	loggingArgs := ai.loggingArgs()
+	if len(loggingArgs) > 1 {
+		loggingArgs[1] = renderer.Quote(loggingArgs[1])
+	}
+	return strings.Join(append([]string{
 		renderer.Quote(ai.jujud(renderer)),
 		string(ai.Kind),
 		"--data-dir", renderer.Quote(renderer.FromSlash(ai.DataDir)),
 		idOptions[ai.Kind], ai.ID,
-		"--debug",
-	}, " ")
+	}, loggingArgs...), " ")
 }
 
 // execArgs returns an unquoted array of service arguments in case we need
@@ -99,12 +106,20 @@ func (ai AgentInfo) cmd(renderer shell.Renderer) string {
 // package, where CreateService correctly does quoting of executable path and
 // individual arguments
 func (ai AgentInfo) execArgs(renderer shell.Renderer) []string {
-	return []string{
+	return append([]string{
 		string(ai.Kind),
 		"--data-dir", renderer.FromSlash(ai.DataDir),
 		idOptions[ai.Kind], ai.ID,
-		"--debug",
+	}, ai.loggingArgs()...)
+}
+
+// loggingArgs returns the unquoted arguments which set the
+// agent's initial logging config.
+func (ai AgentInfo) loggingArgs() []string {
+	if ai.LoggingConfig == "" {
+		return []string{"--debug"}
 	}
+	return []string{"--logging-config", ai.LoggingConfig}
 }
 
 func (ai AgentInfo) logFile(renderer shell.Renderer) string {
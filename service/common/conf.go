@@ -36,6 +36,23 @@ type Conf struct {
 	// Currently not used on Windows.
 	Limit map[string]int
 
+	// CPUShares, if positive, is the relative share of CPU time
+	// given to the service's processes when the CPU is contended.
+	// Process groups otherwise get 1024 shares each.
+	// Currently not used on Windows.
+	CPUShares int
+
+	// MemoryLimitMB, if positive, caps the memory that may be used
+	// by the service's processes, in MB.
+	// Currently not used on Windows.
+	MemoryLimitMB uint64
+
+	// User, if set, is the name of the system user as which the
+	// service's processes are run. The service's log file, if any,
+	// must be writable by the user.
+	// Currently not used on Windows.
+	User string
+
 	// Timeout is how many seconds may pass before an exec call (e.g.
 	// ExecStart) times out. Values less than or equal to 0 (the
 	// default) are treated as though there is no timeout.
@@ -210,6 +210,30 @@ func serializeService(conf common.Conf) []*unit.UnitOption {
 		})
 	}
 
+	if conf.CPUShares > 0 {
+		unitOptions = append(unitOptions, &unit.UnitOption{
+			Section: "Service",
+			Name:    "CPUShares",
+			Value:   strconv.Itoa(conf.CPUShares),
+		})
+	}
+
+	if conf.MemoryLimitMB > 0 {
+		unitOptions = append(unitOptions, &unit.UnitOption{
+			Section: "Service",
+			Name:    "MemoryLimit",
+			Value:   strconv.FormatUint(conf.MemoryLimitMB, 10) + "M",
+		})
+	}
+
+	if conf.User != "" {
+		unitOptions = append(unitOptions, &unit.UnitOption{
+			Section: "Service",
+			Name:    "User",
+			Value:   conf.User,
+		})
+	}
+
 	if conf.ExecStart != "" {
 		unitOptions = append(unitOptions, &unit.UnitOption{
 			Section: "Service",
@@ -313,6 +337,20 @@ func deserializeOptions(opts []*unit.UnitOption, renderer shell.Renderer) (commo
 						break
 					}
 				}
+			case uo.Name == "CPUShares":
+				shares, err := strconv.Atoi(uo.Value)
+				if err != nil {
+					return conf, errors.Trace(err)
+				}
+				conf.CPUShares = shares
+			case uo.Name == "MemoryLimit":
+				limit, err := strconv.ParseUint(strings.TrimSuffix(uo.Value, "M"), 10, 64)
+				if err != nil {
+					return conf, errors.Trace(err)
+				}
+				conf.MemoryLimitMB = limit
+			case uo.Name == "User":
+				conf.User = uo.Value
 			case uo.Name == "TimeoutSec":
 				timeout, err := strconv.Atoi(uo.Value)
 				if err != nil {
@@ -407,6 +407,18 @@ func (s *initSystemSuite) TestExistsTrue(c *gc.C) {
 	s.stub.CheckCallNames(c, "RunCommand")
 }
 
+func (s *initSystemSuite) TestExistsResourceLimits(c *gc.C) {
+	s.conf.CPUShares = 512
+	s.conf.MemoryLimitMB = 2048
+	s.setConf(c, s.conf)
+
+	exists, err := s.newService(c).Exists()
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(exists, jc.IsTrue)
+	s.stub.CheckCallNames(c, "RunCommand")
+}
+
 func (s *initSystemSuite) TestExistsFalse(c *gc.C) {
 	// We force the systemd API to return a slightly different conf.
 	// In this case we simply set Conf.Env, which s.conf does not set.
@@ -810,6 +822,45 @@ exec 2>&1
 	test.CheckCommands(c, commands)
 }
 
+func (s *initSystemSuite) TestInstallCommandsResourceLimits(c *gc.C) {
+	name := "jujud-machine-0"
+	s.conf.CPUShares = 512
+	s.conf.MemoryLimitMB = 2048
+	service := s.newService(c)
+	commands, err := service.InstallCommands()
+	c.Assert(err, jc.ErrorIsNil)
+
+	test := systemdtesting.WriteConfTest{
+		Service: name,
+		DataDir: s.dataDir,
+		Expected: strings.Replace(
+			s.newConfStr(name),
+			"[Service]\n",
+			"[Service]\nCPUShares=512\nMemoryLimit=2048M\n",
+			1),
+	}
+	test.CheckCommands(c, commands)
+}
+
+func (s *initSystemSuite) TestInstallCommandsUser(c *gc.C) {
+	name := "jujud-machine-0"
+	s.conf.User = "juju-unit-foo-0"
+	service := s.newService(c)
+	commands, err := service.InstallCommands()
+	c.Assert(err, jc.ErrorIsNil)
+
+	test := systemdtesting.WriteConfTest{
+		Service: name,
+		DataDir: s.dataDir,
+		Expected: strings.Replace(
+			s.newConfStr(name),
+			"[Service]\n",
+			"[Service]\nUser=juju-unit-foo-0\n",
+			1),
+	}
+	test.CheckCommands(c, commands)
+}
+
 func (s *initSystemSuite) TestInstallCommandsShutdown(c *gc.C) {
 	name := "juju-shutdown-job"
 	conf, err := service.ShutdownAfterConf("cloud-final")
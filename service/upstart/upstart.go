@@ -144,6 +144,12 @@ func (s *Service) Validate() error {
 		if len(s.Service.Conf.Limit) > 0 {
 			return errors.NotSupportedf("Conf.Limit (when transient)")
 		}
+		if s.Service.Conf.CPUShares > 0 || s.Service.Conf.MemoryLimitMB > 0 {
+			return errors.NotSupportedf("Conf.CPUShares or Conf.MemoryLimitMB (when transient)")
+		}
+		if s.Service.Conf.User != "" {
+			return errors.NotSupportedf("Conf.User (when transient)")
+		}
 		if s.Service.Conf.Logfile != "" {
 			return errors.NotSupportedf("Conf.Logfile (when transient)")
 		}
@@ -363,10 +369,14 @@ normal exit 0
 {{range $k, $v := .Env}}env {{$k}}={{$v|printf "%q"}}
 {{end}}
 {{range $k, $v := .Limit}}limit {{$k}} {{$v}} {{$v}}
+{{end}}{{if .CPUShares}}cgroup cpu $UPSTART_CGROUP cpu.shares {{.CPUShares}}
+{{end}}{{if .MemoryLimitMB}}cgroup memory $UPSTART_CGROUP memory.limit_in_bytes {{.MemoryLimitMB}}M
+{{end}}{{if .User}}setuid {{.User}}
+setgid {{.User}}
 {{end}}
 script
 {{if .ExtraScript}}{{.ExtraScript}}{{end}}
-{{if .Logfile}}
+{{if and .Logfile (not .User)}}
   # Ensure log files are properly protected
   touch {{.Logfile}}
   chown syslog:syslog {{.Logfile}}
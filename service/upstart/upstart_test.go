@@ -338,6 +338,38 @@ end script
 `)
 }
 
+func (s *UpstartSuite) TestInstallResourceLimits(c *gc.C) {
+	conf := s.dummyConf(c)
+	conf.CPUShares = 512
+	conf.MemoryLimitMB = 2048
+	s.assertInstall(c, conf, `
+cgroup cpu $UPSTART_CGROUP cpu.shares 512
+cgroup memory $UPSTART_CGROUP memory.limit_in_bytes 2048M
+
+script
+
+
+  exec /path/to/some-command x y z
+end script
+`)
+}
+
+func (s *UpstartSuite) TestInstallUser(c *gc.C) {
+	conf := s.dummyConf(c)
+	conf.User = "juju-unit-foo-0"
+	conf.Logfile = "/some/log"
+	s.assertInstall(c, conf, `
+setuid juju-unit-foo-0
+setgid juju-unit-foo-0
+
+script
+
+
+  exec /path/to/some-command x y z >> /some/log 2>&1
+end script
+`)
+}
+
 func (s *UpstartSuite) TestInstallAlreadyRunning(c *gc.C) {
 	pathTo := func(name string) string {
 		return filepath.Join(s.testPath, name)
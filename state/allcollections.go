@@ -299,8 +299,9 @@ func allCollections() collectionSchema {
 				Key: []string{"model-uuid", "machineid"},
 			}},
 		},
-		rebootC:      {},
-		sshHostKeysC: {},
+		rebootC:             {},
+		sshHostKeysC:        {},
+		upgradeSeriesLocksC: {},
 
 		// This collection contains information from removed machines
 		// that needs to be cleaned up in the provider.
@@ -506,6 +507,7 @@ const (
 	leasesC                  = "leases"
 	machinesC                = "machines"
 	machineRemovalsC         = "machineremovals"
+	upgradeSeriesLocksC      = "machineUpgradeSeriesLocks"
 	meterStatusC             = "meterStatus"
 	metricsC                 = "metrics"
 	metricsManagerC          = "metricsmanager"
@@ -56,6 +56,10 @@ type applicationDoc struct {
 	TxnRevno             int64      `bson:"txn-revno"`
 	MetricCredentials    []byte     `bson:"metric-credentials"`
 	PasswordHash         string     `bson:"passwordhash"`
+
+	// Placement is the placement directive used to
+	// place the pods of CAAS applications.
+	Placement string `bson:"placement,omitempty"`
 }
 
 func newApplication(st *State, doc *applicationDoc) *Application {
@@ -135,6 +139,29 @@ func (a *Application) Series() string {
 	return a.doc.Series
 }
 
+// Placement returns the placement directive for the application's
+// units. This is only used for applications in CAAS models.
+func (a *Application) Placement() string {
+	return a.doc.Placement
+}
+
+// SetPlacement sets the placement directive for the application's
+// units, replacing any given when the application was deployed.
+// This is only used for applications in CAAS models.
+func (a *Application) SetPlacement(placement string) error {
+	ops := []txn.Op{{
+		C:      applicationsC,
+		Id:     a.doc.DocID,
+		Assert: isAliveDoc,
+		Update: bson.D{{"$set", bson.D{{"placement", placement}}}},
+	}}
+	if err := a.st.db().RunTransaction(ops); err != nil {
+		return errors.Errorf("cannot set placement for application %q to %q: %v", a, placement, onAbort(err, errNotAlive))
+	}
+	a.doc.Placement = placement
+	return nil
+}
+
 // Life returns whether the application is Alive, Dying or Dead.
 func (a *Application) Life() Life {
 	return a.doc.Life
@@ -177,6 +204,14 @@ type DestroyApplicationOperation struct {
 	// are removed. If this is false, then the operation will
 	// fail if there are any offers remaining.
 	RemoveOffers bool
+
+	// Force controls whether or not the application is removed
+	// without waiting for its unit agents. If this is true, the
+	// units are removed without running their remaining hooks,
+	// and the units of other applications are removed from the
+	// application's relations, even if the application is
+	// already Dying.
+	Force bool
 }
 
 // Build is part of the ModelOperation interface.
@@ -188,7 +223,7 @@ func (op *DestroyApplicationOperation) Build(attempt int) ([]txn.Op, error) {
 			return nil, err
 		}
 	}
-	ops, err := op.app.destroyOps(op.DestroyStorage, op.RemoveOffers)
+	ops, err := op.app.destroyOps(op.DestroyStorage, op.RemoveOffers, op.Force)
 	switch err {
 	case errRefresh:
 		return nil, jujutxn.ErrTransientFailure
@@ -208,9 +243,22 @@ func (op *DestroyApplicationOperation) Done(err error) error {
 // destroyOps returns the operations required to destroy the application. If it
 // returns errRefresh, the application should be refreshed and the destruction
 // operations recalculated.
-func (a *Application) destroyOps(destroyStorage, removeOffers bool) ([]txn.Op, error) {
+func (a *Application) destroyOps(destroyStorage, removeOffers, force bool) ([]txn.Op, error) {
 	if a.doc.Life == Dying {
-		return nil, errAlreadyDying
+		if !force {
+			return nil, errAlreadyDying
+		}
+		// The application may be stuck waiting for its units or
+		// relations, so force their removal regardless.
+		return []txn.Op{{
+			C:      applicationsC,
+			Id:     a.doc.DocID,
+			Assert: bson.D{{"life", Dying}},
+		}, newCleanupOp(
+			cleanupForceDestroyedApplication,
+			a.doc.Name,
+			destroyStorage,
+		)}, nil
 	}
 	rels, err := a.Relations()
 	if err != nil {
@@ -284,14 +332,25 @@ func (a *Application) destroyOps(destroyStorage, removeOffers bool) ([]txn.Op, e
 	// about is that *some* unit is, or is not, keeping the application from
 	// being removed: the difference between 1 unit and 1000 is irrelevant.
 	if a.doc.UnitCount > 0 {
-		cleanupOp := newCleanupOp(
-			cleanupUnitsForDyingApplication,
-			a.doc.Name,
-			destroyStorage,
-		)
+		kind := cleanupUnitsForDyingApplication
+		if force {
+			kind = cleanupForceDestroyedApplication
+		}
+		cleanupOp := newCleanupOp(kind, a.doc.Name, destroyStorage)
 		ops = append(ops, cleanupOp)
 		notLastRefs = append(notLastRefs, bson.D{{"unitcount", bson.D{{"$gt", 0}}}}...)
 	} else {
+		if force {
+			// The application's remaining relations may
+			// still have units of other applications in
+			// scope, which must be removed.
+			cleanupOp := newCleanupOp(
+				cleanupForceDestroyedApplication,
+				a.doc.Name,
+				destroyStorage,
+			)
+			ops = append(ops, cleanupOp)
+		}
 		notLastRefs = append(notLastRefs, bson.D{{"unitcount", 0}}...)
 	}
 	update := bson.D{{"$set", bson.D{{"life", Dying}}}}
@@ -1255,6 +1314,7 @@ func (a *Application) addUnitOps(
 		providerId:    args.ProviderId,
 		address:       args.Address,
 		ports:         args.Ports,
+		filesystems:   args.Filesystems,
 	})
 	if err != nil {
 		return names, ops, err
@@ -1272,9 +1332,10 @@ type applicationAddUnitOpsArgs struct {
 	attachStorage []names.StorageTag
 
 	// These attributes are relevant to CAAS models.
-	providerId string
-	address    string
-	ports      []string
+	providerId  string
+	address     string
+	ports       []string
+	filesystems []ContainerFilesystem
 }
 
 // addApplicationUnitOps is just like addUnitOps but explicitly takes a
@@ -1325,10 +1386,11 @@ func (a *Application) addUnitOpsWithCons(args applicationAddUnitOpsArgs) (string
 		Principal:              args.principalName,
 		StorageAttachmentCount: numStorageAttachments,
 	}
-	if args.address != "" || args.ports != nil {
+	if args.address != "" || args.ports != nil || args.filesystems != nil {
 		udoc.ContainerInfo = ContainerInfo{
-			Address: args.address,
-			Ports:   args.ports,
+			Address:     args.address,
+			Ports:       args.ports,
+			Filesystems: args.filesystems,
 		}
 	}
 	now := a.st.clock().Now()
@@ -1570,6 +1632,9 @@ type AddUnitParams struct {
 
 	// Ports are the open ports on the container.
 	Ports []string
+
+	// Filesystems are the filesystems mounted into the container.
+	Filesystems []ContainerFilesystem
 }
 
 // AddUnit adds a new principal unit to the application.
@@ -2260,10 +2325,11 @@ func (a *Application) PasswordValid(password string) bool {
 // UnitUpdateProperties holds information used to update
 // the state model for the unit.
 type UnitUpdateProperties struct {
-	ProviderId string
-	Address    string
-	Ports      []string
-	Status     *status.StatusInfo
+	ProviderId  string
+	Address     string
+	Ports       []string
+	Filesystems []ContainerFilesystem
+	Status      *status.StatusInfo
 }
 
 // UpdateUnits applies the given application unit update operations.
@@ -2346,9 +2412,10 @@ func (op *AddUnitOperation) Build(attempt int) ([]txn.Op, error) {
 	var ops []txn.Op
 
 	addUnitArgs := AddUnitParams{
-		ProviderId: op.props.ProviderId,
-		Address:    op.props.Address,
-		Ports:      op.props.Ports,
+		ProviderId:  op.props.ProviderId,
+		Address:     op.props.Address,
+		Ports:       op.props.Ports,
+		Filesystems: op.props.Filesystems,
 	}
 	name, addOps, err := op.application.addUnitOps("", addUnitArgs, nil)
 	if err != nil {
@@ -3190,6 +3190,48 @@ func (s *ApplicationSuite) TestWatchCharmConfig(c *gc.C) {
 	wc.AssertNoChange()
 }
 
+func (s *ApplicationSuite) TestWatchApplicationConfig(c *gc.C) {
+	w := s.mysql.WatchApplicationConfig()
+	defer testing.AssertStop(c, w)
+
+	// Initial event.
+	wc := testing.NewNotifyWatcherC(c, s.State, w)
+	wc.AssertOneChange()
+
+	// Update config a couple of times, check a single event.
+	err := s.mysql.UpdateApplicationConfig(application.ConfigAttributes{"title": "foo"}, nil, sampleApplicationConfigSchema(), nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.mysql.UpdateApplicationConfig(application.ConfigAttributes{"title": "bar"}, nil, sampleApplicationConfigSchema(), nil)
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+
+	// Non-change is not reported.
+	err = s.mysql.UpdateApplicationConfig(application.ConfigAttributes{"title": "bar"}, nil, sampleApplicationConfigSchema(), nil)
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertNoChange()
+}
+
+func (s *ApplicationSuite) TestWatchConstraints(c *gc.C) {
+	w := s.mysql.WatchConstraints()
+	defer testing.AssertStop(c, w)
+
+	// Initial event.
+	wc := testing.NewNotifyWatcherC(c, s.State, w)
+	wc.AssertOneChange()
+
+	// Update constraints a couple of times, check a single event.
+	err := s.mysql.SetConstraints(constraints.MustParse("mem=4G"))
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.mysql.SetConstraints(constraints.MustParse("mem=8G"))
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+
+	// Other application changes are not reported.
+	err = s.mysql.SetExposed()
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertNoChange()
+}
+
 var updateApplicationConfigTests = []struct {
 	about   string
 	initial application.ConfigAttributes
@@ -3323,6 +3365,13 @@ func (s *ApplicationSuite) TestUpdateCAASUnits(c *gc.C) {
 		ProviderId: "new-unit-uuid",
 		Address:    "192.168.1.1",
 		Ports:      []string{"80"},
+		Filesystems: []state.ContainerFilesystem{{
+			StorageName:  "data",
+			FilesystemId: "new-claim-uuid",
+			Size:         1024,
+			MountPoint:   "/var/lib/data",
+			Status:       status.Pending,
+		}},
 		Status: &status.StatusInfo{
 			Status:  status.Running,
 			Message: "new running",
@@ -3332,6 +3381,16 @@ func (s *ApplicationSuite) TestUpdateCAASUnits(c *gc.C) {
 		ProviderId: "unit-uuid",
 		Address:    "192.168.1.2",
 		Ports:      []string{"443"},
+		Filesystems: []state.ContainerFilesystem{{
+			StorageName:  "data",
+			FilesystemId: "claim-uuid",
+			Size:         1024,
+			MountPoint:   "/var/lib/data",
+			Status:       status.Attached,
+			VolumeId:     "pv-1",
+			VolumeSize:   1024,
+			Persistent:   true,
+		}},
 		Status: &status.StatusInfo{
 			Status:  status.Running,
 			Message: "existing running",
@@ -3357,6 +3416,16 @@ func (s *ApplicationSuite) TestUpdateCAASUnits(c *gc.C) {
 	c.Assert(u.ContainerInfo(), jc.DeepEquals, state.ContainerInfo{
 		Address: "192.168.1.2",
 		Ports:   []string{"443"},
+		Filesystems: []state.ContainerFilesystem{{
+			StorageName:  "data",
+			FilesystemId: "claim-uuid",
+			Size:         1024,
+			MountPoint:   "/var/lib/data",
+			Status:       status.Attached,
+			VolumeId:     "pv-1",
+			VolumeSize:   1024,
+			Persistent:   true,
+		}},
 	})
 	statusInfo, err := u.AgentStatus()
 	c.Assert(err, jc.ErrorIsNil)
@@ -3382,6 +3451,13 @@ func (s *ApplicationSuite) TestUpdateCAASUnits(c *gc.C) {
 	c.Assert(u.ContainerInfo(), jc.DeepEquals, state.ContainerInfo{
 		Address: "192.168.1.1",
 		Ports:   []string{"80"},
+		Filesystems: []state.ContainerFilesystem{{
+			StorageName:  "data",
+			FilesystemId: "new-claim-uuid",
+			Size:         1024,
+			MountPoint:   "/var/lib/data",
+			Status:       status.Pending,
+		}},
 	})
 	statusInfo, err = u.AgentStatus()
 	c.Assert(err, jc.ErrorIsNil)
@@ -87,6 +87,10 @@ type Backups interface {
 	// the provided metadata.
 	Create(meta *Metadata, paths *Paths, dbInfo *DBInfo) error
 
+	// Stream creates a new juju backup archive and writes it to out as
+	// it is built, without storing it. It updates the provided metadata.
+	Stream(meta *Metadata, paths *Paths, dbInfo *DBInfo, out io.Writer) error
+
 	// Add stores the backup archive and returns its new ID.
 	Add(archive io.Reader, meta *Metadata) (string, error)
 
@@ -120,6 +124,36 @@ func NewBackups(stor filestorage.FileStorage) Backups {
 // Create creates and stores a new juju backup archive and updates the
 // provided metadata.
 func (b *backups) Create(meta *Metadata, paths *Paths, dbInfo *DBInfo) error {
+	result, err := b.create(meta, paths, dbInfo, nil)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer result.archiveFile.Close()
+
+	// Store the archive.
+	err = storeArchive(b.storage, meta, result.archiveFile)
+	if err != nil {
+		return errors.Annotate(err, "while storing backup archive")
+	}
+
+	return nil
+}
+
+// Stream creates a new juju backup archive and writes it to out as it
+// is built, and updates the provided metadata. The archive is neither
+// staged on disk nor stored, so the metadata is left without an ID.
+func (b *backups) Stream(meta *Metadata, paths *Paths, dbInfo *DBInfo, out io.Writer) error {
+	if err := CheckStreamSupported(dbInfo); err != nil {
+		return errors.Trace(err)
+	}
+	_, err := b.create(meta, paths, dbInfo, out)
+	return errors.Trace(err)
+}
+
+// create builds a new juju backup archive and finalizes the provided
+// metadata. If out is nil the archive is returned in the result, and
+// otherwise it is written to out.
+func (b *backups) create(meta *Metadata, paths *Paths, dbInfo *DBInfo, out io.Writer) (*createResult, error) {
 	// TODO(fwereade): 2016-03-17 lp:1558657
 	meta.Started = time.Now().UTC()
 
@@ -130,38 +164,33 @@ func (b *backups) Create(meta *Metadata, paths *Paths, dbInfo *DBInfo) error {
 	// them in afterward.  Neither is particularly trivial.
 	metadataFile, err := meta.AsJSONBuffer()
 	if err != nil {
-		return errors.Annotate(err, "while preparing the metadata")
+		return nil, errors.Annotate(err, "while preparing the metadata")
 	}
 
 	// Create the archive.
 	filesToBackUp, err := getFilesToBackUp("", paths, meta.Origin.Machine)
 	if err != nil {
-		return errors.Annotate(err, "while listing files to back up")
+		return nil, errors.Annotate(err, "while listing files to back up")
 	}
 	dumper, err := getDBDumper(dbInfo)
 	if err != nil {
-		return errors.Annotate(err, "while preparing for DB dump")
+		return nil, errors.Annotate(err, "while preparing for DB dump")
 	}
-	args := createArgs{filesToBackUp, dumper, metadataFile}
+	args := createArgs{filesToBackUp, dumper, metadataFile, out}
 	result, err := runCreate(&args)
 	if err != nil {
-		return errors.Annotate(err, "while creating backup archive")
+		return nil, errors.Annotate(err, "while creating backup archive")
 	}
-	defer result.archiveFile.Close()
 
 	// Finalize the metadata.
 	err = finishMeta(meta, result)
 	if err != nil {
-		return errors.Annotate(err, "while updating metadata")
-	}
-
-	// Store the archive.
-	err = storeArchive(b.storage, meta, result.archiveFile)
-	if err != nil {
-		return errors.Annotate(err, "while storing backup archive")
+		if result.archiveFile != nil {
+			result.archiveFile.Close()
+		}
+		return nil, errors.Annotate(err, "while updating metadata")
 	}
-
-	return nil
+	return result, nil
 }
 
 // Add stores the backup archive and returns its new ID.
@@ -134,6 +134,51 @@ func (s *backupsSuite) TestCreateOkay(c *gc.C) {
 	c.Check(string(data), gc.Equals, "<compressed tarball>")
 }
 
+func (s *backupsSuite) TestStreamOkay(c *gc.C) {
+	result := backups.NewTestCreateResult(nil, 10, "<checksum>")
+	received, testCreate := backups.NewTestCreate(result)
+	s.PatchValue(backups.RunCreate, testCreate)
+	s.PatchValue(backups.TestGetFilesToBackUp, func(root string, paths *backups.Paths, oldmachine string) ([]string, error) {
+		return []string{"<some file>"}, nil
+	})
+	s.PatchValue(backups.GetDBDumper, func(info *backups.DBInfo) (backups.DBDumper, error) {
+		return nil, nil
+	})
+
+	paths := backups.Paths{DataDir: "/var/lib/juju"}
+	mongo36 := mongo.Version{Major: 3, Minor: 6, StorageEngine: mongo.WiredTiger}
+	dbInfo := backups.DBInfo{"a", "b", "c", set.NewStrings("juju"), mongo36}
+	meta := backupstesting.NewMetadataStarted()
+	meta.Notes = "some notes"
+	var out bytes.Buffer
+	err := s.api.Stream(meta, &paths, &dbInfo, &out)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The archive is written out rather than stored.
+	c.Check(backups.ExposeCreateDestination(received), gc.Equals, &out)
+	c.Check(s.Storage.Calls, gc.HasLen, 0)
+
+	c.Check(meta.ID(), gc.Equals, "")
+	c.Check(meta.Stored(), gc.IsNil)
+	c.Check(meta.Size(), gc.Equals, int64(10))
+	c.Check(meta.Checksum(), gc.Equals, "<checksum>")
+	c.Check(meta.Notes, gc.Equals, "some notes")
+}
+
+func (s *backupsSuite) TestStreamUnsupportedMongo(c *gc.C) {
+	_, testCreate := backups.NewTestCreate(nil)
+	s.PatchValue(backups.RunCreate, testCreate)
+
+	paths := backups.Paths{DataDir: "/var/lib/juju"}
+	dbInfo := backups.DBInfo{"a", "b", "c", set.NewStrings("juju"), mongo.Mongo32wt}
+	meta := backupstesting.NewMetadataStarted()
+	var out bytes.Buffer
+	err := s.api.Stream(meta, &paths, &dbInfo, &out)
+	c.Check(err, gc.ErrorMatches, "streaming backups of mongo 3.2/wiredTiger not supported")
+	c.Check(err, jc.Satisfies, errors.IsNotSupported)
+	c.Check(out.Len(), gc.Equals, 0)
+}
+
 func (s *backupsSuite) TestCreateFailToListFiles(c *gc.C) {
 	s.PatchValue(backups.TestGetFilesToBackUp, func(root string, paths *backups.Paths, oldmachine string) ([]string, error) {
 		return nil, errors.New("failed!")
@@ -31,6 +31,9 @@ type createArgs struct {
 	filesToBackUp  []string
 	db             DBDumper
 	metadataReader io.Reader
+	// destination, if set, is where the archive is written as it is
+	// built, without staging anything in a backups workspace.
+	destination io.Writer
 }
 
 type createResult struct {
@@ -42,6 +45,11 @@ type createResult struct {
 // create builds a new backup archive file and returns it.  It also
 // updates the metadata with the file info.
 func create(args *createArgs) (_ *createResult, err error) {
+	if args.destination != nil {
+		result, err := streamArchive(args)
+		return result, errors.Trace(err)
+	}
+
 	// Prepare the backup builder.
 	builder, err := newBuilder(args.filesToBackUp, args.db)
 	if err != nil {
@@ -261,8 +269,16 @@ func (b *builder) buildFilesBundle() error {
 		return errors.New("missing bundleFile")
 	}
 
+	return errors.Trace(bundleFiles(b.filesToBackUp, b.bundleFile))
+}
+
+// bundleFiles writes a tar file of the given state-related files to out.
+func bundleFiles(filesToBackUp []string, out io.Writer) error {
+	if len(filesToBackUp) == 0 {
+		return errors.New("missing list of files to back up")
+	}
 	stripPrefix := string(os.PathSeparator)
-	_, err := tar.TarFiles(b.filesToBackUp, b.bundleFile, stripPrefix)
+	_, err := tar.TarFiles(filesToBackUp, out, stripPrefix)
 	if err != nil {
 		return errors.Annotate(err, "while bundling state-critical files")
 	}
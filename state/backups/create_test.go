@@ -4,8 +4,13 @@
 package backups_test
 
 import (
+	"bytes"
+	"compress/gzip"
+	"io"
 	"os"
+	"path/filepath"
 	"runtime"
+	"strings"
 
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
@@ -22,6 +27,7 @@ var _ = gc.Suite(&createSuite{}) // Register the suite.
 
 type TestDBDumper struct {
 	DumpDir string
+	Archive string
 }
 
 func (d *TestDBDumper) Dump(dumpDir string) error {
@@ -29,6 +35,11 @@ func (d *TestDBDumper) Dump(dumpDir string) error {
 	return nil
 }
 
+func (d *TestDBDumper) DumpArchive(out io.Writer) error {
+	_, err := io.WriteString(out, d.Archive)
+	return err
+}
+
 func (s *createSuite) TestLegacy(c *gc.C) {
 	if runtime.GOOS == "windows" {
 		c.Skip("bug 1403084: Currently does not work on windows, see comments inside backups.create function")
@@ -56,6 +67,66 @@ func (s *createSuite) TestLegacy(c *gc.C) {
 	s.checkArchive(c, file, expected)
 }
 
+func (s *createSuite) TestStream(c *gc.C) {
+	if runtime.GOOS == "windows" {
+		c.Skip("bug 1403084: Currently does not work on windows, see comments inside backups.create function")
+	}
+	meta := backupstesting.NewMetadataStarted()
+	metadataFile, err := meta.AsJSONBuffer()
+	c.Assert(err, jc.ErrorIsNil)
+	_, testFiles, expected := s.createTestFiles(c)
+
+	file, err := os.Create(filepath.Join(c.MkDir(), "streamed.tar.gz"))
+	c.Assert(err, jc.ErrorIsNil)
+	defer file.Close()
+
+	// The dump is bigger than a chunk, so it is split in two.
+	dumper := &TestDBDumper{
+		Archive: strings.Repeat("a", backups.StreamChunkSize) + "bc",
+	}
+	args := backups.NewTestStreamCreateArgs(testFiles, dumper, metadataFile, file)
+	result, err := backups.Create(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.NotNil)
+
+	// The archive was written to the destination, not returned.
+	archiveFile, size, checksum := backups.ExposeCreateResult(result)
+	c.Assert(archiveFile, gc.IsNil)
+
+	_, err = file.Seek(0, os.SEEK_SET)
+	c.Assert(err, jc.ErrorIsNil)
+	s.checkSize(c, file, size)
+	s.checkChecksum(c, file, checksum)
+	s.checkArchive(c, file, expected)
+
+	gzr, err := gzip.NewReader(file)
+	c.Assert(err, jc.ErrorIsNil)
+	s.checkTarContents(c, gzr, []tarContent{
+		{"juju-backup/dump/archive.000000", dumper.Archive[:backups.StreamChunkSize], nil},
+		{"juju-backup/dump/archive.000001", "bc", nil},
+	})
+}
+
+func (s *createSuite) TestStreamWithoutArchiveDumper(c *gc.C) {
+	meta := backupstesting.NewMetadataStarted()
+	metadataFile, err := meta.AsJSONBuffer()
+	c.Assert(err, jc.ErrorIsNil)
+	_, testFiles, _ := s.createTestFiles(c)
+
+	var out bytes.Buffer
+	args := backups.NewTestStreamCreateArgs(testFiles, &dirOnlyDBDumper{}, metadataFile, &out)
+	_, err = backups.Create(args)
+	c.Check(err, gc.ErrorMatches, "streaming the DB dump not supported")
+	c.Check(out.Len(), gc.Equals, 0)
+}
+
+// dirOnlyDBDumper is a DBDumper which can only dump to a directory.
+type dirOnlyDBDumper struct{}
+
+func (*dirOnlyDBDumper) Dump(string) error {
+	return nil
+}
+
 func (s *createSuite) TestMetadataFileMissing(c *gc.C) {
 	var testFiles []string
 	dumper := &TestDBDumper{}
@@ -5,6 +5,7 @@ package backups
 
 import (
 	"fmt"
+	"io"
 	"io/ioutil"
 	"os"
 	"os/exec"
@@ -28,7 +29,10 @@ import (
 // low-level details publicly.  Thus the backups implementation remains
 // oblivious to the underlying DB implementation.
 
-var runCommandFn = runCommand
+var (
+	runCommandFn   = runCommand
+	runCommandIOFn = runCommandIO
+)
 
 // DBInfo wraps all the DB-specific information backups needs to dump
 // the database. This includes a simplification of the information in
@@ -104,6 +108,27 @@ type DBDumper interface {
 	Dump(dumpDir string) error
 }
 
+// DBStreamDumper is a DBDumper which can also write the dump to a
+// stream, without staging it on disk.
+type DBStreamDumper interface {
+	DBDumper
+	// DumpArchive writes the dump to out as a single archive.
+	DumpArchive(out io.Writer) error
+}
+
+// minStreamMongoVersion is the oldest version of mongo whose dump
+// archives can be restored without also restoring the admin database.
+var minStreamMongoVersion = mongo.Version{Major: 3, Minor: 4}
+
+// CheckStreamSupported returns a NotSupported error if a backup of the
+// described DB cannot be streamed.
+func CheckStreamSupported(info *DBInfo) error {
+	if info.MongoVersion.NewerThan(minStreamMongoVersion) < 0 {
+		return errors.NotSupportedf("streaming backups of mongo %s", info.MongoVersion)
+	}
+	return nil
+}
+
 var getMongodumpPath = func() (string, error) {
 	return getMongoToolPath(dumpName, os.Stat, exec.LookPath)
 }
@@ -173,6 +198,33 @@ func (md *mongoDumper) dump(dumpDir string) error {
 	return nil
 }
 
+func (md *mongoDumper) archiveOptions() []string {
+	options := []string{
+		"--ssl",
+		"--authenticationDatabase", "admin",
+		"--host", md.Address,
+		"--username", md.Username,
+		"--password", md.Password,
+		"--archive",
+		"--oplog",
+	}
+	return options
+}
+
+// DumpArchive writes a dump of all the databases to out, as a mongo
+// archive. Unlike Dump, it cannot remove the ignored databases from
+// the dump, so they are left out when the archive is restored.
+func (md *mongoDumper) DumpArchive(out io.Writer) error {
+	if err := CheckStreamSupported(md.DBInfo); err != nil {
+		return errors.Trace(err)
+	}
+	options := md.archiveOptions()
+	if err := runCommandIOFn(nil, out, md.binPath, options...); err != nil {
+		return errors.Annotate(err, "error dumping databases")
+	}
+	return nil
+}
+
 // Dump dumps the juju state-related databases.  To do this we dump all
 // databases and then remove any ignored databases from the dump results.
 func (md *mongoDumper) Dump(baseDumpDir string) error {
@@ -259,6 +311,7 @@ type mongoRestorer struct {
 	tagUser         string
 	tagUserPassword string
 	runCommandFn    func(string, ...string) error
+	runCommandIOFn  func(io.Reader, io.Writer, string, ...string) error
 }
 type mongoRestorer32 struct {
 	mongoRestorer
@@ -351,6 +404,7 @@ func NewDBRestorer(args RestorerArgs) (DBRestorer, error) {
 		tagUser:         args.TagUser,
 		tagUserPassword: args.TagUserPassword,
 		runCommandFn:    args.RunCommandFn,
+		runCommandIOFn:  runCommandIOFn,
 	}
 	switch args.Version.Major {
 	case 2:
@@ -393,6 +447,44 @@ func (md *mongoRestorer32) options(dumpDir string) []string {
 	return options
 }
 
+func (md *mongoRestorer32) archiveOptions() []string {
+	// The admin and backups databases are in the archive, since
+	// they cannot be left out of a streamed dump, but they must not
+	// be restored; see mongoDumper.Dump.
+	options := []string{
+		"--ssl",
+		"--authenticationDatabase", "admin",
+		"--host", md.Addrs[0],
+		"--username", md.Username,
+		"--password", md.Password,
+		"--drop",
+		"--oplogReplay",
+		"--batchSize", "10",
+		"--archive",
+		"--nsExclude", "admin.*",
+		"--nsExclude", storageDBName + ".*",
+	}
+	return options
+}
+
+// restoreArchive restores the dump archive held, in order, in the
+// given chunk files of a streamed backup.
+func (md *mongoRestorer32) restoreArchive(chunks []string) error {
+	readers := make([]io.Reader, len(chunks))
+	for i, chunk := range chunks {
+		f, err := os.Open(chunk)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		defer f.Close()
+		readers[i] = f
+	}
+	options := md.archiveOptions()
+	logger.Infof("restoring database archive with params %v", options)
+	err := md.runCommandIOFn(io.MultiReader(readers...), nil, md.binPath, options...)
+	return errors.Trace(err)
+}
+
 // MongoDB represents a mgo.DB.
 type MongoDB interface {
 	UpsertUser(*mgo.User) error
@@ -502,10 +594,22 @@ func (md *mongoRestorer32) Restore(dumpDir string, dialInfo *mgo.DialInfo) error
 		return errors.Annotate(err, "setting special user permission in db")
 	}
 
-	options := md.options(dumpDir)
-	logger.Infof("restoring database with params %v", options)
-	if err := md.runCommandFn(md.binPath, options...); err != nil {
-		return errors.Annotate(err, "error restoring database")
+	// A streamed backup holds the dump as a mongo archive, split
+	// into chunks; see streamArchive.
+	chunks, err := filepath.Glob(filepath.Join(dumpDir, dbArchiveChunkPrefix+"*"))
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(chunks) > 0 {
+		if err := md.restoreArchive(chunks); err != nil {
+			return errors.Annotate(err, "error restoring database")
+		}
+	} else {
+		options := md.options(dumpDir)
+		logger.Infof("restoring database with params %v", options)
+		if err := md.runCommandFn(md.binPath, options...); err != nil {
+			return errors.Annotate(err, "error restoring database")
+		}
 	}
 	logger.Infof("updating user credentials")
 	if err := md.ensureTagUser(); err != nil {
@@ -4,6 +4,8 @@
 package backups_test
 
 import (
+	"bytes"
+	"io"
 	"os"
 	"path/filepath"
 
@@ -89,6 +91,48 @@ func (s *dumpSuite) TestDumpRanCommand(c *gc.C) {
 	c.Check(s.ranCommand, jc.IsTrue)
 }
 
+func (s *dumpSuite) TestDumpArchive(c *gc.C) {
+	s.patch(c)
+	var ranWithArgs []string
+	s.PatchValue(backups.RunCommandIO, func(stdin io.Reader, stdout io.Writer, cmd string, args ...string) error {
+		ranWithArgs = append([]string{cmd}, args...)
+		_, err := stdout.Write([]byte("<archive>"))
+		return err
+	})
+	s.dbInfo.MongoVersion = mongo.Version{Major: 3, Minor: 6, StorageEngine: mongo.WiredTiger}
+	dumper := s.prep(c)
+
+	var out bytes.Buffer
+	err := dumper.(backups.DBStreamDumper).DumpArchive(&out)
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(out.String(), gc.Equals, "<archive>")
+	c.Check(ranWithArgs, gc.DeepEquals, []string{
+		"bogusmongodump",
+		"--ssl",
+		"--authenticationDatabase", "admin",
+		"--host", "a",
+		"--username", "b",
+		"--password", "c",
+		"--archive",
+		"--oplog",
+	})
+}
+
+func (s *dumpSuite) TestDumpArchiveUnsupported(c *gc.C) {
+	s.patch(c)
+	s.PatchValue(backups.RunCommandIO, func(io.Reader, io.Writer, string, ...string) error {
+		c.Fatalf("unexpected dump")
+		return nil
+	})
+	s.dbInfo.MongoVersion = mongo.Mongo32wt
+	dumper := s.prep(c)
+
+	var out bytes.Buffer
+	err := dumper.(backups.DBStreamDumper).DumpArchive(&out)
+	c.Check(err, gc.ErrorMatches, "streaming backups of mongo 3.2/wiredTiger not supported")
+}
+
 func (s *dumpSuite) TestDumpStripped(c *gc.C) {
 	s.patch(c)
 	dumper := s.prep(c, "juju", "admin")
@@ -5,6 +5,9 @@ package backups_test
 
 import (
 	"fmt"
+	"io"
+	"io/ioutil"
+	"path/filepath"
 
 	"github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
@@ -159,6 +162,65 @@ func (s *mongoRestoreSuite) TestRestoreIdempotent(c *gc.C) {
 	s.assertRestore(c)
 }
 
+func (s *mongoRestoreSuite) TestRestoreArchive(c *gc.C) {
+	s.PatchValue(backups.GetMongorestorePath, func() (string, error) { return "/a/fake/mongorestore", nil })
+	var ranWithArgs []string
+	var restored []byte
+	s.PatchValue(backups.RunCommandIO, func(stdin io.Reader, stdout io.Writer, cmd string, args ...string) error {
+		ranWithArgs = append([]string{cmd}, args...)
+		var err error
+		restored, err = ioutil.ReadAll(stdin)
+		return err
+	})
+	fakeRunCommand := func(string, ...string) error {
+		c.Fatalf("unexpected directory restore")
+		return nil
+	}
+	dumpDir := c.MkDir()
+	for i, chunk := range []string{"abc", "def"} {
+		name := filepath.Join(dumpDir, fmt.Sprintf("archive.%06d", i))
+		err := ioutil.WriteFile(name, []byte(chunk), 0600)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	mongo36 := mongo.Version{Major: 3, Minor: 6, StorageEngine: mongo.WiredTiger}
+	args := backups.RestorerArgs{
+		DialInfo: &mgo.DialInfo{
+			Username: "fakeUsername",
+			Password: "fakePassword",
+			Addrs:    []string{"127.0.0.1"},
+		},
+		Version:         mongo36,
+		TagUser:         "machine-0",
+		TagUserPassword: "fakePassword",
+		GetDB:           func(string, backups.MongoSession) backups.MongoDB { return &mongoDb{} },
+		NewMongoSession: func(dialInfo *mgo.DialInfo) (backups.MongoSession, error) {
+			return &mongoSession{}, nil
+		},
+		RunCommandFn: fakeRunCommand,
+	}
+	s.PatchValue(backups.MongoInstalledVersion, func() mongo.Version { return mongo36 })
+	restorer, err := backups.NewDBRestorer(args)
+	c.Assert(err, jc.ErrorIsNil)
+	err = restorer.Restore(dumpDir, nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(string(restored), gc.Equals, "abcdef")
+	c.Check(ranWithArgs, gc.DeepEquals, []string{
+		"/a/fake/mongorestore",
+		"--ssl",
+		"--authenticationDatabase", "admin",
+		"--host", "127.0.0.1",
+		"--username", "fakeUsername",
+		"--password", "fakePassword",
+		"--drop",
+		"--oplogReplay",
+		"--batchSize", "10",
+		"--archive",
+		"--nsExclude", "admin.*",
+		"--nsExclude", "backups.*",
+	})
+}
+
 func (s *mongoRestoreSuite) TestRestoreFailsOnOlderMongo(c *gc.C) {
 	s.PatchValue(backups.GetMongorestorePath, func() (string, error) { return "/a/fake/mongorestore", nil })
 	args := backups.RestorerArgs{
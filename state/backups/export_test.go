@@ -28,10 +28,13 @@ var (
 	GetMongodumpPath      = &getMongodumpPath
 	GetMongorestorePath   = &getMongorestorePath
 	RunCommand            = &runCommandFn
+	RunCommandIO          = &runCommandIOFn
 	ReplaceableFolders    = &replaceableFolders
 	MongoInstalledVersion = &mongoInstalledVersion
 )
 
+const StreamChunkSize = streamChunkSize
+
 var _ filestorage.DocStorage = (*backupsDocStorage)(nil)
 var _ filestorage.RawFileStorage = (*backupBlobStorage)(nil)
 
@@ -103,6 +106,19 @@ func NewTestCreateArgs(filesToBackUp []string, db DBDumper, metar io.Reader) *cr
 	return &args
 }
 
+// NewTestStreamCreateArgs builds a new args value for create() calls
+// that write the archive to the given destination.
+func NewTestStreamCreateArgs(filesToBackUp []string, db DBDumper, metar io.Reader, dest io.Writer) *createArgs {
+	args := NewTestCreateArgs(filesToBackUp, db, metar)
+	args.destination = dest
+	return args
+}
+
+// ExposeCreateDestination extracts the destination in a create() args value.
+func ExposeCreateDestination(args *createArgs) io.Writer {
+	return args.destination
+}
+
 // ExposeCreateResult extracts the values in a create() args value.
 func ExposeCreateArgs(args *createArgs) ([]string, DBDumper) {
 	return args.filesToBackUp, args.db
@@ -0,0 +1,186 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backups
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha1"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils/hash"
+)
+
+const (
+	// dbArchiveChunkPrefix is the name prefix of the files in a
+	// streamed archive's dump directory which together hold the
+	// output of "mongodump --archive".
+	dbArchiveChunkPrefix = "archive."
+
+	// streamChunkSize is the most DB dump data held in memory, and
+	// written to a single file in the archive, while streaming.
+	streamChunkSize = 1 << 20
+)
+
+// streamArchive writes a backup archive to args.destination as it is
+// built. Nothing is staged on disk: the state-related files bundle and
+// the metadata are small enough to be built in memory, and the DB is
+// dumped as a mongo archive, which is split into a sequence of files
+// in the dump directory as it is written. The layout is otherwise the
+// same as that of an archive built by create.
+func streamArchive(args *createArgs) (*createResult, error) {
+	if args.metadataReader == nil {
+		return nil, errors.New("missing metadataReader")
+	}
+	var db DBStreamDumper
+	if args.db != nil {
+		var ok bool
+		if db, ok = args.db.(DBStreamDumper); !ok {
+			return nil, errors.NotSupportedf("streaming the DB dump")
+		}
+	}
+
+	metadata, err := ioutil.ReadAll(args.metadataReader)
+	if err != nil {
+		return nil, errors.Annotate(err, "while reading metadata")
+	}
+	logger.Infof("dumping juju state-related files")
+	var bundle bytes.Buffer
+	if err := bundleFiles(args.filesToBackUp, &bundle); err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	// As in buildArchiveAndChecksum, the checksum is of the gzipped
+	// archive, so it matches the checksum of the file the client saves.
+	counter := &writeCounter{Writer: args.destination}
+	hasher := hash.NewHashingWriter(counter, sha1.New())
+	tarball := gzip.NewWriter(hasher)
+	archive := tar.NewWriter(tarball)
+
+	paths := NewCanonicalArchivePaths()
+	now := time.Now()
+	for _, dir := range []string{paths.ContentDir, paths.DBDumpDir} {
+		if err := archive.WriteHeader(&tar.Header{
+			Name:     dir,
+			Mode:     0700,
+			ModTime:  now,
+			Typeflag: tar.TypeDir,
+		}); err != nil {
+			return nil, errors.Annotate(err, "while bundling final archive")
+		}
+	}
+	if err := writeTarFile(archive, paths.FilesBundle, bundle.Bytes(), now); err != nil {
+		return nil, errors.Annotate(err, "while bundling final archive")
+	}
+	if err := writeTarFile(archive, paths.MetadataFile, metadata, now); err != nil {
+		return nil, errors.Annotate(err, "while bundling final archive")
+	}
+
+	logger.Infof("dumping database")
+	if db == nil {
+		logger.Infof("nothing to do")
+	} else {
+		chunks := &chunkWriter{
+			archive: archive,
+			prefix:  path.Join(paths.DBDumpDir, dbArchiveChunkPrefix),
+			modTime: now,
+		}
+		if err := db.DumpArchive(chunks); err != nil {
+			return nil, errors.Annotate(err, "while dumping juju state database")
+		}
+		if err := chunks.flush(); err != nil {
+			return nil, errors.Annotate(err, "while dumping juju state database")
+		}
+	}
+
+	if err := archive.Close(); err != nil {
+		return nil, errors.Annotate(err, "while bundling final archive")
+	}
+	if err := tarball.Close(); err != nil {
+		return nil, errors.Annotate(err, "while bundling final archive")
+	}
+	result := createResult{
+		size:     counter.count,
+		checksum: hasher.Base64Sum(),
+	}
+	return &result, nil
+}
+
+func writeTarFile(archive *tar.Writer, name string, data []byte, modTime time.Time) error {
+	if err := archive.WriteHeader(&tar.Header{
+		Name:     name,
+		Mode:     0600,
+		Size:     int64(len(data)),
+		ModTime:  modTime,
+		Typeflag: tar.TypeReg,
+	}); err != nil {
+		return errors.Trace(err)
+	}
+	_, err := archive.Write(data)
+	return errors.Trace(err)
+}
+
+// chunkWriter is an io.Writer which adds what is written to a tar
+// archive as a sequence of files, each of at most streamChunkSize
+// bytes, so that the total size need not be known in advance.
+type chunkWriter struct {
+	archive *tar.Writer
+	prefix  string
+	modTime time.Time
+	buf     bytes.Buffer
+	count   int
+}
+
+// Write is part of the io.Writer interface.
+func (w *chunkWriter) Write(p []byte) (int, error) {
+	written := 0
+	for len(p) > 0 {
+		n := streamChunkSize - w.buf.Len()
+		if n > len(p) {
+			n = len(p)
+		}
+		w.buf.Write(p[:n])
+		written += n
+		p = p[n:]
+		if w.buf.Len() == streamChunkSize {
+			if err := w.flush(); err != nil {
+				return written, errors.Trace(err)
+			}
+		}
+	}
+	return written, nil
+}
+
+// flush adds any buffered data to the archive as the next file.
+func (w *chunkWriter) flush() error {
+	if w.buf.Len() == 0 {
+		return nil
+	}
+	name := fmt.Sprintf("%s%06d", w.prefix, w.count)
+	if err := writeTarFile(w.archive, name, w.buf.Bytes(), w.modTime); err != nil {
+		return errors.Trace(err)
+	}
+	w.buf.Reset()
+	w.count++
+	return nil
+}
+
+// writeCounter is an io.Writer which counts the bytes written to it.
+type writeCounter struct {
+	io.Writer
+	count int64
+}
+
+// Write is part of the io.Writer interface.
+func (w *writeCounter) Write(p []byte) (int, error) {
+	n, err := w.Writer.Write(p)
+	w.count += int64(n)
+	return n, err
+}
@@ -64,6 +64,27 @@ func (b *FakeBackups) Create(meta *backups.Metadata, paths *backups.Paths, dbInf
 	return b.Error
 }
 
+// Stream creates a new juju backup archive and writes it to out. The
+// archive written is the fake's Archive, if any.
+func (b *FakeBackups) Stream(meta *backups.Metadata, paths *backups.Paths, dbInfo *backups.DBInfo, out io.Writer) error {
+	b.Calls = append(b.Calls, "Stream")
+
+	b.PathsArg = paths
+	b.DBInfoArg = dbInfo
+	b.MetaArg = meta
+
+	if b.Meta != nil {
+		*meta = *b.Meta
+	}
+	if b.Archive != nil {
+		if _, err := io.Copy(out, b.Archive); err != nil {
+			return errors.Trace(err)
+		}
+	}
+
+	return b.Error
+}
+
 // Add stores the backup and returns its new ID.
 func (b *FakeBackups) Add(archive io.Reader, meta *backups.Metadata) (string, error) {
 	b.Calls = append(b.Calls, "Add")
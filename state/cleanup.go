@@ -28,6 +28,7 @@ const (
 	cleanupApplicationsForDyingModel     cleanupKind = "applications"
 	cleanupDyingMachine                  cleanupKind = "dyingMachine"
 	cleanupForceDestroyedMachine         cleanupKind = "machine"
+	cleanupForceDestroyedApplication     cleanupKind = "forceApplication"
 	cleanupAttachmentsForDyingStorage    cleanupKind = "storageAttachments"
 	cleanupAttachmentsForDyingVolume     cleanupKind = "volumeAttachments"
 	cleanupAttachmentsForDyingFilesystem cleanupKind = "filesystemAttachments"
@@ -133,6 +134,8 @@ func (st *State) Cleanup() (err error) {
 			err = st.cleanupDyingMachine(doc.Prefix)
 		case cleanupForceDestroyedMachine:
 			err = st.cleanupForceDestroyedMachine(doc.Prefix)
+		case cleanupForceDestroyedApplication:
+			err = st.cleanupForceDestroyedApplication(doc.Prefix, args)
 		case cleanupAttachmentsForDyingStorage:
 			err = st.cleanupAttachmentsForDyingStorage(doc.Prefix)
 		case cleanupAttachmentsForDyingVolume:
@@ -408,6 +411,94 @@ func (st *State) cleanupUnitsForDyingApplication(applicationname string, cleanup
 	return nil
 }
 
+// cleanupForceDestroyedApplication systematically destroys and removes
+// all units of the supplied application, without waiting for their agents
+// to run their remaining hooks, and then removes the units of other
+// applications from the scopes of the application's relations, so that the
+// application and its relations are removed. It's expected to be used in
+// response to remove-application --force.
+func (st *State) cleanupForceDestroyedApplication(applicationName string, cleanupArgs []bson.Raw) (err error) {
+	var destroyStorage bool
+	switch n := len(cleanupArgs); n {
+	case 0:
+	case 1:
+		if err := cleanupArgs[0].Unmarshal(&destroyStorage); err != nil {
+			return errors.Annotate(err, "unmarshalling cleanup args")
+		}
+	default:
+		return errors.Errorf("expected 0-1 arguments, got %d", n)
+	}
+
+	// The units are removed via individual transactions, which would
+	// disturb an iterator over them, so collect their names first.
+	units, closer := st.db().GetCollection(unitsC)
+	defer closer()
+	var unitDocs []struct {
+		Name string `bson:"name"`
+	}
+	sel := bson.D{{"application", applicationName}}
+	if err := units.Find(sel).Select(bson.D{{"name", 1}}).All(&unitDocs); err != nil {
+		return errors.Annotate(err, "reading unit documents")
+	}
+	for _, doc := range unitDocs {
+		unit, err := st.Unit(doc.Name)
+		if errors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return err
+		}
+		op := unit.DestroyOperation()
+		op.DestroyStorage = destroyStorage
+		if err := st.ApplyOperation(op); err != nil {
+			return errors.Trace(err)
+		}
+		if err := st.obliterateUnit(doc.Name); err != nil {
+			return err
+		}
+	}
+
+	relations, err := applicationRelations(st, applicationName)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for _, rel := range relations {
+		if err := st.forceLeaveRelationScopes(rel); err != nil {
+			return errors.Annotatef(err, "cannot remove units from relation %q", rel)
+		}
+	}
+	return nil
+}
+
+// forceLeaveRelationScopes removes all units from the scopes of the
+// supplied relation without waiting for their agents to leave, which
+// removes the relation if it is Dying.
+func (st *State) forceLeaveRelationScopes(rel *Relation) error {
+	relationScopes, closer := st.db().GetCollection(relationScopesC)
+	defer closer()
+	var docs []relationScopeDoc
+	sel := bson.D{{"key", bson.D{{"$regex", "^" + rel.globalScope() + "#"}}}}
+	if err := relationScopes.Find(sel).All(&docs); err != nil {
+		return errors.Annotate(err, "reading relation scope documents")
+	}
+	for _, doc := range docs {
+		unitName := doc.unitName()
+		var ru *RelationUnit
+		unit, err := st.Unit(unitName)
+		if errors.IsNotFound(err) {
+			ru, err = rel.RemoteUnit(unitName)
+		} else if err == nil {
+			ru, err = rel.Unit(unit)
+		}
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if err := ru.LeaveScope(); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
 // cleanupCharm is speculative: it can abort without error for many
 // reasons, because it's triggered somewhat overenthusiastically for
 // simplicity's sake.
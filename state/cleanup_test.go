@@ -443,6 +443,82 @@ func (s *CleanupSuite) TestCleanupForceDestroyedMachineWithContainer(c *gc.C) {
 	assertLife(c, machine, state.Dead)
 }
 
+func (s *CleanupSuite) TestCleanupForceDestroyedApplication(c *gc.C) {
+	mysql, mysqlUnit, wordpressUnit, rel := s.addRelatedUnitsInScope(c)
+
+	op := mysql.DestroyOperation()
+	op.Force = true
+	err := s.State.ApplyOperation(op)
+	c.Assert(err, jc.ErrorIsNil)
+	assertLife(c, mysql, state.Dying)
+	s.assertNeedsCleanup(c)
+
+	// Clean up, and check that the unit has been removed without
+	// waiting for its agent, along with the relation and the
+	// application...
+	s.assertCleanupRuns(c)
+	assertRemoved(c, mysqlUnit)
+	err = rel.Refresh()
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	err = mysql.Refresh()
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	// ...but that the related unit remains.
+	assertLife(c, wordpressUnit, state.Alive)
+}
+
+func (s *CleanupSuite) TestCleanupForceDestroyedDyingApplication(c *gc.C) {
+	mysql, mysqlUnit, _, rel := s.addRelatedUnitsInScope(c)
+
+	// Destroy the application without force, and check
+	// that it is stuck waiting for its unit's agent.
+	err := mysql.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertCleanupRuns(c)
+	assertLife(c, mysqlUnit, state.Dying)
+	assertLife(c, mysql, state.Dying)
+
+	op := mysql.DestroyOperation()
+	op.Force = true
+	err = s.State.ApplyOperation(op)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertNeedsCleanup(c)
+
+	s.assertCleanupRuns(c)
+	assertRemoved(c, mysqlUnit)
+	err = rel.Refresh()
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	err = mysql.Refresh()
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+// addRelatedUnitsInScope adds related mysql and wordpress units,
+// both in the scope of the relation, whose agents must run for them
+// to be removed.
+func (s *CleanupSuite) addRelatedUnitsInScope(c *gc.C) (*state.Application, *state.Unit, *state.Unit, *state.Relation) {
+	mysql := s.AddTestingApplication(c, "mysql", s.AddTestingCharm(c, "mysql"))
+	mysqlUnit, err := mysql.AddUnit(state.AddUnitParams{})
+	c.Assert(err, jc.ErrorIsNil)
+	preventUnitDestroyRemove(c, mysqlUnit)
+	wordpress := s.AddTestingApplication(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	wordpressUnit, err := wordpress.AddUnit(state.AddUnitParams{})
+	c.Assert(err, jc.ErrorIsNil)
+	preventUnitDestroyRemove(c, wordpressUnit)
+
+	eps, err := s.State.InferEndpoints("wordpress", "mysql")
+	c.Assert(err, jc.ErrorIsNil)
+	rel, err := s.State.AddRelation(eps...)
+	c.Assert(err, jc.ErrorIsNil)
+	for _, unit := range []*state.Unit{mysqlUnit, wordpressUnit} {
+		ru, err := rel.Unit(unit)
+		c.Assert(err, jc.ErrorIsNil)
+		err = ru.EnterScope(nil)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	s.assertDoesNotNeedCleanup(c)
+	return mysql, mysqlUnit, wordpressUnit, rel
+}
+
 func (s *CleanupSuite) TestCleanupDyingUnit(c *gc.C) {
 	// Create active unit, in a relation.
 	prr := newProReqRelation(c, &s.ConnSuite, charm.ScopeGlobal)
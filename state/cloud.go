@@ -8,6 +8,7 @@ import (
 	"github.com/juju/utils/set"
 	"gopkg.in/juju/names.v2"
 	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
 	"gopkg.in/mgo.v2/txn"
 
 	"github.com/juju/juju/cloud"
@@ -34,9 +35,8 @@ type cloudRegionSubdoc struct {
 	StorageEndpoint  string `bson:"storage-endpoint,omitempty"`
 }
 
-// createCloudOp returns a list of txn.Ops that will initialize
-// the cloud definition for the controller.
-func createCloudOp(cloud cloud.Cloud) txn.Op {
+// newCloudDoc returns the document recording the given cloud.
+func newCloudDoc(cloud cloud.Cloud) *cloudDoc {
 	authTypes := make([]string, len(cloud.AuthTypes))
 	for i, authType := range cloud.AuthTypes {
 		authTypes[i] = string(authType)
@@ -49,20 +49,45 @@ func createCloudOp(cloud cloud.Cloud) txn.Op {
 			region.StorageEndpoint,
 		}
 	}
+	return &cloudDoc{
+		Name:             cloud.Name,
+		Type:             cloud.Type,
+		AuthTypes:        authTypes,
+		Endpoint:         cloud.Endpoint,
+		IdentityEndpoint: cloud.IdentityEndpoint,
+		StorageEndpoint:  cloud.StorageEndpoint,
+		Regions:          regions,
+		CACertificates:   cloud.CACertificates,
+	}
+}
+
+// createCloudOp returns a list of txn.Ops that will initialize
+// the cloud definition for the controller.
+func createCloudOp(cloud cloud.Cloud) txn.Op {
 	return txn.Op{
 		C:      cloudsC,
 		Id:     cloud.Name,
 		Assert: txn.DocMissing,
-		Insert: &cloudDoc{
-			Name:             cloud.Name,
-			Type:             cloud.Type,
-			AuthTypes:        authTypes,
-			Endpoint:         cloud.Endpoint,
-			IdentityEndpoint: cloud.IdentityEndpoint,
-			StorageEndpoint:  cloud.StorageEndpoint,
-			Regions:          regions,
-			CACertificates:   cloud.CACertificates,
-		},
+		Insert: newCloudDoc(cloud),
+	}
+}
+
+// updateCloudOp returns a txn.Op that will replace the definition
+// of an existing cloud, provided that its type is unchanged.
+func updateCloudOp(cloud cloud.Cloud) txn.Op {
+	doc := newCloudDoc(cloud)
+	return txn.Op{
+		C:      cloudsC,
+		Id:     cloud.Name,
+		Assert: bson.D{{"type", cloud.Type}},
+		Update: bson.D{{"$set", bson.D{
+			{"auth-types", doc.AuthTypes},
+			{"endpoint", doc.Endpoint},
+			{"identity-endpoint", doc.IdentityEndpoint},
+			{"storage-endpoint", doc.StorageEndpoint},
+			{"regions", doc.Regions},
+			{"ca-certificates", doc.CACertificates},
+		}}},
 	}
 }
 
@@ -147,6 +172,69 @@ func (st *State) AddCloud(c cloud.Cloud) error {
 	return nil
 }
 
+// UpdateCloud replaces the definition of an existing cloud with the
+// given one, so that new regions and endpoints become usable. The type
+// of the cloud cannot be changed, and regions in which models are
+// deployed cannot be removed.
+func (st *State) UpdateCloud(c cloud.Cloud) error {
+	if err := validateCloud(c); err != nil {
+		return errors.Annotate(err, "invalid cloud")
+	}
+	buildTxn := func(int) ([]txn.Op, error) {
+		existing, err := st.Cloud(c.Name)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if existing.Type != c.Type {
+			return nil, errors.NotValidf(
+				"changing type of cloud %q from %q to %q",
+				c.Name, existing.Type, c.Type,
+			)
+		}
+		if err := st.checkRemovedRegionsUnused(existing, c); err != nil {
+			return nil, errors.Trace(err)
+		}
+		return []txn.Op{updateCloudOp(c)}, nil
+	}
+	if err := st.db().Run(buildTxn); err != nil {
+		return errors.Annotatef(err, "updating cloud %q", c.Name)
+	}
+	return nil
+}
+
+// checkRemovedRegionsUnused returns an error if any region of the
+// existing cloud that is missing from the updated one hosts a model.
+func (st *State) checkRemovedRegionsUnused(existing, updated cloud.Cloud) error {
+	regions := set.NewStrings()
+	for _, region := range updated.Regions {
+		regions.Add(region.Name)
+	}
+	var removed []string
+	for _, region := range existing.Regions {
+		if !regions.Contains(region.Name) {
+			removed = append(removed, region.Name)
+		}
+	}
+	if len(removed) == 0 {
+		return nil
+	}
+	models, closer := st.db().GetCollection(modelsC)
+	defer closer()
+	var doc struct {
+		CloudRegion string `bson:"cloud-region"`
+	}
+	err := models.Find(bson.D{
+		{"cloud", existing.Name},
+		{"cloud-region", bson.D{{"$in", removed}}},
+	}).Select(bson.D{{"cloud-region", 1}}).One(&doc)
+	if err == mgo.ErrNotFound {
+		return nil
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Errorf("cannot remove region %q, it is used by models", doc.CloudRegion)
+}
+
 // validateCloud checks that the supplied cloud is valid.
 func validateCloud(cloud cloud.Cloud) error {
 	if cloud.Name == "" {
@@ -168,3 +256,10 @@ func validateCloud(cloud cloud.Cloud) error {
 func regionSettingsGlobalKey(cloud, region string) string {
 	return cloud + "#" + region
 }
+
+// cloudSettingsGlobalKey returns the key for settings which apply
+// to every region of the cloud. Region names are never empty, so
+// this does not collide with any region's key.
+func cloudSettingsGlobalKey(cloud string) string {
+	return regionSettingsGlobalKey(cloud, "")
+}
@@ -106,3 +106,69 @@ func (s *CloudSuite) TestAddCloudNoAuthTypes(c *gc.C) {
 	})
 	c.Assert(err, gc.ErrorMatches, `invalid cloud: empty auth-types not valid`)
 }
+
+func (s *CloudSuite) TestUpdateCloud(c *gc.C) {
+	err := s.State.AddCloud(lowCloud)
+	c.Assert(err, jc.ErrorIsNil)
+
+	updated := lowCloud
+	updated.Endpoint = "new-global-endpoint"
+	updated.AuthTypes = cloud.AuthTypes{cloud.AccessKeyAuthType}
+	updated.Regions = []cloud.Region{lowCloud.Regions[0], {
+		Name:     "region3",
+		Endpoint: "region3-endpoint",
+	}}
+	err = s.State.UpdateCloud(updated)
+	c.Assert(err, jc.ErrorIsNil)
+
+	cld, err := s.State.Cloud("stratus")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cld, jc.DeepEquals, updated)
+}
+
+func (s *CloudSuite) TestUpdateCloudNotFound(c *gc.C) {
+	err := s.State.UpdateCloud(lowCloud)
+	c.Assert(err, gc.ErrorMatches, `updating cloud "stratus": cloud "stratus" not found`)
+	c.Assert(errors.Cause(err), jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *CloudSuite) TestUpdateCloudChangeType(c *gc.C) {
+	err := s.State.AddCloud(lowCloud)
+	c.Assert(err, jc.ErrorIsNil)
+
+	updated := lowCloud
+	updated.Type = "high"
+	err = s.State.UpdateCloud(updated)
+	c.Assert(err, gc.ErrorMatches, `updating cloud "stratus": changing type of cloud "stratus" from "low" to "high" not valid`)
+}
+
+func (s *CloudSuite) TestUpdateCloudInvalid(c *gc.C) {
+	err := s.State.UpdateCloud(cloud.Cloud{
+		Name: "stratus",
+		Type: "low",
+	})
+	c.Assert(err, gc.ErrorMatches, `invalid cloud: empty auth-types not valid`)
+}
+
+func (s *CloudSuite) TestUpdateCloudRemoveUsedRegion(c *gc.C) {
+	dummyCloud, err := s.State.Cloud("dummy")
+	c.Assert(err, jc.ErrorIsNil)
+	model, err := s.State.Model()
+	c.Assert(err, jc.ErrorIsNil)
+	usedRegion := model.CloudRegion()
+	c.Assert(usedRegion, gc.Not(gc.Equals), "")
+
+	updated := dummyCloud
+	updated.Regions = nil
+	for _, region := range dummyCloud.Regions {
+		if region.Name != usedRegion {
+			updated.Regions = append(updated.Regions, region)
+		}
+	}
+	err = s.State.UpdateCloud(updated)
+	c.Assert(err, gc.ErrorMatches, `updating cloud "dummy": cannot remove region "`+usedRegion+`", it is used by models`)
+
+	cld, err := s.State.Cloud("dummy")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cld, jc.DeepEquals, dummyCloud)
+}
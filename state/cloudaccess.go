@@ -0,0 +1,110 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"fmt"
+
+	"github.com/juju/errors"
+	"gopkg.in/juju/names.v2"
+	"gopkg.in/mgo.v2/txn"
+
+	"github.com/juju/juju/permission"
+)
+
+// cloudGlobalKey is the key for a cloud.
+const cloudGlobalKey = "cloud"
+
+// cloudKey will return the key for a given cloud using the
+// cloud name and the cloudGlobalKey.
+func cloudKey(cloudName string) string {
+	return fmt.Sprintf("%s#%s", cloudGlobalKey, cloudName)
+}
+
+// GetCloudAccess gets the access permission for the specified user on a cloud.
+func (st *State) GetCloudAccess(cloudName string, user names.UserTag) (permission.Access, error) {
+	perm, err := st.userPermission(cloudKey(cloudName), userGlobalKey(userAccessID(user)))
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	return perm.access(), nil
+}
+
+// GetCloudUsers gets the access permissions on a cloud.
+func (st *State) GetCloudUsers(cloudName string) (map[string]permission.Access, error) {
+	perms, err := st.usersPermissions(cloudKey(cloudName))
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	result := make(map[string]permission.Access)
+	for _, p := range perms {
+		result[userIDFromGlobalKey(p.doc.SubjectGlobalKey)] = p.access()
+	}
+	return result, nil
+}
+
+// CreateCloudAccess creates a new access permission for a user on a cloud.
+func (st *State) CreateCloudAccess(cloudName string, user names.UserTag, access permission.Access) error {
+	if err := permission.ValidateCloudAccess(access); err != nil {
+		return errors.Trace(err)
+	}
+
+	// Local users must exist.
+	if user.IsLocal() {
+		_, err := st.User(user)
+		if err != nil {
+			if errors.IsNotFound(err) {
+				return errors.Annotatef(err, "user %q does not exist locally", user.Name())
+			}
+			return errors.Trace(err)
+		}
+	}
+
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			if _, err := st.Cloud(cloudName); err != nil {
+				return nil, errors.Trace(err)
+			}
+			if _, err := st.GetCloudAccess(cloudName, user); err == nil {
+				return nil, errors.AlreadyExistsf("permission for user %q for cloud %q", user.Id(), cloudName)
+			}
+		}
+		return []txn.Op{{
+			C:      cloudsC,
+			Id:     cloudName,
+			Assert: txn.DocExists,
+		}, createPermissionOp(cloudKey(cloudName), userGlobalKey(userAccessID(user)), access)}, nil
+	}
+	err := st.db().Run(buildTxn)
+	return errors.Trace(err)
+}
+
+// UpdateCloudAccess changes the user's access permissions on a cloud.
+func (st *State) UpdateCloudAccess(cloudName string, user names.UserTag, access permission.Access) error {
+	if err := permission.ValidateCloudAccess(access); err != nil {
+		return errors.Trace(err)
+	}
+	buildTxn := func(int) ([]txn.Op, error) {
+		_, err := st.GetCloudAccess(cloudName, user)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return []txn.Op{updatePermissionOp(cloudKey(cloudName), userGlobalKey(userAccessID(user)), access)}, nil
+	}
+	err := st.db().Run(buildTxn)
+	return errors.Trace(err)
+}
+
+// RemoveCloudAccess removes the access permission for a user on a cloud.
+func (st *State) RemoveCloudAccess(cloudName string, user names.UserTag) error {
+	buildTxn := func(int) ([]txn.Op, error) {
+		_, err := st.GetCloudAccess(cloudName, user)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return []txn.Op{removePermissionOp(cloudKey(cloudName), userGlobalKey(userAccessID(user)))}, nil
+	}
+	err := st.db().Run(buildTxn)
+	return errors.Trace(err)
+}
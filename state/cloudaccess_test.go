@@ -0,0 +1,108 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/permission"
+	"github.com/juju/juju/testing/factory"
+)
+
+type CloudAccessSuite struct {
+	ConnSuite
+}
+
+var _ = gc.Suite(&CloudAccessSuite{})
+
+func (s *CloudAccessSuite) makeCloudUser(c *gc.C, access permission.Access) names.UserTag {
+	user := s.Factory.MakeUser(c, &factory.UserParams{
+		Name:   "validusername",
+		Access: permission.LoginAccess,
+	})
+
+	// Initially no access.
+	_, err := s.State.GetCloudAccess("dummy", user.UserTag())
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	err = s.State.CreateCloudAccess("dummy", user.UserTag(), access)
+	c.Assert(err, jc.ErrorIsNil)
+	return user.UserTag()
+}
+
+func (s *CloudAccessSuite) TestCreateCloudAccess(c *gc.C) {
+	user := s.makeCloudUser(c, permission.AddModelAccess)
+
+	access, err := s.State.GetCloudAccess("dummy", user)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(access, gc.Equals, permission.AddModelAccess)
+
+	access, err = s.State.UserPermission(user, names.NewCloudTag("dummy"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(access, gc.Equals, permission.AddModelAccess)
+}
+
+func (s *CloudAccessSuite) TestCreateCloudAccessAlreadyExists(c *gc.C) {
+	user := s.makeCloudUser(c, permission.AddModelAccess)
+	err := s.State.CreateCloudAccess("dummy", user, permission.AdminAccess)
+	c.Assert(err, jc.Satisfies, errors.IsAlreadyExists)
+}
+
+func (s *CloudAccessSuite) TestCreateCloudAccessInvalidAccess(c *gc.C) {
+	err := s.State.CreateCloudAccess("dummy", names.NewUserTag("validusername"), permission.SuperuserAccess)
+	c.Assert(err, gc.ErrorMatches, `"superuser" cloud access not valid`)
+}
+
+func (s *CloudAccessSuite) TestCreateCloudAccessNoUserFails(c *gc.C) {
+	err := s.State.CreateCloudAccess("dummy", names.NewUserTag("validusername"), permission.AddModelAccess)
+	c.Assert(err, gc.ErrorMatches, `user "validusername" does not exist locally: user "validusername" not found`)
+}
+
+func (s *CloudAccessSuite) TestCreateCloudAccessNoCloudFails(c *gc.C) {
+	user := s.Factory.MakeUser(c, &factory.UserParams{Name: "validusername"})
+	err := s.State.CreateCloudAccess("unknown", user.UserTag(), permission.AddModelAccess)
+	c.Assert(err, gc.ErrorMatches, `cloud "unknown" not found`)
+}
+
+func (s *CloudAccessSuite) TestGetCloudUsers(c *gc.C) {
+	s.makeCloudUser(c, permission.AdminAccess)
+	users, err := s.State.GetCloudUsers("dummy")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(users, jc.DeepEquals, map[string]permission.Access{
+		"validusername": permission.AdminAccess,
+	})
+}
+
+func (s *CloudAccessSuite) TestUpdateCloudAccess(c *gc.C) {
+	user := s.makeCloudUser(c, permission.AddModelAccess)
+	err := s.State.UpdateCloudAccess("dummy", user, permission.AdminAccess)
+	c.Assert(err, jc.ErrorIsNil)
+
+	access, err := s.State.GetCloudAccess("dummy", user)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(access, gc.Equals, permission.AdminAccess)
+}
+
+func (s *CloudAccessSuite) TestUpdateCloudAccessNoPermission(c *gc.C) {
+	user := s.Factory.MakeUser(c, &factory.UserParams{Name: "validusername"})
+	err := s.State.UpdateCloudAccess("dummy", user.UserTag(), permission.AdminAccess)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *CloudAccessSuite) TestRemoveCloudAccess(c *gc.C) {
+	user := s.makeCloudUser(c, permission.AddModelAccess)
+	err := s.State.RemoveCloudAccess("dummy", user)
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = s.State.GetCloudAccess("dummy", user)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *CloudAccessSuite) TestRemoveCloudAccessNoUser(c *gc.C) {
+	err := s.State.RemoveCloudAccess("dummy", names.NewUserTag("fred"))
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
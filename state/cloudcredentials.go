@@ -125,6 +125,76 @@ func (st *State) RemoveCloudCredential(tag names.CloudCredentialTag) error {
 	return nil
 }
 
+// CloudCredentialModels returns the UUIDs of the models that use
+// the cloud credential with the given tag.
+func (st *State) CloudCredentialModels(tag names.CloudCredentialTag) ([]string, error) {
+	coll, cleanup := st.db().GetCollection(modelsC)
+	defer cleanup()
+
+	var docs []struct {
+		UUID string `bson:"_id"`
+	}
+	err := coll.Find(bson.D{{"cloud-credential", tag.Id()}}).Select(bson.D{{"_id", 1}}).All(&docs)
+	if err != nil {
+		return nil, errors.Annotatef(err, "getting models using cloud credential %q", tag.Id())
+	}
+	uuids := make([]string, len(docs))
+	for i, doc := range docs {
+		uuids[i] = doc.UUID
+	}
+	return uuids, nil
+}
+
+// RotateCloudCredential switches all models using the cloud credential
+// oldTag over to the credential newTag, and removes the old credential,
+// in a single transaction. Both credentials must exist and be for the
+// same cloud.
+func (st *State) RotateCloudCredential(oldTag, newTag names.CloudCredentialTag) error {
+	if oldTag.Cloud() != newTag.Cloud() {
+		return errors.NotValidf(
+			"rotating credential %q for cloud %q to one for cloud %q",
+			oldTag.Id(), oldTag.Cloud().Id(), newTag.Cloud().Id(),
+		)
+	}
+	if oldTag == newTag {
+		return errors.NotValidf("rotating credential %q to itself", oldTag.Id())
+	}
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if _, err := st.CloudCredential(oldTag); err != nil {
+			return nil, errors.Trace(err)
+		}
+		newCredential, err := st.CloudCredential(newTag)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if newCredential.Revoked {
+			return nil, errors.NotValidf("revoked credential %q", newTag.Id())
+		}
+		modelUUIDs, err := st.CloudCredentialModels(oldTag)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		ops := []txn.Op{{
+			C:      cloudCredentialsC,
+			Id:     cloudCredentialDocID(newTag),
+			Assert: bson.D{{"revoked", false}},
+		}}
+		for _, uuid := range modelUUIDs {
+			ops = append(ops, txn.Op{
+				C:      modelsC,
+				Id:     uuid,
+				Assert: bson.D{{"cloud-credential", oldTag.Id()}},
+				Update: bson.D{{"$set", bson.D{{"cloud-credential", newTag.Id()}}}},
+			})
+		}
+		return append(ops, removeCloudCredentialOps(oldTag)...), nil
+	}
+	if err := st.db().Run(buildTxn); err != nil {
+		return errors.Annotate(err, "rotating cloud credential")
+	}
+	return nil
+}
+
 // createCloudCredentialOp returns a txn.Op that will create
 // a cloud credential.
 func createCloudCredentialOp(tag names.CloudCredentialTag, cred cloud.Credential) txn.Op {
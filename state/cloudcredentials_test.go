@@ -12,6 +12,7 @@ import (
 	"github.com/juju/juju/cloud"
 	"github.com/juju/juju/state"
 	statetesting "github.com/juju/juju/state/testing"
+	"github.com/juju/juju/testing/factory"
 )
 
 type CloudCredentialsSuite struct {
@@ -165,6 +166,94 @@ func (s *CloudCredentialsSuite) TestRemoveCredentials(c *gc.C) {
 	c.Assert(err, jc.Satisfies, errors.IsNotFound)
 }
 
+func (s *CloudCredentialsSuite) addModelWithCredential(c *gc.C, tag names.CloudCredentialTag) string {
+	err := s.State.UpdateCloudCredential(tag, cloud.NewCredential(cloud.EmptyAuthType, nil))
+	c.Assert(err, jc.ErrorIsNil)
+	st := s.Factory.MakeModel(c, &factory.ModelParams{
+		Owner:           tag.Owner(),
+		CloudCredential: tag,
+	})
+	defer st.Close()
+	return st.ModelUUID()
+}
+
+func (s *CloudCredentialsSuite) TestCloudCredentialModels(c *gc.C) {
+	s.Factory.MakeUser(c, &factory.UserParams{Name: "bob"})
+	tag := names.NewCloudCredentialTag("dummy/bob/default")
+	uuid := s.addModelWithCredential(c, tag)
+
+	uuids, err := s.State.CloudCredentialModels(tag)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(uuids, jc.DeepEquals, []string{uuid})
+
+	uuids, err = s.State.CloudCredentialModels(names.NewCloudCredentialTag("dummy/bob/other"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(uuids, gc.HasLen, 0)
+}
+
+func (s *CloudCredentialsSuite) TestRotateCloudCredential(c *gc.C) {
+	s.Factory.MakeUser(c, &factory.UserParams{Name: "bob"})
+	oldTag := names.NewCloudCredentialTag("dummy/bob/old")
+	newTag := names.NewCloudCredentialTag("dummy/bob/new")
+	uuid := s.addModelWithCredential(c, oldTag)
+	err := s.State.UpdateCloudCredential(newTag, cloud.NewCredential(cloud.EmptyAuthType, nil))
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.State.RotateCloudCredential(oldTag, newTag)
+	c.Assert(err, jc.ErrorIsNil)
+
+	m, release, err := s.StatePool.GetModel(uuid)
+	c.Assert(err, jc.ErrorIsNil)
+	defer release()
+	credTag, ok := m.CloudCredential()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(credTag, gc.Equals, newTag)
+
+	_, err = s.State.CloudCredential(oldTag)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *CloudCredentialsSuite) TestRotateCloudCredentialDifferentCloud(c *gc.C) {
+	err := s.State.RotateCloudCredential(
+		names.NewCloudCredentialTag("dummy/bob/old"),
+		names.NewCloudCredentialTag("stratus/bob/new"),
+	)
+	c.Assert(err, gc.ErrorMatches, `rotating credential "dummy/bob/old" for cloud "dummy" to one for cloud "stratus" not valid`)
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+}
+
+func (s *CloudCredentialsSuite) TestRotateCloudCredentialToItself(c *gc.C) {
+	tag := names.NewCloudCredentialTag("dummy/bob/old")
+	err := s.State.RotateCloudCredential(tag, tag)
+	c.Assert(err, gc.ErrorMatches, `rotating credential "dummy/bob/old" to itself not valid`)
+}
+
+func (s *CloudCredentialsSuite) TestRotateCloudCredentialNewNotFound(c *gc.C) {
+	s.Factory.MakeUser(c, &factory.UserParams{Name: "bob"})
+	oldTag := names.NewCloudCredentialTag("dummy/bob/old")
+	s.addModelWithCredential(c, oldTag)
+
+	err := s.State.RotateCloudCredential(oldTag, names.NewCloudCredentialTag("dummy/bob/new"))
+	c.Assert(err, gc.ErrorMatches, `rotating cloud credential: cloud credential "dummy/bob/new" not found`)
+
+	_, err = s.State.CloudCredential(oldTag)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *CloudCredentialsSuite) TestRotateCloudCredentialNewRevoked(c *gc.C) {
+	s.Factory.MakeUser(c, &factory.UserParams{Name: "bob"})
+	oldTag := names.NewCloudCredentialTag("dummy/bob/old")
+	newTag := names.NewCloudCredentialTag("dummy/bob/new")
+	s.addModelWithCredential(c, oldTag)
+	cred := cloud.NewCredential(cloud.EmptyAuthType, nil)
+	cred.Revoked = true
+	err := s.State.UpdateCloudCredential(newTag, cred)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.State.RotateCloudCredential(oldTag, newTag)
+	c.Assert(err, gc.ErrorMatches, `rotating cloud credential: revoked credential "dummy/bob/new" not valid`)
+}
+
 func (s *CloudCredentialsSuite) createCredentialWatcher(c *gc.C, st *state.State, cred names.CloudCredentialTag) (
 	state.NotifyWatcher, statetesting.NotifyWatcherC,
 ) {
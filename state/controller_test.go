@@ -28,6 +28,8 @@ func (s *ControllerSuite) TestControllerAndModelConfigInitialisation(c *gc.C) {
 	optional := set.NewStrings(
 		controller.IdentityURL,
 		controller.IdentityPublicKey,
+		controller.OIDCIssuerURL,
+		controller.OIDCClientID,
 		controller.AutocertURLKey,
 		controller.AutocertDNSNameKey,
 		controller.AllowModelAccessKey,
@@ -35,6 +37,7 @@ func (s *ControllerSuite) TestControllerAndModelConfigInitialisation(c *gc.C) {
 		controller.JujuHASpace,
 		controller.JujuManagementSpace,
 		controller.AuditLogExcludeMethods,
+		controller.Features,
 	)
 	for _, controllerAttr := range controller.ControllerOnlyConfigAttributes {
 		v, ok := controllerSettings.Get(controllerAttr)
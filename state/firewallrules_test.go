@@ -12,6 +12,7 @@ import (
 	"gopkg.in/mgo.v2/bson"
 
 	"github.com/juju/juju/state"
+	statetesting "github.com/juju/juju/state/testing"
 )
 
 type FirewallRulesSuite struct {
@@ -132,3 +133,27 @@ func (s *FirewallRulesSuite) TestUpdate(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 	s.assertSavedRules(c, state.JujuApplicationOfferRule, []string{"192.168.2.0/16"})
 }
+
+func (s *FirewallRulesSuite) TestWatchFirewallRules(c *gc.C) {
+	w := s.State.WatchFirewallRules()
+	wc := statetesting.NewNotifyWatcherC(c, s.State, w)
+	wc.AssertOneChange() // Initial event.
+
+	rules := state.NewFirewallRules(s.State)
+	err := rules.Save(state.FirewallRule{
+		WellKnownService: state.SSHRule,
+		WhitelistCIDRs:   []string{"192.168.1.0/16"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+
+	err = rules.Save(state.FirewallRule{
+		WellKnownService: state.SSHRule,
+		WhitelistCIDRs:   []string{"192.168.2.0/16"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+
+	statetesting.AssertStop(c, w)
+	wc.AssertClosed()
+}
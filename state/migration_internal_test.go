@@ -166,6 +166,10 @@ func (s *MigrationSuite) TestKnownCollections(c *gc.C) {
 		// migrate that information.
 		rebootC,
 
+		// A series upgrade blocks migration, so there can be no
+		// upgrade series locks to migrate.
+		upgradeSeriesLocksC,
+
 		// Charms are added into the migrated model during the binary transfer
 		// phase after the initial model migration.
 		charmsC,
@@ -239,6 +243,9 @@ func (s *MigrationSuite) TestModelDocFields(c *gc.C) {
 		"SLA",
 		"MeterStatus",
 		"EnvironVersion",
+		// Suspended isn't migrated; suspended models
+		// fail the migration prechecks.
+		"Suspended",
 	)
 	s.AssertExportedFields(c, modelDoc{}, fields)
 }
@@ -366,6 +373,8 @@ func (s *MigrationSuite) TestApplicationDocFields(c *gc.C) {
 		// RelationCount is handled by the number of times the application name
 		// appears in relation endpoints.
 		"RelationCount",
+		// Placement is only used by CAAS models, which are not migrated.
+		"Placement",
 	)
 	migrated := set.NewStrings(
 		"Name",
@@ -114,6 +114,10 @@ type modelDoc struct {
 
 	// MeterStatus is the current meter status of the model.
 	MeterStatus modelMeterStatusdoc `bson:"meter-status"`
+
+	// Suspended records whether the model's instances have been
+	// powered off, to be powered on again when the model is resumed.
+	Suspended bool `bson:"suspended,omitempty"`
 }
 
 // slaLevel enumerates the support levels available to a model.
@@ -598,6 +602,36 @@ func (m *Model) SetMigrationMode(mode MigrationMode) error {
 	return m.Refresh()
 }
 
+// Suspended returns whether the model's instances have been
+// powered off.
+func (m *Model) Suspended() bool {
+	return m.doc.Suspended
+}
+
+// SetSuspended records whether the model's instances have been
+// powered off. The model must be alive to be suspended.
+func (m *Model) SetSuspended(suspended bool) error {
+	assert := txn.DocExists
+	if suspended {
+		assert = isAliveDoc
+	}
+	ops := []txn.Op{{
+		C:      modelsC,
+		Id:     m.doc.UUID,
+		Assert: assert,
+		Update: bson.D{{"$set", bson.D{{"suspended", suspended}}}},
+	}}
+	if err := m.st.db().RunTransaction(ops); err == txn.ErrAborted {
+		if err := m.Refresh(); err != nil {
+			return errors.Trace(err)
+		}
+		return errors.Errorf("model %q is no longer alive", m.doc.Name)
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	return m.Refresh()
+}
+
 // Life returns whether the model is Alive, Dying or Dead.
 func (m *Model) Life() Life {
 	return m.doc.Life
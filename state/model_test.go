@@ -255,6 +255,42 @@ func (s *ModelSuite) TestSetMigrationMode(c *gc.C) {
 	c.Assert(model.MigrationMode(), gc.Equals, state.MigrationModeExporting)
 }
 
+func (s *ModelSuite) TestSetSuspended(c *gc.C) {
+	model, err := s.State.Model()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(model.Suspended(), jc.IsFalse)
+
+	err = model.SetSuspended(true)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(model.Suspended(), jc.IsTrue)
+
+	model, err = s.State.Model()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(model.Suspended(), jc.IsTrue)
+
+	err = model.SetSuspended(false)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(model.Suspended(), jc.IsFalse)
+}
+
+func (s *ModelSuite) TestSetSuspendedDyingModel(c *gc.C) {
+	st2 := s.Factory.MakeModel(c, nil)
+	defer st2.Close()
+	factory.NewFactory(st2).MakeApplication(c, nil)
+	model, err := st2.Model()
+	c.Assert(err, jc.ErrorIsNil)
+	err = model.Destroy(state.DestroyModelParams{})
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = model.SetSuspended(true)
+	c.Assert(err, gc.ErrorMatches, `model ".*" is no longer alive`)
+	c.Assert(model.Suspended(), jc.IsFalse)
+
+	// A dying model can still be resumed.
+	err = model.SetSuspended(false)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
 func (s *ModelSuite) TestModelExists(c *gc.C) {
 	modelExists, err := s.State.ModelExists(s.State.ModelUUID())
 	c.Assert(err, jc.ErrorIsNil)
@@ -565,6 +565,46 @@ func (s *ModelConfigSourceSuite) TestUpdateModelConfigRegionDefaults(c *gc.C) {
 	c.Assert(cfg, jc.DeepEquals, expectedValues)
 }
 
+func (s *ModelConfigSourceSuite) TestUpdateModelConfigCloudDefaults(c *gc.C) {
+	attrs := map[string]interface{}{
+		"apt-mirror": "http://dummy-cloud-mirror",
+		"ftp-proxy":  "http://dummy-cloud-proxy",
+	}
+	err := s.IAASModel.UpdateModelConfigDefaultValues(attrs, nil, &environs.RegionSpec{Cloud: "dummy"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	cfg, err := s.IAASModel.ModelConfigDefaultValues()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cfg["ftp-proxy"], jc.DeepEquals, config.AttributeDefaultValues{
+		Default: "",
+		Cloud:   "http://dummy-cloud-proxy",
+	})
+	c.Assert(cfg["apt-mirror"], jc.DeepEquals, config.AttributeDefaultValues{
+		Default:    "",
+		Controller: "http://mirror",
+		Cloud:      "http://dummy-cloud-mirror",
+		Regions: []config.RegionDefaultValue{{
+			Name:  "dummy-region",
+			Value: "http://dummy-mirror",
+		}}})
+
+	// Cloud values override the controller's,
+	// and are overridden by the region's.
+	newCfg, err := s.State.ComposeNewModelConfig(nil, &environs.RegionSpec{Cloud: "dummy", Region: "dummy-region"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(newCfg["ftp-proxy"], gc.Equals, "http://dummy-cloud-proxy")
+	c.Assert(newCfg["apt-mirror"], gc.Equals, "http://dummy-mirror")
+	newCfg, err = s.State.ComposeNewModelConfig(nil, &environs.RegionSpec{Cloud: "dummy", Region: "nether-region"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(newCfg["apt-mirror"], gc.Equals, "http://dummy-cloud-mirror")
+
+	err = s.IAASModel.UpdateModelConfigDefaultValues(nil, []string{"apt-mirror", "ftp-proxy"}, &environs.RegionSpec{Cloud: "dummy"})
+	c.Assert(err, jc.ErrorIsNil)
+	cfg, err = s.IAASModel.ModelConfigDefaultValues()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cfg["ftp-proxy"], jc.DeepEquals, config.AttributeDefaultValues{Default: ""})
+}
+
 func (s *ModelConfigSourceSuite) TestUpdateModelConfigDefaultValuesUnknownRegion(c *gc.C) {
 	// Set up settings to create
 	attrs := map[string]interface{}{
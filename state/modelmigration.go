@@ -63,15 +63,27 @@ type ModelMigration interface {
 	// InitiatedBy returns username the initiated the migration.
 	InitiatedBy() string
 
+	// Paused returns true if the migration has been paused.
+	Paused() bool
+
 	// TargetInfo returns the details required to connect to the
 	// migration's target controller.
 	TargetInfo() (*migration.TargetInfo, error)
 
 	// SetPhase sets the phase of the migration. An error will be
-	// returned if the new phase does not follow the current phase or
-	// if the migration is no longer active.
+	// returned if the new phase does not follow the current phase, if
+	// the migration is no longer active, or if the migration is
+	// paused and the new phase is not ABORT.
 	SetPhase(nextPhase migration.Phase) error
 
+	// Pause stops the migration from moving on from its current
+	// phase until it is resumed. Only migrations which could still
+	// be aborted may be paused.
+	Pause() error
+
+	// Resume allows a paused migration to continue.
+	Resume() error
+
 	// SetStatusMessage sets some human readable text about the
 	// current progress of the migration.
 	SetStatusMessage(text string) error
@@ -190,6 +202,10 @@ type modelMigStatusDoc struct {
 	// StatusMessage holds a human readable message about the
 	// migration's progress.
 	StatusMessage string `bson:"status-message"`
+
+	// Paused is set when the migration must not move on from its
+	// current phase until it is resumed (or aborted).
+	Paused bool `bson:"paused,omitempty"`
 }
 
 type modelMigMinionSyncDoc struct {
@@ -255,6 +271,11 @@ func (mig *modelMigration) InitiatedBy() string {
 	return mig.doc.InitiatedBy
 }
 
+// Paused implements ModelMigration.
+func (mig *modelMigration) Paused() bool {
+	return mig.statusDoc.Paused
+}
+
 // TargetInfo implements ModelMigration.
 func (mig *modelMigration) TargetInfo() (*migration.TargetInfo, error) {
 	authTag, err := names.ParseUserTag(mig.doc.TargetAuthTag)
@@ -290,13 +311,25 @@ func (mig *modelMigration) SetPhase(nextPhase migration.Phase) error {
 	if !phase.CanTransitionTo(nextPhase) {
 		return errors.Errorf("illegal phase change: %s -> %s", phase, nextPhase)
 	}
+	// A paused migration may still be aborted.
+	if mig.statusDoc.Paused && nextPhase != migration.ABORT {
+		return errors.Errorf("migration is paused in phase %s", phase)
+	}
 
 	nextDoc := mig.statusDoc
 	nextDoc.Phase = nextPhase.String()
 	nextDoc.PhaseChangedTime = now
+	nextDoc.Paused = false
 	update := bson.M{
 		"phase":              nextDoc.Phase,
 		"phase-changed-time": now,
+		"paused":             false,
+	}
+	// Ensure phase hasn't changed underneath us, and that the
+	// migration hasn't been paused if it is to carry on.
+	statusAssert := bson.M{"phase": mig.statusDoc.Phase}
+	if nextPhase != migration.ABORT {
+		statusAssert["paused"] = bson.M{"$ne": true}
 	}
 	if nextPhase == migration.SUCCESS {
 		nextDoc.SuccessTime = now
@@ -337,8 +370,7 @@ func (mig *modelMigration) SetPhase(nextPhase migration.Phase) error {
 		C:      migrationsStatusC,
 		Id:     mig.statusDoc.Id,
 		Update: bson.M{"$set": update},
-		// Ensure phase hasn't changed underneath us
-		Assert: bson.M{"phase": mig.statusDoc.Phase},
+		Assert: statusAssert,
 	})
 
 	if err := mig.st.db().RunTransaction(ops); err == txn.ErrAborted {
@@ -351,6 +383,44 @@ func (mig *modelMigration) SetPhase(nextPhase migration.Phase) error {
 	return nil
 }
 
+// Pause implements ModelMigration.
+func (mig *modelMigration) Pause() error {
+	phase, err := mig.Phase()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	// Only pause migrations which can be aborted, so that a paused
+	// migration can always be rolled back rather than resumed.
+	if !phase.CanTransitionTo(migration.ABORT) {
+		return errors.Errorf("migration cannot be paused in phase %s", phase)
+	}
+	return errors.Trace(mig.setPaused(true))
+}
+
+// Resume implements ModelMigration.
+func (mig *modelMigration) Resume() error {
+	return errors.Trace(mig.setPaused(false))
+}
+
+func (mig *modelMigration) setPaused(paused bool) error {
+	if mig.statusDoc.Paused == paused {
+		return nil
+	}
+	ops := []txn.Op{{
+		C:      migrationsStatusC,
+		Id:     mig.statusDoc.Id,
+		Update: bson.M{"$set": bson.M{"paused": paused}},
+		Assert: bson.M{"phase": mig.statusDoc.Phase},
+	}}
+	if err := mig.st.db().RunTransaction(ops); err == txn.ErrAborted {
+		return errors.New("phase already changed")
+	} else if err != nil {
+		return errors.Annotate(err, "failed to update migration")
+	}
+	mig.statusDoc.Paused = paused
+	return nil
+}
+
 // migStatusHistoryAndOps sets the model's status history and returns ops for
 // setting model status according to the phase and message.
 func migStatusHistoryAndOps(st *State, phase migration.Phase, now int64, msg string) ([]txn.Op, error) {
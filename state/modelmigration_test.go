@@ -477,6 +477,67 @@ func (s *MigrationSuite) TestPhaseChangeRace(c *gc.C) {
 	assertPhase(c, mig, migration.IMPORT)
 }
 
+func (s *MigrationSuite) TestPauseAndResume(c *gc.C) {
+	mig, err := s.State2.CreateMigration(s.stdSpec)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(mig.Paused(), jc.IsFalse)
+
+	c.Assert(mig.Pause(), jc.ErrorIsNil)
+	c.Assert(mig.Paused(), jc.IsTrue)
+	mig2, err := s.State2.LatestMigration()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(mig2.Paused(), jc.IsTrue)
+
+	err = mig.SetPhase(migration.IMPORT)
+	c.Assert(err, gc.ErrorMatches, "migration is paused in phase QUIESCE")
+	assertPhase(c, mig, migration.QUIESCE)
+
+	c.Assert(mig.Resume(), jc.ErrorIsNil)
+	c.Assert(mig.Paused(), jc.IsFalse)
+	c.Assert(mig.SetPhase(migration.IMPORT), jc.ErrorIsNil)
+}
+
+func (s *MigrationSuite) TestPauseRace(c *gc.C) {
+	mig, err := s.State2.CreateMigration(s.stdSpec)
+	c.Assert(err, jc.ErrorIsNil)
+
+	defer state.SetBeforeHooks(c, s.State2, func() {
+		mig, err := s.State2.LatestMigration()
+		c.Assert(err, jc.ErrorIsNil)
+		c.Assert(mig.Pause(), jc.ErrorIsNil)
+	}).Check()
+
+	err = mig.SetPhase(migration.IMPORT)
+	c.Assert(err, gc.ErrorMatches, "phase already changed")
+	c.Assert(mig.Refresh(), jc.ErrorIsNil)
+	assertPhase(c, mig, migration.QUIESCE)
+	c.Assert(mig.Paused(), jc.IsTrue)
+}
+
+func (s *MigrationSuite) TestAbortWhilePaused(c *gc.C) {
+	mig, err := s.State2.CreateMigration(s.stdSpec)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(mig.Pause(), jc.ErrorIsNil)
+
+	c.Assert(mig.SetPhase(migration.ABORT), jc.ErrorIsNil)
+	c.Assert(mig.Paused(), jc.IsFalse)
+	c.Assert(mig.Refresh(), jc.ErrorIsNil)
+	assertPhase(c, mig, migration.ABORT)
+	c.Assert(mig.Paused(), jc.IsFalse)
+}
+
+func (s *MigrationSuite) TestCannotPauseAfterSuccess(c *gc.C) {
+	mig, err := s.State2.CreateMigration(s.stdSpec)
+	c.Assert(err, jc.ErrorIsNil)
+	for _, phase := range []migration.Phase{migration.IMPORT, migration.VALIDATION, migration.SUCCESS} {
+		c.Assert(mig.SetPhase(phase), jc.ErrorIsNil)
+	}
+
+	err = mig.Pause()
+	c.Assert(err, gc.ErrorMatches, "migration cannot be paused in phase SUCCESS")
+	c.Assert(mig.Paused(), jc.IsFalse)
+}
+
 func (s *MigrationSuite) TestStatusMessage(c *gc.C) {
 	mig, err := s.State2.CreateMigration(s.stdSpec)
 	c.Assert(mig, gc.Not(gc.IsNil))
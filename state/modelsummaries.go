@@ -64,9 +64,14 @@ type ModelSummary struct {
 	Access permission.Access
 	// UserLastConnection is the last time this user has accessed this model
 	UserLastConnection *time.Time
+	// LastConnection is the last time any user has accessed this model
+	LastConnection *time.Time
 
 	MachineCount int64
 	CoreCount    int64
+	// MemoryMB is the total memory of the model's machines, in MiB.
+	MemoryMB  int64
+	UnitCount int64
 
 	// Needs Migration collection
 	// Do we need all the Migration fields?
@@ -262,7 +267,7 @@ func (p *modelSummaryProcessor) fillInMachineSummary() error {
 	instances, closer2 := p.st.db().GetRawCollection(instanceDataC)
 	defer closer2()
 	query = instances.Find(bson.M{"_id": bson.M{"$in": machineIds}})
-	query.Select(bson.M{"cpucores": 1, "model-uuid": 1})
+	query.Select(bson.M{"cpucores": 1, "mem": 1, "model-uuid": 1})
 	iter = query.Iter()
 	defer iter.Close()
 	var instData instanceData
@@ -275,6 +280,35 @@ func (p *modelSummaryProcessor) fillInMachineSummary() error {
 		if instData.CpuCores != nil {
 			details.CoreCount += int64(*instData.CpuCores)
 		}
+		if instData.Mem != nil {
+			details.MemoryMB += int64(*instData.Mem)
+		}
+	}
+	if err := iter.Close(); err != nil {
+		return errors.Trace(err)
+	}
+	return nil
+}
+
+func (p *modelSummaryProcessor) fillInUnitSummary() error {
+	units, closer := p.st.db().GetRawCollection(unitsC)
+	defer closer()
+	query := units.Find(bson.M{
+		"model-uuid": bson.M{"$in": p.modelUUIDs},
+		"life":       Alive,
+	})
+	query.Select(bson.M{"model-uuid": 1})
+	iter := query.Iter()
+	defer iter.Close()
+	var doc struct {
+		ModelUUID string `bson:"model-uuid"`
+	}
+	for iter.Next(&doc) {
+		idx, ok := p.indexByUUID[doc.ModelUUID]
+		if !ok {
+			continue
+		}
+		p.summaries[idx].UnitCount++
 	}
 	if err := iter.Close(); err != nil {
 		return errors.Trace(err)
@@ -413,3 +447,31 @@ func (p *modelSummaryProcessor) fillInLastAccess() error {
 	// actually connected to a model they were given access to.
 	return nil
 }
+
+// fillInModelLastAccess fills in the last time any user connected to each model,
+// which helps to spot models that are no longer being used.
+func (p *modelSummaryProcessor) fillInModelLastAccess() error {
+	lastConnections, closer := p.st.db().GetRawCollection(modelUserLastConnectionC)
+	defer closer()
+	query := lastConnections.Find(bson.M{"model-uuid": bson.M{"$in": p.modelUUIDs}})
+	query.Select(bson.M{"model-uuid": 1, "last-connection": 1})
+	query.Batch(100)
+	iter := query.Iter()
+	defer iter.Close()
+	var connInfo modelUserLastConnectionDoc
+	for iter.Next(&connInfo) {
+		idx, ok := p.indexByUUID[connInfo.ModelUUID]
+		if !ok {
+			continue
+		}
+		details := &p.summaries[idx]
+		if details.LastConnection == nil || connInfo.LastConnection.After(*details.LastConnection) {
+			t := connInfo.LastConnection
+			details.LastConnection = &t
+		}
+	}
+	if err := iter.Close(); err != nil {
+		return errors.Trace(err)
+	}
+	return nil
+}
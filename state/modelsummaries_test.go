@@ -320,6 +320,27 @@ func (s *ModelSummariesSuite) TestContainsAccessInformation(c *gc.C) {
 	})
 }
 
+func (s *ModelSummariesSuite) TestContainsModelLastConnection(c *gc.C) {
+	modelNameToUUID := s.Setup4Models(c)
+	shared, releaser, err := s.StatePool.GetModel(modelNameToUUID["shared"])
+	defer releaser()
+	c.Assert(err, jc.ErrorIsNil)
+	err = shared.UpdateLastModelConnection(names.NewUserTag("user1write"))
+	c.Assert(err, jc.ErrorIsNil)
+	s.Clock.Advance(time.Hour)
+	timeShared := s.Clock.Now().Round(time.Second).UTC()
+	err = shared.UpdateLastModelConnection(names.NewUserTag("user2read"))
+	c.Assert(err, jc.ErrorIsNil)
+
+	summaryMap := s.namedSummariesForUser(c, "user1write")
+	c.Assert(summaryMap["shared"], gc.NotNil)
+	// The model's last connection is the most recent by any user.
+	c.Assert(summaryMap["shared"].LastConnection, gc.NotNil)
+	c.Check(summaryMap["shared"].LastConnection.UTC(), gc.Equals, timeShared)
+	c.Assert(summaryMap["user1model"], gc.NotNil)
+	c.Check(summaryMap["user1model"].LastConnection, gc.IsNil)
+}
+
 func (s *ModelSummariesSuite) TestContainsMachineInformation(c *gc.C) {
 	modelNameToUUID := s.Setup4Models(c)
 	shared, releaser, err := s.StatePool.Get(modelNameToUUID["shared"])
@@ -331,14 +352,17 @@ func (s *ModelSummariesSuite) TestContainsMachineInformation(c *gc.C) {
 	m0, err := shared.AddMachine("quantal", state.JobHostUnits)
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(m0.Life(), gc.Equals, state.Alive)
+	mem := uint64(2048)
 	err = m0.SetInstanceInfo("i-12345", "nonce", &instance.HardwareCharacteristics{
 		CpuCores: &onecore,
+		Mem:      &mem,
 	}, nil, nil, nil, nil)
 	c.Assert(err, jc.ErrorIsNil)
 	m1, err := shared.AddMachine("quantal", state.JobHostUnits)
 	c.Assert(err, jc.ErrorIsNil)
 	err = m1.SetInstanceInfo("i-45678", "nonce", &instance.HardwareCharacteristics{
 		CpuCores: &twocores,
+		Mem:      &mem,
 	}, nil, nil, nil, nil)
 	c.Assert(err, jc.ErrorIsNil)
 	m2, err := shared.AddMachine("quantal", state.JobHostUnits)
@@ -379,10 +403,33 @@ func (s *ModelSummariesSuite) TestContainsMachineInformation(c *gc.C) {
 	c.Assert(sharedSummary, gc.NotNil)
 	c.Check(sharedSummary.MachineCount, gc.Equals, int64(5))
 	c.Check(sharedSummary.CoreCount, gc.Equals, int64(1+2+3))
+	c.Check(sharedSummary.MemoryMB, gc.Equals, int64(2048+2048))
 	userSummary := summaryMap["user1model"]
 	c.Assert(userSummary, gc.NotNil)
 	c.Check(userSummary.MachineCount, gc.Equals, int64(0))
 	c.Check(userSummary.CoreCount, gc.Equals, int64(0))
+	c.Check(userSummary.MemoryMB, gc.Equals, int64(0))
+}
+
+func (s *ModelSummariesSuite) TestContainsUnitInformation(c *gc.C) {
+	modelNameToUUID := s.Setup4Models(c)
+	shared, releaser, err := s.StatePool.Get(modelNameToUUID["shared"])
+	defer releaser()
+	c.Assert(err, jc.ErrorIsNil)
+	f := factory.NewFactory(shared)
+	app := f.MakeApplication(c, nil)
+	f.MakeUnit(c, &factory.UnitParams{Application: app})
+	f.MakeUnit(c, &factory.UnitParams{Application: app})
+	// Dying unit, should not count.
+	unit := f.MakeUnit(c, &factory.UnitParams{Application: app})
+	err = unit.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+
+	summaryMap := s.namedSummariesForUser(c, "user1write")
+	c.Assert(summaryMap["shared"], gc.NotNil)
+	c.Check(summaryMap["shared"].UnitCount, gc.Equals, int64(2))
+	c.Assert(summaryMap["user1model"], gc.NotNil)
+	c.Check(summaryMap["user1model"].UnitCount, gc.Equals, int64(0))
 }
 
 func (s *ModelSummariesSuite) TestContainsMigrationInformation(c *gc.C) {
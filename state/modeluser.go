@@ -217,9 +217,15 @@ func (st *State) ModelSummariesForUser(user names.UserTag, all bool) ([]ModelSum
 	if err := p.fillInLastAccess(); err != nil {
 		return nil, errors.Trace(err)
 	}
+	if err := p.fillInModelLastAccess(); err != nil {
+		return nil, errors.Trace(err)
+	}
 	if err := p.fillInMachineSummary(); err != nil {
 		return nil, errors.Trace(err)
 	}
+	if err := p.fillInUnitSummary(); err != nil {
+		return nil, errors.Trace(err)
+	}
 	if err := p.fillInMigration(); err != nil {
 		return nil, errors.Trace(err)
 	}
@@ -109,3 +109,50 @@ func (st *State) SetUpgradeMongoMode(v mongo.Version) (UpgradeMongoParams, error
 func (st *State) ResumeReplication(members []replicaset.Member) error {
 	return replicaset.Add(st.session, members...)
 }
+
+// replicaSetMachineKey is the replicaset member tag under which the
+// peergrouper records the id of the controller machine running the member.
+const replicaSetMachineKey = "juju-machine-id"
+
+// ReplicaSetMemberStatus describes the mongo replicaset member running
+// on a controller machine.
+type ReplicaSetMemberStatus struct {
+	// Role holds the member's replicaset state, e.g. "PRIMARY".
+	Role string
+
+	// Healthy reports whether the member is reachable by the
+	// rest of the replicaset.
+	Healthy bool
+}
+
+// ReplicaSetStatus returns the status of the mongo replicaset members,
+// keyed by the id of the controller machine each of them runs on.
+// Members not associated with a machine are omitted.
+func (st *State) ReplicaSetStatus() (map[string]ReplicaSetMemberStatus, error) {
+	members, err := replicaset.CurrentMembers(st.session)
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot obtain current replicaset members")
+	}
+	status, err := replicaset.CurrentStatus(st.session)
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot obtain current replicaset status")
+	}
+	machineIds := make(map[int]string)
+	for _, m := range members {
+		if id, ok := m.Tags[replicaSetMachineKey]; ok {
+			machineIds[m.Id] = id
+		}
+	}
+	result := make(map[string]ReplicaSetMemberStatus)
+	for _, m := range status.Members {
+		id, ok := machineIds[m.Id]
+		if !ok {
+			continue
+		}
+		result[id] = ReplicaSetMemberStatus{
+			Role:    m.State.String(),
+			Healthy: m.Healthy,
+		}
+	}
+	return result, nil
+}
@@ -1083,6 +1083,9 @@ func (st *State) AddApplication(args AddApplicationArgs) (_ *Application, err er
 		RelationCount: len(peers),
 		Life:          Alive,
 	}
+	if model.Type() == ModelTypeCAAS && len(args.Placement) > 0 {
+		appDoc.Placement = args.Placement[0].Directive
+	}
 
 	app := newApplication(st, appDoc)
 
@@ -1367,6 +1370,17 @@ func (st *State) processCAASModelApplicationArgs(args *AddApplicationArgs) error
 	// TODO(caas) check that AddApplicationArgs doesn't
 	// contain IAAS-specific things.
 
+	// CAAS units are not assigned to machines; a single
+	// placement directive applies to all of the pods.
+	if len(args.Placement) > 1 {
+		return errors.NotSupportedf("multiple placement directives for CAAS application")
+	}
+	for _, p := range args.Placement {
+		_, err := instance.ParseContainerType(p.Scope)
+		if p.Scope == instance.MachineScope || err == nil || p.Directive == "" {
+			return errors.NotValidf("placement %q for CAAS application", p)
+		}
+	}
 	return nil
 }
 
@@ -1575,6 +1575,86 @@ func (s *StateSuite) TestAddCAASApplication(c *gc.C) {
 	c.Assert(ch.URL(), gc.DeepEquals, ch.URL())
 }
 
+func (s *StateSuite) TestAddCAASApplicationPlacement(c *gc.C) {
+	s.SetFeatureFlags(feature.CAAS)
+	st := s.Factory.MakeModel(c, &factory.ModelParams{
+		Name: "caas-model",
+		Type: state.ModelTypeCAAS, CloudRegion: "<none>",
+		StorageProviderRegistry: factory.NilStorageProviderRegistry{}})
+	defer st.Close()
+	f := factory.NewFactory(st)
+	ch := f.MakeCharm(c, &factory.CharmParams{Name: "wordpress"})
+
+	wordpress, err := st.AddApplication(state.AddApplicationArgs{
+		Name:  "wordpress",
+		Charm: ch,
+		Placement: []*instance.Placement{
+			{Scope: st.ModelUUID(), Directive: "disktype=ssd"},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(wordpress.Placement(), gc.Equals, "disktype=ssd")
+
+	wordpress, err = st.Application("wordpress")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(wordpress.Placement(), gc.Equals, "disktype=ssd")
+}
+
+func (s *StateSuite) TestAddCAASApplicationInvalidPlacement(c *gc.C) {
+	s.SetFeatureFlags(feature.CAAS)
+	st := s.Factory.MakeModel(c, &factory.ModelParams{
+		Name: "caas-model",
+		Type: state.ModelTypeCAAS, CloudRegion: "<none>",
+		StorageProviderRegistry: factory.NilStorageProviderRegistry{}})
+	defer st.Close()
+	f := factory.NewFactory(st)
+	ch := f.MakeCharm(c, &factory.CharmParams{Name: "wordpress"})
+
+	_, err := st.AddApplication(state.AddApplicationArgs{
+		Name:      "wordpress",
+		Charm:     ch,
+		Placement: []*instance.Placement{{Scope: instance.MachineScope, Directive: "0"}},
+	})
+	c.Assert(err, gc.ErrorMatches, `cannot add application "wordpress": placement "#:0" for CAAS application not valid`)
+	c.Assert(errors.Cause(err), jc.Satisfies, errors.IsNotValid)
+
+	_, err = st.AddApplication(state.AddApplicationArgs{
+		Name:  "wordpress",
+		Charm: ch,
+		Placement: []*instance.Placement{
+			{Scope: st.ModelUUID(), Directive: "disktype=ssd"},
+			{Scope: st.ModelUUID(), Directive: "zone=a"},
+		},
+	})
+	c.Assert(err, gc.ErrorMatches, `cannot add application "wordpress": multiple placement directives for CAAS application not supported`)
+}
+
+func (s *StateSuite) TestSetCAASApplicationPlacement(c *gc.C) {
+	s.SetFeatureFlags(feature.CAAS)
+	st := s.Factory.MakeModel(c, &factory.ModelParams{
+		Name: "caas-model",
+		Type: state.ModelTypeCAAS, CloudRegion: "<none>",
+		StorageProviderRegistry: factory.NilStorageProviderRegistry{}})
+	defer st.Close()
+	f := factory.NewFactory(st)
+	ch := f.MakeCharm(c, &factory.CharmParams{Name: "wordpress"})
+	wordpress := f.MakeApplication(c, &factory.ApplicationParams{Name: "wordpress", Charm: ch})
+	c.Assert(wordpress.Placement(), gc.Equals, "")
+
+	err := wordpress.SetPlacement("disktype=ssd,spread=zone")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(wordpress.Placement(), gc.Equals, "disktype=ssd,spread=zone")
+
+	wordpress, err = st.Application("wordpress")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(wordpress.Placement(), gc.Equals, "disktype=ssd,spread=zone")
+
+	err = wordpress.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+	err = wordpress.SetPlacement("disktype=hdd")
+	c.Assert(err, gc.ErrorMatches, `cannot set placement for application "wordpress" to "disktype=hdd": not found or not alive`)
+}
+
 func (s *StateSuite) TestAddApplicationWithNilCharmConfigValues(c *gc.C) {
 	ch := s.AddTestingCharm(c, "dummy")
 	insettings := charm.Settings{"tuning": nil}
@@ -79,8 +79,27 @@ type port struct {
 // ContainerInfo holds attributes about the containing
 // hosting a unit in a CAAS model.
 type ContainerInfo struct {
-	Address string   `bson:"address"`
-	Ports   []string `bson:"ports"`
+	Address     string                `bson:"address"`
+	Ports       []string              `bson:"ports"`
+	Filesystems []ContainerFilesystem `bson:"filesystems,omitempty"`
+}
+
+// ContainerFilesystem holds attributes about a filesystem
+// mounted into the container hosting a unit in a CAAS model.
+type ContainerFilesystem struct {
+	StorageName  string        `bson:"storage-name"`
+	FilesystemId string        `bson:"filesystem-id"`
+	Size         uint64        `bson:"size"`
+	MountPoint   string        `bson:"mount-point"`
+	ReadOnly     bool          `bson:"read-only"`
+	Status       status.Status `bson:"status"`
+	StatusInfo   string        `bson:"status-info,omitempty"`
+
+	// VolumeId, VolumeSize and Persistent describe the
+	// volume backing the filesystem, if there is one.
+	VolumeId   string `bson:"volume-id,omitempty"`
+	VolumeSize uint64 `bson:"volume-size,omitempty"`
+	Persistent bool   `bson:"persistent,omitempty"`
 }
 
 // unitDoc represents the internal state of a unit in MongoDB.
@@ -339,8 +358,9 @@ func (op *UpdateUnitOperation) Build(attempt int) ([]txn.Op, error) {
 			op.unit.Name(), op.unit.ProviderId(), op.props.ProviderId)
 	}
 	containerInfo := ContainerInfo{
-		Address: op.props.Address,
-		Ports:   op.props.Ports,
+		Address:     op.props.Address,
+		Ports:       op.props.Ports,
+		Filesystems: op.props.Filesystems,
 	}
 	var updates bson.D
 	asserts := isAliveDoc
@@ -0,0 +1,189 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"github.com/juju/errors"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+
+	"github.com/juju/juju/core/model"
+)
+
+// upgradeSeriesLockDoc records a series upgrade in progress on a
+// machine, along with the progress of each of the units deployed to
+// it. The machine may not be used for anything else while the lock
+// exists.
+type upgradeSeriesLockDoc struct {
+	DocID         string                               `bson:"_id"`
+	Id            string                               `bson:"machineid"`
+	ModelUUID     string                               `bson:"model-uuid"`
+	FromSeries    string                               `bson:"from-series"`
+	ToSeries      string                               `bson:"to-series"`
+	MachineStatus model.UpgradeSeriesStatus            `bson:"machine-status"`
+	UnitStatuses  map[string]model.UpgradeSeriesStatus `bson:"unit-statuses"`
+}
+
+// CreateUpgradeSeriesLock locks the machine for a series upgrade to
+// the specified series, to be carried out by the given units.
+func (m *Machine) CreateUpgradeSeriesLock(unitNames []string, toSeries string) error {
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			if err := m.Refresh(); err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		if m.Life() != Alive {
+			return nil, errors.Errorf("machine %q is not alive", m.Id())
+		}
+		locked, err := m.IsLockedForSeriesUpgrade()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if locked {
+			return nil, errors.AlreadyExistsf("upgrade series lock for machine %q", m.Id())
+		}
+		unitStatuses := make(map[string]model.UpgradeSeriesStatus)
+		for _, name := range unitNames {
+			unitStatuses[name] = model.UpgradeSeriesNotStarted
+		}
+		return []txn.Op{{
+			C:      machinesC,
+			Id:     m.doc.DocID,
+			Assert: isAliveDoc,
+		}, {
+			C:      upgradeSeriesLocksC,
+			Id:     m.doc.DocID,
+			Assert: txn.DocMissing,
+			Insert: &upgradeSeriesLockDoc{
+				Id:            m.Id(),
+				FromSeries:    m.Series(),
+				ToSeries:      toSeries,
+				MachineStatus: model.UpgradeSeriesNotStarted,
+				UnitStatuses:  unitStatuses,
+			},
+		}}, nil
+	}
+	err := m.st.db().Run(buildTxn)
+	return errors.Annotatef(err, "cannot lock machine %q for series upgrade", m.Id())
+}
+
+// RemoveUpgradeSeriesLock removes the machine's series upgrade lock,
+// if there is one.
+func (m *Machine) RemoveUpgradeSeriesLock() error {
+	locked, err := m.IsLockedForSeriesUpgrade()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if !locked {
+		return nil
+	}
+	ops := []txn.Op{removeUpgradeSeriesLockOp(m.st, m.Id())}
+	err = m.st.db().RunTransaction(ops)
+	return errors.Annotatef(err, "cannot remove series upgrade lock for machine %q", m.Id())
+}
+
+func removeUpgradeSeriesLockOp(st *State, machineId string) txn.Op {
+	return txn.Op{
+		C:      upgradeSeriesLocksC,
+		Id:     st.docID(machineId),
+		Remove: true,
+	}
+}
+
+// IsLockedForSeriesUpgrade returns whether the machine is locked for
+// a series upgrade.
+func (m *Machine) IsLockedForSeriesUpgrade() (bool, error) {
+	_, err := m.getUpgradeSeriesLock()
+	if errors.IsNotFound(err) {
+		return false, nil
+	} else if err != nil {
+		return false, errors.Trace(err)
+	}
+	return true, nil
+}
+
+// UpgradeSeriesTarget returns the series to which the machine is
+// being upgraded.
+func (m *Machine) UpgradeSeriesTarget() (string, error) {
+	lock, err := m.getUpgradeSeriesLock()
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	return lock.ToSeries, nil
+}
+
+// UpgradeSeriesStatus returns the status of the machine's series
+// upgrade.
+func (m *Machine) UpgradeSeriesStatus() (model.UpgradeSeriesStatus, error) {
+	lock, err := m.getUpgradeSeriesLock()
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	return lock.MachineStatus, nil
+}
+
+// SetUpgradeSeriesStatus records the status of the machine's series
+// upgrade.
+func (m *Machine) SetUpgradeSeriesStatus(status model.UpgradeSeriesStatus) error {
+	if err := status.Validate(); err != nil {
+		return errors.Trace(err)
+	}
+	ops := []txn.Op{{
+		C:      upgradeSeriesLocksC,
+		Id:     m.doc.DocID,
+		Assert: txn.DocExists,
+		Update: bson.D{{"$set", bson.D{{"machine-status", status}}}},
+	}}
+	err := m.st.db().RunTransaction(ops)
+	if err == txn.ErrAborted {
+		return errors.NotFoundf("upgrade series lock for machine %q", m.Id())
+	}
+	return errors.Annotatef(err, "cannot set series upgrade status for machine %q", m.Id())
+}
+
+// UpgradeSeriesUnitStatuses returns the series upgrade status of each
+// of the units taking part in the machine's series upgrade.
+func (m *Machine) UpgradeSeriesUnitStatuses() (map[string]model.UpgradeSeriesStatus, error) {
+	lock, err := m.getUpgradeSeriesLock()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return lock.UnitStatuses, nil
+}
+
+// SetUpgradeSeriesUnitStatus records the series upgrade status of a
+// unit taking part in the machine's series upgrade.
+func (m *Machine) SetUpgradeSeriesUnitStatus(unitName string, status model.UpgradeSeriesStatus) error {
+	if err := status.Validate(); err != nil {
+		return errors.Trace(err)
+	}
+	field := "unit-statuses." + unitName
+	ops := []txn.Op{{
+		C:      upgradeSeriesLocksC,
+		Id:     m.doc.DocID,
+		Assert: bson.D{{field, bson.D{{"$exists", true}}}},
+		Update: bson.D{{"$set", bson.D{{field, status}}}},
+	}}
+	err := m.st.db().RunTransaction(ops)
+	if err == txn.ErrAborted {
+		return errors.NotFoundf("unit %q in series upgrade of machine %q", unitName, m.Id())
+	}
+	return errors.Annotatef(err, "cannot set series upgrade status for unit %q", unitName)
+}
+
+func (m *Machine) getUpgradeSeriesLock() (*upgradeSeriesLockDoc, error) {
+	coll, closer := m.st.db().GetCollection(upgradeSeriesLocksC)
+	defer closer()
+
+	var lock upgradeSeriesLockDoc
+	err := coll.FindId(m.doc.DocID).One(&lock)
+	if err == mgo.ErrNotFound {
+		return nil, errors.NotFoundf("upgrade series lock for machine %q", m.Id())
+	} else if err != nil {
+		return nil, errors.Annotatef(err, "cannot get upgrade series lock for machine %q", m.Id())
+	}
+	return &lock, nil
+}
@@ -0,0 +1,137 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/core/model"
+	"github.com/juju/juju/state"
+	statetesting "github.com/juju/juju/state/testing"
+)
+
+type UpgradeSeriesSuite struct {
+	ConnSuite
+
+	machine *state.Machine
+}
+
+var _ = gc.Suite(&UpgradeSeriesSuite{})
+
+func (s *UpgradeSeriesSuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+	var err error
+	s.machine, err = s.State.AddMachine("trusty", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *UpgradeSeriesSuite) TestCreateUpgradeSeriesLock(c *gc.C) {
+	locked, err := s.machine.IsLockedForSeriesUpgrade()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(locked, jc.IsFalse)
+
+	err = s.machine.CreateUpgradeSeriesLock([]string{"wordpress/0"}, "xenial")
+	c.Assert(err, jc.ErrorIsNil)
+
+	locked, err = s.machine.IsLockedForSeriesUpgrade()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(locked, jc.IsTrue)
+
+	target, err := s.machine.UpgradeSeriesTarget()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(target, gc.Equals, "xenial")
+
+	status, err := s.machine.UpgradeSeriesStatus()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(status, gc.Equals, model.UpgradeSeriesNotStarted)
+
+	statuses, err := s.machine.UpgradeSeriesUnitStatuses()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(statuses, jc.DeepEquals, map[string]model.UpgradeSeriesStatus{
+		"wordpress/0": model.UpgradeSeriesNotStarted,
+	})
+}
+
+func (s *UpgradeSeriesSuite) TestCreateUpgradeSeriesLockAlreadyLocked(c *gc.C) {
+	err := s.machine.CreateUpgradeSeriesLock(nil, "xenial")
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.machine.CreateUpgradeSeriesLock(nil, "bionic")
+	c.Assert(err, jc.Satisfies, errors.IsAlreadyExists)
+}
+
+func (s *UpgradeSeriesSuite) TestRemoveUpgradeSeriesLock(c *gc.C) {
+	err := s.machine.CreateUpgradeSeriesLock(nil, "xenial")
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.machine.RemoveUpgradeSeriesLock()
+	c.Assert(err, jc.ErrorIsNil)
+	locked, err := s.machine.IsLockedForSeriesUpgrade()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(locked, jc.IsFalse)
+
+	// Removing a missing lock is not an error.
+	err = s.machine.RemoveUpgradeSeriesLock()
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *UpgradeSeriesSuite) TestSetUpgradeSeriesStatus(c *gc.C) {
+	err := s.machine.CreateUpgradeSeriesLock(nil, "xenial")
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.machine.SetUpgradeSeriesStatus(model.UpgradeSeriesPrepareStarted)
+	c.Assert(err, jc.ErrorIsNil)
+	status, err := s.machine.UpgradeSeriesStatus()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(status, gc.Equals, model.UpgradeSeriesPrepareStarted)
+
+	err = s.machine.SetUpgradeSeriesStatus("bad")
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+}
+
+func (s *UpgradeSeriesSuite) TestSetUpgradeSeriesStatusNotLocked(c *gc.C) {
+	err := s.machine.SetUpgradeSeriesStatus(model.UpgradeSeriesPrepareStarted)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *UpgradeSeriesSuite) TestSetUpgradeSeriesUnitStatus(c *gc.C) {
+	err := s.machine.CreateUpgradeSeriesLock([]string{"wordpress/0", "mysql/1"}, "xenial")
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.machine.SetUpgradeSeriesUnitStatus("mysql/1", model.UpgradeSeriesPrepareCompleted)
+	c.Assert(err, jc.ErrorIsNil)
+	statuses, err := s.machine.UpgradeSeriesUnitStatuses()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(statuses, jc.DeepEquals, map[string]model.UpgradeSeriesStatus{
+		"wordpress/0": model.UpgradeSeriesNotStarted,
+		"mysql/1":     model.UpgradeSeriesPrepareCompleted,
+	})
+
+	err = s.machine.SetUpgradeSeriesUnitStatus("mysql/2", model.UpgradeSeriesPrepareCompleted)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *UpgradeSeriesSuite) TestWatchUpgradeSeriesNotifications(c *gc.C) {
+	w := s.machine.WatchUpgradeSeriesNotifications()
+	defer statetesting.AssertStop(c, w)
+	wc := statetesting.NewNotifyWatcherC(c, s.State, w)
+	wc.AssertOneChange()
+
+	err := s.machine.CreateUpgradeSeriesLock(nil, "xenial")
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+
+	err = s.machine.SetUpgradeSeriesStatus(model.UpgradeSeriesPrepareStarted)
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+
+	err = s.machine.RemoveUpgradeSeriesLock()
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+
+	statetesting.AssertStop(c, w)
+	wc.AssertClosed()
+}
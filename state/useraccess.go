@@ -155,6 +155,8 @@ func (st *State) UserPermission(subject names.UserTag, target names.Tag) (permis
 			return "", errors.Trace(err)
 		}
 		return st.GetOfferAccess(offerUUID, subject)
+	case names.CloudTagKind:
+		return st.GetCloudAccess(target.Id(), subject)
 	default:
 		return "", errors.NotValidf("%q as a target", target.Kind())
 	}
@@ -1600,6 +1600,19 @@ func (a *Application) WatchCharmConfig() (NotifyWatcher, error) {
 	return newEntityWatcher(a.st, settingsC, a.st.docID(configKey)), nil
 }
 
+// WatchApplicationConfig returns a watcher for observing changes
+// to the application's own configuration settings.
+func (a *Application) WatchApplicationConfig() NotifyWatcher {
+	configKey := a.applicationConfigKey()
+	return newEntityWatcher(a.st, settingsC, a.st.docID(configKey))
+}
+
+// WatchConstraints returns a watcher for observing changes
+// to the application's constraints.
+func (a *Application) WatchConstraints() NotifyWatcher {
+	return newEntityWatcher(a.st, constraintsC, a.st.docID(a.globalKey()))
+}
+
 // WatchConfigSettings returns a watcher for observing changes to the
 // unit's service configuration settings. The unit must have a charm URL
 // set before this method is called, and the returned watcher will be
@@ -2618,6 +2631,19 @@ func (m *Machine) WatchForRebootEvent() NotifyWatcher {
 	return newNotifyCollWatcher(m.st, rebootC, filter)
 }
 
+// WatchUpgradeSeriesNotifications returns a notify watcher that will
+// trigger an event whenever the machine's series upgrade lock is
+// created, changed or removed.
+func (m *Machine) WatchUpgradeSeriesNotifications() NotifyWatcher {
+	filter := func(key interface{}) bool {
+		if id, ok := key.(string); ok {
+			return id == m.doc.DocID
+		}
+		return false
+	}
+	return newNotifyCollWatcher(m.st, upgradeSeriesLocksC, filter)
+}
+
 // blockDevicesWatcher notifies about changes to all block devices
 // associated with a machine.
 type blockDevicesWatcher struct {
@@ -2776,6 +2802,12 @@ func (st *State) WatchMachineRemovals() NotifyWatcher {
 	return newNotifyCollWatcher(st, machineRemovalsC, isLocalID(st))
 }
 
+// WatchFirewallRules returns a NotifyWatcher which triggers
+// whenever the model's firewall rules are saved.
+func (st *State) WatchFirewallRules() NotifyWatcher {
+	return newNotifyCollWatcher(st, firewallRulesC, isLocalID(st))
+}
+
 // notifyCollWatcher implements NotifyWatcher, triggering when a
 // change is seen in a specific collection matching the provided
 // filter function.
@@ -299,13 +299,15 @@ func (status Status) WorkloadMatches(candidate Status) bool {
 }
 
 // ValidModelStatus returns true if status has a valid value (that is to say,
-// a value that it's OK to set) for models.
+// a value that it's OK to set) for models. A model is Suspended while
+// the cloud credential it uses is not valid.
 func ValidModelStatus(status Status) bool {
 	switch status {
 	case
 		Available,
 		Busy,
 		Destroying,
+		Suspended,
 		Error:
 		return true
 	default:
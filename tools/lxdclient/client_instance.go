@@ -200,17 +200,7 @@ func (client *instanceClient) removeInstance(name string) error {
 
 	//if info.Status.StatusCode != 0 && info.Status.StatusCode != shared.Stopped {
 	if info.StatusCode != api.Stopped {
-		timeout := -1
-		force := true
-		stateful := false
-		resp, err := client.raw.Action(name, shared.Stop, timeout, force, stateful)
-		if err != nil {
-			return errors.Trace(err)
-		}
-
-		if err := client.raw.WaitForSuccess(resp.Operation); err != nil {
-			// TODO(ericsnow) Handle different failures (from the async
-			// operation) differently?
+		if err := client.changeInstanceState(name, shared.Stop, true); err != nil {
 			return errors.Trace(err)
 		}
 	}
@@ -262,6 +252,85 @@ func (client *instanceClient) RemoveInstances(prefix string, names ...string) er
 	return nil
 }
 
+// changeInstanceState sends a request to the API to start or stop
+// the named instance. The call blocks until the action is complete
+// (or the request fails).
+func (client *instanceClient) changeInstanceState(name string, action shared.ContainerAction, force bool) error {
+	timeout := -1
+	stateful := false
+	resp, err := client.raw.Action(name, action, timeout, force, stateful)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	if err := client.raw.WaitForSuccess(resp.Operation); err != nil {
+		// TODO(ericsnow) Handle different failures (from the async
+		// operation) differently?
+		return errors.Trace(err)
+	}
+
+	return nil
+}
+
+// StopInstances sends a request to the API to shut down all instances
+// (in the Client's namespace) that match one of the provided IDs,
+// leaving the instances and their disks in place so that they can be
+// started again with StartInstances. If a prefix is provided, only IDs
+// that start with the prefix will be considered. The call blocks until
+// all the instances are stopped or the request fails.
+func (client *instanceClient) StopInstances(prefix string, names ...string) error {
+	return client.changeInstancesState(prefix, names, shared.Stop, StatusStopped)
+}
+
+// StartInstances sends a request to the API to start all instances
+// (in the Client's namespace) that match one of the provided IDs. If a
+// prefix is provided, only IDs that start with the prefix will be
+// considered. The call blocks until all the instances are started or
+// the request fails.
+func (client *instanceClient) StartInstances(prefix string, names ...string) error {
+	return client.changeInstancesState(prefix, names, shared.Start, StatusRunning)
+}
+
+func (client *instanceClient) changeInstancesState(prefix string, names []string, action shared.ContainerAction, status string) error {
+	if len(names) == 0 {
+		return nil
+	}
+
+	instances, err := client.Instances(prefix)
+	if err != nil {
+		return errors.Annotatef(err, "while changing state of instances %v", names)
+	}
+
+	var failed []string
+	for _, name := range names {
+		inst, ok := findInstance(name, instances)
+		if !ok {
+			return errors.NotFoundf("instance %q", name)
+		}
+		if inst.Status() == status {
+			continue
+		}
+
+		if err := client.changeInstanceState(name, action, false); err != nil {
+			failed = append(failed, name)
+			logger.Errorf("while changing state of instance %q: %v", name, err)
+		}
+	}
+	if len(failed) != 0 {
+		return errors.Errorf("some instance state changes failed: %v", failed)
+	}
+	return nil
+}
+
+func findInstance(name string, instances []Instance) (*Instance, bool) {
+	for i, inst := range instances {
+		if inst.Name == name {
+			return &instances[i], true
+		}
+	}
+	return nil, false
+}
+
 func checkInstanceName(name string, instances []Instance) bool {
 	for _, inst := range instances {
 		if inst.Name == name {
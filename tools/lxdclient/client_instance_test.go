@@ -6,10 +6,10 @@
 package lxdclient_test
 
 import (
-	"errors"
-
+	"github.com/juju/errors"
 	"github.com/juju/testing"
 	jc "github.com/juju/testing/checkers"
+	"github.com/lxc/lxd/shared"
 	lxdapi "github.com/lxc/lxd/shared/api"
 	gc "gopkg.in/check.v1"
 
@@ -256,3 +256,58 @@ func (s *devicesSuite) TestRemoveDeviceAsyncError(c *gc.C) {
 	err := client.RemoveDevice("instance", "device")
 	c.Assert(err, gc.ErrorMatches, "async error")
 }
+
+type powerSuite struct {
+	lxdclient.BaseSuite
+}
+
+var _ = gc.Suite(&powerSuite{})
+
+func (s *powerSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.Client.Response = &lxdapi.Response{Operation: "op"}
+	s.Client.Instances = []lxdapi.Container{
+		{Name: "juju-a-0", StatusCode: lxdapi.Running},
+		{Name: "juju-a-1", StatusCode: lxdapi.Stopped},
+		{Name: "other-0", StatusCode: lxdapi.Running},
+	}
+}
+
+func (s *powerSuite) TestStopInstances(c *gc.C) {
+	client := lxdclient.NewInstanceClient(s.Client)
+	err := client.StopInstances("juju-a-", "juju-a-0", "juju-a-1")
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The instance that is already stopped is left alone.
+	s.Stub.CheckCalls(c, []testing.StubCall{
+		{"ListContainers", nil},
+		{"Action", []interface{}{"juju-a-0", shared.Stop, -1, false, false}},
+		{"WaitForSuccess", []interface{}{"op"}},
+	})
+}
+
+func (s *powerSuite) TestStartInstances(c *gc.C) {
+	client := lxdclient.NewInstanceClient(s.Client)
+	err := client.StartInstances("juju-a-", "juju-a-0", "juju-a-1")
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.Stub.CheckCalls(c, []testing.StubCall{
+		{"ListContainers", nil},
+		{"Action", []interface{}{"juju-a-1", shared.Start, -1, false, false}},
+		{"WaitForSuccess", []interface{}{"op"}},
+	})
+}
+
+func (s *powerSuite) TestStopInstancesNotInNamespace(c *gc.C) {
+	client := lxdclient.NewInstanceClient(s.Client)
+	err := client.StopInstances("juju-a-", "other-0")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	c.Assert(err, gc.ErrorMatches, `instance "other-0" not found`)
+}
+
+func (s *powerSuite) TestStopInstancesFailure(c *gc.C) {
+	s.Stub.SetErrors(nil, errors.New("boom"))
+	client := lxdclient.NewInstanceClient(s.Client)
+	err := client.StopInstances("juju-a-", "juju-a-0")
+	c.Assert(err, gc.ErrorMatches, `some instance state changes failed: \[juju-a-0\]`)
+}
@@ -0,0 +1,79 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package agentconfwatcher
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils/clock"
+	worker "gopkg.in/juju/worker.v1"
+
+	"github.com/juju/juju/agent"
+	"github.com/juju/juju/worker/dependency"
+)
+
+// ManifoldConfig holds the dependencies and configuration for an
+// agentconfwatcher manifold.
+type ManifoldConfig struct {
+	AgentName string
+	Clock     clock.Clock
+	Interval  time.Duration
+	Reload    func() error
+
+	NewWorker func(Config) (worker.Worker, error)
+}
+
+// validate is called by start to check for bad configuration.
+func (config ManifoldConfig) validate() error {
+	if config.AgentName == "" {
+		return errors.NotValidf("empty AgentName")
+	}
+	if config.NewWorker == nil {
+		return errors.NotValidf("nil NewWorker")
+	}
+	return nil
+}
+
+// start is a StartFunc for a Worker manifold.
+func (config ManifoldConfig) start(context dependency.Context) (worker.Worker, error) {
+	if err := config.validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	var agent agent.Agent
+	if err := context.Get(config.AgentName, &agent); err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	agentConfig := agent.CurrentConfig()
+	worker, err := config.NewWorker(Config{
+		Path:     agentConfigPath(agentConfig),
+		Clock:    config.Clock,
+		Interval: config.Interval,
+		Reload:   config.Reload,
+	})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return worker, nil
+}
+
+// agentConfigPath returns the location of the file from which the
+// supplied config was read.
+func agentConfigPath(config agent.Config) string {
+	return agent.ConfigPath(config.DataDir(), config.Tag())
+}
+
+// Manifold returns a dependency manifold that runs the agentconfwatcher
+// worker. Workers that depend on the agent manifold see the reloaded
+// config through the agent's CurrentConfig, and those that need to
+// react to it do so through the agent's config-changed notifications.
+func Manifold(config ManifoldConfig) dependency.Manifold {
+	return dependency.Manifold{
+		Inputs: []string{
+			config.AgentName,
+		},
+		Start: config.start,
+	}
+}
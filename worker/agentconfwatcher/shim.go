@@ -0,0 +1,18 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package agentconfwatcher
+
+import (
+	"github.com/juju/errors"
+	worker "gopkg.in/juju/worker.v1"
+)
+
+// NewWorker returns a worker.Worker that runs New.
+func NewWorker(config Config) (worker.Worker, error) {
+	worker, err := New(config)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return worker, nil
+}
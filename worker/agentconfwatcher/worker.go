@@ -0,0 +1,127 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package agentconfwatcher
+
+import (
+	"bytes"
+	"io/ioutil"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"github.com/juju/utils/clock"
+
+	"github.com/juju/juju/worker/catacomb"
+)
+
+var logger = loggo.GetLogger("juju.worker.agentconfwatcher")
+
+// DefaultInterval is how often the agent config file is checked
+// for changes.
+const DefaultInterval = 10 * time.Second
+
+// Config defines the parameters of the agentconfwatcher worker.
+type Config struct {
+	// Path is the location of the agent config file.
+	Path string
+
+	Clock    clock.Clock
+	Interval time.Duration
+
+	// Reload is called whenever the content of the file changes. It
+	// is expected to read the file into the running agent, and to
+	// notify the agent's workers that its config has changed.
+	Reload func() error
+}
+
+// Validate returns an error if Config cannot drive an agentconfwatcher.
+func (config Config) Validate() error {
+	if config.Path == "" {
+		return errors.NotValidf("empty Path")
+	}
+	if config.Clock == nil {
+		return errors.NotValidf("nil Clock")
+	}
+	if config.Interval <= 0 {
+		return errors.NotValidf("non-positive Interval")
+	}
+	if config.Reload == nil {
+		return errors.NotValidf("nil Reload")
+	}
+	return nil
+}
+
+// New returns a Worker that periodically reads the agent config file,
+// and reloads the agent's config whenever the file has been changed
+// by something other than the agent itself, so that edits to the file
+// take effect without restarting the agent.
+func New(config Config) (*Worker, error) {
+	if err := config.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	w := &Worker{
+		config:  config,
+		content: config.read(),
+	}
+	err := catacomb.Invoke(catacomb.Plan{
+		Site: &w.catacomb,
+		Work: w.loop,
+	})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return w, nil
+}
+
+// read returns the content of the agent config file, or nil if it
+// cannot be read. The file is replaced atomically when written, so
+// a failure to read it is only expected while the agent is being
+// removed; it is not considered a change.
+func (config Config) read() []byte {
+	content, err := ioutil.ReadFile(config.Path)
+	if err != nil {
+		logger.Debugf("cannot read agent config: %v", err)
+		return nil
+	}
+	return content
+}
+
+// Worker implements worker.Worker.
+type Worker struct {
+	catacomb catacomb.Catacomb
+	config   Config
+	content  []byte
+}
+
+// Kill is part of the worker.Worker interface.
+func (w *Worker) Kill() {
+	w.catacomb.Kill(nil)
+}
+
+// Wait is part of the worker.Worker interface.
+func (w *Worker) Wait() error {
+	return w.catacomb.Wait()
+}
+
+func (w *Worker) loop() error {
+	for {
+		select {
+		case <-w.catacomb.Dying():
+			return w.catacomb.ErrDying()
+		case <-w.config.Clock.After(w.config.Interval):
+			content := w.config.read()
+			if content == nil || bytes.Equal(content, w.content) {
+				continue
+			}
+			// A config that fails to load is left for the next
+			// change to fix; the agent keeps running with the
+			// config it already has.
+			logger.Infof("agent config changed, reloading")
+			if err := w.config.Reload(); err != nil {
+				logger.Errorf("cannot reload agent config: %v", err)
+			}
+			w.content = content
+		}
+	}
+}
@@ -0,0 +1,136 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package agentconfwatcher_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"time"
+
+	"github.com/juju/errors"
+	jujutesting "github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/agentconfwatcher"
+	"github.com/juju/juju/worker/workertest"
+)
+
+type Suite struct {
+	jujutesting.IsolationSuite
+
+	clock   *jujutesting.Clock
+	reloads chan struct{}
+	err     error
+	config  agentconfwatcher.Config
+}
+
+var _ = gc.Suite(&Suite{})
+
+func (s *Suite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.clock = jujutesting.NewClock(time.Time{})
+	s.reloads = make(chan struct{}, 10)
+	s.err = nil
+	s.config = agentconfwatcher.Config{
+		Path:     filepath.Join(c.MkDir(), "agent.conf"),
+		Clock:    s.clock,
+		Interval: time.Minute,
+		Reload:   s.reload,
+	}
+	s.write(c, "original")
+}
+
+func (s *Suite) reload() error {
+	s.reloads <- struct{}{}
+	return s.err
+}
+
+func (s *Suite) write(c *gc.C, content string) {
+	err := ioutil.WriteFile(s.config.Path, []byte(content), 0600)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *Suite) advance(c *gc.C) {
+	err := s.clock.WaitAdvance(time.Minute, coretesting.LongWait, 1)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *Suite) assertReloaded(c *gc.C) {
+	select {
+	case <-s.reloads:
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("config not reloaded")
+	}
+}
+
+func (s *Suite) assertNotReloaded(c *gc.C) {
+	select {
+	case <-s.reloads:
+		c.Fatalf("config reloaded unexpectedly")
+	case <-time.After(coretesting.ShortWait):
+	}
+}
+
+func (s *Suite) TestInvalidConfig(c *gc.C) {
+	s.config.Reload = nil
+	_, err := agentconfwatcher.New(s.config)
+	c.Check(err, gc.ErrorMatches, "nil Reload not valid")
+}
+
+func (s *Suite) TestUnchanged(c *gc.C) {
+	w, err := agentconfwatcher.New(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.CleanKill(c, w)
+
+	s.advance(c)
+	s.advance(c)
+	s.assertNotReloaded(c)
+}
+
+func (s *Suite) TestChanged(c *gc.C) {
+	w, err := agentconfwatcher.New(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.CleanKill(c, w)
+
+	s.write(c, "changed")
+	s.advance(c)
+	s.assertReloaded(c)
+
+	// The change has been seen, and is not reloaded again.
+	s.advance(c)
+	s.advance(c)
+	s.assertNotReloaded(c)
+}
+
+func (s *Suite) TestReloadErrorNotFatal(c *gc.C) {
+	s.err = errors.New("bad config")
+	w, err := agentconfwatcher.New(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.CleanKill(c, w)
+
+	s.write(c, "broken")
+	s.advance(c)
+	s.assertReloaded(c)
+
+	s.write(c, "fixed")
+	s.advance(c)
+	s.assertReloaded(c)
+	workertest.CheckAlive(c, w)
+}
+
+func (s *Suite) TestMissingFileIgnored(c *gc.C) {
+	s.config.Path = filepath.Join(c.MkDir(), "agent.conf")
+	w, err := agentconfwatcher.New(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.CleanKill(c, w)
+
+	s.advance(c)
+	s.assertNotReloaded(c)
+
+	s.write(c, "created")
+	s.advance(c)
+	s.assertReloaded(c)
+}
@@ -5,6 +5,7 @@ package apicaller
 
 import (
 	"github.com/juju/errors"
+	"github.com/juju/pubsub"
 	worker "gopkg.in/juju/worker.v1"
 
 	"github.com/juju/juju/agent"
@@ -50,6 +51,10 @@ type ManifoldConfig struct {
 	// Filter is used to specialize responses to connection errors
 	// made on behalf of different kinds of agent.
 	Filter dependency.FilterFunc
+
+	// Hub, if set, is the agent's local hub, on which the worker
+	// publishes when the connection breaks.
+	Hub *pubsub.SimpleHub
 }
 
 // Manifold returns a manifold whose worker wraps an API connection
@@ -81,7 +86,7 @@ func (config ManifoldConfig) startFunc() dependency.StartFunc {
 		} else if err != nil {
 			return nil, errors.Annotate(err, "cannot open api")
 		}
-		return newAPIConnWorker(conn), nil
+		return newAPIConnWorker(conn, config.Hub), nil
 	}
 }
 
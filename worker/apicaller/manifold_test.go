@@ -4,7 +4,10 @@
 package apicaller_test
 
 import (
+	"time"
+
 	"github.com/juju/errors"
+	"github.com/juju/pubsub"
 	"github.com/juju/testing"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
@@ -13,6 +16,7 @@ import (
 	"github.com/juju/juju/agent"
 	"github.com/juju/juju/api"
 	"github.com/juju/juju/api/base"
+	agentpubsub "github.com/juju/juju/pubsub/agent"
 	coretesting "github.com/juju/juju/testing"
 	"github.com/juju/juju/worker/apicaller"
 	"github.com/juju/juju/worker/dependency"
@@ -25,6 +29,7 @@ type ManifoldSuite struct {
 	manifold dependency.Manifold
 	agent    *mockAgent
 	conn     *mockConn
+	hub      *pubsub.SimpleHub
 	context  dependency.Context
 }
 
@@ -33,6 +38,7 @@ var _ = gc.Suite(&ManifoldSuite{})
 func (s *ManifoldSuite) SetUpTest(c *gc.C) {
 	s.IsolationSuite.SetUpTest(c)
 	s.Stub = testing.Stub{}
+	s.hub = pubsub.NewSimpleHub(nil)
 	s.manifold = apicaller.Manifold(apicaller.ManifoldConfig{
 		AgentName:            "agent-name",
 		APIConfigWatcherName: "api-config-watcher-name",
@@ -50,6 +56,7 @@ func (s *ManifoldSuite) SetUpTest(c *gc.C) {
 		Filter: func(err error) error {
 			panic(err)
 		},
+		Hub: s.hub,
 	})
 	checkFilter := func() {
 		s.manifold.Filter(errors.New("arrgh"))
@@ -161,6 +168,25 @@ func (s *ManifoldSuite) TestBrokenConnectionKillsWorkerWithFallbackErr(c *gc.C)
 	}})
 }
 
+func (s *ManifoldSuite) TestBrokenConnectionPublished(c *gc.C) {
+	published := make(chan string, 1)
+	unsubscribe := s.hub.Subscribe(agentpubsub.APIConnectionLostTopic, func(topic string, _ interface{}) {
+		published <- topic
+	})
+	defer unsubscribe()
+	worker := s.setupWorkerTest(c)
+
+	close(s.conn.broken)
+	err := worker.Wait()
+	c.Check(err, gc.ErrorMatches, "api connection broken unexpectedly")
+	select {
+	case topic := <-published:
+		c.Check(topic, gc.Equals, agentpubsub.APIConnectionLostTopic)
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("connection loss not published")
+	}
+}
+
 func (s *ManifoldSuite) TestOutputSuccess(c *gc.C) {
 	worker := s.setupWorkerTest(c)
 
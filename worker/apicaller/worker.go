@@ -6,10 +6,12 @@ package apicaller
 import (
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
+	"github.com/juju/pubsub"
 	worker "gopkg.in/juju/worker.v1"
 	"gopkg.in/tomb.v1"
 
 	"github.com/juju/juju/api"
+	"github.com/juju/juju/pubsub/agent"
 )
 
 var logger = loggo.GetLogger("juju.worker.apicaller")
@@ -22,8 +24,11 @@ var logger = loggo.GetLogger("juju.worker.apicaller")
 // The lack of error return is considered and intentional; it signals the
 // transfer of responsibility for the connection from the caller to the
 // worker.
-func newAPIConnWorker(conn api.Connection) worker.Worker {
-	w := &apiConnWorker{conn: conn}
+//
+// If hub is not nil, the worker publishes on it when the connection
+// breaks, so that other workers in the agent can react.
+func newAPIConnWorker(conn api.Connection, hub *pubsub.SimpleHub) worker.Worker {
+	w := &apiConnWorker{conn: conn, hub: hub}
 	go func() {
 		defer w.tomb.Done()
 		w.tomb.Kill(w.loop())
@@ -34,6 +39,7 @@ func newAPIConnWorker(conn api.Connection) worker.Worker {
 type apiConnWorker struct {
 	tomb tomb.Tomb
 	conn api.Connection
+	hub  *pubsub.SimpleHub
 }
 
 // Kill is part of the worker.Worker interface.
@@ -72,6 +78,9 @@ func (w *apiConnWorker) loop() (err error) {
 		case <-w.tomb.Dying():
 			return tomb.ErrDying
 		case <-w.conn.Broken():
+			if w.hub != nil {
+				w.hub.Publish(agent.APIConnectionLostTopic, nil)
+			}
 			return errors.New("api connection broken unexpectedly")
 		}
 	}
@@ -8,11 +8,13 @@ import (
 
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
+	"github.com/juju/pubsub"
 	"github.com/juju/utils/voyeur"
 	worker "gopkg.in/juju/worker.v1"
 	"gopkg.in/tomb.v1"
 
 	"github.com/juju/juju/agent"
+	agentpubsub "github.com/juju/juju/pubsub/agent"
 	"github.com/juju/juju/worker/dependency"
 )
 
@@ -21,6 +23,10 @@ var logger = loggo.GetLogger("juju.worker.apiconfigwatcher")
 type ManifoldConfig struct {
 	AgentName          string
 	AgentConfigChanged *voyeur.Value
+
+	// Hub, if set, is the agent's local hub, on which the worker
+	// publishes when the API addresses change.
+	Hub *pubsub.SimpleHub
 }
 
 // Manifold returns a dependency.Manifold which wraps an agent's
@@ -46,6 +52,7 @@ func Manifold(config ManifoldConfig) dependency.Manifold {
 			w := &apiconfigwatcher{
 				agent:              a,
 				agentConfigChanged: config.AgentConfigChanged,
+				hub:                config.Hub,
 				addrs:              getAPIAddresses(a),
 			}
 			go func() {
@@ -61,6 +68,7 @@ type apiconfigwatcher struct {
 	tomb               tomb.Tomb
 	agent              agent.Agent
 	agentConfigChanged *voyeur.Value
+	hub                *pubsub.SimpleHub
 	addrs              []string
 }
 
@@ -92,8 +100,13 @@ func (w *apiconfigwatcher) loop() error {
 		// Always unconditionally check for a change in API addresses
 		// first, in case there was a change between the start func
 		// and the call to Watch.
-		if !stringSliceEq(w.addrs, getAPIAddresses(w.agent)) {
+		if addrs := getAPIAddresses(w.agent); !stringSliceEq(w.addrs, addrs) {
 			logger.Debugf("API addresses changed in agent config")
+			if w.hub != nil {
+				w.hub.Publish(agentpubsub.APIAddressesChangedTopic, agentpubsub.APIAddresses{
+					Addresses: addrs,
+				})
+			}
 			return dependency.ErrBounce
 		}
 
@@ -5,7 +5,9 @@ package apiconfigwatcher_test
 
 import (
 	"sync"
+	"time"
 
+	"github.com/juju/pubsub"
 	"github.com/juju/testing"
 	jc "github.com/juju/testing/checkers"
 	"github.com/juju/utils/voyeur"
@@ -13,6 +15,8 @@ import (
 	worker "gopkg.in/juju/worker.v1"
 
 	"github.com/juju/juju/agent"
+	agentpubsub "github.com/juju/juju/pubsub/agent"
+	coretesting "github.com/juju/juju/testing"
 	"github.com/juju/juju/worker/apiconfigwatcher"
 	"github.com/juju/juju/worker/dependency"
 	dt "github.com/juju/juju/worker/dependency/testing"
@@ -89,6 +93,35 @@ func (s *ManifoldSuite) TestBounceOnChange(c *gc.C) {
 	c.Assert(err, gc.Equals, dependency.ErrBounce)
 }
 
+func (s *ManifoldSuite) TestPublishOnChange(c *gc.C) {
+	hub := pubsub.NewSimpleHub(nil)
+	published := make(chan interface{}, 1)
+	unsubscribe := hub.Subscribe(agentpubsub.APIAddressesChangedTopic, func(_ string, data interface{}) {
+		published <- data
+	})
+	defer unsubscribe()
+	s.manifold = apiconfigwatcher.Manifold(apiconfigwatcher.ManifoldConfig{
+		AgentName:          "agent",
+		AgentConfigChanged: s.agentConfigChanged,
+		Hub:                hub,
+	})
+	s.agent.conf.setAddresses("1.1.1.1:1")
+	w := s.startWorkerClean(c)
+
+	s.agent.conf.setAddresses("2.2.2.2:2", "1.1.1.1:1")
+	s.agentConfigChanged.Set(0)
+	err := workertest.CheckKilled(c, w)
+	c.Assert(err, gc.Equals, dependency.ErrBounce)
+	select {
+	case data := <-published:
+		c.Check(data, jc.DeepEquals, agentpubsub.APIAddresses{
+			Addresses: []string{"1.1.1.1:1", "2.2.2.2:2"},
+		})
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("address change not published")
+	}
+}
+
 func (s *ManifoldSuite) TestConfigChangeWithNoAddrChange(c *gc.C) {
 	s.agent.conf.setAddresses("1.1.1.1:1")
 	w := s.startWorkerClean(c)
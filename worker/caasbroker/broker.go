@@ -9,6 +9,7 @@ import (
 
 	"github.com/juju/juju/caas"
 	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/worker/catacomb"
 )
 
@@ -18,6 +19,7 @@ var logger = loggo.GetLogger("juju.worker.caas")
 // that allows clients to be informed of changes to the configuration.
 type ConfigObserver interface {
 	CloudSpec() (environs.CloudSpec, error)
+	ModelConfig() (*config.Config, error)
 }
 
 // Config describes the dependencies of a Tracker.
@@ -61,7 +63,14 @@ func NewTracker(config Config) (*Tracker, error) {
 	if err != nil {
 		return nil, errors.Annotate(err, "cannot get cloud information")
 	}
-	broker, err := config.NewContainerBrokerFunc(cloudSpec)
+	modelConfig, err := config.Observer.ModelConfig()
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot read model config")
+	}
+	broker, err := config.NewContainerBrokerFunc(environs.OpenParams{
+		Cloud:  cloudSpec,
+		Config: modelConfig,
+	})
 	if err != nil {
 		return nil, errors.Annotate(err, "cannot create caas broker")
 	}
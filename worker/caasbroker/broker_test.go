@@ -90,13 +90,49 @@ func (s *TrackerSuite) TestCloudSpec(c *gc.C) {
 	fix.Run(c, func(context *runContext) {
 		tracker, err := caasbroker.NewTracker(caasbroker.Config{
 			Observer: context,
-			NewContainerBrokerFunc: func(spec environs.CloudSpec) (caas.Broker, error) {
-				c.Assert(spec, jc.DeepEquals, cloudSpec)
+			NewContainerBrokerFunc: func(args environs.OpenParams) (caas.Broker, error) {
+				c.Assert(args.Cloud, jc.DeepEquals, cloudSpec)
 				return nil, errors.NotValidf("cloud spec")
 			},
 		})
 		c.Check(err, gc.ErrorMatches, `cannot create caas broker: cloud spec not valid`)
 		c.Check(tracker, gc.IsNil)
-		context.CheckCallNames(c, "CloudSpec")
+		context.CheckCallNames(c, "CloudSpec", "ModelConfig")
+	})
+}
+
+func (s *TrackerSuite) TestModelConfigFails(c *gc.C) {
+	fix := &fixture{
+		observerErrs: []error{
+			nil, // CloudSpec
+			errors.New("no config"),
+		},
+	}
+	fix.Run(c, func(context *runContext) {
+		tracker, err := caasbroker.NewTracker(caasbroker.Config{
+			Observer:               context,
+			NewContainerBrokerFunc: newMockBroker,
+		})
+		c.Check(err, gc.ErrorMatches, "cannot read model config: no config")
+		c.Check(tracker, gc.IsNil)
+		context.CheckCallNames(c, "CloudSpec", "ModelConfig")
+	})
+}
+
+func (s *TrackerSuite) TestModelConfig(c *gc.C) {
+	fix := &fixture{
+		initialConfig: coretesting.Attrs{"name": "some-model"},
+	}
+	fix.Run(c, func(context *runContext) {
+		tracker, err := caasbroker.NewTracker(caasbroker.Config{
+			Observer: context,
+			NewContainerBrokerFunc: func(args environs.OpenParams) (caas.Broker, error) {
+				c.Assert(args.Config, gc.NotNil)
+				c.Check(args.Config.Name(), gc.Equals, "some-model")
+				return nil, errors.NotValidf("model config")
+			},
+		})
+		c.Check(err, gc.ErrorMatches, `cannot create caas broker: model config not valid`)
+		c.Check(tracker, gc.IsNil)
 	})
 }
@@ -11,17 +11,21 @@ import (
 
 	"github.com/juju/juju/caas"
 	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/config"
+	coretesting "github.com/juju/juju/testing"
 )
 
 type fixture struct {
-	watcherErr   error
-	observerErrs []error
-	cloud        environs.CloudSpec
+	watcherErr    error
+	observerErrs  []error
+	cloud         environs.CloudSpec
+	initialConfig map[string]interface{}
 }
 
 func (fix *fixture) Run(c *gc.C, test func(*runContext)) {
 	context := &runContext{
-		cloud: fix.cloud,
+		cloud:  fix.cloud,
+		config: newModelConfig(c, fix.initialConfig),
 	}
 	context.stub.SetErrors(fix.observerErrs...)
 	test(context)
@@ -44,19 +48,34 @@ func (context *runContext) CloudSpec() (environs.CloudSpec, error) {
 	return context.cloud, nil
 }
 
+func (context *runContext) ModelConfig() (*config.Config, error) {
+	context.mu.Lock()
+	defer context.mu.Unlock()
+	context.stub.AddCall("ModelConfig")
+	if err := context.stub.NextErr(); err != nil {
+		return nil, err
+	}
+	return config.New(config.NoDefaults, context.config)
+}
+
 func (context *runContext) CheckCallNames(c *gc.C, names ...string) {
 	context.mu.Lock()
 	defer context.mu.Unlock()
 	context.stub.CheckCallNames(c, names...)
 }
 
+func newModelConfig(c *gc.C, extraAttrs coretesting.Attrs) map[string]interface{} {
+	return coretesting.CustomModelConfig(c, extraAttrs).AllAttrs()
+}
+
 type mockBroker struct {
 	caas.Broker
 	testing.Stub
 	spec environs.CloudSpec
+	cfg  *config.Config
 	mu   sync.Mutex
 }
 
-func newMockBroker(spec environs.CloudSpec) (caas.Broker, error) {
-	return &mockBroker{spec: spec}, nil
+func newMockBroker(args environs.OpenParams) (caas.Broker, error) {
+	return &mockBroker{spec: args.Cloud, cfg: args.Config}, nil
 }
@@ -0,0 +1,78 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package caasoperatorupgrader
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/utils/clock"
+	"gopkg.in/juju/worker.v1"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/api/caasoperatorprovisioner"
+	"github.com/juju/juju/caas"
+	"github.com/juju/juju/worker/dependency"
+)
+
+// ManifoldConfig defines a CAAS operator upgrader's dependencies.
+type ManifoldConfig struct {
+	APICallerName string
+	BrokerName    string
+	Clock         clock.Clock
+
+	NewWorker func(Config) (worker.Worker, error)
+}
+
+// Validate is called by start to check for bad configuration.
+func (config ManifoldConfig) Validate() error {
+	if config.APICallerName == "" {
+		return errors.NotValidf("empty APICallerName")
+	}
+	if config.BrokerName == "" {
+		return errors.NotValidf("empty BrokerName")
+	}
+	if config.Clock == nil {
+		return errors.NotValidf("nil Clock")
+	}
+	if config.NewWorker == nil {
+		return errors.NotValidf("nil NewWorker")
+	}
+	return nil
+}
+
+func (config ManifoldConfig) start(context dependency.Context) (worker.Worker, error) {
+	if err := config.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	var apiCaller base.APICaller
+	if err := context.Get(config.APICallerName, &apiCaller); err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	var broker caas.Broker
+	if err := context.Get(config.BrokerName, &broker); err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	w, err := config.NewWorker(Config{
+		Facade: caasoperatorprovisioner.NewClient(apiCaller),
+		Broker: broker,
+		Clock:  config.Clock,
+	})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return w, nil
+}
+
+// Manifold creates a manifold that runs a CAAS operator upgrader.
+func Manifold(config ManifoldConfig) dependency.Manifold {
+	return dependency.Manifold{
+		Inputs: []string{
+			config.APICallerName,
+			config.BrokerName,
+		},
+		Start: config.start,
+	}
+}
@@ -0,0 +1,181 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package caasoperatorupgrader
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"github.com/juju/utils/clock"
+	"github.com/juju/utils/set"
+	"github.com/juju/version"
+	"gopkg.in/juju/worker.v1"
+
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/watcher"
+	"github.com/juju/juju/worker/catacomb"
+)
+
+var logger = loggo.GetLogger("juju.workers.caasoperatorupgrader")
+
+// retryDelay is how long the worker waits before retrying
+// the upgrade of operators which could not be found.
+const retryDelay = time.Minute
+
+// Facade exposes the functionality required to watch
+// CAAS applications and the model's agent version.
+type Facade interface {
+	WatchApplications() (watcher.StringsWatcher, error)
+	WatchForModelConfigChanges() (watcher.NotifyWatcher, error)
+	ModelConfig() (*config.Config, error)
+}
+
+// Broker exposes the functionality required to
+// upgrade CAAS operator pods.
+type Broker interface {
+	UpgradeOperator(appName string, vers version.Number) error
+}
+
+// Config holds configuration for the CAAS operator upgrader worker.
+type Config struct {
+	Facade Facade
+	Broker Broker
+	Clock  clock.Clock
+}
+
+// Validate validates the worker configuration.
+func (config Config) Validate() error {
+	if config.Facade == nil {
+		return errors.NotValidf("missing Facade")
+	}
+	if config.Broker == nil {
+		return errors.NotValidf("missing Broker")
+	}
+	if config.Clock == nil {
+		return errors.NotValidf("missing Clock")
+	}
+	return nil
+}
+
+// NewWorker starts and returns a new CAAS operator upgrader worker,
+// which rolls the operator pods of all applications in the model
+// whenever the model's agent version changes.
+func NewWorker(config Config) (worker.Worker, error) {
+	if err := config.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	u := &upgrader{config: config}
+	err := catacomb.Invoke(catacomb.Plan{
+		Site: &u.catacomb,
+		Work: u.loop,
+	})
+	return u, err
+}
+
+type upgrader struct {
+	catacomb catacomb.Catacomb
+	config   Config
+}
+
+// Kill is part of the worker.Worker interface.
+func (u *upgrader) Kill() {
+	u.catacomb.Kill(nil)
+}
+
+// Wait is part of the worker.Worker interface.
+func (u *upgrader) Wait() error {
+	return u.catacomb.Wait()
+}
+
+func (u *upgrader) loop() error {
+	appsWatcher, err := u.config.Facade.WatchApplications()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err := u.catacomb.Add(appsWatcher); err != nil {
+		return errors.Trace(err)
+	}
+	configWatcher, err := u.config.Facade.WatchForModelConfigChanges()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err := u.catacomb.Add(configWatcher); err != nil {
+		return errors.Trace(err)
+	}
+
+	apps := make(set.Strings)
+	// pending holds the applications whose operators could not
+	// be found when they were last upgraded. The operator may
+	// not have been created yet, so the upgrade is retried.
+	pending := make(set.Strings)
+	var agentVersion version.Number
+	var retry <-chan time.Time
+	for {
+		select {
+		case <-u.catacomb.Dying():
+			return u.catacomb.ErrDying()
+		case changes, ok := <-appsWatcher.Changes():
+			if !ok {
+				return errors.New("watcher closed channel")
+			}
+			for _, app := range changes {
+				apps.Add(app)
+			}
+			if agentVersion == version.Zero {
+				continue
+			}
+			appNames := set.NewStrings(changes...).Union(pending)
+			if err := u.upgradeOperators(appNames.SortedValues(), agentVersion, pending); err != nil {
+				return errors.Trace(err)
+			}
+		case _, ok := <-configWatcher.Changes():
+			if !ok {
+				return errors.New("watcher closed channel")
+			}
+			cfg, err := u.config.Facade.ModelConfig()
+			if err != nil {
+				return errors.Trace(err)
+			}
+			vers, ok := cfg.AgentVersion()
+			if !ok || vers == agentVersion {
+				continue
+			}
+			logger.Debugf("model agent version changed to %v", vers)
+			agentVersion = vers
+			pending = make(set.Strings)
+			if err := u.upgradeOperators(apps.SortedValues(), agentVersion, pending); err != nil {
+				return errors.Trace(err)
+			}
+		case <-retry:
+			retry = nil
+			if err := u.upgradeOperators(pending.SortedValues(), agentVersion, pending); err != nil {
+				return errors.Trace(err)
+			}
+		}
+		if retry == nil && !pending.IsEmpty() {
+			retry = u.config.Clock.After(retryDelay)
+		}
+	}
+}
+
+// upgradeOperators upgrades the operators of the specified applications
+// to the specified version. Applications whose operators could not be
+// found are added to the pending set, so that they can be retried, and
+// the others are removed from it.
+func (u *upgrader) upgradeOperators(appNames []string, vers version.Number, pending set.Strings) error {
+	for _, app := range appNames {
+		err := u.config.Broker.UpgradeOperator(app, vers)
+		if errors.IsNotFound(err) {
+			logger.Debugf("operator for %q not found, will retry upgrade", app)
+			pending.Add(app)
+			continue
+		}
+		if err != nil {
+			return errors.Annotatef(err, "upgrading operator for %q", app)
+		}
+		pending.Remove(app)
+	}
+	return nil
+}
@@ -0,0 +1,204 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package caasoperatorupgrader_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/version"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/environs/config"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/watcher"
+	"github.com/juju/juju/watcher/watchertest"
+	"github.com/juju/juju/worker/caasoperatorupgrader"
+	"github.com/juju/juju/worker/workertest"
+)
+
+type WorkerSuite struct {
+	testing.IsolationSuite
+
+	facade *mockFacade
+	broker *mockBroker
+	clock  *testing.Clock
+
+	appChanges    chan []string
+	configChanges chan struct{}
+}
+
+var _ = gc.Suite(&WorkerSuite{})
+
+func (s *WorkerSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+
+	s.appChanges = make(chan []string)
+	s.configChanges = make(chan struct{})
+	s.facade = &mockFacade{
+		appsWatcher:   watchertest.NewMockStringsWatcher(s.appChanges),
+		configWatcher: watchertest.NewMockNotifyWatcher(s.configChanges),
+		config:        coretesting.CustomModelConfig(c, coretesting.Attrs{"agent-version": "2.4.0"}),
+	}
+	s.broker = &mockBroker{upgraded: make(chan struct{}, 10)}
+	s.clock = testing.NewClock(time.Time{})
+}
+
+func (s *WorkerSuite) TestValidateConfig(c *gc.C) {
+	_, err := caasoperatorupgrader.NewWorker(caasoperatorupgrader.Config{Broker: s.broker, Clock: s.clock})
+	c.Check(err, gc.ErrorMatches, "missing Facade not valid")
+	_, err = caasoperatorupgrader.NewWorker(caasoperatorupgrader.Config{Facade: s.facade, Clock: s.clock})
+	c.Check(err, gc.ErrorMatches, "missing Broker not valid")
+	_, err = caasoperatorupgrader.NewWorker(caasoperatorupgrader.Config{Facade: s.facade, Broker: s.broker})
+	c.Check(err, gc.ErrorMatches, "missing Clock not valid")
+}
+
+func (s *WorkerSuite) startWorker(c *gc.C) {
+	w, err := caasoperatorupgrader.NewWorker(caasoperatorupgrader.Config{
+		Facade: s.facade,
+		Broker: s.broker,
+		Clock:  s.clock,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.AddCleanup(func(c *gc.C) { workertest.CleanKill(c, w) })
+}
+
+func (s *WorkerSuite) sendAppChange(c *gc.C, apps ...string) {
+	select {
+	case s.appChanges <- apps:
+	case <-time.After(coretesting.LongWait):
+		c.Fatal("timed out sending applications change")
+	}
+}
+
+func (s *WorkerSuite) sendConfigChange(c *gc.C) {
+	select {
+	case s.configChanges <- struct{}{}:
+	case <-time.After(coretesting.LongWait):
+		c.Fatal("timed out sending config change")
+	}
+}
+
+func (s *WorkerSuite) assertUpgraded(c *gc.C, count int) {
+	for i := 0; i < count; i++ {
+		select {
+		case <-s.broker.upgraded:
+		case <-time.After(coretesting.LongWait):
+			c.Fatal("timed out waiting for operator upgrade")
+		}
+	}
+}
+
+func (s *WorkerSuite) TestUpgradesOnVersionChange(c *gc.C) {
+	s.startWorker(c)
+	s.sendAppChange(c, "gitlab", "mysql")
+	s.sendConfigChange(c)
+	s.assertUpgraded(c, 2)
+	s.broker.CheckCalls(c, []testing.StubCall{
+		{"UpgradeOperator", []interface{}{"gitlab", version.MustParse("2.4.0")}},
+		{"UpgradeOperator", []interface{}{"mysql", version.MustParse("2.4.0")}},
+	})
+
+	// The same version again does nothing.
+	s.broker.ResetCalls()
+	s.sendConfigChange(c)
+	select {
+	case <-s.broker.upgraded:
+		c.Fatal("unexpected operator upgrade")
+	case <-time.After(coretesting.ShortWait):
+	}
+	s.broker.CheckNoCalls(c)
+}
+
+func (s *WorkerSuite) TestNewApplicationUpgraded(c *gc.C) {
+	s.startWorker(c)
+	s.sendConfigChange(c)
+	s.sendAppChange(c, "gitlab")
+	s.assertUpgraded(c, 1)
+	s.broker.CheckCall(c, 0, "UpgradeOperator", "gitlab", version.MustParse("2.4.0"))
+}
+
+func (s *WorkerSuite) TestMissingOperatorRetriedOnChange(c *gc.C) {
+	s.broker.SetErrors(errors.NotFoundf("operator"))
+	s.startWorker(c)
+	s.sendAppChange(c, "gitlab", "mysql")
+	s.sendConfigChange(c)
+	s.assertUpgraded(c, 2)
+
+	// The missing operator is retried along with the changed application.
+	s.broker.ResetCalls()
+	s.sendAppChange(c, "mysql")
+	s.assertUpgraded(c, 2)
+	s.broker.CheckCalls(c, []testing.StubCall{
+		{"UpgradeOperator", []interface{}{"gitlab", version.MustParse("2.4.0")}},
+		{"UpgradeOperator", []interface{}{"mysql", version.MustParse("2.4.0")}},
+	})
+
+	// Once upgraded, it is not retried again.
+	s.broker.ResetCalls()
+	s.sendAppChange(c, "mysql")
+	s.assertUpgraded(c, 1)
+	s.broker.CheckCalls(c, []testing.StubCall{
+		{"UpgradeOperator", []interface{}{"mysql", version.MustParse("2.4.0")}},
+	})
+}
+
+func (s *WorkerSuite) TestMissingOperatorRetriedAfterDelay(c *gc.C) {
+	s.broker.SetErrors(errors.NotFoundf("operator"))
+	s.startWorker(c)
+	s.sendAppChange(c, "gitlab", "mysql")
+	s.sendConfigChange(c)
+	s.assertUpgraded(c, 2)
+
+	s.broker.ResetCalls()
+	err := s.clock.WaitAdvance(caasoperatorupgrader.RetryDelay, coretesting.LongWait, 1)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertUpgraded(c, 1)
+	s.broker.CheckCalls(c, []testing.StubCall{
+		{"UpgradeOperator", []interface{}{"gitlab", version.MustParse("2.4.0")}},
+	})
+
+	// Once upgraded, there is nothing left to retry.
+	s.clock.Advance(caasoperatorupgrader.RetryDelay)
+	select {
+	case <-s.broker.upgraded:
+		c.Fatal("unexpected operator upgrade")
+	case <-time.After(coretesting.ShortWait):
+	}
+}
+
+type mockFacade struct {
+	testing.Stub
+	appsWatcher   *watchertest.MockStringsWatcher
+	configWatcher *watchertest.MockNotifyWatcher
+	config        *config.Config
+}
+
+func (m *mockFacade) WatchApplications() (watcher.StringsWatcher, error) {
+	m.MethodCall(m, "WatchApplications")
+	return m.appsWatcher, m.NextErr()
+}
+
+func (m *mockFacade) WatchForModelConfigChanges() (watcher.NotifyWatcher, error) {
+	m.MethodCall(m, "WatchForModelConfigChanges")
+	return m.configWatcher, m.NextErr()
+}
+
+func (m *mockFacade) ModelConfig() (*config.Config, error) {
+	m.MethodCall(m, "ModelConfig")
+	return m.config, m.NextErr()
+}
+
+type mockBroker struct {
+	testing.Stub
+	upgraded chan struct{}
+}
+
+func (m *mockBroker) UpgradeOperator(appName string, vers version.Number) error {
+	m.MethodCall(m, "UpgradeOperator", appName, vers)
+	m.upgraded <- struct{}{}
+	return m.NextErr()
+}
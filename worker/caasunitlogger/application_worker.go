@@ -0,0 +1,113 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package caasunitlogger
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"gopkg.in/juju/names.v2"
+	"gopkg.in/juju/worker.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/caas"
+	"github.com/juju/juju/worker/catacomb"
+)
+
+// workloadModule is the logging module recorded
+// for the output of an application's containers.
+const workloadModule = "workload"
+
+type applicationWorker struct {
+	catacomb    catacomb.Catacomb
+	application string
+	logsBroker  LogsBroker
+	since       time.Time
+	out         chan<- []*params.LogRecord
+}
+
+func newApplicationWorker(
+	application string,
+	logsBroker LogsBroker,
+	since time.Time,
+	out chan<- []*params.LogRecord,
+) (worker.Worker, error) {
+	w := &applicationWorker{
+		application: application,
+		logsBroker:  logsBroker,
+		since:       since,
+		out:         out,
+	}
+	if err := catacomb.Invoke(catacomb.Plan{
+		Site: &w.catacomb,
+		Work: w.loop,
+	}); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return w, nil
+}
+
+// Kill is part of the worker.Worker interface.
+func (w *applicationWorker) Kill() {
+	w.catacomb.Kill(nil)
+}
+
+// Wait is part of the worker.Worker interface.
+func (w *applicationWorker) Wait() error {
+	return w.catacomb.Wait()
+}
+
+func (aw *applicationWorker) loop() error {
+	logsWatcher, err := aw.logsBroker.WatchLogs(aw.application, aw.since)
+	if errors.IsNotSupported(err) {
+		logger.Infof("not streaming logs of %q: %v", aw.application, err)
+		return nil
+	}
+	if err != nil {
+		return errors.Annotatef(err, "streaming logs of %q", aw.application)
+	}
+	if err := aw.catacomb.Add(logsWatcher); err != nil {
+		return errors.Trace(err)
+	}
+
+	for {
+		select {
+		case <-aw.catacomb.Dying():
+			return aw.catacomb.ErrDying()
+		case records, ok := <-logsWatcher.Changes():
+			if !ok {
+				return errors.New("logs watcher closed")
+			}
+			select {
+			case aw.out <- aw.logRecords(records):
+			case <-aw.catacomb.Dying():
+				return aw.catacomb.ErrDying()
+			}
+		}
+	}
+}
+
+// logRecords converts the output of the application's containers
+// into records for the log sink. Output of a unit's pod is recorded
+// against the unit; other pods of the application, such as those
+// managed by the substrate, are recorded against the application.
+func (aw *applicationWorker) logRecords(records []caas.LogRecord) []*params.LogRecord {
+	result := make([]*params.LogRecord, len(records))
+	for i, r := range records {
+		var entity names.Tag = names.NewApplicationTag(aw.application)
+		if names.IsValidUnit(r.UnitName) {
+			entity = names.NewUnitTag(r.UnitName)
+		}
+		result[i] = &params.LogRecord{
+			Time:     r.Time,
+			Module:   workloadModule,
+			Location: r.PodName + "/" + r.ContainerName,
+			Level:    loggo.INFO.String(),
+			Message:  r.Message,
+			Entity:   entity.String(),
+		}
+	}
+	return result
+}
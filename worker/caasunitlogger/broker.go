@@ -0,0 +1,16 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package caasunitlogger
+
+import (
+	"time"
+
+	"github.com/juju/juju/caas"
+)
+
+// LogsBroker provides an interface for streaming the
+// output of the containers of an application's pods.
+type LogsBroker interface {
+	WatchLogs(appName string, since time.Time) (caas.LogsWatcher, error)
+}
@@ -0,0 +1,31 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package caasunitlogger
+
+import (
+	"github.com/juju/juju/core/life"
+	"github.com/juju/juju/watcher"
+)
+
+// Client provides an interface for interacting with the
+// CAASFirewaller API. Subsets of this should be passed
+// to the CAASUnitLogger worker.
+type Client interface {
+	ApplicationGetter
+	LifeGetter
+}
+
+// ApplicationGetter provides an interface for
+// watching for the lifecycle state changes
+// (including addition) of applications in the
+// model.
+type ApplicationGetter interface {
+	WatchApplications() (watcher.StringsWatcher, error)
+}
+
+// LifeGetter provides an interface for getting the
+// lifecycle state value for an application.
+type LifeGetter interface {
+	Life(string) (life.Value, error)
+}
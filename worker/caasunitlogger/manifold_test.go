@@ -0,0 +1,170 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package caasunitlogger_test
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/utils/clock"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/worker.v1"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/api/logsender"
+	"github.com/juju/juju/worker/caasunitlogger"
+	"github.com/juju/juju/worker/dependency"
+	dt "github.com/juju/juju/worker/dependency/testing"
+	"github.com/juju/juju/worker/workertest"
+)
+
+type ManifoldSuite struct {
+	testing.IsolationSuite
+	testing.Stub
+	manifold dependency.Manifold
+	context  dependency.Context
+
+	apiCaller fakeAPICaller
+	broker    fakeBroker
+	client    fakeClient
+	logWriter fakeLogWriter
+}
+
+var _ = gc.Suite(&ManifoldSuite{})
+
+func (s *ManifoldSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.ResetCalls()
+	s.logWriter = fakeLogWriter{}
+
+	s.context = s.newContext(nil)
+	s.manifold = caasunitlogger.Manifold(s.validConfig())
+}
+
+func (s *ManifoldSuite) validConfig() caasunitlogger.ManifoldConfig {
+	return caasunitlogger.ManifoldConfig{
+		APICallerName: "api-caller",
+		BrokerName:    "broker",
+		Clock:         clock.WallClock,
+		NewClient:     s.newClient,
+		NewLogWriter:  s.newLogWriter,
+		NewWorker:     s.newWorker,
+	}
+}
+
+func (s *ManifoldSuite) newClient(apiCaller base.APICaller) caasunitlogger.Client {
+	s.MethodCall(s, "NewClient", apiCaller)
+	return &s.client
+}
+
+func (s *ManifoldSuite) newLogWriter(apiCaller base.APICaller) (logsender.LogWriter, error) {
+	s.MethodCall(s, "NewLogWriter", apiCaller)
+	if err := s.NextErr(); err != nil {
+		return nil, err
+	}
+	return &s.logWriter, nil
+}
+
+func (s *ManifoldSuite) newWorker(config caasunitlogger.Config) (worker.Worker, error) {
+	s.MethodCall(s, "NewWorker", config)
+	if err := s.NextErr(); err != nil {
+		return nil, err
+	}
+	w := worker.NewRunner(worker.RunnerParams{})
+	s.AddCleanup(func(c *gc.C) { workertest.DirtyKill(c, w) })
+	return w, nil
+}
+
+func (s *ManifoldSuite) newContext(overlay map[string]interface{}) dependency.Context {
+	resources := map[string]interface{}{
+		"api-caller": &s.apiCaller,
+		"broker":     &s.broker,
+	}
+	for k, v := range overlay {
+		resources[k] = v
+	}
+	return dt.StubContext(nil, resources)
+}
+
+func (s *ManifoldSuite) TestMissingAPICallerName(c *gc.C) {
+	config := s.validConfig()
+	config.APICallerName = ""
+	s.checkConfigInvalid(c, config, "empty APICallerName not valid")
+}
+
+func (s *ManifoldSuite) TestMissingBrokerName(c *gc.C) {
+	config := s.validConfig()
+	config.BrokerName = ""
+	s.checkConfigInvalid(c, config, "empty BrokerName not valid")
+}
+
+func (s *ManifoldSuite) TestMissingClock(c *gc.C) {
+	config := s.validConfig()
+	config.Clock = nil
+	s.checkConfigInvalid(c, config, "nil Clock not valid")
+}
+
+func (s *ManifoldSuite) TestMissingNewLogWriter(c *gc.C) {
+	config := s.validConfig()
+	config.NewLogWriter = nil
+	s.checkConfigInvalid(c, config, "nil NewLogWriter not valid")
+}
+
+func (s *ManifoldSuite) TestMissingNewWorker(c *gc.C) {
+	config := s.validConfig()
+	config.NewWorker = nil
+	s.checkConfigInvalid(c, config, "nil NewWorker not valid")
+}
+
+func (s *ManifoldSuite) checkConfigInvalid(c *gc.C, config caasunitlogger.ManifoldConfig, expect string) {
+	err := config.Validate()
+	c.Check(err, gc.ErrorMatches, expect)
+	c.Check(err, jc.Satisfies, errors.IsNotValid)
+}
+
+var expectedInputs = []string{"api-caller", "broker"}
+
+func (s *ManifoldSuite) TestInputs(c *gc.C) {
+	c.Assert(s.manifold.Inputs, jc.SameContents, expectedInputs)
+}
+
+func (s *ManifoldSuite) TestMissingInputs(c *gc.C) {
+	for _, input := range expectedInputs {
+		context := s.newContext(map[string]interface{}{
+			input: dependency.ErrMissing,
+		})
+		_, err := s.manifold.Start(context)
+		c.Assert(errors.Cause(err), gc.Equals, dependency.ErrMissing)
+	}
+}
+
+func (s *ManifoldSuite) TestStart(c *gc.C) {
+	w, err := s.manifold.Start(s.context)
+	c.Assert(err, jc.ErrorIsNil)
+	workertest.CleanKill(c, w)
+
+	s.CheckCallNames(c, "NewLogWriter", "NewClient", "NewWorker")
+	s.CheckCall(c, 0, "NewLogWriter", &s.apiCaller)
+	s.CheckCall(c, 1, "NewClient", &s.apiCaller)
+
+	args := s.Calls()[2].Args
+	c.Assert(args, gc.HasLen, 1)
+	c.Assert(args[0], gc.FitsTypeOf, caasunitlogger.Config{})
+	config := args[0].(caasunitlogger.Config)
+
+	c.Assert(config, jc.DeepEquals, caasunitlogger.Config{
+		ApplicationGetter: &s.client,
+		LifeGetter:        &s.client,
+		LogsBroker:        &s.broker,
+		LogWriter:         &s.logWriter,
+		Clock:             clock.WallClock,
+	})
+}
+
+func (s *ManifoldSuite) TestStartErrorClosesLogWriter(c *gc.C) {
+	s.SetErrors(nil, errors.New("boom"))
+	_, err := s.manifold.Start(s.context)
+	c.Assert(err, gc.ErrorMatches, "boom")
+	c.Assert(s.logWriter.closed, jc.IsTrue)
+}
@@ -0,0 +1,130 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package caasunitlogger_test
+
+import (
+	"time"
+
+	"github.com/juju/testing"
+	"gopkg.in/tomb.v1"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/api/logsender"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/caas"
+	"github.com/juju/juju/core/life"
+	"github.com/juju/juju/watcher"
+	"github.com/juju/juju/watcher/watchertest"
+	"github.com/juju/juju/worker/caasunitlogger"
+)
+
+type fakeAPICaller struct {
+	base.APICaller
+}
+
+type fakeBroker struct {
+	caas.Broker
+}
+
+type fakeClient struct {
+	caasunitlogger.Client
+}
+
+type fakeLogWriter struct {
+	logsender.LogWriter
+	closed bool
+}
+
+func (w *fakeLogWriter) Close() error {
+	w.closed = true
+	return nil
+}
+
+type mockApplicationGetter struct {
+	testing.Stub
+	allWatcher *watchertest.MockStringsWatcher
+}
+
+func (m *mockApplicationGetter) WatchApplications() (watcher.StringsWatcher, error) {
+	m.MethodCall(m, "WatchApplications")
+	if err := m.NextErr(); err != nil {
+		return nil, err
+	}
+	return m.allWatcher, nil
+}
+
+type mockLifeGetter struct {
+	testing.Stub
+	life life.Value
+}
+
+func (m *mockLifeGetter) Life(entityName string) (life.Value, error) {
+	m.MethodCall(m, "Life", entityName)
+	if err := m.NextErr(); err != nil {
+		return "", err
+	}
+	return m.life, nil
+}
+
+type mockLogsBroker struct {
+	testing.Stub
+	logsWatcher *mockLogsWatcher
+}
+
+func (m *mockLogsBroker) WatchLogs(appName string, since time.Time) (caas.LogsWatcher, error) {
+	m.MethodCall(m, "WatchLogs", appName, since)
+	if err := m.NextErr(); err != nil {
+		return nil, err
+	}
+	return m.logsWatcher, nil
+}
+
+type mockLogWriter struct {
+	testing.Stub
+	records chan<- *params.LogRecord
+}
+
+func (m *mockLogWriter) WriteLog(record *params.LogRecord) error {
+	m.MethodCall(m, "WriteLog", record)
+	m.records <- record
+	return m.NextErr()
+}
+
+func (m *mockLogWriter) Close() error {
+	m.MethodCall(m, "Close")
+	return m.NextErr()
+}
+
+type mockLogsWatcher struct {
+	tomb tomb.Tomb
+	ch   <-chan []caas.LogRecord
+}
+
+func newMockLogsWatcher(ch <-chan []caas.LogRecord) *mockLogsWatcher {
+	w := &mockLogsWatcher{ch: ch}
+	go func() {
+		defer w.tomb.Done()
+		<-w.tomb.Dying()
+		w.tomb.Kill(tomb.ErrDying)
+	}()
+	return w
+}
+
+func (w *mockLogsWatcher) Changes() caas.LogsChannel {
+	return caas.LogsChannel(w.ch)
+}
+
+func (w *mockLogsWatcher) Kill() {
+	w.tomb.Kill(nil)
+}
+
+// KillErr can be used to kill the watcher with
+// an error, to simulate a failing watcher.
+func (w *mockLogsWatcher) KillErr(err error) {
+	w.tomb.Kill(err)
+}
+
+func (w *mockLogsWatcher) Wait() error {
+	return w.tomb.Wait()
+}
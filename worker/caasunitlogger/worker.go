@@ -0,0 +1,145 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package caasunitlogger
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"github.com/juju/utils/clock"
+	"gopkg.in/juju/worker.v1"
+
+	"github.com/juju/juju/api/logsender"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/core/life"
+	"github.com/juju/juju/worker/catacomb"
+)
+
+var logger = loggo.GetLogger("juju.workers.caasunitlogger")
+
+// Config holds configuration for the CAAS unit logger worker.
+type Config struct {
+	ApplicationGetter ApplicationGetter
+	LifeGetter        LifeGetter
+	LogsBroker        LogsBroker
+	LogWriter         logsender.LogWriter
+	Clock             clock.Clock
+}
+
+// Validate validates the worker configuration.
+func (config Config) Validate() error {
+	if config.ApplicationGetter == nil {
+		return errors.NotValidf("missing ApplicationGetter")
+	}
+	if config.LifeGetter == nil {
+		return errors.NotValidf("missing LifeGetter")
+	}
+	if config.LogsBroker == nil {
+		return errors.NotValidf("missing LogsBroker")
+	}
+	if config.LogWriter == nil {
+		return errors.NotValidf("missing LogWriter")
+	}
+	if config.Clock == nil {
+		return errors.NotValidf("missing Clock")
+	}
+	return nil
+}
+
+// NewWorker starts and returns a new CAAS unit logger worker,
+// which writes the output of the containers of the model's
+// application pods to the controller's log sink. The worker
+// closes the configured LogWriter when it stops.
+func NewWorker(config Config) (worker.Worker, error) {
+	if err := config.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	p := &unitLogger{
+		config:  config,
+		records: make(chan []*params.LogRecord),
+	}
+	err := catacomb.Invoke(catacomb.Plan{
+		Site: &p.catacomb,
+		Work: p.loop,
+	})
+	return p, err
+}
+
+type unitLogger struct {
+	catacomb catacomb.Catacomb
+	config   Config
+
+	// records receives the log records of all applications,
+	// so that only the main loop writes to the log sink.
+	records chan []*params.LogRecord
+}
+
+// Kill is part of the worker.Worker interface.
+func (p *unitLogger) Kill() {
+	p.catacomb.Kill(nil)
+}
+
+// Wait is part of the worker.Worker interface.
+func (p *unitLogger) Wait() error {
+	return p.catacomb.Wait()
+}
+
+func (p *unitLogger) loop() error {
+	defer p.config.LogWriter.Close()
+
+	w, err := p.config.ApplicationGetter.WatchApplications()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err := p.catacomb.Add(w); err != nil {
+		return errors.Trace(err)
+	}
+
+	appWorkers := make(map[string]worker.Worker)
+	for {
+		select {
+		case <-p.catacomb.Dying():
+			return p.catacomb.ErrDying()
+		case apps, ok := <-w.Changes():
+			if !ok {
+				return errors.New("watcher closed channel")
+			}
+			for _, appId := range apps {
+				appLife, err := p.config.LifeGetter.Life(appId)
+				if errors.IsNotFound(err) || appLife == life.Dead {
+					w, ok := appWorkers[appId]
+					if ok {
+						if err := worker.Stop(w); err != nil {
+							return errors.Trace(err)
+						}
+						delete(appWorkers, appId)
+					}
+					continue
+				}
+				if err != nil {
+					return errors.Trace(err)
+				}
+				if _, ok := appWorkers[appId]; ok {
+					continue
+				}
+				w, err := newApplicationWorker(
+					appId,
+					p.config.LogsBroker,
+					p.config.Clock.Now(),
+					p.records,
+				)
+				if err != nil {
+					return errors.Trace(err)
+				}
+				appWorkers[appId] = w
+				p.catacomb.Add(w)
+			}
+		case records := <-p.records:
+			for _, record := range records {
+				if err := p.config.LogWriter.WriteLog(record); err != nil {
+					return errors.Annotate(err, "writing workload log")
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,249 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package caasunitlogger_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/caas"
+	"github.com/juju/juju/core/life"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/watcher/watchertest"
+	"github.com/juju/juju/worker/caasunitlogger"
+	"github.com/juju/juju/worker/workertest"
+)
+
+type WorkerSuite struct {
+	testing.IsolationSuite
+
+	config            caasunitlogger.Config
+	applicationGetter mockApplicationGetter
+	lifeGetter        mockLifeGetter
+	logsBroker        mockLogsBroker
+	logWriter         mockLogWriter
+	clock             *testing.Clock
+
+	applicationChanges chan []string
+	logChanges         chan []caas.LogRecord
+	records            chan *params.LogRecord
+}
+
+var _ = gc.Suite(&WorkerSuite{})
+
+func (s *WorkerSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+
+	s.applicationChanges = make(chan []string)
+	s.logChanges = make(chan []caas.LogRecord)
+	s.records = make(chan *params.LogRecord, 10)
+
+	s.applicationGetter = mockApplicationGetter{
+		allWatcher: watchertest.NewMockStringsWatcher(s.applicationChanges),
+	}
+	s.AddCleanup(func(c *gc.C) { workertest.DirtyKill(c, s.applicationGetter.allWatcher) })
+
+	s.lifeGetter = mockLifeGetter{
+		life: life.Alive,
+	}
+	s.logsBroker = mockLogsBroker{
+		logsWatcher: newMockLogsWatcher(s.logChanges),
+	}
+	s.AddCleanup(func(c *gc.C) { workertest.DirtyKill(c, s.logsBroker.logsWatcher) })
+	s.logWriter = mockLogWriter{
+		records: s.records,
+	}
+	s.clock = testing.NewClock(time.Date(2018, 3, 1, 10, 0, 0, 0, time.UTC))
+
+	s.config = caasunitlogger.Config{
+		ApplicationGetter: &s.applicationGetter,
+		LifeGetter:        &s.lifeGetter,
+		LogsBroker:        &s.logsBroker,
+		LogWriter:         &s.logWriter,
+		Clock:             s.clock,
+	}
+}
+
+func (s *WorkerSuite) sendApplicationChange(c *gc.C, apps ...string) {
+	select {
+	case s.applicationChanges <- apps:
+	case <-time.After(coretesting.LongWait):
+		c.Fatal("timed out sending applications change")
+	}
+}
+
+func (s *WorkerSuite) sendLogChange(c *gc.C, records ...caas.LogRecord) {
+	select {
+	case s.logChanges <- records:
+	case <-time.After(coretesting.LongWait):
+		c.Fatal("timed out sending logs change")
+	}
+}
+
+func (s *WorkerSuite) waitRecord(c *gc.C) *params.LogRecord {
+	select {
+	case record := <-s.records:
+		return record
+	case <-time.After(coretesting.LongWait):
+		c.Fatal("timed out waiting for log record")
+	}
+	panic("unreachable")
+}
+
+func (s *WorkerSuite) TestValidateConfig(c *gc.C) {
+	s.testValidateConfig(c, func(config *caasunitlogger.Config) {
+		config.ApplicationGetter = nil
+	}, `missing ApplicationGetter not valid`)
+
+	s.testValidateConfig(c, func(config *caasunitlogger.Config) {
+		config.LifeGetter = nil
+	}, `missing LifeGetter not valid`)
+
+	s.testValidateConfig(c, func(config *caasunitlogger.Config) {
+		config.LogsBroker = nil
+	}, `missing LogsBroker not valid`)
+
+	s.testValidateConfig(c, func(config *caasunitlogger.Config) {
+		config.LogWriter = nil
+	}, `missing LogWriter not valid`)
+
+	s.testValidateConfig(c, func(config *caasunitlogger.Config) {
+		config.Clock = nil
+	}, `missing Clock not valid`)
+}
+
+func (s *WorkerSuite) testValidateConfig(c *gc.C, f func(*caasunitlogger.Config), expect string) {
+	config := s.config
+	f(&config)
+	w, err := caasunitlogger.NewWorker(config)
+	if err == nil {
+		workertest.DirtyKill(c, w)
+	}
+	c.Check(err, gc.ErrorMatches, expect)
+}
+
+func (s *WorkerSuite) TestStartStop(c *gc.C) {
+	w, err := caasunitlogger.NewWorker(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	workertest.CheckAlive(c, w)
+	workertest.CleanKill(c, w)
+	s.logWriter.CheckCallNames(c, "Close")
+}
+
+func (s *WorkerSuite) TestWritesLogs(c *gc.C) {
+	w, err := caasunitlogger.NewWorker(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.CleanKill(c, w)
+
+	s.sendApplicationChange(c, "gitlab")
+	t := time.Date(2018, 3, 1, 10, 0, 1, 0, time.UTC)
+	s.sendLogChange(c, caas.LogRecord{
+		UnitId:        "uid-1",
+		UnitName:      "gitlab/0",
+		PodName:       "juju-gitlab-0",
+		ContainerName: "gitlab",
+		Time:          t,
+		Message:       "started",
+	}, caas.LogRecord{
+		UnitId:        "uid-2",
+		PodName:       "gitlab-db-1",
+		ContainerName: "db",
+		Time:          t,
+		Message:       "ready",
+	})
+
+	c.Assert(s.waitRecord(c), jc.DeepEquals, &params.LogRecord{
+		Time:     t,
+		Module:   "workload",
+		Location: "juju-gitlab-0/gitlab",
+		Level:    "INFO",
+		Message:  "started",
+		Entity:   "unit-gitlab-0",
+	})
+	c.Assert(s.waitRecord(c), jc.DeepEquals, &params.LogRecord{
+		Time:     t,
+		Module:   "workload",
+		Location: "gitlab-db-1/db",
+		Level:    "INFO",
+		Message:  "ready",
+		Entity:   "application-gitlab",
+	})
+	s.logsBroker.CheckCall(c, 0, "WatchLogs", "gitlab", s.clock.Now())
+}
+
+func (s *WorkerSuite) TestWatchLogsNotSupported(c *gc.C) {
+	s.logsBroker.SetErrors(errors.NotSupportedf("streaming logs"))
+	w, err := caasunitlogger.NewWorker(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.CleanKill(c, w)
+
+	s.sendApplicationChange(c, "gitlab")
+	for a := coretesting.LongAttempt.Start(); a.Next(); {
+		if len(s.logsBroker.Calls()) > 0 {
+			break
+		}
+	}
+	s.logsBroker.CheckCallNames(c, "WatchLogs")
+	workertest.CheckAlive(c, w)
+}
+
+func (s *WorkerSuite) TestWatchApplicationDead(c *gc.C) {
+	w, err := caasunitlogger.NewWorker(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.CleanKill(c, w)
+
+	s.lifeGetter.life = life.Dead
+	s.sendApplicationChange(c, "gitlab")
+
+	select {
+	case s.logChanges <- nil:
+		c.Fatal("unexpected watch for logs")
+	case <-time.After(coretesting.ShortWait):
+	}
+	s.logsBroker.CheckNoCalls(c)
+}
+
+func (s *WorkerSuite) TestRemoveApplicationStopsWatchingLogs(c *gc.C) {
+	w, err := caasunitlogger.NewWorker(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.CleanKill(c, w)
+
+	s.sendApplicationChange(c, "gitlab")
+	s.lifeGetter.SetErrors(errors.NotFoundf("application"))
+	s.sendApplicationChange(c, "gitlab")
+
+	workertest.CheckKilled(c, s.logsBroker.logsWatcher)
+}
+
+func (s *WorkerSuite) TestWriteErrorStopsWorker(c *gc.C) {
+	s.logWriter.SetErrors(errors.New("splat"))
+	w, err := caasunitlogger.NewWorker(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.DirtyKill(c, w)
+
+	s.sendApplicationChange(c, "gitlab")
+	s.sendLogChange(c, caas.LogRecord{UnitName: "gitlab/0", Message: "hello"})
+
+	err = workertest.CheckKilled(c, w)
+	c.Assert(err, gc.ErrorMatches, "writing workload log: splat")
+	s.logWriter.CheckCallNames(c, "WriteLog", "Close")
+}
+
+func (s *WorkerSuite) TestWatcherErrorStopsWorker(c *gc.C) {
+	w, err := caasunitlogger.NewWorker(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.DirtyKill(c, w)
+
+	s.sendApplicationChange(c, "gitlab")
+	s.logsBroker.logsWatcher.KillErr(errors.New("splat"))
+	workertest.CheckKilled(c, s.logsBroker.logsWatcher)
+	workertest.CheckKilled(c, s.applicationGetter.allWatcher)
+	err = workertest.CheckKilled(c, w)
+	c.Assert(err, gc.ErrorMatches, "splat")
+}
@@ -4,13 +4,17 @@
 package caasunitprovisioner
 
 import (
+	"sort"
+
 	"github.com/juju/errors"
 	"github.com/juju/utils/set"
 	"gopkg.in/juju/names.v2"
 	"gopkg.in/juju/worker.v1"
 
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/caas"
 	"github.com/juju/juju/core/life"
+	"github.com/juju/juju/watcher"
 	"github.com/juju/juju/worker/catacomb"
 )
 
@@ -27,7 +31,9 @@ type applicationWorker struct {
 	unitGetter          UnitGetter
 	unitUpdater         UnitUpdater
 
-	aliveUnitsChan chan []string
+	aliveUnitsChan  chan []string
+	unitSpecsChan   chan caas.UnitSpec
+	specChangesChan chan struct{}
 }
 
 func newApplicationWorker(
@@ -52,6 +58,8 @@ func newApplicationWorker(
 		unitGetter:          unitGetter,
 		unitUpdater:         unitUpdater,
 		aliveUnitsChan:      make(chan []string),
+		unitSpecsChan:       make(chan caas.UnitSpec),
+		specChangesChan:     make(chan struct{}),
 	}
 	if err := catacomb.Invoke(catacomb.Plan{
 		Site: &w.catacomb,
@@ -88,13 +96,29 @@ func (aw *applicationWorker) loop() error {
 	}
 
 	var deploymentWorker worker.Worker
+	var appConfigChanges watcher.NotifyChannel
 	if aw.brokerManagedUnits {
+		// Whether the cloud scales the units depends on the
+		// application config, so we watch it rather than
+		// reading it for every change to the cloud's units.
+		// The watcher also reports changes to the application
+		// constraints, which the service must be updated with.
+		appConfigWatcher, err := aw.applicationGetter.WatchApplicationConfig(aw.application)
+		if err != nil {
+			return errors.Annotatef(err, "failed to start config watcher for %q", aw.application)
+		}
+		if err := aw.catacomb.Add(appConfigWatcher); err != nil {
+			return errors.Trace(err)
+		}
+		appConfigChanges = appConfigWatcher.Changes()
+
 		deploymentWorker, err = newDeploymentWorker(
 			aw.application,
 			aw.serviceBroker,
 			aw.containerSpecGetter,
 			aw.applicationGetter,
-			aw.aliveUnitsChan)
+			aw.aliveUnitsChan,
+			aw.specChangesChan)
 		if err != nil {
 			return errors.Trace(err)
 		}
@@ -102,7 +126,13 @@ func (aw *applicationWorker) loop() error {
 	}
 	unitWorkers := make(map[string]worker.Worker)
 	aliveUnits := make(set.Strings)
+	// cloudUnits holds the units reported by the broker,
+	// keyed on their provider id.
+	cloudUnits := make(map[string]caas.Unit)
 	var aliveUnitsChan chan []string
+	// cloudScaled records whether the cloud scales the units, once
+	// that is known from the application config and the pod spec.
+	var cloudScaled, cloudScaledKnown bool
 
 	for {
 		select {
@@ -117,34 +147,30 @@ func (aw *applicationWorker) loop() error {
 			}
 			for _, unitId := range units {
 				unitLife, err := aw.lifeGetter.Life(unitId)
-				if errors.IsNotFound(err) {
+				if err != nil && !errors.IsNotFound(err) {
+					return errors.Trace(err)
+				}
+				if errors.IsNotFound(err) || unitLife == life.Dead {
 					aliveUnits.Remove(unitId)
-					w, ok := unitWorkers[unitId]
-					if ok {
-						if err := worker.Stop(w); err != nil {
+					if !aw.brokerManagedUnits {
+						// For Juju managed units, the unit's pod is
+						// removed along with the unit. Broker managed
+						// units are removed by scaling the service.
+						if err := aw.removeUnit(unitWorkers, unitId); err != nil {
 							return errors.Trace(err)
 						}
-						delete(unitWorkers, unitId)
 					}
 					continue
 				}
-				if err != nil {
-					return errors.Trace(err)
-				}
 
-				if unitLife == life.Dead {
-					aliveUnits.Remove(unitId)
-				} else {
-					aliveUnits.Add(unitId)
-				}
+				aliveUnits.Add(unitId)
 				if !aw.brokerManagedUnits {
 					// For Juju managed units, we start a worker to manage the unit.
-					if _, ok := unitWorkers[unitId]; ok || unitLife == life.Dead {
-						// Already watching the unit. or we're
-						// not yet watching it and it's dead.
+					if _, ok := unitWorkers[unitId]; ok {
+						// Already watching the unit.
 						continue
 					}
-					w, err := newUnitWorker(aw.application, unitId, aw.containerBroker, aw.containerSpecGetter)
+					w, err := newUnitWorker(aw.application, unitId, aw.containerSpecGetter, aw.applicationGetter, aw.unitSpecsChan)
 					if err != nil {
 						return errors.Trace(err)
 					}
@@ -152,35 +178,179 @@ func (aw *applicationWorker) loop() error {
 					aw.catacomb.Add(w)
 				}
 			}
-		case aliveUnitsChan <- aliveUnits.Values():
-			aliveUnitsChan = nil
-		case _, ok := <-brokerUnitsWatcher.Changes():
-			logger.Debugf("units changed: %#v", ok)
+		case _, ok := <-appConfigChanges:
 			if !ok {
-				return brokerUnitsWatcher.Wait()
+				return errors.New("application config watcher closed channel")
 			}
-			units, err := aw.containerBroker.Units(aw.application)
+			cloudScaled, cloudScaledKnown, err = aw.cloudScaled(aliveUnits.Values())
 			if err != nil {
 				return errors.Trace(err)
 			}
-			logger.Debugf("units for %v: %+v", aw.application, units)
-			args := params.UpdateApplicationUnits{
-				ApplicationTag: names.NewApplicationTag(aw.application).String(),
-				Units:          make([]params.ApplicationUnitParams, len(units)),
+			// Send the alive units again so the deployment
+			// worker updates the service with the changes.
+			if aliveUnits.Size() > 0 {
+				aliveUnitsChan = aw.aliveUnitsChan
+			}
+		case <-aw.specChangesChan:
+			// The charm may request a different deployment mode
+			// in its new pod spec, so whether the cloud scales
+			// the units is found again when next needed.
+			cloudScaledKnown = false
+		case aliveUnitsChan <- aliveUnits.Values():
+			aliveUnitsChan = nil
+		case spec := <-aw.unitSpecsChan:
+			if err := aw.ensureUnits(spec); err != nil {
+				return errors.Trace(err)
+			}
+		case changes, ok := <-brokerUnitsWatcher.Changes():
+			if !ok {
+				return brokerUnitsWatcher.Wait()
+			}
+			for _, change := range changes {
+				logger.Debugf("unit %v of %v %v: %+v", change.Unit.Id, aw.application, change.Kind, change)
+				if change.Kind == caas.UnitRemoved {
+					delete(cloudUnits, change.Unit.Id)
+				} else {
+					cloudUnits[change.Unit.Id] = change.Unit
+				}
 			}
-			for i, u := range units {
-				args.Units[i] = params.ApplicationUnitParams{
-					Id:      u.Id,
-					Address: u.Address,
-					Ports:   u.Ports,
-					Status:  u.Status.Status.String(),
-					Info:    u.Status.Message,
-					Data:    u.Status.Data,
+			if aw.brokerManagedUnits && !cloudScaledKnown {
+				cloudScaled, cloudScaledKnown, err = aw.cloudScaled(aliveUnits.Values())
+				if err != nil {
+					return errors.Trace(err)
 				}
 			}
-			if err := aw.unitUpdater.UpdateUnits(args); err != nil {
+			// The units passed to UpdateUnits are the complete
+			// set of units in the cloud, so we send all of the
+			// units we know about.
+			if err := aw.updateUnits(cloudUnits, cloudScaled); err != nil {
 				return errors.Trace(err)
 			}
 		}
 	}
 }
+
+// ensureUnits creates or updates the pods of the unit with the
+// specified spec, along with those of any other units whose
+// specs are ready to be sent, in one broker call. This means that
+// when many units are added at once, their pods are created together.
+func (aw *applicationWorker) ensureUnits(spec caas.UnitSpec) error {
+	specs := map[string]caas.UnitSpec{spec.UnitName: spec}
+	unitNames := []string{spec.UnitName}
+	for more := true; more; {
+		select {
+		case spec := <-aw.unitSpecsChan:
+			if _, ok := specs[spec.UnitName]; !ok {
+				unitNames = append(unitNames, spec.UnitName)
+			}
+			specs[spec.UnitName] = spec
+		default:
+			more = false
+		}
+	}
+	units := make([]caas.UnitSpec, len(unitNames))
+	for i, name := range unitNames {
+		units[i] = specs[name]
+	}
+	if err := aw.containerBroker.EnsureUnits(aw.application, units); err != nil {
+		return errors.Annotatef(err, "creating or updating units of %q", aw.application)
+	}
+	logger.Debugf("created/updated %d units of %s", len(units), aw.application)
+	return nil
+}
+
+// removeUnit stops the worker managing the specified
+// unit, if there is one, and deletes the unit's pod.
+func (aw *applicationWorker) removeUnit(unitWorkers map[string]worker.Worker, unitId string) error {
+	if w, ok := unitWorkers[unitId]; ok {
+		if err := worker.Stop(w); err != nil {
+			return errors.Trace(err)
+		}
+		delete(unitWorkers, unitId)
+	}
+	if err := aw.containerBroker.DeleteUnit(unitId); err != nil {
+		return errors.Annotatef(err, "deleting unit %q", unitId)
+	}
+	return nil
+}
+
+func (aw *applicationWorker) updateUnits(cloudUnits map[string]caas.Unit, cloudScaled bool) error {
+	ids := make([]string, 0, len(cloudUnits))
+	for id := range cloudUnits {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	args := params.UpdateApplicationUnits{
+		ApplicationTag: names.NewApplicationTag(aw.application).String(),
+		Units:          make([]params.ApplicationUnitParams, len(ids)),
+		Autoscaled:     cloudScaled,
+	}
+	for i, id := range ids {
+		u := cloudUnits[id]
+		args.Units[i] = params.ApplicationUnitParams{
+			Id:      u.Id,
+			Address: u.Address,
+			Ports:   u.Ports,
+			Status:  u.Status.Status.String(),
+			Info:    u.Status.Message,
+			Data:    u.Status.Data,
+		}
+		for _, fs := range u.FilesystemInfo {
+			args.Units[i].FilesystemInfo = append(args.Units[i].FilesystemInfo, params.UnitFilesystemInfo{
+				StorageName:  fs.StorageName,
+				FilesystemId: fs.FilesystemId,
+				Size:         fs.Size,
+				MountPoint:   fs.MountPoint,
+				ReadOnly:     fs.ReadOnly,
+				Status:       fs.Status.Status.String(),
+				Info:         fs.Status.Message,
+				Volume: params.UnitVolumeInfo{
+					VolumeId:   fs.Volume.VolumeId,
+					Size:       fs.Volume.Size,
+					Persistent: fs.Volume.Persistent,
+				},
+			})
+		}
+	}
+	return errors.Trace(aw.unitUpdater.UpdateUnits(args))
+}
+
+// cloudScaled reports whether the number of units of the application
+// is decided by the cloud rather than by Juju, which is the case when
+// the application is autoscaled or its units run one per node. If the
+// config leaves the deployment mode to the charm and there is no pod
+// spec to consult yet, the result is not known.
+func (aw *applicationWorker) cloudScaled(aliveUnits []string) (scaled, known bool, _ error) {
+	appConfig, err := aw.applicationGetter.ApplicationConfig(aw.application)
+	if err != nil {
+		return false, false, errors.Trace(err)
+	}
+	policy, err := caas.AutoscalePolicyFromConfig(appConfig)
+	if err != nil {
+		return false, false, errors.Trace(err)
+	}
+	if policy != nil {
+		return true, true, nil
+	}
+	spec := &caas.PodSpec{}
+	if appConfig.GetString(caas.JujuDeploymentModeKey, "") == "" {
+		// The deployment mode may be requested by the charm.
+		if len(aliveUnits) == 0 {
+			return false, false, nil
+		}
+		specStr, err := aw.containerSpecGetter.ContainerSpec(aliveUnits[0])
+		if errors.IsNotFound(err) {
+			return false, false, nil
+		} else if err != nil {
+			return false, false, errors.Trace(err)
+		}
+		if spec, err = caas.ParsePodSpec(specStr); err != nil {
+			return false, false, errors.Annotate(err, "cannot parse container spec")
+		}
+	}
+	mode, err := deploymentMode(spec, appConfig)
+	if err != nil {
+		return false, false, errors.Trace(err)
+	}
+	return mode == caas.DeploymentOnePerNode, true, nil
+}
@@ -6,16 +6,15 @@ package caasunitprovisioner
 import (
 	"github.com/juju/juju/caas"
 	"github.com/juju/juju/core/application"
-	"github.com/juju/juju/watcher"
 )
 
 type ContainerBroker interface {
-	EnsureUnit(appName, unitName string, spec *caas.ContainerSpec) error
-	WatchUnits(appName string) (watcher.NotifyWatcher, error)
-	Units(appName string) ([]caas.Unit, error)
+	EnsureUnits(appName string, units []caas.UnitSpec) error
+	DeleteUnit(unitName string) error
+	WatchUnits(appName string) (caas.UnitsWatcher, error)
 }
 
 type ServiceBroker interface {
-	EnsureService(appName string, unitSpec *caas.ContainerSpec, numUnits int, config application.ConfigAttributes) error
+	EnsureService(appName string, params *caas.ServiceParams, numUnits int, config application.ConfigAttributes) error
 	DeleteService(appName string) error
 }
@@ -5,8 +5,10 @@ package caasunitprovisioner
 
 import (
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/core/application"
 	"github.com/juju/juju/core/life"
+	"github.com/juju/juju/storage"
 	"github.com/juju/juju/watcher"
 )
 
@@ -27,7 +29,11 @@ type Client interface {
 // model, and fetching their details.
 type ApplicationGetter interface {
 	WatchApplications() (watcher.StringsWatcher, error)
+	WatchApplicationConfig(string) (watcher.NotifyWatcher, error)
 	ApplicationConfig(string) (application.ConfigAttributes, error)
+	ApplicationConstraints(string) (constraints.Value, error)
+	ApplicationPlacement(string) (string, error)
+	StoragePool(string) (*storage.Config, error)
 }
 
 // ContainerSpecGetter provides an interface for
@@ -4,10 +4,13 @@
 package caasunitprovisioner
 
 import (
+	"reflect"
+
 	"github.com/juju/errors"
 	"gopkg.in/juju/worker.v1"
 
 	"github.com/juju/juju/caas"
+	"github.com/juju/juju/core/application"
 	"github.com/juju/juju/watcher"
 	"github.com/juju/juju/worker/catacomb"
 )
@@ -21,7 +24,8 @@ type deploymentWorker struct {
 	applicationGetter   ApplicationGetter
 	containerSpecGetter ContainerSpecGetter
 
-	aliveUnitsChan <-chan []string
+	aliveUnitsChan  <-chan []string
+	specChangesChan chan<- struct{}
 }
 
 func newDeploymentWorker(
@@ -30,6 +34,7 @@ func newDeploymentWorker(
 	containerSpecGetter ContainerSpecGetter,
 	applicationGetter ApplicationGetter,
 	aliveUnitsChan <-chan []string,
+	specChangesChan chan<- struct{},
 ) (worker.Worker, error) {
 	w := &deploymentWorker{
 		application:         application,
@@ -37,6 +42,7 @@ func newDeploymentWorker(
 		containerSpecGetter: containerSpecGetter,
 		applicationGetter:   applicationGetter,
 		aliveUnitsChan:      aliveUnitsChan,
+		specChangesChan:     specChangesChan,
 	}
 	if err := catacomb.Invoke(catacomb.Plan{
 		Site: &w.catacomb,
@@ -65,7 +71,8 @@ func (w *deploymentWorker) loop() error {
 		specChan   watcher.NotifyChannel
 
 		currentAliveCount int
-		currentSpec       string
+		currentParams     *caas.ServiceParams
+		currentConfig     application.ConfigAttributes
 	)
 
 	gotSpecNotify := false
@@ -88,6 +95,13 @@ func (w *deploymentWorker) loop() error {
 				return errors.New("watcher closed channel")
 			}
 			gotSpecNotify = true
+			// The pod spec is how the charm requests its deployment
+			// mode, so let the application worker know it changed.
+			select {
+			case <-w.catacomb.Dying():
+				return w.catacomb.ErrDying()
+			case w.specChangesChan <- struct{}{}:
+			}
 		}
 		if len(aliveUnits) == 0 {
 			if cw != nil {
@@ -115,25 +129,59 @@ func (w *deploymentWorker) loop() error {
 		}
 
 		numUnits := len(aliveUnits)
-		if numUnits == currentAliveCount && specStr == currentSpec {
-			continue
-		}
-
-		currentAliveCount = numUnits
-		currentSpec = specStr
-
 		appConfig, err := w.applicationGetter.ApplicationConfig(w.application)
 		if err != nil {
 			return errors.Trace(err)
 		}
-		spec, err := caas.ParseContainerSpec(specStr)
+		spec, err := caas.ParsePodSpec(specStr)
 		if err != nil {
 			return errors.Annotate(err, "cannot parse container spec")
 		}
-		err = w.broker.EnsureService(w.application, spec, numUnits, appConfig)
+		serviceParams, err := newServiceParams(w.applicationGetter, w.application, spec, appConfig)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		serviceParams.DeploymentMode, err = deploymentMode(spec, appConfig)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		serviceParams.Autoscale, err = caas.AutoscalePolicyFromConfig(appConfig)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		// The alive units are sent again when the application config
+		// or constraints change, so compare everything the service
+		// depends on, not just the number of units and the spec.
+		if numUnits == currentAliveCount &&
+			reflect.DeepEqual(serviceParams, currentParams) &&
+			reflect.DeepEqual(appConfig, currentConfig) {
+			continue
+		}
+		currentAliveCount = numUnits
+		currentParams = serviceParams
+		currentConfig = appConfig
+
+		err = w.broker.EnsureService(w.application, serviceParams, numUnits, appConfig)
 		if err != nil {
 			return errors.Trace(err)
 		}
 		logger.Debugf("created/updated deployment for %s for %d units", w.application, numUnits)
 	}
 }
+
+// deploymentMode returns the deployment mode for the application's
+// pods, which is set in the application config, or else requested
+// by the charm in its pod spec.
+func deploymentMode(spec *caas.PodSpec, appConfig application.ConfigAttributes) (caas.DeploymentMode, error) {
+	mode := caas.DeploymentMode(appConfig.GetString(caas.JujuDeploymentModeKey, ""))
+	if mode == "" {
+		mode = spec.DeploymentMode
+	}
+	if mode == "" {
+		return caas.DeploymentStateless, nil
+	}
+	if err := mode.Validate(); err != nil {
+		return "", errors.Trace(err)
+	}
+	return mode, nil
+}
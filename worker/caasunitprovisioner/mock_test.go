@@ -8,13 +8,15 @@ import (
 
 	"github.com/juju/testing"
 	gc "gopkg.in/check.v1"
+	"gopkg.in/tomb.v1"
 
 	"github.com/juju/juju/api/base"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/caas"
+	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/core/application"
 	"github.com/juju/juju/core/life"
-	"github.com/juju/juju/status"
+	"github.com/juju/juju/storage"
 	coretesting "github.com/juju/juju/testing"
 	"github.com/juju/juju/watcher"
 	"github.com/juju/juju/watcher/watchertest"
@@ -38,8 +40,8 @@ type mockServiceBroker struct {
 	ensured chan<- struct{}
 }
 
-func (m *mockServiceBroker) EnsureService(appName string, unitSpec *caas.ContainerSpec, numUnits int, config application.ConfigAttributes) error {
-	m.MethodCall(m, "EnsureService", appName, unitSpec, numUnits, config)
+func (m *mockServiceBroker) EnsureService(appName string, params *caas.ServiceParams, numUnits int, config application.ConfigAttributes) error {
+	m.MethodCall(m, "EnsureService", appName, params, numUnits, config)
 	m.ensured <- struct{}{}
 	return m.NextErr()
 }
@@ -52,34 +54,58 @@ func (m *mockServiceBroker) DeleteService(appName string) error {
 type mockContainerBroker struct {
 	testing.Stub
 	ensured      chan<- struct{}
-	unitsWatcher *watchertest.MockNotifyWatcher
+	unitsWatcher *mockUnitsWatcher
 }
 
-func (m *mockContainerBroker) EnsureUnit(appName, unitName string, spec *caas.ContainerSpec) error {
-	m.MethodCall(m, "EnsureUnit", appName, unitName, spec)
+func (m *mockContainerBroker) EnsureUnits(appName string, units []caas.UnitSpec) error {
+	m.MethodCall(m, "EnsureUnits", appName, units)
 	m.ensured <- struct{}{}
 	return m.NextErr()
 }
 
-func (m *mockContainerBroker) WatchUnits(appName string) (watcher.NotifyWatcher, error) {
+func (m *mockContainerBroker) DeleteUnit(unitName string) error {
+	m.MethodCall(m, "DeleteUnit", unitName)
+	return m.NextErr()
+}
+
+func (m *mockContainerBroker) WatchUnits(appName string) (caas.UnitsWatcher, error) {
 	m.MethodCall(m, "WatchUnits", appName)
 	return m.unitsWatcher, m.NextErr()
 }
 
-func (m *mockContainerBroker) Units(appName string) ([]caas.Unit, error) {
-	m.MethodCall(m, "Units", appName)
-	return []caas.Unit{
-		{
-			Id:      "u1",
-			Address: "10.0.0.1",
-			Status:  status.StatusInfo{Status: status.Allocating},
-		},
-	}, m.NextErr()
+type mockUnitsWatcher struct {
+	tomb tomb.Tomb
+	ch   <-chan []caas.UnitChange
+}
+
+func newMockUnitsWatcher(ch <-chan []caas.UnitChange) *mockUnitsWatcher {
+	w := &mockUnitsWatcher{ch: ch}
+	go func() {
+		defer w.tomb.Done()
+		<-w.tomb.Dying()
+		w.tomb.Kill(tomb.ErrDying)
+	}()
+	return w
+}
+
+func (w *mockUnitsWatcher) Changes() caas.UnitsChannel {
+	return caas.UnitsChannel(w.ch)
+}
+
+func (w *mockUnitsWatcher) Kill() {
+	w.tomb.Kill(nil)
+}
+
+func (w *mockUnitsWatcher) Wait() error {
+	return w.tomb.Wait()
 }
 
 type mockApplicationGetter struct {
 	testing.Stub
-	watcher *watchertest.MockStringsWatcher
+	watcher       *watchertest.MockStringsWatcher
+	configWatcher *watchertest.MockNotifyWatcher
+	config        application.ConfigAttributes
+	cons          string
 }
 
 func (m *mockApplicationGetter) WatchApplications() (watcher.StringsWatcher, error) {
@@ -90,11 +116,43 @@ func (m *mockApplicationGetter) WatchApplications() (watcher.StringsWatcher, err
 	return m.watcher, nil
 }
 
+func (m *mockApplicationGetter) WatchApplicationConfig(appName string) (watcher.NotifyWatcher, error) {
+	m.MethodCall(m, "WatchApplicationConfig", appName)
+	if err := m.NextErr(); err != nil {
+		return nil, err
+	}
+	return m.configWatcher, nil
+}
+
 func (a *mockApplicationGetter) ApplicationConfig(appName string) (application.ConfigAttributes, error) {
 	a.MethodCall(a, "ApplicationConfig", appName)
+	if a.config != nil {
+		return a.config, a.NextErr()
+	}
 	return application.ConfigAttributes{"juju-external-hostname": "exthost"}, a.NextErr()
 }
 
+func (a *mockApplicationGetter) ApplicationConstraints(appName string) (constraints.Value, error) {
+	a.MethodCall(a, "ApplicationConstraints", appName)
+	if a.cons != "" {
+		return constraints.MustParse(a.cons), a.NextErr()
+	}
+	return constraints.MustParse("mem=4G"), a.NextErr()
+}
+
+func (a *mockApplicationGetter) ApplicationPlacement(appName string) (string, error) {
+	a.MethodCall(a, "ApplicationPlacement", appName)
+	return "disktype=ssd", a.NextErr()
+}
+
+func (a *mockApplicationGetter) StoragePool(name string) (*storage.Config, error) {
+	a.MethodCall(a, "StoragePool", name)
+	if err := a.NextErr(); err != nil {
+		return nil, err
+	}
+	return storage.NewConfig(name, "kubernetes", map[string]interface{}{"storage-class": "ssd"})
+}
+
 type mockContainerSpecGetter struct {
 	testing.Stub
 	spec          string
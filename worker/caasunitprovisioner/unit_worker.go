@@ -5,9 +5,11 @@ package caasunitprovisioner
 
 import (
 	"github.com/juju/errors"
-	"github.com/juju/juju/caas"
 	"gopkg.in/juju/worker.v1"
 
+	"github.com/juju/juju/caas"
+	"github.com/juju/juju/core/application"
+	"github.com/juju/juju/storage"
 	"github.com/juju/juju/worker/catacomb"
 )
 
@@ -15,21 +17,28 @@ type unitWorker struct {
 	catacomb            catacomb.Catacomb
 	application         string
 	unit                string
-	broker              ContainerBroker
 	containerSpecGetter ContainerSpecGetter
+	applicationGetter   ApplicationGetter
+
+	// unitSpecs is the channel on which the unit's
+	// spec is sent, whenever the unit's pod is to
+	// be created or updated.
+	unitSpecs chan<- caas.UnitSpec
 }
 
 func newUnitWorker(
 	application string,
 	unit string,
-	broker ContainerBroker,
 	containerSpecGetter ContainerSpecGetter,
+	applicationGetter ApplicationGetter,
+	unitSpecs chan<- caas.UnitSpec,
 ) (worker.Worker, error) {
 	w := &unitWorker{
 		application:         application,
 		unit:                unit,
-		broker:              broker,
 		containerSpecGetter: containerSpecGetter,
+		applicationGetter:   applicationGetter,
+		unitSpecs:           unitSpecs,
 	}
 	if err := catacomb.Invoke(catacomb.Plan{
 		Site: &w.catacomb,
@@ -78,14 +87,77 @@ func (w *unitWorker) loop() error {
 			if err != nil {
 				return errors.Trace(err)
 			}
-			spec, err := caas.ParseContainerSpec(specStr)
+			spec, err := caas.ParsePodSpec(specStr)
 			if err != nil {
 				return errors.Annotate(err, "cannot parse container spec")
 			}
-			if err := w.broker.EnsureUnit(w.application, w.unit, spec); err != nil {
+			appConfig, err := w.applicationGetter.ApplicationConfig(w.application)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			serviceParams, err := newServiceParams(w.applicationGetter, w.application, spec, appConfig)
+			if err != nil {
 				return errors.Trace(err)
 			}
-			logger.Debugf("created/updated unit %s", w.unit)
+			// The application worker creates or updates
+			// the pods of its units in batches.
+			select {
+			case <-w.catacomb.Dying():
+				return w.catacomb.ErrDying()
+			case w.unitSpecs <- caas.UnitSpec{UnitName: w.unit, Params: serviceParams}:
+			}
+		}
+	}
+}
+
+// newServiceParams returns the parameters used to create pods
+// for the specified application with the given spec.
+func newServiceParams(
+	applicationGetter ApplicationGetter,
+	appName string,
+	spec *caas.PodSpec,
+	appConfig application.ConfigAttributes,
+) (*caas.ServiceParams, error) {
+	cons, err := applicationGetter.ApplicationConstraints(appName)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	placement, err := applicationGetter.ApplicationPlacement(appName)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	podLabels, err := caas.KeyValues(appConfig, caas.JujuPodLabelsKey)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	podAnnotations, err := caas.KeyValues(appConfig, caas.JujuPodAnnotationsKey)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var storagePools map[string]*storage.Config
+	for _, container := range spec.Containers {
+		for _, vol := range container.Volumes {
+			if vol.StoragePool == "" || storagePools[vol.StoragePool] != nil {
+				continue
+			}
+			pool, err := applicationGetter.StoragePool(vol.StoragePool)
+			if err != nil {
+				return nil, errors.Annotatef(err, "getting storage pool for volume %q", vol.Name)
+			}
+			if storagePools == nil {
+				storagePools = make(map[string]*storage.Config)
+			}
+			storagePools[vol.StoragePool] = pool
 		}
 	}
+	return &caas.ServiceParams{
+		PodSpec:         spec,
+		Constraints:     cons,
+		Placement:       placement,
+		ImagePullSecret: appConfig.GetString(caas.JujuImagePullSecretKey, ""),
+		PodLabels:       podLabels,
+		PodAnnotations:  podAnnotations,
+		StoragePools:    storagePools,
+		Trusted:         appConfig.GetBool(application.TrustConfigOptionName, false),
+	}, nil
 }
@@ -4,6 +4,7 @@
 package caasunitprovisioner_test
 
 import (
+	"strings"
 	"time"
 
 	"github.com/juju/errors"
@@ -15,8 +16,11 @@ import (
 
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/caas"
+	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/core/application"
 	"github.com/juju/juju/core/life"
+	"github.com/juju/juju/status"
+	"github.com/juju/juju/storage"
 	coretesting "github.com/juju/juju/testing"
 	"github.com/juju/juju/watcher/watchertest"
 	"github.com/juju/juju/worker/caasunitprovisioner"
@@ -36,8 +40,9 @@ type WorkerSuite struct {
 	unitUpdater         mockUnitUpdater
 
 	applicationChanges   chan []string
+	appConfigChanges     chan struct{}
 	jujuUnitChanges      chan []string
-	caasUnitsChanges     chan struct{}
+	caasUnitsChanges     chan []caas.UnitChange
 	containerSpecChanges chan struct{}
 	serviceEnsured       chan struct{}
 	unitEnsured          chan struct{}
@@ -57,19 +62,41 @@ ports:
 config:
   attr: foo=bar; fred=blogs
   foo: bar
+volumes:
+- name: data
+  mount-path: /var/opt/gitlab
+  size: 1Gi
 `[1:]
 
-	parsedSpec = caas.ContainerSpec{
-		Name:      "gitlab",
-		ImageName: "gitlab/latest",
-		Ports: []caas.ContainerPort{
-			{ContainerPort: 80, Protocol: "TCP"},
-			{ContainerPort: 443},
-		},
-		Config: map[string]string{
-			"attr": "foo=bar; fred=blogs",
-			"foo":  "bar",
-		},
+	parsedSpec = caas.PodSpec{
+		Containers: []caas.ContainerSpec{{
+			Name:      "gitlab",
+			ImageName: "gitlab/latest",
+			Ports: []caas.ContainerPort{
+				{ContainerPort: 80, Protocol: "TCP"},
+				{ContainerPort: 443},
+			},
+			Config: map[string]string{
+				"attr": "foo=bar; fred=blogs",
+				"foo":  "bar",
+			},
+			Volumes: []caas.ContainerVolume{
+				{Name: "data", MountPath: "/var/opt/gitlab", Size: "1Gi"},
+			},
+		}},
+	}
+
+	expectedParams = caas.ServiceParams{
+		PodSpec:     &parsedSpec,
+		Constraints: constraints.MustParse("mem=4G"),
+		Placement:   "disktype=ssd",
+	}
+
+	expectedServiceParams = caas.ServiceParams{
+		PodSpec:        &parsedSpec,
+		Constraints:    constraints.MustParse("mem=4G"),
+		Placement:      "disktype=ssd",
+		DeploymentMode: caas.DeploymentStateless,
 	}
 )
 
@@ -77,16 +104,19 @@ func (s *WorkerSuite) SetUpTest(c *gc.C) {
 	s.IsolationSuite.SetUpTest(c)
 
 	s.applicationChanges = make(chan []string)
+	s.appConfigChanges = make(chan struct{})
 	s.jujuUnitChanges = make(chan []string)
-	s.caasUnitsChanges = make(chan struct{})
+	s.caasUnitsChanges = make(chan []caas.UnitChange)
 	s.containerSpecChanges = make(chan struct{})
 	s.serviceEnsured = make(chan struct{})
 	s.unitEnsured = make(chan struct{})
 
 	s.applicationGetter = mockApplicationGetter{
-		watcher: watchertest.NewMockStringsWatcher(s.applicationChanges),
+		watcher:       watchertest.NewMockStringsWatcher(s.applicationChanges),
+		configWatcher: watchertest.NewMockNotifyWatcher(s.appConfigChanges),
 	}
 	s.AddCleanup(func(c *gc.C) { workertest.DirtyKill(c, s.applicationGetter.watcher) })
+	s.AddCleanup(func(c *gc.C) { workertest.DirtyKill(c, s.applicationGetter.configWatcher) })
 
 	s.containerSpecGetter = mockContainerSpecGetter{
 		watcher: watchertest.NewMockNotifyWatcher(s.containerSpecChanges),
@@ -102,7 +132,7 @@ func (s *WorkerSuite) SetUpTest(c *gc.C) {
 
 	s.containerBroker = mockContainerBroker{
 		ensured:      s.unitEnsured,
-		unitsWatcher: watchertest.NewMockNotifyWatcher(s.caasUnitsChanges),
+		unitsWatcher: newMockUnitsWatcher(s.caasUnitsChanges),
 	}
 	s.lifeGetter = mockLifeGetter{}
 	s.lifeGetter.setLife(life.Alive)
@@ -215,7 +245,7 @@ func (s *WorkerSuite) TestNewJujuManagedUnit(c *gc.C) {
 	w := s.setupNewUnitScenario(c, false, s.unitEnsured)
 	defer workertest.CleanKill(c, w)
 
-	s.applicationGetter.CheckCallNames(c, "WatchApplications")
+	s.applicationGetter.CheckCallNames(c, "WatchApplications", "ApplicationConfig", "ApplicationConstraints", "ApplicationPlacement")
 	s.unitGetter.CheckCallNames(c, "WatchUnits")
 	s.unitGetter.CheckCall(c, 0, "WatchUnits", "gitlab")
 	s.containerSpecGetter.CheckCallNames(c, "WatchContainerSpec", "ContainerSpec", "ContainerSpec")
@@ -225,15 +255,18 @@ func (s *WorkerSuite) TestNewJujuManagedUnit(c *gc.C) {
 	s.lifeGetter.CheckCallNames(c, "Life", "Life")
 	s.lifeGetter.CheckCall(c, 0, "Life", "gitlab")
 	s.lifeGetter.CheckCall(c, 1, "Life", "gitlab/0")
-	s.containerBroker.CheckCallNames(c, "WatchUnits", "EnsureUnit")
-	s.containerBroker.CheckCall(c, 1, "EnsureUnit", "gitlab", "gitlab/0", &parsedSpec)
+	s.containerBroker.CheckCallNames(c, "WatchUnits", "EnsureUnits")
+	s.containerBroker.CheckCall(c, 1, "EnsureUnits", "gitlab", []caas.UnitSpec{
+		{UnitName: "gitlab/0", Params: &expectedParams},
+	})
 }
 
 func (s *WorkerSuite) TestNewBrokerManagedUnit(c *gc.C) {
 	w := s.setupNewUnitScenario(c, true, s.serviceEnsured)
 	defer workertest.CleanKill(c, w)
 
-	s.applicationGetter.CheckCallNames(c, "WatchApplications", "ApplicationConfig")
+	s.applicationGetter.CheckCallNames(c, "WatchApplications", "WatchApplicationConfig", "ApplicationConfig", "ApplicationConstraints", "ApplicationPlacement")
+	s.applicationGetter.CheckCall(c, 1, "WatchApplicationConfig", "gitlab")
 	s.containerSpecGetter.CheckCallNames(c, "WatchContainerSpec", "ContainerSpec", "ContainerSpec")
 	s.containerSpecGetter.CheckCall(c, 0, "WatchContainerSpec", "gitlab/0")
 	s.containerSpecGetter.CheckCall(c, 1, "ContainerSpec", "gitlab/0") // not found
@@ -243,7 +276,7 @@ func (s *WorkerSuite) TestNewBrokerManagedUnit(c *gc.C) {
 	s.lifeGetter.CheckCall(c, 1, "Life", "gitlab/0")
 	s.serviceBroker.CheckCallNames(c, "EnsureService")
 	s.serviceBroker.CheckCall(c, 0, "EnsureService",
-		"gitlab", &parsedSpec, 1, application.ConfigAttributes{"juju-external-hostname": "exthost"})
+		"gitlab", &expectedServiceParams, 1, application.ConfigAttributes{"juju-external-hostname": "exthost"})
 
 	s.serviceBroker.ResetCalls()
 	// Add another unit.
@@ -261,7 +294,7 @@ func (s *WorkerSuite) TestNewBrokerManagedUnit(c *gc.C) {
 
 	s.serviceBroker.CheckCallNames(c, "EnsureService")
 	s.serviceBroker.CheckCall(c, 0, "EnsureService",
-		"gitlab", &parsedSpec, 2, application.ConfigAttributes{"juju-external-hostname": "exthost"})
+		"gitlab", &expectedServiceParams, 2, application.ConfigAttributes{"juju-external-hostname": "exthost"})
 
 	s.serviceBroker.ResetCalls()
 	// Delete a unit.
@@ -280,7 +313,7 @@ func (s *WorkerSuite) TestNewBrokerManagedUnit(c *gc.C) {
 
 	s.serviceBroker.CheckCallNames(c, "EnsureService")
 	s.serviceBroker.CheckCall(c, 0, "EnsureService",
-		"gitlab", &parsedSpec, 1, application.ConfigAttributes{"juju-external-hostname": "exthost"})
+		"gitlab", &expectedServiceParams, 1, application.ConfigAttributes{"juju-external-hostname": "exthost"})
 }
 
 func (s *WorkerSuite) TestNewBrokerManagedUnitSpecChange(c *gc.C) {
@@ -300,13 +333,21 @@ func (s *WorkerSuite) TestNewBrokerManagedUnitSpecChange(c *gc.C) {
 
 	var (
 		anotherSpec = `
-name: gitlab
-image-name: gitlab/latest
+containers:
+- name: gitlab
+  image-name: gitlab/latest
+- name: exporter
+  image-name: prom/exporter
 `[1:]
 
-		anotherParsedSpec = caas.ContainerSpec{
-			Name:      "gitlab",
-			ImageName: "gitlab/latest",
+		anotherParsedSpec = caas.PodSpec{
+			Containers: []caas.ContainerSpec{{
+				Name:      "gitlab",
+				ImageName: "gitlab/latest",
+			}, {
+				Name:      "exporter",
+				ImageName: "prom/exporter",
+			}},
 		}
 	)
 
@@ -322,7 +363,325 @@ image-name: gitlab/latest
 
 	s.serviceBroker.CheckCallNames(c, "EnsureService")
 	s.serviceBroker.CheckCall(c, 0, "EnsureService",
-		"gitlab", &anotherParsedSpec, 1, application.ConfigAttributes{"juju-external-hostname": "exthost"})
+		"gitlab", &caas.ServiceParams{
+			PodSpec:        &anotherParsedSpec,
+			Constraints:    constraints.MustParse("mem=4G"),
+			Placement:      "disktype=ssd",
+			DeploymentMode: caas.DeploymentStateless,
+		}, 1, application.ConfigAttributes{"juju-external-hostname": "exthost"})
+}
+
+func (s *WorkerSuite) TestNewBrokerManagedUnitStatefulSpec(c *gc.C) {
+	w := s.setupNewUnitScenario(c, true, s.serviceEnsured)
+	defer workertest.CleanKill(c, w)
+
+	s.serviceBroker.ResetCalls()
+
+	statefulSpec := "deployment-mode: stateful\n" + containerSpec
+	statefulParsedSpec := parsedSpec
+	statefulParsedSpec.DeploymentMode = caas.DeploymentStateful
+	s.containerSpecGetter.setSpec(statefulSpec)
+	s.sendContainerSpecChange(c)
+	s.containerSpecGetter.assertSpecRetrieved(c)
+
+	select {
+	case <-s.serviceEnsured:
+	case <-time.After(coretesting.LongWait):
+		c.Fatal("timed out waiting for service to be ensured")
+	}
+
+	s.serviceBroker.CheckCallNames(c, "EnsureService")
+	s.serviceBroker.CheckCall(c, 0, "EnsureService",
+		"gitlab", &caas.ServiceParams{
+			PodSpec:        &statefulParsedSpec,
+			Constraints:    constraints.MustParse("mem=4G"),
+			Placement:      "disktype=ssd",
+			DeploymentMode: caas.DeploymentStateful,
+		}, 1, application.ConfigAttributes{"juju-external-hostname": "exthost"})
+}
+
+func (s *WorkerSuite) TestNewBrokerManagedUnitStoragePool(c *gc.C) {
+	w := s.setupNewUnitScenario(c, true, s.serviceEnsured)
+	defer workertest.CleanKill(c, w)
+
+	s.serviceBroker.ResetCalls()
+
+	poolSpec := strings.Replace(containerSpec, "size: 1Gi\n", "size: 1Gi\n  storage-pool: fast\n", 1)
+	poolContainer := parsedSpec.Containers[0]
+	poolContainer.Volumes = []caas.ContainerVolume{
+		{Name: "data", MountPath: "/var/opt/gitlab", Size: "1Gi", StoragePool: "fast"},
+	}
+	poolParsedSpec := parsedSpec
+	poolParsedSpec.Containers = []caas.ContainerSpec{poolContainer}
+	s.containerSpecGetter.setSpec(poolSpec)
+	s.sendContainerSpecChange(c)
+	s.containerSpecGetter.assertSpecRetrieved(c)
+
+	select {
+	case <-s.serviceEnsured:
+	case <-time.After(coretesting.LongWait):
+		c.Fatal("timed out waiting for service to be ensured")
+	}
+
+	pool, err := storage.NewConfig("fast", "kubernetes", map[string]interface{}{"storage-class": "ssd"})
+	c.Assert(err, jc.ErrorIsNil)
+	s.serviceBroker.CheckCallNames(c, "EnsureService")
+	s.serviceBroker.CheckCall(c, 0, "EnsureService",
+		"gitlab", &caas.ServiceParams{
+			PodSpec:        &poolParsedSpec,
+			Constraints:    constraints.MustParse("mem=4G"),
+			Placement:      "disktype=ssd",
+			DeploymentMode: caas.DeploymentStateless,
+			StoragePools:   map[string]*storage.Config{"fast": pool},
+		}, 1, application.ConfigAttributes{"juju-external-hostname": "exthost"})
+}
+
+func (s *WorkerSuite) TestNewBrokerManagedUnitDeploymentModeConfig(c *gc.C) {
+	s.applicationGetter.config = application.ConfigAttributes{
+		"juju-external-hostname": "exthost",
+		"juju-deployment-mode":   "stateful",
+	}
+	w := s.setupNewUnitScenario(c, true, s.serviceEnsured)
+	defer workertest.CleanKill(c, w)
+
+	params := expectedServiceParams
+	params.DeploymentMode = caas.DeploymentStateful
+	s.serviceBroker.CheckCallNames(c, "EnsureService")
+	s.serviceBroker.CheckCall(c, 0, "EnsureService",
+		"gitlab", &params, 1, s.applicationGetter.config)
+}
+
+func (s *WorkerSuite) TestNewBrokerManagedUnitAutoscaleConfig(c *gc.C) {
+	s.applicationGetter.config = application.ConfigAttributes{
+		"juju-external-hostname":    "exthost",
+		"juju-autoscale-min-units":  1,
+		"juju-autoscale-max-units":  5,
+		"juju-autoscale-target-cpu": 70,
+	}
+	w := s.setupNewUnitScenario(c, true, s.serviceEnsured)
+	defer workertest.CleanKill(c, w)
+
+	params := expectedServiceParams
+	params.Autoscale = &caas.AutoscalePolicy{MinUnits: 1, MaxUnits: 5, TargetCPUPercent: 70}
+	s.serviceBroker.CheckCallNames(c, "EnsureService")
+	s.serviceBroker.CheckCall(c, 0, "EnsureService",
+		"gitlab", &params, 1, s.applicationGetter.config)
+}
+
+func (s *WorkerSuite) TestNewBrokerManagedUnitOnePerNode(c *gc.C) {
+	s.applicationGetter.config = application.ConfigAttributes{
+		"juju-external-hostname": "exthost",
+		"juju-deployment-mode":   "one-per-node",
+	}
+	w := s.setupNewUnitScenario(c, true, s.serviceEnsured)
+	defer workertest.CleanKill(c, w)
+
+	serviceParams := expectedServiceParams
+	serviceParams.DeploymentMode = caas.DeploymentOnePerNode
+	s.serviceBroker.CheckCallNames(c, "EnsureService")
+	s.serviceBroker.CheckCall(c, 0, "EnsureService",
+		"gitlab", &serviceParams, 1, s.applicationGetter.config)
+
+	// The cloud runs a unit on each node, so Juju
+	// units with no pod are removed.
+	select {
+	case s.caasUnitsChanges <- []caas.UnitChange{{Kind: caas.UnitAdded, Unit: caas.Unit{Id: "u1"}}}:
+	case <-time.After(coretesting.LongWait):
+		c.Fatal("timed out sending units change")
+	}
+	for a := coretesting.LongAttempt.Start(); a.Next(); {
+		if len(s.unitUpdater.Calls()) > 0 {
+			break
+		}
+	}
+	s.unitUpdater.CheckCallNames(c, "UpdateUnits")
+	c.Assert(s.unitUpdater.Calls()[0].Args, jc.DeepEquals, []interface{}{
+		params.UpdateApplicationUnits{
+			ApplicationTag: names.NewApplicationTag("gitlab").String(),
+			Units: []params.ApplicationUnitParams{
+				{Id: "u1", Ports: []string(nil)},
+			},
+			Autoscaled: true,
+		},
+	})
+}
+
+func (s *WorkerSuite) TestNewBrokerManagedUnitCloudScaledCached(c *gc.C) {
+	s.applicationGetter.config = application.ConfigAttributes{
+		"juju-external-hostname":    "exthost",
+		"juju-autoscale-min-units":  1,
+		"juju-autoscale-max-units":  5,
+		"juju-autoscale-target-cpu": 70,
+	}
+	w := s.setupNewUnitScenario(c, true, s.serviceEnsured)
+	defer workertest.CleanKill(c, w)
+
+	// Change the config so the service is updated, and wait
+	// for that before counting the calls made.
+	s.applicationGetter.config = application.ConfigAttributes{
+		"juju-external-hostname":    "exthost",
+		"juju-autoscale-min-units":  1,
+		"juju-autoscale-max-units":  6,
+		"juju-autoscale-target-cpu": 70,
+	}
+	select {
+	case s.appConfigChanges <- struct{}{}:
+	case <-time.After(coretesting.LongWait):
+		c.Fatal("timed out sending application config change")
+	}
+	select {
+	case <-s.serviceEnsured:
+	case <-time.After(coretesting.LongWait):
+		c.Fatal("timed out waiting for service to be ensured")
+	}
+	s.applicationGetter.ResetCalls()
+	s.containerSpecGetter.ResetCalls()
+
+	// The config is read when it changes, and not
+	// again for each change to the cloud's units.
+	for i, id := range []string{"u1", "u2"} {
+		select {
+		case s.caasUnitsChanges <- []caas.UnitChange{{Kind: caas.UnitAdded, Unit: caas.Unit{Id: id}}}:
+		case <-time.After(coretesting.LongWait):
+			c.Fatal("timed out sending units change")
+		}
+		for a := coretesting.LongAttempt.Start(); a.Next(); {
+			if len(s.unitUpdater.Calls()) > i {
+				break
+			}
+		}
+	}
+	s.unitUpdater.CheckCallNames(c, "UpdateUnits", "UpdateUnits")
+	for _, call := range s.unitUpdater.Calls() {
+		c.Assert(call.Args[0].(params.UpdateApplicationUnits).Autoscaled, jc.IsTrue)
+	}
+	s.applicationGetter.CheckNoCalls(c)
+	s.containerSpecGetter.CheckNoCalls(c)
+}
+
+func (s *WorkerSuite) TestNewBrokerManagedUnitCloudScaledCharmChange(c *gc.C) {
+	w := s.setupNewUnitScenario(c, true, s.serviceEnsured)
+	defer workertest.CleanKill(c, w)
+
+	sendUnitChange := func(id string, autoscaled bool) {
+		s.unitUpdater.ResetCalls()
+		select {
+		case s.caasUnitsChanges <- []caas.UnitChange{{Kind: caas.UnitAdded, Unit: caas.Unit{Id: id}}}:
+		case <-time.After(coretesting.LongWait):
+			c.Fatal("timed out sending units change")
+		}
+		for a := coretesting.LongAttempt.Start(); a.Next(); {
+			if len(s.unitUpdater.Calls()) > 0 {
+				break
+			}
+		}
+		s.unitUpdater.CheckCallNames(c, "UpdateUnits")
+		c.Assert(s.unitUpdater.Calls()[0].Args[0].(params.UpdateApplicationUnits).Autoscaled, gc.Equals, autoscaled)
+	}
+
+	// The config leaves the deployment mode to the charm,
+	// whose pod spec does not ask for one.
+	sendUnitChange("u1", false)
+
+	// The upgraded charm asks for a unit on each node.
+	s.containerSpecGetter.setSpec("deployment-mode: one-per-node\n" + containerSpec)
+	s.sendContainerSpecChange(c)
+	select {
+	case <-s.serviceEnsured:
+	case <-time.After(coretesting.LongWait):
+		c.Fatal("timed out waiting for service to be ensured")
+	}
+	sendUnitChange("u2", true)
+}
+
+func (s *WorkerSuite) TestNewBrokerManagedUnitConstraintsChange(c *gc.C) {
+	w := s.setupNewUnitScenario(c, true, s.serviceEnsured)
+	defer workertest.CleanKill(c, w)
+
+	s.serviceBroker.ResetCalls()
+	// The application config watcher also reports
+	// changes to the application constraints.
+	s.applicationGetter.cons = "mem=8G"
+	select {
+	case s.appConfigChanges <- struct{}{}:
+	case <-time.After(coretesting.LongWait):
+		c.Fatal("timed out sending application config change")
+	}
+	select {
+	case <-s.serviceEnsured:
+	case <-time.After(coretesting.LongWait):
+		c.Fatal("timed out waiting for service to be ensured")
+	}
+
+	serviceParams := expectedServiceParams
+	serviceParams.Constraints = constraints.MustParse("mem=8G")
+	s.serviceBroker.CheckCallNames(c, "EnsureService")
+	s.serviceBroker.CheckCall(c, 0, "EnsureService",
+		"gitlab", &serviceParams, 1, application.ConfigAttributes{"juju-external-hostname": "exthost"})
+
+	s.serviceBroker.ResetCalls()
+	// Nothing changed, so the service is not updated.
+	select {
+	case s.appConfigChanges <- struct{}{}:
+	case <-time.After(coretesting.LongWait):
+		c.Fatal("timed out sending application config change")
+	}
+	select {
+	case <-s.serviceEnsured:
+		c.Fatal("service ensured unexpectedly")
+	case <-time.After(coretesting.ShortWait):
+	}
+	s.serviceBroker.CheckNoCalls(c)
+}
+
+func (s *WorkerSuite) TestNewBrokerManagedUnitImagePullSecretConfig(c *gc.C) {
+	s.applicationGetter.config = application.ConfigAttributes{
+		"juju-external-hostname": "exthost",
+		"juju-image-pull-secret": "registry-creds",
+	}
+	w := s.setupNewUnitScenario(c, true, s.serviceEnsured)
+	defer workertest.CleanKill(c, w)
+
+	params := expectedServiceParams
+	params.ImagePullSecret = "registry-creds"
+	s.serviceBroker.CheckCallNames(c, "EnsureService")
+	s.serviceBroker.CheckCall(c, 0, "EnsureService",
+		"gitlab", &params, 1, s.applicationGetter.config)
+}
+
+func (s *WorkerSuite) TestNewBrokerManagedUnitTrustConfig(c *gc.C) {
+	s.applicationGetter.config = application.ConfigAttributes{
+		"juju-external-hostname": "exthost",
+		"trust":                  true,
+	}
+	w := s.setupNewUnitScenario(c, true, s.serviceEnsured)
+	defer workertest.CleanKill(c, w)
+
+	params := expectedServiceParams
+	params.Trusted = true
+	s.serviceBroker.CheckCallNames(c, "EnsureService")
+	s.serviceBroker.CheckCall(c, 0, "EnsureService",
+		"gitlab", &params, 1, s.applicationGetter.config)
+}
+
+func (s *WorkerSuite) TestNewBrokerManagedUnitPodMetadataConfig(c *gc.C) {
+	s.applicationGetter.config = application.ConfigAttributes{
+		"juju-external-hostname": "exthost",
+		"juju-pod-labels":        "team=data",
+		"juju-pod-annotations":   "prometheus.io/scrape=true,prometheus.io/port=9090",
+	}
+	w := s.setupNewUnitScenario(c, true, s.serviceEnsured)
+	defer workertest.CleanKill(c, w)
+
+	params := expectedServiceParams
+	params.PodLabels = map[string]string{"team": "data"}
+	params.PodAnnotations = map[string]string{
+		"prometheus.io/scrape": "true",
+		"prometheus.io/port":   "9090",
+	}
+	s.serviceBroker.CheckCallNames(c, "EnsureService")
+	s.serviceBroker.CheckCall(c, 0, "EnsureService",
+		"gitlab", &params, 1, s.applicationGetter.config)
 }
 
 func (s *WorkerSuite) TestNewBrokerManagedUnitAllRemoved(c *gc.C) {
@@ -457,6 +816,28 @@ func (s *WorkerSuite) TestRemoveUnitStopsWatchingContainerSpec(c *gc.C) {
 	workertest.CheckKilled(c, s.containerSpecGetter.watcher)
 }
 
+func (s *WorkerSuite) TestRemoveUnitDeletesPod(c *gc.C) {
+	w := s.setupNewUnitScenario(c, false, s.unitEnsured)
+	defer workertest.CleanKill(c, w)
+
+	s.containerBroker.ResetCalls()
+	s.lifeGetter.setLife(life.Dead)
+	select {
+	case s.jujuUnitChanges <- []string{"gitlab/0"}:
+	case <-time.After(coretesting.LongWait):
+		c.Fatal("timed out sending units change")
+	}
+
+	workertest.CheckKilled(c, s.containerSpecGetter.watcher)
+	for a := coretesting.LongAttempt.Start(); a.Next(); {
+		if len(s.containerBroker.Calls()) > 0 {
+			break
+		}
+	}
+	s.containerBroker.CheckCallNames(c, "DeleteUnit")
+	s.containerBroker.CheckCall(c, 0, "DeleteUnit", "gitlab/0")
+}
+
 func (s *WorkerSuite) TestWatcherErrorStopsWorker(c *gc.C) {
 	w, err := caasunitprovisioner.NewWorker(s.config)
 	c.Assert(err, jc.ErrorIsNil)
@@ -496,19 +877,40 @@ func (s *WorkerSuite) TestUnitsChange(c *gc.C) {
 
 	s.containerBroker.ResetCalls()
 
-	select {
-	case s.caasUnitsChanges <- struct{}{}:
-	case <-time.After(coretesting.LongWait):
-		c.Fatal("timed out sending units change")
-	}
-
-	for a := coretesting.LongAttempt.Start(); a.Next(); {
-		if len(s.containerBroker.Calls()) > 1 {
-			break
+	sendUnitsChange := func(changes ...caas.UnitChange) {
+		select {
+		case s.caasUnitsChanges <- changes:
+		case <-time.After(coretesting.LongWait):
+			c.Fatal("timed out sending units change")
 		}
 	}
-	s.containerBroker.CheckCallNames(c, "WatchUnits", "Units")
-	c.Assert(s.containerBroker.Calls()[1].Args, jc.DeepEquals, []interface{}{"gitlab"})
+	u1 := caas.Unit{
+		Id:      "u1",
+		Address: "10.0.0.1",
+		Status:  status.StatusInfo{Status: status.Allocating},
+	}
+	u2 := caas.Unit{
+		Id:      "u2",
+		Address: "10.0.0.2",
+		Status:  status.StatusInfo{Status: status.Running},
+		FilesystemInfo: []caas.FilesystemInfo{{
+			StorageName:  "data",
+			FilesystemId: "claim-uuid",
+			Size:         1024,
+			MountPoint:   "/var/opt/gitlab",
+			Status:       status.StatusInfo{Status: status.Attached},
+			Volume:       caas.VolumeInfo{VolumeId: "pv-1", Size: 1024, Persistent: true},
+		}},
+	}
+	u2Filesystems := []params.UnitFilesystemInfo{{
+		StorageName:  "data",
+		FilesystemId: "claim-uuid",
+		Size:         1024,
+		MountPoint:   "/var/opt/gitlab",
+		Status:       "attached",
+		Volume:       params.UnitVolumeInfo{VolumeId: "pv-1", Size: 1024, Persistent: true},
+	}}
+	sendUnitsChange(caas.UnitChange{Kind: caas.UnitAdded, Unit: u1})
 
 	for a := coretesting.LongAttempt.Start(); a.Next(); {
 		if len(s.unitUpdater.Calls()) > 0 {
@@ -524,4 +926,38 @@ func (s *WorkerSuite) TestUnitsChange(c *gc.C) {
 			},
 		},
 	})
+
+	// The units sent are the complete set known to the
+	// worker, so removed units are omitted.
+	u1.Status.Status = status.Running
+	sendUnitsChange(caas.UnitChange{Kind: caas.UnitUpdated, Unit: u1}, caas.UnitChange{Kind: caas.UnitAdded, Unit: u2})
+	sendUnitsChange(caas.UnitChange{Kind: caas.UnitRemoved, Unit: caas.Unit{Id: "u1"}, Reason: "pod deleted"})
+
+	for a := coretesting.LongAttempt.Start(); a.Next(); {
+		if len(s.unitUpdater.Calls()) > 2 {
+			break
+		}
+	}
+	s.unitUpdater.CheckCallNames(c, "UpdateUnits", "UpdateUnits", "UpdateUnits")
+	c.Assert(s.unitUpdater.Calls()[1].Args, jc.DeepEquals, []interface{}{
+		params.UpdateApplicationUnits{
+			ApplicationTag: names.NewApplicationTag("gitlab").String(),
+			Units: []params.ApplicationUnitParams{
+				{Id: "u1", Address: "10.0.0.1", Ports: []string(nil), Status: "running"},
+				{Id: "u2", Address: "10.0.0.2", Ports: []string(nil), Status: "running", FilesystemInfo: u2Filesystems},
+			},
+		},
+	})
+	c.Assert(s.unitUpdater.Calls()[2].Args, jc.DeepEquals, []interface{}{
+		params.UpdateApplicationUnits{
+			ApplicationTag: names.NewApplicationTag("gitlab").String(),
+			Units: []params.ApplicationUnitParams{
+				{Id: "u2", Address: "10.0.0.2", Ports: []string(nil), Status: "running", FilesystemInfo: u2Filesystems},
+			},
+		},
+	})
+
+	// The units are reported by the watcher, so
+	// the broker is not asked to list them.
+	s.containerBroker.CheckNoCalls(c)
 }
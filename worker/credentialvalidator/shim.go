@@ -0,0 +1,28 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package credentialvalidator
+
+import (
+	"github.com/juju/errors"
+	worker "gopkg.in/juju/worker.v1"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/api/credentialvalidator"
+)
+
+func NewFacade(apiCaller base.APICaller) (Facade, error) {
+	modelTag, ok := apiCaller.ModelTag()
+	if !ok {
+		return nil, errors.New("credentialvalidator may only be used with a model API connection")
+	}
+	return credentialvalidator.NewFacade(apiCaller, modelTag), nil
+}
+
+func NewWorker(config Config) (worker.Worker, error) {
+	worker, err := New(config)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return worker, nil
+}
@@ -0,0 +1,175 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package credentialvalidator
+
+import (
+	"fmt"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/api/credentialvalidator"
+	"github.com/juju/juju/status"
+	"github.com/juju/juju/watcher"
+	"github.com/juju/juju/worker/catacomb"
+)
+
+var logger = loggo.GetLogger("juju.worker.credentialvalidator")
+
+// ErrChanged indicates that a Worker has stopped because its
+// Check result is no longer valid.
+var ErrChanged = errors.New("cloud credential validity changed")
+
+// Facade exposes controller functionality to a Worker.
+type Facade interface {
+	ModelCredential() (credentialvalidator.ModelCredential, error)
+	WatchCredential(names.CloudCredentialTag) (watcher.NotifyWatcher, error)
+	ModelStatus() (status.Status, error)
+	SetModelStatus(status.Status, string, map[string]interface{}) error
+}
+
+// Config holds the dependencies and configuration for a Worker.
+type Config struct {
+	Facade Facade
+}
+
+// Validate returns an error if the config cannot be expected to
+// drive a functional Worker.
+func (config Config) Validate() error {
+	if config.Facade == nil {
+		return errors.NotValidf("nil Facade")
+	}
+	return nil
+}
+
+// New returns a Worker that tracks whether the cloud credential used
+// by the model is valid. While it is not, the model is suspended, and
+// the worker's Check result is false so that the workers which use the
+// credential to talk to the cloud are stopped; when the credential is
+// fixed the model becomes available again and those workers restart.
+func New(config Config) (*Worker, error) {
+	if err := config.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	credential, err := config.Facade.ModelCredential()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	w := &Worker{
+		config:     config,
+		credential: credential,
+	}
+	if err := w.setStatus(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	err = catacomb.Invoke(catacomb.Plan{
+		Site: &w.catacomb,
+		Work: w.loop,
+	})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return w, nil
+}
+
+// Worker implements worker.Worker and engine.Flag, and exits with
+// ErrChanged whenever the model's credential becomes, or stops being,
+// valid.
+type Worker struct {
+	catacomb   catacomb.Catacomb
+	config     Config
+	credential credentialvalidator.ModelCredential
+}
+
+// Kill is part of the worker.Worker interface.
+func (w *Worker) Kill() {
+	w.catacomb.Kill(nil)
+}
+
+// Wait is part of the worker.Worker interface.
+func (w *Worker) Wait() error {
+	return w.catacomb.Wait()
+}
+
+// Check is part of the engine.Flag interface. It returns true unless
+// the model uses a credential which is not valid; models which do not
+// need a credential are never suspended.
+func (w *Worker) Check() bool {
+	return valid(w.credential)
+}
+
+func valid(credential credentialvalidator.ModelCredential) bool {
+	return !credential.Exists || credential.Valid
+}
+
+func (w *Worker) loop() error {
+	if !w.credential.Exists {
+		<-w.catacomb.Dying()
+		return w.catacomb.ErrDying()
+	}
+	watcher, err := w.config.Facade.WatchCredential(w.credential.Credential)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err := w.catacomb.Add(watcher); err != nil {
+		return errors.Trace(err)
+	}
+	for {
+		select {
+		case <-w.catacomb.Dying():
+			return w.catacomb.ErrDying()
+		case _, ok := <-watcher.Changes():
+			if !ok {
+				return errors.New("credential watcher closed")
+			}
+			credential, err := w.config.Facade.ModelCredential()
+			if err != nil {
+				return errors.Trace(err)
+			}
+			if credential.Credential != w.credential.Credential {
+				// The model now uses a different credential; start
+				// again to watch that one instead.
+				return ErrChanged
+			}
+			if valid(credential) == valid(w.credential) {
+				continue
+			}
+			w.credential = credential
+			if err := w.setStatus(); err != nil {
+				return errors.Trace(err)
+			}
+			return ErrChanged
+		}
+	}
+}
+
+// setStatus suspends the model if its credential is not valid, and
+// makes it available again if it was suspended and the credential is
+// now valid. Other model statuses are left alone.
+func (w *Worker) setStatus() error {
+	if !valid(w.credential) {
+		credentialID := w.credential.Credential.Id()
+		logger.Warningf("cloud credential %q is not valid, suspending model", credentialID)
+		message := fmt.Sprintf(
+			"suspended since cloud credential %q is not valid; "+
+				"use \"juju update-credential\" to fix it",
+			credentialID,
+		)
+		err := w.config.Facade.SetModelStatus(status.Suspended, message, map[string]interface{}{
+			"credential": credentialID,
+		})
+		return errors.Annotate(err, "suspending model")
+	}
+	modelStatus, err := w.config.Facade.ModelStatus()
+	if err != nil {
+		return errors.Annotate(err, "getting model status")
+	}
+	if modelStatus != status.Suspended {
+		return nil
+	}
+	logger.Infof("cloud credential is valid, resuming model")
+	err = w.config.Facade.SetModelStatus(status.Available, "", nil)
+	return errors.Annotate(err, "resuming model")
+}
@@ -0,0 +1,224 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package credentialvalidator_test
+
+import (
+	"sync"
+	"time"
+
+	"github.com/juju/errors"
+	jujutesting "github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v2"
+
+	apicredentialvalidator "github.com/juju/juju/api/credentialvalidator"
+	"github.com/juju/juju/status"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/watcher"
+	"github.com/juju/juju/watcher/watchertest"
+	"github.com/juju/juju/worker/credentialvalidator"
+	"github.com/juju/juju/worker/workertest"
+)
+
+var credentialTag = names.NewCloudCredentialTag("aws/bob/default")
+
+type Suite struct {
+	jujutesting.IsolationSuite
+
+	facade *mockFacade
+	config credentialvalidator.Config
+}
+
+var _ = gc.Suite(&Suite{})
+
+func (s *Suite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.facade = &mockFacade{
+		stub:    new(jujutesting.Stub),
+		changes: make(chan struct{}),
+		credential: apicredentialvalidator.ModelCredential{
+			Exists:     true,
+			Credential: credentialTag,
+			Valid:      true,
+		},
+		status: status.Available,
+	}
+	s.config = credentialvalidator.Config{
+		Facade: s.facade,
+	}
+}
+
+func (s *Suite) notifyChange(c *gc.C) {
+	select {
+	case s.facade.changes <- struct{}{}:
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out sending credential change")
+	}
+}
+
+func (s *Suite) TestInvalidConfig(c *gc.C) {
+	s.config.Facade = nil
+	_, err := credentialvalidator.New(s.config)
+	c.Check(err, gc.ErrorMatches, "nil Facade not valid")
+}
+
+func (s *Suite) TestValidCredential(c *gc.C) {
+	w, err := credentialvalidator.New(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.CleanKill(c, w)
+	c.Check(w.Check(), jc.IsTrue)
+
+	// The second change can only be delivered once the first has
+	// been handled.
+	s.notifyChange(c)
+	s.notifyChange(c)
+	workertest.CheckAlive(c, w)
+	c.Check(w.Check(), jc.IsTrue)
+	s.facade.stub.CheckCallNames(c,
+		"ModelCredential", "ModelStatus", "WatchCredential",
+		"ModelCredential", "ModelCredential",
+	)
+}
+
+func (s *Suite) TestNoCredential(c *gc.C) {
+	s.facade.credential = apicredentialvalidator.ModelCredential{}
+	w, err := credentialvalidator.New(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.CleanKill(c, w)
+	c.Check(w.Check(), jc.IsTrue)
+	workertest.CheckAlive(c, w)
+	s.facade.stub.CheckCallNames(c, "ModelCredential", "ModelStatus")
+}
+
+func (s *Suite) TestStartsInvalid(c *gc.C) {
+	s.facade.credential.Valid = false
+	w, err := credentialvalidator.New(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(w.Check(), jc.IsFalse)
+	workertest.CleanKill(c, w)
+	s.facade.stub.CheckCalls(c, []jujutesting.StubCall{{
+		"ModelCredential", nil,
+	}, {
+		"SetModelStatus", []interface{}{
+			status.Suspended,
+			`suspended since cloud credential "aws/bob/default" is not valid; use "juju update-credential" to fix it`,
+			map[string]interface{}{"credential": "aws/bob/default"},
+		},
+	}, {
+		"WatchCredential", []interface{}{credentialTag},
+	}})
+}
+
+func (s *Suite) TestStartsValidWhileSuspended(c *gc.C) {
+	s.facade.status = status.Suspended
+	w, err := credentialvalidator.New(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(w.Check(), jc.IsTrue)
+	workertest.CleanKill(c, w)
+	s.facade.stub.CheckCalls(c, []jujutesting.StubCall{{
+		"ModelCredential", nil,
+	}, {
+		"ModelStatus", nil,
+	}, {
+		"SetModelStatus", []interface{}{status.Available, "", map[string]interface{}(nil)},
+	}, {
+		"WatchCredential", []interface{}{credentialTag},
+	}})
+}
+
+func (s *Suite) TestBecomesInvalid(c *gc.C) {
+	w, err := credentialvalidator.New(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.DirtyKill(c, w)
+
+	s.facade.setValid(false)
+	s.notifyChange(c)
+	err = workertest.CheckKilled(c, w)
+	c.Check(err, gc.Equals, credentialvalidator.ErrChanged)
+	s.facade.stub.CheckCallNames(c,
+		"ModelCredential", "ModelStatus", "WatchCredential",
+		"ModelCredential", "SetModelStatus",
+	)
+}
+
+func (s *Suite) TestBecomesValid(c *gc.C) {
+	s.facade.credential.Valid = false
+	w, err := credentialvalidator.New(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.DirtyKill(c, w)
+
+	s.facade.setValid(true)
+	s.facade.status = status.Suspended
+	s.notifyChange(c)
+	err = workertest.CheckKilled(c, w)
+	c.Check(err, gc.Equals, credentialvalidator.ErrChanged)
+	s.facade.stub.CheckCallNames(c,
+		"ModelCredential", "SetModelStatus", "WatchCredential",
+		"ModelCredential", "ModelStatus", "SetModelStatus",
+	)
+	c.Check(s.facade.stub.Calls()[5].Args[0], gc.Equals, status.Available)
+}
+
+func (s *Suite) TestCredentialReplaced(c *gc.C) {
+	w, err := credentialvalidator.New(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.DirtyKill(c, w)
+
+	s.facade.mu.Lock()
+	s.facade.credential.Credential = names.NewCloudCredentialTag("aws/bob/other")
+	s.facade.mu.Unlock()
+	s.notifyChange(c)
+	err = workertest.CheckKilled(c, w)
+	c.Check(err, gc.Equals, credentialvalidator.ErrChanged)
+}
+
+func (s *Suite) TestSetModelStatusError(c *gc.C) {
+	s.facade.credential.Valid = false
+	s.facade.stub.SetErrors(nil, errors.New("blam"))
+	_, err := credentialvalidator.New(s.config)
+	c.Check(err, gc.ErrorMatches, "suspending model: blam")
+}
+
+type mockFacade struct {
+	stub    *jujutesting.Stub
+	changes chan struct{}
+
+	mu         sync.Mutex
+	credential apicredentialvalidator.ModelCredential
+	status     status.Status
+}
+
+func (f *mockFacade) setValid(valid bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.credential.Valid = valid
+}
+
+func (f *mockFacade) ModelCredential() (apicredentialvalidator.ModelCredential, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.stub.AddCall("ModelCredential")
+	return f.credential, f.stub.NextErr()
+}
+
+func (f *mockFacade) WatchCredential(tag names.CloudCredentialTag) (watcher.NotifyWatcher, error) {
+	f.stub.AddCall("WatchCredential", tag)
+	if err := f.stub.NextErr(); err != nil {
+		return nil, err
+	}
+	return watchertest.NewMockNotifyWatcher(f.changes), nil
+}
+
+func (f *mockFacade) ModelStatus() (status.Status, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.stub.AddCall("ModelStatus")
+	return f.status, f.stub.NextErr()
+}
+
+func (f *mockFacade) SetModelStatus(s status.Status, info string, data map[string]interface{}) error {
+	f.stub.AddCall("SetModelStatus", s, info, data)
+	return f.stub.NextErr()
+}
@@ -0,0 +1,66 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package dependency
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strconv"
+)
+
+// stateColours holds the fill colour of the manifolds in a DotGraph,
+// keyed on the state of their workers.
+var stateColours = map[string]string{
+	"starting": "khaki",
+	"started":  "palegreen",
+	"stopping": "orange",
+	"stopped":  "lightgrey",
+}
+
+// DotGraph renders an Engine's Report as a graph in the DOT language,
+// with an edge from each manifold to each of its inputs. Each manifold
+// is coloured by the state of its worker and labelled with the last
+// error the worker returned, so manifolds which cannot start, and the
+// inputs they are waiting for, stand out.
+func DotGraph(report map[string]interface{}) string {
+	manifolds, _ := report[KeyManifolds].(map[string]interface{})
+	names := make([]string, 0, len(manifolds))
+	for name := range manifolds {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var buf bytes.Buffer
+	fmt.Fprintln(&buf, "digraph dependencies {")
+	fmt.Fprintln(&buf, "\trankdir=LR;")
+	fmt.Fprintln(&buf, "\tnode [shape=box, style=filled];")
+	for _, name := range names {
+		manifold, _ := manifolds[name].(map[string]interface{})
+		state, _ := manifold[KeyState].(string)
+		label := name + "\n" + state
+		colour, ok := stateColours[state]
+		if !ok {
+			colour = "white"
+		}
+		if err, _ := manifold[KeyError].(string); err != "" {
+			label += "\n" + err
+			if state == "stopped" {
+				colour = "salmon"
+			}
+		}
+		fmt.Fprintf(&buf, "\t%s [fillcolor=%s, label=%s];\n",
+			strconv.Quote(name), colour, strconv.Quote(label),
+		)
+	}
+	for _, name := range names {
+		manifold, _ := manifolds[name].(map[string]interface{})
+		inputs, _ := manifold[KeyInputs].([]string)
+		for _, input := range inputs {
+			fmt.Fprintf(&buf, "\t%s -> %s;\n", strconv.Quote(name), strconv.Quote(input))
+		}
+	}
+	fmt.Fprintln(&buf, "}")
+	return buf.String()
+}
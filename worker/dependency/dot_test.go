@@ -0,0 +1,82 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package dependency_test
+
+import (
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/worker/dependency"
+)
+
+type DotSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&DotSuite{})
+
+func (s *DotSuite) TestEmpty(c *gc.C) {
+	dot := dependency.DotGraph(map[string]interface{}{
+		"state":     "started",
+		"manifolds": map[string]interface{}{},
+	})
+	c.Check(dot, gc.Equals, `
+digraph dependencies {
+	rankdir=LR;
+	node [shape=box, style=filled];
+}
+`[1:])
+}
+
+func (s *DotSuite) TestManifolds(c *gc.C) {
+	dot := dependency.DotGraph(map[string]interface{}{
+		"state": "started",
+		"manifolds": map[string]interface{}{
+			"agent": map[string]interface{}{
+				"state":  "started",
+				"inputs": []string(nil),
+			},
+			"api-caller": map[string]interface{}{
+				"state":  "stopped",
+				"inputs": []string{"agent"},
+				"error":  `cannot connect: "blam"`,
+			},
+			"machiner": map[string]interface{}{
+				"state":  "stopped",
+				"inputs": []string{"agent", "api-caller"},
+			},
+		},
+	})
+	c.Check(dot, gc.Equals, `
+digraph dependencies {
+	rankdir=LR;
+	node [shape=box, style=filled];
+	"agent" [fillcolor=palegreen, label="agent\nstarted"];
+	"api-caller" [fillcolor=salmon, label="api-caller\nstopped\ncannot connect: \"blam\""];
+	"machiner" [fillcolor=lightgrey, label="machiner\nstopped"];
+	"api-caller" -> "agent";
+	"machiner" -> "agent";
+	"machiner" -> "api-caller";
+}
+`[1:])
+}
+
+func (s *DotSuite) TestEngineReport(c *gc.C) {
+	fix := &engineFixture{}
+	fix.run(c, func(engine *dependency.Engine) {
+		err := engine.Install("some-task", dependency.Manifold{
+			Start: startMinimalWorker,
+		})
+		c.Assert(err, jc.ErrorIsNil)
+		err = engine.Install("other-task", dependency.Manifold{
+			Inputs: []string{"some-task"},
+			Start:  startMinimalWorker,
+		})
+		c.Assert(err, jc.ErrorIsNil)
+
+		dot := dependency.DotGraph(engine.Report())
+		c.Check(dot, gc.Matches, `(?s).*"other-task" -> "some-task";.*`)
+	})
+}
@@ -17,6 +17,10 @@ import (
 
 var logger = loggo.GetLogger("juju.worker.dependency")
 
+// maxErrorDelay is the longest an engine with a MaxRestartBurst will
+// wait before restarting a failing worker.
+const maxErrorDelay = 5 * time.Minute
+
 // EngineConfig defines the parameters needed to create a new engine.
 type EngineConfig struct {
 
@@ -42,6 +46,24 @@ type EngineConfig struct {
 	// a worker that was deliberately stopped because its dependencies
 	// changed. It must not be negative.
 	BounceDelay time.Duration
+
+	// MaxRestartBurst, if positive, is the number of times in a row
+	// a worker may stop with an unknown error and be restarted after
+	// ErrorDelay. Each further restart waits twice as long as the one
+	// before, up to maxErrorDelay, so that a worker which keeps failing
+	// does not keep the machine busy; the count is reset when the worker
+	// stops for any other reason. It must not be negative, and requires
+	// a positive ErrorDelay.
+	MaxRestartBurst int
+
+	// ShutdownTimeout, if positive, makes the engine stop its workers
+	// in order as it shuts down: each worker is stopped only once the
+	// workers that depend on it have stopped, so that (say) a uniter
+	// can finish running a hook while its API connection is still
+	// available. Once ShutdownTimeout has passed, any workers still
+	// running are stopped regardless. If ShutdownTimeout is zero, all
+	// workers are stopped at once. It must not be negative.
+	ShutdownTimeout time.Duration
 }
 
 // Validate returns an error if any field is invalid.
@@ -58,6 +80,15 @@ func (config *EngineConfig) Validate() error {
 	if config.BounceDelay < 0 {
 		return errors.New("BounceDelay is negative")
 	}
+	if config.MaxRestartBurst < 0 {
+		return errors.New("MaxRestartBurst is negative")
+	}
+	if config.MaxRestartBurst > 0 && config.ErrorDelay == 0 {
+		return errors.New("MaxRestartBurst requires a positive ErrorDelay")
+	}
+	if config.ShutdownTimeout < 0 {
+		return errors.New("ShutdownTimeout is negative")
+	}
 	return nil
 }
 
@@ -135,13 +166,22 @@ type Engine struct {
 // in this method.)
 func (engine *Engine) loop() error {
 	oneShotDying := engine.tomb.Dying()
+	// shutdownTimeout is only set while workers are being stopped in
+	// dependency order.
+	var shutdownTimeout <-chan time.Time
 	for {
 		select {
 		case <-oneShotDying:
 			oneShotDying = nil
-			for name := range engine.current {
-				engine.requestStop(name)
+			if engine.config.ShutdownTimeout > 0 {
+				shutdownTimeout = time.After(engine.config.ShutdownTimeout)
+			} else {
+				engine.stopAll()
 			}
+		case <-shutdownTimeout:
+			shutdownTimeout = nil
+			logger.Warningf("workers still running after %s; stopping all workers", engine.config.ShutdownTimeout)
+			engine.stopAll()
 		case ticket := <-engine.report:
 			// This is safe so long as the Report method reads the result.
 			ticket.result <- engine.liveReport()
@@ -154,6 +194,9 @@ func (engine *Engine) loop() error {
 			engine.gotStopped(ticket.name, ticket.error, ticket.resourceLog)
 		}
 		if engine.isDying() {
+			if shutdownTimeout != nil {
+				engine.stopLeaves()
+			}
 			if engine.allOthersStopped() {
 				return tomb.ErrDying
 			}
@@ -234,6 +277,7 @@ func (engine *Engine) manifoldsReport() map[string]interface{} {
 			KeyState:       info.state(),
 			KeyInputs:      engine.manifolds[name].Inputs,
 			KeyResourceLog: resourceLogReport(info.resourceLog),
+			KeyStartCount:  info.startCount,
 		}
 		if info.err != nil {
 			report[KeyError] = info.err.Error()
@@ -487,6 +531,8 @@ func (engine *Engine) gotStarted(name string, worker worker.Worker, resourceLog
 		engine.current[name] = workerInfo{
 			worker:      worker,
 			resourceLog: resourceLog,
+			startCount:  info.startCount + 1,
+			failures:    info.failures,
 		}
 
 		// Any manifold that declares this one as an input needs to be restarted.
@@ -519,6 +565,7 @@ func (engine *Engine) gotStopped(name string, err error, resourceLog []resourceA
 	engine.current[name] = workerInfo{
 		err:         err,
 		resourceLog: resourceLog,
+		startCount:  info.startCount,
 	}
 	if engine.isDying() {
 		logger.Tracef("permanently stopped %q manifold worker (shutting down)", name)
@@ -552,7 +599,9 @@ func (engine *Engine) gotStopped(name string, err error, resourceLog []resourceA
 			if tracer, ok := err.(stackTracer); ok {
 				logger.Debugf("stack trace:\n%s", strings.Join(tracer.StackTrace(), "\n"))
 			}
-			engine.requestStart(name, engine.config.ErrorDelay)
+			failures := info.failures + 1
+			engine.setFailures(name, failures)
+			engine.requestStart(name, engine.errorDelay(failures))
 		}
 	}
 
@@ -563,6 +612,33 @@ func (engine *Engine) gotStopped(name string, err error, resourceLog []resourceA
 	}
 }
 
+// setFailures records the number of times in a row the named manifold's
+// worker has stopped with an unknown error. It must only be called from
+// the loop goroutine.
+func (engine *Engine) setFailures(name string, failures int) {
+	info := engine.current[name]
+	info.failures = failures
+	engine.current[name] = info
+}
+
+// errorDelay returns how long to wait before restarting a worker that
+// has stopped with an unknown error the supplied number of times in a
+// row.
+func (engine *Engine) errorDelay(failures int) time.Duration {
+	delay := engine.config.ErrorDelay
+	burst := engine.config.MaxRestartBurst
+	if burst <= 0 {
+		return delay
+	}
+	for i := burst; i < failures && delay < maxErrorDelay; i++ {
+		delay *= 2
+	}
+	if delay > maxErrorDelay {
+		delay = maxErrorDelay
+	}
+	return delay
+}
+
 // requestStop ensures that any running or starting worker will be stopped in the
 // near future. It must only be called from the loop goroutine.
 func (engine *Engine) requestStop(name string) {
@@ -585,6 +661,40 @@ func (engine *Engine) requestStop(name string) {
 	engine.current[name] = info
 }
 
+// stopAll requests that every worker stop. It must only be called from
+// the loop goroutine.
+func (engine *Engine) stopAll() {
+	for name := range engine.current {
+		engine.requestStop(name)
+	}
+}
+
+// stopLeaves requests that every worker stop whose dependents have all
+// stopped; so, as it is called while the engine shuts down, workers
+// stop before the workers they depend on. It must only be called from
+// the loop goroutine.
+func (engine *Engine) stopLeaves() {
+	for name := range engine.current {
+		if engine.dependentsStopped(name) {
+			engine.requestStop(name)
+		}
+	}
+}
+
+// dependentsStopped returns true if no workers (other than the engine
+// itself, if it happens to have been injected) that depend on the named
+// manifold are running or starting. It must only be called from the
+// loop goroutine.
+func (engine *Engine) dependentsStopped(name string) bool {
+	for _, dependentName := range engine.dependents[name] {
+		info := engine.current[dependentName]
+		if !info.stopped() && info.worker != engine {
+			return false
+		}
+	}
+	return true
+}
+
 // isDying returns true if the engine is shutting down. It's safe to call it
 // from any goroutine.
 func (engine *Engine) isDying() bool {
@@ -631,6 +741,13 @@ type workerInfo struct {
 	worker      worker.Worker
 	err         error
 	resourceLog []resourceAccess
+
+	// startCount is the number of workers started for the manifold.
+	startCount int
+
+	// failures is the number of times in a row the manifold's worker
+	// has stopped with an unknown error.
+	failures int
 }
 
 // stopped returns true unless the worker is either assigned or starting.
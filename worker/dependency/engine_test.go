@@ -667,6 +667,71 @@ func (s *EngineSuite) TestWorstError(c *gc.C) {
 	})
 }
 
+func (s *EngineSuite) TestShutdownOrder(c *gc.C) {
+	s.fix.shutdownTimeout = coretesting.LongWait
+	s.fix.run(c, func(engine *dependency.Engine) {
+		infra := newShutdownWorkerManifold()
+		err := engine.Install("infra", infra.manifold())
+		c.Assert(err, jc.ErrorIsNil)
+		infra.waitStarted(c)
+
+		leaf := newShutdownWorkerManifold("infra")
+		err = engine.Install("leaf", leaf.manifold())
+		c.Assert(err, jc.ErrorIsNil)
+		leaf.waitStarted(c)
+
+		// The leaf is stopped first, and the infra worker is only
+		// stopped once the leaf worker has finished.
+		engine.Kill()
+		leaf.waitKilled(c)
+		infra.checkNotKilled(c)
+		close(leaf.release)
+		infra.waitKilled(c)
+	})
+}
+
+func (s *EngineSuite) TestShutdownTimeout(c *gc.C) {
+	s.fix.shutdownTimeout = coretesting.ShortWait
+	s.fix.run(c, func(engine *dependency.Engine) {
+		infra := newShutdownWorkerManifold()
+		err := engine.Install("infra", infra.manifold())
+		c.Assert(err, jc.ErrorIsNil)
+		infra.waitStarted(c)
+
+		leaf := newShutdownWorkerManifold("infra")
+		err = engine.Install("leaf", leaf.manifold())
+		c.Assert(err, jc.ErrorIsNil)
+		leaf.waitStarted(c)
+
+		// The infra worker is stopped once the timeout passes, even
+		// though the leaf worker is still running.
+		engine.Kill()
+		leaf.waitKilled(c)
+		infra.waitKilled(c)
+		close(leaf.release)
+	})
+}
+
+func (s *EngineSuite) TestMaxRestartBurst(c *gc.C) {
+	s.fix.maxRestartBurst = 2
+	s.fix.run(c, func(engine *dependency.Engine) {
+		mh := newManifoldHarness()
+		err := engine.Install("some-task", mh.Manifold())
+		c.Assert(err, jc.ErrorIsNil)
+		mh.AssertStart(c)
+
+		// The first failures are restarted after ErrorDelay; later
+		// ones wait twice as long each time.
+		for i := 0; i < 3; i++ {
+			mh.InjectError(c, errors.New("boom"))
+			mh.AssertStart(c)
+		}
+		mh.InjectError(c, errors.New("boom"))
+		mh.AssertNoStart(c)
+		mh.AssertStart(c)
+	})
+}
+
 func (s *EngineSuite) TestConfigValidate(c *gc.C) {
 	tests := []struct {
 		breakConfig func(*dependency.EngineConfig)
@@ -687,6 +752,19 @@ func (s *EngineSuite) TestConfigValidate(c *gc.C) {
 		func(config *dependency.EngineConfig) {
 			config.BounceDelay = -time.Second
 		}, "BounceDelay is negative",
+	}, {
+		func(config *dependency.EngineConfig) {
+			config.ShutdownTimeout = -time.Second
+		}, "ShutdownTimeout is negative",
+	}, {
+		func(config *dependency.EngineConfig) {
+			config.MaxRestartBurst = -1
+		}, "MaxRestartBurst is negative",
+	}, {
+		func(config *dependency.EngineConfig) {
+			config.ErrorDelay = 0
+			config.MaxRestartBurst = 3
+		}, "MaxRestartBurst requires a positive ErrorDelay",
 	}}
 
 	for i, test := range tests {
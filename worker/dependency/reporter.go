@@ -57,6 +57,10 @@ const (
 	// error encountered.
 	KeyResourceLog = "resource-log"
 
+	// KeyStartCount holds the number of workers started for a manifold;
+	// one that keeps growing indicates a worker which keeps failing.
+	KeyStartCount = "start-count"
+
 	// KeyName holds the name of some resource.
 	KeyName = "name"
 
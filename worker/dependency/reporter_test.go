@@ -6,6 +6,7 @@ package dependency_test
 import (
 	"time"
 
+	"github.com/juju/errors"
 	"github.com/juju/testing"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
@@ -94,6 +95,7 @@ func (s *ReportSuite) TestReportStopping(c *gc.C) {
 					"state":        "stopping",
 					"inputs":       ([]string)(nil),
 					"resource-log": []map[string]interface{}{},
+					"start-count":  1,
 					"report": map[string]interface{}{
 						"key1": "hello there",
 					},
@@ -123,6 +125,7 @@ func (s *ReportSuite) TestReportInputs(c *gc.C) {
 					"state":        "started",
 					"inputs":       ([]string)(nil),
 					"resource-log": []map[string]interface{}{},
+					"start-count":  1,
 					"report": map[string]interface{}{
 						"key1": "hello there",
 					},
@@ -134,6 +137,7 @@ func (s *ReportSuite) TestReportInputs(c *gc.C) {
 						"name": "task",
 						"type": "<nil>",
 					}},
+					"start-count": 1,
 					"report": map[string]interface{}{
 						"key1": "hello there",
 					},
@@ -165,8 +169,30 @@ func (s *ReportSuite) TestReportError(c *gc.C) {
 						"type":  "<nil>",
 						"error": `"missing" not running: dependency not available`,
 					}},
+					"start-count": 0,
 				},
 			},
 		})
 	})
 }
+
+func (s *ReportSuite) TestReportStartCount(c *gc.C) {
+	s.fix.run(c, func(engine *dependency.Engine) {
+		mh1 := newManifoldHarness()
+		err := engine.Install("task", mh1.Manifold())
+		c.Assert(err, jc.ErrorIsNil)
+		mh1.AssertOneStart(c)
+		mh1.InjectError(c, errors.New("ZAP"))
+		mh1.AssertOneStart(c)
+
+		// The engine may not yet have recorded the second start.
+		startCount := func() interface{} {
+			manifolds := engine.Report()["manifolds"].(map[string]interface{})
+			return manifolds["task"].(map[string]interface{})["start-count"]
+		}
+		for i := 0; i < 3 && startCount() != 2; i++ {
+			time.Sleep(coretesting.ShortWait)
+		}
+		c.Check(startCount(), gc.Equals, 2)
+	})
+}
@@ -21,6 +21,9 @@ type engineFixture struct {
 	worstError dependency.WorstErrorFunc
 	filter     dependency.FilterFunc
 	dirty      bool
+
+	shutdownTimeout time.Duration
+	maxRestartBurst int
 }
 
 func (fix *engineFixture) isFatalFunc() dependency.IsFatalFunc {
@@ -44,6 +47,9 @@ func (fix *engineFixture) run(c *gc.C, test func(*dependency.Engine)) {
 		Filter:      fix.filter, // can be nil anyway
 		ErrorDelay:  coretesting.ShortWait / 2,
 		BounceDelay: coretesting.ShortWait / 10,
+
+		MaxRestartBurst: fix.maxRestartBurst,
+		ShutdownTimeout: fix.shutdownTimeout,
 	}
 
 	engine, err := dependency.NewEngine(config)
@@ -202,3 +208,87 @@ func alwaysFatal(_ error) bool {
 func firstError(err, _ error) error {
 	return err
 }
+
+// shutdownWorkerManifold starts workers which report when they are
+// killed, and finish when their release channel is closed, if it is
+// not nil.
+type shutdownWorkerManifold struct {
+	inputs  []string
+	starts  chan struct{}
+	killed  chan struct{}
+	release chan struct{}
+}
+
+func newShutdownWorkerManifold(inputs ...string) *shutdownWorkerManifold {
+	swm := &shutdownWorkerManifold{
+		inputs: inputs,
+		starts: make(chan struct{}, 1000),
+		killed: make(chan struct{}, 1000),
+	}
+	if len(inputs) > 0 {
+		swm.release = make(chan struct{})
+	}
+	return swm
+}
+
+func (swm *shutdownWorkerManifold) manifold() dependency.Manifold {
+	return dependency.Manifold{
+		Inputs: swm.inputs,
+		Start:  swm.start,
+	}
+}
+
+func (swm *shutdownWorkerManifold) start(context dependency.Context) (worker.Worker, error) {
+	w := &shutdownWorker{
+		killed:  swm.killed,
+		release: swm.release,
+	}
+	go func() {
+		defer w.tomb.Done()
+		<-w.tomb.Dying()
+		if w.release != nil {
+			<-w.release
+		}
+	}()
+	swm.starts <- struct{}{}
+	return w, nil
+}
+
+func (swm *shutdownWorkerManifold) waitStarted(c *gc.C) {
+	select {
+	case <-swm.starts:
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("worker never started")
+	}
+}
+
+func (swm *shutdownWorkerManifold) waitKilled(c *gc.C) {
+	select {
+	case <-swm.killed:
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("worker never killed")
+	}
+}
+
+func (swm *shutdownWorkerManifold) checkNotKilled(c *gc.C) {
+	select {
+	case <-swm.killed:
+		c.Fatalf("worker killed unexpectedly")
+	case <-time.After(coretesting.ShortWait):
+	}
+}
+
+type shutdownWorker struct {
+	tomb    tomb.Tomb
+	killed  chan<- struct{}
+	release <-chan struct{}
+}
+
+func (w *shutdownWorker) Kill() {
+	w.killed <- struct{}{}
+	w.tomb.Kill(nil)
+}
+
+func (w *shutdownWorker) Wait() error {
+	return w.tomb.Wait()
+}
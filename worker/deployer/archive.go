@@ -0,0 +1,148 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package deployer
+
+import (
+	"compress/gzip"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils/tar"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/agent"
+)
+
+const (
+	// unitArchiveDir is the directory, below the data directory,
+	// in which the agent files of recalled units are archived.
+	unitArchiveDir = "unit-archives"
+
+	// unitArchiveSuffix is the suffix of the names of archives.
+	unitArchiveSuffix = ".tar.gz"
+
+	// unitArchiveTimeFormat formats the time at which an archive
+	// was made, in its name.
+	unitArchiveTimeFormat = "20060102T150405Z"
+)
+
+// UnitArchiveDir returns the directory in which the agent files of
+// units recalled from the machine with the specified data directory
+// are archived.
+func UnitArchiveDir(dataDir string) string {
+	return filepath.Join(dataDir, unitArchiveDir)
+}
+
+// archiveAgentFiles writes the agent directory of the specified unit,
+// which holds its charm and state, to a gzipped tarball in the unit
+// archive directory, and returns the path of the tarball. The tarball
+// is named after the unit and the specified time.
+func archiveAgentFiles(dataDir, unitName string, now time.Time) (_ string, err error) {
+	tag := names.NewUnitTag(unitName)
+	dir := UnitArchiveDir(dataDir)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", errors.Trace(err)
+	}
+	name := tag.String() + "-" + now.UTC().Format(unitArchiveTimeFormat) + unitArchiveSuffix
+	path := filepath.Join(dir, name)
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	defer removeOnErr(&err, path)
+	defer f.Close()
+
+	tarball := gzip.NewWriter(f)
+	agentDir := agent.Dir(dataDir, tag)
+	stripPrefix := filepath.Dir(agentDir) + string(os.PathSeparator)
+	if _, err := tar.TarFiles([]string{agentDir}, tarball, stripPrefix); err != nil {
+		return "", errors.Annotatef(err, "archiving agent files of unit %q", unitName)
+	}
+	if err := tarball.Close(); err != nil {
+		return "", errors.Trace(err)
+	}
+	return path, errors.Trace(f.Close())
+}
+
+// pruneAgentArchives removes the archives in the unit archive
+// directory which were last modified longer than maxAge before
+// the specified time.
+func pruneAgentArchives(dataDir string, maxAge time.Duration, now time.Time) error {
+	dir := UnitArchiveDir(dataDir)
+	infos, err := ioutil.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	for _, info := range infos {
+		if info.IsDir() || !strings.HasSuffix(info.Name(), unitArchiveSuffix) {
+			continue
+		}
+		if now.Sub(info.ModTime()) <= maxAge {
+			continue
+		}
+		logger.Infof("removing unit archive %q", info.Name())
+		if err := os.Remove(filepath.Join(dir, info.Name())); err != nil && !os.IsNotExist(err) {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+// archiver archives the agent files of recalled units, if the
+// maximum archive age is set.
+type archiver struct {
+	dataDir string
+
+	// mu guards maxAge, which is how long archives are kept.
+	mu     sync.Mutex
+	maxAge time.Duration
+}
+
+func newArchiver(dataDir string) *archiver {
+	return &archiver{dataDir: dataDir}
+}
+
+// setMaxAge sets how long archives are kept, and prunes those
+// which are now too old. Archiving is disabled if it is zero.
+func (a *archiver) setMaxAge(maxAge time.Duration) {
+	a.mu.Lock()
+	a.maxAge = maxAge
+	a.mu.Unlock()
+	if maxAge == 0 {
+		return
+	}
+	if err := pruneAgentArchives(a.dataDir, maxAge, time.Now()); err != nil {
+		logger.Errorf("cannot prune unit archives: %v", err)
+	}
+}
+
+// archive archives the agent files of the specified unit, if
+// archiving is enabled, and prunes archives which are too old.
+// Failures are logged, so that the agent files can still be
+// removed.
+func (a *archiver) archive(unitName string) {
+	a.mu.Lock()
+	maxAge := a.maxAge
+	a.mu.Unlock()
+	if maxAge == 0 {
+		return
+	}
+	now := time.Now()
+	path, err := archiveAgentFiles(a.dataDir, unitName, now)
+	if err != nil {
+		logger.Errorf("cannot archive agent files of unit %q: %v", unitName, err)
+	} else {
+		logger.Infof("archived agent files of unit %q to %q", unitName, path)
+	}
+	if err := pruneAgentArchives(a.dataDir, maxAge, now); err != nil {
+		logger.Errorf("cannot prune unit archives: %v", err)
+	}
+}
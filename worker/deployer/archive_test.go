@@ -0,0 +1,101 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package deployer_test
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/agent"
+	"github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/deployer"
+)
+
+type ArchiveSuite struct {
+	testing.BaseSuite
+	dataDir string
+}
+
+var _ = gc.Suite(&ArchiveSuite{})
+
+func (s *ArchiveSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.dataDir = c.MkDir()
+}
+
+func (s *ArchiveSuite) TestArchiveAgentFiles(c *gc.C) {
+	agentDir := agent.Dir(s.dataDir, names.NewUnitTag("foo/123"))
+	err := os.MkdirAll(filepath.Join(agentDir, "charm"), 0755)
+	c.Assert(err, jc.ErrorIsNil)
+	err = ioutil.WriteFile(filepath.Join(agentDir, "charm", "metadata.yaml"), []byte("name: foo"), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+
+	now := time.Date(2018, 3, 4, 5, 6, 7, 0, time.UTC)
+	path, err := deployer.ArchiveAgentFiles(s.dataDir, "foo/123", now)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(path, gc.Equals, filepath.Join(
+		deployer.UnitArchiveDir(s.dataDir), "unit-foo-123-20180304T050607Z.tar.gz",
+	))
+
+	f, err := os.Open(path)
+	c.Assert(err, jc.ErrorIsNil)
+	defer f.Close()
+	gzr, err := gzip.NewReader(f)
+	c.Assert(err, jc.ErrorIsNil)
+	tr := tar.NewReader(gzr)
+	contents := make(map[string]string)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		c.Assert(err, jc.ErrorIsNil)
+		data, err := ioutil.ReadAll(tr)
+		c.Assert(err, jc.ErrorIsNil)
+		contents[hdr.Name] = string(data)
+	}
+	c.Assert(contents["unit-foo-123/charm/metadata.yaml"], gc.Equals, "name: foo")
+}
+
+func (s *ArchiveSuite) TestPruneAgentArchives(c *gc.C) {
+	dir := deployer.UnitArchiveDir(s.dataDir)
+	err := os.MkdirAll(dir, 0700)
+	c.Assert(err, jc.ErrorIsNil)
+	now := time.Now()
+	for name, age := range map[string]time.Duration{
+		"unit-foo-0-old.tar.gz": 2 * time.Hour,
+		"unit-foo-1-new.tar.gz": 30 * time.Minute,
+		"not-an-archive":        2 * time.Hour,
+	} {
+		path := filepath.Join(dir, name)
+		err := ioutil.WriteFile(path, nil, 0600)
+		c.Assert(err, jc.ErrorIsNil)
+		err = os.Chtimes(path, now.Add(-age), now.Add(-age))
+		c.Assert(err, jc.ErrorIsNil)
+	}
+
+	err = deployer.PruneAgentArchives(s.dataDir, time.Hour, now)
+	c.Assert(err, jc.ErrorIsNil)
+	infos, err := ioutil.ReadDir(dir)
+	c.Assert(err, jc.ErrorIsNil)
+	var remaining []string
+	for _, info := range infos {
+		remaining = append(remaining, info.Name())
+	}
+	c.Assert(remaining, jc.DeepEquals, []string{"not-an-archive", "unit-foo-1-new.tar.gz"})
+}
+
+func (s *ArchiveSuite) TestPruneAgentArchivesNoDir(c *gc.C) {
+	err := deployer.PruneAgentArchives(s.dataDir, time.Hour, time.Now())
+	c.Assert(err, jc.ErrorIsNil)
+}
@@ -0,0 +1,346 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package deployer
+
+import (
+	"sync"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils/arch"
+	"github.com/juju/utils/series"
+	"github.com/juju/utils/shell"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/agent"
+	"github.com/juju/juju/agent/tools"
+	"github.com/juju/juju/cloudconfig/cloudinit"
+	"github.com/juju/juju/container/lxd"
+	"github.com/juju/juju/service/common"
+	"github.com/juju/juju/service/systemd"
+	"github.com/juju/juju/tools/lxdclient"
+)
+
+// unitContainerPrefix prefixes the names of the LXD containers
+// in which unit agents are run; the unit's service name follows.
+const unitContainerPrefix = "jujud-unit-"
+
+// LXDClient defines the LXD operations that the container context
+// needs.
+type LXDClient interface {
+	EnsureImageExists(series, arch string, sources []lxdclient.Remote, copyProgressHandler func(string)) (string, error)
+	AddInstance(spec lxdclient.InstanceSpec) (*lxdclient.Instance, error)
+	Instances(prefix string, statuses ...string) ([]lxdclient.Instance, error)
+	RemoveInstances(prefix string, names ...string) error
+}
+
+// ContainerContext is a Context that deploys each unit agent into
+// its own LXD container on the local system, isolating the files
+// and processes of units deployed to the same machine. The agent's
+// tools, config and log directories are bind-mounted from the
+// machine into the container, where the agent is run by systemd.
+// It is safe to use concurrently for different units.
+type ContainerContext struct {
+
+	// api is used to get the current controller addresses at the time the
+	// given unit is deployed.
+	api APICalls
+
+	// agentConfig returns the agent config for the machine agent that is
+	// running the deployer.
+	agentConfig agent.Config
+
+	// connect returns the client used to manage the containers.
+	connect func() (LXDClient, error)
+
+	// archiver archives the agent files of recalled units.
+	archiver *archiver
+
+	// mu guards the fields below.
+	mu sync.Mutex
+
+	// loggingConfig is the logging config with which the unit
+	// agents are started.
+	loggingConfig string
+
+	// client is the client returned by connect.
+	client LXDClient
+}
+
+var _ Context = (*ContainerContext)(nil)
+
+// NewContainerContext returns a new ContainerContext, acting on behalf
+// of the specified deployer, that deploys unit agents into containers
+// managed by the local LXD daemon.
+func NewContainerContext(agentConfig agent.Config, api APICalls) *ContainerContext {
+	return NewContainerContextWithClient(agentConfig, api, func() (LXDClient, error) {
+		return lxd.ConnectLocal()
+	})
+}
+
+// NewContainerContextWithClient returns a new ContainerContext which
+// manages containers using the client returned by connect, which is
+// called when the client is first needed.
+func NewContainerContextWithClient(agentConfig agent.Config, api APICalls, connect func() (LXDClient, error)) *ContainerContext {
+	return &ContainerContext{
+		api:           api,
+		agentConfig:   agentConfig,
+		loggingConfig: agentLoggingConfig(agentConfig),
+		connect:       connect,
+		archiver:      newArchiver(agentConfig.DataDir()),
+	}
+}
+
+// AgentConfig is part of the Context interface.
+func (ctx *ContainerContext) AgentConfig() agent.Config {
+	return ctx.agentConfig
+}
+
+// LoggingConfig is part of the Context interface.
+func (ctx *ContainerContext) LoggingConfig() string {
+	ctx.mu.Lock()
+	defer ctx.mu.Unlock()
+	return ctx.loggingConfig
+}
+
+// SetLoggingConfig is part of the Context interface. The logging
+// config of agents already deployed is not changed.
+func (ctx *ContainerContext) SetLoggingConfig(loggingConfig string) {
+	ctx.mu.Lock()
+	defer ctx.mu.Unlock()
+	ctx.loggingConfig = loggingConfig
+}
+
+// SetUnitAgentUsers is part of the Context interface. Unit agents
+// are always run as root, since they are already isolated from each
+// other by their containers.
+func (ctx *ContainerContext) SetUnitAgentUsers(enabled bool) {}
+
+// SetMaxArchiveAge is part of the Context interface.
+func (ctx *ContainerContext) SetMaxArchiveAge(maxAge time.Duration) {
+	ctx.archiver.setMaxAge(maxAge)
+}
+
+func (ctx *ContainerContext) lxdClient() (LXDClient, error) {
+	ctx.mu.Lock()
+	defer ctx.mu.Unlock()
+	if ctx.client != nil {
+		return ctx.client, nil
+	}
+	client, err := ctx.connect()
+	if err != nil {
+		return nil, errors.Annotate(err, "connecting to local LXD")
+	}
+	ctx.client = client
+	return client, nil
+}
+
+// DeployUnit is part of the Context interface. Nothing is left behind
+// if it fails, and a deployment interrupted by the agent stopping is
+// rolled back before the unit is deployed again.
+func (ctx *ContainerContext) DeployUnit(unitName, initialPassword string, limits ResourceLimits) error {
+	client, err := ctx.lxdClient()
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	// Link the current tools for use by the new agent, write
+	// its config, and start a container that runs it.
+	steps := agentFileSteps(ctx.agentConfig, ctx.api, unitName, initialPassword)
+	steps = append(steps, deployStep{
+		name: "container",
+		run: func() error {
+			return ctx.startContainer(client, unitName, limits)
+		},
+		undo: func() error {
+			deployed, err := ctx.deployedUnitContainers()
+			if err != nil {
+				return errors.Trace(err)
+			}
+			name, ok := deployed[unitName]
+			if !ok {
+				return nil
+			}
+			return client.RemoveInstances(unitContainerPrefix, name)
+		},
+	})
+	deployment := newDeployment(ctx.agentConfig.DataDir(), unitName, steps...)
+	if err := deployment.recover(); err != nil {
+		return errors.Trace(err)
+	}
+	deployed, err := ctx.deployedUnitContainers()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if _, ok := deployed[unitName]; ok {
+		return errors.Errorf("unit %q is already deployed", unitName)
+	}
+	return errors.Trace(deployment.run())
+}
+
+// startContainer starts the container running the agent of the
+// specified unit.
+func (ctx *ContainerContext) startContainer(client LXDClient, unitName string, limits ResourceLimits) error {
+	// The container runs the same series as the machine,
+	// so that it can run the machine's tools, and is named
+	// after the service which runs the agent inside it.
+	hostSeries, err := series.HostSeries()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	renderer, err := shell.NewRenderer("")
+	if err != nil {
+		return errors.Trace(err)
+	}
+	name, svcConf, err := unitService(ctx.agentConfig, unitName, ctx.LoggingConfig(), renderer)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	limits.apply(&svcConf)
+	userData, err := ctx.userData(name, svcConf, hostSeries)
+	if err != nil {
+		return errors.Annotate(err, "generating container user data")
+	}
+	image, err := client.EnsureImageExists(hostSeries, arch.HostArch(), lxdclient.DefaultImageSources, nil)
+	if err != nil {
+		return errors.Annotate(err, "ensuring LXD image")
+	}
+	spec := lxdclient.InstanceSpec{
+		Name:  name,
+		Image: image,
+		Metadata: map[string]string{
+			lxdclient.UserdataKey: string(userData),
+			"user.juju-unit":      unitName,
+			// Make sure the agent comes back up on machine reboot.
+			"boot.autostart": "true",
+		},
+		Devices:  ctx.agentDevices(unitName),
+		Profiles: []string{"default"},
+	}
+	logger.Infof("starting container %q for unit %q", name, unitName)
+	if _, err := client.AddInstance(spec); err != nil {
+		return errors.Annotatef(err, "starting container for unit %q", unitName)
+	}
+	return nil
+}
+
+// UpdateUnit is part of the Context interface. The services of unit
+// agents are installed when their containers are first started, so
+// cannot be updated.
+func (ctx *ContainerContext) UpdateUnit(unitName string, limits ResourceLimits) error {
+	return errors.NotSupportedf("updating the agent of unit %q in its container", unitName)
+}
+
+// userData returns the cloud-init user data which installs and starts
+// the systemd service running the unit agent inside its container.
+func (ctx *ContainerContext) userData(svcName string, svcConf common.Conf, hostSeries string) ([]byte, error) {
+	svc, err := systemd.NewService(svcName, svcConf, ctx.agentConfig.DataDir())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	installCommands, err := svc.InstallCommands()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	startCommands, err := svc.StartCommands()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	cloudConfig, err := cloudinit.New(hostSeries)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	cloudConfig.AddScripts(installCommands...)
+	cloudConfig.AddScripts(startCommands...)
+	return cloudConfig.RenderYAML()
+}
+
+// agentDevices returns the disk devices which bind-mount the unit
+// agent's tools, config and logs into its container, at the paths
+// used on the machine.
+func (ctx *ContainerContext) agentDevices(unitName string) lxdclient.Devices {
+	tag := names.NewUnitTag(unitName)
+	dataDir := ctx.agentConfig.DataDir()
+	disk := func(path string, readOnly bool) lxdclient.Device {
+		device := lxdclient.Device{
+			"type":   "disk",
+			"source": path,
+			"path":   path,
+		}
+		if readOnly {
+			device["readonly"] = "true"
+		}
+		return device
+	}
+	return lxdclient.Devices{
+		"juju-tools": disk(tools.ToolsDir(dataDir, tag.String()), true),
+		"juju-agent": disk(agent.Dir(dataDir, tag), false),
+		"juju-logs":  disk(ctx.agentConfig.LogDir(), false),
+	}
+}
+
+// RecallUnit is part of the Context interface.
+func (ctx *ContainerContext) RecallUnit(unitName string) error {
+	client, err := ctx.lxdClient()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	deployed, err := ctx.deployedUnitContainers()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	name, ok := deployed[unitName]
+	if !ok {
+		return errors.Errorf("unit %q is not deployed", unitName)
+	}
+	if err := client.RemoveInstances(unitContainerPrefix, name); err != nil {
+		return errors.Annotatef(err, "removing container for unit %q", unitName)
+	}
+	ctx.archiver.archive(unitName)
+	return removeAgentFiles(ctx.agentConfig.DataDir(), unitName)
+}
+
+// DeployedUnits is part of the Context interface.
+func (ctx *ContainerContext) DeployedUnits() ([]string, error) {
+	deployed, err := ctx.deployedUnitContainers()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var units []string
+	for unitName := range deployed {
+		units = append(units, unitName)
+	}
+	return units, nil
+}
+
+// CheckUnit is part of the Context interface. The introspection
+// sockets of unit agents can't be reached from outside their
+// containers.
+func (ctx *ContainerContext) CheckUnit(unitName string) error {
+	return errors.NotSupportedf("checking the agent of unit %q in its container", unitName)
+}
+
+// deployedUnitContainers returns the names of the containers
+// running unit agents, keyed on unit name.
+func (ctx *ContainerContext) deployedUnitContainers() (map[string]string, error) {
+	client, err := ctx.lxdClient()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	instances, err := client.Instances(unitContainerPrefix)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	deployed := make(map[string]string)
+	for _, inst := range instances {
+		if groups := deployedRe.FindStringSubmatch(inst.Name); len(groups) > 0 {
+			unitName := groups[2] + "/" + groups[3]
+			if !names.IsValidUnit(unitName) {
+				continue
+			}
+			deployed[unitName] = groups[1]
+		}
+	}
+	return deployed, nil
+}
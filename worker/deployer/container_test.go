@@ -0,0 +1,184 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package deployer_test
+
+import (
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/agent"
+	"github.com/juju/juju/agent/tools"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/tools/lxdclient"
+	"github.com/juju/juju/worker/deployer"
+)
+
+type ContainerContextSuite struct {
+	SimpleToolsFixture
+	client *fakeLXDClient
+}
+
+var _ = gc.Suite(&ContainerContextSuite{})
+
+func (s *ContainerContextSuite) SetUpTest(c *gc.C) {
+	s.SimpleToolsFixture.SetUp(c, c.MkDir())
+	s.client = &fakeLXDClient{}
+}
+
+func (s *ContainerContextSuite) TearDownTest(c *gc.C) {
+	s.SimpleToolsFixture.TearDown(c)
+}
+
+func (s *ContainerContextSuite) getContext(c *gc.C) *deployer.ContainerContext {
+	config := agentConfig(names.NewMachineTag("99"), s.dataDir, s.logDir)
+	return deployer.NewContainerContextWithClient(config, fakeAPICalls{}, func() (deployer.LXDClient, error) {
+		return s.client, nil
+	})
+}
+
+func (s *ContainerContextSuite) TestDeployRecall(c *gc.C) {
+	ctx := s.getContext(c)
+	units, err := ctx.DeployedUnits()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(units, gc.HasLen, 0)
+
+	err = ctx.DeployUnit("foo/123", "some-password", deployer.ResourceLimits{})
+	c.Assert(err, jc.ErrorIsNil)
+	units, err = ctx.DeployedUnits()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(units, jc.DeepEquals, []string{"foo/123"})
+
+	c.Assert(s.client.specs, gc.HasLen, 1)
+	spec := s.client.specs[0]
+	c.Assert(spec.Name, gc.Equals, "jujud-unit-foo-123")
+	c.Assert(spec.Image, gc.Equals, "ubuntu-image")
+	c.Assert(spec.Metadata["user.juju-unit"], gc.Equals, "foo/123")
+	userData := spec.Metadata[lxdclient.UserdataKey]
+	c.Assert(strings.HasPrefix(userData, "#cloud-config"), jc.IsTrue)
+	c.Assert(userData, jc.Contains, "jujud-unit-foo-123.service")
+
+	tag := names.NewUnitTag("foo/123")
+	c.Assert(spec.Devices, jc.DeepEquals, lxdclient.Devices{
+		"juju-tools": {
+			"type":     "disk",
+			"source":   tools.ToolsDir(s.dataDir, tag.String()),
+			"path":     tools.ToolsDir(s.dataDir, tag.String()),
+			"readonly": "true",
+		},
+		"juju-agent": {
+			"type":   "disk",
+			"source": agent.Dir(s.dataDir, tag),
+			"path":   agent.Dir(s.dataDir, tag),
+		},
+		"juju-logs": {
+			"type":   "disk",
+			"source": s.logDir,
+			"path":   s.logDir,
+		},
+	})
+	conf, err := agent.ReadConfig(agent.ConfigPath(s.dataDir, tag))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(conf.Tag(), gc.Equals, tag)
+
+	err = ctx.RecallUnit("foo/123")
+	c.Assert(err, jc.ErrorIsNil)
+	units, err = ctx.DeployedUnits()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(units, gc.HasLen, 0)
+	s.client.CheckCall(c, 6, "RemoveInstances", "jujud-unit-", []string{"jujud-unit-foo-123"})
+	s.checkUnitRemoved(c, "foo/123")
+}
+
+func (s *ContainerContextSuite) TestDeployTwice(c *gc.C) {
+	ctx := s.getContext(c)
+	err := ctx.DeployUnit("foo/123", "some-password", deployer.ResourceLimits{})
+	c.Assert(err, jc.ErrorIsNil)
+	err = ctx.DeployUnit("foo/123", "some-password", deployer.ResourceLimits{})
+	c.Assert(err, gc.ErrorMatches, `unit "foo/123" is already deployed`)
+}
+
+func (s *ContainerContextSuite) TestDeployFailureRemovesAgentFiles(c *gc.C) {
+	s.client.SetErrors(nil, nil, errors.New("boom"))
+	ctx := s.getContext(c)
+	err := ctx.DeployUnit("foo/123", "some-password", deployer.ResourceLimits{})
+	c.Assert(err, gc.ErrorMatches, `starting container for unit "foo/123": boom`)
+	s.checkUnitRemoved(c, "foo/123")
+}
+
+func (s *ContainerContextSuite) TestUpdateUnitNotSupported(c *gc.C) {
+	ctx := s.getContext(c)
+	err := ctx.UpdateUnit("foo/123", deployer.ResourceLimits{})
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
+
+func (s *ContainerContextSuite) TestRecallUnknownUnit(c *gc.C) {
+	ctx := s.getContext(c)
+	err := ctx.RecallUnit("foo/123")
+	c.Assert(err, gc.ErrorMatches, `unit "foo/123" is not deployed`)
+}
+
+type fakeAPICalls struct{}
+
+func (fakeAPICalls) ConnectionInfo() (params.DeployerConnectionValues, error) {
+	return params.DeployerConnectionValues{
+		APIAddresses: []string{"a1:123", "a2:123"},
+	}, nil
+}
+
+type fakeLXDClient struct {
+	testing.Stub
+	specs []lxdclient.InstanceSpec
+}
+
+func (f *fakeLXDClient) EnsureImageExists(series, arch string, sources []lxdclient.Remote, copyProgressHandler func(string)) (string, error) {
+	f.MethodCall(f, "EnsureImageExists", series, arch)
+	return "ubuntu-image", f.NextErr()
+}
+
+func (f *fakeLXDClient) AddInstance(spec lxdclient.InstanceSpec) (*lxdclient.Instance, error) {
+	f.MethodCall(f, "AddInstance", spec)
+	if err := f.NextErr(); err != nil {
+		return nil, err
+	}
+	f.specs = append(f.specs, spec)
+	return lxdclient.NewInstance(spec.Summary(""), &spec), nil
+}
+
+func (f *fakeLXDClient) Instances(prefix string, statuses ...string) ([]lxdclient.Instance, error) {
+	f.MethodCall(f, "Instances", prefix)
+	if err := f.NextErr(); err != nil {
+		return nil, err
+	}
+	var instances []lxdclient.Instance
+	for _, spec := range f.specs {
+		if strings.HasPrefix(spec.Name, prefix) {
+			instances = append(instances, *lxdclient.NewInstance(spec.Summary(""), &spec))
+		}
+	}
+	return instances, nil
+}
+
+func (f *fakeLXDClient) RemoveInstances(prefix string, names ...string) error {
+	f.MethodCall(f, "RemoveInstances", prefix, names)
+	if err := f.NextErr(); err != nil {
+		return err
+	}
+	var remaining []lxdclient.InstanceSpec
+	for _, spec := range f.specs {
+		removed := false
+		for _, name := range names {
+			removed = removed || spec.Name == name
+		}
+		if !removed {
+			remaining = append(remaining, spec)
+		}
+	}
+	f.specs = remaining
+	return nil
+}
@@ -5,6 +5,8 @@ package deployer
 
 import (
 	"fmt"
+	"sync"
+	"time"
 
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
@@ -16,8 +18,11 @@ import (
 	"github.com/juju/juju/agent"
 	apideployer "github.com/juju/juju/api/deployer"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/status"
 	"github.com/juju/juju/watcher"
+	"github.com/juju/juju/worker/catacomb"
 )
 
 var logger = loggo.GetLogger("juju.worker.deployer")
@@ -26,51 +31,328 @@ var logger = loggo.GetLogger("juju.worker.deployer")
 // to changes in a set of state units; and for the final removal of its agents'
 // units from state when they are no longer needed.
 type Deployer struct {
-	st       *apideployer.State
-	ctx      Context
-	deployed set.Strings
+	catacomb   catacomb.Catacomb
+	st         *apideployer.State
+	loggingAPI LoggingConfigAPI
+	modelAPI   ModelConfigAPI
+	ctx        Context
+	metrics    *Metrics
+	deployed   set.Strings
+
+	// loggingConfig is the logging config of the deployed
+	// unit agents.
+	loggingConfig string
+
+	// maxArchiveAge is how long the archived agent files of
+	// recalled units are kept.
+	maxArchiveAge time.Duration
+
+	// unitAgentUsers is whether unit agents are deployed to
+	// run as their own system users.
+	unitAgentUsers bool
+
+	// Changes to units are handled concurrently, but each unit is
+	// handled by at most one goroutine at a time. The fields below
+	// are only used by the loop goroutine.
+
+	// busy holds the units being handled.
+	busy set.Strings
+
+	// dirty holds the busy units which have changed again since
+	// they started being handled.
+	dirty set.Strings
+
+	// queued holds, in order, the units waiting to be handled.
+	queued []string
+
+	// outdated holds the units whose agents' services must be
+	// re-rendered when they are next handled.
+	outdated set.Strings
+
+	// unchecked holds the units whose agents must be health-checked
+	// when they are next handled.
+	unchecked set.Strings
+
+	// results receives the outcomes of handling units.
+	results chan unitResult
+
+	// wg tracks the goroutines handling units.
+	wg sync.WaitGroup
+}
+
+// maxConcurrentChanges is the maximum number of units whose changes
+// are handled at once.
+const maxConcurrentChanges = 4
+
+// unitResult holds the outcome of handling a change to a unit.
+type unitResult struct {
+	unitName string
+	deployed bool
+	err      error
+}
+
+// LoggingConfigAPI provides the logging config of the machine agent
+// running the deployer, which is passed on to the unit agents.
+type LoggingConfigAPI interface {
+	LoggingConfig(agentTag names.Tag) (string, error)
+	WatchLoggingConfig(agentTag names.Tag) (watcher.NotifyWatcher, error)
+}
+
+// ModelConfigAPI provides the config of the model, which determines
+// whether the agent files of recalled units are archived.
+type ModelConfigAPI interface {
+	ModelConfig() (*config.Config, error)
+	WatchForModelConfigChanges() (watcher.NotifyWatcher, error)
 }
 
 // Context abstracts away the differences between different unit deployment
 // strategies; where a Deployer is responsible for what to deploy, a Context
-// is responsible for how to deploy.
+// is responsible for how to deploy. Changes to different units are made
+// concurrently, so implementations must be safe for concurrent use. A
+// Context which is also a worker.Worker is stopped with the Deployer.
 type Context interface {
 	// DeployUnit causes the agent for the specified unit to be started and run
-	// continuously until further notice without further intervention. It will
-	// return an error if the agent is already deployed.
-	DeployUnit(unitName, initialPassword string) error
+	// continuously until further notice without further intervention, within
+	// the specified resource limits. It will return an error if the agent is
+	// already deployed.
+	DeployUnit(unitName, initialPassword string, limits ResourceLimits) error
+
+	// UpdateUnit re-renders the service running the agent for the specified
+	// deployed unit with the specified resource limits and the current
+	// logging config, restarting the agent.
+	UpdateUnit(unitName string, limits ResourceLimits) error
 
 	// RecallUnit causes the agent for the specified unit to be stopped, and
-	// the agent's data to be destroyed. It will return an error if the agent
-	// was not deployed by the manager.
+	// the agent's data to be destroyed, after archiving it if archiving is
+	// enabled. It will return an error if the agent was not deployed by the
+	// manager.
 	RecallUnit(unitName string) error
 
 	// DeployedUnits returns the names of all units deployed by the manager.
 	DeployedUnits() ([]string, error)
 
+	// CheckUnit returns an error if the agent for the specified deployed
+	// unit is not responding. It returns a NotSupported error if agents
+	// can't be checked.
+	CheckUnit(unitName string) error
+
+	// LoggingConfig returns the logging config with which unit agents
+	// are started.
+	LoggingConfig() string
+
+	// SetLoggingConfig sets the logging config with which unit agents
+	// are started.
+	SetLoggingConfig(loggingConfig string)
+
+	// SetUnitAgentUsers sets whether the agents of units deployed in
+	// future are run as their own system users, isolating them from
+	// the other units on the machine.
+	SetUnitAgentUsers(enabled bool)
+
+	// SetMaxArchiveAge sets how long the archived agent files of recalled
+	// units are kept, pruning archives which are older. The agent files
+	// are not archived if it is zero.
+	SetMaxArchiveAge(maxAge time.Duration)
+
 	// AgentConfig returns the agent config for the machine agent that is
 	// running the deployer.
 	AgentConfig() agent.Config
 }
 
 // NewDeployer returns a Worker that deploys and recalls unit agents
-// via ctx, taking a machine id to operate on.
-func NewDeployer(st *apideployer.State, ctx Context) (worker.Worker, error) {
+// via ctx, taking a machine id to operate on. The unit agents are
+// re-rendered whenever the machine agent's logging config, obtained
+// from loggingAPI, changes. The agent files of recalled units are
+// archived according to the model config obtained from modelAPI.
+// Deployed unit agents are health-checked periodically, and those
+// not responding are restarted. The operations on unit agents are
+// recorded in metrics.
+func NewDeployer(
+	st *apideployer.State,
+	loggingAPI LoggingConfigAPI,
+	modelAPI ModelConfigAPI,
+	ctx Context,
+	metrics *Metrics,
+) (worker.Worker, error) {
 	d := &Deployer{
-		st:       st,
-		ctx:      ctx,
-		deployed: make(set.Strings),
+		st:            st,
+		loggingAPI:    loggingAPI,
+		modelAPI:      modelAPI,
+		ctx:           ctx,
+		metrics:       metrics,
+		deployed:      make(set.Strings),
+		loggingConfig: ctx.LoggingConfig(),
+		busy:          make(set.Strings),
+		dirty:         make(set.Strings),
+		outdated:      make(set.Strings),
+		unchecked:     make(set.Strings),
+		results:       make(chan unitResult),
 	}
-	w, err := watcher.NewStringsWorker(watcher.StringsConfig{
-		Handler: d,
-	})
-	if err != nil {
+	if err := catacomb.Invoke(catacomb.Plan{
+		Site: &d.catacomb,
+		Work: d.loop,
+	}); err != nil {
 		return nil, errors.Trace(err)
 	}
-	return w, nil
+	return d, nil
+}
+
+// Kill is part of the worker.Worker interface.
+func (d *Deployer) Kill() {
+	d.catacomb.Kill(nil)
+}
+
+// Wait is part of the worker.Worker interface.
+func (d *Deployer) Wait() error {
+	return d.catacomb.Wait()
+}
+
+func (d *Deployer) loop() error {
+	// Units still being handled when the loop exits are left to
+	// finish, but their results are discarded.
+	stop := make(chan struct{})
+	defer d.wg.Wait()
+	defer close(stop)
+
+	if w, ok := d.ctx.(worker.Worker); ok {
+		if err := d.catacomb.Add(w); err != nil {
+			return errors.Trace(err)
+		}
+	}
+
+	unitsWatcher, err := d.setUp()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err := d.catacomb.Add(unitsWatcher); err != nil {
+		return errors.Trace(err)
+	}
+
+	// A logging override in the machine agent's config applies
+	// to its unit agents too, so there's nothing to watch.
+	agentConfig := d.ctx.AgentConfig()
+	var loggingChanges watcher.NotifyChannel
+	if agentConfig.Value(agent.LoggingOverride) == "" {
+		loggingWatcher, err := d.loggingAPI.WatchLoggingConfig(agentConfig.Tag())
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if err := d.catacomb.Add(loggingWatcher); err != nil {
+			return errors.Trace(err)
+		}
+		loggingChanges = loggingWatcher.Changes()
+	}
+
+	modelWatcher, err := d.modelAPI.WatchForModelConfigChanges()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err := d.catacomb.Add(modelWatcher); err != nil {
+		return errors.Trace(err)
+	}
+
+	healthChecks := time.After(healthCheckInterval)
+	for {
+		d.startQueued(stop)
+		select {
+		case <-d.catacomb.Dying():
+			return d.catacomb.ErrDying()
+		case <-healthChecks:
+			d.checkDeployed()
+			healthChecks = time.After(healthCheckInterval)
+		case unitNames, ok := <-unitsWatcher.Changes():
+			if !ok {
+				return errors.New("units watcher closed")
+			}
+			for _, unitName := range unitNames {
+				d.enqueue(unitName)
+			}
+		case result := <-d.results:
+			if err := d.finished(result); err != nil {
+				return err
+			}
+		case _, ok := <-loggingChanges:
+			if !ok {
+				return errors.New("logging config watcher closed")
+			}
+			loggingConfig, err := d.loggingAPI.LoggingConfig(agentConfig.Tag())
+			if err != nil {
+				return errors.Trace(err)
+			}
+			d.setLoggingConfig(loggingConfig)
+		case _, ok := <-modelWatcher.Changes():
+			if !ok {
+				return errors.New("model config watcher closed")
+			}
+			modelConfig, err := d.modelAPI.ModelConfig()
+			if err != nil {
+				return errors.Trace(err)
+			}
+			d.setModelConfig(modelConfig)
+		}
+	}
+}
+
+// enqueue arranges for the change to the named unit to be handled,
+// after any change already being handled for it.
+func (d *Deployer) enqueue(unitName string) {
+	if d.busy.Contains(unitName) {
+		d.dirty.Add(unitName)
+		return
+	}
+	for _, queued := range d.queued {
+		if queued == unitName {
+			return
+		}
+	}
+	d.queued = append(d.queued, unitName)
+}
+
+// startQueued starts handling queued units, while fewer than
+// maxConcurrentChanges are being handled.
+func (d *Deployer) startQueued(stop <-chan struct{}) {
+	for len(d.queued) > 0 && len(d.busy) < maxConcurrentChanges {
+		unitName := d.queued[0]
+		d.queued = d.queued[1:]
+		deployed := d.deployed.Contains(unitName)
+		outdated := d.outdated.Contains(unitName)
+		d.outdated.Remove(unitName)
+		unchecked := d.unchecked.Contains(unitName)
+		d.unchecked.Remove(unitName)
+		d.busy.Add(unitName)
+		d.wg.Add(1)
+		go func() {
+			defer d.wg.Done()
+			deployed, err := d.changed(unitName, deployed, outdated, unchecked)
+			select {
+			case d.results <- unitResult{unitName, deployed, err}:
+			case <-stop:
+			}
+		}()
+	}
+}
+
+// finished records the result of handling a unit, and requeues
+// the unit if it changed again in the meantime.
+func (d *Deployer) finished(result unitResult) error {
+	d.busy.Remove(result.unitName)
+	if result.err != nil {
+		return result.err
+	}
+	if result.deployed {
+		d.deployed.Add(result.unitName)
+	} else {
+		d.deployed.Remove(result.unitName)
+	}
+	d.metrics.setDeployed(len(d.deployed))
+	if d.dirty.Contains(result.unitName) {
+		d.dirty.Remove(result.unitName)
+		d.enqueue(result.unitName)
+	}
+	return nil
 }
 
-func (d *Deployer) SetUp() (watcher.StringsWatcher, error) {
+func (d *Deployer) setUp() (watcher.StringsWatcher, error) {
 	tag := d.ctx.AgentConfig().Tag()
 	machineTag, ok := tag.(names.MachineTag)
 	if !ok {
@@ -91,25 +373,57 @@ func (d *Deployer) SetUp() (watcher.StringsWatcher, error) {
 	}
 	for _, unitName := range deployed {
 		d.deployed.Add(unitName)
-		if err := d.changed(unitName); err != nil {
-			return nil, err
-		}
+		d.enqueue(unitName)
 	}
+	d.metrics.setDeployed(len(d.deployed))
 	return machineUnitsWatcher, nil
 }
 
-func (d *Deployer) Handle(_ <-chan struct{}, unitNames []string) error {
-	for _, unitName := range unitNames {
-		if err := d.changed(unitName); err != nil {
-			return err
-		}
+// setLoggingConfig updates the logging config of the unit agents,
+// arranging for the services of those already deployed, or being
+// deployed, to be re-rendered if it has changed.
+func (d *Deployer) setLoggingConfig(loggingConfig string) {
+	if loggingConfig == d.loggingConfig {
+		return
+	}
+	logger.Infof("updating unit agent logging config to %q", loggingConfig)
+	d.ctx.SetLoggingConfig(loggingConfig)
+	d.loggingConfig = loggingConfig
+	for _, unitName := range d.deployed.Union(d.busy).SortedValues() {
+		d.outdated.Add(unitName)
+		d.enqueue(unitName)
+	}
+}
+
+// checkDeployed arranges for the agents of the deployed units which
+// are not being handled to be health-checked.
+func (d *Deployer) checkDeployed() {
+	for _, unitName := range d.deployed.Difference(d.busy).SortedValues() {
+		d.unchecked.Add(unitName)
+		d.enqueue(unitName)
+	}
+}
+
+// setModelConfig updates how unit agents are deployed and
+// recalled according to the model config.
+func (d *Deployer) setModelConfig(modelConfig *config.Config) {
+	if maxAge := modelConfig.MaxUnitArchiveAge(); maxAge != d.maxArchiveAge {
+		logger.Infof("archiving agent files of recalled units for %v", maxAge)
+		d.ctx.SetMaxArchiveAge(maxAge)
+		d.maxArchiveAge = maxAge
+	}
+	if enabled := modelConfig.UnitAgentUsers(); enabled != d.unitAgentUsers {
+		logger.Infof("running unit agents as their own users: %v", enabled)
+		d.ctx.SetUnitAgentUsers(enabled)
+		d.unitAgentUsers = enabled
 	}
-	return nil
 }
 
 // changed ensures that the named unit is deployed, recalled, or removed, as
-// indicated by its state.
-func (d *Deployer) changed(unitName string) error {
+// indicated by its state, and reports whether it is deployed afterwards.
+// The service running the agent of a deployed unit is re-rendered if it is
+// outdated, and otherwise the agent is health-checked if it is unchecked.
+func (d *Deployer) changed(unitName string, deployed, outdated, unchecked bool) (bool, error) {
 	unitTag := names.NewUnitTag(unitName)
 	// Determine unit life state, and whether we're responsible for it.
 	logger.Infof("checking unit %q", unitName)
@@ -118,44 +432,53 @@ func (d *Deployer) changed(unitName string) error {
 	if params.IsCodeNotFoundOrCodeUnauthorized(err) {
 		life = params.Dead
 	} else if err != nil {
-		return err
+		return deployed, err
 	} else {
 		life = unit.Life()
 	}
 	// Deployed units must be removed if they're Dead, or if the deployer
 	// is no longer responsible for them.
-	if d.deployed.Contains(unitName) {
-		if life == params.Dead {
-			if err := d.recall(unitName); err != nil {
-				return err
+	if deployed {
+		if life != params.Dead {
+			if outdated {
+				return true, d.update(unit)
 			}
+			if unchecked {
+				return true, d.check(unit)
+			}
+			return true, nil
+		}
+		if err := d.recall(unitName); err != nil {
+			return true, err
 		}
 	}
 	// The only units that should be deployed are those that (1) we are responsible
 	// for and (2) are Alive -- if we're responsible for a Dying unit that is not
 	// yet deployed, we should remove it immediately rather than undergo the hassle
 	// of deploying a unit agent purely so it can set itself to Dead.
-	if !d.deployed.Contains(unitName) {
-		if life == params.Alive {
-			return d.deploy(unit)
-		} else if unit != nil {
-			return d.remove(unit)
+	if life == params.Alive {
+		if err := d.deploy(unit); err != nil {
+			return false, err
 		}
+		return true, nil
+	} else if unit != nil {
+		return false, d.remove(unit)
 	}
-	return nil
+	return false, nil
 }
 
-// deploy will deploy the supplied unit with the deployer's manager. It will
-// panic if it observes inconsistent internal state.
-func (d *Deployer) deploy(unit *apideployer.Unit) error {
+// deploy will deploy the supplied unit with the deployer's manager.
+func (d *Deployer) deploy(unit *apideployer.Unit) (err error) {
+	defer d.metrics.observe(operationDeploy, time.Now(), &err)
 	unitName := unit.Name()
-	if d.deployed.Contains(unit.Name()) {
-		panic("must not re-deploy a deployed unit")
-	}
 	if err := unit.SetStatus(status.Waiting, status.MessageInstallingAgent, nil); err != nil {
 		return errors.Trace(err)
 	}
 	logger.Infof("deploying unit %q", unitName)
+	limits, err := unitLimits(unit)
+	if err != nil {
+		return errors.Trace(err)
+	}
 	initialPassword, err := utils.RandomPassword()
 	if err != nil {
 		return err
@@ -163,41 +486,91 @@ func (d *Deployer) deploy(unit *apideployer.Unit) error {
 	if err := unit.SetPassword(initialPassword); err != nil {
 		return fmt.Errorf("cannot set password for unit %q: %v", unitName, err)
 	}
-	if err := d.ctx.DeployUnit(unitName, initialPassword); err != nil {
-		return err
+	return d.ctx.DeployUnit(unitName, initialPassword, limits)
+}
+
+// update re-renders the service running the agent of the supplied
+// deployed unit.
+func (d *Deployer) update(unit *apideployer.Unit) (err error) {
+	defer d.metrics.observe(operationUpdate, time.Now(), &err)
+	unitName := unit.Name()
+	logger.Infof("updating unit %q", unitName)
+	limits, err := unitLimits(unit)
+	if err != nil {
+		return errors.Trace(err)
 	}
-	d.deployed.Add(unitName)
-	return nil
+	err = d.ctx.UpdateUnit(unitName, limits)
+	if errors.IsNotSupported(err) {
+		logger.Warningf("cannot update unit %q: %v", unitName, err)
+		return nil
+	}
+	return errors.Annotatef(err, "updating unit %q", unitName)
 }
 
-// recall will recall the named unit with the deployer's manager. It will
-// panic if it observes inconsistent internal state.
-func (d *Deployer) recall(unitName string) error {
-	if !d.deployed.Contains(unitName) {
-		panic("must not recall a unit that is not deployed")
+// check health-checks the agent of the supplied deployed unit, and
+// restarts it if it fails healthCheckAttempts checks in a row. The
+// lost agent is reported to the controller by setting its status to
+// failed; the agent sets its own status again once it is running.
+func (d *Deployer) check(unit *apideployer.Unit) error {
+	unitName := unit.Name()
+	var err error
+	for attempt := 0; attempt < healthCheckAttempts; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-d.catacomb.Dying():
+				return nil
+			case <-time.After(healthCheckRetryDelay):
+			}
+		}
+		err = d.ctx.CheckUnit(unitName)
+		if err == nil || errors.IsNotSupported(err) {
+			return nil
+		}
+		logger.Debugf("agent of unit %q failed health check: %v", unitName, err)
 	}
-	logger.Infof("recalling unit %q", unitName)
-	if err := d.ctx.RecallUnit(unitName); err != nil {
-		return err
+	logger.Warningf("agent of unit %q is lost, restarting it: %v", unitName, err)
+	message := fmt.Sprintf("agent not responding, restarting: %v", err)
+	if err := unit.SetAgentStatus(status.Failed, message, nil); errors.IsNotImplemented(err) {
+		logger.Debugf("cannot report lost agent of unit %q: %v", unitName, err)
+	} else if err != nil {
+		return errors.Annotatef(err, "reporting lost agent of unit %q", unitName)
 	}
-	d.deployed.Remove(unitName)
+	if err := d.update(unit); err != nil {
+		return errors.Trace(err)
+	}
+	logger.Warningf("restarted agent of unit %q", unitName)
 	return nil
 }
 
+// recall will recall the named unit with the deployer's manager.
+func (d *Deployer) recall(unitName string) (err error) {
+	defer d.metrics.observe(operationRecall, time.Now(), &err)
+	logger.Infof("recalling unit %q", unitName)
+	return d.ctx.RecallUnit(unitName)
+}
+
 // remove will remove the supplied unit from state. It will panic if it
 // observes inconsistent internal state.
-func (d *Deployer) remove(unit *apideployer.Unit) error {
+func (d *Deployer) remove(unit *apideployer.Unit) (err error) {
+	defer d.metrics.observe(operationRemove, time.Now(), &err)
 	unitName := unit.Name()
-	if d.deployed.Contains(unitName) {
-		panic("must not remove a deployed unit")
-	} else if unit.Life() == params.Alive {
+	if unit.Life() == params.Alive {
 		panic("must not remove an Alive unit")
 	}
 	logger.Infof("removing unit %q", unitName)
 	return unit.Remove()
 }
 
-func (d *Deployer) TearDown() error {
-	// Nothing to do here.
-	return nil
+// unitLimits returns the resource limits of the agent of the
+// specified unit.
+func unitLimits(unit *apideployer.Unit) (ResourceLimits, error) {
+	cons, err := unit.Constraints()
+	if errors.IsNotImplemented(err) {
+		// The controller can't tell us the unit's constraints,
+		// so its agent gets the default limits.
+		cons = constraints.Value{}
+	} else if err != nil {
+		return ResourceLimits{}, errors.Annotatef(err, "cannot get constraints for unit %q", unit.Name())
+	}
+	return NewResourceLimits(cons), nil
 }
@@ -4,17 +4,23 @@
 package deployer_test
 
 import (
+	"io/ioutil"
 	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
 	gc "gopkg.in/check.v1"
 	worker "gopkg.in/juju/worker.v1"
 
 	"github.com/juju/juju/api"
+	apiagent "github.com/juju/juju/api/agent"
 	apideployer "github.com/juju/juju/api/deployer"
+	apilogger "github.com/juju/juju/api/logger"
 	jujutesting "github.com/juju/juju/juju/testing"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/status"
@@ -50,11 +56,17 @@ func (s *deployerSuite) TearDownTest(c *gc.C) {
 func (s *deployerSuite) makeDeployerAndContext(c *gc.C) (worker.Worker, deployer.Context) {
 	// Create a deployer acting on behalf of the machine.
 	ctx := s.getContextForMachine(c, s.machine.Tag())
-	deployer, err := deployer.NewDeployer(s.deployerState, ctx)
+	deployer, err := deployer.NewDeployer(s.deployerState, apilogger.NewState(s.stateAPI), s.agentAPI(c), ctx, deployer.NewMetrics())
 	c.Assert(err, jc.ErrorIsNil)
 	return deployer, ctx
 }
 
+func (s *deployerSuite) agentAPI(c *gc.C) *apiagent.State {
+	agentAPI, err := apiagent.NewState(s.stateAPI)
+	c.Assert(err, jc.ErrorIsNil)
+	return agentAPI
+}
+
 func (s *deployerSuite) TestDeployRecallRemovePrincipals(c *gc.C) {
 	// Create a machine, and a couple of units.
 	app := s.AddTestingApplication(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
@@ -241,6 +253,223 @@ func (s *deployerSuite) TestNonAliveSubordinates(c *gc.C) {
 	s.waitFor(c, isRemoved(s.State, sub1.Name()))
 }
 
+func (s *deployerSuite) TestLoggingConfigChangeUpdatesUnits(c *gc.C) {
+	app := s.AddTestingApplication(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	u0, err := app.AddUnit(state.AddUnitParams{})
+	c.Assert(err, jc.ErrorIsNil)
+	err = u0.AssignToMachine(s.machine)
+	c.Assert(err, jc.ErrorIsNil)
+
+	dep, ctx := s.makeDeployerAndContext(c)
+	defer stop(c, dep)
+	s.waitFor(c, isDeployed(ctx, u0.Name()))
+
+	err = s.IAASModel.UpdateModelConfig(map[string]interface{}{
+		"logging-config": "<root>=ERROR",
+	}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	s.waitFor(c, func(c *gc.C) bool {
+		conf := s.installedConf(c, "jujud-unit-wordpress-0")
+		return strings.HasSuffix(conf.ExecStart, " --logging-config "+quote+"<root>=ERROR"+quote)
+	})
+	c.Assert(ctx.LoggingConfig(), gc.Equals, "<root>=ERROR")
+	s.waitFor(c, isDeployed(ctx, u0.Name()))
+}
+
+func (s *deployerSuite) TestRecallArchivesAgentFiles(c *gc.C) {
+	err := s.IAASModel.UpdateModelConfig(map[string]interface{}{
+		"max-unit-archive-age": "1h",
+	}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	app := s.AddTestingApplication(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	u0, err := app.AddUnit(state.AddUnitParams{})
+	c.Assert(err, jc.ErrorIsNil)
+	err = u0.AssignToMachine(s.machine)
+	c.Assert(err, jc.ErrorIsNil)
+
+	dep, ctx := s.makeDeployerAndContext(c)
+	defer stop(c, dep)
+	s.waitFor(c, isDeployed(ctx, u0.Name()))
+
+	err = u0.EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+	s.waitFor(c, isRemoved(s.State, u0.Name()))
+	s.waitFor(c, isDeployed(ctx))
+
+	infos, err := ioutil.ReadDir(deployer.UnitArchiveDir(s.dataDir))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(infos, gc.HasLen, 1)
+	c.Assert(infos[0].Name(), gc.Matches, `unit-wordpress-0-.*\.tar\.gz`)
+}
+
+func (s *deployerSuite) TestDeployConcurrently(c *gc.C) {
+	app := s.AddTestingApplication(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	var unitNames []string
+	for i := 0; i < deployer.MaxConcurrentChanges+2; i++ {
+		u, err := app.AddUnit(state.AddUnitParams{})
+		c.Assert(err, jc.ErrorIsNil)
+		err = u.AssignToMachine(s.machine)
+		c.Assert(err, jc.ErrorIsNil)
+		unitNames = append(unitNames, u.Name())
+	}
+
+	ctx := &blockingContext{
+		Context: s.getContextForMachine(c, s.machine.Tag()),
+		release: make(chan struct{}),
+	}
+	dep, err := deployer.NewDeployer(s.deployerState, apilogger.NewState(s.stateAPI), s.agentAPI(c), ctx, deployer.NewMetrics())
+	c.Assert(err, jc.ErrorIsNil)
+	defer stop(c, dep)
+
+	// As many units as allowed are deployed at once,
+	// and the rest once those are done.
+	s.waitFor(c, func(c *gc.C) bool {
+		return ctx.maxInFlight() == deployer.MaxConcurrentChanges
+	})
+	close(ctx.release)
+	s.waitFor(c, isDeployed(ctx, unitNames...))
+	c.Assert(ctx.maxInFlight(), gc.Equals, deployer.MaxConcurrentChanges)
+}
+
+func (s *deployerSuite) TestMetrics(c *gc.C) {
+	app := s.AddTestingApplication(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	u0, err := app.AddUnit(state.AddUnitParams{})
+	c.Assert(err, jc.ErrorIsNil)
+	err = u0.AssignToMachine(s.machine)
+	c.Assert(err, jc.ErrorIsNil)
+
+	registry := prometheus.NewPedanticRegistry()
+	metrics := deployer.NewMetrics()
+	registry.MustRegister(metrics)
+	ctx := s.getContextForMachine(c, s.machine.Tag())
+	dep, err := deployer.NewDeployer(s.deployerState, apilogger.NewState(s.stateAPI), s.agentAPI(c), ctx, metrics)
+	c.Assert(err, jc.ErrorIsNil)
+	defer stop(c, dep)
+	s.waitFor(c, isDeployed(ctx, u0.Name()))
+
+	// The gauge is set once the deployer has recorded the result.
+	s.waitFor(c, func(c *gc.C) bool {
+		deployed := gatherMetrics(c, registry)["juju_deployer_deployed_units"]
+		return len(deployed) == 1 && deployed[0].GetGauge().GetValue() == 1
+	})
+	operations := gatherMetrics(c, registry)["juju_deployer_operations_total"]
+	c.Assert(operations, gc.HasLen, 1)
+	c.Assert(operations[0].GetLabel()[0].GetValue(), gc.Equals, "deploy")
+	c.Assert(operations[0].GetCounter().GetValue(), gc.Equals, float64(1))
+	c.Assert(gatherMetrics(c, registry)["juju_deployer_operation_failures_total"], gc.HasLen, 0)
+}
+
+// gatherMetrics returns the metrics gathered from the registry,
+// keyed on the names of their families.
+func gatherMetrics(c *gc.C, registry *prometheus.Registry) map[string][]*dto.Metric {
+	families, err := registry.Gather()
+	c.Assert(err, jc.ErrorIsNil)
+	metrics := make(map[string][]*dto.Metric)
+	for _, family := range families {
+		metrics[family.GetName()] = family.GetMetric()
+	}
+	return metrics
+}
+
+func (s *deployerSuite) TestLostAgentRestarted(c *gc.C) {
+	s.PatchValue(deployer.HealthCheckInterval, coretesting.ShortWait)
+	s.PatchValue(deployer.HealthCheckRetryDelay, coretesting.ShortWait)
+	app := s.AddTestingApplication(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	u0, err := app.AddUnit(state.AddUnitParams{})
+	c.Assert(err, jc.ErrorIsNil)
+	err = u0.AssignToMachine(s.machine)
+	c.Assert(err, jc.ErrorIsNil)
+
+	ctx := &unresponsiveContext{
+		Context: s.getContextForMachine(c, s.machine.Tag()),
+	}
+	dep, err := deployer.NewDeployer(s.deployerState, apilogger.NewState(s.stateAPI), s.agentAPI(c), ctx, deployer.NewMetrics())
+	c.Assert(err, jc.ErrorIsNil)
+	defer stop(c, dep)
+
+	// The agent is only restarted once it has failed every check.
+	s.waitFor(c, func(c *gc.C) bool {
+		return ctx.updateCount() > 0
+	})
+	c.Assert(ctx.checkCount(), jc.GreaterThan, deployer.HealthCheckAttempts-1)
+	s.waitFor(c, isDeployed(ctx, u0.Name()))
+
+	// The lost agent is reported to the controller.
+	s.waitFor(c, agentStatus(u0, status.StatusInfo{
+		Status:  status.Failed,
+		Message: "agent not responding, restarting: not responding",
+	}))
+}
+
+// unresponsiveContext is a Context whose unit agents always fail
+// their health checks, recording the checks and updates made.
+type unresponsiveContext struct {
+	deployer.Context
+
+	mu      sync.Mutex
+	checks  int
+	updates int
+}
+
+func (ctx *unresponsiveContext) CheckUnit(unitName string) error {
+	ctx.mu.Lock()
+	defer ctx.mu.Unlock()
+	ctx.checks++
+	return errors.New("not responding")
+}
+
+func (ctx *unresponsiveContext) UpdateUnit(unitName string, limits deployer.ResourceLimits) error {
+	ctx.mu.Lock()
+	ctx.updates++
+	ctx.mu.Unlock()
+	return ctx.Context.UpdateUnit(unitName, limits)
+}
+
+func (ctx *unresponsiveContext) checkCount() int {
+	ctx.mu.Lock()
+	defer ctx.mu.Unlock()
+	return ctx.checks
+}
+
+func (ctx *unresponsiveContext) updateCount() int {
+	ctx.mu.Lock()
+	defer ctx.mu.Unlock()
+	return ctx.updates
+}
+
+// blockingContext is a Context whose DeployUnit blocks until
+// released, recording the most deployments made at once.
+type blockingContext struct {
+	deployer.Context
+	release chan struct{}
+
+	mu       sync.Mutex
+	inFlight int
+	max      int
+}
+
+func (ctx *blockingContext) DeployUnit(unitName, initialPassword string, limits deployer.ResourceLimits) error {
+	ctx.mu.Lock()
+	ctx.inFlight++
+	if ctx.inFlight > ctx.max {
+		ctx.max = ctx.inFlight
+	}
+	ctx.mu.Unlock()
+	defer func() {
+		ctx.mu.Lock()
+		ctx.inFlight--
+		ctx.mu.Unlock()
+	}()
+	<-ctx.release
+	return ctx.Context.DeployUnit(unitName, initialPassword, limits)
+}
+
+func (ctx *blockingContext) maxInFlight() int {
+	ctx.mu.Lock()
+	defer ctx.mu.Unlock()
+	return ctx.max
+}
+
 func (s *deployerSuite) waitFor(c *gc.C, t func(c *gc.C) bool) {
 	s.BackingState.StartSync()
 	if t(c) {
@@ -288,6 +517,14 @@ func unitStatus(u *state.Unit, statusInfo status.StatusInfo) func(*gc.C) bool {
 	}
 }
 
+func agentStatus(u *state.Unit, statusInfo status.StatusInfo) func(*gc.C) bool {
+	return func(c *gc.C) bool {
+		sInfo, err := u.AgentStatus()
+		c.Assert(err, jc.ErrorIsNil)
+		return sInfo.Status == statusInfo.Status && sInfo.Message == statusInfo.Message
+	}
+}
+
 func stop(c *gc.C, w worker.Worker) {
 	c.Assert(worker.Stop(w), gc.IsNil)
 }
@@ -0,0 +1,159 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package deployer
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/juju/errors"
+	"gopkg.in/juju/names.v2"
+)
+
+// deploymentDir is the directory, below the data directory, in which
+// the progress of unit deployments is recorded.
+const deploymentDir = "deployments"
+
+// deployStep is a step in deploying a unit agent.
+type deployStep struct {
+	// name identifies the step in the recorded progress.
+	name string
+
+	// run performs the step.
+	run func() error
+
+	// undo reverses the step. It must succeed if the step was
+	// performed only in part, or not at all.
+	undo func() error
+}
+
+// deployStepError is returned when a deployment fails because
+// one of its steps did.
+type deployStepError struct {
+	step string
+	err  error
+}
+
+// Error is part of the error interface.
+func (e *deployStepError) Error() string {
+	return e.err.Error()
+}
+
+// deployment deploys a unit agent by running a sequence of steps.
+// The steps started are recorded in a progress file before they are
+// run, so that a deployment which fails, or is interrupted by the
+// agent stopping, is rolled back in full.
+type deployment struct {
+	unitName string
+	path     string
+	steps    []deployStep
+}
+
+// newDeployment returns a deployment of the agent of the specified
+// unit, recording its progress below the specified data directory.
+func newDeployment(dataDir, unitName string, steps ...deployStep) *deployment {
+	name := names.NewUnitTag(unitName).String()
+	return &deployment{
+		unitName: unitName,
+		path:     filepath.Join(dataDir, deploymentDir, name),
+		steps:    steps,
+	}
+}
+
+// recover rolls back the steps started by an earlier deployment of
+// the unit which did not complete, so that it can be run again.
+func (d *deployment) recover() error {
+	f, err := os.Open(d.path)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	started := make(map[string]bool)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		started[scanner.Text()] = true
+	}
+	f.Close()
+	if err := scanner.Err(); err != nil {
+		return errors.Annotatef(err, "reading deployment progress of unit %q", d.unitName)
+	}
+
+	logger.Infof("rolling back interrupted deployment of unit %q", d.unitName)
+	var steps []deployStep
+	for _, step := range d.steps {
+		if started[step.name] {
+			steps = append(steps, step)
+		}
+	}
+	if err := d.undo(steps); err != nil {
+		return errors.Trace(err)
+	}
+	return d.finish()
+}
+
+// run runs the steps of the deployment in order. If a step fails,
+// those started are undone in reverse order.
+func (d *deployment) run() (err error) {
+	var started []deployStep
+	defer func() {
+		if err == nil {
+			return
+		}
+		if err := d.undo(started); err != nil {
+			logger.Errorf("cannot roll back deployment of unit %q: %v", d.unitName, err)
+			return
+		}
+		if err := d.finish(); err != nil {
+			logger.Errorf("cannot roll back deployment of unit %q: %v", d.unitName, err)
+		}
+	}()
+
+	if err := os.MkdirAll(filepath.Dir(d.path), 0700); err != nil {
+		return errors.Trace(err)
+	}
+	f, err := os.OpenFile(d.path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return errors.Annotatef(err, "recording deployment progress of unit %q", d.unitName)
+	}
+	// The file is closed before the deployment is rolled back.
+	defer f.Close()
+
+	for _, step := range d.steps {
+		if _, err := fmt.Fprintln(f, step.name); err != nil {
+			return errors.Trace(err)
+		}
+		if err := f.Sync(); err != nil {
+			return errors.Trace(err)
+		}
+		started = append(started, step)
+		if err := step.run(); err != nil {
+			return &deployStepError{step: step.name, err: err}
+		}
+	}
+	if err := f.Close(); err != nil {
+		return errors.Trace(err)
+	}
+	return d.finish()
+}
+
+// undo undoes the specified steps in reverse order.
+func (d *deployment) undo(steps []deployStep) error {
+	for i := len(steps) - 1; i >= 0; i-- {
+		if err := steps[i].undo(); err != nil {
+			return errors.Annotatef(err, "undoing %s for unit %q", steps[i].name, d.unitName)
+		}
+	}
+	return nil
+}
+
+// finish removes the record of the deployment's progress.
+func (d *deployment) finish() error {
+	if err := os.Remove(d.path); err != nil && !os.IsNotExist(err) {
+		return errors.Trace(err)
+	}
+	return nil
+}
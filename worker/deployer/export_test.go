@@ -10,6 +10,27 @@ import (
 	svctesting "github.com/juju/juju/service/common/testing"
 )
 
+const (
+	MaxConcurrentChanges = maxConcurrentChanges
+	HealthCheckAttempts  = healthCheckAttempts
+)
+
+var (
+	ArchiveAgentFiles  = archiveAgentFiles
+	PruneAgentArchives = pruneAgentArchives
+	UnitUserName       = unitUserName
+
+	CheckAgentIntrospection = checkAgentIntrospection
+	FailureReason           = failureReason
+
+	HealthCheckInterval   = &healthCheckInterval
+	HealthCheckRetryDelay = &healthCheckRetryDelay
+)
+
+func NewDeployStepError(step string, err error) error {
+	return &deployStepError{step: step, err: err}
+}
+
 type fakeAPI struct{}
 
 func (*fakeAPI) ConnectionInfo() (params.DeployerConnectionValues, error) {
@@ -18,11 +39,28 @@ func (*fakeAPI) ConnectionInfo() (params.DeployerConnectionValues, error) {
 	}, nil
 }
 
-func NewTestSimpleContext(agentConfig agent.Config, logDir string, data *svctesting.FakeServiceData) *SimpleContext {
+func NewTestSimpleContext(
+	agentConfig agent.Config,
+	logDir string,
+	data *svctesting.FakeServiceData,
+	users UserManager,
+	sudoersDir string,
+) *SimpleContext {
 	return &SimpleContext{
-		api:         &fakeAPI{},
-		agentConfig: agentConfig,
-		discoverService: func(name string, conf common.Conf) (deployerService, error) {
+		api:           &fakeAPI{},
+		agentConfig:   agentConfig,
+		initSystem:    NewTestInitSystem(data),
+		archiver:      newArchiver(agentConfig.DataDir()),
+		users:         users,
+		sudoersDir:    sudoersDir,
+		checkAgent:    func(string) error { return nil },
+		loggingConfig: agentLoggingConfig(agentConfig),
+	}
+}
+
+func NewTestInitSystem(data *svctesting.FakeServiceData) InitSystem {
+	return &serviceInitSystem{
+		newService: func(name string, conf common.Conf) (deployerService, error) {
 			svc := svctesting.NewFakeService(name, conf)
 			svc.FakeServiceData = data
 			return svc, nil
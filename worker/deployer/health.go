@@ -0,0 +1,66 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package deployer
+
+import (
+	"net"
+	"net/http"
+	"runtime"
+	"time"
+
+	"github.com/juju/errors"
+	"gopkg.in/juju/names.v2"
+)
+
+const (
+	// healthCheckAttempts is the number of consecutive health checks
+	// an agent must fail before it is restarted.
+	healthCheckAttempts = 3
+
+	// healthCheckTimeout is how long an agent has to respond to a
+	// health check.
+	healthCheckTimeout = 10 * time.Second
+)
+
+var (
+	// healthCheckInterval is how often the agents of deployed
+	// units are health-checked.
+	healthCheckInterval = 5 * time.Minute
+
+	// healthCheckRetryDelay is how long to wait before checking
+	// an agent again after it fails a health check.
+	healthCheckRetryDelay = 10 * time.Second
+)
+
+// checkAgentIntrospection checks that the agent of the specified unit
+// is responsive, by querying the dependency engine report from the
+// agent's introspection socket. The socket is only served on Linux.
+func checkAgentIntrospection(unitName string) error {
+	if runtime.GOOS != "linux" {
+		return errors.NotSupportedf("checking unit agents on %s", runtime.GOOS)
+	}
+	// The socket is in the abstract namespace, named as by the
+	// agent's DefaultIntrospectionSocketName.
+	socketName := "@jujud-" + names.NewUnitTag(unitName).String()
+	client := &http.Client{
+		Transport: &http.Transport{
+			Dial: func(proto, addr string) (net.Conn, error) {
+				return net.Dial("unix", socketName)
+			},
+		},
+		Timeout: healthCheckTimeout,
+	}
+	resp, err := client.Get("http://unix.socket/depengine")
+	if err != nil {
+		return errors.Annotatef(err, "querying agent of unit %q", unitName)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return errors.Errorf(
+			"agent of unit %q responded %d (%s)",
+			unitName, resp.StatusCode, http.StatusText(resp.StatusCode),
+		)
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package deployer_test
+
+import (
+	"net"
+	"net/http"
+	"runtime"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/deployer"
+)
+
+type HealthSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&HealthSuite{})
+
+func (s *HealthSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	if runtime.GOOS != "linux" {
+		c.Skip("introspection sockets are only served on linux")
+	}
+}
+
+func (s *HealthSuite) serve(c *gc.C, code int) {
+	listener, err := net.Listen("unix", "@jujud-unit-foo-123")
+	c.Assert(err, jc.ErrorIsNil)
+	s.AddCleanup(func(*gc.C) { listener.Close() })
+	mux := http.NewServeMux()
+	mux.HandleFunc("/depengine", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(code)
+	})
+	go http.Serve(listener, mux)
+}
+
+func (s *HealthSuite) TestCheckAgentIntrospection(c *gc.C) {
+	s.serve(c, http.StatusOK)
+	err := deployer.CheckAgentIntrospection("foo/123")
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *HealthSuite) TestCheckAgentIntrospectionBadResponse(c *gc.C) {
+	s.serve(c, http.StatusInternalServerError)
+	err := deployer.CheckAgentIntrospection("foo/123")
+	c.Assert(err, gc.ErrorMatches, `agent of unit "foo/123" responded 500 \(Internal Server Error\)`)
+}
+
+func (s *HealthSuite) TestCheckAgentIntrospectionNotListening(c *gc.C) {
+	err := deployer.CheckAgentIntrospection("foo/123")
+	c.Assert(err, gc.ErrorMatches, `querying agent of unit "foo/123": .*`)
+}
@@ -0,0 +1,237 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package deployer
+
+import (
+	"sync"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils/series"
+
+	"github.com/juju/juju/service"
+	"github.com/juju/juju/service/common"
+	"github.com/juju/juju/service/systemd"
+	"github.com/juju/juju/service/upstart"
+	"github.com/juju/juju/service/windows"
+)
+
+// InitSystem manages the init system services which run the
+// agents of units deployed by a SimpleContext. Implementations
+// must be safe to use concurrently for different services.
+type InitSystem interface {
+	// Install installs and starts a service with the
+	// specified name and config.
+	Install(name string, conf common.Conf) error
+
+	// Remove stops and removes the named service.
+	Remove(name string) error
+
+	// Installed reports whether the named service is installed.
+	Installed(name string) (bool, error)
+
+	// List returns the status of all installed services.
+	List() ([]ServiceStatus, error)
+}
+
+// ServiceStatus describes an installed init system service.
+type ServiceStatus struct {
+	// Name is the name of the service.
+	Name string
+
+	// Running reports whether the service is running.
+	Running bool
+}
+
+// NewInitSystem returns the InitSystem with the specified name,
+// which must be one of service.InitSystemUpstart,
+// service.InitSystemSystemd or service.InitSystemWindows.
+// Systemd unit files are written below the data directory.
+func NewInitSystem(name, dataDir string) (InitSystem, error) {
+	switch name {
+	case service.InitSystemUpstart:
+		return NewUpstartInitSystem(), nil
+	case service.InitSystemSystemd:
+		return NewSystemdInitSystem(dataDir), nil
+	case service.InitSystemWindows:
+		return NewWindowsInitSystem(), nil
+	}
+	return nil, errors.NotFoundf("init system %q", name)
+}
+
+// HostInitSystem returns an InitSystem which manages services
+// using the init system of the host's series, determined when
+// the services are first managed.
+func HostInitSystem(dataDir string) InitSystem {
+	return &hostInitSystem{dataDir: dataDir}
+}
+
+// NewUpstartInitSystem returns an InitSystem which manages
+// upstart jobs.
+func NewUpstartInitSystem() InitSystem {
+	return &serviceInitSystem{
+		newService: func(name string, conf common.Conf) (deployerService, error) {
+			return upstart.NewService(name, conf), nil
+		},
+		listServices: upstart.ListServices,
+	}
+}
+
+// NewSystemdInitSystem returns an InitSystem which manages
+// systemd units, keeping the unit files in the data directory.
+func NewSystemdInitSystem(dataDir string) InitSystem {
+	return &serviceInitSystem{
+		newService: func(name string, conf common.Conf) (deployerService, error) {
+			return systemd.NewService(name, conf, dataDir)
+		},
+		listServices: systemd.ListServices,
+	}
+}
+
+// NewWindowsInitSystem returns an InitSystem which manages
+// windows services.
+func NewWindowsInitSystem() InitSystem {
+	return &serviceInitSystem{
+		newService: func(name string, conf common.Conf) (deployerService, error) {
+			return windows.NewService(name, conf)
+		},
+		listServices: windows.ListServices,
+	}
+}
+
+type deployerService interface {
+	Installed() (bool, error)
+	Running() (bool, error)
+	Install() error
+	Remove() error
+	Start() error
+	Stop() error
+}
+
+// serviceInitSystem is an InitSystem which manages services
+// using the implementations in the service package.
+type serviceInitSystem struct {
+	newService   func(string, common.Conf) (deployerService, error)
+	listServices func() ([]string, error)
+}
+
+// Install is part of the InitSystem interface.
+func (s *serviceInitSystem) Install(name string, conf common.Conf) error {
+	svc, err := s.newService(name, conf)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(service.InstallAndStart(svc))
+}
+
+// Remove is part of the InitSystem interface.
+func (s *serviceInitSystem) Remove(name string) error {
+	svc, err := s.newService(name, common.Conf{})
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err := svc.Stop(); err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(svc.Remove())
+}
+
+// Installed is part of the InitSystem interface.
+func (s *serviceInitSystem) Installed(name string) (bool, error) {
+	svc, err := s.newService(name, common.Conf{})
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	installed, err := svc.Installed()
+	return installed, errors.Trace(err)
+}
+
+// List is part of the InitSystem interface.
+func (s *serviceInitSystem) List() ([]ServiceStatus, error) {
+	names, err := s.listServices()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	statuses := make([]ServiceStatus, len(names))
+	for i, name := range names {
+		svc, err := s.newService(name, common.Conf{})
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		running, err := svc.Running()
+		if err != nil {
+			return nil, errors.Annotatef(err, "checking service %q", name)
+		}
+		statuses[i] = ServiceStatus{Name: name, Running: running}
+	}
+	return statuses, nil
+}
+
+// hostInitSystem is an InitSystem which delegates to the
+// InitSystem for the host's series.
+type hostInitSystem struct {
+	dataDir string
+
+	// mu guards initSystem, which is determined
+	// when first needed.
+	mu         sync.Mutex
+	initSystem InitSystem
+}
+
+func (h *hostInitSystem) host() (InitSystem, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.initSystem != nil {
+		return h.initSystem, nil
+	}
+	hostSeries, err := series.HostSeries()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	name, err := service.VersionInitSystem(hostSeries)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	initSystem, err := NewInitSystem(name, h.dataDir)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	h.initSystem = initSystem
+	return initSystem, nil
+}
+
+// Install is part of the InitSystem interface.
+func (h *hostInitSystem) Install(name string, conf common.Conf) error {
+	initSystem, err := h.host()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return initSystem.Install(name, conf)
+}
+
+// Remove is part of the InitSystem interface.
+func (h *hostInitSystem) Remove(name string) error {
+	initSystem, err := h.host()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return initSystem.Remove(name)
+}
+
+// Installed is part of the InitSystem interface.
+func (h *hostInitSystem) Installed(name string) (bool, error) {
+	initSystem, err := h.host()
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	return initSystem.Installed(name)
+}
+
+// List is part of the InitSystem interface.
+func (h *hostInitSystem) List() ([]ServiceStatus, error) {
+	initSystem, err := h.host()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return initSystem.List()
+}
@@ -0,0 +1,81 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package deployer_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/service"
+	"github.com/juju/juju/service/common"
+	svctesting "github.com/juju/juju/service/common/testing"
+	"github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/deployer"
+)
+
+type InitSystemSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&InitSystemSuite{})
+
+func (s *InitSystemSuite) TestNewInitSystem(c *gc.C) {
+	for _, name := range []string{
+		service.InitSystemUpstart,
+		service.InitSystemSystemd,
+		service.InitSystemWindows,
+	} {
+		initSystem, err := deployer.NewInitSystem(name, c.MkDir())
+		c.Check(err, jc.ErrorIsNil)
+		c.Check(initSystem, gc.NotNil)
+	}
+}
+
+func (s *InitSystemSuite) TestNewInitSystemUnknown(c *gc.C) {
+	_, err := deployer.NewInitSystem("runit", c.MkDir())
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	c.Assert(err, gc.ErrorMatches, `init system "runit" not found`)
+}
+
+func (s *InitSystemSuite) TestInstallRemove(c *gc.C) {
+	data := svctesting.NewFakeServiceData()
+	initSystem := deployer.NewTestInitSystem(data)
+
+	err := initSystem.Install("jujud-unit-foo-0", common.Conf{Desc: "foo", ExecStart: "/bin/true"})
+	c.Assert(err, jc.ErrorIsNil)
+	installed, err := initSystem.Installed("jujud-unit-foo-0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(installed, jc.IsTrue)
+	statuses, err := initSystem.List()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(statuses, jc.DeepEquals, []deployer.ServiceStatus{
+		{Name: "jujud-unit-foo-0", Running: true},
+	})
+
+	err = initSystem.Remove("jujud-unit-foo-0")
+	c.Assert(err, jc.ErrorIsNil)
+	installed, err = initSystem.Installed("jujud-unit-foo-0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(installed, jc.IsFalse)
+	statuses, err = initSystem.List()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(statuses, gc.HasLen, 0)
+}
+
+func (s *InitSystemSuite) TestListStopped(c *gc.C) {
+	data := svctesting.NewFakeServiceData()
+	err := data.SetStatus("jujud-unit-foo-0", "installed")
+	c.Assert(err, jc.ErrorIsNil)
+	err = data.SetStatus("jujud-unit-foo-1", "running")
+	c.Assert(err, jc.ErrorIsNil)
+	initSystem := deployer.NewTestInitSystem(data)
+
+	statuses, err := initSystem.List()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(statuses, jc.SameContents, []deployer.ServiceStatus{
+		{Name: "jujud-unit-foo-0", Running: false},
+		{Name: "jujud-unit-foo-1", Running: true},
+	})
+}
@@ -0,0 +1,311 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package deployer
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/juju/errors"
+	"gopkg.in/juju/names.v2"
+	worker "gopkg.in/juju/worker.v1"
+
+	"github.com/juju/juju/agent"
+	jworker "github.com/juju/juju/worker"
+)
+
+// inProcessUnitDir is the directory, below the data directory, which
+// records the units whose agents are run in process.
+const inProcessUnitDir = "in-process-units"
+
+// NewUnitAgentFunc returns a worker which runs the agent of the
+// specified unit, whose files are already written, in the current
+// process.
+type NewUnitAgentFunc func(unitName string) (worker.Worker, error)
+
+// InProcessContext is a Context that runs each unit agent in the
+// process of the machine agent running the deployer, with its own
+// dependency engine, rather than as a service of its own; this uses
+// much less memory on machines with many units. The agents' files are
+// written as by a SimpleContext, and the agents are restarted if they
+// stop, until they are recalled.
+//
+// It is a worker, which stops the unit agents when it is killed, and
+// starts those already deployed when it is created. It is safe to use
+// concurrently for different units.
+type InProcessContext struct {
+
+	// api is used to get the current controller addresses at the time the
+	// given unit is deployed.
+	api APICalls
+
+	// agentConfig returns the agent config for the machine agent that is
+	// running the deployer.
+	agentConfig agent.Config
+
+	// newAgent returns the workers which run the unit agents.
+	newAgent NewUnitAgentFunc
+
+	// archiver archives the agent files of recalled units.
+	archiver *archiver
+
+	// runner runs the unit agents, keyed on unit name.
+	runner *worker.Runner
+
+	// mu guards the fields below.
+	mu sync.Mutex
+
+	// loggingConfig is the logging config of the unit agents.
+	loggingConfig string
+
+	// stopped holds, for each unit, a channel which is closed when
+	// the latest worker running its agent stops.
+	stopped map[string]chan struct{}
+}
+
+var (
+	_ Context       = (*InProcessContext)(nil)
+	_ worker.Worker = (*InProcessContext)(nil)
+)
+
+// NewInProcessContext returns a new InProcessContext, acting on behalf
+// of the specified deployer, that runs unit agents using the workers
+// returned by newAgent.
+func NewInProcessContext(agentConfig agent.Config, api APICalls, newAgent NewUnitAgentFunc) (*InProcessContext, error) {
+	ctx := &InProcessContext{
+		api:           api,
+		agentConfig:   agentConfig,
+		newAgent:      newAgent,
+		archiver:      newArchiver(agentConfig.DataDir()),
+		loggingConfig: agentLoggingConfig(agentConfig),
+		stopped:       make(map[string]chan struct{}),
+		runner: worker.NewRunner(worker.RunnerParams{
+			// Unit agents are restarted until they are recalled.
+			IsFatal:      func(error) bool { return false },
+			RestartDelay: jworker.RestartDelay,
+		}),
+	}
+	deployed, err := ctx.DeployedUnits()
+	if err == nil {
+		for _, unitName := range deployed {
+			if err = ctx.startAgent(unitName); err != nil {
+				break
+			}
+		}
+	}
+	if err != nil {
+		worker.Stop(ctx.runner)
+		return nil, errors.Trace(err)
+	}
+	return ctx, nil
+}
+
+// Kill is part of the worker.Worker interface.
+func (ctx *InProcessContext) Kill() {
+	ctx.runner.Kill()
+}
+
+// Wait is part of the worker.Worker interface.
+func (ctx *InProcessContext) Wait() error {
+	return ctx.runner.Wait()
+}
+
+// AgentConfig is part of the Context interface.
+func (ctx *InProcessContext) AgentConfig() agent.Config {
+	return ctx.agentConfig
+}
+
+// LoggingConfig is part of the Context interface.
+func (ctx *InProcessContext) LoggingConfig() string {
+	ctx.mu.Lock()
+	defer ctx.mu.Unlock()
+	return ctx.loggingConfig
+}
+
+// SetLoggingConfig is part of the Context interface. The unit agents
+// log with the machine agent, so their logging config is that of the
+// machine agent's process.
+func (ctx *InProcessContext) SetLoggingConfig(loggingConfig string) {
+	ctx.mu.Lock()
+	defer ctx.mu.Unlock()
+	ctx.loggingConfig = loggingConfig
+}
+
+// SetUnitAgentUsers is part of the Context interface. Unit agents
+// always run as the user running the machine agent, since they share
+// its process.
+func (ctx *InProcessContext) SetUnitAgentUsers(enabled bool) {}
+
+// SetMaxArchiveAge is part of the Context interface.
+func (ctx *InProcessContext) SetMaxArchiveAge(maxAge time.Duration) {
+	ctx.archiver.setMaxAge(maxAge)
+}
+
+// DeployUnit is part of the Context interface. Nothing is left behind
+// if it fails, and a deployment interrupted by the agent stopping is
+// rolled back before the unit is deployed again.
+func (ctx *InProcessContext) DeployUnit(unitName, initialPassword string, limits ResourceLimits) error {
+	// Link the current tools for use by the new agent, write
+	// its config, and start running it.
+	steps := agentFileSteps(ctx.agentConfig, ctx.api, unitName, initialPassword)
+	steps = append(steps, deployStep{
+		name: "agent",
+		run: func() error {
+			if err := os.MkdirAll(ctx.unitDir(), 0755); err != nil {
+				return errors.Trace(err)
+			}
+			if err := ioutil.WriteFile(ctx.unitPath(unitName), nil, 0644); err != nil {
+				return errors.Trace(err)
+			}
+			return ctx.startAgent(unitName)
+		},
+		undo: func() error {
+			if err := ctx.stopAgent(unitName); err != nil {
+				return errors.Trace(err)
+			}
+			return ctx.removeUnit(unitName)
+		},
+	})
+	deployment := newDeployment(ctx.agentConfig.DataDir(), unitName, steps...)
+	if err := deployment.recover(); err != nil {
+		return errors.Trace(err)
+	}
+	deployed, err := ctx.isDeployed(unitName)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if deployed {
+		return errors.Errorf("unit %q is already deployed", unitName)
+	}
+	return errors.Trace(deployment.run())
+}
+
+// UpdateUnit is part of the Context interface. Unit agents are not
+// subject to resource limits, since they share the machine agent's
+// process, so they are just restarted.
+func (ctx *InProcessContext) UpdateUnit(unitName string, limits ResourceLimits) error {
+	deployed, err := ctx.isDeployed(unitName)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if !deployed {
+		return errors.Errorf("unit %q is not deployed", unitName)
+	}
+	if err := ctx.stopAgent(unitName); err != nil {
+		return errors.Trace(err)
+	}
+	return ctx.startAgent(unitName)
+}
+
+// RecallUnit is part of the Context interface.
+func (ctx *InProcessContext) RecallUnit(unitName string) error {
+	deployed, err := ctx.isDeployed(unitName)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if !deployed {
+		return errors.Errorf("unit %q is not deployed", unitName)
+	}
+	if err := ctx.stopAgent(unitName); err != nil {
+		return errors.Trace(err)
+	}
+	ctx.archiver.archive(unitName)
+	if err := removeAgentFiles(ctx.agentConfig.DataDir(), unitName); err != nil {
+		return errors.Trace(err)
+	}
+	return ctx.removeUnit(unitName)
+}
+
+// DeployedUnits is part of the Context interface.
+func (ctx *InProcessContext) DeployedUnits() ([]string, error) {
+	infos, err := ioutil.ReadDir(ctx.unitDir())
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var deployed []string
+	for _, info := range infos {
+		tag, err := names.ParseUnitTag(info.Name())
+		if err != nil {
+			continue
+		}
+		deployed = append(deployed, tag.Id())
+	}
+	return deployed, nil
+}
+
+// CheckUnit is part of the Context interface. Agents are checked via
+// their introspection sockets, which they serve as usual.
+func (ctx *InProcessContext) CheckUnit(unitName string) error {
+	return checkAgentIntrospection(unitName)
+}
+
+// startAgent starts running the agent of the specified unit.
+func (ctx *InProcessContext) startAgent(unitName string) error {
+	logger.Infof("starting agent of unit %q in process", unitName)
+	return ctx.runner.StartWorker(unitName, func() (worker.Worker, error) {
+		w, err := ctx.newAgent(unitName)
+		if err != nil {
+			return nil, errors.Annotatef(err, "starting agent of unit %q", unitName)
+		}
+		stopped := make(chan struct{})
+		go func() {
+			w.Wait()
+			close(stopped)
+		}()
+		ctx.mu.Lock()
+		ctx.stopped[unitName] = stopped
+		ctx.mu.Unlock()
+		return w, nil
+	})
+}
+
+// stopAgent stops running the agent of the specified unit, and waits
+// for it to stop, so that its files can be removed.
+func (ctx *InProcessContext) stopAgent(unitName string) error {
+	if err := ctx.runner.StopWorker(unitName); err != nil {
+		return errors.Trace(err)
+	}
+	ctx.mu.Lock()
+	stopped := ctx.stopped[unitName]
+	delete(ctx.stopped, unitName)
+	ctx.mu.Unlock()
+	if stopped != nil {
+		<-stopped
+	}
+	return nil
+}
+
+// isDeployed reports whether the specified unit is deployed.
+func (ctx *InProcessContext) isDeployed(unitName string) (bool, error) {
+	_, err := os.Stat(ctx.unitPath(unitName))
+	if os.IsNotExist(err) {
+		return false, nil
+	} else if err != nil {
+		return false, errors.Trace(err)
+	}
+	return true, nil
+}
+
+// removeUnit removes the record that the specified unit is deployed.
+func (ctx *InProcessContext) removeUnit(unitName string) error {
+	if err := os.Remove(ctx.unitPath(unitName)); err != nil && !os.IsNotExist(err) {
+		return errors.Trace(err)
+	}
+	return nil
+}
+
+// unitDir returns the directory in which the deployed units are
+// recorded, each by an empty file named after its tag.
+func (ctx *InProcessContext) unitDir() string {
+	return filepath.Join(ctx.agentConfig.DataDir(), inProcessUnitDir)
+}
+
+func (ctx *InProcessContext) unitPath(unitName string) string {
+	return filepath.Join(ctx.unitDir(), names.NewUnitTag(unitName).String())
+}
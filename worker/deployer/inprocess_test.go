@@ -0,0 +1,133 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package deployer_test
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v2"
+	worker "gopkg.in/juju/worker.v1"
+
+	"github.com/juju/juju/agent"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/deployer"
+	"github.com/juju/juju/worker/workertest"
+)
+
+type InProcessContextSuite struct {
+	SimpleToolsFixture
+
+	mu      sync.Mutex
+	started []string
+}
+
+var _ = gc.Suite(&InProcessContextSuite{})
+
+func (s *InProcessContextSuite) SetUpTest(c *gc.C) {
+	s.SimpleToolsFixture.SetUp(c, c.MkDir())
+	s.started = nil
+}
+
+func (s *InProcessContextSuite) TearDownTest(c *gc.C) {
+	s.SimpleToolsFixture.TearDown(c)
+}
+
+func (s *InProcessContextSuite) newAgent(unitName string) (worker.Worker, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.started = append(s.started, unitName)
+	return workertest.NewErrorWorker(nil), nil
+}
+
+func (s *InProcessContextSuite) getContext(c *gc.C) *deployer.InProcessContext {
+	config := agentConfig(names.NewMachineTag("99"), s.dataDir, s.logDir)
+	ctx, err := deployer.NewInProcessContext(config, fakeAPICalls{}, s.newAgent)
+	c.Assert(err, jc.ErrorIsNil)
+	return ctx
+}
+
+func (s *InProcessContextSuite) waitStarted(c *gc.C, expected ...string) {
+	timeout := time.After(coretesting.LongWait)
+	for {
+		s.mu.Lock()
+		started := append([]string(nil), s.started...)
+		s.mu.Unlock()
+		if len(started) >= len(expected) {
+			c.Assert(started, jc.DeepEquals, expected)
+			return
+		}
+		select {
+		case <-timeout:
+			c.Fatalf("agents started: %v", started)
+		case <-time.After(coretesting.ShortWait):
+		}
+	}
+}
+
+func (s *InProcessContextSuite) TestDeployRecall(c *gc.C) {
+	ctx := s.getContext(c)
+	defer workertest.CleanKill(c, ctx)
+	units, err := ctx.DeployedUnits()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(units, gc.HasLen, 0)
+
+	err = ctx.DeployUnit("foo/123", "some-password", deployer.ResourceLimits{})
+	c.Assert(err, jc.ErrorIsNil)
+	units, err = ctx.DeployedUnits()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(units, jc.DeepEquals, []string{"foo/123"})
+	s.waitStarted(c, "foo/123")
+	agentDir := agent.Dir(s.dataDir, names.NewUnitTag("foo/123"))
+	_, err = os.Stat(filepath.Join(agentDir, "agent.conf"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.data.InstalledNames(), gc.HasLen, 0)
+
+	err = ctx.DeployUnit("foo/123", "some-password", deployer.ResourceLimits{})
+	c.Assert(err, gc.ErrorMatches, `unit "foo/123" is already deployed`)
+
+	err = ctx.RecallUnit("foo/123")
+	c.Assert(err, jc.ErrorIsNil)
+	units, err = ctx.DeployedUnits()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(units, gc.HasLen, 0)
+	_, err = os.Stat(agentDir)
+	c.Assert(err, jc.Satisfies, os.IsNotExist)
+
+	err = ctx.RecallUnit("foo/123")
+	c.Assert(err, gc.ErrorMatches, `unit "foo/123" is not deployed`)
+}
+
+func (s *InProcessContextSuite) TestUpdateRestartsAgent(c *gc.C) {
+	ctx := s.getContext(c)
+	defer workertest.CleanKill(c, ctx)
+	err := ctx.DeployUnit("foo/123", "some-password", deployer.ResourceLimits{})
+	c.Assert(err, jc.ErrorIsNil)
+	s.waitStarted(c, "foo/123")
+
+	err = ctx.UpdateUnit("foo/123", deployer.ResourceLimits{})
+	c.Assert(err, jc.ErrorIsNil)
+	s.waitStarted(c, "foo/123", "foo/123")
+}
+
+func (s *InProcessContextSuite) TestStartsDeployedAgents(c *gc.C) {
+	ctx := s.getContext(c)
+	err := ctx.DeployUnit("foo/123", "some-password", deployer.ResourceLimits{})
+	c.Assert(err, jc.ErrorIsNil)
+	s.waitStarted(c, "foo/123")
+	workertest.CleanKill(c, ctx)
+
+	// The agents of units already deployed are started
+	// when a new context is created.
+	ctx = s.getContext(c)
+	defer workertest.CleanKill(c, ctx)
+	units, err := ctx.DeployedUnits()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(units, jc.DeepEquals, []string{"foo/123"})
+	s.waitStarted(c, "foo/123", "foo/123")
+}
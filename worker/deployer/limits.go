@@ -0,0 +1,65 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package deployer
+
+import (
+	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/service/common"
+)
+
+const (
+	// defaultCPUShares is the number of CPU shares given to each
+	// process group by the kernel; a unit with a cpu-power of 100,
+	// i.e. one core, gets that many shares.
+	defaultCPUShares = 1024
+
+	// unitAgentMaxOpenFiles is the maximum number of files that
+	// may be held open by a unit agent and its hooks.
+	unitAgentMaxOpenFiles = 20000
+)
+
+// ResourceLimits holds the limits on the resources that may be used
+// by a deployed unit agent and the processes it runs, so that a
+// runaway unit cannot starve the rest of the machine. Zero values
+// leave the corresponding resource unlimited.
+type ResourceLimits struct {
+	// CPUShares is the agent's relative share of CPU time.
+	CPUShares int
+
+	// MemoryMB is the memory available to the agent, in MB.
+	MemoryMB uint64
+
+	// MaxOpenFiles is the maximum number of open files.
+	MaxOpenFiles int
+}
+
+// NewResourceLimits returns the resource limits of the agent of a
+// unit with the specified constraints. The CPU share is in proportion
+// to the unit's cpu-power, and its memory is capped at its mem
+// constraint.
+func NewResourceLimits(cons constraints.Value) ResourceLimits {
+	limits := ResourceLimits{
+		MaxOpenFiles: unitAgentMaxOpenFiles,
+	}
+	if cons.HasCpuPower() {
+		limits.CPUShares = int(*cons.CpuPower * defaultCPUShares / 100)
+	}
+	if cons.Mem != nil {
+		limits.MemoryMB = *cons.Mem
+	}
+	return limits
+}
+
+// apply sets the limits in the conf of the init system service
+// running the agent.
+func (limits ResourceLimits) apply(conf *common.Conf) {
+	conf.CPUShares = limits.CPUShares
+	conf.MemoryLimitMB = limits.MemoryMB
+	if limits.MaxOpenFiles > 0 {
+		if conf.Limit == nil {
+			conf.Limit = make(map[string]int)
+		}
+		conf.Limit["nofile"] = limits.MaxOpenFiles
+	}
+}
@@ -0,0 +1,42 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package deployer_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/deployer"
+)
+
+type ResourceLimitsSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&ResourceLimitsSuite{})
+
+func (s *ResourceLimitsSuite) TestNewResourceLimits(c *gc.C) {
+	for i, test := range []struct {
+		cons   string
+		limits deployer.ResourceLimits
+	}{{
+		cons:   "",
+		limits: deployer.ResourceLimits{MaxOpenFiles: 20000},
+	}, {
+		cons:   "cpu-power=100",
+		limits: deployer.ResourceLimits{CPUShares: 1024, MaxOpenFiles: 20000},
+	}, {
+		cons:   "cpu-power=50 mem=2G",
+		limits: deployer.ResourceLimits{CPUShares: 512, MemoryMB: 2048, MaxOpenFiles: 20000},
+	}, {
+		cons:   "cpu-cores=4 root-disk=8G",
+		limits: deployer.ResourceLimits{MaxOpenFiles: 20000},
+	}} {
+		c.Logf("test %d: %q", i, test.cons)
+		limits := deployer.NewResourceLimits(constraints.MustParse(test.cons))
+		c.Check(limits, jc.DeepEquals, test.limits)
+	}
+}
@@ -5,6 +5,7 @@ package deployer
 
 import (
 	"github.com/juju/errors"
+	"github.com/prometheus/client_golang/prometheus"
 	"gopkg.in/juju/names.v2"
 	worker "gopkg.in/juju/worker.v1"
 
@@ -12,6 +13,7 @@ import (
 	apiagent "github.com/juju/juju/api/agent"
 	"github.com/juju/juju/api/base"
 	apideployer "github.com/juju/juju/api/deployer"
+	apilogger "github.com/juju/juju/api/logger"
 	"github.com/juju/juju/cmd/jujud/agent/engine"
 	"github.com/juju/juju/state/multiwatcher"
 	"github.com/juju/juju/worker/dependency"
@@ -22,6 +24,10 @@ type ManifoldConfig struct {
 	AgentName        string
 	APICallerName    string
 	NewDeployContext func(st *apideployer.State, agentConfig agent.Config) Context
+
+	// PrometheusRegisterer is used to register the deployer's
+	// metrics, which are kept when the worker is restarted.
+	PrometheusRegisterer prometheus.Registerer
 }
 
 // Manifold returns a dependency manifold that runs a deployer worker,
@@ -31,14 +37,20 @@ func Manifold(config ManifoldConfig) dependency.Manifold {
 		AgentName:     config.AgentName,
 		APICallerName: config.APICallerName,
 	}
-	return engine.AgentAPIManifold(typedConfig, config.newWorker)
+	metrics := NewMetrics()
+	return engine.AgentAPIManifold(typedConfig, func(a agent.Agent, apiCaller base.APICaller) (worker.Worker, error) {
+		return config.newWorker(a, apiCaller, metrics)
+	})
 }
 
 // newWorker trivially wraps NewDeployer for use in a engine.AgentAPIManifold.
 //
 // It's not tested at the moment, because the scaffolding
 // necessary is too unwieldy/distracting to introduce at this point.
-func (config ManifoldConfig) newWorker(a agent.Agent, apiCaller base.APICaller) (worker.Worker, error) {
+func (config ManifoldConfig) newWorker(a agent.Agent, apiCaller base.APICaller, metrics *Metrics) (worker.Worker, error) {
+	if config.PrometheusRegisterer == nil {
+		return nil, errors.NotValidf("nil PrometheusRegisterer")
+	}
 	cfg := a.CurrentConfig()
 	// Grab the tag and ensure that it's for a machine.
 	tag, ok := cfg.Tag().(names.MachineTag)
@@ -70,10 +82,20 @@ func (config ManifoldConfig) newWorker(a agent.Agent, apiCaller base.APICaller)
 		return nil, dependency.ErrUninstall
 	}
 
+	// The metrics are already registered if the worker is restarting.
+	config.PrometheusRegisterer.Unregister(metrics)
+	if err := config.PrometheusRegisterer.Register(metrics); err != nil {
+		return nil, errors.Trace(err)
+	}
+
 	deployerFacade := apideployer.NewState(apiCaller)
 	context := config.NewDeployContext(deployerFacade, cfg)
-	w, err := NewDeployer(deployerFacade, context)
+	loggerFacade := apilogger.NewState(apiCaller)
+	w, err := NewDeployer(deployerFacade, loggerFacade, agentFacade, context, metrics)
 	if err != nil {
+		if contextWorker, ok := context.(worker.Worker); ok {
+			worker.Stop(contextWorker)
+		}
 		return nil, errors.Annotate(err, "cannot start unit agent deployer worker")
 	}
 	return w, nil
@@ -0,0 +1,122 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package deployer
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/juju/juju/apiserver/params"
+)
+
+const (
+	metricsNamespace = "juju"
+	metricsSubsystem = "deployer"
+
+	operationLabel = "operation"
+	reasonLabel    = "reason"
+
+	operationDeploy = "deploy"
+	operationUpdate = "update"
+	operationRecall = "recall"
+	operationRemove = "remove"
+)
+
+// Metrics is a prometheus.Collector of metrics about the unit agents
+// deployed by a Deployer, so that operators can alert on machines
+// which fail to converge their units.
+type Metrics struct {
+	operations *prometheus.CounterVec
+	failures   *prometheus.CounterVec
+	durations  *prometheus.SummaryVec
+	deployed   prometheus.Gauge
+}
+
+// NewMetrics returns a new Metrics.
+func NewMetrics() *Metrics {
+	return &Metrics{
+		operations: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: metricsNamespace,
+				Subsystem: metricsSubsystem,
+				Name:      "operations_total",
+				Help:      "Number of operations on unit agents attempted.",
+			},
+			[]string{operationLabel},
+		),
+		failures: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: metricsNamespace,
+				Subsystem: metricsSubsystem,
+				Name:      "operation_failures_total",
+				Help:      "Number of operations on unit agents which failed, by reason.",
+			},
+			[]string{operationLabel, reasonLabel},
+		),
+		durations: prometheus.NewSummaryVec(
+			prometheus.SummaryOpts{
+				Namespace: metricsNamespace,
+				Subsystem: metricsSubsystem,
+				Name:      "operation_duration_seconds",
+				Help:      "Latency of operations on unit agents in seconds.",
+			},
+			[]string{operationLabel},
+		),
+		deployed: prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Namespace: metricsNamespace,
+				Subsystem: metricsSubsystem,
+				Name:      "deployed_units",
+				Help:      "Number of unit agents deployed on the machine.",
+			},
+		),
+	}
+}
+
+// Describe is part of the prometheus.Collector interface.
+func (m *Metrics) Describe(ch chan<- *prometheus.Desc) {
+	m.operations.Describe(ch)
+	m.failures.Describe(ch)
+	m.durations.Describe(ch)
+	m.deployed.Describe(ch)
+}
+
+// Collect is part of the prometheus.Collector interface.
+func (m *Metrics) Collect(ch chan<- prometheus.Metric) {
+	m.operations.Collect(ch)
+	m.failures.Collect(ch)
+	m.durations.Collect(ch)
+	m.deployed.Collect(ch)
+}
+
+// observe records an operation on a unit agent which started at the
+// specified time and failed if *err is not nil. It is intended to be
+// deferred.
+func (m *Metrics) observe(operation string, started time.Time, err *error) {
+	m.operations.WithLabelValues(operation).Inc()
+	m.durations.WithLabelValues(operation).Observe(time.Since(started).Seconds())
+	if *err != nil {
+		m.failures.WithLabelValues(operation, failureReason(*err)).Inc()
+	}
+}
+
+// setDeployed records the number of unit agents deployed.
+func (m *Metrics) setDeployed(count int) {
+	m.deployed.Set(float64(count))
+}
+
+// failureReason returns a short description of why an operation
+// failed, suitable for use as a label value: the step of a failed
+// deployment, or the code of a failed API call.
+func failureReason(err error) string {
+	if stepErr, ok := errors.Cause(err).(*deployStepError); ok {
+		return stepErr.step
+	}
+	if code := params.ErrCode(err); code != "" {
+		return code
+	}
+	return "unknown"
+}
@@ -0,0 +1,38 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package deployer_test
+
+import (
+	"github.com/juju/errors"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/deployer"
+)
+
+type MetricsSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&MetricsSuite{})
+
+func (s *MetricsSuite) TestFailureReason(c *gc.C) {
+	for i, test := range []struct {
+		err    error
+		reason string
+	}{{
+		err:    errors.Trace(deployer.NewDeployStepError("service", errors.New("boom"))),
+		reason: "service",
+	}, {
+		err:    common.ServerError(errors.NotFoundf("unit")),
+		reason: "not found",
+	}, {
+		err:    errors.New("boom"),
+		reason: "unknown",
+	}} {
+		c.Logf("test %d: %v", i, test.err)
+		c.Check(deployer.FailureReason(test.err), gc.Equals, test.reason)
+	}
+}
@@ -8,6 +8,8 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"sync"
+	"time"
 
 	"github.com/juju/errors"
 	"github.com/juju/utils/arch"
@@ -32,6 +34,10 @@ type APICalls interface {
 }
 
 // SimpleContext is a Context that manages unit deployments on the local system.
+// On Windows, with the windows init system, unit agents are installed as
+// services of the service control manager; as on other systems, the agents
+// write their own log files, since their output is not redirected.
+// It is safe to use concurrently for different units.
 type SimpleContext struct {
 
 	// api is used to get the current controller addresses at the time the
@@ -42,11 +48,32 @@ type SimpleContext struct {
 	// running the deployer.
 	agentConfig agent.Config
 
-	// discoverService is a surrogate for service.DiscoverService.
-	discoverService func(string, common.Conf) (deployerService, error)
+	// initSystem manages the services which run the unit agents.
+	initSystem InitSystem
 
-	// listServices is a surrogate for service.ListServices.
-	listServices func() ([]string, error)
+	// archiver archives the agent files of recalled units.
+	archiver *archiver
+
+	// users manages the system users as which unit agents are run,
+	// when unitAgentUsers is set, and sudoersDir holds their sudo
+	// config.
+	users      UserManager
+	sudoersDir string
+
+	// checkAgent checks that the agent of a deployed unit is
+	// responding.
+	checkAgent func(unitName string) error
+
+	// mu guards the fields below.
+	mu sync.Mutex
+
+	// loggingConfig is the logging config with which the
+	// unit agents are started.
+	loggingConfig string
+
+	// unitAgentUsers is whether unit agents are run as their
+	// own system users.
+	unitAgentUsers bool
 }
 
 var _ Context = (*SimpleContext)(nil)
@@ -70,18 +97,19 @@ func recursiveChmod(path string, mode os.FileMode) error {
 }
 
 // NewSimpleContext returns a new SimpleContext, acting on behalf of
-// the specified deployer, that deploys unit agents.
+// the specified deployer, that deploys unit agents as services of
+// the specified init system.
 // Paths to which agents and tools are installed are relative to dataDir.
-func NewSimpleContext(agentConfig agent.Config, api APICalls) *SimpleContext {
+func NewSimpleContext(agentConfig agent.Config, initSystem InitSystem, api APICalls) *SimpleContext {
 	return &SimpleContext{
-		api:         api,
-		agentConfig: agentConfig,
-		discoverService: func(name string, conf common.Conf) (deployerService, error) {
-			return service.DiscoverService(name, conf)
-		},
-		listServices: func() ([]string, error) {
-			return service.ListServices()
-		},
+		api:           api,
+		agentConfig:   agentConfig,
+		initSystem:    initSystem,
+		archiver:      newArchiver(agentConfig.DataDir()),
+		users:         NewUserManager(),
+		sudoersDir:    defaultSudoersDir,
+		checkAgent:    checkAgentIntrospection,
+		loggingConfig: agentLoggingConfig(agentConfig),
 	}
 }
 
@@ -89,28 +117,152 @@ func (ctx *SimpleContext) AgentConfig() agent.Config {
 	return ctx.agentConfig
 }
 
-func (ctx *SimpleContext) DeployUnit(unitName, initialPassword string) (err error) {
+// LoggingConfig is part of the Context interface.
+func (ctx *SimpleContext) LoggingConfig() string {
+	ctx.mu.Lock()
+	defer ctx.mu.Unlock()
+	return ctx.loggingConfig
+}
+
+// SetLoggingConfig is part of the Context interface.
+func (ctx *SimpleContext) SetLoggingConfig(loggingConfig string) {
+	ctx.mu.Lock()
+	defer ctx.mu.Unlock()
+	ctx.loggingConfig = loggingConfig
+}
+
+// SetUnitAgentUsers is part of the Context interface. The agents of
+// units already deployed continue to run as the same user.
+func (ctx *SimpleContext) SetUnitAgentUsers(enabled bool) {
+	ctx.mu.Lock()
+	defer ctx.mu.Unlock()
+	ctx.unitAgentUsers = enabled
+}
+
+func (ctx *SimpleContext) useUnitAgentUsers() bool {
+	ctx.mu.Lock()
+	defer ctx.mu.Unlock()
+	return ctx.unitAgentUsers
+}
+
+// SetMaxArchiveAge is part of the Context interface.
+func (ctx *SimpleContext) SetMaxArchiveAge(maxAge time.Duration) {
+	ctx.archiver.setMaxAge(maxAge)
+}
+
+// DeployUnit is part of the Context interface. Nothing is left behind
+// if it fails, and a deployment interrupted by the agent stopping is
+// rolled back before the unit is deployed again.
+func (ctx *SimpleContext) DeployUnit(unitName, initialPassword string, limits ResourceLimits) error {
 	// Check sanity.
 	renderer, err := shell.NewRenderer("")
 	if err != nil {
 		return errors.Trace(err)
 	}
-	svc, err := ctx.service(unitName, renderer)
+	svcName, svcConf, err := ctx.service(unitName, renderer)
 	if err != nil {
 		return errors.Trace(err)
 	}
-	installed, err := svc.Installed()
+	limits.apply(&svcConf)
+
+	// Link the current tools for use by the new agent, write
+	// its config, create the user it runs as if necessary, and
+	// install an init service that runs it.
+	steps := agentFileSteps(ctx.agentConfig, ctx.api, unitName, initialPassword)
+	if ctx.useUnitAgentUsers() {
+		step, err := unitUserStep(ctx.users, ctx.sudoersDir, ctx.agentConfig, unitName)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		steps = append(steps, step)
+		svcConf.User, err = unitUserName(unitName)
+		if err != nil {
+			return errors.Trace(err)
+		}
+	}
+	steps = append(steps, deployStep{
+		name: "service",
+		run: func() error {
+			return ctx.initSystem.Install(svcName, svcConf)
+		},
+		undo: func() error {
+			installed, err := ctx.initSystem.Installed(svcName)
+			if err != nil || !installed {
+				return errors.Trace(err)
+			}
+			return ctx.initSystem.Remove(svcName)
+		},
+	})
+	deployment := newDeployment(ctx.agentConfig.DataDir(), unitName, steps...)
+	if err := deployment.recover(); err != nil {
+		return errors.Trace(err)
+	}
+	installed, err := ctx.initSystem.Installed(svcName)
 	if err != nil {
 		return errors.Trace(err)
 	}
 	if installed {
 		return fmt.Errorf("unit %q is already deployed", unitName)
 	}
+	return errors.Trace(deployment.run())
+}
+
+// UpdateUnit is part of the Context interface.
+func (ctx *SimpleContext) UpdateUnit(unitName string, limits ResourceLimits) error {
+	job, err := ctx.findInitSystemJob(unitName)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	renderer, err := shell.NewRenderer("")
+	if err != nil {
+		return errors.Trace(err)
+	}
+	svcName, svcConf, err := ctx.service(unitName, renderer)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	limits.apply(&svcConf)
+	svcConf.User, err = deployedUnitUser(ctx.sudoersDir, unitName)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	// The service is replaced, under its current name if it was
+	// deployed with an older name format.
+	if err := ctx.initSystem.Remove(job); err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(ctx.initSystem.Install(svcName, svcConf))
+}
 
-	// Link the current tools for use by the new agent.
+// agentFileSteps returns the deployment steps which link the current
+// tools for use by the agent of the specified unit, and write the
+// agent's config.
+func agentFileSteps(agentConfig agent.Config, api APICalls, unitName, initialPassword string) []deployStep {
+	dataDir := agentConfig.DataDir()
+	return []deployStep{{
+		name: "tools",
+		run: func() error {
+			return writeAgentTools(dataDir, unitName)
+		},
+		undo: func() error {
+			return removeAgentTools(dataDir, unitName)
+		},
+	}, {
+		name: "config",
+		run: func() error {
+			return writeAgentConfig(agentConfig, api, unitName, initialPassword)
+		},
+		undo: func() error {
+			return removeAgentConfig(dataDir, unitName)
+		},
+	}}
+}
+
+// writeAgentTools links the current tools for use by the agent of
+// the specified unit.
+func writeAgentTools(dataDir, unitName string) error {
 	tag := names.NewUnitTag(unitName)
-	dataDir := ctx.agentConfig.DataDir()
-	logDir := ctx.agentConfig.LogDir()
 	hostSeries, err := series.HostSeries()
 	if err != nil {
 		return errors.Trace(err)
@@ -120,20 +272,23 @@ func (ctx *SimpleContext) DeployUnit(unitName, initialPassword string) (err erro
 		Arch:   arch.HostArch(),
 		Series: hostSeries,
 	}
-	toolsDir := tools.ToolsDir(dataDir, tag.String())
-	defer removeOnErr(&err, toolsDir)
 	_, err = tools.ChangeAgentTools(dataDir, tag.String(), current)
-	if err != nil {
-		return errors.Trace(err)
-	}
+	return errors.Trace(err)
+}
 
-	result, err := ctx.api.ConnectionInfo()
+// writeAgentConfig writes the config of the agent of the specified
+// unit.
+func writeAgentConfig(agentConfig agent.Config, api APICalls, unitName, initialPassword string) error {
+	tag := names.NewUnitTag(unitName)
+	dataDir := agentConfig.DataDir()
+	logDir := agentConfig.LogDir()
+	result, err := api.ConnectionInfo()
 	if err != nil {
 		return errors.Trace(err)
 	}
 	logger.Debugf("API addresses: %q", result.APIAddresses)
-	containerType := ctx.agentConfig.Value(agent.ContainerType)
-	namespace := ctx.agentConfig.Value(agent.Namespace)
+	containerType := agentConfig.Value(agent.ContainerType)
+	namespace := agentConfig.Value(agent.Namespace)
 	conf, err := agent.NewAgentConfig(
 		agent.AgentConfigParams{
 			Paths: agent.Paths{
@@ -145,10 +300,10 @@ func (ctx *SimpleContext) DeployUnit(unitName, initialPassword string) (err erro
 			Tag:               tag,
 			Password:          initialPassword,
 			Nonce:             "unused",
-			Controller:        ctx.agentConfig.Controller(),
-			Model:             ctx.agentConfig.Model(),
+			Controller:        agentConfig.Controller(),
+			Model:             agentConfig.Model(),
 			APIAddresses:      result.APIAddresses,
-			CACert:            ctx.agentConfig.CACert(),
+			CACert:            agentConfig.CACert(),
 			Values: map[string]string{
 				agent.ContainerType: containerType,
 				agent.Namespace:     namespace,
@@ -157,93 +312,106 @@ func (ctx *SimpleContext) DeployUnit(unitName, initialPassword string) (err erro
 	if err != nil {
 		return errors.Trace(err)
 	}
-	if err := conf.Write(); err != nil {
+	return conf.Write()
+}
+
+// removeAgentFiles removes the config and tools of the agent of the
+// specified unit, along with any record of its deployment.
+func removeAgentFiles(dataDir, unitName string) error {
+	if err := removeAgentConfig(dataDir, unitName); err != nil {
+		return err
+	}
+	if err := removeAgentTools(dataDir, unitName); err != nil {
 		return err
 	}
-	defer removeOnErr(&err, conf.Dir())
+	return newDeployment(dataDir, unitName).finish()
+}
 
-	// Install an init service that runs the unit agent.
-	if err := service.InstallAndStart(svc); err != nil {
-		return errors.Trace(err)
+// removeAgentConfig removes the directory holding the config of the
+// agent of the specified unit, if it exists.
+func removeAgentConfig(dataDir, unitName string) error {
+	agentDir := agent.Dir(dataDir, names.NewUnitTag(unitName))
+	// Recursivley change mode to 777 on windows to avoid
+	// Operation not permitted errors when deleting the agentDir
+	err := recursiveChmod(agentDir, os.FileMode(0777))
+	if err != nil {
+		return err
 	}
-	return nil
+	return os.RemoveAll(agentDir)
 }
 
-type deployerService interface {
-	Installed() (bool, error)
-	Install() error
-	Remove() error
-	Start() error
-	Stop() error
+// removeAgentTools removes the link to the tools of the agent of
+// the specified unit, if it exists.
+func removeAgentTools(dataDir, unitName string) error {
+	// TODO(dfc) should take a Tag
+	toolsDir := tools.ToolsDir(dataDir, names.NewUnitTag(unitName).String())
+	if err := os.Remove(toolsDir); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
 }
 
-// findUpstartJob tries to find an init system job matching the
+// findInitSystemJob tries to find an init system job matching the
 // given unit name in one of these formats:
-//   jujud-<deployer-tag>:<unit-tag>.conf (for compatibility)
-//   jujud-<unit-tag>.conf (default)
-func (ctx *SimpleContext) findInitSystemJob(unitName string) (deployerService, error) {
+//   jujud-<deployer-tag>:<unit-tag> (for compatibility)
+//   jujud-<unit-tag> (default)
+// The job is managed by the context's init system.
+func (ctx *SimpleContext) findInitSystemJob(unitName string) (string, error) {
 	unitsAndJobs, err := ctx.deployedUnitsInitSystemJobs()
 	if err != nil {
-		return nil, errors.Trace(err)
+		return "", errors.Trace(err)
 	}
 	if job, ok := unitsAndJobs[unitName]; ok {
-		return ctx.discoverService(job, common.Conf{})
+		return job, nil
 	}
-	return nil, errors.Errorf("unit %q is not deployed", unitName)
+	return "", errors.Errorf("unit %q is not deployed", unitName)
 }
 
 func (ctx *SimpleContext) RecallUnit(unitName string) error {
-	svc, err := ctx.findInitSystemJob(unitName)
+	job, err := ctx.findInitSystemJob(unitName)
 	if err != nil {
 		return errors.Trace(err)
 	}
-	installed, err := svc.Installed()
+	installed, err := ctx.initSystem.Installed(job)
 	if err != nil {
 		return errors.Trace(err)
 	}
 	if !installed {
 		return errors.Errorf("unit %q is not deployed", unitName)
 	}
-	if err := svc.Stop(); err != nil {
+	if err := ctx.initSystem.Remove(job); err != nil {
 		return err
 	}
-	if err := svc.Remove(); err != nil {
+	ctx.archiver.archive(unitName)
+	if err := removeAgentFiles(ctx.agentConfig.DataDir(), unitName); err != nil {
 		return err
 	}
-	tag := names.NewUnitTag(unitName)
-	dataDir := ctx.agentConfig.DataDir()
-	agentDir := agent.Dir(dataDir, tag)
-	// Recursivley change mode to 777 on windows to avoid
-	// Operation not permitted errors when deleting the agentDir
-	err = recursiveChmod(agentDir, os.FileMode(0777))
-	if err != nil {
-		return err
-	}
-	if err := os.RemoveAll(agentDir); err != nil {
-		return err
-	}
-	// TODO(dfc) should take a Tag
-	toolsDir := tools.ToolsDir(dataDir, tag.String())
-	return os.Remove(toolsDir)
+	// The agent may have been deployed to run as its own user,
+	// whether or not new agents are.
+	return removeUnitUser(ctx.users, ctx.sudoersDir, unitName)
 }
 
 var deployedRe = regexp.MustCompile("^(jujud-.*unit-([a-z0-9-]+)-([0-9]+))$")
 
+// deployedUnitsInitSystemJobs returns the names of the init system
+// jobs running unit agents, keyed on unit name. Units whose jobs are
+// installed but not running are still deployed, so that they can be
+// recalled, but are reported.
 func (ctx *SimpleContext) deployedUnitsInitSystemJobs() (map[string]string, error) {
-	fis, err := ctx.listServices()
-	if err != nil {
-		return nil, err
-	}
+	statuses, err := ctx.initSystem.List()
 	if err != nil {
-		return nil, err
+		return nil, errors.Trace(err)
 	}
 	installed := make(map[string]string)
-	for _, fi := range fis {
-		if groups := deployedRe.FindStringSubmatch(fi); len(groups) > 0 {
+	for _, status := range statuses {
+		if groups := deployedRe.FindStringSubmatch(status.Name); len(groups) > 0 {
 			unitName := groups[2] + "/" + groups[3]
 			if !names.IsValidUnit(unitName) {
 				continue
 			}
+			if !status.Running {
+				logger.Warningf("agent of unit %q is installed as %q but not running", unitName, status.Name)
+			}
 			installed[unitName] = groups[1]
 		}
 	}
@@ -262,13 +430,26 @@ func (ctx *SimpleContext) DeployedUnits() ([]string, error) {
 	return installed, nil
 }
 
-// service returns a service.Service corresponding to the specified
-// unit.
-func (ctx *SimpleContext) service(unitName string, renderer shell.Renderer) (deployerService, error) {
+// CheckUnit is part of the Context interface. Agents are checked
+// via their introspection sockets.
+func (ctx *SimpleContext) CheckUnit(unitName string) error {
+	return ctx.checkAgent(unitName)
+}
+
+// service returns the name and config of the init system service
+// which runs the agent of the specified unit.
+func (ctx *SimpleContext) service(unitName string, renderer shell.Renderer) (string, common.Conf, error) {
+	return unitService(ctx.agentConfig, unitName, ctx.LoggingConfig(), renderer)
+}
+
+// unitService returns the name and config of the init system
+// service which runs the agent of the specified unit, starting
+// it with the specified logging config.
+func unitService(agentConfig agent.Config, unitName, loggingConfig string, renderer shell.Renderer) (string, common.Conf, error) {
 	// Service name can be at most 64 characters long, we limit it to 56 just to be safe.
 	tag, err := names.NewUnitTag(unitName).ShortenedString(56)
 	if err != nil {
-		return nil, errors.Trace(err)
+		return "", common.Conf{}, errors.Trace(err)
 	}
 
 	svcName := "jujud-" + tag
@@ -276,18 +457,29 @@ func (ctx *SimpleContext) service(unitName string, renderer shell.Renderer) (dep
 	info := service.NewAgentInfo(
 		service.AgentKindUnit,
 		unitName,
-		ctx.agentConfig.DataDir(),
-		ctx.agentConfig.LogDir(),
+		agentConfig.DataDir(),
+		agentConfig.LogDir(),
 	)
+	info.LoggingConfig = loggingConfig
 
 	// TODO(thumper): 2013-09-02 bug 1219630
 	// As much as I'd like to remove JujuContainerType now, it is still
 	// needed as MAAS still needs it at this stage, and we can't fix
 	// everything at once.
-	containerType := ctx.agentConfig.Value(agent.ContainerType)
+	containerType := agentConfig.Value(agent.ContainerType)
 
 	conf := service.ContainerAgentConf(info, renderer, containerType)
-	return ctx.discoverService(svcName, conf)
+	return svcName, conf, nil
+}
+
+// agentLoggingConfig returns the logging config of the machine
+// agent with the specified config, which is passed on to the agents
+// of the units it deploys.
+func agentLoggingConfig(agentConfig agent.Config) string {
+	if loggingOverride := agentConfig.Value(agent.LoggingOverride); loggingOverride != "" {
+		return loggingOverride
+	}
+	return agentConfig.LoggingConfig()
 }
 
 func removeOnErr(err *error, path string) {
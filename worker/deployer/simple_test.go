@@ -12,6 +12,8 @@ import (
 	"runtime"
 	"sort"
 
+	"github.com/juju/errors"
+	jujutesting "github.com/juju/testing"
 	jc "github.com/juju/testing/checkers"
 	"github.com/juju/utils/arch"
 	"github.com/juju/utils/series"
@@ -21,6 +23,7 @@ import (
 
 	"github.com/juju/juju/agent"
 	"github.com/juju/juju/agent/tools"
+	"github.com/juju/juju/service/common"
 	svctesting "github.com/juju/juju/service/common/testing"
 	"github.com/juju/juju/service/upstart"
 	"github.com/juju/juju/state/multiwatcher"
@@ -61,7 +64,7 @@ func (s *SimpleContextSuite) TestDeployRecall(c *gc.C) {
 	c.Assert(units, gc.HasLen, 0)
 	s.assertUpstartCount(c, 0)
 
-	err = mgr0.DeployUnit("foo/123", "some-password")
+	err = mgr0.DeployUnit("foo/123", "some-password", deployer.ResourceLimits{})
 	c.Assert(err, jc.ErrorIsNil)
 	units, err = mgr0.DeployedUnits()
 	c.Assert(err, jc.ErrorIsNil)
@@ -78,6 +81,140 @@ func (s *SimpleContextSuite) TestDeployRecall(c *gc.C) {
 	s.checkUnitRemoved(c, "foo/123")
 }
 
+func (s *SimpleContextSuite) TestDeployFailureRollsBack(c *gc.C) {
+	ctx := s.getContext(c)
+	// The installed check passes, but installing the service fails.
+	s.data.SetErrors(nil, errors.New("boom"))
+	err := ctx.DeployUnit("foo/123", "some-password", deployer.ResourceLimits{})
+	c.Assert(err, gc.ErrorMatches, "boom")
+
+	units, err := ctx.DeployedUnits()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(units, gc.HasLen, 0)
+	s.checkUnitRemoved(c, "foo/123")
+	_, err = os.Stat(filepath.Join(s.dataDir, "deployments", "unit-foo-123"))
+	c.Assert(err, jc.Satisfies, os.IsNotExist)
+}
+
+func (s *SimpleContextSuite) TestDeployRollsBackInterruptedDeployment(c *gc.C) {
+	ctx := s.getContext(c)
+	err := ctx.DeployUnit("foo/123", "some-password", deployer.ResourceLimits{})
+	c.Assert(err, jc.ErrorIsNil)
+	progress := filepath.Join(s.dataDir, "deployments", "unit-foo-123")
+	_, err = os.Stat(progress)
+	c.Assert(err, jc.Satisfies, os.IsNotExist)
+
+	// Simulate the agent stopping before the deployment completed.
+	err = ioutil.WriteFile(progress, []byte("tools\nconfig\nservice\n"), 0600)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = ctx.DeployUnit("foo/123", "other-password", deployer.ResourceLimits{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.data.Removed(), gc.HasLen, 1)
+	s.checkUnitInstalled(c, "foo/123", "other-password")
+	_, err = os.Stat(progress)
+	c.Assert(err, jc.Satisfies, os.IsNotExist)
+}
+
+func (s *SimpleContextSuite) TestDeployUnitAgentUser(c *gc.C) {
+	ctx := s.getContext(c)
+	ctx.SetUnitAgentUsers(true)
+	err := ctx.DeployUnit("foo/123", "some-password", deployer.ResourceLimits{})
+	c.Assert(err, jc.ErrorIsNil)
+	s.checkUnitInstalled(c, "foo/123", "some-password")
+
+	svcConf := s.data.GetInstalled("jujud-unit-foo-123").Conf()
+	c.Assert(svcConf.User, gc.Equals, "juju-unit-foo-123")
+	agentDir := agent.Dir(s.dataDir, names.NewUnitTag("foo/123"))
+	logFile := filepath.Join(s.logDir, "unit-foo-123.log")
+	s.users.CheckCalls(c, []jujutesting.StubCall{
+		{"AddUser", []interface{}{"juju-unit-foo-123", agentDir}},
+		{"Chown", []interface{}{"juju-unit-foo-123", agentDir}},
+		{"Chown", []interface{}{"juju-unit-foo-123", logFile}},
+	})
+	sudoers, err := ioutil.ReadFile(filepath.Join(s.sudoersDir, "juju-unit-foo-123"))
+	c.Assert(err, jc.ErrorIsNil)
+	toolsDir := tools.ToolsDir(s.dataDir, "unit-foo-123")
+	c.Assert(string(sudoers), jc.Contains, "juju-unit-foo-123 ALL=(root) NOPASSWD: "+toolsDir+"/\n")
+
+	// The agent keeps running as its user when updated,
+	// even if new agents are run as root.
+	ctx.SetUnitAgentUsers(false)
+	err = ctx.UpdateUnit("foo/123", deployer.ResourceLimits{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.installedConf(c, "jujud-unit-foo-123").User, gc.Equals, "juju-unit-foo-123")
+
+	s.users.ResetCalls()
+	err = ctx.RecallUnit("foo/123")
+	c.Assert(err, jc.ErrorIsNil)
+	s.users.CheckCall(c, 0, "RemoveUser", "juju-unit-foo-123")
+	_, err = os.Stat(filepath.Join(s.sudoersDir, "juju-unit-foo-123"))
+	c.Assert(err, jc.Satisfies, os.IsNotExist)
+}
+
+func (s *SimpleContextSuite) TestDeployUnitAgentUserFailureRollsBack(c *gc.C) {
+	ctx := s.getContext(c)
+	ctx.SetUnitAgentUsers(true)
+	s.users.SetErrors(errors.New("boom"))
+	err := ctx.DeployUnit("foo/123", "some-password", deployer.ResourceLimits{})
+	c.Assert(err, gc.ErrorMatches, `adding user "juju-unit-foo-123": boom`)
+	s.users.CheckCallNames(c, "AddUser", "RemoveUser")
+	s.assertUpstartCount(c, 0)
+	s.checkUnitRemoved(c, "foo/123")
+}
+
+func (s *SimpleContextSuite) TestDeployResourceLimits(c *gc.C) {
+	ctx := s.getContext(c)
+	err := ctx.DeployUnit("foo/123", "some-password", deployer.ResourceLimits{
+		CPUShares:    2048,
+		MemoryMB:     512,
+		MaxOpenFiles: 1000,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.checkUnitInstalled(c, "foo/123", "some-password")
+
+	svcConf := s.data.GetInstalled("jujud-unit-foo-123").Conf()
+	c.Assert(svcConf.CPUShares, gc.Equals, 2048)
+	c.Assert(svcConf.MemoryLimitMB, gc.Equals, uint64(512))
+	c.Assert(svcConf.Limit, jc.DeepEquals, map[string]int{"nofile": 1000})
+}
+
+func (s *SimpleContextSuite) TestDeployLoggingConfig(c *gc.C) {
+	config := agentConfig(names.NewMachineTag("99"), s.dataDir, s.logDir)
+	config.(*mockConfig).loggingConfig = "<root>=WARNING"
+	ctx := deployer.NewTestSimpleContext(config, s.logDir, s.data, s.users, s.sudoersDir)
+	c.Assert(ctx.LoggingConfig(), gc.Equals, "<root>=WARNING")
+
+	err := ctx.DeployUnit("foo/123", "some-password", deployer.ResourceLimits{})
+	c.Assert(err, jc.ErrorIsNil)
+	svcConf := s.data.GetInstalled("jujud-unit-foo-123").Conf()
+	c.Assert(svcConf.ExecStart, jc.HasSuffix, " --logging-config "+quote+"<root>=WARNING"+quote)
+}
+
+func (s *SimpleContextSuite) TestUpdateUnit(c *gc.C) {
+	ctx := s.getContext(c)
+	err := ctx.DeployUnit("foo/123", "some-password", deployer.ResourceLimits{})
+	c.Assert(err, jc.ErrorIsNil)
+	svcConf := s.data.GetInstalled("jujud-unit-foo-123").Conf()
+	c.Assert(svcConf.ExecStart, jc.HasSuffix, " --debug")
+
+	ctx.SetLoggingConfig("<root>=INFO")
+	err = ctx.UpdateUnit("foo/123", deployer.ResourceLimits{CPUShares: 512})
+	c.Assert(err, jc.ErrorIsNil)
+	units, err := ctx.DeployedUnits()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(units, jc.DeepEquals, []string{"foo/123"})
+	svcConf = s.installedConf(c, "jujud-unit-foo-123")
+	c.Assert(svcConf.ExecStart, jc.HasSuffix, " --logging-config "+quote+"<root>=INFO"+quote)
+	c.Assert(svcConf.CPUShares, gc.Equals, 512)
+}
+
+func (s *SimpleContextSuite) TestUpdateUnknownUnit(c *gc.C) {
+	ctx := s.getContext(c)
+	err := ctx.UpdateUnit("foo/123", deployer.ResourceLimits{})
+	c.Assert(err, gc.ErrorMatches, `unit "foo/123" is not deployed`)
+}
+
 func (s *SimpleContextSuite) TestOldDeployedUnitsCanBeRecalled(c *gc.C) {
 	// After r1347 deployer tag is no longer part of the upstart conf filenames,
 	// now only the units' tags are used. This change is with the assumption only
@@ -113,11 +250,11 @@ func (s *SimpleContextSuite) TestOldDeployedUnitsCanBeRecalled(c *gc.C) {
 	c.Assert(units, gc.DeepEquals, []string{"mysql/0", "nrpe/0"})
 
 	// Deploy some units.
-	err = manager.DeployUnit("principal/1", "some-password")
+	err = manager.DeployUnit("principal/1", "some-password", deployer.ResourceLimits{})
 	c.Assert(err, jc.ErrorIsNil)
 	s.checkUnitInstalled(c, "principal/1", "some-password")
 	s.assertUpstartCount(c, 3)
-	err = manager.DeployUnit("subordinate/2", "fake-password")
+	err = manager.DeployUnit("subordinate/2", "fake-password", deployer.ResourceLimits{})
 	c.Assert(err, jc.ErrorIsNil)
 	s.checkUnitInstalled(c, "subordinate/2", "fake-password")
 	s.assertUpstartCount(c, 4)
@@ -151,7 +288,9 @@ type SimpleToolsFixture struct {
 	origPath string
 	binDir   string
 
-	data *svctesting.FakeServiceData
+	data       *svctesting.FakeServiceData
+	users      *fakeUserManager
+	sudoersDir string
 }
 
 var fakeJujud = "#!/bin/bash --norc\n# fake-jujud\nexit 0\n"
@@ -186,6 +325,8 @@ func (fix *SimpleToolsFixture) SetUp(c *gc.C, dataDir string) {
 	fix.makeBin(c, "stop", "cp $(which stopped-status) $(which status)")
 
 	fix.data = svctesting.NewFakeServiceData()
+	fix.users = &fakeUserManager{}
+	fix.sudoersDir = c.MkDir()
 }
 
 func (fix *SimpleToolsFixture) TearDown(c *gc.C) {
@@ -204,12 +345,12 @@ func (fix *SimpleToolsFixture) assertUpstartCount(c *gc.C, count int) {
 
 func (fix *SimpleToolsFixture) getContext(c *gc.C) *deployer.SimpleContext {
 	config := agentConfig(names.NewMachineTag("99"), fix.dataDir, fix.logDir)
-	return deployer.NewTestSimpleContext(config, fix.logDir, fix.data)
+	return deployer.NewTestSimpleContext(config, fix.logDir, fix.data, fix.users, fix.sudoersDir)
 }
 
 func (fix *SimpleToolsFixture) getContextForMachine(c *gc.C, machineTag names.Tag) *deployer.SimpleContext {
 	config := agentConfig(machineTag, fix.dataDir, fix.logDir)
-	return deployer.NewTestSimpleContext(config, fix.logDir, fix.data)
+	return deployer.NewTestSimpleContext(config, fix.logDir, fix.data, fix.users, fix.sudoersDir)
 }
 
 func (fix *SimpleToolsFixture) paths(tag names.Tag) (agentDir, toolsDir string) {
@@ -266,6 +407,19 @@ func (fix *SimpleToolsFixture) checkUnitInstalled(c *gc.C, name, password string
 	c.Assert(string(jujudData), gc.Equals, fakeJujud)
 }
 
+// installedConf returns the conf of the most recently
+// installed service with the specified name.
+func (fix *SimpleToolsFixture) installedConf(c *gc.C, svcName string) common.Conf {
+	installed := fix.data.Installed()
+	for i := len(installed) - 1; i >= 0; i-- {
+		if installed[i].Name() == svcName {
+			return installed[i].Conf()
+		}
+	}
+	c.Fatalf("service %q not installed", svcName)
+	return common.Conf{}
+}
+
 func (fix *SimpleToolsFixture) checkUnitRemoved(c *gc.C, name string) {
 	assertNotContains(c, fix.data.InstalledNames(), name)
 
@@ -296,6 +450,7 @@ type mockConfig struct {
 	logdir            string
 	upgradedToVersion version.Number
 	jobs              []multiwatcher.MachineJob
+	loggingConfig     string
 }
 
 func (mock *mockConfig) Tag() names.Tag {
@@ -339,6 +494,10 @@ func (mock *mockConfig) Value(_ string) string {
 	return ""
 }
 
+func (mock *mockConfig) LoggingConfig() string {
+	return mock.loggingConfig
+}
+
 func agentConfig(tag names.Tag, datadir, logdir string) agent.Config {
 	return &mockConfig{tag: tag, datadir: datadir, logdir: logdir}
 }
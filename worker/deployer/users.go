@@ -0,0 +1,181 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package deployer
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"os/user"
+	"path/filepath"
+	"strings"
+
+	"github.com/juju/errors"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/agent"
+	"github.com/juju/juju/agent/tools"
+)
+
+const (
+	// unitUserPrefix prefixes the names of the system users as which
+	// unit agents are run; the unit's tag follows.
+	unitUserPrefix = "juju-"
+
+	// maxUserNameLength is the maximum length of a system user name.
+	maxUserNameLength = 32
+
+	// defaultSudoersDir is the directory holding the sudo config
+	// which allows the hooks of units to run their tools as root.
+	defaultSudoersDir = "/etc/sudoers.d"
+)
+
+// UserManager manages the system users as which unit agents are run.
+type UserManager interface {
+	// AddUser creates a system user with the specified name and
+	// home directory, if it does not already exist.
+	AddUser(name, homeDir string) error
+
+	// RemoveUser removes the named system user, if it exists.
+	RemoveUser(name string) error
+
+	// Chown recursively makes the named user, and its group, the
+	// owner of the specified path.
+	Chown(name, path string) error
+}
+
+// NewUserManager returns a UserManager which manages the users of
+// the local system, using the shadow utilities. It is only supported
+// on Linux.
+func NewUserManager() UserManager {
+	return shadowUserManager{}
+}
+
+type shadowUserManager struct{}
+
+// AddUser is part of the UserManager interface.
+func (shadowUserManager) AddUser(name, homeDir string) error {
+	if _, err := user.Lookup(name); err == nil {
+		return nil
+	}
+	return runCommand("useradd",
+		"--system",
+		"--user-group",
+		"--home-dir", homeDir,
+		"--no-create-home",
+		"--shell", "/bin/false",
+		name,
+	)
+}
+
+// RemoveUser is part of the UserManager interface.
+func (shadowUserManager) RemoveUser(name string) error {
+	if _, err := user.Lookup(name); err != nil {
+		if _, ok := err.(user.UnknownUserError); ok {
+			return nil
+		}
+		return errors.Trace(err)
+	}
+	return runCommand("userdel", name)
+}
+
+// Chown is part of the UserManager interface.
+func (shadowUserManager) Chown(name, path string) error {
+	return runCommand("chown", "-R", name+":"+name, path)
+}
+
+func runCommand(name string, args ...string) error {
+	out, err := exec.Command(name, args...).CombinedOutput()
+	if err != nil {
+		return errors.Annotatef(err, "running %s: %s", name, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// unitUserName returns the name of the system user as which the
+// agent of the specified unit is run.
+func unitUserName(unitName string) (string, error) {
+	tag, err := names.NewUnitTag(unitName).ShortenedString(maxUserNameLength - len(unitUserPrefix))
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	return unitUserPrefix + tag, nil
+}
+
+// unitUserStep returns the deployment step which creates the system
+// user as which the agent of the specified unit is run, gives it the
+// agent's files, and allows it to run the agent's tools as root, so
+// that the unit's hooks can.
+func unitUserStep(users UserManager, sudoersDir string, agentConfig agent.Config, unitName string) (deployStep, error) {
+	userName, err := unitUserName(unitName)
+	if err != nil {
+		return deployStep{}, errors.Trace(err)
+	}
+	tag := names.NewUnitTag(unitName)
+	dataDir := agentConfig.DataDir()
+	agentDir := agent.Dir(dataDir, tag)
+	logFile := filepath.Join(agentConfig.LogDir(), tag.String()+".log")
+	return deployStep{
+		name: "user",
+		run: func() error {
+			if err := users.AddUser(userName, agentDir); err != nil {
+				return errors.Annotatef(err, "adding user %q", userName)
+			}
+			// The agent can't create its log file in the
+			// log directory, which is owned by root.
+			f, err := os.OpenFile(logFile, os.O_WRONLY|os.O_CREATE, 0600)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			f.Close()
+			for _, path := range []string{agentDir, logFile} {
+				if err := users.Chown(userName, path); err != nil {
+					return errors.Annotatef(err, "changing owner of %q", path)
+				}
+			}
+			toolsDir := tools.ToolsDir(dataDir, tag.String())
+			sudoers := fmt.Sprintf(
+				"# Allows the hooks of unit %s to run its tools as root.\n%s ALL=(root) NOPASSWD: %s/\n",
+				unitName, userName, toolsDir,
+			)
+			path := filepath.Join(sudoersDir, userName)
+			return errors.Trace(ioutil.WriteFile(path, []byte(sudoers), 0440))
+		},
+		undo: func() error {
+			return removeUnitUser(users, sudoersDir, unitName)
+		},
+	}, nil
+}
+
+// deployedUnitUser returns the name of the system user as which the
+// deployed agent of the specified unit runs, or "" if it runs as
+// root. Only agents run as their own users have sudo config.
+func deployedUnitUser(sudoersDir, unitName string) (string, error) {
+	userName, err := unitUserName(unitName)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	_, err = os.Stat(filepath.Join(sudoersDir, userName))
+	if os.IsNotExist(err) {
+		return "", nil
+	} else if err != nil {
+		return "", errors.Trace(err)
+	}
+	return userName, nil
+}
+
+// removeUnitUser removes the system user as which the agent of the
+// specified unit is run, and its sudo config, if they exist.
+func removeUnitUser(users UserManager, sudoersDir, unitName string) error {
+	userName, err := unitUserName(unitName)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	path := filepath.Join(sudoersDir, userName)
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return errors.Trace(err)
+	}
+	return errors.Annotatef(users.RemoveUser(userName), "removing user %q", userName)
+}
@@ -0,0 +1,50 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package deployer_test
+
+import (
+	jujutesting "github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/deployer"
+)
+
+type UnitUserSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&UnitUserSuite{})
+
+func (s *UnitUserSuite) TestUnitUserName(c *gc.C) {
+	name, err := deployer.UnitUserName("wordpress/0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(name, gc.Equals, "juju-unit-wordpress-0")
+
+	// Long names are shortened to fit the limit on user names.
+	name, err = deployer.UnitUserName("a-very-long-application-name/12345")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(len(name) <= 32, jc.IsTrue)
+	c.Assert(name, jc.HasPrefix, "juju-unit-a-very-")
+}
+
+type fakeUserManager struct {
+	jujutesting.Stub
+}
+
+func (f *fakeUserManager) AddUser(name, homeDir string) error {
+	f.MethodCall(f, "AddUser", name, homeDir)
+	return f.NextErr()
+}
+
+func (f *fakeUserManager) RemoveUser(name string) error {
+	f.MethodCall(f, "RemoveUser", name)
+	return f.NextErr()
+}
+
+func (f *fakeUserManager) Chown(name, path string) error {
+	f.MethodCall(f, "Chown", name, path)
+	return f.NextErr()
+}
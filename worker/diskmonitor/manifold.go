@@ -0,0 +1,113 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package diskmonitor
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils/clock"
+	"gopkg.in/juju/names.v2"
+	worker "gopkg.in/juju/worker.v1"
+
+	"github.com/juju/juju/agent"
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/cmd/jujud/agent/engine"
+	"github.com/juju/juju/worker/dependency"
+)
+
+// ManifoldConfig holds the dependencies and configuration for a
+// diskmonitor manifold. The data and log directories of the agent
+// are monitored.
+type ManifoldConfig struct {
+	AgentName     string
+	APICallerName string
+	Clock         clock.Clock
+	Interval      time.Duration
+	LowSpace      uint64
+	CriticalSpace uint64
+	FreeSpace     FreeSpaceFunc
+
+	NewFacade func(base.APICaller, names.MachineTag) (Facade, error)
+	NewWorker func(Config) (worker.Worker, error)
+}
+
+// validate is called by start to check for bad configuration.
+func (config ManifoldConfig) validate() error {
+	if config.AgentName == "" {
+		return errors.NotValidf("empty AgentName")
+	}
+	if config.APICallerName == "" {
+		return errors.NotValidf("empty APICallerName")
+	}
+	if config.NewFacade == nil {
+		return errors.NotValidf("nil NewFacade")
+	}
+	if config.NewWorker == nil {
+		return errors.NotValidf("nil NewWorker")
+	}
+	return nil
+}
+
+// start is a StartFunc for a Worker manifold.
+func (config ManifoldConfig) start(context dependency.Context) (worker.Worker, error) {
+	if err := config.validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	var agent agent.Agent
+	if err := context.Get(config.AgentName, &agent); err != nil {
+		return nil, errors.Trace(err)
+	}
+	var apiCaller base.APICaller
+	if err := context.Get(config.APICallerName, &apiCaller); err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	agentConfig := agent.CurrentConfig()
+	tag, ok := agentConfig.Tag().(names.MachineTag)
+	if !ok {
+		return nil, errors.New("diskmonitor may only be used with a machine agent")
+	}
+
+	facade, err := config.NewFacade(apiCaller, tag)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	worker, err := config.NewWorker(Config{
+		Facade:        facade,
+		Clock:         config.Clock,
+		Paths:         []string{agentConfig.DataDir(), agentConfig.LogDir()},
+		Interval:      config.Interval,
+		LowSpace:      config.LowSpace,
+		CriticalSpace: config.CriticalSpace,
+		FreeSpace:     config.FreeSpace,
+	})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return worker, nil
+}
+
+// Manifold returns a dependency manifold that runs the diskmonitor
+// worker, exposing whether the disk has space as an engine.Flag.
+func Manifold(config ManifoldConfig) dependency.Manifold {
+	return dependency.Manifold{
+		Inputs: []string{
+			config.AgentName,
+			config.APICallerName,
+		},
+		Start:  config.start,
+		Output: engine.FlagOutput,
+		Filter: bounceErrChanged,
+	}
+}
+
+// bounceErrChanged converts ErrChanged to dependency.ErrBounce.
+func bounceErrChanged(err error) error {
+	if errors.Cause(err) == ErrChanged {
+		return dependency.ErrBounce
+	}
+	return err
+}
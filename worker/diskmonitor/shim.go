@@ -0,0 +1,29 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package diskmonitor
+
+import (
+	"github.com/juju/errors"
+	"gopkg.in/juju/names.v2"
+	worker "gopkg.in/juju/worker.v1"
+
+	"github.com/juju/juju/api/base"
+	apimachiner "github.com/juju/juju/api/machiner"
+)
+
+func NewFacade(apiCaller base.APICaller, tag names.MachineTag) (Facade, error) {
+	machine, err := apimachiner.NewState(apiCaller).Machine(tag)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return machine, nil
+}
+
+func NewWorker(config Config) (worker.Worker, error) {
+	worker, err := New(config)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return worker, nil
+}
@@ -0,0 +1,209 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package diskmonitor
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"github.com/juju/utils/clock"
+	"github.com/juju/utils/du"
+
+	"github.com/juju/juju/status"
+	"github.com/juju/juju/worker/catacomb"
+)
+
+var logger = loggo.GetLogger("juju.worker.diskmonitor")
+
+const (
+	// DefaultLowSpace is the free space, in bytes, below which the
+	// machine is reported as short of disk space.
+	DefaultLowSpace = 1024 * humanize.MiByte
+
+	// DefaultCriticalSpace is the free space, in bytes, below which
+	// the disk is considered nearly full, and the workers which need
+	// it are stopped.
+	DefaultCriticalSpace = 250 * humanize.MiByte
+
+	// DefaultInterval is how often free space is checked.
+	DefaultInterval = time.Minute
+)
+
+// ErrChanged indicates that a Worker has stopped because its
+// Check result is no longer valid.
+var ErrChanged = errors.New("disk space flag value changed")
+
+// Facade exposes controller functionality to a Worker.
+type Facade interface {
+	SetStatus(status status.Status, info string, data map[string]interface{}) error
+}
+
+// FreeSpaceFunc returns the free space, in bytes, available to
+// unprivileged users on the filesystem holding the specified path.
+type FreeSpaceFunc func(path string) uint64
+
+// FreeSpace is a FreeSpaceFunc that queries the filesystem.
+func FreeSpace(path string) uint64 {
+	return du.NewDiskUsage(path).Free()
+}
+
+// Config defines the parameters of the diskmonitor worker.
+type Config struct {
+	Facade        Facade
+	Clock         clock.Clock
+	Paths         []string
+	Interval      time.Duration
+	LowSpace      uint64
+	CriticalSpace uint64
+	FreeSpace     FreeSpaceFunc
+}
+
+// Validate returns an error if Config cannot drive a diskmonitor.
+func (config Config) Validate() error {
+	if config.Facade == nil {
+		return errors.NotValidf("nil Facade")
+	}
+	if config.Clock == nil {
+		return errors.NotValidf("nil Clock")
+	}
+	if len(config.Paths) == 0 {
+		return errors.NotValidf("empty Paths")
+	}
+	if config.Interval <= 0 {
+		return errors.NotValidf("non-positive Interval")
+	}
+	if config.CriticalSpace > config.LowSpace {
+		return errors.NotValidf("CriticalSpace greater than LowSpace")
+	}
+	if config.FreeSpace == nil {
+		return errors.NotValidf("nil FreeSpace")
+	}
+	return nil
+}
+
+// level describes how much free space there is.
+type level int
+
+const (
+	levelOK level = iota
+	levelLow
+	levelCritical
+)
+
+// New returns a Worker that periodically checks the free space on the
+// filesystems holding the configured paths. When space runs low the
+// machine's status says so, and when the disk is nearly full the
+// machine is put into an error state and the worker's Check result
+// changes, so that the workers which need disk space are stopped
+// before they fail part-way through writing their data.
+func New(config Config) (*Worker, error) {
+	if err := config.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	w := &Worker{config: config}
+	level, message := w.check()
+	if level != levelOK {
+		if err := w.setStatus(level, message); err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	w.level = level
+	err := catacomb.Invoke(catacomb.Plan{
+		Site: &w.catacomb,
+		Work: w.loop,
+	})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return w, nil
+}
+
+// Worker implements worker.Worker and engine.Flag, and exits with
+// ErrChanged whenever the disk becomes, or stops being, nearly full.
+type Worker struct {
+	catacomb catacomb.Catacomb
+	config   Config
+	level    level
+}
+
+// Kill is part of the worker.Worker interface.
+func (w *Worker) Kill() {
+	w.catacomb.Kill(nil)
+}
+
+// Wait is part of the worker.Worker interface.
+func (w *Worker) Wait() error {
+	return w.catacomb.Wait()
+}
+
+// Check is part of the engine.Flag interface. It returns true
+// unless the disk is nearly full.
+func (w *Worker) Check() bool {
+	return w.level != levelCritical
+}
+
+func (w *Worker) loop() error {
+	for {
+		select {
+		case <-w.catacomb.Dying():
+			return w.catacomb.ErrDying()
+		case <-w.config.Clock.After(w.config.Interval):
+			level, message := w.check()
+			if level == w.level {
+				continue
+			}
+			if err := w.setStatus(level, message); err != nil {
+				return errors.Trace(err)
+			}
+			if (level == levelCritical) != (w.level == levelCritical) {
+				return ErrChanged
+			}
+			w.level = level
+		}
+	}
+}
+
+// check returns the level of the filesystem with the least free
+// space, and a message describing it.
+func (w *Worker) check() (level, string) {
+	var (
+		minFree uint64
+		minPath string
+	)
+	for i, path := range w.config.Paths {
+		free := w.config.FreeSpace(path)
+		if i == 0 || free < minFree {
+			minFree, minPath = free, path
+		}
+	}
+	message := fmt.Sprintf("%s free in %s", humanize.IBytes(minFree), minPath)
+	switch {
+	case minFree < w.config.CriticalSpace:
+		return levelCritical, "disk nearly full: " + message
+	case minFree < w.config.LowSpace:
+		return levelLow, "low disk space: " + message
+	}
+	return levelOK, ""
+}
+
+// setStatus reports the level of free space as the machine's status.
+// There is no machine status for warnings, so low space is reported
+// in the message of the started status.
+func (w *Worker) setStatus(level level, message string) error {
+	machineStatus := status.Started
+	switch level {
+	case levelCritical:
+		logger.Errorf("%s", message)
+		machineStatus = status.Error
+	case levelLow:
+		logger.Warningf("%s", message)
+	default:
+		logger.Infof("disk space recovered")
+	}
+	err := w.config.Facade.SetStatus(machineStatus, message, nil)
+	return errors.Annotate(err, "setting machine status")
+}
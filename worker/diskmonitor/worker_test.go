@@ -0,0 +1,183 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package diskmonitor_test
+
+import (
+	"sync"
+	"time"
+
+	"github.com/juju/errors"
+	jujutesting "github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/status"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/diskmonitor"
+	"github.com/juju/juju/worker/workertest"
+)
+
+const (
+	mib = 1024 * 1024
+	gib = 1024 * mib
+)
+
+type Suite struct {
+	jujutesting.IsolationSuite
+
+	mu     sync.Mutex
+	free   map[string]uint64
+	clock  *jujutesting.Clock
+	stub   *jujutesting.Stub
+	config diskmonitor.Config
+}
+
+var _ = gc.Suite(&Suite{})
+
+func (s *Suite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.free = map[string]uint64{
+		"/var/lib/juju": 10 * gib,
+		"/var/log/juju": 10 * gib,
+	}
+	s.clock = jujutesting.NewClock(time.Time{})
+	s.stub = new(jujutesting.Stub)
+	s.config = diskmonitor.Config{
+		Facade:        &stubFacade{s.stub},
+		Clock:         s.clock,
+		Paths:         []string{"/var/lib/juju", "/var/log/juju"},
+		Interval:      time.Minute,
+		LowSpace:      gib,
+		CriticalSpace: 100 * mib,
+		FreeSpace:     s.freeSpace,
+	}
+}
+
+func (s *Suite) freeSpace(path string) uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.free[path]
+}
+
+func (s *Suite) setFreeSpace(path string, free uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.free[path] = free
+}
+
+func (s *Suite) advance(c *gc.C) {
+	err := s.clock.WaitAdvance(time.Minute, coretesting.LongWait, 1)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *Suite) TestInvalidConfig(c *gc.C) {
+	s.config.CriticalSpace = 2 * gib
+	_, err := diskmonitor.New(s.config)
+	c.Check(err, gc.ErrorMatches, "CriticalSpace greater than LowSpace not valid")
+	c.Check(s.stub.Calls(), gc.HasLen, 0)
+}
+
+func (s *Suite) TestPlentyOfSpace(c *gc.C) {
+	w, err := diskmonitor.New(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.CleanKill(c, w)
+	c.Check(w.Check(), jc.IsTrue)
+
+	s.advance(c)
+	s.advance(c)
+	workertest.CheckAlive(c, w)
+	c.Check(s.stub.Calls(), gc.HasLen, 0)
+}
+
+func (s *Suite) TestLowSpace(c *gc.C) {
+	w, err := diskmonitor.New(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.CleanKill(c, w)
+
+	s.setFreeSpace("/var/log/juju", 500*mib)
+	s.advance(c)
+	s.setFreeSpace("/var/log/juju", 2*gib)
+	s.advance(c)
+	s.advance(c)
+	workertest.CheckAlive(c, w)
+	c.Check(w.Check(), jc.IsTrue)
+	s.stub.CheckCalls(c, []jujutesting.StubCall{{
+		"SetStatus", []interface{}{
+			status.Started, "low disk space: 500 MiB free in /var/log/juju", map[string]interface{}(nil),
+		},
+	}, {
+		"SetStatus", []interface{}{
+			status.Started, "", map[string]interface{}(nil),
+		},
+	}})
+}
+
+func (s *Suite) TestStartsNearlyFull(c *gc.C) {
+	s.setFreeSpace("/var/lib/juju", 50*mib)
+	w, err := diskmonitor.New(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.CleanKill(c, w)
+	c.Check(w.Check(), jc.IsFalse)
+	s.stub.CheckCalls(c, []jujutesting.StubCall{{
+		"SetStatus", []interface{}{
+			status.Error, "disk nearly full: 50 MiB free in /var/lib/juju", map[string]interface{}(nil),
+		},
+	}})
+}
+
+func (s *Suite) TestBecomesNearlyFull(c *gc.C) {
+	w, err := diskmonitor.New(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.DirtyKill(c, w)
+	c.Check(w.Check(), jc.IsTrue)
+
+	s.setFreeSpace("/var/lib/juju", 50*mib)
+	s.advance(c)
+	err = workertest.CheckKilled(c, w)
+	c.Check(err, gc.Equals, diskmonitor.ErrChanged)
+	s.stub.CheckCallNames(c, "SetStatus")
+}
+
+func (s *Suite) TestStopsBeingNearlyFull(c *gc.C) {
+	s.setFreeSpace("/var/lib/juju", 50*mib)
+	w, err := diskmonitor.New(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.DirtyKill(c, w)
+
+	// The flag changes once space is freed, even if it is still low.
+	s.setFreeSpace("/var/lib/juju", 500*mib)
+	s.advance(c)
+	err = workertest.CheckKilled(c, w)
+	c.Check(err, gc.Equals, diskmonitor.ErrChanged)
+	s.stub.CheckCalls(c, []jujutesting.StubCall{{
+		"SetStatus", []interface{}{
+			status.Error, "disk nearly full: 50 MiB free in /var/lib/juju", map[string]interface{}(nil),
+		},
+	}, {
+		"SetStatus", []interface{}{
+			status.Started, "low disk space: 500 MiB free in /var/lib/juju", map[string]interface{}(nil),
+		},
+	}})
+}
+
+func (s *Suite) TestSetStatusError(c *gc.C) {
+	w, err := diskmonitor.New(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.DirtyKill(c, w)
+
+	s.stub.SetErrors(errors.New("blam"))
+	s.setFreeSpace("/var/lib/juju", 500*mib)
+	s.advance(c)
+	err = workertest.CheckKilled(c, w)
+	c.Check(err, gc.ErrorMatches, "setting machine status: blam")
+}
+
+type stubFacade struct {
+	stub *jujutesting.Stub
+}
+
+func (f *stubFacade) SetStatus(status status.Status, info string, data map[string]interface{}) error {
+	f.stub.AddCall("SetStatus", status, info, data)
+	return f.stub.NextErr()
+}
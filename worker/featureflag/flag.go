@@ -0,0 +1,131 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package featureflag
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+
+	"github.com/juju/juju/controller"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/worker/catacomb"
+)
+
+var logger = loggo.GetLogger("juju.worker.featureflag")
+
+// ErrRefresh indicates that a Worker has stopped because its
+// Check result is no longer valid.
+var ErrRefresh = errors.New("feature flag value changed")
+
+// ConfigSource exposes the controller config, in which the enabled
+// feature flags are recorded.
+type ConfigSource interface {
+	WatchControllerConfig() state.NotifyWatcher
+	ControllerConfig() (controller.Config, error)
+}
+
+// Config holds the dependencies and configuration for a Worker.
+type Config struct {
+	Source   ConfigSource
+	FlagName string
+
+	// Invert makes the worker's Check result true when the feature
+	// flag is not enabled, for manifolds which are to run only until
+	// a feature is enabled.
+	Invert bool
+}
+
+// Validate returns an error if the config cannot be expected to
+// drive a functional Worker.
+func (config Config) Validate() error {
+	if config.Source == nil {
+		return errors.NotValidf("nil Source")
+	}
+	if config.FlagName == "" {
+		return errors.NotValidf("empty FlagName")
+	}
+	return nil
+}
+
+// New returns a Worker that tracks whether the configured
+// feature flag is enabled in the controller config.
+func New(config Config) (*Worker, error) {
+	if err := config.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	value, err := config.value()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	w := &Worker{
+		config: config,
+		value:  value,
+	}
+	err = catacomb.Invoke(catacomb.Plan{
+		Site: &w.catacomb,
+		Work: w.loop,
+	})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return w, nil
+}
+
+// value returns whether the feature flag is enabled, or not if
+// the config is inverted.
+func (config Config) value() (bool, error) {
+	controllerConfig, err := config.Source.ControllerConfig()
+	if err != nil {
+		return false, errors.Annotate(err, "getting controller config")
+	}
+	return controllerConfig.Features().Contains(config.FlagName) != config.Invert, nil
+}
+
+// Worker implements worker.Worker and engine.Flag, and exits with
+// ErrRefresh whenever the feature flag is enabled or disabled.
+type Worker struct {
+	catacomb catacomb.Catacomb
+	config   Config
+	value    bool
+}
+
+// Kill is part of the worker.Worker interface.
+func (w *Worker) Kill() {
+	w.catacomb.Kill(nil)
+}
+
+// Wait is part of the worker.Worker interface.
+func (w *Worker) Wait() error {
+	return w.catacomb.Wait()
+}
+
+// Check is part of the engine.Flag interface.
+func (w *Worker) Check() bool {
+	return w.value
+}
+
+func (w *Worker) loop() error {
+	watcher := w.config.Source.WatchControllerConfig()
+	if err := w.catacomb.Add(watcher); err != nil {
+		return errors.Trace(err)
+	}
+	for {
+		select {
+		case <-w.catacomb.Dying():
+			return w.catacomb.ErrDying()
+		case _, ok := <-watcher.Changes():
+			if !ok {
+				return errors.New("controller config watcher closed")
+			}
+			value, err := w.config.value()
+			if err != nil {
+				return errors.Trace(err)
+			}
+			if value != w.value {
+				logger.Infof("feature flag %q changed", w.config.FlagName)
+				return ErrRefresh
+			}
+		}
+	}
+}
@@ -0,0 +1,136 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package featureflag_test
+
+import (
+	"sync"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/controller"
+	"github.com/juju/juju/state"
+	statetesting "github.com/juju/juju/state/testing"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/featureflag"
+	"github.com/juju/juju/worker/workertest"
+)
+
+type WorkerSuite struct {
+	testing.IsolationSuite
+	source *configSource
+	config featureflag.Config
+}
+
+var _ = gc.Suite(&WorkerSuite{})
+
+func (s *WorkerSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.source = &configSource{
+		cfg:     controller.Config{},
+		changes: make(chan struct{}),
+	}
+	s.config = featureflag.Config{
+		Source:   s.source,
+		FlagName: "new-hotness",
+	}
+}
+
+func (s *WorkerSuite) setFeatures(features ...interface{}) {
+	s.source.setConfig(controller.Config{
+		"features": features,
+	})
+}
+
+func (s *WorkerSuite) change(c *gc.C) {
+	select {
+	case s.source.changes <- struct{}{}:
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out sending config change")
+	}
+}
+
+func (s *WorkerSuite) TestValidateFlagName(c *gc.C) {
+	s.config.FlagName = ""
+	_, err := featureflag.New(s.config)
+	c.Assert(err, gc.ErrorMatches, "empty FlagName not valid")
+}
+
+func (s *WorkerSuite) TestConfigError(c *gc.C) {
+	s.source.SetErrors(errors.New("boom"))
+	_, err := featureflag.New(s.config)
+	c.Assert(err, gc.ErrorMatches, "getting controller config: boom")
+}
+
+func (s *WorkerSuite) TestCheck(c *gc.C) {
+	w, err := featureflag.New(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.CleanKill(c, w)
+	c.Check(w.Check(), jc.IsFalse)
+
+	s.setFeatures("new-hotness")
+	w2, err := featureflag.New(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.CleanKill(c, w2)
+	c.Check(w2.Check(), jc.IsTrue)
+}
+
+func (s *WorkerSuite) TestCheckInverted(c *gc.C) {
+	s.config.Invert = true
+	w, err := featureflag.New(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.CleanKill(c, w)
+	c.Check(w.Check(), jc.IsTrue)
+}
+
+func (s *WorkerSuite) TestUnrelatedChange(c *gc.C) {
+	s.setFeatures("new-hotness")
+	w, err := featureflag.New(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.CleanKill(c, w)
+
+	s.setFeatures("new-hotness", "old-and-busted")
+	s.change(c)
+	s.change(c)
+	workertest.CheckAlive(c, w)
+}
+
+func (s *WorkerSuite) TestFlagChanged(c *gc.C) {
+	w, err := featureflag.New(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.DirtyKill(c, w)
+
+	s.setFeatures("new-hotness")
+	s.change(c)
+	err = workertest.CheckKilled(c, w)
+	c.Assert(err, gc.Equals, featureflag.ErrRefresh)
+}
+
+type configSource struct {
+	testing.Stub
+	mu      sync.Mutex
+	cfg     controller.Config
+	changes chan struct{}
+}
+
+func (s *configSource) setConfig(cfg controller.Config) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cfg = cfg
+}
+
+func (s *configSource) WatchControllerConfig() state.NotifyWatcher {
+	s.AddCall("WatchControllerConfig")
+	return statetesting.NewMockNotifyWatcher(s.changes)
+}
+
+func (s *configSource) ControllerConfig() (controller.Config, error) {
+	s.AddCall("ControllerConfig")
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.cfg, s.NextErr()
+}
@@ -0,0 +1,89 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package featureflag
+
+import (
+	"github.com/juju/errors"
+	worker "gopkg.in/juju/worker.v1"
+
+	"github.com/juju/juju/cmd/jujud/agent/engine"
+	"github.com/juju/juju/worker/dependency"
+	workerstate "github.com/juju/juju/worker/state"
+)
+
+// ManifoldConfig holds the dependencies and configuration for a
+// feature flag manifold.
+type ManifoldConfig struct {
+	StateName string
+	FlagName  string
+	Invert    bool
+
+	NewWorker func(Config) (worker.Worker, error)
+}
+
+// validate is called by start to check for bad configuration.
+func (config ManifoldConfig) validate() error {
+	if config.StateName == "" {
+		return errors.NotValidf("empty StateName")
+	}
+	if config.FlagName == "" {
+		return errors.NotValidf("empty FlagName")
+	}
+	if config.NewWorker == nil {
+		return errors.NotValidf("nil NewWorker")
+	}
+	return nil
+}
+
+// start is a StartFunc for a Worker manifold.
+func (config ManifoldConfig) start(context dependency.Context) (worker.Worker, error) {
+	if err := config.validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	var stTracker workerstate.StateTracker
+	if err := context.Get(config.StateName, &stTracker); err != nil {
+		return nil, errors.Trace(err)
+	}
+	statePool, err := stTracker.Use()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	w, err := config.NewWorker(Config{
+		Source:   statePool.SystemState(),
+		FlagName: config.FlagName,
+		Invert:   config.Invert,
+	})
+	if err != nil {
+		stTracker.Done()
+		return nil, errors.Trace(err)
+	}
+	go func() {
+		w.Wait()
+		stTracker.Done()
+	}()
+	return w, nil
+}
+
+// Manifold returns a dependency.Manifold that runs a feature flag
+// worker, exposing whether the flag is enabled in the controller config
+// as an engine.Flag. A manifold housed with the flag is started and
+// stopped as the feature is enabled and disabled, without restarting
+// the agent or any manifolds which do not depend on it; it may only be
+// used on controllers.
+func Manifold(config ManifoldConfig) dependency.Manifold {
+	return dependency.Manifold{
+		Inputs: []string{config.StateName},
+		Start:  config.start,
+		Output: engine.FlagOutput,
+		Filter: bounceErrRefresh,
+	}
+}
+
+// bounceErrRefresh converts ErrRefresh to dependency.ErrBounce.
+func bounceErrRefresh(err error) error {
+	if errors.Cause(err) == ErrRefresh {
+		return dependency.ErrBounce
+	}
+	return err
+}
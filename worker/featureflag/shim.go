@@ -0,0 +1,19 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package featureflag
+
+import (
+	"github.com/juju/errors"
+	worker "gopkg.in/juju/worker.v1"
+)
+
+// NewWorker calls New, returning the Worker as a worker.Worker,
+// for use in a ManifoldConfig.
+func NewWorker(config Config) (worker.Worker, error) {
+	w, err := New(config)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return w, nil
+}
@@ -5,6 +5,7 @@ package firewaller
 
 import (
 	"io"
+	"net"
 	"strings"
 	"time"
 
@@ -43,6 +44,7 @@ type FirewallerAPI interface {
 	MacaroonForRelation(relationKey string) (*macaroon.Macaroon, error)
 	SetRelationStatus(relationKey string, status relation.Status, message string) error
 	FirewallRules(serviceNames ...string) ([]params.FirewallRule, error)
+	WatchFirewallRules() (watcher.NotifyWatcher, error)
 }
 
 // CrossModelFirewallerFacade exposes firewaller functionality on the
@@ -132,6 +134,11 @@ type Firewaller struct {
 	globalMode           bool
 	globalIngressRuleRef map[string]int // map of rule names to count of occurrences
 
+	// firewallRulesWatcher is nil if the controller
+	// cannot report changes to the firewall rules.
+	firewallRulesWatcher watcher.NotifyWatcher
+	sshWhitelist         []string
+
 	modelUUID                  string
 	newRemoteFirewallerAPIFunc newCrossModelFacadeFunc
 	remoteRelationsWatcher     watcher.StringsWatcher
@@ -223,6 +230,18 @@ func (fw *Firewaller) setUp() error {
 		return errors.Trace(err)
 	}
 
+	if err := fw.updateSSHWhitelist(); err != nil {
+		return errors.Trace(err)
+	}
+	fw.firewallRulesWatcher, err = fw.firewallerApi.WatchFirewallRules()
+	if errors.IsNotSupported(err) {
+		logger.Debugf("not watching firewall rules: %v", err)
+	} else if err != nil {
+		return errors.Trace(err)
+	} else if err := fw.catacomb.Add(fw.firewallRulesWatcher); err != nil {
+		return errors.Trace(err)
+	}
+
 	logger.Debugf("started watching opened port ranges for the model")
 	return nil
 }
@@ -233,6 +252,10 @@ func (fw *Firewaller) loop() error {
 	}
 	var reconciled bool
 	portsChange := fw.portsWatcher.Changes()
+	var firewallRulesChange watcher.NotifyChannel
+	if fw.firewallRulesWatcher != nil {
+		firewallRulesChange = fw.firewallRulesWatcher.Changes()
+	}
 	for {
 		select {
 		case <-fw.catacomb.Dying():
@@ -280,6 +303,13 @@ func (fw *Firewaller) loop() error {
 					return err
 				}
 			}
+		case _, ok := <-firewallRulesChange:
+			if !ok {
+				return errors.New("firewall rules watcher closed")
+			}
+			if err := fw.firewallRulesChanged(); err != nil {
+				return errors.Trace(err)
+			}
 		case change := <-fw.localRelationsChange:
 			// We have a notification that the remote (consuming) model
 			// has changed egress networks so need to update the local
@@ -754,11 +784,11 @@ func (fw *Firewaller) gatherIngressRules(machines ...*machineData) ([]network.In
 			if cidrs.Size() > 0 {
 				for portRange := range portRanges {
 					sourceCidrs := cidrs.SortedValues()
-					rule, err := network.NewIngressRule(portRange.Protocol, portRange.FromPort, portRange.ToPort, sourceCidrs...)
+					rules, err := restrictSSHIngress(portRange, sourceCidrs, fw.sshWhitelist)
 					if err != nil {
 						return nil, errors.Trace(err)
 					}
-					want = append(want, rule)
+					want = append(want, rules...)
 				}
 			}
 		}
@@ -823,6 +853,112 @@ func (fw *Firewaller) updateForRemoteRelationIngress(appTag names.ApplicationTag
 	return nil
 }
 
+// updateSSHWhitelist reads the CIDRs from which SSH
+// ingress is allowed from the model's ssh firewall rule.
+func (fw *Firewaller) updateSSHWhitelist() error {
+	rules, err := fw.firewallerApi.FirewallRules(string(params.SSHRule))
+	if err != nil {
+		return errors.Trace(err)
+	}
+	fw.sshWhitelist = nil
+	if len(rules) > 0 {
+		fw.sshWhitelist = rules[0].WhitelistCIDRS
+	}
+	return nil
+}
+
+// firewallRulesChanged re-reads the ssh firewall rule and, if its
+// whitelist has changed, updates the ingress rules of all machines.
+func (fw *Firewaller) firewallRulesChanged() error {
+	oldWhitelist := set.NewStrings(fw.sshWhitelist...)
+	if err := fw.updateSSHWhitelist(); err != nil {
+		return errors.Trace(err)
+	}
+	newWhitelist := set.NewStrings(fw.sshWhitelist...)
+	if oldWhitelist.Size() == newWhitelist.Size() && oldWhitelist.Difference(newWhitelist).IsEmpty() {
+		return nil
+	}
+	logger.Infof("ssh ingress whitelist changed to %v", newWhitelist.SortedValues())
+	for _, machined := range fw.machineds {
+		if err := fw.flushMachine(machined); err != nil {
+			return errors.Annotate(err, "cannot change firewall ports")
+		}
+	}
+	return nil
+}
+
+const sshPort = 22
+
+// restrictSSHIngress returns the ingress rules which allow the port
+// range to be reached from the source CIDRs, except that access to the
+// SSH port is limited to the CIDRs in the whitelist, if there are any.
+func restrictSSHIngress(portRange network.PortRange, sourceCIDRs, whitelist []string) ([]network.IngressRule, error) {
+	newRule := func(from, to int, cidrs []string) (network.IngressRule, error) {
+		return network.NewIngressRule(portRange.Protocol, from, to, cidrs...)
+	}
+	if len(whitelist) == 0 || portRange.Protocol != "tcp" ||
+		portRange.FromPort > sshPort || portRange.ToPort < sshPort {
+		rule, err := newRule(portRange.FromPort, portRange.ToPort, sourceCIDRs)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return []network.IngressRule{rule}, nil
+	}
+
+	// Split the port range around the SSH port.
+	type portSpan struct {
+		from, to int
+		cidrs    []string
+	}
+	spans := []portSpan{{sshPort, sshPort, intersectCIDRs(sourceCIDRs, whitelist)}}
+	if portRange.FromPort < sshPort {
+		spans = append(spans, portSpan{portRange.FromPort, sshPort - 1, sourceCIDRs})
+	}
+	if portRange.ToPort > sshPort {
+		spans = append(spans, portSpan{sshPort + 1, portRange.ToPort, sourceCIDRs})
+	}
+	var rules []network.IngressRule
+	for _, span := range spans {
+		if len(span.cidrs) == 0 {
+			continue
+		}
+		rule, err := newRule(span.from, span.to, span.cidrs)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// intersectCIDRs returns the CIDRs covering the addresses which are
+// in both sets; where one CIDR contains another, the narrower one is
+// used. CIDRs which cannot be parsed are ignored.
+func intersectCIDRs(a, b []string) []string {
+	result := set.NewStrings()
+	for _, cidrA := range a {
+		_, netA, err := net.ParseCIDR(cidrA)
+		if err != nil {
+			continue
+		}
+		sizeA, _ := netA.Mask.Size()
+		for _, cidrB := range b {
+			_, netB, err := net.ParseCIDR(cidrB)
+			if err != nil {
+				continue
+			}
+			sizeB, _ := netB.Mask.Size()
+			switch {
+			case sizeA <= sizeB && netA.Contains(netB.IP):
+				result.Add(cidrB)
+			case sizeB < sizeA && netB.Contains(netA.IP):
+				result.Add(cidrA)
+			}
+		}
+	}
+	return result.SortedValues()
+}
+
 // flushGlobalPorts opens and closes global ports in the environment.
 // It keeps a reference count for ports so that only 0-to-1 and 1-to-0 events
 // modify the environment.
@@ -291,6 +291,59 @@ func (s *InstanceModeSuite) TestExposedApplication(c *gc.C) {
 	})
 }
 
+func (s *InstanceModeSuite) TestSSHWhitelist(c *gc.C) {
+	fwRules := state.NewFirewallRules(s.State)
+	err := fwRules.Save(state.FirewallRule{
+		WellKnownService: state.SSHRule,
+		WhitelistCIDRs:   []string{"10.0.0.0/8"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	fw := s.newFirewaller(c)
+	defer statetesting.AssertKillAndWait(c, fw)
+
+	app := s.AddTestingApplication(c, "wordpress", s.charm)
+	err = app.SetExposed()
+	c.Assert(err, jc.ErrorIsNil)
+	u, m := s.addUnit(c, app)
+	inst := s.startInstance(c, m)
+
+	err = u.OpenPorts("tcp", 20, 30)
+	c.Assert(err, jc.ErrorIsNil)
+	err = u.OpenPort("udp", 22)
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.assertPorts(c, inst, m.Id(), []network.IngressRule{
+		network.MustNewIngressRule("tcp", 20, 21, "0.0.0.0/0"),
+		network.MustNewIngressRule("tcp", 22, 22, "10.0.0.0/8"),
+		network.MustNewIngressRule("tcp", 23, 30, "0.0.0.0/0"),
+		network.MustNewIngressRule("udp", 22, 22, "0.0.0.0/0"),
+	})
+
+	// Changing the whitelist updates the open ports.
+	err = fwRules.Save(state.FirewallRule{
+		WellKnownService: state.SSHRule,
+		WhitelistCIDRs:   []string{"192.168.0.0/16"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertPorts(c, inst, m.Id(), []network.IngressRule{
+		network.MustNewIngressRule("tcp", 20, 21, "0.0.0.0/0"),
+		network.MustNewIngressRule("tcp", 22, 22, "192.168.0.0/16"),
+		network.MustNewIngressRule("tcp", 23, 30, "0.0.0.0/0"),
+		network.MustNewIngressRule("udp", 22, 22, "0.0.0.0/0"),
+	})
+
+	// Clearing the whitelist allows SSH from anywhere.
+	err = fwRules.Save(state.FirewallRule{
+		WellKnownService: state.SSHRule,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertPorts(c, inst, m.Id(), []network.IngressRule{
+		network.MustNewIngressRule("tcp", 20, 30, "0.0.0.0/0"),
+		network.MustNewIngressRule("udp", 22, 22, "0.0.0.0/0"),
+	})
+}
+
 func (s *InstanceModeSuite) TestMultipleExposedApplications(c *gc.C) {
 	fw := s.newFirewaller(c)
 	defer statetesting.AssertKillAndWait(c, fw)
@@ -1318,6 +1371,59 @@ func (s *GlobalModeSuite) TestGlobalMode(c *gc.C) {
 	s.assertEnvironPorts(c, nil)
 }
 
+func (s *GlobalModeSuite) TestSSHWhitelist(c *gc.C) {
+	fw := s.newFirewaller(c)
+	defer statetesting.AssertKillAndWait(c, fw)
+
+	app1 := s.AddTestingApplication(c, "wordpress", s.charm)
+	err := app1.SetExposed()
+	c.Assert(err, jc.ErrorIsNil)
+	u1, m1 := s.addUnit(c, app1)
+	s.startInstance(c, m1)
+	err = u1.OpenPort("tcp", 22)
+	c.Assert(err, jc.ErrorIsNil)
+	err = u1.OpenPort("tcp", 80)
+	c.Assert(err, jc.ErrorIsNil)
+
+	app2 := s.AddTestingApplication(c, "moinmoin", s.charm)
+	err = app2.SetExposed()
+	c.Assert(err, jc.ErrorIsNil)
+	u2, m2 := s.addUnit(c, app2)
+	s.startInstance(c, m2)
+	err = u2.OpenPort("tcp", 22)
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.assertEnvironPorts(c, []network.IngressRule{
+		network.MustNewIngressRule("tcp", 22, 22, "0.0.0.0/0"),
+		network.MustNewIngressRule("tcp", 80, 80, "0.0.0.0/0"),
+	})
+
+	// Setting a whitelist restricts SSH ingress to it.
+	fwRules := state.NewFirewallRules(s.State)
+	err = fwRules.Save(state.FirewallRule{
+		WellKnownService: state.SSHRule,
+		WhitelistCIDRs:   []string{"10.0.0.0/8", "192.168.1.0/24"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertEnvironPorts(c, []network.IngressRule{
+		network.MustNewIngressRule("tcp", 22, 22, "10.0.0.0/8", "192.168.1.0/24"),
+		network.MustNewIngressRule("tcp", 80, 80, "0.0.0.0/0"),
+	})
+
+	// The restricted rule is shared between the units.
+	err = u1.ClosePort("tcp", 22)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertEnvironPorts(c, []network.IngressRule{
+		network.MustNewIngressRule("tcp", 22, 22, "10.0.0.0/8", "192.168.1.0/24"),
+		network.MustNewIngressRule("tcp", 80, 80, "0.0.0.0/0"),
+	})
+	err = u2.ClosePort("tcp", 22)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertEnvironPorts(c, []network.IngressRule{
+		network.MustNewIngressRule("tcp", 80, 80, "0.0.0.0/0"),
+	})
+}
+
 func (s *GlobalModeSuite) TestStartWithUnexposedApplication(c *gc.C) {
 	m, err := s.State.AddMachine("quantal", state.JobHostUnits)
 	c.Assert(err, jc.ErrorIsNil)
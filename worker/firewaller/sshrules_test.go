@@ -0,0 +1,82 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package firewaller
+
+import (
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/network"
+)
+
+type SSHRulesSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&SSHRulesSuite{})
+
+func (s *SSHRulesSuite) assertRestricted(c *gc.C, portRange network.PortRange, sources, whitelist []string, expected ...network.IngressRule) {
+	rules, err := restrictSSHIngress(portRange, sources, whitelist)
+	c.Assert(err, jc.ErrorIsNil)
+	network.SortIngressRules(rules)
+	c.Assert(rules, jc.DeepEquals, expected)
+}
+
+func (s *SSHRulesSuite) TestNoWhitelist(c *gc.C) {
+	s.assertRestricted(c,
+		network.PortRange{Protocol: "tcp", FromPort: 22, ToPort: 22},
+		[]string{"0.0.0.0/0"}, nil,
+		network.MustNewIngressRule("tcp", 22, 22, "0.0.0.0/0"),
+	)
+}
+
+func (s *SSHRulesSuite) TestOtherPorts(c *gc.C) {
+	whitelist := []string{"10.0.0.0/8"}
+	s.assertRestricted(c,
+		network.PortRange{Protocol: "tcp", FromPort: 80, ToPort: 90},
+		[]string{"0.0.0.0/0"}, whitelist,
+		network.MustNewIngressRule("tcp", 80, 90, "0.0.0.0/0"),
+	)
+	s.assertRestricted(c,
+		network.PortRange{Protocol: "udp", FromPort: 22, ToPort: 22},
+		[]string{"0.0.0.0/0"}, whitelist,
+		network.MustNewIngressRule("udp", 22, 22, "0.0.0.0/0"),
+	)
+}
+
+func (s *SSHRulesSuite) TestSSHPort(c *gc.C) {
+	s.assertRestricted(c,
+		network.PortRange{Protocol: "tcp", FromPort: 22, ToPort: 22},
+		[]string{"0.0.0.0/0"}, []string{"10.0.0.0/8", "192.168.1.0/24"},
+		network.MustNewIngressRule("tcp", 22, 22, "10.0.0.0/8", "192.168.1.0/24"),
+	)
+}
+
+func (s *SSHRulesSuite) TestSplitsPortRange(c *gc.C) {
+	s.assertRestricted(c,
+		network.PortRange{Protocol: "tcp", FromPort: 20, ToPort: 30},
+		[]string{"0.0.0.0/0"}, []string{"10.0.0.0/8"},
+		network.MustNewIngressRule("tcp", 20, 21, "0.0.0.0/0"),
+		network.MustNewIngressRule("tcp", 22, 22, "10.0.0.0/8"),
+		network.MustNewIngressRule("tcp", 23, 30, "0.0.0.0/0"),
+	)
+}
+
+func (s *SSHRulesSuite) TestNarrowsSources(c *gc.C) {
+	s.assertRestricted(c,
+		network.PortRange{Protocol: "tcp", FromPort: 22, ToPort: 22},
+		[]string{"10.1.2.0/24", "172.16.0.0/12", "192.168.0.0/16"},
+		[]string{"10.0.0.0/8", "192.168.1.0/24"},
+		network.MustNewIngressRule("tcp", 22, 22, "10.1.2.0/24", "192.168.1.0/24"),
+	)
+}
+
+func (s *SSHRulesSuite) TestNoOverlap(c *gc.C) {
+	s.assertRestricted(c,
+		network.PortRange{Protocol: "tcp", FromPort: 21, ToPort: 22},
+		[]string{"172.16.0.0/12"}, []string{"10.0.0.0/8"},
+		network.MustNewIngressRule("tcp", 21, 21, "172.16.0.0/12"),
+	)
+}
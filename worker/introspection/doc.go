@@ -9,4 +9,15 @@
 //   - prints out all the goroutines in the agent
 // * `/debug/pprof/heap?debug=1`
 //   - prints out the heap profile
+// * `/debug/pprof/profile?seconds=N`
+//   - samples the CPU for N seconds, for use with `go tool pprof`
+// * `/metrics`
+//   - prints out the agent's Prometheus metrics, including the Go
+//     runtime's goroutine, memory and GC counters
+// * `/depengine`
+//   - prints out the dependency engine report; `?format=dot` renders
+//     it as a graph
+//
+// Each of these has a bash function, such as juju-heap-profile, defined
+// in /etc/profile.d/juju-introspection.sh.
 package introspection
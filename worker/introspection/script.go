@@ -84,7 +84,13 @@ juju-heap-profile () {
 }
 
 juju-engine-report () {
-  jujuMachineOrUnit depengine $@
+  # Pass "--format dot" to get the graph of manifolds, for graphviz.
+  local path=depengine
+  if [ "$1" = "--format" ]; then
+    path="depengine?format=$2"
+    shift 2
+  fi
+  jujuMachineOrUnit $path $@
 }
 
 juju-statepool-report () {
@@ -17,6 +17,7 @@ import (
 	"gopkg.in/tomb.v1"
 	"gopkg.in/yaml.v2"
 
+	"github.com/juju/juju/worker/dependency"
 	"github.com/juju/juju/worker/introspection/pprof"
 )
 
@@ -182,6 +183,18 @@ func (h depengineHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		fmt.Fprintln(w, "missing dependency engine reporter")
 		return
 	}
+	switch format := r.URL.Query().Get("format"); format {
+	case "", "yaml":
+	case "dot":
+		// Render the graph of manifolds, for viewing with graphviz.
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		fmt.Fprint(w, dependency.DotGraph(h.reporter.Report()))
+		return
+	default:
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprintf(w, "unknown format %q\n", format)
+		return
+	}
 	bytes, err := yaml.Marshal(h.reporter.Report())
 	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
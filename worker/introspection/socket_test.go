@@ -152,6 +152,36 @@ func (s *introspectionSuite) TestEngineReporter(c *gc.C) {
 	matches(c, buf, "working: true")
 }
 
+func (s *introspectionSuite) TestEngineReporterDot(c *gc.C) {
+	workertest.CheckKill(c, s.worker)
+	s.reporter = &reporter{
+		values: map[string]interface{}{
+			"manifolds": map[string]interface{}{
+				"machiner": map[string]interface{}{
+					"state":  "started",
+					"inputs": []string{"agent"},
+				},
+			},
+		},
+	}
+	s.startWorker(c)
+	buf := s.call(c, "/depengine?format=dot")
+
+	matches(c, buf, "200 OK")
+	matches(c, buf, "digraph dependencies {")
+	matches(c, buf, `"machiner" -> "agent";`)
+}
+
+func (s *introspectionSuite) TestEngineReporterUnknownFormat(c *gc.C) {
+	workertest.CheckKill(c, s.worker)
+	s.reporter = &reporter{}
+	s.startWorker(c)
+	buf := s.call(c, "/depengine?format=xml")
+
+	matches(c, buf, "400 Bad Request")
+	matches(c, buf, `unknown format "xml"`)
+}
+
 func (s *introspectionSuite) TestPrometheusMetrics(c *gc.C) {
 	buf := s.call(c, "/metrics")
 	c.Assert(buf, gc.NotNil)
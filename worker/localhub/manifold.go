@@ -0,0 +1,77 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package localhub
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/pubsub"
+	worker "gopkg.in/juju/worker.v1"
+	"gopkg.in/tomb.v1"
+
+	"github.com/juju/juju/worker/dependency"
+)
+
+// ManifoldConfig provides the dependencies for Manifold.
+type ManifoldConfig struct {
+	// Hub is the agent's local hub. It is created by the agent, and
+	// outlives the dependency engine, so that the workers which
+	// cannot depend on this manifold (because this manifold's
+	// dependents depend on them) can still be configured to publish
+	// on it.
+	Hub *pubsub.SimpleHub
+}
+
+// Manifold returns a manifold whose worker simply provides the agent's
+// local hub. Workers that need to know about events elsewhere in the
+// agent should subscribe to the topics in the pubsub/agent package on
+// this hub, rather than adding ad-hoc inputs to other manifolds.
+func Manifold(config ManifoldConfig) dependency.Manifold {
+	return dependency.Manifold{
+		Start: func(context dependency.Context) (worker.Worker, error) {
+			if config.Hub == nil {
+				return nil, errors.NotValidf("missing hub")
+			}
+			w := &localHub{
+				hub: config.Hub,
+			}
+			go func() {
+				defer w.tomb.Done()
+				<-w.tomb.Dying()
+			}()
+			return w, nil
+		},
+		Output: outputFunc,
+	}
+}
+
+// outputFunc extracts a pubsub.SimpleHub from a *localHub.
+func outputFunc(in worker.Worker, out interface{}) error {
+	inWorker, _ := in.(*localHub)
+	if inWorker == nil {
+		return errors.Errorf("in should be a %T; got %T", inWorker, in)
+	}
+
+	switch outPointer := out.(type) {
+	case **pubsub.SimpleHub:
+		*outPointer = inWorker.hub
+	default:
+		return errors.Errorf("out should be *pubsub.SimpleHub; got %T", out)
+	}
+	return nil
+}
+
+type localHub struct {
+	tomb tomb.Tomb
+	hub  *pubsub.SimpleHub
+}
+
+// Kill is part of the worker.Worker interface.
+func (w *localHub) Kill() {
+	w.tomb.Kill(nil)
+}
+
+// Wait is part of the worker.Worker interface.
+func (w *localHub) Wait() error {
+	return w.tomb.Wait()
+}
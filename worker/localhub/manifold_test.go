@@ -0,0 +1,78 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package localhub_test
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/pubsub"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/worker/dependency"
+	dt "github.com/juju/juju/worker/dependency/testing"
+	"github.com/juju/juju/worker/localhub"
+	"github.com/juju/juju/worker/workertest"
+)
+
+type ManifoldSuite struct {
+	testing.IsolationSuite
+	hub    *pubsub.SimpleHub
+	config localhub.ManifoldConfig
+}
+
+var _ = gc.Suite(&ManifoldSuite{})
+
+func (s *ManifoldSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.hub = pubsub.NewSimpleHub(nil)
+	s.config = localhub.ManifoldConfig{
+		Hub: s.hub,
+	}
+}
+
+func (s *ManifoldSuite) manifold() dependency.Manifold {
+	return localhub.Manifold(s.config)
+}
+
+func (s *ManifoldSuite) TestInputs(c *gc.C) {
+	c.Check(s.manifold().Inputs, gc.HasLen, 0)
+}
+
+func (s *ManifoldSuite) TestMissingHub(c *gc.C) {
+	s.config.Hub = nil
+	context := dt.StubContext(nil, nil)
+
+	worker, err := s.manifold().Start(context)
+	c.Check(worker, gc.IsNil)
+	c.Check(errors.Cause(err), jc.Satisfies, errors.IsNotValid)
+}
+
+func (s *ManifoldSuite) TestHubOutput(c *gc.C) {
+	context := dt.StubContext(nil, nil)
+
+	manifold := s.manifold()
+	worker, err := manifold.Start(context)
+	c.Check(err, jc.ErrorIsNil)
+	c.Assert(worker, gc.NotNil)
+	defer workertest.CleanKill(c, worker)
+
+	var hub *pubsub.SimpleHub
+	err = manifold.Output(worker, &hub)
+	c.Check(err, jc.ErrorIsNil)
+	c.Check(hub, gc.Equals, s.hub)
+}
+
+func (s *ManifoldSuite) TestBadOutput(c *gc.C) {
+	context := dt.StubContext(nil, nil)
+
+	manifold := s.manifold()
+	worker, err := manifold.Start(context)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.CleanKill(c, worker)
+
+	var hub *pubsub.StructuredHub
+	err = manifold.Output(worker, &hub)
+	c.Check(err, gc.ErrorMatches, `out should be \*pubsub.SimpleHub; got \*\*pubsub.StructuredHub`)
+}
@@ -58,6 +58,10 @@ const (
 	// reports from minions and while it's transferring log messages
 	// to the newly-migrated model.
 	progressUpdateInterval = 30 * time.Second
+
+	// pausePollInterval is the time between checks of the migration
+	// status while the migration has been paused by the user.
+	pausePollInterval = 10 * time.Second
 )
 
 // Facade exposes controller functionality to a Worker.
@@ -248,8 +252,14 @@ func (w *Worker) run() error {
 		}
 
 		w.logger.Infof("setting migration phase to %s", phase)
-		if err := w.config.Facade.SetPhase(phase); err != nil {
-			return errors.Annotate(err, "failed to set phase")
+		aborted, err := w.setPhase(phase)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if aborted {
+			// The user aborted the migration while the phase was in
+			// progress; roll it back instead.
+			phase = coremigration.ABORT
 		}
 		status.Phase = phase
 
@@ -263,6 +273,37 @@ func (w *Worker) run() error {
 	}
 }
 
+// setPhase moves the migration to the given phase. If the migration
+// has been paused by the user, setPhase waits until it is resumed or
+// aborted. It returns true if the user aborted the migration instead.
+func (w *Worker) setPhase(phase coremigration.Phase) (bool, error) {
+	for {
+		err := w.config.Facade.SetPhase(phase)
+		if err == nil {
+			return false, nil
+		}
+		status, statusErr := w.config.Facade.MigrationStatus()
+		if statusErr != nil {
+			w.logger.Errorf("retrieving migration status: %v", statusErr)
+			return false, errors.Annotate(err, "failed to set phase")
+		}
+		switch {
+		case status.Phase == coremigration.ABORT && phase != coremigration.ABORTDONE:
+			w.setErrorStatus("aborted by user")
+			return true, nil
+		case !status.Paused:
+			return false, errors.Annotate(err, "failed to set phase")
+		}
+
+		w.setInfoStatus("paused before %s, waiting to be resumed or aborted", phase)
+		select {
+		case <-w.catacomb.Dying():
+			return false, w.catacomb.ErrDying()
+		case <-w.config.Clock.After(pausePollInterval):
+		}
+	}
+}
+
 func (w *Worker) killed() bool {
 	select {
 	case <-w.catacomb.Dying():
@@ -524,6 +524,75 @@ func (s *Suite) TestImportFailure(c *gc.C) {
 	))
 }
 
+func (s *Suite) TestPausedMigrationWaitsForResume(c *gc.C) {
+	s.facade.queueStatus(s.makeStatus(coremigration.IMPORT))
+	s.facade.setPhaseErrs = []error{errors.New("migration is paused in phase IMPORT")}
+	paused := s.makeStatus(coremigration.IMPORT)
+	paused.Paused = true
+	s.facade.status = append(s.facade.status, paused)
+	s.facade.minionReportsWatchErr = errors.New("boom")
+
+	go func() {
+		err := s.clock.WaitAdvance(10*time.Second, coretesting.LongWait, 1)
+		c.Check(err, jc.ErrorIsNil)
+	}()
+
+	s.checkWorkerErr(c, "boom")
+	s.stub.CheckCalls(c, joinCalls(
+		watchStatusLockdownCalls,
+		[]jujutesting.StubCall{
+			{"facade.Export", nil},
+			apiOpenControllerCall,
+			importCall,
+			apiCloseCall,
+			{"facade.SetPhase", []interface{}{coremigration.VALIDATION}},
+			{"facade.MigrationStatus", nil},
+			{"facade.SetPhase", []interface{}{coremigration.VALIDATION}},
+			{"facade.WatchMinionReports", nil},
+		},
+	))
+	c.Assert(s.facade.statuses[len(s.facade.statuses)-2:], gc.DeepEquals, []string{
+		"paused before VALIDATION, waiting to be resumed or aborted",
+		"validating, waiting for agents to report back",
+	})
+}
+
+func (s *Suite) TestMigrationAbortedByUser(c *gc.C) {
+	s.facade.queueStatus(s.makeStatus(coremigration.IMPORT))
+	s.facade.setPhaseErrs = []error{errors.New("phase already changed")}
+	s.facade.status = append(s.facade.status, s.makeStatus(coremigration.ABORT))
+
+	s.checkWorkerReturns(c, migrationmaster.ErrInactive)
+	s.stub.CheckCalls(c, joinCalls(
+		watchStatusLockdownCalls,
+		[]jujutesting.StubCall{
+			{"facade.Export", nil},
+			apiOpenControllerCall,
+			importCall,
+			apiCloseCall,
+			{"facade.SetPhase", []interface{}{coremigration.VALIDATION}},
+			{"facade.MigrationStatus", nil},
+			apiOpenControllerCall,
+			abortCall,
+			apiCloseCall,
+			{"facade.SetPhase", []interface{}{coremigration.ABORTDONE}},
+		},
+	))
+	lastMessages := s.facade.statuses[len(s.facade.statuses)-2:]
+	c.Assert(lastMessages, gc.DeepEquals, []string{
+		"aborted by user",
+		"aborted, removing model from target controller: aborted by user",
+	})
+}
+
+func (s *Suite) TestSetPhaseErrorNotPaused(c *gc.C) {
+	s.facade.queueStatus(s.makeStatus(coremigration.REAP))
+	s.facade.setPhaseErrs = []error{errors.New("boom")}
+	s.facade.status = append(s.facade.status, s.makeStatus(coremigration.REAP))
+
+	s.checkWorkerErr(c, "failed to set phase: boom")
+}
+
 func (s *Suite) TestVALIDATIONMinionWaitWatchError(c *gc.C) {
 	s.checkMinionWaitWatchError(c, coremigration.VALIDATION)
 }
@@ -1086,6 +1155,8 @@ type stubMasterFacade struct {
 
 	exportedResources []coremigration.SerializedModelResource
 
+	setPhaseErrs []error
+
 	statuses []string
 }
 
@@ -1193,7 +1264,12 @@ func (f *stubMasterFacade) Export() (coremigration.SerializedModel, error) {
 
 func (f *stubMasterFacade) SetPhase(phase coremigration.Phase) error {
 	f.stub.AddCall("facade.SetPhase", phase)
-	return nil
+	if len(f.setPhaseErrs) == 0 {
+		return nil
+	}
+	err := f.setPhaseErrs[0]
+	f.setPhaseErrs = f.setPhaseErrs[1:]
+	return err
 }
 
 func (f *stubMasterFacade) SetStatusMessage(message string) error {
@@ -53,12 +53,13 @@ func Manifold(config ManifoldConfig) dependency.Manifold {
 				return nil, err
 			}
 			w, err := config.WorkerFunc(Config{
-				SystemdFiles:    []string{"/etc/juju-proxy-systemd.conf"},
-				EnvFiles:        []string{"/etc/juju-proxy.conf"},
-				RegistryPath:    `HKCU:\Software\Microsoft\Windows\CurrentVersion\Internet Settings`,
-				API:             proxyAPI,
-				ExternalUpdate:  config.ExternalUpdate,
-				InProcessUpdate: config.InProcessUpdate,
+				SystemdFiles:     []string{"/etc/juju-proxy-systemd.conf"},
+				EnvFiles:         []string{"/etc/juju-proxy.conf"},
+				EnvironmentFiles: []string{"/etc/environment"},
+				RegistryPath:     `HKCU:\Software\Microsoft\Windows\CurrentVersion\Internet Settings`,
+				API:              proxyAPI,
+				ExternalUpdate:   config.ExternalUpdate,
+				InProcessUpdate:  config.InProcessUpdate,
 			})
 			if err != nil {
 				return nil, errors.Trace(err)
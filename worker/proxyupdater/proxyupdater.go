@@ -6,6 +6,8 @@ package proxyupdater
 import (
 	"fmt"
 	"io/ioutil"
+	stdos "os"
+	"strings"
 
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
@@ -25,9 +27,15 @@ var (
 )
 
 type Config struct {
-	RegistryPath    string
-	EnvFiles        []string
-	SystemdFiles    []string
+	RegistryPath string
+	EnvFiles     []string
+	SystemdFiles []string
+
+	// EnvironmentFiles are files in pam_env's KEY=value format, such
+	// as /etc/environment, in which the proxy variables are updated
+	// while any other variables are left alone.
+	EnvironmentFiles []string
+
 	API             API
 	ExternalUpdate  func(proxyutils.Settings) error
 	InProcessUpdate func(proxyutils.Settings) error
@@ -82,6 +90,8 @@ func (w *proxyWorker) saveProxySettingsToFiles() error {
 	// - /etc/juju-proxy.conf - in 'env' format
 	// - /etc/systemd/system.conf.d/juju-proxy.conf
 	// - /etc/systemd/user.conf.d/juju-proxy.conf - both in 'systemd' format
+	// The proxy variables are also updated in /etc/environment, so that
+	// new login sessions see them.
 	for _, file := range w.config.EnvFiles {
 		err := ioutil.WriteFile(file, []byte(w.proxy.AsScriptEnvironment()), 0644)
 		if err != nil {
@@ -94,9 +104,64 @@ func (w *proxyWorker) saveProxySettingsToFiles() error {
 			logger.Errorf("Error updating systemd file - %v", err)
 		}
 	}
+	for _, file := range w.config.EnvironmentFiles {
+		if err := updateEnvironmentFile(file, w.proxy); err != nil {
+			logger.Errorf("Error updating environment file %s - %v", file, err)
+		}
+	}
 	return nil
 }
 
+// proxyVariables returns the names of the proxy environment variables,
+// in both lower and upper case, with their values in settings.
+func proxyVariables(settings proxyutils.Settings) [][2]string {
+	var variables [][2]string
+	for _, v := range [][2]string{
+		{"http_proxy", settings.Http},
+		{"https_proxy", settings.Https},
+		{"ftp_proxy", settings.Ftp},
+		{"no_proxy", settings.NoProxy},
+	} {
+		variables = append(variables, v, [2]string{strings.ToUpper(v[0]), v[1]})
+	}
+	return variables
+}
+
+// updateEnvironmentFile rewrites the proxy variables in the specified
+// file, which is in pam_env's KEY=value format, to match settings.
+// Other lines are preserved, and unset proxies are removed.
+func updateEnvironmentFile(file string, settings proxyutils.Settings) error {
+	content, err := ioutil.ReadFile(file)
+	if err != nil && !stdos.IsNotExist(err) {
+		return errors.Trace(err)
+	}
+	isProxy := make(map[string]bool)
+	for _, v := range proxyVariables(settings) {
+		isProxy[v[0]] = true
+	}
+	var lines []string
+	for _, line := range strings.Split(string(content), "\n") {
+		if line == "" {
+			continue
+		}
+		key := strings.TrimSpace(strings.SplitN(line, "=", 2)[0])
+		if isProxy[strings.TrimPrefix(key, "export ")] {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	for _, v := range proxyVariables(settings) {
+		if v[1] != "" {
+			lines = append(lines, fmt.Sprintf("%s=%q", v[0], v[1]))
+		}
+	}
+	newContent := strings.Join(lines, "\n") + "\n"
+	if newContent == string(content) {
+		return nil
+	}
+	return errors.Trace(ioutil.WriteFile(file, []byte(newContent), 0644))
+}
+
 func (w *proxyWorker) saveProxySettingsToRegistry() error {
 	// On windows we write the proxy settings to the registry.
 	setProxyScript := `$value_path = "%s"
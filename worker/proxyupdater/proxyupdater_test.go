@@ -35,6 +35,7 @@ type ProxyUpdaterSuite struct {
 	api              *fakeAPI
 	proxyEnvFile     string
 	proxySystemdFile string
+	environmentFile  string
 	detectedSettings proxy.Settings
 	inProcSettings   chan proxy.Settings
 	config           proxyupdater.Config
@@ -89,11 +90,13 @@ func (s *ProxyUpdaterSuite) SetUpTest(c *gc.C) {
 	directory := c.MkDir()
 	s.proxySystemdFile = filepath.Join(directory, "systemd.file")
 	s.proxyEnvFile = filepath.Join(directory, "env.file")
+	s.environmentFile = filepath.Join(directory, "environment")
 
 	s.config = proxyupdater.Config{
-		SystemdFiles: []string{s.proxySystemdFile},
-		EnvFiles:     []string{s.proxyEnvFile},
-		API:          s.api,
+		SystemdFiles:     []string{s.proxySystemdFile},
+		EnvFiles:         []string{s.proxyEnvFile},
+		EnvironmentFiles: []string{s.environmentFile},
+		API:              s.api,
 		InProcessUpdate: func(newSettings proxyutils.Settings) error {
 			select {
 			case s.inProcSettings <- newSettings:
@@ -226,6 +229,36 @@ func (s *ProxyUpdaterSuite) TestWriteSystemFiles(c *gc.C) {
 	s.waitForFile(c, pacconfig.AptProxyConfigFile, paccmder.ProxyConfigContents(aptProxySettings)+"\n")
 }
 
+func (s *ProxyUpdaterSuite) TestUpdateEnvironmentFile(c *gc.C) {
+	err := ioutil.WriteFile(s.environmentFile, []byte(`
+PATH="/usr/local/sbin:/usr/local/bin:/usr/sbin:/usr/bin"
+http_proxy="old proxy"
+HTTP_PROXY="old proxy"
+ftp_proxy="old proxy"
+LANG=C.UTF-8
+`[1:]), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+
+	proxySettings, _ := s.updateConfig(c)
+	proxySettings.Ftp = ""
+	s.api.Proxy = proxySettings
+	updater, err := proxyupdater.NewWorker(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	defer worker.Stop(updater)
+
+	// Other variables are preserved, and unset proxies removed.
+	s.waitForFile(c, s.environmentFile, `
+PATH="/usr/local/sbin:/usr/local/bin:/usr/sbin:/usr/bin"
+LANG=C.UTF-8
+http_proxy="http proxy"
+HTTP_PROXY="http proxy"
+https_proxy="https proxy"
+HTTPS_PROXY="https proxy"
+no_proxy="localhost,no proxy"
+NO_PROXY="localhost,no proxy"
+`[1:])
+}
+
 func (s *ProxyUpdaterSuite) TestEnvironmentVariables(c *gc.C) {
 	setenv := func(proxy, value string) {
 		os.Setenv(proxy, value)
@@ -0,0 +1,156 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package rebootmonitor
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	worker "gopkg.in/juju/worker.v1"
+	"gopkg.in/tomb.v1"
+
+	"github.com/juju/juju/status"
+	"github.com/juju/juju/worker/dependency"
+)
+
+var logger = loggo.GetLogger("juju.worker.rebootmonitor")
+
+const (
+	// DefaultBootIDPath is the path of the file holding the
+	// identifier of the current boot, on Linux.
+	DefaultBootIDPath = "/proc/sys/kernel/random/boot_id"
+
+	// bootIDFile is the file, in the data directory, holding
+	// the identifier of the boot in which the agent last ran.
+	bootIDFile = "boot-id"
+
+	// expectedRebootFile is the file, in the data directory,
+	// which marks a reboot as requested.
+	expectedRebootFile = "reboot-expected"
+)
+
+// Facade exposes controller functionality to a Worker.
+type Facade interface {
+	SetStatus(status status.Status, info string, data map[string]interface{}) error
+}
+
+// Config defines the parameters of the rebootmonitor worker.
+type Config struct {
+	Facade     Facade
+	DataDir    string
+	BootIDPath string
+}
+
+// Validate returns an error if Config cannot drive a rebootmonitor.
+func (config Config) Validate() error {
+	if config.Facade == nil {
+		return errors.NotValidf("nil Facade")
+	}
+	if config.DataDir == "" {
+		return errors.NotValidf("empty DataDir")
+	}
+	if config.BootIDPath == "" {
+		return errors.NotValidf("empty BootIDPath")
+	}
+	return nil
+}
+
+// RecordExpectedReboot records that the host is being rebooted as
+// requested, so that the reboot is not reported as unexpected when
+// the agent next starts.
+func RecordExpectedReboot(dataDir string) error {
+	path := filepath.Join(dataDir, expectedRebootFile)
+	return errors.Trace(ioutil.WriteFile(path, nil, 0644))
+}
+
+// New returns a Worker backed by config, or an error. The worker
+// detects whether the host has rebooted since the agent last ran,
+// by comparing boot identifiers, and records a reboot which was not
+// requested in the machine's status history. It then uninstalls
+// itself. Interrupted upgrades and unit deployments are resumed or
+// rolled back by the workers responsible for them when they start,
+// so need nothing from it.
+func New(config Config) (worker.Worker, error) {
+	if err := config.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	w := &rebootmonitor{config: config}
+	go func() {
+		defer w.tomb.Done()
+		w.tomb.Kill(w.run())
+	}()
+	return w, nil
+}
+
+type rebootmonitor struct {
+	tomb   tomb.Tomb
+	config Config
+}
+
+// Kill implements worker.Worker.
+func (w *rebootmonitor) Kill() {
+	w.tomb.Kill(nil)
+}
+
+// Wait implements worker.Worker.
+func (w *rebootmonitor) Wait() error {
+	return w.tomb.Wait()
+}
+
+func (w *rebootmonitor) run() error {
+	current, err := readBootID(w.config.BootIDPath)
+	if os.IsNotExist(errors.Cause(err)) {
+		logger.Debugf("%s doesn't exist - giving up", w.config.BootIDPath)
+		return dependency.ErrUninstall
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	bootIDPath := filepath.Join(w.config.DataDir, bootIDFile)
+	previous, err := readBootID(bootIDPath)
+	if err != nil && !os.IsNotExist(errors.Cause(err)) {
+		return errors.Trace(err)
+	}
+	expectedPath := filepath.Join(w.config.DataDir, expectedRebootFile)
+	if previous != "" && previous != current {
+		if err := w.rebooted(previous, current, expectedPath); err != nil {
+			return errors.Trace(err)
+		}
+	}
+
+	if err := os.Remove(expectedPath); err != nil && !os.IsNotExist(err) {
+		return errors.Trace(err)
+	}
+	if err := ioutil.WriteFile(bootIDPath, []byte(current+"\n"), 0644); err != nil {
+		return errors.Trace(err)
+	}
+	return dependency.ErrUninstall
+}
+
+// rebooted records a reboot of the host, unless it was requested.
+func (w *rebootmonitor) rebooted(previous, current, expectedPath string) error {
+	if _, err := os.Stat(expectedPath); err == nil {
+		logger.Infof("host rebooted as requested")
+		return nil
+	} else if !os.IsNotExist(err) {
+		return errors.Trace(err)
+	}
+	logger.Warningf("host rebooted unexpectedly")
+	err := w.config.Facade.SetStatus(status.Started, "restarted after unexpected host reboot", map[string]interface{}{
+		"boot-id":          current,
+		"previous-boot-id": previous,
+	})
+	return errors.Annotate(err, "recording unexpected reboot")
+}
+
+func readBootID(path string) (string, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	return strings.TrimSpace(string(data)), nil
+}
@@ -0,0 +1,143 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package rebootmonitor_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+
+	"github.com/juju/errors"
+	jujutesting "github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/status"
+	"github.com/juju/juju/worker/dependency"
+	"github.com/juju/juju/worker/rebootmonitor"
+	"github.com/juju/juju/worker/workertest"
+)
+
+type Suite struct {
+	jujutesting.IsolationSuite
+
+	dataDir string
+	stub    *jujutesting.Stub
+	config  rebootmonitor.Config
+}
+
+var _ = gc.Suite(&Suite{})
+
+func (s *Suite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.dataDir = c.MkDir()
+	s.stub = new(jujutesting.Stub)
+	s.config = rebootmonitor.Config{
+		Facade:     &stubFacade{s.stub},
+		DataDir:    s.dataDir,
+		BootIDPath: filepath.Join(c.MkDir(), "boot_id"),
+	}
+	s.setBootID(c, "boot-2")
+}
+
+func (s *Suite) setBootID(c *gc.C, bootID string) {
+	err := ioutil.WriteFile(s.config.BootIDPath, []byte(bootID+"\n"), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *Suite) setPreviousBootID(c *gc.C, bootID string) {
+	err := ioutil.WriteFile(filepath.Join(s.dataDir, "boot-id"), []byte(bootID+"\n"), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *Suite) run(c *gc.C) error {
+	w, err := rebootmonitor.New(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	return workertest.CheckKilled(c, w)
+}
+
+func (s *Suite) checkPreviousBootID(c *gc.C, expected string) {
+	data, err := ioutil.ReadFile(filepath.Join(s.dataDir, "boot-id"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(string(data), gc.Equals, expected+"\n")
+}
+
+func (s *Suite) TestInvalidConfig(c *gc.C) {
+	s.config.DataDir = ""
+	_, err := rebootmonitor.New(s.config)
+	c.Check(err, gc.ErrorMatches, "empty DataDir .+")
+	c.Check(s.stub.Calls(), gc.HasLen, 0)
+}
+
+func (s *Suite) TestNoBootID(c *gc.C) {
+	s.config.BootIDPath = filepath.Join(c.MkDir(), "missing")
+	err := s.run(c)
+	c.Check(errors.Cause(err), gc.Equals, dependency.ErrUninstall)
+	c.Check(s.stub.Calls(), gc.HasLen, 0)
+}
+
+func (s *Suite) TestFirstBoot(c *gc.C) {
+	err := s.run(c)
+	c.Check(err, gc.Equals, dependency.ErrUninstall)
+	c.Check(s.stub.Calls(), gc.HasLen, 0)
+	s.checkPreviousBootID(c, "boot-2")
+}
+
+func (s *Suite) TestSameBoot(c *gc.C) {
+	s.setPreviousBootID(c, "boot-2")
+	err := s.run(c)
+	c.Check(err, gc.Equals, dependency.ErrUninstall)
+	c.Check(s.stub.Calls(), gc.HasLen, 0)
+}
+
+func (s *Suite) TestUnexpectedReboot(c *gc.C) {
+	s.setPreviousBootID(c, "boot-1")
+	err := s.run(c)
+	c.Check(err, gc.Equals, dependency.ErrUninstall)
+	s.stub.CheckCalls(c, []jujutesting.StubCall{{
+		"SetStatus", []interface{}{
+			status.Started,
+			"restarted after unexpected host reboot",
+			map[string]interface{}{
+				"boot-id":          "boot-2",
+				"previous-boot-id": "boot-1",
+			},
+		},
+	}})
+	s.checkPreviousBootID(c, "boot-2")
+}
+
+func (s *Suite) TestExpectedReboot(c *gc.C) {
+	s.setPreviousBootID(c, "boot-1")
+	err := rebootmonitor.RecordExpectedReboot(s.dataDir)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.run(c)
+	c.Check(err, gc.Equals, dependency.ErrUninstall)
+	c.Check(s.stub.Calls(), gc.HasLen, 0)
+	s.checkPreviousBootID(c, "boot-2")
+
+	// The next reboot is unexpected, unless recorded again.
+	s.setBootID(c, "boot-3")
+	err = s.run(c)
+	c.Check(err, gc.Equals, dependency.ErrUninstall)
+	s.stub.CheckCallNames(c, "SetStatus")
+}
+
+func (s *Suite) TestSetStatusError(c *gc.C) {
+	s.setPreviousBootID(c, "boot-1")
+	s.stub.SetErrors(errors.New("blam"))
+	err := s.run(c)
+	c.Check(err, gc.ErrorMatches, "recording unexpected reboot: blam")
+
+	// The reboot is recorded when the worker is restarted.
+	s.checkPreviousBootID(c, "boot-1")
+}
+
+type stubFacade struct {
+	stub *jujutesting.Stub
+}
+
+func (f *stubFacade) SetStatus(status status.Status, info string, data map[string]interface{}) error {
+	f.stub.AddCall("SetStatus", status, info, data)
+	return f.stub.NextErr()
+}
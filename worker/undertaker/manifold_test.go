@@ -76,7 +76,7 @@ func (*ManifoldSuite) TestNewWorkerError(c *gc.C) {
 	}
 	config.NewWorker = func(cfg undertaker.Config) (worker.Worker, error) {
 		c.Check(cfg.Facade, gc.Equals, expectFacade)
-		checkResource(c, cfg.Environ, resources, "environ")
+		checkResource(c, cfg.Destroyer, resources, "environ")
 		return nil, errors.New("lhiis")
 	}
 	manifold := undertaker.Manifold(config)
@@ -105,8 +105,8 @@ func (*ManifoldSuite) TestNewWorkerSuccess(c *gc.C) {
 
 func namesConfig() undertaker.ManifoldConfig {
 	return undertaker.ManifoldConfig{
-		APICallerName: "api-caller",
-		EnvironName:   "environ",
+		APICallerName:      "api-caller",
+		CloudDestroyerName: "environ",
 	}
 }
 
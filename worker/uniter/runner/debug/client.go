@@ -11,12 +11,15 @@ import (
 )
 
 type hookArgs struct {
-	Hooks []string `yaml:"hooks,omitempty"`
+	Hooks   []string `yaml:"hooks,omitempty"`
+	DebugAt string   `yaml:"debug-at,omitempty"`
 }
 
 // ClientScript returns a bash script suitable for executing
 // on the unit system to intercept matching hooks or actions via tmux shell.
-func ClientScript(c *HooksContext, match []string) string {
+// If debugAt is not empty, matching hooks and actions are run in the shell
+// with JUJU_DEBUG_AT set to it, rather than left for the user to run.
+func ClientScript(c *HooksContext, match []string, debugAt string) string {
 	// If any argument is "*", then the client is interested in all.
 	for _, m := range match {
 		if m == "*" {
@@ -30,15 +33,15 @@ func ClientScript(c *HooksContext, match []string) string {
 	s = strings.Replace(s, "{entry_flock}", c.ClientFileLock(), -1)
 	s = strings.Replace(s, "{exit_flock}", c.ClientExitFileLock(), -1)
 
-	yamlArgs := encodeArgs(match)
+	yamlArgs := encodeArgs(match, debugAt)
 	base64Args := base64.StdEncoding.EncodeToString(yamlArgs)
 	s = strings.Replace(s, "{hook_args}", base64Args, 1)
 	return s
 }
 
-func encodeArgs(args []string) []byte {
+func encodeArgs(hooks []string, debugAt string) []byte {
 	// Marshal to YAML, then encode in base64 to avoid shell escapes.
-	yamlArgs, err := goyaml.Marshal(hookArgs{Hooks: args})
+	yamlArgs, err := goyaml.Marshal(hookArgs{Hooks: hooks, DebugAt: debugAt})
 	if err != nil {
 		// This should not happen: we're in full control.
 		panic(err)
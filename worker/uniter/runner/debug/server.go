@@ -18,10 +18,11 @@ import (
 	goyaml "gopkg.in/yaml.v2"
 )
 
-// ServerSession represents a "juju debug-hooks" session.
+// ServerSession represents a "juju debug-hooks" or "juju debug-code" session.
 type ServerSession struct {
 	*HooksContext
-	hooks set.Strings
+	hooks   set.Strings
+	debugAt string
 
 	output io.Writer
 }
@@ -32,6 +33,12 @@ func (s *ServerSession) MatchHook(hookName string) bool {
 	return s.hooks.IsEmpty() || s.hooks.Contains(hookName)
 }
 
+// DebugAt returns the value of JUJU_DEBUG_AT requested by a debug-code
+// client, or an empty string for a debug-hooks session.
+func (s *ServerSession) DebugAt() string {
+	return s.debugAt
+}
+
 // waitClientExit executes flock, waiting for the SSH client to exit.
 // This is a var so it can be replaced for testing.
 var waitClientExit = func(s *ServerSession) {
@@ -40,18 +47,23 @@ var waitClientExit = func(s *ServerSession) {
 }
 
 // RunHook "runs" the hook with the specified name via debug-hooks.
-func (s *ServerSession) RunHook(hookName, charmDir string, env []string) error {
+// For a debug-code session, the hook is run by executing the file at
+// hookRunner in the debug shell.
+func (s *ServerSession) RunHook(hookName, charmDir string, env []string, hookRunner string) error {
 	debugDir, err := ioutil.TempDir("", "juju-debug-hooks-")
 	if err != nil {
 		return errors.Trace(err)
 	}
 	defer os.RemoveAll(debugDir)
-	if err := s.writeDebugFiles(debugDir); err != nil {
+	if err := s.writeDebugFiles(debugDir, hookRunner); err != nil {
 		return errors.Trace(err)
 	}
 
 	env = utils.Setenv(env, "JUJU_HOOK_NAME="+hookName)
 	env = utils.Setenv(env, "JUJU_DEBUG="+debugDir)
+	if s.debugAt != "" {
+		env = utils.Setenv(env, "JUJU_DEBUG_AT="+s.debugAt)
+	}
 
 	cmd := exec.Command("/bin/bash", "-s")
 	cmd.Env = env
@@ -74,14 +86,15 @@ func (s *ServerSession) RunHook(hookName, charmDir string, env []string) error {
 	return cmd.Wait()
 }
 
-func (s *ServerSession) writeDebugFiles(debugDir string) error {
+func (s *ServerSession) writeDebugFiles(debugDir, hookRunner string) error {
 	// hook.sh does not inherit environment variables,
 	// so we must insert the path to the directory
 	// containing env.sh for it to source.
-	debugHooksHookScript := strings.Replace(
-		debugHooksHookScript,
-		"__JUJU_DEBUG__", debugDir, -1,
-	)
+	hookScript := debugHooksHookScript
+	if s.debugAt != "" {
+		hookScript = strings.Replace(debugCodeHookScript, "__HOOK_RUNNER__", hookRunner, -1)
+	}
+	hookScript = strings.Replace(hookScript, "__JUJU_DEBUG__", debugDir, -1)
 
 	type file struct {
 		filename string
@@ -91,7 +104,7 @@ func (s *ServerSession) writeDebugFiles(debugDir string) error {
 	files := []file{
 		{"welcome.msg", debugHooksWelcomeMessage, 0644},
 		{"init.sh", debugHooksInitScript, 0755},
-		{"hook.sh", debugHooksHookScript, 0755},
+		{"hook.sh", hookScript, 0755},
 	}
 	for _, file := range files {
 		if err := ioutil.WriteFile(
@@ -128,7 +141,7 @@ func (c *HooksContext) FindSession() (*ServerSession, error) {
 		return nil, err
 	}
 	hooks := set.NewStrings(args.Hooks...)
-	session := &ServerSession{HooksContext: c, hooks: hooks}
+	session := &ServerSession{HooksContext: c, hooks: hooks, debugAt: args.DebugAt}
 	return session, nil
 }
 
@@ -190,3 +203,18 @@ const debugHooksHookScript = `#!/bin/bash
 echo $$ > $JUJU_DEBUG/hook.pid
 exec /bin/bash --noprofile --init-file $JUJU_DEBUG/init.sh
 `
+
+// debugCodeHookScript runs the hook rather than starting a shell for
+// the user to run it in, so that breakpoints in the charm's code that
+// are enabled by JUJU_DEBUG_AT are reached.
+const debugCodeHookScript = `#!/bin/bash
+. __JUJU_DEBUG__/env.sh
+echo $$ > $JUJU_DEBUG/hook.pid
+trap 'echo $? > $JUJU_DEBUG/hook_exit_status' EXIT
+cd "$CHARM_DIR"
+if [ ! -x "__HOOK_RUNNER__" ]; then
+    echo "$JUJU_HOOK_NAME is not implemented by the charm"
+    exit 0
+fi
+"__HOOK_RUNNER__"
+`
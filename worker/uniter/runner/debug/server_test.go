@@ -103,6 +103,37 @@ func (s *DebugHooksServerSuite) TestFindSession(c *gc.C) {
 	c.Assert(session.MatchHook("foo bar baz"), jc.IsFalse)
 }
 
+func (s *DebugHooksServerSuite) TestFindSessionDebugAt(c *gc.C) {
+	err := ioutil.WriteFile(s.ctx.ClientFileLock(), []byte(`hooks: [install]`), 0777)
+	c.Assert(err, jc.ErrorIsNil)
+	session, err := s.ctx.FindSession()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(session.DebugAt(), gc.Equals, "")
+
+	err = ioutil.WriteFile(s.ctx.ClientFileLock(), []byte(`{hooks: [install], debug-at: all}`), 0777)
+	c.Assert(err, jc.ErrorIsNil)
+	session, err = s.ctx.FindSession()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(session.DebugAt(), gc.Equals, "all")
+	c.Assert(session.MatchHook("install"), jc.IsTrue)
+	c.Assert(session.MatchHook("start"), jc.IsFalse)
+}
+
+func (s *DebugHooksServerSuite) TestDebugCodeHookScript(c *gc.C) {
+	session := &ServerSession{HooksContext: s.ctx, debugAt: "all"}
+	debugDir := c.MkDir()
+	err := session.writeDebugFiles(debugDir, "/var/lib/juju/charm/hooks/install")
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Rather than starting a shell, the hook script runs the hook.
+	data, err := ioutil.ReadFile(filepath.Join(debugDir, "hook.sh"))
+	c.Assert(err, jc.ErrorIsNil)
+	script := string(data)
+	c.Assert(script, jc.Contains, ". "+debugDir+"/env.sh\n")
+	c.Assert(script, jc.Contains, "\n\"/var/lib/juju/charm/hooks/install\"\n")
+	c.Assert(script, gc.Not(jc.Contains), "--init-file")
+}
+
 func (s *DebugHooksServerSuite) TestRunHookExceptional(c *gc.C) {
 	err := ioutil.WriteFile(s.ctx.ClientFileLock(), []byte{}, 0777)
 	c.Assert(err, jc.ErrorIsNil)
@@ -125,7 +156,7 @@ func (s *DebugHooksServerSuite) TestRunHookExceptional(c *gc.C) {
 	s.PatchValue(&waitClientExit, func(*ServerSession) {
 		flockAcquired <- struct{}{}
 	})
-	err = session.RunHook("myhook", s.tmpdir, os.Environ())
+	err = session.RunHook("myhook", s.tmpdir, os.Environ(), filepath.Join(s.tmpdir, "hooks", "myhook"))
 	c.Assert(err, gc.ErrorMatches, "signal: [kK]illed")
 	waitForFlock()
 
@@ -141,7 +172,7 @@ func (s *DebugHooksServerSuite) TestRunHookExceptional(c *gc.C) {
 		flockAcquired <- struct{}{}
 	})
 	go func() { ch <- true }() // asynchronously release the flock
-	err = session.RunHook("myhook", s.tmpdir, os.Environ())
+	err = session.RunHook("myhook", s.tmpdir, os.Environ(), filepath.Join(s.tmpdir, "hooks", "myhook"))
 	waitForFlock()
 	c.Assert(clientExited, jc.IsTrue)
 	c.Assert(err, gc.ErrorMatches, "signal: [kK]illed")
@@ -164,7 +195,7 @@ func (s *DebugHooksServerSuite) TestRunHook(c *gc.C) {
 	const hookName = "myhook"
 	runHookCh := make(chan error)
 	go func() {
-		runHookCh <- session.RunHook(hookName, s.tmpdir, os.Environ())
+		runHookCh <- session.RunHook(hookName, s.tmpdir, os.Environ(), filepath.Join(s.tmpdir, "hooks", hookName))
 	}()
 
 	flockCh := make(chan struct{})
@@ -225,7 +225,9 @@ func (runner *runner) runCharmHookWithLocation(hookName, charmLocation string) e
 	debugctx := debug.NewHooksContext(runner.context.UnitName())
 	if session, _ := debugctx.FindSession(); session != nil && session.MatchHook(hookName) {
 		logger.Infof("executing %s via debug-hooks", hookName)
-		err = session.RunHook(hookName, runner.paths.GetCharmDir(), env)
+		charmDir := runner.paths.GetCharmDir()
+		hookRunner := filepath.Join(charmDir, charmLocation, hookName)
+		err = session.RunHook(hookName, charmDir, env, hookRunner)
 	} else {
 		err = runner.runCharmHook(hookName, env, charmLocation)
 	}
@@ -5,6 +5,7 @@ package upgrader
 
 import (
 	"github.com/juju/errors"
+	"github.com/juju/pubsub"
 	"github.com/juju/version"
 	worker "gopkg.in/juju/worker.v1"
 
@@ -23,6 +24,10 @@ type ManifoldConfig struct {
 	UpgradeStepsGateName string
 	UpgradeCheckGateName string
 	PreviousAgentVersion version.Number
+
+	// Hub, if set, is the agent's local hub, on which the worker
+	// announces an upgrade before restarting the agent to run it.
+	Hub *pubsub.SimpleHub
 }
 
 // Manifold returns a dependency manifold that runs an upgrader
@@ -83,6 +88,7 @@ func Manifold(config ManifoldConfig) dependency.Manifold {
 				config.PreviousAgentVersion,
 				upgradeStepsWaiter,
 				initialCheckUnlocker,
+				config.Hub,
 			)
 		},
 	}
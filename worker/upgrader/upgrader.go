@@ -10,6 +10,7 @@ import (
 
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
+	"github.com/juju/pubsub"
 	"github.com/juju/utils"
 	"github.com/juju/utils/arch"
 	"github.com/juju/utils/series"
@@ -19,6 +20,7 @@ import (
 	"github.com/juju/juju/agent"
 	agenttools "github.com/juju/juju/agent/tools"
 	"github.com/juju/juju/api/upgrader"
+	agentpubsub "github.com/juju/juju/pubsub/agent"
 	coretools "github.com/juju/juju/tools"
 	jujuversion "github.com/juju/juju/version"
 	"github.com/juju/juju/worker/catacomb"
@@ -44,6 +46,7 @@ type Upgrader struct {
 	origAgentVersion            version.Number
 	upgradeStepsWaiter          gate.Waiter
 	initialUpgradeCheckComplete gate.Unlocker
+	hub                         *pubsub.SimpleHub
 }
 
 // NewAgentUpgrader returns a new upgrader worker. It watches changes to the
@@ -51,13 +54,15 @@ type Upgrader struct {
 // download the tools for any new version into the given data directory.  If
 // an upgrade is needed, the worker will exit with an UpgradeReadyError
 // holding details of the requested upgrade. The tools will have been
-// downloaded and unpacked.
+// downloaded and unpacked. If hub is not nil, the upgrade is announced
+// on it before the worker exits.
 func NewAgentUpgrader(
 	st *upgrader.State,
 	agentConfig agent.Config,
 	origAgentVersion version.Number,
 	upgradeStepsWaiter gate.Waiter,
 	initialUpgradeCheckComplete gate.Unlocker,
+	hub *pubsub.SimpleHub,
 ) (*Upgrader, error) {
 	u := &Upgrader{
 		st:                          st,
@@ -66,6 +71,7 @@ func NewAgentUpgrader(
 		origAgentVersion:            origAgentVersion,
 		upgradeStepsWaiter:          upgradeStepsWaiter,
 		initialUpgradeCheckComplete: initialUpgradeCheckComplete,
+		hub:                         hub,
 	}
 	err := catacomb.Invoke(catacomb.Plan{
 		Site: &u.catacomb,
@@ -201,7 +207,7 @@ func (u *Upgrader) loop() error {
 		// Check if tools have already been downloaded.
 		wantVersionBinary := toBinaryVersion(wantVersion)
 		if u.toolsAlreadyDownloaded(wantVersionBinary) {
-			return u.newUpgradeReadyError(wantVersionBinary)
+			return u.upgradeReady(wantVersionBinary)
 		}
 
 		// Check if tools are available for download.
@@ -218,7 +224,7 @@ func (u *Upgrader) loop() error {
 		for _, wantTools := range wantToolsList {
 			err = u.ensureTools(wantTools)
 			if err == nil {
-				return u.newUpgradeReadyError(wantTools.Version)
+				return u.upgradeReady(wantTools.Version)
 			}
 			logger.Errorf("failed to fetch agent binaries from %q: %v", wantTools.URL, err)
 		}
@@ -240,6 +246,20 @@ func (u *Upgrader) toolsAlreadyDownloaded(wantVersion version.Binary) bool {
 	return err == nil
 }
 
+// upgradeReady announces that the agent is about to upgrade to the
+// supplied version, and returns the error with which the worker exits
+// to make it do so.
+func (u *Upgrader) upgradeReady(newVersion version.Binary) error {
+	err := u.newUpgradeReadyError(newVersion)
+	if u.hub != nil {
+		u.hub.Publish(agentpubsub.UpgradeStartingTopic, agentpubsub.UpgradeStarting{
+			From: err.OldTools,
+			To:   err.NewTools,
+		})
+	}
+	return err
+}
+
 func (u *Upgrader) newUpgradeReadyError(newVersion version.Binary) *UpgradeReadyError {
 	return &UpgradeReadyError{
 		OldTools:  toBinaryVersion(jujuversion.Current),
@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/juju/errors"
+	"github.com/juju/pubsub"
 	jc "github.com/juju/testing/checkers"
 	"github.com/juju/utils"
 	"github.com/juju/utils/arch"
@@ -26,6 +27,7 @@ import (
 	envtesting "github.com/juju/juju/environs/testing"
 	envtools "github.com/juju/juju/environs/tools"
 	jujutesting "github.com/juju/juju/juju/testing"
+	agentpubsub "github.com/juju/juju/pubsub/agent"
 	"github.com/juju/juju/state"
 	statetesting "github.com/juju/juju/state/testing"
 	coretesting "github.com/juju/juju/testing"
@@ -48,6 +50,7 @@ type UpgraderSuite struct {
 	confVersion          version.Number
 	upgradeStepsComplete gate.Lock
 	initialCheckComplete gate.Lock
+	hub                  *pubsub.SimpleHub
 }
 
 type AllowedTargetVersionSuite struct{}
@@ -68,6 +71,7 @@ func (s *UpgraderSuite) SetUpTest(c *gc.C) {
 	})
 	s.upgradeStepsComplete = gate.NewLock()
 	s.initialCheckComplete = gate.NewLock()
+	s.hub = pubsub.NewSimpleHub(nil)
 }
 
 func (s *UpgraderSuite) patchVersion(v version.Binary) {
@@ -105,6 +109,7 @@ func (s *UpgraderSuite) makeUpgrader(c *gc.C) *upgrader.Upgrader {
 		s.confVersion,
 		s.upgradeStepsComplete,
 		s.initialCheckComplete,
+		s.hub,
 	)
 	c.Assert(err, jc.ErrorIsNil)
 	return w
@@ -288,6 +293,35 @@ func (s *UpgraderSuite) TestUsesAlreadyDownloadedToolsIfAvailable(c *gc.C) {
 	})
 }
 
+func (s *UpgraderSuite) TestUpgradeStartingPublished(c *gc.C) {
+	oldVersion := version.MustParseBinary("1.2.3-quantal-amd64")
+	s.patchVersion(oldVersion)
+
+	newVersion := version.MustParseBinary("5.4.3-quantal-amd64")
+	err := statetesting.SetAgentVersion(s.State, newVersion.Number)
+	c.Assert(err, jc.ErrorIsNil)
+	envtesting.InstallFakeDownloadedTools(c, s.DataDir(), newVersion)
+
+	published := make(chan interface{}, 1)
+	unsubscribe := s.hub.Subscribe(agentpubsub.UpgradeStartingTopic, func(_ string, data interface{}) {
+		published <- data
+	})
+	defer unsubscribe()
+
+	u := s.makeUpgrader(c)
+	err = u.Stop()
+	c.Assert(err, gc.FitsTypeOf, &upgrader.UpgradeReadyError{})
+	select {
+	case data := <-published:
+		c.Check(data, jc.DeepEquals, agentpubsub.UpgradeStarting{
+			From: oldVersion,
+			To:   newVersion,
+		})
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("upgrade not published")
+	}
+}
+
 func (s *UpgraderSuite) TestUpgraderRefusesToDowngradeMinorVersions(c *gc.C) {
 	stor := s.DefaultToolsStorage
 	origTools := envtesting.PrimeTools(c, stor, s.DataDir(), s.Environ.Config().AgentStream(), version.MustParseBinary("5.4.3-precise-amd64"))
@@ -0,0 +1,95 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package upgradeseries
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/utils/series"
+	"gopkg.in/juju/names.v2"
+	worker "gopkg.in/juju/worker.v1"
+
+	"github.com/juju/juju/agent"
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/cmd/jujud/agent/engine"
+	"github.com/juju/juju/worker/dependency"
+)
+
+// ManifoldConfig holds the dependencies and configuration for an
+// upgradeseries manifold.
+type ManifoldConfig struct {
+	AgentName     string
+	APICallerName string
+
+	NewFacade func(base.APICaller, names.MachineTag) Facade
+	NewWorker func(Config) (worker.Worker, error)
+}
+
+// validate is called by start to check for bad configuration.
+func (config ManifoldConfig) validate() error {
+	if config.AgentName == "" {
+		return errors.NotValidf("empty AgentName")
+	}
+	if config.APICallerName == "" {
+		return errors.NotValidf("empty APICallerName")
+	}
+	if config.NewFacade == nil {
+		return errors.NotValidf("nil NewFacade")
+	}
+	if config.NewWorker == nil {
+		return errors.NotValidf("nil NewWorker")
+	}
+	return nil
+}
+
+// start is a StartFunc for a Worker manifold.
+func (config ManifoldConfig) start(context dependency.Context) (worker.Worker, error) {
+	if err := config.validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	var agent agent.Agent
+	if err := context.Get(config.AgentName, &agent); err != nil {
+		return nil, errors.Trace(err)
+	}
+	var apiCaller base.APICaller
+	if err := context.Get(config.APICallerName, &apiCaller); err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	tag, ok := agent.CurrentConfig().Tag().(names.MachineTag)
+	if !ok {
+		return nil, errors.New("upgradeseries may only be used with a machine agent")
+	}
+
+	worker, err := config.NewWorker(Config{
+		Facade:     config.NewFacade(apiCaller, tag),
+		Service:    serviceAccess{},
+		HostSeries: series.HostSeries,
+	})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return worker, nil
+}
+
+// Manifold returns a dependency manifold that runs the upgradeseries
+// worker, exposing whether the unit agents may run as an engine.Flag.
+func Manifold(config ManifoldConfig) dependency.Manifold {
+	return dependency.Manifold{
+		Inputs: []string{
+			config.AgentName,
+			config.APICallerName,
+		},
+		Start:  config.start,
+		Output: engine.FlagOutput,
+		Filter: bounceErrChanged,
+	}
+}
+
+// bounceErrChanged converts ErrChanged to dependency.ErrBounce.
+func bounceErrChanged(err error) error {
+	if errors.Cause(err) == ErrChanged {
+		return dependency.ErrBounce
+	}
+	return err
+}
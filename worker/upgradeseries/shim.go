@@ -0,0 +1,39 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package upgradeseries
+
+import (
+	"github.com/juju/errors"
+	"gopkg.in/juju/names.v2"
+	worker "gopkg.in/juju/worker.v1"
+
+	"github.com/juju/juju/api/base"
+	apiupgradeseries "github.com/juju/juju/api/upgradeseries"
+	"github.com/juju/juju/service"
+	"github.com/juju/juju/service/common"
+)
+
+func NewFacade(apiCaller base.APICaller, tag names.MachineTag) Facade {
+	return apiupgradeseries.NewFacade(apiCaller, tag)
+}
+
+func NewWorker(config Config) (worker.Worker, error) {
+	worker, err := New(config)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return worker, nil
+}
+
+// serviceAccess implements ServiceAccess using the host's init system.
+type serviceAccess struct{}
+
+// DiscoverService is part of the ServiceAccess interface.
+func (serviceAccess) DiscoverService(name string) (AgentService, error) {
+	svc, err := service.DiscoverService(name, common.Conf{})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return svc, nil
+}
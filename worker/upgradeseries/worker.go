@@ -0,0 +1,271 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package upgradeseries
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/core/model"
+	"github.com/juju/juju/watcher"
+	"github.com/juju/juju/worker/catacomb"
+)
+
+var logger = loggo.GetLogger("juju.worker.upgradeseries")
+
+// ErrChanged indicates that a Worker has stopped because its
+// Check result is no longer valid.
+var ErrChanged = errors.New("upgrade series flag value changed")
+
+// Facade exposes controller functionality to a Worker.
+type Facade interface {
+	WatchUpgradeSeriesNotifications() (watcher.NotifyWatcher, error)
+	MachineStatus() (model.UpgradeSeriesStatus, error)
+	SetMachineStatus(model.UpgradeSeriesStatus) error
+	TargetSeries() (string, error)
+	UnitStatuses() (map[string]model.UpgradeSeriesStatus, error)
+	FinishUpgradeSeries(hostSeries string) error
+}
+
+// AgentService controls the init system service of a unit agent.
+type AgentService interface {
+	Running() (bool, error)
+	Start() error
+	Stop() error
+}
+
+// ServiceAccess finds the init system services of unit agents.
+type ServiceAccess interface {
+	DiscoverService(name string) (AgentService, error)
+}
+
+// Config defines the parameters of the upgradeseries worker.
+type Config struct {
+	Facade     Facade
+	Service    ServiceAccess
+	HostSeries func() (string, error)
+}
+
+// Validate returns an error if Config cannot drive an upgradeseries
+// worker.
+func (config Config) Validate() error {
+	if config.Facade == nil {
+		return errors.NotValidf("nil Facade")
+	}
+	if config.Service == nil {
+		return errors.NotValidf("nil Service")
+	}
+	if config.HostSeries == nil {
+		return errors.NotValidf("nil HostSeries")
+	}
+	return nil
+}
+
+// New returns a Worker that drives the machine's side of a series
+// upgrade. Once every unit has done its preparation, the unit agents
+// are stopped and the worker's Check result changes, so that workers
+// which would deploy or run units are stopped while the operating
+// system is upgraded. When the upgrade is to be completed, the unit
+// agents are started again, and the machine's series is updated once
+// every unit has completed its own part of the upgrade.
+func New(config Config) (*Worker, error) {
+	if err := config.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	status, err := machineStatus(config.Facade)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	w := &Worker{
+		config: config,
+		gated:  isGated(status),
+	}
+	err = catacomb.Invoke(catacomb.Plan{
+		Site: &w.catacomb,
+		Work: w.loop,
+	})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return w, nil
+}
+
+// Worker implements worker.Worker and engine.Flag, and exits with
+// ErrChanged whenever the unit agents are stopped for, or restarted
+// after, the operating system upgrade.
+type Worker struct {
+	catacomb catacomb.Catacomb
+	config   Config
+	gated    bool
+}
+
+// Kill is part of the worker.Worker interface.
+func (w *Worker) Kill() {
+	w.catacomb.Kill(nil)
+}
+
+// Wait is part of the worker.Worker interface.
+func (w *Worker) Wait() error {
+	return w.catacomb.Wait()
+}
+
+// Check is part of the engine.Flag interface. It returns true unless
+// the unit agents are stopped while the operating system is upgraded.
+func (w *Worker) Check() bool {
+	return !w.gated
+}
+
+func (w *Worker) loop() error {
+	watcher, err := w.config.Facade.WatchUpgradeSeriesNotifications()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err := w.catacomb.Add(watcher); err != nil {
+		return errors.Trace(err)
+	}
+	for {
+		select {
+		case <-w.catacomb.Dying():
+			return w.catacomb.ErrDying()
+		case _, ok := <-watcher.Changes():
+			if !ok {
+				return errors.New("upgrade series watcher closed")
+			}
+			if err := w.handleChange(); err != nil {
+				return errors.Trace(err)
+			}
+		}
+	}
+}
+
+func (w *Worker) handleChange() error {
+	status, err := machineStatus(w.config.Facade)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if isGated(status) != w.gated {
+		return ErrChanged
+	}
+	switch status {
+	case model.UpgradeSeriesPrepareStarted:
+		return w.handlePrepareStarted()
+	case model.UpgradeSeriesCompleteStarted:
+		return w.handleCompleteStarted()
+	}
+	return nil
+}
+
+// handlePrepareStarted stops the unit agents, once all the units have
+// prepared for the series upgrade.
+func (w *Worker) handlePrepareStarted() error {
+	unitNames, ok, err := w.unitsWithStatus(model.UpgradeSeriesPrepareCompleted)
+	if err != nil || !ok {
+		return errors.Trace(err)
+	}
+	for _, unitName := range unitNames {
+		svc, err := w.unitService(unitName)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		logger.Infof("stopping unit agent %q for series upgrade", unitName)
+		if err := svc.Stop(); err != nil {
+			return errors.Annotatef(err, "stopping unit agent %q", unitName)
+		}
+	}
+	err = w.config.Facade.SetMachineStatus(model.UpgradeSeriesPrepareCompleted)
+	return errors.Trace(err)
+}
+
+// handleCompleteStarted checks that the operating system has been
+// upgraded and starts the unit agents; once all the units have
+// completed the series upgrade, the new series is recorded.
+func (w *Worker) handleCompleteStarted() error {
+	hostSeries, err := w.config.HostSeries()
+	if err != nil {
+		return errors.Annotate(err, "getting host series")
+	}
+	targetSeries, err := w.config.Facade.TargetSeries()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if hostSeries != targetSeries {
+		logger.Errorf("machine series is %q, expected %q after series upgrade", hostSeries, targetSeries)
+		err := w.config.Facade.SetMachineStatus(model.UpgradeSeriesError)
+		return errors.Trace(err)
+	}
+
+	statuses, err := w.config.Facade.UnitStatuses()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for unitName := range statuses {
+		svc, err := w.unitService(unitName)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		running, err := svc.Running()
+		if err != nil {
+			return errors.Annotatef(err, "checking unit agent %q", unitName)
+		}
+		if running {
+			continue
+		}
+		logger.Infof("starting unit agent %q after series upgrade", unitName)
+		if err := svc.Start(); err != nil {
+			return errors.Annotatef(err, "starting unit agent %q", unitName)
+		}
+	}
+
+	_, ok, err := w.unitsWithStatus(model.UpgradeSeriesCompleted)
+	if err != nil || !ok {
+		return errors.Trace(err)
+	}
+	logger.Infof("series upgrade to %q completed", hostSeries)
+	err = w.config.Facade.FinishUpgradeSeries(hostSeries)
+	return errors.Trace(err)
+}
+
+// unitsWithStatus returns the names of the units in the series
+// upgrade, and whether all of them have the supplied status.
+func (w *Worker) unitsWithStatus(status model.UpgradeSeriesStatus) ([]string, bool, error) {
+	statuses, err := w.config.Facade.UnitStatuses()
+	if err != nil {
+		return nil, false, errors.Trace(err)
+	}
+	unitNames := make([]string, 0, len(statuses))
+	for unitName, unitStatus := range statuses {
+		if unitStatus != status {
+			return nil, false, nil
+		}
+		unitNames = append(unitNames, unitName)
+	}
+	return unitNames, true, nil
+}
+
+func (w *Worker) unitService(unitName string) (AgentService, error) {
+	name := "jujud-" + names.NewUnitTag(unitName).String()
+	svc, err := w.config.Service.DiscoverService(name)
+	return svc, errors.Annotatef(err, "finding service for unit agent %q", unitName)
+}
+
+// machineStatus returns the status of the machine's series upgrade,
+// or UpgradeSeriesNotStarted if the machine is not being upgraded.
+func machineStatus(facade Facade) (model.UpgradeSeriesStatus, error) {
+	status, err := facade.MachineStatus()
+	if params.IsCodeNotFound(err) {
+		return model.UpgradeSeriesNotStarted, nil
+	} else if err != nil {
+		return "", errors.Annotate(err, "getting series upgrade status")
+	}
+	return status, nil
+}
+
+// isGated returns whether workers depending on the flag should be
+// stopped, because the unit agents are stopped for the operating
+// system upgrade.
+func isGated(status model.UpgradeSeriesStatus) bool {
+	return status == model.UpgradeSeriesPrepareCompleted
+}
@@ -0,0 +1,259 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package upgradeseries_test
+
+import (
+	"sync"
+	"time"
+
+	"github.com/juju/errors"
+	jujutesting "github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/core/model"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/watcher"
+	"github.com/juju/juju/watcher/watchertest"
+	"github.com/juju/juju/worker/upgradeseries"
+	"github.com/juju/juju/worker/workertest"
+)
+
+type Suite struct {
+	jujutesting.IsolationSuite
+
+	stub    *jujutesting.Stub
+	facade  *stubFacade
+	changes chan struct{}
+	config  upgradeseries.Config
+}
+
+var _ = gc.Suite(&Suite{})
+
+func (s *Suite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.stub = new(jujutesting.Stub)
+	s.changes = make(chan struct{})
+	s.facade = &stubFacade{
+		stub:    s.stub,
+		changes: s.changes,
+		status:  model.UpgradeSeriesPrepareStarted,
+		units: map[string]model.UpgradeSeriesStatus{
+			"mysql/0": model.UpgradeSeriesPrepareCompleted,
+		},
+		target: "xenial",
+	}
+	s.config = upgradeseries.Config{
+		Facade:  s.facade,
+		Service: &stubServiceAccess{stub: s.stub},
+		HostSeries: func() (string, error) {
+			return "xenial", nil
+		},
+	}
+}
+
+// sendChange sends two changes to the worker; the second can only be
+// delivered once the first has been handled.
+func (s *Suite) sendChange(c *gc.C) {
+	for i := 0; i < 2; i++ {
+		select {
+		case s.changes <- struct{}{}:
+		case <-time.After(coretesting.LongWait):
+			c.Fatalf("timed out sending change")
+		}
+	}
+}
+
+func (s *Suite) TestInvalidConfig(c *gc.C) {
+	s.config.Service = nil
+	_, err := upgradeseries.New(s.config)
+	c.Check(err, gc.ErrorMatches, "nil Service not valid")
+	c.Check(s.stub.Calls(), gc.HasLen, 0)
+}
+
+func (s *Suite) TestNotUpgrading(c *gc.C) {
+	s.facade.setStatus("", &params.Error{Code: params.CodeNotFound})
+	w, err := upgradeseries.New(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.CleanKill(c, w)
+	c.Check(w.Check(), jc.IsTrue)
+
+	s.sendChange(c)
+	workertest.CheckAlive(c, w)
+	s.stub.CheckCallNames(c,
+		"MachineStatus",
+		"WatchUpgradeSeriesNotifications",
+		"MachineStatus",
+		"MachineStatus",
+	)
+}
+
+func (s *Suite) TestPrepareStartedWaitsForUnits(c *gc.C) {
+	s.facade.units["mysql/0"] = model.UpgradeSeriesPrepareStarted
+	w, err := upgradeseries.New(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.CleanKill(c, w)
+	c.Check(w.Check(), jc.IsTrue)
+
+	s.sendChange(c)
+	s.stub.CheckCallNames(c,
+		"MachineStatus",
+		"WatchUpgradeSeriesNotifications",
+		"MachineStatus", "UnitStatuses",
+		"MachineStatus", "UnitStatuses",
+	)
+}
+
+func (s *Suite) TestPrepareStartedStopsUnitAgents(c *gc.C) {
+	w, err := upgradeseries.New(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.DirtyKill(c, w)
+
+	s.sendChange(c)
+	err = workertest.CheckKilled(c, w)
+	c.Check(errors.Cause(err), gc.Equals, upgradeseries.ErrChanged)
+
+	s.stub.CheckCalls(c, []jujutesting.StubCall{
+		{"MachineStatus", nil},
+		{"WatchUpgradeSeriesNotifications", nil},
+		{"MachineStatus", nil},
+		{"UnitStatuses", nil},
+		{"DiscoverService", []interface{}{"jujud-unit-mysql-0"}},
+		{"Stop", nil},
+		{"SetMachineStatus", []interface{}{model.UpgradeSeriesPrepareCompleted}},
+		{"MachineStatus", nil},
+	})
+}
+
+func (s *Suite) TestPrepareCompletedGates(c *gc.C) {
+	s.facade.setStatus(model.UpgradeSeriesPrepareCompleted, nil)
+	w, err := upgradeseries.New(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.CleanKill(c, w)
+	c.Check(w.Check(), jc.IsFalse)
+}
+
+func (s *Suite) TestCompleteStartedFinishesUpgrade(c *gc.C) {
+	s.facade.setStatus(model.UpgradeSeriesCompleteStarted, nil)
+	s.facade.units["mysql/0"] = model.UpgradeSeriesCompleted
+	w, err := upgradeseries.New(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.CleanKill(c, w)
+	c.Check(w.Check(), jc.IsTrue)
+
+	s.sendChange(c)
+	s.stub.CheckCalls(c, []jujutesting.StubCall{
+		{"MachineStatus", nil},
+		{"WatchUpgradeSeriesNotifications", nil},
+		{"MachineStatus", nil},
+		{"TargetSeries", nil},
+		{"UnitStatuses", nil},
+		{"DiscoverService", []interface{}{"jujud-unit-mysql-0"}},
+		{"Running", nil},
+		{"Start", nil},
+		{"UnitStatuses", nil},
+		{"FinishUpgradeSeries", []interface{}{"xenial"}},
+		{"MachineStatus", nil},
+	})
+}
+
+func (s *Suite) TestCompleteStartedWrongSeries(c *gc.C) {
+	s.facade.setStatus(model.UpgradeSeriesCompleteStarted, nil)
+	s.config.HostSeries = func() (string, error) {
+		return "trusty", nil
+	}
+	w, err := upgradeseries.New(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.CleanKill(c, w)
+
+	s.sendChange(c)
+	s.stub.CheckCalls(c, []jujutesting.StubCall{
+		{"MachineStatus", nil},
+		{"WatchUpgradeSeriesNotifications", nil},
+		{"MachineStatus", nil},
+		{"TargetSeries", nil},
+		{"SetMachineStatus", []interface{}{model.UpgradeSeriesError}},
+		{"MachineStatus", nil},
+	})
+}
+
+type stubFacade struct {
+	stub    *jujutesting.Stub
+	changes chan struct{}
+
+	mu        sync.Mutex
+	status    model.UpgradeSeriesStatus
+	statusErr error
+	units     map[string]model.UpgradeSeriesStatus
+	target    string
+}
+
+func (f *stubFacade) setStatus(status model.UpgradeSeriesStatus, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.status, f.statusErr = status, err
+}
+
+func (f *stubFacade) WatchUpgradeSeriesNotifications() (watcher.NotifyWatcher, error) {
+	f.stub.AddCall("WatchUpgradeSeriesNotifications")
+	return watchertest.NewMockNotifyWatcher(f.changes), f.stub.NextErr()
+}
+
+func (f *stubFacade) MachineStatus() (model.UpgradeSeriesStatus, error) {
+	f.stub.AddCall("MachineStatus")
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.status, f.statusErr
+}
+
+func (f *stubFacade) SetMachineStatus(status model.UpgradeSeriesStatus) error {
+	f.stub.AddCall("SetMachineStatus", status)
+	f.setStatus(status, nil)
+	return f.stub.NextErr()
+}
+
+func (f *stubFacade) TargetSeries() (string, error) {
+	f.stub.AddCall("TargetSeries")
+	return f.target, f.stub.NextErr()
+}
+
+func (f *stubFacade) UnitStatuses() (map[string]model.UpgradeSeriesStatus, error) {
+	f.stub.AddCall("UnitStatuses")
+	return f.units, f.stub.NextErr()
+}
+
+func (f *stubFacade) FinishUpgradeSeries(hostSeries string) error {
+	f.stub.AddCall("FinishUpgradeSeries", hostSeries)
+	f.setStatus("", &params.Error{Code: params.CodeNotFound})
+	return f.stub.NextErr()
+}
+
+type stubServiceAccess struct {
+	stub *jujutesting.Stub
+}
+
+func (s *stubServiceAccess) DiscoverService(name string) (upgradeseries.AgentService, error) {
+	s.stub.AddCall("DiscoverService", name)
+	return &stubService{stub: s.stub}, s.stub.NextErr()
+}
+
+type stubService struct {
+	stub *jujutesting.Stub
+}
+
+func (s *stubService) Running() (bool, error) {
+	s.stub.AddCall("Running")
+	return false, s.stub.NextErr()
+}
+
+func (s *stubService) Start() error {
+	s.stub.AddCall("Start")
+	return s.stub.NextErr()
+}
+
+func (s *stubService) Stop() error {
+	s.stub.AddCall("Stop")
+	return s.stub.NextErr()
+}